@@ -5,6 +5,8 @@ package audit
 
 import (
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 
 	"github.com/trisacrypto/envoy/pkg/store/models"
@@ -19,6 +21,7 @@ var (
 
 	ErrSigningKeyMissing      = errors.New("could not get a signing key")
 	ErrVerificationKeyMissing = errors.New("could not get a verification key")
+	ErrNoKeyChain             = errors.New("no keychain is available for signing")
 )
 
 // The package will use the given keychain.KeyChain for signing and verification
@@ -47,7 +50,7 @@ func Sign(log *models.ComplianceAuditLog) (err error) {
 
 	// Assign the log signature and metadata
 	log.Signature = logSig
-	log.Algorithm = signatureAlgorithm()
+	log.Algorithm = SignatureAlgorithm()
 	log.KeyID = keySig
 
 	return nil
@@ -59,6 +62,61 @@ func Verify(log *models.ComplianceAuditLog) (err error) {
 	return verifyData(log.Data(), log.Signature, log.KeyID)
 }
 
+// Signs arbitrary data (e.g. the manifest of an evidence bundle) with the node's
+// signing key so that it can be verified outside of the node. The signature can
+// be verified with the key identified by VerificationKeyID().
+func SignData(data []byte) (signature []byte, err error) {
+	return signData(data)
+}
+
+// Returns the ID of the local node's current verification key.
+func VerificationKeyID() (string, error) {
+	return verificationKeySignature()
+}
+
+// Verifies data signed by SignData using the verification key identified by keyID.
+// The data is valid if no error is returned.
+func VerifyData(data, signature []byte, keyID string) error {
+	return verifyData(data, signature, keyID)
+}
+
+// Returns the PEM encoded public verification key identified by keyID (or the local
+// node's current verification key if keyID is empty) so that signatures can be
+// verified offline with standard tools.
+func VerificationKeyPEM(keyID string) (_ []byte, err error) {
+	if kc == nil {
+		return nil, ErrNoKeyChain
+	}
+
+	var (
+		pubkey       keys.PublicKey
+		interfacekey any
+		der          []byte
+	)
+
+	if pubkey, err = kc.VerificationKey(keyID); err != nil {
+		return nil, err
+	}
+
+	if interfacekey, err = pubkey.SealingKey(); err != nil {
+		return nil, err
+	}
+
+	if der, err = x509.MarshalPKIXPublicKey(interfacekey); err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// Returns the algorithm details as a string for the current data-signing
+// algorithm.
+func SignatureAlgorithm() string {
+	// NOTE: in order to not have to return an error, we're bypassing the
+	// SignatureAlgorithm() function so we don't have to init an RSA.
+	return rsaoeap.SignerAlgorithm
+}
+
 // ============================================================================
 // Helpers
 // ============================================================================
@@ -93,14 +151,6 @@ func verificationKeySignature() (signature string, err error) {
 	return verifier.PublicKeySignature()
 }
 
-// Returns the algorithm details as a string for the current data-signing
-// algorithm.
-func signatureAlgorithm() string {
-	// NOTE: in order to not have to return an error, we're bypassing the
-	// SignatureAlgorithm() function so we don't have to init an RSA.
-	return rsaoeap.SignerAlgorithm
-}
-
 // Returns an rsaoeap.RSA that can be used to sign data.
 func getSigner() (rsaSigner *rsaoeap.RSA, err error) {
 	var (
@@ -110,6 +160,10 @@ func getSigner() (rsaSigner *rsaoeap.RSA, err error) {
 		ok           bool
 	)
 
+	if kc == nil {
+		return nil, ErrNoKeyChain
+	}
+
 	// Get the singing key
 	if privkey, err = kc.SigningKey(); err != nil {
 		return nil, err
@@ -144,6 +198,10 @@ func getVerifier(signature string) (rsaVerifier *rsaoeap.RSA, err error) {
 		ok           bool
 	)
 
+	if kc == nil {
+		return nil, ErrNoKeyChain
+	}
+
 	// Get the singing key
 	if pubkey, err = kc.VerificationKey(signature); err != nil {
 		return nil, err
@@ -1,6 +1,11 @@
 package audit_test
 
 import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 	"time"
 
@@ -28,6 +33,48 @@ func TestSignVerify(t *testing.T) {
 	require.NoError(t, err, "data was not verified")
 }
 
+func TestSignVerifyData(t *testing.T) {
+	loadAuditKeyChainFixture(t)
+	data := []byte(`{"files":[{"name":"transaction.json"}]}`)
+
+	sig, err := audit.SignData(data)
+	require.NoError(t, err, "couldn't sign the data")
+	require.NotEmpty(t, sig, "expected a signature")
+
+	keyID, err := audit.VerificationKeyID()
+	require.NoError(t, err, "couldn't get the verification key id")
+	require.NotEmpty(t, keyID, "expected a key id")
+
+	require.NoError(t, audit.VerifyData(data, sig, keyID), "data was not verified")
+	require.Error(t, audit.VerifyData([]byte("tampered"), sig, keyID), "tampered data should not verify")
+
+	// The exported public key should verify the signature without the keychain
+	keyPEM, err := audit.VerificationKeyPEM(keyID)
+	require.NoError(t, err, "couldn't get verification key")
+
+	block, _ := pem.Decode(keyPEM)
+	require.NotNil(t, block, "expected a PEM encoded key")
+	require.Equal(t, "PUBLIC KEY", block.Type)
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err, "could not parse public key")
+
+	digest := sha512.Sum512(data)
+	err = rsa.VerifyPSS(pub.(*rsa.PublicKey), crypto.SHA512, digest[:], sig, nil)
+	require.NoError(t, err, "signature was not verified with the exported key")
+}
+
+func TestNoKeyChain(t *testing.T) {
+	audit.UseKeyChain(nil)
+	defer loadAuditKeyChainFixture(t)
+
+	_, err := audit.SignData([]byte("data"))
+	require.ErrorIs(t, err, audit.ErrNoKeyChain)
+
+	_, err = audit.VerificationKeyPEM("")
+	require.ErrorIs(t, err, audit.ErrNoKeyChain)
+}
+
 // ===========================================================================
 // Helpers
 // ===========================================================================
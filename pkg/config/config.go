@@ -1,12 +1,17 @@
 package config
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net"
+	"net/mail"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/trisacrypto/envoy/pkg/emails"
@@ -15,6 +20,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rotationalio/confire"
 	"github.com/rs/zerolog"
+	"golang.org/x/text/language"
 )
 
 // All environment variables will have this prefix unless otherwise defined in struct
@@ -22,6 +28,17 @@ import (
 // because of this prefix and the split_words struct tag in the conf below.
 const Prefix = "trisa"
 
+// The minimum amount of time a signing key is used before it is rotated. Signing keys
+// are published before they are used so that every replica can verify their tokens.
+const MinKeyRotation = 1 * time.Hour
+
+// Password policies cannot be weaker than the minimum length and cannot require a
+// strength score that no password can achieve.
+const (
+	MinPasswordLength   = 8
+	MaxPasswordStrength = 5
+)
+
 // If any of the keys are not set or are empty, they will be remapped to the value
 // of the specified environment variable. This is primarily used when we want to be
 // able to override a required environment variable with a different specified value
@@ -39,21 +56,29 @@ var environRemap = map[string]string{
 // values that are omitted. The Config should be validated in preparation for running
 // the server to ensure that all server operations work as expected.
 type Config struct {
-	Maintenance     bool                `default:"false" desc:"if true, the node will start in maintenance mode"`
-	Organization    string              `default:"Envoy" desc:"specify the name of the organization of the Envoy node for display purposes"`
-	Mode            string              `default:"release" desc:"specify the mode of the server (release, debug, testing)"`
-	LogLevel        logger.LevelDecoder `split_words:"true" default:"info" desc:"specify the verbosity of logging (trace, debug, info, warn, error, fatal panic)"`
-	ConsoleLog      bool                `split_words:"true" default:"false" desc:"if true logs colorized human readable output instead of json"`
-	DatabaseURL     string              `split_words:"true" default:"sqlite3:///trisa.db" desc:"dsn containing backend database configuration"`
-	SearchThreshold float64             `split_words:"true" default:"0.0" desc:"specify the threshold for fuzzy search (0.0 to 1.0)"`
-	Web             WebConfig           `split_words:"true"`
-	Webhook         WebhookConfig       `split_words:"true"`
-	Node            TRISAConfig         `split_words:"true"`
-	DirectorySync   DirectorySyncConfig `split_words:"true"`
-	TRP             TRPConfig           `split_words:"true"`
-	Sunrise         SunriseConfig       `split_words:"true"`
-	Email           emails.Config       `split_words:"true"`
-	RegionInfo      RegionInfo          `split_words:"true"`
+	Maintenance     bool                  `default:"false" desc:"if true, the node will start in maintenance mode"`
+	Organization    string                `default:"Envoy" desc:"specify the name of the organization of the Envoy node for display purposes"`
+	Mode            string                `default:"release" desc:"specify the mode of the server (release, debug, testing)"`
+	LogLevel        logger.LevelDecoder   `split_words:"true" default:"info" desc:"specify the verbosity of logging (trace, debug, info, warn, error, fatal panic)"`
+	ConsoleLog      bool                  `split_words:"true" default:"false" desc:"if true logs colorized human readable output instead of json"`
+	DatabaseURL     string                `split_words:"true" default:"sqlite3:///trisa.db" desc:"dsn containing backend database configuration"`
+	SearchThreshold float64               `split_words:"true" default:"0.0" desc:"specify the threshold for fuzzy search (0.0 to 1.0)"`
+	Web             WebConfig             `split_words:"true"`
+	Webhook         WebhookConfig         `split_words:"true"`
+	Node            TRISAConfig           `split_words:"true"`
+	DirectorySync   DirectorySyncConfig   `split_words:"true"`
+	Retention       RetentionConfig       `split_words:"true"`
+	Deadlines       DeadlinesConfig       `split_words:"true"`
+	OutboundQueue   OutboundQueueConfig   `split_words:"true"`
+	Idempotency     IdempotencyConfig     `split_words:"true"`
+	Batch           BatchConfig           `split_words:"true"`
+	Reports         ReportsConfig         `split_words:"true"`
+	Thresholds      ThresholdsConfig      `split_words:"true"`
+	FieldEncryption FieldEncryptionConfig `split_words:"true"`
+	TRP             TRPConfig             `split_words:"true"`
+	Sunrise         SunriseConfig         `split_words:"true"`
+	Email           emails.Config         `split_words:"true"`
+	RegionInfo      RegionInfo            `split_words:"true"`
 	processed       bool
 }
 
@@ -61,29 +86,68 @@ type Config struct {
 // TRISA transactions. The web UI can be enabled or disabled and runs independently of
 // the other servers on the node.
 type WebConfig struct {
-	Maintenance   bool           `env:"TRISA_MAINTENANCE" desc:"if true sets the web UI to maintenance mode; inherited from parent"`
-	Enabled       bool           `default:"true" desc:"if false, the web UI server will not be run"`
-	APIEnabled    bool           `default:"true" split_words:"true" desc:"if false, the API server will return unavailable when accessed; subordinate to the enabled flag"`
-	UIEnabled     bool           `default:"true" split_words:"true" desc:"if false, the UI server will return unavailable when accessed; subordinate to the enabled flag"`
-	BindAddr      string         `default:":8000" split_words:"true" desc:"the ip address and port to bind the web server on"`
-	Origin        string         `default:"http://localhost:8000" desc:"origin (url) of the web ui for creating endpoints and CORS access"`
-	TRISAEndpoint string         `env:"TRISA_ENDPOINT" desc:"trisa endpoint as assigned to the mTLS certificates for the trisa node"`
-	TRPEndpoint   string         `env:"TRISA_TRP_ENDPOINT" desc:"trp endpoint as assigned to the mTLS certificates for the trp node"`
-	DocsName      string         `split_words:"true" desc:"the display name for the API docs server in the Swagger app"`
-	LogoURI       string         `split_words:"true" desc:"use a custom logo for the web UI"`
-	Auth          AuthConfig     `split_words:"true"`
-	Daybreak      DaybreakConfig `split_words:"true"`
+	Maintenance    bool           `env:"TRISA_MAINTENANCE" desc:"if true sets the web UI to maintenance mode; inherited from parent"`
+	Enabled        bool           `default:"true" desc:"if false, the web UI server will not be run"`
+	APIEnabled     bool           `default:"true" split_words:"true" desc:"if false, the API server will return unavailable when accessed; subordinate to the enabled flag"`
+	UIEnabled      bool           `default:"true" split_words:"true" desc:"if false, the UI server will return unavailable when accessed; subordinate to the enabled flag"`
+	BindAddr       string         `default:":8000" split_words:"true" desc:"the ip address and port to bind the web server on"`
+	Origin         string         `default:"http://localhost:8000" desc:"origin (url) of the web ui for creating endpoints and CORS access"`
+	TRISAEndpoint  string         `env:"TRISA_ENDPOINT" desc:"trisa endpoint as assigned to the mTLS certificates for the trisa node"`
+	TRPEndpoint    string         `env:"TRISA_TRP_ENDPOINT" desc:"trp endpoint as assigned to the mTLS certificates for the trp node"`
+	DocsName       string         `split_words:"true" desc:"the display name for the API docs server in the Swagger app"`
+	LogoURI        string         `split_words:"true" desc:"use a custom logo for the web UI"`
+	TrustedProxies []string       `split_words:"true" desc:"ip addresses or cidr ranges of the reverse proxies whose X-Forwarded-For headers are trusted to identify the client ip; if empty, the ip of the connection is used"`
+	Auth           AuthConfig     `split_words:"true"`
+	Daybreak       DaybreakConfig `split_words:"true"`
 }
 
 // AuthConfig specifies the configuration for authenticating WebUI requests
 type AuthConfig struct {
-	Keys            map[string]string `required:"false" desc:"optional static key configuration as a map of keyID to path on disk"`
-	Audience        string            `default:"http://localhost:8000" desc:"value for the aud jwt claim"`
-	Issuer          string            `default:"http://localhost:8000" desc:"value for the iss jwt claim"`
-	CookieDomain    string            `split_words:"true" default:"localhost" desc:"limit cookies to the specified domain (exclude port)"`
-	AccessTokenTTL  time.Duration     `split_words:"true" default:"1h" desc:"the amount of time before an access token expires"`
-	RefreshTokenTTL time.Duration     `split_words:"true" default:"2h" desc:"the amount of time before a refresh token expires"`
-	TokenOverlap    time.Duration     `split_words:"true" default:"-15m" desc:"the amount of overlap between the access and refresh token"`
+	Keys                 map[string]string    `required:"false" desc:"optional static key configuration as a map of keyID to path on disk"`
+	Audience             string               `default:"http://localhost:8000" desc:"value for the aud jwt claim"`
+	Issuer               string               `default:"http://localhost:8000" desc:"value for the iss jwt claim"`
+	CookieDomain         string               `split_words:"true" default:"localhost" desc:"limit cookies to the specified domain (exclude port)"`
+	AccessTokenTTL       time.Duration        `split_words:"true" default:"1h" desc:"the amount of time before an access token expires"`
+	RefreshTokenTTL      time.Duration        `split_words:"true" default:"2h" desc:"the amount of time before a refresh token expires"`
+	TokenOverlap         time.Duration        `split_words:"true" default:"-15m" desc:"the amount of overlap between the access and refresh token"`
+	KeyRotation          time.Duration        `split_words:"true" default:"720h" desc:"the amount of time a signing key stored in the database is used before it is rotated; if zero, only static or generated keys are used"`
+	DisablePasswordLogin bool                 `split_words:"true" default:"false" desc:"if true, users cannot login with a password and must use single sign-on"`
+	MaxLoginFailures     int64                `split_words:"true" default:"5" desc:"the number of consecutive failed logins before a user is locked out; if zero, users are never locked out"`
+	LockoutDuration      time.Duration        `split_words:"true" default:"5m" desc:"the amount of time a user is locked out after the first lockout, doubled for every subsequent lockout"`
+	MaxLockoutDuration   time.Duration        `split_words:"true" default:"24h" desc:"the maximum amount of time a user is locked out after repeated lockouts"`
+	PasswordPolicy       PasswordPolicyConfig `split_words:"true"`
+	InviteTTL            time.Duration        `split_words:"true" default:"72h" desc:"the amount of time before the link to accept a user invite expires"`
+	OIDC                 OIDCConfig
+}
+
+// PasswordPolicyConfig specifies the complexity requirements of user passwords. The
+// strength of a password is the number of character classes (numbers, uppercase,
+// lowercase, and special characters) it contains plus one if it is longer than 16
+// characters.
+type PasswordPolicyConfig struct {
+	MinLength        int   `split_words:"true" default:"8" desc:"the minimum number of characters in a password"`
+	MinStrength      uint8 `split_words:"true" default:"3" desc:"the minimum password strength score from 0 to 5"`
+	RequireUppercase bool  `split_words:"true" default:"false" desc:"if true, passwords must contain an uppercase letter"`
+	RequireLowercase bool  `split_words:"true" default:"false" desc:"if true, passwords must contain a lowercase letter"`
+	RequireNumber    bool  `split_words:"true" default:"false" desc:"if true, passwords must contain a number"`
+	RequireSpecial   bool  `split_words:"true" default:"false" desc:"if true, passwords must contain a special character"`
+}
+
+// OIDCConfig specifies an OpenID Connect identity provider that web UI users can use to
+// login with single sign-on. Users are provisioned when they first login and are
+// assigned the role mapped from their groups claim.
+type OIDCConfig struct {
+	Enabled      bool              `default:"false" desc:"if true, users can login with the OpenID Connect identity provider"`
+	ProviderName string            `split_words:"true" default:"Single Sign-On" desc:"the name of the identity provider displayed on the login button"`
+	IssuerURL    string            `split_words:"true" desc:"the issuer url of the identity provider used to discover its endpoints"`
+	ClientID     string            `split_words:"true" desc:"the client id of envoy registered with the identity provider"`
+	ClientSecret string            `split_words:"true" desc:"the client secret of envoy registered with the identity provider"`
+	RedirectURL  string            `split_words:"true" desc:"the callback url registered with the identity provider, e.g. https://envoy.example.com/login/oidc/callback"`
+	Scopes       []string          `default:"openid,email,profile" desc:"the scopes requested from the identity provider"`
+	GroupsClaim  string            `split_words:"true" default:"groups" desc:"the id token claim that contains the user's groups"`
+	RoleMapping  map[string]string `split_words:"true" desc:"a map of identity provider group to envoy role, e.g. envoy-admins:admin,envoy-compliance:compliance"`
+	DefaultRole  string            `split_words:"true" desc:"the role assigned to users whose groups are not mapped; if empty these users cannot login"`
+	Provision    bool              `default:"true" desc:"if true, users are created when they first login with single sign-on"`
 }
 
 // TRISAConfig is a generic configuration for the TRISA node options
@@ -95,6 +159,20 @@ type TRISAConfig struct {
 	BindAddr            string          `split_words:"true" default:":8100" desc:"the ip address and port to bind the trisa grpc server on"`
 	KeyExchangeCacheTTL time.Duration   `split_words:"true" default:"24h"`
 	Directory           DirectoryConfig `split_words:"true"`
+	Pending             PendingConfig   `split_words:"true"`
+}
+
+// PendingConfig specifies the pending response that is automatically returned when an
+// incoming TRISA or TRP request must be reviewed before the node can reply. Messages
+// are text templates that can reference {{.Organization}}, {{.Counterparty}},
+// {{.ReplyNotBefore}}, and {{.ReplyNotAfter}}; the message and reply windows can be
+// overridden for each counterparty.
+type PendingConfig struct {
+	Organization   string            `env:"TRISA_ORGANIZATION" default:"Envoy" desc:"the name of the organization that is reviewing the request; inherited from parent"`
+	Message        string            `default:"We are reviewing your travel rule exchange request and will reply once we have completed our internal compliance checks" desc:"the template of the pending message if no localized message is available"`
+	Messages       map[string]string `desc:"localized message templates as a map of language tag to template, e.g. de:Wir prüfen Ihre Anfrage"`
+	ReplyNotBefore time.Duration     `split_words:"true" default:"5m" desc:"the earliest time after the request that the counterparty should expect a reply"`
+	ReplyNotAfter  time.Duration     `split_words:"true" default:"24h" desc:"the latest time after the request that the counterparty should expect a reply"`
 }
 
 // DirectoryConfig is a generic configuration for connecting to a TRISA GDS service.
@@ -113,6 +191,114 @@ type DirectorySyncConfig struct {
 	Interval time.Duration `default:"6h" desc:"the interval synchronization is run"`
 }
 
+// RetentionConfig specifies the data retention policies that are enforced by the
+// retention background service. Each policy is the amount of time a record is kept
+// after it has expired; a zero duration disables the policy and keeps the records
+// forever. Transactions under legal hold are never archived, purged, or shredded.
+type RetentionConfig struct {
+	Enabled         bool          `default:"false" desc:"if true, the retention background service will archive, purge, and shred expired records"`
+	Interval        time.Duration `default:"24h" desc:"the interval retention policies are evaluated"`
+	CryptoShred     bool          `split_words:"true" default:"true" desc:"if true, the PII and envelope keys of expired transactions are destroyed instead of deleting the transaction"`
+	Transactions    time.Duration `default:"43800h" desc:"transactions older than this duration are archived"`
+	Archives        time.Duration `default:"720h" desc:"archived transactions are purged or crypto-shredded this duration after they are archived"`
+	SecureEnvelopes time.Duration `split_words:"true" default:"0s" desc:"secure envelopes older than this duration are crypto-shredded"`
+	Sunrise         time.Duration `default:"2160h" desc:"sunrise records are deleted this duration after they expire"`
+	ResetLinks      time.Duration `split_words:"true" default:"24h" desc:"reset password links are deleted this duration after they expire"`
+	Accounts        time.Duration `default:"0s" desc:"customer accounts that have not been modified or transacted for this duration are deleted"`
+}
+
+// DeadlinesConfig specifies how the deadlines background service handles transactions
+// that are awaiting a reply. Reminders are sent once when a reply deadline is within
+// the reminder window; when the deadline passes the transaction is marked as expired
+// or, if the local node owes the reply, it can be automatically rejected instead.
+type DeadlinesConfig struct {
+	Enabled        bool          `default:"false" desc:"if true, the deadlines background service will send reminders and expire transactions awaiting a reply"`
+	Interval       time.Duration `default:"5m" desc:"the interval reply deadlines are checked"`
+	ReminderWindow time.Duration `split_words:"true" default:"4h" desc:"reminders are sent when a reply deadline is within this duration"`
+	OnExpiry       string        `split_words:"true" default:"expire" desc:"the action taken when the local node misses a reply deadline (expire, reject)"`
+}
+
+const (
+	OnExpiryExpire = "expire"
+	OnExpiryReject = "reject"
+)
+
+// OutboundQueueConfig specifies how transfers that could not be delivered to the
+// counterparty (or that are scheduled to be sent later) are retried by the outbound
+// queue background service. The delay between attempts doubles after every failed
+// attempt, starting at the initial backoff and never exceeding the maximum backoff.
+type OutboundQueueConfig struct {
+	Enabled        bool          `default:"false" desc:"if true, transfers to unreachable counterparties are queued and retried in the background"`
+	Interval       time.Duration `default:"1m" desc:"the interval the outbound queue is checked for transfers that are due"`
+	MaxAttempts    int64         `split_words:"true" default:"10" desc:"the number of attempts to deliver a queued transfer before it is marked as failed"`
+	InitialBackoff time.Duration `split_words:"true" default:"1m" desc:"the delay before the first retry of a queued transfer"`
+	MaxBackoff     time.Duration `split_words:"true" default:"1h" desc:"the maximum delay between retries of a queued transfer"`
+}
+
+// IdempotencyConfig specifies how long the responses to requests made with an
+// Idempotency-Key header are stored so that retries of the request with the same key
+// replay the original response instead of performing the operation again. If not
+// enabled, the Idempotency-Key header is ignored.
+type IdempotencyConfig struct {
+	Enabled bool          `default:"true" desc:"if true, responses to requests with an Idempotency-Key header are stored and replayed"`
+	Window  time.Duration `default:"24h" desc:"the duration idempotency keys and their responses are stored"`
+}
+
+// BatchConfig specifies how batches of transfers imported from CSV or JSON Lines files
+// are handled. The valid transfers in a batch are sent concurrently, but no more than
+// the specified number of transfers are sent at the same time.
+type BatchConfig struct {
+	Enabled     bool `default:"true" desc:"if true, transfers can be imported and sent in batches"`
+	Concurrency int  `default:"4" desc:"the maximum number of transfers in a batch that are sent at the same time"`
+	MaxRows     int  `split_words:"true" default:"1000" desc:"the maximum number of transfers that can be imported in a single batch"`
+}
+
+// ReportsConfig specifies how regulatory reports are generated on a schedule. When the
+// scheduler is enabled, a report of the travel rule transfers created during the last
+// complete period (day, week, or month in UTC) is generated and stored once the period
+// ends, and it is emailed to the recipients in the specified format.
+type ReportsConfig struct {
+	Enabled    bool          `default:"false" desc:"if true, the reports scheduler will generate a report for each completed period"`
+	Interval   time.Duration `default:"1h" desc:"the interval the scheduler checks if the report of the last period has been generated"`
+	Frequency  string        `default:"monthly" desc:"the period covered by each scheduled report (daily, weekly, monthly)"`
+	Format     string        `default:"csv" desc:"the format of the report attached to emails (csv, json, html)"`
+	Sections   []string      `desc:"the sections included in scheduled reports; if empty all sections are included"`
+	Recipients []string      `desc:"the email addresses that scheduled reports are sent to"`
+}
+
+// ThresholdsConfig enables the jurisdiction-aware threshold rules that determine if
+// travel rule data is required for a transfer. Thresholds are expressed in the fiat
+// currency; the amount of a transfer is converted into the currency using the rate of
+// its virtual asset. The country of the local VASP is the RegionInfo country.
+type ThresholdsConfig struct {
+	Enabled  bool               `default:"false" desc:"if true, transfers are evaluated against the threshold rules to determine if travel rule data is required"`
+	Currency string             `default:"USD" desc:"the alpha-3 code of the fiat currency that thresholds are expressed in"`
+	Rates    map[string]float64 `desc:"the value in the fiat currency of one unit of a virtual asset by network or asset type, e.g. BTC:65000,ETH:3500"`
+}
+
+const (
+	ReportDaily   = "daily"
+	ReportWeekly  = "weekly"
+	ReportMonthly = "monthly"
+)
+
+const (
+	ReportCSV  = "csv"
+	ReportJSON = "json"
+	ReportHTML = "html"
+)
+
+// FieldEncryptionConfig specifies how the data encryption key used to encrypt PII
+// fields in the database is loaded. If a key or secret is not specified, a data key is
+// generated and stored in the database wrapped by the node's storage key.
+type FieldEncryptionConfig struct {
+	Enabled       bool     `default:"false" desc:"if true, account, contact, and transaction PII is encrypted at rest"`
+	Key           string   `desc:"base64 encoded 32 byte data encryption key (overrides the secret and keychain)"`
+	SecretProject string   `split_words:"true" desc:"the GCP project of the secret manager containing the data encryption key"`
+	SecretName    string   `split_words:"true" desc:"the name of the secret containing the data encryption key"`
+	PreviousKeys  []string `split_words:"true" desc:"base64 encoded data encryption keys that were previously used; values encrypted with these keys are reencrypted with the current key on startup"`
+}
+
 type TRPConfig struct {
 	MTLSConfig
 	Maintenance bool   `env:"TRISA_MAINTENANCE" desc:"if true sets the trp node to maintenance mode; inherited from parent"`
@@ -210,6 +396,38 @@ func (c Config) Validate() (err error) {
 		return err
 	}
 
+	if err = c.Retention.Validate(); err != nil {
+		return err
+	}
+
+	if err = c.Deadlines.Validate(); err != nil {
+		return err
+	}
+
+	if err = c.OutboundQueue.Validate(); err != nil {
+		return err
+	}
+
+	if err = c.Idempotency.Validate(); err != nil {
+		return err
+	}
+
+	if err = c.Batch.Validate(); err != nil {
+		return err
+	}
+
+	if err = c.Reports.Validate(); err != nil {
+		return err
+	}
+
+	if err = c.Thresholds.Validate(); err != nil {
+		return err
+	}
+
+	if err = c.FieldEncryption.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -236,6 +454,81 @@ func (c WebConfig) Validate() (err error) {
 		return errors.New("invalid configuration: origin is required")
 	}
 
+	for _, proxy := range c.TrustedProxies {
+		if _, _, cidrErr := net.ParseCIDR(proxy); cidrErr != nil && net.ParseIP(proxy) == nil {
+			return fmt.Errorf("invalid configuration: could not parse trusted proxy %q", proxy)
+		}
+	}
+
+	if err = c.Auth.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c AuthConfig) Validate() (err error) {
+	if err = c.OIDC.Validate(); err != nil {
+		return err
+	}
+
+	if c.DisablePasswordLogin && !c.OIDC.Enabled {
+		return errors.New("invalid configuration: single sign-on must be enabled if password login is disabled")
+	}
+
+	if c.KeyRotation != 0 && c.KeyRotation < MinKeyRotation {
+		return fmt.Errorf("invalid configuration: signing key rotation must be at least %s", MinKeyRotation)
+	}
+
+	if c.MaxLoginFailures < 0 {
+		return errors.New("invalid configuration: max login failures must not be negative")
+	}
+
+	if c.MaxLoginFailures > 0 && (c.LockoutDuration <= 0 || c.MaxLockoutDuration < c.LockoutDuration) {
+		return errors.New("invalid configuration: lockout duration must be positive and no more than the max lockout duration")
+	}
+
+	if c.InviteTTL < 0 {
+		return errors.New("invalid configuration: invite ttl must not be negative")
+	}
+
+	if err = c.PasswordPolicy.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c PasswordPolicyConfig) Validate() error {
+	if c.MinLength != 0 && c.MinLength < MinPasswordLength {
+		return fmt.Errorf("invalid configuration: password policy min length must be at least %d", MinPasswordLength)
+	}
+
+	if c.MinStrength > MaxPasswordStrength {
+		return fmt.Errorf("invalid configuration: password policy min strength must be at most %d", MaxPasswordStrength)
+	}
+
+	return nil
+}
+
+func (c OIDCConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.IssuerURL == "" || c.ClientID == "" || c.RedirectURL == "" {
+		return errors.New("invalid configuration: oidc issuer url, client id, and redirect url are required")
+	}
+
+	if _, err := url.Parse(c.RedirectURL); err != nil {
+		return errors.New("invalid configuration: oidc redirect url could not be parsed")
+	}
+
+	if !slices.Contains(c.Scopes, "openid") {
+		return errors.New("invalid configuration: oidc scopes must include openid")
+	}
+
 	return nil
 }
 
@@ -245,6 +538,30 @@ func (c WebConfig) ResetPasswordURL() *url.URL {
 	return u
 }
 
+func (c WebConfig) InviteURL() *url.URL {
+	u, _ := url.Parse(c.Origin)
+	u.Path = "/invite"
+	return u
+}
+
+func (c WebConfig) ApprovalsURL() *url.URL {
+	u, _ := url.Parse(c.Origin)
+	u.Path = "/approvals"
+	return u
+}
+
+func (c WebConfig) ReportsURL() *url.URL {
+	u, _ := url.Parse(c.Origin)
+	u.Path = "/reports"
+	return u
+}
+
+func (c WebConfig) TransactionURL(transactionID string) *url.URL {
+	u, _ := url.Parse(c.Origin)
+	u.Path = "/transactions/" + transactionID
+	return u
+}
+
 func (c WebhookConfig) Validate() (err error) {
 	if c.Enabled() {
 		if _, err = url.Parse(c.URL); err != nil {
@@ -300,14 +617,253 @@ func (c WebhookConfig) DecodeAuthKey() []byte {
 	return key
 }
 
+func (c RetentionConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval <= 0 {
+		return errors.New("invalid configuration: retention interval must be greater than zero")
+	}
+
+	for _, policy := range []time.Duration{c.Transactions, c.Archives, c.SecureEnvelopes, c.Sunrise, c.ResetLinks, c.Accounts} {
+		if policy < 0 {
+			return errors.New("invalid configuration: retention policies cannot be negative")
+		}
+	}
+	return nil
+}
+
+func (c DeadlinesConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval <= 0 {
+		return errors.New("invalid configuration: deadlines interval must be greater than zero")
+	}
+
+	if c.ReminderWindow < 0 {
+		return errors.New("invalid configuration: deadlines reminder window cannot be negative")
+	}
+
+	switch c.OnExpiry {
+	case OnExpiryExpire, OnExpiryReject:
+		return nil
+	default:
+		return fmt.Errorf("invalid configuration: unknown deadlines on expiry action %q", c.OnExpiry)
+	}
+}
+
+func (c OutboundQueueConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval <= 0 {
+		return errors.New("invalid configuration: outbound queue interval must be greater than zero")
+	}
+
+	if c.MaxAttempts <= 0 {
+		return errors.New("invalid configuration: outbound queue max attempts must be greater than zero")
+	}
+
+	if c.InitialBackoff <= 0 || c.MaxBackoff < c.InitialBackoff {
+		return errors.New("invalid configuration: outbound queue max backoff must be greater than the initial backoff")
+	}
+	return nil
+}
+
+// Backoff returns the delay before the next attempt to deliver a queued transfer
+// after the specified number of failed attempts.
+func (c OutboundQueueConfig) Backoff(attempts int64) time.Duration {
+	backoff := c.InitialBackoff
+	for i := int64(1); i < attempts && backoff < c.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, c.MaxBackoff)
+}
+
+func (c IdempotencyConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Window <= 0 {
+		return errors.New("invalid configuration: idempotency window must be greater than zero")
+	}
+	return nil
+}
+
+func (c BatchConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Concurrency <= 0 {
+		return errors.New("invalid configuration: batch concurrency must be greater than zero")
+	}
+
+	if c.MaxRows <= 0 {
+		return errors.New("invalid configuration: batch max rows must be greater than zero")
+	}
+	return nil
+}
+
+func (c ReportsConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval <= 0 {
+		return errors.New("invalid configuration: reports interval must be greater than zero")
+	}
+
+	switch c.Frequency {
+	case ReportDaily, ReportWeekly, ReportMonthly:
+	default:
+		return fmt.Errorf("invalid configuration: unknown reports frequency %q", c.Frequency)
+	}
+
+	switch c.Format {
+	case ReportCSV, ReportJSON, ReportHTML:
+	default:
+		return fmt.Errorf("invalid configuration: unknown reports format %q", c.Format)
+	}
+
+	for _, recipient := range c.Recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("invalid configuration: could not parse reports recipient %q", recipient)
+		}
+	}
+	return nil
+}
+
+func (c ThresholdsConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if len(c.Currency) != 3 {
+		return fmt.Errorf("invalid configuration: thresholds currency %q must be an alpha-3 currency code", c.Currency)
+	}
+
+	for asset, rate := range c.Rates {
+		if rate <= 0 {
+			return fmt.Errorf("invalid configuration: thresholds rate of %q must be greater than zero", asset)
+		}
+	}
+	return nil
+}
+
+func (c FieldEncryptionConfig) Validate() error {
+	// If not enabled, do not validate the config.
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Key != "" {
+		if key, err := base64.StdEncoding.DecodeString(c.Key); err != nil || len(key) != 32 {
+			return errors.New("invalid configuration: field encryption key must be a base64 encoded 32 byte key")
+		}
+	}
+
+	if c.SecretName != "" && c.SecretProject == "" {
+		return errors.New("invalid configuration: field encryption secret project is required with a secret name")
+	}
+
+	for _, prev := range c.PreviousKeys {
+		if key, err := base64.StdEncoding.DecodeString(prev); err != nil || len(key) != 32 {
+			return errors.New("invalid configuration: previous field encryption keys must be base64 encoded 32 byte keys")
+		}
+	}
+	return nil
+}
+
 // Validate that the TRISA config has mTLS certificates for operation.
 func (c *TRISAConfig) Validate() error {
 	if c.Certs == "" {
 		return errors.New("invalid configuration: specify certificates path")
 	}
+
+	if err := c.Pending.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
+func (c PendingConfig) Validate() (err error) {
+	if c.ReplyNotBefore < 0 || c.ReplyNotAfter < 0 {
+		return errors.New("invalid configuration: pending reply windows cannot be negative")
+	}
+
+	if c.ReplyNotAfter != 0 && c.ReplyNotAfter < c.ReplyNotBefore {
+		return errors.New("invalid configuration: pending reply not after window must not be before the reply not before window")
+	}
+
+	if _, err = RenderPending(c.Message, &PendingData{}); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	for tag, message := range c.Messages {
+		if _, err = language.Parse(tag); err != nil {
+			return fmt.Errorf("invalid configuration: %q is not a valid pending message language", tag)
+		}
+
+		if _, err = RenderPending(message, &PendingData{}); err != nil {
+			return fmt.Errorf("invalid configuration: %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// PendingData is used to render pending message templates.
+type PendingData struct {
+	Organization   string    // the name of the organization reviewing the request
+	Counterparty   string    // the name of the counterparty that sent the request
+	ReplyNotBefore time.Time // the earliest time the counterparty should expect a reply
+	ReplyNotAfter  time.Time // the latest time the counterparty should expect a reply
+}
+
+// RenderPending executes the pending message template with the specified data.
+func RenderPending(message string, data *PendingData) (_ string, err error) {
+	var tmpl *template.Template
+	if tmpl, err = template.New("pending").Parse(message); err != nil {
+		return "", fmt.Errorf("could not parse pending message template: %w", err)
+	}
+
+	out := &strings.Builder{}
+	if err = tmpl.Execute(out, data); err != nil {
+		return "", fmt.Errorf("could not render pending message template: %w", err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// Template returns the pending message template for the specified language, falling
+// back to the base language (e.g. de for de-CH) and then the default message.
+func (c PendingConfig) Template(lang string) string {
+	if lang != "" {
+		if message, ok := c.Messages[lang]; ok {
+			return message
+		}
+
+		if tag, err := language.Parse(lang); err == nil {
+			base, _ := tag.Base()
+			if message, ok := c.Messages[base.String()]; ok {
+				return message
+			}
+		}
+	}
+	return c.Message
+}
+
 // Network parses the directory service endpoint to identify the network of the directory.
 func (c DirectoryConfig) Network() string {
 	endpoint := c.Endpoint
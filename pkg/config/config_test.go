@@ -17,60 +17,107 @@ import (
 )
 
 var testEnv = map[string]string{
-	"TRISA_MAINTENANCE":                     "true",
-	"TRISA_ORGANIZATION":                    "Testing Organization",
-	"TRISA_MODE":                            "test",
-	"TRISA_LOG_LEVEL":                       "debug",
-	"TRISA_CONSOLE_LOG":                     "true",
-	"TRISA_DATABASE_URL":                    "sqlite3:///tmp/trisa.db",
-	"TRISA_ENDPOINT":                        "testing.tr-envoy.com:443",
-	"TRISA_SEARCH_THRESHOLD":                "0.75",
-	"TRISA_WEB_ENABLED":                     "false",
-	"TRISA_WEB_API_ENABLED":                 "false",
-	"TRISA_WEB_UI_ENABLED":                  "false",
-	"TRISA_WEB_BIND_ADDR":                   ":4000",
-	"TRISA_WEB_ORIGIN":                      "https://example.com",
-	"TRISA_WEB_DOCS_NAME":                   "Test Server",
-	"TRISA_WEB_LOGO_URI":                    "/static/img/blockpass-logo.webp",
-	"TRISA_WEB_AUTH_KEYS":                   "foo:/path/to/foo.pem,bar:/path/to/bar.pem",
-	"TRISA_WEB_AUTH_AUDIENCE":               "https://example.com",
-	"TRISA_WEB_AUTH_ISSUER":                 "https://auth.example.com",
-	"TRISA_WEB_AUTH_COOKIE_DOMAIN":          "example.com",
-	"TRISA_WEB_AUTH_ACCESS_TOKEN_TTL":       "24h",
-	"TRISA_WEB_AUTH_REFRESH_TOKEN_TTL":      "48h",
-	"TRISA_WEB_AUTH_TOKEN_OVERLAP":          "-12h",
-	"TRISA_WEBHOOK_URL":                     "https://example.com/callback",
-	"TRISA_WEBHOOK_USE_MTLS":                "true",
-	"TRISA_WEBHOOK_CERTS":                   "fixtures/certs/webhook/certs.pem",
-	"TRISA_WEBHOOK_POOL":                    "fixtures/certs/webhook/pool.pem",
-	"TRISA_WEBHOOK_AUTH_KEY_ID":             "01JT4B3R5Z6AHJXV87QHPPKRBM",
-	"TRISA_WEBHOOK_AUTH_KEY_SECRET":         "cfbabc4715b4759d45ba26953dd2fc0bfc2344ef70a2005432e7f16b5081610d",
-	"TRISA_WEBHOOK_REQUIRE_SERVER_AUTH":     "true",
-	"TRISA_NODE_ENABLED":                    "true",
-	"TRISA_NODE_BIND_ADDR":                  ":556",
-	"TRISA_NODE_POOL":                       "fixtures/certs/pool.gz",
-	"TRISA_NODE_CERTS":                      "fixtures/certs/certs.gz",
-	"TRISA_NODE_KEY_EXCHANGE_CACHE_TTL":     "5m",
-	"TRISA_NODE_DIRECTORY_INSECURE":         "true",
-	"TRISA_NODE_DIRECTORY_ENDPOINT":         "localhost:2525",
-	"TRISA_NODE_DIRECTORY_MEMBERS_ENDPOINT": "localhost:2526",
-	"TRISA_DIRECTORY_SYNC_ENABLED":          "true",
-	"TRISA_DIRECTORY_SYNC_INTERVAL":         "10m",
-	"TRISA_TRP_ENABLED":                     "true",
-	"TRISA_TRP_BIND_ADDR":                   ":8012",
-	"TRISA_TRP_USE_MTLS":                    "false",
-	"TRISA_TRP_POOL":                        "fixtures/certs/trp/pool.pem",
-	"TRISA_TRP_CERTS":                       "fixtures/certs/trp/certs.pem",
-	"TRISA_TRP_IDENTITY_VASP_NAME":          "Testing VASP",
-	"TRISA_TRP_IDENTITY_LEI":                "GTFZ00N6IHYMHHNT8S51",
-	"TRISA_SUNRISE_ENABLED":                 "false",
-	"TRISA_EMAIL_TESTING":                   "true",
-	"REGION_INFO_ID":                        "2840302",
-	"REGION_INFO_NAME":                      "us-east4c",
-	"REGION_INFO_COUNTRY":                   "US",
-	"REGION_INFO_CLOUD":                     "GCP",
-	"REGION_INFO_CLUSTER":                   "rotational-testing-gke-9",
-	"TRISA_WEB_DAYBREAK_ENABLED":            "true",
+	"TRISA_MAINTENANCE":                              "true",
+	"TRISA_ORGANIZATION":                             "Testing Organization",
+	"TRISA_MODE":                                     "test",
+	"TRISA_LOG_LEVEL":                                "debug",
+	"TRISA_CONSOLE_LOG":                              "true",
+	"TRISA_DATABASE_URL":                             "sqlite3:///tmp/trisa.db",
+	"TRISA_ENDPOINT":                                 "testing.tr-envoy.com:443",
+	"TRISA_SEARCH_THRESHOLD":                         "0.75",
+	"TRISA_WEB_ENABLED":                              "false",
+	"TRISA_WEB_API_ENABLED":                          "false",
+	"TRISA_WEB_UI_ENABLED":                           "false",
+	"TRISA_WEB_BIND_ADDR":                            ":4000",
+	"TRISA_WEB_ORIGIN":                               "https://example.com",
+	"TRISA_WEB_DOCS_NAME":                            "Test Server",
+	"TRISA_WEB_LOGO_URI":                             "/static/img/blockpass-logo.webp",
+	"TRISA_WEB_TRUSTED_PROXIES":                      "10.0.0.0/8,192.168.1.1",
+	"TRISA_WEB_AUTH_KEYS":                            "foo:/path/to/foo.pem,bar:/path/to/bar.pem",
+	"TRISA_WEB_AUTH_AUDIENCE":                        "https://example.com",
+	"TRISA_WEB_AUTH_ISSUER":                          "https://auth.example.com",
+	"TRISA_WEB_AUTH_COOKIE_DOMAIN":                   "example.com",
+	"TRISA_WEB_AUTH_ACCESS_TOKEN_TTL":                "24h",
+	"TRISA_WEB_AUTH_REFRESH_TOKEN_TTL":               "48h",
+	"TRISA_WEB_AUTH_TOKEN_OVERLAP":                   "-12h",
+	"TRISA_WEB_AUTH_KEY_ROTATION":                    "168h",
+	"TRISA_WEB_AUTH_MAX_LOGIN_FAILURES":              "3",
+	"TRISA_WEB_AUTH_PASSWORD_POLICY_MIN_LENGTH":      "12",
+	"TRISA_WEB_AUTH_PASSWORD_POLICY_REQUIRE_SPECIAL": "true",
+	"TRISA_WEB_AUTH_OIDC_ENABLED":                    "true",
+	"TRISA_WEB_AUTH_OIDC_ISSUER_URL":                 "https://idp.example.com",
+	"TRISA_WEB_AUTH_OIDC_CLIENT_ID":                  "envoy",
+	"TRISA_WEB_AUTH_OIDC_CLIENT_SECRET":              "supersecretsquirrel",
+	"TRISA_WEB_AUTH_OIDC_REDIRECT_URL":               "https://example.com/login/oidc/callback",
+	"TRISA_WEB_AUTH_OIDC_ROLE_MAPPING":               "envoy-admins:admin,envoy-compliance:compliance",
+	"TRISA_WEBHOOK_URL":                              "https://example.com/callback",
+	"TRISA_WEBHOOK_USE_MTLS":                         "true",
+	"TRISA_WEBHOOK_CERTS":                            "fixtures/certs/webhook/certs.pem",
+	"TRISA_WEBHOOK_POOL":                             "fixtures/certs/webhook/pool.pem",
+	"TRISA_WEBHOOK_AUTH_KEY_ID":                      "01JT4B3R5Z6AHJXV87QHPPKRBM",
+	"TRISA_WEBHOOK_AUTH_KEY_SECRET":                  "cfbabc4715b4759d45ba26953dd2fc0bfc2344ef70a2005432e7f16b5081610d",
+	"TRISA_WEBHOOK_REQUIRE_SERVER_AUTH":              "true",
+	"TRISA_NODE_ENABLED":                             "true",
+	"TRISA_NODE_BIND_ADDR":                           ":556",
+	"TRISA_NODE_POOL":                                "fixtures/certs/pool.gz",
+	"TRISA_NODE_CERTS":                               "fixtures/certs/certs.gz",
+	"TRISA_NODE_KEY_EXCHANGE_CACHE_TTL":              "5m",
+	"TRISA_NODE_DIRECTORY_INSECURE":                  "true",
+	"TRISA_NODE_DIRECTORY_ENDPOINT":                  "localhost:2525",
+	"TRISA_NODE_DIRECTORY_MEMBERS_ENDPOINT":          "localhost:2526",
+	"TRISA_NODE_PENDING_MESSAGE":                     "{{ .Organization }} is reviewing your request",
+	"TRISA_NODE_PENDING_MESSAGES":                    "de:{{ .Organization }} prüft Ihre Anfrage",
+	"TRISA_NODE_PENDING_REPLY_NOT_BEFORE":            "1m",
+	"TRISA_NODE_PENDING_REPLY_NOT_AFTER":             "48h",
+	"TRISA_DIRECTORY_SYNC_ENABLED":                   "true",
+	"TRISA_DIRECTORY_SYNC_INTERVAL":                  "10m",
+	"TRISA_RETENTION_ENABLED":                        "true",
+	"TRISA_RETENTION_INTERVAL":                       "1h",
+	"TRISA_RETENTION_CRYPTO_SHRED":                   "false",
+	"TRISA_RETENTION_TRANSACTIONS":                   "8760h",
+	"TRISA_RETENTION_ARCHIVES":                       "168h",
+	"TRISA_RETENTION_SECURE_ENVELOPES":               "4380h",
+	"TRISA_RETENTION_SUNRISE":                        "720h",
+	"TRISA_RETENTION_RESET_LINKS":                    "12h",
+	"TRISA_RETENTION_ACCOUNTS":                       "17520h",
+	"TRISA_DEADLINES_ENABLED":                        "true",
+	"TRISA_DEADLINES_INTERVAL":                       "10m",
+	"TRISA_DEADLINES_REMINDER_WINDOW":                "2h",
+	"TRISA_DEADLINES_ON_EXPIRY":                      "reject",
+	"TRISA_OUTBOUND_QUEUE_ENABLED":                   "true",
+	"TRISA_OUTBOUND_QUEUE_INTERVAL":                  "30s",
+	"TRISA_OUTBOUND_QUEUE_MAX_ATTEMPTS":              "5",
+	"TRISA_OUTBOUND_QUEUE_INITIAL_BACKOFF":           "2m",
+	"TRISA_OUTBOUND_QUEUE_MAX_BACKOFF":               "30m",
+	"TRISA_IDEMPOTENCY_ENABLED":                      "true",
+	"TRISA_IDEMPOTENCY_WINDOW":                       "48h",
+	"TRISA_BATCH_ENABLED":                            "true",
+	"TRISA_BATCH_CONCURRENCY":                        "8",
+	"TRISA_BATCH_MAX_ROWS":                           "500",
+	"TRISA_REPORTS_ENABLED":                          "true",
+	"TRISA_REPORTS_INTERVAL":                         "30m",
+	"TRISA_REPORTS_FREQUENCY":                        "weekly",
+	"TRISA_REPORTS_FORMAT":                           "html",
+	"TRISA_REPORTS_SECTIONS":                         "jurisdiction,response_times",
+	"TRISA_REPORTS_RECIPIENTS":                       "compliance@example.com,mlro@example.com",
+	"TRISA_THRESHOLDS_ENABLED":                       "true",
+	"TRISA_THRESHOLDS_CURRENCY":                      "EUR",
+	"TRISA_THRESHOLDS_RATES":                         "BTC:60000,ETH:3200.5",
+	"TRISA_TRP_ENABLED":                              "true",
+	"TRISA_TRP_BIND_ADDR":                            ":8012",
+	"TRISA_TRP_USE_MTLS":                             "false",
+	"TRISA_TRP_POOL":                                 "fixtures/certs/trp/pool.pem",
+	"TRISA_TRP_CERTS":                                "fixtures/certs/trp/certs.pem",
+	"TRISA_TRP_IDENTITY_VASP_NAME":                   "Testing VASP",
+	"TRISA_TRP_IDENTITY_LEI":                         "GTFZ00N6IHYMHHNT8S51",
+	"TRISA_SUNRISE_ENABLED":                          "false",
+	"TRISA_EMAIL_TESTING":                            "true",
+	"REGION_INFO_ID":                                 "2840302",
+	"REGION_INFO_NAME":                               "us-east4c",
+	"REGION_INFO_COUNTRY":                            "US",
+	"REGION_INFO_CLOUD":                              "GCP",
+	"REGION_INFO_CLUSTER":                            "rotational-testing-gke-9",
+	"TRISA_WEB_DAYBREAK_ENABLED":                     "true",
 }
 
 func TestConfig(t *testing.T) {
@@ -99,10 +146,31 @@ func TestConfig(t *testing.T) {
 	require.Equal(t, testEnv["TRISA_ENDPOINT"], conf.Web.TRISAEndpoint)
 	require.Equal(t, testEnv["TRISA_WEB_DOCS_NAME"], conf.Web.DocsName)
 	require.Equal(t, testEnv["TRISA_WEB_LOGO_URI"], conf.Web.LogoURI)
+	require.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, conf.Web.TrustedProxies)
 	require.Len(t, conf.Web.Auth.Keys, 2)
 	require.Equal(t, testEnv["TRISA_WEB_AUTH_AUDIENCE"], conf.Web.Auth.Audience)
 	require.Equal(t, testEnv["TRISA_WEB_AUTH_ISSUER"], conf.Web.Auth.Issuer)
 	require.Equal(t, testEnv["TRISA_WEB_AUTH_COOKIE_DOMAIN"], conf.Web.Auth.CookieDomain)
+	require.Equal(t, 168*time.Hour, conf.Web.Auth.KeyRotation)
+	require.Equal(t, int64(3), conf.Web.Auth.MaxLoginFailures)
+	require.Equal(t, 5*time.Minute, conf.Web.Auth.LockoutDuration)
+	require.Equal(t, 24*time.Hour, conf.Web.Auth.MaxLockoutDuration)
+	require.Equal(t, 72*time.Hour, conf.Web.Auth.InviteTTL)
+	require.Equal(t, 12, conf.Web.Auth.PasswordPolicy.MinLength)
+	require.Equal(t, uint8(3), conf.Web.Auth.PasswordPolicy.MinStrength)
+	require.True(t, conf.Web.Auth.PasswordPolicy.RequireSpecial)
+	require.False(t, conf.Web.Auth.PasswordPolicy.RequireUppercase)
+	require.False(t, conf.Web.Auth.DisablePasswordLogin)
+	require.True(t, conf.Web.Auth.OIDC.Enabled)
+	require.Equal(t, "Single Sign-On", conf.Web.Auth.OIDC.ProviderName)
+	require.Equal(t, testEnv["TRISA_WEB_AUTH_OIDC_ISSUER_URL"], conf.Web.Auth.OIDC.IssuerURL)
+	require.Equal(t, testEnv["TRISA_WEB_AUTH_OIDC_CLIENT_ID"], conf.Web.Auth.OIDC.ClientID)
+	require.Equal(t, testEnv["TRISA_WEB_AUTH_OIDC_CLIENT_SECRET"], conf.Web.Auth.OIDC.ClientSecret)
+	require.Equal(t, testEnv["TRISA_WEB_AUTH_OIDC_REDIRECT_URL"], conf.Web.Auth.OIDC.RedirectURL)
+	require.Equal(t, []string{"openid", "email", "profile"}, conf.Web.Auth.OIDC.Scopes)
+	require.Equal(t, "groups", conf.Web.Auth.OIDC.GroupsClaim)
+	require.Equal(t, map[string]string{"envoy-admins": "admin", "envoy-compliance": "compliance"}, conf.Web.Auth.OIDC.RoleMapping)
+	require.True(t, conf.Web.Auth.OIDC.Provision)
 	require.Equal(t, 24*time.Hour, conf.Web.Auth.AccessTokenTTL)
 	require.Equal(t, 48*time.Hour, conf.Web.Auth.RefreshTokenTTL)
 	require.Equal(t, -12*time.Hour, conf.Web.Auth.TokenOverlap)
@@ -123,8 +191,45 @@ func TestConfig(t *testing.T) {
 	require.True(t, conf.Node.Directory.Insecure)
 	require.Equal(t, testEnv["TRISA_NODE_DIRECTORY_ENDPOINT"], conf.Node.Directory.Endpoint)
 	require.Equal(t, testEnv["TRISA_NODE_DIRECTORY_MEMBERS_ENDPOINT"], conf.Node.Directory.MembersEndpoint)
+	require.Equal(t, conf.Organization, conf.Node.Pending.Organization)
+	require.Equal(t, testEnv["TRISA_NODE_PENDING_MESSAGE"], conf.Node.Pending.Message)
+	require.Equal(t, map[string]string{"de": "{{ .Organization }} prüft Ihre Anfrage"}, conf.Node.Pending.Messages)
+	require.Equal(t, 1*time.Minute, conf.Node.Pending.ReplyNotBefore)
+	require.Equal(t, 48*time.Hour, conf.Node.Pending.ReplyNotAfter)
 	require.True(t, conf.DirectorySync.Enabled)
 	require.Equal(t, 10*time.Minute, conf.DirectorySync.Interval)
+	require.True(t, conf.Retention.Enabled)
+	require.Equal(t, 1*time.Hour, conf.Retention.Interval)
+	require.False(t, conf.Retention.CryptoShred)
+	require.Equal(t, 8760*time.Hour, conf.Retention.Transactions)
+	require.Equal(t, 168*time.Hour, conf.Retention.Archives)
+	require.Equal(t, 4380*time.Hour, conf.Retention.SecureEnvelopes)
+	require.Equal(t, 720*time.Hour, conf.Retention.Sunrise)
+	require.Equal(t, 12*time.Hour, conf.Retention.ResetLinks)
+	require.Equal(t, 17520*time.Hour, conf.Retention.Accounts)
+	require.True(t, conf.Deadlines.Enabled)
+	require.Equal(t, 10*time.Minute, conf.Deadlines.Interval)
+	require.Equal(t, 2*time.Hour, conf.Deadlines.ReminderWindow)
+	require.Equal(t, config.OnExpiryReject, conf.Deadlines.OnExpiry)
+	require.True(t, conf.OutboundQueue.Enabled)
+	require.Equal(t, 30*time.Second, conf.OutboundQueue.Interval)
+	require.Equal(t, int64(5), conf.OutboundQueue.MaxAttempts)
+	require.Equal(t, 2*time.Minute, conf.OutboundQueue.InitialBackoff)
+	require.Equal(t, 30*time.Minute, conf.OutboundQueue.MaxBackoff)
+	require.True(t, conf.Idempotency.Enabled)
+	require.Equal(t, 48*time.Hour, conf.Idempotency.Window)
+	require.True(t, conf.Batch.Enabled)
+	require.Equal(t, 8, conf.Batch.Concurrency)
+	require.Equal(t, 500, conf.Batch.MaxRows)
+	require.True(t, conf.Reports.Enabled)
+	require.Equal(t, 30*time.Minute, conf.Reports.Interval)
+	require.Equal(t, config.ReportWeekly, conf.Reports.Frequency)
+	require.Equal(t, config.ReportHTML, conf.Reports.Format)
+	require.Equal(t, []string{"jurisdiction", "response_times"}, conf.Reports.Sections)
+	require.Equal(t, []string{"compliance@example.com", "mlro@example.com"}, conf.Reports.Recipients)
+	require.True(t, conf.Thresholds.Enabled)
+	require.Equal(t, "EUR", conf.Thresholds.Currency)
+	require.Equal(t, map[string]float64{"BTC": 60000, "ETH": 3200.5}, conf.Thresholds.Rates)
 	require.Equal(t, int32(2840302), conf.RegionInfo.ID)
 	require.True(t, conf.TRP.Maintenance)
 	require.True(t, conf.TRP.Enabled)
@@ -180,6 +285,390 @@ func TestConfigValidation(t *testing.T) {
 	})
 }
 
+func TestRetentionConfigValidation(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.RetentionConfig{Enabled: false, Interval: -1 * time.Hour}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		conf := config.RetentionConfig{
+			Enabled:      true,
+			Interval:     24 * time.Hour,
+			Transactions: 43800 * time.Hour,
+			Archives:     720 * time.Hour,
+		}
+		require.NoError(t, conf.Validate(), "expected valid config to be valid")
+	})
+
+	t.Run("BadInterval", func(t *testing.T) {
+		conf := config.RetentionConfig{Enabled: true}
+		require.EqualError(t, conf.Validate(), "invalid configuration: retention interval must be greater than zero")
+	})
+
+	t.Run("NegativePolicy", func(t *testing.T) {
+		conf := config.RetentionConfig{Enabled: true, Interval: time.Hour, Sunrise: -1 * time.Hour}
+		require.EqualError(t, conf.Validate(), "invalid configuration: retention policies cannot be negative")
+	})
+}
+
+func TestDeadlinesConfigValidation(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.DeadlinesConfig{Enabled: false, Interval: -1 * time.Hour}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		conf := config.DeadlinesConfig{Enabled: true, Interval: 5 * time.Minute, ReminderWindow: 4 * time.Hour, OnExpiry: config.OnExpiryExpire}
+		require.NoError(t, conf.Validate(), "expected valid config to be valid")
+	})
+
+	t.Run("BadInterval", func(t *testing.T) {
+		conf := config.DeadlinesConfig{Enabled: true, OnExpiry: config.OnExpiryExpire}
+		require.EqualError(t, conf.Validate(), "invalid configuration: deadlines interval must be greater than zero")
+	})
+
+	t.Run("NegativeWindow", func(t *testing.T) {
+		conf := config.DeadlinesConfig{Enabled: true, Interval: time.Minute, ReminderWindow: -1 * time.Hour, OnExpiry: config.OnExpiryExpire}
+		require.EqualError(t, conf.Validate(), "invalid configuration: deadlines reminder window cannot be negative")
+	})
+
+	t.Run("BadOnExpiry", func(t *testing.T) {
+		conf := config.DeadlinesConfig{Enabled: true, Interval: time.Minute, OnExpiry: "cancel"}
+		require.EqualError(t, conf.Validate(), `invalid configuration: unknown deadlines on expiry action "cancel"`)
+	})
+}
+
+func TestIdempotencyConfig(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.IdempotencyConfig{Enabled: false}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		conf := config.IdempotencyConfig{Enabled: true, Window: 24 * time.Hour}
+		require.NoError(t, conf.Validate(), "expected valid config to be valid")
+	})
+
+	t.Run("BadWindow", func(t *testing.T) {
+		conf := config.IdempotencyConfig{Enabled: true}
+		require.EqualError(t, conf.Validate(), "invalid configuration: idempotency window must be greater than zero")
+	})
+}
+
+func TestBatchConfig(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.BatchConfig{Enabled: false}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		conf := config.BatchConfig{Enabled: true, Concurrency: 4, MaxRows: 1000}
+		require.NoError(t, conf.Validate(), "expected valid config to be valid")
+	})
+
+	t.Run("BadConcurrency", func(t *testing.T) {
+		conf := config.BatchConfig{Enabled: true, MaxRows: 1000}
+		require.EqualError(t, conf.Validate(), "invalid configuration: batch concurrency must be greater than zero")
+	})
+
+	t.Run("BadMaxRows", func(t *testing.T) {
+		conf := config.BatchConfig{Enabled: true, Concurrency: 4}
+		require.EqualError(t, conf.Validate(), "invalid configuration: batch max rows must be greater than zero")
+	})
+}
+
+func TestReportsConfig(t *testing.T) {
+	valid := func() config.ReportsConfig {
+		return config.ReportsConfig{Enabled: true, Interval: time.Hour, Frequency: config.ReportMonthly, Format: config.ReportCSV, Recipients: []string{"compliance@example.com"}}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.ReportsConfig{Enabled: false, Frequency: "yearly"}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		require.NoError(t, valid().Validate(), "expected valid config to be valid")
+	})
+
+	t.Run("BadInterval", func(t *testing.T) {
+		conf := valid()
+		conf.Interval = 0
+		require.EqualError(t, conf.Validate(), "invalid configuration: reports interval must be greater than zero")
+	})
+
+	t.Run("BadFrequency", func(t *testing.T) {
+		conf := valid()
+		conf.Frequency = "yearly"
+		require.EqualError(t, conf.Validate(), `invalid configuration: unknown reports frequency "yearly"`)
+	})
+
+	t.Run("BadFormat", func(t *testing.T) {
+		conf := valid()
+		conf.Format = "pdf"
+		require.EqualError(t, conf.Validate(), `invalid configuration: unknown reports format "pdf"`)
+	})
+
+	t.Run("BadRecipient", func(t *testing.T) {
+		conf := valid()
+		conf.Recipients = append(conf.Recipients, "compliance")
+		require.EqualError(t, conf.Validate(), `invalid configuration: could not parse reports recipient "compliance"`)
+	})
+}
+
+func TestThresholdsConfig(t *testing.T) {
+	valid := func() config.ThresholdsConfig {
+		return config.ThresholdsConfig{Enabled: true, Currency: "USD", Rates: map[string]float64{"BTC": 65000}}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.ThresholdsConfig{Enabled: false, Currency: "dollars"}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		require.NoError(t, valid().Validate(), "expected valid config to be valid")
+	})
+
+	t.Run("BadCurrency", func(t *testing.T) {
+		conf := valid()
+		conf.Currency = "dollars"
+		require.EqualError(t, conf.Validate(), `invalid configuration: thresholds currency "dollars" must be an alpha-3 currency code`)
+	})
+
+	t.Run("BadRate", func(t *testing.T) {
+		conf := valid()
+		conf.Rates["ETH"] = 0
+		require.EqualError(t, conf.Validate(), `invalid configuration: thresholds rate of "ETH" must be greater than zero`)
+	})
+}
+
+func TestOutboundQueueConfig(t *testing.T) {
+	valid := func() config.OutboundQueueConfig {
+		return config.OutboundQueueConfig{Enabled: true, Interval: time.Minute, MaxAttempts: 10, InitialBackoff: time.Minute, MaxBackoff: time.Hour}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.OutboundQueueConfig{Enabled: false, Interval: -1 * time.Hour}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		conf := valid()
+		require.NoError(t, conf.Validate(), "expected valid config to be valid")
+	})
+
+	t.Run("BadInterval", func(t *testing.T) {
+		conf := valid()
+		conf.Interval = 0
+		require.EqualError(t, conf.Validate(), "invalid configuration: outbound queue interval must be greater than zero")
+	})
+
+	t.Run("BadMaxAttempts", func(t *testing.T) {
+		conf := valid()
+		conf.MaxAttempts = 0
+		require.EqualError(t, conf.Validate(), "invalid configuration: outbound queue max attempts must be greater than zero")
+	})
+
+	t.Run("BadBackoff", func(t *testing.T) {
+		conf := valid()
+		conf.MaxBackoff = 30 * time.Second
+		require.EqualError(t, conf.Validate(), "invalid configuration: outbound queue max backoff must be greater than the initial backoff")
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		conf := valid()
+		tests := []struct {
+			attempts int64
+			expected time.Duration
+		}{
+			{0, time.Minute},
+			{1, time.Minute},
+			{2, 2 * time.Minute},
+			{3, 4 * time.Minute},
+			{6, 32 * time.Minute},
+			{7, time.Hour},
+			{100, time.Hour},
+		}
+
+		for i, test := range tests {
+			require.Equal(t, test.expected, conf.Backoff(test.attempts), "test case %d failed", i)
+		}
+	})
+}
+
+func TestPendingConfig(t *testing.T) {
+	valid := func() config.PendingConfig {
+		return config.PendingConfig{
+			Organization:   "Alice VASP",
+			Message:        "{{ .Organization }} is reviewing your request",
+			Messages:       map[string]string{"de": "{{ .Organization }} prüft Ihre Anfrage", "fr-CA": "{{ .Organization }} examine votre demande"},
+			ReplyNotBefore: 5 * time.Minute,
+			ReplyNotAfter:  24 * time.Hour,
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		conf := valid()
+		require.NoError(t, conf.Validate(), "expected valid config to be valid")
+
+		conf.ReplyNotAfter = 0
+		require.NoError(t, conf.Validate(), "expected no reply deadline to be valid")
+	})
+
+	t.Run("NegativeWindow", func(t *testing.T) {
+		conf := valid()
+		conf.ReplyNotBefore = -1 * time.Minute
+		require.EqualError(t, conf.Validate(), "invalid configuration: pending reply windows cannot be negative")
+	})
+
+	t.Run("WindowOrder", func(t *testing.T) {
+		conf := valid()
+		conf.ReplyNotAfter = time.Minute
+		require.EqualError(t, conf.Validate(), "invalid configuration: pending reply not after window must not be before the reply not before window")
+	})
+
+	t.Run("BadTemplate", func(t *testing.T) {
+		conf := valid()
+		conf.Message = "{{ .Organization"
+		require.ErrorContains(t, conf.Validate(), "invalid configuration: could not parse pending message template")
+
+		conf = valid()
+		conf.Messages["es"] = "{{ end }}"
+		require.ErrorContains(t, conf.Validate(), "invalid configuration: es: could not parse pending message template")
+
+		conf = valid()
+		conf.Message = "{{ .Unknown }}"
+		require.ErrorContains(t, conf.Validate(), "invalid configuration: could not render pending message template")
+	})
+
+	t.Run("BadLanguage", func(t *testing.T) {
+		conf := valid()
+		conf.Messages["not a language"] = "hello"
+		require.EqualError(t, conf.Validate(), `invalid configuration: "not a language" is not a valid pending message language`)
+	})
+
+	t.Run("Template", func(t *testing.T) {
+		conf := valid()
+		tests := []struct {
+			lang     string
+			expected string
+		}{
+			{"", conf.Message},
+			{"en", conf.Message},
+			{"de", conf.Messages["de"]},
+			{"de-CH", conf.Messages["de"]},
+			{"fr-CA", conf.Messages["fr-CA"]},
+			{"fr", conf.Message},
+			{"invalid tag", conf.Message},
+		}
+
+		for _, tc := range tests {
+			require.Equal(t, tc.expected, conf.Template(tc.lang), "unexpected template for %q", tc.lang)
+		}
+	})
+}
+
+func TestFieldEncryptionConfigValidation(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.FieldEncryptionConfig{Enabled: false, Key: "notbase64"}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("KeyChain", func(t *testing.T) {
+		conf := config.FieldEncryptionConfig{Enabled: true}
+		require.NoError(t, conf.Validate(), "expected keychain config to be valid")
+	})
+
+	t.Run("Key", func(t *testing.T) {
+		conf := config.FieldEncryptionConfig{Enabled: true, Key: "QkJCQkJCQkJCQkJCQkJCQkJCQkJCQkJCQkJCQkJCQkI="}
+		require.NoError(t, conf.Validate(), "expected key config to be valid")
+	})
+
+	t.Run("BadKey", func(t *testing.T) {
+		conf := config.FieldEncryptionConfig{Enabled: true, Key: "QkJCQkJC"}
+		require.EqualError(t, conf.Validate(), "invalid configuration: field encryption key must be a base64 encoded 32 byte key")
+	})
+
+	t.Run("MissingProject", func(t *testing.T) {
+		conf := config.FieldEncryptionConfig{Enabled: true, SecretName: "envoy-pii-key"}
+		require.EqualError(t, conf.Validate(), "invalid configuration: field encryption secret project is required with a secret name")
+	})
+
+	t.Run("PreviousKeys", func(t *testing.T) {
+		conf := config.FieldEncryptionConfig{Enabled: true, PreviousKeys: []string{"QkJCQkJCQkJCQkJCQkJCQkJCQkJCQkJCQkJCQkJCQkI="}}
+		require.NoError(t, conf.Validate(), "expected previous keys config to be valid")
+
+		conf.PreviousKeys = append(conf.PreviousKeys, "QkJCQkJC")
+		require.EqualError(t, conf.Validate(), "invalid configuration: previous field encryption keys must be base64 encoded 32 byte keys")
+	})
+}
+
+func TestAuthConfigValidation(t *testing.T) {
+	valid := config.OIDCConfig{
+		Enabled:     true,
+		IssuerURL:   "https://idp.example.com",
+		ClientID:    "envoy",
+		RedirectURL: "https://example.com/login/oidc/callback",
+		Scopes:      []string{"openid", "email"},
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		conf := config.AuthConfig{OIDC: config.OIDCConfig{Enabled: false}}
+		require.NoError(t, conf.Validate(), "expected disabled config to be valid")
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		conf := config.AuthConfig{OIDC: valid}
+		require.NoError(t, conf.Validate(), "expected valid config to be valid")
+
+		conf.DisablePasswordLogin = true
+		require.NoError(t, conf.Validate(), "expected password login to be disabled with sso")
+	})
+
+	t.Run("PasswordLogin", func(t *testing.T) {
+		conf := config.AuthConfig{DisablePasswordLogin: true}
+		require.EqualError(t, conf.Validate(), "invalid configuration: single sign-on must be enabled if password login is disabled")
+	})
+
+	t.Run("KeyRotation", func(t *testing.T) {
+		conf := config.AuthConfig{KeyRotation: 30 * time.Minute}
+		require.EqualError(t, conf.Validate(), "invalid configuration: signing key rotation must be at least 1h0m0s")
+	})
+
+	t.Run("Lockout", func(t *testing.T) {
+		conf := config.AuthConfig{MaxLoginFailures: -1}
+		require.EqualError(t, conf.Validate(), "invalid configuration: max login failures must not be negative")
+
+		conf = config.AuthConfig{MaxLoginFailures: 5, LockoutDuration: 1 * time.Hour, MaxLockoutDuration: 30 * time.Minute}
+		require.EqualError(t, conf.Validate(), "invalid configuration: lockout duration must be positive and no more than the max lockout duration")
+
+		conf.MaxLockoutDuration = 24 * time.Hour
+		require.NoError(t, conf.Validate())
+	})
+
+	t.Run("PasswordPolicy", func(t *testing.T) {
+		conf := config.AuthConfig{PasswordPolicy: config.PasswordPolicyConfig{MinLength: 6}}
+		require.EqualError(t, conf.Validate(), "invalid configuration: password policy min length must be at least 8")
+
+		conf = config.AuthConfig{PasswordPolicy: config.PasswordPolicyConfig{MinLength: 8, MinStrength: 6}}
+		require.EqualError(t, conf.Validate(), "invalid configuration: password policy min strength must be at most 5")
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		conf := config.AuthConfig{OIDC: valid}
+		conf.OIDC.ClientID = ""
+		require.EqualError(t, conf.Validate(), "invalid configuration: oidc issuer url, client id, and redirect url are required")
+	})
+
+	t.Run("Scopes", func(t *testing.T) {
+		conf := config.AuthConfig{OIDC: valid}
+		conf.OIDC.Scopes = []string{"email"}
+		require.EqualError(t, conf.Validate(), "invalid configuration: oidc scopes must include openid")
+	})
+}
+
 func TestConfigNestedCerts(t *testing.T) {
 	t.Run("Specified", func(t *testing.T) {
 		t.Cleanup(cleanupEnv())
@@ -299,6 +788,14 @@ func TestWebConfig(t *testing.T) {
 				BindAddr:   "127.0.0.1:0",
 				Origin:     "http://localhost",
 			},
+			{
+				Enabled:        true,
+				APIEnabled:     true,
+				UIEnabled:      true,
+				BindAddr:       "127.0.0.1:0",
+				Origin:         "http://localhost",
+				TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1", "::1"},
+			},
 		}
 
 		for _, conf := range testCases {
@@ -339,6 +836,17 @@ func TestWebConfig(t *testing.T) {
 				},
 				errString: "invalid configuration: if enabled, either the api, ui, or both need to be enabled",
 			},
+			{
+				conf: config.WebConfig{
+					Enabled:        true,
+					APIEnabled:     true,
+					UIEnabled:      true,
+					BindAddr:       "127.0.0.1:0",
+					Origin:         "http://localhost",
+					TrustedProxies: []string{"10.0.0.0/33"},
+				},
+				errString: "invalid configuration: could not parse trusted proxy \"10.0.0.0/33\"",
+			},
 		}
 
 		for i, tc := range testCases {
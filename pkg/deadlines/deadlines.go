@@ -0,0 +1,298 @@
+package deadlines
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/emails"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/webhook"
+)
+
+// Scheduler is a background routine that checks the reply deadlines of transactions
+// that are awaiting a reply at a specified interval. A reminder is sent once when the
+// deadline is within the reminder window; when the deadline passes the transaction is
+// marked as expired and, if configured, rejected when the local node owes the reply.
+// Reminders and expirations are emailed to the assignee of the transaction (or to all
+// users that can manage transactions if it is unassigned) and sent to the webhook.
+type Scheduler struct {
+	sync.Mutex
+	conf     config.DeadlinesConfig
+	web      config.WebConfig
+	support  string
+	store    Store
+	webhook  webhook.Handler
+	rejecter Rejecter
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// Store is the subset of the store required by the deadlines service.
+type Store interface {
+	store.ReplyDeadlineStore
+	RetrieveUser(ctx context.Context, emailOrUserID any) (*models.User, error)
+}
+
+// Rejecter sends a rejection to the counterparty of a transaction whose reply deadline
+// was missed by the local node; it is implemented by the web server.
+type Rejecter interface {
+	RejectExpiredTransaction(ctx context.Context, transactionID uuid.UUID) error
+}
+
+// Creates a new reply deadlines service but does not run it. The webhook is optional;
+// the rejecter is only required if transactions are rejected on expiry.
+func New(conf config.Config, store Store, hook webhook.Handler, rejecter Rejecter) (*Scheduler, error) {
+	// Only return a deadlines stub if not enabled
+	if !conf.Deadlines.Enabled {
+		return &Scheduler{conf: conf.Deadlines}, nil
+	}
+
+	return &Scheduler{
+		conf:     conf.Deadlines,
+		web:      conf.Web,
+		support:  conf.Email.SupportEmail,
+		store:    store,
+		webhook:  hook,
+		rejecter: rejecter,
+	}, nil
+}
+
+// Run the reply deadlines service.
+func (s *Scheduler) Run() error {
+	// Do not run the service if deadlines are not enabled.
+	if !s.conf.Enabled {
+		return nil
+	}
+
+	// Lock the deadlines routine to initialize and start it.
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stop != nil {
+		return ErrDeadlinesAlreadyRunning
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return nil
+}
+
+func (s *Scheduler) run() {
+	ticker := time.NewTicker(s.conf.Interval)
+	log.Info().Dur("deadlines_interval", s.conf.Interval).Msg("reply deadlines service running")
+
+	// Check the deadlines at startup. Errors are not fatal since the deadlines will be
+	// checked again on the next interval.
+	if err := s.Check(); err != nil {
+		log.Warn().Err(err).Msg("could not check reply deadlines")
+	}
+
+deadlinesloop:
+	for {
+		select {
+		case <-s.stop:
+			break deadlinesloop
+		case <-ticker.C:
+			if err := s.Check(); err != nil {
+				log.Warn().Err(err).Msg("could not check reply deadlines")
+			}
+		}
+	}
+
+	ticker.Stop()
+	close(s.done)
+	log.Info().Msg("reply deadlines service stopped")
+}
+
+// Stop the reply deadlines service, blocking until the service is shutdown.
+func (s *Scheduler) Stop() error {
+	// Do not stop the deadlines service if it is not enabled
+	if !s.conf.Enabled {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stop == nil {
+		return ErrDeadlinesNotRunning
+	}
+
+	// Send the stop signal and wait for routine to stop.
+	close(s.stop)
+	<-s.done
+
+	s.stop = nil
+	s.done = nil
+	return nil
+}
+
+// Check lists the transactions whose reply deadline is within the reminder window,
+// expiring the transactions whose deadline has passed and sending reminders for the
+// others if a reminder has not already been sent. Each transaction is handled in its
+// own database transaction; all errors are joined and returned once every transaction
+// has been checked.
+func (s *Scheduler) Check() (err error) {
+	log.Debug().Msg("starting reply deadlines check")
+
+	// Add actor information to the context for the audit log
+	ctx := audit.WithActor(context.Background(), []byte("Scheduler.Check()"), enum.ActorSystem)
+
+	now := time.Now()
+	var transactions []*models.Transaction
+	if transactions, err = s.store.ListReplyDeadlines(ctx, now.Add(s.conf.ReminderWindow)); err != nil {
+		return err
+	}
+
+	var reminded, expired, rejected int
+	for _, transaction := range transactions {
+		switch {
+		case now.After(transaction.ReplyNotAfter.Time):
+			wasRejected, xerr := s.expire(ctx, transaction)
+			if xerr != nil {
+				log.Warn().Err(xerr).Str("transaction_id", transaction.ID.String()).Msg("could not expire transaction reply deadline")
+				err = errors.Join(err, xerr)
+				continue
+			}
+
+			expired++
+			if wasRejected {
+				rejected++
+			}
+		case !transaction.ReplyRemindedOn().Valid:
+			if rerr := s.remind(ctx, transaction); rerr != nil {
+				log.Warn().Err(rerr).Str("transaction_id", transaction.ID.String()).Msg("could not send transaction reply reminder")
+				err = errors.Join(err, rerr)
+				continue
+			}
+			reminded++
+		}
+	}
+
+	log.Info().
+		Int("reminded", reminded).
+		Int("expired", expired).
+		Int("rejected", rejected).
+		Msg("reply deadlines check complete")
+	return err
+}
+
+func (s *Scheduler) remind(ctx context.Context, transaction *models.Transaction) (err error) {
+	// Mark the reminder first so that a failure to notify does not cause the reminder
+	// to be sent again on the next interval.
+	if err = s.store.MarkReplyReminded(ctx, transaction.ID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Scheduler.Check()"},
+	}); err != nil {
+		return err
+	}
+
+	s.notify(ctx, transaction, webhook.EventReplyReminder, false)
+	return nil
+}
+
+func (s *Scheduler) expire(ctx context.Context, transaction *models.Transaction) (rejected bool, err error) {
+	// If configured, reject the transfer when the local node missed the deadline; if
+	// the rejection fails the transaction is still marked as expired.
+	if s.conf.OnExpiry == config.OnExpiryReject && transaction.Status == enum.StatusReview && s.rejecter != nil {
+		if rerr := s.rejecter.RejectExpiredTransaction(ctx, transaction.ID); rerr != nil {
+			log.Warn().Err(rerr).Str("transaction_id", transaction.ID.String()).Msg("could not reject expired transaction")
+		} else {
+			rejected = true
+			transaction.Status = enum.StatusRejected
+		}
+	}
+
+	if err = s.store.MarkReplyExpired(ctx, transaction.ID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Scheduler.Check()"},
+	}); err != nil {
+		return rejected, err
+	}
+
+	s.notify(ctx, transaction, webhook.EventReplyExpired, rejected)
+	return rejected, nil
+}
+
+// Sends the event to the webhook and emails the assignee of the transaction or all of
+// the users that can manage transactions. Errors are logged but not returned since the
+// reminder or expiration has already been recorded.
+func (s *Scheduler) notify(ctx context.Context, transaction *models.Transaction, event string, rejected bool) {
+	expired := event == webhook.EventReplyExpired
+	replyStatus := models.ReplyDueSoon
+	if expired {
+		replyStatus = models.ReplyExpired
+	}
+
+	if s.webhook != nil {
+		if err := s.webhook.Notify(ctx, &webhook.Event{
+			Type:          event,
+			TransactionID: transaction.ID,
+			Timestamp:     time.Now().Format(time.RFC3339),
+			Status:        transaction.Status.String(),
+			Counterparty:  transaction.Counterparty,
+			ReplyNotAfter: transaction.ReplyNotAfter.Time.Format(time.RFC3339),
+			ReplyStatus:   replyStatus,
+		}); err != nil {
+			log.Warn().Err(err).Str("transaction_id", transaction.ID.String()).Msg("could not send reply deadline webhook event")
+		}
+	}
+
+	recipients, err := s.recipients(ctx, transaction)
+	if err != nil {
+		log.Warn().Err(err).Str("transaction_id", transaction.ID.String()).Msg("could not list reply deadline recipients")
+		return
+	}
+
+	data := emails.ReplyDeadlineEmailData{
+		Counterparty:   transaction.Counterparty,
+		VirtualAsset:   transaction.VirtualAsset,
+		AwaitingLocal:  transaction.Status != enum.StatusPending,
+		Expired:        expired,
+		Rejected:       rejected,
+		ReplyNotAfter:  transaction.ReplyNotAfter.Time,
+		TransactionURL: s.web.TransactionURL(transaction.ID.String()),
+		SupportEmail:   s.support,
+	}
+
+	if transaction.Amount != 0 {
+		data.Amount = strconv.FormatFloat(transaction.Amount, 'f', -1, 64)
+	}
+
+	for _, recipient := range recipients {
+		data.ContactName = recipient.Name.String
+
+		var email *emails.Email
+		if email, err = emails.NewReplyDeadlineEmail(recipient.Email, data); err != nil {
+			log.Warn().Err(err).Str("transaction_id", transaction.ID.String()).Msg("could not create reply deadline email")
+			continue
+		}
+
+		if err = email.Send(); err != nil {
+			log.Warn().Err(err).Str("transaction_id", transaction.ID.String()).Msg("could not send reply deadline email")
+		}
+	}
+}
+
+// Returns the assignee of the transaction or all of the users that can manage
+// transactions if the transaction is unassigned.
+func (s *Scheduler) recipients(ctx context.Context, transaction *models.Transaction) ([]*models.User, error) {
+	if assignee := transaction.AssigneeID(); assignee.Valid {
+		user, err := s.store.RetrieveUser(ctx, assignee.ULID)
+		if err != nil {
+			return nil, err
+		}
+		return []*models.User{user}, nil
+	}
+	return s.store.ListTransactionReviewers(ctx)
+}
@@ -0,0 +1,179 @@
+package deadlines_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/deadlines"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	store "github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/webhook"
+)
+
+func TestStartStop(t *testing.T) {
+	db := mockStore(t, nil)
+	conf := config.Config{
+		Deadlines: config.DeadlinesConfig{Enabled: true, Interval: time.Hour, ReminderWindow: time.Hour, OnExpiry: config.OnExpiryExpire},
+	}
+
+	svc, err := deadlines.New(conf, db, nil, nil)
+	require.NoError(t, err, "could not create deadlines service")
+
+	require.ErrorIs(t, svc.Stop(), deadlines.ErrDeadlinesNotRunning)
+	require.NoError(t, svc.Run(), "could not run deadlines service")
+	require.ErrorIs(t, svc.Run(), deadlines.ErrDeadlinesAlreadyRunning)
+	require.NoError(t, svc.Stop(), "could not stop deadlines service")
+
+	db.AssertCalls(t, "ListReplyDeadlines", 1)
+}
+
+func TestDisabled(t *testing.T) {
+	svc, err := deadlines.New(config.Config{}, nil, nil, nil)
+	require.NoError(t, err, "could not create deadlines service")
+	require.NoError(t, svc.Run(), "expected no error running disabled service")
+	require.NoError(t, svc.Stop(), "expected no error stopping disabled service")
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	reminded := sql.NullTime{Valid: true, Time: now.Add(-1 * time.Hour)}
+
+	// Due soon and not yet reminded
+	dueSoon := &models.Transaction{ID: uuid.New(), Status: enum.StatusReview, ReplyNotAfter: sql.NullTime{Valid: true, Time: now.Add(time.Hour)}}
+
+	// Due soon and already reminded
+	alreadyReminded := &models.Transaction{ID: uuid.New(), Status: enum.StatusPending, ReplyNotAfter: sql.NullTime{Valid: true, Time: now.Add(time.Hour)}}
+	alreadyReminded.SetReplyState(reminded, sql.NullTime{})
+
+	// Overdue transactions awaiting the local node and the counterparty
+	overdueLocal := &models.Transaction{ID: uuid.New(), Status: enum.StatusReview, ReplyNotAfter: sql.NullTime{Valid: true, Time: now.Add(-1 * time.Hour)}}
+	overdueRemote := &models.Transaction{ID: uuid.New(), Status: enum.StatusPending, ReplyNotAfter: sql.NullTime{Valid: true, Time: now.Add(-1 * time.Hour)}}
+	overdueRemote.SetReplyState(reminded, sql.NullTime{})
+
+	transactions := []*models.Transaction{overdueLocal, overdueRemote, dueSoon, alreadyReminded}
+
+	t.Run("Expire", func(t *testing.T) {
+		calls := make(map[string][]uuid.UUID)
+		db := mockStore(t, calls)
+		db.OnListReplyDeadlines = func(ctx context.Context, dueBefore time.Time) ([]*models.Transaction, error) {
+			require.WithinDuration(t, now.Add(4*time.Hour), dueBefore, time.Minute, "unexpected reminder window")
+			return transactions, nil
+		}
+
+		hook := webhook.NewMock()
+		events := make(map[uuid.UUID]string)
+		hook.OnNotify = func(_ context.Context, event *webhook.Event) error {
+			events[event.TransactionID] = event.Type
+			return nil
+		}
+
+		rejecter := &mockRejecter{}
+		conf := config.Config{
+			Deadlines: config.DeadlinesConfig{Enabled: true, Interval: time.Minute, ReminderWindow: 4 * time.Hour, OnExpiry: config.OnExpiryExpire},
+		}
+
+		svc, _ := deadlines.New(conf, db, hook, rejecter)
+		require.NoError(t, svc.Check(), "could not check reply deadlines")
+
+		require.Equal(t, []uuid.UUID{dueSoon.ID}, calls["MarkReplyReminded"])
+		require.Equal(t, []uuid.UUID{overdueLocal.ID, overdueRemote.ID}, calls["MarkReplyExpired"])
+		require.Len(t, rejecter.rejected, 0, "expected no transactions to be rejected")
+
+		require.Equal(t, 3, hook.Notifies)
+		require.Equal(t, webhook.EventReplyReminder, events[dueSoon.ID])
+		require.Equal(t, webhook.EventReplyExpired, events[overdueLocal.ID])
+		require.Equal(t, webhook.EventReplyExpired, events[overdueRemote.ID])
+		db.AssertCalls(t, "ListTransactionReviewers", 3)
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		calls := make(map[string][]uuid.UUID)
+		db := mockStore(t, calls)
+		db.OnListReplyDeadlines = func(context.Context, time.Time) ([]*models.Transaction, error) {
+			return []*models.Transaction{overdueLocal, overdueRemote}, nil
+		}
+
+		rejecter := &mockRejecter{}
+		conf := config.Config{
+			Deadlines: config.DeadlinesConfig{Enabled: true, Interval: time.Minute, ReminderWindow: 4 * time.Hour, OnExpiry: config.OnExpiryReject},
+		}
+
+		svc, _ := deadlines.New(conf, db, nil, rejecter)
+		require.NoError(t, svc.Check(), "could not check reply deadlines")
+
+		// Only transactions that the local node owes a reply to are rejected
+		require.Equal(t, []uuid.UUID{overdueLocal.ID}, rejecter.rejected)
+		require.Equal(t, []uuid.UUID{overdueLocal.ID, overdueRemote.ID}, calls["MarkReplyExpired"])
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		db := mockStore(t, nil)
+		db.OnListReplyDeadlines = func(context.Context, time.Time) ([]*models.Transaction, error) {
+			return []*models.Transaction{overdueRemote, dueSoon}, nil
+		}
+		db.OnMarkReplyExpired = func(context.Context, uuid.UUID, *models.ComplianceAuditLog) error {
+			return errors.New("database is locked")
+		}
+
+		hook := webhook.NewMock()
+		hook.OnNotify = func(context.Context, *webhook.Event) error { return nil }
+
+		conf := config.Config{
+			Deadlines: config.DeadlinesConfig{Enabled: true, Interval: time.Minute, ReminderWindow: 4 * time.Hour, OnExpiry: config.OnExpiryExpire},
+		}
+
+		svc, _ := deadlines.New(conf, db, hook, nil)
+		require.EqualError(t, svc.Check(), "database is locked")
+
+		// Other transactions are still checked when one of them fails and no
+		// notification is sent for the transaction that could not be expired
+		db.AssertCalls(t, "MarkReplyReminded", 1)
+		require.Equal(t, 1, hook.Notifies)
+	})
+}
+
+type mockRejecter struct {
+	rejected []uuid.UUID
+}
+
+func (m *mockRejecter) RejectExpiredTransaction(_ context.Context, transactionID uuid.UUID) error {
+	m.rejected = append(m.rejected, transactionID)
+	return nil
+}
+
+func mockStore(t *testing.T, calls map[string][]uuid.UUID) *store.Store {
+	db, err := store.Open(nil)
+	require.NoError(t, err, "could not open mock store")
+
+	mark := func(name string) func(context.Context, uuid.UUID, *models.ComplianceAuditLog) error {
+		return func(ctx context.Context, transactionID uuid.UUID, log *models.ComplianceAuditLog) error {
+			actorType, ok := audit.ActorType(ctx)
+			require.True(t, ok, "expected actor type in context")
+			require.Equal(t, enum.ActorSystem, actorType, "expected system actor for audit logs")
+			require.True(t, log.ChangeNotes.Valid, "expected change notes for audit logs")
+
+			if calls != nil {
+				calls[name] = append(calls[name], transactionID)
+			}
+			return nil
+		}
+	}
+
+	db.OnListReplyDeadlines = func(context.Context, time.Time) ([]*models.Transaction, error) {
+		return nil, nil
+	}
+	db.OnMarkReplyReminded = mark("MarkReplyReminded")
+	db.OnMarkReplyExpired = mark("MarkReplyExpired")
+	db.OnListTransactionReviewers = func(context.Context) ([]*models.User, error) {
+		return nil, nil
+	}
+	return db
+}
@@ -0,0 +1,8 @@
+package deadlines
+
+import "errors"
+
+var (
+	ErrDeadlinesAlreadyRunning = errors.New("deadlines service is already running")
+	ErrDeadlinesNotRunning     = errors.New("deadlines service is not running")
+)
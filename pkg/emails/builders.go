@@ -2,6 +2,7 @@ package emails
 
 import (
 	"net/url"
+	"time"
 
 	"go.rtnl.ai/x/vero"
 )
@@ -95,3 +96,168 @@ func (s ResetPasswordEmailData) VerifyURL() string {
 	s.BaseURL.RawQuery = params.Encode()
 	return s.BaseURL.String()
 }
+
+// ===========================================================================
+// User Invite Email
+// ===========================================================================
+
+const (
+	UserInviteRE       = "You have been invited to TRISA Envoy"
+	UserInviteTemplate = "user_invite"
+)
+
+func NewUserInviteEmail(recipient string, data UserInviteEmailData) (*Email, error) {
+	return New(recipient, UserInviteRE, UserInviteTemplate, data)
+}
+
+// UserInviteEmailData is used to complete the user_invite template.
+type UserInviteEmailData struct {
+	ContactName    string                 // the invitee's name, if available
+	ComplianceName string                 // the organization running the Envoy node
+	Role           string                 // the role the invitee will be assigned
+	Expiration     time.Time              // when the invite link expires
+	BaseURL        *url.URL               // the Envoy node's url
+	Token          vero.VerificationToken // verification token for the invite record
+	SupportEmail   string                 // the Envoy node's support email address
+}
+
+func (s UserInviteEmailData) VerifyURL() string {
+	if s.BaseURL == nil {
+		return ""
+	}
+
+	params := make(url.Values, 1)
+	params.Set("token", s.Token.String())
+
+	s.BaseURL.RawQuery = params.Encode()
+	return s.BaseURL.String()
+}
+
+// ExpiresOn returns a human readable expiration date for the invite link.
+func (s UserInviteEmailData) ExpiresOn() string {
+	return s.Expiration.UTC().Format("January 2, 2006 at 15:04 MST")
+}
+
+// ===========================================================================
+// Approval Request Email
+// ===========================================================================
+
+const (
+	ApprovalRequestRE       = "TRISA Envoy transfer awaiting your approval"
+	ApprovalRequestTemplate = "approval_request"
+)
+
+func NewApprovalRequestEmail(recipient string, data ApprovalRequestEmailData) (*Email, error) {
+	return New(recipient, ApprovalRequestRE, ApprovalRequestTemplate, data)
+}
+
+// ApprovalRequestEmailData is used to complete the approval_request template.
+type ApprovalRequestEmailData struct {
+	ContactName  string   // the reviewer's name, if available
+	RequestedBy  string   // the name of the user or api key that requested the action
+	Action       string   // the transfer workflow action that requires approval
+	Counterparty string   // the counterparty of the transfer, if known
+	VirtualAsset string   // the network and asset type of the transfer, if known
+	Amount       string   // the formatted amount of the transfer, if known
+	Rule         string   // the description of the approval rule that matched
+	ApprovalsURL *url.URL // the url of the approvals page of the Envoy node
+	SupportEmail string   // the Envoy node's support email address
+}
+
+func (s ApprovalRequestEmailData) ReviewURL() string {
+	if s.ApprovalsURL == nil {
+		return ""
+	}
+	return s.ApprovalsURL.String()
+}
+
+// ===========================================================================
+// Reply Deadline Email
+// ===========================================================================
+
+const (
+	ReplyReminderRE       = "TRISA Envoy transfer reply deadline approaching"
+	ReplyExpiredRE        = "TRISA Envoy transfer reply deadline has passed"
+	ReplyDeadlineTemplate = "reply_deadline"
+)
+
+// NewReplyDeadlineEmail returns a reminder email if the deadline has not yet passed,
+// otherwise an email notifying the recipient that the deadline has expired.
+func NewReplyDeadlineEmail(recipient string, data ReplyDeadlineEmailData) (*Email, error) {
+	subject := ReplyReminderRE
+	if data.Expired {
+		subject = ReplyExpiredRE
+	}
+	return New(recipient, subject, ReplyDeadlineTemplate, data)
+}
+
+// ReplyDeadlineEmailData is used to complete the reply_deadline template.
+type ReplyDeadlineEmailData struct {
+	ContactName    string    // the reviewer's name, if available
+	Counterparty   string    // the counterparty of the transfer, if known
+	VirtualAsset   string    // the network and asset type of the transfer, if known
+	Amount         string    // the formatted amount of the transfer, if known
+	AwaitingLocal  bool      // true if the reply is owed by the local node rather than the counterparty
+	Expired        bool      // true if the deadline has passed
+	Rejected       bool      // true if the transfer was automatically rejected
+	ReplyNotAfter  time.Time // the deadline of the reply
+	TransactionURL *url.URL  // the url of the transaction detail page of the Envoy node
+	SupportEmail   string    // the Envoy node's support email address
+}
+
+func (s ReplyDeadlineEmailData) ReviewURL() string {
+	if s.TransactionURL == nil {
+		return ""
+	}
+	return s.TransactionURL.String()
+}
+
+// Deadline returns a human readable reply deadline.
+func (s ReplyDeadlineEmailData) Deadline() string {
+	return s.ReplyNotAfter.UTC().Format("January 2, 2006 at 15:04 MST")
+}
+
+// ===========================================================================
+// Report Email
+// ===========================================================================
+
+const (
+	ReportRE       = "TRISA Envoy travel rule report"
+	ReportTemplate = "report"
+)
+
+// NewReportEmail returns an email notifying the recipient that a scheduled report has
+// been generated; the rendered report should be attached to the email by the caller.
+func NewReportEmail(recipient string, data ReportEmailData) (*Email, error) {
+	return New(recipient, ReportRE+": "+data.ReportName, ReportTemplate, data)
+}
+
+// ReportEmailData is used to complete the report template.
+type ReportEmailData struct {
+	ReportName   string    // the name of the generated report
+	PeriodStart  time.Time // the start of the reporting period (inclusive)
+	PeriodEnd    time.Time // the end of the reporting period (exclusive)
+	Transfers    int       // the number of transfers in the reporting period
+	Attachment   string    // the filename of the attached report
+	ReportURL    *url.URL  // the url to download the report from the Envoy node
+	SupportEmail string    // the Envoy node's support email address
+}
+
+func (s ReportEmailData) DownloadURL() string {
+	if s.ReportURL == nil {
+		return ""
+	}
+	return s.ReportURL.String()
+}
+
+// Period returns a human readable reporting period; since the end of the period is
+// exclusive the last day of the period is the day before the end.
+func (s ReportEmailData) Period() string {
+	if s.PeriodStart.IsZero() || s.PeriodEnd.IsZero() {
+		return ""
+	}
+
+	const layout = "January 2, 2006"
+	last := s.PeriodEnd.UTC().Add(-time.Nanosecond)
+	return s.PeriodStart.UTC().Format(layout) + " to " + last.Format(layout)
+}
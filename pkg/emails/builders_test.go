@@ -3,6 +3,7 @@ package emails_test
 import (
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/trisacrypto/envoy/pkg/emails"
@@ -33,3 +34,38 @@ func TestVerifyResetPasswordURL(t *testing.T) {
 
 	require.Equal(t, "https://resetpassword.example.com/reset-password?token=YWJjMTIz", invite.VerifyURL())
 }
+
+func TestVerifyUserInviteURL(t *testing.T) {
+	invite := emails.UserInviteEmailData{
+		BaseURL: &url.URL{
+			Scheme: "https",
+			Host:   "envoy.example.com",
+			Path:   "/invite",
+		},
+		Token:      vero.VerificationToken("abc123"),
+		Expiration: time.Date(2024, 9, 2, 17, 53, 58, 0, time.UTC),
+	}
+
+	require.Equal(t, "https://envoy.example.com/invite?token=YWJjMTIz", invite.VerifyURL())
+	require.Equal(t, "September 2, 2024 at 17:53 UTC", invite.ExpiresOn())
+}
+
+func TestReportEmailData(t *testing.T) {
+	data := emails.ReportEmailData{
+		ReportName:  "Monthly Travel Rule Report",
+		PeriodStart: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		ReportURL: &url.URL{
+			Scheme: "https",
+			Host:   "envoy.example.com",
+			Path:   "/reports/01HZ3K8Q3ZJ1F0J9W3V1N9P2D4",
+		},
+	}
+
+	require.Equal(t, "May 1, 2024 to May 31, 2024", data.Period())
+	require.Equal(t, "https://envoy.example.com/reports/01HZ3K8Q3ZJ1F0J9W3V1N9P2D4", data.DownloadURL())
+
+	email, err := emails.NewReportEmail("compliance@example.com", data)
+	require.NoError(t, err, "could not create report email")
+	require.Equal(t, "TRISA Envoy travel rule report: Monthly Travel Rule Report", email.Subject)
+}
@@ -1,6 +1,8 @@
 package emails
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
 	"net/mail"
 
@@ -10,11 +12,19 @@ import (
 )
 
 type Email struct {
-	Sender   string
-	To       []string
-	Subject  string
-	Template string
-	Data     interface{}
+	Sender      string
+	To          []string
+	Subject     string
+	Template    string
+	Data        interface{}
+	Attachments []*Attachment
+}
+
+// Attachment is a file that is attached to the email, e.g. a generated report.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
 }
 
 // New creates a new email template with the currently configured sender attached. If
@@ -34,6 +44,11 @@ func New(recipient, subject, template string, data interface{}) (*Email, error)
 	return msg, nil
 }
 
+// Attach a file to the email.
+func (e *Email) Attach(filename, contentType string, data []byte) {
+	e.Attachments = append(e.Attachments, &Attachment{Filename: filename, ContentType: contentType, Data: data})
+}
+
 // Validate that all required data is present to assemble a sendable email.
 func (e *Email) Validate() error {
 	switch {
@@ -57,6 +72,12 @@ func (e *Email) Validate() error {
 		}
 	}
 
+	for _, attachment := range e.Attachments {
+		if attachment.Filename == "" {
+			return ErrMissingFilename
+		}
+	}
+
 	return nil
 }
 
@@ -80,6 +101,12 @@ func (e *Email) ToSMTP() (msg *email.Email, err error) {
 		return nil, err
 	}
 
+	for _, attachment := range e.Attachments {
+		if _, err = msg.Attach(bytes.NewReader(attachment.Data), attachment.Filename, attachment.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
 	return msg, nil
 }
 
@@ -112,5 +139,14 @@ func (e *Email) ToSendGrid() (msg *sgmail.SGMailV3, err error) {
 		sgmail.NewContent("text/html", html),
 	)
 
+	for _, attachment := range e.Attachments {
+		a := sgmail.NewAttachment()
+		a.SetContent(base64.StdEncoding.EncodeToString(attachment.Data))
+		a.SetType(attachment.ContentType)
+		a.SetFilename(attachment.Filename)
+		a.SetDisposition("attachment")
+		msg.AddAttachment(a)
+	}
+
 	return msg, nil
 }
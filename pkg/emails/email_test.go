@@ -1,6 +1,7 @@
 package emails_test
 
 import (
+	"encoding/base64"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -16,6 +17,7 @@ func TestEmailValidate(t *testing.T) {
 				"This is a test email",
 				"test",
 				nil,
+				nil,
 			},
 			{
 				"admin@server.com",
@@ -23,6 +25,7 @@ func TestEmailValidate(t *testing.T) {
 				"This is a test email",
 				"test",
 				map[string]interface{}{"count": 4},
+				[]*emails.Attachment{{Filename: "report.csv", ContentType: "text/csv", Data: []byte("a,b")}},
 			},
 		}
 
@@ -92,6 +95,16 @@ func TestEmailValidate(t *testing.T) {
 				},
 				emails.ErrIncorrectEmail,
 			},
+			{
+				&emails.Email{
+					Sender:      "admin@server.com",
+					To:          []string{"test@example.com"},
+					Subject:     "This is a test email",
+					Template:    "test",
+					Attachments: []*emails.Attachment{{ContentType: "text/csv", Data: []byte("a,b")}},
+				},
+				emails.ErrMissingFilename,
+			},
 		}
 
 		for i, tc := range testCases {
@@ -100,3 +113,27 @@ func TestEmailValidate(t *testing.T) {
 	})
 
 }
+
+func TestEmailAttachments(t *testing.T) {
+	email := &emails.Email{
+		Sender:   "admin@server.com",
+		To:       []string{"test@example.com"},
+		Subject:  "This is a test email",
+		Template: emails.ReportTemplate,
+		Data:     emails.ReportEmailData{ReportName: "Monthly"},
+	}
+	email.Attach("report.csv", "text/csv", []byte("section,key\n"))
+
+	msg, err := email.ToSMTP()
+	require.NoError(t, err, "could not create smtp message")
+	require.Len(t, msg.Attachments, 1)
+	require.Equal(t, "report.csv", msg.Attachments[0].Filename)
+	require.Equal(t, []byte("section,key\n"), msg.Attachments[0].Content)
+
+	sg, err := email.ToSendGrid()
+	require.NoError(t, err, "could not create sendgrid message")
+	require.Len(t, sg.Attachments, 1)
+	require.Equal(t, "report.csv", sg.Attachments[0].Filename)
+	require.Equal(t, "text/csv", sg.Attachments[0].Type)
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte("section,key\n")), sg.Attachments[0].Content)
+}
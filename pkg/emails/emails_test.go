@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/rotationalio/confire"
@@ -78,6 +79,82 @@ func TestLiveEmails(t *testing.T) {
 		err = email.Send()
 		require.NoError(t, err, "could not send reset password email")
 	})
+
+	t.Run("UserInvite", func(t *testing.T) {
+		data := UserInviteEmailData{
+			ContactName:    "Invited User",
+			ComplianceName: "Testing Compliance",
+			Role:           "Compliance",
+			Expiration:     time.Now().Add(72 * time.Hour),
+			BaseURL:        &url.URL{Scheme: "http", Host: "envoy.local:8000", Path: "/invite"},
+			Token:          vero.VerificationToken("abc123"),
+			SupportEmail:   "support@example.com",
+		}
+
+		email, err := NewUserInviteEmail(recipient, data)
+		require.NoError(t, err, "could not create user invite email")
+
+		err = email.Send()
+		require.NoError(t, err, "could not send user invite email")
+	})
+
+	t.Run("ApprovalRequest", func(t *testing.T) {
+		data := ApprovalRequestEmailData{
+			ContactName:  "Reviewing User",
+			RequestedBy:  "Requesting User",
+			Action:       "send",
+			Counterparty: "AliceVASP",
+			VirtualAsset: "BTC",
+			Amount:       "1.5",
+			Rule:         "Large bitcoin transfers",
+			ApprovalsURL: &url.URL{Scheme: "http", Host: "envoy.local:8000", Path: "/approvals"},
+			SupportEmail: "support@example.com",
+		}
+
+		email, err := NewApprovalRequestEmail(recipient, data)
+		require.NoError(t, err, "could not create approval request email")
+
+		err = email.Send()
+		require.NoError(t, err, "could not send approval request email")
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		data := ReportEmailData{
+			ReportName:   "Monthly Travel Rule Report",
+			PeriodStart:  time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			PeriodEnd:    time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			Transfers:    42,
+			Attachment:   "report-2024-05-01.csv",
+			ReportURL:    &url.URL{Scheme: "http", Host: "envoy.local:8000", Path: "/reports"},
+			SupportEmail: "support@example.com",
+		}
+
+		email, err := NewReportEmail(recipient, data)
+		require.NoError(t, err, "could not create report email")
+		email.Attach(data.Attachment, "text/csv", []byte("section,key,virtual_asset,count,volume\n"))
+
+		err = email.Send()
+		require.NoError(t, err, "could not send report email")
+	})
+
+	t.Run("ReplyDeadline", func(t *testing.T) {
+		data := ReplyDeadlineEmailData{
+			ContactName:    "Reviewing User",
+			Counterparty:   "AliceVASP",
+			VirtualAsset:   "BTC",
+			Amount:         "1.5",
+			AwaitingLocal:  true,
+			ReplyNotAfter:  time.Now().Add(4 * time.Hour),
+			TransactionURL: &url.URL{Scheme: "http", Host: "envoy.local:8000", Path: "/transactions/2b2ab1d9-4f4c-4b0f-8a3d-2f5d7b3a4c5e"},
+			SupportEmail:   "support@example.com",
+		}
+
+		email, err := NewReplyDeadlineEmail(recipient, data)
+		require.NoError(t, err, "could not create reply deadline email")
+
+		err = email.Send()
+		require.NoError(t, err, "could not send reply deadline email")
+	})
 }
 
 func CheckEnvVars(t *testing.T, envs ...string) {
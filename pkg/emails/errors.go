@@ -8,6 +8,7 @@ var (
 	ErrMissingRecipient = errors.New("missing email recipient(s)")
 	ErrMissingTemplate  = errors.New("missing email template name")
 	ErrIncorrectEmail   = errors.New("could not parse email address")
+	ErrMissingFilename  = errors.New("missing email attachment filename")
 	ErrNotInitialized   = errors.New("email sending method has not been configured")
 )
 
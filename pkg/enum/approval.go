@@ -0,0 +1,210 @@
+package enum
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ApprovalAction is the transfer workflow action that is held for four-eyes approval.
+type ApprovalAction uint8
+
+const (
+	ApprovalActionUnknown  ApprovalAction = iota
+	ApprovalActionSend                    // send a new outgoing transfer
+	ApprovalActionAccept                  // accept an incoming transfer under review
+	ApprovalActionReject                  // reject an incoming transfer under review
+	ApprovalActionComplete                // complete a transfer with the on-chain transaction
+
+	// The terminator is used to determine the last value of the enum. It should be
+	// the last value in the list and is automatically incremented when enums are
+	// added above it.
+	// NOTE: you should not reorder the enums, just append them to the list above
+	// to add new values.
+	approvalActionTerminator
+)
+
+var approvalActionNames = [5]string{
+	"unknown",
+	"send",
+	"accept",
+	"reject",
+	"complete",
+}
+
+// Returns true if the provided approval action is valid (e.g. parseable), false otherwise.
+func ValidApprovalAction(t interface{}) bool {
+	if r, err := ParseApprovalAction(t); err != nil || r >= approvalActionTerminator {
+		return false
+	}
+	return true
+}
+
+// Parse the approval action from the provided value.
+func ParseApprovalAction(t interface{}) (ApprovalAction, error) {
+	switch t := t.(type) {
+	case string:
+		t = strings.ToLower(t)
+		if t == "" {
+			return ApprovalActionUnknown, nil
+		}
+
+		for i, name := range approvalActionNames {
+			if name == t {
+				return ApprovalAction(i), nil
+			}
+		}
+		return ApprovalActionUnknown, fmt.Errorf("invalid approval action: %q", t)
+	case uint8:
+		return ApprovalAction(t), nil
+	case ApprovalAction:
+		return t, nil
+	default:
+		return ApprovalActionUnknown, fmt.Errorf("cannot parse %T into an approval action", t)
+	}
+}
+
+// Return a string representation of the approval action.
+func (a ApprovalAction) String() string {
+	if a >= approvalActionTerminator {
+		return approvalActionNames[0]
+	}
+	return approvalActionNames[a]
+}
+
+func (a ApprovalAction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+func (a *ApprovalAction) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if *a, err = ParseApprovalAction(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *ApprovalAction) Scan(src interface{}) (err error) {
+	switch x := src.(type) {
+	case nil:
+		return nil
+	case string:
+		*a, err = ParseApprovalAction(x)
+		return err
+	case []byte:
+		*a, err = ParseApprovalAction(string(x))
+		return err
+	}
+
+	return fmt.Errorf("cannot scan %T into an approval action", src)
+}
+
+func (a ApprovalAction) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// ApprovalStatus describes the state of a four-eyes approval request.
+type ApprovalStatus uint8
+
+const (
+	ApprovalStatusUnknown ApprovalStatus = iota
+	ApprovalPending                      // waiting for review by a different user
+	ApprovalApproved                     // approved and executed
+	ApprovalDenied                       // denied by the reviewer; the action is not executed
+	ApprovalCanceled                     // withdrawn by the requester before review
+	ApprovalExecuting                    // approved by the reviewer and the action is being executed
+
+	// The terminator is used to determine the last value of the enum. It should be
+	// the last value in the list and is automatically incremented when enums are
+	// added above it.
+	// NOTE: you should not reorder the enums, just append them to the list above
+	// to add new values.
+	approvalStatusTerminator
+)
+
+var approvalStatusNames = [6]string{
+	"unknown",
+	"pending",
+	"approved",
+	"denied",
+	"canceled",
+	"executing",
+}
+
+// Returns true if the provided approval status is valid (e.g. parseable), false otherwise.
+func ValidApprovalStatus(t interface{}) bool {
+	if r, err := ParseApprovalStatus(t); err != nil || r >= approvalStatusTerminator {
+		return false
+	}
+	return true
+}
+
+// Parse the approval status from the provided value.
+func ParseApprovalStatus(t interface{}) (ApprovalStatus, error) {
+	switch t := t.(type) {
+	case string:
+		t = strings.ToLower(t)
+		if t == "" {
+			return ApprovalStatusUnknown, nil
+		}
+
+		for i, name := range approvalStatusNames {
+			if name == t {
+				return ApprovalStatus(i), nil
+			}
+		}
+		return ApprovalStatusUnknown, fmt.Errorf("invalid approval status: %q", t)
+	case uint8:
+		return ApprovalStatus(t), nil
+	case ApprovalStatus:
+		return t, nil
+	default:
+		return ApprovalStatusUnknown, fmt.Errorf("cannot parse %T into an approval status", t)
+	}
+}
+
+// Return a string representation of the approval status.
+func (a ApprovalStatus) String() string {
+	if a >= approvalStatusTerminator {
+		return approvalStatusNames[0]
+	}
+	return approvalStatusNames[a]
+}
+
+func (a ApprovalStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+func (a *ApprovalStatus) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if *a, err = ParseApprovalStatus(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *ApprovalStatus) Scan(src interface{}) (err error) {
+	switch x := src.(type) {
+	case nil:
+		return nil
+	case string:
+		*a, err = ParseApprovalStatus(x)
+		return err
+	case []byte:
+		*a, err = ParseApprovalStatus(string(x))
+		return err
+	}
+
+	return fmt.Errorf("cannot scan %T into an approval status", src)
+}
+
+func (a ApprovalStatus) Value() (driver.Value, error) {
+	return a.String(), nil
+}
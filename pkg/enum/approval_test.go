@@ -0,0 +1,275 @@
+package enum_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/enum"
+)
+
+func TestParseApprovalAction(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := []struct {
+			input    interface{}
+			expected enum.ApprovalAction
+		}{
+			{"unknown", enum.ApprovalActionUnknown},
+			{"UNKNOWN", enum.ApprovalActionUnknown},
+			{"send", enum.ApprovalActionSend},
+			{"SEND", enum.ApprovalActionSend},
+			{"accept", enum.ApprovalActionAccept},
+			{"ACCEPT", enum.ApprovalActionAccept},
+			{"reject", enum.ApprovalActionReject},
+			{"REJECT", enum.ApprovalActionReject},
+			{"complete", enum.ApprovalActionComplete},
+			{"COMPLETE", enum.ApprovalActionComplete},
+			{"", enum.ApprovalActionUnknown},
+			{uint8(0), enum.ApprovalActionUnknown},
+			{uint8(1), enum.ApprovalActionSend},
+			{uint8(2), enum.ApprovalActionAccept},
+			{uint8(3), enum.ApprovalActionReject},
+			{uint8(4), enum.ApprovalActionComplete},
+			{enum.ApprovalActionUnknown, enum.ApprovalActionUnknown},
+			{enum.ApprovalActionSend, enum.ApprovalActionSend},
+			{enum.ApprovalActionAccept, enum.ApprovalActionAccept},
+			{enum.ApprovalActionReject, enum.ApprovalActionReject},
+			{enum.ApprovalActionComplete, enum.ApprovalActionComplete},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseApprovalAction(test.input)
+			require.NoError(t, err, "test case %d failed", i)
+			require.Equal(t, test.expected, result, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			input interface{}
+			errs  string
+		}{
+			{"aloha", "invalid approval action: \"aloha\""},
+			{true, "cannot parse bool into an approval action"},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseApprovalAction(test.input)
+			require.Equal(t, enum.ApprovalActionUnknown, result, "test case %d failed", i)
+			require.EqualError(t, err, test.errs, "test case %d failed", i)
+		}
+	})
+}
+
+func TestApprovalActionString(t *testing.T) {
+	tests := []struct {
+		value    enum.ApprovalAction
+		expected string
+	}{
+		{enum.ApprovalActionUnknown, "unknown"},
+		{enum.ApprovalActionSend, "send"},
+		{enum.ApprovalActionAccept, "accept"},
+		{enum.ApprovalActionReject, "reject"},
+		{enum.ApprovalActionComplete, "complete"},
+		{enum.ApprovalAction(5), "unknown"},
+		{enum.ApprovalAction(99), "unknown"},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.expected, test.value.String(), "test case %d failed", i)
+	}
+}
+
+func TestApprovalActionJSON(t *testing.T) {
+	tests := []enum.ApprovalAction{
+		enum.ApprovalActionUnknown,
+		enum.ApprovalActionSend,
+		enum.ApprovalActionAccept,
+		enum.ApprovalActionReject,
+		enum.ApprovalActionComplete,
+	}
+
+	for _, value := range tests {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		var result enum.ApprovalAction
+		err = json.Unmarshal(data, &result)
+		require.NoError(t, err)
+		require.Equal(t, value, result)
+	}
+}
+
+func TestApprovalActionScan(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected enum.ApprovalAction
+	}{
+		{nil, enum.ApprovalActionUnknown},
+		{"unknown", enum.ApprovalActionUnknown},
+		{[]byte("unknown"), enum.ApprovalActionUnknown},
+		{"send", enum.ApprovalActionSend},
+		{[]byte("send"), enum.ApprovalActionSend},
+		{"accept", enum.ApprovalActionAccept},
+		{[]byte("accept"), enum.ApprovalActionAccept},
+		{"reject", enum.ApprovalActionReject},
+		{[]byte("reject"), enum.ApprovalActionReject},
+		{"complete", enum.ApprovalActionComplete},
+		{[]byte("complete"), enum.ApprovalActionComplete},
+	}
+
+	for i, test := range tests {
+		var value enum.ApprovalAction
+		err := value.Scan(test.input)
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, test.expected, value, "test case %d failed", i)
+	}
+
+	var value enum.ApprovalAction
+	require.EqualError(t, value.Scan(42), "cannot scan int into an approval action")
+}
+
+func TestApprovalActionValue(t *testing.T) {
+	for i, name := range []string{"unknown", "send", "accept", "reject", "complete"} {
+		value, err := enum.ApprovalAction(i).Value()
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, name, value, "test case %d failed", i)
+	}
+}
+
+func TestParseApprovalStatus(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := []struct {
+			input    interface{}
+			expected enum.ApprovalStatus
+		}{
+			{"unknown", enum.ApprovalStatusUnknown},
+			{"UNKNOWN", enum.ApprovalStatusUnknown},
+			{"pending", enum.ApprovalPending},
+			{"PENDING", enum.ApprovalPending},
+			{"approved", enum.ApprovalApproved},
+			{"APPROVED", enum.ApprovalApproved},
+			{"denied", enum.ApprovalDenied},
+			{"DENIED", enum.ApprovalDenied},
+			{"canceled", enum.ApprovalCanceled},
+			{"CANCELED", enum.ApprovalCanceled},
+			{"executing", enum.ApprovalExecuting},
+			{"EXECUTING", enum.ApprovalExecuting},
+			{"", enum.ApprovalStatusUnknown},
+			{uint8(0), enum.ApprovalStatusUnknown},
+			{uint8(1), enum.ApprovalPending},
+			{uint8(2), enum.ApprovalApproved},
+			{uint8(3), enum.ApprovalDenied},
+			{uint8(4), enum.ApprovalCanceled},
+			{uint8(5), enum.ApprovalExecuting},
+			{enum.ApprovalStatusUnknown, enum.ApprovalStatusUnknown},
+			{enum.ApprovalPending, enum.ApprovalPending},
+			{enum.ApprovalApproved, enum.ApprovalApproved},
+			{enum.ApprovalDenied, enum.ApprovalDenied},
+			{enum.ApprovalCanceled, enum.ApprovalCanceled},
+			{enum.ApprovalExecuting, enum.ApprovalExecuting},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseApprovalStatus(test.input)
+			require.NoError(t, err, "test case %d failed", i)
+			require.Equal(t, test.expected, result, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			input interface{}
+			errs  string
+		}{
+			{"aloha", "invalid approval status: \"aloha\""},
+			{true, "cannot parse bool into an approval status"},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseApprovalStatus(test.input)
+			require.Equal(t, enum.ApprovalStatusUnknown, result, "test case %d failed", i)
+			require.EqualError(t, err, test.errs, "test case %d failed", i)
+		}
+	})
+}
+
+func TestApprovalStatusString(t *testing.T) {
+	tests := []struct {
+		value    enum.ApprovalStatus
+		expected string
+	}{
+		{enum.ApprovalStatusUnknown, "unknown"},
+		{enum.ApprovalPending, "pending"},
+		{enum.ApprovalApproved, "approved"},
+		{enum.ApprovalDenied, "denied"},
+		{enum.ApprovalCanceled, "canceled"},
+		{enum.ApprovalExecuting, "executing"},
+		{enum.ApprovalStatus(6), "unknown"},
+		{enum.ApprovalStatus(99), "unknown"},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.expected, test.value.String(), "test case %d failed", i)
+	}
+}
+
+func TestApprovalStatusJSON(t *testing.T) {
+	tests := []enum.ApprovalStatus{
+		enum.ApprovalStatusUnknown,
+		enum.ApprovalPending,
+		enum.ApprovalApproved,
+		enum.ApprovalDenied,
+		enum.ApprovalCanceled,
+		enum.ApprovalExecuting,
+	}
+
+	for _, value := range tests {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		var result enum.ApprovalStatus
+		err = json.Unmarshal(data, &result)
+		require.NoError(t, err)
+		require.Equal(t, value, result)
+	}
+}
+
+func TestApprovalStatusScan(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected enum.ApprovalStatus
+	}{
+		{nil, enum.ApprovalStatusUnknown},
+		{"unknown", enum.ApprovalStatusUnknown},
+		{[]byte("unknown"), enum.ApprovalStatusUnknown},
+		{"pending", enum.ApprovalPending},
+		{[]byte("pending"), enum.ApprovalPending},
+		{"approved", enum.ApprovalApproved},
+		{[]byte("approved"), enum.ApprovalApproved},
+		{"denied", enum.ApprovalDenied},
+		{[]byte("denied"), enum.ApprovalDenied},
+		{"canceled", enum.ApprovalCanceled},
+		{[]byte("canceled"), enum.ApprovalCanceled},
+		{"executing", enum.ApprovalExecuting},
+		{[]byte("executing"), enum.ApprovalExecuting},
+	}
+
+	for i, test := range tests {
+		var value enum.ApprovalStatus
+		err := value.Scan(test.input)
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, test.expected, value, "test case %d failed", i)
+	}
+
+	var value enum.ApprovalStatus
+	require.EqualError(t, value.Scan(42), "cannot scan int into an approval status")
+}
+
+func TestApprovalStatusValue(t *testing.T) {
+	for i, name := range []string{"unknown", "pending", "approved", "denied", "canceled", "executing"} {
+		value, err := enum.ApprovalStatus(i).Value()
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, name, value, "test case %d failed", i)
+	}
+}
@@ -0,0 +1,214 @@
+package enum
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BatchStatus describes the progress of a batch of transfers that was imported to be
+// sent together.
+type BatchStatus uint8
+
+const (
+	BatchStatusUnknown BatchStatus = iota
+	BatchValidated                 // imported and validated, waiting to be sent by a user
+	BatchSending                   // the valid transfers in the batch are being sent
+	BatchCompleted                 // every valid transfer in the batch has been processed
+	BatchCanceled                  // canceled by a user before every transfer was sent
+
+	// The terminator is used to determine the last value of the enum. It should be
+	// the last value in the list and is automatically incremented when enums are
+	// added above it.
+	// NOTE: you should not reorder the enums, just append them to the list above
+	// to add new values.
+	batchStatusTerminator
+)
+
+var batchStatusNames = [5]string{
+	"unknown",
+	"validated",
+	"sending",
+	"completed",
+	"canceled",
+}
+
+// Returns true if the provided batch status is valid (e.g. parseable), false otherwise.
+func ValidBatchStatus(t interface{}) bool {
+	if r, err := ParseBatchStatus(t); err != nil || r >= batchStatusTerminator {
+		return false
+	}
+	return true
+}
+
+// Parse the batch status from the provided value.
+func ParseBatchStatus(t interface{}) (BatchStatus, error) {
+	switch t := t.(type) {
+	case string:
+		t = strings.ToLower(t)
+		if t == "" {
+			return BatchStatusUnknown, nil
+		}
+
+		for i, name := range batchStatusNames {
+			if name == t {
+				return BatchStatus(i), nil
+			}
+		}
+		return BatchStatusUnknown, fmt.Errorf("invalid batch status: %q", t)
+	case uint8:
+		return BatchStatus(t), nil
+	case BatchStatus:
+		return t, nil
+	default:
+		return BatchStatusUnknown, fmt.Errorf("cannot parse %T into a batch status", t)
+	}
+}
+
+// Return a string representation of the batch status.
+func (b BatchStatus) String() string {
+	if b >= batchStatusTerminator {
+		return batchStatusNames[0]
+	}
+	return batchStatusNames[b]
+}
+
+func (b BatchStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.String())
+}
+
+func (b *BatchStatus) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if *b, err = ParseBatchStatus(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (b *BatchStatus) Scan(src interface{}) (err error) {
+	switch x := src.(type) {
+	case nil:
+		return nil
+	case string:
+		*b, err = ParseBatchStatus(x)
+		return err
+	case []byte:
+		*b, err = ParseBatchStatus(string(x))
+		return err
+	}
+
+	return fmt.Errorf("cannot scan %T into a batch status", src)
+}
+
+func (b BatchStatus) Value() (driver.Value, error) {
+	return b.String(), nil
+}
+
+// BatchItemStatus describes the result of a single transfer (row) in an imported batch.
+type BatchItemStatus uint8
+
+const (
+	BatchItemStatusUnknown BatchItemStatus = iota
+	BatchItemInvalid                       // the row could not be parsed or validated and is not sent
+	BatchItemPending                       // the row is valid and waiting to be sent
+	BatchItemSent                          // the transfer was sent to the counterparty
+	BatchItemQueued                        // the transfer was added to the outbound queue
+	BatchItemHeld                          // the transfer is held for four-eyes approval
+	BatchItemFailed                        // the transfer could not be sent
+
+	// The terminator is used to determine the last value of the enum. It should be
+	// the last value in the list and is automatically incremented when enums are
+	// added above it.
+	// NOTE: you should not reorder the enums, just append them to the list above
+	// to add new values.
+	batchItemStatusTerminator
+)
+
+var batchItemStatusNames = [7]string{
+	"unknown",
+	"invalid",
+	"pending",
+	"sent",
+	"queued",
+	"held",
+	"failed",
+}
+
+// Returns true if the provided batch item status is valid (e.g. parseable), false
+// otherwise.
+func ValidBatchItemStatus(t interface{}) bool {
+	if r, err := ParseBatchItemStatus(t); err != nil || r >= batchItemStatusTerminator {
+		return false
+	}
+	return true
+}
+
+// Parse the batch item status from the provided value.
+func ParseBatchItemStatus(t interface{}) (BatchItemStatus, error) {
+	switch t := t.(type) {
+	case string:
+		t = strings.ToLower(t)
+		if t == "" {
+			return BatchItemStatusUnknown, nil
+		}
+
+		for i, name := range batchItemStatusNames {
+			if name == t {
+				return BatchItemStatus(i), nil
+			}
+		}
+		return BatchItemStatusUnknown, fmt.Errorf("invalid batch item status: %q", t)
+	case uint8:
+		return BatchItemStatus(t), nil
+	case BatchItemStatus:
+		return t, nil
+	default:
+		return BatchItemStatusUnknown, fmt.Errorf("cannot parse %T into a batch item status", t)
+	}
+}
+
+// Return a string representation of the batch item status.
+func (i BatchItemStatus) String() string {
+	if i >= batchItemStatusTerminator {
+		return batchItemStatusNames[0]
+	}
+	return batchItemStatusNames[i]
+}
+
+func (i BatchItemStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+func (i *BatchItemStatus) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if *i, err = ParseBatchItemStatus(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (i *BatchItemStatus) Scan(src interface{}) (err error) {
+	switch x := src.(type) {
+	case nil:
+		return nil
+	case string:
+		*i, err = ParseBatchItemStatus(x)
+		return err
+	case []byte:
+		*i, err = ParseBatchItemStatus(string(x))
+		return err
+	}
+
+	return fmt.Errorf("cannot scan %T into a batch item status", src)
+}
+
+func (i BatchItemStatus) Value() (driver.Value, error) {
+	return i.String(), nil
+}
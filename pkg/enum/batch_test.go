@@ -0,0 +1,283 @@
+package enum_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/enum"
+)
+
+func TestParseBatchStatus(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := []struct {
+			input    interface{}
+			expected enum.BatchStatus
+		}{
+			{"unknown", enum.BatchStatusUnknown},
+			{"UNKNOWN", enum.BatchStatusUnknown},
+			{"validated", enum.BatchValidated},
+			{"VALIDATED", enum.BatchValidated},
+			{"sending", enum.BatchSending},
+			{"SENDING", enum.BatchSending},
+			{"completed", enum.BatchCompleted},
+			{"COMPLETED", enum.BatchCompleted},
+			{"canceled", enum.BatchCanceled},
+			{"CANCELED", enum.BatchCanceled},
+			{"", enum.BatchStatusUnknown},
+			{uint8(0), enum.BatchStatusUnknown},
+			{uint8(1), enum.BatchValidated},
+			{uint8(2), enum.BatchSending},
+			{uint8(3), enum.BatchCompleted},
+			{uint8(4), enum.BatchCanceled},
+			{enum.BatchStatusUnknown, enum.BatchStatusUnknown},
+			{enum.BatchValidated, enum.BatchValidated},
+			{enum.BatchSending, enum.BatchSending},
+			{enum.BatchCompleted, enum.BatchCompleted},
+			{enum.BatchCanceled, enum.BatchCanceled},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseBatchStatus(test.input)
+			require.NoError(t, err, "test case %d failed", i)
+			require.Equal(t, test.expected, result, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			input interface{}
+			errs  string
+		}{
+			{"aloha", "invalid batch status: \"aloha\""},
+			{true, "cannot parse bool into a batch status"},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseBatchStatus(test.input)
+			require.Equal(t, enum.BatchStatusUnknown, result, "test case %d failed", i)
+			require.EqualError(t, err, test.errs, "test case %d failed", i)
+		}
+	})
+}
+
+func TestBatchStatusString(t *testing.T) {
+	tests := []struct {
+		value    enum.BatchStatus
+		expected string
+	}{
+		{enum.BatchStatusUnknown, "unknown"},
+		{enum.BatchValidated, "validated"},
+		{enum.BatchSending, "sending"},
+		{enum.BatchCompleted, "completed"},
+		{enum.BatchCanceled, "canceled"},
+		{enum.BatchStatus(5), "unknown"},
+		{enum.BatchStatus(99), "unknown"},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.expected, test.value.String(), "test case %d failed", i)
+	}
+}
+
+func TestBatchStatusJSON(t *testing.T) {
+	tests := []enum.BatchStatus{
+		enum.BatchStatusUnknown,
+		enum.BatchValidated,
+		enum.BatchSending,
+		enum.BatchCompleted,
+		enum.BatchCanceled,
+	}
+
+	for _, value := range tests {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		var result enum.BatchStatus
+		err = json.Unmarshal(data, &result)
+		require.NoError(t, err)
+		require.Equal(t, value, result)
+	}
+}
+
+func TestBatchStatusScan(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected enum.BatchStatus
+	}{
+		{nil, enum.BatchStatusUnknown},
+		{"unknown", enum.BatchStatusUnknown},
+		{[]byte("unknown"), enum.BatchStatusUnknown},
+		{"validated", enum.BatchValidated},
+		{[]byte("validated"), enum.BatchValidated},
+		{"sending", enum.BatchSending},
+		{[]byte("sending"), enum.BatchSending},
+		{"completed", enum.BatchCompleted},
+		{[]byte("completed"), enum.BatchCompleted},
+		{"canceled", enum.BatchCanceled},
+		{[]byte("canceled"), enum.BatchCanceled},
+	}
+
+	for i, test := range tests {
+		var value enum.BatchStatus
+		err := value.Scan(test.input)
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, test.expected, value, "test case %d failed", i)
+	}
+
+	var value enum.BatchStatus
+	require.EqualError(t, value.Scan(42), "cannot scan int into a batch status")
+}
+
+func TestBatchStatusValue(t *testing.T) {
+	for i, name := range []string{"unknown", "validated", "sending", "completed", "canceled"} {
+		value, err := enum.BatchStatus(i).Value()
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, name, value, "test case %d failed", i)
+	}
+}
+
+func TestParseBatchItemStatus(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := []struct {
+			input    interface{}
+			expected enum.BatchItemStatus
+		}{
+			{"unknown", enum.BatchItemStatusUnknown},
+			{"UNKNOWN", enum.BatchItemStatusUnknown},
+			{"invalid", enum.BatchItemInvalid},
+			{"INVALID", enum.BatchItemInvalid},
+			{"pending", enum.BatchItemPending},
+			{"PENDING", enum.BatchItemPending},
+			{"sent", enum.BatchItemSent},
+			{"SENT", enum.BatchItemSent},
+			{"queued", enum.BatchItemQueued},
+			{"QUEUED", enum.BatchItemQueued},
+			{"held", enum.BatchItemHeld},
+			{"HELD", enum.BatchItemHeld},
+			{"failed", enum.BatchItemFailed},
+			{"FAILED", enum.BatchItemFailed},
+			{"", enum.BatchItemStatusUnknown},
+			{uint8(0), enum.BatchItemStatusUnknown},
+			{uint8(1), enum.BatchItemInvalid},
+			{uint8(2), enum.BatchItemPending},
+			{uint8(3), enum.BatchItemSent},
+			{uint8(4), enum.BatchItemQueued},
+			{uint8(5), enum.BatchItemHeld},
+			{uint8(6), enum.BatchItemFailed},
+			{enum.BatchItemStatusUnknown, enum.BatchItemStatusUnknown},
+			{enum.BatchItemInvalid, enum.BatchItemInvalid},
+			{enum.BatchItemPending, enum.BatchItemPending},
+			{enum.BatchItemSent, enum.BatchItemSent},
+			{enum.BatchItemQueued, enum.BatchItemQueued},
+			{enum.BatchItemHeld, enum.BatchItemHeld},
+			{enum.BatchItemFailed, enum.BatchItemFailed},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseBatchItemStatus(test.input)
+			require.NoError(t, err, "test case %d failed", i)
+			require.Equal(t, test.expected, result, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			input interface{}
+			errs  string
+		}{
+			{"aloha", "invalid batch item status: \"aloha\""},
+			{true, "cannot parse bool into a batch item status"},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseBatchItemStatus(test.input)
+			require.Equal(t, enum.BatchItemStatusUnknown, result, "test case %d failed", i)
+			require.EqualError(t, err, test.errs, "test case %d failed", i)
+		}
+	})
+}
+
+func TestBatchItemStatusString(t *testing.T) {
+	tests := []struct {
+		value    enum.BatchItemStatus
+		expected string
+	}{
+		{enum.BatchItemStatusUnknown, "unknown"},
+		{enum.BatchItemInvalid, "invalid"},
+		{enum.BatchItemPending, "pending"},
+		{enum.BatchItemSent, "sent"},
+		{enum.BatchItemQueued, "queued"},
+		{enum.BatchItemHeld, "held"},
+		{enum.BatchItemFailed, "failed"},
+		{enum.BatchItemStatus(7), "unknown"},
+		{enum.BatchItemStatus(99), "unknown"},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.expected, test.value.String(), "test case %d failed", i)
+	}
+}
+
+func TestBatchItemStatusJSON(t *testing.T) {
+	tests := []enum.BatchItemStatus{
+		enum.BatchItemStatusUnknown,
+		enum.BatchItemInvalid,
+		enum.BatchItemPending,
+		enum.BatchItemSent,
+		enum.BatchItemQueued,
+		enum.BatchItemHeld,
+		enum.BatchItemFailed,
+	}
+
+	for _, value := range tests {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		var result enum.BatchItemStatus
+		err = json.Unmarshal(data, &result)
+		require.NoError(t, err)
+		require.Equal(t, value, result)
+	}
+}
+
+func TestBatchItemStatusScan(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected enum.BatchItemStatus
+	}{
+		{nil, enum.BatchItemStatusUnknown},
+		{"unknown", enum.BatchItemStatusUnknown},
+		{[]byte("unknown"), enum.BatchItemStatusUnknown},
+		{"invalid", enum.BatchItemInvalid},
+		{[]byte("invalid"), enum.BatchItemInvalid},
+		{"pending", enum.BatchItemPending},
+		{[]byte("pending"), enum.BatchItemPending},
+		{"sent", enum.BatchItemSent},
+		{[]byte("sent"), enum.BatchItemSent},
+		{"queued", enum.BatchItemQueued},
+		{[]byte("queued"), enum.BatchItemQueued},
+		{"held", enum.BatchItemHeld},
+		{[]byte("held"), enum.BatchItemHeld},
+		{"failed", enum.BatchItemFailed},
+		{[]byte("failed"), enum.BatchItemFailed},
+	}
+
+	for i, test := range tests {
+		var value enum.BatchItemStatus
+		err := value.Scan(test.input)
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, test.expected, value, "test case %d failed", i)
+	}
+
+	var value enum.BatchItemStatus
+	require.EqualError(t, value.Scan(42), "cannot scan int into a batch item status")
+}
+
+func TestBatchItemStatusValue(t *testing.T) {
+	for i, name := range []string{"unknown", "invalid", "pending", "sent", "queued", "held", "failed"} {
+		value, err := enum.BatchItemStatus(i).Value()
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, name, value, "test case %d failed", i)
+	}
+}
@@ -0,0 +1,108 @@
+package enum
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// QueueStatus describes the state of a transfer in the outbound queue.
+type QueueStatus uint8
+
+const (
+	QueueStatusUnknown QueueStatus = iota
+	QueueQueued                    // waiting to be sent by the outbound queue worker
+	QueueSent                      // delivered to the counterparty
+	QueueFailed                    // the maximum number of attempts was reached without delivery
+	QueueCanceled                  // removed from the queue by a user before delivery
+
+	// The terminator is used to determine the last value of the enum. It should be
+	// the last value in the list and is automatically incremented when enums are
+	// added above it.
+	// NOTE: you should not reorder the enums, just append them to the list above
+	// to add new values.
+	queueStatusTerminator
+)
+
+var queueStatusNames = [5]string{
+	"unknown",
+	"queued",
+	"sent",
+	"failed",
+	"canceled",
+}
+
+// Returns true if the provided queue status is valid (e.g. parseable), false otherwise.
+func ValidQueueStatus(t interface{}) bool {
+	if r, err := ParseQueueStatus(t); err != nil || r >= queueStatusTerminator {
+		return false
+	}
+	return true
+}
+
+// Parse the queue status from the provided value.
+func ParseQueueStatus(t interface{}) (QueueStatus, error) {
+	switch t := t.(type) {
+	case string:
+		t = strings.ToLower(t)
+		if t == "" {
+			return QueueStatusUnknown, nil
+		}
+
+		for i, name := range queueStatusNames {
+			if name == t {
+				return QueueStatus(i), nil
+			}
+		}
+		return QueueStatusUnknown, fmt.Errorf("invalid queue status: %q", t)
+	case uint8:
+		return QueueStatus(t), nil
+	case QueueStatus:
+		return t, nil
+	default:
+		return QueueStatusUnknown, fmt.Errorf("cannot parse %T into a queue status", t)
+	}
+}
+
+// Return a string representation of the queue status.
+func (q QueueStatus) String() string {
+	if q >= queueStatusTerminator {
+		return queueStatusNames[0]
+	}
+	return queueStatusNames[q]
+}
+
+func (q QueueStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(q.String())
+}
+
+func (q *QueueStatus) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if *q, err = ParseQueueStatus(s); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (q *QueueStatus) Scan(src interface{}) (err error) {
+	switch x := src.(type) {
+	case nil:
+		return nil
+	case string:
+		*q, err = ParseQueueStatus(x)
+		return err
+	case []byte:
+		*q, err = ParseQueueStatus(string(x))
+		return err
+	}
+
+	return fmt.Errorf("cannot scan %T into a queue status", src)
+}
+
+func (q QueueStatus) Value() (driver.Value, error) {
+	return q.String(), nil
+}
@@ -0,0 +1,138 @@
+package enum_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/enum"
+)
+
+func TestParseQueueStatus(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := []struct {
+			input    interface{}
+			expected enum.QueueStatus
+		}{
+			{"unknown", enum.QueueStatusUnknown},
+			{"UNKNOWN", enum.QueueStatusUnknown},
+			{"queued", enum.QueueQueued},
+			{"QUEUED", enum.QueueQueued},
+			{"sent", enum.QueueSent},
+			{"SENT", enum.QueueSent},
+			{"failed", enum.QueueFailed},
+			{"FAILED", enum.QueueFailed},
+			{"canceled", enum.QueueCanceled},
+			{"CANCELED", enum.QueueCanceled},
+			{"", enum.QueueStatusUnknown},
+			{uint8(0), enum.QueueStatusUnknown},
+			{uint8(1), enum.QueueQueued},
+			{uint8(2), enum.QueueSent},
+			{uint8(3), enum.QueueFailed},
+			{uint8(4), enum.QueueCanceled},
+			{enum.QueueStatusUnknown, enum.QueueStatusUnknown},
+			{enum.QueueQueued, enum.QueueQueued},
+			{enum.QueueSent, enum.QueueSent},
+			{enum.QueueFailed, enum.QueueFailed},
+			{enum.QueueCanceled, enum.QueueCanceled},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseQueueStatus(test.input)
+			require.NoError(t, err, "test case %d failed", i)
+			require.Equal(t, test.expected, result, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			input interface{}
+			errs  string
+		}{
+			{"aloha", "invalid queue status: \"aloha\""},
+			{true, "cannot parse bool into a queue status"},
+		}
+
+		for i, test := range tests {
+			result, err := enum.ParseQueueStatus(test.input)
+			require.Equal(t, enum.QueueStatusUnknown, result, "test case %d failed", i)
+			require.EqualError(t, err, test.errs, "test case %d failed", i)
+		}
+	})
+}
+
+func TestQueueStatusString(t *testing.T) {
+	tests := []struct {
+		value    enum.QueueStatus
+		expected string
+	}{
+		{enum.QueueStatusUnknown, "unknown"},
+		{enum.QueueQueued, "queued"},
+		{enum.QueueSent, "sent"},
+		{enum.QueueFailed, "failed"},
+		{enum.QueueCanceled, "canceled"},
+		{enum.QueueStatus(5), "unknown"},
+		{enum.QueueStatus(99), "unknown"},
+	}
+
+	for i, test := range tests {
+		require.Equal(t, test.expected, test.value.String(), "test case %d failed", i)
+	}
+}
+
+func TestQueueStatusJSON(t *testing.T) {
+	tests := []enum.QueueStatus{
+		enum.QueueStatusUnknown,
+		enum.QueueQueued,
+		enum.QueueSent,
+		enum.QueueFailed,
+		enum.QueueCanceled,
+	}
+
+	for _, value := range tests {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+
+		var result enum.QueueStatus
+		err = json.Unmarshal(data, &result)
+		require.NoError(t, err)
+		require.Equal(t, value, result)
+	}
+}
+
+func TestQueueStatusScan(t *testing.T) {
+	tests := []struct {
+		input    interface{}
+		expected enum.QueueStatus
+	}{
+		{nil, enum.QueueStatusUnknown},
+		{"unknown", enum.QueueStatusUnknown},
+		{[]byte("unknown"), enum.QueueStatusUnknown},
+		{"queued", enum.QueueQueued},
+		{[]byte("queued"), enum.QueueQueued},
+		{"sent", enum.QueueSent},
+		{[]byte("sent"), enum.QueueSent},
+		{"failed", enum.QueueFailed},
+		{[]byte("failed"), enum.QueueFailed},
+		{"canceled", enum.QueueCanceled},
+		{[]byte("canceled"), enum.QueueCanceled},
+	}
+
+	for i, test := range tests {
+		var value enum.QueueStatus
+		err := value.Scan(test.input)
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, test.expected, value, "test case %d failed", i)
+	}
+
+	var value enum.QueueStatus
+	require.EqualError(t, value.Scan(42), "cannot scan int into a queue status")
+}
+
+func TestQueueStatusValue(t *testing.T) {
+	for i, name := range []string{"unknown", "queued", "sent", "failed", "canceled"} {
+		value, err := enum.QueueStatus(i).Value()
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, name, value, "test case %d failed", i)
+	}
+}
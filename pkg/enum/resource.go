@@ -21,6 +21,16 @@ const (
 	ResourceSecureEnvelope
 	ResourceCryptoAddress
 	ResourceContact
+	ResourceLegalHold
+	ResourceRole
+	ResourceUserInvite
+	ResourceApproval
+	ResourceApprovalRule
+	ResourceTransactionNote
+	ResourceQueuedTransfer
+	ResourceBatch
+	ResourceReport
+	ResourceThresholdRule
 
 	// The terminator is used to determine the last value of the enum. It should be
 	// the last value in the list and is automatically incremented when enums are
@@ -30,7 +40,7 @@ const (
 	resourceTerminator
 )
 
-var resourceNames = [10]string{
+var resourceNames = [20]string{
 	"unknown",
 	"transaction",
 	"user",
@@ -41,6 +51,16 @@ var resourceNames = [10]string{
 	"secure_envelope",
 	"crypto_address",
 	"contact",
+	"legal_hold",
+	"role",
+	"user_invite",
+	"approval",
+	"approval_rule",
+	"transaction_note",
+	"queued_transfer",
+	"batch",
+	"report",
+	"threshold_rule",
 }
 
 // Returns true if the provided resource is valid (e.g. parseable), false otherwise.
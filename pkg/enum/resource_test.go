@@ -35,6 +35,26 @@ func TestParseResource(t *testing.T) {
 			{"CRYPTO_ADDRESS", enum.ResourceCryptoAddress},
 			{"contact", enum.ResourceContact},
 			{"CONTACT", enum.ResourceContact},
+			{"legal_hold", enum.ResourceLegalHold},
+			{"LEGAL_HOLD", enum.ResourceLegalHold},
+			{"role", enum.ResourceRole},
+			{"ROLE", enum.ResourceRole},
+			{"user_invite", enum.ResourceUserInvite},
+			{"USER_INVITE", enum.ResourceUserInvite},
+			{"approval", enum.ResourceApproval},
+			{"APPROVAL", enum.ResourceApproval},
+			{"approval_rule", enum.ResourceApprovalRule},
+			{"APPROVAL_RULE", enum.ResourceApprovalRule},
+			{"transaction_note", enum.ResourceTransactionNote},
+			{"TRANSACTION_NOTE", enum.ResourceTransactionNote},
+			{"queued_transfer", enum.ResourceQueuedTransfer},
+			{"QUEUED_TRANSFER", enum.ResourceQueuedTransfer},
+			{"batch", enum.ResourceBatch},
+			{"BATCH", enum.ResourceBatch},
+			{"report", enum.ResourceReport},
+			{"REPORT", enum.ResourceReport},
+			{"threshold_rule", enum.ResourceThresholdRule},
+			{"THRESHOLD_RULE", enum.ResourceThresholdRule},
 			{uint8(0), enum.ResourceUnknown},
 			{uint8(1), enum.ResourceTransaction},
 			{uint8(2), enum.ResourceUser},
@@ -45,6 +65,16 @@ func TestParseResource(t *testing.T) {
 			{uint8(7), enum.ResourceSecureEnvelope},
 			{uint8(8), enum.ResourceCryptoAddress},
 			{uint8(9), enum.ResourceContact},
+			{uint8(10), enum.ResourceLegalHold},
+			{uint8(11), enum.ResourceRole},
+			{uint8(12), enum.ResourceUserInvite},
+			{uint8(13), enum.ResourceApproval},
+			{uint8(14), enum.ResourceApprovalRule},
+			{uint8(15), enum.ResourceTransactionNote},
+			{uint8(16), enum.ResourceQueuedTransfer},
+			{uint8(17), enum.ResourceBatch},
+			{uint8(18), enum.ResourceReport},
+			{uint8(19), enum.ResourceThresholdRule},
 			{enum.ResourceUnknown, enum.ResourceUnknown},
 			{enum.ResourceTransaction, enum.ResourceTransaction},
 			{enum.ResourceUser, enum.ResourceUser},
@@ -55,6 +85,16 @@ func TestParseResource(t *testing.T) {
 			{enum.ResourceSecureEnvelope, enum.ResourceSecureEnvelope},
 			{enum.ResourceCryptoAddress, enum.ResourceCryptoAddress},
 			{enum.ResourceContact, enum.ResourceContact},
+			{enum.ResourceLegalHold, enum.ResourceLegalHold},
+			{enum.ResourceRole, enum.ResourceRole},
+			{enum.ResourceUserInvite, enum.ResourceUserInvite},
+			{enum.ResourceApproval, enum.ResourceApproval},
+			{enum.ResourceApprovalRule, enum.ResourceApprovalRule},
+			{enum.ResourceTransactionNote, enum.ResourceTransactionNote},
+			{enum.ResourceQueuedTransfer, enum.ResourceQueuedTransfer},
+			{enum.ResourceBatch, enum.ResourceBatch},
+			{enum.ResourceReport, enum.ResourceReport},
+			{enum.ResourceThresholdRule, enum.ResourceThresholdRule},
 		}
 
 		for i, test := range tests {
@@ -96,7 +136,17 @@ func TestResourceString(t *testing.T) {
 		{enum.ResourceSecureEnvelope, "secure_envelope"},
 		{enum.ResourceCryptoAddress, "crypto_address"},
 		{enum.ResourceContact, "contact"},
-		{enum.Resource(10), "unknown"},
+		{enum.ResourceLegalHold, "legal_hold"},
+		{enum.ResourceRole, "role"},
+		{enum.ResourceUserInvite, "user_invite"},
+		{enum.ResourceApproval, "approval"},
+		{enum.ResourceApprovalRule, "approval_rule"},
+		{enum.ResourceTransactionNote, "transaction_note"},
+		{enum.ResourceQueuedTransfer, "queued_transfer"},
+		{enum.ResourceBatch, "batch"},
+		{enum.ResourceReport, "report"},
+		{enum.ResourceThresholdRule, "threshold_rule"},
+		{enum.Resource(20), "unknown"},
 		{enum.Resource(99), "unknown"},
 	}
 
@@ -118,6 +168,16 @@ func TestResourceJSON(t *testing.T) {
 		enum.ResourceSecureEnvelope,
 		enum.ResourceCryptoAddress,
 		enum.ResourceContact,
+		enum.ResourceLegalHold,
+		enum.ResourceRole,
+		enum.ResourceUserInvite,
+		enum.ResourceApproval,
+		enum.ResourceApprovalRule,
+		enum.ResourceTransactionNote,
+		enum.ResourceQueuedTransfer,
+		enum.ResourceBatch,
+		enum.ResourceReport,
+		enum.ResourceThresholdRule,
 	}
 
 	for _, resource := range tests {
@@ -158,6 +218,12 @@ func TestResourceScan(t *testing.T) {
 		{"CRYPTO_ADDRESS", enum.ResourceCryptoAddress},
 		{"contact", enum.ResourceContact},
 		{"CONTACT", enum.ResourceContact},
+		{"legal_hold", enum.ResourceLegalHold},
+		{"LEGAL_HOLD", enum.ResourceLegalHold},
+		{"role", enum.ResourceRole},
+		{"ROLE", enum.ResourceRole},
+		{"user_invite", enum.ResourceUserInvite},
+		{"USER_INVITE", enum.ResourceUserInvite},
 		{[]byte(""), enum.ResourceUnknown},
 		{[]byte("unknown"), enum.ResourceUnknown},
 		{[]byte("UNKNOWN"), enum.ResourceUnknown},
@@ -179,6 +245,19 @@ func TestResourceScan(t *testing.T) {
 		{[]byte("CRYPTO_ADDRESS"), enum.ResourceCryptoAddress},
 		{[]byte("contact"), enum.ResourceContact},
 		{[]byte("CONTACT"), enum.ResourceContact},
+		{[]byte("legal_hold"), enum.ResourceLegalHold},
+		{[]byte("LEGAL_HOLD"), enum.ResourceLegalHold},
+		{[]byte("role"), enum.ResourceRole},
+		{[]byte("ROLE"), enum.ResourceRole},
+		{[]byte("user_invite"), enum.ResourceUserInvite},
+		{[]byte("USER_INVITE"), enum.ResourceUserInvite},
+		{[]byte("approval"), enum.ResourceApproval},
+		{[]byte("approval_rule"), enum.ResourceApprovalRule},
+		{[]byte("transaction_note"), enum.ResourceTransactionNote},
+		{[]byte("queued_transfer"), enum.ResourceQueuedTransfer},
+		{[]byte("batch"), enum.ResourceBatch},
+		{[]byte("report"), enum.ResourceReport},
+		{[]byte("threshold_rule"), enum.ResourceThresholdRule},
 	}
 
 	for i, test := range tests {
@@ -76,6 +76,44 @@ func (s Status) String() string {
 	return statusNames[s]
 }
 
+//===========================================================================
+// State Machine
+//===========================================================================
+
+// The statuses that a transaction may move to from each status. A transaction may
+// always remain in its current status and a transaction whose status is unknown (e.g.
+// it was created before the status was recorded) may move to any status. Completed
+// and rejected transactions are final and cannot move to any other status.
+var statusTransitions = map[Status][]Status{
+	StatusDraft:     {StatusPending, StatusReview, StatusRepair, StatusAccepted, StatusCompleted, StatusRejected},
+	StatusPending:   {StatusReview, StatusRepair, StatusAccepted, StatusCompleted, StatusRejected},
+	StatusReview:    {StatusPending, StatusRepair, StatusAccepted, StatusCompleted, StatusRejected},
+	StatusRepair:    {StatusPending, StatusReview, StatusAccepted, StatusCompleted, StatusRejected},
+	StatusAccepted:  {StatusCompleted, StatusRejected},
+	StatusCompleted: {},
+	StatusRejected:  {},
+}
+
+// Final returns true if the transaction has been completed or rejected; the status
+// and the transfer details of a final transaction can no longer be changed.
+func (s Status) Final() bool {
+	return s == StatusCompleted || s == StatusRejected
+}
+
+// CanTransition returns true if a transaction may move from this status to the next.
+func (s Status) CanTransition(next Status) bool {
+	if s == next || s == StatusUnspecified {
+		return true
+	}
+
+	for _, target := range statusTransitions[s] {
+		if target == next {
+			return true
+		}
+	}
+	return false
+}
+
 //===========================================================================
 // Serialization and Deserialization
 //===========================================================================
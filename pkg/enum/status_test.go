@@ -220,3 +220,47 @@ func TestStatusValue(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "draft", value)
 }
+
+func TestStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from   enum.Status
+		to     enum.Status
+		assert require.BoolAssertionFunc
+	}{
+		{enum.StatusUnspecified, enum.StatusDraft, require.True},
+		{enum.StatusUnspecified, enum.StatusCompleted, require.True},
+		{enum.StatusDraft, enum.StatusDraft, require.True},
+		{enum.StatusDraft, enum.StatusPending, require.True},
+		{enum.StatusDraft, enum.StatusRejected, require.True},
+		{enum.StatusPending, enum.StatusReview, require.True},
+		{enum.StatusPending, enum.StatusAccepted, require.True},
+		{enum.StatusPending, enum.StatusDraft, require.False},
+		{enum.StatusReview, enum.StatusRepair, require.True},
+		{enum.StatusReview, enum.StatusDraft, require.False},
+		{enum.StatusRepair, enum.StatusPending, require.True},
+		{enum.StatusAccepted, enum.StatusCompleted, require.True},
+		{enum.StatusAccepted, enum.StatusRejected, require.True},
+		{enum.StatusAccepted, enum.StatusPending, require.False},
+		{enum.StatusAccepted, enum.StatusReview, require.False},
+		{enum.StatusCompleted, enum.StatusCompleted, require.True},
+		{enum.StatusCompleted, enum.StatusPending, require.False},
+		{enum.StatusCompleted, enum.StatusRejected, require.False},
+		{enum.StatusRejected, enum.StatusRejected, require.True},
+		{enum.StatusRejected, enum.StatusRepair, require.False},
+		{enum.StatusRejected, enum.StatusAccepted, require.False},
+	}
+
+	for i, tc := range tests {
+		tc.assert(t, tc.from.CanTransition(tc.to), "test case %d failed", i)
+	}
+}
+
+func TestStatusFinal(t *testing.T) {
+	for _, status := range []enum.Status{enum.StatusUnspecified, enum.StatusDraft, enum.StatusPending, enum.StatusReview, enum.StatusRepair, enum.StatusAccepted} {
+		require.False(t, status.Final(), "expected %s to not be final", status)
+	}
+
+	for _, status := range []enum.Status{enum.StatusCompleted, enum.StatusRejected} {
+		require.True(t, status.Final(), "expected %s to be final", status)
+	}
+}
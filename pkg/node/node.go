@@ -1,6 +1,7 @@
 package node
 
 import (
+	"context"
 	"errors"
 	"os"
 	"os/signal"
@@ -9,13 +10,20 @@ import (
 
 	"github.com/trisacrypto/envoy/pkg/audit"
 	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/deadlines"
 	"github.com/trisacrypto/envoy/pkg/directory"
 	"github.com/trisacrypto/envoy/pkg/emails"
 	"github.com/trisacrypto/envoy/pkg/logger"
 	"github.com/trisacrypto/envoy/pkg/metrics"
+	"github.com/trisacrypto/envoy/pkg/outbound"
+	"github.com/trisacrypto/envoy/pkg/reports"
+	"github.com/trisacrypto/envoy/pkg/retention"
 	"github.com/trisacrypto/envoy/pkg/store"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
+	"github.com/trisacrypto/envoy/pkg/store/secrets"
 	"github.com/trisacrypto/envoy/pkg/store/sqlite"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
 	"github.com/trisacrypto/envoy/pkg/trisa"
 	"github.com/trisacrypto/envoy/pkg/trisa/keychain"
 	"github.com/trisacrypto/envoy/pkg/trisa/network"
@@ -118,13 +126,32 @@ func New(conf config.Config) (node *Node, err error) {
 	}
 	audit.UseKeyChain(kc)
 
+	// Configure field level encryption of PII in the database; if field encryption is
+	// not enabled the store continues to write plaintext values.
+	var cipher *pii.Cipher
+	if conf.FieldEncryption.Enabled {
+		if cipher, err = fieldEncryption(conf.FieldEncryption, kc, node.store); err != nil {
+			return nil, err
+		}
+
+		// Previous keys are required to reencrypt existing values when rotating keys.
+		if err = pii.AddPreviousKeys(cipher, conf.FieldEncryption.PreviousKeys...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = node.store.UseFieldEncryption(cipher); err != nil {
+		return nil, err
+	}
+	log.Debug().Str("key_id", cipher.KeyID()).Msg("field encryption configured")
+
 	// Create the admin web ui server if it is enabled
 	if node.admin, err = web.New(conf, node.store, node.network); err != nil {
 		return nil, err
 	}
 
 	// Create the TRISA API server
-	if node.trisa, err = trisa.New(conf.Node, node.network, node.store, node.webhook, node.errc); err != nil {
+	if node.trisa, err = trisa.New(conf.Node, node.network, node.store, node.webhook, thresholds.New(conf, node.store), node.errc); err != nil {
 		return nil, err
 	}
 
@@ -138,22 +165,64 @@ func New(conf config.Config) (node *Node, err error) {
 		return nil, err
 	}
 
+	// Create the data retention background routine
+	if node.retention, err = retention.New(conf.Retention, node.store); err != nil {
+		return nil, err
+	}
+
+	// Create the reply deadlines background routine
+	if node.deadlines, err = deadlines.New(conf, node.store, node.webhook, node.admin); err != nil {
+		return nil, err
+	}
+
+	// Create the outbound queue background routine
+	if node.outbound, err = outbound.New(conf, node.store, node.admin); err != nil {
+		return nil, err
+	}
+
+	// Create the regulatory reports background routine
+	if node.reports, err = reports.New(conf, node.store); err != nil {
+		return nil, err
+	}
+
 	return node, nil
 }
 
+// Load the field encryption key from the configuration, the secret manager, or from the
+// database where it is wrapped by the node's storage key (in that order).
+func fieldEncryption(conf config.FieldEncryptionConfig, kc keychain.KeyChain, db store.Store) (_ *pii.Cipher, err error) {
+	switch {
+	case conf.Key != "":
+		return pii.FromKey(conf.Key)
+	case conf.SecretName != "":
+		var sm *secrets.GCP
+		if sm, err = secrets.NewGCP(); err != nil {
+			return nil, err
+		}
+		defer sm.Close()
+		return pii.FromSecret(context.Background(), sm, conf.SecretProject, conf.SecretName)
+	default:
+		return pii.FromKeyChain(context.Background(), kc, db)
+	}
+}
+
 // Node implements the complete TRISA Self Hosted Node including the TRISA gRPC server,
 // the TRP API server, the web compliance and admin user interface, and the internal API
 // server, along with kubernetes probes and metrics if required.
 type Node struct {
-	conf    config.Config
-	admin   *web.Server
-	trisa   *trisa.Server
-	trp     *trp.Server
-	syncd   *directory.Sync
-	store   store.Store
-	network network.Network
-	webhook webhook.Handler
-	errc    chan error
+	conf      config.Config
+	admin     *web.Server
+	trisa     *trisa.Server
+	trp       *trp.Server
+	syncd     *directory.Sync
+	retention *retention.Enforcer
+	deadlines *deadlines.Scheduler
+	outbound  *outbound.Queue
+	reports   *reports.Scheduler
+	store     store.Store
+	network   network.Network
+	webhook   webhook.Handler
+	errc      chan error
 }
 
 // Serve all enabled services based on configuration and block until shutdown or until
@@ -173,6 +242,26 @@ func (s *Node) Serve() (err error) {
 		if err = s.syncd.Run(); err != nil {
 			return err
 		}
+
+		// Run the data retention service
+		if err = s.retention.Run(); err != nil {
+			return err
+		}
+
+		// Run the reply deadlines service
+		if err = s.deadlines.Run(); err != nil {
+			return err
+		}
+
+		// Run the outbound queue service
+		if err = s.outbound.Run(); err != nil {
+			return err
+		}
+
+		// Run the regulatory reports service
+		if err = s.reports.Run(); err != nil {
+			return err
+		}
 	}
 
 	// Start the web ui server if it is enabled
@@ -207,6 +296,22 @@ func (s *Node) Shutdown() (err error) {
 		if serr := s.syncd.Stop(); serr != nil {
 			err = errors.Join(err, serr)
 		}
+
+		if serr := s.retention.Stop(); serr != nil {
+			err = errors.Join(err, serr)
+		}
+
+		if serr := s.deadlines.Stop(); serr != nil {
+			err = errors.Join(err, serr)
+		}
+
+		if serr := s.outbound.Stop(); serr != nil {
+			err = errors.Join(err, serr)
+		}
+
+		if serr := s.reports.Stop(); serr != nil {
+			err = errors.Join(err, serr)
+		}
 	}
 
 	// Shutdown web ui server if it is enabled.
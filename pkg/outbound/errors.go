@@ -0,0 +1,8 @@
+package outbound
+
+import "errors"
+
+var (
+	ErrQueueAlreadyRunning = errors.New("outbound queue is already running")
+	ErrQueueNotRunning     = errors.New("outbound queue is not running")
+)
@@ -0,0 +1,160 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.rtnl.ai/ulid"
+
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+// Queue is a background routine that sends the transfers in the outbound queue whose
+// next attempt is due at a specified interval. Transfers are queued when they are
+// scheduled to be sent later or when the counterparty could not be reached; the sender
+// records each failed attempt and reschedules the transfer with backoff until it is
+// delivered or the maximum number of attempts is reached.
+type Queue struct {
+	sync.Mutex
+	conf   config.OutboundQueueConfig
+	store  Store
+	sender Sender
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// Store is the subset of the store required by the outbound queue.
+type Store interface {
+	ListDueTransfers(ctx context.Context, before time.Time) ([]*models.QueuedTransfer, error)
+}
+
+// Sender sends a queued transfer to its counterparty through the normal send path and
+// records the result of the attempt; it is implemented by the web server.
+type Sender interface {
+	SendQueuedTransfer(ctx context.Context, transferID ulid.ULID) error
+}
+
+// Creates a new outbound queue service but does not run it.
+func New(conf config.Config, store Store, sender Sender) (*Queue, error) {
+	// Only return an outbound queue stub if not enabled
+	if !conf.OutboundQueue.Enabled {
+		return &Queue{conf: conf.OutboundQueue}, nil
+	}
+
+	return &Queue{
+		conf:   conf.OutboundQueue,
+		store:  store,
+		sender: sender,
+	}, nil
+}
+
+// Run the outbound queue service.
+func (q *Queue) Run() error {
+	// Do not run the service if the outbound queue is not enabled.
+	if !q.conf.Enabled {
+		return nil
+	}
+
+	// Lock the outbound queue routine to initialize and start it.
+	q.Lock()
+	defer q.Unlock()
+
+	if q.stop != nil {
+		return ErrQueueAlreadyRunning
+	}
+
+	q.stop = make(chan struct{})
+	q.done = make(chan struct{})
+	go q.run()
+	return nil
+}
+
+func (q *Queue) run() {
+	ticker := time.NewTicker(q.conf.Interval)
+	log.Info().Dur("outbound_queue_interval", q.conf.Interval).Msg("outbound queue service running")
+
+	// Process the queue at startup. Errors are not fatal since the due transfers will
+	// be sent again on the next interval.
+	if err := q.Process(); err != nil {
+		log.Warn().Err(err).Msg("could not process outbound queue")
+	}
+
+queueloop:
+	for {
+		select {
+		case <-q.stop:
+			break queueloop
+		case <-ticker.C:
+			if err := q.Process(); err != nil {
+				log.Warn().Err(err).Msg("could not process outbound queue")
+			}
+		}
+	}
+
+	ticker.Stop()
+	close(q.done)
+	log.Info().Msg("outbound queue service stopped")
+}
+
+// Stop the outbound queue service, blocking until the service is shutdown.
+func (q *Queue) Stop() error {
+	// Do not stop the outbound queue service if it is not enabled
+	if !q.conf.Enabled {
+		return nil
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	if q.stop == nil {
+		return ErrQueueNotRunning
+	}
+
+	// Send the stop signal and wait for routine to stop.
+	close(q.stop)
+	<-q.done
+
+	q.stop = nil
+	q.done = nil
+	return nil
+}
+
+// Process lists the queued transfers whose next attempt is due and sends each of them
+// to its counterparty. A failure to send one transfer does not prevent the others from
+// being sent; all errors are joined and returned once every transfer has been sent.
+func (q *Queue) Process() (err error) {
+	log.Debug().Msg("starting outbound queue processing")
+
+	// Add actor information to the context for the audit log
+	ctx := audit.WithActor(context.Background(), []byte("Queue.Process()"), enum.ActorSystem)
+
+	var transfers []*models.QueuedTransfer
+	if transfers, err = q.store.ListDueTransfers(ctx, time.Now()); err != nil {
+		return err
+	}
+
+	var sent int
+	for _, transfer := range transfers {
+		if serr := q.sender.SendQueuedTransfer(ctx, transfer.ID); serr != nil {
+			log.Warn().Err(serr).
+				Str("transfer_id", transfer.ID.String()).
+				Str("transaction_id", transfer.TransactionID.String()).
+				Msg("could not send queued transfer")
+			err = errors.Join(err, serr)
+			continue
+		}
+		sent++
+	}
+
+	log.Info().
+		Int("due", len(transfers)).
+		Int("sent", sent).
+		Msg("outbound queue processing complete")
+	return err
+}
@@ -0,0 +1,110 @@
+package outbound_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/outbound"
+	store "github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func TestStartStop(t *testing.T) {
+	db := mockStore(nil)
+	conf := config.Config{
+		OutboundQueue: config.OutboundQueueConfig{Enabled: true, Interval: time.Hour, MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour},
+	}
+
+	svc, err := outbound.New(conf, db, &mockSender{})
+	require.NoError(t, err, "could not create outbound queue service")
+
+	require.ErrorIs(t, svc.Stop(), outbound.ErrQueueNotRunning)
+	require.NoError(t, svc.Run(), "could not run outbound queue service")
+	require.ErrorIs(t, svc.Run(), outbound.ErrQueueAlreadyRunning)
+	require.NoError(t, svc.Stop(), "could not stop outbound queue service")
+
+	db.AssertCalls(t, "ListDueTransfers", 1)
+}
+
+func TestDisabled(t *testing.T) {
+	svc, err := outbound.New(config.Config{}, nil, nil)
+	require.NoError(t, err, "could not create outbound queue service")
+	require.NoError(t, svc.Run(), "expected no error running disabled service")
+	require.NoError(t, svc.Stop(), "expected no error stopping disabled service")
+}
+
+func TestProcess(t *testing.T) {
+	conf := config.Config{
+		OutboundQueue: config.OutboundQueueConfig{Enabled: true, Interval: time.Minute, MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Hour},
+	}
+
+	transfers := []*models.QueuedTransfer{
+		{Model: models.Model{ID: ulid.MakeSecure()}},
+		{Model: models.Model{ID: ulid.MakeSecure()}},
+		{Model: models.Model{ID: ulid.MakeSecure()}},
+	}
+
+	t.Run("Send", func(t *testing.T) {
+		db := mockStore(transfers)
+		sender := &mockSender{}
+
+		svc, _ := outbound.New(conf, db, sender)
+		require.NoError(t, svc.Process(), "could not process outbound queue")
+		require.Equal(t, []ulid.ULID{transfers[0].ID, transfers[1].ID, transfers[2].ID}, sender.sent)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		db := mockStore(transfers)
+		sender := &mockSender{fail: transfers[1].ID}
+
+		svc, _ := outbound.New(conf, db, sender)
+		require.EqualError(t, svc.Process(), "could not connect to remote counterparty")
+
+		// Other transfers are still sent when one of them fails
+		require.Equal(t, []ulid.ULID{transfers[0].ID, transfers[1].ID, transfers[2].ID}, sender.sent)
+	})
+
+	t.Run("StoreError", func(t *testing.T) {
+		db := mockStore(nil)
+		db.OnListDueTransfers = func(context.Context, time.Time) ([]*models.QueuedTransfer, error) {
+			return nil, errors.New("database is locked")
+		}
+		sender := &mockSender{}
+
+		svc, _ := outbound.New(conf, db, sender)
+		require.EqualError(t, svc.Process(), "database is locked")
+		require.Len(t, sender.sent, 0, "expected no transfers to be sent")
+	})
+}
+
+type mockSender struct {
+	sent []ulid.ULID
+	fail ulid.ULID
+}
+
+func (m *mockSender) SendQueuedTransfer(ctx context.Context, transferID ulid.ULID) error {
+	if actorType, ok := audit.ActorType(ctx); !ok || actorType != enum.ActorSystem {
+		return errors.New("expected system actor in context")
+	}
+
+	m.sent = append(m.sent, transferID)
+	if transferID == m.fail {
+		return errors.New("could not connect to remote counterparty")
+	}
+	return nil
+}
+
+func mockStore(transfers []*models.QueuedTransfer) *store.Store {
+	db, _ := store.Open(nil)
+	db.OnListDueTransfers = func(context.Context, time.Time) ([]*models.QueuedTransfer, error) {
+		return transfers, nil
+	}
+	return db
+}
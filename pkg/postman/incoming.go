@@ -146,6 +146,11 @@ func (i *Incoming) UpdateTransaction() (err error) {
 		Valid: !timestamp.IsZero(), Time: timestamp,
 	}
 
+	// If this is a pending message, record the deadline for the reply
+	if payload := i.Envelope.FindPayload(); payload != nil {
+		i.packet.Transaction.ReplyNotAfter = ReplyNotAfter(payload)
+	}
+
 	// Update the transaction in the database
 	if err = i.packet.DB.Update(i.packet.Transaction, &models.ComplianceAuditLog{
 		ChangeNotes: sql.NullString{Valid: true, String: "Incoming.UpdateTransaction()"},
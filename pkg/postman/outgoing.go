@@ -161,6 +161,11 @@ func (o *Outgoing) UpdateTransaction() (err error) {
 		Valid: !timestamp.IsZero(), Time: timestamp,
 	}
 
+	// If this is a pending message, record the deadline for the reply
+	if payload := o.Envelope.FindPayload(); payload != nil {
+		o.packet.Transaction.ReplyNotAfter = ReplyNotAfter(payload)
+	}
+
 	// Update the transaction in the database
 	if err = o.packet.DB.Update(o.packet.Transaction, &models.ComplianceAuditLog{
 		ChangeNotes: sql.NullString{Valid: true, String: "Outgoing.UpdateTransaction()"},
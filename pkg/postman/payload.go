@@ -28,15 +28,7 @@ func TransactionFromPayload(in *api.Payload) *models.Transaction {
 
 	data := &generic.Transaction{}
 	if err = in.Transaction.UnmarshalTo(data); err == nil {
-		switch {
-		case data.Network != "" && data.AssetType != "":
-			virtualAsset = fmt.Sprintf("%s (%s)", data.Network, data.AssetType)
-		case data.Network != "":
-			virtualAsset = data.Network
-		case data.AssetType != "":
-			virtualAsset = data.AssetType
-		}
-
+		virtualAsset = VirtualAsset(data.Network, data.AssetType)
 		amount = data.Amount
 		originatorAddress = data.Originator
 		beneficiaryAddress = data.Beneficiary
@@ -71,6 +63,39 @@ func TransactionFromPayload(in *api.Payload) *models.Transaction {
 	}
 }
 
+// ReplyNotAfter returns the reply deadline of a pending payload in UTC so that it can
+// be stored on the transaction; the deadline is null if the payload is not a pending
+// message or if the deadline cannot be parsed.
+func ReplyNotAfter(in *api.Payload) (deadline sql.NullTime) {
+	if in == nil || in.Transaction == nil {
+		return deadline
+	}
+
+	pending := &generic.Pending{}
+	if err := in.Transaction.UnmarshalTo(pending); err != nil {
+		return deadline
+	}
+
+	if ts, err := time.Parse(time.RFC3339, pending.ReplyNotAfter); err == nil {
+		deadline = sql.NullTime{Valid: true, Time: ts.UTC()}
+	}
+	return deadline
+}
+
+// VirtualAsset returns the representation of the network and asset type of a transfer
+// that is stored on the transaction, e.g. "Bitcoin (BTC)" or just the network or the
+// asset type if only one is available.
+func VirtualAsset(network, assetType string) string {
+	switch {
+	case network != "" && assetType != "":
+		return fmt.Sprintf("%s (%s)", network, assetType)
+	case network != "":
+		return network
+	default:
+		return assetType
+	}
+}
+
 func FindName(persons ...*ivms101.Person) (name string) {
 	// Search all persons for the first legal name available. Use the last available
 	// non-zero name for any other name identifier types.
@@ -2,10 +2,14 @@ package postman_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/trisacrypto/envoy/pkg/postman"
 	"github.com/trisacrypto/trisa/pkg/ivms101"
+	api "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"google.golang.org/protobuf/types/known/anypb"
 )
 
 func TestTransactionFromPayload(t *testing.T) {
@@ -27,6 +31,30 @@ func TestTransactionFromPayload(t *testing.T) {
 	})
 }
 
+func TestReplyNotAfter(t *testing.T) {
+	t.Run("Pending", func(t *testing.T) {
+		pending, err := anypb.New(&generic.Pending{ReplyNotAfter: "2024-05-01T12:00:00-04:00"})
+		require.NoError(t, err, "could not create pending payload")
+
+		deadline := postman.ReplyNotAfter(&api.Payload{Transaction: pending})
+		require.True(t, deadline.Valid)
+		require.Equal(t, time.Date(2024, 5, 1, 16, 0, 0, 0, time.UTC), deadline.Time)
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		payload, err := loadPayloadFixture("testdata/identity.pb.json", "testdata/transaction.pb.json")
+		require.NoError(t, err, "could not load payload from fixtures")
+		require.False(t, postman.ReplyNotAfter(payload).Valid)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		pending, err := anypb.New(&generic.Pending{ReplyNotAfter: "tomorrow"})
+		require.NoError(t, err, "could not create pending payload")
+		require.False(t, postman.ReplyNotAfter(&api.Payload{Transaction: pending}).Valid)
+		require.False(t, postman.ReplyNotAfter(nil).Valid)
+	})
+}
+
 func TestFindName(t *testing.T) {
 	testCases := []struct {
 		persons  []*ivms101.Person
@@ -0,0 +1,88 @@
+package postman
+
+import (
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// PendingPayload creates a pending response to the incoming payload using the pending
+// configuration and the pending overrides of the packet's counterparty. If the
+// counterparty has not been loaded from the database yet (e.g. it was created from the
+// peer certificates) then its overrides are looked up by common name.
+func (p *Packet) PendingPayload(in *trisa.Payload, envelopeID string, conf config.PendingConfig) (*trisa.Payload, error) {
+	counterparty := p.Counterparty
+	if counterparty != nil && counterparty.ID.IsZero() && counterparty.CommonName != "" && p.DB != nil {
+		if record, err := p.DB.LookupCounterparty(models.FieldCommonName, counterparty.CommonName); err == nil {
+			counterparty = record
+		} else {
+			p.Log.Debug().Err(err).Msg("could not lookup counterparty pending overrides, using pending configuration")
+		}
+	}
+
+	return PendingPayload(in, envelopeID, conf, counterparty)
+}
+
+// PendingPayload creates a pending response to the incoming payload whose message and
+// reply windows are determined by the pending configuration, overridden by the pending
+// overrides of the counterparty if it is not nil. The transaction of the incoming
+// payload, if any, is included in the pending message.
+func PendingPayload(in *trisa.Payload, envelopeID string, conf config.PendingConfig, counterparty *models.Counterparty) (out *trisa.Payload, err error) {
+	ts := time.Now().UTC()
+	out = &trisa.Payload{
+		Identity:   in.Identity,
+		SentAt:     in.SentAt,
+		ReceivedAt: ts.Format(time.RFC3339),
+	}
+
+	var overrides models.PendingOverrides
+	data := &config.PendingData{Organization: conf.Organization}
+	if counterparty != nil {
+		overrides = counterparty.Pending
+		data.Counterparty = counterparty.Name
+	}
+
+	notBefore, notAfter := overrides.ReplyWindows(conf.ReplyNotBefore, conf.ReplyNotAfter)
+	data.ReplyNotBefore = ts.Add(notBefore)
+	data.ReplyNotAfter = ts.Add(notAfter)
+
+	pending := &generic.Pending{
+		EnvelopeId:  envelopeID,
+		ReceivedBy:  conf.Organization,
+		ReceivedAt:  ts.Format(time.RFC3339),
+		Transaction: &generic.Transaction{},
+	}
+
+	// A zero reply window means that no reply deadline is given to the counterparty
+	if notBefore > 0 {
+		pending.ReplyNotBefore = data.ReplyNotBefore.Format(time.RFC3339)
+	}
+
+	if notAfter > 0 {
+		pending.ReplyNotAfter = data.ReplyNotAfter.Format(time.RFC3339)
+	}
+
+	message := conf.Template(overrides.Language.String)
+	if overrides.Message.Valid {
+		message = overrides.Message.String
+	}
+
+	if pending.Message, err = config.RenderPending(message, data); err != nil {
+		return nil, err
+	}
+
+	// If we've received a transaction, add it to the pending response
+	// NOTE: ignoring errors here, expecting transaction to be nil if we didn't receive
+	// an incoming transaction (e.g. if we received another pending message).
+	in.Transaction.UnmarshalTo(pending.Transaction)
+
+	// Add the pending payload to the transaction
+	if out.Transaction, err = anypb.New(pending); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,106 @@
+package postman_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/postman"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+)
+
+func TestPendingPayload(t *testing.T) {
+	conf := config.PendingConfig{
+		Organization:   "Alice VASP",
+		Message:        "{{ .Organization }} is reviewing the request from {{ .Counterparty }}",
+		Messages:       map[string]string{"de": "{{ .Organization }} prüft die Anfrage von {{ .Counterparty }}"},
+		ReplyNotBefore: 5 * time.Minute,
+		ReplyNotAfter:  24 * time.Hour,
+	}
+
+	envelopeID := "2a2bd2c6-b7a8-4d5c-9fb8-0cbd2b1a0e4d"
+	payload, err := loadPayloadFixture("testdata/identity.pb.json", "testdata/transaction.pb.json")
+	require.NoError(t, err, "could not load payload from fixtures")
+
+	// Unmarshals the pending message and checks the reply windows of the payload.
+	checkPending := func(t *testing.T, counterparty *models.Counterparty, notBefore, notAfter time.Duration) *generic.Pending {
+		now := time.Now().UTC().Truncate(time.Second)
+		out, err := postman.PendingPayload(payload, envelopeID, conf, counterparty)
+		require.NoError(t, err, "could not create pending payload")
+		require.Equal(t, payload.Identity, out.Identity, "expected identity to be echoed")
+		require.Equal(t, payload.SentAt, out.SentAt, "expected sent at to be echoed")
+
+		pending := &generic.Pending{}
+		require.NoError(t, out.Transaction.UnmarshalTo(pending), "expected a pending message")
+		require.Equal(t, envelopeID, pending.EnvelopeId)
+		require.Equal(t, conf.Organization, pending.ReceivedBy)
+		require.Equal(t, 0.46602501, pending.Transaction.Amount, "expected the incoming transaction to be included")
+
+		checkWindow := func(ts string, window time.Duration) {
+			if window == 0 {
+				require.Empty(t, ts, "expected no reply window")
+				return
+			}
+
+			deadline, err := time.Parse(time.RFC3339, ts)
+			require.NoError(t, err, "could not parse reply window")
+			require.WithinDuration(t, now.Add(window), deadline, 2*time.Second)
+		}
+
+		checkWindow(pending.ReplyNotBefore, notBefore)
+		checkWindow(pending.ReplyNotAfter, notAfter)
+		return pending
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		pending := checkPending(t, nil, 5*time.Minute, 24*time.Hour)
+		require.Equal(t, "Alice VASP is reviewing the request from", pending.Message)
+	})
+
+	t.Run("Counterparty", func(t *testing.T) {
+		counterparty := &models.Counterparty{Name: "Bob VASP"}
+		pending := checkPending(t, counterparty, 5*time.Minute, 24*time.Hour)
+		require.Equal(t, "Alice VASP is reviewing the request from Bob VASP", pending.Message)
+	})
+
+	t.Run("Localized", func(t *testing.T) {
+		counterparty := &models.Counterparty{
+			Name: "Bob VASP",
+			Pending: models.PendingOverrides{
+				Language: sql.NullString{Valid: true, String: "de-CH"},
+			},
+		}
+
+		pending := checkPending(t, counterparty, 5*time.Minute, 24*time.Hour)
+		require.Equal(t, "Alice VASP prüft die Anfrage von Bob VASP", pending.Message)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		counterparty := &models.Counterparty{
+			Name: "Bob VASP",
+			Pending: models.PendingOverrides{
+				Language:       sql.NullString{Valid: true, String: "de"},
+				Message:        sql.NullString{Valid: true, String: "Reply expected by {{ .ReplyNotAfter.Format \"2006-01-02\" }}"},
+				ReplyNotBefore: sql.NullInt64{Valid: true, Int64: 0},
+				ReplyNotAfter:  sql.NullInt64{Valid: true, Int64: 7200},
+			},
+		}
+
+		pending := checkPending(t, counterparty, 0, 2*time.Hour)
+		require.Equal(t, "Reply expected by "+time.Now().UTC().Add(2*time.Hour).Format("2006-01-02"), pending.Message)
+	})
+
+	t.Run("BadTemplate", func(t *testing.T) {
+		counterparty := &models.Counterparty{
+			Pending: models.PendingOverrides{
+				Message: sql.NullString{Valid: true, String: "{{ .Unknown }}"},
+			},
+		}
+
+		_, err := postman.PendingPayload(payload, envelopeID, conf, counterparty)
+		require.ErrorContains(t, err, "could not render pending message template")
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/enum"
 	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/trisa/pkg/openvasp/client"
@@ -75,9 +76,16 @@ func ReceiveTRPInquiry(inquiry *trp.Inquiry, mtls *tls.ConnectionState) (packet
 	return packet, nil
 }
 
-func (p *TRPPacket) Resolve(out *trp.Resolution) (err error) {
+// Resolve creates the outgoing envelope from the resolution that is returned to the
+// counterparty. If the inquiry is neither approved nor rejected, the outgoing envelope
+// contains a pending message created from the pending configuration.
+func (p *TRPPacket) Resolve(out *trp.Resolution, pending config.PendingConfig) (err error) {
 	// TODO: handle accepted and rejected envelopes better!
-	var transferState trisa.TransferState
+	var (
+		transferState trisa.TransferState
+		payload       *trisa.Payload
+	)
+
 	switch {
 	case out.Approved != nil:
 		return errors.New("approved resolution not yet supported")
@@ -85,10 +93,14 @@ func (p *TRPPacket) Resolve(out *trp.Resolution) (err error) {
 		return errors.New("rejected resolution not yet supported")
 	default:
 		transferState = trisa.TransferPending
+		if payload, err = p.PendingPayload(p.payload, p.envelopeID.String(), pending); err != nil {
+			p.Log.Debug().Err(err).Msg("could not create pending payload")
+			return fmt.Errorf("could not create outgoing trp pending payload: %w", err)
+		}
 	}
 
 	// TODO: handle accept and reject envelopes!
-	if p.Out.Envelope, err = p.In.Envelope.Update(p.payload, envelope.WithTransferState(transferState)); err != nil {
+	if p.Out.Envelope, err = p.In.Envelope.Update(payload, envelope.WithTransferState(transferState)); err != nil {
 		p.Log.Debug().Err(err).Msg("could not prepare outgoing payload")
 		return fmt.Errorf("could not create outgoing trp resolution envelope: %w", err)
 	}
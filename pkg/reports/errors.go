@@ -0,0 +1,13 @@
+package reports
+
+import "errors"
+
+var (
+	ErrReportsAlreadyRunning = errors.New("reports scheduler is already running")
+	ErrReportsNotRunning     = errors.New("reports scheduler is not running")
+	ErrMissingName           = errors.New("report name is required")
+	ErrMissingPeriod         = errors.New("report period start and end are required")
+	ErrInvalidPeriod         = errors.New("report period start must be before the period end")
+	ErrUnknownSection        = errors.New("unknown report section")
+	ErrUnknownFormat         = errors.New("unknown report format")
+)
@@ -0,0 +1,169 @@
+package reports
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/config"
+)
+
+// Content types of the rendered report formats.
+const (
+	ContentTypeCSV  = "text/csv"
+	ContentTypeJSON = "application/json"
+	ContentTypeHTML = "text/html; charset=utf-8"
+)
+
+// CSVHeader is the header of CSV reports. Every statistic of the report is written as
+// a row so that the sections of the report can be loaded into a single table.
+var CSVHeader = []string{"section", "key", "virtual_asset", "metric", "value"}
+
+var (
+	//go:embed report.html
+	htmlSource string
+	htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+		"title":   SectionTitle,
+		"decimal": decimal,
+		"date":    func(ts time.Time) string { return ts.UTC().Format(time.RFC3339) },
+	}).Parse(htmlSource))
+)
+
+// ContentType returns the content type of the report format.
+func ContentType(format string) (string, error) {
+	switch format {
+	case config.ReportCSV:
+		return ContentTypeCSV, nil
+	case config.ReportJSON:
+		return ContentTypeJSON, nil
+	case config.ReportHTML:
+		return ContentTypeHTML, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Filename returns the filename to download or attach the report in the format.
+func (r *Report) Filename(format string) string {
+	slug := strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(r.Name), "-"), "-")
+	if slug == "" {
+		slug = "report"
+	}
+	return slug + "." + format
+}
+
+// Render the report in the specified format (csv, json, or html).
+func (r *Report) Render(w io.Writer, format string) error {
+	switch format {
+	case config.ReportCSV:
+		return r.renderCSV(w)
+	case config.ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case config.ReportHTML:
+		return htmlReport.Execute(w, r)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+func (r *Report) renderCSV(w io.Writer) (err error) {
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		CSVHeader,
+		{"report", "name", "", "", r.Name},
+		{"report", "period_start", "", "", r.PeriodStart.UTC().Format(time.RFC3339)},
+		{"report", "period_end", "", "", r.PeriodEnd.UTC().Format(time.RFC3339)},
+		{"report", "generated", "", "", r.Generated.UTC().Format(time.RFC3339)},
+		{"report", "transfers", "", "count", strconv.Itoa(r.Transfers)},
+	}
+
+	for _, section := range r.Sections {
+		switch section {
+		case SectionMissingData:
+			for _, missing := range r.MissingData {
+				rows = append(rows, []string{section, missing.TransactionID.String(), "", "missing", strings.Join(missing.Missing, ";")})
+			}
+		case SectionResponseTimes:
+			for _, rt := range r.ResponseTimes {
+				rows = append(rows,
+					[]string{section, rt.Responder, "", "responses", strconv.Itoa(rt.Responses)},
+					[]string{section, rt.Responder, "", "unanswered", strconv.Itoa(rt.Unanswered)},
+					[]string{section, rt.Responder, "", "mean_seconds", decimal(rt.Mean)},
+					[]string{section, rt.Responder, "", "median_seconds", decimal(rt.Median)},
+					[]string{section, rt.Responder, "", "max_seconds", decimal(rt.Max)},
+				)
+			}
+		default:
+			for _, tally := range r.Tallies(section) {
+				rows = append(rows,
+					[]string{section, tally.Key, tally.VirtualAsset, "count", strconv.Itoa(tally.Count)},
+					[]string{section, tally.Key, tally.VirtualAsset, "volume", decimal(tally.Volume)},
+				)
+			}
+		}
+	}
+
+	if err = writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// Tallies returns the tallies of the section or nil if the section is not tallied.
+func (r *Report) Tallies(section string) []*Tally {
+	switch section {
+	case SectionJurisdiction:
+		return r.Jurisdictions
+	case SectionCounterparty:
+		return r.Counterparties
+	case SectionAsset:
+		return r.Assets
+	case SectionDirection:
+		return r.Directions
+	case SectionOutcome:
+		return r.Outcomes
+	case SectionSunrise:
+		return r.Sunrise
+	default:
+		return nil
+	}
+}
+
+// SectionTitle returns a human readable title of the report section.
+func SectionTitle(section string) string {
+	switch section {
+	case SectionJurisdiction:
+		return "Transfers by Jurisdiction"
+	case SectionCounterparty:
+		return "Transfers by Counterparty"
+	case SectionAsset:
+		return "Transfers by Virtual Asset"
+	case SectionDirection:
+		return "Transfers by Direction"
+	case SectionOutcome:
+		return "Transfers by Outcome"
+	case SectionMissingData:
+		return "Transfers Missing Originator or Beneficiary Data"
+	case SectionSunrise:
+		return "Sunrise Transfers by Outcome"
+	case SectionResponseTimes:
+		return "Response Times"
+	default:
+		return section
+	}
+}
+
+func decimal(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
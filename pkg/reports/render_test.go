@@ -0,0 +1,82 @@
+package reports_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/reports"
+)
+
+func TestRender(t *testing.T) {
+	report := &reports.Report{
+		Name:        "Monthly Travel Rule Report May 2024",
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Generated:   periodEnd.Add(time.Hour),
+		Sections:    []string{reports.SectionJurisdiction, reports.SectionMissingData, reports.SectionResponseTimes},
+		Transfers:   3,
+		Jurisdictions: []*reports.Tally{
+			{Key: "DE", VirtualAsset: "BTC", Count: 2, Volume: 1.5},
+			{Key: "US", VirtualAsset: "ETH", Count: 1, Volume: 10},
+		},
+		MissingData: []*reports.MissingData{
+			{TransactionID: uuid.MustParse("b04dc71c-7214-46a5-a514-381ef0bcc494"), Counterparty: "<script>", Direction: reports.DirectionOutgoing, Status: "pending", Created: periodStart, Missing: []string{"originator", "beneficiary"}},
+		},
+		ResponseTimes: []*reports.ResponseTime{
+			{Responder: reports.RespondedByCounterparty, Responses: 2, Unanswered: 1, Mean: 90, Median: 90, Max: 120.5},
+		},
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, report.Render(buf, "csv"), "could not render csv report")
+
+		rows, err := csv.NewReader(buf).ReadAll()
+		require.NoError(t, err, "could not read csv report")
+		require.Equal(t, reports.CSVHeader, rows[0])
+		require.Equal(t, []string{"report", "name", "", "", report.Name}, rows[1])
+		require.Contains(t, rows, []string{"report", "transfers", "", "count", "3"})
+		require.Contains(t, rows, []string{"jurisdiction", "DE", "BTC", "volume", "1.5"})
+		require.Contains(t, rows, []string{"missing_data", "b04dc71c-7214-46a5-a514-381ef0bcc494", "", "missing", "originator;beneficiary"})
+		require.Contains(t, rows, []string{"response_times", "counterparty", "", "max_seconds", "120.5"})
+		require.Len(t, rows, 6+4+1+5)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, report.Render(buf, "json"), "could not render json report")
+
+		cmp := &reports.Report{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), cmp), "could not parse json report")
+		require.Equal(t, report, cmp)
+	})
+
+	t.Run("HTML", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, report.Render(buf, "html"), "could not render html report")
+
+		html := buf.String()
+		require.Contains(t, html, "<h1>Monthly Travel Rule Report May 2024</h1>")
+		require.Contains(t, html, "Transfers by Jurisdiction")
+		require.Contains(t, html, "originator, beneficiary")
+		require.Contains(t, html, "&lt;script&gt;", "expected values to be escaped")
+		require.NotContains(t, html, "Transfers by Outcome", "expected only included sections")
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		require.ErrorIs(t, report.Render(&bytes.Buffer{}, "pdf"), reports.ErrUnknownFormat)
+
+		_, err := reports.ContentType("pdf")
+		require.ErrorIs(t, err, reports.ErrUnknownFormat)
+	})
+
+	t.Run("Filename", func(t *testing.T) {
+		require.Equal(t, "monthly-travel-rule-report-may-2024.csv", report.Filename("csv"))
+		require.Equal(t, "report.html", (&reports.Report{Name: "!!"}).Filename("html"))
+	})
+}
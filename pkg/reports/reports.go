@@ -0,0 +1,437 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+// Sections of a report that can be selected when the report is generated.
+const (
+	SectionJurisdiction  = "jurisdiction"
+	SectionCounterparty  = "counterparty"
+	SectionAsset         = "asset"
+	SectionDirection     = "direction"
+	SectionOutcome       = "outcome"
+	SectionMissingData   = "missing_data"
+	SectionSunrise       = "sunrise"
+	SectionResponseTimes = "response_times"
+)
+
+// Sections lists all of the report sections in the order they are rendered.
+var Sections = []string{
+	SectionJurisdiction,
+	SectionCounterparty,
+	SectionAsset,
+	SectionDirection,
+	SectionOutcome,
+	SectionMissingData,
+	SectionSunrise,
+	SectionResponseTimes,
+}
+
+// Directions of transfers and responders of response times.
+const (
+	DirectionOutgoing       = "outgoing"
+	DirectionIncoming       = "incoming"
+	RespondedByCounterparty = "counterparty"
+	RespondedByLocal        = "local"
+	UnknownJurisdiction     = "unknown"
+)
+
+// Store is the subset of the store required to generate and schedule reports.
+type Store interface {
+	store.ReportStore
+	IterateTransactions(context.Context, *models.TransactionFilter) (models.TransactionIterator, error)
+	RetrieveCounterparty(ctx context.Context, counterpartyID ulid.ULID) (*models.Counterparty, error)
+}
+
+// Params specifies the period and the sections of a report to generate.
+type Params struct {
+	Name        string    // the name of the report
+	PeriodStart time.Time // transactions created on or after this time are reported
+	PeriodEnd   time.Time // transactions created before this time are reported
+	Sections    []string  // the sections of the report; if empty all sections are included
+}
+
+// Validate the report params, returning an error that can be shown to the user.
+func (p *Params) Validate() (err error) {
+	if strings.TrimSpace(p.Name) == "" {
+		err = errors.Join(err, ErrMissingName)
+	}
+
+	if p.PeriodStart.IsZero() || p.PeriodEnd.IsZero() {
+		err = errors.Join(err, ErrMissingPeriod)
+	} else if !p.PeriodStart.Before(p.PeriodEnd) {
+		err = errors.Join(err, ErrInvalidPeriod)
+	}
+
+	return errors.Join(err, ValidateSections(p.Sections))
+}
+
+// ValidateSections returns an error if any of the sections is not a report section.
+func ValidateSections(sections []string) error {
+	for _, section := range sections {
+		if !slices.Contains(Sections, section) {
+			return fmt.Errorf("%w %q", ErrUnknownSection, section)
+		}
+	}
+	return nil
+}
+
+// Report contains the statistics of the travel rule transfers (both active and
+// archived) created during the reporting period. Reports are stored as JSON when they
+// are generated and rendered as CSV, JSON, or HTML when downloaded.
+type Report struct {
+	Name           string          `json:"name"`
+	PeriodStart    time.Time       `json:"period_start"`
+	PeriodEnd      time.Time       `json:"period_end"`
+	Generated      time.Time       `json:"generated"`
+	Sections       []string        `json:"sections"`
+	Transfers      int             `json:"transfers"`
+	Jurisdictions  []*Tally        `json:"jurisdictions,omitempty"`
+	Counterparties []*Tally        `json:"counterparties,omitempty"`
+	Assets         []*Tally        `json:"assets,omitempty"`
+	Directions     []*Tally        `json:"directions,omitempty"`
+	Outcomes       []*Tally        `json:"outcomes,omitempty"`
+	MissingData    []*MissingData  `json:"missing_data,omitempty"`
+	Sunrise        []*Tally        `json:"sunrise,omitempty"`
+	ResponseTimes  []*ResponseTime `json:"response_times,omitempty"`
+}
+
+// Tally is the number of transfers and the total amount transferred for a key of a
+// section (e.g. a jurisdiction or a status). Volumes are only comparable for the same
+// virtual asset so transfers are tallied by key and by virtual asset.
+type Tally struct {
+	Key          string  `json:"key"`
+	VirtualAsset string  `json:"virtual_asset"`
+	Count        int     `json:"count"`
+	Volume       float64 `json:"volume"`
+}
+
+// MissingData identifies a transfer that is missing required originator or beneficiary
+// information. The missing values themselves are not included so that the report does
+// not contain any PII.
+type MissingData struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Counterparty  string    `json:"counterparty"`
+	Direction     string    `json:"direction"`
+	Status        string    `json:"status"`
+	Created       time.Time `json:"created"`
+	Missing       []string  `json:"missing"`
+}
+
+// ResponseTime summarizes how long it took to reply to the first secure envelope of a
+// transfer: the counterparty replies to outgoing transfers and the local node replies
+// to incoming transfers. Transfers without a reply are counted as unanswered.
+type ResponseTime struct {
+	Responder  string  `json:"responder"`
+	Responses  int     `json:"responses"`
+	Unanswered int     `json:"unanswered"`
+	Mean       float64 `json:"mean_seconds"`
+	Median     float64 `json:"median_seconds"`
+	Max        float64 `json:"max_seconds"`
+}
+
+// Includes returns true if the section was included when the report was generated.
+func (r *Report) Includes(section string) bool {
+	return slices.Contains(r.Sections, section)
+}
+
+// Generate a report of the transfers created during the period of the params.
+func Generate(ctx context.Context, db Store, params Params) (report *Report, err error) {
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+
+	report = &Report{
+		Name:        params.Name,
+		PeriodStart: params.PeriodStart.UTC(),
+		PeriodEnd:   params.PeriodEnd.UTC(),
+		Generated:   time.Now().UTC(),
+		Sections:    params.Sections,
+	}
+
+	if len(report.Sections) == 0 {
+		report.Sections = Sections
+	}
+
+	g := &generator{
+		db:             db,
+		report:         report,
+		counterparties: make(map[ulid.ULID]*models.Counterparty),
+		tallies:        make(map[string]map[[2]string]*Tally),
+		sources:        make(map[uuid.UUID]enum.Source),
+	}
+
+	// Both active and archived transfers are reported.
+	for _, archives := range []bool{false, true} {
+		if err = g.iterate(ctx, &models.TransactionFilter{After: params.PeriodStart, Before: params.PeriodEnd, Archives: archives}); err != nil {
+			return nil, err
+		}
+	}
+
+	if report.Includes(SectionResponseTimes) {
+		if err = g.responseTimes(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	report.Jurisdictions = g.sorted(SectionJurisdiction)
+	report.Counterparties = g.sorted(SectionCounterparty)
+	report.Assets = g.sorted(SectionAsset)
+	report.Directions = g.sorted(SectionDirection)
+	report.Outcomes = g.sorted(SectionOutcome)
+	report.Sunrise = g.sorted(SectionSunrise)
+	return report, nil
+}
+
+// Period returns the last complete period of the frequency before now in UTC. Weekly
+// periods start on Monday and monthly periods start on the first of the month.
+func Period(frequency string, now time.Time) (start, end time.Time) {
+	now = now.UTC()
+	end = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch frequency {
+	case config.ReportWeekly:
+		end = end.AddDate(0, 0, -((int(end.Weekday()) + 6) % 7))
+		start = end.AddDate(0, 0, -7)
+	case config.ReportMonthly:
+		end = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		start = end.AddDate(0, -1, 0)
+	default:
+		start = end.AddDate(0, 0, -1)
+	}
+	return start, end
+}
+
+// Name returns the name of a scheduled report for the period starting at start.
+func Name(frequency string, start time.Time) string {
+	switch frequency {
+	case config.ReportWeekly:
+		return "Weekly Travel Rule Report " + start.Format(time.DateOnly)
+	case config.ReportMonthly:
+		return "Monthly Travel Rule Report " + start.Format("January 2006")
+	default:
+		return "Daily Travel Rule Report " + start.Format(time.DateOnly)
+	}
+}
+
+type generator struct {
+	db             Store
+	report         *Report
+	counterparties map[ulid.ULID]*models.Counterparty
+	tallies        map[string]map[[2]string]*Tally
+	sources        map[uuid.UUID]enum.Source
+}
+
+func (g *generator) iterate(ctx context.Context, filter *models.TransactionFilter) (err error) {
+	var iter models.TransactionIterator
+	if iter, err = g.db.IterateTransactions(ctx, filter); err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.Next() {
+		if err = g.add(ctx, iter.Transaction()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func (g *generator) add(ctx context.Context, tx *models.Transaction) (err error) {
+	g.report.Transfers++
+	g.sources[tx.ID] = tx.Source
+
+	direction := DirectionIncoming
+	if tx.Source == enum.SourceLocal {
+		direction = DirectionOutgoing
+	}
+
+	var counterparty *models.Counterparty
+	if g.report.Includes(SectionJurisdiction) || g.report.Includes(SectionSunrise) {
+		if counterparty, err = g.counterparty(ctx, tx.CounterpartyID); err != nil {
+			return err
+		}
+	}
+
+	if g.report.Includes(SectionJurisdiction) {
+		jurisdiction := UnknownJurisdiction
+		if counterparty != nil && counterparty.Country.String != "" {
+			jurisdiction = strings.ToUpper(counterparty.Country.String)
+		}
+		g.tally(SectionJurisdiction, jurisdiction, tx)
+	}
+
+	g.tally(SectionCounterparty, tx.Counterparty, tx)
+	g.tally(SectionAsset, tx.VirtualAsset, tx)
+	g.tally(SectionDirection, direction, tx)
+	g.tally(SectionOutcome, tx.Status.String(), tx)
+
+	if counterparty != nil && counterparty.Protocol == enum.ProtocolSunrise {
+		g.tally(SectionSunrise, tx.Status.String(), tx)
+	}
+
+	if g.report.Includes(SectionMissingData) {
+		var missing []string
+		for _, field := range []struct {
+			name  string
+			value string
+		}{
+			{"originator", tx.Originator.String},
+			{"originator_address", tx.OriginatorAddress.String},
+			{"beneficiary", tx.Beneficiary.String},
+			{"beneficiary_address", tx.BeneficiaryAddress.String},
+		} {
+			if strings.TrimSpace(field.value) == "" {
+				missing = append(missing, field.name)
+			}
+		}
+
+		if len(missing) > 0 {
+			g.report.MissingData = append(g.report.MissingData, &MissingData{
+				TransactionID: tx.ID,
+				Counterparty:  tx.Counterparty,
+				Direction:     direction,
+				Status:        tx.Status.String(),
+				Created:       tx.Created.UTC(),
+				Missing:       missing,
+			})
+		}
+	}
+
+	return nil
+}
+
+// Returns the counterparty of a transfer, caching it for the other transfers with the
+// same counterparty; nil is returned if the transfer has no counterparty or if the
+// counterparty has been deleted.
+func (g *generator) counterparty(ctx context.Context, id ulid.NullULID) (_ *models.Counterparty, err error) {
+	if !id.Valid {
+		return nil, nil
+	}
+
+	counterparty, ok := g.counterparties[id.ULID]
+	if !ok {
+		if counterparty, err = g.db.RetrieveCounterparty(ctx, id.ULID); err != nil && !errors.Is(err, dberr.ErrNotFound) {
+			return nil, err
+		}
+		g.counterparties[id.ULID] = counterparty
+	}
+	return counterparty, nil
+}
+
+func (g *generator) tally(section, key string, tx *models.Transaction) {
+	if !g.report.Includes(section) {
+		return
+	}
+
+	tallies, ok := g.tallies[section]
+	if !ok {
+		tallies = make(map[[2]string]*Tally)
+		g.tallies[section] = tallies
+	}
+
+	id := [2]string{key, tx.VirtualAsset}
+	tally, ok := tallies[id]
+	if !ok {
+		tally = &Tally{Key: key, VirtualAsset: tx.VirtualAsset}
+		tallies[id] = tally
+	}
+
+	tally.Count++
+	tally.Volume += tx.Amount
+}
+
+// Returns the tallies of the section sorted by key and virtual asset.
+func (g *generator) sorted(section string) []*Tally {
+	tallies, ok := g.tallies[section]
+	if !ok {
+		return nil
+	}
+
+	out := make([]*Tally, 0, len(tallies))
+	for _, tally := range tallies {
+		out = append(out, tally)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Key == out[j].Key {
+			return out[i].VirtualAsset < out[j].VirtualAsset
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func (g *generator) responseTimes(ctx context.Context) (err error) {
+	var timings []*models.EnvelopeTiming
+	if timings, err = g.db.ListEnvelopeTimings(ctx, g.report.PeriodStart, g.report.PeriodEnd); err != nil {
+		return err
+	}
+
+	counterparty := &ResponseTime{Responder: RespondedByCounterparty}
+	local := &ResponseTime{Responder: RespondedByLocal}
+	durations := map[*ResponseTime][]time.Duration{}
+
+	for _, timing := range timings {
+		source, ok := g.sources[timing.TransactionID]
+		if !ok {
+			continue
+		}
+
+		// The responder replies to the first envelope sent by the other party.
+		responder, request, response := counterparty, timing.FirstOutgoing, timing.FirstIncoming
+		if source == enum.SourceRemote {
+			responder, request, response = local, timing.FirstIncoming, timing.FirstOutgoing
+		}
+
+		if !request.Valid {
+			continue
+		}
+
+		if !response.Valid || response.Time.Before(request.Time) {
+			responder.Unanswered++
+			continue
+		}
+
+		durations[responder] = append(durations[responder], response.Time.Sub(request.Time))
+	}
+
+	for _, responder := range []*ResponseTime{counterparty, local} {
+		if values := durations[responder]; len(values) > 0 {
+			slices.Sort(values)
+
+			var total time.Duration
+			for _, value := range values {
+				total += value
+			}
+
+			responder.Responses = len(values)
+			responder.Mean = (total / time.Duration(len(values))).Seconds()
+			responder.Max = values[len(values)-1].Seconds()
+			if mid := len(values) / 2; len(values)%2 == 0 {
+				responder.Median = ((values[mid-1] + values[mid]) / 2).Seconds()
+			} else {
+				responder.Median = values[mid].Seconds()
+			}
+		}
+	}
+
+	g.report.ResponseTimes = []*ResponseTime{counterparty, local}
+	return nil
+}
@@ -0,0 +1,196 @@
+package reports_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/reports"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	store "github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+var (
+	periodStart = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd   = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func TestGenerate(t *testing.T) {
+	db, archived := mockStore(t)
+
+	report, err := reports.Generate(context.Background(), db, reports.Params{Name: "May 2024", PeriodStart: periodStart, PeriodEnd: periodEnd})
+	require.NoError(t, err, "could not generate report")
+	require.Equal(t, reports.Sections, report.Sections, "expected all sections by default")
+	require.Equal(t, 4, report.Transfers, "expected active and archived transfers")
+	db.AssertCalls(t, "IterateTransactions", 2)
+	db.AssertCalls(t, "RetrieveCounterparty", 3)
+
+	require.Equal(t, []*reports.Tally{
+		{Key: "DE", VirtualAsset: "BTC", Count: 2, Volume: 1.5},
+		{Key: "DE", VirtualAsset: "ETH", Count: 1, Volume: 10},
+		{Key: reports.UnknownJurisdiction, VirtualAsset: "BTC", Count: 1, Volume: 0.25},
+	}, report.Jurisdictions)
+
+	require.Equal(t, []*reports.Tally{
+		{Key: reports.DirectionIncoming, VirtualAsset: "BTC", Count: 1, Volume: 0.25},
+		{Key: reports.DirectionOutgoing, VirtualAsset: "BTC", Count: 2, Volume: 1.5},
+		{Key: reports.DirectionOutgoing, VirtualAsset: "ETH", Count: 1, Volume: 10},
+	}, report.Directions)
+
+	require.Equal(t, []*reports.Tally{
+		{Key: "completed", VirtualAsset: "BTC", Count: 1, Volume: 1},
+		{Key: "pending", VirtualAsset: "BTC", Count: 1, Volume: 0.5},
+	}, report.Sunrise, "expected only transfers to sunrise counterparties")
+
+	require.Len(t, report.Assets, 2)
+	require.Len(t, report.Outcomes, 4)
+	require.Len(t, report.Counterparties, 3)
+
+	require.Len(t, report.MissingData, 1)
+	require.Equal(t, archived[0].ID, report.MissingData[0].TransactionID)
+	require.Equal(t, []string{"originator", "beneficiary_address"}, report.MissingData[0].Missing)
+
+	require.Equal(t, []*reports.ResponseTime{
+		{Responder: reports.RespondedByCounterparty, Responses: 2, Unanswered: 1, Mean: 90, Median: 90, Max: 120},
+		{Responder: reports.RespondedByLocal, Responses: 1, Unanswered: 0, Mean: 30, Median: 30, Max: 30},
+	}, report.ResponseTimes)
+}
+
+func TestGenerateSections(t *testing.T) {
+	db, _ := mockStore(t)
+
+	params := reports.Params{Name: "Assets", PeriodStart: periodStart, PeriodEnd: periodEnd, Sections: []string{reports.SectionAsset}}
+	report, err := reports.Generate(context.Background(), db, params)
+	require.NoError(t, err, "could not generate report")
+	require.Equal(t, 4, report.Transfers)
+	require.Len(t, report.Assets, 2)
+	require.Nil(t, report.Jurisdictions)
+	require.Nil(t, report.MissingData)
+	require.Nil(t, report.ResponseTimes)
+
+	// Counterparties and envelopes are only loaded if required by a section
+	db.AssertCalls(t, "RetrieveCounterparty", 0)
+	db.AssertCalls(t, "ListEnvelopeTimings", 0)
+}
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		params reports.Params
+		err    error
+	}{
+		{reports.Params{PeriodStart: periodStart, PeriodEnd: periodEnd}, reports.ErrMissingName},
+		{reports.Params{Name: "foo", PeriodStart: periodStart}, reports.ErrMissingPeriod},
+		{reports.Params{Name: "foo", PeriodStart: periodEnd, PeriodEnd: periodStart}, reports.ErrInvalidPeriod},
+		{reports.Params{Name: "foo", PeriodStart: periodStart, PeriodEnd: periodEnd, Sections: []string{"asset", "foo"}}, reports.ErrUnknownSection},
+	}
+
+	for i, tc := range tests {
+		require.ErrorIs(t, tc.params.Validate(), tc.err, "test case %d failed", i)
+	}
+
+	valid := reports.Params{Name: "foo", PeriodStart: periodStart, PeriodEnd: periodEnd, Sections: []string{"asset", "sunrise"}}
+	require.NoError(t, valid.Validate())
+}
+
+func TestPeriod(t *testing.T) {
+	// Wednesday, June 12 2024
+	now := time.Date(2024, 6, 12, 14, 32, 0, 0, time.UTC)
+
+	tests := []struct {
+		frequency  string
+		start, end time.Time
+		name       string
+	}{
+		{config.ReportDaily, time.Date(2024, 6, 11, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC), "Daily Travel Rule Report 2024-06-11"},
+		{config.ReportWeekly, time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), "Weekly Travel Rule Report 2024-06-03"},
+		{config.ReportMonthly, periodStart, periodEnd, "Monthly Travel Rule Report May 2024"},
+	}
+
+	for _, tc := range tests {
+		start, end := reports.Period(tc.frequency, now)
+		require.True(t, tc.start.Equal(start), "unexpected %s period start %s", tc.frequency, start)
+		require.True(t, tc.end.Equal(end), "unexpected %s period end %s", tc.frequency, end)
+		require.Equal(t, tc.name, reports.Name(tc.frequency, start))
+	}
+
+	// On a Monday the last week ends today
+	start, end := reports.Period(config.ReportWeekly, time.Date(2024, 6, 10, 0, 0, 1, 0, time.UTC))
+	require.True(t, time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC).Equal(start))
+	require.True(t, time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC).Equal(end))
+
+	// January reports the previous December
+	start, _ = reports.Period(config.ReportMonthly, time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC))
+	require.True(t, time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC).Equal(start))
+}
+
+// Returns a mock store with two active and two archived transfers created in May 2024.
+func mockStore(t *testing.T) (db *store.Store, archived []*models.Transaction) {
+	db, err := store.Open(nil)
+	require.NoError(t, err, "could not open mock store")
+
+	sunrise := &models.Counterparty{Model: models.Model{ID: ulid.MakeSecure()}, Protocol: enum.ProtocolSunrise, Country: sql.NullString{Valid: true, String: "de"}}
+	trisa := &models.Counterparty{Model: models.Model{ID: ulid.MakeSecure()}, Protocol: enum.ProtocolTRISA, Country: sql.NullString{Valid: true, String: "DE"}}
+	deleted := ulid.MakeSecure()
+
+	complete := func(tx *models.Transaction) *models.Transaction {
+		tx.ID = uuid.New()
+		tx.Created = periodStart.Add(48 * time.Hour)
+		tx.Originator = sql.NullString{Valid: true, String: "Alice"}
+		tx.OriginatorAddress = sql.NullString{Valid: true, String: "n2H1gGu5GEGxgqDd3oT6ysxBLTxaKwqvkw"}
+		tx.Beneficiary = sql.NullString{Valid: true, String: "Bob"}
+		tx.BeneficiaryAddress = sql.NullString{Valid: true, String: "mvF2QdV1oQ4nXVDQvRhNTXyAAK3ukhqS3u"}
+		return tx
+	}
+
+	active := []*models.Transaction{
+		complete(&models.Transaction{Source: enum.SourceLocal, Status: enum.StatusCompleted, Counterparty: "Sunrise VASP", CounterpartyID: ulid.NullULID{Valid: true, ULID: sunrise.ID}, VirtualAsset: "BTC", Amount: 1}),
+		complete(&models.Transaction{Source: enum.SourceRemote, Status: enum.StatusReview, Counterparty: "Deleted VASP", CounterpartyID: ulid.NullULID{Valid: true, ULID: deleted}, VirtualAsset: "BTC", Amount: 0.25}),
+	}
+
+	archived = []*models.Transaction{
+		complete(&models.Transaction{Source: enum.SourceLocal, Status: enum.StatusPending, Counterparty: "Sunrise VASP", CounterpartyID: ulid.NullULID{Valid: true, ULID: sunrise.ID}, VirtualAsset: "BTC", Amount: 0.5, Archived: true}),
+		complete(&models.Transaction{Source: enum.SourceLocal, Status: enum.StatusRejected, Counterparty: "TRISA VASP", CounterpartyID: ulid.NullULID{Valid: true, ULID: trisa.ID}, VirtualAsset: "ETH", Amount: 10, Archived: true}),
+	}
+	archived[0].Originator = sql.NullString{}
+	archived[0].BeneficiaryAddress = sql.NullString{Valid: true, String: " "}
+
+	db.OnIterateTransactions = func(_ context.Context, filter *models.TransactionFilter) (models.TransactionIterator, error) {
+		require.True(t, periodStart.Equal(filter.After), "unexpected period start")
+		require.True(t, periodEnd.Equal(filter.Before), "unexpected period end")
+
+		if filter.Archives {
+			return store.NewTransactionIterator(archived...), nil
+		}
+		return store.NewTransactionIterator(active...), nil
+	}
+
+	db.OnRetrieveCounterparty = func(_ context.Context, id ulid.ULID) (*models.Counterparty, error) {
+		switch id {
+		case sunrise.ID:
+			return sunrise, nil
+		case trisa.ID:
+			return trisa, nil
+		}
+		return nil, dberr.ErrNotFound
+	}
+
+	sent := periodStart.Add(50 * time.Hour)
+	db.OnListEnvelopeTimings = func(context.Context, time.Time, time.Time) ([]*models.EnvelopeTiming, error) {
+		return []*models.EnvelopeTiming{
+			{TransactionID: active[0].ID, FirstOutgoing: sql.NullTime{Valid: true, Time: sent}, FirstIncoming: sql.NullTime{Valid: true, Time: sent.Add(2 * time.Minute)}},
+			{TransactionID: active[1].ID, FirstIncoming: sql.NullTime{Valid: true, Time: sent}, FirstOutgoing: sql.NullTime{Valid: true, Time: sent.Add(30 * time.Second)}},
+			{TransactionID: archived[0].ID, FirstOutgoing: sql.NullTime{Valid: true, Time: sent}},
+			{TransactionID: archived[1].ID, FirstOutgoing: sql.NullTime{Valid: true, Time: sent}, FirstIncoming: sql.NullTime{Valid: true, Time: sent.Add(time.Minute)}},
+			{TransactionID: uuid.New(), FirstOutgoing: sql.NullTime{Valid: true, Time: sent}},
+		}, nil
+	}
+
+	return db, archived
+}
@@ -0,0 +1,228 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/emails"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+// CreatedByScheduler is the creator of reports generated by the scheduler.
+const CreatedByScheduler = "Scheduler"
+
+// Scheduler is a background routine that checks at a specified interval if the report
+// of the last complete period (day, week, or month) has been generated. If not, the
+// report is generated and stored and then emailed to the configured recipients in the
+// configured format. Only one report is stored for each scheduled period so that the
+// report is not generated again if the node is restarted.
+type Scheduler struct {
+	sync.Mutex
+	conf    config.ReportsConfig
+	web     config.WebConfig
+	support string
+	store   Store
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Creates a new reports scheduler but does not run it.
+func New(conf config.Config, store Store) (*Scheduler, error) {
+	// Only return a reports stub if not enabled
+	if !conf.Reports.Enabled {
+		return &Scheduler{conf: conf.Reports}, nil
+	}
+
+	if err := ValidateSections(conf.Reports.Sections); err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		conf:    conf.Reports,
+		web:     conf.Web,
+		support: conf.Email.SupportEmail,
+		store:   store,
+	}, nil
+}
+
+// Run the reports scheduler.
+func (s *Scheduler) Run() error {
+	// Do not run the scheduler if reports are not enabled.
+	if !s.conf.Enabled {
+		return nil
+	}
+
+	// Lock the reports routine to initialize and start it.
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stop != nil {
+		return ErrReportsAlreadyRunning
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+	return nil
+}
+
+func (s *Scheduler) run() {
+	ticker := time.NewTicker(s.conf.Interval)
+	log.Info().Dur("reports_interval", s.conf.Interval).Str("frequency", s.conf.Frequency).Msg("reports scheduler running")
+
+	// Check the reports at startup. Errors are not fatal since the report will be
+	// generated on the next interval.
+	if err := s.Check(); err != nil {
+		log.Warn().Err(err).Msg("could not generate scheduled report")
+	}
+
+reportsloop:
+	for {
+		select {
+		case <-s.stop:
+			break reportsloop
+		case <-ticker.C:
+			if err := s.Check(); err != nil {
+				log.Warn().Err(err).Msg("could not generate scheduled report")
+			}
+		}
+	}
+
+	ticker.Stop()
+	close(s.done)
+	log.Info().Msg("reports scheduler stopped")
+}
+
+// Stop the reports scheduler, blocking until the scheduler is shutdown.
+func (s *Scheduler) Stop() error {
+	// Do not stop the scheduler if it is not enabled
+	if !s.conf.Enabled {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stop == nil {
+		return ErrReportsNotRunning
+	}
+
+	// Send the stop signal and wait for routine to stop.
+	close(s.stop)
+	<-s.done
+
+	s.stop = nil
+	s.done = nil
+	return nil
+}
+
+// Check generates the report of the last complete period if it has not already been
+// generated, then emails it to the recipients.
+func (s *Scheduler) Check() (err error) {
+	log.Debug().Msg("starting scheduled reports check")
+
+	// Add actor information to the context for the audit log
+	ctx := audit.WithActor(context.Background(), []byte("Scheduler.Check()"), enum.ActorSystem)
+
+	start, end := Period(s.conf.Frequency, time.Now())
+	if _, err = s.store.RetrieveScheduledReport(ctx, s.conf.Frequency, start); err == nil {
+		return nil
+	} else if !errors.Is(err, dberr.ErrNotFound) {
+		return err
+	}
+
+	var report *Report
+	if report, err = Generate(ctx, s.store, Params{
+		Name:        Name(s.conf.Frequency, start),
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Sections:    s.conf.Sections,
+	}); err != nil {
+		return err
+	}
+
+	record := &models.Report{
+		Name:        report.Name,
+		Schedule:    sql.NullString{Valid: true, String: s.conf.Frequency},
+		PeriodStart: report.PeriodStart,
+		PeriodEnd:   report.PeriodEnd,
+		CreatedBy:   CreatedByScheduler,
+	}
+
+	if record.Data, err = json.Marshal(report); err != nil {
+		return err
+	}
+
+	if err = s.store.CreateReport(ctx, record, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Scheduler.Check()"},
+	}); err != nil {
+		// Another node sharing the database generated the report first.
+		if errors.Is(err, dberr.ErrAlreadyExists) {
+			return nil
+		}
+		return err
+	}
+
+	log.Info().
+		Str("report_id", record.ID.String()).
+		Str("name", report.Name).
+		Int("transfers", report.Transfers).
+		Msg("scheduled report generated")
+
+	s.send(report)
+	return nil
+}
+
+// Emails the report to each of the recipients; errors are logged but not returned
+// since the report has already been stored and can be downloaded.
+func (s *Scheduler) send(report *Report) {
+	if len(s.conf.Recipients) == 0 {
+		return
+	}
+
+	contentType, err := ContentType(s.conf.Format)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not render scheduled report")
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	if err = report.Render(buf, s.conf.Format); err != nil {
+		log.Warn().Err(err).Msg("could not render scheduled report")
+		return
+	}
+
+	data := emails.ReportEmailData{
+		ReportName:   report.Name,
+		PeriodStart:  report.PeriodStart,
+		PeriodEnd:    report.PeriodEnd,
+		Transfers:    report.Transfers,
+		Attachment:   report.Filename(s.conf.Format),
+		ReportURL:    s.web.ReportsURL(),
+		SupportEmail: s.support,
+	}
+
+	for _, recipient := range s.conf.Recipients {
+		var email *emails.Email
+		if email, err = emails.NewReportEmail(recipient, data); err != nil {
+			log.Warn().Err(err).Str("recipient", recipient).Msg("could not create report email")
+			continue
+		}
+
+		email.Attach(data.Attachment, contentType, buf.Bytes())
+		if err = email.Send(); err != nil {
+			log.Warn().Err(err).Str("recipient", recipient).Msg("could not send report email")
+		}
+	}
+}
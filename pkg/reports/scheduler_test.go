@@ -0,0 +1,147 @@
+package reports_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/reports"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	store "github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+func TestStartStop(t *testing.T) {
+	db := mockSchedulerStore(t)
+
+	db.OnRetrieveScheduledReport = func(context.Context, string, time.Time) (*models.Report, error) {
+		return &models.Report{}, nil
+	}
+
+	conf := config.Config{
+		Reports: config.ReportsConfig{Enabled: true, Interval: time.Hour, Frequency: config.ReportDaily, Format: config.ReportCSV},
+	}
+
+	svc, err := reports.New(conf, db)
+	require.NoError(t, err, "could not create reports scheduler")
+
+	require.ErrorIs(t, svc.Stop(), reports.ErrReportsNotRunning)
+	require.NoError(t, svc.Run(), "could not run reports scheduler")
+	require.ErrorIs(t, svc.Run(), reports.ErrReportsAlreadyRunning)
+	require.NoError(t, svc.Stop(), "could not stop reports scheduler")
+
+	db.AssertCalls(t, "RetrieveScheduledReport", 1)
+}
+
+func TestDisabled(t *testing.T) {
+	svc, err := reports.New(config.Config{}, nil)
+	require.NoError(t, err, "could not create reports scheduler")
+	require.NoError(t, svc.Run(), "expected no error running disabled scheduler")
+	require.NoError(t, svc.Stop(), "expected no error stopping disabled scheduler")
+}
+
+func TestNewInvalidSections(t *testing.T) {
+	conf := config.Config{
+		Reports: config.ReportsConfig{Enabled: true, Interval: time.Hour, Frequency: config.ReportDaily, Format: config.ReportCSV, Sections: []string{"foo"}},
+	}
+
+	_, err := reports.New(conf, nil)
+	require.ErrorIs(t, err, reports.ErrUnknownSection)
+}
+
+func TestCheck(t *testing.T) {
+	conf := config.Config{
+		Reports: config.ReportsConfig{Enabled: true, Interval: time.Hour, Frequency: config.ReportMonthly, Format: config.ReportHTML, Sections: []string{reports.SectionAsset}},
+	}
+	start, end := reports.Period(config.ReportMonthly, time.Now())
+
+	t.Run("Generate", func(t *testing.T) {
+		db := mockSchedulerStore(t)
+
+		var created *models.Report
+		db.OnCreateReport = func(ctx context.Context, report *models.Report, log *models.ComplianceAuditLog) error {
+			actorType, ok := audit.ActorType(ctx)
+			require.True(t, ok, "expected actor type in context")
+			require.Equal(t, enum.ActorSystem, actorType, "expected system actor for audit logs")
+			require.True(t, log.ChangeNotes.Valid, "expected change notes for audit logs")
+			created = report
+			return nil
+		}
+
+		svc, _ := reports.New(conf, db)
+		require.NoError(t, svc.Check(), "could not check scheduled reports")
+
+		require.NotNil(t, created, "expected a report to be created")
+		require.Equal(t, reports.Name(config.ReportMonthly, start), created.Name)
+		require.Equal(t, config.ReportMonthly, created.Schedule.String)
+		require.True(t, start.Equal(created.PeriodStart))
+		require.True(t, end.Equal(created.PeriodEnd))
+		require.Equal(t, reports.CreatedByScheduler, created.CreatedBy)
+
+		report := &reports.Report{}
+		require.NoError(t, json.Unmarshal(created.Data, report), "expected the report statistics as json")
+		require.Equal(t, []string{reports.SectionAsset}, report.Sections)
+		db.AssertCalls(t, "IterateTransactions", 2)
+	})
+
+	t.Run("AlreadyGenerated", func(t *testing.T) {
+		db := mockSchedulerStore(t)
+
+		db.OnRetrieveScheduledReport = func(_ context.Context, schedule string, periodStart time.Time) (*models.Report, error) {
+			require.Equal(t, config.ReportMonthly, schedule)
+			require.True(t, start.Equal(periodStart))
+			return &models.Report{}, nil
+		}
+
+		svc, _ := reports.New(conf, db)
+		require.NoError(t, svc.Check(), "could not check scheduled reports")
+		db.AssertCalls(t, "IterateTransactions", 0)
+		db.AssertCalls(t, "CreateReport", 0)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		db := mockSchedulerStore(t)
+
+		db.OnCreateReport = func(context.Context, *models.Report, *models.ComplianceAuditLog) error {
+			return dberr.ErrAlreadyExists
+		}
+
+		svc, _ := reports.New(conf, db)
+		require.NoError(t, svc.Check(), "expected no error if the report was generated by another node")
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		db := mockSchedulerStore(t)
+
+		db.OnRetrieveScheduledReport = func(context.Context, string, time.Time) (*models.Report, error) {
+			return nil, errors.New("database is locked")
+		}
+
+		svc, _ := reports.New(conf, db)
+		require.EqualError(t, svc.Check(), "database is locked")
+		db.AssertCalls(t, "CreateReport", 0)
+	})
+}
+
+// Returns a mock store where the report of the period has not been generated yet.
+func mockSchedulerStore(t *testing.T) *store.Store {
+	db, err := store.Open(nil)
+	require.NoError(t, err, "could not open mock store")
+
+	db.OnRetrieveScheduledReport = func(context.Context, string, time.Time) (*models.Report, error) {
+		return nil, dberr.ErrNotFound
+	}
+	db.OnIterateTransactions = func(context.Context, *models.TransactionFilter) (models.TransactionIterator, error) {
+		return store.NewTransactionIterator(), nil
+	}
+	db.OnCreateReport = func(context.Context, *models.Report, *models.ComplianceAuditLog) error {
+		return nil
+	}
+	return db
+}
@@ -0,0 +1,8 @@
+package retention
+
+import "errors"
+
+var (
+	ErrRetentionAlreadyRunning = errors.New("retention service is already running")
+	ErrRetentionNotRunning     = errors.New("retention service is not running")
+)
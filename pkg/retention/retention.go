@@ -0,0 +1,173 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Enforcer is a background routine that evaluates the configured data retention
+// policies at a specified interval. Expired transactions are first archived and then
+// purged or crypto-shredded once the archives grace period has passed; expired
+// secure envelopes are crypto-shredded and expired sunrise records, reset password
+// links, and customer accounts are deleted. The compliance audit trail is always
+// preserved and transactions under legal hold are skipped by the store.
+type Enforcer struct {
+	sync.Mutex
+	conf  config.RetentionConfig
+	store store.RetentionStore
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// Creates a new data retention enforcement service but does not run it.
+func New(conf config.RetentionConfig, store store.RetentionStore) (*Enforcer, error) {
+	// Only return a retention stub if not enabled
+	if !conf.Enabled {
+		return &Enforcer{conf: conf}, nil
+	}
+
+	return &Enforcer{
+		conf:  conf,
+		store: store,
+	}, nil
+}
+
+// Run the data retention enforcement service.
+func (e *Enforcer) Run() error {
+	// Do not run the service if retention is not enabled.
+	if !e.conf.Enabled {
+		return nil
+	}
+
+	// Lock the retention routine to initialize and start it.
+	e.Lock()
+	defer e.Unlock()
+
+	if e.stop != nil {
+		return ErrRetentionAlreadyRunning
+	}
+
+	e.stop = make(chan struct{})
+	e.done = make(chan struct{})
+	go e.run()
+	return nil
+}
+
+func (e *Enforcer) run() {
+	ticker := time.NewTicker(e.conf.Interval)
+	log.Info().Dur("retention_interval", e.conf.Interval).Msg("data retention service running")
+
+	// Enforce the retention policies at startup. Errors are not fatal since the
+	// policies will be evaluated again on the next interval.
+	if err := e.Enforce(); err != nil {
+		log.Warn().Err(err).Msg("could not enforce data retention policies")
+	}
+
+retentionloop:
+	for {
+		select {
+		case <-e.stop:
+			break retentionloop
+		case <-ticker.C:
+			if err := e.Enforce(); err != nil {
+				log.Warn().Err(err).Msg("could not enforce data retention policies")
+			}
+		}
+	}
+
+	ticker.Stop()
+	close(e.done)
+	log.Info().Msg("data retention service stopped")
+}
+
+// Stop the data retention enforcement service, blocking until the service is shutdown.
+func (e *Enforcer) Stop() error {
+	// Do not stop the retention service if it is not enabled
+	if !e.conf.Enabled {
+		return nil
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	if e.stop == nil {
+		return ErrRetentionNotRunning
+	}
+
+	// Send the stop signal and wait for routine to stop.
+	close(e.stop)
+	<-e.done
+
+	e.stop = nil
+	e.done = nil
+	return nil
+}
+
+// Enforce evaluates each of the configured retention policies against the current
+// time. Each policy is applied in its own database transaction so that a failure to
+// apply one policy does not prevent the others from being applied; all errors are
+// joined and returned once every policy has been evaluated.
+func (e *Enforcer) Enforce() (err error) {
+	log.Debug().Msg("starting data retention policy enforcement")
+
+	// Add actor information to the context for the audit log
+	ctx := audit.WithActor(context.Background(), []byte("Enforcer.Enforce()"), enum.ActorSystem)
+	auditLog := &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Enforcer.Enforce()"},
+	}
+
+	now := time.Now()
+	counts := make(map[string]int64, 7)
+
+	apply := func(policy string, duration time.Duration, fn func(context.Context, time.Time, *models.ComplianceAuditLog) (int64, error)) {
+		if duration <= 0 {
+			return
+		}
+
+		n, perr := fn(ctx, now.Add(-duration), auditLog)
+		if perr != nil {
+			log.Warn().Err(perr).Str("policy", policy).Msg("could not apply data retention policy")
+			err = errors.Join(err, perr)
+			return
+		}
+		counts[policy] = n
+	}
+
+	// Expired transactions are archived first and are only purged or shredded once
+	// the archives duration has passed since they were archived; transactions that
+	// were unarchived in the meantime are not purged or shredded.
+	apply("archived", e.conf.Transactions, e.store.ArchiveExpiredTransactions)
+	if e.conf.Transactions > 0 && e.conf.Archives > 0 {
+		if e.conf.CryptoShred {
+			apply("shredded", e.conf.Archives, e.store.ShredArchivedTransactions)
+		} else {
+			apply("purged", e.conf.Archives, e.store.PurgeArchivedTransactions)
+		}
+	}
+
+	apply("envelopes", e.conf.SecureEnvelopes, e.store.ShredExpiredSecureEnvelopes)
+	apply("sunrise", e.conf.Sunrise, e.store.PurgeExpiredSunrise)
+	apply("accounts", e.conf.Accounts, e.store.PurgeExpiredAccounts)
+	apply("reset_links", e.conf.ResetLinks, func(ctx context.Context, before time.Time, _ *models.ComplianceAuditLog) (int64, error) {
+		return e.store.PurgeExpiredResetPasswordLinks(ctx, before)
+	})
+
+	ev := log.Info()
+	for policy, n := range counts {
+		ev = ev.Int64(policy, n)
+	}
+	ev.Msg("data retention policy enforcement complete")
+
+	return err
+}
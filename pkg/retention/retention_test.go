@@ -0,0 +1,179 @@
+package retention_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/retention"
+	store "github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+func TestStartStop(t *testing.T) {
+	db := mockStore(t, nil)
+	conf := config.RetentionConfig{
+		Enabled:      true,
+		Interval:     48 * time.Hour,
+		Transactions: 43800 * time.Hour,
+	}
+
+	svc, err := retention.New(conf, db)
+	require.NoError(t, err, "could not create retention service")
+
+	require.ErrorIs(t, svc.Stop(), retention.ErrRetentionNotRunning)
+	require.NoError(t, svc.Run(), "could not run retention service")
+	require.ErrorIs(t, svc.Run(), retention.ErrRetentionAlreadyRunning)
+	require.NoError(t, svc.Stop(), "could not stop retention service")
+
+	db.AssertCalls(t, "ArchiveExpiredTransactions", 1)
+}
+
+func TestDisabled(t *testing.T) {
+	svc, err := retention.New(config.RetentionConfig{Enabled: false}, nil)
+	require.NoError(t, err, "could not create retention service")
+	require.NoError(t, svc.Run(), "expected no error running disabled service")
+	require.NoError(t, svc.Stop(), "expected no error stopping disabled service")
+}
+
+func TestEnforce(t *testing.T) {
+	t.Run("CryptoShred", func(t *testing.T) {
+		cutoffs := make(map[string]time.Time)
+		db := mockStore(t, cutoffs)
+		conf := config.RetentionConfig{
+			Enabled:         true,
+			Interval:        24 * time.Hour,
+			CryptoShred:     true,
+			Transactions:    43800 * time.Hour,
+			Archives:        720 * time.Hour,
+			SecureEnvelopes: 8760 * time.Hour,
+			Sunrise:         2160 * time.Hour,
+			ResetLinks:      24 * time.Hour,
+		}
+
+		svc, _ := retention.New(conf, db)
+		start := time.Now()
+		require.NoError(t, svc.Enforce(), "could not enforce retention policies")
+
+		db.AssertCalls(t, "ArchiveExpiredTransactions", 1)
+		db.AssertCalls(t, "ShredArchivedTransactions", 1)
+		db.AssertCalls(t, "PurgeArchivedTransactions", 0)
+		db.AssertCalls(t, "ShredExpiredSecureEnvelopes", 1)
+		db.AssertCalls(t, "PurgeExpiredSunrise", 1)
+		db.AssertCalls(t, "PurgeExpiredResetPasswordLinks", 1)
+		db.AssertCalls(t, "PurgeExpiredAccounts", 0)
+
+		requireCutoff(t, start, conf.Transactions, cutoffs["ArchiveExpiredTransactions"])
+		requireCutoff(t, start, conf.Archives, cutoffs["ShredArchivedTransactions"])
+		requireCutoff(t, start, conf.SecureEnvelopes, cutoffs["ShredExpiredSecureEnvelopes"])
+		requireCutoff(t, start, conf.Sunrise, cutoffs["PurgeExpiredSunrise"])
+		requireCutoff(t, start, conf.ResetLinks, cutoffs["PurgeExpiredResetPasswordLinks"])
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		db := mockStore(t, nil)
+		conf := config.RetentionConfig{
+			Enabled:      true,
+			Interval:     24 * time.Hour,
+			CryptoShred:  false,
+			Transactions: 43800 * time.Hour,
+			Archives:     720 * time.Hour,
+			Accounts:     87600 * time.Hour,
+		}
+
+		svc, _ := retention.New(conf, db)
+		require.NoError(t, svc.Enforce(), "could not enforce retention policies")
+
+		db.AssertCalls(t, "ArchiveExpiredTransactions", 1)
+		db.AssertCalls(t, "ShredArchivedTransactions", 0)
+		db.AssertCalls(t, "PurgeArchivedTransactions", 1)
+		db.AssertCalls(t, "ShredExpiredSecureEnvelopes", 0)
+		db.AssertCalls(t, "PurgeExpiredSunrise", 0)
+		db.AssertCalls(t, "PurgeExpiredResetPasswordLinks", 0)
+		db.AssertCalls(t, "PurgeExpiredAccounts", 1)
+	})
+
+	t.Run("NoArchives", func(t *testing.T) {
+		db := mockStore(t, nil)
+		conf := config.RetentionConfig{
+			Enabled:      true,
+			Interval:     24 * time.Hour,
+			CryptoShred:  true,
+			Transactions: 43800 * time.Hour,
+		}
+
+		svc, _ := retention.New(conf, db)
+		require.NoError(t, svc.Enforce(), "could not enforce retention policies")
+
+		db.AssertCalls(t, "ArchiveExpiredTransactions", 1)
+		db.AssertCalls(t, "ShredArchivedTransactions", 0)
+		db.AssertCalls(t, "PurgeArchivedTransactions", 0)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		db := mockStore(t, nil)
+		db.OnArchiveExpiredTransactions = func(context.Context, time.Time, *models.ComplianceAuditLog) (int64, error) {
+			return 0, errors.New("database is locked")
+		}
+
+		conf := config.RetentionConfig{
+			Enabled:      true,
+			Interval:     24 * time.Hour,
+			Transactions: 43800 * time.Hour,
+			Sunrise:      2160 * time.Hour,
+		}
+
+		svc, _ := retention.New(conf, db)
+		require.EqualError(t, svc.Enforce(), "database is locked")
+
+		// Other policies are still applied when one of them fails
+		db.AssertCalls(t, "PurgeExpiredSunrise", 1)
+	})
+}
+
+func mockStore(t *testing.T, cutoffs map[string]time.Time) *store.Store {
+	db, err := store.Open(nil)
+	require.NoError(t, err, "could not open mock store")
+
+	policy := func(name string) func(context.Context, time.Time, *models.ComplianceAuditLog) (int64, error) {
+		return func(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+			actorID, ok := audit.ActorID(ctx)
+			require.True(t, ok, "expected actor id in context")
+			require.NotEmpty(t, actorID, "expected actor id for audit logs")
+
+			actorType, ok := audit.ActorType(ctx)
+			require.True(t, ok, "expected actor type in context")
+			require.Equal(t, enum.ActorSystem, actorType, "expected system actor for audit logs")
+			require.True(t, log.ChangeNotes.Valid, "expected change notes for audit logs")
+
+			if cutoffs != nil {
+				cutoffs[name] = before
+			}
+			return 1, nil
+		}
+	}
+
+	db.OnArchiveExpiredTransactions = policy("ArchiveExpiredTransactions")
+	db.OnPurgeArchivedTransactions = policy("PurgeArchivedTransactions")
+	db.OnShredArchivedTransactions = policy("ShredArchivedTransactions")
+	db.OnShredExpiredSecureEnvelopes = policy("ShredExpiredSecureEnvelopes")
+	db.OnPurgeExpiredSunrise = policy("PurgeExpiredSunrise")
+	db.OnPurgeExpiredAccounts = policy("PurgeExpiredAccounts")
+	db.OnPurgeExpiredResetPasswordLinks = func(_ context.Context, before time.Time) (int64, error) {
+		if cutoffs != nil {
+			cutoffs["PurgeExpiredResetPasswordLinks"] = before
+		}
+		return 1, nil
+	}
+	return db
+}
+
+func requireCutoff(t *testing.T, start time.Time, policy time.Duration, actual time.Time) {
+	expected := start.Add(-policy)
+	require.WithinDuration(t, expected, actual, time.Second, "unexpected cutoff for retention policy")
+}
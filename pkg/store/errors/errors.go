@@ -3,26 +3,40 @@ package errors
 import "errors"
 
 var (
-	ErrDSNParse           = errors.New("could not parse dsn")
-	ErrInvalidDSN         = errors.New("could not parse DSN, critical component missing")
-	ErrUnknownScheme      = errors.New("database scheme not handled by this package")
-	ErrPathRequired       = errors.New("a path is required for this database scheme")
-	ErrReadOnly           = errors.New("cannot perform operation in read-only mode")
-	ErrMissingAssociation = errors.New("associated record(s) not cached on model")
-	ErrMissingReference   = errors.New("missing id of foreign key reference")
-	ErrNotFound           = errors.New("record not found in database")
-	ErrAlreadyExists      = errors.New("record already exists in database")
-	ErrTooSoon            = errors.New("a previous record has not expired yet")
-	ErrNotImplemented     = errors.New("method not implemented for this storage backend")
-	ErrNoIDOnCreate       = errors.New("cannot create a resource with an established id")
-	ErrMissingID          = errors.New("missing id of resource")
-	ErrIDMismatch         = errors.New("id does not match id of prepared resource")
-	ErrNoEndpoint         = errors.New("cannot create travel address: no endpoint defined")
-	ErrAmbiguous          = errors.New("ambiguous query: more than one result returned")
-	ErrInternal           = errors.New("something critical went wrong, please try again later")
-	ErrDaybreakHasTxns    = errors.New("daybreak counterparty will not be deleted because it has transactions")
-	ErrMissingTimestamp   = errors.New("missing a timestamp for resource")
-	ErrNullString         = errors.New("cannot apply string method to a null value")
-	ErrMissingValue       = errors.New("missing a required record value")
-	ErrMissingActor       = errors.New("missing actor metadata")
+	ErrDSNParse            = errors.New("could not parse dsn")
+	ErrInvalidDSN          = errors.New("could not parse DSN, critical component missing")
+	ErrUnknownScheme       = errors.New("database scheme not handled by this package")
+	ErrPathRequired        = errors.New("a path is required for this database scheme")
+	ErrReadOnly            = errors.New("cannot perform operation in read-only mode")
+	ErrMissingAssociation  = errors.New("associated record(s) not cached on model")
+	ErrMissingReference    = errors.New("missing id of foreign key reference")
+	ErrNotFound            = errors.New("record not found in database")
+	ErrAlreadyExists       = errors.New("record already exists in database")
+	ErrTooSoon             = errors.New("a previous record has not expired yet")
+	ErrNotImplemented      = errors.New("method not implemented for this storage backend")
+	ErrNoIDOnCreate        = errors.New("cannot create a resource with an established id")
+	ErrMissingID           = errors.New("missing id of resource")
+	ErrIDMismatch          = errors.New("id does not match id of prepared resource")
+	ErrNoEndpoint          = errors.New("cannot create travel address: no endpoint defined")
+	ErrAmbiguous           = errors.New("ambiguous query: more than one result returned")
+	ErrInternal            = errors.New("something critical went wrong, please try again later")
+	ErrDaybreakHasTxns     = errors.New("daybreak counterparty will not be deleted because it has transactions")
+	ErrMissingTimestamp    = errors.New("missing a timestamp for resource")
+	ErrNullString          = errors.New("cannot apply string method to a null value")
+	ErrMissingValue        = errors.New("missing a required record value")
+	ErrMissingActor        = errors.New("missing actor metadata")
+	ErrLegalHold           = errors.New("resource is under legal hold")
+	ErrHoldReleased        = errors.New("legal hold has already been released")
+	ErrInvalidHoldResource = errors.New("legal holds can only be applied to transactions, accounts, or counterparties")
+	ErrBuiltinRole         = errors.New("built-in roles cannot be modified or deleted")
+	ErrRoleInUse           = errors.New("role is assigned to one or more users or pending invites")
+	ErrUnknownPermission   = errors.New("unknown permission")
+	ErrClientIDRevoked     = errors.New("client id belongs to a revoked api key")
+	ErrApprovalNotPending  = errors.New("approval has already been reviewed")
+	ErrApprovalNotClaimed  = errors.New("approval must be claimed before the action is executed")
+	ErrSelfApproval        = errors.New("actions cannot be reviewed by the actor that requested them")
+	ErrNotRequester        = errors.New("approvals can only be canceled by the actor that requested them")
+	ErrUnknownAssignee     = errors.New("transactions can only be assigned to existing users")
+	ErrInvalidTransition   = errors.New("invalid transaction status transition")
+	ErrTransactionFinal    = errors.New("completed or rejected transactions cannot be modified")
 )
@@ -0,0 +1,41 @@
+package mock
+
+import "github.com/trisacrypto/envoy/pkg/store/models"
+
+// TransactionIterator iterates over a fixed list of transactions so that callbacks
+// such as OnIterateTransactions can return an iterator in tests.
+type TransactionIterator struct {
+	transactions []*models.Transaction
+	index        int
+	Closed       bool
+}
+
+var _ models.TransactionIterator = &TransactionIterator{}
+
+func NewTransactionIterator(transactions ...*models.Transaction) *TransactionIterator {
+	return &TransactionIterator{transactions: transactions, index: -1}
+}
+
+func (i *TransactionIterator) Next() bool {
+	if i.index+1 >= len(i.transactions) {
+		return false
+	}
+	i.index++
+	return true
+}
+
+func (i *TransactionIterator) Transaction() *models.Transaction {
+	if i.index < 0 || i.index >= len(i.transactions) {
+		return nil
+	}
+	return i.transactions[i.index]
+}
+
+func (i *TransactionIterator) Err() error {
+	return nil
+}
+
+func (i *TransactionIterator) Close() error {
+	i.Closed = true
+	return nil
+}
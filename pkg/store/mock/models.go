@@ -139,6 +139,8 @@ func GetSampleAPIKey(includeNulls bool) (model *models.APIKey) {
 	if includeNulls {
 		model.Description = sql.NullString{String: "Description", Valid: true}
 		model.LastSeen = sql.NullTime{Time: timeNow, Valid: true}
+		model.Expires = sql.NullTime{Time: timeNow.AddDate(1, 0, 0), Valid: true}
+		model.AllowedNetworks = models.AllowedNetworks{"10.0.0.0/8"}
 	}
 
 	return model
@@ -236,6 +236,36 @@ func (p *PreparedTransaction) UpdateSunriseStatus(id uuid.UUID, status enum.Stat
 	return fn.(func(uuid.UUID, enum.Status, *models.ComplianceAuditLog) error)(id, status, log)
 }
 
+// Sets a callback for when "Enqueue()" is called on the mock PreparedTransaction.
+func (p *PreparedTransaction) OnEnqueue(fn func(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error) {
+	p.callbacks["Enqueue"] = fn
+}
+
+// Calls the callback previously set with "OnEnqueue()".
+func (p *PreparedTransaction) Enqueue(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error {
+	fn, err := p.check("Enqueue")
+	if err != nil {
+		return err
+	}
+
+	return fn.(func(*models.QueuedTransfer, *models.ComplianceAuditLog) error)(transfer, log)
+}
+
+// Sets a callback for when "UpdateQueuedTransfer()" is called on the mock PreparedTransaction.
+func (p *PreparedTransaction) OnUpdateQueuedTransfer(fn func(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error) {
+	p.callbacks["UpdateQueuedTransfer"] = fn
+}
+
+// Calls the callback previously set with "OnUpdateQueuedTransfer()".
+func (p *PreparedTransaction) UpdateQueuedTransfer(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error {
+	fn, err := p.check("UpdateQueuedTransfer")
+	if err != nil {
+		return err
+	}
+
+	return fn.(func(*models.QueuedTransfer, *models.ComplianceAuditLog) error)(transfer, log)
+}
+
 // Sets a callback for when "Rollback()" is called on the mock PreparedTransaction.
 func (p *PreparedTransaction) OnRollback(fn func() error) {
 	p.callbacks["Rollback"] = fn
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/trisacrypto/envoy/pkg/store/dsn"
 	"github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
 	"github.com/trisacrypto/envoy/pkg/store/txn"
 
 	"github.com/google/uuid"
@@ -20,16 +22,19 @@ import (
 )
 
 // Store implements the store.Store interface with callback functions that the tester
-// can specify to simulate a specific behavior. The Store is not thread-safe and one
-// mock store should be used per test. To set a callback for `Store.Function()`, set
-// the `Store.OnFunction` stub to your desired callback function.
+// can specify to simulate a specific behavior. Call counts may be recorded from the
+// background routines of the server, but callbacks should not be modified while the
+// server is in use and one mock store should be used per test. To set a callback for
+// `Store.Function()`, set the `Store.OnFunction` stub to your desired callback function.
 type Store struct {
+	sync.Mutex
 	calls    map[string]int
 	readonly bool
 
 	OnClose                          func() error
 	OnBegin                          func(context.Context, *sql.TxOptions) (txn.Txn, error)
 	OnListTransactions               func(ctx context.Context, in *models.TransactionPageInfo) (*models.TransactionPage, error)
+	OnIterateTransactions            func(ctx context.Context, in *models.TransactionFilter) (models.TransactionIterator, error)
 	OnCreateTransaction              func(ctx context.Context, in *models.Transaction, log *models.ComplianceAuditLog) error
 	OnRetrieveTransaction            func(ctx context.Context, id uuid.UUID) (*models.Transaction, error)
 	OnUpdateTransaction              func(ctx context.Context, in *models.Transaction, log *models.ComplianceAuditLog) error
@@ -39,6 +44,16 @@ type Store struct {
 	OnCountTransactions              func(ctx context.Context) (*models.TransactionCounts, error)
 	OnPrepareTransaction             func(ctx context.Context, id uuid.UUID, log *models.ComplianceAuditLog) (models.PreparedTransaction, error)
 	OnTransactionState               func(ctx context.Context, id uuid.UUID) (bool, enum.Status, error)
+	OnListStatusHistory              func(ctx context.Context, txID uuid.UUID) ([]*models.StatusTransition, error)
+	OnListTransactionNotes           func(ctx context.Context, txID uuid.UUID) ([]*models.TransactionNote, error)
+	OnCreateTransactionNote          func(ctx context.Context, note *models.TransactionNote, auditLog *models.ComplianceAuditLog) error
+	OnAssignTransaction              func(ctx context.Context, txID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error
+	OnListTransactionTags            func(ctx context.Context) ([]string, error)
+	OnSetTransactionTags             func(ctx context.Context, txID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error
+	OnListReplyDeadlines             func(ctx context.Context, dueBefore time.Time) ([]*models.Transaction, error)
+	OnMarkReplyReminded              func(ctx context.Context, txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	OnMarkReplyExpired               func(ctx context.Context, txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	OnListTransactionReviewers       func(ctx context.Context) ([]*models.User, error)
 	OnListSecureEnvelopes            func(ctx context.Context, txID uuid.UUID, page *models.PageInfo) (*models.SecureEnvelopePage, error)
 	OnCreateSecureEnvelope           func(ctx context.Context, in *models.SecureEnvelope, log *models.ComplianceAuditLog) error
 	OnRetrieveSecureEnvelope         func(ctx context.Context, txID uuid.UUID, envID ulid.ULID) (*models.SecureEnvelope, error)
@@ -65,6 +80,7 @@ type Store struct {
 	OnRetrieveCounterparty           func(ctx context.Context, counterpartyID ulid.ULID) (*models.Counterparty, error)
 	OnLookupCounterparty             func(ctx context.Context, field, value string) (*models.Counterparty, error)
 	OnUpdateCounterparty             func(ctx context.Context, in *models.Counterparty, log *models.ComplianceAuditLog) error
+	OnUpdateCounterpartyPending      func(ctx context.Context, counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error
 	OnDeleteCounterparty             func(ctx context.Context, counterpartyID ulid.ULID, log *models.ComplianceAuditLog) error
 	OnListContacts                   func(ctx context.Context, counterparty any, page *models.PageInfo) (*models.ContactsPage, error)
 	OnCreateContact                  func(ctx context.Context, in *models.Contact, log *models.ComplianceAuditLog) error
@@ -72,6 +88,31 @@ type Store struct {
 	OnUpdateContact                  func(ctx context.Context, in *models.Contact, log *models.ComplianceAuditLog) error
 	OnDeleteContact                  func(ctx context.Context, contactID, counterparty any, log *models.ComplianceAuditLog) error
 	OnUseTravelAddressFactory        func(models.TravelAddressFactory)
+	OnUseFieldEncryption             func(*pii.Cipher) error
+	OnRetrieveDataKey                func(ctx context.Context) (*models.DataKey, error)
+	OnCreateDataKey                  func(ctx context.Context, key *models.DataKey) error
+	OnListSigningKeys                func(ctx context.Context) ([]*models.SigningKey, error)
+	OnCreateSigningKey               func(ctx context.Context, key *models.SigningKey) error
+	OnDeleteExpiredSigningKeys       func(ctx context.Context) (int64, error)
+	OnRetrieveIdempotencyKey         func(ctx context.Context, actorID []byte, key string) (*models.IdempotencyKey, error)
+	OnCreateIdempotencyKey           func(ctx context.Context, key *models.IdempotencyKey) error
+	OnListBatches                    func(ctx context.Context) ([]*models.Batch, error)
+	OnCreateBatch                    func(ctx context.Context, batch *models.Batch, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveBatch                  func(ctx context.Context, id ulid.ULID) (*models.Batch, error)
+	OnUpdateBatch                    func(ctx context.Context, batch *models.Batch, auditLog *models.ComplianceAuditLog) error
+	OnListBatchItems                 func(ctx context.Context, batchID ulid.ULID) ([]*models.BatchItem, error)
+	OnUpdateBatchItem                func(ctx context.Context, item *models.BatchItem) error
+	OnListReports                    func(ctx context.Context) ([]*models.Report, error)
+	OnCreateReport                   func(ctx context.Context, report *models.Report, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveReport                 func(ctx context.Context, id ulid.ULID) (*models.Report, error)
+	OnRetrieveScheduledReport        func(ctx context.Context, schedule string, periodStart time.Time) (*models.Report, error)
+	OnDeleteReport                   func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	OnListEnvelopeTimings            func(ctx context.Context, after, before time.Time) ([]*models.EnvelopeTiming, error)
+	OnListThresholdRules             func(ctx context.Context) ([]*models.ThresholdRule, error)
+	OnCreateThresholdRule            func(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveThresholdRule          func(ctx context.Context, ruleID ulid.ULID) (*models.ThresholdRule, error)
+	OnUpdateThresholdRule            func(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) error
+	OnDeleteThresholdRule            func(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error
 	OnListSunrise                    func(ctx context.Context, page *models.PageInfo) (*models.SunrisePage, error)
 	OnCreateSunrise                  func(ctx context.Context, msg *models.Sunrise, log *models.ComplianceAuditLog) error
 	OnRetrieveSunrise                func(ctx context.Context, id ulid.ULID) (*models.Sunrise, error)
@@ -85,22 +126,84 @@ type Store struct {
 	OnUpdateUser                     func(ctx context.Context, in *models.User, log *models.ComplianceAuditLog) error
 	OnSetUserPassword                func(ctx context.Context, userID ulid.ULID, password string) (err error)
 	OnSetUserLastLogin               func(ctx context.Context, userID ulid.ULID, lastLogin time.Time) (err error)
+	OnRecordUserLoginFailure         func(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) error
+	OnResetUserLoginFailures         func(ctx context.Context, userID ulid.ULID) error
+	OnUnlockUser                     func(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	OnListUserSessions               func(ctx context.Context, userID ulid.ULID) ([]*models.UserSession, error)
+	OnCreateUserSession              func(ctx context.Context, session *models.UserSession) error
+	OnRefreshUserSession             func(ctx context.Context, userID, sessionID ulid.ULID, expires time.Time) error
+	OnRevokeUserSession              func(ctx context.Context, userID, sessionID ulid.ULID) error
+	OnRevokeUserSessions             func(ctx context.Context, userID, keep ulid.ULID) error
 	OnDeleteUser                     func(ctx context.Context, userID ulid.ULID, log *models.ComplianceAuditLog) error
+	OnRetrieveRevokedUser            func(ctx context.Context, userID ulid.ULID) (*models.RevokedUser, error)
+	OnListRoles                      func(ctx context.Context) ([]*models.Role, error)
 	OnLookupRole                     func(ctx context.Context, role string) (*models.Role, error)
+	OnSetUserMFASecret               func(ctx context.Context, userID ulid.ULID, secret string) error
+	OnEnableUserMFA                  func(ctx context.Context, userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error
+	OnSetUserRecoveryCodes           func(ctx context.Context, userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error
+	OnUseUserRecoveryCode            func(ctx context.Context, userID ulid.ULID, recoveryCode string) error
+	OnSetUserMFAStep                 func(ctx context.Context, userID ulid.ULID, step int64) error
+	OnRecordUserMFAFailure           func(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout time.Duration) error
+	OnResetUserMFA                   func(ctx context.Context, userID ulid.ULID, log *models.ComplianceAuditLog) error
+	OnSetRoleMFARequired             func(ctx context.Context, roleID int64, required bool, log *models.ComplianceAuditLog) error
+	OnCreateRole                     func(ctx context.Context, in *models.Role, log *models.ComplianceAuditLog) error
+	OnRetrieveRole                   func(ctx context.Context, roleID int64) (*models.Role, error)
+	OnUpdateRole                     func(ctx context.Context, in *models.Role, log *models.ComplianceAuditLog) error
+	OnDeleteRole                     func(ctx context.Context, roleID int64, log *models.ComplianceAuditLog) error
+	OnListPermissions                func(ctx context.Context) ([]*models.Permission, error)
 	OnListAPIKeys                    func(ctx context.Context, in *models.PageInfo) (*models.APIKeyPage, error)
 	OnCreateAPIKey                   func(ctx context.Context, in *models.APIKey, log *models.ComplianceAuditLog) error
 	OnRetrieveAPIKey                 func(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error)
 	OnUpdateAPIKey                   func(ctx context.Context, in *models.APIKey, log *models.ComplianceAuditLog) error
 	OnSetAPIKeyLastSeen              func(ctx context.Context, keyID ulid.ULID, lastSeen time.Time) error
+	OnRotateAPIKey                   func(ctx context.Context, key *models.APIKey, auditLog *models.ComplianceAuditLog) error
 	OnDeleteAPIKey                   func(ctx context.Context, keyID ulid.ULID, log *models.ComplianceAuditLog) error
+	OnRetrieveRevokedAPIKey          func(ctx context.Context, keyID ulid.ULID) (*models.RevokedAPIKey, error)
+	OnRecordAPIKeyUsage              func(ctx context.Context, keyID ulid.ULID, ip string, ts time.Time) error
+	OnListAPIKeyUsage                func(ctx context.Context, keyID ulid.ULID, since time.Time) ([]*models.APIKeyUsage, error)
 	OnListResetPasswordLinks         func(ctx context.Context, page *models.PageInfo) (*models.ResetPasswordLinkPage, error)
 	OnCreateResetPasswordLink        func(ctx context.Context, link *models.ResetPasswordLink) error
 	OnRetrieveResetPasswordLink      func(ctx context.Context, linkID ulid.ULID) (*models.ResetPasswordLink, error)
 	OnUpdateResetPasswordLink        func(ctx context.Context, link *models.ResetPasswordLink) error
 	OnDeleteResetPasswordLink        func(ctx context.Context, linkID ulid.ULID) (err error)
+	OnListUserInvites                func(ctx context.Context) ([]*models.UserInvite, error)
+	OnCreateUserInvite               func(ctx context.Context, invite *models.UserInvite, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveUserInvite             func(ctx context.Context, inviteID ulid.ULID) (*models.UserInvite, error)
+	OnUpdateUserInvite               func(ctx context.Context, invite *models.UserInvite) error
+	OnDeleteUserInvite               func(ctx context.Context, inviteID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	OnAcceptUserInvite               func(ctx context.Context, inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) error
 	OnListComplianceAuditLogs        func(ctx context.Context, page *models.ComplianceAuditLogPageInfo) (*models.ComplianceAuditLogPage, error)
 	OnCreateComplianceAuditLog       func(ctx context.Context, log *models.ComplianceAuditLog) error
 	OnRetrieveComplianceAuditLog     func(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error)
+	OnArchiveExpiredTransactions     func(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeArchivedTransactions      func(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnShredArchivedTransactions      func(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnShredExpiredSecureEnvelopes    func(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeExpiredSunrise            func(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeExpiredAccounts           func(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeExpiredResetPasswordLinks func(ctx context.Context, before time.Time) (int64, error)
+	OnListLegalHolds                 func(ctx context.Context, page *models.LegalHoldPageInfo) (*models.LegalHoldPage, error)
+	OnCreateLegalHold                func(ctx context.Context, hold *models.LegalHold, log *models.ComplianceAuditLog) error
+	OnRetrieveLegalHold              func(ctx context.Context, id ulid.ULID) (*models.LegalHold, error)
+	OnUpdateLegalHold                func(ctx context.Context, hold *models.LegalHold, log *models.ComplianceAuditLog) error
+	OnReleaseLegalHold               func(ctx context.Context, id ulid.ULID, releasedBy string, log *models.ComplianceAuditLog) error
+	OnListApprovalRules              func(ctx context.Context) ([]*models.ApprovalRule, error)
+	OnCreateApprovalRule             func(ctx context.Context, rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveApprovalRule           func(ctx context.Context, ruleID ulid.ULID) (*models.ApprovalRule, error)
+	OnUpdateApprovalRule             func(ctx context.Context, rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error
+	OnDeleteApprovalRule             func(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	OnListApprovals                  func(ctx context.Context, page *models.ApprovalPageInfo) (*models.ApprovalPage, error)
+	OnCreateApproval                 func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveApproval               func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error)
+	OnClaimApproval                  func(ctx context.Context, approvalID ulid.ULID) error
+	OnReleaseApproval                func(ctx context.Context, approvalID ulid.ULID) error
+	OnReviewApproval                 func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error
+	OnListApprovalReviewers          func(ctx context.Context) ([]*models.User, error)
+	OnListQueuedTransfers            func(ctx context.Context) ([]*models.QueuedTransfer, error)
+	OnListDueTransfers               func(ctx context.Context, before time.Time) ([]*models.QueuedTransfer, error)
+	OnCreateQueuedTransfer           func(ctx context.Context, transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error
+	OnRetrieveQueuedTransfer         func(ctx context.Context, id ulid.ULID) (*models.QueuedTransfer, error)
+	OnUpdateQueuedTransfer           func(ctx context.Context, transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error
 }
 
 // Open a new mock store. Generally, the nil uri can be used to create the mock;
@@ -127,8 +230,9 @@ func Open(uri *dsn.DSN) (*Store, error) {
 // Reset all the calls and callbacks in the store.
 func (s *Store) Reset() {
 	// reset the call counts
-	s.calls = nil
+	s.Lock()
 	s.calls = make(map[string]int)
+	s.Unlock()
 
 	// reset the callbacks using reflection
 	v := reflect.ValueOf(s)
@@ -145,7 +249,17 @@ func (s *Store) Reset() {
 
 // Assert that the expected number of calls were made to the given method.
 func (s *Store) AssertCalls(t testing.TB, method string, expected int) {
-	require.Equal(t, expected, s.calls[method], "expected %d calls to %s, got %d", expected, method, s.calls[method])
+	s.Lock()
+	actual := s.calls[method]
+	s.Unlock()
+	require.Equal(t, expected, actual, "expected %d calls to %s, got %d", expected, method, actual)
+}
+
+// Records a call to the given method.
+func (s *Store) called(method string) {
+	s.Lock()
+	s.calls[method]++
+	s.Unlock()
 }
 
 //===========================================================================
@@ -155,7 +269,7 @@ func (s *Store) AssertCalls(t testing.TB, method string, expected int) {
 // If present, calls the callback previously set for "Close()" (set the callback
 // with "OnClose()"), otherwise returns `nil` indicating success.
 func (s *Store) Close() error {
-	s.calls["Close"]++
+	s.called("Close")
 
 	// perform callback if there is one
 	if s.OnClose != nil {
@@ -169,7 +283,7 @@ func (s *Store) Close() error {
 // If present, calls the callback previously set for "Begin()" (set the callback
 // with "OnBegin()"), otherwise returns a `Txn` mock.
 func (s *Store) Begin(ctx context.Context, opts *sql.TxOptions) (txn.Txn, error) {
-	s.calls["Begin"]++
+	s.called("Begin")
 
 	// perform callback if there is one
 	if s.OnBegin != nil {
@@ -196,16 +310,25 @@ func (s *Store) Begin(ctx context.Context, opts *sql.TxOptions) (txn.Txn, error)
 
 // Calls the callback previously set with `s.OnListTransactions = ...`
 func (s *Store) ListTransactions(ctx context.Context, in *models.TransactionPageInfo) (*models.TransactionPage, error) {
-	s.calls["ListTransactions"]++
+	s.called("ListTransactions")
 	if s.OnListTransactions != nil {
 		return s.OnListTransactions(ctx, in)
 	}
 	panic("ListTransactions callback not set")
 }
 
+// Calls the callback previously set with `s.OnIterateTransactions = ...`
+func (s *Store) IterateTransactions(ctx context.Context, in *models.TransactionFilter) (models.TransactionIterator, error) {
+	s.called("IterateTransactions")
+	if s.OnIterateTransactions != nil {
+		return s.OnIterateTransactions(ctx, in)
+	}
+	panic("IterateTransactions callback not set")
+}
+
 // Calls the callback previously set with `s.OnCreateTransaction = ...`
 func (s *Store) CreateTransaction(ctx context.Context, in *models.Transaction, log *models.ComplianceAuditLog) error {
-	s.calls["CreateTransaction"]++
+	s.called("CreateTransaction")
 	if s.OnCreateTransaction != nil {
 		return s.OnCreateTransaction(ctx, in, log)
 	}
@@ -214,7 +337,7 @@ func (s *Store) CreateTransaction(ctx context.Context, in *models.Transaction, l
 
 // Calls the callback previously set with `s.OnRetrieveTransaction = ...`
 func (s *Store) RetrieveTransaction(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
-	s.calls["RetrieveTransaction"]++
+	s.called("RetrieveTransaction")
 	if s.OnRetrieveTransaction != nil {
 		return s.OnRetrieveTransaction(ctx, id)
 	}
@@ -223,7 +346,7 @@ func (s *Store) RetrieveTransaction(ctx context.Context, id uuid.UUID) (*models.
 
 // Calls the callback previously set with `s.OnUpdateTransaction = ...`
 func (s *Store) UpdateTransaction(ctx context.Context, in *models.Transaction, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateTransaction"]++
+	s.called("UpdateTransaction")
 	if s.OnUpdateTransaction != nil {
 		return s.OnUpdateTransaction(ctx, in, log)
 	}
@@ -232,7 +355,7 @@ func (s *Store) UpdateTransaction(ctx context.Context, in *models.Transaction, l
 
 // Calls the callback previously set with `s.OnDeleteTransaction = ...`
 func (s *Store) DeleteTransaction(ctx context.Context, id uuid.UUID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteTransaction"]++
+	s.called("DeleteTransaction")
 	if s.OnDeleteTransaction != nil {
 		return s.OnDeleteTransaction(ctx, id, log)
 	}
@@ -241,7 +364,7 @@ func (s *Store) DeleteTransaction(ctx context.Context, id uuid.UUID, log *models
 
 // Calls the callback previously set with `s.OnArchiveTransaction = ...`
 func (s *Store) ArchiveTransaction(ctx context.Context, id uuid.UUID, log *models.ComplianceAuditLog) error {
-	s.calls["ArchiveTransaction"]++
+	s.called("ArchiveTransaction")
 	if s.OnArchiveTransaction != nil {
 		return s.OnArchiveTransaction(ctx, id, log)
 	}
@@ -250,7 +373,7 @@ func (s *Store) ArchiveTransaction(ctx context.Context, id uuid.UUID, log *model
 
 // Calls the callback previously set with `s.OnUnarchiveTransaction = ...`
 func (s *Store) UnarchiveTransaction(ctx context.Context, id uuid.UUID, log *models.ComplianceAuditLog) error {
-	s.calls["UnarchiveTransaction"]++
+	s.called("UnarchiveTransaction")
 	if s.OnUnarchiveTransaction != nil {
 		return s.OnUnarchiveTransaction(ctx, id, log)
 	}
@@ -259,7 +382,7 @@ func (s *Store) UnarchiveTransaction(ctx context.Context, id uuid.UUID, log *mod
 
 // Calls the callback previously set with `s.OnCountTransactions = ...`
 func (s *Store) CountTransactions(ctx context.Context) (*models.TransactionCounts, error) {
-	s.calls["CountTransactions"]++
+	s.called("CountTransactions")
 	if s.OnCountTransactions != nil {
 		return s.OnCountTransactions(ctx)
 	}
@@ -268,7 +391,7 @@ func (s *Store) CountTransactions(ctx context.Context) (*models.TransactionCount
 
 // Calls the callback previously set with `s.OnPrepareTransaction = ...`
 func (s *Store) PrepareTransaction(ctx context.Context, id uuid.UUID, log *models.ComplianceAuditLog) (models.PreparedTransaction, error) {
-	s.calls["PrepareTransaction"]++
+	s.called("PrepareTransaction")
 	if s.OnPrepareTransaction != nil {
 		return s.OnPrepareTransaction(ctx, id, log)
 	}
@@ -281,20 +404,110 @@ func (s *Store) PrepareTransaction(ctx context.Context, id uuid.UUID, log *model
 
 // Calls the callback previously set with `s.OnTransactionState = ...`
 func (s *Store) TransactionState(ctx context.Context, id uuid.UUID) (bool, enum.Status, error) {
-	s.calls["TransactionState"]++
+	s.called("TransactionState")
 	if s.OnTransactionState != nil {
 		return s.OnTransactionState(ctx, id)
 	}
 	panic("TransactionState callback not set")
 }
 
+// Calls the callback previously set with `s.OnListStatusHistory = ...`
+func (s *Store) ListStatusHistory(ctx context.Context, txID uuid.UUID) ([]*models.StatusTransition, error) {
+	s.called("ListStatusHistory")
+	if s.OnListStatusHistory != nil {
+		return s.OnListStatusHistory(ctx, txID)
+	}
+	panic("ListStatusHistory callback not set")
+}
+
+// Calls the callback previously set with `s.OnListTransactionNotes = ...`
+func (s *Store) ListTransactionNotes(ctx context.Context, txID uuid.UUID) ([]*models.TransactionNote, error) {
+	s.called("ListTransactionNotes")
+	if s.OnListTransactionNotes != nil {
+		return s.OnListTransactionNotes(ctx, txID)
+	}
+	panic("ListTransactionNotes callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateTransactionNote = ...`
+func (s *Store) CreateTransactionNote(ctx context.Context, note *models.TransactionNote, auditLog *models.ComplianceAuditLog) error {
+	s.called("CreateTransactionNote")
+	if s.OnCreateTransactionNote != nil {
+		return s.OnCreateTransactionNote(ctx, note, auditLog)
+	}
+	panic("CreateTransactionNote callback not set")
+}
+
+// Calls the callback previously set with `s.OnAssignTransaction = ...`
+func (s *Store) AssignTransaction(ctx context.Context, txID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error {
+	s.called("AssignTransaction")
+	if s.OnAssignTransaction != nil {
+		return s.OnAssignTransaction(ctx, txID, assigneeID, auditLog)
+	}
+	panic("AssignTransaction callback not set")
+}
+
+// Calls the callback previously set with `s.OnListTransactionTags = ...`
+func (s *Store) ListTransactionTags(ctx context.Context) ([]string, error) {
+	s.called("ListTransactionTags")
+	if s.OnListTransactionTags != nil {
+		return s.OnListTransactionTags(ctx)
+	}
+	panic("ListTransactionTags callback not set")
+}
+
+// Calls the callback previously set with `s.OnSetTransactionTags = ...`
+func (s *Store) SetTransactionTags(ctx context.Context, txID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error {
+	s.called("SetTransactionTags")
+	if s.OnSetTransactionTags != nil {
+		return s.OnSetTransactionTags(ctx, txID, tags, auditLog)
+	}
+	panic("SetTransactionTags callback not set")
+}
+
+// Calls the callback previously set with `s.OnListReplyDeadlines = ...`
+func (s *Store) ListReplyDeadlines(ctx context.Context, dueBefore time.Time) ([]*models.Transaction, error) {
+	s.called("ListReplyDeadlines")
+	if s.OnListReplyDeadlines != nil {
+		return s.OnListReplyDeadlines(ctx, dueBefore)
+	}
+	panic("ListReplyDeadlines callback not set")
+}
+
+// Calls the callback previously set with `s.OnMarkReplyReminded = ...`
+func (s *Store) MarkReplyReminded(ctx context.Context, txID uuid.UUID, auditLog *models.ComplianceAuditLog) error {
+	s.called("MarkReplyReminded")
+	if s.OnMarkReplyReminded != nil {
+		return s.OnMarkReplyReminded(ctx, txID, auditLog)
+	}
+	panic("MarkReplyReminded callback not set")
+}
+
+// Calls the callback previously set with `s.OnMarkReplyExpired = ...`
+func (s *Store) MarkReplyExpired(ctx context.Context, txID uuid.UUID, auditLog *models.ComplianceAuditLog) error {
+	s.called("MarkReplyExpired")
+	if s.OnMarkReplyExpired != nil {
+		return s.OnMarkReplyExpired(ctx, txID, auditLog)
+	}
+	panic("MarkReplyExpired callback not set")
+}
+
+// Calls the callback previously set with `s.OnListTransactionReviewers = ...`
+func (s *Store) ListTransactionReviewers(ctx context.Context) ([]*models.User, error) {
+	s.called("ListTransactionReviewers")
+	if s.OnListTransactionReviewers != nil {
+		return s.OnListTransactionReviewers(ctx)
+	}
+	panic("ListTransactionReviewers callback not set")
+}
+
 //===========================================================================
 // SecureEnvelope Store Methods
 //===========================================================================
 
 // Calls the callback previously set with `s.OnListSecureEnvelopes = ...`
 func (s *Store) ListSecureEnvelopes(ctx context.Context, txID uuid.UUID, page *models.PageInfo) (*models.SecureEnvelopePage, error) {
-	s.calls["ListSecureEnvelopes"]++
+	s.called("ListSecureEnvelopes")
 	if s.OnListSecureEnvelopes != nil {
 		return s.OnListSecureEnvelopes(ctx, txID, page)
 	}
@@ -303,7 +516,7 @@ func (s *Store) ListSecureEnvelopes(ctx context.Context, txID uuid.UUID, page *m
 
 // Calls the callback previously set with `s.OnCreateSecureEnvelope = ...`
 func (s *Store) CreateSecureEnvelope(ctx context.Context, in *models.SecureEnvelope, log *models.ComplianceAuditLog) error {
-	s.calls["CreateSecureEnvelope"]++
+	s.called("CreateSecureEnvelope")
 	if s.OnCreateSecureEnvelope != nil {
 		return s.OnCreateSecureEnvelope(ctx, in, log)
 	}
@@ -312,7 +525,7 @@ func (s *Store) CreateSecureEnvelope(ctx context.Context, in *models.SecureEnvel
 
 // Calls the callback previously set with `s.OnRetrieveSecureEnvelope = ...`
 func (s *Store) RetrieveSecureEnvelope(ctx context.Context, txID uuid.UUID, envID ulid.ULID) (*models.SecureEnvelope, error) {
-	s.calls["RetrieveSecureEnvelope"]++
+	s.called("RetrieveSecureEnvelope")
 	if s.OnRetrieveSecureEnvelope != nil {
 		return s.OnRetrieveSecureEnvelope(ctx, txID, envID)
 	}
@@ -321,7 +534,7 @@ func (s *Store) RetrieveSecureEnvelope(ctx context.Context, txID uuid.UUID, envI
 
 // Calls the callback previously set with `s.OnUpdateSecureEnvelope = ...`
 func (s *Store) UpdateSecureEnvelope(ctx context.Context, in *models.SecureEnvelope, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateSecureEnvelope"]++
+	s.called("UpdateSecureEnvelope")
 	if s.OnUpdateSecureEnvelope != nil {
 		return s.OnUpdateSecureEnvelope(ctx, in, log)
 	}
@@ -330,7 +543,7 @@ func (s *Store) UpdateSecureEnvelope(ctx context.Context, in *models.SecureEnvel
 
 // Calls the callback previously set with `s.OnDeleteSecureEnvelope = ...`
 func (s *Store) DeleteSecureEnvelope(ctx context.Context, txID uuid.UUID, envID ulid.ULID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteSecureEnvelope"]++
+	s.called("DeleteSecureEnvelope")
 	if s.OnDeleteSecureEnvelope != nil {
 		return s.OnDeleteSecureEnvelope(ctx, txID, envID, log)
 	}
@@ -339,7 +552,7 @@ func (s *Store) DeleteSecureEnvelope(ctx context.Context, txID uuid.UUID, envID
 
 // Calls the callback previously set with `s.OnLatestSecureEnvelope = ...`
 func (s *Store) LatestSecureEnvelope(ctx context.Context, txID uuid.UUID, direction enum.Direction) (*models.SecureEnvelope, error) {
-	s.calls["LatestSecureEnvelope"]++
+	s.called("LatestSecureEnvelope")
 	if s.OnLatestSecureEnvelope != nil {
 		return s.OnLatestSecureEnvelope(ctx, txID, direction)
 	}
@@ -348,7 +561,7 @@ func (s *Store) LatestSecureEnvelope(ctx context.Context, txID uuid.UUID, direct
 
 // Calls the callback previously set with `s.OnLatestPayloadEnvelope = ...`
 func (s *Store) LatestPayloadEnvelope(ctx context.Context, txID uuid.UUID, direction enum.Direction) (*models.SecureEnvelope, error) {
-	s.calls["LatestPayloadEnvelope"]++
+	s.called("LatestPayloadEnvelope")
 	if s.OnLatestPayloadEnvelope != nil {
 		return s.OnLatestPayloadEnvelope(ctx, txID, direction)
 	}
@@ -361,7 +574,7 @@ func (s *Store) LatestPayloadEnvelope(ctx context.Context, txID uuid.UUID, direc
 
 // Calls the callback previously set with `s.OnListAccounts = ...`
 func (s *Store) ListAccounts(ctx context.Context, in *models.PageInfo) (*models.AccountsPage, error) {
-	s.calls["ListAccounts"]++
+	s.called("ListAccounts")
 	if s.OnListAccounts != nil {
 		return s.OnListAccounts(ctx, in)
 	}
@@ -370,7 +583,7 @@ func (s *Store) ListAccounts(ctx context.Context, in *models.PageInfo) (*models.
 
 // Calls the callback previously set with `s.OnCreateAccount = ...`
 func (s *Store) CreateAccount(ctx context.Context, in *models.Account, log *models.ComplianceAuditLog) error {
-	s.calls["CreateAccount"]++
+	s.called("CreateAccount")
 	if s.OnCreateAccount != nil {
 		return s.OnCreateAccount(ctx, in, log)
 	}
@@ -379,7 +592,7 @@ func (s *Store) CreateAccount(ctx context.Context, in *models.Account, log *mode
 
 // Calls the callback previously set with `s.OnLookupAccount = ...`
 func (s *Store) LookupAccount(ctx context.Context, cryptoAddress string) (*models.Account, error) {
-	s.calls["LookupAccount"]++
+	s.called("LookupAccount")
 	if s.OnLookupAccount != nil {
 		return s.OnLookupAccount(ctx, cryptoAddress)
 	}
@@ -388,7 +601,7 @@ func (s *Store) LookupAccount(ctx context.Context, cryptoAddress string) (*model
 
 // Calls the callback previously set with `s.OnRetrieveAccount = ...`
 func (s *Store) RetrieveAccount(ctx context.Context, id ulid.ULID) (*models.Account, error) {
-	s.calls["RetrieveAccount"]++
+	s.called("RetrieveAccount")
 	if s.OnRetrieveAccount != nil {
 		return s.OnRetrieveAccount(ctx, id)
 	}
@@ -397,7 +610,7 @@ func (s *Store) RetrieveAccount(ctx context.Context, id ulid.ULID) (*models.Acco
 
 // Calls the callback previously set with `s.OnUpdateAccount = ...`
 func (s *Store) UpdateAccount(ctx context.Context, in *models.Account, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateAccount"]++
+	s.called("UpdateAccount")
 	if s.OnUpdateAccount != nil {
 		return s.OnUpdateAccount(ctx, in, log)
 	}
@@ -406,7 +619,7 @@ func (s *Store) UpdateAccount(ctx context.Context, in *models.Account, log *mode
 
 // Calls the callback previously set with `s.OnDeleteAccount = ...`
 func (s *Store) DeleteAccount(ctx context.Context, id ulid.ULID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteAccount"]++
+	s.called("DeleteAccount")
 	if s.OnDeleteAccount != nil {
 		return s.OnDeleteAccount(ctx, id, log)
 	}
@@ -415,7 +628,7 @@ func (s *Store) DeleteAccount(ctx context.Context, id ulid.ULID, log *models.Com
 
 // Calls the callback previously set with `s.OnListAccountTransactions = ...`
 func (s *Store) ListAccountTransactions(ctx context.Context, accountID ulid.ULID, page *models.TransactionPageInfo) (*models.TransactionPage, error) {
-	s.calls["ListAccountTransactions"]++
+	s.called("ListAccountTransactions")
 	if s.OnListAccountTransactions != nil {
 		return s.OnListAccountTransactions(ctx, accountID, page)
 	}
@@ -428,7 +641,7 @@ func (s *Store) ListAccountTransactions(ctx context.Context, accountID ulid.ULID
 
 // Calls the callback previously set with `s.OnListCryptoAddresses = ...`
 func (s *Store) ListCryptoAddresses(ctx context.Context, accountID ulid.ULID, page *models.PageInfo) (*models.CryptoAddressPage, error) {
-	s.calls["ListCryptoAddresses"]++
+	s.called("ListCryptoAddresses")
 	if s.OnListCryptoAddresses != nil {
 		return s.OnListCryptoAddresses(ctx, accountID, page)
 	}
@@ -437,7 +650,7 @@ func (s *Store) ListCryptoAddresses(ctx context.Context, accountID ulid.ULID, pa
 
 // Calls the callback previously set with `s.OnCreateCryptoAddress = ...`
 func (s *Store) CreateCryptoAddress(ctx context.Context, in *models.CryptoAddress, log *models.ComplianceAuditLog) error {
-	s.calls["CreateCryptoAddress"]++
+	s.called("CreateCryptoAddress")
 	if s.OnCreateCryptoAddress != nil {
 		return s.OnCreateCryptoAddress(ctx, in, log)
 	}
@@ -446,7 +659,7 @@ func (s *Store) CreateCryptoAddress(ctx context.Context, in *models.CryptoAddres
 
 // Calls the callback previously set with `s.OnRetrieveCryptoAddress = ...`
 func (s *Store) RetrieveCryptoAddress(ctx context.Context, accountID, cryptoAddressID ulid.ULID) (*models.CryptoAddress, error) {
-	s.calls["RetrieveCryptoAddress"]++
+	s.called("RetrieveCryptoAddress")
 	if s.OnRetrieveCryptoAddress != nil {
 		return s.OnRetrieveCryptoAddress(ctx, accountID, cryptoAddressID)
 	}
@@ -455,7 +668,7 @@ func (s *Store) RetrieveCryptoAddress(ctx context.Context, accountID, cryptoAddr
 
 // Calls the callback previously set with `s.OnUpdateCryptoAddress = ...`
 func (s *Store) UpdateCryptoAddress(ctx context.Context, in *models.CryptoAddress, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateCryptoAddress"]++
+	s.called("UpdateCryptoAddress")
 	if s.OnUpdateCryptoAddress != nil {
 		return s.OnUpdateCryptoAddress(ctx, in, log)
 	}
@@ -464,7 +677,7 @@ func (s *Store) UpdateCryptoAddress(ctx context.Context, in *models.CryptoAddres
 
 // Calls the callback previously set with `s.OnDeleteCryptoAddress = ...`
 func (s *Store) DeleteCryptoAddress(ctx context.Context, accountID, cryptoAddressID ulid.ULID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteCryptoAddress"]++
+	s.called("DeleteCryptoAddress")
 	if s.OnDeleteCryptoAddress != nil {
 		return s.OnDeleteCryptoAddress(ctx, accountID, cryptoAddressID, log)
 	}
@@ -477,7 +690,7 @@ func (s *Store) DeleteCryptoAddress(ctx context.Context, accountID, cryptoAddres
 
 // Calls the callback previously set with `s.OnSearchCounterparties = ...`
 func (s *Store) SearchCounterparties(ctx context.Context, query *models.SearchQuery) (*models.CounterpartyPage, error) {
-	s.calls["SearchCounterparties"]++
+	s.called("SearchCounterparties")
 	if s.OnSearchCounterparties != nil {
 		return s.OnSearchCounterparties(ctx, query)
 	}
@@ -486,7 +699,7 @@ func (s *Store) SearchCounterparties(ctx context.Context, query *models.SearchQu
 
 // Calls the callback previously set with `s.OnListCounterparties = ...`
 func (s *Store) ListCounterparties(ctx context.Context, page *models.CounterpartyPageInfo) (*models.CounterpartyPage, error) {
-	s.calls["ListCounterparties"]++
+	s.called("ListCounterparties")
 	if s.OnListCounterparties != nil {
 		return s.OnListCounterparties(ctx, page)
 	}
@@ -495,7 +708,7 @@ func (s *Store) ListCounterparties(ctx context.Context, page *models.Counterpart
 
 // Calls the callback previously set with `s.OnListCounterpartySourceInfo = ...`
 func (s *Store) ListCounterpartySourceInfo(ctx context.Context, source enum.Source) ([]*models.CounterpartySourceInfo, error) {
-	s.calls["ListCounterpartySourceInfo"]++
+	s.called("ListCounterpartySourceInfo")
 	if s.OnListCounterpartySourceInfo != nil {
 		return s.OnListCounterpartySourceInfo(ctx, source)
 	}
@@ -504,7 +717,7 @@ func (s *Store) ListCounterpartySourceInfo(ctx context.Context, source enum.Sour
 
 // Calls the callback previously set with `s.OnCreateCounterparty = ...`
 func (s *Store) CreateCounterparty(ctx context.Context, in *models.Counterparty, log *models.ComplianceAuditLog) error {
-	s.calls["CreateCounterparty"]++
+	s.called("CreateCounterparty")
 	if s.OnCreateCounterparty != nil {
 		return s.OnCreateCounterparty(ctx, in, log)
 	}
@@ -513,7 +726,7 @@ func (s *Store) CreateCounterparty(ctx context.Context, in *models.Counterparty,
 
 // Calls the callback previously set with `s.OnRetrieveCounterparty = ...`
 func (s *Store) RetrieveCounterparty(ctx context.Context, counterpartyID ulid.ULID) (*models.Counterparty, error) {
-	s.calls["RetrieveCounterparty"]++
+	s.called("RetrieveCounterparty")
 	if s.OnRetrieveCounterparty != nil {
 		return s.OnRetrieveCounterparty(ctx, counterpartyID)
 	}
@@ -522,7 +735,7 @@ func (s *Store) RetrieveCounterparty(ctx context.Context, counterpartyID ulid.UL
 
 // Calls the callback previously set with `s.OnLookupCounterparty = ...`
 func (s *Store) LookupCounterparty(ctx context.Context, field, value string) (*models.Counterparty, error) {
-	s.calls["LookupCounterparty"]++
+	s.called("LookupCounterparty")
 	if s.OnLookupCounterparty != nil {
 		return s.OnLookupCounterparty(ctx, field, value)
 	}
@@ -531,16 +744,25 @@ func (s *Store) LookupCounterparty(ctx context.Context, field, value string) (*m
 
 // Calls the callback previously set with `s.OnUpdateCounterparty = ...`
 func (s *Store) UpdateCounterparty(ctx context.Context, in *models.Counterparty, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateCounterparty"]++
+	s.called("UpdateCounterparty")
 	if s.OnUpdateCounterparty != nil {
 		return s.OnUpdateCounterparty(ctx, in, log)
 	}
 	panic("UpdateCounterparty callback not set")
 }
 
+// Calls the callback previously set with `s.OnUpdateCounterpartyPending = ...`
+func (s *Store) UpdateCounterpartyPending(ctx context.Context, counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error {
+	s.called("UpdateCounterpartyPending")
+	if s.OnUpdateCounterpartyPending != nil {
+		return s.OnUpdateCounterpartyPending(ctx, counterpartyID, overrides, auditLog)
+	}
+	panic("UpdateCounterpartyPending callback not set")
+}
+
 // Calls the callback previously set with `s.OnDeleteCounterparty = ...`
 func (s *Store) DeleteCounterparty(ctx context.Context, counterpartyID ulid.ULID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteCounterparty"]++
+	s.called("DeleteCounterparty")
 	if s.OnDeleteCounterparty != nil {
 		return s.OnDeleteCounterparty(ctx, counterpartyID, log)
 	}
@@ -553,7 +775,7 @@ func (s *Store) DeleteCounterparty(ctx context.Context, counterpartyID ulid.ULID
 
 // Calls the callback previously set with `s.OnListContacts = ...`
 func (s *Store) ListContacts(ctx context.Context, counterparty any, page *models.PageInfo) (*models.ContactsPage, error) {
-	s.calls["ListContacts"]++
+	s.called("ListContacts")
 	if s.OnListContacts != nil {
 		return s.OnListContacts(ctx, counterparty, page)
 	}
@@ -562,7 +784,7 @@ func (s *Store) ListContacts(ctx context.Context, counterparty any, page *models
 
 // Calls the callback previously set with `s.OnCreateContact = ...`
 func (s *Store) CreateContact(ctx context.Context, in *models.Contact, log *models.ComplianceAuditLog) error {
-	s.calls["CreateContact"]++
+	s.called("CreateContact")
 	if s.OnCreateContact != nil {
 		return s.OnCreateContact(ctx, in, log)
 	}
@@ -571,7 +793,7 @@ func (s *Store) CreateContact(ctx context.Context, in *models.Contact, log *mode
 
 // Calls the callback previously set with `s.OnRetrieveContact = ...`
 func (s *Store) RetrieveContact(ctx context.Context, contactID, counterparty any) (*models.Contact, error) {
-	s.calls["RetrieveContact"]++
+	s.called("RetrieveContact")
 	if s.OnRetrieveContact != nil {
 		return s.OnRetrieveContact(ctx, counterparty, contactID)
 	}
@@ -580,7 +802,7 @@ func (s *Store) RetrieveContact(ctx context.Context, contactID, counterparty any
 
 // Calls the callback previously set with `s.OnUpdateContact = ...`
 func (s *Store) UpdateContact(ctx context.Context, in *models.Contact, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateContact"]++
+	s.called("UpdateContact")
 	if s.OnUpdateContact != nil {
 		return s.OnUpdateContact(ctx, in, log)
 	}
@@ -589,7 +811,7 @@ func (s *Store) UpdateContact(ctx context.Context, in *models.Contact, log *mode
 
 // Calls the callback previously set with `s.OnDeleteContact = ...`
 func (s *Store) DeleteContact(ctx context.Context, contactID, counterparty any, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteContact"]++
+	s.called("DeleteContact")
 	if s.OnDeleteContact != nil {
 		return s.OnDeleteContact(ctx, contactID, counterparty, log)
 	}
@@ -602,20 +824,257 @@ func (s *Store) DeleteContact(ctx context.Context, contactID, counterparty any,
 
 // Calls the callback previously set with `s.OnUseTravelAddressFactory = ...`
 func (s *Store) UseTravelAddressFactory(f models.TravelAddressFactory) {
-	s.calls["UseTravelAddressFactory"]++
+	s.called("UseTravelAddressFactory")
 	if s.OnUseTravelAddressFactory != nil {
 		s.OnUseTravelAddressFactory(f)
 	}
 	panic("UseTravelAddressFactory callback not set")
 }
 
+//===========================================================================
+// Field Encryption
+//===========================================================================
+
+// Calls the callback previously set with `s.OnUseFieldEncryption = ...`
+func (s *Store) UseFieldEncryption(cipher *pii.Cipher) error {
+	s.called("UseFieldEncryption")
+	if s.OnUseFieldEncryption != nil {
+		return s.OnUseFieldEncryption(cipher)
+	}
+	panic("UseFieldEncryption callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveDataKey = ...`
+func (s *Store) RetrieveDataKey(ctx context.Context) (*models.DataKey, error) {
+	s.called("RetrieveDataKey")
+	if s.OnRetrieveDataKey != nil {
+		return s.OnRetrieveDataKey(ctx)
+	}
+	panic("RetrieveDataKey callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateDataKey = ...`
+func (s *Store) CreateDataKey(ctx context.Context, key *models.DataKey) error {
+	s.called("CreateDataKey")
+	if s.OnCreateDataKey != nil {
+		return s.OnCreateDataKey(ctx, key)
+	}
+	panic("CreateDataKey callback not set")
+}
+
+// Calls the callback previously set with `s.OnListSigningKeys = ...`
+func (s *Store) ListSigningKeys(ctx context.Context) ([]*models.SigningKey, error) {
+	s.called("ListSigningKeys")
+	if s.OnListSigningKeys != nil {
+		return s.OnListSigningKeys(ctx)
+	}
+	panic("ListSigningKeys callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateSigningKey = ...`
+func (s *Store) CreateSigningKey(ctx context.Context, key *models.SigningKey) error {
+	s.called("CreateSigningKey")
+	if s.OnCreateSigningKey != nil {
+		return s.OnCreateSigningKey(ctx, key)
+	}
+	panic("CreateSigningKey callback not set")
+}
+
+// Calls the callback previously set with `s.OnDeleteExpiredSigningKeys = ...`
+func (s *Store) DeleteExpiredSigningKeys(ctx context.Context) (int64, error) {
+	s.called("DeleteExpiredSigningKeys")
+	if s.OnDeleteExpiredSigningKeys != nil {
+		return s.OnDeleteExpiredSigningKeys(ctx)
+	}
+	panic("DeleteExpiredSigningKeys callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveIdempotencyKey = ...`
+func (s *Store) RetrieveIdempotencyKey(ctx context.Context, actorID []byte, key string) (*models.IdempotencyKey, error) {
+	s.called("RetrieveIdempotencyKey")
+	if s.OnRetrieveIdempotencyKey != nil {
+		return s.OnRetrieveIdempotencyKey(ctx, actorID, key)
+	}
+	panic("RetrieveIdempotencyKey callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateIdempotencyKey = ...`
+func (s *Store) CreateIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) error {
+	s.called("CreateIdempotencyKey")
+	if s.OnCreateIdempotencyKey != nil {
+		return s.OnCreateIdempotencyKey(ctx, key)
+	}
+	panic("CreateIdempotencyKey callback not set")
+}
+
+// Calls the callback previously set with `s.OnListBatches = ...`
+func (s *Store) ListBatches(ctx context.Context) ([]*models.Batch, error) {
+	s.called("ListBatches")
+	if s.OnListBatches != nil {
+		return s.OnListBatches(ctx)
+	}
+	panic("ListBatches callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateBatch = ...`
+func (s *Store) CreateBatch(ctx context.Context, batch *models.Batch, auditLog *models.ComplianceAuditLog) error {
+	s.called("CreateBatch")
+	if s.OnCreateBatch != nil {
+		return s.OnCreateBatch(ctx, batch, auditLog)
+	}
+	panic("CreateBatch callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveBatch = ...`
+func (s *Store) RetrieveBatch(ctx context.Context, id ulid.ULID) (*models.Batch, error) {
+	s.called("RetrieveBatch")
+	if s.OnRetrieveBatch != nil {
+		return s.OnRetrieveBatch(ctx, id)
+	}
+	panic("RetrieveBatch callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateBatch = ...`
+func (s *Store) UpdateBatch(ctx context.Context, batch *models.Batch, auditLog *models.ComplianceAuditLog) error {
+	s.called("UpdateBatch")
+	if s.OnUpdateBatch != nil {
+		return s.OnUpdateBatch(ctx, batch, auditLog)
+	}
+	panic("UpdateBatch callback not set")
+}
+
+// Calls the callback previously set with `s.OnListBatchItems = ...`
+func (s *Store) ListBatchItems(ctx context.Context, batchID ulid.ULID) ([]*models.BatchItem, error) {
+	s.called("ListBatchItems")
+	if s.OnListBatchItems != nil {
+		return s.OnListBatchItems(ctx, batchID)
+	}
+	panic("ListBatchItems callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateBatchItem = ...`
+func (s *Store) UpdateBatchItem(ctx context.Context, item *models.BatchItem) error {
+	s.called("UpdateBatchItem")
+	if s.OnUpdateBatchItem != nil {
+		return s.OnUpdateBatchItem(ctx, item)
+	}
+	panic("UpdateBatchItem callback not set")
+}
+
+//===========================================================================
+// Report Store Methods
+//===========================================================================
+
+// Calls the callback previously set with `s.OnListReports = ...`
+func (s *Store) ListReports(ctx context.Context) ([]*models.Report, error) {
+	s.called("ListReports")
+	if s.OnListReports != nil {
+		return s.OnListReports(ctx)
+	}
+	panic("ListReports callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateReport = ...`
+func (s *Store) CreateReport(ctx context.Context, report *models.Report, auditLog *models.ComplianceAuditLog) error {
+	s.called("CreateReport")
+	if s.OnCreateReport != nil {
+		return s.OnCreateReport(ctx, report, auditLog)
+	}
+	panic("CreateReport callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveReport = ...`
+func (s *Store) RetrieveReport(ctx context.Context, id ulid.ULID) (*models.Report, error) {
+	s.called("RetrieveReport")
+	if s.OnRetrieveReport != nil {
+		return s.OnRetrieveReport(ctx, id)
+	}
+	panic("RetrieveReport callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveScheduledReport = ...`
+func (s *Store) RetrieveScheduledReport(ctx context.Context, schedule string, periodStart time.Time) (*models.Report, error) {
+	s.called("RetrieveScheduledReport")
+	if s.OnRetrieveScheduledReport != nil {
+		return s.OnRetrieveScheduledReport(ctx, schedule, periodStart)
+	}
+	panic("RetrieveScheduledReport callback not set")
+}
+
+// Calls the callback previously set with `s.OnDeleteReport = ...`
+func (s *Store) DeleteReport(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	s.called("DeleteReport")
+	if s.OnDeleteReport != nil {
+		return s.OnDeleteReport(ctx, id, auditLog)
+	}
+	panic("DeleteReport callback not set")
+}
+
+// Calls the callback previously set with `s.OnListEnvelopeTimings = ...`
+func (s *Store) ListEnvelopeTimings(ctx context.Context, after, before time.Time) ([]*models.EnvelopeTiming, error) {
+	s.called("ListEnvelopeTimings")
+	if s.OnListEnvelopeTimings != nil {
+		return s.OnListEnvelopeTimings(ctx, after, before)
+	}
+	panic("ListEnvelopeTimings callback not set")
+}
+
+//===========================================================================
+// Threshold Rule Store Methods
+//===========================================================================
+
+// Calls the callback previously set with `s.OnListThresholdRules = ...`
+func (s *Store) ListThresholdRules(ctx context.Context) ([]*models.ThresholdRule, error) {
+	s.called("ListThresholdRules")
+	if s.OnListThresholdRules != nil {
+		return s.OnListThresholdRules(ctx)
+	}
+	panic("ListThresholdRules callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateThresholdRule = ...`
+func (s *Store) CreateThresholdRule(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) error {
+	s.called("CreateThresholdRule")
+	if s.OnCreateThresholdRule != nil {
+		return s.OnCreateThresholdRule(ctx, rule, auditLog)
+	}
+	panic("CreateThresholdRule callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveThresholdRule = ...`
+func (s *Store) RetrieveThresholdRule(ctx context.Context, ruleID ulid.ULID) (*models.ThresholdRule, error) {
+	s.called("RetrieveThresholdRule")
+	if s.OnRetrieveThresholdRule != nil {
+		return s.OnRetrieveThresholdRule(ctx, ruleID)
+	}
+	panic("RetrieveThresholdRule callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateThresholdRule = ...`
+func (s *Store) UpdateThresholdRule(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) error {
+	s.called("UpdateThresholdRule")
+	if s.OnUpdateThresholdRule != nil {
+		return s.OnUpdateThresholdRule(ctx, rule, auditLog)
+	}
+	panic("UpdateThresholdRule callback not set")
+}
+
+// Calls the callback previously set with `s.OnDeleteThresholdRule = ...`
+func (s *Store) DeleteThresholdRule(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	s.called("DeleteThresholdRule")
+	if s.OnDeleteThresholdRule != nil {
+		return s.OnDeleteThresholdRule(ctx, ruleID, auditLog)
+	}
+	panic("DeleteThresholdRule callback not set")
+}
+
 //===========================================================================
 // Sunrise Store Methods
 //===========================================================================
 
 // Calls the callback previously set with `s.OnListSunrise = ...`
 func (s *Store) ListSunrise(ctx context.Context, page *models.PageInfo) (*models.SunrisePage, error) {
-	s.calls["ListSunrise"]++
+	s.called("ListSunrise")
 	if s.OnListSunrise != nil {
 		return s.OnListSunrise(ctx, page)
 	}
@@ -624,7 +1083,7 @@ func (s *Store) ListSunrise(ctx context.Context, page *models.PageInfo) (*models
 
 // Calls the callback previously set with `s.OnCreateSunrise = ...`
 func (s *Store) CreateSunrise(ctx context.Context, msg *models.Sunrise, log *models.ComplianceAuditLog) error {
-	s.calls["CreateSunrise"]++
+	s.called("CreateSunrise")
 	if s.OnCreateSunrise != nil {
 		return s.OnCreateSunrise(ctx, msg, log)
 	}
@@ -633,7 +1092,7 @@ func (s *Store) CreateSunrise(ctx context.Context, msg *models.Sunrise, log *mod
 
 // Calls the callback previously set with `s.OnRetrieveSunrise = ...`
 func (s *Store) RetrieveSunrise(ctx context.Context, id ulid.ULID) (*models.Sunrise, error) {
-	s.calls["RetrieveSunrise"]++
+	s.called("RetrieveSunrise")
 	if s.OnRetrieveSunrise != nil {
 		return s.OnRetrieveSunrise(ctx, id)
 	}
@@ -642,7 +1101,7 @@ func (s *Store) RetrieveSunrise(ctx context.Context, id ulid.ULID) (*models.Sunr
 
 // Calls the callback previously set with `s.OnUpdateSunrise = ...`
 func (s *Store) UpdateSunrise(ctx context.Context, msg *models.Sunrise, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateSunrise"]++
+	s.called("UpdateSunrise")
 	if s.OnUpdateSunrise != nil {
 		return s.OnUpdateSunrise(ctx, msg, log)
 	}
@@ -651,7 +1110,7 @@ func (s *Store) UpdateSunrise(ctx context.Context, msg *models.Sunrise, log *mod
 
 // Calls the callback previously set with `s.OnUpdateSunriseStatus = ...`
 func (s *Store) UpdateSunriseStatus(ctx context.Context, txID uuid.UUID, status enum.Status, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateSunriseStatus"]++
+	s.called("UpdateSunriseStatus")
 	if s.OnUpdateSunriseStatus != nil {
 		return s.OnUpdateSunriseStatus(ctx, txID, status, log)
 	}
@@ -660,7 +1119,7 @@ func (s *Store) UpdateSunriseStatus(ctx context.Context, txID uuid.UUID, status
 
 // Calls the callback previously set with `s.OnDeleteSunrise = ...`
 func (s *Store) DeleteSunrise(ctx context.Context, id ulid.ULID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteSunrise"]++
+	s.called("DeleteSunrise")
 	if s.OnDeleteSunrise != nil {
 		return s.OnDeleteSunrise(ctx, id, log)
 	}
@@ -669,7 +1128,7 @@ func (s *Store) DeleteSunrise(ctx context.Context, id ulid.ULID, log *models.Com
 
 // Calls the callback previously set with `s.OnGetOrCreateSunriseCounterparty = ...`
 func (s *Store) GetOrCreateSunriseCounterparty(ctx context.Context, email, name string, log *models.ComplianceAuditLog) (*models.Counterparty, error) {
-	s.calls["GetOrCreateSunriseCounterparty"]++
+	s.called("GetOrCreateSunriseCounterparty")
 	if s.OnGetOrCreateSunriseCounterparty != nil {
 		return s.OnGetOrCreateSunriseCounterparty(ctx, email, name, log)
 	}
@@ -682,7 +1141,7 @@ func (s *Store) GetOrCreateSunriseCounterparty(ctx context.Context, email, name
 
 // Calls the callback previously set with `s.OnListUsers = ...`
 func (s *Store) ListUsers(ctx context.Context, page *models.UserPageInfo) (*models.UserPage, error) {
-	s.calls["ListUsers"]++
+	s.called("ListUsers")
 	if s.OnListUsers != nil {
 		return s.OnListUsers(ctx, page)
 	}
@@ -691,7 +1150,7 @@ func (s *Store) ListUsers(ctx context.Context, page *models.UserPageInfo) (*mode
 
 // Calls the callback previously set with `s.OnCreateUser = ...`
 func (s *Store) CreateUser(ctx context.Context, in *models.User, log *models.ComplianceAuditLog) error {
-	s.calls["CreateUser"]++
+	s.called("CreateUser")
 	if s.OnCreateUser != nil {
 		return s.OnCreateUser(ctx, in, log)
 	}
@@ -700,7 +1159,7 @@ func (s *Store) CreateUser(ctx context.Context, in *models.User, log *models.Com
 
 // Calls the callback previously set with `s.OnRetrieveUser = ...`
 func (s *Store) RetrieveUser(ctx context.Context, emailOrUserID any) (*models.User, error) {
-	s.calls["RetrieveUser"]++
+	s.called("RetrieveUser")
 	if s.OnRetrieveUser != nil {
 		return s.OnRetrieveUser(ctx, emailOrUserID)
 	}
@@ -709,7 +1168,7 @@ func (s *Store) RetrieveUser(ctx context.Context, emailOrUserID any) (*models.Us
 
 // Calls the callback previously set with `s.OnUpdateUser = ...`
 func (s *Store) UpdateUser(ctx context.Context, in *models.User, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateUser"]++
+	s.called("UpdateUser")
 	if s.OnUpdateUser != nil {
 		return s.OnUpdateUser(ctx, in, log)
 	}
@@ -718,7 +1177,7 @@ func (s *Store) UpdateUser(ctx context.Context, in *models.User, log *models.Com
 
 // Calls the callback previously set with `s.OnSetUserPassword = ...`
 func (s *Store) SetUserPassword(ctx context.Context, userID ulid.ULID, password string) (err error) {
-	s.calls["SetUserPassword"]++
+	s.called("SetUserPassword")
 	if s.OnSetUserPassword != nil {
 		return s.OnSetUserPassword(ctx, userID, password)
 	}
@@ -727,38 +1186,245 @@ func (s *Store) SetUserPassword(ctx context.Context, userID ulid.ULID, password
 
 // Calls the callback previously set with `s.OnSetUserLastLogin = ...`
 func (s *Store) SetUserLastLogin(ctx context.Context, userID ulid.ULID, lastLogin time.Time) (err error) {
-	s.calls["SetUserLastLogin"]++
+	s.called("SetUserLastLogin")
 	if s.OnSetUserLastLogin != nil {
 		return s.OnSetUserLastLogin(ctx, userID, lastLogin)
 	}
 	panic("SetUserLastLogin callback not set")
 }
 
+// Calls the callback previously set with `s.OnRecordUserLoginFailure = ...`
+func (s *Store) RecordUserLoginFailure(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) error {
+	s.called("RecordUserLoginFailure")
+	if s.OnRecordUserLoginFailure != nil {
+		return s.OnRecordUserLoginFailure(ctx, userID, maxFailures, lockout, maxLockout)
+	}
+	panic("RecordUserLoginFailure callback not set")
+}
+
+// Calls the callback previously set with `s.OnResetUserLoginFailures = ...`
+func (s *Store) ResetUserLoginFailures(ctx context.Context, userID ulid.ULID) error {
+	s.called("ResetUserLoginFailures")
+	if s.OnResetUserLoginFailures != nil {
+		return s.OnResetUserLoginFailures(ctx, userID)
+	}
+	panic("ResetUserLoginFailures callback not set")
+}
+
+// Calls the callback previously set with `s.OnUnlockUser = ...`
+func (s *Store) UnlockUser(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	s.called("UnlockUser")
+	if s.OnUnlockUser != nil {
+		return s.OnUnlockUser(ctx, userID, auditLog)
+	}
+	panic("UnlockUser callback not set")
+}
+
+// Calls the callback previously set with `s.OnListUserSessions = ...`
+func (s *Store) ListUserSessions(ctx context.Context, userID ulid.ULID) ([]*models.UserSession, error) {
+	s.called("ListUserSessions")
+	if s.OnListUserSessions != nil {
+		return s.OnListUserSessions(ctx, userID)
+	}
+	panic("ListUserSessions callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateUserSession = ...`
+func (s *Store) CreateUserSession(ctx context.Context, session *models.UserSession) error {
+	s.called("CreateUserSession")
+	if s.OnCreateUserSession != nil {
+		return s.OnCreateUserSession(ctx, session)
+	}
+	panic("CreateUserSession callback not set")
+}
+
+// Calls the callback previously set with `s.OnRefreshUserSession = ...`
+func (s *Store) RefreshUserSession(ctx context.Context, userID, sessionID ulid.ULID, expires time.Time) error {
+	s.called("RefreshUserSession")
+	if s.OnRefreshUserSession != nil {
+		return s.OnRefreshUserSession(ctx, userID, sessionID, expires)
+	}
+	panic("RefreshUserSession callback not set")
+}
+
+// Calls the callback previously set with `s.OnRevokeUserSession = ...`
+func (s *Store) RevokeUserSession(ctx context.Context, userID, sessionID ulid.ULID) error {
+	s.called("RevokeUserSession")
+	if s.OnRevokeUserSession != nil {
+		return s.OnRevokeUserSession(ctx, userID, sessionID)
+	}
+	panic("RevokeUserSession callback not set")
+}
+
+// Calls the callback previously set with `s.OnRevokeUserSessions = ...`
+func (s *Store) RevokeUserSessions(ctx context.Context, userID, keep ulid.ULID) error {
+	s.called("RevokeUserSessions")
+	if s.OnRevokeUserSessions != nil {
+		return s.OnRevokeUserSessions(ctx, userID, keep)
+	}
+	panic("RevokeUserSessions callback not set")
+}
+
 // Calls the callback previously set with `s.OnDeleteUser = ...`
 func (s *Store) DeleteUser(ctx context.Context, userID ulid.ULID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteUser"]++
+	s.called("DeleteUser")
 	if s.OnDeleteUser != nil {
 		return s.OnDeleteUser(ctx, userID, log)
 	}
 	panic("DeleteUser callback not set")
 }
 
+// Calls the callback previously set with `s.OnRetrieveRevokedUser = ...`
+func (s *Store) RetrieveRevokedUser(ctx context.Context, userID ulid.ULID) (*models.RevokedUser, error) {
+	s.called("RetrieveRevokedUser")
+	if s.OnRetrieveRevokedUser != nil {
+		return s.OnRetrieveRevokedUser(ctx, userID)
+	}
+	panic("RetrieveRevokedUser callback not set")
+}
+
+// Calls the callback previously set with `s.OnListRoles = ...`
+func (s *Store) ListRoles(ctx context.Context) ([]*models.Role, error) {
+	s.called("ListRoles")
+	if s.OnListRoles != nil {
+		return s.OnListRoles(ctx)
+	}
+	panic("ListRoles callback not set")
+}
+
 // Calls the callback previously set with `s.OnLookupRole = ...`
 func (s *Store) LookupRole(ctx context.Context, role string) (*models.Role, error) {
-	s.calls["LookupRole"]++
+	s.called("LookupRole")
 	if s.OnLookupRole != nil {
 		return s.OnLookupRole(ctx, role)
 	}
 	panic("LookupRole callback not set")
 }
 
+// Calls the callback previously set with `s.OnSetUserMFASecret = ...`
+func (s *Store) SetUserMFASecret(ctx context.Context, userID ulid.ULID, secret string) error {
+	s.called("SetUserMFASecret")
+	if s.OnSetUserMFASecret != nil {
+		return s.OnSetUserMFASecret(ctx, userID, secret)
+	}
+	panic("SetUserMFASecret callback not set")
+}
+
+// Calls the callback previously set with `s.OnEnableUserMFA = ...`
+func (s *Store) EnableUserMFA(ctx context.Context, userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error {
+	s.called("EnableUserMFA")
+	if s.OnEnableUserMFA != nil {
+		return s.OnEnableUserMFA(ctx, userID, recoveryCodes, log)
+	}
+	panic("EnableUserMFA callback not set")
+}
+
+// Calls the callback previously set with `s.OnSetUserRecoveryCodes = ...`
+func (s *Store) SetUserRecoveryCodes(ctx context.Context, userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error {
+	s.called("SetUserRecoveryCodes")
+	if s.OnSetUserRecoveryCodes != nil {
+		return s.OnSetUserRecoveryCodes(ctx, userID, recoveryCodes, log)
+	}
+	panic("SetUserRecoveryCodes callback not set")
+}
+
+// Calls the callback previously set with `s.OnUseUserRecoveryCode = ...`
+func (s *Store) UseUserRecoveryCode(ctx context.Context, userID ulid.ULID, recoveryCode string) error {
+	s.called("UseUserRecoveryCode")
+	if s.OnUseUserRecoveryCode != nil {
+		return s.OnUseUserRecoveryCode(ctx, userID, recoveryCode)
+	}
+	panic("UseUserRecoveryCode callback not set")
+}
+
+// Calls the callback previously set with `s.OnSetUserMFAStep = ...`
+func (s *Store) SetUserMFAStep(ctx context.Context, userID ulid.ULID, step int64) error {
+	s.called("SetUserMFAStep")
+	if s.OnSetUserMFAStep != nil {
+		return s.OnSetUserMFAStep(ctx, userID, step)
+	}
+	panic("SetUserMFAStep callback not set")
+}
+
+// Calls the callback previously set with `s.OnRecordUserMFAFailure = ...`
+func (s *Store) RecordUserMFAFailure(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout time.Duration) error {
+	s.called("RecordUserMFAFailure")
+	if s.OnRecordUserMFAFailure != nil {
+		return s.OnRecordUserMFAFailure(ctx, userID, maxFailures, lockout)
+	}
+	panic("RecordUserMFAFailure callback not set")
+}
+
+// Calls the callback previously set with `s.OnResetUserMFA = ...`
+func (s *Store) ResetUserMFA(ctx context.Context, userID ulid.ULID, log *models.ComplianceAuditLog) error {
+	s.called("ResetUserMFA")
+	if s.OnResetUserMFA != nil {
+		return s.OnResetUserMFA(ctx, userID, log)
+	}
+	panic("ResetUserMFA callback not set")
+}
+
+// Calls the callback previously set with `s.OnSetRoleMFARequired = ...`
+func (s *Store) SetRoleMFARequired(ctx context.Context, roleID int64, required bool, log *models.ComplianceAuditLog) error {
+	s.called("SetRoleMFARequired")
+	if s.OnSetRoleMFARequired != nil {
+		return s.OnSetRoleMFARequired(ctx, roleID, required, log)
+	}
+	panic("SetRoleMFARequired callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateRole = ...`
+func (s *Store) CreateRole(ctx context.Context, in *models.Role, log *models.ComplianceAuditLog) error {
+	s.called("CreateRole")
+	if s.OnCreateRole != nil {
+		return s.OnCreateRole(ctx, in, log)
+	}
+	panic("CreateRole callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveRole = ...`
+func (s *Store) RetrieveRole(ctx context.Context, roleID int64) (*models.Role, error) {
+	s.called("RetrieveRole")
+	if s.OnRetrieveRole != nil {
+		return s.OnRetrieveRole(ctx, roleID)
+	}
+	panic("RetrieveRole callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateRole = ...`
+func (s *Store) UpdateRole(ctx context.Context, in *models.Role, log *models.ComplianceAuditLog) error {
+	s.called("UpdateRole")
+	if s.OnUpdateRole != nil {
+		return s.OnUpdateRole(ctx, in, log)
+	}
+	panic("UpdateRole callback not set")
+}
+
+// Calls the callback previously set with `s.OnDeleteRole = ...`
+func (s *Store) DeleteRole(ctx context.Context, roleID int64, log *models.ComplianceAuditLog) error {
+	s.called("DeleteRole")
+	if s.OnDeleteRole != nil {
+		return s.OnDeleteRole(ctx, roleID, log)
+	}
+	panic("DeleteRole callback not set")
+}
+
+// Calls the callback previously set with `s.OnListPermissions = ...`
+func (s *Store) ListPermissions(ctx context.Context) ([]*models.Permission, error) {
+	s.called("ListPermissions")
+	if s.OnListPermissions != nil {
+		return s.OnListPermissions(ctx)
+	}
+	panic("ListPermissions callback not set")
+}
+
 //===========================================================================
 // API Key Store Methods
 //===========================================================================
 
 // Calls the callback previously set with `s.OnListAPIKeys = ...`
 func (s *Store) ListAPIKeys(ctx context.Context, in *models.PageInfo) (*models.APIKeyPage, error) {
-	s.calls["ListAPIKeys"]++
+	s.called("ListAPIKeys")
 	if s.OnListAPIKeys != nil {
 		return s.OnListAPIKeys(ctx, in)
 	}
@@ -767,7 +1433,7 @@ func (s *Store) ListAPIKeys(ctx context.Context, in *models.PageInfo) (*models.A
 
 // Calls the callback previously set with `s.OnCreateAPIKey = ...`
 func (s *Store) CreateAPIKey(ctx context.Context, in *models.APIKey, log *models.ComplianceAuditLog) error {
-	s.calls["CreateAPIKey"]++
+	s.called("CreateAPIKey")
 	if s.OnCreateAPIKey != nil {
 		return s.OnCreateAPIKey(ctx, in, log)
 	}
@@ -776,7 +1442,7 @@ func (s *Store) CreateAPIKey(ctx context.Context, in *models.APIKey, log *models
 
 // Calls the callback previously set with `s.OnRetrieveAPIKey = ...`
 func (s *Store) RetrieveAPIKey(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error) {
-	s.calls["RetrieveAPIKey"]++
+	s.called("RetrieveAPIKey")
 	if s.OnRetrieveAPIKey != nil {
 		return s.OnRetrieveAPIKey(ctx, clientIDOrKeyID)
 	}
@@ -785,7 +1451,7 @@ func (s *Store) RetrieveAPIKey(ctx context.Context, clientIDOrKeyID any) (*model
 
 // Calls the callback previously set with `s.OnUpdateAPIKey = ...`
 func (s *Store) UpdateAPIKey(ctx context.Context, in *models.APIKey, log *models.ComplianceAuditLog) error {
-	s.calls["UpdateAPIKey"]++
+	s.called("UpdateAPIKey")
 	if s.OnUpdateAPIKey != nil {
 		return s.OnUpdateAPIKey(ctx, in, log)
 	}
@@ -794,29 +1460,65 @@ func (s *Store) UpdateAPIKey(ctx context.Context, in *models.APIKey, log *models
 
 // Calls the callback previously set with `s.OnSetAPIKeyLastSeen = ...`
 func (s *Store) SetAPIKeyLastSeen(ctx context.Context, keyID ulid.ULID, lastSeen time.Time) error {
-	s.calls["SetAPIKeyLastSeen"]++
+	s.called("SetAPIKeyLastSeen")
 	if s.OnSetAPIKeyLastSeen != nil {
 		return s.OnSetAPIKeyLastSeen(ctx, keyID, lastSeen)
 	}
 	panic("SetAPIKeyLastSeen callback not set")
 }
 
+// Calls the callback previously set with `s.OnRotateAPIKey = ...`
+func (s *Store) RotateAPIKey(ctx context.Context, key *models.APIKey, auditLog *models.ComplianceAuditLog) error {
+	s.called("RotateAPIKey")
+	if s.OnRotateAPIKey != nil {
+		return s.OnRotateAPIKey(ctx, key, auditLog)
+	}
+	panic("RotateAPIKey callback not set")
+}
+
 // Calls the callback previously set with `s.OnDeleteAPIKey = ...`
 func (s *Store) DeleteAPIKey(ctx context.Context, keyID ulid.ULID, log *models.ComplianceAuditLog) error {
-	s.calls["DeleteAPIKey"]++
+	s.called("DeleteAPIKey")
 	if s.OnDeleteAPIKey != nil {
 		return s.OnDeleteAPIKey(ctx, keyID, log)
 	}
 	panic("DeleteAPIKey callback not set")
 }
 
+// Calls the callback previously set with `s.OnRetrieveRevokedAPIKey = ...`
+func (s *Store) RetrieveRevokedAPIKey(ctx context.Context, keyID ulid.ULID) (*models.RevokedAPIKey, error) {
+	s.called("RetrieveRevokedAPIKey")
+	if s.OnRetrieveRevokedAPIKey != nil {
+		return s.OnRetrieveRevokedAPIKey(ctx, keyID)
+	}
+	panic("RetrieveRevokedAPIKey callback not set")
+}
+
+// Calls the callback previously set with `s.OnRecordAPIKeyUsage = ...`
+func (s *Store) RecordAPIKeyUsage(ctx context.Context, keyID ulid.ULID, ip string, ts time.Time) error {
+	s.called("RecordAPIKeyUsage")
+	if s.OnRecordAPIKeyUsage != nil {
+		return s.OnRecordAPIKeyUsage(ctx, keyID, ip, ts)
+	}
+	panic("RecordAPIKeyUsage callback not set")
+}
+
+// Calls the callback previously set with `s.OnListAPIKeyUsage = ...`
+func (s *Store) ListAPIKeyUsage(ctx context.Context, keyID ulid.ULID, since time.Time) ([]*models.APIKeyUsage, error) {
+	s.called("ListAPIKeyUsage")
+	if s.OnListAPIKeyUsage != nil {
+		return s.OnListAPIKeyUsage(ctx, keyID, since)
+	}
+	panic("ListAPIKeyUsage callback not set")
+}
+
 //===========================================================================
 // Reset Password Link Store Methods
 //===========================================================================
 
 // Calls the callback previously set with `s.OnListResetPasswordLinks = ...`
 func (s *Store) ListResetPasswordLinks(ctx context.Context, page *models.PageInfo) (*models.ResetPasswordLinkPage, error) {
-	s.calls["ListResetPasswordLinks"]++
+	s.called("ListResetPasswordLinks")
 	if s.OnListResetPasswordLinks != nil {
 		return s.OnListResetPasswordLinks(ctx, page)
 	}
@@ -825,7 +1527,7 @@ func (s *Store) ListResetPasswordLinks(ctx context.Context, page *models.PageInf
 
 // Calls the callback previously set with `s.OnCreateResetPasswordLink = ...`
 func (s *Store) CreateResetPasswordLink(ctx context.Context, link *models.ResetPasswordLink) error {
-	s.calls["CreateResetPasswordLink"]++
+	s.called("CreateResetPasswordLink")
 	if s.OnCreateResetPasswordLink != nil {
 		return s.OnCreateResetPasswordLink(ctx, link)
 	}
@@ -834,7 +1536,7 @@ func (s *Store) CreateResetPasswordLink(ctx context.Context, link *models.ResetP
 
 // Calls the callback previously set with `s.OnRetrieveResetPasswordLink = ...`
 func (s *Store) RetrieveResetPasswordLink(ctx context.Context, linkID ulid.ULID) (*models.ResetPasswordLink, error) {
-	s.calls["RetrieveResetPasswordLink"]++
+	s.called("RetrieveResetPasswordLink")
 	if s.OnRetrieveResetPasswordLink != nil {
 		return s.OnRetrieveResetPasswordLink(ctx, linkID)
 	}
@@ -843,7 +1545,7 @@ func (s *Store) RetrieveResetPasswordLink(ctx context.Context, linkID ulid.ULID)
 
 // Calls the callback previously set with `s.OnUpdateResetPasswordLink = ...`
 func (s *Store) UpdateResetPasswordLink(ctx context.Context, link *models.ResetPasswordLink) error {
-	s.calls["UpdateResetPasswordLink"]++
+	s.called("UpdateResetPasswordLink")
 	if s.OnUpdateResetPasswordLink != nil {
 		return s.OnUpdateResetPasswordLink(ctx, link)
 	}
@@ -852,20 +1554,74 @@ func (s *Store) UpdateResetPasswordLink(ctx context.Context, link *models.ResetP
 
 // Calls the callback previously set with `s.OnDeleteResetPasswordLink = ...`
 func (s *Store) DeleteResetPasswordLink(ctx context.Context, linkID ulid.ULID) (err error) {
-	s.calls["DeleteResetPasswordLink"]++
+	s.called("DeleteResetPasswordLink")
 	if s.OnDeleteResetPasswordLink != nil {
 		return s.OnDeleteResetPasswordLink(ctx, linkID)
 	}
 	panic("DeleteResetPasswordLink callback not set")
 }
 
+// Calls the callback previously set with `s.OnListUserInvites = ...`
+func (s *Store) ListUserInvites(ctx context.Context) ([]*models.UserInvite, error) {
+	s.called("ListUserInvites")
+	if s.OnListUserInvites != nil {
+		return s.OnListUserInvites(ctx)
+	}
+	panic("ListUserInvites callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateUserInvite = ...`
+func (s *Store) CreateUserInvite(ctx context.Context, invite *models.UserInvite, auditLog *models.ComplianceAuditLog) error {
+	s.called("CreateUserInvite")
+	if s.OnCreateUserInvite != nil {
+		return s.OnCreateUserInvite(ctx, invite, auditLog)
+	}
+	panic("CreateUserInvite callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveUserInvite = ...`
+func (s *Store) RetrieveUserInvite(ctx context.Context, inviteID ulid.ULID) (*models.UserInvite, error) {
+	s.called("RetrieveUserInvite")
+	if s.OnRetrieveUserInvite != nil {
+		return s.OnRetrieveUserInvite(ctx, inviteID)
+	}
+	panic("RetrieveUserInvite callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateUserInvite = ...`
+func (s *Store) UpdateUserInvite(ctx context.Context, invite *models.UserInvite) error {
+	s.called("UpdateUserInvite")
+	if s.OnUpdateUserInvite != nil {
+		return s.OnUpdateUserInvite(ctx, invite)
+	}
+	panic("UpdateUserInvite callback not set")
+}
+
+// Calls the callback previously set with `s.OnDeleteUserInvite = ...`
+func (s *Store) DeleteUserInvite(ctx context.Context, inviteID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	s.called("DeleteUserInvite")
+	if s.OnDeleteUserInvite != nil {
+		return s.OnDeleteUserInvite(ctx, inviteID, auditLog)
+	}
+	panic("DeleteUserInvite callback not set")
+}
+
+// Calls the callback previously set with `s.OnAcceptUserInvite = ...`
+func (s *Store) AcceptUserInvite(ctx context.Context, inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) error {
+	s.called("AcceptUserInvite")
+	if s.OnAcceptUserInvite != nil {
+		return s.OnAcceptUserInvite(ctx, inviteID, user, auditLog)
+	}
+	panic("AcceptUserInvite callback not set")
+}
+
 //===========================================================================
 // Compliance Audit Log Store Methods
 //===========================================================================
 
 // Calls the callback previously set with `s.OnListComplianceAuditLog = ...`
 func (s *Store) ListComplianceAuditLogs(ctx context.Context, page *models.ComplianceAuditLogPageInfo) (*models.ComplianceAuditLogPage, error) {
-	s.calls["ListComplianceAuditLogs"]++
+	s.called("ListComplianceAuditLogs")
 	if s.OnListComplianceAuditLogs != nil {
 		return s.OnListComplianceAuditLogs(ctx, page)
 	}
@@ -874,7 +1630,7 @@ func (s *Store) ListComplianceAuditLogs(ctx context.Context, page *models.Compli
 
 // Calls the callback previously set with `s.OnCreateComplianceAuditLog = ...`
 func (s *Store) CreateComplianceAuditLog(ctx context.Context, log *models.ComplianceAuditLog) error {
-	s.calls["CreateComplianceAuditLog"]++
+	s.called("CreateComplianceAuditLog")
 	if s.OnCreateComplianceAuditLog != nil {
 		return s.OnCreateComplianceAuditLog(ctx, log)
 	}
@@ -883,9 +1639,278 @@ func (s *Store) CreateComplianceAuditLog(ctx context.Context, log *models.Compli
 
 // Calls the callback previously set with `s.OnRetrieveComplianceAuditLog = ...`
 func (s *Store) RetrieveComplianceAuditLog(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error) {
-	s.calls["RetrieveComplianceAuditLog"]++
+	s.called("RetrieveComplianceAuditLog")
 	if s.OnRetrieveComplianceAuditLog != nil {
 		return s.OnRetrieveComplianceAuditLog(ctx, id)
 	}
 	panic("RetrieveComplianceAuditLog callback not set")
 }
+
+//===========================================================================
+// Retention Store Methods
+//===========================================================================
+
+// Calls the callback previously set with `s.OnArchiveExpiredTransactions = ...`
+func (s *Store) ArchiveExpiredTransactions(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	s.called("ArchiveExpiredTransactions")
+	if s.OnArchiveExpiredTransactions != nil {
+		return s.OnArchiveExpiredTransactions(ctx, before, log)
+	}
+	panic("ArchiveExpiredTransactions callback not set")
+}
+
+// Calls the callback previously set with `s.OnPurgeArchivedTransactions = ...`
+func (s *Store) PurgeArchivedTransactions(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	s.called("PurgeArchivedTransactions")
+	if s.OnPurgeArchivedTransactions != nil {
+		return s.OnPurgeArchivedTransactions(ctx, before, log)
+	}
+	panic("PurgeArchivedTransactions callback not set")
+}
+
+// Calls the callback previously set with `s.OnShredArchivedTransactions = ...`
+func (s *Store) ShredArchivedTransactions(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	s.called("ShredArchivedTransactions")
+	if s.OnShredArchivedTransactions != nil {
+		return s.OnShredArchivedTransactions(ctx, before, log)
+	}
+	panic("ShredArchivedTransactions callback not set")
+}
+
+// Calls the callback previously set with `s.OnShredExpiredSecureEnvelopes = ...`
+func (s *Store) ShredExpiredSecureEnvelopes(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	s.called("ShredExpiredSecureEnvelopes")
+	if s.OnShredExpiredSecureEnvelopes != nil {
+		return s.OnShredExpiredSecureEnvelopes(ctx, before, log)
+	}
+	panic("ShredExpiredSecureEnvelopes callback not set")
+}
+
+// Calls the callback previously set with `s.OnPurgeExpiredSunrise = ...`
+func (s *Store) PurgeExpiredSunrise(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	s.called("PurgeExpiredSunrise")
+	if s.OnPurgeExpiredSunrise != nil {
+		return s.OnPurgeExpiredSunrise(ctx, before, log)
+	}
+	panic("PurgeExpiredSunrise callback not set")
+}
+
+// Calls the callback previously set with `s.OnPurgeExpiredAccounts = ...`
+func (s *Store) PurgeExpiredAccounts(ctx context.Context, before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	s.called("PurgeExpiredAccounts")
+	if s.OnPurgeExpiredAccounts != nil {
+		return s.OnPurgeExpiredAccounts(ctx, before, log)
+	}
+	panic("PurgeExpiredAccounts callback not set")
+}
+
+// Calls the callback previously set with `s.OnPurgeExpiredResetPasswordLinks = ...`
+func (s *Store) PurgeExpiredResetPasswordLinks(ctx context.Context, before time.Time) (int64, error) {
+	s.called("PurgeExpiredResetPasswordLinks")
+	if s.OnPurgeExpiredResetPasswordLinks != nil {
+		return s.OnPurgeExpiredResetPasswordLinks(ctx, before)
+	}
+	panic("PurgeExpiredResetPasswordLinks callback not set")
+}
+
+//===========================================================================
+// Legal Hold Store Methods
+//===========================================================================
+
+// Calls the callback previously set with `s.OnListLegalHolds = ...`
+func (s *Store) ListLegalHolds(ctx context.Context, page *models.LegalHoldPageInfo) (*models.LegalHoldPage, error) {
+	s.called("ListLegalHolds")
+	if s.OnListLegalHolds != nil {
+		return s.OnListLegalHolds(ctx, page)
+	}
+	panic("ListLegalHolds callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateLegalHold = ...`
+func (s *Store) CreateLegalHold(ctx context.Context, hold *models.LegalHold, log *models.ComplianceAuditLog) error {
+	s.called("CreateLegalHold")
+	if s.OnCreateLegalHold != nil {
+		return s.OnCreateLegalHold(ctx, hold, log)
+	}
+	panic("CreateLegalHold callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveLegalHold = ...`
+func (s *Store) RetrieveLegalHold(ctx context.Context, id ulid.ULID) (*models.LegalHold, error) {
+	s.called("RetrieveLegalHold")
+	if s.OnRetrieveLegalHold != nil {
+		return s.OnRetrieveLegalHold(ctx, id)
+	}
+	panic("RetrieveLegalHold callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateLegalHold = ...`
+func (s *Store) UpdateLegalHold(ctx context.Context, hold *models.LegalHold, log *models.ComplianceAuditLog) error {
+	s.called("UpdateLegalHold")
+	if s.OnUpdateLegalHold != nil {
+		return s.OnUpdateLegalHold(ctx, hold, log)
+	}
+	panic("UpdateLegalHold callback not set")
+}
+
+// Calls the callback previously set with `s.OnReleaseLegalHold = ...`
+func (s *Store) ReleaseLegalHold(ctx context.Context, id ulid.ULID, releasedBy string, log *models.ComplianceAuditLog) error {
+	s.called("ReleaseLegalHold")
+	if s.OnReleaseLegalHold != nil {
+		return s.OnReleaseLegalHold(ctx, id, releasedBy, log)
+	}
+	panic("ReleaseLegalHold callback not set")
+}
+
+// Calls the callback previously set with `s.OnListApprovalRules = ...`
+func (s *Store) ListApprovalRules(ctx context.Context) ([]*models.ApprovalRule, error) {
+	s.called("ListApprovalRules")
+	if s.OnListApprovalRules != nil {
+		return s.OnListApprovalRules(ctx)
+	}
+	panic("ListApprovalRules callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateApprovalRule = ...`
+func (s *Store) CreateApprovalRule(ctx context.Context, rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error {
+	s.called("CreateApprovalRule")
+	if s.OnCreateApprovalRule != nil {
+		return s.OnCreateApprovalRule(ctx, rule, auditLog)
+	}
+	panic("CreateApprovalRule callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveApprovalRule = ...`
+func (s *Store) RetrieveApprovalRule(ctx context.Context, ruleID ulid.ULID) (*models.ApprovalRule, error) {
+	s.called("RetrieveApprovalRule")
+	if s.OnRetrieveApprovalRule != nil {
+		return s.OnRetrieveApprovalRule(ctx, ruleID)
+	}
+	panic("RetrieveApprovalRule callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateApprovalRule = ...`
+func (s *Store) UpdateApprovalRule(ctx context.Context, rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error {
+	s.called("UpdateApprovalRule")
+	if s.OnUpdateApprovalRule != nil {
+		return s.OnUpdateApprovalRule(ctx, rule, auditLog)
+	}
+	panic("UpdateApprovalRule callback not set")
+}
+
+// Calls the callback previously set with `s.OnDeleteApprovalRule = ...`
+func (s *Store) DeleteApprovalRule(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	s.called("DeleteApprovalRule")
+	if s.OnDeleteApprovalRule != nil {
+		return s.OnDeleteApprovalRule(ctx, ruleID, auditLog)
+	}
+	panic("DeleteApprovalRule callback not set")
+}
+
+// Calls the callback previously set with `s.OnListApprovals = ...`
+func (s *Store) ListApprovals(ctx context.Context, page *models.ApprovalPageInfo) (*models.ApprovalPage, error) {
+	s.called("ListApprovals")
+	if s.OnListApprovals != nil {
+		return s.OnListApprovals(ctx, page)
+	}
+	panic("ListApprovals callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateApproval = ...`
+func (s *Store) CreateApproval(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+	s.called("CreateApproval")
+	if s.OnCreateApproval != nil {
+		return s.OnCreateApproval(ctx, approval, auditLog)
+	}
+	panic("CreateApproval callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveApproval = ...`
+func (s *Store) RetrieveApproval(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+	s.called("RetrieveApproval")
+	if s.OnRetrieveApproval != nil {
+		return s.OnRetrieveApproval(ctx, approvalID)
+	}
+	panic("RetrieveApproval callback not set")
+}
+
+// Calls the callback previously set with `s.OnClaimApproval = ...`
+func (s *Store) ClaimApproval(ctx context.Context, approvalID ulid.ULID) error {
+	s.called("ClaimApproval")
+	if s.OnClaimApproval != nil {
+		return s.OnClaimApproval(ctx, approvalID)
+	}
+	panic("ClaimApproval callback not set")
+}
+
+// Calls the callback previously set with `s.OnReleaseApproval = ...`
+func (s *Store) ReleaseApproval(ctx context.Context, approvalID ulid.ULID) error {
+	s.called("ReleaseApproval")
+	if s.OnReleaseApproval != nil {
+		return s.OnReleaseApproval(ctx, approvalID)
+	}
+	panic("ReleaseApproval callback not set")
+}
+
+// Calls the callback previously set with `s.OnReviewApproval = ...`
+func (s *Store) ReviewApproval(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+	s.called("ReviewApproval")
+	if s.OnReviewApproval != nil {
+		return s.OnReviewApproval(ctx, approval, auditLog)
+	}
+	panic("ReviewApproval callback not set")
+}
+
+// Calls the callback previously set with `s.OnListApprovalReviewers = ...`
+func (s *Store) ListApprovalReviewers(ctx context.Context) ([]*models.User, error) {
+	s.called("ListApprovalReviewers")
+	if s.OnListApprovalReviewers != nil {
+		return s.OnListApprovalReviewers(ctx)
+	}
+	panic("ListApprovalReviewers callback not set")
+}
+
+// Calls the callback previously set with `s.OnListQueuedTransfers = ...`
+func (s *Store) ListQueuedTransfers(ctx context.Context) ([]*models.QueuedTransfer, error) {
+	s.called("ListQueuedTransfers")
+	if s.OnListQueuedTransfers != nil {
+		return s.OnListQueuedTransfers(ctx)
+	}
+	panic("ListQueuedTransfers callback not set")
+}
+
+// Calls the callback previously set with `s.OnListDueTransfers = ...`
+func (s *Store) ListDueTransfers(ctx context.Context, before time.Time) ([]*models.QueuedTransfer, error) {
+	s.called("ListDueTransfers")
+	if s.OnListDueTransfers != nil {
+		return s.OnListDueTransfers(ctx, before)
+	}
+	panic("ListDueTransfers callback not set")
+}
+
+// Calls the callback previously set with `s.OnCreateQueuedTransfer = ...`
+func (s *Store) CreateQueuedTransfer(ctx context.Context, transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error {
+	s.called("CreateQueuedTransfer")
+	if s.OnCreateQueuedTransfer != nil {
+		return s.OnCreateQueuedTransfer(ctx, transfer, log)
+	}
+	panic("CreateQueuedTransfer callback not set")
+}
+
+// Calls the callback previously set with `s.OnRetrieveQueuedTransfer = ...`
+func (s *Store) RetrieveQueuedTransfer(ctx context.Context, id ulid.ULID) (*models.QueuedTransfer, error) {
+	s.called("RetrieveQueuedTransfer")
+	if s.OnRetrieveQueuedTransfer != nil {
+		return s.OnRetrieveQueuedTransfer(ctx, id)
+	}
+	panic("RetrieveQueuedTransfer callback not set")
+}
+
+// Calls the callback previously set with `s.OnUpdateQueuedTransfer = ...`
+func (s *Store) UpdateQueuedTransfer(ctx context.Context, transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error {
+	s.called("UpdateQueuedTransfer")
+	if s.OnUpdateQueuedTransfer != nil {
+		return s.OnUpdateQueuedTransfer(ctx, transfer, log)
+	}
+	panic("UpdateQueuedTransfer callback not set")
+}
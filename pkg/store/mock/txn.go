@@ -39,6 +39,16 @@ type Tx struct {
 	OnUnarchiveTransaction           func(id uuid.UUID, log *models.ComplianceAuditLog) error
 	OnCountTransactions              func() (*models.TransactionCounts, error)
 	OnTransactionState               func(id uuid.UUID) (bool, enum.Status, error)
+	OnListStatusHistory              func(txID uuid.UUID) ([]*models.StatusTransition, error)
+	OnListTransactionNotes           func(txID uuid.UUID) ([]*models.TransactionNote, error)
+	OnCreateTransactionNote          func(note *models.TransactionNote, auditLog *models.ComplianceAuditLog) error
+	OnAssignTransaction              func(txID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error
+	OnListTransactionTags            func() ([]string, error)
+	OnSetTransactionTags             func(txID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error
+	OnListReplyDeadlines             func(dueBefore time.Time) ([]*models.Transaction, error)
+	OnMarkReplyReminded              func(txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	OnMarkReplyExpired               func(txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	OnListTransactionReviewers       func() ([]*models.User, error)
 	OnListSecureEnvelopes            func(txID uuid.UUID, page *models.PageInfo) (*models.SecureEnvelopePage, error)
 	OnCreateSecureEnvelope           func(in *models.SecureEnvelope, log *models.ComplianceAuditLog) error
 	OnRetrieveSecureEnvelope         func(txID uuid.UUID, envID ulid.ULID) (*models.SecureEnvelope, error)
@@ -65,6 +75,7 @@ type Tx struct {
 	OnRetrieveCounterparty           func(counterpartyID ulid.ULID) (*models.Counterparty, error)
 	OnLookupCounterparty             func(field, value string) (*models.Counterparty, error)
 	OnUpdateCounterparty             func(in *models.Counterparty, log *models.ComplianceAuditLog) error
+	OnUpdateCounterpartyPending      func(counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error
 	OnDeleteCounterparty             func(counterpartyID ulid.ULID, log *models.ComplianceAuditLog) error
 	OnListContacts                   func(counterparty any, page *models.PageInfo) (*models.ContactsPage, error)
 	OnCreateContact                  func(in *models.Contact, log *models.ComplianceAuditLog) error
@@ -84,22 +95,84 @@ type Tx struct {
 	OnUpdateUser                     func(in *models.User, log *models.ComplianceAuditLog) error
 	OnSetUserPassword                func(userID ulid.ULID, password string) error
 	OnSetUserLastLogin               func(userID ulid.ULID, lastLogin time.Time) error
+	OnRecordUserLoginFailure         func(userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) error
+	OnResetUserLoginFailures         func(userID ulid.ULID) error
+	OnUnlockUser                     func(userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	OnListUserSessions               func(userID ulid.ULID) ([]*models.UserSession, error)
+	OnCreateUserSession              func(session *models.UserSession) error
+	OnRefreshUserSession             func(userID, sessionID ulid.ULID, expires time.Time) error
+	OnRevokeUserSession              func(userID, sessionID ulid.ULID) error
+	OnRevokeUserSessions             func(userID, keep ulid.ULID) error
 	OnDeleteUser                     func(userID ulid.ULID, log *models.ComplianceAuditLog) error
+	OnRetrieveRevokedUser            func(userID ulid.ULID) (*models.RevokedUser, error)
+	OnListRoles                      func() ([]*models.Role, error)
 	OnLookupRole                     func(role string) (*models.Role, error)
+	OnSetUserMFASecret               func(userID ulid.ULID, secret string) error
+	OnEnableUserMFA                  func(userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error
+	OnSetUserRecoveryCodes           func(userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error
+	OnUseUserRecoveryCode            func(userID ulid.ULID, recoveryCode string) error
+	OnSetUserMFAStep                 func(userID ulid.ULID, step int64) error
+	OnRecordUserMFAFailure           func(userID ulid.ULID, maxFailures int64, lockout time.Duration) error
+	OnResetUserMFA                   func(userID ulid.ULID, log *models.ComplianceAuditLog) error
+	OnSetRoleMFARequired             func(roleID int64, required bool, log *models.ComplianceAuditLog) error
+	OnCreateRole                     func(in *models.Role, log *models.ComplianceAuditLog) error
+	OnRetrieveRole                   func(roleID int64) (*models.Role, error)
+	OnUpdateRole                     func(in *models.Role, log *models.ComplianceAuditLog) error
+	OnDeleteRole                     func(roleID int64, log *models.ComplianceAuditLog) error
+	OnListPermissions                func() ([]*models.Permission, error)
 	OnListAPIKeys                    func(page *models.PageInfo) (*models.APIKeyPage, error)
 	OnCreateAPIKey                   func(in *models.APIKey, log *models.ComplianceAuditLog) error
 	OnRetrieveAPIKey                 func(clientIDOrKeyID any) (*models.APIKey, error)
 	OnUpdateAPIKey                   func(in *models.APIKey, log *models.ComplianceAuditLog) error
 	OnSetAPIKeyLastSeen              func(keyID ulid.ULID, lastSeen time.Time) error
+	OnRotateAPIKey                   func(key *models.APIKey, auditLog *models.ComplianceAuditLog) error
 	OnDeleteAPIKey                   func(keyID ulid.ULID, log *models.ComplianceAuditLog) error
+	OnRetrieveRevokedAPIKey          func(keyID ulid.ULID) (*models.RevokedAPIKey, error)
+	OnRecordAPIKeyUsage              func(keyID ulid.ULID, ip string, ts time.Time) error
+	OnListAPIKeyUsage                func(keyID ulid.ULID, since time.Time) ([]*models.APIKeyUsage, error)
 	OnListResetPasswordLinks         func(page *models.PageInfo) (*models.ResetPasswordLinkPage, error)
 	OnCreateResetPasswordLink        func(in *models.ResetPasswordLink) error
 	OnRetrieveResetPasswordLink      func(id ulid.ULID) (*models.ResetPasswordLink, error)
 	OnUpdateResetPasswordLink        func(in *models.ResetPasswordLink) error
 	OnDeleteResetPasswordLink        func(id ulid.ULID) error
+	OnListUserInvites                func() ([]*models.UserInvite, error)
+	OnCreateUserInvite               func(invite *models.UserInvite, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveUserInvite             func(inviteID ulid.ULID) (*models.UserInvite, error)
+	OnUpdateUserInvite               func(invite *models.UserInvite) error
+	OnDeleteUserInvite               func(inviteID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	OnAcceptUserInvite               func(inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) error
 	OnListComplianceAuditLogs        func(page *models.ComplianceAuditLogPageInfo) (*models.ComplianceAuditLogPage, error)
 	OnCreateComplianceAuditLog       func(log *models.ComplianceAuditLog) error
 	OnRetrieveComplianceAuditLog     func(id ulid.ULID) (*models.ComplianceAuditLog, error)
+	OnArchiveExpiredTransactions     func(before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeArchivedTransactions      func(before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnShredArchivedTransactions      func(before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnShredExpiredSecureEnvelopes    func(before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeExpiredSunrise            func(before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeExpiredAccounts           func(before time.Time, log *models.ComplianceAuditLog) (int64, error)
+	OnPurgeExpiredResetPasswordLinks func(before time.Time) (int64, error)
+	OnListLegalHolds                 func(page *models.LegalHoldPageInfo) (*models.LegalHoldPage, error)
+	OnCreateLegalHold                func(hold *models.LegalHold, log *models.ComplianceAuditLog) error
+	OnRetrieveLegalHold              func(id ulid.ULID) (*models.LegalHold, error)
+	OnUpdateLegalHold                func(hold *models.LegalHold, log *models.ComplianceAuditLog) error
+	OnReleaseLegalHold               func(id ulid.ULID, releasedBy string, log *models.ComplianceAuditLog) error
+	OnListApprovalRules              func() ([]*models.ApprovalRule, error)
+	OnCreateApprovalRule             func(rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveApprovalRule           func(ruleID ulid.ULID) (*models.ApprovalRule, error)
+	OnUpdateApprovalRule             func(rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error
+	OnDeleteApprovalRule             func(ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	OnListApprovals                  func(page *models.ApprovalPageInfo) (*models.ApprovalPage, error)
+	OnCreateApproval                 func(approval *models.Approval, auditLog *models.ComplianceAuditLog) error
+	OnRetrieveApproval               func(approvalID ulid.ULID) (*models.Approval, error)
+	OnClaimApproval                  func(approvalID ulid.ULID) error
+	OnReleaseApproval                func(approvalID ulid.ULID) error
+	OnReviewApproval                 func(approval *models.Approval, auditLog *models.ComplianceAuditLog) error
+	OnListApprovalReviewers          func() ([]*models.User, error)
+	OnListQueuedTransfers            func() ([]*models.QueuedTransfer, error)
+	OnListDueTransfers               func(before time.Time) ([]*models.QueuedTransfer, error)
+	OnCreateQueuedTransfer           func(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error
+	OnRetrieveQueuedTransfer         func(id ulid.ULID) (*models.QueuedTransfer, error)
+	OnUpdateQueuedTransfer           func(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error
 	OnListDaybreak                   func() (map[string]*models.CounterpartySourceInfo, error)
 	OnCreateDaybreak                 func(counterparty *models.Counterparty) error
 	OnUpdateDaybreak                 func(counterparty *models.Counterparty) error
@@ -342,6 +415,126 @@ func (tx *Tx) TransactionState(id uuid.UUID) (bool, enum.Status, error) {
 	panic("TransactionState callback not set")
 }
 
+// Calls the callback previously set with "OnListStatusHistory()".
+func (tx *Tx) ListStatusHistory(txID uuid.UUID) ([]*models.StatusTransition, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListStatusHistory != nil {
+		return tx.OnListStatusHistory(txID)
+	}
+	panic("ListStatusHistory callback not set")
+}
+
+// Calls the callback previously set with "OnListTransactionNotes()".
+func (tx *Tx) ListTransactionNotes(txID uuid.UUID) ([]*models.TransactionNote, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListTransactionNotes != nil {
+		return tx.OnListTransactionNotes(txID)
+	}
+	panic("ListTransactionNotes callback not set")
+}
+
+// Calls the callback previously set with "OnCreateTransactionNote()".
+func (tx *Tx) CreateTransactionNote(note *models.TransactionNote, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateTransactionNote != nil {
+		return tx.OnCreateTransactionNote(note, auditLog)
+	}
+	panic("CreateTransactionNote callback not set")
+}
+
+// Calls the callback previously set with "OnAssignTransaction()".
+func (tx *Tx) AssignTransaction(txID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnAssignTransaction != nil {
+		return tx.OnAssignTransaction(txID, assigneeID, auditLog)
+	}
+	panic("AssignTransaction callback not set")
+}
+
+// Calls the callback previously set with "OnListTransactionTags()".
+func (tx *Tx) ListTransactionTags() ([]string, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListTransactionTags != nil {
+		return tx.OnListTransactionTags()
+	}
+	panic("ListTransactionTags callback not set")
+}
+
+// Calls the callback previously set with "OnSetTransactionTags()".
+func (tx *Tx) SetTransactionTags(txID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnSetTransactionTags != nil {
+		return tx.OnSetTransactionTags(txID, tags, auditLog)
+	}
+	panic("SetTransactionTags callback not set")
+}
+
+// Calls the callback previously set with "OnListReplyDeadlines()".
+func (tx *Tx) ListReplyDeadlines(dueBefore time.Time) ([]*models.Transaction, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListReplyDeadlines != nil {
+		return tx.OnListReplyDeadlines(dueBefore)
+	}
+	panic("ListReplyDeadlines callback not set")
+}
+
+// Calls the callback previously set with "OnMarkReplyReminded()".
+func (tx *Tx) MarkReplyReminded(txID uuid.UUID, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnMarkReplyReminded != nil {
+		return tx.OnMarkReplyReminded(txID, auditLog)
+	}
+	panic("MarkReplyReminded callback not set")
+}
+
+// Calls the callback previously set with "OnMarkReplyExpired()".
+func (tx *Tx) MarkReplyExpired(txID uuid.UUID, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnMarkReplyExpired != nil {
+		return tx.OnMarkReplyExpired(txID, auditLog)
+	}
+	panic("MarkReplyExpired callback not set")
+}
+
+// Calls the callback previously set with "OnListTransactionReviewers()".
+func (tx *Tx) ListTransactionReviewers() ([]*models.User, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListTransactionReviewers != nil {
+		return tx.OnListTransactionReviewers()
+	}
+	panic("ListTransactionReviewers callback not set")
+}
+
 //===========================================================================
 // SecureEnvelope Interface Methods
 //===========================================================================
@@ -671,6 +864,18 @@ func (tx *Tx) UpdateCounterparty(in *models.Counterparty, log *models.Compliance
 	panic("UpdateCounterparty callback not set")
 }
 
+// Calls the callback previously set with "OnUpdateCounterpartyPending()".
+func (tx *Tx) UpdateCounterpartyPending(counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUpdateCounterpartyPending != nil {
+		return tx.OnUpdateCounterpartyPending(counterpartyID, overrides, auditLog)
+	}
+	panic("UpdateCounterpartyPending callback not set")
+}
+
 // Calls the callback previously set with "OnDeleteCounterparty()".
 func (tx *Tx) DeleteCounterparty(counterpartyID ulid.ULID, log *models.ComplianceAuditLog) error {
 	if err := tx.check(true); err != nil {
@@ -912,6 +1117,102 @@ func (tx *Tx) SetUserLastLogin(userID ulid.ULID, lastLogin time.Time) error {
 	panic("SetUserLastLogin callback not set")
 }
 
+// Calls the callback previously set with "OnRecordUserLoginFailure()".
+func (tx *Tx) RecordUserLoginFailure(userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnRecordUserLoginFailure != nil {
+		return tx.OnRecordUserLoginFailure(userID, maxFailures, lockout, maxLockout)
+	}
+	panic("RecordUserLoginFailure callback not set")
+}
+
+// Calls the callback previously set with "OnResetUserLoginFailures()".
+func (tx *Tx) ResetUserLoginFailures(userID ulid.ULID) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnResetUserLoginFailures != nil {
+		return tx.OnResetUserLoginFailures(userID)
+	}
+	panic("ResetUserLoginFailures callback not set")
+}
+
+// Calls the callback previously set with "OnUnlockUser()".
+func (tx *Tx) UnlockUser(userID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUnlockUser != nil {
+		return tx.OnUnlockUser(userID, auditLog)
+	}
+	panic("UnlockUser callback not set")
+}
+
+// Calls the callback previously set with "OnListUserSessions()".
+func (tx *Tx) ListUserSessions(userID ulid.ULID) ([]*models.UserSession, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListUserSessions != nil {
+		return tx.OnListUserSessions(userID)
+	}
+	panic("ListUserSessions callback not set")
+}
+
+// Calls the callback previously set with "OnCreateUserSession()".
+func (tx *Tx) CreateUserSession(session *models.UserSession) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateUserSession != nil {
+		return tx.OnCreateUserSession(session)
+	}
+	panic("CreateUserSession callback not set")
+}
+
+// Calls the callback previously set with "OnRefreshUserSession()".
+func (tx *Tx) RefreshUserSession(userID, sessionID ulid.ULID, expires time.Time) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnRefreshUserSession != nil {
+		return tx.OnRefreshUserSession(userID, sessionID, expires)
+	}
+	panic("RefreshUserSession callback not set")
+}
+
+// Calls the callback previously set with "OnRevokeUserSession()".
+func (tx *Tx) RevokeUserSession(userID, sessionID ulid.ULID) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnRevokeUserSession != nil {
+		return tx.OnRevokeUserSession(userID, sessionID)
+	}
+	panic("RevokeUserSession callback not set")
+}
+
+// Calls the callback previously set with "OnRevokeUserSessions()".
+func (tx *Tx) RevokeUserSessions(userID, keep ulid.ULID) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnRevokeUserSessions != nil {
+		return tx.OnRevokeUserSessions(userID, keep)
+	}
+	panic("RevokeUserSessions callback not set")
+}
+
 // Calls the callback previously set with "OnDeleteUser()".
 func (tx *Tx) DeleteUser(userID ulid.ULID, log *models.ComplianceAuditLog) error {
 	if err := tx.check(true); err != nil {
@@ -924,6 +1225,30 @@ func (tx *Tx) DeleteUser(userID ulid.ULID, log *models.ComplianceAuditLog) error
 	panic("DeleteUser callback not set")
 }
 
+// Calls the callback previously set with "OnRetrieveRevokedUser()".
+func (tx *Tx) RetrieveRevokedUser(userID ulid.ULID) (*models.RevokedUser, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveRevokedUser != nil {
+		return tx.OnRetrieveRevokedUser(userID)
+	}
+	panic("RetrieveRevokedUser callback not set")
+}
+
+// Calls the callback previously set with "OnListRoles()".
+func (tx *Tx) ListRoles() ([]*models.Role, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListRoles != nil {
+		return tx.OnListRoles()
+	}
+	panic("ListRoles callback not set")
+}
+
 // Calls the callback previously set with "OnLookupRole()".
 func (tx *Tx) LookupRole(role string) (*models.Role, error) {
 	if err := tx.check(false); err != nil {
@@ -936,6 +1261,162 @@ func (tx *Tx) LookupRole(role string) (*models.Role, error) {
 	panic("LookupRole callback not set")
 }
 
+// Calls the callback previously set with "OnSetUserMFASecret()".
+func (tx *Tx) SetUserMFASecret(userID ulid.ULID, secret string) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnSetUserMFASecret != nil {
+		return tx.OnSetUserMFASecret(userID, secret)
+	}
+	panic("SetUserMFASecret callback not set")
+}
+
+// Calls the callback previously set with "OnEnableUserMFA()".
+func (tx *Tx) EnableUserMFA(userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnEnableUserMFA != nil {
+		return tx.OnEnableUserMFA(userID, recoveryCodes, log)
+	}
+	panic("EnableUserMFA callback not set")
+}
+
+// Calls the callback previously set with "OnSetUserRecoveryCodes()".
+func (tx *Tx) SetUserRecoveryCodes(userID ulid.ULID, recoveryCodes []string, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnSetUserRecoveryCodes != nil {
+		return tx.OnSetUserRecoveryCodes(userID, recoveryCodes, log)
+	}
+	panic("SetUserRecoveryCodes callback not set")
+}
+
+// Calls the callback previously set with "OnUseUserRecoveryCode()".
+func (tx *Tx) UseUserRecoveryCode(userID ulid.ULID, recoveryCode string) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUseUserRecoveryCode != nil {
+		return tx.OnUseUserRecoveryCode(userID, recoveryCode)
+	}
+	panic("UseUserRecoveryCode callback not set")
+}
+
+// Calls the callback previously set with "OnSetUserMFAStep()".
+func (tx *Tx) SetUserMFAStep(userID ulid.ULID, step int64) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnSetUserMFAStep != nil {
+		return tx.OnSetUserMFAStep(userID, step)
+	}
+	panic("SetUserMFAStep callback not set")
+}
+
+// Calls the callback previously set with "OnRecordUserMFAFailure()".
+func (tx *Tx) RecordUserMFAFailure(userID ulid.ULID, maxFailures int64, lockout time.Duration) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnRecordUserMFAFailure != nil {
+		return tx.OnRecordUserMFAFailure(userID, maxFailures, lockout)
+	}
+	panic("RecordUserMFAFailure callback not set")
+}
+
+// Calls the callback previously set with "OnResetUserMFA()".
+func (tx *Tx) ResetUserMFA(userID ulid.ULID, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnResetUserMFA != nil {
+		return tx.OnResetUserMFA(userID, log)
+	}
+	panic("ResetUserMFA callback not set")
+}
+
+// Calls the callback previously set with "OnSetRoleMFARequired()".
+func (tx *Tx) SetRoleMFARequired(roleID int64, required bool, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnSetRoleMFARequired != nil {
+		return tx.OnSetRoleMFARequired(roleID, required, log)
+	}
+	panic("SetRoleMFARequired callback not set")
+}
+
+// Calls the callback previously set with "OnCreateRole()".
+func (tx *Tx) CreateRole(in *models.Role, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateRole != nil {
+		return tx.OnCreateRole(in, log)
+	}
+	panic("CreateRole callback not set")
+}
+
+// Calls the callback previously set with "OnRetrieveRole()".
+func (tx *Tx) RetrieveRole(roleID int64) (*models.Role, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveRole != nil {
+		return tx.OnRetrieveRole(roleID)
+	}
+	panic("RetrieveRole callback not set")
+}
+
+// Calls the callback previously set with "OnUpdateRole()".
+func (tx *Tx) UpdateRole(in *models.Role, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUpdateRole != nil {
+		return tx.OnUpdateRole(in, log)
+	}
+	panic("UpdateRole callback not set")
+}
+
+// Calls the callback previously set with "OnDeleteRole()".
+func (tx *Tx) DeleteRole(roleID int64, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnDeleteRole != nil {
+		return tx.OnDeleteRole(roleID, log)
+	}
+	panic("DeleteRole callback not set")
+}
+
+// Calls the callback previously set with "OnListPermissions()".
+func (tx *Tx) ListPermissions() ([]*models.Permission, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListPermissions != nil {
+		return tx.OnListPermissions()
+	}
+	panic("ListPermissions callback not set")
+}
+
 //===========================================================================
 // APIKey Interface Methods
 //===========================================================================
@@ -1000,6 +1481,18 @@ func (tx *Tx) SetAPIKeyLastSeen(keyID ulid.ULID, lastSeen time.Time) error {
 	panic("SetAPIKeyLastSeen callback not set")
 }
 
+// Calls the callback previously set with "OnRotateAPIKey()".
+func (tx *Tx) RotateAPIKey(key *models.APIKey, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnRotateAPIKey != nil {
+		return tx.OnRotateAPIKey(key, auditLog)
+	}
+	panic("RotateAPIKey callback not set")
+}
+
 // Calls the callback previously set with "OnDeleteAPIKey()".
 func (tx *Tx) DeleteAPIKey(keyID ulid.ULID, log *models.ComplianceAuditLog) error {
 	if err := tx.check(true); err != nil {
@@ -1012,6 +1505,42 @@ func (tx *Tx) DeleteAPIKey(keyID ulid.ULID, log *models.ComplianceAuditLog) erro
 	panic("DeleteAPIKey callback not set")
 }
 
+// Calls the callback previously set with "OnRetrieveRevokedAPIKey()".
+func (tx *Tx) RetrieveRevokedAPIKey(keyID ulid.ULID) (*models.RevokedAPIKey, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveRevokedAPIKey != nil {
+		return tx.OnRetrieveRevokedAPIKey(keyID)
+	}
+	panic("RetrieveRevokedAPIKey callback not set")
+}
+
+// Calls the callback previously set with "OnRecordAPIKeyUsage()".
+func (tx *Tx) RecordAPIKeyUsage(keyID ulid.ULID, ip string, ts time.Time) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnRecordAPIKeyUsage != nil {
+		return tx.OnRecordAPIKeyUsage(keyID, ip, ts)
+	}
+	panic("RecordAPIKeyUsage callback not set")
+}
+
+// Calls the callback previously set with "OnListAPIKeyUsage()".
+func (tx *Tx) ListAPIKeyUsage(keyID ulid.ULID, since time.Time) ([]*models.APIKeyUsage, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListAPIKeyUsage != nil {
+		return tx.OnListAPIKeyUsage(keyID, since)
+	}
+	panic("ListAPIKeyUsage callback not set")
+}
+
 //===========================================================================
 // ResetPasswordLink Interface Methods
 //===========================================================================
@@ -1076,6 +1605,78 @@ func (tx *Tx) DeleteResetPasswordLink(id ulid.ULID) error {
 	panic("DeleteResetPasswordLink callback not set")
 }
 
+// Calls the callback previously set with "OnListUserInvites()".
+func (tx *Tx) ListUserInvites() ([]*models.UserInvite, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListUserInvites != nil {
+		return tx.OnListUserInvites()
+	}
+	panic("ListUserInvites callback not set")
+}
+
+// Calls the callback previously set with "OnCreateUserInvite()".
+func (tx *Tx) CreateUserInvite(invite *models.UserInvite, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateUserInvite != nil {
+		return tx.OnCreateUserInvite(invite, auditLog)
+	}
+	panic("CreateUserInvite callback not set")
+}
+
+// Calls the callback previously set with "OnRetrieveUserInvite()".
+func (tx *Tx) RetrieveUserInvite(inviteID ulid.ULID) (*models.UserInvite, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveUserInvite != nil {
+		return tx.OnRetrieveUserInvite(inviteID)
+	}
+	panic("RetrieveUserInvite callback not set")
+}
+
+// Calls the callback previously set with "OnUpdateUserInvite()".
+func (tx *Tx) UpdateUserInvite(invite *models.UserInvite) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUpdateUserInvite != nil {
+		return tx.OnUpdateUserInvite(invite)
+	}
+	panic("UpdateUserInvite callback not set")
+}
+
+// Calls the callback previously set with "OnDeleteUserInvite()".
+func (tx *Tx) DeleteUserInvite(inviteID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnDeleteUserInvite != nil {
+		return tx.OnDeleteUserInvite(inviteID, auditLog)
+	}
+	panic("DeleteUserInvite callback not set")
+}
+
+// Calls the callback previously set with "OnAcceptUserInvite()".
+func (tx *Tx) AcceptUserInvite(inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnAcceptUserInvite != nil {
+		return tx.OnAcceptUserInvite(inviteID, user, auditLog)
+	}
+	panic("AcceptUserInvite callback not set")
+}
+
 //===========================================================================
 // Compliance Audit Log Store Methods
 //===========================================================================
@@ -1116,6 +1717,94 @@ func (tx *Tx) RetrieveComplianceAuditLog(id ulid.ULID) (*models.ComplianceAuditL
 	panic("RetrieveComplianceAuditLog callback not set")
 }
 
+//===========================================================================
+// Retention Store Methods
+//===========================================================================
+
+// Calls the callback previously set with "OnArchiveExpiredTransactions()".
+func (tx *Tx) ArchiveExpiredTransactions(before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	if err := tx.check(true); err != nil {
+		return 0, err
+	}
+
+	if tx.OnArchiveExpiredTransactions != nil {
+		return tx.OnArchiveExpiredTransactions(before, log)
+	}
+	panic("ArchiveExpiredTransactions callback not set")
+}
+
+// Calls the callback previously set with "OnPurgeArchivedTransactions()".
+func (tx *Tx) PurgeArchivedTransactions(before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	if err := tx.check(true); err != nil {
+		return 0, err
+	}
+
+	if tx.OnPurgeArchivedTransactions != nil {
+		return tx.OnPurgeArchivedTransactions(before, log)
+	}
+	panic("PurgeArchivedTransactions callback not set")
+}
+
+// Calls the callback previously set with "OnShredArchivedTransactions()".
+func (tx *Tx) ShredArchivedTransactions(before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	if err := tx.check(true); err != nil {
+		return 0, err
+	}
+
+	if tx.OnShredArchivedTransactions != nil {
+		return tx.OnShredArchivedTransactions(before, log)
+	}
+	panic("ShredArchivedTransactions callback not set")
+}
+
+// Calls the callback previously set with "OnShredExpiredSecureEnvelopes()".
+func (tx *Tx) ShredExpiredSecureEnvelopes(before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	if err := tx.check(true); err != nil {
+		return 0, err
+	}
+
+	if tx.OnShredExpiredSecureEnvelopes != nil {
+		return tx.OnShredExpiredSecureEnvelopes(before, log)
+	}
+	panic("ShredExpiredSecureEnvelopes callback not set")
+}
+
+// Calls the callback previously set with "OnPurgeExpiredSunrise()".
+func (tx *Tx) PurgeExpiredSunrise(before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	if err := tx.check(true); err != nil {
+		return 0, err
+	}
+
+	if tx.OnPurgeExpiredSunrise != nil {
+		return tx.OnPurgeExpiredSunrise(before, log)
+	}
+	panic("PurgeExpiredSunrise callback not set")
+}
+
+// Calls the callback previously set with "OnPurgeExpiredAccounts()".
+func (tx *Tx) PurgeExpiredAccounts(before time.Time, log *models.ComplianceAuditLog) (int64, error) {
+	if err := tx.check(true); err != nil {
+		return 0, err
+	}
+
+	if tx.OnPurgeExpiredAccounts != nil {
+		return tx.OnPurgeExpiredAccounts(before, log)
+	}
+	panic("PurgeExpiredAccounts callback not set")
+}
+
+// Calls the callback previously set with "OnPurgeExpiredResetPasswordLinks()".
+func (tx *Tx) PurgeExpiredResetPasswordLinks(before time.Time) (int64, error) {
+	if err := tx.check(true); err != nil {
+		return 0, err
+	}
+
+	if tx.OnPurgeExpiredResetPasswordLinks != nil {
+		return tx.OnPurgeExpiredResetPasswordLinks(before)
+	}
+	panic("PurgeExpiredResetPasswordLinks callback not set")
+}
+
 //===========================================================================
 // Daybreak Interface Methods
 //===========================================================================
@@ -1167,3 +1856,271 @@ func (tx *Tx) DeleteDaybreak(counterpartyID ulid.ULID, ignoreTxns bool) error {
 	}
 	panic("DeleteDaybreak callback not set")
 }
+
+//===========================================================================
+// Legal Hold Store Methods
+//===========================================================================
+
+// Calls the callback previously set with "OnListLegalHolds()".
+func (tx *Tx) ListLegalHolds(page *models.LegalHoldPageInfo) (*models.LegalHoldPage, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListLegalHolds != nil {
+		return tx.OnListLegalHolds(page)
+	}
+	panic("ListLegalHolds callback not set")
+}
+
+// Calls the callback previously set with "OnCreateLegalHold()".
+func (tx *Tx) CreateLegalHold(hold *models.LegalHold, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateLegalHold != nil {
+		return tx.OnCreateLegalHold(hold, log)
+	}
+	panic("CreateLegalHold callback not set")
+}
+
+// Calls the callback previously set with "OnRetrieveLegalHold()".
+func (tx *Tx) RetrieveLegalHold(id ulid.ULID) (*models.LegalHold, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveLegalHold != nil {
+		return tx.OnRetrieveLegalHold(id)
+	}
+	panic("RetrieveLegalHold callback not set")
+}
+
+// Calls the callback previously set with "OnUpdateLegalHold()".
+func (tx *Tx) UpdateLegalHold(hold *models.LegalHold, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUpdateLegalHold != nil {
+		return tx.OnUpdateLegalHold(hold, log)
+	}
+	panic("UpdateLegalHold callback not set")
+}
+
+// Calls the callback previously set with "OnReleaseLegalHold()".
+func (tx *Tx) ReleaseLegalHold(id ulid.ULID, releasedBy string, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnReleaseLegalHold != nil {
+		return tx.OnReleaseLegalHold(id, releasedBy, log)
+	}
+	panic("ReleaseLegalHold callback not set")
+}
+
+// Calls the callback previously set with "OnListApprovalRules()".
+func (tx *Tx) ListApprovalRules() ([]*models.ApprovalRule, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListApprovalRules != nil {
+		return tx.OnListApprovalRules()
+	}
+	panic("ListApprovalRules callback not set")
+}
+
+// Calls the callback previously set with "OnCreateApprovalRule()".
+func (tx *Tx) CreateApprovalRule(rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateApprovalRule != nil {
+		return tx.OnCreateApprovalRule(rule, auditLog)
+	}
+	panic("CreateApprovalRule callback not set")
+}
+
+// Calls the callback previously set with "OnRetrieveApprovalRule()".
+func (tx *Tx) RetrieveApprovalRule(ruleID ulid.ULID) (*models.ApprovalRule, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveApprovalRule != nil {
+		return tx.OnRetrieveApprovalRule(ruleID)
+	}
+	panic("RetrieveApprovalRule callback not set")
+}
+
+// Calls the callback previously set with "OnUpdateApprovalRule()".
+func (tx *Tx) UpdateApprovalRule(rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUpdateApprovalRule != nil {
+		return tx.OnUpdateApprovalRule(rule, auditLog)
+	}
+	panic("UpdateApprovalRule callback not set")
+}
+
+// Calls the callback previously set with "OnDeleteApprovalRule()".
+func (tx *Tx) DeleteApprovalRule(ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnDeleteApprovalRule != nil {
+		return tx.OnDeleteApprovalRule(ruleID, auditLog)
+	}
+	panic("DeleteApprovalRule callback not set")
+}
+
+// Calls the callback previously set with "OnListApprovals()".
+func (tx *Tx) ListApprovals(page *models.ApprovalPageInfo) (*models.ApprovalPage, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListApprovals != nil {
+		return tx.OnListApprovals(page)
+	}
+	panic("ListApprovals callback not set")
+}
+
+// Calls the callback previously set with "OnCreateApproval()".
+func (tx *Tx) CreateApproval(approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateApproval != nil {
+		return tx.OnCreateApproval(approval, auditLog)
+	}
+	panic("CreateApproval callback not set")
+}
+
+// Calls the callback previously set with "OnRetrieveApproval()".
+func (tx *Tx) RetrieveApproval(approvalID ulid.ULID) (*models.Approval, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveApproval != nil {
+		return tx.OnRetrieveApproval(approvalID)
+	}
+	panic("RetrieveApproval callback not set")
+}
+
+// Calls the callback previously set with "OnClaimApproval()".
+func (tx *Tx) ClaimApproval(approvalID ulid.ULID) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnClaimApproval != nil {
+		return tx.OnClaimApproval(approvalID)
+	}
+	panic("ClaimApproval callback not set")
+}
+
+// Calls the callback previously set with "OnReleaseApproval()".
+func (tx *Tx) ReleaseApproval(approvalID ulid.ULID) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnReleaseApproval != nil {
+		return tx.OnReleaseApproval(approvalID)
+	}
+	panic("ReleaseApproval callback not set")
+}
+
+// Calls the callback previously set with "OnReviewApproval()".
+func (tx *Tx) ReviewApproval(approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnReviewApproval != nil {
+		return tx.OnReviewApproval(approval, auditLog)
+	}
+	panic("ReviewApproval callback not set")
+}
+
+// Calls the callback previously set with "OnListApprovalReviewers()".
+func (tx *Tx) ListApprovalReviewers() ([]*models.User, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListApprovalReviewers != nil {
+		return tx.OnListApprovalReviewers()
+	}
+	panic("ListApprovalReviewers callback not set")
+}
+
+// Calls the callback previously set with "OnListQueuedTransfers()".
+func (tx *Tx) ListQueuedTransfers() ([]*models.QueuedTransfer, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListQueuedTransfers != nil {
+		return tx.OnListQueuedTransfers()
+	}
+	panic("ListQueuedTransfers callback not set")
+}
+
+// Calls the callback previously set with "OnListDueTransfers()".
+func (tx *Tx) ListDueTransfers(before time.Time) ([]*models.QueuedTransfer, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnListDueTransfers != nil {
+		return tx.OnListDueTransfers(before)
+	}
+	panic("ListDueTransfers callback not set")
+}
+
+// Calls the callback previously set with "OnCreateQueuedTransfer()".
+func (tx *Tx) CreateQueuedTransfer(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnCreateQueuedTransfer != nil {
+		return tx.OnCreateQueuedTransfer(transfer, log)
+	}
+	panic("CreateQueuedTransfer callback not set")
+}
+
+// Calls the callback previously set with "OnRetrieveQueuedTransfer()".
+func (tx *Tx) RetrieveQueuedTransfer(id ulid.ULID) (*models.QueuedTransfer, error) {
+	if err := tx.check(false); err != nil {
+		return nil, err
+	}
+
+	if tx.OnRetrieveQueuedTransfer != nil {
+		return tx.OnRetrieveQueuedTransfer(id)
+	}
+	panic("RetrieveQueuedTransfer callback not set")
+}
+
+// Calls the callback previously set with "OnUpdateQueuedTransfer()".
+func (tx *Tx) UpdateQueuedTransfer(transfer *models.QueuedTransfer, log *models.ComplianceAuditLog) error {
+	if err := tx.check(true); err != nil {
+		return err
+	}
+
+	if tx.OnUpdateQueuedTransfer != nil {
+		return tx.OnUpdateQueuedTransfer(transfer, log)
+	}
+	panic("UpdateQueuedTransfer callback not set")
+}
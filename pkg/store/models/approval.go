@@ -0,0 +1,199 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"go.rtnl.ai/ulid"
+)
+
+// ###########################################################################
+// ApprovalRule
+// ###########################################################################
+
+// ApprovalRule defines the threshold above which a transfer workflow action requires
+// approval by a second user (four-eyes or maker-checker). Each criteria of the rule is
+// optional; an unset criteria matches any value. An action matches the rule if all of
+// the set criteria match the action.
+type ApprovalRule struct {
+	Model
+	Description    string              // a human readable description of the rule
+	Action         enum.ApprovalAction // the action the rule applies to; unknown matches any action
+	VirtualAsset   sql.NullString      // the network or asset type the rule applies to (case-insensitive)
+	MinAmount      sql.NullFloat64     // the rule applies to amounts greater than or equal to the minimum
+	CounterpartyID ulid.NullULID       // the counterparty the rule applies to
+}
+
+// Scan a complete SELECT into the approval rule model
+func (r *ApprovalRule) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&r.ID,
+		&r.Description,
+		&r.Action,
+		&r.VirtualAsset,
+		&r.MinAmount,
+		&r.CounterpartyID,
+		&r.Created,
+		&r.Modified,
+	)
+}
+
+// Get the complete named params of the approval rule from the model.
+func (r *ApprovalRule) Params() []any {
+	// An unknown action is stored as NULL so that it matches any action
+	var action any
+	if r.Action != enum.ApprovalActionUnknown {
+		action = r.Action
+	}
+
+	return []any{
+		sql.Named("id", r.ID),
+		sql.Named("description", r.Description),
+		sql.Named("action", action),
+		sql.Named("virtualAsset", r.VirtualAsset),
+		sql.Named("minAmount", r.MinAmount),
+		sql.Named("counterpartyID", r.CounterpartyID),
+		sql.Named("created", r.Created),
+		sql.Named("modified", r.Modified),
+	}
+}
+
+// Matches returns true if the action, virtual asset, amount, and counterparty of the
+// approval meet all of the criteria of the rule.
+func (r *ApprovalRule) Matches(approval *Approval) bool {
+	if r.Action != enum.ApprovalActionUnknown && r.Action != approval.Action {
+		return false
+	}
+
+	if r.VirtualAsset.Valid && !MatchVirtualAsset(r.VirtualAsset.String, approval.VirtualAsset.String) {
+		return false
+	}
+
+	if r.MinAmount.Valid && (!approval.Amount.Valid || approval.Amount.Float64 < r.MinAmount.Float64) {
+		return false
+	}
+
+	if r.CounterpartyID.Valid && (!approval.CounterpartyID.Valid || r.CounterpartyID.ULID.Compare(approval.CounterpartyID.ULID) != 0) {
+		return false
+	}
+
+	return true
+}
+
+// MatchVirtualAsset compares the virtual asset criteria of a rule to the virtual asset
+// of a transaction. Transactions represent the virtual asset as the network, the asset
+// type, or as "network (asset type)", so the criteria matches (case-insensitive) if it
+// is equal to the entire representation, the network, or the asset type.
+func MatchVirtualAsset(criteria, asset string) bool {
+	criteria = strings.TrimSpace(criteria)
+	asset = strings.TrimSpace(asset)
+
+	if strings.EqualFold(criteria, asset) {
+		return true
+	}
+
+	if network, assetType, ok := strings.Cut(asset, " ("); ok {
+		assetType = strings.TrimSuffix(assetType, ")")
+		return strings.EqualFold(criteria, network) || strings.EqualFold(criteria, assetType)
+	}
+
+	return false
+}
+
+// ###########################################################################
+// Approval
+// ###########################################################################
+
+// Approval holds a transfer workflow action (sending a prepared transaction or
+// accepting, rejecting, or completing a transaction) that matched an approval rule
+// until a second user approves it, at which point the action is executed. The original
+// request is stored so that the action can be executed exactly as it was requested.
+// Approvals are not deleted so that the history of the review is preserved.
+type Approval struct {
+	Model
+	Action          enum.ApprovalAction // the transfer workflow action that requires approval
+	Status          enum.ApprovalStatus // one of pending, executing, approved, denied, or canceled
+	RuleID          ulid.NullULID       // the rule that required the approval (null if the rule was deleted)
+	TransactionID   uuid.NullUUID       // the transaction of the action; set on approval when sending a prepared transaction
+	Counterparty    sql.NullString      // the name of the counterparty of the transfer
+	CounterpartyID  ulid.NullULID       // a reference to the counterparty in the database, if any
+	VirtualAsset    sql.NullString      // a representation of the network/asset type of the transfer
+	Amount          sql.NullFloat64     // the amount of the transfer
+	Request         []byte              // the JSON request of the action to execute when approved
+	RequestedBy     string              // the name of the user or api key that requested the action
+	RequestedByID   []byte              // the actor id of the user or api key that requested the action
+	RequestedByType enum.Actor          // the type of actor that requested the action
+	ReviewedBy      sql.NullString      // the name of the user that approved, denied, or canceled the action
+	ReviewedByID    []byte              // the actor id of the user that reviewed the action
+	ReviewNotes     sql.NullString      // optional notes about the decision of the reviewer
+	ReviewedOn      sql.NullTime        // the timestamp the approval was reviewed
+}
+
+type ApprovalPageInfo struct {
+	PageInfo
+	Status        string `json:"status,omitempty"`
+	TransactionID string `json:"transaction_id,omitempty"`
+}
+
+// Scan a complete SELECT into the approval model
+func (a *Approval) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&a.ID,
+		&a.Action,
+		&a.Status,
+		&a.RuleID,
+		&a.TransactionID,
+		&a.Counterparty,
+		&a.CounterpartyID,
+		&a.VirtualAsset,
+		&a.Amount,
+		&a.Request,
+		&a.RequestedBy,
+		&a.RequestedByID,
+		&a.RequestedByType,
+		&a.ReviewedBy,
+		&a.ReviewedByID,
+		&a.ReviewNotes,
+		&a.ReviewedOn,
+		&a.Created,
+		&a.Modified,
+	)
+}
+
+// Get the complete named params of the approval from the model.
+func (a *Approval) Params() []any {
+	return []any{
+		sql.Named("id", a.ID),
+		sql.Named("action", a.Action),
+		sql.Named("status", a.Status),
+		sql.Named("ruleID", a.RuleID),
+		sql.Named("transactionID", a.TransactionID),
+		sql.Named("counterparty", a.Counterparty),
+		sql.Named("counterpartyID", a.CounterpartyID),
+		sql.Named("virtualAsset", a.VirtualAsset),
+		sql.Named("amount", a.Amount),
+		sql.Named("request", a.Request),
+		sql.Named("requestedBy", a.RequestedBy),
+		sql.Named("requestedByID", a.RequestedByID),
+		sql.Named("requestedByType", a.RequestedByType),
+		sql.Named("reviewedBy", a.ReviewedBy),
+		sql.Named("reviewedByID", a.ReviewedByID),
+		sql.Named("reviewNotes", a.ReviewNotes),
+		sql.Named("reviewedOn", a.ReviewedOn),
+		sql.Named("created", a.Created),
+		sql.Named("modified", a.Modified),
+	}
+}
+
+// Returns true if the approval has not yet been approved, denied, or canceled.
+func (a *Approval) IsPending() bool {
+	return a.Status == enum.ApprovalPending
+}
+
+// Returns true if the approval has been claimed by a reviewer and the approved action
+// is being executed.
+func (a *Approval) IsExecuting() bool {
+	return a.Status == enum.ApprovalExecuting
+}
@@ -0,0 +1,92 @@
+package models_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func TestApprovalRuleMatches(t *testing.T) {
+	counterpartyID := ulid.MustParse("01JB7T6Y4KX8QS0R6V3M2N9P5D")
+	otherID := ulid.MustParse("01JB7T7F9C2D5E8G1H4J7K0M3N")
+
+	approval := &models.Approval{
+		Action:         enum.ApprovalActionSend,
+		VirtualAsset:   sql.NullString{Valid: true, String: "Bitcoin (BTC)"},
+		Amount:         sql.NullFloat64{Valid: true, Float64: 1.5},
+		CounterpartyID: ulid.NullULID{Valid: true, ULID: counterpartyID},
+	}
+
+	testCases := []struct {
+		rule     *models.ApprovalRule
+		expected bool
+	}{
+		{&models.ApprovalRule{}, true},
+		{&models.ApprovalRule{Action: enum.ApprovalActionSend}, true},
+		{&models.ApprovalRule{Action: enum.ApprovalActionAccept}, false},
+		{&models.ApprovalRule{VirtualAsset: sql.NullString{Valid: true, String: "btc"}}, true},
+		{&models.ApprovalRule{VirtualAsset: sql.NullString{Valid: true, String: "bitcoin"}}, true},
+		{&models.ApprovalRule{VirtualAsset: sql.NullString{Valid: true, String: "Bitcoin (BTC)"}}, true},
+		{&models.ApprovalRule{VirtualAsset: sql.NullString{Valid: true, String: "ETH"}}, false},
+		{&models.ApprovalRule{MinAmount: sql.NullFloat64{Valid: true, Float64: 1.5}}, true},
+		{&models.ApprovalRule{MinAmount: sql.NullFloat64{Valid: true, Float64: 1.0}}, true},
+		{&models.ApprovalRule{MinAmount: sql.NullFloat64{Valid: true, Float64: 2.0}}, false},
+		{&models.ApprovalRule{CounterpartyID: ulid.NullULID{Valid: true, ULID: counterpartyID}}, true},
+		{&models.ApprovalRule{CounterpartyID: ulid.NullULID{Valid: true, ULID: otherID}}, false},
+		{
+			&models.ApprovalRule{
+				Action:         enum.ApprovalActionSend,
+				VirtualAsset:   sql.NullString{Valid: true, String: "BTC"},
+				MinAmount:      sql.NullFloat64{Valid: true, Float64: 1.0},
+				CounterpartyID: ulid.NullULID{Valid: true, ULID: counterpartyID},
+			},
+			true,
+		},
+		{
+			&models.ApprovalRule{
+				Action:    enum.ApprovalActionSend,
+				MinAmount: sql.NullFloat64{Valid: true, Float64: 10.0},
+			},
+			false,
+		},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, tc.rule.Matches(approval), "test case %d failed", i)
+	}
+
+	t.Run("Unknown", func(t *testing.T) {
+		// An approval without an amount or counterparty does not match rules that
+		// require them but does match rules that do not.
+		approval := &models.Approval{Action: enum.ApprovalActionComplete}
+		require.True(t, (&models.ApprovalRule{}).Matches(approval))
+		require.False(t, (&models.ApprovalRule{MinAmount: sql.NullFloat64{Valid: true}}).Matches(approval))
+		require.False(t, (&models.ApprovalRule{CounterpartyID: ulid.NullULID{Valid: true, ULID: counterpartyID}}).Matches(approval))
+	})
+}
+
+func TestMatchVirtualAsset(t *testing.T) {
+	testCases := []struct {
+		criteria string
+		asset    string
+		expected bool
+	}{
+		{"BTC", "BTC", true},
+		{"btc", "BTC", true},
+		{"BTC", "Bitcoin (BTC)", true},
+		{"bitcoin", "Bitcoin (BTC)", true},
+		{"Bitcoin (BTC)", "bitcoin (btc)", true},
+		{" ETH ", "ETH", true},
+		{"ETH", "Bitcoin (BTC)", false},
+		{"BTC", "", false},
+		{"BT", "BTC", false},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, models.MatchVirtualAsset(tc.criteria, tc.asset), "test case %d failed", i)
+	}
+}
@@ -158,16 +158,16 @@ func (l *ComplianceAuditLog) Params() []any {
 // Options for listing ComplianceAuditLog objects from the store interface.
 // ResourceTypes and ResourceID are mutually exclusive, as well as ActorTypes
 // and ActorID; if both of either pair are provided in an object, then only the
-// ID field(s) will be used to filter the result. After and Before may be used
-// with any other combination. These options will be concatenated into the SQL
-// query using 'AND' logic.
+// ID field(s) will be used to filter the result. Actions, After and Before may
+// be used with any other combination. These options will be concatenated into
+// the SQL query using 'AND' logic.
+//
+// Logs are returned newest first; if there are more logs than the page size the
+// NextPageID is set to the ID of the last log in the page and can be passed back
+// in to fetch the next (older) page of logs with the same filters.
 type ComplianceAuditLogPageInfo struct {
 	PageInfo
 
-	// Maximum number of records to query from database
-	// TODO: remove this once proper pagination has been implemented
-	Limit int
-
 	// FILTERING OPTIONS
 
 	// ResourceTypes filters results to include only these enum.Resource values
@@ -178,6 +178,8 @@ type ComplianceAuditLogPageInfo struct {
 	ActorTypes []string
 	// ActorID filters results by a specific actor ID
 	ActorID string
+	// Actions filters results to include only these enum.Action values
+	Actions []string
 	// After filters results to include logs with ResourceModified on or after this time (inclusive)
 	After time.Time
 	// Before filters results to include logs with ResourceModified before this time (exclusive)
@@ -191,19 +193,23 @@ type ComplianceAuditLogPageInfo struct {
 }
 
 // Copies the page info from the input into a new object, or creates a new
-// zero'ed object if the input is nil.
+// object with the default page size if the input is nil.
 func ComplianceAuditLogPageInfoFrom(in *ComplianceAuditLogPageInfo) (out *ComplianceAuditLogPageInfo) {
 	out = &ComplianceAuditLogPageInfo{}
-	if in != nil {
-		out.PageInfo = *PageInfoFrom(&in.PageInfo)
-		out.Limit = in.Limit
-		out.ResourceTypes = in.ResourceTypes
-		out.ResourceID = in.ResourceID
-		out.ActorTypes = in.ActorTypes
-		out.ActorID = in.ActorID
-		out.After = in.After
-		out.Before = in.Before
-		out.DetailedLogs = in.DetailedLogs
+	if in == nil {
+		out.PageInfo = *PageInfoFrom(nil)
+		return out
 	}
+
+	out.PageInfo = *PageInfoFrom(&in.PageInfo)
+	out.NextPageID = in.NextPageID
+	out.ResourceTypes = in.ResourceTypes
+	out.ResourceID = in.ResourceID
+	out.ActorTypes = in.ActorTypes
+	out.ActorID = in.ActorID
+	out.Actions = in.Actions
+	out.After = in.After
+	out.Before = in.Before
+	out.DetailedLogs = in.DetailedLogs
 	return out
 }
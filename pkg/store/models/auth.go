@@ -2,6 +2,10 @@ package models
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net"
 	"time"
 
 	"github.com/trisacrypto/envoy/pkg/store/errors"
@@ -11,29 +15,93 @@ import (
 
 type User struct {
 	Model
-	Name        sql.NullString
-	Email       string
-	Password    string
-	RoleID      int64
-	LastLogin   sql.NullTime
-	role        *Role
-	permissions []string
+	Name          sql.NullString
+	Email         string
+	Password      string
+	RoleID        int64
+	LastLogin     sql.NullTime
+	MFASecret     sql.NullString // TOTP secret; set when MFA enrollment is started
+	MFAEnrolled   sql.NullTime   // Set when the user has verified a code from the TOTP secret
+	MFALastStep   sql.NullInt64  // The time step of the last accepted TOTP code
+	MFAFailures   int64          // The number of consecutive failed MFA codes
+	MFALocked     sql.NullTime   // MFA codes cannot be verified until this time
+	LoginFailures int64          // The number of consecutive failed logins
+	LoginLockouts int64          // The number of lockouts since the last successful login
+	LoginLocked   sql.NullTime   // The user cannot login until this time
+	role          *Role
+	permissions   []string
+}
+
+// UserSession is created when a user logs in and is identified by the sid claim of the
+// access and refresh tokens issued to the user. The session expires with the refresh
+// token and is extended when the tokens are refreshed; once a session is revoked its
+// refresh token can no longer be used.
+type UserSession struct {
+	Model
+	UserID    ulid.ULID
+	UserAgent sql.NullString
+	IPAddress sql.NullString
+	SSO       bool      // True if the user logged in with single sign-on
+	LastUsed  time.Time // The last time the session tokens were issued or refreshed
+	Expires   time.Time // The refresh token of the session expires at this time
 }
 
 type APIKey struct {
 	Model
+	Description           sql.NullString
+	ClientID              string
+	Secret                string
+	LastSeen              sql.NullTime
+	Expires               sql.NullTime    // The key cannot be used to authenticate after this time
+	AllowedNetworks       AllowedNetworks // If set, the key can only be used from these networks
+	PreviousSecret        sql.NullString  // The secret replaced by the last rotation
+	PreviousSecretExpires sql.NullTime    // The previous secret is valid until this time
+	SecretRotated         sql.NullTime    // Refresh tokens issued before this time are rejected
+	permissions           []string
+}
+
+// APIKeyUsage is the number of requests made with an API key on a single (UTC) day.
+type APIKeyUsage struct {
+	APIKeyID ulid.ULID
+	Day      time.Time
+	Requests int64
+	LastIP   sql.NullString
+	LastUsed time.Time
+}
+
+// RevokedUser is the identity record retained when a user is deleted; it does not
+// contain the user's password or MFA secret.
+type RevokedUser struct {
+	ID        ulid.ULID
+	Name      sql.NullString
+	Email     string
+	Role      string // The title of the user's role when they were revoked
+	LastLogin sql.NullTime
+	Created   time.Time
+	Revoked   time.Time
+}
+
+// RevokedAPIKey is the identity record retained when an API key is deleted; it does
+// not contain the key's secret. The client ID of a revoked key cannot be reused.
+type RevokedAPIKey struct {
+	ID          ulid.ULID
 	Description sql.NullString
 	ClientID    string
-	Secret      string
 	LastSeen    sql.NullTime
-	permissions []string
+	Created     time.Time
+	Revoked     time.Time
 }
 
+// AllowedNetworks is a list of CIDRs stored in the database as a JSON array.
+type AllowedNetworks []string
+
 type Role struct {
 	ID          int64
 	Title       string
 	Description string
 	IsDefault   bool
+	RequireMFA  bool // If true, users with the role must enroll in MFA to be granted permissions
+	IsBuiltin   bool // Built-in roles are created by migrations and cannot be modified
 	Created     time.Time
 	Modified    time.Time
 	permissions []*Permission
@@ -68,6 +136,25 @@ func (u *User) SetRole(role *Role) {
 	u.RoleID = role.ID
 }
 
+// MFAEnabled returns true if the user has completed MFA enrollment. Only the enrolled
+// timestamp is checked since user summaries do not include the TOTP secret and the
+// secret is always removed when enrollment is reset.
+func (u User) MFAEnabled() bool {
+	return u.MFAEnrolled.Valid
+}
+
+// MFALockedOut returns true if the user has failed too many MFA codes and cannot
+// verify codes until the lockout expires.
+func (u User) MFALockedOut(now time.Time) bool {
+	return u.MFALocked.Valid && now.Before(u.MFALocked.Time)
+}
+
+// LoginLockedOut returns true if the user has failed too many logins and cannot login
+// until the lockout expires.
+func (u User) LoginLockedOut(now time.Time) bool {
+	return u.LoginLocked.Valid && now.Before(u.LoginLocked.Time)
+}
+
 func (u User) Permissions() []string {
 	return u.permissions
 }
@@ -76,6 +163,17 @@ func (u *User) SetPermissions(permissions []string) {
 	u.permissions = permissions
 }
 
+// Expired returns true if the API key has an expiration date that has passed.
+func (k APIKey) Expired(now time.Time) bool {
+	return k.Expires.Valid && !now.Before(k.Expires.Time)
+}
+
+// PreviousSecretValid returns true if the key has been rotated and the grace period
+// for the previous secret has not ended.
+func (k APIKey) PreviousSecretValid(now time.Time) bool {
+	return k.PreviousSecret.Valid && k.PreviousSecretExpires.Valid && now.Before(k.PreviousSecretExpires.Time)
+}
+
 func (k APIKey) Permissions() []string {
 	return k.permissions
 }
@@ -109,6 +207,14 @@ func (u *User) Scan(scanner Scanner) error {
 		&u.LastLogin,
 		&u.Created,
 		&u.Modified,
+		&u.MFASecret,
+		&u.MFAEnrolled,
+		&u.MFALastStep,
+		&u.MFAFailures,
+		&u.MFALocked,
+		&u.LoginFailures,
+		&u.LoginLockouts,
+		&u.LoginLocked,
 	)
 }
 
@@ -121,6 +227,8 @@ func (u *User) ScanSummary(scanner Scanner) error {
 		&u.LastLogin,
 		&u.Created,
 		&u.Modified,
+		&u.MFAEnrolled,
+		&u.LoginLocked,
 	)
 }
 
@@ -134,6 +242,42 @@ func (u *User) Params() []any {
 		sql.Named("lastLogin", u.LastLogin),
 		sql.Named("created", u.Created),
 		sql.Named("modified", u.Modified),
+		sql.Named("mfaSecret", u.MFASecret),
+		sql.Named("mfaEnrolled", u.MFAEnrolled),
+		sql.Named("mfaLastStep", u.MFALastStep),
+		sql.Named("mfaFailures", u.MFAFailures),
+		sql.Named("mfaLocked", u.MFALocked),
+		sql.Named("loginFailures", u.LoginFailures),
+		sql.Named("loginLockouts", u.LoginLockouts),
+		sql.Named("loginLocked", u.LoginLocked),
+	}
+}
+
+func (s *UserSession) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&s.ID,
+		&s.UserID,
+		&s.UserAgent,
+		&s.IPAddress,
+		&s.SSO,
+		&s.LastUsed,
+		&s.Expires,
+		&s.Created,
+		&s.Modified,
+	)
+}
+
+func (s *UserSession) Params() []any {
+	return []any{
+		sql.Named("id", s.ID),
+		sql.Named("userID", s.UserID),
+		sql.Named("userAgent", s.UserAgent),
+		sql.Named("ipAddress", s.IPAddress),
+		sql.Named("sso", s.SSO),
+		sql.Named("lastUsed", s.LastUsed),
+		sql.Named("expires", s.Expires),
+		sql.Named("created", s.Created),
+		sql.Named("modified", s.Modified),
 	}
 }
 
@@ -146,6 +290,11 @@ func (k *APIKey) Scan(scanner Scanner) error {
 		&k.LastSeen,
 		&k.Created,
 		&k.Modified,
+		&k.Expires,
+		&k.AllowedNetworks,
+		&k.PreviousSecret,
+		&k.PreviousSecretExpires,
+		&k.SecretRotated,
 	)
 }
 
@@ -157,6 +306,8 @@ func (k *APIKey) ScanSummary(scanner Scanner) error {
 		&k.LastSeen,
 		&k.Created,
 		&k.Modified,
+		&k.Expires,
+		&k.AllowedNetworks,
 	)
 }
 
@@ -169,7 +320,107 @@ func (k *APIKey) Params() []any {
 		sql.Named("lastSeen", k.LastSeen),
 		sql.Named("created", k.Created),
 		sql.Named("modified", k.Modified),
+		sql.Named("expires", k.Expires),
+		sql.Named("allowedNetworks", k.AllowedNetworks),
+		sql.Named("previousSecret", k.PreviousSecret),
+		sql.Named("previousSecretExpires", k.PreviousSecretExpires),
+		sql.Named("secretRotated", k.SecretRotated),
+	}
+}
+
+func (u *APIKeyUsage) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&u.APIKeyID,
+		&u.Day,
+		&u.Requests,
+		&u.LastIP,
+		&u.LastUsed,
+	)
+}
+
+func (u *RevokedUser) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&u.ID,
+		&u.Name,
+		&u.Email,
+		&u.Role,
+		&u.LastLogin,
+		&u.Created,
+		&u.Revoked,
+	)
+}
+
+func (k *RevokedAPIKey) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&k.ID,
+		&k.Description,
+		&k.ClientID,
+		&k.LastSeen,
+		&k.Created,
+		&k.Revoked,
+	)
+}
+
+//===========================================================================
+// Allowed Networks
+//===========================================================================
+
+// Allows returns true if no networks are specified or if the IP address is in one of
+// the allowed networks. Unparseable IP addresses and networks are never allowed.
+func (n AllowedNetworks) Allows(ip string) bool {
+	if len(n) == 0 {
+		return true
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, cidr := range n {
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *AllowedNetworks) Scan(src interface{}) error {
+	// Convert src into a byte array for unmarshaling
+	var source []byte
+	switch t := src.(type) {
+	case []byte:
+		source = t
+	case string:
+		source = []byte(t)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("incompatible type for allowed networks: %T", t)
+	}
+
+	// Unmarshal the JSON string array
+	cidrs := make([]string, 0)
+	if err := json.Unmarshal(source, &cidrs); err != nil {
+		return err
+	}
+
+	*n = AllowedNetworks(cidrs)
+	return nil
+}
+
+func (n AllowedNetworks) Value() (_ driver.Value, err error) {
+	// Store NULL for empty lists
+	if len(n) == 0 {
+		return nil, nil
+	}
+
+	var data []byte
+	if data, err = json.Marshal(n); err != nil {
+		return nil, err
 	}
+
+	return driver.Value(data), nil
 }
 
 func (r *Role) Scan(scanner Scanner) error {
@@ -180,6 +431,8 @@ func (r *Role) Scan(scanner Scanner) error {
 		&r.IsDefault,
 		&r.Created,
 		&r.Modified,
+		&r.RequireMFA,
+		&r.IsBuiltin,
 	)
 }
 
@@ -189,6 +442,8 @@ func (r *Role) Params() []any {
 		sql.Named("title", r.Title),
 		sql.Named("description", r.Description),
 		sql.Named("isDefault", r.IsDefault),
+		sql.Named("requireMFA", r.RequireMFA),
+		sql.Named("isBuiltin", r.IsBuiltin),
 		sql.Named("created", r.Created),
 		sql.Named("modified", r.Modified),
 	}
@@ -271,3 +526,106 @@ func (s *ResetPasswordLink) Params() []any {
 func (s *ResetPasswordLink) IsExpired() bool {
 	return time.Now().After(s.Expiration)
 }
+
+//===========================================================================
+// UserInvite
+//===========================================================================
+
+// UserInvite is created when an administrator invites a user by email. The invitee
+// sets their password using a signed, expiring link (the same mechanism as the
+// ResetPasswordLink) and the user is created with the role of the invite when the
+// invite is accepted.
+type UserInvite struct {
+	Model
+	Email      string            // Email address of the invitee; must not belong to a user
+	Name       sql.NullString    // The name of the invitee, if known
+	RoleID     int64             // The role assigned to the user when the invite is accepted
+	Expiration time.Time         // The timestamp that the invite link is no longer valid
+	Signature  *vero.SignedToken // The signed token used to verify the invite link
+	SentOn     sql.NullTime      // The timestamp that the invite email was last sent
+	role       *Role
+}
+
+func (i UserInvite) Role() (*Role, error) {
+	if i.role == nil {
+		return nil, errors.ErrMissingAssociation
+	}
+	return i.role, nil
+}
+
+func (i *UserInvite) SetRole(role *Role) {
+	i.role = role
+	i.RoleID = role.ID
+}
+
+// Scans a complete SELECT into the UserInvite model
+func (i *UserInvite) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.RoleID,
+		&i.Expiration,
+		&i.Signature,
+		&i.SentOn,
+		&i.Created,
+		&i.Modified,
+	)
+}
+
+// Scans a complete SELECT of the invite followed by the title of its role into the
+// UserInvite model, setting the role association.
+func (i *UserInvite) ScanWithRole(scanner Scanner) (err error) {
+	role := &Role{}
+	if err = scanner.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.RoleID,
+		&i.Expiration,
+		&i.Signature,
+		&i.SentOn,
+		&i.Created,
+		&i.Modified,
+		&role.Title,
+	); err != nil {
+		return err
+	}
+
+	role.ID = i.RoleID
+	i.role = role
+	return nil
+}
+
+// Get the named params of the UserInvite from the model that are required to send
+// (or resend) the invite.
+func (i *UserInvite) UpdateParams() []any {
+	return []any{
+		sql.Named("id", i.ID),
+		sql.Named("expiration", i.Expiration),
+		sql.Named("signature", i.Signature),
+		sql.Named("sentOn", i.SentOn),
+		sql.Named("modified", i.Modified),
+	}
+}
+
+// Get the complete named params of the UserInvite from the model.
+func (i *UserInvite) Params() []any {
+	return []any{
+		sql.Named("id", i.ID),
+		sql.Named("email", i.Email),
+		sql.Named("name", i.Name),
+		sql.Named("roleID", i.RoleID),
+		sql.Named("expiration", i.Expiration),
+		sql.Named("signature", i.Signature),
+		sql.Named("sentOn", i.SentOn),
+		sql.Named("created", i.Created),
+		sql.Named("modified", i.Modified),
+	}
+}
+
+// IsExpired returns true if the current time is after the invite expiration; expired
+// invites must be resent before they can be accepted.
+func (i *UserInvite) IsExpired() bool {
+	return time.Now().After(i.Expiration)
+}
@@ -1,6 +1,7 @@
 package models_test
 
 import (
+	"database/sql"
 	"testing"
 	"time"
 
@@ -120,6 +121,14 @@ func TestUserScan(t *testing.T) {
 			time.Now(),                    // LastLogin
 			time.Now(),                    // Created
 			time.Now().Add(1 * time.Hour), // Modified
+			"JBSWY3DPEHPK3PXP",            // MFASecret
+			time.Now(),                    // MFAEnrolled
+			int64(58000000),               // MFALastStep
+			int64(2),                      // MFAFailures
+			time.Now().Add(1 * time.Hour), // MFALocked
+			int64(3),                      // LoginFailures
+			int64(1),                      // LoginLockouts
+			time.Now().Add(1 * time.Hour), // LoginLocked
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -139,6 +148,17 @@ func TestUserScan(t *testing.T) {
 		require.Equal(t, data[5], model.LastLogin.Time, "expected field LastLogin to match data[5]")
 		require.Equal(t, data[6], model.Created, "expected field Created to match data[6]")
 		require.Equal(t, data[7], model.Modified, "expected field Modified to match data[7]")
+		require.Equal(t, data[8], model.MFASecret.String, "expected field MFASecret to match data[8]")
+		require.Equal(t, data[9], model.MFAEnrolled.Time, "expected field MFAEnrolled to match data[9]")
+		require.Equal(t, data[10], model.MFALastStep.Int64, "expected field MFALastStep to match data[10]")
+		require.Equal(t, data[11], model.MFAFailures, "expected field MFAFailures to match data[11]")
+		require.Equal(t, data[12], model.MFALocked.Time, "expected field MFALocked to match data[12]")
+		require.True(t, model.MFAEnabled(), "expected MFA to be enabled")
+		require.Equal(t, data[13], model.LoginFailures, "expected field LoginFailures to match data[13]")
+		require.Equal(t, data[14], model.LoginLockouts, "expected field LoginLockouts to match data[14]")
+		require.Equal(t, data[15], model.LoginLocked.Time, "expected field LoginLocked to match data[15]")
+		require.True(t, model.MFALockedOut(time.Now()), "expected MFA to be locked out")
+		require.True(t, model.LoginLockedOut(time.Now()), "expected login to be locked out")
 	})
 
 	t.Run("SuccessNulls", func(t *testing.T) {
@@ -152,6 +172,14 @@ func TestUserScan(t *testing.T) {
 			nil,                        // LastLogin (testing null time)
 			time.Now(),                 // Created
 			time.Time{},                // Modified (testing zero time)
+			nil,                        // MFASecret (testing null string)
+			nil,                        // MFAEnrolled (testing null time)
+			nil,                        // MFALastStep (testing null int)
+			int64(0),                   // MFAFailures
+			nil,                        // MFALocked (testing null time)
+			int64(0),                   // LoginFailures
+			int64(0),                   // LoginLockouts
+			nil,                        // LoginLocked (testing null time)
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -161,9 +189,43 @@ func TestUserScan(t *testing.T) {
 		err := model.Scan(mockScanner)
 		require.NoError(t, err, "expected no errors when scanning")
 		mockScanner.AssertScanned(t, len(data))
+		require.False(t, model.MFAEnabled(), "expected MFA to not be enabled")
+		require.False(t, model.MFALockedOut(time.Now()), "expected MFA to not be locked out")
+		require.False(t, model.LoginLockedOut(time.Now()), "expected login to not be locked out")
 	})
 }
 
+func TestUserSessionScan(t *testing.T) {
+	//setup
+	data := []any{
+		ulid.MakeSecure().String(),    // ID
+		ulid.MakeSecure().String(),    // UserID
+		"Mozilla/5.0",                 // UserAgent
+		"192.168.1.1",                 // IPAddress
+		true,                          // SSO
+		time.Now(),                    // LastUsed
+		time.Now().Add(2 * time.Hour), // Expires
+		time.Now(),                    // Created
+		time.Now(),                    // Modified
+	}
+	mockScanner := &mock.MockScanner{}
+	mockScanner.SetData(data)
+
+	//test
+	model := &models.UserSession{}
+	err := model.Scan(mockScanner)
+	require.NoError(t, err, "expected no errors when scanning")
+	mockScanner.AssertScanned(t, len(data))
+
+	require.Equal(t, data[0], model.ID.String(), "expected field ID to match data[0]")
+	require.Equal(t, data[1], model.UserID.String(), "expected field UserID to match data[1]")
+	require.Equal(t, data[2], model.UserAgent.String, "expected field UserAgent to match data[2]")
+	require.Equal(t, data[3], model.IPAddress.String, "expected field IPAddress to match data[3]")
+	require.Equal(t, data[4], model.SSO, "expected field SSO to match data[4]")
+	require.Equal(t, data[5], model.LastUsed, "expected field LastUsed to match data[5]")
+	require.Equal(t, data[6], model.Expires, "expected field Expires to match data[6]")
+}
+
 func TestAPIKeyScan(t *testing.T) {
 	t.Run("SuccessFilled", func(t *testing.T) {
 		//setup
@@ -175,6 +237,11 @@ func TestAPIKeyScan(t *testing.T) {
 			time.Now(),                 // LastSeen
 			time.Now(),                 // Created
 			time.Now(),                 // Modified
+			time.Now(),                 // Expires
+			`["10.0.0.0/8"]`,           // AllowedNetworks
+			"PreviousSecret",           // PreviousSecret
+			time.Now(),                 // PreviousSecretExpires
+			time.Now(),                 // SecretRotated
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -193,6 +260,11 @@ func TestAPIKeyScan(t *testing.T) {
 		require.Equal(t, data[4], model.LastSeen.Time, "expected field LastSeen to match data[4]")
 		require.Equal(t, data[5], model.Created, "expected field Created to match data[5]")
 		require.Equal(t, data[6], model.Modified, "expected field Modified to match data[6]")
+		require.Equal(t, data[7], model.Expires.Time, "expected field Expires to match data[7]")
+		require.Equal(t, models.AllowedNetworks{"10.0.0.0/8"}, model.AllowedNetworks, "expected field AllowedNetworks to match data[8]")
+		require.Equal(t, data[9], model.PreviousSecret.String, "expected field PreviousSecret to match data[9]")
+		require.Equal(t, data[10], model.PreviousSecretExpires.Time, "expected field PreviousSecretExpires to match data[10]")
+		require.Equal(t, data[11], model.SecretRotated.Time, "expected field SecretRotated to match data[11]")
 	})
 
 	t.Run("SuccessNulls", func(t *testing.T) {
@@ -205,6 +277,11 @@ func TestAPIKeyScan(t *testing.T) {
 			nil,                        // LastSeen (testing null time)
 			time.Now(),                 // Created
 			time.Time{},                // Modified (testing a zero value time)
+			nil,                        // Expires
+			nil,                        // AllowedNetworks
+			nil,                        // PreviousSecret
+			nil,                        // PreviousSecretExpires
+			nil,                        // SecretRotated
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -217,6 +294,119 @@ func TestAPIKeyScan(t *testing.T) {
 	})
 }
 
+func TestAPIKeyExpired(t *testing.T) {
+	now := time.Now()
+
+	key := &models.APIKey{}
+	require.False(t, key.Expired(now), "keys without an expiration should not expire")
+	require.False(t, key.PreviousSecretValid(now), "keys that were not rotated have no previous secret")
+
+	key.Expires = sql.NullTime{Valid: true, Time: now.Add(time.Hour)}
+	require.False(t, key.Expired(now))
+	require.True(t, key.Expired(now.Add(time.Hour)))
+
+	key.PreviousSecret = sql.NullString{Valid: true, String: "secret"}
+	key.PreviousSecretExpires = sql.NullTime{Valid: true, Time: now.Add(time.Minute)}
+	require.True(t, key.PreviousSecretValid(now))
+	require.False(t, key.PreviousSecretValid(now.Add(time.Minute)))
+}
+
+func TestAllowedNetworks(t *testing.T) {
+	var networks models.AllowedNetworks
+	require.True(t, networks.Allows("203.0.113.42"), "expected all addresses to be allowed with no networks")
+
+	networks = models.AllowedNetworks{"10.0.0.0/8", "2001:db8::/32", "192.0.2.1/32"}
+	testCases := []struct {
+		ip      string
+		allowed bool
+	}{
+		{"10.1.2.3", true},
+		{"192.0.2.1", true},
+		{"2001:db8::1", true},
+		{"192.0.2.2", false},
+		{"203.0.113.42", false},
+		{"", false},
+		{"not an ip", false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.allowed, networks.Allows(tc.ip), "unexpected result for %q", tc.ip)
+	}
+
+	value, err := networks.Value()
+	require.NoError(t, err)
+
+	var cmp models.AllowedNetworks
+	require.NoError(t, cmp.Scan(value))
+	require.Equal(t, networks, cmp)
+
+	value, err = models.AllowedNetworks{}.Value()
+	require.NoError(t, err)
+	require.Nil(t, value, "expected empty networks to be stored as null")
+}
+
+func TestRevokedUserScan(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		//setup
+		data := []any{
+			ulid.MakeSecure().String(), // ID
+			"Name",                     // Name
+			"email@example.com",        // Email
+			"Compliance",               // Role
+			time.Now(),                 // LastLogin
+			time.Now(),                 // Created
+			time.Now(),                 // Revoked
+		}
+		mockScanner := &mock.MockScanner{}
+		mockScanner.SetData(data)
+
+		//test
+		model := &models.RevokedUser{}
+		err := model.Scan(mockScanner)
+		require.NoError(t, err, "expected no errors when scanning")
+		mockScanner.AssertScanned(t, len(data))
+
+		// make sure scanned data matches the fields they were supposed to scan into
+		require.Equal(t, data[0], model.ID.String(), "expected field ID to match data[0]")
+		require.Equal(t, data[1], model.Name.String, "expected field Name to match data[1]")
+		require.Equal(t, data[2], model.Email, "expected field Email to match data[2]")
+		require.Equal(t, data[3], model.Role, "expected field Role to match data[3]")
+		require.Equal(t, data[4], model.LastLogin.Time, "expected field LastLogin to match data[4]")
+		require.Equal(t, data[5], model.Created, "expected field Created to match data[5]")
+		require.Equal(t, data[6], model.Revoked, "expected field Revoked to match data[6]")
+	})
+}
+
+func TestRevokedAPIKeyScan(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		//setup
+		data := []any{
+			ulid.MakeSecure().String(), // ID
+			nil,                        // Description (testing null string)
+			"ClientID",                 // ClientID
+			nil,                        // LastSeen (testing null time)
+			time.Now(),                 // Created
+			time.Now(),                 // Revoked
+		}
+		mockScanner := &mock.MockScanner{}
+		mockScanner.SetData(data)
+
+		//test
+		model := &models.RevokedAPIKey{}
+		err := model.Scan(mockScanner)
+		require.NoError(t, err, "expected no errors when scanning")
+		mockScanner.AssertScanned(t, len(data))
+
+		// make sure scanned data matches the fields they were supposed to scan into
+		require.Equal(t, data[0], model.ID.String(), "expected field ID to match data[0]")
+		require.False(t, model.Description.Valid, "expected field Description to be null")
+		require.Equal(t, data[2], model.ClientID, "expected field ClientID to match data[2]")
+		require.False(t, model.LastSeen.Valid, "expected field LastSeen to be null")
+		require.Equal(t, data[4], model.Created, "expected field Created to match data[4]")
+		require.Equal(t, data[5], model.Revoked, "expected field Revoked to match data[5]")
+	})
+}
+
 func TestRoleScan(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		//setup
@@ -227,6 +417,8 @@ func TestRoleScan(t *testing.T) {
 			true,          // IsDefault
 			time.Now(),    // Created
 			time.Now(),    // Modified
+			true,          // RequireMFA
+			true,          // IsBuiltin
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -244,6 +436,8 @@ func TestRoleScan(t *testing.T) {
 		require.Equal(t, data[3], model.IsDefault, "expected field IsDefault to match data[3]")
 		require.Equal(t, data[4], model.Created, "expected field Created to match data[4]")
 		require.Equal(t, data[5], model.Modified, "expected field Modified to match data[5]")
+		require.Equal(t, data[6], model.RequireMFA, "expected field RequireMFA to match data[6]")
+		require.Equal(t, data[7], model.IsBuiltin, "expected field IsBuiltin to match data[7]")
 	})
 }
 
@@ -329,3 +523,85 @@ func TestResetPasswordLinkScan(t *testing.T) {
 		mockScanner.AssertScanned(t, len(data)-1) // will not scan Signature
 	})
 }
+
+func TestUserInviteScan(t *testing.T) {
+	t.Run("Scan", func(t *testing.T) {
+		//setup
+		data := []any{
+			ulid.MakeSecure().String(), // ID
+			"email@example.com",        // Email
+			"First Last",               // Name
+			int64(2),                   // RoleID
+			time.Now(),                 // Expiration
+			nil,                        // Signature (vero token; ignored for now)
+			time.Now(),                 // SentOn
+			time.Now(),                 // Created
+			time.Now(),                 // Modified
+		}
+		mockScanner := &mock.MockScanner{}
+		mockScanner.SetData(data)
+
+		//test
+		model := &models.UserInvite{}
+		err := model.Scan(mockScanner)
+		require.NoError(t, err, "expected no errors when scanning")
+		mockScanner.AssertScanned(t, len(data)-1) // will not scan Signature
+
+		require.Equal(t, data[0], model.ID.String(), "expected field ID to match data[0]")
+		require.Equal(t, data[1], model.Email, "expected field Email to match data[1]")
+		require.Equal(t, data[2], model.Name.String, "expected field Name to match data[2]")
+		require.Equal(t, data[3], model.RoleID, "expected field RoleID to match data[3]")
+		require.Equal(t, data[4], model.Expiration, "expected field Expiration to match data[4]")
+		require.Equal(t, data[6], model.SentOn.Time, "expected field SentOn to match data[6]")
+
+		_, err = model.Role()
+		require.ErrorIs(t, err, errors.ErrMissingAssociation, "expected no role to be set")
+	})
+
+	t.Run("ScanWithRole", func(t *testing.T) {
+		//setup
+		data := []any{
+			ulid.MakeSecure().String(), // ID
+			"email@example.com",        // Email
+			nil,                        // Name
+			int64(2),                   // RoleID
+			time.Now(),                 // Expiration
+			nil,                        // Signature (vero token; ignored for now)
+			nil,                        // SentOn
+			time.Now(),                 // Created
+			time.Now(),                 // Modified
+			"Compliance",               // Role Title
+		}
+		mockScanner := &mock.MockScanner{}
+		mockScanner.SetData(data)
+
+		//test
+		model := &models.UserInvite{}
+		err := model.ScanWithRole(mockScanner)
+		require.NoError(t, err, "expected no errors when scanning")
+		mockScanner.AssertScanned(t, len(data)-1) // will not scan Signature
+		require.False(t, model.Name.Valid, "expected name to be null")
+		require.False(t, model.SentOn.Valid, "expected sent on to be null")
+
+		role, err := model.Role()
+		require.NoError(t, err, "expected role to be set")
+		require.Equal(t, int64(2), role.ID)
+		require.Equal(t, "Compliance", role.Title)
+	})
+}
+
+func TestUserInviteParams(t *testing.T) {
+	// setup a model
+	theModel := &models.UserInvite{}
+
+	// create the model public field name comparison list
+	fields := GetPublicFieldNames(*theModel)
+
+	// create the `Params()` comparison list
+	// Exceptions: None
+	exceptions := map[string]string{}
+	params := GetParamsNames(theModel, exceptions)
+
+	// test
+	require.ElementsMatch(t, fields, params, "the model's public fields and Params() lists should have the same names")
+}
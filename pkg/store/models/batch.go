@@ -0,0 +1,116 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"go.rtnl.ai/ulid"
+)
+
+// Batch is a set of transfers imported from a CSV or JSON Lines file that are
+// validated together and then sent concurrently. The counts of the items in each
+// status are summarized when the batch is retrieved so that the progress of the batch
+// can be tracked while it is being sent.
+type Batch struct {
+	Model
+	Filename  string           // the name of the imported file
+	Format    string           // the format of the imported file (csv or jsonl)
+	Status    enum.BatchStatus // one of validated, sending, completed, or canceled
+	CreatedBy string           // the name of the user or api key that imported the batch
+	Items     []*BatchItem     // the items to create with the batch (not stored)
+	Total     int64            // the number of items in the batch (not stored)
+	Invalid   int64            // the number of items that could not be validated (not stored)
+	Pending   int64            // the number of items waiting to be sent (not stored)
+	Sent      int64            // the number of items sent to the counterparty (not stored)
+	Queued    int64            // the number of items added to the outbound queue (not stored)
+	Held      int64            // the number of items held for approval (not stored)
+	Failed    int64            // the number of items that could not be sent (not stored)
+}
+
+// BatchItem is a single transfer (row) of an imported batch.
+type BatchItem struct {
+	Model
+	BatchID       ulid.ULID            // the batch the item belongs to
+	Row           int64                // the row or line number of the item in the imported file
+	Status        enum.BatchItemStatus // the result of validating or sending the item
+	Request       sql.NullString       // the prepare transaction JSON of the item (PII)
+	Errors        []byte               // the JSON validation or send errors of the item
+	TransactionID uuid.NullUUID        // the transaction created when the item was sent
+}
+
+// Scan a complete SELECT with the item counts into the batch model
+func (b *Batch) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&b.ID,
+		&b.Filename,
+		&b.Format,
+		&b.Status,
+		&b.CreatedBy,
+		&b.Created,
+		&b.Modified,
+		&b.Total,
+		&b.Invalid,
+		&b.Pending,
+		&b.Sent,
+		&b.Queued,
+		&b.Held,
+		&b.Failed,
+	)
+}
+
+// Get the complete named params of the batch from the model.
+func (b *Batch) Params() []any {
+	return []any{
+		sql.Named("id", b.ID),
+		sql.Named("filename", b.Filename),
+		sql.Named("format", b.Format),
+		sql.Named("status", b.Status),
+		sql.Named("createdBy", b.CreatedBy),
+		sql.Named("created", b.Created),
+		sql.Named("modified", b.Modified),
+	}
+}
+
+// Returns true if the batch has items that have not been sent yet; a canceled batch
+// can be resumed to send its remaining items. A batch that is still sending can also
+// be resumed in case the send was interrupted (e.g. the node restarted while the batch
+// was being sent), so callers must check that the batch is not currently being sent.
+func (b *Batch) IsSendable() bool {
+	switch b.Status {
+	case enum.BatchValidated, enum.BatchCanceled, enum.BatchSending:
+		return b.Pending > 0
+	default:
+		return false
+	}
+}
+
+// Scan a complete SELECT into the batch item model
+func (i *BatchItem) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&i.ID,
+		&i.BatchID,
+		&i.Row,
+		&i.Status,
+		&i.Request,
+		&i.Errors,
+		&i.TransactionID,
+		&i.Created,
+		&i.Modified,
+	)
+}
+
+// Get the complete named params of the batch item from the model.
+func (i *BatchItem) Params() []any {
+	return []any{
+		sql.Named("id", i.ID),
+		sql.Named("batchID", i.BatchID),
+		sql.Named("row", i.Row),
+		sql.Named("status", i.Status),
+		sql.Named("request", i.Request),
+		sql.Named("errors", i.Errors),
+		sql.Named("transactionID", i.TransactionID),
+		sql.Named("created", i.Created),
+		sql.Named("modified", i.Modified),
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/mail"
 	"net/url"
+	"time"
 
 	"github.com/trisacrypto/envoy/pkg/enum"
 	"github.com/trisacrypto/envoy/pkg/store/errors"
@@ -41,6 +42,7 @@ type Counterparty struct {
 	VerifiedOn          sql.NullTime         // the datetime the VASP was verified in the directory (directory only)
 	IVMSRecord          *ivms101.LegalPerson // IVMS101 record for the counterparty
 	LEI                 sql.NullString       // Legal Entity Identifier for the counterparty (generally for TRP)
+	Pending             PendingOverrides     // Customizations of the pending response sent to the counterparty
 	contacts            []*Contact           // Associated contacts if any
 }
 
@@ -69,6 +71,10 @@ func (c *Counterparty) Scan(scanner Scanner) error {
 		&c.Created,
 		&c.Modified,
 		&c.LEI,
+		&c.Pending.Language,
+		&c.Pending.Message,
+		&c.Pending.ReplyNotBefore,
+		&c.Pending.ReplyNotAfter,
 	)
 }
 
@@ -107,6 +113,10 @@ func (c *Counterparty) Params() []any {
 		sql.Named("created", c.Created),
 		sql.Named("modified", c.Modified),
 		sql.Named("lei", c.LEI),
+		sql.Named("pendingLanguage", c.Pending.Language),
+		sql.Named("pendingMessage", c.Pending.Message),
+		sql.Named("replyNotBefore", c.Pending.ReplyNotBefore),
+		sql.Named("replyNotAfter", c.Pending.ReplyNotAfter),
 	}
 }
 
@@ -167,6 +177,38 @@ func (c *Counterparty) NormalizedWebsite() (out string, err error) {
 
 }
 
+// ###########################################################################
+// PendingOverrides
+// ###########################################################################
+
+// PendingOverrides customize the pending response that is sent to a counterparty when
+// an incoming travel rule request must be reviewed. Any field that is not valid falls
+// back to the pending configuration of the node. Overrides are not modified by the
+// directory sync and can therefore be set on counterparties from any source.
+type PendingOverrides struct {
+	Language       sql.NullString // the language tag used to select a localized pending message
+	Message        sql.NullString // a message template that replaces the configured message
+	ReplyNotBefore sql.NullInt64  // the earliest reply window in seconds after the request
+	ReplyNotAfter  sql.NullInt64  // the latest reply window in seconds after the request
+}
+
+// Returns true if none of the overrides are set.
+func (p PendingOverrides) IsZero() bool {
+	return !p.Language.Valid && !p.Message.Valid && !p.ReplyNotBefore.Valid && !p.ReplyNotAfter.Valid
+}
+
+// Returns the reply windows as durations, using the specified defaults if not set.
+func (p PendingOverrides) ReplyWindows(notBefore, notAfter time.Duration) (time.Duration, time.Duration) {
+	if p.ReplyNotBefore.Valid {
+		notBefore = time.Duration(p.ReplyNotBefore.Int64) * time.Second
+	}
+
+	if p.ReplyNotAfter.Valid {
+		notAfter = time.Duration(p.ReplyNotAfter.Int64) * time.Second
+	}
+	return notBefore, notAfter
+}
+
 // ###########################################################################
 // Contact
 // ###########################################################################
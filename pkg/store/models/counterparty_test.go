@@ -25,7 +25,14 @@ func TestCounterpartyParams(t *testing.T) {
 
 	// create the `Params()` comparison list
 	// Exception 1) replace "ivms101" as "ivmsrecord"
-	exceptions := map[string]string{ConvertNameForComparison("ivms101"): ConvertNameForComparison("IVMSRecord")}
+	// Exception 2) the pending overrides are params of the nested Pending field
+	exceptions := map[string]string{
+		ConvertNameForComparison("ivms101"):         ConvertNameForComparison("IVMSRecord"),
+		ConvertNameForComparison("pendingLanguage"): ConvertNameForComparison("Pending"),
+		ConvertNameForComparison("pendingMessage"):  "",
+		ConvertNameForComparison("replyNotBefore"):  "",
+		ConvertNameForComparison("replyNotAfter"):   "",
+	}
 	params := GetParamsNames(theModel, exceptions)
 
 	// test
@@ -93,6 +100,10 @@ func TestCounterpartyScan(t *testing.T) {
 			time.Now(),                          // Created
 			time.Now(),                          // Modified
 			"LEI",                               // LEI
+			"de",                                // Pending.Language
+			"Message",                           // Pending.Message
+			int64(300),                          // Pending.ReplyNotBefore
+			int64(86400),                        // Pending.ReplyNotAfter
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -121,6 +132,10 @@ func TestCounterpartyScan(t *testing.T) {
 		require.Equal(t, data[14], model.Created, "expected field Created to match data[14]")
 		require.Equal(t, data[15], model.Modified, "expected field Modified to match data[15]")
 		require.Equal(t, data[16], model.LEI.String, "expected field LEI to match data[16]")
+		require.Equal(t, data[17], model.Pending.Language.String, "expected field Pending.Language to match data[17]")
+		require.Equal(t, data[18], model.Pending.Message.String, "expected field Pending.Message to match data[18]")
+		require.Equal(t, data[19], model.Pending.ReplyNotBefore.Int64, "expected field Pending.ReplyNotBefore to match data[19]")
+		require.Equal(t, data[20], model.Pending.ReplyNotAfter.Int64, "expected field Pending.ReplyNotAfter to match data[20]")
 	})
 
 	t.Run("SuccessNulls", func(t *testing.T) {
@@ -143,6 +158,10 @@ func TestCounterpartyScan(t *testing.T) {
 			time.Now(),                 // Created
 			time.Time{},                // Modified (testing zero value)
 			nil,                        // LEI (testing null)
+			nil,                        // Pending.Language (testing null)
+			nil,                        // Pending.Message (testing null)
+			nil,                        // Pending.ReplyNotBefore (testing null)
+			nil,                        // Pending.ReplyNotAfter (testing null)
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -174,6 +193,10 @@ func TestCounterpartyScan(t *testing.T) {
 			time.Now(),                    // Created
 			time.Time{},                   // Modified (testing zero value)
 			nil,                           // LEI (testing null)
+			nil,                           // Pending.Language (testing null)
+			nil,                           // Pending.Message (testing null)
+			nil,                           // Pending.ReplyNotBefore (testing null)
+			nil,                           // Pending.ReplyNotAfter (testing null)
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -205,6 +228,10 @@ func TestCounterpartyScan(t *testing.T) {
 			time.Now(),                 // Created
 			time.Time{},                // Modified (testing zero value)
 			nil,                        // LEI (testing null)
+			nil,                        // Pending.Language (testing null)
+			nil,                        // Pending.Message (testing null)
+			nil,                        // Pending.ReplyNotBefore (testing null)
+			nil,                        // Pending.ReplyNotAfter (testing null)
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
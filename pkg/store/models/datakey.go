@@ -0,0 +1,41 @@
+package models
+
+import "database/sql"
+
+// ###########################################################################
+// DataKey
+// ###########################################################################
+
+// DataKey is the field encryption key used to encrypt PII columns in the database,
+// wrapped (encrypted) by the node's storage key so that it is never stored in
+// plaintext. The signature identifies the storage key required to unwrap it.
+type DataKey struct {
+	Model
+	KeyID      string // the short identifier of the key recorded on the ciphertexts
+	WrappedKey []byte // the data key encrypted with the storage key
+	Signature  string // the public key signature of the storage key
+}
+
+// Scan a complete SELECT into the data key model
+func (k *DataKey) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&k.ID,
+		&k.KeyID,
+		&k.WrappedKey,
+		&k.Signature,
+		&k.Created,
+		&k.Modified,
+	)
+}
+
+// Get the complete named params of the data key from the model.
+func (k *DataKey) Params() []any {
+	return []any{
+		sql.Named("id", k.ID),
+		sql.Named("keyID", k.KeyID),
+		sql.Named("wrappedKey", k.WrappedKey),
+		sql.Named("signature", k.Signature),
+		sql.Named("created", k.Created),
+		sql.Named("modified", k.Modified),
+	}
+}
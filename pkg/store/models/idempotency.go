@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ###########################################################################
+// IdempotencyKey
+// ###########################################################################
+
+// IdempotencyKey stores the response to a request made with an Idempotency-Key header
+// so that the response can be replayed if the request is retried with the same key.
+// Keys are scoped to the actor that made the request and the request hash is used to
+// detect a key that is reused with a different request.
+type IdempotencyKey struct {
+	Key           string        // the value of the Idempotency-Key header
+	ActorID       []byte        // the user or api key that made the request
+	RequestHash   []byte        // the hash of the method, path, and body of the request
+	Status        int           // the http status code of the response
+	ContentType   string        // the content type of the response
+	Response      []byte        // the body of the response; encrypted at rest with the field cipher
+	Expires       time.Time     // the key is not replayed after this time
+	Created       time.Time     // when the response was stored
+	TransactionID uuid.NullUUID // the transaction described by the response, deleted with it
+}
+
+// Scan a complete SELECT into the idempotency key model
+func (k *IdempotencyKey) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&k.Key,
+		&k.ActorID,
+		&k.RequestHash,
+		&k.Status,
+		&k.ContentType,
+		&k.Response,
+		&k.Expires,
+		&k.Created,
+		&k.TransactionID,
+	)
+}
+
+// Get the complete named params of the idempotency key from the model.
+func (k *IdempotencyKey) Params() []any {
+	return []any{
+		sql.Named("key", k.Key),
+		sql.Named("actorID", k.ActorID),
+		sql.Named("requestHash", k.RequestHash),
+		sql.Named("status", k.Status),
+		sql.Named("contentType", k.ContentType),
+		sql.Named("response", k.Response),
+		sql.Named("expires", k.Expires),
+		sql.Named("created", k.Created),
+		sql.Named("transactionID", k.TransactionID),
+	}
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"go.rtnl.ai/ulid"
+)
+
+// ###########################################################################
+// LegalHold
+// ###########################################################################
+
+// LegalHold prevents a transaction, customer account, or counterparty from being
+// deleted, archived, or purged by the data retention policies until it is released.
+// Holds on accounts and counterparties also apply to their associated transactions.
+// Released holds are not deleted so that the history of the hold is preserved.
+type LegalHold struct {
+	Model
+	ResourceType  enum.Resource  // the type of the held resource (transaction, account, or counterparty)
+	ResourceID    string         // the UUID or ULID of the held resource depending on the resource type
+	Reason        string         // the reason the legal hold was applied
+	CaseReference sql.NullString // an optional reference to the investigation or case
+	AppliedBy     string         // the name of the user or api key that applied the hold
+	AppliedByID   []byte         // the actor id of the user or api key that applied the hold
+	AppliedByType enum.Actor     // the type of actor that applied the hold
+	ReleasedBy    sql.NullString // the name of the user or api key that released the hold
+	ReleasedOn    sql.NullTime   // the timestamp the hold was released; holds are active until released
+}
+
+type LegalHoldPageInfo struct {
+	PageInfo
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Released     bool   `json:"released,omitempty"`
+}
+
+// Returns true if the legal hold can be applied to the specified resource type.
+func ValidLegalHoldResource(resource enum.Resource) bool {
+	switch resource {
+	case enum.ResourceTransaction, enum.ResourceAccount, enum.ResourceCounterparty:
+		return true
+	default:
+		return false
+	}
+}
+
+// Scan a complete SELECT into the legal hold model
+func (h *LegalHold) Scan(scanner Scanner) (err error) {
+	var resourceID any
+	if err = scanner.Scan(
+		&h.ID,
+		&h.ResourceType,
+		&resourceID,
+		&h.Reason,
+		&h.CaseReference,
+		&h.AppliedBy,
+		&h.AppliedByID,
+		&h.AppliedByType,
+		&h.ReleasedBy,
+		&h.ReleasedOn,
+		&h.Created,
+		&h.Modified,
+	); err != nil {
+		return err
+	}
+
+	// The resource ID is stored in the same representation as the primary key of the
+	// held resource: a UUID string for transactions and ULID bytes otherwise.
+	switch id := resourceID.(type) {
+	case string:
+		h.ResourceID = id
+	case []byte:
+		var rid ulid.ULID
+		if err = rid.Scan(id); err != nil {
+			return err
+		}
+		h.ResourceID = rid.String()
+	default:
+		return fmt.Errorf("cannot scan %T into a legal hold resource id", resourceID)
+	}
+
+	return nil
+}
+
+// Get the complete named params of the legal hold from the model.
+func (h *LegalHold) Params() []any {
+	// NOTE: the resource key must be validated before the params are created
+	resourceID, _ := h.ResourceKey()
+
+	return []any{
+		sql.Named("id", h.ID),
+		sql.Named("resourceType", h.ResourceType),
+		sql.Named("resourceID", resourceID),
+		sql.Named("reason", h.Reason),
+		sql.Named("caseReference", h.CaseReference),
+		sql.Named("appliedBy", h.AppliedBy),
+		sql.Named("appliedByID", h.AppliedByID),
+		sql.Named("appliedByType", h.AppliedByType),
+		sql.Named("releasedBy", h.ReleasedBy),
+		sql.Named("releasedOn", h.ReleasedOn),
+		sql.Named("created", h.Created),
+		sql.Named("modified", h.Modified),
+	}
+}
+
+// ResourceKey parses the resource ID into the type of the primary key of the held
+// resource so that it can be compared to the resource in the database; e.g. a
+// uuid.UUID for transactions and a ulid.ULID for accounts and counterparties.
+func (h *LegalHold) ResourceKey() (any, error) {
+	switch h.ResourceType {
+	case enum.ResourceTransaction:
+		return uuid.Parse(h.ResourceID)
+	case enum.ResourceAccount, enum.ResourceCounterparty:
+		return ulid.Parse(h.ResourceID)
+	default:
+		return nil, errors.ErrInvalidHoldResource
+	}
+}
+
+// Returns true if the legal hold has not been released.
+func (h *LegalHold) IsActive() bool {
+	return !h.ReleasedOn.Valid
+}
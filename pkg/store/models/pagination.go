@@ -65,6 +65,16 @@ type ComplianceAuditLogPage struct {
 	Page *ComplianceAuditLogPageInfo `json:"page"`
 }
 
+type LegalHoldPage struct {
+	LegalHolds []*LegalHold       `json:"legal_holds"`
+	Page       *LegalHoldPageInfo `json:"page"`
+}
+
+type ApprovalPage struct {
+	Approvals []*Approval       `json:"approvals"`
+	Page      *ApprovalPageInfo `json:"page"`
+}
+
 func PageInfoFrom(in *PageInfo) (out *PageInfo) {
 	out = &PageInfo{
 		PageSize: DefaultPageSize,
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"go.rtnl.ai/ulid"
+
+	api "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+// QueuedTransfer is an outgoing transfer that could not be delivered to the
+// counterparty (or that is scheduled to be sent later) and that is retried by the
+// outbound queue until it is sent, it fails too many times, or it is canceled. The
+// payload of the transfer is sealed with the storage key of the local node so that it
+// can be sent exactly as it was prepared.
+type QueuedTransfer struct {
+	Model
+	TransactionID  uuid.UUID           // the transaction of the transfer (also the envelope ID)
+	CounterpartyID ulid.ULID           // the counterparty the transfer is sent to
+	Status         enum.QueueStatus    // one of queued, sent, failed, or canceled
+	Envelope       *api.SecureEnvelope // the payload sealed with the local storage key
+	Attempts       int64               // the number of attempts to deliver the transfer
+	LastError      sql.NullString      // the error of the most recent failed attempt
+	LastAttempt    sql.NullTime        // the timestamp of the most recent attempt
+	NextAttempt    time.Time           // the transfer is sent by the queue after this timestamp
+	Counterparty   string              // the name of the counterparty (not stored)
+	VirtualAsset   string              // the virtual asset of the transaction (not stored)
+	Amount         float64             // the amount of the transaction (not stored)
+}
+
+// Scan a complete SELECT into the queued transfer model
+func (q *QueuedTransfer) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&q.ID,
+		&q.TransactionID,
+		&q.CounterpartyID,
+		&q.Status,
+		&q.Envelope,
+		&q.Attempts,
+		&q.LastError,
+		&q.LastAttempt,
+		&q.NextAttempt,
+		&q.Created,
+		&q.Modified,
+		&q.Counterparty,
+		&q.VirtualAsset,
+		&q.Amount,
+	)
+}
+
+// Scan a partial SELECT (without the sealed envelope) into the queued transfer model
+func (q *QueuedTransfer) ScanSummary(scanner Scanner) error {
+	return scanner.Scan(
+		&q.ID,
+		&q.TransactionID,
+		&q.CounterpartyID,
+		&q.Status,
+		&q.Attempts,
+		&q.LastError,
+		&q.LastAttempt,
+		&q.NextAttempt,
+		&q.Created,
+		&q.Modified,
+		&q.Counterparty,
+		&q.VirtualAsset,
+		&q.Amount,
+	)
+}
+
+// Get the complete named params of the queued transfer from the model.
+func (q *QueuedTransfer) Params() []any {
+	return []any{
+		sql.Named("id", q.ID),
+		sql.Named("transactionID", q.TransactionID),
+		sql.Named("counterpartyID", q.CounterpartyID),
+		sql.Named("status", q.Status),
+		sql.Named("envelope", q.Envelope),
+		sql.Named("attempts", q.Attempts),
+		sql.Named("lastError", q.LastError),
+		sql.Named("lastAttempt", q.LastAttempt),
+		sql.Named("nextAttempt", q.NextAttempt),
+		sql.Named("created", q.Created),
+		sql.Named("modified", q.Modified),
+	}
+}
+
+// Returns true if the transfer is waiting to be sent by the outbound queue.
+func (q *QueuedTransfer) IsQueued() bool {
+	return q.Status == enum.QueueQueued
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Report is a regulatory report that summarizes the travel rule transfers created
+// during a period. The statistics of the report are computed when it is generated and
+// stored as JSON so that the report can be downloaded later in any format without
+// being recomputed, even if the transactions are modified or deleted.
+type Report struct {
+	Model
+	Name        string         // a descriptive name of the report
+	Schedule    sql.NullString // the schedule of the report if it was generated by the scheduler
+	PeriodStart time.Time      // transactions created on or after this timestamp are reported
+	PeriodEnd   time.Time      // transactions created before this timestamp are reported
+	Data        []byte         // the JSON statistics of the report (not returned by ListReports)
+	CreatedBy   string         // the name of the user or api key that generated the report
+}
+
+// Scan a complete SELECT into the report model
+func (r *Report) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&r.ID,
+		&r.Name,
+		&r.Schedule,
+		&r.PeriodStart,
+		&r.PeriodEnd,
+		&r.Data,
+		&r.CreatedBy,
+		&r.Created,
+		&r.Modified,
+	)
+}
+
+// Scan a partial SELECT (without the data) into the report model
+func (r *Report) ScanSummary(scanner Scanner) error {
+	return scanner.Scan(
+		&r.ID,
+		&r.Name,
+		&r.Schedule,
+		&r.PeriodStart,
+		&r.PeriodEnd,
+		&r.CreatedBy,
+		&r.Created,
+		&r.Modified,
+	)
+}
+
+// Get the complete named params of the report from the model.
+func (r *Report) Params() []any {
+	return []any{
+		sql.Named("id", r.ID),
+		sql.Named("name", r.Name),
+		sql.Named("schedule", r.Schedule),
+		sql.Named("periodStart", r.PeriodStart),
+		sql.Named("periodEnd", r.PeriodEnd),
+		sql.Named("data", r.Data),
+		sql.Named("createdBy", r.CreatedBy),
+		sql.Named("created", r.Created),
+		sql.Named("modified", r.Modified),
+	}
+}
+
+// EnvelopeTiming records when the first secure envelope of a transaction was sent to
+// and received from the counterparty so that reports can compute response times.
+type EnvelopeTiming struct {
+	TransactionID uuid.UUID    // the transaction of the envelopes
+	FirstOutgoing sql.NullTime // the timestamp of the first envelope sent to the counterparty
+	FirstIncoming sql.NullTime // the timestamp of the first envelope received from the counterparty
+}
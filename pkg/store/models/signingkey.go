@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ###########################################################################
+// SigningKey
+// ###########################################################################
+
+// SigningKey is an RSA key used to sign the access and refresh tokens issued by the
+// node. The ID of the model is the kid of the tokens signed by the key. The key signs
+// tokens between NotBefore and NotAfter and is published to verify the tokens it has
+// signed until it Expires.
+type SigningKey struct {
+	Model
+	PrivateKey []byte    // the PEM encoded private key (decrypted)
+	NotBefore  time.Time // the key is not used to sign tokens before this time
+	NotAfter   time.Time // the key is not used to sign tokens after this time
+	Expires    time.Time // tokens signed by the key are not verified after this time
+}
+
+// Scan a complete SELECT into the signing key model
+func (k *SigningKey) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&k.ID,
+		&k.PrivateKey,
+		&k.NotBefore,
+		&k.NotAfter,
+		&k.Expires,
+		&k.Created,
+		&k.Modified,
+	)
+}
+
+// Get the complete named params of the signing key from the model.
+func (k *SigningKey) Params() []any {
+	return []any{
+		sql.Named("id", k.ID),
+		sql.Named("privateKey", k.PrivateKey),
+		sql.Named("notBefore", k.NotBefore),
+		sql.Named("notAfter", k.NotAfter),
+		sql.Named("expires", k.Expires),
+		sql.Named("created", k.Created),
+		sql.Named("modified", k.Modified),
+	}
+}
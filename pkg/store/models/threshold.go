@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// ThresholdRule defines the fiat-equivalent amount at or above which a transfer
+// between the originator and beneficiary jurisdictions requires complete travel rule
+// data (e.g. a zero threshold in the EU or USD 3,000 in the US). Each criteria of the
+// rule is optional; an unset criteria matches any value. If more than one rule matches
+// a transfer, the most specific rule (the rule with the most criteria set) applies.
+type ThresholdRule struct {
+	Model
+	Description        string         // a human readable description of the rule
+	OriginatorCountry  sql.NullString // the alpha-2 country code of the originator jurisdiction
+	BeneficiaryCountry sql.NullString // the alpha-2 country code of the beneficiary jurisdiction
+	VirtualAsset       sql.NullString // the network or asset type the rule applies to (case-insensitive)
+	Threshold          float64        // travel rule data is required for fiat-equivalent amounts greater than or equal to the threshold
+}
+
+// Scan a complete SELECT into the threshold rule model
+func (r *ThresholdRule) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&r.ID,
+		&r.Description,
+		&r.OriginatorCountry,
+		&r.BeneficiaryCountry,
+		&r.VirtualAsset,
+		&r.Threshold,
+		&r.Created,
+		&r.Modified,
+	)
+}
+
+// Get the complete named params of the threshold rule from the model.
+func (r *ThresholdRule) Params() []any {
+	return []any{
+		sql.Named("id", r.ID),
+		sql.Named("description", r.Description),
+		sql.Named("originatorCountry", r.OriginatorCountry),
+		sql.Named("beneficiaryCountry", r.BeneficiaryCountry),
+		sql.Named("virtualAsset", r.VirtualAsset),
+		sql.Named("threshold", r.Threshold),
+		sql.Named("created", r.Created),
+		sql.Named("modified", r.Modified),
+	}
+}
+
+// Matches returns true if the originator country, beneficiary country, and virtual
+// asset of a transfer meet all of the criteria of the rule. Countries are compared
+// case-insensitively; an unknown country only matches rules without that criteria.
+func (r *ThresholdRule) Matches(originatorCountry, beneficiaryCountry, virtualAsset string) bool {
+	if r.OriginatorCountry.Valid && !strings.EqualFold(strings.TrimSpace(r.OriginatorCountry.String), strings.TrimSpace(originatorCountry)) {
+		return false
+	}
+
+	if r.BeneficiaryCountry.Valid && !strings.EqualFold(strings.TrimSpace(r.BeneficiaryCountry.String), strings.TrimSpace(beneficiaryCountry)) {
+		return false
+	}
+
+	if r.VirtualAsset.Valid && !MatchVirtualAsset(r.VirtualAsset.String, virtualAsset) {
+		return false
+	}
+
+	return true
+}
+
+// Specificity returns the number of criteria that are set on the rule so that the most
+// specific of several matching rules can be applied to a transfer.
+func (r *ThresholdRule) Specificity() (n int) {
+	for _, criteria := range []bool{r.OriginatorCountry.Valid, r.BeneficiaryCountry.Valid, r.VirtualAsset.Valid} {
+		if criteria {
+			n++
+		}
+	}
+	return n
+}
@@ -2,6 +2,9 @@ package models
 
 import (
 	"database/sql"
+	"io"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/trisacrypto/envoy/pkg/enum"
@@ -35,10 +38,28 @@ type Transaction struct {
 	LastUpdate         sql.NullTime      // The last time a TRISA RPC occurred for this transaction
 	Created            time.Time         // Timestamp the transaction was created
 	Modified           time.Time         // Timestamp the transaction was last modified, including when a new secure envelope was received
+	ReplyNotAfter      sql.NullTime      // The deadline for the reply to the most recent pending message, if any
+	TravelRuleRequired sql.NullBool      // If travel rule data is required by the threshold rules (null if not evaluated)
+	FiatAmount         sql.NullFloat64   // The fiat-equivalent amount of the transfer compared to the threshold, if known
+	FiatCurrency       sql.NullString    // The currency of the fiat-equivalent amount
+	ThresholdRuleID    ulid.NullULID     // The threshold rule that determined if travel rule data is required, if any
+	replyRemindedOn    sql.NullTime      // When the reminder for the current deadline was sent (read-only, see MarkReplyReminded)
+	replyExpiredOn     sql.NullTime      // When the transaction expired because the deadline passed (read-only, see MarkReplyExpired)
 	numEnvelopes       int64             // The number of secure envelopes associated with the transaction
 	envelopes          []*SecureEnvelope // Associated secure envelopes
+	assigneeID         ulid.NullULID     // The user responsible for reviewing the transaction (read-only, see AssignTransaction)
+	assignee           sql.NullString    // The name of the assigned user
+	tags               []string          // Free-form labels used to filter transactions (read-only, see SetTransactionTags)
 }
 
+// Reply deadline statuses of transactions that are awaiting a reply.
+const (
+	ReplyOnTime  = "on_time"
+	ReplyDueSoon = "due_soon"
+	ReplyOverdue = "overdue"
+	ReplyExpired = "expired"
+)
+
 type TransactionCounts struct {
 	Active   map[string]int // Active transaction counts by status
 	Archived map[string]int // Archived transaction counts by status
@@ -48,9 +69,58 @@ type TransactionPageInfo struct {
 	PageInfo
 	Status       []string `json:"status,omitempty"`
 	VirtualAsset []string `json:"asset,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Overdue      bool     `json:"overdue,omitempty"`
 	Archives     bool     `json:"archives,omitempty"`
 }
 
+// TransactionFilter selects the transactions returned by a TransactionIterator, e.g.
+// for an export. Zero values are not used to filter the transactions.
+type TransactionFilter struct {
+	Status         []string  // Only include transactions with one of these statuses
+	VirtualAsset   []string  // Only include transactions of one of these assets
+	CounterpartyID ulid.ULID // Only include transactions with this counterparty
+	After          time.Time // Only include transactions created on or after this time
+	Before         time.Time // Only include transactions created before this time
+	Archives       bool      // Iterate over archived rather than active transactions
+}
+
+// TransactionIterator streams transactions from the database one at a time so that
+// large result sets are never loaded into memory. The iterator must be closed when
+// iteration is complete to release the database resources it holds.
+type TransactionIterator interface {
+	io.Closer
+	Next() bool
+	Transaction() *Transaction
+	Err() error
+}
+
+// TransactionNote is an investigator's comment in the review thread of a transaction.
+// Notes cannot be modified once they are posted.
+type TransactionNote struct {
+	ID            ulid.ULID  // Unique ID of the note
+	TransactionID uuid.UUID  // The transaction the note is posted on
+	Body          string     // The text of the note
+	Author        string     // The name of the user or api key that posted the note
+	AuthorID      []byte     // The actor id of the user or api key that posted the note
+	AuthorType    enum.Actor // The type of actor that posted the note
+	Created       time.Time  // Timestamp the note was posted
+}
+
+// StatusTransition records a change of the status of a transaction. Transitions are
+// validated by the state machine of the status and cannot be modified once recorded.
+type StatusTransition struct {
+	ID            ulid.ULID      // Unique ID of the transition
+	TransactionID uuid.UUID      // The transaction whose status changed
+	From          enum.Status    // The status of the transaction before the change
+	To            enum.Status    // The status of the transaction after the change
+	ActorID       []byte         // The actor id of the user, api key, or system that made the change
+	ActorType     enum.Actor     // The type of actor that made the change
+	EnvelopeID    ulid.NullULID  // The secure envelope that caused the change, if any
+	Created       time.Time      // Timestamp the status changed
+	Actor         sql.NullString // The name of the user or api key that made the change (not stored)
+}
+
 type SecureEnvelope struct {
 	Model
 	EnvelopeID    uuid.UUID           // Also a foreign key reference to the Transaction
@@ -83,6 +153,8 @@ type PreparedTransaction interface {
 	CreateSunrise(*Sunrise, *ComplianceAuditLog) error                     // Create a sunrise message sent to the counterparty for the transaction
 	UpdateSunrise(*Sunrise, *ComplianceAuditLog) error                     // Update the sunrise message
 	UpdateSunriseStatus(uuid.UUID, enum.Status, *ComplianceAuditLog) error // Update the status of all sunrise messages
+	Enqueue(*QueuedTransfer, *ComplianceAuditLog) error                    // Add the transfer to the outbound queue to be sent later
+	UpdateQueuedTransfer(*QueuedTransfer, *ComplianceAuditLog) error       // Update the status of a transfer in the outbound queue
 	Rollback() error                                                       // Rollback the prepared transaction and conclude it
 	Commit() error                                                         // Commit the prepared transaction and conclude it
 }
@@ -94,8 +166,9 @@ type EnvelopeStorage interface {
 	AddEnvelope(*SecureEnvelope) error
 }
 
-func (t *Transaction) Scan(scanner Scanner) error {
-	return scanner.Scan(
+func (t *Transaction) Scan(scanner Scanner) (err error) {
+	var tags sql.NullString
+	if err = scanner.Scan(
 		&t.ID,
 		&t.Source,
 		&t.Status,
@@ -112,11 +185,27 @@ func (t *Transaction) Scan(scanner Scanner) error {
 		&t.LastUpdate,
 		&t.Created,
 		&t.Modified,
-	)
+		&t.ReplyNotAfter,
+		&t.replyRemindedOn,
+		&t.replyExpiredOn,
+		&t.TravelRuleRequired,
+		&t.FiatAmount,
+		&t.FiatCurrency,
+		&t.ThresholdRuleID,
+		&t.assigneeID,
+		&t.assignee,
+		&tags,
+	); err != nil {
+		return err
+	}
+
+	t.tags = SplitTags(tags.String)
+	return nil
 }
 
-func (t *Transaction) ScanWithCount(scanner Scanner) error {
-	return scanner.Scan(
+func (t *Transaction) ScanWithCount(scanner Scanner) (err error) {
+	var tags sql.NullString
+	if err = scanner.Scan(
 		&t.ID,
 		&t.Source,
 		&t.Status,
@@ -133,8 +222,23 @@ func (t *Transaction) ScanWithCount(scanner Scanner) error {
 		&t.LastUpdate,
 		&t.Created,
 		&t.Modified,
+		&t.ReplyNotAfter,
+		&t.replyRemindedOn,
+		&t.replyExpiredOn,
+		&t.TravelRuleRequired,
+		&t.FiatAmount,
+		&t.FiatCurrency,
+		&t.ThresholdRuleID,
+		&t.assigneeID,
+		&t.assignee,
+		&tags,
 		&t.numEnvelopes,
-	)
+	); err != nil {
+		return err
+	}
+
+	t.tags = SplitTags(tags.String)
+	return nil
 }
 
 func (t *Transaction) Params() []any {
@@ -155,6 +259,11 @@ func (t *Transaction) Params() []any {
 		sql.Named("lastUpdate", t.LastUpdate),
 		sql.Named("created", t.Created),
 		sql.Named("modified", t.Modified),
+		sql.Named("replyNotAfter", utcNullTime(t.ReplyNotAfter)),
+		sql.Named("travelRuleRequired", t.TravelRuleRequired),
+		sql.Named("fiatAmount", t.FiatAmount),
+		sql.Named("fiatCurrency", t.FiatCurrency),
+		sql.Named("thresholdRuleID", t.ThresholdRuleID),
 	}
 }
 
@@ -180,6 +289,78 @@ func (t *Transaction) SetSecureEnvelopes(envelopes []*SecureEnvelope) {
 	t.envelopes = envelopes
 }
 
+// AssigneeID returns the ID of the user the transaction is assigned to, if any.
+func (t *Transaction) AssigneeID() ulid.NullULID {
+	return t.assigneeID
+}
+
+// Assignee returns the name of the user the transaction is assigned to, if any.
+func (t *Transaction) Assignee() string {
+	return t.assignee.String
+}
+
+func (t *Transaction) SetAssignee(userID ulid.NullULID, name string) {
+	t.assigneeID = userID
+	t.assignee = sql.NullString{Valid: name != "", String: name}
+}
+
+// Tags returns the tags of the transaction sorted alphabetically.
+func (t *Transaction) Tags() []string {
+	return t.tags
+}
+
+func (t *Transaction) SetTags(tags []string) {
+	t.tags = NormalizeTags(tags)
+}
+
+// ReplyRemindedOn returns when the reminder for the current reply deadline was sent.
+func (t *Transaction) ReplyRemindedOn() sql.NullTime {
+	return t.replyRemindedOn
+}
+
+// ReplyExpiredOn returns when the transaction expired because its reply deadline passed.
+func (t *Transaction) ReplyExpiredOn() sql.NullTime {
+	return t.replyExpiredOn
+}
+
+func (t *Transaction) SetReplyState(remindedOn, expiredOn sql.NullTime) {
+	t.replyRemindedOn = remindedOn
+	t.replyExpiredOn = expiredOn
+}
+
+// AwaitingReply returns true if the transaction has a reply deadline and is waiting on
+// a response from either the local compliance team or the counterparty.
+func (t *Transaction) AwaitingReply() bool {
+	if !t.ReplyNotAfter.Valid || t.Archived {
+		return false
+	}
+
+	switch t.Status {
+	case enum.StatusPending, enum.StatusReview, enum.StatusRepair:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReplyStatus returns the status of the reply deadline at the specified time or an
+// empty string if the transaction is not awaiting a reply. A transaction is due soon
+// once a reminder has been sent for its deadline.
+func (t *Transaction) ReplyStatus(now time.Time) string {
+	switch {
+	case !t.AwaitingReply():
+		return ""
+	case t.replyExpiredOn.Valid:
+		return ReplyExpired
+	case now.After(t.ReplyNotAfter.Time):
+		return ReplyOverdue
+	case t.replyRemindedOn.Valid:
+		return ReplyDueSoon
+	default:
+		return ReplyOnTime
+	}
+}
+
 // Update the transaction t with values from other if the field in other is non-zero;
 // e.g. if a nullable field is valid or an empty string is empty. This method skips the
 // ID and Modified fields.
@@ -228,6 +409,19 @@ func (t *Transaction) Update(other *Transaction) {
 		t.LastUpdate = other.LastUpdate
 	}
 
+	if other.ReplyNotAfter.Valid {
+		t.ReplyNotAfter = other.ReplyNotAfter
+	}
+
+	// The threshold evaluation is replaced as a whole so that a rule or fiat amount
+	// from a previous evaluation is not combined with the result of a new evaluation.
+	if other.TravelRuleRequired.Valid {
+		t.TravelRuleRequired = other.TravelRuleRequired
+		t.FiatAmount = other.FiatAmount
+		t.FiatCurrency = other.FiatCurrency
+		t.ThresholdRuleID = other.ThresholdRuleID
+	}
+
 	if !other.Created.IsZero() {
 		t.Created = other.Created
 	}
@@ -336,3 +530,100 @@ func (c *TransactionCounts) Total() int {
 	}
 	return total
 }
+
+// Scan a complete SELECT into the transaction note model
+func (n *TransactionNote) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&n.ID,
+		&n.TransactionID,
+		&n.Body,
+		&n.Author,
+		&n.AuthorID,
+		&n.AuthorType,
+		&n.Created,
+	)
+}
+
+// Get the complete named params of the transaction note from the model.
+func (n *TransactionNote) Params() []any {
+	return []any{
+		sql.Named("id", n.ID),
+		sql.Named("transactionID", n.TransactionID),
+		sql.Named("body", n.Body),
+		sql.Named("author", n.Author),
+		sql.Named("authorID", n.AuthorID),
+		sql.Named("authorType", n.AuthorType),
+		sql.Named("created", n.Created),
+	}
+}
+
+// Scan a complete SELECT into the status transition model; the query must also select
+// the name of the actor as the last column.
+func (s *StatusTransition) Scan(scanner Scanner) error {
+	return scanner.Scan(
+		&s.ID,
+		&s.TransactionID,
+		&s.From,
+		&s.To,
+		&s.ActorID,
+		&s.ActorType,
+		&s.EnvelopeID,
+		&s.Created,
+		&s.Actor,
+	)
+}
+
+// Get the complete named params of the status transition from the model.
+func (s *StatusTransition) Params() []any {
+	return []any{
+		sql.Named("id", s.ID),
+		sql.Named("transactionID", s.TransactionID),
+		sql.Named("fromStatus", s.From),
+		sql.Named("toStatus", s.To),
+		sql.Named("actorID", s.ActorID),
+		sql.Named("actorType", s.ActorType),
+		sql.Named("envelopeID", s.EnvelopeID),
+		sql.Named("created", s.Created),
+	}
+}
+
+// TagSeparator separates the tags of a transaction when they are aggregated in a
+// single column; tags cannot contain the separator.
+const TagSeparator = ","
+
+// NormalizeTag trims whitespace from the tag and converts it to lowercase so that tags
+// are matched case-insensitively.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags normalizes each tag, removes empty and duplicate tags, and sorts the
+// tags alphabetically.
+func NormalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			out = append(out, tag)
+		}
+	}
+
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// SplitTags parses the tags aggregated in a single column into normalized tags.
+func SplitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return NormalizeTags(strings.Split(tags, TagSeparator))
+}
+
+// Deadlines are stored in UTC so that they can be compared to other timestamps in the
+// database regardless of the time zone of the counterparty that set them.
+func utcNullTime(t sql.NullTime) sql.NullTime {
+	if t.Valid {
+		t.Time = t.Time.UTC()
+	}
+	return t
+}
@@ -107,6 +107,16 @@ func TestTransactionScan(t *testing.T) {
 			time.Now(),                 // LastUpdate
 			time.Now(),                 // Created
 			time.Now(),                 // Modified
+			time.Now(),                 // ReplyNotAfter
+			time.Now(),                 // ReplyRemindedOn
+			nil,                        // ReplyExpiredOn (testing null time)
+			true,                       // TravelRuleRequired
+			float64(3250.5),            // FiatAmount
+			"USD",                      // FiatCurrency
+			ulid.MakeSecure().String(), // ThresholdRuleID
+			ulid.MakeSecure().String(), // AssigneeID
+			"Assignee",                 // Assignee
+			"kyc,review,sanctions",     // Tags
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -134,6 +144,16 @@ func TestTransactionScan(t *testing.T) {
 		require.Equal(t, data[13], model.LastUpdate.Time, "expected field LastUpdate to match data[13]")
 		require.Equal(t, data[14], model.Created, "expected field Created to match data[14]")
 		require.Equal(t, data[15], model.Modified, "expected field Modified to match data[15]")
+		require.Equal(t, data[16], model.ReplyNotAfter.Time, "expected field ReplyNotAfter to match data[16]")
+		require.Equal(t, data[17], model.ReplyRemindedOn().Time, "expected field ReplyRemindedOn to match data[17]")
+		require.False(t, model.ReplyExpiredOn().Valid, "expected field ReplyExpiredOn to be null")
+		require.Equal(t, data[19], model.TravelRuleRequired.Bool, "expected field TravelRuleRequired to match data[19]")
+		require.Equal(t, data[20], model.FiatAmount.Float64, "expected field FiatAmount to match data[20]")
+		require.Equal(t, data[21], model.FiatCurrency.String, "expected field FiatCurrency to match data[21]")
+		require.Equal(t, data[22], model.ThresholdRuleID.ULID.String(), "expected field ThresholdRuleID to match data[22]")
+		require.Equal(t, data[23], model.AssigneeID().ULID.String(), "expected field AssigneeID to match data[23]")
+		require.Equal(t, data[24], model.Assignee(), "expected field Assignee to match data[24]")
+		require.Equal(t, []string{"kyc", "review", "sanctions"}, model.Tags(), "expected field Tags to match data[25]")
 	})
 
 	t.Run("SuccessNulls", func(t *testing.T) {
@@ -155,6 +175,16 @@ func TestTransactionScan(t *testing.T) {
 			nil,                        // LastUpdate (testing null time)
 			time.Now(),                 // Created
 			time.Now(),                 // Modified
+			nil,                        // ReplyNotAfter (testing null time)
+			nil,                        // ReplyRemindedOn (testing null time)
+			nil,                        // ReplyExpiredOn (testing null time)
+			nil,                        // TravelRuleRequired (testing null bool)
+			nil,                        // FiatAmount (testing null float)
+			nil,                        // FiatCurrency (testing null string)
+			nil,                        // ThresholdRuleID (testing null ulid)
+			nil,                        // AssigneeID (testing null ulid)
+			nil,                        // Assignee (testing null string)
+			nil,                        // Tags (testing null string)
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -164,6 +194,9 @@ func TestTransactionScan(t *testing.T) {
 		err := model.Scan(mockScanner)
 		require.NoError(t, err, "expected no errors from the scanner")
 		mockScanner.AssertScanned(t, len(data))
+		require.False(t, model.TravelRuleRequired.Valid, "expected a null travel rule requirement")
+		require.False(t, model.AssigneeID().Valid, "expected a null assignee")
+		require.Empty(t, model.Tags(), "expected no tags")
 	})
 
 	t.Run("InvalidSource", func(t *testing.T) {
@@ -185,6 +218,16 @@ func TestTransactionScan(t *testing.T) {
 			nil,                        // LastUpdate (testing null time)
 			time.Now(),                 // Created
 			time.Now(),                 // Modified
+			nil,                        // ReplyNotAfter (testing null time)
+			nil,                        // ReplyRemindedOn (testing null time)
+			nil,                        // ReplyExpiredOn (testing null time)
+			nil,                        // TravelRuleRequired (testing null bool)
+			nil,                        // FiatAmount (testing null float)
+			nil,                        // FiatCurrency (testing null string)
+			nil,                        // ThresholdRuleID (testing null ulid)
+			nil,                        // AssigneeID (testing null ulid)
+			nil,                        // Assignee (testing null string)
+			nil,                        // Tags (testing null string)
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -215,6 +258,16 @@ func TestTransactionScan(t *testing.T) {
 			nil,                        // LastUpdate (testing null time)
 			time.Now(),                 // Created
 			time.Now(),                 // Modified
+			nil,                        // ReplyNotAfter (testing null time)
+			nil,                        // ReplyRemindedOn (testing null time)
+			nil,                        // ReplyExpiredOn (testing null time)
+			nil,                        // TravelRuleRequired (testing null bool)
+			nil,                        // FiatAmount (testing null float)
+			nil,                        // FiatCurrency (testing null string)
+			nil,                        // ThresholdRuleID (testing null ulid)
+			nil,                        // AssigneeID (testing null ulid)
+			nil,                        // Assignee (testing null string)
+			nil,                        // Tags (testing null string)
 		}
 		mockScanner := &mock.MockScanner{}
 		mockScanner.SetData(data)
@@ -227,6 +280,25 @@ func TestTransactionScan(t *testing.T) {
 	})
 }
 
+func TestNormalizeTags(t *testing.T) {
+	testCases := []struct {
+		tags     []string
+		expected []string
+	}{
+		{nil, []string{}},
+		{[]string{"", "  "}, []string{}},
+		{[]string{"Sanctions"}, []string{"sanctions"}},
+		{[]string{" review ", "KYC", "kyc", "Review"}, []string{"kyc", "review"}},
+	}
+
+	for i, tc := range testCases {
+		require.Equal(t, tc.expected, models.NormalizeTags(tc.tags), "test case %d failed", i)
+	}
+
+	require.Nil(t, models.SplitTags(""))
+	require.Equal(t, []string{"kyc", "review"}, models.SplitTags("review,kyc"))
+}
+
 func TestSecureEnvelopeScan(t *testing.T) {
 	t.Run("SuccessFilled", func(t *testing.T) {
 		// setup
@@ -0,0 +1,162 @@
+package pii
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/secrets"
+	"github.com/trisacrypto/envoy/pkg/trisa/keychain"
+	"github.com/trisacrypto/trisa/pkg/trisa/crypto/rsaoeap"
+	"github.com/trisacrypto/trisa/pkg/trisa/keys"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+)
+
+// KeyStore persists the data encryption key wrapped by the node's storage key so that
+// the key survives restarts without being stored in plaintext.
+type KeyStore interface {
+	RetrieveDataKey(context.Context) (*models.DataKey, error)
+	CreateDataKey(context.Context, *models.DataKey) error
+}
+
+// SecretStore retrieves the data encryption key from a secret manager such as the
+// store.Secrets interface.
+type SecretStore interface {
+	RetrieveSecret(context.Context, *secrets.Secret) error
+}
+
+// FromKey creates a Cipher from a base64 encoded data encryption key, e.g. from the
+// environment or configuration.
+func FromKey(encoded string) (_ *Cipher, err error) {
+	var key []byte
+	if key, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+		return nil, fmt.Errorf("could not decode field encryption key: %w", err)
+	}
+	return New(key)
+}
+
+// AddPreviousKeys adds the base64 encoded data encryption keys that were used before
+// the current key to the cipher so that existing values can be reencrypted.
+func AddPreviousKeys(c *Cipher, encoded ...string) (err error) {
+	for _, enc := range encoded {
+		var key []byte
+		if key, err = base64.StdEncoding.DecodeString(enc); err != nil {
+			return fmt.Errorf("could not decode previous field encryption key: %w", err)
+		}
+
+		if err = c.AddPreviousKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FromSecret creates a Cipher from a data encryption key stored in the secret manager.
+// The secret may contain either the raw 32 byte key or the base64 encoding of the key.
+func FromSecret(ctx context.Context, sm SecretStore, namespace, name string) (_ *Cipher, err error) {
+	secret := &secrets.Secret{Namespace: namespace, Name: name}
+	if err = sm.RetrieveSecret(ctx, secret); err != nil {
+		return nil, fmt.Errorf("could not retrieve field encryption key secret: %w", err)
+	}
+
+	if len(secret.Data) == KeySize {
+		return New(secret.Data)
+	}
+	return FromKey(string(secret.Data))
+}
+
+// FromKeyChain creates a Cipher from a data encryption key that is wrapped with the
+// node's storage key and persisted in the key store. If no data key exists, a new one
+// is generated, wrapped with the default storage key, and stored. The signature of the
+// wrapping key is stored so the key can still be unwrapped after certificate rotation
+// as long as the previous keys remain in the keychain.
+func FromKeyChain(ctx context.Context, kc keychain.KeyChain, ks KeyStore) (_ *Cipher, err error) {
+	var dk *models.DataKey
+	if dk, err = ks.RetrieveDataKey(ctx); err != nil {
+		if !errors.Is(err, dberr.ErrNotFound) {
+			return nil, err
+		}
+
+		var key []byte
+		if key, err = GenerateKey(); err != nil {
+			return nil, err
+		}
+
+		if dk, err = wrapKey(kc, key); err != nil {
+			return nil, err
+		}
+
+		if err = ks.CreateDataKey(ctx, dk); err != nil {
+			return nil, err
+		}
+
+		return New(key)
+	}
+
+	var key []byte
+	if key, err = unwrapKey(kc, dk); err != nil {
+		return nil, err
+	}
+
+	return New(key)
+}
+
+func wrapKey(kc keychain.KeyChain, key []byte) (dk *models.DataKey, err error) {
+	var pubkey keys.PublicKey
+	if pubkey, err = kc.StorageKey("", ""); err != nil {
+		return nil, fmt.Errorf("could not retrieve storage key to wrap field encryption key: %w", err)
+	}
+
+	var sealingKey interface{}
+	if sealingKey, err = pubkey.SealingKey(); err != nil {
+		return nil, err
+	}
+
+	var wrapper *rsaoeap.RSA
+	if wrapper, err = rsaoeap.New(sealingKey); err != nil {
+		return nil, err
+	}
+
+	dk = &models.DataKey{}
+	if dk.WrappedKey, err = wrapper.Encrypt(key); err != nil {
+		return nil, err
+	}
+
+	if dk.Signature, err = pubkey.PublicKeySignature(); err != nil {
+		return nil, err
+	}
+
+	// Record the ID of the key so that the key can be matched with ciphertexts.
+	var cipher *Cipher
+	if cipher, err = New(key); err != nil {
+		return nil, err
+	}
+	dk.KeyID = cipher.KeyID()
+
+	return dk, nil
+}
+
+func unwrapKey(kc keychain.KeyChain, dk *models.DataKey) (key []byte, err error) {
+	var privkey keys.PrivateKey
+	if privkey, err = kc.UnsealingKey(dk.Signature, ""); err != nil {
+		return nil, fmt.Errorf("could not retrieve storage key to unwrap field encryption key: %w", err)
+	}
+
+	var unsealingKey interface{}
+	if unsealingKey, err = privkey.UnsealingKey(); err != nil {
+		return nil, err
+	}
+
+	var unwrapper *rsaoeap.RSA
+	if unwrapper, err = rsaoeap.New(unsealingKey); err != nil {
+		return nil, err
+	}
+
+	if key, err = unwrapper.Decrypt(dk.WrappedKey); err != nil {
+		return nil, fmt.Errorf("could not unwrap field encryption key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,234 @@
+/*
+Package pii implements transparent field-level encryption for personally identifiable
+information that is stored in the database. Values are encrypted with AES-256-GCM using
+a key derived from a 32 byte data encryption key and are serialized with a versioned
+prefix so that encrypted and plaintext values can be distinguished (e.g. when existing
+rows are migrated). Because encrypted values are randomized, equality lookups are
+performed against blind indexes: keyed HMAC-SHA256 digests of the plaintext value.
+*/
+package pii
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const (
+	KeySize     = 32
+	prefix      = "pii:v1:"
+	keyIDLength = 8
+)
+
+var (
+	ErrInvalidKeySize = fmt.Errorf("field encryption key must be %d bytes", KeySize)
+	ErrNoKey          = errors.New("value is encrypted but field encryption is not configured")
+	ErrUnknownKey     = errors.New("value was encrypted with an unknown field encryption key; configure the key as a previous key to rotate it")
+	ErrMalformed      = errors.New("could not parse encrypted field value")
+)
+
+// Cipher encrypts and decrypts field values and computes blind indexes. A nil Cipher
+// is valid and passes plaintext values through unmodified, returns ErrNoKey if asked
+// to decrypt an encrypted value, and computes unkeyed blind indexes so that equality
+// lookups still work when field encryption is not configured.
+//
+// To rotate the data encryption key, create the Cipher with the new key and add the
+// old key with AddPreviousKey. Values encrypted with a previous key can be decrypted
+// but are not Current, so they are reencrypted with the new key when migrated.
+type Cipher struct {
+	keyID    string
+	aead     cipher.AEAD
+	index    []byte
+	previous map[string]cipher.AEAD
+}
+
+// New creates a Cipher from a 32 byte data encryption key. Independent encryption
+// and blind index keys are derived from the data key so that the index digests
+// reveal nothing about the encryption key.
+func New(key []byte) (_ *Cipher, err error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	var encKey []byte
+	if encKey, err = hkdf.Key(sha256.New, key, nil, "envoy pii encryption", KeySize); err != nil {
+		return nil, err
+	}
+
+	c := &Cipher{}
+	if c.index, err = hkdf.Key(sha256.New, key, nil, "envoy pii blind index", KeySize); err != nil {
+		return nil, err
+	}
+
+	var block cipher.Block
+	if block, err = aes.NewCipher(encKey); err != nil {
+		return nil, err
+	}
+
+	if c.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+
+	// The key ID identifies which key encrypted a value without revealing the key.
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("envoy pii key id"))
+	c.keyID = hex.EncodeToString(mac.Sum(nil))[:keyIDLength]
+
+	return c, nil
+}
+
+// AddPreviousKey allows values encrypted with a previous data encryption key to be
+// decrypted so that they can be reencrypted with the current key.
+func (c *Cipher) AddPreviousKey(key []byte) (err error) {
+	var prev *Cipher
+	if prev, err = New(key); err != nil {
+		return err
+	}
+
+	if prev.keyID == c.keyID {
+		return nil
+	}
+
+	if c.previous == nil {
+		c.previous = make(map[string]cipher.AEAD, 1)
+	}
+	c.previous[prev.keyID] = prev.aead
+	return nil
+}
+
+// GenerateKey returns a new random data encryption key.
+func GenerateKey() (key []byte, err error) {
+	key = make([]byte, KeySize)
+	if _, err = rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// KeyID returns a short identifier of the data encryption key, or "plaintext" if the
+// cipher is nil and field encryption is not configured.
+func (c *Cipher) KeyID() string {
+	if c == nil {
+		return "plaintext"
+	}
+	return c.keyID
+}
+
+// Encrypt the plaintext value, returning the serialized ciphertext. If the cipher is
+// nil or the value is empty the plaintext is returned unmodified.
+func (c *Cipher) Encrypt(plaintext []byte) (_ []byte, err error) {
+	if c == nil || len(plaintext) == 0 {
+		return plaintext, nil
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+
+	out := make([]byte, 0, len(prefix)+keyIDLength+1+base64.RawStdEncoding.EncodedLen(len(sealed)))
+	out = append(out, prefix...)
+	out = append(out, c.keyID...)
+	out = append(out, ':')
+	out = base64.RawStdEncoding.AppendEncode(out, sealed)
+	return out, nil
+}
+
+// Decrypt the serialized ciphertext, returning the plaintext value. Values that are
+// not encrypted are returned unmodified so that rows that have not yet been migrated
+// can still be read.
+func (c *Cipher) Decrypt(value []byte) (_ []byte, err error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	if c == nil {
+		return nil, ErrNoKey
+	}
+
+	value = value[len(prefix):]
+	if len(value) < keyIDLength+1 || value[keyIDLength] != ':' {
+		return nil, ErrMalformed
+	}
+
+	aead := c.aead
+	if keyID := string(value[:keyIDLength]); keyID != c.keyID {
+		var ok bool
+		if aead, ok = c.previous[keyID]; !ok {
+			return nil, fmt.Errorf("%w (key id %s)", ErrUnknownKey, keyID)
+		}
+	}
+
+	var sealed []byte
+	if sealed, err = base64.RawStdEncoding.AppendDecode(nil, value[keyIDLength+1:]); err != nil {
+		return nil, ErrMalformed
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrMalformed
+	}
+
+	return aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+}
+
+// EncryptString is a helper for encrypting text columns.
+func (c *Cipher) EncryptString(plaintext string) (string, error) {
+	ciphertext, err := c.Encrypt([]byte(plaintext))
+	return string(ciphertext), err
+}
+
+// DecryptString is a helper for decrypting text columns.
+func (c *Cipher) DecryptString(value string) (string, error) {
+	plaintext, err := c.Decrypt([]byte(value))
+	return string(plaintext), err
+}
+
+// Current returns true if the value is encrypted with this cipher's key or if the
+// cipher is nil and the value is plaintext; e.g. the value does not need migration.
+func (c *Cipher) Current(value []byte) bool {
+	if !IsEncrypted(value) {
+		return c == nil || len(value) == 0
+	}
+
+	if c == nil {
+		return false
+	}
+
+	value = value[len(prefix):]
+	return len(value) > keyIDLength && string(value[:keyIDLength]) == c.keyID
+}
+
+// BlindIndex returns a deterministic hex encoded digest of the value that can be
+// stored alongside the encrypted value and used for equality lookups. Callers are
+// responsible for normalizing the value (e.g. lowercasing email addresses). Empty
+// values have an empty blind index.
+func (c *Cipher) BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if c == nil {
+		digest := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(digest[:])
+	}
+
+	mac := hmac.New(sha256.New, c.index)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted returns true if the value has been serialized by a Cipher.
+func IsEncrypted(value []byte) bool {
+	return bytes.HasPrefix(value, []byte(prefix))
+}
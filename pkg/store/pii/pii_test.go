@@ -0,0 +1,120 @@
+package pii_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
+)
+
+func TestCipher(t *testing.T) {
+	cipher, err := pii.New(bytes.Repeat([]byte{0x42}, pii.KeySize))
+	require.NoError(t, err, "could not create cipher")
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		ciphertext, err := cipher.EncryptString("Mary Elizabeth Morgan")
+		require.NoError(t, err, "could not encrypt value")
+		require.True(t, pii.IsEncrypted([]byte(ciphertext)), "expected value to be encrypted")
+		require.NotContains(t, ciphertext, "Morgan", "expected plaintext to not be in ciphertext")
+		require.True(t, cipher.Current([]byte(ciphertext)), "expected ciphertext to be current")
+
+		other, err := cipher.EncryptString("Mary Elizabeth Morgan")
+		require.NoError(t, err, "could not encrypt value")
+		require.NotEqual(t, ciphertext, other, "expected ciphertexts to be randomized")
+
+		plaintext, err := cipher.DecryptString(ciphertext)
+		require.NoError(t, err, "could not decrypt value")
+		require.Equal(t, "Mary Elizabeth Morgan", plaintext)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		ciphertext, err := cipher.EncryptString("")
+		require.NoError(t, err, "could not encrypt empty value")
+		require.Equal(t, "", ciphertext, "expected empty values to not be encrypted")
+		require.Equal(t, "", cipher.BlindIndex(""), "expected empty blind index")
+	})
+
+	t.Run("Plaintext", func(t *testing.T) {
+		plaintext, err := cipher.DecryptString("mary@example.com")
+		require.NoError(t, err, "expected plaintext to pass through decrypt")
+		require.Equal(t, "mary@example.com", plaintext)
+		require.False(t, cipher.Current([]byte(plaintext)), "expected plaintext to require encryption")
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		other, err := pii.New(bytes.Repeat([]byte{0x24}, pii.KeySize))
+		require.NoError(t, err, "could not create cipher")
+		require.NotEqual(t, cipher.KeyID(), other.KeyID())
+
+		ciphertext, err := other.EncryptString("mary@example.com")
+		require.NoError(t, err, "could not encrypt value")
+		require.False(t, cipher.Current([]byte(ciphertext)), "expected other key to not be current")
+
+		_, err = cipher.DecryptString(ciphertext)
+		require.ErrorIs(t, err, pii.ErrUnknownKey)
+	})
+
+	t.Run("PreviousKey", func(t *testing.T) {
+		oldKey := bytes.Repeat([]byte{0x24}, pii.KeySize)
+		old, err := pii.New(oldKey)
+		require.NoError(t, err, "could not create cipher")
+
+		ciphertext, err := old.EncryptString("mary@example.com")
+		require.NoError(t, err, "could not encrypt value")
+
+		rotated, err := pii.New(bytes.Repeat([]byte{0x42}, pii.KeySize))
+		require.NoError(t, err, "could not create cipher")
+		require.NoError(t, rotated.AddPreviousKey(oldKey), "could not add previous key")
+
+		plaintext, err := rotated.DecryptString(ciphertext)
+		require.NoError(t, err, "expected value encrypted with previous key to be decrypted")
+		require.Equal(t, "mary@example.com", plaintext)
+		require.False(t, rotated.Current([]byte(ciphertext)), "expected previous key to not be current")
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := cipher.DecryptString("pii:v1:" + cipher.KeyID() + ":!!!")
+		require.ErrorIs(t, err, pii.ErrMalformed)
+
+		_, err = cipher.DecryptString("pii:v1:abc")
+		require.ErrorIs(t, err, pii.ErrMalformed)
+	})
+
+	t.Run("BlindIndex", func(t *testing.T) {
+		idx := cipher.BlindIndex("mary@example.com")
+		require.Len(t, idx, 64, "expected hex encoded sha256 digest")
+		require.Equal(t, idx, cipher.BlindIndex("mary@example.com"), "expected blind index to be deterministic")
+		require.NotEqual(t, idx, cipher.BlindIndex("jane@example.com"))
+
+		var unkeyed *pii.Cipher
+		require.NotEqual(t, idx, unkeyed.BlindIndex("mary@example.com"), "expected blind index to be keyed")
+	})
+}
+
+func TestNilCipher(t *testing.T) {
+	var cipher *pii.Cipher
+	require.Equal(t, "plaintext", cipher.KeyID())
+
+	ciphertext, err := cipher.EncryptString("mary@example.com")
+	require.NoError(t, err, "expected nil cipher to pass through plaintext")
+	require.Equal(t, "mary@example.com", ciphertext)
+	require.True(t, cipher.Current([]byte(ciphertext)), "expected plaintext to be current")
+
+	encrypted, err := pii.New(bytes.Repeat([]byte{0x42}, pii.KeySize))
+	require.NoError(t, err, "could not create cipher")
+	ciphertext, err = encrypted.EncryptString("mary@example.com")
+	require.NoError(t, err, "could not encrypt value")
+
+	_, err = cipher.DecryptString(ciphertext)
+	require.ErrorIs(t, err, pii.ErrNoKey)
+}
+
+func TestNew(t *testing.T) {
+	_, err := pii.New([]byte("tooshort"))
+	require.ErrorIs(t, err, pii.ErrInvalidKeySize)
+
+	key, err := pii.GenerateKey()
+	require.NoError(t, err, "could not generate key")
+	require.Len(t, key, pii.KeySize)
+}
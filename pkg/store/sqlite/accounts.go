@@ -51,7 +51,7 @@ func (t *Tx) ListAccounts(page *models.PageInfo) (out *models.AccountsPage, err
 	for rows.Next() {
 		// Scan account into memory
 		account := &models.Account{}
-		if err = account.ScanSummary(rows); err != nil {
+		if err = account.ScanSummary(t.decrypt(rows, encryptedAccountSummaryFields(account)...)); err != nil {
 			return nil, err
 		}
 
@@ -103,8 +103,13 @@ func (t *Tx) CreateAccount(account *models.Account, auditLog *models.ComplianceA
 		account.TravelAddress = sql.NullString{Valid: travelAddress != "", String: travelAddress}
 	}
 
-	// Execute the insert into the database
-	if _, err = t.tx.Exec(createAccountSQL, account.Params()...); err != nil {
+	// Execute the insert into the database with the PII fields encrypted
+	var params []any
+	if params, err = t.accountParams(account); err != nil {
+		return err
+	}
+
+	if _, err = t.tx.Exec(createAccountSQL, params...); err != nil {
 		return dbe(err)
 	}
 
@@ -137,7 +142,7 @@ func (t *Tx) CreateAccount(account *models.Account, auditLog *models.ComplianceA
 	return nil
 }
 
-const lookupAccountSQL = "SELECT account_id FROM crypto_addresses WHERE crypto_address=:cryptoAddress"
+const lookupAccountSQL = "SELECT account_id FROM crypto_addresses WHERE crypto_address_idx=:cryptoAddressIdx"
 
 // Lookup an account by an associated crypto address.
 func (s *Store) LookupAccount(ctx context.Context, cryptoAddress string) (account *models.Account, err error) {
@@ -157,10 +162,11 @@ func (s *Store) LookupAccount(ctx context.Context, cryptoAddress string) (accoun
 	return account, nil
 }
 
-// Lookup an account by an associated crypto address.
+// Lookup an account by an associated crypto address using the blind index of the
+// crypto address since the crypto address itself is encrypted.
 func (t *Tx) LookupAccount(cryptoAddress string) (account *models.Account, err error) {
 	var accountID ulid.ULID
-	if err = t.tx.QueryRow(lookupAccountSQL, sql.Named("cryptoAddress", cryptoAddress)).Scan(&accountID); err != nil {
+	if err = t.tx.QueryRow(lookupAccountSQL, sql.Named("cryptoAddressIdx", t.cipher.BlindIndex(cryptoAddress))).Scan(&accountID); err != nil {
 		return nil, dbe(err)
 	}
 
@@ -194,7 +200,7 @@ func (s *Store) RetrieveAccount(ctx context.Context, accountID ulid.ULID) (accou
 // Retrieve account detail information including all associated crypto addresses.
 func (t *Tx) RetrieveAccount(accountID ulid.ULID) (account *models.Account, err error) {
 	account = &models.Account{}
-	if err = account.Scan(t.tx.QueryRow(retreiveAccountSQL, sql.Named("id", accountID))); err != nil {
+	if err = account.Scan(t.decrypt(t.tx.QueryRow(retreiveAccountSQL, sql.Named("id", accountID)), encryptedAccountFields(account)...)); err != nil {
 		return nil, dbe(err)
 	}
 
@@ -242,9 +248,14 @@ func (t *Tx) UpdateAccount(account *models.Account, auditLog *models.ComplianceA
 	// Update modified timestamp (in place).
 	account.Modified = time.Now()
 
-	// Execute the update into the database
+	// Execute the update into the database with the PII fields encrypted
+	var params []any
+	if params, err = t.accountParams(account); err != nil {
+		return err
+	}
+
 	var result sql.Result
-	if result, err = t.tx.Exec(updateAccountSQL, account.Params()...); err != nil {
+	if result, err = t.tx.Exec(updateAccountSQL, params...); err != nil {
 		return dbe(err)
 	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
 		return dberr.ErrNotFound
@@ -286,6 +297,14 @@ func (s *Store) DeleteAccount(ctx context.Context, accountID ulid.ULID, auditLog
 
 // Delete account and all associated crypto addresses
 func (t *Tx) DeleteAccount(accountID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	// Accounts under legal hold cannot be deleted
+	var held bool
+	if held, err = t.resourceOnHold(enum.ResourceAccount, accountID); err != nil {
+		return err
+	} else if held {
+		return dberr.ErrLegalHold
+	}
+
 	var result sql.Result
 	if result, err = t.tx.Exec(deleteAccountSQL, sql.Named("id", accountID)); err != nil {
 		return dbe(err)
@@ -313,13 +332,14 @@ func (t *Tx) DeleteAccount(accountID ulid.ULID, auditLog *models.ComplianceAudit
 }
 
 const listAccountTxnsSQL = `
-	WITH wallet AS (SELECT crypto_address FROM crypto_addresses WHERE account_id=:accountID)
-	SELECT t.id, t.source, t.status, t.counterparty, t.counterparty_id, t.originator, t.originator_address, t.beneficiary, t.beneficiary_address, t.virtual_asset, t.amount, t.archived, t.archived_on, t.last_update, t.modified, t.created, count(e.id) AS numEnvelopes
+	WITH wallet AS (SELECT crypto_address_idx FROM crypto_addresses WHERE account_id=:accountID)
+	SELECT t.id, t.source, t.status, t.counterparty, t.counterparty_id, t.originator, t.originator_address, t.beneficiary, t.beneficiary_address, t.virtual_asset, t.amount, t.archived, t.archived_on, t.last_update, t.modified, t.created, t.reply_not_after, t.reply_reminded_on, t.reply_expired_on, t.travel_rule_required, t.fiat_amount, t.fiat_currency, t.threshold_rule_id, t.assignee_id, u.name AS assignee, ` + transactionTagsColumn + `, count(e.id) AS numEnvelopes
 		FROM transactions t
 		LEFT JOIN secure_envelopes e ON t.id=e.envelope_id
+		LEFT JOIN users u ON t.assignee_id=u.id
 		WHERE t.archived=:archives AND (
-			t.originator_address IN (SELECT * FROM wallet) OR
-			t.beneficiary_address IN (SELECT * FROM wallet)
+			t.originator_address_idx IN (SELECT * FROM wallet) OR
+			t.beneficiary_address_idx IN (SELECT * FROM wallet)
 		)
 		GROUP BY t.id
 		ORDER BY t.created DESC`
@@ -390,7 +410,7 @@ func (t *Tx) ListAccountTransactions(accountID ulid.ULID, page *models.Transacti
 
 	for rows.Next() {
 		transaction := &models.Transaction{}
-		if err = transaction.ScanWithCount(rows); err != nil {
+		if err = transaction.ScanWithCount(t.decrypt(rows, encryptedTransactionFields(transaction)...)); err != nil {
 			return nil, err
 		}
 		out.Transactions = append(out.Transactions, transaction)
@@ -399,7 +419,11 @@ func (t *Tx) ListAccountTransactions(accountID ulid.ULID, page *models.Transacti
 	return out, nil
 }
 
-const listCryptoAddressesSQL = "SELECT * FROM crypto_addresses WHERE account_id=:accountID"
+// Selects the crypto address columns in the order of the model's Scan method, omitting
+// the blind index of the crypto address.
+const cryptoAddressColumns = "id, account_id, crypto_address, network, asset_type, tag, travel_address, created, modified"
+
+const listCryptoAddressesSQL = "SELECT " + cryptoAddressColumns + " FROM crypto_addresses WHERE account_id=:accountID"
 
 // List crypto addresses associated with the specified accountID.
 func (s *Store) ListCryptoAddresses(ctx context.Context, accountID ulid.ULID, page *models.PageInfo) (out *models.CryptoAddressPage, err error) {
@@ -441,7 +465,7 @@ func (t *Tx) ListCryptoAddresses(accountID ulid.ULID, page *models.PageInfo) (ou
 
 	for rows.Next() {
 		addr := &models.CryptoAddress{}
-		if err = addr.Scan(rows); err != nil {
+		if err = addr.Scan(t.decrypt(rows, encryptedCryptoAddressFields(addr)...)); err != nil {
 			return nil, err
 		}
 
@@ -469,7 +493,7 @@ func (t *Tx) listCryptoAddressesForAccount(account *models.Account) (err error)
 	addresses := make([]*models.CryptoAddress, 0)
 	for rows.Next() {
 		addr := &models.CryptoAddress{}
-		if err = addr.Scan(rows); err != nil {
+		if err = addr.Scan(t.decrypt(rows, encryptedCryptoAddressFields(addr)...)); err != nil {
 			return err
 		}
 		addr.SetAccount(account)
@@ -480,7 +504,7 @@ func (t *Tx) listCryptoAddressesForAccount(account *models.Account) (err error)
 	return nil
 }
 
-const createCryptoAddressSQL = "INSERT INTO crypto_addresses (id, account_id, crypto_address, network, asset_type, tag, travel_address, created, modified) VALUES (:id, :accountID, :cryptoAddress, :network, :assetType, :tag, :travelAddress, :created, :modified)"
+const createCryptoAddressSQL = "INSERT INTO crypto_addresses (id, account_id, crypto_address, network, asset_type, tag, travel_address, created, modified, crypto_address_idx) VALUES (:id, :accountID, :cryptoAddress, :network, :assetType, :tag, :travelAddress, :created, :modified, :cryptoAddressIdx)"
 
 func (s *Store) CreateCryptoAddress(ctx context.Context, addr *models.CryptoAddress, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
@@ -519,7 +543,12 @@ func (t *Tx) CreateCryptoAddress(addr *models.CryptoAddress, auditLog *models.Co
 		addr.TravelAddress = sql.NullString{Valid: travelAddress != "", String: travelAddress}
 	}
 
-	if _, err = t.tx.Exec(createCryptoAddressSQL, addr.Params()...); err != nil {
+	var params []any
+	if params, err = t.cryptoAddressParams(addr); err != nil {
+		return err
+	}
+
+	if _, err = t.tx.Exec(createCryptoAddressSQL, params...); err != nil {
 		return dbe(err)
 	}
 
@@ -540,7 +569,7 @@ func (t *Tx) CreateCryptoAddress(addr *models.CryptoAddress, auditLog *models.Co
 	return nil
 }
 
-const retrieveCryptoAddressSQL = "SELECT * FROM crypto_addresses WHERE id=:cryptoAddressID and account_id=:accountID"
+const retrieveCryptoAddressSQL = "SELECT " + cryptoAddressColumns + " FROM crypto_addresses WHERE id=:cryptoAddressID and account_id=:accountID"
 
 func (s *Store) RetrieveCryptoAddress(ctx context.Context, accountID, cryptoAddressID ulid.ULID) (addr *models.CryptoAddress, err error) {
 	var tx *Tx
@@ -561,7 +590,7 @@ func (s *Store) RetrieveCryptoAddress(ctx context.Context, accountID, cryptoAddr
 
 func (t *Tx) RetrieveCryptoAddress(accountID, cryptoAddressID ulid.ULID) (addr *models.CryptoAddress, err error) {
 	addr = &models.CryptoAddress{}
-	if err = addr.Scan(t.tx.QueryRow(retrieveCryptoAddressSQL, sql.Named("cryptoAddressID", cryptoAddressID), sql.Named("accountID", accountID))); err != nil {
+	if err = addr.Scan(t.decrypt(t.tx.QueryRow(retrieveCryptoAddressSQL, sql.Named("cryptoAddressID", cryptoAddressID), sql.Named("accountID", accountID)), encryptedCryptoAddressFields(addr)...)); err != nil {
 		return nil, dbe(err)
 	}
 
@@ -570,7 +599,7 @@ func (t *Tx) RetrieveCryptoAddress(accountID, cryptoAddressID ulid.ULID) (addr *
 }
 
 // TODO: this must be an upsert/delete since the data is being modified on the relation
-const updateCryptoAddressSQL = "UPDATE crypto_addresses SET crypto_address=:cryptoAddress, network=:network, asset_type=:assetType, tag=:tag, travel_address=:travelAddress, modified=:modified WHERE id=:id and account_id=:accountID"
+const updateCryptoAddressSQL = "UPDATE crypto_addresses SET crypto_address=:cryptoAddress, crypto_address_idx=:cryptoAddressIdx, network=:network, asset_type=:assetType, tag=:tag, travel_address=:travelAddress, modified=:modified WHERE id=:id and account_id=:accountID"
 
 func (s *Store) UpdateCryptoAddress(ctx context.Context, addr *models.CryptoAddress, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
@@ -608,9 +637,14 @@ func (t *Tx) UpdateCryptoAddress(addr *models.CryptoAddress, auditLog *models.Co
 	// Update modified timestamp (in place).
 	addr.Modified = time.Now()
 
-	// Execute the update into the database
+	// Execute the update into the database with the crypto address encrypted
+	var params []any
+	if params, err = t.cryptoAddressParams(addr); err != nil {
+		return err
+	}
+
 	var result sql.Result
-	if result, err = t.tx.Exec(updateCryptoAddressSQL, addr.Params()...); err != nil {
+	if result, err = t.tx.Exec(updateCryptoAddressSQL, params...); err != nil {
 		return dbe(err)
 	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
 		return dberr.ErrNotFound
@@ -650,6 +684,14 @@ func (s *Store) DeleteCryptoAddress(ctx context.Context, accountID, cryptoAddres
 }
 
 func (t *Tx) DeleteCryptoAddress(accountID, cryptoAddressID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	// Crypto addresses of accounts under legal hold cannot be deleted
+	var held bool
+	if held, err = t.resourceOnHold(enum.ResourceAccount, accountID); err != nil {
+		return err
+	} else if held {
+		return dberr.ErrLegalHold
+	}
+
 	var result sql.Result
 	if result, err = t.tx.Exec(deleteCryptoAddressSQL, sql.Named("cryptoAddressID", cryptoAddressID), sql.Named("accountID", accountID)); err != nil {
 		return dbe(err)
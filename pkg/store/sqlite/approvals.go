@@ -0,0 +1,633 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Approval Rules CRUD interface
+//===========================================================================
+
+const listApprovalRulesSQL = "SELECT * FROM approval_rules ORDER BY created ASC"
+
+func (s *Store) ListApprovalRules(ctx context.Context) (out []*models.ApprovalRule, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListApprovalRules(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists all of the approval rules in the order they were created; rules are not
+// paginated since they are evaluated together whenever a transfer action is requested.
+func (t *Tx) ListApprovalRules() (out []*models.ApprovalRule, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listApprovalRulesSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.ApprovalRule, 0)
+	for rows.Next() {
+		rule := &models.ApprovalRule{}
+		if err = rule.Scan(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const createApprovalRuleSQL = "INSERT INTO approval_rules (id, description, action, virtual_asset, min_amount, counterparty_id, created, modified) VALUES (:id, :description, :action, :virtualAsset, :minAmount, :counterpartyID, :created, :modified)"
+
+func (s *Store) CreateApprovalRule(ctx context.Context, rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateApprovalRule(rule, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) CreateApprovalRule(rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) (err error) {
+	if !rule.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	rule.ID = ulid.MakeSecure()
+	rule.Created = time.Now()
+	rule.Modified = rule.Created
+
+	if _, err = t.tx.Exec(createApprovalRuleSQL, rule.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	return t.approvalAuditLog(rule.ID, enum.ResourceApprovalRule, rule.Modified, enum.ActionCreate, auditLog.ChangeNotes)
+}
+
+const retrieveApprovalRuleSQL = "SELECT * FROM approval_rules WHERE id=:id"
+
+func (s *Store) RetrieveApprovalRule(ctx context.Context, ruleID ulid.ULID) (rule *models.ApprovalRule, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if rule, err = tx.RetrieveApprovalRule(ruleID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (t *Tx) RetrieveApprovalRule(ruleID ulid.ULID) (rule *models.ApprovalRule, err error) {
+	rule = &models.ApprovalRule{}
+	if err = rule.Scan(t.tx.QueryRow(retrieveApprovalRuleSQL, sql.Named("id", ruleID))); err != nil {
+		return nil, dbe(err)
+	}
+	return rule, nil
+}
+
+const updateApprovalRuleSQL = "UPDATE approval_rules SET description=:description, action=:action, virtual_asset=:virtualAsset, min_amount=:minAmount, counterparty_id=:counterpartyID, modified=:modified WHERE id=:id"
+
+// Update the criteria of an approval rule. Changing a rule does not affect pending
+// approvals that were created by the rule before it was changed.
+func (s *Store) UpdateApprovalRule(ctx context.Context, rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UpdateApprovalRule(rule, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) UpdateApprovalRule(rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) (err error) {
+	if rule.ID.IsZero() {
+		return dberr.ErrMissingID
+	}
+
+	rule.Modified = time.Now()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(updateApprovalRuleSQL, rule.Params()...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	// Refresh the created timestamp of the rule from the database
+	var orig *models.ApprovalRule
+	if orig, err = t.RetrieveApprovalRule(rule.ID); err != nil {
+		return err
+	}
+	rule.Created = orig.Created
+
+	return t.approvalAuditLog(rule.ID, enum.ResourceApprovalRule, rule.Modified, enum.ActionUpdate, auditLog.ChangeNotes)
+}
+
+const deleteApprovalRuleSQL = "DELETE FROM approval_rules WHERE id=:id"
+
+// Delete an approval rule; approvals created by the rule are preserved.
+func (s *Store) DeleteApprovalRule(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.DeleteApprovalRule(ruleID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) DeleteApprovalRule(ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var result sql.Result
+	if result, err = t.tx.Exec(deleteApprovalRuleSQL, sql.Named("id", ruleID)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return t.approvalAuditLog(ruleID, enum.ResourceApprovalRule, time.Now(), enum.ActionDelete, auditLog.ChangeNotes)
+}
+
+//===========================================================================
+// Approvals interface
+//===========================================================================
+
+const (
+	listApprovalsSQL       = "SELECT * FROM approvals"
+	listApprovalsCursorSQL = "(created, id) < (SELECT created, id FROM approvals WHERE id = :cursor)"
+	listApprovalsOrderSQL  = " ORDER BY created DESC, id DESC LIMIT :limit"
+)
+
+// List the approvals filtered by status and transaction in the page. Approvals are
+// returned newest first; if there are more approvals than the page size, the next page
+// ID is set to the ID of the last approval in the page and is used as the cursor to
+// fetch the next page.
+func (s *Store) ListApprovals(ctx context.Context, page *models.ApprovalPageInfo) (out *models.ApprovalPage, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListApprovals(page); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *Tx) ListApprovals(page *models.ApprovalPageInfo) (out *models.ApprovalPage, err error) {
+	out = &models.ApprovalPage{
+		Approvals: make([]*models.Approval, 0),
+		Page: &models.ApprovalPageInfo{
+			PageInfo: *models.PageInfoFrom(nil),
+		},
+	}
+
+	if page != nil {
+		out.Page.PageInfo = *models.PageInfoFrom(&page.PageInfo)
+		out.Page.NextPageID = page.NextPageID
+		out.Page.Status = page.Status
+		out.Page.TransactionID = page.TransactionID
+	}
+
+	query := listApprovalsSQL
+	params := make([]any, 0, 4)
+	filters := make([]string, 0, 3)
+
+	if out.Page.Status != "" {
+		filters = append(filters, "status=:status")
+		params = append(params, sql.Named("status", out.Page.Status))
+	}
+
+	if out.Page.TransactionID != "" {
+		var txID uuid.UUID
+		if txID, err = uuid.Parse(out.Page.TransactionID); err != nil {
+			return nil, dberr.ErrNotFound
+		}
+
+		filters = append(filters, "transaction_id=:transactionID")
+		params = append(params, sql.Named("transactionID", txID))
+	}
+
+	// Start the page after the cursor approval if one was specified
+	if !out.Page.NextPageID.IsZero() {
+		filters = append(filters, listApprovalsCursorSQL)
+		params = append(params, sql.Named("cursor", out.Page.NextPageID))
+	}
+
+	if len(filters) != 0 {
+		query += " WHERE " + strings.Join(filters, " AND ")
+	}
+
+	// Fetch one more record than the page size to determine if there is a next page
+	query += listApprovalsOrderSQL
+	params = append(params, sql.Named("limit", out.Page.PageSize+1))
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(query, params...); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out.Page.NextPageID = ulid.Zero
+	for rows.Next() {
+		// If we've filled the page then there is at least one more approval to fetch
+		if uint32(len(out.Approvals)) == out.Page.PageSize {
+			out.Page.NextPageID = out.Approvals[len(out.Approvals)-1].ID
+			break
+		}
+
+		approval := &models.Approval{}
+		if err = approval.Scan(rows); err != nil {
+			return nil, err
+		}
+		out.Approvals = append(out.Approvals, approval)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const createApprovalSQL = "INSERT INTO approvals (id, action, status, rule_id, transaction_id, counterparty, counterparty_id, virtual_asset, amount, request, requested_by, requested_by_id, requested_by_type, reviewed_by, reviewed_by_id, review_notes, reviewed_on, created, modified) VALUES (:id, :action, :status, :ruleID, :transactionID, :counterparty, :counterpartyID, :virtualAsset, :amount, :request, :requestedBy, :requestedByID, :requestedByType, :reviewedBy, :reviewedByID, :reviewNotes, :reviewedOn, :created, :modified)"
+
+// Create a pending approval for a transfer workflow action. The actor that requested
+// the action is taken from the context of the transaction.
+func (s *Store) CreateApproval(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateApproval(approval, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) CreateApproval(approval *models.Approval, auditLog *models.ComplianceAuditLog) (err error) {
+	if !approval.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	if len(approval.Request) == 0 {
+		return dberr.ErrMissingValue
+	}
+
+	approval.ID = ulid.MakeSecure()
+	approval.Status = enum.ApprovalPending
+	approval.RequestedByID, approval.RequestedByType = t.GetActor()
+	approval.ReviewedBy = sql.NullString{}
+	approval.ReviewedByID = nil
+	approval.ReviewNotes = sql.NullString{}
+	approval.ReviewedOn = sql.NullTime{}
+	approval.Created = time.Now()
+	approval.Modified = approval.Created
+
+	if approval.RequestedBy == "" {
+		approval.RequestedBy = approval.RequestedByType.String()
+	}
+
+	if _, err = t.tx.Exec(createApprovalSQL, approval.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	return t.approvalAuditLog(approval.ID, enum.ResourceApproval, approval.Modified, enum.ActionCreate, auditLog.ChangeNotes)
+}
+
+const retrieveApprovalSQL = "SELECT * FROM approvals WHERE id=:id"
+
+func (s *Store) RetrieveApproval(ctx context.Context, approvalID ulid.ULID) (approval *models.Approval, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if approval, err = tx.RetrieveApproval(approvalID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+func (t *Tx) RetrieveApproval(approvalID ulid.ULID) (approval *models.Approval, err error) {
+	approval = &models.Approval{}
+	if err = approval.Scan(t.tx.QueryRow(retrieveApprovalSQL, sql.Named("id", approvalID))); err != nil {
+		return nil, dbe(err)
+	}
+	return approval, nil
+}
+
+const (
+	claimApprovalSQL   = "UPDATE approvals SET status=:executing, modified=:modified WHERE id=:id AND status=:pending"
+	releaseApprovalSQL = "UPDATE approvals SET status=:pending, modified=:modified WHERE id=:id AND status=:executing"
+)
+
+// Claim a pending approval so that the approved action can be executed. The status of
+// the approval is changed to executing only if it is still pending so that the action
+// is executed at most once even if reviewers approve it concurrently from different
+// processes. The claim must be resolved by ReviewApproval once the action has been
+// executed or by ReleaseApproval if the action could not be executed.
+func (s *Store) ClaimApproval(ctx context.Context, approvalID ulid.ULID) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.ClaimApproval(approvalID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) ClaimApproval(approvalID ulid.ULID) (err error) {
+	//NOTE: claims do not require an audit log entry since the review is audited
+	var orig *models.Approval
+	if orig, err = t.RetrieveApproval(approvalID); err != nil {
+		return err
+	}
+
+	actorID, actorType := t.GetActor()
+	if bytes.Equal(actorID, orig.RequestedByID) && actorType == orig.RequestedByType {
+		return dberr.ErrSelfApproval
+	}
+
+	params := []any{
+		sql.Named("id", approvalID),
+		sql.Named("pending", enum.ApprovalPending),
+		sql.Named("executing", enum.ApprovalExecuting),
+		sql.Named("modified", time.Now()),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(claimApprovalSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrApprovalNotPending
+	}
+	return nil
+}
+
+// Release the claim on an approval whose action could not be executed so that it is
+// pending again and can be retried or denied.
+func (s *Store) ReleaseApproval(ctx context.Context, approvalID ulid.ULID) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.ReleaseApproval(approvalID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) ReleaseApproval(approvalID ulid.ULID) (err error) {
+	//NOTE: claims do not require an audit log entry since the review is audited
+	params := []any{
+		sql.Named("id", approvalID),
+		sql.Named("pending", enum.ApprovalPending),
+		sql.Named("executing", enum.ApprovalExecuting),
+		sql.Named("modified", time.Now()),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(releaseApprovalSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrApprovalNotClaimed
+	}
+	return nil
+}
+
+const reviewApprovalSQL = "UPDATE approvals SET status=:status, transaction_id=:transactionID, reviewed_by=:reviewedBy, reviewed_by_id=:reviewedByID, review_notes=:reviewNotes, reviewed_on=:reviewedOn, modified=:modified WHERE id=:id AND status=:current"
+
+// Record the decision on a pending approval: the status of the approval must be one of
+// approved, denied, or canceled. Approvals can only be approved or denied by an actor
+// other than the one that requested the action and can only be canceled by the actor
+// that requested the action. An approval must be claimed with ClaimApproval before it
+// is approved, and claimed approvals cannot be denied or canceled. The reviewed by name, the review notes, and the
+// transaction ID (if the approved action created a transaction) are set from the
+// approval; all other fields are populated on the approval from the database.
+func (s *Store) ReviewApproval(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.ReviewApproval(approval, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) ReviewApproval(approval *models.Approval, auditLog *models.ComplianceAuditLog) (err error) {
+	var orig *models.Approval
+	if orig, err = t.RetrieveApproval(approval.ID); err != nil {
+		return err
+	}
+
+	actorID, actorType := t.GetActor()
+	isRequester := bytes.Equal(actorID, orig.RequestedByID) && actorType == orig.RequestedByType
+
+	switch approval.Status {
+	case enum.ApprovalApproved:
+		if isRequester {
+			return dberr.ErrSelfApproval
+		}
+
+		if orig.IsPending() {
+			return dberr.ErrApprovalNotClaimed
+		}
+
+		if !orig.IsExecuting() {
+			return dberr.ErrApprovalNotPending
+		}
+	case enum.ApprovalDenied:
+		if !orig.IsPending() {
+			return dberr.ErrApprovalNotPending
+		}
+
+		if isRequester {
+			return dberr.ErrSelfApproval
+		}
+	case enum.ApprovalCanceled:
+		if !orig.IsPending() {
+			return dberr.ErrApprovalNotPending
+		}
+
+		if !isRequester {
+			return dberr.ErrNotRequester
+		}
+	default:
+		return dberr.ErrMissingValue
+	}
+
+	// The update is conditional on the current status so that concurrent reviews of
+	// the same approval cannot both succeed.
+	current := orig.Status
+	orig.Status = approval.Status
+	orig.ReviewedBy = approval.ReviewedBy
+	orig.ReviewedByID = actorID
+	orig.ReviewNotes = approval.ReviewNotes
+	orig.ReviewedOn = sql.NullTime{Valid: true, Time: time.Now()}
+	orig.Modified = orig.ReviewedOn.Time
+
+	if approval.TransactionID.Valid {
+		orig.TransactionID = approval.TransactionID
+	}
+
+	if !orig.ReviewedBy.Valid || orig.ReviewedBy.String == "" {
+		orig.ReviewedBy = sql.NullString{Valid: true, String: actorType.String()}
+	}
+
+	params := []any{
+		sql.Named("id", orig.ID),
+		sql.Named("status", orig.Status),
+		sql.Named("transactionID", orig.TransactionID),
+		sql.Named("reviewedBy", orig.ReviewedBy),
+		sql.Named("reviewedByID", orig.ReviewedByID),
+		sql.Named("reviewNotes", orig.ReviewNotes),
+		sql.Named("reviewedOn", orig.ReviewedOn),
+		sql.Named("modified", orig.Modified),
+		sql.Named("current", current),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(reviewApprovalSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrApprovalNotPending
+	}
+
+	// Make an audit log note with the decision so we know what kind of update it was
+	notes := sql.NullString{
+		Valid:  true,
+		String: "ReviewApproval:" + orig.Status.String(),
+	}
+	if auditLog.ChangeNotes.Valid {
+		notes.String = auditLog.ChangeNotes.String + "-" + notes.String
+	}
+
+	if err = t.approvalAuditLog(orig.ID, enum.ResourceApproval, orig.Modified, enum.ActionUpdate, notes); err != nil {
+		return err
+	}
+
+	*approval = *orig
+	return nil
+}
+
+const listApprovalReviewersSQL = "SELECT id, name, email, role_id, last_login, created, modified, mfa_enrolled, login_locked_until FROM users WHERE id IN (SELECT user_id FROM user_permissions WHERE permission='approvals:review') ORDER BY created ASC"
+
+func (s *Store) ListApprovalReviewers(ctx context.Context) (out []*models.User, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListApprovalReviewers(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists the users whose role permits them to review approvals so that they can be
+// notified when an action requires approval.
+func (t *Tx) ListApprovalReviewers() (out []*models.User, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listApprovalReviewersSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.User, 0)
+	for rows.Next() {
+		user := &models.User{}
+		if err = user.ScanSummary(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+func (t *Tx) approvalAuditLog(resourceID ulid.ULID, resourceType enum.Resource, modified time.Time, action enum.Action, notes sql.NullString) error {
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       resourceID.Bytes(),
+		ResourceType:     resourceType,
+		ResourceModified: modified,
+		Action:           action,
+		ChangeNotes:      notes,
+	})
+}
@@ -0,0 +1,351 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestApprovalRules() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	rules, err := s.store.ListApprovalRules(ctx)
+	require.NoError(err, "could not list approval rules")
+	require.Len(rules, 0, "expected no approval rules in the fixtures")
+
+	rule := &models.ApprovalRule{
+		Description:    "Large bitcoin transfers to AliceVASP",
+		Action:         enum.ApprovalActionSend,
+		VirtualAsset:   sql.NullString{Valid: true, String: "BTC"},
+		MinAmount:      sql.NullFloat64{Valid: true, Float64: 1.0},
+		CounterpartyID: ulid.NullULID{Valid: true, ULID: ulid.MustParse("01HWR5VWW8V7ZFFVJVBEC7AV8A")},
+	}
+
+	err = s.store.CreateApprovalRule(ctx, rule, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not create approval rule")
+	require.False(rule.ID.IsZero(), "expected an id to be assigned")
+
+	// Create a second rule that applies to any action
+	err = s.store.CreateApprovalRule(ctx, &models.ApprovalRule{Description: "Everything"}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not create approval rule")
+
+	err = s.store.CreateApprovalRule(ctx, &models.ApprovalRule{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNoIDOnCreate)
+
+	rules, err = s.store.ListApprovalRules(ctx)
+	require.NoError(err, "could not list approval rules")
+	require.Len(rules, 2)
+	require.Equal(rule.ID, rules[0].ID, "expected rules to be ordered by creation")
+	require.Equal(enum.ApprovalActionUnknown, rules[1].Action, "expected a null action to be unknown")
+
+	cmp, err := s.store.RetrieveApprovalRule(ctx, rule.ID)
+	require.NoError(err, "could not retrieve approval rule")
+	require.Equal(rule.Description, cmp.Description)
+	require.Equal(enum.ApprovalActionSend, cmp.Action)
+	require.Equal("BTC", cmp.VirtualAsset.String)
+	require.Equal(1.0, cmp.MinAmount.Float64)
+	require.Equal(rule.CounterpartyID, cmp.CounterpartyID)
+
+	cmp.Action = enum.ApprovalActionUnknown
+	cmp.MinAmount = sql.NullFloat64{Valid: true, Float64: 5.0}
+	err = s.store.UpdateApprovalRule(ctx, cmp, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update approval rule")
+	require.Equal(rule.Created.Unix(), cmp.Created.Unix(), "expected created timestamp to be refreshed")
+
+	cmp, err = s.store.RetrieveApprovalRule(ctx, rule.ID)
+	require.NoError(err, "could not retrieve approval rule")
+	require.Equal(enum.ApprovalActionUnknown, cmp.Action)
+	require.Equal(5.0, cmp.MinAmount.Float64)
+
+	err = s.store.UpdateApprovalRule(ctx, &models.ApprovalRule{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.DeleteApprovalRule(ctx, rule.ID, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not delete approval rule")
+
+	_, err = s.store.RetrieveApprovalRule(ctx, rule.ID)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.DeleteApprovalRule(ctx, rule.ID, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionCreate, enum.ResourceApprovalRule): 2,
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceApprovalRule): 1,
+		ActionResourceKey(enum.ActionDelete, enum.ResourceApprovalRule): 1,
+	})
+}
+
+func (s *storeTestSuite) TestApprovals() {
+	txID := uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+	requester := s.ActorContext()
+	reviewer := s.ActorContext()
+
+	createApproval := func(ctx context.Context) *models.Approval {
+		approval := &models.Approval{
+			Action:        enum.ApprovalActionAccept,
+			TransactionID: uuid.NullUUID{Valid: true, UUID: txID},
+			Counterparty:  sql.NullString{Valid: true, String: "AliceVASP"},
+			VirtualAsset:  sql.NullString{Valid: true, String: "BTC"},
+			Amount:        sql.NullFloat64{Valid: true, Float64: 0.0003842},
+			Request:       []byte(`{"identity":{}}`),
+			RequestedBy:   "Compliance User",
+		}
+
+		err := s.store.CreateApproval(ctx, approval, &models.ComplianceAuditLog{})
+		s.Require().NoError(err, "could not create approval")
+		return approval
+	}
+
+	s.Run("Create", func() {
+		s.ResetDB()
+		require := s.Require()
+
+		approval := createApproval(requester)
+		require.False(approval.ID.IsZero(), "expected an id to be assigned")
+		require.Equal(enum.ApprovalPending, approval.Status)
+		require.NotEmpty(approval.RequestedByID, "expected the actor to be assigned")
+		require.Equal(enum.ActorAPIKey, approval.RequestedByType)
+
+		cmp, err := s.store.RetrieveApproval(requester, approval.ID)
+		require.NoError(err, "could not retrieve approval")
+		require.Equal(approval.Action, cmp.Action)
+		require.Equal(txID, cmp.TransactionID.UUID)
+		require.Equal("AliceVASP", cmp.Counterparty.String)
+		require.Equal(approval.Request, cmp.Request)
+		require.Equal("Compliance User", cmp.RequestedBy)
+		require.True(cmp.IsPending())
+
+		err = s.store.CreateApproval(requester, &models.Approval{Action: enum.ApprovalActionSend}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue, "expected the request to be required")
+
+		_, err = s.store.RetrieveApproval(requester, ulid.MakeSecure())
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceApproval): 1,
+		})
+	})
+
+	s.Run("List", func() {
+		s.ResetDB()
+		require := s.Require()
+
+		for i := 0; i < 3; i++ {
+			createApproval(requester)
+		}
+
+		send := &models.Approval{Action: enum.ApprovalActionSend, Request: []byte(`{}`)}
+		require.NoError(s.store.CreateApproval(requester, send, &models.ComplianceAuditLog{}))
+
+		page, err := s.store.ListApprovals(requester, nil)
+		require.NoError(err, "could not list approvals")
+		require.Len(page.Approvals, 4)
+		require.Equal(send.ID, page.Approvals[0].ID, "expected newest approvals first")
+
+		page, err = s.store.ListApprovals(requester, &models.ApprovalPageInfo{TransactionID: txID.String()})
+		require.NoError(err, "could not list approvals")
+		require.Len(page.Approvals, 3)
+
+		page, err = s.store.ListApprovals(requester, &models.ApprovalPageInfo{PageInfo: models.PageInfo{PageSize: 3}, Status: enum.ApprovalPending.String()})
+		require.NoError(err, "could not list approvals")
+		require.Len(page.Approvals, 3)
+		require.False(page.Page.NextPageID.IsZero(), "expected a next page")
+
+		page, err = s.store.ListApprovals(requester, &models.ApprovalPageInfo{PageInfo: models.PageInfo{PageSize: 3, NextPageID: page.Page.NextPageID}})
+		require.NoError(err, "could not list approvals")
+		require.Len(page.Approvals, 1)
+		require.True(page.Page.NextPageID.IsZero(), "expected no next page")
+
+		page, err = s.store.ListApprovals(requester, &models.ApprovalPageInfo{Status: enum.ApprovalApproved.String()})
+		require.NoError(err, "could not list approvals")
+		require.Len(page.Approvals, 0)
+	})
+
+	s.Run("Approve", func() {
+		s.ResetDB()
+		require := s.Require()
+		approval := createApproval(requester)
+
+		review := &models.Approval{
+			Model:       models.Model{ID: approval.ID},
+			Status:      enum.ApprovalApproved,
+			ReviewedBy:  sql.NullString{Valid: true, String: "Admin User"},
+			ReviewNotes: sql.NullString{Valid: true, String: "looks good"},
+		}
+
+		err := s.store.ReviewApproval(requester, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrSelfApproval, "the requester should not be able to approve")
+
+		err = s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrApprovalNotClaimed, "the approval must be claimed before it is approved")
+
+		require.NoError(s.store.ClaimApproval(reviewer, approval.ID), "could not claim approval")
+		err = s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not approve approval")
+		require.Equal(enum.ApprovalApproved, review.Status)
+		require.Equal(approval.Request, review.Request, "expected the approval to be populated from the database")
+		require.NotEmpty(review.ReviewedByID)
+		require.True(review.ReviewedOn.Valid)
+
+		cmp, err := s.store.RetrieveApproval(reviewer, approval.ID)
+		require.NoError(err, "could not retrieve approval")
+		require.Equal(enum.ApprovalApproved, cmp.Status)
+		require.Equal("Admin User", cmp.ReviewedBy.String)
+		require.Equal("looks good", cmp.ReviewNotes.String)
+
+		review.Status = enum.ApprovalDenied
+		err = s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrApprovalNotPending)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceApproval): 1,
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceApproval): 1,
+		})
+	})
+
+	s.Run("ApproveSend", func() {
+		s.ResetDB()
+		require := s.Require()
+
+		approval := &models.Approval{Action: enum.ApprovalActionSend, Request: []byte(`{}`)}
+		require.NoError(s.store.CreateApproval(requester, approval, &models.ComplianceAuditLog{}))
+		require.False(approval.TransactionID.Valid)
+
+		review := &models.Approval{
+			Model:         models.Model{ID: approval.ID},
+			Status:        enum.ApprovalApproved,
+			TransactionID: uuid.NullUUID{Valid: true, UUID: txID},
+		}
+
+		require.NoError(s.store.ClaimApproval(reviewer, approval.ID), "could not claim approval")
+		err := s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not approve approval")
+		require.Equal(txID, review.TransactionID.UUID, "expected the sent transaction to be recorded")
+		require.Equal(enum.ActorAPIKey.String(), review.ReviewedBy.String, "expected reviewed by to default to the actor type")
+	})
+
+	s.Run("Deny", func() {
+		s.ResetDB()
+		require := s.Require()
+		approval := createApproval(requester)
+
+		review := &models.Approval{Model: models.Model{ID: approval.ID}, Status: enum.ApprovalDenied}
+		err := s.store.ReviewApproval(requester, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrSelfApproval, "the requester should not be able to deny")
+
+		err = s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not deny approval")
+		require.Equal(enum.ApprovalDenied, review.Status)
+	})
+
+	s.Run("Cancel", func() {
+		s.ResetDB()
+		require := s.Require()
+		approval := createApproval(requester)
+
+		review := &models.Approval{Model: models.Model{ID: approval.ID}, Status: enum.ApprovalCanceled}
+		err := s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotRequester, "only the requester should be able to cancel")
+
+		err = s.store.ReviewApproval(requester, review, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not cancel approval")
+		require.Equal(enum.ApprovalCanceled, review.Status)
+	})
+
+	s.Run("InvalidStatus", func() {
+		s.ResetDB()
+		require := s.Require()
+		approval := createApproval(requester)
+
+		review := &models.Approval{Model: models.Model{ID: approval.ID}, Status: enum.ApprovalPending}
+		err := s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue)
+
+		review.ID = ulid.MakeSecure()
+		review.Status = enum.ApprovalApproved
+		err = s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotFound)
+	})
+
+	s.Run("SameActorDifferentType", func() {
+		s.ResetDB()
+		require := s.Require()
+		approval := createApproval(requester)
+
+		// The same actor ID with a different actor type is a different actor
+		actorID, _ := audit.ActorID(requester)
+		ctx := audit.WithActor(context.Background(), actorID, enum.ActorUser)
+
+		require.NoError(s.store.ClaimApproval(ctx, approval.ID))
+		review := &models.Approval{Model: models.Model{ID: approval.ID}, Status: enum.ApprovalApproved}
+		require.NoError(s.store.ReviewApproval(ctx, review, &models.ComplianceAuditLog{}))
+	})
+
+	s.Run("Claim", func() {
+		s.ResetDB()
+		require := s.Require()
+		approval := createApproval(requester)
+
+		err := s.store.ClaimApproval(requester, approval.ID)
+		require.ErrorIs(err, errors.ErrSelfApproval, "the requester should not be able to claim")
+
+		require.NoError(s.store.ClaimApproval(reviewer, approval.ID), "could not claim approval")
+		cmp, err := s.store.RetrieveApproval(reviewer, approval.ID)
+		require.NoError(err, "could not retrieve approval")
+		require.True(cmp.IsExecuting())
+
+		// A claimed approval cannot be claimed again or denied or canceled
+		err = s.store.ClaimApproval(reviewer, approval.ID)
+		require.ErrorIs(err, errors.ErrApprovalNotPending, "the approval should only be claimed once")
+
+		review := &models.Approval{Model: models.Model{ID: approval.ID}, Status: enum.ApprovalDenied}
+		err = s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrApprovalNotPending)
+
+		review.Status = enum.ApprovalCanceled
+		err = s.store.ReviewApproval(requester, review, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrApprovalNotPending)
+
+		// Releasing the claim allows the approval to be reviewed again
+		require.NoError(s.store.ReleaseApproval(reviewer, approval.ID), "could not release approval")
+		err = s.store.ReleaseApproval(reviewer, approval.ID)
+		require.ErrorIs(err, errors.ErrApprovalNotClaimed)
+
+		cmp, err = s.store.RetrieveApproval(reviewer, approval.ID)
+		require.NoError(err, "could not retrieve approval")
+		require.True(cmp.IsPending())
+
+		review.Status = enum.ApprovalDenied
+		require.NoError(s.store.ReviewApproval(reviewer, review, &models.ComplianceAuditLog{}))
+
+		err = s.store.ClaimApproval(reviewer, approval.ID)
+		require.ErrorIs(err, errors.ErrApprovalNotPending, "reviewed approvals cannot be claimed")
+
+		err = s.store.ClaimApproval(reviewer, ulid.MakeSecure())
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceApproval): 1,
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceApproval): 1,
+		})
+	})
+}
+
+func (s *storeTestSuite) TestListApprovalReviewers() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	users, err := s.store.ListApprovalReviewers(ctx)
+	require.NoError(err, "could not list approval reviewers")
+	require.Len(users, 2, "expected the admin and compliance users to be reviewers")
+	require.Equal("admin@example.com", users[0].Email)
+	require.Equal("compliance@example.com", users[1].Email)
+}
@@ -76,14 +76,17 @@ func (s *Store) RetrieveComplianceAuditLog(ctx context.Context, id ulid.ULID) (l
 // # ComplianceAuditLogTxn implementation for SQLite #
 // ###################################################
 
-const listComplianceAuditLogsSummarySQL = "SELECT id, actor_id, actor_type, resource_id, resource_type, resource_modified, action FROM compliance_audit_log ORDER BY resource_modified DESC"
-const listComplianceAuditLogsDetailedSQL = "SELECT id, actor_id, actor_type, resource_id, resource_type, resource_modified, action, change_notes, signature, key_id, algorithm FROM compliance_audit_log ORDER BY resource_modified DESC"
+const (
+	listComplianceAuditLogsSummarySQL  = "SELECT id, actor_id, actor_type, resource_id, resource_type, resource_modified, action FROM compliance_audit_log"
+	listComplianceAuditLogsDetailedSQL = "SELECT id, actor_id, actor_type, resource_id, resource_type, resource_modified, action, change_notes, signature, key_id, algorithm FROM compliance_audit_log"
+	listComplianceAuditLogsCursorSQL   = "(resource_modified, id) < (SELECT resource_modified, id FROM compliance_audit_log WHERE id = :cursor)"
+	listComplianceAuditLogsOrderSQL    = " ORDER BY resource_modified DESC, id DESC LIMIT :limit"
+)
 
 func (t *Tx) ListComplianceAuditLogs(page *models.ComplianceAuditLogPageInfo) (out *models.ComplianceAuditLogPage, err error) {
 	// Setup out variable with page info
 	out = &models.ComplianceAuditLogPage{
 		Logs: make([]*models.ComplianceAuditLog, 0),
-		// TODO: implement pagination
 		Page: models.ComplianceAuditLogPageInfoFrom(page),
 	}
 
@@ -94,8 +97,8 @@ func (t *Tx) ListComplianceAuditLogs(page *models.ComplianceAuditLogPageInfo) (o
 	}
 
 	// Create lists for filters/params then process each filter option
-	params := make([]any, 0, 4)
-	filters := make([]string, 0, 4)
+	params := make([]any, 0, 8)
+	filters := make([]string, 0, 8)
 
 	// After resource_modified (inclusive)
 	if !out.Page.After.IsZero() {
@@ -112,13 +115,7 @@ func (t *Tx) ListComplianceAuditLogs(page *models.ComplianceAuditLogPageInfo) (o
 	// Resource filtering (if ResourceID is set, prefer it over ResourceTypes)
 	if out.Page.ResourceID != "" {
 		filters = append(filters, "resource_id = :resourceId")
-		if uid, err := ulid.Parse(out.Page.ResourceID); err == nil {
-			params = append(params, sql.Named("resourceId", uid.Bytes()))
-		} else if uid, err := uuid.Parse(out.Page.ResourceID); err == nil {
-			params = append(params, sql.Named("resourceId", uid[:]))
-		} else {
-			params = append(params, sql.Named("resourceId", out.Page.ResourceID))
-		}
+		params = append(params, sql.Named("resourceId", auditLogID(out.Page.ResourceID)))
 	} else if 0 < len(out.Page.ResourceTypes) {
 		inquery, inparams := listParametrize(out.Page.ResourceTypes, "r")
 		filters = append(filters, "resource_type IN "+inquery)
@@ -128,28 +125,34 @@ func (t *Tx) ListComplianceAuditLogs(page *models.ComplianceAuditLogPageInfo) (o
 	// Actor filtering (if ActorID is set, prefer it over ActorTypes)
 	if out.Page.ActorID != "" {
 		filters = append(filters, "actor_id = :actorId")
-		if uid, err := ulid.Parse(out.Page.ActorID); err == nil {
-			params = append(params, sql.Named("actorId", uid.Bytes()))
-		} else {
-			params = append(params, sql.Named("actorId", out.Page.ActorID))
-		}
+		params = append(params, sql.Named("actorId", auditLogID(out.Page.ActorID)))
 	} else if 0 < len(out.Page.ActorTypes) {
 		inquery, inparams := listParametrize(out.Page.ActorTypes, "a")
 		filters = append(filters, "actor_type IN "+inquery)
 		params = append(params, inparams...)
 	}
 
+	// Action filtering
+	if 0 < len(out.Page.Actions) {
+		inquery, inparams := listParametrize(out.Page.Actions, "x")
+		filters = append(filters, "action IN "+inquery)
+		params = append(params, inparams...)
+	}
+
+	// Start the page after the cursor log if one was specified
+	if !out.Page.NextPageID.IsZero() {
+		filters = append(filters, listComplianceAuditLogsCursorSQL)
+		params = append(params, sql.Named("cursor", out.Page.NextPageID))
+	}
+
 	// Concatenate filters with AND if there are any
 	if len(filters) != 0 {
-		query = "WITH logs AS (" + query + ") SELECT * FROM logs WHERE "
-		query += strings.Join(filters, " AND ")
+		query += " WHERE " + strings.Join(filters, " AND ")
 	}
 
-	// If the query has a Limit, apply it
-	if out.Page.Limit != 0 {
-		query += " LIMIT :limit"
-		params = append(params, sql.Named("limit", page.Limit))
-	}
+	// Fetch one more record than the page size to determine if there is a next page
+	query += listComplianceAuditLogsOrderSQL
+	params = append(params, sql.Named("limit", out.Page.PageSize+1))
 
 	var rows *sql.Rows
 	if rows, err = t.tx.Query(query, params...); err != nil {
@@ -157,7 +160,14 @@ func (t *Tx) ListComplianceAuditLogs(page *models.ComplianceAuditLogPageInfo) (o
 	}
 	defer rows.Close()
 
+	out.Page.NextPageID = ulid.Zero
 	for rows.Next() {
+		// If we've filled the page then there is at least one more log to fetch
+		if uint32(len(out.Logs)) == out.Page.PageSize {
+			out.Page.NextPageID = out.Logs[len(out.Logs)-1].ID
+			break
+		}
+
 		log := &models.ComplianceAuditLog{}
 
 		// Scan the summary info only unless the user has requested detailed logs
@@ -174,9 +184,27 @@ func (t *Tx) ListComplianceAuditLogs(page *models.ComplianceAuditLogPageInfo) (o
 		out.Logs = append(out.Logs, log)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+
 	return out, nil
 }
 
+// Actor and resource IDs are stored as the bytes of a ULID or UUID where possible,
+// otherwise the raw string is stored (e.g. for the system and CLI actors).
+func auditLogID(id string) any {
+	if uid, err := ulid.Parse(id); err == nil {
+		return uid.Bytes()
+	}
+
+	if uid, err := uuid.Parse(id); err == nil {
+		return uid[:]
+	}
+
+	return []byte(id)
+}
+
 const createComplianceAuditLogsSQL = "INSERT INTO compliance_audit_log (id, actor_id, actor_type, resource_id, resource_type, resource_modified, action, change_notes, signature, key_id, algorithm) VALUES (:id, :actorId, :actorType, :resourceId, :resourceType, :resourceModified, :action, :changeNotes, :signature, :keyId, :algorithm)"
 
 func (t *Tx) CreateComplianceAuditLog(log *models.ComplianceAuditLog) (err error) {
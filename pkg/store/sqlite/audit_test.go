@@ -156,6 +156,40 @@ func (s *storeTestSuite) TestListComplianceAuditLogsFilters() {
 		require.Len(logs.Logs, 2, fmt.Sprintf("there should be 2 logs, but there were %d", len(logs.Logs)))
 	})
 
+	s.Run("SuccessFilterByAction", func() {
+		//setup
+		require := s.Require()
+		ctx := s.ActorContext()
+		pageInfo := &models.ComplianceAuditLogPageInfo{
+			Actions: []string{"delete"},
+		}
+
+		//test
+		logs, err := s.store.ListComplianceAuditLogs(ctx, pageInfo)
+		require.NoError(err, "expected no errors")
+		require.NotNil(logs.Logs, "there were no logs")
+		require.Len(logs.Logs, 2, fmt.Sprintf("there should be 2 logs, but there were %d", len(logs.Logs)))
+		for _, log := range logs.Logs {
+			require.Equal(enum.ActionDelete, log.Action, "expected only delete actions")
+		}
+	})
+
+	s.Run("SuccessFilterByActorTypeAndAction", func() {
+		//setup
+		require := s.Require()
+		ctx := s.ActorContext()
+		pageInfo := &models.ComplianceAuditLogPageInfo{
+			ActorTypes: []string{"api_key", "sunrise"},
+			Actions:    []string{"update"},
+		}
+
+		//test
+		logs, err := s.store.ListComplianceAuditLogs(ctx, pageInfo)
+		require.NoError(err, "expected no errors")
+		require.NotNil(logs.Logs, "there were no logs")
+		require.Len(logs.Logs, 2, fmt.Sprintf("there should be 2 logs, but there were %d", len(logs.Logs)))
+	})
+
 	s.Run("SuccessFilterByActorTypeAndResourceType", func() {
 		//setup
 		require := s.Require()
@@ -267,6 +301,44 @@ func (s *storeTestSuite) TestListComplianceAuditLogsFilters() {
 	})
 }
 
+func (s *storeTestSuite) TestListComplianceAuditLogsPagination() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	// The first page should be full and have a next page cursor
+	logs, err := s.store.ListComplianceAuditLogs(ctx, &models.ComplianceAuditLogPageInfo{PageInfo: models.PageInfo{PageSize: 4}})
+	require.NoError(err, "expected no errors")
+	require.Len(logs.Logs, 4, "expected a full first page")
+	require.Equal(logs.Logs[3].ID, logs.Page.NextPageID, "expected the next page id to be the last log in the page")
+
+	for i := 1; i < len(logs.Logs); i++ {
+		require.False(logs.Logs[i].ResourceModified.After(logs.Logs[i-1].ResourceModified), "expected logs to be ordered newest first")
+	}
+
+	// The second page should contain the remaining logs and no cursor
+	next, err := s.store.ListComplianceAuditLogs(ctx, &models.ComplianceAuditLogPageInfo{PageInfo: models.PageInfo{PageSize: 4, NextPageID: logs.Page.NextPageID}})
+	require.NoError(err, "expected no errors")
+	require.Len(next.Logs, 2, "expected a partial second page")
+	require.True(next.Page.NextPageID.IsZero(), "expected no next page id on the last page")
+
+	seen := make(map[ulid.ULID]struct{})
+	for _, log := range append(logs.Logs, next.Logs...) {
+		seen[log.ID] = struct{}{}
+	}
+	require.Len(seen, 6, "expected each log to appear on exactly one page")
+
+	// Pagination should respect the filters
+	filtered, err := s.store.ListComplianceAuditLogs(ctx, &models.ComplianceAuditLogPageInfo{PageInfo: models.PageInfo{PageSize: 1}, ActorTypes: []string{"user"}})
+	require.NoError(err, "expected no errors")
+	require.Len(filtered.Logs, 1, "expected a full first page")
+	require.False(filtered.Page.NextPageID.IsZero(), "expected a next page id")
+
+	filtered, err = s.store.ListComplianceAuditLogs(ctx, &models.ComplianceAuditLogPageInfo{PageInfo: models.PageInfo{PageSize: 1, NextPageID: filtered.Page.NextPageID}, ActorTypes: []string{"user"}})
+	require.NoError(err, "expected no errors")
+	require.Len(filtered.Logs, 1, "expected a full second page")
+	require.True(filtered.Page.NextPageID.IsZero(), "expected no next page id on the last page")
+}
+
 func (s *storeTestSuite) TestCreateComplianceAuditLog() {
 	s.Run("SuccessWithChangeNotes", func() {
 		//setup
@@ -19,8 +19,8 @@ import (
 //===========================================================================
 
 const (
-	listUsersSQL   = "SELECT id, name, email, role_id, last_login, created, modified FROM users ORDER BY created DESC"
-	filterUsersSQL = "SELECT u.id, u.name, u.email, u.role_id, u.last_login, u.created, u.modified FROM users u JOIN roles r ON role_id=r.id WHERE r.title=:role COLLATE NOCASE ORDER BY u.created DESC"
+	listUsersSQL   = "SELECT id, name, email, role_id, last_login, created, modified, mfa_enrolled, login_locked_until FROM users ORDER BY created DESC"
+	filterUsersSQL = "SELECT u.id, u.name, u.email, u.role_id, u.last_login, u.created, u.modified, u.mfa_enrolled, u.login_locked_until FROM users u JOIN roles r ON role_id=r.id WHERE r.title=:role COLLATE NOCASE ORDER BY u.created DESC"
 )
 
 func (s *Store) ListUsers(ctx context.Context, page *models.UserPageInfo) (out *models.UserPage, err error) {
@@ -181,7 +181,7 @@ func (t *Tx) RetrieveUser(emailOrUserID any) (user *models.User, err error) {
 
 	// Fetch user details
 	user = &models.User{}
-	if err = user.Scan(t.tx.QueryRow(query, param)); err != nil {
+	if err = user.Scan(t.decrypt(t.tx.QueryRow(query, param), encryptedUserFields(user)...)); err != nil {
 		return nil, dbe(err)
 	}
 
@@ -317,8 +317,140 @@ func (t *Tx) SetUserLastLogin(userID ulid.ULID, lastLogin time.Time) (err error)
 	return nil
 }
 
-const deleteUserSQL = "DELETE FROM users WHERE id=:id"
+const (
+	loginFailuresSQL      = "SELECT login_failures, login_lockouts FROM users WHERE id=:id"
+	recordLoginFailureSQL = "UPDATE users SET login_failures=:loginFailures, login_lockouts=:loginLockouts, login_locked_until=:loginLocked WHERE id=:id"
+	resetLoginFailuresSQL = "UPDATE users SET login_failures=0, login_lockouts=0, login_locked_until=NULL WHERE id=:id"
+	unlockUserSQL         = "UPDATE users SET login_failures=0, login_lockouts=0, login_locked_until=NULL, modified=:modified WHERE id=:id"
+)
+
+func (s *Store) RecordUserLoginFailure(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) (err error) {
+	//NOTE: recording failed logins does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.RecordUserLoginFailure(userID, maxFailures, lockout, maxLockout); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Increments the user's consecutive failed logins; once maxFailures consecutive logins
+// have failed, the user is locked out and the count is reset so that the user has
+// another maxFailures attempts after the lockout. The lockout duration is doubled for
+// every lockout since the user's last successful login up to the maxLockout duration.
+func (t *Tx) RecordUserLoginFailure(userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) (err error) {
+	//NOTE: recording failed logins does not require an audit log entry
+	var failures, lockouts int64
+	if err = t.tx.QueryRow(loginFailuresSQL, sql.Named("id", userID)).Scan(&failures, &lockouts); err != nil {
+		return dbe(err)
+	}
+
+	var lockedUntil sql.NullTime
+	if failures++; failures >= maxFailures {
+		for i := int64(0); i < lockouts && lockout < maxLockout; i++ {
+			lockout *= 2
+		}
+
+		lockedUntil = sql.NullTime{Valid: true, Time: time.Now().Add(min(lockout, maxLockout))}
+		failures = 0
+		lockouts++
+	}
+
+	params := []any{
+		sql.Named("id", userID),
+		sql.Named("loginFailures", failures),
+		sql.Named("loginLockouts", lockouts),
+		sql.Named("loginLocked", lockedUntil),
+	}
+
+	if _, err = t.tx.Exec(recordLoginFailureSQL, params...); err != nil {
+		return dbe(err)
+	}
+	return nil
+}
+
+func (s *Store) ResetUserLoginFailures(ctx context.Context, userID ulid.ULID) (err error) {
+	//NOTE: recording successful logins does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.ResetUserLoginFailures(userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Resets the user's failed logins and lockouts after a successful login.
+func (t *Tx) ResetUserLoginFailures(userID ulid.ULID) (err error) {
+	//NOTE: recording successful logins does not require an audit log entry
+	var result sql.Result
+	if result, err = t.tx.Exec(resetLoginFailuresSQL, sql.Named("id", userID)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) UnlockUser(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UnlockUser(userID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Removes the login lockout of the user and resets their failed logins and lockouts so
+// that the user can login immediately.
+func (t *Tx) UnlockUser(userID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	now := time.Now()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(unlockUserSQL, sql.Named("id", userID), sql.Named("modified", now)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	// Fill the audit log and create it
+	actorID, actorType := t.GetActor()
+	if err = t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       userID.Bytes(),
+		ResourceType:     enum.ResourceUser,
+		ResourceModified: now,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const (
+	revokeUserSQL = "INSERT INTO revoked_users (id, name, email, role, last_login, created, revoked) SELECT u.id, u.name, u.email, r.title, u.last_login, u.created, :revoked FROM users u JOIN roles r ON r.id=u.role_id WHERE u.id=:id"
+	deleteUserSQL = "DELETE FROM users WHERE id=:id"
+)
 
+// DeleteUser removes the user and their credentials from the database, retaining the
+// identity of the user in the revoked users table so audit log actors can be resolved.
 func (s *Store) DeleteUser(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
 	if tx, err = s.BeginTx(ctx, nil); err != nil {
@@ -335,12 +467,16 @@ func (s *Store) DeleteUser(ctx context.Context, userID ulid.ULID, auditLog *mode
 
 func (t *Tx) DeleteUser(userID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
 	var result sql.Result
-	if result, err = t.tx.Exec(deleteUserSQL, sql.Named("id", userID)); err != nil {
+	if result, err = t.tx.Exec(revokeUserSQL, sql.Named("id", userID), sql.Named("revoked", time.Now())); err != nil {
 		return dbe(err)
 	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
 		return dberr.ErrNotFound
 	}
 
+	if _, err = t.tx.Exec(deleteUserSQL, sql.Named("id", userID)); err != nil {
+		return dbe(err)
+	}
+
 	// Fill the audit log and create it
 	actorID, actorType := t.GetActor()
 	if err := t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
@@ -358,6 +494,87 @@ func (t *Tx) DeleteUser(userID ulid.ULID, auditLog *models.ComplianceAuditLog) (
 	return nil
 }
 
+const retrieveRevokedUserSQL = "SELECT * FROM revoked_users WHERE id=:id"
+
+func (s *Store) RetrieveRevokedUser(ctx context.Context, userID ulid.ULID) (user *models.RevokedUser, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if user, err = tx.RetrieveRevokedUser(userID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (t *Tx) RetrieveRevokedUser(userID ulid.ULID) (user *models.RevokedUser, err error) {
+	user = &models.RevokedUser{}
+	if err = user.Scan(t.tx.QueryRow(retrieveRevokedUserSQL, sql.Named("id", userID))); err != nil {
+		return nil, dbe(err)
+	}
+	return user, nil
+}
+
+const listRolesSQL = "SELECT * FROM roles ORDER BY id"
+
+func (s *Store) ListRoles(ctx context.Context) (roles []*models.Role, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if roles, err = tx.ListRoles(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (t *Tx) ListRoles() (roles []*models.Role, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listRolesSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	roles = make([]*models.Role, 0, 3)
+	for rows.Next() {
+		role := &models.Role{}
+		if err = role.Scan(rows); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	rows.Close()
+
+	// Fetch the permissions of each role
+	for _, role := range roles {
+		var permissions []*models.Permission
+		if permissions, err = t.fetchRolePermissions(role.ID); err != nil {
+			return nil, err
+		}
+		role.SetPermissions(permissions)
+	}
+
+	return roles, nil
+}
+
 const lookupRoleSQL = "SELECT * FROM roles WHERE title like :role LIMIT 1"
 
 func (s *Store) LookupRole(ctx context.Context, role string) (model *models.Role, err error) {
@@ -447,7 +664,7 @@ func (t *Tx) fetchUserPermissions(userID ulid.ULID) (permissions []string, err e
 // APIKeys Store
 //===========================================================================
 
-const listAPIKeysSQL = "SELECT id, description, client_id, last_seen, created, modified FROM api_keys"
+const listAPIKeysSQL = "SELECT id, description, client_id, last_seen, created, modified, expires, allowed_networks FROM api_keys"
 
 func (s *Store) ListAPIKeys(ctx context.Context, page *models.PageInfo) (out *models.APIKeyPage, err error) {
 	var tx *Tx
@@ -490,8 +707,9 @@ func (t *Tx) ListAPIKeys(page *models.PageInfo) (out *models.APIKeyPage, err err
 }
 
 const (
-	createKeySQL     = "INSERT INTO api_keys (id, description, client_id, secret, last_seen, created, modified) VALUES (:id, :description, :clientID, :secret, :lastSeen, :created, :modified)"
-	createKeyPermSQL = "INSERT INTO api_key_permissions (api_key_id, permission_id, created, modified) VALUES (:keyID, (SELECT id FROM permissions WHERE title=:permission), :created, :modified)"
+	createKeySQL       = "INSERT INTO api_keys (id, description, client_id, secret, last_seen, created, modified, expires, allowed_networks) VALUES (:id, :description, :clientID, :secret, :lastSeen, :created, :modified, :expires, :allowedNetworks)"
+	createKeyPermSQL   = "INSERT INTO api_key_permissions (api_key_id, permission_id, created, modified) VALUES (:keyID, (SELECT id FROM permissions WHERE title=:permission), :created, :modified)"
+	clientIDRevokedSQL = "SELECT EXISTS(SELECT 1 FROM revoked_api_keys WHERE client_id=:clientID)"
 )
 
 func (s *Store) CreateAPIKey(ctx context.Context, key *models.APIKey, auditLog *models.ComplianceAuditLog) (err error) {
@@ -513,6 +731,16 @@ func (t *Tx) CreateAPIKey(key *models.APIKey, auditLog *models.ComplianceAuditLo
 		return dberr.ErrNoIDOnCreate
 	}
 
+	// The client id of a revoked api key cannot be reused
+	var revoked bool
+	if err = t.tx.QueryRow(clientIDRevokedSQL, sql.Named("clientID", key.ClientID)).Scan(&revoked); err != nil {
+		return dbe(err)
+	}
+
+	if revoked {
+		return dberr.ErrClientIDRevoked
+	}
+
 	key.ID = ulid.MakeSecure()
 	key.Created = time.Now()
 	key.Modified = key.Created
@@ -609,9 +837,10 @@ func (t *Tx) RetrieveAPIKey(clientIDOrKeyID any) (key *models.APIKey, err error)
 	return key, nil
 }
 
-const updateKeySQL = "UPDATE api_keys SET description=:description, last_seen=:lastSeen, modified=:modified WHERE id=:id"
+const updateKeySQL = "UPDATE api_keys SET description=:description, expires=:expires, allowed_networks=:allowedNetworks, modified=:modified WHERE id=:id"
 
-// NOTE: the only thing that can be updated on an api key right now is last_seen
+// NOTE: only the description, expiration, and allowed networks of an api key can be
+// updated; use RotateAPIKey to change the secret and SetAPIKeyLastSeen for last_seen.
 func (s *Store) UpdateAPIKey(ctx context.Context, key *models.APIKey, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
 	if tx, err = s.BeginTx(ctx, nil); err != nil {
@@ -626,7 +855,6 @@ func (s *Store) UpdateAPIKey(ctx context.Context, key *models.APIKey, auditLog *
 	return tx.Commit()
 }
 
-// NOTE: the only thing that can be updated on an api key right now is last_seen
 func (t *Tx) UpdateAPIKey(key *models.APIKey, auditLog *models.ComplianceAuditLog) (err error) {
 	key.Modified = time.Now()
 	var result sql.Result
@@ -688,8 +916,141 @@ func (t *Tx) SetAPIKeyLastSeen(keyID ulid.ULID, lastSeen time.Time) (err error)
 	return nil
 }
 
-const deleteKeySQL = "DELETE FROM api_keys WHERE id=:id"
+const rotateKeySQL = "UPDATE api_keys SET previous_secret=secret, previous_secret_expires=:previousSecretExpires, secret=:secret, secret_rotated=:secretRotated, modified=:modified WHERE id=:id"
+
+// RotateAPIKey replaces the secret of the api key with the secret on the model. The
+// current secret is kept as the previous secret, which remains valid until the
+// PreviousSecretExpires timestamp so that clients can be updated without downtime.
+// Refresh tokens issued before the rotation can no longer be used.
+func (s *Store) RotateAPIKey(ctx context.Context, key *models.APIKey, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.RotateAPIKey(key, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) RotateAPIKey(key *models.APIKey, auditLog *models.ComplianceAuditLog) (err error) {
+	key.Modified = time.Now()
+	key.SecretRotated = sql.NullTime{Valid: true, Time: key.Modified}
+	var result sql.Result
+	if result, err = t.tx.Exec(rotateKeySQL, key.Params()...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	// Fill the audit log and create it
+	actorID, actorType := t.GetActor()
+	if err := t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       key.ID.Bytes(),
+		ResourceType:     enum.ResourceAPIKey,
+		ResourceModified: key.Modified,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const recordAPIKeyUsageSQL = "INSERT INTO api_key_usage (api_key_id, day, requests, last_ip, last_used) VALUES (:keyID, :day, 1, :ip, :ts) ON CONFLICT (api_key_id, day) DO UPDATE SET requests=requests+1, last_ip=excluded.last_ip, last_used=excluded.last_used"
+
+// RecordAPIKeyUsage increments the daily request count for the api key.
+func (s *Store) RecordAPIKeyUsage(ctx context.Context, keyID ulid.ULID, ip string, ts time.Time) (err error) {
+	//NOTE: this type of update does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.RecordAPIKeyUsage(keyID, ip, ts); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) RecordAPIKeyUsage(keyID ulid.ULID, ip string, ts time.Time) (err error) {
+	//NOTE: this type of update does not require an audit log entry
+	ts = ts.UTC()
+	params := []any{
+		sql.Named("keyID", keyID),
+		sql.Named("day", time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.UTC)),
+		sql.Named("ip", sql.NullString{String: ip, Valid: ip != ""}),
+		sql.Named("ts", ts),
+	}
+
+	if _, err = t.tx.Exec(recordAPIKeyUsageSQL, params...); err != nil {
+		return dbe(err)
+	}
+	return nil
+}
+
+const listAPIKeyUsageSQL = "SELECT * FROM api_key_usage WHERE api_key_id=:keyID AND day >= :since ORDER BY day DESC"
+
+// ListAPIKeyUsage returns the daily usage statistics of the api key since the
+// specified timestamp, ordered from the most recent day.
+func (s *Store) ListAPIKeyUsage(ctx context.Context, keyID ulid.ULID, since time.Time) (out []*models.APIKeyUsage, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListAPIKeyUsage(keyID, since); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *Tx) ListAPIKeyUsage(keyID ulid.ULID, since time.Time) (out []*models.APIKeyUsage, err error) {
+	since = since.UTC()
+	params := []any{
+		sql.Named("keyID", keyID),
+		sql.Named("since", time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)),
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listAPIKeyUsageSQL, params...); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.APIKeyUsage, 0)
+	for rows.Next() {
+		usage := &models.APIKeyUsage{}
+		if err = usage.Scan(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, usage)
+	}
+
+	return out, dbe(rows.Err())
+}
+
+const (
+	revokeKeySQL = "INSERT INTO revoked_api_keys (id, description, client_id, last_seen, created, revoked) SELECT id, description, client_id, last_seen, created, :revoked FROM api_keys WHERE id=:id"
+	deleteKeySQL = "DELETE FROM api_keys WHERE id=:id"
+)
 
+// DeleteAPIKey removes the api key and its secret from the database, retaining the
+// identity of the key in the revoked api keys table so audit log actors can be
+// resolved and so that the client id cannot be reused.
 func (s *Store) DeleteAPIKey(ctx context.Context, keyID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
 	if tx, err = s.BeginTx(ctx, nil); err != nil {
@@ -706,12 +1067,16 @@ func (s *Store) DeleteAPIKey(ctx context.Context, keyID ulid.ULID, auditLog *mod
 
 func (t *Tx) DeleteAPIKey(keyID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
 	var result sql.Result
-	if result, err = t.tx.Exec(deleteKeySQL, sql.Named("id", keyID)); err != nil {
+	if result, err = t.tx.Exec(revokeKeySQL, sql.Named("id", keyID), sql.Named("revoked", time.Now())); err != nil {
 		return dbe(err)
 	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
 		return dberr.ErrNotFound
 	}
 
+	if _, err = t.tx.Exec(deleteKeySQL, sql.Named("id", keyID)); err != nil {
+		return dbe(err)
+	}
+
 	// Fill the audit log and create it
 	actorID, actorType := t.GetActor()
 	if err := t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
@@ -729,6 +1094,34 @@ func (t *Tx) DeleteAPIKey(keyID ulid.ULID, auditLog *models.ComplianceAuditLog)
 	return nil
 }
 
+const retrieveRevokedKeySQL = "SELECT * FROM revoked_api_keys WHERE id=:id"
+
+func (s *Store) RetrieveRevokedAPIKey(ctx context.Context, keyID ulid.ULID) (key *models.RevokedAPIKey, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if key, err = tx.RetrieveRevokedAPIKey(keyID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (t *Tx) RetrieveRevokedAPIKey(keyID ulid.ULID) (key *models.RevokedAPIKey, err error) {
+	key = &models.RevokedAPIKey{}
+	if err = key.Scan(t.tx.QueryRow(retrieveRevokedKeySQL, sql.Named("id", keyID))); err != nil {
+		return nil, dbe(err)
+	}
+	return key, nil
+}
+
 const keyPermissionsSQL = "SELECT permission FROM api_key_permission_list WHERE api_key_id=:keyID"
 
 func (t *Tx) fetchAPIKeyPermissions(keyID ulid.ULID) (permissions []string, err error) {
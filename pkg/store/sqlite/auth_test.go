@@ -358,6 +358,14 @@ func (s *storeTestSuite) TestDeleteUser_Success() {
 	require.Equal(errors.ErrNotFound, err, "expected user to be missing")
 	require.Nil(user, "user should be nil")
 
+	// the identity of the user should be retained without credentials
+	revoked, err := s.store.RetrieveRevokedUser(ctx, userId)
+	require.NoError(err, "expected the user to be revoked")
+	require.Equal(userId, revoked.ID)
+	require.NotEmpty(revoked.Email, "expected the email to be retained")
+	require.NotEmpty(revoked.Role, "expected the role title to be retained")
+	require.False(revoked.Revoked.IsZero(), "expected the revoked timestamp to be set")
+
 	//check for audit log creation
 	ok := s.AssertAuditLogCount(map[string]int{
 		ActionResourceKey(enum.ActionDelete, enum.ResourceUser): 1,
@@ -376,6 +384,9 @@ func (s *storeTestSuite) TestDeleteUser_FailureNotFound() {
 	require.Error(err, "expected no error")
 	require.Equal(errors.ErrNotFound, err, "expected user to be missing")
 
+	_, err = s.store.RetrieveRevokedUser(ctx, userId)
+	require.ErrorIs(err, errors.ErrNotFound, "expected no revoked user to be created")
+
 	//check for audit log creation
 	ok := s.AssertAuditLogCount(map[string]int{})
 	require.True(ok, "audit log count was off")
@@ -636,7 +647,10 @@ func (s *storeTestSuite) TestUpdateAPIKey_Success() {
 
 	prevMod := apiKey.Modified
 	newDescription := sql.NullString{String: "New Description 83r29123e89", Valid: true}
+	newExpires := time.Now().AddDate(0, 3, 0).Truncate(time.Second)
 	apiKey.Description = newDescription
+	apiKey.Expires = sql.NullTime{Time: newExpires, Valid: true}
+	apiKey.AllowedNetworks = models.AllowedNetworks{"10.0.0.0/8", "192.0.2.0/24"}
 
 	//test
 	err = s.store.UpdateAPIKey(ctx, apiKey, &models.ComplianceAuditLog{})
@@ -647,6 +661,8 @@ func (s *storeTestSuite) TestUpdateAPIKey_Success() {
 	require.NoError(err, "expected no errors")
 	require.NotNil(apiKey, "api key should not be nil")
 	require.Equal(newDescription, apiKey.Description, "expected the new description")
+	require.True(newExpires.Equal(apiKey.Expires.Time), "expected the new expiration")
+	require.Equal(models.AllowedNetworks{"10.0.0.0/8", "192.0.2.0/24"}, apiKey.AllowedNetworks, "expected the new allowed networks")
 	require.True(prevMod.Before(apiKey.Modified), "expected the modified time to be newer")
 
 	permissions := apiKey.Permissions()
@@ -744,6 +760,89 @@ func (s *storeTestSuite) TestSetAPIKeyLastSeen_FailureNotFoundZeroID() {
 	require.Equal(errors.ErrNotFound, err, "expected ErrNotFound")
 }
 
+func (s *storeTestSuite) TestRotateAPIKey_Success() {
+	//setup
+	require := s.Require()
+	ctx := s.ActorContext()
+	keyId := ulid.MustParse("01HWQEJJDMS5EKNARHPJEDMHA4")
+	apiKey, err := s.store.RetrieveAPIKey(ctx, keyId)
+	require.NoError(err, "expected no errors")
+	require.False(apiKey.PreviousSecret.Valid, "expected no previous secret before rotation")
+	require.False(apiKey.SecretRotated.Valid, "expected no rotation timestamp before rotation")
+
+	prevSecret := apiKey.Secret
+	graceExpires := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	apiKey.Secret = "$argon2id$v=19$m=65536,t=1,p=2$rotated$rotated"
+	apiKey.PreviousSecretExpires = sql.NullTime{Time: graceExpires, Valid: true}
+
+	//test
+	err = s.store.RotateAPIKey(ctx, apiKey, &models.ComplianceAuditLog{})
+	require.NoError(err, "expected no errors")
+
+	apiKey, err = s.store.RetrieveAPIKey(ctx, keyId)
+	require.NoError(err, "expected no errors")
+	require.Equal("$argon2id$v=19$m=65536,t=1,p=2$rotated$rotated", apiKey.Secret, "expected the new secret")
+	require.Equal(sql.NullString{String: prevSecret, Valid: true}, apiKey.PreviousSecret, "expected the old secret to be kept")
+	require.True(graceExpires.Equal(apiKey.PreviousSecretExpires.Time), "expected the grace period to be set")
+	require.True(apiKey.SecretRotated.Valid, "expected the rotation timestamp to be set")
+	require.WithinDuration(time.Now(), apiKey.SecretRotated.Time, time.Minute)
+
+	//check for audit log creation
+	ok := s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceAPIKey): 1,
+	})
+	require.True(ok, "audit log count was off")
+}
+
+func (s *storeTestSuite) TestRotateAPIKey_FailureNotFound() {
+	//setup
+	require := s.Require()
+	ctx := s.ActorContext()
+	apiKey := mock.GetSampleAPIKey(true)
+
+	//test
+	err := s.store.RotateAPIKey(ctx, apiKey, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound, "expected ErrNotFound")
+
+	//check for audit log creation
+	ok := s.AssertAuditLogCount(map[string]int{})
+	require.True(ok, "audit log count was off")
+}
+
+func (s *storeTestSuite) TestAPIKeyUsage() {
+	//setup
+	require := s.Require()
+	ctx := s.ActorContext()
+	keyId := ulid.MustParse("01HWQEJJDMS5EKNARHPJEDMHA4")
+
+	today := time.Now().UTC().Truncate(24 * time.Hour).Add(12 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	lastMonth := today.AddDate(0, -1, -1)
+
+	//test
+	require.NoError(s.store.RecordAPIKeyUsage(ctx, keyId, "10.0.0.1", lastMonth))
+	require.NoError(s.store.RecordAPIKeyUsage(ctx, keyId, "10.0.0.1", yesterday))
+	require.NoError(s.store.RecordAPIKeyUsage(ctx, keyId, "10.0.0.2", today.Add(-time.Second)))
+	require.NoError(s.store.RecordAPIKeyUsage(ctx, keyId, "10.0.0.3", today))
+
+	usage, err := s.store.ListAPIKeyUsage(ctx, keyId, today.AddDate(0, 0, -7))
+	require.NoError(err, "expected no errors")
+	require.Len(usage, 2, "expected usage for today and yesterday only")
+
+	require.Equal(keyId, usage[0].APIKeyID)
+	require.Equal(int64(2), usage[0].Requests, "expected two requests recorded today")
+	require.Equal("10.0.0.3", usage[0].LastIP.String, "expected the last ip address to be updated")
+	require.Equal(int64(1), usage[1].Requests, "expected one request recorded yesterday")
+
+	usage, err = s.store.ListAPIKeyUsage(ctx, ulid.MakeSecure(), lastMonth)
+	require.NoError(err, "expected no errors")
+	require.Len(usage, 0, "expected no usage for an unknown key")
+
+	//check no audit logs were created
+	ok := s.AssertAuditLogCount(map[string]int{})
+	require.True(ok, "audit log count was off")
+}
+
 func (s *storeTestSuite) TestDeleteAPIKey_Success() {
 	//setup
 	require := s.Require()
@@ -759,6 +858,20 @@ func (s *storeTestSuite) TestDeleteAPIKey_Success() {
 	require.Equal(errors.ErrNotFound, err, "expected ErrNotFound")
 	require.Nil(apiKey, "api key should be nil")
 
+	// the identity of the api key should be retained without the secret
+	revoked, err := s.store.RetrieveRevokedAPIKey(ctx, keyId)
+	require.NoError(err, "expected the api key to be revoked")
+	require.Equal(keyId, revoked.ID)
+	require.NotEmpty(revoked.ClientID, "expected the client id to be retained")
+	require.False(revoked.Revoked.IsZero(), "expected the revoked timestamp to be set")
+
+	// the client id of the revoked api key cannot be reused
+	reuse := mock.GetSampleAPIKey(true)
+	reuse.ID = ulid.Zero
+	reuse.ClientID = revoked.ClientID
+	err = s.store.CreateAPIKey(ctx, reuse, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrClientIDRevoked, "expected the revoked client id to be rejected")
+
 	//check for audit log creation
 	ok := s.AssertAuditLogCount(map[string]int{
 		ActionResourceKey(enum.ActionDelete, enum.ResourceAPIKey): 1,
@@ -777,6 +890,9 @@ func (s *storeTestSuite) TestDeleteAPIKey_FailureNotFound() {
 	require.Error(err, "expected an error")
 	require.Equal(errors.ErrNotFound, err, "expected ErrNotFound")
 
+	_, err = s.store.RetrieveRevokedAPIKey(ctx, keyId)
+	require.ErrorIs(err, errors.ErrNotFound, "expected no revoked api key to be created")
+
 	//check for audit log creation
 	ok := s.AssertAuditLogCount(map[string]int{})
 	require.True(ok, "audit log count was off")
@@ -0,0 +1,246 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Batches
+//===========================================================================
+
+const (
+	batchSummarySQL = "SELECT b.id, b.filename, b.format, b.status, b.created_by, b.created, b.modified, COUNT(i.id), COUNT(i.id) FILTER (WHERE i.status='invalid'), COUNT(i.id) FILTER (WHERE i.status='pending'), COUNT(i.id) FILTER (WHERE i.status='sent'), COUNT(i.id) FILTER (WHERE i.status='queued'), COUNT(i.id) FILTER (WHERE i.status='held'), COUNT(i.id) FILTER (WHERE i.status='failed') FROM batches b LEFT JOIN batch_items i ON i.batch_id=b.id"
+	listBatchesSQL  = batchSummarySQL + " GROUP BY b.id ORDER BY b.created DESC, b.id DESC"
+)
+
+// List the batches that have been imported, most recent first, with a summary of the
+// status of the items in each batch.
+func (s *Store) ListBatches(ctx context.Context) (out []*models.Batch, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.tx.Query(listBatchesSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.Batch, 0)
+	for rows.Next() {
+		batch := &models.Batch{}
+		if err = batch.Scan(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, batch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const (
+	createBatchSQL     = "INSERT INTO batches (id, filename, format, status, created_by, created, modified) VALUES (:id, :filename, :format, :status, :createdBy, :created, :modified)"
+	createBatchItemSQL = "INSERT INTO batch_items (id, batch_id, row, status, request, errors, transaction_id, created, modified) VALUES (:id, :batchID, :row, :status, :request, :errors, :transactionID, :created, :modified)"
+)
+
+// Create a batch and all of its items in a single transaction. The batch is created in
+// the validated state; the status of each item must be either invalid or pending.
+func (s *Store) CreateBatch(ctx context.Context, batch *models.Batch, auditLog *models.ComplianceAuditLog) (err error) {
+	if !batch.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	if batch.Format == "" {
+		return dberr.ErrMissingValue
+	}
+
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	batch.ID = ulid.MakeSecure()
+	batch.Status = enum.BatchValidated
+	batch.Created = time.Now()
+	batch.Modified = batch.Created
+
+	if _, err = tx.tx.Exec(createBatchSQL, batch.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	for _, item := range batch.Items {
+		if item.Status != enum.BatchItemInvalid && item.Status != enum.BatchItemPending {
+			return dberr.ErrMissingValue
+		}
+
+		item.ID = ulid.MakeSecure()
+		item.BatchID = batch.ID
+		item.Created = batch.Created
+		item.Modified = batch.Created
+
+		var params []any
+		if params, err = tx.encryptParams(item.Params(), "request"); err != nil {
+			return err
+		}
+
+		if _, err = tx.tx.Exec(createBatchItemSQL, params...); err != nil {
+			return dbe(err)
+		}
+	}
+
+	if err = tx.batchAuditLog(batch, enum.ActionCreate, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const retrieveBatchSQL = batchSummarySQL + " WHERE b.id=:id GROUP BY b.id"
+
+// Retrieve a batch with a summary of the status of its items; the items themselves
+// are listed with ListBatchItems.
+func (s *Store) RetrieveBatch(ctx context.Context, id ulid.ULID) (batch *models.Batch, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	batch = &models.Batch{}
+	if err = batch.Scan(tx.tx.QueryRow(retrieveBatchSQL, sql.Named("id", id))); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+const updateBatchSQL = "UPDATE batches SET status=:status, modified=:modified WHERE id=:id"
+
+// Update the status of a batch; the items of the batch are updated with UpdateBatchItem.
+func (s *Store) UpdateBatch(ctx context.Context, batch *models.Batch, auditLog *models.ComplianceAuditLog) (err error) {
+	if batch.ID.IsZero() {
+		return dberr.ErrMissingID
+	}
+
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	batch.Modified = time.Now()
+
+	var result sql.Result
+	if result, err = tx.tx.Exec(updateBatchSQL, batch.Params()...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	if err = tx.batchAuditLog(batch, enum.ActionUpdate, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) batchAuditLog(batch *models.Batch, action enum.Action, auditLog *models.ComplianceAuditLog) error {
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       batch.ID.Bytes(),
+		ResourceType:     enum.ResourceBatch,
+		ResourceModified: batch.Modified,
+		Action:           action,
+		ChangeNotes:      auditLog.ChangeNotes,
+	})
+}
+
+//===========================================================================
+// Batch Items
+//===========================================================================
+
+const listBatchItemsSQL = "SELECT * FROM batch_items WHERE batch_id=:batchID ORDER BY row ASC"
+
+// List the items of a batch in the order they were imported with the requests of the
+// items decrypted.
+func (s *Store) ListBatchItems(ctx context.Context, batchID ulid.ULID) (out []*models.BatchItem, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.tx.Query(listBatchItemsSQL, sql.Named("batchID", batchID)); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.BatchItem, 0)
+	for rows.Next() {
+		item := &models.BatchItem{}
+		if err = item.Scan(tx.decrypt(rows, encryptedBatchItemFields(item)...)); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const updateBatchItemSQL = "UPDATE batch_items SET status=:status, errors=:errors, transaction_id=:transactionID, modified=:modified WHERE id=:id"
+
+// Update the result of sending a batch item; the request of the item cannot be
+// modified. Item updates are not audit logged since the transactions that are created
+// when the items are sent are audit logged instead.
+func (s *Store) UpdateBatchItem(ctx context.Context, item *models.BatchItem) (err error) {
+	if item.ID.IsZero() {
+		return dberr.ErrMissingID
+	}
+
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	item.Modified = time.Now()
+
+	var result sql.Result
+	if result, err = tx.tx.Exec(updateBatchItemSQL, item.Params()...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,128 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestBatches() {
+	s.Run("Lifecycle", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		batch := &models.Batch{
+			Filename:  "withdrawals.csv",
+			Format:    "csv",
+			CreatedBy: "Compliance User",
+			Items: []*models.BatchItem{
+				{Row: 2, Status: enum.BatchItemPending, Request: sql.NullString{Valid: true, String: `{"originator":{"forename":"Jenny"}}`}},
+				{Row: 3, Status: enum.BatchItemInvalid, Request: sql.NullString{Valid: true, String: `{}`}, Errors: []byte(`[{"field":"routing","error":"missing routing"}]`)},
+			},
+		}
+
+		err := s.store.CreateBatch(ctx, batch, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create batch")
+		require.False(batch.ID.IsZero(), "expected an id to be assigned")
+		require.Equal(enum.BatchValidated, batch.Status)
+
+		cmp, err := s.store.RetrieveBatch(ctx, batch.ID)
+		require.NoError(err, "could not retrieve batch")
+		require.Equal("withdrawals.csv", cmp.Filename)
+		require.Equal("Compliance User", cmp.CreatedBy)
+		require.Equal(int64(2), cmp.Total)
+		require.Equal(int64(1), cmp.Pending)
+		require.Equal(int64(1), cmp.Invalid)
+		require.True(cmp.IsSendable())
+
+		// A batch whose send was interrupted can be resumed
+		cmp.Status = enum.BatchSending
+		require.True(cmp.IsSendable())
+
+		// The requests of the items contain PII and are encrypted
+		request, _ := s.rawColumns("SELECT request, id FROM batch_items WHERE row=2")
+		require.True(pii.IsEncrypted([]byte(request)), "expected the request to be encrypted")
+
+		items, err := s.store.ListBatchItems(ctx, batch.ID)
+		require.NoError(err, "could not list batch items")
+		require.Len(items, 2)
+		require.Equal(int64(2), items[0].Row)
+		require.Equal(`{"originator":{"forename":"Jenny"}}`, items[0].Request.String)
+		require.Equal(`[{"field":"routing","error":"missing routing"}]`, string(items[1].Errors))
+
+		// Record the result of sending the pending item
+		items[0].Status = enum.BatchItemSent
+		items[0].TransactionID = uuid.NullUUID{Valid: true, UUID: txID}
+		require.NoError(s.store.UpdateBatchItem(ctx, items[0]), "could not update batch item")
+
+		cmp.Status = enum.BatchCompleted
+		require.NoError(s.store.UpdateBatch(ctx, cmp, &models.ComplianceAuditLog{}), "could not update batch")
+
+		batches, err := s.store.ListBatches(ctx)
+		require.NoError(err, "could not list batches")
+		require.Len(batches, 1)
+		require.Equal(enum.BatchCompleted, batches[0].Status)
+		require.Equal(int64(1), batches[0].Sent)
+		require.Equal(int64(0), batches[0].Pending)
+		require.False(batches[0].IsSendable())
+
+		items, err = s.store.ListBatchItems(ctx, batch.ID)
+		require.NoError(err, "could not list batch items")
+		require.Equal(txID, items[0].TransactionID.UUID)
+
+		logs, err := s.store.ListComplianceAuditLogs(ctx, &models.ComplianceAuditLogPageInfo{ResourceTypes: []string{enum.ResourceBatch.String()}})
+		require.NoError(err, "could not list audit logs")
+		require.Len(logs.Logs, 2, "expected a create and an update audit log")
+	})
+
+	s.Run("Empty", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		batch := &models.Batch{Format: "jsonl"}
+		require.NoError(s.store.CreateBatch(ctx, batch, &models.ComplianceAuditLog{}), "could not create empty batch")
+
+		cmp, err := s.store.RetrieveBatch(ctx, batch.ID)
+		require.NoError(err, "could not retrieve empty batch")
+		require.Equal(int64(0), cmp.Total)
+	})
+
+	s.Run("Errors", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.CreateBatch(ctx, &models.Batch{Model: models.Model{ID: ulid.MakeSecure()}, Format: "csv"}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNoIDOnCreate)
+
+		err = s.store.CreateBatch(ctx, &models.Batch{}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue)
+
+		err = s.store.CreateBatch(ctx, &models.Batch{Format: "csv", Items: []*models.BatchItem{{Status: enum.BatchItemSent}}}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue)
+
+		_, err = s.store.RetrieveBatch(context.Background(), ulid.MakeSecure())
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		err = s.store.UpdateBatch(ctx, &models.Batch{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		err = s.store.UpdateBatch(ctx, &models.Batch{}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingID)
+
+		err = s.store.UpdateBatchItem(ctx, &models.BatchItem{Model: models.Model{ID: ulid.MakeSecure()}})
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		err = s.store.UpdateBatchItem(ctx, &models.BatchItem{})
+		require.ErrorIs(err, errors.ErrMissingID)
+	})
+}
@@ -0,0 +1,316 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Transaction Notes
+//===========================================================================
+
+const listTransactionNotesSQL = "SELECT * FROM transaction_notes WHERE transaction_id=:transactionID ORDER BY created ASC"
+
+func (s *Store) ListTransactionNotes(ctx context.Context, transactionID uuid.UUID) (out []*models.TransactionNote, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListTransactionNotes(transactionID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists the notes of the transaction in the order they were posted so that the thread
+// reads from oldest to newest. Returns ErrNotFound if the transaction does not exist.
+func (t *Tx) ListTransactionNotes(transactionID uuid.UUID) (out []*models.TransactionNote, err error) {
+	if err = t.checkTransactionExists(transactionID); err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listTransactionNotesSQL, sql.Named("transactionID", transactionID)); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.TransactionNote, 0)
+	for rows.Next() {
+		note := &models.TransactionNote{}
+		if err = note.Scan(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, note)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const createTransactionNoteSQL = "INSERT INTO transaction_notes (id, transaction_id, body, author, author_id, author_type, created) VALUES (:id, :transactionID, :body, :author, :authorID, :authorType, :created)"
+
+func (s *Store) CreateTransactionNote(ctx context.Context, note *models.TransactionNote, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateTransactionNote(note, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Posts the note to the transaction; the author of the note is the actor of the
+// transaction. The author name should be set on the note by the caller, otherwise the
+// type of the actor is used as the name.
+func (t *Tx) CreateTransactionNote(note *models.TransactionNote, auditLog *models.ComplianceAuditLog) (err error) {
+	if !note.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	if strings.TrimSpace(note.Body) == "" {
+		return dberr.ErrMissingValue
+	}
+
+	if err = t.checkTransactionExists(note.TransactionID); err != nil {
+		return err
+	}
+
+	note.ID = ulid.MakeSecure()
+	note.AuthorID, note.AuthorType = t.GetActor()
+	note.Created = time.Now()
+
+	if note.Author == "" {
+		note.Author = note.AuthorType.String()
+	}
+
+	if _, err = t.tx.Exec(createTransactionNoteSQL, note.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       note.ID.Bytes(),
+		ResourceType:     enum.ResourceTransactionNote,
+		ResourceModified: note.Created,
+		Action:           enum.ActionCreate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	})
+}
+
+//===========================================================================
+// Transaction Assignment
+//===========================================================================
+
+const (
+	assigneeNameSQL      = "SELECT name FROM users WHERE id=:id"
+	assignTransactionSQL = "UPDATE transactions SET assignee_id=:assigneeID, modified=:modified WHERE id=:id"
+)
+
+func (s *Store) AssignTransaction(ctx context.Context, transactionID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.AssignTransaction(transactionID, assigneeID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Assigns the transaction to the specified user, or unassigns the transaction if the
+// assignee is null. Returns ErrUnknownAssignee if the user does not exist.
+func (t *Tx) AssignTransaction(transactionID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) (err error) {
+	if assigneeID.Valid {
+		var name sql.NullString
+		if err = t.tx.QueryRow(assigneeNameSQL, sql.Named("id", assigneeID.ULID)).Scan(&name); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return dberr.ErrUnknownAssignee
+			}
+			return dbe(err)
+		}
+	}
+
+	modified := time.Now()
+	params := []any{
+		sql.Named("id", transactionID),
+		sql.Named("assigneeID", assigneeID),
+		sql.Named("modified", modified),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(assignTransactionSQL, params...); err != nil {
+		return dbe(err)
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return t.caseAuditLog(transactionID, modified, "AssignTransaction", auditLog)
+}
+
+//===========================================================================
+// Transaction Tags
+//===========================================================================
+
+const listTransactionTagsSQL = "SELECT DISTINCT tag FROM transaction_tags ORDER BY tag ASC"
+
+func (s *Store) ListTransactionTags(ctx context.Context) (out []string, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListTransactionTags(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists every tag that is applied to at least one transaction, e.g. to populate the
+// tag filter of the transactions list.
+func (t *Tx) ListTransactionTags() (out []string, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listTransactionTagsSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]string, 0)
+	for rows.Next() {
+		var tag string
+		if err = rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		out = append(out, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const (
+	deleteTransactionTagsSQL = "DELETE FROM transaction_tags WHERE transaction_id=:transactionID"
+	createTransactionTagSQL  = "INSERT INTO transaction_tags (transaction_id, tag, created) VALUES (:transactionID, :tag, :created)"
+	touchTransactionSQL      = "UPDATE transactions SET modified=:modified WHERE id=:id"
+)
+
+func (s *Store) SetTransactionTags(ctx context.Context, transactionID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.SetTransactionTags(transactionID, tags, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Replaces the tags of the transaction with the specified tags, which are normalized
+// before they are stored; an empty list removes all of the tags from the transaction.
+func (t *Tx) SetTransactionTags(transactionID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) (err error) {
+	modified := time.Now()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(touchTransactionSQL, sql.Named("id", transactionID), sql.Named("modified", modified)); err != nil {
+		return dbe(err)
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	if _, err = t.tx.Exec(deleteTransactionTagsSQL, sql.Named("transactionID", transactionID)); err != nil {
+		return dbe(err)
+	}
+
+	for _, tag := range models.NormalizeTags(tags) {
+		params := []any{
+			sql.Named("transactionID", transactionID),
+			sql.Named("tag", tag),
+			sql.Named("created", modified),
+		}
+
+		if _, err = t.tx.Exec(createTransactionTagSQL, params...); err != nil {
+			return dbe(err)
+		}
+	}
+
+	return t.caseAuditLog(transactionID, modified, "SetTransactionTags", auditLog)
+}
+
+//===========================================================================
+// Helpers
+//===========================================================================
+
+const transactionIDExistsSQL = "SELECT EXISTS (SELECT 1 FROM transactions WHERE id=:id)"
+
+// Returns ErrNotFound if the transaction does not exist.
+func (t *Tx) checkTransactionExists(transactionID uuid.UUID) (err error) {
+	var exists bool
+	if err = t.tx.QueryRow(transactionIDExistsSQL, sql.Named("id", transactionID)).Scan(&exists); err != nil {
+		return dbe(err)
+	}
+
+	if !exists {
+		return dberr.ErrNotFound
+	}
+	return nil
+}
+
+// Creates an update audit log for the transaction with the case management operation
+// in the change notes so we know what kind of update it was.
+func (t *Tx) caseAuditLog(transactionID uuid.UUID, modified time.Time, operation string, auditLog *models.ComplianceAuditLog) error {
+	notes := sql.NullString{Valid: true, String: operation}
+	if auditLog.ChangeNotes.Valid {
+		notes.String = auditLog.ChangeNotes.String + "-" + notes.String
+	}
+
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       transactionID[:],
+		ResourceType:     enum.ResourceTransaction,
+		ResourceModified: modified,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      notes,
+	})
+}
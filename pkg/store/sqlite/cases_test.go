@@ -0,0 +1,203 @@
+package sqlite_test
+
+import (
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestTransactionNotes() {
+	s.Run("CreateAndList", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+
+		notes, err := s.store.ListTransactionNotes(ctx, txID)
+		require.NoError(err, "could not list transaction notes")
+		require.Len(notes, 0)
+
+		first := &models.TransactionNote{TransactionID: txID, Body: "requested source of funds", Author: "Compliance User"}
+		err = s.store.CreateTransactionNote(ctx, first, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create transaction note")
+		require.False(first.ID.IsZero(), "expected an id to be assigned")
+		require.NotEmpty(first.AuthorID, "expected the actor id to be assigned")
+		require.Equal(enum.ActorAPIKey, first.AuthorType)
+
+		second := &models.TransactionNote{TransactionID: txID, Body: "source of funds verified"}
+		err = s.store.CreateTransactionNote(ctx, second, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create transaction note")
+		require.Equal(enum.ActorAPIKey.String(), second.Author, "expected author to default to the actor type")
+
+		notes, err = s.store.ListTransactionNotes(ctx, txID)
+		require.NoError(err, "could not list transaction notes")
+		require.Len(notes, 2)
+		require.Equal(first.ID, notes[0].ID, "expected notes to be ordered oldest first")
+		require.Equal("Compliance User", notes[0].Author)
+		require.Equal(second.ID, notes[1].ID)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceTransactionNote): 2,
+		})
+	})
+
+	s.Run("Immutable", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		note := &models.TransactionNote{TransactionID: uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3"), Body: "original"}
+		err := s.store.CreateTransactionNote(ctx, note, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create transaction note")
+
+		tx, err := s.store.BeginTx(ctx, nil)
+		require.NoError(err)
+		defer tx.Rollback()
+
+		_, err = tx.Exec("UPDATE transaction_notes SET body='modified' WHERE id=$1", note.ID)
+		require.ErrorContains(err, "transaction notes cannot be modified")
+	})
+
+	s.Run("Errors", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		_, err := s.store.ListTransactionNotes(ctx, uuid.New())
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		err = s.store.CreateTransactionNote(ctx, &models.TransactionNote{TransactionID: uuid.New(), Body: "note"}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		err = s.store.CreateTransactionNote(ctx, &models.TransactionNote{TransactionID: uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3"), Body: "  "}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue)
+
+		err = s.store.CreateTransactionNote(ctx, &models.TransactionNote{ID: ulid.MakeSecure(), TransactionID: uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3"), Body: "note"}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNoIDOnCreate)
+	})
+}
+
+func (s *storeTestSuite) TestAssignTransaction() {
+	require := s.Require()
+	ctx := s.ActorContext()
+	txID := s.createCaseTransaction()
+	userID := ulid.MustParse("01HWQE347SRM7CBRSYM7QJ3M83")
+
+	err := s.store.AssignTransaction(ctx, txID, ulid.NullULID{ULID: userID, Valid: true}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not assign transaction")
+
+	txn, err := s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+	require.Equal(userID, txn.AssigneeID().ULID)
+	require.Equal("Compliance User", txn.Assignee())
+
+	// The assignment should not be modified by updating the transaction
+	txn.Amount = 42
+	err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update transaction")
+
+	page, err := s.store.ListTransactions(ctx, &models.TransactionPageInfo{})
+	require.NoError(err, "could not list transactions")
+	for _, txn := range page.Transactions {
+		if txn.ID == txID {
+			require.Equal(userID, txn.AssigneeID().ULID)
+			require.Equal("Compliance User", txn.Assignee())
+		} else {
+			require.False(txn.AssigneeID().Valid)
+		}
+	}
+
+	err = s.store.AssignTransaction(ctx, txID, ulid.NullULID{}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not unassign transaction")
+
+	txn, err = s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+	require.False(txn.AssigneeID().Valid)
+	require.Empty(txn.Assignee())
+
+	err = s.store.AssignTransaction(ctx, txID, ulid.NullULID{ULID: ulid.MakeSecure(), Valid: true}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrUnknownAssignee)
+
+	err = s.store.AssignTransaction(ctx, uuid.New(), ulid.NullULID{ULID: userID, Valid: true}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionCreate, enum.ResourceTransaction): 1,
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceTransaction): 3,
+	})
+}
+
+func (s *storeTestSuite) TestTransactionTags() {
+	require := s.Require()
+	ctx := s.ActorContext()
+	first := s.createCaseTransaction()
+	second := uuid.MustParse("b04dc71c-7214-46a5-a514-381ef0bcc494")
+
+	tags, err := s.store.ListTransactionTags(ctx)
+	require.NoError(err, "could not list transaction tags")
+	require.Len(tags, 0)
+
+	err = s.store.SetTransactionTags(ctx, first, []string{"Sanctions", "review", " review "}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not set transaction tags")
+
+	err = s.store.SetTransactionTags(ctx, second, []string{"review"}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not set transaction tags")
+
+	txn, err := s.store.RetrieveTransaction(ctx, first)
+	require.NoError(err, "could not retrieve transaction")
+	require.Equal([]string{"review", "sanctions"}, txn.Tags())
+
+	tags, err = s.store.ListTransactionTags(ctx)
+	require.NoError(err, "could not list transaction tags")
+	require.Equal([]string{"review", "sanctions"}, tags)
+
+	// Filter transactions by tag
+	page, err := s.store.ListTransactions(ctx, &models.TransactionPageInfo{Tags: []string{"SANCTIONS"}})
+	require.NoError(err, "could not list transactions")
+	require.Len(page.Transactions, 1)
+	require.Equal(first, page.Transactions[0].ID)
+	require.Equal([]string{"review", "sanctions"}, page.Transactions[0].Tags())
+
+	page, err = s.store.ListTransactions(ctx, &models.TransactionPageInfo{Tags: []string{"review", "sanctions"}})
+	require.NoError(err, "could not list transactions")
+	require.Len(page.Transactions, 2)
+
+	page, err = s.store.ListTransactions(ctx, &models.TransactionPageInfo{Tags: []string{"unknown"}})
+	require.NoError(err, "could not list transactions")
+	require.Len(page.Transactions, 0)
+
+	// Replace and remove tags
+	err = s.store.SetTransactionTags(ctx, first, []string{"cleared"}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not set transaction tags")
+
+	err = s.store.SetTransactionTags(ctx, second, nil, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not set transaction tags")
+
+	tags, err = s.store.ListTransactionTags(ctx)
+	require.NoError(err, "could not list transaction tags")
+	require.Equal([]string{"cleared"}, tags)
+
+	err = s.store.SetTransactionTags(ctx, uuid.New(), []string{"review"}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionCreate, enum.ResourceTransaction): 1,
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceTransaction): 4,
+	})
+}
+
+// Creates a transaction without secure envelopes so that it can be retrieved.
+func (s *storeTestSuite) createCaseTransaction() uuid.UUID {
+	txn := &models.Transaction{
+		Source:       enum.SourceLocal,
+		Status:       enum.StatusDraft,
+		Counterparty: "Example VASP",
+		VirtualAsset: "BTC",
+		Amount:       0.25,
+	}
+
+	err := s.store.CreateTransaction(s.ActorContext(), txn, &models.ComplianceAuditLog{})
+	s.Require().NoError(err, "could not create transaction")
+	return txn.ID
+}
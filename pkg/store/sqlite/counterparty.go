@@ -115,7 +115,7 @@ func (t *Tx) ListCounterpartySourceInfo(source enum.Source) (out []*models.Count
 	return out, nil
 }
 
-const createCounterpartySQL = "INSERT INTO counterparties (id, source, directory_id, registered_directory, protocol, common_name, endpoint, name, website, country, business_category, vasp_categories, verified_on, ivms101, lei, created, modified) VALUES (:id, :source, :directoryID, :registeredDirectory, :protocol, :commonName, :endpoint, :name, :website, :country, :businessCategory, :vaspCategories, :verifiedOn, :ivms101, :lei, :created, :modified)"
+const createCounterpartySQL = "INSERT INTO counterparties (id, source, directory_id, registered_directory, protocol, common_name, endpoint, name, website, country, business_category, vasp_categories, verified_on, ivms101, lei, created, modified, pending_language, pending_message, reply_not_before, reply_not_after) VALUES (:id, :source, :directoryID, :registeredDirectory, :protocol, :commonName, :endpoint, :name, :website, :country, :businessCategory, :vaspCategories, :verifiedOn, :ivms101, :lei, :created, :modified, :pendingLanguage, :pendingMessage, :replyNotBefore, :replyNotAfter)"
 
 func (s *Store) CreateCounterparty(ctx context.Context, counterparty *models.Counterparty, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
@@ -305,6 +305,63 @@ func (t *Tx) UpdateCounterparty(counterparty *models.Counterparty, auditLog *mod
 	return nil
 }
 
+const updateCounterpartyPendingSQL = "UPDATE counterparties SET pending_language=:pendingLanguage, pending_message=:pendingMessage, reply_not_before=:replyNotBefore, reply_not_after=:replyNotAfter, modified=:modified WHERE id=:id"
+
+// UpdateCounterpartyPending replaces the pending response overrides of the
+// counterparty without modifying any of the other counterparty fields.
+func (s *Store) UpdateCounterpartyPending(ctx context.Context, counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UpdateCounterpartyPending(counterpartyID, overrides, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) UpdateCounterpartyPending(counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) (err error) {
+	if counterpartyID.IsZero() {
+		return dberr.ErrMissingID
+	}
+
+	modified := time.Now()
+	params := []any{
+		sql.Named("id", counterpartyID),
+		sql.Named("pendingLanguage", overrides.Language),
+		sql.Named("pendingMessage", overrides.Message),
+		sql.Named("replyNotBefore", overrides.ReplyNotBefore),
+		sql.Named("replyNotAfter", overrides.ReplyNotAfter),
+		sql.Named("modified", modified),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(updateCounterpartyPendingSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	// Fill the audit log and create it
+	actorID, actorType := t.GetActor()
+	if err := t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       counterpartyID.Bytes(),
+		ResourceType:     enum.ResourceCounterparty,
+		ResourceModified: modified,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 const deleteCounterpartySQL = "DELETE FROM counterparties WHERE id=:id"
 
 func (s *Store) DeleteCounterparty(ctx context.Context, counterpartyID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
@@ -322,6 +379,14 @@ func (s *Store) DeleteCounterparty(ctx context.Context, counterpartyID ulid.ULID
 }
 
 func (t *Tx) DeleteCounterparty(counterpartyID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	// Counterparties under legal hold cannot be deleted
+	var held bool
+	if held, err = t.resourceOnHold(enum.ResourceCounterparty, counterpartyID); err != nil {
+		return err
+	} else if held {
+		return dberr.ErrLegalHold
+	}
+
 	var result sql.Result
 	if result, err = t.tx.Exec(deleteCounterpartySQL, sql.Named("id", counterpartyID)); err != nil {
 		return dbe(err)
@@ -346,7 +411,11 @@ func (t *Tx) DeleteCounterparty(counterpartyID ulid.ULID, auditLog *models.Compl
 	return nil
 }
 
-const listContactsSQL = "SELECT * FROM contacts WHERE counterparty_id=:counterpartyID"
+// Selects the contact columns in the order of the model's Scan method, omitting the
+// blind index of the email address.
+const contactColumns = "id, name, email, role, counterparty_id, created, modified"
+
+const listContactsSQL = "SELECT " + contactColumns + " FROM contacts WHERE counterparty_id=:counterpartyID"
 
 // List contacts associated with the specified counterparty. The counterparty can either
 // be a ULID of the counterparty ID or a pointer to the Counterparty model. If the
@@ -408,7 +477,7 @@ func (t *Tx) ListContacts(counterparty any, page *models.PageInfo) (out *models.
 
 	for rows.Next() {
 		contact := &models.Contact{}
-		if err = contact.Scan(rows); err != nil {
+		if err = contact.Scan(t.decrypt(rows, encryptedContactFields(contact)...)); err != nil {
 			return nil, err
 		}
 
@@ -439,7 +508,7 @@ func (t *Tx) listCounterpartyContacts(counterparty *models.Counterparty) (err er
 	contacts := make([]*models.Contact, 0)
 	for rows.Next() {
 		contact := &models.Contact{}
-		if err = contact.Scan(rows); err != nil {
+		if err = contact.Scan(t.decrypt(rows, encryptedContactFields(contact)...)); err != nil {
 			return err
 		}
 
@@ -451,7 +520,7 @@ func (t *Tx) listCounterpartyContacts(counterparty *models.Counterparty) (err er
 	return nil
 }
 
-const createContactSQL = "INSERT INTO contacts (id, name, email, role, counterparty_id, created, modified) VALUES (:id, :name, :email, :role, :counterpartyID, :created, :modified)"
+const createContactSQL = "INSERT INTO contacts (id, name, email, role, counterparty_id, created, modified, email_idx) VALUES (:id, :name, :email, :role, :counterpartyID, :created, :modified, :emailIdx)"
 
 func (s *Store) CreateContact(ctx context.Context, contact *models.Contact, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
@@ -481,7 +550,12 @@ func (t *Tx) CreateContact(contact *models.Contact, auditLog *models.ComplianceA
 	contact.Created = time.Now()
 	contact.Modified = contact.Created
 
-	if _, err = t.tx.Exec(createContactSQL, contact.Params()...); err != nil {
+	var params []any
+	if params, err = t.contactParams(contact); err != nil {
+		return err
+	}
+
+	if _, err = t.tx.Exec(createContactSQL, params...); err != nil {
 		return dbe(err)
 	}
 
@@ -502,7 +576,7 @@ func (t *Tx) CreateContact(contact *models.Contact, auditLog *models.ComplianceA
 	return nil
 }
 
-const retrieveContactSQL = "SELECT * FROM contacts WHERE id=:id and counterparty_id=:counterpartyID"
+const retrieveContactSQL = "SELECT " + contactColumns + " FROM contacts WHERE id=:id and counterparty_id=:counterpartyID"
 
 // Retrieve the contact with the specified ID and associate it with the
 // specified counterparty. The counterparty can either be the ULID of the counterparty
@@ -553,7 +627,7 @@ func (t *Tx) RetrieveContact(contactID, counterparty any) (contact *models.Conta
 
 	// Retrieve the contact
 	contact = &models.Contact{}
-	if err = contact.Scan(t.tx.QueryRow(retrieveContactSQL, sql.Named("id", contactID), sql.Named("counterpartyID", counterpartyID))); err != nil {
+	if err = contact.Scan(t.decrypt(t.tx.QueryRow(retrieveContactSQL, sql.Named("id", contactID), sql.Named("counterpartyID", counterpartyID)), encryptedContactFields(contact)...)); err != nil {
 		return nil, dbe(err)
 	}
 
@@ -563,7 +637,7 @@ func (t *Tx) RetrieveContact(contactID, counterparty any) (contact *models.Conta
 }
 
 // TODO: this must be an upsert/delete since the data is being modified on the relation
-const updateContactSQL = "UPDATE contacts SET name=:name, email=:email, role=:role, modified=:modified WHERE id=:id AND counterparty_id=:counterpartyID"
+const updateContactSQL = "UPDATE contacts SET name=:name, email=:email, email_idx=:emailIdx, role=:role, modified=:modified WHERE id=:id AND counterparty_id=:counterpartyID"
 
 func (s *Store) UpdateContact(ctx context.Context, contact *models.Contact, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
@@ -592,9 +666,14 @@ func (t *Tx) UpdateContact(contact *models.Contact, auditLog *models.ComplianceA
 	// Update modified timestamp (in place).
 	contact.Modified = time.Now()
 
-	// Execute the update into the database
+	// Execute the update into the database with the name and email encrypted
+	var params []any
+	if params, err = t.contactParams(contact); err != nil {
+		return err
+	}
+
 	var result sql.Result
-	if result, err = t.tx.Exec(updateContactSQL, contact.Params()...); err != nil {
+	if result, err = t.tx.Exec(updateContactSQL, params...); err != nil {
 		return dbe(err)
 	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
 		return dberr.ErrNotFound
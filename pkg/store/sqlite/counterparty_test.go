@@ -1,7 +1,9 @@
 package sqlite_test
 
 import (
+	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/trisacrypto/envoy/pkg/enum"
@@ -371,6 +373,63 @@ func (s *storeTestSuite) TestUpdateCounterparty_FailureNotFound() {
 	require.True(ok, "audit log count was off")
 }
 
+func (s *storeTestSuite) TestUpdateCounterpartyPending() {
+	require := s.Require()
+	ctx := s.ActorContext()
+	counterpartyId := ulid.MustParse("01JXTQCDE6ZES5MPXNW7K19QVQ")
+
+	original, err := s.store.RetrieveCounterparty(ctx, counterpartyId)
+	require.NoError(err, "could not retrieve counterparty")
+	require.True(original.Pending.IsZero(), "expected no pending overrides on fixture")
+
+	overrides := &models.PendingOverrides{
+		Language:      sql.NullString{Valid: true, String: "de"},
+		Message:       sql.NullString{Valid: true, String: "{{ .Organization }} prüft Ihre Anfrage"},
+		ReplyNotAfter: sql.NullInt64{Valid: true, Int64: 7200},
+	}
+
+	err = s.store.UpdateCounterpartyPending(ctx, counterpartyId, overrides, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update pending overrides")
+
+	counterparty, err := s.store.RetrieveCounterparty(ctx, counterpartyId)
+	require.NoError(err, "could not retrieve counterparty")
+	require.Equal(*overrides, counterparty.Pending, "expected pending overrides to be stored")
+	require.Equal(original.Name, counterparty.Name, "expected other fields to be unmodified")
+	require.True(original.Modified.Before(counterparty.Modified), "expected the modified time to be newer")
+
+	notBefore, notAfter := counterparty.Pending.ReplyWindows(5*time.Minute, 24*time.Hour)
+	require.Equal(5*time.Minute, notBefore)
+	require.Equal(2*time.Hour, notAfter)
+
+	// Overrides must not be cleared when the counterparty is updated (e.g. by sync)
+	counterparty.Pending = models.PendingOverrides{}
+	err = s.store.UpdateCounterparty(ctx, counterparty, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update counterparty")
+
+	counterparty, err = s.store.RetrieveCounterparty(ctx, counterpartyId)
+	require.NoError(err, "could not retrieve counterparty")
+	require.Equal(*overrides, counterparty.Pending, "expected pending overrides to be unmodified")
+
+	// Clear the overrides
+	err = s.store.UpdateCounterpartyPending(ctx, counterpartyId, &models.PendingOverrides{}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not clear pending overrides")
+
+	counterparty, err = s.store.RetrieveCounterparty(ctx, counterpartyId)
+	require.NoError(err, "could not retrieve counterparty")
+	require.True(counterparty.Pending.IsZero(), "expected pending overrides to be cleared")
+
+	ok := s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceCounterparty): 3,
+	})
+	require.True(ok, "audit log count was off")
+
+	err = s.store.UpdateCounterpartyPending(ctx, ulid.Zero, overrides, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrMissingID)
+
+	err = s.store.UpdateCounterpartyPending(ctx, ulid.MakeSecure(), overrides, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+}
+
 func (s *storeTestSuite) TestDeleteCounterparty_Success() {
 	//setup
 	require := s.Require()
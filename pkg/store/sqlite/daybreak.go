@@ -209,6 +209,14 @@ func (s *Store) DeleteDaybreak(ctx context.Context, counterpartyID ulid.ULID, ig
 }
 
 func (t *Tx) DeleteDaybreak(counterpartyID ulid.ULID, ignoreTxns bool, auditLog *models.ComplianceAuditLog) (err error) {
+	// Counterparties under legal hold cannot be deleted
+	var held bool
+	if held, err = t.resourceOnHold(enum.ResourceCounterparty, counterpartyID); err != nil {
+		return err
+	} else if held {
+		return dberr.ErrLegalHold
+	}
+
 	if ignoreTxns {
 		if err = t.deleteDaybreakCounterparty(counterpartyID, auditLog); err != nil {
 			log.Warn().Str("counterparty_id", counterpartyID.String()).Msg("error when deleting daybreak counterparty")
@@ -305,7 +313,7 @@ func (t *Tx) listMapCounterpartyContactsByEmail(counterpartyID ulid.ULID) (conta
 	contacts = make(map[string]*models.Contact)
 	for rows.Next() {
 		contact := &models.Contact{}
-		if err = contact.Scan(rows); err != nil {
+		if err = contact.Scan(t.decrypt(rows, encryptedContactFields(contact)...)); err != nil {
 			return nil, err
 		}
 
@@ -0,0 +1,163 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+//===========================================================================
+// Reply Deadlines
+//===========================================================================
+
+const listReplyDeadlinesSQL = "SELECT t.id, t.source, t.status, t.counterparty, t.counterparty_id, t.originator, t.originator_address, t.beneficiary, t.beneficiary_address, t.virtual_asset, t.amount, t.archived, t.archived_on, t.last_update, t.created, t.modified, t.reply_not_after, t.reply_reminded_on, t.reply_expired_on, t.travel_rule_required, t.fiat_amount, t.fiat_currency, t.threshold_rule_id, t.assignee_id, u.name AS assignee, " + transactionTagsColumn + " FROM transactions t LEFT JOIN users u ON t.assignee_id=u.id WHERE t.archived=0 AND t.reply_expired_on IS NULL AND t.reply_not_after IS NOT NULL AND datetime(t.reply_not_after) <= datetime(:dueBefore) AND t.status IN ('pending', 'review', 'repair') ORDER BY t.reply_not_after ASC"
+
+func (s *Store) ListReplyDeadlines(ctx context.Context, dueBefore time.Time) (out []*models.Transaction, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListReplyDeadlines(dueBefore); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists the transactions that are awaiting a reply whose deadline is before the
+// specified time and that have not already expired, ordered by the earliest deadline.
+// Secure envelopes are not associated with the transactions that are returned.
+func (t *Tx) ListReplyDeadlines(dueBefore time.Time) (out []*models.Transaction, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listReplyDeadlinesSQL, sql.Named("dueBefore", dueBefore.UTC())); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.Transaction, 0)
+	for rows.Next() {
+		transaction := &models.Transaction{}
+		if err = transaction.Scan(t.decrypt(rows, encryptedTransactionFields(transaction)...)); err != nil {
+			return nil, err
+		}
+		out = append(out, transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const markReplyRemindedSQL = "UPDATE transactions SET reply_reminded_on=:now, modified=:now WHERE id=:id AND reply_not_after IS NOT NULL"
+
+func (s *Store) MarkReplyReminded(ctx context.Context, transactionID uuid.UUID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.MarkReplyReminded(transactionID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Records that a reminder was sent for the current reply deadline of the transaction
+// so that the reminder is not sent again. Returns ErrNotFound if the transaction does
+// not exist or does not have a reply deadline.
+func (t *Tx) MarkReplyReminded(transactionID uuid.UUID, auditLog *models.ComplianceAuditLog) (err error) {
+	return t.markReplyDeadline(markReplyRemindedSQL, transactionID, "MarkReplyReminded", auditLog)
+}
+
+const markReplyExpiredSQL = "UPDATE transactions SET reply_expired_on=:now, modified=:now WHERE id=:id AND reply_not_after IS NOT NULL"
+
+func (s *Store) MarkReplyExpired(ctx context.Context, transactionID uuid.UUID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.MarkReplyExpired(transactionID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Records that the reply deadline of the transaction has passed so that the
+// transaction is no longer returned when listing reply deadlines. Returns ErrNotFound
+// if the transaction does not exist or does not have a reply deadline.
+func (t *Tx) MarkReplyExpired(transactionID uuid.UUID, auditLog *models.ComplianceAuditLog) (err error) {
+	return t.markReplyDeadline(markReplyExpiredSQL, transactionID, "MarkReplyExpired", auditLog)
+}
+
+func (t *Tx) markReplyDeadline(query string, transactionID uuid.UUID, operation string, auditLog *models.ComplianceAuditLog) (err error) {
+	now := time.Now().UTC()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(query, sql.Named("id", transactionID), sql.Named("now", now)); err != nil {
+		return dbe(err)
+	}
+
+	if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return t.caseAuditLog(transactionID, now, operation, auditLog)
+}
+
+const listTransactionReviewersSQL = "SELECT id, name, email, role_id, last_login, created, modified, mfa_enrolled, login_locked_until FROM users WHERE id IN (SELECT user_id FROM user_permissions WHERE permission='travelrule:manage') ORDER BY created ASC"
+
+func (s *Store) ListTransactionReviewers(ctx context.Context) (out []*models.User, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListTransactionReviewers(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists the users whose role permits them to manage transactions so that they can be
+// reminded about unassigned transactions that are awaiting a reply.
+func (t *Tx) ListTransactionReviewers() (out []*models.User, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listTransactionReviewersSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.User, 0)
+	for rows.Next() {
+		user := &models.User{}
+		if err = user.ScanSummary(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
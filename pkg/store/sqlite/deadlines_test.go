@@ -0,0 +1,142 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+func (s *storeTestSuite) TestReplyDeadlines() {
+	require := s.Require()
+	ctx := s.ActorContext()
+	txID := s.createCaseTransaction()
+
+	deadlines, err := s.store.ListReplyDeadlines(ctx, time.Now().Add(48*time.Hour))
+	require.NoError(err, "could not list reply deadlines")
+	require.Len(deadlines, 0, "expected no transactions to have reply deadlines")
+
+	// Set a reply deadline on the transaction
+	txn, err := s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+
+	deadline := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	txn.Status = enum.StatusReview
+	txn.ReplyNotAfter = sql.NullTime{Valid: true, Time: deadline}
+	err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update transaction")
+
+	deadlines, err = s.store.ListReplyDeadlines(ctx, time.Now().Add(time.Hour))
+	require.NoError(err, "could not list reply deadlines")
+	require.Len(deadlines, 0, "expected deadline to be outside of the window")
+
+	deadlines, err = s.store.ListReplyDeadlines(ctx, time.Now().Add(4*time.Hour))
+	require.NoError(err, "could not list reply deadlines")
+	require.Len(deadlines, 1)
+	require.Equal(txID, deadlines[0].ID)
+	require.True(deadline.Equal(deadlines[0].ReplyNotAfter.Time))
+	require.Equal(models.ReplyOnTime, deadlines[0].ReplyStatus(time.Now()))
+
+	// Send a reminder
+	err = s.store.MarkReplyReminded(ctx, txID, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not mark reply reminded")
+
+	txn, err = s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+	require.True(txn.ReplyRemindedOn().Valid)
+	require.Equal(models.ReplyDueSoon, txn.ReplyStatus(time.Now()))
+	require.Equal(models.ReplyOverdue, txn.ReplyStatus(deadline.Add(time.Minute)))
+
+	// Updating the transaction without changing the deadline keeps the reminder
+	txn.Amount = 42
+	err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update transaction")
+
+	txn, err = s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+	require.True(txn.ReplyRemindedOn().Valid, "expected reminder to be kept")
+
+	// Expire the deadline; expired transactions are no longer listed
+	err = s.store.MarkReplyExpired(ctx, txID, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not mark reply expired")
+
+	txn, err = s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+	require.True(txn.ReplyExpiredOn().Valid)
+	require.Equal(models.ReplyExpired, txn.ReplyStatus(time.Now()))
+
+	deadlines, err = s.store.ListReplyDeadlines(ctx, time.Now().Add(4*time.Hour))
+	require.NoError(err, "could not list reply deadlines")
+	require.Len(deadlines, 0, "expected expired transactions to be excluded")
+
+	// A new deadline resets the reminder and expiration of the previous deadline
+	txn.ReplyNotAfter = sql.NullTime{Valid: true, Time: deadline.Add(time.Hour)}
+	err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update transaction")
+
+	txn, err = s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+	require.False(txn.ReplyRemindedOn().Valid, "expected reminder to be reset")
+	require.False(txn.ReplyExpiredOn().Valid, "expected expiration to be reset")
+	require.Equal(models.ReplyOnTime, txn.ReplyStatus(time.Now()))
+
+	err = s.store.MarkReplyReminded(ctx, uuid.New(), &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.MarkReplyExpired(ctx, uuid.New(), &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionCreate, enum.ResourceTransaction): 1,
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceTransaction): 5,
+	})
+}
+
+func (s *storeTestSuite) TestOverdueTransactions() {
+	require := s.Require()
+	ctx := s.ActorContext()
+	txID := s.createCaseTransaction()
+
+	page, err := s.store.ListTransactions(ctx, &models.TransactionPageInfo{Overdue: true})
+	require.NoError(err, "could not list transactions")
+	require.Len(page.Transactions, 0)
+	require.True(page.Page.Overdue)
+
+	txn, err := s.store.RetrieveTransaction(ctx, txID)
+	require.NoError(err, "could not retrieve transaction")
+
+	txn.Status = enum.StatusPending
+	txn.ReplyNotAfter = sql.NullTime{Valid: true, Time: time.Now().Add(-1 * time.Hour)}
+	err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update transaction")
+
+	page, err = s.store.ListTransactions(ctx, &models.TransactionPageInfo{Overdue: true})
+	require.NoError(err, "could not list transactions")
+	require.Len(page.Transactions, 1)
+	require.Equal(txID, page.Transactions[0].ID)
+	require.Equal(models.ReplyOverdue, page.Transactions[0].ReplyStatus(time.Now()))
+
+	// Completed transactions are no longer overdue
+	txn.Status = enum.StatusCompleted
+	err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update transaction")
+
+	page, err = s.store.ListTransactions(ctx, &models.TransactionPageInfo{Overdue: true})
+	require.NoError(err, "could not list transactions")
+	require.Len(page.Transactions, 0)
+}
+
+func (s *storeTestSuite) TestListTransactionReviewers() {
+	require := s.Require()
+
+	reviewers, err := s.store.ListTransactionReviewers(s.ActorContext())
+	require.NoError(err, "could not list transaction reviewers")
+	require.NotEmpty(reviewers)
+
+	for _, reviewer := range reviewers {
+		require.NotEmpty(reviewer.Email)
+	}
+}
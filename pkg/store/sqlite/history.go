@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Transaction Status History
+//===========================================================================
+
+const listStatusHistorySQL = "SELECT h.id, h.transaction_id, h.from_status, h.to_status, h.actor_id, h.actor_type, h.envelope_id, h.created, COALESCE(u.name, k.description) AS actor FROM transaction_status_history h LEFT JOIN users u ON h.actor_id=u.id LEFT JOIN api_keys k ON h.actor_id=k.id WHERE h.transaction_id=:transactionID ORDER BY h.created ASC, h.id ASC"
+
+func (s *Store) ListStatusHistory(ctx context.Context, transactionID uuid.UUID) (out []*models.StatusTransition, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListStatusHistory(transactionID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists the status transitions of the transaction from oldest to newest so that the
+// history reads as a timeline. Returns ErrNotFound if the transaction does not exist.
+func (t *Tx) ListStatusHistory(transactionID uuid.UUID) (out []*models.StatusTransition, err error) {
+	if err = t.checkTransactionExists(transactionID); err != nil {
+		return nil, err
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listStatusHistorySQL, sql.Named("transactionID", transactionID)); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.StatusTransition, 0)
+	for rows.Next() {
+		transition := &models.StatusTransition{}
+		if err = transition.Scan(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, transition)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const createStatusTransitionSQL = "INSERT INTO transaction_status_history (id, transaction_id, from_status, to_status, actor_id, actor_type, envelope_id, created) VALUES (:id, :transactionID, :fromStatus, :toStatus, :actorID, :actorType, :envelopeID, :created)"
+
+// Validates the change from the original to the updated transaction and records the
+// change of status in the status history, if the status changed. The status must be
+// an allowed transition of the state machine and the transfer details of a completed
+// or rejected transaction cannot be changed. Returns nil if the status did not change.
+func (t *Tx) transition(orig, next *models.Transaction, envelopeID ulid.NullULID) (_ *models.StatusTransition, err error) {
+	if err = validateTransition(orig, next); err != nil {
+		return nil, err
+	}
+
+	if orig != nil && orig.Status == next.Status {
+		return nil, nil
+	}
+
+	transition := &models.StatusTransition{
+		ID:            ulid.MakeSecure(),
+		TransactionID: next.ID,
+		To:            next.Status,
+		EnvelopeID:    envelopeID,
+		Created:       time.Now(),
+	}
+
+	if orig != nil {
+		transition.From = orig.Status
+	}
+
+	transition.ActorID, transition.ActorType = t.GetActor()
+	if _, err = t.tx.Exec(createStatusTransitionSQL, transition.Params()...); err != nil {
+		return nil, dbe(err)
+	}
+	return transition, nil
+}
+
+const linkStatusTransitionSQL = "UPDATE transaction_status_history SET envelope_id=:envelopeID WHERE id=:id"
+
+// Associates the secure envelope that caused the status change with the transition.
+func (t *Tx) linkTransition(transitionID, envelopeID ulid.ULID) (err error) {
+	if _, err = t.tx.Exec(linkStatusTransitionSQL, sql.Named("envelopeID", envelopeID), sql.Named("id", transitionID)); err != nil {
+		return dbe(err)
+	}
+	return nil
+}
+
+// Checks the state machine of the status and ensures that the transfer details of a
+// completed or rejected transaction are not modified. A nil original transaction is
+// being created and may have any status other than unspecified.
+func validateTransition(orig, next *models.Transaction) error {
+	if orig == nil {
+		if next.Status == enum.StatusUnspecified {
+			return fmt.Errorf("%w: transactions cannot be created with an %s status", dberr.ErrInvalidTransition, next.Status)
+		}
+		return nil
+	}
+
+	if !orig.Status.CanTransition(next.Status) {
+		if orig.Status.Final() {
+			return fmt.Errorf("%w: transaction is %s", dberr.ErrTransactionFinal, orig.Status)
+		}
+		return fmt.Errorf("%w: cannot change status from %s to %s", dberr.ErrInvalidTransition, orig.Status, next.Status)
+	}
+
+	if orig.Status.Final() && !sameTransferDetails(orig, next) {
+		return fmt.Errorf("%w: transaction is %s", dberr.ErrTransactionFinal, orig.Status)
+	}
+	return nil
+}
+
+// Returns true if the counterparty, the originator and beneficiary, and the amount of
+// virtual asset transferred are the same on both transactions.
+func sameTransferDetails(a, b *models.Transaction) bool {
+	return a.Counterparty == b.Counterparty &&
+		a.CounterpartyID == b.CounterpartyID &&
+		a.Originator == b.Originator &&
+		a.OriginatorAddress == b.OriginatorAddress &&
+		a.Beneficiary == b.Beneficiary &&
+		a.BeneficiaryAddress == b.BeneficiaryAddress &&
+		a.VirtualAsset == b.VirtualAsset &&
+		a.Amount == b.Amount
+}
@@ -0,0 +1,190 @@
+package sqlite_test
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+)
+
+func (s *storeTestSuite) TestStatusHistory() {
+	s.Run("Transitions", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		for _, status := range []enum.Status{enum.StatusPending, enum.StatusReview, enum.StatusReview, enum.StatusAccepted, enum.StatusCompleted} {
+			txn, err := s.store.RetrieveTransaction(ctx, txID)
+			require.NoError(err, "could not retrieve transaction")
+
+			txn.Status = status
+			err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+			require.NoError(err, "could not transition transaction to %s", status)
+		}
+
+		history, err := s.store.ListStatusHistory(ctx, txID)
+		require.NoError(err, "could not list status history")
+		require.Len(history, 5, "expected no history when the status does not change")
+
+		expected := [][2]enum.Status{
+			{enum.StatusUnspecified, enum.StatusDraft},
+			{enum.StatusDraft, enum.StatusPending},
+			{enum.StatusPending, enum.StatusReview},
+			{enum.StatusReview, enum.StatusAccepted},
+			{enum.StatusAccepted, enum.StatusCompleted},
+		}
+
+		for i, transition := range history {
+			require.Equal(txID, transition.TransactionID)
+			require.Equal(expected[i][0], transition.From, "unexpected from status of transition %d", i)
+			require.Equal(expected[i][1], transition.To, "unexpected to status of transition %d", i)
+			require.Equal(enum.ActorAPIKey, transition.ActorType)
+			require.NotEmpty(transition.ActorID)
+			require.False(transition.EnvelopeID.Valid, "expected no envelope for updates from the api")
+		}
+	})
+
+	s.Run("InvalidTransition", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		txn, err := s.store.RetrieveTransaction(ctx, txID)
+		require.NoError(err, "could not retrieve transaction")
+
+		txn.Status = enum.StatusAccepted
+		require.NoError(s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{}))
+
+		txn.Status = enum.StatusPending
+		err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrInvalidTransition)
+
+		txn.Status = enum.StatusUnspecified
+		err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrInvalidTransition)
+
+		_, status, err := s.store.TransactionState(ctx, txID)
+		require.NoError(err)
+		require.Equal(enum.StatusAccepted, status, "expected the status to be unchanged")
+
+		err = s.store.CreateTransaction(ctx, &models.Transaction{Source: enum.SourceLocal, Counterparty: "Example VASP", VirtualAsset: "BTC", Amount: 1}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrInvalidTransition)
+	})
+
+	s.Run("Final", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		txn, err := s.store.RetrieveTransaction(ctx, txID)
+		require.NoError(err, "could not retrieve transaction")
+
+		txn.Status = enum.StatusRejected
+		require.NoError(s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{}))
+
+		// The status of a rejected transaction cannot be changed
+		txn.Status = enum.StatusRepair
+		err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrTransactionFinal)
+
+		// The transfer details of a rejected transaction cannot be changed
+		txn.Status = enum.StatusRejected
+		txn.Amount = 42.0
+		err = s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrTransactionFinal)
+
+		// Other metadata of a rejected transaction can still be updated
+		txn.Amount = 0.25
+		txn.LastUpdate = sql.NullTime{Valid: true, Time: txn.Modified}
+		require.NoError(s.store.UpdateTransaction(ctx, txn, &models.ComplianceAuditLog{}))
+	})
+
+	s.Run("PreparedTransaction", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		envelopeID := uuid.New()
+		payload, err := loadPayload("testdata/identity.pb.json", "testdata/transaction.pb.json")
+		require.NoError(err, "could not create payload for secure envelope")
+
+		incoming, err := envelope.New(payload, envelope.WithEnvelopeID(envelopeID.String()))
+		require.NoError(err, "could not create envelope from payload")
+		incoming, _, err = incoming.Encrypt()
+		require.NoError(err, "cannot encrypt envelope")
+
+		outgoing, err := envelope.New(payload, envelope.WithEnvelopeID(envelopeID.String()))
+		require.NoError(err, "could not create envelope from payload")
+		outgoing, _, err = outgoing.Encrypt()
+		require.NoError(err, "cannot encrypt envelope")
+
+		inTS, _ := incoming.Timestamp()
+		outTS, _ := outgoing.Timestamp()
+		require.False(inTS.Equal(outTS), "expected envelopes to have different timestamps")
+
+		db, err := s.store.PrepareTransaction(ctx, envelopeID, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not start prepared transaction")
+		defer db.Rollback()
+
+		// The outgoing envelope changes the status before it is stored, the incoming
+		// envelope changes the status after it is stored.
+		err = db.Update(&models.Transaction{Status: enum.StatusPending, LastUpdate: sql.NullTime{Valid: true, Time: outTS}}, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not update transaction")
+
+		inModel := models.FromEnvelope(incoming)
+		inModel.Direction = enum.DirectionIncoming
+		require.NoError(db.AddEnvelope(inModel, &models.ComplianceAuditLog{}))
+
+		err = db.Update(&models.Transaction{Status: enum.StatusAccepted, LastUpdate: sql.NullTime{Valid: true, Time: inTS}}, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not update transaction")
+
+		outModel := models.FromEnvelope(outgoing)
+		outModel.Direction = enum.DirectionOutgoing
+		require.NoError(db.AddEnvelope(outModel, &models.ComplianceAuditLog{}))
+
+		// An invalid transition fails the prepared transaction
+		err = db.Update(&models.Transaction{Status: enum.StatusReview}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrInvalidTransition)
+
+		require.NoError(db.Commit(), "could not commit transaction to database")
+
+		history, err := s.store.ListStatusHistory(ctx, envelopeID)
+		require.NoError(err, "could not list status history")
+		require.Len(history, 3)
+
+		require.Equal(enum.StatusDraft, history[0].To, "expected the creation of the transaction to be recorded")
+		require.Equal(inModel.ID, history[0].EnvelopeID.ULID, "expected creation to be linked to the first envelope stored")
+
+		require.Equal(enum.StatusPending, history[1].To)
+		require.Equal(outModel.ID, history[1].EnvelopeID.ULID, "expected transition to be linked to the outgoing envelope")
+
+		require.Equal(enum.StatusAccepted, history[2].To)
+		require.Equal(inModel.ID, history[2].EnvelopeID.ULID, "expected transition to be linked to the incoming envelope")
+	})
+
+	s.Run("Immutable", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		tx, err := s.store.BeginTx(ctx, nil)
+		require.NoError(err)
+		defer tx.Rollback()
+
+		_, err = tx.Exec("UPDATE transaction_status_history SET to_status='completed' WHERE transaction_id=$1", txID)
+		require.ErrorContains(err, "transaction status history cannot be modified")
+	})
+
+	s.Run("NotFound", func() {
+		require := s.Require()
+		_, err := s.store.ListStatusHistory(s.ActorContext(), uuid.New())
+		require.ErrorIs(err, errors.ErrNotFound)
+	})
+}
@@ -0,0 +1,83 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+//===========================================================================
+// Idempotency Keys
+//===========================================================================
+
+const (
+	retrieveIdempotencyKeySQL      = "SELECT * FROM idempotency_keys WHERE actor_id=:actorID AND key=:key AND expires > :now"
+	createIdempotencyKeySQL        = "INSERT INTO idempotency_keys (key, actor_id, request_hash, status, content_type, response, expires, created, transaction_id) VALUES (:key, :actorID, :requestHash, :status, :contentType, :response, :expires, :created, :transactionID)"
+	deleteExpiredIdempotencyKeySQL = "DELETE FROM idempotency_keys WHERE expires <= :now"
+)
+
+// Retrieve the stored response for the idempotency key of the specified actor; the
+// response is decrypted with the field cipher. If the key has expired or does not
+// exist, ErrNotFound is returned.
+func (s *Store) RetrieveIdempotencyKey(ctx context.Context, actorID []byte, key string) (out *models.IdempotencyKey, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	out = &models.IdempotencyKey{}
+	if err = out.Scan(tx.tx.QueryRow(retrieveIdempotencyKeySQL, sql.Named("actorID", actorID), sql.Named("key", key), sql.Named("now", time.Now()))); err != nil {
+		return nil, dbe(err)
+	}
+
+	if out.Response, err = tx.cipher.Decrypt(out.Response); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Store the response for an idempotency key, encrypting the response with the field
+// cipher since it may contain PII. Expired keys are deleted before the key is stored so
+// that the table does not grow without bound and so that an expired key can be reused.
+// If an unexpired key exists for the actor, ErrAlreadyExists is returned.
+func (s *Store) CreateIdempotencyKey(ctx context.Context, key *models.IdempotencyKey) (err error) {
+	if key.Key == "" || len(key.ActorID) == 0 || len(key.RequestHash) == 0 {
+		return dberr.ErrMissingValue
+	}
+
+	if key.Expires.IsZero() {
+		return dberr.ErrMissingTimestamp
+	}
+
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	key.Created = time.Now()
+	if _, err = tx.tx.Exec(deleteExpiredIdempotencyKeySQL, sql.Named("now", key.Created)); err != nil {
+		return dbe(err)
+	}
+
+	params := key.Params()
+	var encrypted []byte
+	if encrypted, err = tx.cipher.Encrypt(key.Response); err != nil {
+		return err
+	}
+	params[5] = sql.Named("response", encrypted)
+
+	if _, err = tx.tx.Exec(createIdempotencyKeySQL, params...); err != nil {
+		return dbe(err)
+	}
+
+	return tx.Commit()
+}
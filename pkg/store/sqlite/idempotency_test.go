@@ -0,0 +1,101 @@
+package sqlite_test
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+func (s *storeTestSuite) TestIdempotencyKeys() {
+	actorID := []byte("01HWR5VWW8V7ZFFVJVBEC7AV8A")
+
+	s.Run("Lifecycle", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		key := &models.IdempotencyKey{
+			Key:         "7d1c5e1b-1f7e-4a5b-9d3c-2f6b1a0e8c4d",
+			ActorID:     actorID,
+			RequestHash: []byte("hash"),
+			Status:      201,
+			ContentType: "application/json; charset=utf-8",
+			Response:    []byte(`{"id":"foo"}`),
+			Expires:     time.Now().Add(time.Hour),
+		}
+
+		require.NoError(s.store.CreateIdempotencyKey(ctx, key), "could not create idempotency key")
+		require.False(key.Created.IsZero(), "expected created timestamp to be set")
+
+		cmp, err := s.store.RetrieveIdempotencyKey(ctx, actorID, key.Key)
+		require.NoError(err, "could not retrieve idempotency key")
+		require.Equal(key.RequestHash, cmp.RequestHash)
+		require.Equal(201, cmp.Status)
+		require.Equal(key.ContentType, cmp.ContentType)
+		require.Equal(key.Response, cmp.Response)
+
+		// The response may contain PII so it is encrypted at rest
+		var raw []byte
+		err = s.queryRow("SELECT response FROM idempotency_keys WHERE key=?", key.Key).Scan(&raw)
+		require.NoError(err, "could not query raw response")
+		require.True(s.cipher.Current(raw), "expected the response to be encrypted")
+
+		// Keys are scoped to the actor that made the request
+		_, err = s.store.RetrieveIdempotencyKey(ctx, []byte("other"), key.Key)
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		// An unexpired key cannot be stored twice
+		err = s.store.CreateIdempotencyKey(ctx, &models.IdempotencyKey{Key: key.Key, ActorID: actorID, RequestHash: []byte("other"), Expires: time.Now().Add(time.Hour)})
+		require.ErrorIs(err, errors.ErrAlreadyExists)
+	})
+
+	s.Run("Expired", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		key := &models.IdempotencyKey{Key: "expired", ActorID: actorID, RequestHash: []byte("hash"), Status: 200, Expires: time.Now().Add(-time.Minute)}
+		require.NoError(s.store.CreateIdempotencyKey(ctx, key), "could not create idempotency key")
+
+		_, err := s.store.RetrieveIdempotencyKey(ctx, actorID, key.Key)
+		require.ErrorIs(err, errors.ErrNotFound, "expected expired key not to be returned")
+
+		// An expired key is deleted so that it can be reused
+		key.Expires = time.Now().Add(time.Hour)
+		require.NoError(s.store.CreateIdempotencyKey(ctx, key), "could not reuse expired idempotency key")
+
+		_, err = s.store.RetrieveIdempotencyKey(ctx, actorID, key.Key)
+		require.NoError(err, "expected reused key to be returned")
+	})
+
+	s.Run("Errors", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.CreateIdempotencyKey(ctx, &models.IdempotencyKey{ActorID: actorID, RequestHash: []byte("hash"), Expires: time.Now()})
+		require.ErrorIs(err, errors.ErrMissingValue)
+
+		err = s.store.CreateIdempotencyKey(ctx, &models.IdempotencyKey{Key: "foo", ActorID: actorID, RequestHash: []byte("hash")})
+		require.ErrorIs(err, errors.ErrMissingTimestamp)
+	})
+}
+
+// Creates an idempotency key whose response describes the specified transaction.
+func (s *storeTestSuite) createIdempotencyKey(txID uuid.UUID) *models.IdempotencyKey {
+	key := &models.IdempotencyKey{
+		Key:           txID.String(),
+		ActorID:       []byte("01HWR5VWW8V7ZFFVJVBEC7AV8A"),
+		RequestHash:   []byte("hash"),
+		Status:        200,
+		ContentType:   "application/json; charset=utf-8",
+		Response:      []byte(`{"originator":"Mary Tilcott"}`),
+		Expires:       time.Now().Add(time.Hour),
+		TransactionID: uuid.NullUUID{Valid: true, UUID: txID},
+	}
+
+	s.Require().NoError(s.store.CreateIdempotencyKey(s.ActorContext(), key), "could not create idempotency key")
+	return key
+}
@@ -0,0 +1,252 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// User Invites
+//===========================================================================
+
+const listUserInvitesSQL = "SELECT i.*, r.title FROM user_invites i JOIN roles r ON i.role_id=r.id ORDER BY i.created DESC"
+
+func (s *Store) ListUserInvites(ctx context.Context) (out []*models.UserInvite, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListUserInvites(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists the pending invites (including expired invites that can be resent) with the
+// title of the role that will be assigned to each invitee, most recent first.
+func (t *Tx) ListUserInvites() (out []*models.UserInvite, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listUserInvitesSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.UserInvite, 0)
+	for rows.Next() {
+		invite := &models.UserInvite{}
+		if err = invite.ScanWithRole(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, invite)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const (
+	createUserInviteSQL = "INSERT INTO user_invites (id, email, name, role_id, expiration, signature, sent_on, created, modified) VALUES (:id, :email, :name, :roleID, :expiration, :signature, :sentOn, :created, :modified)"
+	userEmailExistsSQL  = "SELECT EXISTS(SELECT 1 FROM users WHERE email=:email)"
+)
+
+func (s *Store) CreateUserInvite(ctx context.Context, invite *models.UserInvite, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateUserInvite(invite, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Creates an invite for a user; returns ErrAlreadyExists if a user with the email
+// already exists or if the email has already been invited (the invite should be
+// resent instead).
+func (t *Tx) CreateUserInvite(invite *models.UserInvite, auditLog *models.ComplianceAuditLog) (err error) {
+	if !invite.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	var exists bool
+	if err = t.tx.QueryRow(userEmailExistsSQL, sql.Named("email", invite.Email)).Scan(&exists); err != nil {
+		return dbe(err)
+	}
+
+	if exists {
+		return dberr.ErrAlreadyExists
+	}
+
+	invite.ID = ulid.MakeSecure()
+	invite.Created = time.Now()
+	invite.Modified = invite.Created
+
+	if _, err = t.tx.Exec(createUserInviteSQL, invite.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	return t.userInviteAuditLog(invite.ID, invite.Modified, enum.ActionCreate, auditLog)
+}
+
+const retrieveUserInviteSQL = "SELECT i.*, r.title FROM user_invites i JOIN roles r ON i.role_id=r.id WHERE i.id=:id"
+
+func (s *Store) RetrieveUserInvite(ctx context.Context, inviteID ulid.ULID) (invite *models.UserInvite, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if invite, err = tx.RetrieveUserInvite(inviteID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// Retrieves the invite with the title of the role that will be assigned to the invitee.
+func (t *Tx) RetrieveUserInvite(inviteID ulid.ULID) (invite *models.UserInvite, err error) {
+	invite = &models.UserInvite{}
+	if err = invite.ScanWithRole(t.tx.QueryRow(retrieveUserInviteSQL, sql.Named("id", inviteID))); err != nil {
+		return nil, dbe(err)
+	}
+	return invite, nil
+}
+
+const updateUserInviteSQL = "UPDATE user_invites SET expiration=:expiration, signature=:signature, sent_on=:sentOn, modified=:modified WHERE id=:id"
+
+func (s *Store) UpdateUserInvite(ctx context.Context, invite *models.UserInvite) (err error) {
+	//NOTE: sending an invite does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UpdateUserInvite(invite); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Updates the expiration, signature, and sent on timestamp of the invite when the
+// invite is sent or resent; the invitee, email, and role cannot be updated.
+func (t *Tx) UpdateUserInvite(invite *models.UserInvite) (err error) {
+	//NOTE: sending an invite does not require an audit log entry
+	if invite.ID.IsZero() {
+		return dberr.ErrMissingID
+	}
+
+	invite.Modified = time.Now()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(updateUserInviteSQL, invite.UpdateParams()...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+	return nil
+}
+
+const deleteUserInviteSQL = "DELETE FROM user_invites WHERE id=:id"
+
+func (s *Store) DeleteUserInvite(ctx context.Context, inviteID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.DeleteUserInvite(inviteID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Revokes the invite so that the invite link can no longer be used.
+func (t *Tx) DeleteUserInvite(inviteID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var result sql.Result
+	if result, err = t.tx.Exec(deleteUserInviteSQL, sql.Named("id", inviteID)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return t.userInviteAuditLog(inviteID, time.Now(), enum.ActionDelete, auditLog)
+}
+
+func (s *Store) AcceptUserInvite(ctx context.Context, inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.AcceptUserInvite(inviteID, user, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Creates the user from the invite and deletes the invite in a single transaction so
+// that an invite can only be accepted once. The email and role of the user are set
+// from the invite (as is the name if the user did not provide one); the caller is
+// responsible for verifying the invite link and setting the password of the user.
+func (t *Tx) AcceptUserInvite(inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) (err error) {
+	var invite *models.UserInvite
+	if invite, err = t.RetrieveUserInvite(inviteID); err != nil {
+		return err
+	}
+
+	user.Email = invite.Email
+	user.RoleID = invite.RoleID
+	if !user.Name.Valid {
+		user.Name = invite.Name
+	}
+
+	if err = t.CreateUser(user, auditLog); err != nil {
+		return err
+	}
+
+	if _, err = t.tx.Exec(deleteUserInviteSQL, sql.Named("id", inviteID)); err != nil {
+		return dbe(err)
+	}
+	return nil
+}
+
+func (t *Tx) userInviteAuditLog(inviteID ulid.ULID, modified time.Time, action enum.Action, auditLog *models.ComplianceAuditLog) error {
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       inviteID.Bytes(),
+		ResourceType:     enum.ResourceUserInvite,
+		ResourceModified: modified,
+		Action:           action,
+		ChangeNotes:      auditLog.ChangeNotes,
+	})
+}
@@ -0,0 +1,117 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+	"go.rtnl.ai/x/vero"
+)
+
+func (s *storeTestSuite) TestUserInvites() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	invites, err := s.store.ListUserInvites(ctx)
+	require.NoError(err, "could not list user invites")
+	require.Empty(invites)
+
+	invite := &models.UserInvite{
+		Email:      "invitee@example.com",
+		Name:       sql.NullString{Valid: true, String: "Invited User"},
+		RoleID:     2,
+		Expiration: time.Now().Add(time.Hour),
+	}
+
+	err = s.store.CreateUserInvite(ctx, invite, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not create user invite")
+	require.False(invite.ID.IsZero(), "expected an id to be assigned")
+
+	err = s.store.CreateUserInvite(ctx, invite, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNoIDOnCreate)
+
+	// The same email cannot be invited twice
+	err = s.store.CreateUserInvite(ctx, &models.UserInvite{Email: invite.Email, RoleID: 3, Expiration: time.Now()}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrAlreadyExists)
+
+	// Existing users cannot be invited
+	err = s.store.CreateUserInvite(ctx, &models.UserInvite{Email: "observer@example.com", RoleID: 3, Expiration: time.Now()}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrAlreadyExists)
+
+	invites, err = s.store.ListUserInvites(ctx)
+	require.NoError(err, "could not list user invites")
+	require.Len(invites, 1)
+
+	role, err := invites[0].Role()
+	require.NoError(err, "expected the role to be set on the invite")
+	require.Equal("Compliance", role.Title)
+
+	// Sign and send the invite
+	token, err := vero.New(invite.ID.Bytes(), invite.Expiration)
+	require.NoError(err, "could not create verification token")
+	_, invite.Signature, err = token.Sign()
+	require.NoError(err, "could not sign verification token")
+	invite.SentOn = sql.NullTime{Valid: true, Time: time.Now()}
+	require.NoError(s.store.UpdateUserInvite(ctx, invite), "could not update user invite")
+
+	cmp, err := s.store.RetrieveUserInvite(ctx, invite.ID)
+	require.NoError(err, "could not retrieve user invite")
+	require.Equal(invite.Email, cmp.Email)
+	require.Equal(invite.Name, cmp.Name)
+	require.True(cmp.SentOn.Valid)
+	require.False(cmp.IsExpired())
+
+	_, err = s.store.RetrieveUserInvite(ctx, ulid.MakeSecure())
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.UpdateUserInvite(ctx, &models.UserInvite{Model: models.Model{ID: ulid.MakeSecure()}})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	// Revoke the invite
+	require.NoError(s.store.DeleteUserInvite(ctx, invite.ID, &models.ComplianceAuditLog{}), "could not delete user invite")
+	err = s.store.DeleteUserInvite(ctx, invite.ID, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionCreate, enum.ResourceUserInvite): 1,
+		ActionResourceKey(enum.ActionDelete, enum.ResourceUserInvite): 1,
+	})
+}
+
+func (s *storeTestSuite) TestAcceptUserInvite() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	invite := &models.UserInvite{
+		Email:      "invitee@example.com",
+		Name:       sql.NullString{Valid: true, String: "Invited User"},
+		RoleID:     3,
+		Expiration: time.Now().Add(time.Hour),
+	}
+	require.NoError(s.store.CreateUserInvite(ctx, invite, &models.ComplianceAuditLog{}), "could not create user invite")
+
+	user := &models.User{Password: "$argon2id$v=19$m=65536,t=1,p=2$invited"}
+	require.NoError(s.store.AcceptUserInvite(ctx, invite.ID, user, &models.ComplianceAuditLog{}), "could not accept user invite")
+	require.False(user.ID.IsZero(), "expected the user to be created")
+
+	cmp, err := s.store.RetrieveUser(ctx, "invitee@example.com")
+	require.NoError(err, "could not retrieve created user")
+	require.Equal(user.ID, cmp.ID)
+	require.Equal("Invited User", cmp.Name.String, "expected the name of the invite to be used")
+	require.Equal(int64(3), cmp.RoleID, "expected the role of the invite to be assigned")
+
+	// The invite cannot be accepted twice
+	_, err = s.store.RetrieveUserInvite(ctx, invite.ID)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.AcceptUserInvite(ctx, invite.ID, &models.User{}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionCreate, enum.ResourceUserInvite): 1,
+		ActionResourceKey(enum.ActionCreate, enum.ResourceUser):       1,
+	})
+}
@@ -0,0 +1,440 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Legal Hold CRUD interface
+//===========================================================================
+
+const (
+	listLegalHoldsSQL       = "SELECT * FROM legal_holds"
+	listLegalHoldsCursorSQL = "(created, id) < (SELECT created, id FROM legal_holds WHERE id = :cursor)"
+	listLegalHoldsOrderSQL  = " ORDER BY created DESC, id DESC LIMIT :limit"
+)
+
+// List the legal holds filtered by the resource type and resource ID in the page. By
+// default only active holds are returned unless released holds are requested. Holds
+// are returned newest first; if there are more holds than the page size, the next page
+// ID is set to the ID of the last hold in the page and is used as the cursor to fetch
+// the next page.
+func (s *Store) ListLegalHolds(ctx context.Context, page *models.LegalHoldPageInfo) (out *models.LegalHoldPage, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListLegalHolds(page); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *Tx) ListLegalHolds(page *models.LegalHoldPageInfo) (out *models.LegalHoldPage, err error) {
+	out = &models.LegalHoldPage{
+		LegalHolds: make([]*models.LegalHold, 0),
+		Page: &models.LegalHoldPageInfo{
+			PageInfo: *models.PageInfoFrom(nil),
+		},
+	}
+
+	if page != nil {
+		out.Page.PageInfo = *models.PageInfoFrom(&page.PageInfo)
+		out.Page.NextPageID = page.NextPageID
+		out.Page.ResourceType = page.ResourceType
+		out.Page.ResourceID = page.ResourceID
+		out.Page.Released = page.Released
+	}
+
+	query := listLegalHoldsSQL
+	params := make([]any, 0, 4)
+	filters := make([]string, 0, 4)
+
+	if !out.Page.Released {
+		filters = append(filters, "released_on IS NULL")
+	}
+
+	if out.Page.ResourceType != "" {
+		filters = append(filters, "resource_type=:resourceType")
+		params = append(params, sql.Named("resourceType", out.Page.ResourceType))
+	}
+
+	if out.Page.ResourceID != "" {
+		filters = append(filters, "resource_id=:resourceID")
+		params = append(params, sql.Named("resourceID", legalHoldResourceID(out.Page.ResourceID)))
+	}
+
+	// Start the page after the cursor hold if one was specified
+	if !out.Page.NextPageID.IsZero() {
+		filters = append(filters, listLegalHoldsCursorSQL)
+		params = append(params, sql.Named("cursor", out.Page.NextPageID))
+	}
+
+	if len(filters) != 0 {
+		query += " WHERE " + strings.Join(filters, " AND ")
+	}
+
+	// Fetch one more record than the page size to determine if there is a next page
+	query += listLegalHoldsOrderSQL
+	params = append(params, sql.Named("limit", out.Page.PageSize+1))
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(query, params...); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out.Page.NextPageID = ulid.Zero
+	for rows.Next() {
+		// If we've filled the page then there is at least one more hold to fetch
+		if uint32(len(out.LegalHolds)) == out.Page.PageSize {
+			out.Page.NextPageID = out.LegalHolds[len(out.LegalHolds)-1].ID
+			break
+		}
+
+		hold := &models.LegalHold{}
+		if err = hold.Scan(rows); err != nil {
+			return nil, err
+		}
+		out.LegalHolds = append(out.LegalHolds, hold)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const createLegalHoldSQL = "INSERT INTO legal_holds (id, resource_type, resource_id, reason, case_reference, applied_by, applied_by_id, applied_by_type, released_by, released_on, created, modified) VALUES (:id, :resourceType, :resourceID, :reason, :caseReference, :appliedBy, :appliedByID, :appliedByType, :releasedBy, :releasedOn, :created, :modified)"
+
+// Apply a legal hold to the transaction, account, or counterparty in the hold. The
+// actor that applied the hold is taken from the context of the transaction.
+func (s *Store) CreateLegalHold(ctx context.Context, hold *models.LegalHold, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateLegalHold(hold, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) CreateLegalHold(hold *models.LegalHold, auditLog *models.ComplianceAuditLog) (err error) {
+	if !hold.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	// Ensure the held resource exists in the database
+	var exists bool
+	if exists, err = t.legalHoldResourceExists(hold); err != nil {
+		return err
+	}
+
+	if !exists {
+		return dberr.ErrNotFound
+	}
+
+	hold.ID = ulid.MakeSecure()
+	hold.AppliedByID, hold.AppliedByType = t.GetActor()
+	hold.ReleasedBy = sql.NullString{}
+	hold.ReleasedOn = sql.NullTime{}
+	hold.Created = time.Now()
+	hold.Modified = hold.Created
+
+	if hold.AppliedBy == "" {
+		hold.AppliedBy = hold.AppliedByType.String()
+	}
+
+	if _, err = t.tx.Exec(createLegalHoldSQL, hold.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	// Fill the audit log and create it
+	if err = t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          hold.AppliedByID,
+		ActorType:        hold.AppliedByType,
+		ResourceID:       hold.ID.Bytes(),
+		ResourceType:     enum.ResourceLegalHold,
+		ResourceModified: hold.Modified,
+		Action:           enum.ActionCreate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const retrieveLegalHoldSQL = "SELECT * FROM legal_holds WHERE id=:id"
+
+func (s *Store) RetrieveLegalHold(ctx context.Context, holdID ulid.ULID) (hold *models.LegalHold, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if hold, err = tx.RetrieveLegalHold(holdID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (t *Tx) RetrieveLegalHold(holdID ulid.ULID) (hold *models.LegalHold, err error) {
+	hold = &models.LegalHold{}
+	if err = hold.Scan(t.tx.QueryRow(retrieveLegalHoldSQL, sql.Named("id", holdID))); err != nil {
+		return nil, dbe(err)
+	}
+	return hold, nil
+}
+
+const updateLegalHoldSQL = "UPDATE legal_holds SET reason=:reason, case_reference=:caseReference, modified=:modified WHERE id=:id"
+
+// Update the reason and case reference of an active legal hold; all other fields of
+// the legal hold are read-only and are populated on the hold from the database.
+func (s *Store) UpdateLegalHold(ctx context.Context, hold *models.LegalHold, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UpdateLegalHold(hold, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) UpdateLegalHold(hold *models.LegalHold, auditLog *models.ComplianceAuditLog) (err error) {
+	var orig *models.LegalHold
+	if orig, err = t.RetrieveLegalHold(hold.ID); err != nil {
+		return err
+	}
+
+	if !orig.IsActive() {
+		return dberr.ErrHoldReleased
+	}
+
+	orig.Reason = hold.Reason
+	orig.CaseReference = hold.CaseReference
+	orig.Modified = time.Now()
+
+	params := []any{
+		sql.Named("id", orig.ID),
+		sql.Named("reason", orig.Reason),
+		sql.Named("caseReference", orig.CaseReference),
+		sql.Named("modified", orig.Modified),
+	}
+
+	if _, err = t.tx.Exec(updateLegalHoldSQL, params...); err != nil {
+		return dbe(err)
+	}
+
+	// Fill the audit log and create it
+	actorID, actorType := t.GetActor()
+	if err = t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       orig.ID.Bytes(),
+		ResourceType:     enum.ResourceLegalHold,
+		ResourceModified: orig.Modified,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	}); err != nil {
+		return err
+	}
+
+	*hold = *orig
+	return nil
+}
+
+const releaseLegalHoldSQL = "UPDATE legal_holds SET released_by=:releasedBy, released_on=:releasedOn, modified=:modified WHERE id=:id"
+
+// Release a legal hold so that the held resource can be deleted, archived, or purged.
+// The hold is not deleted so that the history of the hold is preserved.
+func (s *Store) ReleaseLegalHold(ctx context.Context, holdID ulid.ULID, releasedBy string, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.ReleaseLegalHold(holdID, releasedBy, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) ReleaseLegalHold(holdID ulid.ULID, releasedBy string, auditLog *models.ComplianceAuditLog) (err error) {
+	var hold *models.LegalHold
+	if hold, err = t.RetrieveLegalHold(holdID); err != nil {
+		return err
+	}
+
+	if !hold.IsActive() {
+		return dberr.ErrHoldReleased
+	}
+
+	actorID, actorType := t.GetActor()
+	if releasedBy == "" {
+		releasedBy = actorType.String()
+	}
+
+	timestamp := time.Now()
+	params := []any{
+		sql.Named("id", holdID),
+		sql.Named("releasedBy", releasedBy),
+		sql.Named("releasedOn", timestamp),
+		sql.Named("modified", timestamp),
+	}
+
+	if _, err = t.tx.Exec(releaseLegalHoldSQL, params...); err != nil {
+		return dbe(err)
+	}
+
+	// Make an audit log note with this function name so we know what kind of
+	// update it was
+	notes := sql.NullString{
+		Valid:  true,
+		String: "ReleaseLegalHold",
+	}
+	if auditLog.ChangeNotes.Valid {
+		notes.String = auditLog.ChangeNotes.String + "-" + notes.String
+	}
+
+	// Fill the audit log and create it
+	if err = t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       holdID.Bytes(),
+		ResourceType:     enum.ResourceLegalHold,
+		ResourceModified: timestamp,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      notes,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//===========================================================================
+// Legal Hold Enforcement
+//===========================================================================
+
+// A transaction is under legal hold if the transaction has an active hold or if its
+// counterparty or one of the customer accounts that own its crypto addresses has an
+// active hold. The transactions table must be aliased as t to use this condition.
+const transactionHeldSQL = `EXISTS (
+	SELECT 1 FROM legal_holds h
+		WHERE h.released_on IS NULL AND (
+			(h.resource_type='transaction' AND h.resource_id=t.id) OR
+			(h.resource_type='counterparty' AND h.resource_id=t.counterparty_id) OR
+			(h.resource_type='account' AND h.resource_id IN (
+				SELECT c.account_id FROM crypto_addresses c WHERE c.crypto_address_idx IN (t.originator_address_idx, t.beneficiary_address_idx)
+			))
+		)
+)`
+
+const (
+	transactionOnHoldSQL = "SELECT " + transactionHeldSQL + " FROM transactions t WHERE t.id=:id"
+	resourceOnHoldSQL    = "SELECT EXISTS (SELECT 1 FROM legal_holds WHERE resource_type=:resourceType AND resource_id=:resourceID AND released_on IS NULL)"
+)
+
+// Returns true if the transaction or its counterparty or accounts are under legal hold.
+// If the transaction does not exist, false is returned with no error.
+func (t *Tx) transactionOnHold(transactionID uuid.UUID) (held bool, err error) {
+	if err = t.tx.QueryRow(transactionOnHoldSQL, sql.Named("id", transactionID)).Scan(&held); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, dbe(err)
+	}
+	return held, nil
+}
+
+// Returns true if the specified account or counterparty has an active legal hold.
+func (t *Tx) resourceOnHold(resourceType enum.Resource, resourceID ulid.ULID) (held bool, err error) {
+	params := []any{
+		sql.Named("resourceType", resourceType),
+		sql.Named("resourceID", resourceID),
+	}
+
+	if err = t.tx.QueryRow(resourceOnHoldSQL, params...).Scan(&held); err != nil {
+		return false, dbe(err)
+	}
+	return held, nil
+}
+
+const (
+	heldTransactionExistsSQL  = "SELECT EXISTS (SELECT 1 FROM transactions WHERE id=:id)"
+	heldAccountExistsSQL      = "SELECT EXISTS (SELECT 1 FROM accounts WHERE id=:id)"
+	heldCounterpartyExistsSQL = "SELECT EXISTS (SELECT 1 FROM counterparties WHERE id=:id)"
+)
+
+func (t *Tx) legalHoldResourceExists(hold *models.LegalHold) (exists bool, err error) {
+	var resourceID any
+	if resourceID, err = hold.ResourceKey(); err != nil {
+		if errors.Is(err, dberr.ErrInvalidHoldResource) {
+			return false, err
+		}
+		return false, dberr.ErrNotFound
+	}
+
+	var query string
+	switch hold.ResourceType {
+	case enum.ResourceTransaction:
+		query = heldTransactionExistsSQL
+	case enum.ResourceAccount:
+		query = heldAccountExistsSQL
+	case enum.ResourceCounterparty:
+		query = heldCounterpartyExistsSQL
+	default:
+		return false, dberr.ErrInvalidHoldResource
+	}
+
+	if err = t.tx.QueryRow(query, sql.Named("id", resourceID)).Scan(&exists); err != nil {
+		return false, dbe(err)
+	}
+	return exists, nil
+}
+
+// Converts the string resource ID into the representation stored in the database:
+// ULIDs are stored as bytes and transaction UUIDs are stored as strings.
+func legalHoldResourceID(id string) any {
+	if uid, err := ulid.Parse(id); err == nil {
+		return uid.Bytes()
+	}
+
+	if uid, err := uuid.Parse(id); err == nil {
+		return uid.String()
+	}
+
+	return id
+}
@@ -0,0 +1,349 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestCreateLegalHold() {
+	s.Run("Transaction", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		hold := &models.LegalHold{
+			ResourceType:  enum.ResourceTransaction,
+			ResourceID:    "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3",
+			Reason:        "subject of an ongoing investigation",
+			CaseReference: sql.NullString{String: "CASE-0042", Valid: true},
+			AppliedBy:     "Compliance Officer",
+		}
+
+		err := s.store.CreateLegalHold(ctx, hold, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create legal hold")
+		require.False(hold.ID.IsZero(), "expected an id to be assigned")
+		require.NotEmpty(hold.AppliedByID, "expected the actor id to be assigned")
+		require.True(hold.IsActive(), "expected the hold to be active")
+
+		cmp, err := s.store.RetrieveLegalHold(ctx, hold.ID)
+		require.NoError(err, "could not retrieve legal hold")
+		require.Equal(enum.ResourceTransaction, cmp.ResourceType)
+		require.Equal("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3", cmp.ResourceID)
+		require.Equal("CASE-0042", cmp.CaseReference.String)
+		require.Equal("Compliance Officer", cmp.AppliedBy)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceLegalHold): 1,
+		})
+	})
+
+	s.Run("Account", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		hold := &models.LegalHold{
+			ResourceType: enum.ResourceAccount,
+			ResourceID:   "01HV6RV08YNR2GH8MEEFCV4NKN",
+			Reason:       "subpoena",
+		}
+
+		err := s.store.CreateLegalHold(ctx, hold, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create legal hold")
+
+		cmp, err := s.store.RetrieveLegalHold(ctx, hold.ID)
+		require.NoError(err, "could not retrieve legal hold")
+		require.Equal("01HV6RV08YNR2GH8MEEFCV4NKN", cmp.ResourceID)
+		require.Equal(enum.ActorAPIKey.String(), cmp.AppliedBy, "expected applied by to default to the actor type")
+	})
+
+	s.Run("Counterparty", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		hold := &models.LegalHold{
+			ResourceType: enum.ResourceCounterparty,
+			ResourceID:   "01HWR5VWW8V7ZFFVJVBEC7AV8A",
+			Reason:       "subpoena",
+		}
+
+		err := s.store.CreateLegalHold(ctx, hold, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create legal hold")
+
+		cmp, err := s.store.RetrieveLegalHold(ctx, hold.ID)
+		require.NoError(err, "could not retrieve legal hold")
+		require.Equal("01HWR5VWW8V7ZFFVJVBEC7AV8A", cmp.ResourceID)
+	})
+
+	s.Run("NotFound", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.CreateLegalHold(ctx, &models.LegalHold{
+			ResourceType: enum.ResourceTransaction,
+			ResourceID:   uuid.NewString(),
+			Reason:       "subpoena",
+		}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		s.AssertAuditLogCount(map[string]int{})
+	})
+
+	s.Run("InvalidResource", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.CreateLegalHold(ctx, &models.LegalHold{
+			ResourceType: enum.ResourceUser,
+			ResourceID:   ulid.MakeSecure().String(),
+			Reason:       "subpoena",
+		}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrInvalidHoldResource)
+	})
+
+	s.Run("NoIDOnCreate", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.CreateLegalHold(ctx, &models.LegalHold{
+			Model:        models.Model{ID: ulid.MakeSecure()},
+			ResourceType: enum.ResourceTransaction,
+			ResourceID:   "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3",
+		}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNoIDOnCreate)
+	})
+}
+
+func (s *storeTestSuite) TestListLegalHolds() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	txHold := s.createLegalHold(enum.ResourceTransaction, "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+	s.createLegalHold(enum.ResourceAccount, "01HV6RV08YNR2GH8MEEFCV4NKN")
+	released := s.createLegalHold(enum.ResourceCounterparty, "01HWR5VWW8V7ZFFVJVBEC7AV8A")
+	require.NoError(s.store.ReleaseLegalHold(ctx, released.ID, "Compliance Officer", &models.ComplianceAuditLog{}))
+
+	page, err := s.store.ListLegalHolds(ctx, &models.LegalHoldPageInfo{})
+	require.NoError(err, "could not list legal holds")
+	require.Len(page.LegalHolds, 2, "expected only active holds to be returned")
+
+	page, err = s.store.ListLegalHolds(ctx, &models.LegalHoldPageInfo{Released: true})
+	require.NoError(err, "could not list legal holds")
+	require.Len(page.LegalHolds, 3, "expected released holds to be returned")
+
+	page, err = s.store.ListLegalHolds(ctx, &models.LegalHoldPageInfo{ResourceType: enum.ResourceTransaction.String()})
+	require.NoError(err, "could not list legal holds")
+	require.Len(page.LegalHolds, 1)
+	require.Equal(txHold.ID, page.LegalHolds[0].ID)
+
+	page, err = s.store.ListLegalHolds(ctx, &models.LegalHoldPageInfo{ResourceID: "01HV6RV08YNR2GH8MEEFCV4NKN"})
+	require.NoError(err, "could not list legal holds")
+	require.Len(page.LegalHolds, 1)
+	require.Equal(enum.ResourceAccount, page.LegalHolds[0].ResourceType)
+
+	page, err = s.store.ListLegalHolds(ctx, nil)
+	require.NoError(err, "could not list legal holds with nil page")
+	require.Len(page.LegalHolds, 2)
+}
+
+func (s *storeTestSuite) TestListLegalHoldsPagination() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	holds := make(map[ulid.ULID]struct{}, 5)
+	for i := 0; i < 5; i++ {
+		hold := s.createLegalHold(enum.ResourceTransaction, "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+		holds[hold.ID] = struct{}{}
+	}
+
+	// Page through the holds two at a time using the next page cursor
+	info := &models.LegalHoldPageInfo{PageInfo: models.PageInfo{PageSize: 2}}
+	seen := make(map[ulid.ULID]struct{}, 5)
+	pages := 0
+	for {
+		page, err := s.store.ListLegalHolds(ctx, info)
+		require.NoError(err, "could not list legal holds")
+		require.LessOrEqual(len(page.LegalHolds), 2, "expected page size to be enforced")
+		pages++
+
+		for _, hold := range page.LegalHolds {
+			require.NotContains(seen, hold.ID, "expected holds to not be repeated across pages")
+			seen[hold.ID] = struct{}{}
+		}
+
+		if page.Page.NextPageID.IsZero() {
+			break
+		}
+
+		require.Equal(page.LegalHolds[len(page.LegalHolds)-1].ID, page.Page.NextPageID, "expected the cursor to be the last hold on the page")
+		info.NextPageID = page.Page.NextPageID
+	}
+
+	require.Equal(3, pages, "expected three pages of holds")
+	require.Equal(holds, seen, "expected all holds to be returned")
+}
+
+func (s *storeTestSuite) TestUpdateLegalHold() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		hold := s.createLegalHold(enum.ResourceTransaction, "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+		update := &models.LegalHold{
+			Model:         models.Model{ID: hold.ID},
+			ResourceType:  enum.ResourceCounterparty,
+			Reason:        "updated reason",
+			CaseReference: sql.NullString{String: "CASE-0043", Valid: true},
+		}
+
+		err := s.store.UpdateLegalHold(ctx, update, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not update legal hold")
+		require.Equal(enum.ResourceTransaction, update.ResourceType, "expected resource type to be read-only")
+		require.Equal("updated reason", update.Reason)
+
+		cmp, err := s.store.RetrieveLegalHold(ctx, hold.ID)
+		require.NoError(err, "could not retrieve legal hold")
+		require.Equal("updated reason", cmp.Reason)
+		require.Equal("CASE-0043", cmp.CaseReference.String)
+		require.Equal("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3", cmp.ResourceID)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceLegalHold): 1,
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceLegalHold): 1,
+		})
+	})
+
+	s.Run("Released", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		hold := s.createLegalHold(enum.ResourceTransaction, "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+		require.NoError(s.store.ReleaseLegalHold(ctx, hold.ID, "", &models.ComplianceAuditLog{}))
+
+		hold.Reason = "updated reason"
+		err := s.store.UpdateLegalHold(ctx, hold, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrHoldReleased)
+	})
+
+	s.Run("NotFound", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.UpdateLegalHold(ctx, &models.LegalHold{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotFound)
+	})
+}
+
+func (s *storeTestSuite) TestReleaseLegalHold() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+
+		hold := s.createLegalHold(enum.ResourceTransaction, txID.String())
+		err := s.store.DeleteTransaction(ctx, txID, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrLegalHold)
+
+		err = s.store.ReleaseLegalHold(ctx, hold.ID, "Compliance Officer", &models.ComplianceAuditLog{})
+		require.NoError(err, "could not release legal hold")
+
+		cmp, err := s.store.RetrieveLegalHold(ctx, hold.ID)
+		require.NoError(err, "could not retrieve legal hold")
+		require.False(cmp.IsActive(), "expected hold to be released")
+		require.Equal("Compliance Officer", cmp.ReleasedBy.String)
+		require.WithinDuration(time.Now(), cmp.ReleasedOn.Time, time.Minute)
+
+		err = s.store.DeleteTransaction(ctx, txID, &models.ComplianceAuditLog{})
+		require.NoError(err, "expected transaction to be deleted once the hold is released")
+	})
+
+	s.Run("AlreadyReleased", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		hold := s.createLegalHold(enum.ResourceTransaction, "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+		require.NoError(s.store.ReleaseLegalHold(ctx, hold.ID, "", &models.ComplianceAuditLog{}))
+
+		err := s.store.ReleaseLegalHold(ctx, hold.ID, "", &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrHoldReleased)
+	})
+
+	s.Run("NotFound", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.ReleaseLegalHold(ctx, ulid.MakeSecure(), "", &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotFound)
+	})
+}
+
+func (s *storeTestSuite) TestLegalHoldEnforcement() {
+	s.Run("Transaction", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+
+		s.createLegalHold(enum.ResourceTransaction, txID.String())
+		require.ErrorIs(s.store.DeleteTransaction(ctx, txID, &models.ComplianceAuditLog{}), errors.ErrLegalHold)
+		require.ErrorIs(s.store.ArchiveTransaction(ctx, txID, &models.ComplianceAuditLog{}), errors.ErrLegalHold)
+
+		// Other transactions are not affected by the hold
+		require.NoError(s.store.ArchiveTransaction(ctx, uuid.MustParse("82624eee-2dab-45e6-abc0-df931fe2d832"), &models.ComplianceAuditLog{}))
+	})
+
+	s.Run("Counterparty", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		s.createLegalHold(enum.ResourceCounterparty, "01HWR5VWW8V7ZFFVJVBEC7AV8A")
+		require.ErrorIs(s.store.DeleteCounterparty(ctx, ulid.MustParse("01HWR5VWW8V7ZFFVJVBEC7AV8A"), &models.ComplianceAuditLog{}), errors.ErrLegalHold)
+
+		// Transactions with the held counterparty are also held
+		txID := uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+		require.ErrorIs(s.store.DeleteTransaction(ctx, txID, &models.ComplianceAuditLog{}), errors.ErrLegalHold)
+	})
+
+	s.Run("Account", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		accountID := ulid.MustParse("01HV6RV08YNR2GH8MEEFCV4NKN")
+
+		s.createLegalHold(enum.ResourceAccount, accountID.String())
+		require.ErrorIs(s.store.DeleteAccount(ctx, accountID, &models.ComplianceAuditLog{}), errors.ErrLegalHold)
+		require.ErrorIs(s.store.DeleteCryptoAddress(ctx, accountID, ulid.MustParse("01HV6RV08YNR2GH8MEEKB7DH2W"), &models.ComplianceAuditLog{}), errors.ErrLegalHold)
+
+		// Transactions using the account's crypto addresses are also held
+		txID := uuid.MustParse("b04dc71c-7214-46a5-a514-381ef0bcc494")
+		require.ErrorIs(s.store.ArchiveTransaction(ctx, txID, &models.ComplianceAuditLog{}), errors.ErrLegalHold)
+
+		// Other accounts are not affected by the hold
+		require.NoError(s.store.DeleteAccount(ctx, ulid.MustParse("01HV6QS6AK4KNS46Q9HEB7DTPR"), &models.ComplianceAuditLog{}))
+	})
+}
+
+func (s *storeTestSuite) createLegalHold(resource enum.Resource, resourceID string) *models.LegalHold {
+	hold := &models.LegalHold{
+		ResourceType: resource,
+		ResourceID:   resourceID,
+		Reason:       "subject of an ongoing investigation",
+	}
+
+	err := s.store.CreateLegalHold(s.ActorContext(), hold, &models.ComplianceAuditLog{})
+	s.Require().NoError(err, "could not create legal hold")
+	return hold
+}
@@ -0,0 +1,380 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Multi-Factor Authentication
+//===========================================================================
+
+const setUserMFASecretSQL = "UPDATE users SET mfa_secret=:mfaSecret, mfa_enrolled=NULL, modified=:modified WHERE id=:id AND mfa_enrolled IS NULL"
+
+func (s *Store) SetUserMFASecret(ctx context.Context, userID ulid.ULID, secret string) (err error) {
+	//NOTE: starting enrollment does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.SetUserMFASecret(userID, secret); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Sets the TOTP secret of a user who is starting MFA enrollment; the secret is not used
+// to authenticate the user until enrollment is completed with EnableUserMFA. Returns
+// ErrNotFound if the user does not exist or has already enrolled in MFA.
+func (t *Tx) SetUserMFASecret(userID ulid.ULID, secret string) (err error) {
+	//NOTE: starting enrollment does not require an audit log entry
+	var encrypted string
+	if encrypted, err = t.cipher.EncryptString(secret); err != nil {
+		return err
+	}
+
+	params := []any{
+		sql.Named("id", userID),
+		sql.Named("mfaSecret", sql.NullString{String: encrypted, Valid: secret != ""}),
+		sql.Named("modified", time.Now()),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(setUserMFASecretSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return nil
+}
+
+const enableUserMFASQL = "UPDATE users SET mfa_enrolled=:mfaEnrolled, modified=:modified WHERE id=:id AND mfa_secret IS NOT NULL"
+
+func (s *Store) EnableUserMFA(ctx context.Context, userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.EnableUserMFA(userID, recoveryCodes, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Completes MFA enrollment for a user who has a TOTP secret and replaces any existing
+// recovery codes with the hashed recovery codes specified.
+func (t *Tx) EnableUserMFA(userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) (err error) {
+	now := time.Now()
+	params := []any{
+		sql.Named("id", userID),
+		sql.Named("mfaEnrolled", now),
+		sql.Named("modified", now),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(enableUserMFASQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	if err = t.replaceRecoveryCodes(userID, recoveryCodes, now); err != nil {
+		return err
+	}
+
+	return t.mfaAuditLog(userID, now, auditLog)
+}
+
+const mfaEnrolledSQL = "SELECT EXISTS(SELECT 1 FROM users WHERE id=:id AND mfa_enrolled IS NOT NULL)"
+
+func (s *Store) SetUserRecoveryCodes(ctx context.Context, userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.SetUserRecoveryCodes(userID, recoveryCodes, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Replaces the recovery codes of a user who has enrolled in MFA with the hashed
+// recovery codes specified, invalidating any previously issued recovery codes.
+func (t *Tx) SetUserRecoveryCodes(userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) (err error) {
+	var enrolled bool
+	if err = t.tx.QueryRow(mfaEnrolledSQL, sql.Named("id", userID)).Scan(&enrolled); err != nil {
+		return dbe(err)
+	}
+
+	if !enrolled {
+		return dberr.ErrNotFound
+	}
+
+	now := time.Now()
+	if err = t.replaceRecoveryCodes(userID, recoveryCodes, now); err != nil {
+		return err
+	}
+
+	return t.mfaAuditLog(userID, now, auditLog)
+}
+
+const (
+	useRecoveryCodeSQL  = "UPDATE mfa_recovery_codes SET used_on=:usedOn, modified=:modified WHERE user_id=:userID AND code_hash=:codeHash AND used_on IS NULL"
+	resetMFAFailuresSQL = "UPDATE users SET mfa_failures=0, mfa_locked_until=NULL WHERE id=:userID"
+)
+
+func (s *Store) UseUserRecoveryCode(ctx context.Context, userID ulid.ULID, recoveryCode string) (err error) {
+	//NOTE: using a recovery code does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UseUserRecoveryCode(userID, recoveryCode); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Marks the hashed recovery code as used so that it cannot be used again and resets
+// the user's failed MFA attempts. Returns ErrNotFound if the recovery code does not
+// exist or has already been used.
+func (t *Tx) UseUserRecoveryCode(userID ulid.ULID, recoveryCode string) (err error) {
+	//NOTE: using a recovery code does not require an audit log entry
+	now := time.Now()
+	params := []any{
+		sql.Named("userID", userID),
+		sql.Named("codeHash", recoveryCode),
+		sql.Named("usedOn", now),
+		sql.Named("modified", now),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(useRecoveryCodeSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	if _, err = t.tx.Exec(resetMFAFailuresSQL, sql.Named("userID", userID)); err != nil {
+		return dbe(err)
+	}
+
+	return nil
+}
+
+const setUserMFAStepSQL = "UPDATE users SET mfa_last_step=:step, mfa_failures=0, mfa_locked_until=NULL WHERE id=:id AND COALESCE(mfa_last_step, 0) < :step"
+
+func (s *Store) SetUserMFAStep(ctx context.Context, userID ulid.ULID, step int64) (err error) {
+	//NOTE: recording MFA verification attempts does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.SetUserMFAStep(userID, step); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Records the time step of a TOTP code accepted for the user and resets the user's
+// failed MFA attempts. Returns ErrNotFound if the user does not exist or if a code at
+// or after the time step has already been accepted so that codes cannot be replayed.
+func (t *Tx) SetUserMFAStep(userID ulid.ULID, step int64) (err error) {
+	//NOTE: recording MFA verification attempts does not require an audit log entry
+	var result sql.Result
+	if result, err = t.tx.Exec(setUserMFAStepSQL, sql.Named("id", userID), sql.Named("step", step)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+	return nil
+}
+
+const recordMFAFailureSQL = `UPDATE users SET
+	mfa_failures=CASE WHEN mfa_failures+1 >= :maxFailures THEN 0 ELSE mfa_failures+1 END,
+	mfa_locked_until=CASE WHEN mfa_failures+1 >= :maxFailures THEN :lockedUntil ELSE mfa_locked_until END
+	WHERE id=:id`
+
+func (s *Store) RecordUserMFAFailure(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout time.Duration) (err error) {
+	//NOTE: recording MFA verification attempts does not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.RecordUserMFAFailure(userID, maxFailures, lockout); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Increments the user's failed MFA attempts; once maxFailures consecutive attempts
+// have failed, the user is locked out of MFA verification for the lockout duration
+// and the count is reset so that the user has another maxFailures attempts after it.
+func (t *Tx) RecordUserMFAFailure(userID ulid.ULID, maxFailures int64, lockout time.Duration) (err error) {
+	//NOTE: recording MFA verification attempts does not require an audit log entry
+	params := []any{
+		sql.Named("id", userID),
+		sql.Named("maxFailures", maxFailures),
+		sql.Named("lockedUntil", time.Now().Add(lockout)),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(recordMFAFailureSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+	return nil
+}
+
+const (
+	resetUserMFASQL        = "UPDATE users SET mfa_secret=NULL, mfa_enrolled=NULL, mfa_last_step=NULL, mfa_failures=0, mfa_locked_until=NULL, modified=:modified WHERE id=:id"
+	deleteRecoveryCodesSQL = "DELETE FROM mfa_recovery_codes WHERE user_id=:userID"
+)
+
+func (s *Store) ResetUserMFA(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.ResetUserMFA(userID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Removes the TOTP secret and recovery codes of the user so that they can re-enroll.
+func (t *Tx) ResetUserMFA(userID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	now := time.Now()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(resetUserMFASQL, sql.Named("id", userID), sql.Named("modified", now)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	if _, err = t.tx.Exec(deleteRecoveryCodesSQL, sql.Named("userID", userID)); err != nil {
+		return dbe(err)
+	}
+
+	return t.mfaAuditLog(userID, now, auditLog)
+}
+
+const setRoleMFARequiredSQL = "UPDATE roles SET require_mfa=:requireMFA, modified=:modified WHERE id=:id"
+
+func (s *Store) SetRoleMFARequired(ctx context.Context, roleID int64, required bool, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.SetRoleMFARequired(roleID, required, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Sets the MFA policy of the role; if required, users with the role are not granted
+// the role's permissions until they have enrolled in MFA.
+func (t *Tx) SetRoleMFARequired(roleID int64, required bool, auditLog *models.ComplianceAuditLog) (err error) {
+	now := time.Now()
+	params := []any{
+		sql.Named("id", roleID),
+		sql.Named("requireMFA", required),
+		sql.Named("modified", now),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(setRoleMFARequiredSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	// Fill the audit log and create it
+	actorID, actorType := t.GetActor()
+	if err := t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       []byte(strconv.FormatInt(roleID, 10)),
+		ResourceType:     enum.ResourceRole,
+		ResourceModified: now,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+const insertRecoveryCodeSQL = "INSERT INTO mfa_recovery_codes (id, user_id, code_hash, used_on, created, modified) VALUES (:id, :userID, :codeHash, NULL, :created, :modified)"
+
+func (t *Tx) replaceRecoveryCodes(userID ulid.ULID, recoveryCodes []string, now time.Time) (err error) {
+	if _, err = t.tx.Exec(deleteRecoveryCodesSQL, sql.Named("userID", userID)); err != nil {
+		return dbe(err)
+	}
+
+	for _, code := range recoveryCodes {
+		params := []any{
+			sql.Named("id", ulid.MakeSecure()),
+			sql.Named("userID", userID),
+			sql.Named("codeHash", code),
+			sql.Named("created", now),
+			sql.Named("modified", now),
+		}
+
+		if _, err = t.tx.Exec(insertRecoveryCodeSQL, params...); err != nil {
+			return dbe(err)
+		}
+	}
+
+	return nil
+}
+
+// MFA enrollment and resets are recorded as updates to the user.
+func (t *Tx) mfaAuditLog(userID ulid.ULID, modified time.Time, auditLog *models.ComplianceAuditLog) (err error) {
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       userID.Bytes(),
+		ResourceType:     enum.ResourceUser,
+		ResourceModified: modified,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      auditLog.ChangeNotes,
+	})
+}
@@ -0,0 +1,244 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestEnableUserMFA_Success() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+	secret := "JBSWY3DPEHPK3PXP"
+	codes := []string{"hash-one", "hash-two"}
+
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	err := s.store.SetUserMFASecret(ctx, userID, secret)
+	require.NoError(err, "could not set mfa secret")
+
+	user, err := s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.Equal(secret, user.MFASecret.String, "expected secret to be decrypted")
+	require.False(user.MFAEnabled(), "expected mfa to not be enabled until verified")
+	s.assertMFASecretEncrypted(userID)
+
+	err = s.store.EnableUserMFA(ctx, userID, codes, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not enable mfa")
+
+	user, err = s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.True(user.MFAEnabled(), "expected mfa to be enabled")
+
+	page, err := s.store.ListUsers(ctx, &models.UserPageInfo{})
+	require.NoError(err, "could not list users")
+	for _, u := range page.Users {
+		require.Equal(u.ID == userID, u.MFAEnabled(), "expected only the enrolled user to have mfa enabled in the user summary")
+	}
+
+	// Cannot restart enrollment once enrolled
+	err = s.store.SetUserMFASecret(ctx, userID, "NEWSECRET")
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceUser): 1,
+	})
+}
+
+func (s *storeTestSuite) TestEnableUserMFA_FailureNotStarted() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+	codes := []string{"hash-one", "hash-two"}
+
+	require := s.Require()
+	err := s.store.EnableUserMFA(s.ActorContext(), userID, codes, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound, "expected error when no secret has been set")
+
+	err = s.store.SetUserRecoveryCodes(s.ActorContext(), userID, codes, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound, "expected error when mfa is not enabled")
+}
+
+func (s *storeTestSuite) TestUseUserRecoveryCode() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+	secret := "JBSWY3DPEHPK3PXP"
+	codes := []string{"hash-one", "hash-two"}
+
+	require := s.Require()
+	ctx := s.ActorContext()
+	s.enrollMFA(userID, secret, codes)
+
+	err := s.store.UseUserRecoveryCode(ctx, userID, "hash-one")
+	require.NoError(err, "could not use recovery code")
+
+	err = s.store.UseUserRecoveryCode(ctx, userID, "hash-one")
+	require.ErrorIs(err, errors.ErrNotFound, "expected recovery code to be single use")
+
+	err = s.store.UseUserRecoveryCode(ctx, userID, "hash-three")
+	require.ErrorIs(err, errors.ErrNotFound, "expected unknown recovery code to fail")
+
+	err = s.store.UseUserRecoveryCode(ctx, ulid.MustParse("01HWQE347SRM7CBRSYM7QJ3M83"), "hash-two")
+	require.ErrorIs(err, errors.ErrNotFound, "expected recovery code of another user to fail")
+
+	// Regenerating the recovery codes invalidates the previous codes
+	err = s.store.SetUserRecoveryCodes(ctx, userID, []string{"hash-three"}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not regenerate recovery codes")
+
+	err = s.store.UseUserRecoveryCode(ctx, userID, "hash-two")
+	require.ErrorIs(err, errors.ErrNotFound, "expected previous recovery codes to be invalidated")
+
+	err = s.store.UseUserRecoveryCode(ctx, userID, "hash-three")
+	require.NoError(err, "could not use regenerated recovery code")
+}
+
+func (s *storeTestSuite) TestSetUserMFAStep() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+	secret := "JBSWY3DPEHPK3PXP"
+	codes := []string{"hash-one", "hash-two"}
+
+	require := s.Require()
+	ctx := s.ActorContext()
+	s.enrollMFA(userID, secret, codes)
+
+	err := s.store.SetUserMFAStep(ctx, userID, 42)
+	require.NoError(err, "could not set mfa step")
+
+	user, err := s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.Equal(int64(42), user.MFALastStep.Int64, "expected the last step to be recorded")
+
+	err = s.store.SetUserMFAStep(ctx, userID, 42)
+	require.ErrorIs(err, errors.ErrNotFound, "expected the same step to be rejected as a replay")
+
+	err = s.store.SetUserMFAStep(ctx, userID, 41)
+	require.ErrorIs(err, errors.ErrNotFound, "expected an earlier step to be rejected as a replay")
+
+	err = s.store.SetUserMFAStep(ctx, userID, 43)
+	require.NoError(err, "could not set a later mfa step")
+
+	err = s.store.SetUserMFAStep(ctx, ulid.MakeSecure(), 44)
+	require.ErrorIs(err, errors.ErrNotFound)
+}
+
+func (s *storeTestSuite) TestRecordUserMFAFailure() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+	secret := "JBSWY3DPEHPK3PXP"
+	codes := []string{"hash-one", "hash-two"}
+
+	require := s.Require()
+	ctx := s.ActorContext()
+	s.enrollMFA(userID, secret, codes)
+
+	for i := 1; i < 3; i++ {
+		require.NoError(s.store.RecordUserMFAFailure(ctx, userID, 3, time.Hour), "could not record mfa failure")
+
+		user, err := s.store.RetrieveUser(ctx, userID)
+		require.NoError(err, "could not retrieve user")
+		require.Equal(int64(i), user.MFAFailures)
+		require.False(user.MFALockedOut(time.Now()), "expected user to not be locked out before max failures")
+	}
+
+	// The user is locked out once the max failures is reached
+	require.NoError(s.store.RecordUserMFAFailure(ctx, userID, 3, time.Hour), "could not record mfa failure")
+
+	user, err := s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.Zero(user.MFAFailures, "expected failures to be reset when locked out")
+	require.True(user.MFALockedOut(time.Now()), "expected user to be locked out")
+	require.False(user.MFALockedOut(time.Now().Add(2*time.Hour)), "expected lockout to expire")
+
+	// A successful code resets the lockout
+	require.NoError(s.store.SetUserMFAStep(ctx, userID, 42), "could not set mfa step")
+	user, err = s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.False(user.MFALockedOut(time.Now()), "expected lockout to be reset")
+
+	// A recovery code also resets failures
+	require.NoError(s.store.RecordUserMFAFailure(ctx, userID, 3, time.Hour), "could not record mfa failure")
+	require.NoError(s.store.UseUserRecoveryCode(ctx, userID, "hash-one"), "could not use recovery code")
+	user, err = s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.Zero(user.MFAFailures, "expected failures to be reset by a recovery code")
+
+	err = s.store.RecordUserMFAFailure(ctx, ulid.MakeSecure(), 3, time.Hour)
+	require.ErrorIs(err, errors.ErrNotFound)
+}
+
+func (s *storeTestSuite) TestResetUserMFA() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+	secret := "JBSWY3DPEHPK3PXP"
+	codes := []string{"hash-one", "hash-two"}
+
+	require := s.Require()
+	ctx := s.ActorContext()
+	s.enrollMFA(userID, secret, codes)
+
+	err := s.store.ResetUserMFA(ctx, userID, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not reset mfa")
+
+	user, err := s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.False(user.MFAEnabled(), "expected mfa to be disabled")
+	require.False(user.MFASecret.Valid, "expected mfa secret to be removed")
+
+	err = s.store.UseUserRecoveryCode(ctx, userID, "hash-two")
+	require.ErrorIs(err, errors.ErrNotFound, "expected recovery codes to be deleted")
+
+	err = s.store.ResetUserMFA(ctx, ulid.MakeSecure(), &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+}
+
+func (s *storeTestSuite) TestSetRoleMFARequired() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		role, err := s.store.LookupRole(ctx, "compliance")
+		require.NoError(err, "could not lookup role")
+		require.False(role.RequireMFA, "expected mfa to not be required by default")
+
+		err = s.store.SetRoleMFARequired(ctx, role.ID, true, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not set role mfa policy")
+
+		role, err = s.store.LookupRole(ctx, "compliance")
+		require.NoError(err, "could not lookup role")
+		require.True(role.RequireMFA, "expected mfa to be required")
+
+		user, err := s.store.RetrieveUser(ctx, "compliance@example.com")
+		require.NoError(err, "could not retrieve user")
+		userRole, err := user.Role()
+		require.NoError(err, "expected user role to be fetched")
+		require.True(userRole.RequireMFA, "expected user role to require mfa")
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceRole): 1,
+		})
+	})
+
+	s.Run("NotFound", func() {
+		err := s.store.SetRoleMFARequired(s.ActorContext(), 42, true, &models.ComplianceAuditLog{})
+		s.Require().ErrorIs(err, errors.ErrNotFound)
+	})
+}
+
+func (s *storeTestSuite) enrollMFA(userID ulid.ULID, secret string, codes []string) {
+	require := s.Require()
+	require.NoError(s.store.SetUserMFASecret(s.ActorContext(), userID, secret), "could not set mfa secret")
+	require.NoError(s.store.EnableUserMFA(s.ActorContext(), userID, codes, &models.ComplianceAuditLog{}), "could not enable mfa")
+}
+
+func (s *storeTestSuite) assertMFASecretEncrypted(userID ulid.ULID) {
+	require := s.Require()
+	tx, err := s.store.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	require.NoError(err, "could not open transaction")
+	defer tx.Rollback()
+
+	var secret string
+	err = tx.QueryRow("SELECT mfa_secret FROM users WHERE id=$1", userID).Scan(&secret)
+	require.NoError(err, "could not query raw mfa secret")
+	require.True(pii.IsEncrypted([]byte(secret)), "expected mfa secret to be encrypted at rest")
+}
@@ -0,0 +1,13 @@
+-- Adds indexes to the compliance audit log to support filtering by actor, resource
+-- and action when querying the history of a record.
+BEGIN;
+
+-- Compound indexes with resource_modified support filtering by the actor or resource
+-- while ordering by the time the resource was modified (the default list ordering).
+CREATE INDEX IF NOT EXISTS idx_cal_resource_id ON compliance_audit_log(resource_id, resource_modified);
+CREATE INDEX IF NOT EXISTS idx_cal_resource_type ON compliance_audit_log(resource_type, resource_modified);
+CREATE INDEX IF NOT EXISTS idx_cal_actor_id ON compliance_audit_log(actor_id, resource_modified);
+CREATE INDEX IF NOT EXISTS idx_cal_actor_type ON compliance_audit_log(actor_type, resource_modified);
+CREATE INDEX IF NOT EXISTS idx_cal_action ON compliance_audit_log(action, resource_modified);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Adds fields to transactions required to enforce data retention policies.
+BEGIN;
+
+-- Records when the PII of an expired transaction was removed and the encryption keys
+-- of its secure envelopes were destroyed (crypto-shredded).
+ALTER TABLE transactions ADD COLUMN shredded_on DATETIME DEFAULT NULL;
+
+COMMIT;
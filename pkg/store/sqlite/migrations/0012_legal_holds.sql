@@ -0,0 +1,46 @@
+-- Adds legal holds that prevent transactions, accounts, and counterparties from being
+-- deleted, archived, or purged by the data retention policies.
+BEGIN;
+
+-- A legal hold is applied to a single resource identified by its type and primary key;
+-- the resource_id is stored in the same representation as the primary key of the held
+-- resource (e.g. a UUID string for transactions and a ULID blob for accounts). Holds
+-- are not deleted when released so that the history of the hold is preserved.
+CREATE TABLE IF NOT EXISTS legal_holds (
+    id                  TEXT PRIMARY KEY,
+    resource_type       TEXT NOT NULL,
+    resource_id         BLOB NOT NULL,
+    reason              TEXT NOT NULL,
+    case_reference      TEXT,
+    applied_by          TEXT NOT NULL,
+    applied_by_id       BLOB,
+    applied_by_type     TEXT NOT NULL,
+    released_by         TEXT,
+    released_on         DATETIME DEFAULT NULL,
+    created             DATETIME NOT NULL,
+    modified            DATETIME NOT NULL
+);
+
+-- Holds are looked up by resource whenever a resource is deleted or archived.
+CREATE INDEX IF NOT EXISTS idx_legal_holds_resource ON legal_holds(resource_type, resource_id);
+
+-- Add the legal holds permissions and grant them to the default roles.
+INSERT INTO permissions (id, title, description, created, modified) VALUES
+    (18, 'legalholds:manage', 'Can apply, edit, and release legal holds on transactions, accounts, and counterparties', datetime('now'), datetime('now')),
+    (19, 'legalholds:view', 'Can view legal holds applied to transactions, accounts, and counterparties', datetime('now'), datetime('now'))
+;
+
+INSERT INTO role_permissions (role_id, permission_id, created, modified) VALUES
+    -- Admin Permissions
+    (1, 18, datetime('now'), datetime('now')),
+    (1, 19, datetime('now'), datetime('now')),
+
+    -- Compliance Permissions
+    (2, 18, datetime('now'), datetime('now')),
+    (2, 19, datetime('now'), datetime('now')),
+
+    -- Observer Permissions
+    (3, 19, datetime('now'), datetime('now'))
+;
+
+COMMIT;
@@ -0,0 +1,94 @@
+-- Adds blind index columns so that PII columns can be encrypted at rest while still
+-- supporting equality lookups, and stores the wrapped field encryption key. Existing
+-- rows are encrypted and indexed by the store when field encryption is configured.
+BEGIN;
+
+-- The field encryption data key wrapped by the node's storage key.
+CREATE TABLE IF NOT EXISTS data_keys (
+    id              TEXT PRIMARY KEY,
+    key_id          TEXT NOT NULL UNIQUE,
+    wrapped_key     BLOB NOT NULL,
+    signature       TEXT NOT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL
+);
+
+-- Encrypted crypto addresses are randomized so uniqueness is enforced on the blind
+-- index of the crypto address rather than on the crypto address itself.
+CREATE TABLE crypto_addresses_new (
+    id                  TEXT PRIMARY KEY,
+    account_id          TEXT NOT NULL,
+    crypto_address      TEXT NOT NULL,
+    network             TEXT NOT NULL,
+    asset_type          TEXT,
+    tag                 TEXT,
+    travel_address      TEXT UNIQUE,
+    created             DATETIME NOT NULL,
+    modified            DATETIME NOT NULL,
+    crypto_address_idx  TEXT UNIQUE,
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE CASCADE
+);
+
+INSERT INTO crypto_addresses_new SELECT id, account_id, crypto_address, network, asset_type, tag, travel_address, created, modified, NULL FROM crypto_addresses;
+DROP TABLE crypto_addresses;
+ALTER TABLE crypto_addresses_new RENAME TO crypto_addresses;
+
+-- Encrypted contact emails are unique by the blind index of the lowercased email.
+CREATE TABLE contacts_new (
+    id              TEXT PRIMARY KEY,
+    name            TEXT NOT NULL DEFAULT '',
+    email           TEXT NOT NULL,
+    role            TEXT NOT NULL DEFAULT '',
+    counterparty_id TEXT NOT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL,
+    email_idx       TEXT UNIQUE,
+    FOREIGN KEY (counterparty_id) REFERENCES counterparties(id) ON DELETE CASCADE
+);
+
+-- Sunrise messages reference contacts by the blind index of the email address since
+-- both emails are encrypted. The foreign key is updated when contacts_new is renamed
+-- and cascades when contacts are reindexed (e.g. when field encryption is enabled or
+-- the data encryption key is rotated).
+CREATE TABLE sunrise_new (
+    id              TEXT PRIMARY KEY,
+    envelope_id     TEXT NOT NULL,
+    email           TEXT NOT NULL,
+    expiration      DATETIME NOT NULL,
+    signature       BLOB DEFAULT NULL,
+    status          TEXT NOT NULL,
+    sent_on         DATETIME DEFAULT NULL,
+    verified_on     DATETIME DEFAULT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL,
+    email_idx       TEXT,
+    UNIQUE(envelope_id, email_idx),
+    FOREIGN KEY (envelope_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    FOREIGN KEY (email_idx) REFERENCES contacts_new(email_idx) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- Contact emails were previously unique by case-sensitive value but the blind index is
+-- computed from the lowercased email, so contacts with emails that differ only by case
+-- (and likewise the sunrise messages of a transaction) must be merged by an operator
+-- before this migration is applied. The store checks for these conflicts and lists
+-- them before applying the migration; the temporary unique indexes ensure that the
+-- migration fails rather than dropping or merging rows if any conflicts remain.
+INSERT INTO contacts_new SELECT id, name, email, role, counterparty_id, created, modified, NULL FROM contacts;
+INSERT INTO sunrise_new SELECT id, envelope_id, email, expiration, signature, status, sent_on, verified_on, created, modified, NULL FROM sunrise;
+CREATE UNIQUE INDEX idx_contacts_new_email_nocase ON contacts_new(lower(trim(email)));
+CREATE UNIQUE INDEX idx_sunrise_new_email_nocase ON sunrise_new(envelope_id, lower(trim(email)));
+DROP INDEX idx_contacts_new_email_nocase;
+DROP INDEX idx_sunrise_new_email_nocase;
+DROP TABLE sunrise;
+DROP TABLE contacts;
+ALTER TABLE contacts_new RENAME TO contacts;
+ALTER TABLE sunrise_new RENAME TO sunrise;
+
+-- Transaction wallet addresses are indexed so they can be joined against the blind
+-- index of the crypto addresses of local accounts.
+ALTER TABLE transactions ADD COLUMN originator_address_idx TEXT DEFAULT NULL;
+ALTER TABLE transactions ADD COLUMN beneficiary_address_idx TEXT DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_originator_address ON transactions(originator_address_idx);
+CREATE INDEX IF NOT EXISTS idx_transactions_beneficiary_address ON transactions(beneficiary_address_idx);
+
+COMMIT;
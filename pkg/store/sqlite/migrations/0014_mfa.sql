@@ -0,0 +1,30 @@
+-- Adds TOTP multi-factor authentication for users and a per-role MFA policy.
+BEGIN;
+
+-- If true, users with the role must enroll in MFA before they are granted permissions.
+ALTER TABLE roles ADD COLUMN require_mfa BOOLEAN DEFAULT false NOT NULL;
+
+-- The TOTP secret is set when enrollment starts and is encrypted if field encryption
+-- is enabled; mfa_enrolled is set once the user verifies a code from the secret.
+ALTER TABLE users ADD COLUMN mfa_secret TEXT DEFAULT NULL;
+ALTER TABLE users ADD COLUMN mfa_enrolled DATETIME DEFAULT NULL;
+
+-- The time step of the last accepted TOTP code so that codes cannot be replayed, and
+-- the count of consecutive failed codes used to lock out MFA verification.
+ALTER TABLE users ADD COLUMN mfa_last_step INTEGER DEFAULT NULL;
+ALTER TABLE users ADD COLUMN mfa_failures INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE users ADD COLUMN mfa_locked_until DATETIME DEFAULT NULL;
+
+-- Single-use recovery codes; only the SHA-256 hash of the code is stored.
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id              TEXT PRIMARY KEY,
+    user_id         TEXT NOT NULL,
+    code_hash       TEXT NOT NULL,
+    used_on         DATETIME DEFAULT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL,
+    UNIQUE (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+COMMIT;
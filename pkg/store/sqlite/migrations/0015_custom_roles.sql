@@ -0,0 +1,8 @@
+-- Allows roles to be created and managed by users; the roles created by the default
+-- roles migration are built-in and cannot be modified or deleted.
+BEGIN;
+
+ALTER TABLE roles ADD COLUMN is_builtin BOOLEAN DEFAULT false NOT NULL;
+UPDATE roles SET is_builtin=true WHERE id IN (1, 2, 3);
+
+COMMIT;
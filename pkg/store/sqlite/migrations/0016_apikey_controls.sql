@@ -0,0 +1,25 @@
+-- Adds expiration, network allowlists, secret rotation, and usage statistics to api keys.
+BEGIN;
+
+-- API keys cannot be used to authenticate after they expire; if allowed_networks is
+-- set (a JSON array of CIDRs) the key can only be used from those networks.
+ALTER TABLE api_keys ADD COLUMN expires DATETIME DEFAULT NULL;
+ALTER TABLE api_keys ADD COLUMN allowed_networks TEXT DEFAULT NULL;
+
+-- When the secret is rotated the previous secret remains valid for a grace period so
+-- that clients can be updated without downtime.
+ALTER TABLE api_keys ADD COLUMN previous_secret TEXT DEFAULT NULL;
+ALTER TABLE api_keys ADD COLUMN previous_secret_expires DATETIME DEFAULT NULL;
+
+-- Daily count of the requests made with an api key.
+CREATE TABLE IF NOT EXISTS api_key_usage (
+    api_key_id      TEXT NOT NULL,
+    day             DATE NOT NULL,
+    requests        INTEGER DEFAULT 0 NOT NULL,
+    last_ip         TEXT DEFAULT NULL,
+    last_used       DATETIME NOT NULL,
+    PRIMARY KEY (api_key_id, day),
+    FOREIGN KEY (api_key_id) REFERENCES api_keys (id) ON DELETE CASCADE
+);
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- Retains the identity of deleted users and api keys so that the actors of historical
+-- compliance audit log entries can still be resolved.
+BEGIN;
+
+-- Revoked users no longer have a password or MFA secret, only the identity record
+-- and the title of the role they had when they were revoked.
+CREATE TABLE IF NOT EXISTS revoked_users (
+    id              TEXT PRIMARY KEY,
+    name            TEXT,
+    email           TEXT NOT NULL,
+    role            TEXT NOT NULL,
+    last_login      DATETIME,
+    created         DATETIME NOT NULL,
+    revoked         DATETIME NOT NULL
+);
+
+-- Revoked api keys no longer have a secret; the client id remains unique so that it
+-- cannot be reused by another api key.
+CREATE TABLE IF NOT EXISTS revoked_api_keys (
+    id              TEXT PRIMARY KEY,
+    description     TEXT,
+    client_id       TEXT NOT NULL UNIQUE,
+    last_seen       DATETIME,
+    created         DATETIME NOT NULL,
+    revoked         DATETIME NOT NULL
+);
+
+COMMIT;
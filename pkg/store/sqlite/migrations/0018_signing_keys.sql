@@ -0,0 +1,20 @@
+-- Stores the keys used to sign access and refresh tokens so that every replica of the
+-- node signs and verifies tokens with the same keys and so that keys can be rotated.
+BEGIN;
+
+-- The private key is PEM encoded and encrypted with the field encryption key. A key
+-- is used to sign tokens between not_before and not_after and is published so that
+-- tokens it signed can be verified until it expires.
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id              TEXT PRIMARY KEY,
+    private_key     BLOB NOT NULL,
+    not_before      DATETIME NOT NULL,
+    not_after       DATETIME NOT NULL,
+    expires         DATETIME NOT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_signing_keys_expires ON signing_keys(expires);
+
+COMMIT;
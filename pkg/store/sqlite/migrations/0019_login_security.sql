@@ -0,0 +1,27 @@
+-- Adds progressive lockout after failed logins and the active sessions of users.
+BEGIN;
+
+-- The count of consecutive failed logins and the number of times the user has been
+-- locked out since their last successful login; the lockout doubles each time.
+ALTER TABLE users ADD COLUMN login_failures INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE users ADD COLUMN login_lockouts INTEGER DEFAULT 0 NOT NULL;
+ALTER TABLE users ADD COLUMN login_locked_until DATETIME DEFAULT NULL;
+
+-- A session is created when a user logs in and is identified by the sid claim of the
+-- tokens issued to the user; refresh tokens cannot be used once the session is revoked.
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id              TEXT PRIMARY KEY,
+    user_id         TEXT NOT NULL,
+    user_agent      TEXT,
+    ip_address      TEXT,
+    sso             BOOLEAN DEFAULT false NOT NULL,
+    last_used       DATETIME NOT NULL,
+    expires         DATETIME NOT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions (user_id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Allows administrators to invite users by email so that the invitee chooses their
+-- own password rather than having a password sent to them out-of-band.
+BEGIN;
+
+-- A pending invitation for a user who does not yet have an account; the invite is
+-- deleted when it is accepted and the user is created with the role of the invite.
+CREATE TABLE IF NOT EXISTS user_invites (
+    id              TEXT PRIMARY KEY,
+    email           TEXT NOT NULL UNIQUE,
+    name            TEXT,
+    role_id         INTEGER NOT NULL,
+    expiration      DATETIME NOT NULL,
+    signature       BLOB DEFAULT NULL,
+    sent_on         DATETIME DEFAULT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL,
+    FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE
+);
+
+COMMIT;
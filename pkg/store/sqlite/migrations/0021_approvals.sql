@@ -0,0 +1,67 @@
+-- Adds four-eyes (maker-checker) approvals so that outgoing transfers and decisions on
+-- incoming transfers above configured thresholds require review by a second user.
+BEGIN;
+
+-- Approval rules define the thresholds above which an action requires approval; a
+-- NULL action, virtual asset, minimum amount, or counterparty matches any value.
+CREATE TABLE IF NOT EXISTS approval_rules (
+    id                  TEXT PRIMARY KEY,
+    description         TEXT NOT NULL,
+    action              TEXT DEFAULT NULL,
+    virtual_asset       TEXT DEFAULT NULL,
+    min_amount          REAL DEFAULT NULL,
+    counterparty_id     TEXT DEFAULT NULL,
+    created             DATETIME NOT NULL,
+    modified            DATETIME NOT NULL,
+    FOREIGN KEY (counterparty_id) REFERENCES counterparties(id) ON DELETE CASCADE
+);
+
+-- An approval holds the original request of an action until it is approved (and the
+-- action is executed), denied, or canceled. Approvals are not deleted so that the
+-- history of the review is preserved. The transaction is NULL for outgoing transfers
+-- since the transaction is only created when the transfer is sent.
+CREATE TABLE IF NOT EXISTS approvals (
+    id                  TEXT PRIMARY KEY,
+    action              TEXT NOT NULL,
+    status              TEXT NOT NULL,
+    rule_id             TEXT DEFAULT NULL,
+    transaction_id      TEXT DEFAULT NULL,
+    counterparty        TEXT,
+    counterparty_id     TEXT DEFAULT NULL,
+    virtual_asset       TEXT,
+    amount              REAL,
+    request             BLOB NOT NULL,
+    requested_by        TEXT NOT NULL,
+    requested_by_id     BLOB NOT NULL,
+    requested_by_type   TEXT NOT NULL,
+    reviewed_by         TEXT,
+    reviewed_by_id      BLOB DEFAULT NULL,
+    review_notes        TEXT,
+    reviewed_on         DATETIME DEFAULT NULL,
+    created             DATETIME NOT NULL,
+    modified            DATETIME NOT NULL,
+    FOREIGN KEY (rule_id) REFERENCES approval_rules(id) ON DELETE SET NULL,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    FOREIGN KEY (counterparty_id) REFERENCES counterparties(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_approvals_status ON approvals(status);
+CREATE INDEX IF NOT EXISTS idx_approvals_transaction ON approvals(transaction_id);
+
+-- Add the approvals permissions; the compliance role can review approvals but only
+-- administrators can manage the approval rules.
+INSERT INTO permissions (id, title, description, created, modified) VALUES
+    (20, 'approvals:manage', 'Can create, edit, and delete the rules that require four-eyes approval of transfers', datetime('now'), datetime('now')),
+    (21, 'approvals:review', 'Can approve or deny transfer actions requested by other users', datetime('now'), datetime('now'))
+;
+
+INSERT INTO role_permissions (role_id, permission_id, created, modified) VALUES
+    -- Admin Permissions
+    (1, 20, datetime('now'), datetime('now')),
+    (1, 21, datetime('now'), datetime('now')),
+
+    -- Compliance Permissions
+    (2, 21, datetime('now'), datetime('now'))
+;
+
+COMMIT;
@@ -0,0 +1,42 @@
+-- Adds investigator case management to transactions: a thread of notes, assignment of
+-- the transaction to a user, and free-form tags that can be used to filter transactions.
+BEGIN;
+
+-- The user that is responsible for reviewing the transaction, if any.
+ALTER TABLE transactions ADD COLUMN assignee_id TEXT DEFAULT NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_assignee ON transactions(assignee_id);
+
+-- Notes are posted by users or api keys and cannot be modified once posted so that the
+-- thread is a reliable record of the review; they are deleted with the transaction.
+CREATE TABLE IF NOT EXISTS transaction_notes (
+    id              TEXT PRIMARY KEY,
+    transaction_id  TEXT NOT NULL,
+    body            TEXT NOT NULL,
+    author          TEXT NOT NULL,
+    author_id       BLOB NOT NULL,
+    author_type     TEXT NOT NULL,
+    created         DATETIME NOT NULL,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_notes_transaction ON transaction_notes(transaction_id);
+
+CREATE TRIGGER IF NOT EXISTS transaction_notes_immutable
+    BEFORE UPDATE ON transaction_notes
+BEGIN
+    SELECT RAISE(ABORT, 'transaction notes cannot be modified');
+END;
+
+-- Tags are normalized to lowercase before they are stored.
+CREATE TABLE IF NOT EXISTS transaction_tags (
+    transaction_id  TEXT NOT NULL,
+    tag             TEXT NOT NULL,
+    created         DATETIME NOT NULL,
+    PRIMARY KEY (transaction_id, tag),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag ON transaction_tags(tag);
+
+COMMIT;
@@ -0,0 +1,18 @@
+-- Tracks the reply-by deadline of transactions that are waiting for a response from
+-- either the local compliance team or the counterparty so that reminders can be sent
+-- as the deadline approaches and overdue transfers can be expired.
+BEGIN;
+
+-- The reply-not-after timestamp of the most recent pending message; cleared when a
+-- message without a deadline is exchanged.
+ALTER TABLE transactions ADD COLUMN reply_not_after DATETIME DEFAULT NULL;
+
+-- When the reminder for the current deadline was sent, if any.
+ALTER TABLE transactions ADD COLUMN reply_reminded_on DATETIME DEFAULT NULL;
+
+-- When the transaction was expired because the current deadline passed, if ever.
+ALTER TABLE transactions ADD COLUMN reply_expired_on DATETIME DEFAULT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_reply_not_after ON transactions(reply_not_after);
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Allows compliance officers to customize the pending response that is sent to a
+-- counterparty when an incoming travel rule request must be reviewed. Any override
+-- that is NULL uses the pending configuration of the node.
+BEGIN;
+
+-- The language tag used to select a localized pending message, e.g. de or fr-CA.
+ALTER TABLE counterparties ADD COLUMN pending_language TEXT DEFAULT NULL;
+
+-- A message template that replaces the configured pending message.
+ALTER TABLE counterparties ADD COLUMN pending_message TEXT DEFAULT NULL;
+
+-- The reply windows in seconds after the request is received.
+ALTER TABLE counterparties ADD COLUMN reply_not_before INTEGER DEFAULT NULL;
+ALTER TABLE counterparties ADD COLUMN reply_not_after INTEGER DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- Records every change of the status of a transaction so that the review of a transfer
+-- can be reconstructed as a timeline: the status it moved from and to, the actor that
+-- caused the change, and the secure envelope that was exchanged, if any.
+BEGIN;
+
+-- Status history cannot be modified once recorded; it is deleted with the transaction.
+-- The envelope is cleared if the secure envelope is deleted by the retention policy.
+CREATE TABLE IF NOT EXISTS transaction_status_history (
+    id              TEXT PRIMARY KEY,
+    transaction_id  TEXT NOT NULL,
+    from_status     TEXT NOT NULL,
+    to_status       TEXT NOT NULL,
+    actor_id        BLOB,
+    actor_type      TEXT NOT NULL,
+    envelope_id     TEXT DEFAULT NULL,
+    created         DATETIME NOT NULL,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    FOREIGN KEY (envelope_id) REFERENCES secure_envelopes(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_status_history_transaction ON transaction_status_history(transaction_id);
+
+CREATE TRIGGER IF NOT EXISTS transaction_status_history_immutable
+    BEFORE UPDATE OF transaction_id, from_status, to_status, actor_id, actor_type, created ON transaction_status_history
+BEGIN
+    SELECT RAISE(ABORT, 'transaction status history cannot be modified');
+END;
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- Adds the outbound queue so that transfers that could not be delivered because the
+-- counterparty was unreachable (or that are scheduled to be sent later) are retried
+-- in the background instead of requiring the user to send them again.
+BEGIN;
+
+-- The payload of a queued transfer is stored as a secure envelope sealed with the
+-- storage key of the local node so that it is not stored in plaintext. Queued
+-- transfers are not deleted when they are sent or canceled so that the history of
+-- the delivery attempts is preserved with the transaction.
+CREATE TABLE IF NOT EXISTS queued_transfers (
+    id              TEXT PRIMARY KEY,
+    transaction_id  TEXT NOT NULL,
+    counterparty_id TEXT NOT NULL,
+    status          TEXT NOT NULL,
+    envelope        BLOB NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT DEFAULT NULL,
+    last_attempt    DATETIME DEFAULT NULL,
+    next_attempt    DATETIME NOT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE,
+    FOREIGN KEY (counterparty_id) REFERENCES counterparties(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_queued_transfers_status ON queued_transfers(status, next_attempt);
+CREATE INDEX IF NOT EXISTS idx_queued_transfers_transaction ON queued_transfers(transaction_id);
+
+COMMIT;
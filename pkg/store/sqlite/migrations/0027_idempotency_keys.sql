@@ -0,0 +1,23 @@
+-- Stores the responses to requests made with an Idempotency-Key header so that a client
+-- that retries a request (e.g. after a timeout) receives the original response instead
+-- of creating or sending a transaction twice.
+BEGIN;
+
+-- Keys are scoped to the actor (user or api key) that made the request so that keys
+-- generated by different clients cannot collide. The request hash is used to detect a
+-- key that is reused with a different request.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key             TEXT NOT NULL,
+    actor_id        BLOB NOT NULL,
+    request_hash    BLOB NOT NULL,
+    status          INTEGER NOT NULL,
+    content_type    TEXT NOT NULL DEFAULT '',
+    response        BLOB,
+    expires         DATETIME NOT NULL,
+    created         DATETIME NOT NULL,
+    PRIMARY KEY (actor_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires);
+
+COMMIT;
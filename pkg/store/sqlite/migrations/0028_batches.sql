@@ -0,0 +1,35 @@
+-- Adds batches of transfers that are imported from a CSV or JSON Lines file so that
+-- many withdrawals can be validated and sent together rather than one at a time.
+BEGIN;
+
+-- The progress of a batch is summarized from the status of its items.
+CREATE TABLE IF NOT EXISTS batches (
+    id              TEXT PRIMARY KEY,
+    filename        TEXT NOT NULL DEFAULT '',
+    format          TEXT NOT NULL,
+    status          TEXT NOT NULL,
+    created_by      TEXT NOT NULL DEFAULT '',
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL
+);
+
+-- Each item is a row of the imported file. The request is the prepare transaction
+-- JSON of the row and contains PII so it is encrypted if field encryption is enabled.
+-- The transaction is set when the item is sent (or queued or held for approval).
+CREATE TABLE IF NOT EXISTS batch_items (
+    id              TEXT PRIMARY KEY,
+    batch_id        TEXT NOT NULL,
+    row             INTEGER NOT NULL,
+    status          TEXT NOT NULL,
+    request         TEXT,
+    errors          BLOB,
+    transaction_id  TEXT DEFAULT NULL,
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL,
+    FOREIGN KEY (batch_id) REFERENCES batches(id) ON DELETE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_items_batch ON batch_items(batch_id, row);
+
+COMMIT;
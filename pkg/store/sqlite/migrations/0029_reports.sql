@@ -0,0 +1,22 @@
+-- Adds regulatory reports that summarize the travel rule transfers of a period so
+-- that they can be downloaded later or emailed to compliance officers on a schedule.
+BEGIN;
+
+-- The data of a report is the JSON of the computed statistics; it is rendered as CSV,
+-- JSON, or HTML when the report is downloaded. Reports generated by the scheduler
+-- have a schedule and only one report is generated for each scheduled period.
+CREATE TABLE IF NOT EXISTS reports (
+    id              TEXT PRIMARY KEY,
+    name            TEXT NOT NULL,
+    schedule        TEXT DEFAULT NULL,
+    period_start    DATETIME NOT NULL,
+    period_end      DATETIME NOT NULL,
+    data            BLOB NOT NULL,
+    created_by      TEXT NOT NULL DEFAULT '',
+    created         DATETIME NOT NULL,
+    modified        DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reports_schedule ON reports(schedule, period_start) WHERE schedule IS NOT NULL;
+
+COMMIT;
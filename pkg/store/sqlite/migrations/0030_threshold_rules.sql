@@ -0,0 +1,34 @@
+-- Adds jurisdiction-aware travel rule thresholds so that transfers below the threshold
+-- of the originator or beneficiary jurisdiction are not required to include complete
+-- originator and beneficiary data.
+BEGIN;
+
+-- Threshold rules define the fiat-equivalent amount at or above which travel rule data
+-- is required; a NULL originator country, beneficiary country, or virtual asset
+-- matches any value. Thresholds are expressed in the configured fiat currency.
+CREATE TABLE IF NOT EXISTS threshold_rules (
+    id                  TEXT PRIMARY KEY,
+    description         TEXT NOT NULL,
+    originator_country  TEXT DEFAULT NULL,
+    beneficiary_country TEXT DEFAULT NULL,
+    virtual_asset       TEXT DEFAULT NULL,
+    threshold           REAL NOT NULL DEFAULT 0,
+    created             DATETIME NOT NULL,
+    modified            DATETIME NOT NULL
+);
+
+-- If the threshold rules required travel rule data for the transaction; NULL if the
+-- transaction was not evaluated (e.g. thresholds were not enabled).
+ALTER TABLE transactions ADD COLUMN travel_rule_required BOOLEAN DEFAULT NULL;
+
+-- The fiat-equivalent amount of the transfer that was compared to the threshold.
+ALTER TABLE transactions ADD COLUMN fiat_amount REAL DEFAULT NULL;
+
+-- The fiat currency of the fiat-equivalent amount.
+ALTER TABLE transactions ADD COLUMN fiat_currency TEXT DEFAULT NULL;
+
+-- The threshold rule that determined if travel rule data is required; NULL if no rule
+-- matched the transfer or if the rule was deleted.
+ALTER TABLE transactions ADD COLUMN threshold_rule_id TEXT DEFAULT NULL REFERENCES threshold_rules(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Records when the secret of an api key was last rotated so that refresh tokens that
+-- were issued before the rotation cannot be used to obtain new access tokens.
+BEGIN;
+
+ALTER TABLE api_keys ADD COLUMN secret_rotated DATETIME DEFAULT NULL;
+
+COMMIT;
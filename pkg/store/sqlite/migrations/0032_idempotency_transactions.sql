@@ -0,0 +1,13 @@
+-- Links stored idempotent responses to the transaction they describe so that they are
+-- deleted when the transaction is purged or crypto-shredded. Responses are now stored
+-- encrypted with the field cipher, so any responses that were stored in plaintext are
+-- deleted; clients retrying those requests will receive a new response.
+BEGIN;
+
+DELETE FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys ADD COLUMN transaction_id TEXT DEFAULT NULL REFERENCES transactions(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_transaction ON idempotency_keys(transaction_id);
+
+COMMIT;
@@ -0,0 +1,837 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
+
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	"go.rtnl.ai/ulid"
+)
+
+// Encrypted fields of the models. Decryption is matched to the scan destination of the
+// field rather than to the position of its column so that the select lists of the
+// queries and the Scan methods of the models can change independently.
+func encryptedAccountFields(a *models.Account) []any {
+	return []any{&a.CustomerID, &a.FirstName, &a.LastName, &a.IVMSRecord}
+}
+
+func encryptedAccountSummaryFields(a *models.Account) []any {
+	return []any{&a.CustomerID, &a.FirstName, &a.LastName}
+}
+
+func encryptedCryptoAddressFields(a *models.CryptoAddress) []any {
+	return []any{&a.CryptoAddress}
+}
+
+func encryptedContactFields(c *models.Contact) []any {
+	return []any{&c.Name, &c.Email}
+}
+
+func encryptedSunriseFields(s *models.Sunrise) []any {
+	return []any{&s.Email}
+}
+
+func encryptedTransactionFields(t *models.Transaction) []any {
+	return []any{&t.Originator, &t.OriginatorAddress, &t.Beneficiary, &t.BeneficiaryAddress}
+}
+
+func encryptedUserFields(u *models.User) []any {
+	return []any{&u.MFASecret}
+}
+
+func encryptedBatchItemFields(i *models.BatchItem) []any {
+	return []any{&i.Request}
+}
+
+// Encrypted PII columns by table, used to encrypt existing rows.
+const (
+	fieldEncryptionAccountsSQL          = "SELECT id, customer_id, first_name, last_name, ivms101 FROM accounts"
+	fieldEncryptionUpdateAccountSQL     = "UPDATE accounts SET customer_id=:customerID, first_name=:firstName, last_name=:lastName, ivms101=:ivms101 WHERE id=:id"
+	fieldEncryptionAddressesSQL         = "SELECT id, crypto_address, crypto_address_idx FROM crypto_addresses"
+	fieldEncryptionUpdateAddressSQL     = "UPDATE crypto_addresses SET crypto_address=:cryptoAddress, crypto_address_idx=:cryptoAddressIdx WHERE id=:id"
+	fieldEncryptionContactsSQL          = "SELECT id, name, email, email_idx FROM contacts"
+	fieldEncryptionUpdateContactSQL     = "UPDATE contacts SET name=:name, email=:email, email_idx=:emailIdx WHERE id=:id"
+	fieldEncryptionSunriseSQL           = "SELECT id, email, email_idx FROM sunrise"
+	fieldEncryptionUpdateSunriseSQL     = "UPDATE sunrise SET email=:email, email_idx=:emailIdx WHERE id=:id"
+	fieldEncryptionUsersSQL             = "SELECT id, mfa_secret FROM users WHERE mfa_secret IS NOT NULL"
+	fieldEncryptionUpdateUserSQL        = "UPDATE users SET mfa_secret=:mfaSecret WHERE id=:id"
+	fieldEncryptionBatchItemsSQL        = "SELECT id, request FROM batch_items WHERE request IS NOT NULL"
+	fieldEncryptionUpdateBatchItemSQL   = "UPDATE batch_items SET request=:request WHERE id=:id"
+	fieldEncryptionIdempotencyKeysSQL   = "SELECT actor_id, key, response FROM idempotency_keys WHERE response IS NOT NULL"
+	fieldEncryptionUpdateIdempotencySQL = "UPDATE idempotency_keys SET response=:response WHERE actor_id=:actorID AND key=:key"
+	fieldEncryptionTransactionsSQL      = "SELECT id, originator, originator_address, originator_address_idx, beneficiary, beneficiary_address, beneficiary_address_idx FROM transactions"
+	fieldEncryptionUpdateTransactionSQL = "UPDATE transactions SET originator=:originator, originator_address=:originatorAddress, originator_address_idx=:originatorAddressIdx, beneficiary=:beneficiary, beneficiary_address=:beneficiaryAddress, beneficiary_address_idx=:beneficiaryAddressIdx WHERE id=:id"
+)
+
+// UseFieldEncryption configures the cipher used to encrypt PII columns and to compute
+// blind indexes. Any rows that are not encrypted with the cipher's key (e.g. rows that
+// were created before field encryption was enabled) are encrypted and reindexed in a
+// single transaction. Rows encrypted with a different key can only be reencrypted if
+// that key has been added to the cipher as a previous key, otherwise an error is
+// returned and no rows are modified. A nil cipher stores PII in plaintext but still
+// maintains the blind indexes; it will return an error if the database contains
+// encrypted values.
+func (s *Store) UseFieldEncryption(cipher *pii.Cipher) (err error) {
+	s.cipher = cipher
+	if s.readonly {
+		return nil
+	}
+
+	var tx *Tx
+	if tx, err = s.BeginTx(context.Background(), nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var nRows int64
+	if nRows, err = tx.encryptExistingRows(); err != nil {
+		return fmt.Errorf("could not encrypt existing rows: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if nRows > 0 {
+		log.Info().Int64("rows", nRows).Str("key_id", cipher.KeyID()).Msg("encrypted existing pii fields")
+	}
+	return nil
+}
+
+// Encrypts and reindexes all rows whose PII columns are not encrypted with the current
+// key or whose blind indexes are stale; returns the number of rows updated.
+func (t *Tx) encryptExistingRows() (nRows int64, err error) {
+	var n int64
+	for _, migrate := range []func() (int64, error){
+		t.encryptExistingAccounts,
+		t.encryptExistingCryptoAddresses,
+		t.encryptExistingContacts,
+		t.encryptExistingSunrise,
+		t.encryptExistingTransactions,
+		t.encryptExistingUsers,
+		t.encryptExistingBatchItems,
+		t.encryptExistingIdempotencyKeys,
+	} {
+		if n, err = migrate(); err != nil {
+			return nRows, err
+		}
+		nRows += n
+	}
+	return nRows, nil
+}
+
+func (t *Tx) encryptExistingAccounts() (nRows int64, err error) {
+	type account struct {
+		id     ulid.ULID
+		fields [4]sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionAccountsSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	stale := make([]*account, 0)
+	for rows.Next() {
+		a := &account{}
+		if err = rows.Scan(&a.id, &a.fields[0], &a.fields[1], &a.fields[2], &a.fields[3]); err != nil {
+			return 0, err
+		}
+
+		for i, field := range a.fields {
+			// Null IVMS101 records are not encrypted so that they can be summarized.
+			if i == 3 && field.String == ivmsNull {
+				continue
+			}
+
+			if !t.current(field) {
+				stale = append(stale, a)
+				break
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, a := range stale {
+		for i, field := range a.fields {
+			if i == 3 && field.String == ivmsNull {
+				continue
+			}
+
+			if a.fields[i], err = t.reencrypt(field); err != nil {
+				return 0, fmt.Errorf("account %s: %w", a.id, err)
+			}
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateAccountSQL,
+			sql.Named("id", a.id),
+			sql.Named("customerID", a.fields[0]),
+			sql.Named("firstName", a.fields[1]),
+			sql.Named("lastName", a.fields[2]),
+			sql.Named("ivms101", nullBytes(a.fields[3])),
+		); err != nil {
+			return 0, dbe(err)
+		}
+	}
+
+	return int64(len(stale)), nil
+}
+
+func (t *Tx) encryptExistingCryptoAddresses() (nRows int64, err error) {
+	type address struct {
+		id      ulid.ULID
+		address sql.NullString
+		index   sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionAddressesSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	addresses := make([]*address, 0)
+	for rows.Next() {
+		a := &address{}
+		if err = rows.Scan(&a.id, &a.address, &a.index); err != nil {
+			return 0, err
+		}
+		addresses = append(addresses, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, a := range addresses {
+		var plaintext string
+		if plaintext, err = t.cipher.DecryptString(a.address.String); err != nil {
+			return 0, fmt.Errorf("crypto address %s: %w", a.id, err)
+		}
+
+		index := t.cipher.BlindIndex(plaintext)
+		if t.current(a.address) && a.index.String == index {
+			continue
+		}
+
+		var ciphertext string
+		if ciphertext, err = t.cipher.EncryptString(plaintext); err != nil {
+			return 0, err
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateAddressSQL,
+			sql.Named("id", a.id),
+			sql.Named("cryptoAddress", ciphertext),
+			sql.Named("cryptoAddressIdx", index),
+		); err != nil {
+			return 0, dbe(err)
+		}
+		nRows++
+	}
+
+	return nRows, nil
+}
+
+func (t *Tx) encryptExistingContacts() (nRows int64, err error) {
+	type contact struct {
+		id    ulid.ULID
+		name  sql.NullString
+		email sql.NullString
+		index sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionContactsSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	contacts := make([]*contact, 0)
+	for rows.Next() {
+		c := &contact{}
+		if err = rows.Scan(&c.id, &c.name, &c.email, &c.index); err != nil {
+			return 0, err
+		}
+		contacts = append(contacts, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, c := range contacts {
+		var email string
+		if email, err = t.cipher.DecryptString(c.email.String); err != nil {
+			return 0, fmt.Errorf("contact %s: %w", c.id, err)
+		}
+
+		index := t.emailIndex(email)
+		if t.current(c.name) && t.current(c.email) && c.index.String == index {
+			continue
+		}
+
+		if c.name, err = t.reencrypt(c.name); err != nil {
+			return 0, fmt.Errorf("contact %s: %w", c.id, err)
+		}
+
+		if c.email.String, err = t.cipher.EncryptString(email); err != nil {
+			return 0, err
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateContactSQL,
+			sql.Named("id", c.id),
+			sql.Named("name", c.name.String),
+			sql.Named("email", c.email.String),
+			sql.Named("emailIdx", index),
+		); err != nil {
+			return 0, dbe(err)
+		}
+		nRows++
+	}
+
+	return nRows, nil
+}
+
+// Sunrise emails are indexed to reference the contact the message was sent to; the
+// index is updated by the foreign key when the contact is reindexed.
+func (t *Tx) encryptExistingSunrise() (nRows int64, err error) {
+	type sunrise struct {
+		id    ulid.ULID
+		email sql.NullString
+		index sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionSunriseSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	messages := make([]*sunrise, 0)
+	for rows.Next() {
+		msg := &sunrise{}
+		if err = rows.Scan(&msg.id, &msg.email, &msg.index); err != nil {
+			return 0, err
+		}
+		messages = append(messages, msg)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, msg := range messages {
+		var email string
+		if email, err = t.cipher.DecryptString(msg.email.String); err != nil {
+			return 0, fmt.Errorf("sunrise %s: %w", msg.id, err)
+		}
+
+		index := t.emailIndex(email)
+		if t.current(msg.email) && msg.index.String == index {
+			continue
+		}
+
+		if msg.email.String, err = t.cipher.EncryptString(email); err != nil {
+			return 0, err
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateSunriseSQL,
+			sql.Named("id", msg.id),
+			sql.Named("email", msg.email.String),
+			sql.Named("emailIdx", index),
+		); err != nil {
+			return 0, dbe(err)
+		}
+		nRows++
+	}
+
+	return nRows, nil
+}
+
+func (t *Tx) encryptExistingTransactions() (nRows int64, err error) {
+	type transaction struct {
+		id                 string
+		originator         sql.NullString
+		originatorAddress  sql.NullString
+		originatorIndex    sql.NullString
+		beneficiary        sql.NullString
+		beneficiaryAddress sql.NullString
+		beneficiaryIndex   sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionTransactionsSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	transactions := make([]*transaction, 0)
+	for rows.Next() {
+		tx := &transaction{}
+		if err = rows.Scan(&tx.id, &tx.originator, &tx.originatorAddress, &tx.originatorIndex, &tx.beneficiary, &tx.beneficiaryAddress, &tx.beneficiaryIndex); err != nil {
+			return 0, err
+		}
+		transactions = append(transactions, tx)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, tx := range transactions {
+		// The wallet addresses must be decrypted to compute their blind indexes.
+		var originatorAddress, beneficiaryAddress sql.NullString
+		if originatorAddress, err = t.decryptValue(tx.originatorAddress); err != nil {
+			return 0, fmt.Errorf("transaction %s: %w", tx.id, err)
+		}
+
+		if beneficiaryAddress, err = t.decryptValue(tx.beneficiaryAddress); err != nil {
+			return 0, fmt.Errorf("transaction %s: %w", tx.id, err)
+		}
+
+		originatorIndex := t.addressIndex(originatorAddress)
+		beneficiaryIndex := t.addressIndex(beneficiaryAddress)
+
+		if t.current(tx.originator) && t.current(tx.originatorAddress) && t.current(tx.beneficiary) && t.current(tx.beneficiaryAddress) &&
+			tx.originatorIndex == originatorIndex && tx.beneficiaryIndex == beneficiaryIndex {
+			continue
+		}
+
+		for _, field := range []*sql.NullString{&tx.originator, &tx.originatorAddress, &tx.beneficiary, &tx.beneficiaryAddress} {
+			if *field, err = t.reencrypt(*field); err != nil {
+				return 0, fmt.Errorf("transaction %s: %w", tx.id, err)
+			}
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateTransactionSQL,
+			sql.Named("id", tx.id),
+			sql.Named("originator", tx.originator),
+			sql.Named("originatorAddress", tx.originatorAddress),
+			sql.Named("originatorAddressIdx", originatorIndex),
+			sql.Named("beneficiary", tx.beneficiary),
+			sql.Named("beneficiaryAddress", tx.beneficiaryAddress),
+			sql.Named("beneficiaryAddressIdx", beneficiaryIndex),
+		); err != nil {
+			return 0, dbe(err)
+		}
+		nRows++
+	}
+
+	return nRows, nil
+}
+
+// MFA secrets are the only PII of users that is encrypted.
+func (t *Tx) encryptExistingUsers() (nRows int64, err error) {
+	type user struct {
+		id     ulid.ULID
+		secret sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionUsersSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	stale := make([]*user, 0)
+	for rows.Next() {
+		u := &user{}
+		if err = rows.Scan(&u.id, &u.secret); err != nil {
+			return 0, err
+		}
+
+		if !t.current(u.secret) {
+			stale = append(stale, u)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, u := range stale {
+		if u.secret, err = t.reencrypt(u.secret); err != nil {
+			return 0, fmt.Errorf("user %s: %w", u.id, err)
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateUserSQL, sql.Named("id", u.id), sql.Named("mfaSecret", u.secret)); err != nil {
+			return 0, dbe(err)
+		}
+	}
+
+	return int64(len(stale)), nil
+}
+
+// The requests of batch items contain the originator and beneficiary of the transfer.
+func (t *Tx) encryptExistingBatchItems() (nRows int64, err error) {
+	type item struct {
+		id      ulid.ULID
+		request sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionBatchItemsSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	stale := make([]*item, 0)
+	for rows.Next() {
+		i := &item{}
+		if err = rows.Scan(&i.id, &i.request); err != nil {
+			return 0, err
+		}
+
+		if !t.current(i.request) {
+			stale = append(stale, i)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, i := range stale {
+		if i.request, err = t.reencrypt(i.request); err != nil {
+			return 0, fmt.Errorf("batch item %s: %w", i.id, err)
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateBatchItemSQL, sql.Named("id", i.id), sql.Named("request", i.request)); err != nil {
+			return 0, dbe(err)
+		}
+	}
+
+	return int64(len(stale)), nil
+}
+
+func (t *Tx) encryptExistingIdempotencyKeys() (nRows int64, err error) {
+	type idempotencyKey struct {
+		actorID  []byte
+		key      string
+		response sql.NullString
+	}
+
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(fieldEncryptionIdempotencyKeysSQL); err != nil {
+		return 0, dbe(err)
+	}
+	defer rows.Close()
+
+	stale := make([]*idempotencyKey, 0)
+	for rows.Next() {
+		k := &idempotencyKey{}
+		if err = rows.Scan(&k.actorID, &k.key, &k.response); err != nil {
+			return 0, err
+		}
+
+		if !t.current(k.response) {
+			stale = append(stale, k)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, dbe(err)
+	}
+	rows.Close()
+
+	for _, k := range stale {
+		if k.response, err = t.reencrypt(k.response); err != nil {
+			return 0, fmt.Errorf("idempotency key %s: %w", k.key, err)
+		}
+
+		params := []any{
+			sql.Named("actorID", k.actorID),
+			sql.Named("key", k.key),
+			sql.Named("response", []byte(k.response.String)),
+		}
+
+		if _, err = t.tx.Exec(fieldEncryptionUpdateIdempotencySQL, params...); err != nil {
+			return 0, dbe(err)
+		}
+	}
+
+	return int64(len(stale)), nil
+}
+
+//===========================================================================
+// Data Keys
+//===========================================================================
+
+const (
+	retrieveDataKeySQL = "SELECT * FROM data_keys ORDER BY created DESC LIMIT 1"
+	createDataKeySQL   = "INSERT INTO data_keys (id, key_id, wrapped_key, signature, created, modified) VALUES (:id, :keyID, :wrappedKey, :signature, :created, :modified)"
+)
+
+// Retrieve the most recent wrapped field encryption key.
+func (s *Store) RetrieveDataKey(ctx context.Context) (key *models.DataKey, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	key = &models.DataKey{}
+	if err = key.Scan(tx.tx.QueryRow(retrieveDataKeySQL)); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Store a wrapped field encryption key.
+func (s *Store) CreateDataKey(ctx context.Context, key *models.DataKey) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	key.ID = ulid.MakeSecure()
+	key.Created = time.Now()
+	key.Modified = key.Created
+
+	if _, err = tx.tx.Exec(createDataKeySQL, key.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	return tx.Commit()
+}
+
+//===========================================================================
+// Encryption Helpers
+//===========================================================================
+
+// JSON encoding of a nil IVMS101 record; stored unencrypted so that accounts without
+// an IVMS101 record can be identified without decrypting the record.
+const ivmsNull = "null"
+
+// Returns true if the value does not need to be reencrypted.
+func (t *Tx) current(value sql.NullString) bool {
+	return !value.Valid || t.cipher.Current([]byte(value.String))
+}
+
+// Decrypts the value if it is encrypted.
+func (t *Tx) decryptValue(value sql.NullString) (_ sql.NullString, err error) {
+	if !value.Valid {
+		return value, nil
+	}
+
+	value.String, err = t.cipher.DecryptString(value.String)
+	return value, err
+}
+
+// Decrypts the value if necessary and reencrypts it with the current key.
+func (t *Tx) reencrypt(value sql.NullString) (_ sql.NullString, err error) {
+	if !value.Valid {
+		return value, nil
+	}
+
+	if value.String, err = t.cipher.DecryptString(value.String); err != nil {
+		return value, err
+	}
+
+	if value.String, err = t.cipher.EncryptString(value.String); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// Blind index of a contact's email address; email addresses are case-insensitive.
+func (t *Tx) emailIndex(email string) string {
+	return t.cipher.BlindIndex(strings.ToLower(strings.TrimSpace(email)))
+}
+
+// Blind index of a wallet address on a transaction; null if there is no address.
+func (t *Tx) addressIndex(address sql.NullString) sql.NullString {
+	if !address.Valid || address.String == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{Valid: true, String: t.cipher.BlindIndex(address.String)}
+}
+
+// Replaces the values of the named parameters with their encrypted values.
+func (t *Tx) encryptParams(params []any, names ...string) (_ []any, err error) {
+	for i, param := range params {
+		arg, ok := param.(sql.NamedArg)
+		if !ok || !slices.Contains(names, arg.Name) {
+			continue
+		}
+
+		if arg.Value, err = t.encryptValue(arg.Value); err != nil {
+			return nil, fmt.Errorf("could not encrypt %s: %w", arg.Name, err)
+		}
+		params[i] = arg
+	}
+	return params, nil
+}
+
+func (t *Tx) encryptValue(value any) (_ any, err error) {
+	switch v := value.(type) {
+	case string:
+		return t.cipher.EncryptString(v)
+	case sql.NullString:
+		if !v.Valid {
+			return v, nil
+		}
+		v.String, err = t.cipher.EncryptString(v.String)
+		return v, err
+	case *ivms101.Person:
+		if v == nil {
+			return v, nil
+		}
+
+		var data any
+		if data, err = v.Value(); err != nil {
+			return nil, err
+		}
+		return t.cipher.Encrypt(data.([]byte))
+	default:
+		return nil, fmt.Errorf("unhandled encrypted field type %T", value)
+	}
+}
+
+// Returns a scanner that decrypts the values of the specified fields of the model
+// before they are scanned into the model; an error is returned if any of the fields is
+// not a scan destination so that encrypted values are never silently returned.
+func (t *Tx) decrypt(scanner models.Scanner, fields ...any) models.Scanner {
+	return &decryptScanner{scanner: scanner, cipher: t.cipher, fields: fields}
+}
+
+type decryptScanner struct {
+	scanner models.Scanner
+	cipher  *pii.Cipher
+	fields  []any
+}
+
+func (s *decryptScanner) Scan(dest ...any) error {
+	for _, field := range s.fields {
+		i := slices.Index(dest, field)
+		if i < 0 {
+			return fmt.Errorf("encrypted field %T is not scanned", field)
+		}
+		dest[i] = &decryptField{cipher: s.cipher, dest: dest[i]}
+	}
+	return s.scanner.Scan(dest...)
+}
+
+// decryptField implements sql.Scanner to decrypt a column value before it is
+// assigned to the model field.
+type decryptField struct {
+	cipher *pii.Cipher
+	dest   any
+}
+
+func (f *decryptField) Scan(src any) (err error) {
+	var value []byte
+	switch v := src.(type) {
+	case nil:
+	case string:
+		value = []byte(v)
+	case []byte:
+		value = v
+	default:
+		return fmt.Errorf("cannot decrypt %T column", src)
+	}
+
+	if value != nil {
+		if value, err = f.cipher.Decrypt(value); err != nil {
+			return err
+		}
+	}
+
+	switch d := f.dest.(type) {
+	case *string:
+		*d = string(value)
+	case *sql.NullString:
+		*d = sql.NullString{Valid: value != nil, String: string(value)}
+	case **ivms101.Person:
+		if value == nil {
+			*d = nil
+			return nil
+		}
+
+		person := &ivms101.Person{}
+		if err = person.Scan(value); err != nil {
+			return err
+		}
+		*d = person
+	default:
+		return fmt.Errorf("cannot scan decrypted column into %T", f.dest)
+	}
+	return nil
+}
+
+func nullBytes(value sql.NullString) any {
+	if !value.Valid {
+		return nil
+	}
+	return []byte(value.String)
+}
+
+// Returns the named params of the account with the PII fields encrypted.
+func (t *Tx) accountParams(account *models.Account) ([]any, error) {
+	return t.encryptParams(account.Params(), "customerID", "firstName", "lastName", "ivms101")
+}
+
+// Returns the named params of the crypto address with the crypto address encrypted
+// and the blind index of the crypto address for lookups.
+func (t *Tx) cryptoAddressParams(addr *models.CryptoAddress) (params []any, err error) {
+	if params, err = t.encryptParams(addr.Params(), "cryptoAddress"); err != nil {
+		return nil, err
+	}
+	return append(params, sql.Named("cryptoAddressIdx", t.cipher.BlindIndex(addr.CryptoAddress))), nil
+}
+
+// Returns the named params of the contact with the name and email encrypted and the
+// blind index of the email address for lookups.
+func (t *Tx) contactParams(contact *models.Contact) (params []any, err error) {
+	if params, err = t.encryptParams(contact.Params(), "name", "email"); err != nil {
+		return nil, err
+	}
+	return append(params, sql.Named("emailIdx", t.emailIndex(contact.Email))), nil
+}
+
+// Returns the named params of the transaction with the originator and beneficiary
+// names and wallet addresses encrypted and the blind indexes of the wallet addresses
+// for joins against the crypto addresses of local accounts.
+func (t *Tx) transactionParams(transaction *models.Transaction) (params []any, err error) {
+	if params, err = t.encryptParams(transaction.Params(), "originator", "originatorAddress", "beneficiary", "beneficiaryAddress"); err != nil {
+		return nil, err
+	}
+
+	return append(params,
+		sql.Named("originatorAddressIdx", t.addressIndex(transaction.OriginatorAddress)),
+		sql.Named("beneficiaryAddressIdx", t.addressIndex(transaction.BeneficiaryAddress)),
+	), nil
+}
+
+// Returns the named params of the sunrise message with the email encrypted and the
+// blind index of the email address that references the contact the message was sent to.
+func (t *Tx) sunriseParams(msg *models.Sunrise) (params []any, err error) {
+	if params, err = t.encryptParams(msg.Params(), "email"); err != nil {
+		return nil, err
+	}
+	return append(params, sql.Named("emailIdx", t.emailIndex(msg.Email))), nil
+}
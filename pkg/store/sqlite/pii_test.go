@@ -0,0 +1,390 @@
+package sqlite_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/dsn"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
+	db "github.com/trisacrypto/envoy/pkg/store/sqlite"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestFieldEncryption() {
+	s.Run("Fixtures", func() {
+		require := s.Require()
+
+		tx, err := s.store.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		require.NoError(err, "could not open transaction")
+		defer tx.Rollback()
+
+		var firstName, lastName string
+		err = tx.QueryRow("SELECT first_name, last_name FROM accounts WHERE id=$1", ulid.MustParse("01HV6RV08YNR2GH8MEEFCV4NKN")).Scan(&firstName, &lastName)
+		require.NoError(err, "could not query raw account")
+		require.True(pii.IsEncrypted([]byte(firstName)), "expected first name to be encrypted")
+		require.True(pii.IsEncrypted([]byte(lastName)), "expected last name to be encrypted")
+
+		var address, index string
+		err = tx.QueryRow("SELECT crypto_address, crypto_address_idx FROM crypto_addresses WHERE id=$1", ulid.MustParse("01HV6RV08ZB905HW3ET5KDRJWP")).Scan(&address, &index)
+		require.NoError(err, "could not query raw crypto address")
+		require.True(pii.IsEncrypted([]byte(address)), "expected crypto address to be encrypted")
+		require.Equal(s.cipher.BlindIndex("mjJ9xufmdSfZLRUXV6Ac3r64M6bbrxCu48"), index)
+	})
+
+	s.Run("Account", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		account := &models.Account{
+			CustomerID: sql.NullString{String: "8675309", Valid: true},
+			FirstName:  sql.NullString{String: "Jenny", Valid: true},
+			LastName:   sql.NullString{String: "Tutone", Valid: true},
+		}
+
+		err := s.store.CreateAccount(ctx, account, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create account")
+
+		tx, err := s.store.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+		require.NoError(err, "could not open transaction")
+		defer tx.Rollback()
+
+		var lastName string
+		err = tx.QueryRow("SELECT last_name FROM accounts WHERE id=$1", account.ID).Scan(&lastName)
+		require.NoError(err, "could not query raw account")
+		require.NotContains(lastName, "Tutone", "expected last name to be encrypted")
+
+		cmp, err := s.store.RetrieveAccount(ctx, account.ID)
+		require.NoError(err, "could not retrieve account")
+		require.Equal("Jenny", cmp.FirstName.String)
+		require.Equal("Tutone", cmp.LastName.String)
+	})
+
+	s.Run("CryptoAddress", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		addr := &models.CryptoAddress{
+			AccountID:     ulid.MustParse("01HV6RV08YNR2GH8MEEFCV4NKN"),
+			CryptoAddress: "bc1qxy2kgdygjrsqtzq2n0yrf2493p83kkfjhx0wlh",
+			Network:       "BTC",
+		}
+
+		err := s.store.CreateCryptoAddress(ctx, addr, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create crypto address")
+
+		raw, index := s.rawColumns("SELECT crypto_address, crypto_address_idx FROM crypto_addresses WHERE id=$1", addr.ID)
+		require.True(pii.IsEncrypted([]byte(raw)), "expected crypto address to be encrypted")
+		require.Equal(s.cipher.BlindIndex(addr.CryptoAddress), index)
+
+		cmp, err := s.store.RetrieveCryptoAddress(ctx, addr.AccountID, addr.ID)
+		require.NoError(err, "could not retrieve crypto address")
+		require.Equal(addr.CryptoAddress, cmp.CryptoAddress)
+
+		account, err := s.store.LookupAccount(ctx, addr.CryptoAddress)
+		require.NoError(err, "could not lookup account by blind index")
+		require.Equal(addr.AccountID, account.ID)
+	})
+
+	s.Run("Contact", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+		counterpartyID := ulid.MustParse("01JXTQCDE6ZES5MPXNW7K19QVQ")
+
+		contact := &models.Contact{
+			Name:           "Jenny Tutone",
+			Email:          "jenny@daybreak.example.com",
+			Role:           "Compliance",
+			CounterpartyID: counterpartyID,
+		}
+
+		err := s.store.CreateContact(ctx, contact, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create contact")
+
+		name, email := s.rawColumns("SELECT name, email FROM contacts WHERE id=$1", contact.ID)
+		require.True(pii.IsEncrypted([]byte(name)), "expected contact name to be encrypted")
+		require.True(pii.IsEncrypted([]byte(email)), "expected contact email to be encrypted")
+
+		cmp, err := s.store.RetrieveContact(ctx, contact.ID, counterpartyID)
+		require.NoError(err, "could not retrieve contact")
+		require.Equal(contact.Name, cmp.Name)
+		require.Equal(contact.Email, cmp.Email)
+
+		// Contacts are looked up by the blind index of the lowercased email
+		counterparty, err := s.store.GetOrCreateSunriseCounterparty(ctx, "JENNY@daybreak.example.com", "Daybreak", &models.ComplianceAuditLog{})
+		require.NoError(err, "could not lookup counterparty by contact email")
+		require.Equal(counterpartyID, counterparty.ID)
+
+		// Emails that differ only by case are not unique
+		dup := &models.Contact{Email: "Jenny@Daybreak.example.com", CounterpartyID: counterpartyID}
+		err = s.store.CreateContact(ctx, dup, &models.ComplianceAuditLog{})
+		require.Error(err, "expected duplicate contact email to be rejected")
+	})
+
+	s.Run("Transaction", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		transaction := &models.Transaction{
+			Source:             enum.SourceLocal,
+			Status:             enum.StatusDraft,
+			Counterparty:       "BobVASP",
+			Originator:         sql.NullString{String: "Jenny Tutone", Valid: true},
+			OriginatorAddress:  sql.NullString{String: "mjJ9xufmdSfZLRUXV6Ac3r64M6bbrxCu48", Valid: true},
+			Beneficiary:        sql.NullString{String: "Frank Jeffers", Valid: true},
+			BeneficiaryAddress: sql.NullString{String: "n4RmmDzqaJiq86NYTp55P1yi1XGRczskFy", Valid: true},
+			VirtualAsset:       "BTC",
+			Amount:             0.42,
+		}
+
+		err := s.store.CreateTransaction(ctx, transaction, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create transaction")
+
+		originator, beneficiary := s.rawColumns("SELECT originator_address, beneficiary_address FROM transactions WHERE id=$1", transaction.ID.String())
+		require.True(pii.IsEncrypted([]byte(originator)), "expected originator address to be encrypted")
+		require.True(pii.IsEncrypted([]byte(beneficiary)), "expected beneficiary address to be encrypted")
+
+		originatorIdx, _ := s.rawColumns("SELECT originator_address_idx, beneficiary_address_idx FROM transactions WHERE id=$1", transaction.ID.String())
+		require.Equal(s.cipher.BlindIndex(transaction.OriginatorAddress.String), originatorIdx)
+
+		cmp, err := s.store.RetrieveTransaction(ctx, transaction.ID)
+		require.NoError(err, "could not retrieve transaction")
+		require.Equal(transaction.Originator, cmp.Originator)
+		require.Equal(transaction.OriginatorAddress, cmp.OriginatorAddress)
+		require.Equal(transaction.BeneficiaryAddress, cmp.BeneficiaryAddress)
+
+		// Account transactions are joined on the blind index of the wallet address
+		page, err := s.store.ListAccountTransactions(ctx, ulid.MustParse("01HV6RV08YNR2GH8MEEFCV4NKN"), &models.TransactionPageInfo{})
+		require.NoError(err, "could not list account transactions")
+		require.True(containsTransaction(page, transaction.ID), "expected transaction to be joined by blind index")
+	})
+
+	s.Run("Sunrise", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		msg := &models.Sunrise{
+			EnvelopeID: uuid.MustParse("17c802fb-0c7d-4288-8a3a-bb49c95b85c7"),
+			Email:      "compliance@daybreak.example.com",
+			Expiration: time.Now().Add(24 * time.Hour),
+			Status:     enum.StatusPending,
+		}
+
+		err := s.store.CreateSunrise(ctx, msg, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create sunrise message")
+
+		email, index := s.rawColumns("SELECT email, email_idx FROM sunrise WHERE id=$1", msg.ID)
+		require.True(pii.IsEncrypted([]byte(email)), "expected sunrise email to be encrypted")
+
+		contactIdx, _ := s.rawColumns("SELECT email_idx, email FROM contacts WHERE id=$1", ulid.MustParse("01JXXW0WTA40A4BJ5Q2GNEW9J4"))
+		require.Equal(contactIdx, index, "expected sunrise to reference the contact by blind index")
+
+		cmp, err := s.store.RetrieveSunrise(ctx, msg.ID)
+		require.NoError(err, "could not retrieve sunrise message")
+		require.Equal(msg.Email, cmp.Email)
+	})
+
+	s.Run("Idempotent", func() {
+		require := s.Require()
+		require.NoError(s.store.UseFieldEncryption(s.cipher), "could not reapply field encryption")
+
+		account, err := s.store.LookupAccount(s.ActorContext(), "mjJ9xufmdSfZLRUXV6Ac3r64M6bbrxCu48")
+		require.NoError(err, "could not lookup account by blind index")
+		require.Equal("Mary", account.FirstName.String)
+	})
+}
+
+func (s *storeTestSuite) TestFieldEncryptionStartup() {
+	require := s.Require()
+	ctx := s.ActorContext()
+	accountID := ulid.MustParse("01HV6RV08YNR2GH8MEEFCV4NKN")
+	sunriseID := ulid.MustParse("01JXTGSFRC88HAY8V173976Z9D")
+
+	// Start from a database where field encryption has not been enabled so the PII is
+	// plaintext and the blind indexes are unkeyed, including the sunrise fixtures.
+	require.NoError(s.store.Close(), "could not close connection to db")
+	require.NoError(os.Remove(s.dbpath), "could not delete old database")
+	s.OpenFixturesDB()
+	require.NoError(s.store.UseFieldEncryption(nil), "could not index plaintext fixtures")
+
+	page, err := s.store.ListAccountTransactions(ctx, accountID, &models.TransactionPageInfo{})
+	require.NoError(err, "could not list account transactions")
+	nTransactions := len(page.Transactions)
+	require.NotZero(nTransactions, "expected account transactions in the fixtures")
+
+	// Enabling field encryption must reindex contacts along with their sunrise messages
+	require.NoError(s.store.UseFieldEncryption(s.cipher), "could not enable field encryption on existing data")
+	s.assertSunriseEncrypted(sunriseID, s.cipher)
+
+	page, err = s.store.ListAccountTransactions(ctx, accountID, &models.TransactionPageInfo{})
+	require.NoError(err, "could not list account transactions")
+	require.Len(page.Transactions, nTransactions, "expected account transactions to be reindexed")
+
+	// Rotating the key without configuring the previous key fails without changes
+	rotated, err := pii.New(bytes.Repeat([]byte{0x24}, pii.KeySize))
+	require.NoError(err, "could not create rotated cipher")
+	err = s.store.UseFieldEncryption(rotated)
+	require.ErrorIs(err, pii.ErrUnknownKey, "expected an unknown key error")
+
+	require.NoError(s.store.UseFieldEncryption(s.cipher), "could not restore field encryption")
+	s.assertSunriseEncrypted(sunriseID, s.cipher)
+
+	// Rotating the key with the previous key reencrypts and reindexes existing data
+	require.NoError(rotated.AddPreviousKey(bytes.Repeat([]byte{0x42}, pii.KeySize)), "could not add previous key")
+	require.NoError(s.store.UseFieldEncryption(rotated), "could not rotate field encryption key")
+	s.assertSunriseEncrypted(sunriseID, rotated)
+
+	page, err = s.store.ListAccountTransactions(ctx, accountID, &models.TransactionPageInfo{})
+	require.NoError(err, "could not list account transactions")
+	require.Len(page.Transactions, nTransactions, "expected account transactions to be reindexed")
+
+	account, err := s.store.LookupAccount(ctx, "mjJ9xufmdSfZLRUXV6Ac3r64M6bbrxCu48")
+	require.NoError(err, "could not lookup account by rotated blind index")
+	require.Equal("Mary", account.FirstName.String)
+}
+
+// Contacts whose emails differ only by case must be merged before the migration that
+// adds the blind indexes is applied; the migration lists the conflicts instead of
+// dropping rows so that operators can decide how to merge them.
+func TestFieldEncryptionMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migration.db")
+	conn, err := sql.Open("sqlite3", path)
+	require.NoError(t, err, "could not open database")
+
+	_, err = conn.Exec("PRAGMA foreign_keys = on")
+	require.NoError(t, err, "could not enable foreign keys")
+
+	// Apply the migrations prior to field encryption
+	migrations, err := db.Migrations()
+	require.NoError(t, err, "could not load migrations")
+	for _, migration := range migrations {
+		if migration.Path == "0013_field_encryption.sql" {
+			break
+		}
+
+		query, err := migration.SQL()
+		require.NoError(t, err, "could not load migration sql")
+		_, err = conn.Exec(query)
+		require.NoError(t, err, "could not apply migration %d", migration.ID)
+		_, err = conn.Exec("INSERT INTO migrations (id, name, version, created) VALUES ($1, $2, 'test', datetime('now'))", migration.ID, migration.Name)
+		require.NoError(t, err, "could not record migration %d", migration.ID)
+	}
+
+	var (
+		counterpartyID = ulid.MakeSecure()
+		transactionID  = uuid.New()
+		aliceID        = ulid.MustParse("01JXTW2Y53KRDB033ZT5P3B001")
+		duplicateID    = ulid.MustParse("01JXTW2Y53KRDB033ZT5P3B002")
+		bobID          = ulid.MustParse("01JXTW2Y53KRDB033ZT5P3B003")
+		sunriseID      = ulid.MustParse("01JXTW2Y53KRDB033ZT5P3B004")
+		dupSunriseID   = ulid.MustParse("01JXTW2Y53KRDB033ZT5P3B005")
+		older          = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		newer          = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	)
+
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{"INSERT INTO counterparties (id, source, protocol, common_name, endpoint, name, created, modified) VALUES ($1, 'user', 'sunrise', 'example.com', 'mailto:alice@example.com', 'Example', $2, $2)", []any{counterpartyID, older}},
+		{"INSERT INTO transactions (id, source, status, counterparty, virtual_asset, amount, created, modified) VALUES ($1, 'local', 'pending', 'Example', 'BTC', 1.0, $2, $2)", []any{transactionID.String(), older}},
+		{"INSERT INTO contacts (id, name, email, role, counterparty_id, created, modified) VALUES ($1, 'Alice', 'Alice@Example.com', '', $2, $3, $3)", []any{duplicateID, counterpartyID, older}},
+		{"INSERT INTO contacts (id, name, email, role, counterparty_id, created, modified) VALUES ($1, 'Alice', 'alice@example.com', '', $2, $3, $3)", []any{aliceID, counterpartyID, newer}},
+		{"INSERT INTO contacts (id, name, email, role, counterparty_id, created, modified) VALUES ($1, 'Bob', 'bob@example.com', '', $2, $3, $3)", []any{bobID, counterpartyID, older}},
+		{"INSERT INTO sunrise (id, envelope_id, email, expiration, status, created, modified) VALUES ($1, $2, 'Alice@Example.com', $3, 'pending', $3, $3)", []any{dupSunriseID, transactionID.String(), older}},
+		{"INSERT INTO sunrise (id, envelope_id, email, expiration, status, created, modified) VALUES ($1, $2, 'alice@example.com', $3, 'pending', $3, $3)", []any{sunriseID, transactionID.String(), newer}},
+	} {
+		_, err = conn.Exec(stmt.query, stmt.args...)
+		require.NoError(t, err, "could not insert fixture")
+	}
+
+	// The migration should fail and list the conflicting rows
+	uri, _ := dsn.Parse("sqlite3:///" + path)
+	_, err = db.Open(uri)
+	require.ErrorContains(t, err, "cannot apply schema 13: emails that differ only by case must be merged")
+	require.ErrorContains(t, err, "contacts "+aliceID.String()+", "+duplicateID.String())
+	require.ErrorContains(t, err, "sunrise messages "+sunriseID.String()+", "+dupSunriseID.String())
+	require.NotContains(t, err.Error(), bobID.String(), "expected only conflicting rows to be listed")
+
+	var applied int
+	require.NoError(t, conn.QueryRow("SELECT count(*) FROM contacts").Scan(&applied))
+	require.Equal(t, 3, applied, "expected no contacts to be removed by the failed migration")
+
+	// Merge the duplicates then apply the remaining migrations and enable encryption
+	_, err = conn.Exec("DELETE FROM sunrise WHERE id=$1", dupSunriseID)
+	require.NoError(t, err, "could not merge duplicate sunrise messages")
+	_, err = conn.Exec("DELETE FROM contacts WHERE id=$1", duplicateID)
+	require.NoError(t, err, "could not merge duplicate contacts")
+	require.NoError(t, conn.Close(), "could not close database")
+
+	store, err := db.Open(uri)
+	require.NoError(t, err, "could not apply field encryption migration")
+	defer store.Close()
+
+	cipher, err := pii.New(bytes.Repeat([]byte{0x42}, pii.KeySize))
+	require.NoError(t, err, "could not create cipher")
+	require.NoError(t, store.UseFieldEncryption(cipher), "could not enable field encryption")
+
+	tx, err := store.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	require.NoError(t, err, "could not open transaction")
+	defer tx.Rollback()
+
+	var ids []ulid.ULID
+	rows, err := tx.Query("SELECT id FROM contacts ORDER BY id")
+	require.NoError(t, err, "could not query contacts")
+	for rows.Next() {
+		var id ulid.ULID
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, []ulid.ULID{aliceID, bobID}, ids, "expected the merged contacts to be migrated")
+
+	var (
+		msgID ulid.ULID
+		index string
+	)
+	err = tx.QueryRow("SELECT id, email_idx FROM sunrise").Scan(&msgID, &index)
+	require.NoError(t, err, "expected a single sunrise message for the transaction")
+	require.Equal(t, sunriseID, msgID)
+	require.Equal(t, cipher.BlindIndex("alice@example.com"), index)
+}
+
+// Returns the raw values of two columns without decrypting them.
+func (s *storeTestSuite) rawColumns(query string, args ...any) (a, b string) {
+	tx, err := s.store.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	s.Require().NoError(err, "could not open transaction")
+	defer tx.Rollback()
+
+	err = tx.QueryRow(query, args...).Scan(&a, &b)
+	s.Require().NoError(err, "could not query raw columns")
+	return a, b
+}
+
+func (s *storeTestSuite) assertSunriseEncrypted(sunriseID ulid.ULID, cipher *pii.Cipher) {
+	require := s.Require()
+	email, index := s.rawColumns("SELECT email, email_idx FROM sunrise WHERE id=$1", sunriseID)
+	require.True(cipher.Current([]byte(email)), "expected sunrise email to be encrypted with the current key")
+	require.Equal(cipher.BlindIndex("compliance@daybreak.example.com"), index, "expected sunrise email to be reindexed")
+
+	msg, err := s.store.RetrieveSunrise(s.ActorContext(), sunriseID)
+	require.NoError(err, "could not retrieve sunrise message")
+	require.Equal("compliance@daybreak.example.com", msg.Email)
+}
+
+func containsTransaction(page *models.TransactionPage, id uuid.UUID) bool {
+	for _, tx := range page.Transactions {
+		if tx.ID == id {
+			return true
+		}
+	}
+	return false
+}
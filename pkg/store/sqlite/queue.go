@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Outbound Queue
+//===========================================================================
+
+const (
+	queuedTransferSummarySQL = "SELECT q.id, q.transaction_id, q.counterparty_id, q.status, q.attempts, q.last_error, q.last_attempt, q.next_attempt, q.created, q.modified, t.counterparty, t.virtual_asset, t.amount FROM queued_transfers q JOIN transactions t ON q.transaction_id=t.id"
+	listQueuedTransfersSQL   = queuedTransferSummarySQL + " WHERE q.status IN ('queued', 'failed') ORDER BY q.next_attempt ASC, q.id ASC"
+	listDueTransfersSQL      = queuedTransferSummarySQL + " WHERE q.status='queued' AND q.next_attempt<=:before ORDER BY q.next_attempt ASC, q.id ASC"
+)
+
+// List the transfers that are waiting in the outbound queue or that have failed to be
+// delivered, ordered by when they will next be sent. Transfers that have been sent or
+// canceled are not returned.
+func (s *Store) ListQueuedTransfers(ctx context.Context) (out []*models.QueuedTransfer, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListQueuedTransfers(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *Tx) ListQueuedTransfers() ([]*models.QueuedTransfer, error) {
+	return t.listQueuedTransfers(listQueuedTransfersSQL)
+}
+
+// List the queued transfers whose next attempt is before the specified timestamp so
+// that they can be sent by the outbound queue.
+func (s *Store) ListDueTransfers(ctx context.Context, before time.Time) (out []*models.QueuedTransfer, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListDueTransfers(before); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *Tx) ListDueTransfers(before time.Time) ([]*models.QueuedTransfer, error) {
+	return t.listQueuedTransfers(listDueTransfersSQL, sql.Named("before", before))
+}
+
+func (t *Tx) listQueuedTransfers(query string, params ...any) (out []*models.QueuedTransfer, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(query, params...); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.QueuedTransfer, 0)
+	for rows.Next() {
+		transfer := &models.QueuedTransfer{}
+		if err = transfer.ScanSummary(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, transfer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const createQueuedTransferSQL = "INSERT INTO queued_transfers (id, transaction_id, counterparty_id, status, envelope, attempts, last_error, last_attempt, next_attempt, created, modified) VALUES (:id, :transactionID, :counterpartyID, :status, :envelope, :attempts, :lastError, :lastAttempt, :nextAttempt, :created, :modified)"
+
+// Add a transfer to the outbound queue. If the next attempt is not set, the transfer
+// is sent the next time the queue is checked.
+func (s *Store) CreateQueuedTransfer(ctx context.Context, transfer *models.QueuedTransfer, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateQueuedTransfer(transfer, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) CreateQueuedTransfer(transfer *models.QueuedTransfer, auditLog *models.ComplianceAuditLog) (err error) {
+	if !transfer.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	if transfer.Envelope == nil {
+		return dberr.ErrMissingValue
+	}
+
+	transfer.ID = ulid.MakeSecure()
+	transfer.Status = enum.QueueQueued
+	transfer.Created = time.Now()
+	transfer.Modified = transfer.Created
+
+	if transfer.NextAttempt.IsZero() {
+		transfer.NextAttempt = transfer.Created
+	}
+
+	if _, err = t.tx.Exec(createQueuedTransferSQL, transfer.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	return t.queuedTransferAuditLog(transfer, enum.ActionCreate, auditLog)
+}
+
+const retrieveQueuedTransferSQL = "SELECT q.id, q.transaction_id, q.counterparty_id, q.status, q.envelope, q.attempts, q.last_error, q.last_attempt, q.next_attempt, q.created, q.modified, t.counterparty, t.virtual_asset, t.amount FROM queued_transfers q JOIN transactions t ON q.transaction_id=t.id WHERE q.id=:id"
+
+// Retrieve a queued transfer including the sealed payload of the transfer.
+func (s *Store) RetrieveQueuedTransfer(ctx context.Context, id ulid.ULID) (transfer *models.QueuedTransfer, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if transfer, err = tx.RetrieveQueuedTransfer(id); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
+
+func (t *Tx) RetrieveQueuedTransfer(id ulid.ULID) (transfer *models.QueuedTransfer, err error) {
+	transfer = &models.QueuedTransfer{}
+	if err = transfer.Scan(t.tx.QueryRow(retrieveQueuedTransferSQL, sql.Named("id", id))); err != nil {
+		return nil, dbe(err)
+	}
+	return transfer, nil
+}
+
+const updateQueuedTransferSQL = "UPDATE queued_transfers SET status=:status, attempts=:attempts, last_error=:lastError, last_attempt=:lastAttempt, next_attempt=:nextAttempt, modified=:modified WHERE id=:id"
+
+// Update the status and the delivery attempts of a queued transfer; the transaction,
+// counterparty, and sealed payload of the transfer cannot be modified.
+func (s *Store) UpdateQueuedTransfer(ctx context.Context, transfer *models.QueuedTransfer, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UpdateQueuedTransfer(transfer, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) UpdateQueuedTransfer(transfer *models.QueuedTransfer, auditLog *models.ComplianceAuditLog) (err error) {
+	if transfer.ID.IsZero() {
+		return dberr.ErrMissingID
+	}
+
+	transfer.Modified = time.Now()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(updateQueuedTransferSQL, transfer.Params()...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return t.queuedTransferAuditLog(transfer, enum.ActionUpdate, auditLog)
+}
+
+func (t *Tx) queuedTransferAuditLog(transfer *models.QueuedTransfer, action enum.Action, auditLog *models.ComplianceAuditLog) error {
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       transfer.ID.Bytes(),
+		ResourceType:     enum.ResourceQueuedTransfer,
+		ResourceModified: transfer.Modified,
+		Action:           action,
+		ChangeNotes:      auditLog.ChangeNotes,
+	})
+}
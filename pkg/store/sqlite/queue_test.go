@@ -0,0 +1,141 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+
+	api "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+func (s *storeTestSuite) TestOutboundQueue() {
+	counterpartyID := ulid.MustParse("01HWR5VWW8V7ZFFVJVBEC7AV8A")
+
+	s.Run("Lifecycle", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		transfer := &models.QueuedTransfer{
+			TransactionID:  txID,
+			CounterpartyID: counterpartyID,
+			Envelope:       &api.SecureEnvelope{Id: txID.String(), Payload: []byte("sealed")},
+		}
+
+		err := s.store.CreateQueuedTransfer(ctx, transfer, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not queue transfer")
+		require.False(transfer.ID.IsZero(), "expected an id to be assigned")
+		require.Equal(enum.QueueQueued, transfer.Status)
+		require.Equal(transfer.Created, transfer.NextAttempt, "expected transfer to be due immediately")
+
+		cmp, err := s.store.RetrieveQueuedTransfer(ctx, transfer.ID)
+		require.NoError(err, "could not retrieve queued transfer")
+		require.Equal(txID, cmp.TransactionID)
+		require.Equal(counterpartyID, cmp.CounterpartyID)
+		require.Equal(enum.QueueQueued, cmp.Status)
+		require.Equal([]byte("sealed"), cmp.Envelope.Payload)
+		require.Equal("Example VASP", cmp.Counterparty)
+		require.Equal("BTC", cmp.VirtualAsset)
+		require.Equal(0.25, cmp.Amount)
+
+		// Record a failed attempt and reschedule the transfer
+		now := time.Now()
+		cmp.Attempts++
+		cmp.LastError = sql.NullString{Valid: true, String: "could not connect to remote counterparty"}
+		cmp.LastAttempt = sql.NullTime{Valid: true, Time: now}
+		cmp.NextAttempt = now.Add(time.Hour)
+		require.NoError(s.store.UpdateQueuedTransfer(ctx, cmp, &models.ComplianceAuditLog{}))
+
+		due, err := s.store.ListDueTransfers(ctx, now)
+		require.NoError(err, "could not list due transfers")
+		require.Len(due, 0, "expected rescheduled transfer not to be due")
+
+		due, err = s.store.ListDueTransfers(ctx, now.Add(2*time.Hour))
+		require.NoError(err, "could not list due transfers")
+		require.Len(due, 1, "expected rescheduled transfer to be due")
+		require.Equal(int64(1), due[0].Attempts)
+		require.Nil(due[0].Envelope, "expected the envelope not to be listed")
+
+		queued, err := s.store.ListQueuedTransfers(ctx)
+		require.NoError(err, "could not list queued transfers")
+		require.Len(queued, 1)
+
+		// Failed transfers are listed but are not due
+		cmp.Status = enum.QueueFailed
+		require.NoError(s.store.UpdateQueuedTransfer(ctx, cmp, &models.ComplianceAuditLog{}))
+
+		due, err = s.store.ListDueTransfers(ctx, now.Add(2*time.Hour))
+		require.NoError(err, "could not list due transfers")
+		require.Len(due, 0, "expected failed transfer not to be due")
+
+		queued, err = s.store.ListQueuedTransfers(ctx)
+		require.NoError(err, "could not list queued transfers")
+		require.Len(queued, 1, "expected failed transfer to be listed")
+
+		// Canceled transfers are no longer listed
+		cmp.Status = enum.QueueCanceled
+		require.NoError(s.store.UpdateQueuedTransfer(ctx, cmp, &models.ComplianceAuditLog{}))
+
+		queued, err = s.store.ListQueuedTransfers(ctx)
+		require.NoError(err, "could not list queued transfers")
+		require.Len(queued, 0, "expected canceled transfer not to be listed")
+
+		logs, err := s.store.ListComplianceAuditLogs(ctx, &models.ComplianceAuditLogPageInfo{ResourceTypes: []string{enum.ResourceQueuedTransfer.String()}})
+		require.NoError(err, "could not list audit logs")
+		require.Len(logs.Logs, 4, "expected a create and three update audit logs")
+	})
+
+	s.Run("PreparedTransaction", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		db, err := s.store.PrepareTransaction(ctx, txID, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not prepare transaction")
+		defer db.Rollback()
+
+		transfer := &models.QueuedTransfer{
+			CounterpartyID: counterpartyID,
+			Envelope:       &api.SecureEnvelope{Id: txID.String()},
+		}
+
+		require.NoError(db.Enqueue(transfer, &models.ComplianceAuditLog{}), "could not enqueue transfer")
+		require.Equal(txID, transfer.TransactionID, "expected the transaction of the prepared transaction")
+
+		transfer.Status = enum.QueueSent
+		require.NoError(db.UpdateQueuedTransfer(transfer, &models.ComplianceAuditLog{}))
+		require.NoError(db.Commit())
+
+		cmp, err := s.store.RetrieveQueuedTransfer(ctx, transfer.ID)
+		require.NoError(err, "could not retrieve queued transfer")
+		require.Equal(enum.QueueSent, cmp.Status)
+	})
+
+	s.Run("Errors", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := s.createCaseTransaction()
+
+		err := s.store.CreateQueuedTransfer(ctx, &models.QueuedTransfer{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNoIDOnCreate)
+
+		err = s.store.CreateQueuedTransfer(ctx, &models.QueuedTransfer{TransactionID: txID, CounterpartyID: counterpartyID}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue)
+
+		_, err = s.store.RetrieveQueuedTransfer(ctx, ulid.MakeSecure())
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		err = s.store.UpdateQueuedTransfer(ctx, &models.QueuedTransfer{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		err = s.store.UpdateQueuedTransfer(ctx, &models.QueuedTransfer{}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingID)
+	})
+}
@@ -0,0 +1,230 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Reports
+//===========================================================================
+
+const listReportsSQL = "SELECT id, name, schedule, period_start, period_end, created_by, created, modified FROM reports ORDER BY created DESC, id DESC"
+
+// List the reports that have been generated, most recent first, without their data.
+func (s *Store) ListReports(ctx context.Context) (out []*models.Report, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.tx.Query(listReportsSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.Report, 0)
+	for rows.Next() {
+		report := &models.Report{}
+		if err = report.ScanSummary(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const createReportSQL = "INSERT INTO reports (id, name, schedule, period_start, period_end, data, created_by, created, modified) VALUES (:id, :name, :schedule, :periodStart, :periodEnd, :data, :createdBy, :created, :modified)"
+
+// Create a report; if a report has already been generated for the schedule and period
+// then ErrAlreadyExists is returned.
+func (s *Store) CreateReport(ctx context.Context, report *models.Report, auditLog *models.ComplianceAuditLog) (err error) {
+	if !report.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	if report.Name == "" || len(report.Data) == 0 {
+		return dberr.ErrMissingValue
+	}
+
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	report.ID = ulid.MakeSecure()
+	report.Created = time.Now()
+	report.Modified = report.Created
+
+	if _, err = tx.tx.Exec(createReportSQL, report.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	if err = tx.reportAuditLog(report.ID, report.Modified, enum.ActionCreate, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const retrieveReportSQL = "SELECT * FROM reports WHERE id=:id"
+
+// Retrieve a report with its data.
+func (s *Store) RetrieveReport(ctx context.Context, id ulid.ULID) (report *models.Report, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	report = &models.Report{}
+	if err = report.Scan(tx.tx.QueryRow(retrieveReportSQL, sql.Named("id", id))); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+const retrieveScheduledReportSQL = "SELECT * FROM reports WHERE schedule=:schedule AND datetime(period_start)=datetime(:periodStart)"
+
+// Retrieve the report generated by the scheduler for the period starting at the
+// specified time; returns ErrNotFound if the report has not been generated yet.
+func (s *Store) RetrieveScheduledReport(ctx context.Context, schedule string, periodStart time.Time) (report *models.Report, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	report = &models.Report{}
+	if err = report.Scan(tx.tx.QueryRow(retrieveScheduledReportSQL, sql.Named("schedule", schedule), sql.Named("periodStart", periodStart.UTC()))); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+const deleteReportSQL = "DELETE FROM reports WHERE id=:id"
+
+// Delete a report; a deleted scheduled report is generated again by the scheduler if
+// its period is still the most recent period of the schedule.
+func (s *Store) DeleteReport(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.tx.Exec(deleteReportSQL, sql.Named("id", id)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	if err = tx.reportAuditLog(id, time.Now(), enum.ActionDelete, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) reportAuditLog(reportID ulid.ULID, modified time.Time, action enum.Action, auditLog *models.ComplianceAuditLog) error {
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       reportID.Bytes(),
+		ResourceType:     enum.ResourceReport,
+		ResourceModified: modified,
+		Action:           action,
+		ChangeNotes:      auditLog.ChangeNotes,
+	})
+}
+
+//===========================================================================
+// Envelope Timings
+//===========================================================================
+
+const listEnvelopeTimingsSQL = "SELECT e.envelope_id, e.direction, e.timestamp FROM secure_envelopes e JOIN transactions t ON t.id=e.envelope_id WHERE datetime(t.created) >= datetime(:after) AND datetime(t.created) < datetime(:before) ORDER BY e.envelope_id"
+
+// List the timestamps of the first secure envelope sent and received for each of the
+// transactions (active or archived) created in the period. Transactions without any
+// secure envelopes are not returned.
+func (s *Store) ListEnvelopeTimings(ctx context.Context, after, before time.Time) (out []*models.EnvelopeTiming, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.tx.Query(listEnvelopeTimingsSQL, sql.Named("after", after.UTC()), sql.Named("before", before.UTC())); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	var timing *models.EnvelopeTiming
+	out = make([]*models.EnvelopeTiming, 0)
+	for rows.Next() {
+		var (
+			envelopeID uuid.UUID
+			direction  enum.Direction
+			timestamp  time.Time
+		)
+
+		if err = rows.Scan(&envelopeID, &direction, &timestamp); err != nil {
+			return nil, err
+		}
+
+		// Rows are ordered by envelope ID so a new timing is started for each transaction
+		if timing == nil || timing.TransactionID != envelopeID {
+			timing = &models.EnvelopeTiming{TransactionID: envelopeID}
+			out = append(out, timing)
+		}
+
+		switch direction {
+		case enum.DirectionOutgoing:
+			if !timing.FirstOutgoing.Valid || timestamp.Before(timing.FirstOutgoing.Time) {
+				timing.FirstOutgoing = sql.NullTime{Time: timestamp, Valid: true}
+			}
+		case enum.DirectionIncoming:
+			if !timing.FirstIncoming.Valid || timestamp.Before(timing.FirstIncoming.Time) {
+				timing.FirstIncoming = sql.NullTime{Time: timestamp, Valid: true}
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
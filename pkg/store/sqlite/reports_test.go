@@ -0,0 +1,138 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestReports() {
+	s.Run("Lifecycle", func() {
+		defer s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		report := &models.Report{
+			Name:        "Monthly Travel Rule Report",
+			Schedule:    sql.NullString{Valid: true, String: "monthly"},
+			PeriodStart: start,
+			PeriodEnd:   start.AddDate(0, 1, 0),
+			Data:        []byte(`{"summary":{"transfers":12}}`),
+			CreatedBy:   "Scheduler",
+		}
+
+		err := s.store.CreateReport(ctx, report, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create report")
+		require.False(report.ID.IsZero(), "expected an id to be assigned")
+
+		cmp, err := s.store.RetrieveReport(ctx, report.ID)
+		require.NoError(err, "could not retrieve report")
+		require.Equal(report.Name, cmp.Name)
+		require.Equal("monthly", cmp.Schedule.String)
+		require.True(start.Equal(cmp.PeriodStart))
+		require.Equal(report.Data, cmp.Data)
+
+		cmp, err = s.store.RetrieveScheduledReport(ctx, "monthly", start)
+		require.NoError(err, "could not retrieve scheduled report")
+		require.Equal(report.ID, cmp.ID)
+
+		_, err = s.store.RetrieveScheduledReport(ctx, "monthly", start.AddDate(0, 1, 0))
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		_, err = s.store.RetrieveScheduledReport(ctx, "weekly", start)
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		// Only one report can be generated for each scheduled period
+		duplicate := &models.Report{Name: "Duplicate", Schedule: report.Schedule, PeriodStart: start, PeriodEnd: report.PeriodEnd, Data: []byte("{}")}
+		err = s.store.CreateReport(ctx, duplicate, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrAlreadyExists)
+
+		// Reports generated on demand are not limited to a single report per period
+		for i := 0; i < 2; i++ {
+			adhoc := &models.Report{Name: "Ad Hoc Report", PeriodStart: start, PeriodEnd: report.PeriodEnd, Data: []byte("{}")}
+			require.NoError(s.store.CreateReport(ctx, adhoc, &models.ComplianceAuditLog{}), "could not create ad hoc report")
+		}
+
+		reports, err := s.store.ListReports(ctx)
+		require.NoError(err, "could not list reports")
+		require.Len(reports, 3)
+		for _, r := range reports {
+			require.Nil(r.Data, "expected the report data to be omitted from the list")
+		}
+
+		require.NoError(s.store.DeleteReport(ctx, report.ID, &models.ComplianceAuditLog{}), "could not delete report")
+		_, err = s.store.RetrieveReport(ctx, report.ID)
+		require.ErrorIs(err, errors.ErrNotFound)
+		require.ErrorIs(s.store.DeleteReport(ctx, report.ID, &models.ComplianceAuditLog{}), errors.ErrNotFound)
+
+		logs, err := s.store.ListComplianceAuditLogs(ctx, &models.ComplianceAuditLogPageInfo{ResourceTypes: []string{enum.ResourceReport.String()}})
+		require.NoError(err, "could not list audit logs")
+		require.Len(logs.Logs, 4, "expected three create and one delete audit logs")
+	})
+
+	s.Run("Invalid", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		err := s.store.CreateReport(ctx, &models.Report{Model: models.Model{ID: ulid.MakeSecure()}, Name: "foo", Data: []byte("{}")}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrNoIDOnCreate)
+
+		err = s.store.CreateReport(ctx, &models.Report{Data: []byte("{}")}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue)
+
+		err = s.store.CreateReport(ctx, &models.Report{Name: "foo"}, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrMissingValue)
+	})
+}
+
+func (s *storeTestSuite) TestListEnvelopeTimings() {
+	defer s.ResetDB()
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	replied := s.createCaseTransaction()
+	unanswered := s.createCaseTransaction()
+	s.createCaseTransaction()
+
+	sent := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	envelopes := []*models.SecureEnvelope{
+		{EnvelopeID: replied, Direction: enum.DirectionOutgoing, Timestamp: sent.Add(time.Hour)},
+		{EnvelopeID: replied, Direction: enum.DirectionOutgoing, Timestamp: sent},
+		{EnvelopeID: replied, Direction: enum.DirectionIncoming, Timestamp: sent.Add(90 * time.Second)},
+		{EnvelopeID: unanswered, Direction: enum.DirectionOutgoing, Timestamp: sent},
+	}
+
+	for _, env := range envelopes {
+		env.Envelope = &trisa.SecureEnvelope{Id: env.EnvelopeID.String(), Timestamp: env.Timestamp.Format(time.RFC3339Nano)}
+		require.NoError(s.store.CreateSecureEnvelope(ctx, env, &models.ComplianceAuditLog{}), "could not create secure envelope")
+	}
+
+	now := time.Now()
+	timings, err := s.store.ListEnvelopeTimings(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(err, "could not list envelope timings")
+	require.Len(timings, 2, "expected transactions without envelopes to be omitted")
+
+	for _, timing := range timings {
+		switch timing.TransactionID {
+		case replied:
+			require.True(sent.Equal(timing.FirstOutgoing.Time), "expected the earliest outgoing envelope")
+			require.True(sent.Add(90 * time.Second).Equal(timing.FirstIncoming.Time))
+		case unanswered:
+			require.True(timing.FirstOutgoing.Valid)
+			require.False(timing.FirstIncoming.Valid)
+		default:
+			require.Fail("unexpected transaction in envelope timings")
+		}
+	}
+
+	// Transactions created outside of the period are not included
+	timings, err = s.store.ListEnvelopeTimings(ctx, now.Add(time.Hour), now.Add(2*time.Hour))
+	require.NoError(err, "could not list envelope timings")
+	require.Len(timings, 0)
+}
@@ -0,0 +1,424 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Transaction Retention
+//===========================================================================
+
+const expiredTransactionsSQL = "SELECT t.id FROM transactions t WHERE t.archived=0 AND datetime(t.created) < datetime(:before) AND NOT " + transactionHeldSQL
+
+// Archive all transactions that were created before the cutoff timestamp, skipping
+// any transactions that are under legal hold. Returns the number of
+// transactions that were archived.
+func (s *Store) ArchiveExpiredTransactions(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if n, err = tx.ArchiveExpiredTransactions(before, auditLog); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *Tx) ArchiveExpiredTransactions(before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var txIDs []uuid.UUID
+	if txIDs, err = t.expiredTransactions(expiredTransactionsSQL, before); err != nil {
+		return 0, err
+	}
+
+	notes := retentionNotes(auditLog, "ArchiveExpiredTransactions")
+	for _, txID := range txIDs {
+		if err = t.ArchiveTransaction(txID, &models.ComplianceAuditLog{ChangeNotes: notes}); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// Archived transactions are expired by when they were archived rather than created so
+// that the grace period begins when the transaction is archived; transactions archived
+// before the archived_on timestamp was recorded fall back to their modified timestamp.
+const archivedTransactionsSQL = "SELECT t.id FROM transactions t WHERE t.archived=1 AND datetime(COALESCE(t.archived_on, t.modified)) < datetime(:before) AND NOT " + transactionHeldSQL
+
+// Delete all transactions that were archived before the cutoff timestamp along with
+// their secure envelopes, sunrise records, and idempotent responses, skipping any
+// transactions that are under legal hold or that have been unarchived. The audit trail
+// of the deleted transactions is preserved. Returns the number of transactions that
+// were deleted.
+func (s *Store) PurgeArchivedTransactions(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if n, err = tx.PurgeArchivedTransactions(before, auditLog); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *Tx) PurgeArchivedTransactions(before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var txIDs []uuid.UUID
+	if txIDs, err = t.expiredTransactions(archivedTransactionsSQL, before); err != nil {
+		return 0, err
+	}
+
+	notes := retentionNotes(auditLog, "PurgeArchivedTransactions")
+	for _, txID := range txIDs {
+		if err = t.DeleteTransaction(txID, &models.ComplianceAuditLog{ChangeNotes: notes}); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+const (
+	unshreddedTransactionsSQL = "SELECT t.id FROM transactions t WHERE t.archived=1 AND t.shredded_on IS NULL AND datetime(COALESCE(t.archived_on, t.modified)) < datetime(:before) AND NOT " + transactionHeldSQL
+	shredTransactionSQL       = "UPDATE transactions SET originator=NULL, originator_address=NULL, originator_address_idx=NULL, beneficiary=NULL, beneficiary_address=NULL, beneficiary_address_idx=NULL, shredded_on=:shreddedOn, modified=:modified WHERE id=:id"
+	shredEnvelopesSQL         = "SELECT id FROM secure_envelopes WHERE envelope_id=:envelopeID AND (encryption_key IS NOT NULL OR hmac_secret IS NOT NULL)"
+	shredIdempotencyKeysSQL   = "DELETE FROM idempotency_keys WHERE transaction_id=:transactionID"
+)
+
+// Crypto-shred all transactions that were archived before the cutoff timestamp
+// by removing the PII stored on the transaction, deleting the idempotent responses
+// stored for it, and destroying the sealed encryption keys and hmac secrets of its
+// secure envelopes so that they can no longer be decrypted.
+// Transactions under legal hold are skipped. The transaction record is kept so that
+// the audit trail can still be associated with it. Returns the number of transactions
+// that were shredded.
+func (s *Store) ShredArchivedTransactions(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if n, err = tx.ShredArchivedTransactions(before, auditLog); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *Tx) ShredArchivedTransactions(before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var txIDs []uuid.UUID
+	if txIDs, err = t.expiredTransactions(unshreddedTransactionsSQL, before); err != nil {
+		return 0, err
+	}
+
+	notes := retentionNotes(auditLog, "ShredArchivedTransactions")
+	for _, txID := range txIDs {
+		// Destroy the keys of all secure envelopes associated with the transaction.
+		var envIDs []ulid.ULID
+		if envIDs, err = t.expiredRecords(shredEnvelopesSQL, sql.Named("envelopeID", txID)); err != nil {
+			return 0, err
+		}
+
+		for _, envID := range envIDs {
+			if err = t.shredSecureEnvelope(envID, notes); err != nil {
+				return 0, err
+			}
+		}
+
+		// Delete the stored responses to idempotent requests that contain the PII.
+		if _, err = t.tx.Exec(shredIdempotencyKeysSQL, sql.Named("transactionID", txID)); err != nil {
+			return 0, dbe(err)
+		}
+
+		// Remove the PII from the transaction record.
+		timestamp := time.Now()
+		params := []any{
+			sql.Named("id", txID),
+			sql.Named("shreddedOn", timestamp),
+			sql.Named("modified", timestamp),
+		}
+
+		if _, err = t.tx.Exec(shredTransactionSQL, params...); err != nil {
+			return 0, dbe(err)
+		}
+
+		actorID, actorType := t.GetActor()
+		if err = t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+			ActorID:          actorID,
+			ActorType:        actorType,
+			ResourceID:       txID[:],
+			ResourceType:     enum.ResourceTransaction,
+			ResourceModified: timestamp,
+			Action:           enum.ActionUpdate,
+			ChangeNotes:      notes,
+		}); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+func (t *Tx) expiredTransactions(query string, before time.Time) (txIDs []uuid.UUID, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(query, sql.Named("before", before)); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	txIDs = make([]uuid.UUID, 0)
+	for rows.Next() {
+		var txID uuid.UUID
+		if err = rows.Scan(&txID); err != nil {
+			return nil, err
+		}
+		txIDs = append(txIDs, txID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return txIDs, nil
+}
+
+//===========================================================================
+// Secure Envelope Retention
+//===========================================================================
+
+const (
+	expiredEnvelopesSQL = "SELECT id FROM secure_envelopes WHERE (encryption_key IS NOT NULL OR hmac_secret IS NOT NULL) AND datetime(created) < datetime(:before) AND envelope_id NOT IN (SELECT t.id FROM transactions t WHERE " + transactionHeldSQL + ")"
+	shredEnvelopeSQL    = "UPDATE secure_envelopes SET encryption_key=NULL, hmac_secret=NULL, modified=:modified WHERE id=:id"
+)
+
+// Crypto-shred all secure envelopes created before the cutoff timestamp by destroying
+// their sealed encryption keys and hmac secrets so that the envelope payloads can no
+// longer be decrypted. Envelopes of transactions under legal hold are skipped. Returns
+// the number of secure envelopes that were shredded.
+func (s *Store) ShredExpiredSecureEnvelopes(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if n, err = tx.ShredExpiredSecureEnvelopes(before, auditLog); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *Tx) ShredExpiredSecureEnvelopes(before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var envIDs []ulid.ULID
+	if envIDs, err = t.expiredRecords(expiredEnvelopesSQL, sql.Named("before", before)); err != nil {
+		return 0, err
+	}
+
+	notes := retentionNotes(auditLog, "ShredExpiredSecureEnvelopes")
+	for _, envID := range envIDs {
+		if err = t.shredSecureEnvelope(envID, notes); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+func (t *Tx) shredSecureEnvelope(envID ulid.ULID, notes sql.NullString) (err error) {
+	modified := time.Now()
+	if _, err = t.tx.Exec(shredEnvelopeSQL, sql.Named("id", envID), sql.Named("modified", modified)); err != nil {
+		return dbe(err)
+	}
+
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       envID.Bytes(),
+		ResourceType:     enum.ResourceSecureEnvelope,
+		ResourceModified: modified,
+		Action:           enum.ActionUpdate,
+		ChangeNotes:      notes,
+	})
+}
+
+func (t *Tx) expiredRecords(query string, args ...any) (ids []ulid.ULID, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(query, args...); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	ids = make([]ulid.ULID, 0)
+	for rows.Next() {
+		var id ulid.ULID
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+//===========================================================================
+// Sunrise, Account, and Reset Password Link Retention
+//===========================================================================
+
+const expiredSunriseSQL = "SELECT id FROM sunrise WHERE datetime(expiration) < datetime(:before) AND envelope_id NOT IN (SELECT t.id FROM transactions t WHERE " + transactionHeldSQL + ")"
+
+// Delete all sunrise records that expired before the cutoff timestamp, skipping any
+// records associated with a transaction that is under legal hold. Returns the number
+// of sunrise records that were deleted.
+func (s *Store) PurgeExpiredSunrise(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if n, err = tx.PurgeExpiredSunrise(before, auditLog); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *Tx) PurgeExpiredSunrise(before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var ids []ulid.ULID
+	if ids, err = t.expiredRecords(expiredSunriseSQL, sql.Named("before", before)); err != nil {
+		return 0, err
+	}
+
+	notes := retentionNotes(auditLog, "PurgeExpiredSunrise")
+	for _, id := range ids {
+		if err = t.DeleteSunrise(id, &models.ComplianceAuditLog{ChangeNotes: notes}); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+const expiredAccountsSQL = `
+	SELECT a.id FROM accounts a
+		WHERE datetime(a.modified) < datetime(:before) AND NOT EXISTS (
+			SELECT 1 FROM legal_holds h WHERE h.resource_type='account' AND h.resource_id=a.id AND h.released_on IS NULL
+		) AND NOT EXISTS (
+			SELECT 1 FROM transactions t
+				JOIN crypto_addresses c ON t.originator_address_idx=c.crypto_address_idx OR t.beneficiary_address_idx=c.crypto_address_idx
+				WHERE c.account_id=a.id AND (datetime(t.modified) >= datetime(:before) OR ` + transactionHeldSQL + `)
+		)`
+
+// Delete all customer accounts that have not been modified since the cutoff timestamp
+// and that have not been involved in a transaction since the cutoff timestamp. Accounts
+// under legal hold or involved in a transaction under legal hold are skipped. Returns the number of
+// accounts that were deleted.
+func (s *Store) PurgeExpiredAccounts(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if n, err = tx.PurgeExpiredAccounts(before, auditLog); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *Tx) PurgeExpiredAccounts(before time.Time, auditLog *models.ComplianceAuditLog) (n int64, err error) {
+	var ids []ulid.ULID
+	if ids, err = t.expiredRecords(expiredAccountsSQL, sql.Named("before", before)); err != nil {
+		return 0, err
+	}
+
+	notes := retentionNotes(auditLog, "PurgeExpiredAccounts")
+	for _, id := range ids {
+		if err = t.DeleteAccount(id, &models.ComplianceAuditLog{ChangeNotes: notes}); err != nil {
+			return 0, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+const purgeResetPasswordLinksSQL = "DELETE FROM reset_password_link WHERE datetime(expiration) < datetime(:before)"
+
+// Delete all reset password links that expired before the cutoff timestamp. Returns
+// the number of links that were deleted.
+func (s *Store) PurgeExpiredResetPasswordLinks(ctx context.Context, before time.Time) (n int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if n, err = tx.PurgeExpiredResetPasswordLinks(before); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *Tx) PurgeExpiredResetPasswordLinks(before time.Time) (n int64, err error) {
+	var result sql.Result
+	if result, err = t.tx.Exec(purgeResetPasswordLinksSQL, sql.Named("before", before)); err != nil {
+		return 0, dbe(err)
+	}
+	return result.RowsAffected()
+}
+
+// Make an audit log note with the retention function name so we know why the record
+// was modified or deleted.
+func retentionNotes(auditLog *models.ComplianceAuditLog, method string) sql.NullString {
+	notes := sql.NullString{Valid: true, String: method}
+	if auditLog != nil && auditLog.ChangeNotes.Valid {
+		notes.String = auditLog.ChangeNotes.String + "-" + notes.String
+	}
+	return notes
+}
@@ -0,0 +1,315 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestArchiveExpiredTransactions() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+		before := time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)
+
+		n, err := s.store.ArchiveExpiredTransactions(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not archive expired transactions")
+		require.Equal(int64(3), n, "expected three transactions to be archived")
+
+		counts, err := s.store.CountTransactions(ctx)
+		require.NoError(err, "could not count transactions")
+		require.Equal(map[string]int{"draft": 1}, counts.Active)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceTransaction): 3,
+		})
+
+		// Running the policy again should have no effect
+		n, err = s.store.ArchiveExpiredTransactions(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not archive expired transactions")
+		require.Zero(n, "expected no transactions to be archived")
+	})
+
+	s.Run("LegalHold", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+		before := time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)
+
+		s.setLegalHold("2c891c75-14fa-4c71-aa07-6405b98db7a3")
+		n, err := s.store.ArchiveExpiredTransactions(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not archive expired transactions")
+		require.Equal(int64(2), n, "expected two transactions to be archived")
+
+		archived, _, err := s.store.TransactionState(ctx, uuid.MustParse("2c891c75-14fa-4c71-aa07-6405b98db7a3"))
+		require.NoError(err, "could not fetch transaction state")
+		require.False(archived, "expected transaction under legal hold to not be archived")
+	})
+}
+
+func (s *storeTestSuite) TestPurgeArchivedTransactions() {
+	txID := uuid.MustParse("17c802fb-0c7d-4288-8a3a-bb49c95b85c7")
+	before := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+		key := s.createIdempotencyKey(txID)
+
+		n, err := s.store.PurgeArchivedTransactions(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge archived transactions")
+		require.Equal(int64(1), n, "expected one transaction to be purged")
+
+		_, _, err = s.store.TransactionState(ctx, txID)
+		require.ErrorIs(err, errors.ErrNotFound, "expected transaction to be deleted")
+
+		_, err = s.store.RetrieveIdempotencyKey(ctx, key.ActorID, key.Key)
+		require.ErrorIs(err, errors.ErrNotFound, "expected the idempotent response to be deleted")
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionDelete, enum.ResourceTransaction): 1,
+		})
+	})
+
+	s.Run("LegalHold", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		s.setLegalHold(txID.String())
+		n, err := s.store.PurgeArchivedTransactions(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge archived transactions")
+		require.Zero(n, "expected no transactions to be purged")
+
+		archived, _, err := s.store.TransactionState(ctx, txID)
+		require.NoError(err, "expected transaction under legal hold to not be deleted")
+		require.True(archived, "expected transaction to still be archived")
+	})
+
+	s.Run("GracePeriod", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		// The transaction was created before the cutoff but archived after it, so it
+		// is still within the grace period and must not be purged.
+		cutoff := time.Date(2024, 5, 22, 12, 0, 0, 0, time.UTC)
+		n, err := s.store.PurgeArchivedTransactions(ctx, cutoff, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge archived transactions")
+		require.Zero(n, "expected no transactions to be purged")
+
+		archived, _, err := s.store.TransactionState(ctx, txID)
+		require.NoError(err, "expected transaction in grace period to not be deleted")
+		require.True(archived, "expected transaction to still be archived")
+	})
+}
+
+func (s *storeTestSuite) TestShredArchivedTransactions() {
+	before := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+		txID := uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+		key := s.createIdempotencyKey(txID)
+
+		// Archive the transaction so that its envelopes can be shredded
+		require.NoError(s.store.ArchiveTransaction(ctx, txID, &models.ComplianceAuditLog{}))
+
+		// The transaction was just archived so it is not shredded until the cutoff
+		// is after the time it was archived.
+		n, err := s.store.ShredArchivedTransactions(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not shred archived transactions")
+		require.Equal(int64(1), n, "expected one transaction to be shredded")
+		require.NotZero(s.countEnvelopeKeys(txID), "expected secure envelope keys to be retained")
+
+		n, err = s.store.ShredArchivedTransactions(ctx, time.Now().Add(time.Minute), &models.ComplianceAuditLog{})
+		require.NoError(err, "could not shred archived transactions")
+		require.Equal(int64(1), n, "expected the recently archived transaction to be shredded")
+
+		// NOTE: the secure envelope fixtures cannot be parsed so the transaction is
+		// checked directly in the database rather than with RetrieveTransaction.
+		var pii int
+		err = s.queryRow("SELECT count(originator)+count(originator_address)+count(beneficiary)+count(beneficiary_address) FROM transactions WHERE id=?", txID.String()).Scan(&pii)
+		require.NoError(err, "could not query shredded transaction")
+		require.Zero(pii, "expected transaction pii to be removed")
+		require.Zero(s.countEnvelopeKeys(txID), "expected secure envelope keys to be destroyed")
+
+		_, err = s.store.RetrieveIdempotencyKey(ctx, key.ActorID, key.Key)
+		require.ErrorIs(err, errors.ErrNotFound, "expected the idempotent response to be deleted")
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceTransaction):    3,
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceSecureEnvelope): 2,
+		})
+
+		// Running the policy again should have no effect
+		n, err = s.store.ShredArchivedTransactions(ctx, time.Now().Add(time.Minute), &models.ComplianceAuditLog{})
+		require.NoError(err, "could not shred archived transactions")
+		require.Zero(n, "expected no transactions to be shredded")
+	})
+
+	s.Run("LegalHold", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		s.setLegalHold("17c802fb-0c7d-4288-8a3a-bb49c95b85c7")
+		n, err := s.store.ShredArchivedTransactions(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not shred archived transactions")
+		require.Zero(n, "expected no transactions to be shredded")
+	})
+}
+
+func (s *storeTestSuite) TestShredExpiredSecureEnvelopes() {
+	txID := uuid.MustParse("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+	before := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		n, err := s.store.ShredExpiredSecureEnvelopes(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not shred expired secure envelopes")
+		require.Equal(int64(2), n, "expected two secure envelopes to be shredded")
+
+		require.Zero(s.countEnvelopeKeys(txID), "expected secure envelope keys to be destroyed")
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceSecureEnvelope): 2,
+		})
+	})
+
+	s.Run("LegalHold", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		s.setLegalHold(txID.String())
+		n, err := s.store.ShredExpiredSecureEnvelopes(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not shred expired secure envelopes")
+		require.Zero(n, "expected no secure envelopes to be shredded")
+		require.Equal(4, s.countEnvelopeKeys(txID), "expected secure envelope keys to be preserved")
+	})
+}
+
+func (s *storeTestSuite) TestPurgeExpiredSunrise() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		n, err := s.store.PurgeExpiredSunrise(ctx, time.Now(), &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge expired sunrise records")
+		require.Equal(int64(1), n, "expected one sunrise record to be purged")
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionDelete, enum.ResourceSunrise): 1,
+		})
+	})
+
+	s.Run("NotExpired", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		n, err := s.store.PurgeExpiredSunrise(ctx, time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge expired sunrise records")
+		require.Zero(n, "expected no sunrise records to be purged")
+	})
+
+	s.Run("LegalHold", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		s.setLegalHold("b04dc71c-7214-46a5-a514-381ef0bcc494")
+		n, err := s.store.PurgeExpiredSunrise(ctx, time.Now(), &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge expired sunrise records")
+		require.Zero(n, "expected no sunrise records to be purged")
+	})
+}
+
+func (s *storeTestSuite) TestPurgeExpiredAccounts() {
+	before := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		n, err := s.store.PurgeExpiredAccounts(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge expired accounts")
+		require.Equal(int64(2), n, "expected two accounts to be purged")
+
+		_, err = s.store.RetrieveAccount(ctx, ulid.MustParse("01HV6QS6AK4KNS46Q9HEB7DTPR"))
+		require.ErrorIs(err, errors.ErrNotFound, "expected account to be deleted")
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionDelete, enum.ResourceAccount): 2,
+		})
+	})
+
+	s.Run("LegalHold", func() {
+		s.ResetDB()
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		// Mary Tilcott is the originator of this transaction
+		s.setLegalHold("c20a7cdf-5c23-4b44-b7cd-a29cd00761a3")
+		n, err := s.store.PurgeExpiredAccounts(ctx, before, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not purge expired accounts")
+		require.Equal(int64(1), n, "expected one account to be purged")
+
+		_, err = s.store.RetrieveAccount(ctx, ulid.MustParse("01HV6RV08YNR2GH8MEEFCV4NKN"))
+		require.NoError(err, "expected account with transaction under legal hold to not be deleted")
+	})
+}
+
+func (s *storeTestSuite) TestPurgeExpiredResetPasswordLinks() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	n, err := s.store.PurgeExpiredResetPasswordLinks(ctx, time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(err, "could not purge expired reset password links")
+	require.Zero(n, "expected no reset password links to be purged")
+
+	n, err = s.store.PurgeExpiredResetPasswordLinks(ctx, time.Now())
+	require.NoError(err, "could not purge expired reset password links")
+	require.Equal(int64(1), n, "expected one reset password link to be purged")
+}
+
+// Executes a query directly against the database for checks that cannot be made with
+// the store methods.
+func (s *storeTestSuite) queryRow(query string, args ...any) *sql.Row {
+	tx, err := s.store.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	s.Require().NoError(err, "could not open transaction")
+	s.T().Cleanup(func() { tx.Rollback() })
+	return tx.QueryRow(query, args...)
+}
+
+// Counts the number of encryption keys and hmac secrets that have not been destroyed
+// for the secure envelopes of the specified transaction.
+func (s *storeTestSuite) countEnvelopeKeys(txID uuid.UUID) (keys int) {
+	tx, err := s.store.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	s.Require().NoError(err, "could not open transaction")
+	defer tx.Rollback()
+
+	err = tx.QueryRow("SELECT count(encryption_key)+count(hmac_secret) FROM secure_envelopes WHERE envelope_id=?", txID.String()).Scan(&keys)
+	s.Require().NoError(err, "could not count secure envelope keys")
+	return keys
+}
+
+// Places the transaction with the specified ID under legal hold.
+func (s *storeTestSuite) setLegalHold(txID string) {
+	err := s.store.CreateLegalHold(s.ActorContext(), &models.LegalHold{
+		ResourceType: enum.ResourceTransaction,
+		ResourceID:   txID,
+		Reason:       "subject of an ongoing investigation",
+	}, &models.ComplianceAuditLog{})
+	s.Require().NoError(err, "could not place transaction under legal hold")
+}
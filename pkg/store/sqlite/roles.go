@@ -0,0 +1,315 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+)
+
+const (
+	createRoleSQL      = "INSERT INTO roles (title, description, is_default, require_mfa, is_builtin, created, modified) VALUES (:title, :description, false, :requireMFA, false, :created, :modified)"
+	roleTitleExistsSQL = "SELECT EXISTS(SELECT 1 FROM roles WHERE lower(title)=lower(:title) AND id<>:id)"
+)
+
+func (s *Store) CreateRole(ctx context.Context, role *models.Role, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateRole(role, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Creates a custom role with the permissions set on the role model. Custom roles are
+// never built-in or the default role for new users.
+func (t *Tx) CreateRole(role *models.Role, auditLog *models.ComplianceAuditLog) (err error) {
+	if role.ID != 0 {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	if err = t.checkRoleTitle(role); err != nil {
+		return err
+	}
+
+	role.IsDefault = false
+	role.IsBuiltin = false
+	role.Created = time.Now()
+	role.Modified = role.Created
+
+	var result sql.Result
+	if result, err = t.tx.Exec(createRoleSQL, role.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	if role.ID, err = result.LastInsertId(); err != nil {
+		return dbe(err)
+	}
+
+	if err = t.setRolePermissions(role); err != nil {
+		return err
+	}
+
+	return t.roleAuditLog(role.ID, role.Modified, enum.ActionCreate, auditLog)
+}
+
+const retrieveRoleSQL = "SELECT * FROM roles WHERE id=:id"
+
+func (s *Store) RetrieveRole(ctx context.Context, roleID int64) (role *models.Role, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if role, err = tx.RetrieveRole(roleID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Retrieves the role with its permissions.
+func (t *Tx) RetrieveRole(roleID int64) (role *models.Role, err error) {
+	role = &models.Role{}
+	if err = role.Scan(t.tx.QueryRow(retrieveRoleSQL, sql.Named("id", roleID))); err != nil {
+		return nil, dbe(err)
+	}
+
+	var permissions []*models.Permission
+	if permissions, err = t.fetchRolePermissions(role.ID); err != nil {
+		return nil, err
+	}
+	role.SetPermissions(permissions)
+
+	return role, nil
+}
+
+const updateRoleSQL = "UPDATE roles SET title=:title, description=:description, modified=:modified WHERE id=:id"
+
+func (s *Store) UpdateRole(ctx context.Context, role *models.Role, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UpdateRole(role, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Updates the title, description, and permissions of a custom role; built-in roles
+// cannot be updated. The MFA policy of a role is updated by SetRoleMFARequired.
+func (t *Tx) UpdateRole(role *models.Role, auditLog *models.ComplianceAuditLog) (err error) {
+	var current *models.Role
+	if current, err = t.RetrieveRole(role.ID); err != nil {
+		return err
+	}
+
+	if current.IsBuiltin {
+		return dberr.ErrBuiltinRole
+	}
+
+	if err = t.checkRoleTitle(role); err != nil {
+		return err
+	}
+
+	role.Modified = time.Now()
+	if _, err = t.tx.Exec(updateRoleSQL, role.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	if _, err = t.tx.Exec(deleteRolePermissionsSQL, sql.Named("roleID", role.ID)); err != nil {
+		return dbe(err)
+	}
+
+	if err = t.setRolePermissions(role); err != nil {
+		return err
+	}
+
+	return t.roleAuditLog(role.ID, role.Modified, enum.ActionUpdate, auditLog)
+}
+
+const (
+	deleteRoleSQL    = "DELETE FROM roles WHERE id=:id"
+	roleUserCountSQL = "SELECT (SELECT count(id) FROM users WHERE role_id=:roleID) + (SELECT count(id) FROM user_invites WHERE role_id=:roleID)"
+)
+
+func (s *Store) DeleteRole(ctx context.Context, roleID int64, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.DeleteRole(roleID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Deletes a custom role; built-in roles and roles that are assigned to users or to
+// pending user invites cannot be deleted (otherwise the cascade on user_invites would
+// silently delete the invites). The role's permissions are deleted by the cascade on
+// role_permissions.
+func (t *Tx) DeleteRole(roleID int64, auditLog *models.ComplianceAuditLog) (err error) {
+	var role *models.Role
+	if role, err = t.fetchRole(roleID); err != nil {
+		return err
+	}
+
+	if role.IsBuiltin {
+		return dberr.ErrBuiltinRole
+	}
+
+	var nAssigned int64
+	if err = t.tx.QueryRow(roleUserCountSQL, sql.Named("roleID", roleID)).Scan(&nAssigned); err != nil {
+		return dbe(err)
+	}
+
+	if nAssigned > 0 {
+		return dberr.ErrRoleInUse
+	}
+
+	if _, err = t.tx.Exec(deleteRoleSQL, sql.Named("id", roleID)); err != nil {
+		return dbe(err)
+	}
+
+	return t.roleAuditLog(roleID, time.Now(), enum.ActionDelete, auditLog)
+}
+
+const listPermissionsSQL = "SELECT * FROM permissions ORDER BY id"
+
+func (s *Store) ListPermissions(ctx context.Context) (permissions []*models.Permission, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if permissions, err = tx.ListPermissions(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (t *Tx) ListPermissions() (permissions []*models.Permission, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listPermissionsSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	permissions = make([]*models.Permission, 0)
+	for rows.Next() {
+		permission := &models.Permission{}
+		if err = permission.Scan(rows); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, dbe(rows.Err())
+}
+
+//===========================================================================
+// Role Helpers
+//===========================================================================
+
+// Roles are looked up by title case-insensitively so titles must be unique without
+// regard to case (the unique constraint on the table is case-sensitive).
+func (t *Tx) checkRoleTitle(role *models.Role) (err error) {
+	var exists bool
+	if err = t.tx.QueryRow(roleTitleExistsSQL, sql.Named("title", role.Title), sql.Named("id", role.ID)).Scan(&exists); err != nil {
+		return dbe(err)
+	}
+
+	if exists {
+		return dberr.ErrAlreadyExists
+	}
+	return nil
+}
+
+const (
+	createRolePermSQL        = "INSERT INTO role_permissions (role_id, permission_id, created, modified) SELECT :roleID, id, :created, :modified FROM permissions WHERE title=:permission"
+	deleteRolePermissionsSQL = "DELETE FROM role_permissions WHERE role_id=:roleID"
+)
+
+func (t *Tx) setRolePermissions(role *models.Role) (err error) {
+	var permissions []*models.Permission
+	if permissions, err = role.Permissions(); err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		var result sql.Result
+		if result, err = t.tx.Exec(createRolePermSQL,
+			sql.Named("roleID", role.ID),
+			sql.Named("permission", permission.Title),
+			sql.Named("created", role.Modified),
+			sql.Named("modified", role.Modified),
+		); err != nil {
+			return dbe(err)
+		}
+
+		if nRows, _ := result.RowsAffected(); nRows == 0 {
+			return dberr.ErrUnknownPermission
+		}
+	}
+
+	return nil
+}
+
+const rolePermissionsSQL = "SELECT p.* FROM permissions p JOIN role_permissions rp ON rp.permission_id=p.id WHERE rp.role_id=:roleID ORDER BY p.id"
+
+func (t *Tx) fetchRolePermissions(roleID int64) (permissions []*models.Permission, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(rolePermissionsSQL, sql.Named("roleID", roleID)); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	permissions = make([]*models.Permission, 0)
+	for rows.Next() {
+		permission := &models.Permission{}
+		if err = permission.Scan(rows); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, dbe(rows.Err())
+}
+
+func (t *Tx) roleAuditLog(roleID int64, modified time.Time, action enum.Action, auditLog *models.ComplianceAuditLog) error {
+	actorID, actorType := t.GetActor()
+	return t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
+		ActorID:          actorID,
+		ActorType:        actorType,
+		ResourceID:       []byte(strconv.FormatInt(roleID, 10)),
+		ResourceType:     enum.ResourceRole,
+		ResourceModified: modified,
+		Action:           action,
+		ChangeNotes:      auditLog.ChangeNotes,
+	})
+}
@@ -0,0 +1,210 @@
+package sqlite_test
+
+import (
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestListRoles() {
+	require := s.Require()
+	roles, err := s.store.ListRoles(s.ActorContext())
+	require.NoError(err, "could not list roles")
+	require.Len(roles, 3, "expected the built-in roles")
+
+	for _, role := range roles {
+		require.True(role.IsBuiltin, "expected role %q to be built-in", role.Title)
+		permissions, err := role.Permissions()
+		require.NoError(err, "expected role permissions to be fetched")
+		require.NotEmpty(permissions, "expected role %q to have permissions", role.Title)
+	}
+}
+
+func (s *storeTestSuite) TestCreateRole() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		role := newRole("Auditor", "travelrule:view", "accounts:view", "legalholds:view")
+		err := s.store.CreateRole(ctx, role, &models.ComplianceAuditLog{})
+		require.NoError(err, "could not create role")
+		require.NotZero(role.ID, "expected role id to be assigned")
+		require.False(role.IsBuiltin)
+		require.False(role.IsDefault)
+
+		cmp, err := s.store.RetrieveRole(ctx, role.ID)
+		require.NoError(err, "could not retrieve role")
+		require.Equal("Auditor", cmp.Title)
+		require.Equal([]string{"accounts:view", "travelrule:view", "legalholds:view"}, permissionTitles(cmp))
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceRole): 1,
+		})
+	})
+
+	s.Run("DuplicateTitle", func() {
+		err := s.store.CreateRole(s.ActorContext(), newRole("observer", "users:view"), &models.ComplianceAuditLog{})
+		s.Require().ErrorIs(err, errors.ErrAlreadyExists)
+	})
+
+	s.Run("WildcardTitle", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		// LIKE wildcards in a title must not match other roles
+		for _, title := range []string{"Obs%", "_bserver", "%"} {
+			role := newRole(title, "users:view")
+			require.NoError(s.store.CreateRole(ctx, role, &models.ComplianceAuditLog{}), "could not create role %q", title)
+		}
+
+		err := s.store.CreateRole(ctx, newRole("obs%", "users:view"), &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrAlreadyExists, "expected titles to be compared case-insensitively")
+	})
+
+	s.Run("UnknownPermission", func() {
+		err := s.store.CreateRole(s.ActorContext(), newRole("Bad", "users:view", "foo:bar"), &models.ComplianceAuditLog{})
+		s.Require().ErrorIs(err, errors.ErrUnknownPermission)
+	})
+}
+
+func (s *storeTestSuite) TestUpdateRole() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		role := newRole("Counterparty Manager", "counterparties:view")
+		require.NoError(s.store.CreateRole(ctx, role, &models.ComplianceAuditLog{}), "could not create role")
+
+		role.Description = "Manages counterparties"
+		role.SetPermissions(newPermissions("counterparties:manage", "counterparties:view"))
+		require.NoError(s.store.UpdateRole(ctx, role, &models.ComplianceAuditLog{}), "could not update role")
+
+		cmp, err := s.store.RetrieveRole(ctx, role.ID)
+		require.NoError(err, "could not retrieve role")
+		require.Equal("Manages counterparties", cmp.Description)
+		require.Equal([]string{"counterparties:manage", "counterparties:view"}, permissionTitles(cmp))
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceRole): 1,
+			ActionResourceKey(enum.ActionUpdate, enum.ResourceRole): 1,
+		})
+	})
+
+	s.Run("Builtin", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		role, err := s.store.LookupRole(ctx, "admin")
+		require.NoError(err, "could not lookup role")
+
+		role.SetPermissions(newPermissions("users:view"))
+		err = s.store.UpdateRole(ctx, role, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrBuiltinRole)
+	})
+
+	s.Run("NotFound", func() {
+		role := newRole("Missing", "users:view")
+		role.ID = 42
+		err := s.store.UpdateRole(s.ActorContext(), role, &models.ComplianceAuditLog{})
+		s.Require().ErrorIs(err, errors.ErrNotFound)
+	})
+}
+
+func (s *storeTestSuite) TestDeleteRole() {
+	s.Run("Success", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		role := newRole("Temporary", "users:view")
+		require.NoError(s.store.CreateRole(ctx, role, &models.ComplianceAuditLog{}), "could not create role")
+		require.NoError(s.store.DeleteRole(ctx, role.ID, &models.ComplianceAuditLog{}), "could not delete role")
+
+		_, err := s.store.RetrieveRole(ctx, role.ID)
+		require.ErrorIs(err, errors.ErrNotFound)
+
+		s.AssertAuditLogCount(map[string]int{
+			ActionResourceKey(enum.ActionCreate, enum.ResourceRole): 1,
+			ActionResourceKey(enum.ActionDelete, enum.ResourceRole): 1,
+		})
+	})
+
+	s.Run("Builtin", func() {
+		err := s.store.DeleteRole(s.ActorContext(), 3, &models.ComplianceAuditLog{})
+		s.Require().ErrorIs(err, errors.ErrBuiltinRole)
+	})
+
+	s.Run("InUse", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		role := newRole("Assigned", "users:view")
+		require.NoError(s.store.CreateRole(ctx, role, &models.ComplianceAuditLog{}), "could not create role")
+
+		user, err := s.store.RetrieveUser(ctx, ulid.MustParse("01HWQE347SRM7CBRSYM7QJ3M83"))
+		require.NoError(err, "could not retrieve user")
+		user.SetRole(role)
+		require.NoError(s.store.UpdateUser(ctx, user, &models.ComplianceAuditLog{}), "could not update user")
+
+		err = s.store.DeleteRole(ctx, role.ID, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrRoleInUse)
+
+		user, err = s.store.RetrieveUser(ctx, user.ID)
+		require.NoError(err, "could not retrieve user")
+		require.Equal([]string{"users:view"}, user.Permissions(), "expected user to have the custom role permissions")
+	})
+
+	s.Run("PendingInvite", func() {
+		require := s.Require()
+		ctx := s.ActorContext()
+
+		role := newRole("Invited", "users:view")
+		require.NoError(s.store.CreateRole(ctx, role, &models.ComplianceAuditLog{}), "could not create role")
+
+		invite := &models.UserInvite{Email: "pending@example.com", RoleID: role.ID, Expiration: time.Now().Add(time.Hour)}
+		require.NoError(s.store.CreateUserInvite(ctx, invite, &models.ComplianceAuditLog{}), "could not create user invite")
+
+		err := s.store.DeleteRole(ctx, role.ID, &models.ComplianceAuditLog{})
+		require.ErrorIs(err, errors.ErrRoleInUse)
+
+		invites, err := s.store.ListUserInvites(ctx)
+		require.NoError(err, "could not list user invites")
+		require.Len(invites, 1, "expected the pending invite to be retained")
+	})
+
+	s.Run("NotFound", func() {
+		err := s.store.DeleteRole(s.ActorContext(), 42, &models.ComplianceAuditLog{})
+		s.Require().ErrorIs(err, errors.ErrNotFound)
+	})
+}
+
+func (s *storeTestSuite) TestListPermissions() {
+	permissions, err := s.store.ListPermissions(s.ActorContext())
+	s.Require().NoError(err, "could not list permissions")
+	s.Require().Len(permissions, 21)
+}
+
+func newRole(title string, permissions ...string) *models.Role {
+	role := &models.Role{Title: title}
+	role.SetPermissions(newPermissions(permissions...))
+	return role
+}
+
+func newPermissions(titles ...string) []*models.Permission {
+	permissions := make([]*models.Permission, 0, len(titles))
+	for _, title := range titles {
+		permissions = append(permissions, &models.Permission{Title: title})
+	}
+	return permissions
+}
+
+func permissionTitles(role *models.Role) []string {
+	permissions, _ := role.Permissions()
+	titles := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		titles = append(titles, permission.Title)
+	}
+	return titles
+}
@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"database/sql"
 	"embed"
 	"fmt"
 	"io/fs"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/trisacrypto/envoy/pkg"
+	"go.rtnl.ai/ulid"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -43,6 +45,12 @@ func (s *Store) InitializeSchema(empty bool) (err error) {
 
 	for _, migration := range migrations {
 		if migration.ID > lastApplied {
+			if check, ok := preconditions[migration.ID]; ok {
+				if err = check(s.conn); err != nil {
+					return fmt.Errorf("cannot apply schema %d: %w", migration.ID, err)
+				}
+			}
+
 			var query string
 			if query, err = migration.SQL(); err != nil {
 				return err
@@ -61,6 +69,76 @@ func (s *Store) InitializeSchema(empty bool) (err error) {
 	return nil
 }
 
+// Preconditions are checked before the migration with the same ID is applied so that
+// a migration that cannot be applied to the existing data fails with an error that
+// describes the rows an operator must fix rather than modifying or dropping the rows.
+var preconditions = map[int]func(*sql.DB) error{
+	13: checkCaseInsensitiveEmails,
+}
+
+const (
+	caseConflictContactsSQL = "SELECT id, lower(trim(email)) FROM contacts WHERE lower(trim(email)) IN (SELECT lower(trim(email)) FROM contacts GROUP BY lower(trim(email)) HAVING count(*) > 1) ORDER BY lower(trim(email)), id"
+	caseConflictSunriseSQL  = "SELECT s.id, s.envelope_id || ':' || lower(trim(s.email)) FROM sunrise s WHERE (SELECT count(*) FROM sunrise r WHERE r.envelope_id = s.envelope_id AND lower(trim(r.email)) = lower(trim(s.email))) > 1 ORDER BY s.envelope_id, lower(trim(s.email)), s.id"
+)
+
+// The field encryption migration indexes contacts by their lowercased email, so the
+// contacts whose emails differ only by case, and the sunrise messages of a transaction
+// whose emails differ only by case, must be merged before the migration is applied.
+// The conflicts are listed by ID so that the error does not contain email addresses.
+func checkCaseInsensitiveEmails(conn *sql.DB) (err error) {
+	var conflicts []string
+	for _, check := range []struct {
+		resource string
+		query    string
+	}{
+		{"contacts", caseConflictContactsSQL},
+		{"sunrise messages", caseConflictSunriseSQL},
+	} {
+		var groups [][]string
+		if groups, err = caseConflicts(conn, check.query); err != nil {
+			return err
+		}
+
+		for _, ids := range groups {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s", check.resource, strings.Join(ids, ", ")))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("emails that differ only by case must be merged before field encryption can be enabled: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// Returns the IDs of the rows of the query grouped by the conflicting key.
+func caseConflicts(conn *sql.DB, query string) (groups [][]string, err error) {
+	var rows *sql.Rows
+	if rows, err = conn.Query(query); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prev string
+	for rows.Next() {
+		var (
+			id  ulid.ULID
+			key string
+		)
+
+		if err = rows.Scan(&id, &key); err != nil {
+			return nil, err
+		}
+
+		if len(groups) == 0 || key != prev {
+			groups = append(groups, nil)
+			prev = key
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], id.String())
+	}
+
+	return groups, rows.Err()
+}
+
 // Migrations contains the SQL commands from the migrations directory and is used to
 // ensure that the database has the most current and up to date schema.
 //
@@ -65,6 +65,121 @@ func TestMigrations(t *testing.T) {
 			Name: "Compliance Audit Log",
 			Path: "0009_compliance_audit_log.sql",
 		},
+		{
+			ID:   10,
+			Name: "Audit Log Indexes",
+			Path: "0010_audit_log_indexes.sql",
+		},
+		{
+			ID:   11,
+			Name: "Data Retention",
+			Path: "0011_data_retention.sql",
+		},
+		{
+			ID:   12,
+			Name: "Legal Holds",
+			Path: "0012_legal_holds.sql",
+		},
+		{
+			ID:   13,
+			Name: "Field Encryption",
+			Path: "0013_field_encryption.sql",
+		},
+		{
+			ID:   14,
+			Name: "Mfa",
+			Path: "0014_mfa.sql",
+		},
+		{
+			ID:   15,
+			Name: "Custom Roles",
+			Path: "0015_custom_roles.sql",
+		},
+		{
+			ID:   16,
+			Name: "Apikey Controls",
+			Path: "0016_apikey_controls.sql",
+		},
+		{
+			ID:   17,
+			Name: "Revocations",
+			Path: "0017_revocations.sql",
+		},
+		{
+			ID:   18,
+			Name: "Signing Keys",
+			Path: "0018_signing_keys.sql",
+		},
+		{
+			ID:   19,
+			Name: "Login Security",
+			Path: "0019_login_security.sql",
+		},
+		{
+			ID:   20,
+			Name: "User Invites",
+			Path: "0020_user_invites.sql",
+		},
+		{
+			ID:   21,
+			Name: "Approvals",
+			Path: "0021_approvals.sql",
+		},
+		{
+			ID:   22,
+			Name: "Case Management",
+			Path: "0022_case_management.sql",
+		},
+		{
+			ID:   23,
+			Name: "Reply Deadlines",
+			Path: "0023_reply_deadlines.sql",
+		},
+		{
+			ID:   24,
+			Name: "Pending Overrides",
+			Path: "0024_pending_overrides.sql",
+		},
+		{
+			ID:   25,
+			Name: "Status History",
+			Path: "0025_status_history.sql",
+		},
+		{
+			ID:   26,
+			Name: "Outbound Queue",
+			Path: "0026_outbound_queue.sql",
+		},
+		{
+			ID:   27,
+			Name: "Idempotency Keys",
+			Path: "0027_idempotency_keys.sql",
+		},
+		{
+			ID:   28,
+			Name: "Batches",
+			Path: "0028_batches.sql",
+		},
+		{
+			ID:   29,
+			Name: "Reports",
+			Path: "0029_reports.sql",
+		},
+		{
+			ID:   30,
+			Name: "Threshold Rules",
+			Path: "0030_threshold_rules.sql",
+		},
+		{
+			ID:   31,
+			Name: "Apikey Secret Rotation",
+			Path: "0031_apikey_secret_rotation.sql",
+		},
+		{
+			ID:   32,
+			Name: "Idempotency Transactions",
+			Path: "0032_idempotency_transactions.sql",
+		},
 	}
 
 	for i, migration := range migrations {
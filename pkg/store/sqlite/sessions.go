@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// User Sessions
+//===========================================================================
+
+const listUserSessionsSQL = "SELECT * FROM user_sessions WHERE user_id=:userID AND expires > :now ORDER BY last_used DESC"
+
+func (s *Store) ListUserSessions(ctx context.Context, userID ulid.ULID) (out []*models.UserSession, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListUserSessions(userID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists the sessions of the user that have not expired, most recently used first.
+func (t *Tx) ListUserSessions(userID ulid.ULID) (out []*models.UserSession, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listUserSessionsSQL, sql.Named("userID", userID), sql.Named("now", time.Now())); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.UserSession, 0)
+	for rows.Next() {
+		session := &models.UserSession{}
+		if err = session.Scan(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, session)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const (
+	createUserSessionSQL         = "INSERT INTO user_sessions (id, user_id, user_agent, ip_address, sso, last_used, expires, created, modified) VALUES (:id, :userID, :userAgent, :ipAddress, :sso, :lastUsed, :expires, :created, :modified)"
+	deleteExpiredUserSessionsSQL = "DELETE FROM user_sessions WHERE user_id=:userID AND expires <= :now"
+)
+
+func (s *Store) CreateUserSession(ctx context.Context, session *models.UserSession) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateUserSession(session); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Creates a session for the user, deleting the expired sessions of the user so that
+// the sessions table does not grow without bound.
+func (t *Tx) CreateUserSession(session *models.UserSession) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	if !session.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	session.ID = ulid.MakeSecure()
+	session.Created = time.Now()
+	session.Modified = session.Created
+
+	if session.LastUsed.IsZero() {
+		session.LastUsed = session.Created
+	}
+
+	if _, err = t.tx.Exec(deleteExpiredUserSessionsSQL, sql.Named("userID", session.UserID), sql.Named("now", session.Created)); err != nil {
+		return dbe(err)
+	}
+
+	if _, err = t.tx.Exec(createUserSessionSQL, session.Params()...); err != nil {
+		return dbe(err)
+	}
+	return nil
+}
+
+const refreshUserSessionSQL = "UPDATE user_sessions SET last_used=:now, expires=:expires, modified=:now WHERE id=:id AND user_id=:userID AND expires > :now"
+
+func (s *Store) RefreshUserSession(ctx context.Context, userID, sessionID ulid.ULID, expires time.Time) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.RefreshUserSession(userID, sessionID, expires); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Records that new tokens were issued for the session and extends the session until
+// the new refresh token expires. Returns ErrNotFound if the session has been revoked
+// or has expired so that the refresh token cannot be used.
+func (t *Tx) RefreshUserSession(userID, sessionID ulid.ULID, expires time.Time) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	params := []any{
+		sql.Named("id", sessionID),
+		sql.Named("userID", userID),
+		sql.Named("expires", expires),
+		sql.Named("now", time.Now()),
+	}
+
+	var result sql.Result
+	if result, err = t.tx.Exec(refreshUserSessionSQL, params...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+	return nil
+}
+
+const (
+	revokeUserSessionSQL  = "DELETE FROM user_sessions WHERE id=:id AND user_id=:userID"
+	revokeUserSessionsSQL = "DELETE FROM user_sessions WHERE user_id=:userID AND id<>:keep"
+)
+
+func (s *Store) RevokeUserSession(ctx context.Context, userID, sessionID ulid.ULID) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.RevokeUserSession(userID, sessionID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Revokes the session of the user; returns ErrNotFound if the session does not exist
+// or does not belong to the user.
+func (t *Tx) RevokeUserSession(userID, sessionID ulid.ULID) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	var result sql.Result
+	if result, err = t.tx.Exec(revokeUserSessionSQL, sql.Named("id", sessionID), sql.Named("userID", userID)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+	return nil
+}
+
+func (s *Store) RevokeUserSessions(ctx context.Context, userID, keep ulid.ULID) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.RevokeUserSessions(userID, keep); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Revokes all of the sessions of the user except for the session to keep, which may be
+// ulid.Null to revoke every session of the user.
+func (t *Tx) RevokeUserSessions(userID, keep ulid.ULID) (err error) {
+	//NOTE: user sessions do not require an audit log entry
+	if _, err = t.tx.Exec(revokeUserSessionsSQL, sql.Named("userID", userID), sql.Named("keep", keep)); err != nil {
+		return dbe(err)
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package sqlite_test
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestRecordUserLoginFailure() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	for i := 1; i < 3; i++ {
+		require.NoError(s.store.RecordUserLoginFailure(ctx, userID, 3, time.Minute, time.Hour), "could not record login failure")
+
+		user, err := s.store.RetrieveUser(ctx, userID)
+		require.NoError(err, "could not retrieve user")
+		require.Equal(int64(i), user.LoginFailures)
+		require.False(user.LoginLockedOut(time.Now()), "expected user to not be locked out before max failures")
+	}
+
+	// The user is locked out once the max failures is reached
+	require.NoError(s.store.RecordUserLoginFailure(ctx, userID, 3, time.Minute, time.Hour), "could not record login failure")
+
+	user, err := s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.Zero(user.LoginFailures, "expected failures to be reset when locked out")
+	require.Equal(int64(1), user.LoginLockouts)
+	require.True(user.LoginLockedOut(time.Now()), "expected user to be locked out")
+	require.False(user.LoginLockedOut(time.Now().Add(2*time.Minute)), "expected lockout to expire")
+
+	// The lockout is included in the user summary
+	page, err := s.store.ListUsers(ctx, &models.UserPageInfo{})
+	require.NoError(err, "could not list users")
+	for _, u := range page.Users {
+		require.Equal(u.ID == userID, u.LoginLockedOut(time.Now()), "expected only the locked user to be locked out in the user summary")
+	}
+
+	// Subsequent lockouts are doubled up to the max lockout
+	for i, expected := range []time.Duration{2 * time.Minute, 4 * time.Minute} {
+		for j := 0; j < 3; j++ {
+			require.NoError(s.store.RecordUserLoginFailure(ctx, userID, 3, time.Minute, 3*time.Minute), "could not record login failure")
+		}
+
+		user, err = s.store.RetrieveUser(ctx, userID)
+		require.NoError(err, "could not retrieve user")
+		require.Equal(int64(i+2), user.LoginLockouts)
+
+		expected = min(expected, 3*time.Minute)
+		require.WithinDuration(time.Now().Add(expected), user.LoginLocked.Time, 5*time.Second)
+	}
+
+	// A successful login resets the failures and the lockouts
+	require.NoError(s.store.ResetUserLoginFailures(ctx, userID), "could not reset login failures")
+	user, err = s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.Zero(user.LoginFailures)
+	require.Zero(user.LoginLockouts)
+	require.False(user.LoginLockedOut(time.Now()), "expected lockout to be reset")
+
+	err = s.store.RecordUserLoginFailure(ctx, ulid.MakeSecure(), 3, time.Minute, time.Hour)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.ResetUserLoginFailures(ctx, ulid.MakeSecure())
+	require.ErrorIs(err, errors.ErrNotFound)
+}
+
+func (s *storeTestSuite) TestUnlockUser() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	require.NoError(s.store.RecordUserLoginFailure(ctx, userID, 1, time.Hour, time.Hour), "could not record login failure")
+	user, err := s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.True(user.LoginLockedOut(time.Now()), "expected user to be locked out")
+
+	err = s.store.UnlockUser(ctx, userID, &models.ComplianceAuditLog{ChangeNotes: sql.NullString{Valid: true, String: "test"}})
+	require.NoError(err, "could not unlock user")
+
+	user, err = s.store.RetrieveUser(ctx, userID)
+	require.NoError(err, "could not retrieve user")
+	require.False(user.LoginLockedOut(time.Now()), "expected user to be unlocked")
+	require.Zero(user.LoginLockouts)
+
+	err = s.store.UnlockUser(ctx, ulid.MakeSecure(), &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceUser): 1,
+	})
+}
+
+func (s *storeTestSuite) TestUserSessions() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	sessions, err := s.store.ListUserSessions(ctx, userID)
+	require.NoError(err, "could not list user sessions")
+	require.Empty(sessions)
+
+	// Create sessions for the user
+	created := make([]*models.UserSession, 0, 3)
+	for i := 0; i < 3; i++ {
+		session := &models.UserSession{
+			UserID:    userID,
+			UserAgent: sql.NullString{Valid: true, String: "Mozilla/5.0"},
+			IPAddress: sql.NullString{Valid: true, String: "192.168.1.1"},
+			SSO:       i == 0,
+			Expires:   time.Now().Add(time.Hour),
+		}
+
+		require.NoError(s.store.CreateUserSession(ctx, session), "could not create user session")
+		require.False(session.ID.IsZero(), "expected an id to be assigned")
+		require.False(session.LastUsed.IsZero(), "expected last used to be set")
+		created = append(created, session)
+	}
+
+	err = s.store.CreateUserSession(ctx, created[0])
+	require.ErrorIs(err, errors.ErrNoIDOnCreate)
+
+	sessions, err = s.store.ListUserSessions(ctx, userID)
+	require.NoError(err, "could not list user sessions")
+	require.Len(sessions, 3)
+
+	// Refreshing a session marks it as the most recently used
+	expires := time.Now().Add(2 * time.Hour)
+	require.NoError(s.store.RefreshUserSession(ctx, userID, created[0].ID, expires), "could not refresh user session")
+
+	sessions, err = s.store.ListUserSessions(ctx, userID)
+	require.NoError(err, "could not list user sessions")
+	require.Equal(created[0].ID, sessions[0].ID)
+	require.True(sessions[0].SSO)
+	require.WithinDuration(expires, sessions[0].Expires, time.Second)
+
+	// Sessions of other users cannot be refreshed or revoked
+	err = s.store.RefreshUserSession(ctx, ulid.MakeSecure(), created[0].ID, expires)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.RevokeUserSession(ctx, ulid.MakeSecure(), created[0].ID)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	// A revoked session cannot be refreshed
+	require.NoError(s.store.RevokeUserSession(ctx, userID, created[1].ID), "could not revoke user session")
+	err = s.store.RefreshUserSession(ctx, userID, created[1].ID, expires)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.RevokeUserSession(ctx, userID, created[1].ID)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	// Revoke all sessions except the current session
+	require.NoError(s.store.RevokeUserSessions(ctx, userID, created[0].ID), "could not revoke user sessions")
+	sessions, err = s.store.ListUserSessions(ctx, userID)
+	require.NoError(err, "could not list user sessions")
+	require.Len(sessions, 1)
+	require.Equal(created[0].ID, sessions[0].ID)
+
+	// Revoke all sessions
+	require.NoError(s.store.RevokeUserSessions(ctx, userID, ulid.Null), "could not revoke user sessions")
+	sessions, err = s.store.ListUserSessions(ctx, userID)
+	require.NoError(err, "could not list user sessions")
+	require.Empty(sessions)
+}
+
+func (s *storeTestSuite) TestUserSessions_Expired() {
+	userID := ulid.MustParse("01HWQE29RW1S1D8ZN58M528A1M")
+
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	expired := &models.UserSession{UserID: userID, LastUsed: time.Now().Add(-2 * time.Hour), Expires: time.Now().Add(-1 * time.Hour)}
+	require.NoError(s.store.CreateUserSession(ctx, expired), "could not create user session")
+
+	// Expired sessions are not listed and cannot be refreshed
+	sessions, err := s.store.ListUserSessions(ctx, userID)
+	require.NoError(err, "could not list user sessions")
+	require.Empty(sessions)
+
+	err = s.store.RefreshUserSession(ctx, userID, expired.ID, time.Now().Add(time.Hour))
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	// Creating a new session deletes the expired sessions of the user
+	require.NoError(s.store.CreateUserSession(ctx, &models.UserSession{UserID: userID, Expires: time.Now().Add(time.Hour)}), "could not create user session")
+	err = s.store.RevokeUserSession(ctx, userID, expired.ID)
+	require.ErrorIs(err, errors.ErrNotFound, "expected expired session to be deleted")
+}
@@ -0,0 +1,110 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Signing Keys
+//===========================================================================
+
+const (
+	listSigningKeysSQL         = "SELECT * FROM signing_keys WHERE expires > :now ORDER BY not_before DESC, id DESC"
+	createSigningKeySQL        = "INSERT INTO signing_keys (id, private_key, not_before, not_after, expires, created, modified) VALUES (:id, :privateKey, :notBefore, :notAfter, :expires, :created, :modified)"
+	deleteExpiredSigningKeySQL = "DELETE FROM signing_keys WHERE expires <= :now"
+)
+
+// List the signing keys that have not expired ordered by the time they start signing
+// tokens, most recent first. The private keys are decrypted with the field cipher.
+func (s *Store) ListSigningKeys(ctx context.Context) (keys []*models.SigningKey, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rows *sql.Rows
+	if rows, err = tx.tx.Query(listSigningKeysSQL, sql.Named("now", time.Now())); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	keys = make([]*models.SigningKey, 0)
+	for rows.Next() {
+		key := &models.SigningKey{}
+		if err = key.Scan(rows); err != nil {
+			return nil, err
+		}
+
+		if key.PrivateKey, err = tx.cipher.Decrypt(key.PrivateKey); err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Store a signing key, encrypting the private key with the field cipher. If the key
+// does not have an ID one is assigned; the ID is the kid of the tokens it signs.
+func (s *Store) CreateSigningKey(ctx context.Context, key *models.SigningKey) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if key.ID.IsZero() {
+		key.ID = ulid.MakeSecure()
+	}
+
+	key.Created = time.Now()
+	key.Modified = key.Created
+
+	params := key.Params()
+	var encrypted []byte
+	if encrypted, err = tx.cipher.Encrypt(key.PrivateKey); err != nil {
+		return err
+	}
+	params[1] = sql.Named("privateKey", encrypted)
+
+	if _, err = tx.tx.Exec(createSigningKeySQL, params...); err != nil {
+		return dbe(err)
+	}
+
+	return tx.Commit()
+}
+
+// Delete the signing keys that have expired, returning the number of keys deleted.
+func (s *Store) DeleteExpiredSigningKeys(ctx context.Context) (deleted int64, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var result sql.Result
+	if result, err = tx.tx.Exec(deleteExpiredSigningKeySQL, sql.Named("now", time.Now())); err != nil {
+		return 0, dbe(err)
+	}
+
+	if deleted, err = result.RowsAffected(); err != nil {
+		return 0, err
+	}
+
+	return deleted, tx.Commit()
+}
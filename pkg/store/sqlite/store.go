@@ -11,6 +11,7 @@ import (
 	"github.com/trisacrypto/envoy/pkg/store/dsn"
 	"github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
 	"github.com/trisacrypto/envoy/pkg/store/txn"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -21,13 +22,15 @@ type Store struct {
 	readonly bool
 	conn     *sql.DB
 	mkta     models.TravelAddressFactory
+	cipher   *pii.Cipher
 }
 
 // Tx implements the store.Tx interface using SQLite3 as the storage backend.
 type Tx struct {
-	tx   *sql.Tx
-	opts *sql.TxOptions
-	mkta models.TravelAddressFactory
+	tx     *sql.Tx
+	opts   *sql.TxOptions
+	mkta   models.TravelAddressFactory
+	cipher *pii.Cipher
 
 	// Compliance audit log actor metadata
 	actorID   []byte
@@ -120,6 +123,7 @@ func (s *Store) BeginTx(ctx context.Context, opts *sql.TxOptions) (_ *Tx, err er
 		tx:        tx,
 		opts:      opts,
 		mkta:      s.mkta,
+		cipher:    s.cipher,
 		actorID:   actorID,
 		actorType: actorType,
 	}, nil
@@ -1,6 +1,7 @@
 package sqlite_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/trisacrypto/envoy/pkg/store/dsn"
 	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
 	db "github.com/trisacrypto/envoy/pkg/store/sqlite"
 	"github.com/trisacrypto/envoy/pkg/trisa/keychain"
 	"go.rtnl.ai/ulid"
@@ -98,9 +100,14 @@ type storeTestSuite struct {
 	suite.Suite
 	dbpath string
 	store  *db.Store
+	cipher *pii.Cipher
 }
 
 func (s *storeTestSuite) SetupSuite() {
+	var err error
+	s.cipher, err = pii.New(bytes.Repeat([]byte{0x42}, pii.KeySize))
+	s.Require().NoError(err, "could not create field encryption cipher")
+
 	s.CreateDB()
 	loadAuditKeyChainFixture(s.T())
 }
@@ -116,6 +123,15 @@ func (s *storeTestSuite) SetupSubtest() {
 }
 
 func (s *storeTestSuite) CreateDB() {
+	s.OpenFixturesDB()
+
+	// Encrypt the PII in the fixtures as the node does on startup
+	s.Require().NoError(s.store.UseFieldEncryption(s.cipher), "could not encrypt fixtures")
+}
+
+// Open the database and load the fixtures without configuring field encryption so
+// the PII in the fixtures is still plaintext and the blind indexes are not set.
+func (s *storeTestSuite) OpenFixturesDB() {
 	var err error
 	require := s.Require()
 
@@ -64,7 +64,7 @@ func (t *Tx) ListSunrise(page *models.PageInfo) (out *models.SunrisePage, err er
 	return out, nil
 }
 
-const createSunriseSQL = "INSERT INTO sunrise (id, envelope_id, email, expiration, signature, status, sent_on, verified_on, created, modified) VALUES (:id, :envelopeID, :email, :expiration, :signature, :status, :sentOn, :verifiedOn, :created, :modified)"
+const createSunriseSQL = "INSERT INTO sunrise (id, envelope_id, email, expiration, signature, status, sent_on, verified_on, created, modified, email_idx) VALUES (:id, :envelopeID, :email, :expiration, :signature, :status, :sentOn, :verifiedOn, :created, :modified, :emailIdx)"
 
 // Create a sunrise message in the database.
 func (s *Store) CreateSunrise(ctx context.Context, msg *models.Sunrise, auditLog *models.ComplianceAuditLog) (err error) {
@@ -96,8 +96,13 @@ func (t *Tx) CreateSunrise(msg *models.Sunrise, auditLog *models.ComplianceAudit
 	msg.Created = time.Now()
 	msg.Modified = msg.Created
 
+	var params []any
+	if params, err = t.sunriseParams(msg); err != nil {
+		return err
+	}
+
 	// Execute the insert into the database
-	if _, err = t.tx.Exec(createSunriseSQL, msg.Params()...); err != nil {
+	if _, err = t.tx.Exec(createSunriseSQL, params...); err != nil {
 		return dbe(err)
 	}
 
@@ -118,7 +123,7 @@ func (t *Tx) CreateSunrise(msg *models.Sunrise, auditLog *models.ComplianceAudit
 	return nil
 }
 
-const retrieveSunriseSQL = "SELECT * FROM sunrise WHERE id=:id"
+const retrieveSunriseSQL = "SELECT id, envelope_id, email, expiration, signature, status, sent_on, verified_on, created, modified FROM sunrise WHERE id=:id"
 
 // Retrieve sunrise message detail information.
 func (s *Store) RetrieveSunrise(ctx context.Context, id ulid.ULID) (msg *models.Sunrise, err error) {
@@ -141,13 +146,13 @@ func (s *Store) RetrieveSunrise(ctx context.Context, id ulid.ULID) (msg *models.
 // Retrieve sunrise message detail information.
 func (t *Tx) RetrieveSunrise(sunriseID ulid.ULID) (msg *models.Sunrise, err error) {
 	msg = &models.Sunrise{}
-	if err = msg.Scan(t.tx.QueryRow(retrieveSunriseSQL, sql.Named("id", sunriseID))); err != nil {
+	if err = msg.Scan(t.decrypt(t.tx.QueryRow(retrieveSunriseSQL, sql.Named("id", sunriseID)), encryptedSunriseFields(msg)...)); err != nil {
 		return nil, dbe(err)
 	}
 	return msg, nil
 }
 
-const updateSunriseSQL = "UPDATE sunrise SET envelope_id=:envelopeID, email=:email, expiration=:expiration, signature=:signature, status=:status, sent_on=:sentOn, verified_on=:verifiedOn, modified=:modified WHERE id=:id"
+const updateSunriseSQL = "UPDATE sunrise SET envelope_id=:envelopeID, email=:email, email_idx=:emailIdx, expiration=:expiration, signature=:signature, status=:status, sent_on=:sentOn, verified_on=:verifiedOn, modified=:modified WHERE id=:id"
 
 // Update sunrise message information.
 func (s *Store) UpdateSunrise(ctx context.Context, msg *models.Sunrise, auditLog *models.ComplianceAuditLog) (err error) {
@@ -174,9 +179,14 @@ func (t *Tx) UpdateSunrise(msg *models.Sunrise, auditLog *models.ComplianceAudit
 	// Update modified timestamp (in place).
 	msg.Modified = time.Now()
 
+	var params []any
+	if params, err = t.sunriseParams(msg); err != nil {
+		return err
+	}
+
 	// Execute the sunrise message into the database
 	var result sql.Result
-	if result, err = t.tx.Exec(updateSunriseSQL, msg.Params()...); err != nil {
+	if result, err = t.tx.Exec(updateSunriseSQL, params...); err != nil {
 		return dbe(err)
 	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
 		return dberr.ErrNotFound
@@ -301,7 +311,7 @@ func (t *Tx) DeleteSunrise(id ulid.ULID, auditLog *models.ComplianceAuditLog) (e
 }
 
 const (
-	lookupContactEmailSQL     = "SELECT counterparty_id FROM contacts WHERE LOWER(email)=LOWER(:email)"
+	lookupContactEmailSQL     = "SELECT counterparty_id FROM contacts WHERE email_idx=:emailIdx"
 	countCounterpartyNameSQL  = "SELECT count(id) FROM counterparties WHERE name LIKE :name"
 	lookupCounterpartyNameSQL = "SELECT id FROM counterparties WHERE name LIKE :name LIMIT 1"
 )
@@ -397,7 +407,7 @@ func (t *Tx) GetOrCreateSunriseCounterparty(email, name string, auditLog *models
 }
 
 func (t *Tx) lookupContactCounterparty(email string) (counterpartyID ulid.ULID, err error) {
-	if err = t.tx.QueryRow(lookupContactEmailSQL, sql.Named("email", email)).Scan(&counterpartyID); err != nil {
+	if err = t.tx.QueryRow(lookupContactEmailSQL, sql.Named("emailIdx", t.emailIndex(email))).Scan(&counterpartyID); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ulid.ULID{}, dberr.ErrNotFound
 		}
@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Threshold Rules CRUD interface
+//===========================================================================
+
+const listThresholdRulesSQL = "SELECT * FROM threshold_rules ORDER BY created ASC"
+
+func (s *Store) ListThresholdRules(ctx context.Context) (out []*models.ThresholdRule, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if out, err = tx.ListThresholdRules(); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Lists all of the threshold rules in the order they were created; rules are not
+// paginated since they are evaluated together whenever a transfer is evaluated.
+func (t *Tx) ListThresholdRules() (out []*models.ThresholdRule, err error) {
+	var rows *sql.Rows
+	if rows, err = t.tx.Query(listThresholdRulesSQL); err != nil {
+		return nil, dbe(err)
+	}
+	defer rows.Close()
+
+	out = make([]*models.ThresholdRule, 0)
+	for rows.Next() {
+		rule := &models.ThresholdRule{}
+		if err = rule.Scan(rows); err != nil {
+			return nil, err
+		}
+		out = append(out, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, dbe(err)
+	}
+	return out, nil
+}
+
+const createThresholdRuleSQL = "INSERT INTO threshold_rules (id, description, originator_country, beneficiary_country, virtual_asset, threshold, created, modified) VALUES (:id, :description, :originatorCountry, :beneficiaryCountry, :virtualAsset, :threshold, :created, :modified)"
+
+func (s *Store) CreateThresholdRule(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.CreateThresholdRule(rule, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) CreateThresholdRule(rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) (err error) {
+	if !rule.ID.IsZero() {
+		return dberr.ErrNoIDOnCreate
+	}
+
+	rule.ID = ulid.MakeSecure()
+	rule.Created = time.Now()
+	rule.Modified = rule.Created
+
+	if _, err = t.tx.Exec(createThresholdRuleSQL, rule.Params()...); err != nil {
+		return dbe(err)
+	}
+
+	return t.approvalAuditLog(rule.ID, enum.ResourceThresholdRule, rule.Modified, enum.ActionCreate, auditLog.ChangeNotes)
+}
+
+const retrieveThresholdRuleSQL = "SELECT * FROM threshold_rules WHERE id=:id"
+
+func (s *Store) RetrieveThresholdRule(ctx context.Context, ruleID ulid.ULID) (rule *models.ThresholdRule, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if rule, err = tx.RetrieveThresholdRule(ruleID); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (t *Tx) RetrieveThresholdRule(ruleID ulid.ULID) (rule *models.ThresholdRule, err error) {
+	rule = &models.ThresholdRule{}
+	if err = rule.Scan(t.tx.QueryRow(retrieveThresholdRuleSQL, sql.Named("id", ruleID))); err != nil {
+		return nil, dbe(err)
+	}
+	return rule, nil
+}
+
+const updateThresholdRuleSQL = "UPDATE threshold_rules SET description=:description, originator_country=:originatorCountry, beneficiary_country=:beneficiaryCountry, virtual_asset=:virtualAsset, threshold=:threshold, modified=:modified WHERE id=:id"
+
+// Update the criteria and threshold of a rule. Changing a rule does not change the
+// evaluation of transactions that were annotated before the rule was changed.
+func (s *Store) UpdateThresholdRule(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.UpdateThresholdRule(rule, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) UpdateThresholdRule(rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) (err error) {
+	if rule.ID.IsZero() {
+		return dberr.ErrMissingID
+	}
+
+	rule.Modified = time.Now()
+
+	var result sql.Result
+	if result, err = t.tx.Exec(updateThresholdRuleSQL, rule.Params()...); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	// Refresh the created timestamp of the rule from the database
+	var orig *models.ThresholdRule
+	if orig, err = t.RetrieveThresholdRule(rule.ID); err != nil {
+		return err
+	}
+	rule.Created = orig.Created
+
+	return t.approvalAuditLog(rule.ID, enum.ResourceThresholdRule, rule.Modified, enum.ActionUpdate, auditLog.ChangeNotes)
+}
+
+const deleteThresholdRuleSQL = "DELETE FROM threshold_rules WHERE id=:id"
+
+// Delete a threshold rule; transactions annotated by the rule keep their evaluation
+// but no longer reference the rule.
+func (s *Store) DeleteThresholdRule(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, nil); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = tx.DeleteThresholdRule(ruleID, auditLog); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (t *Tx) DeleteThresholdRule(ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	var result sql.Result
+	if result, err = t.tx.Exec(deleteThresholdRuleSQL, sql.Named("id", ruleID)); err != nil {
+		return dbe(err)
+	} else if nRows, _ := result.RowsAffected(); nRows == 0 {
+		return dberr.ErrNotFound
+	}
+
+	return t.approvalAuditLog(ruleID, enum.ResourceThresholdRule, time.Now(), enum.ActionDelete, auditLog.ChangeNotes)
+}
@@ -0,0 +1,110 @@
+package sqlite_test
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *storeTestSuite) TestThresholdRules() {
+	require := s.Require()
+	ctx := s.ActorContext()
+
+	rules, err := s.store.ListThresholdRules(ctx)
+	require.NoError(err, "could not list threshold rules")
+	require.Len(rules, 0, "expected no threshold rules in the fixtures")
+
+	rule := &models.ThresholdRule{
+		Description:       "US transfers of USD 3,000 or more",
+		OriginatorCountry: sql.NullString{Valid: true, String: "US"},
+		Threshold:         3000,
+	}
+
+	err = s.store.CreateThresholdRule(ctx, rule, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not create threshold rule")
+	require.False(rule.ID.IsZero(), "expected an id to be assigned")
+
+	// Create a second rule that applies to any transfer
+	err = s.store.CreateThresholdRule(ctx, &models.ThresholdRule{Description: "Everything"}, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not create threshold rule")
+
+	err = s.store.CreateThresholdRule(ctx, &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNoIDOnCreate)
+
+	rules, err = s.store.ListThresholdRules(ctx)
+	require.NoError(err, "could not list threshold rules")
+	require.Len(rules, 2)
+	require.Equal(rule.ID, rules[0].ID, "expected rules to be ordered by creation")
+	require.False(rules[1].OriginatorCountry.Valid, "expected a null originator country")
+	require.Equal(0.0, rules[1].Threshold)
+
+	cmp, err := s.store.RetrieveThresholdRule(ctx, rule.ID)
+	require.NoError(err, "could not retrieve threshold rule")
+	require.Equal(rule.Description, cmp.Description)
+	require.Equal("US", cmp.OriginatorCountry.String)
+	require.False(cmp.BeneficiaryCountry.Valid)
+	require.False(cmp.VirtualAsset.Valid)
+	require.Equal(3000.0, cmp.Threshold)
+
+	cmp.BeneficiaryCountry = sql.NullString{Valid: true, String: "DE"}
+	cmp.VirtualAsset = sql.NullString{Valid: true, String: "BTC"}
+	cmp.Threshold = 1000
+	err = s.store.UpdateThresholdRule(ctx, cmp, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not update threshold rule")
+	require.Equal(rule.Created.Unix(), cmp.Created.Unix(), "expected created timestamp to be refreshed")
+
+	cmp, err = s.store.RetrieveThresholdRule(ctx, rule.ID)
+	require.NoError(err, "could not retrieve threshold rule")
+	require.Equal("DE", cmp.BeneficiaryCountry.String)
+	require.Equal("BTC", cmp.VirtualAsset.String)
+	require.Equal(1000.0, cmp.Threshold)
+
+	err = s.store.UpdateThresholdRule(ctx, &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}}, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	// Annotate a transaction with the evaluation of the rule
+	txn := mock.GetSampleTransaction(true, true, false)
+	txn.ID = uuid.Nil
+	txn.CounterpartyID = ulid.NullULID{ULID: ulid.MustParse("01JXTQCDE6ZES5MPXNW7K19QVQ"), Valid: true}
+	txn.TravelRuleRequired = sql.NullBool{Valid: true, Bool: true}
+	txn.FiatAmount = sql.NullFloat64{Valid: true, Float64: 3250.5}
+	txn.FiatCurrency = sql.NullString{Valid: true, String: "USD"}
+	txn.ThresholdRuleID = ulid.NullULID{Valid: true, ULID: rule.ID}
+
+	err = s.store.CreateTransaction(ctx, txn, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not create transaction")
+
+	annotated, err := s.store.RetrieveTransaction(ctx, txn.ID)
+	require.NoError(err, "could not retrieve transaction")
+	require.True(annotated.TravelRuleRequired.Valid && annotated.TravelRuleRequired.Bool)
+	require.Equal(3250.5, annotated.FiatAmount.Float64)
+	require.Equal("USD", annotated.FiatCurrency.String)
+	require.Equal(rule.ID, annotated.ThresholdRuleID.ULID)
+
+	err = s.store.DeleteThresholdRule(ctx, rule.ID, &models.ComplianceAuditLog{})
+	require.NoError(err, "could not delete threshold rule")
+
+	_, err = s.store.RetrieveThresholdRule(ctx, rule.ID)
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	err = s.store.DeleteThresholdRule(ctx, rule.ID, &models.ComplianceAuditLog{})
+	require.ErrorIs(err, errors.ErrNotFound)
+
+	// Deleting the rule preserves the evaluation of the transaction
+	annotated, err = s.store.RetrieveTransaction(ctx, txn.ID)
+	require.NoError(err, "could not retrieve transaction")
+	require.True(annotated.TravelRuleRequired.Bool, "expected the evaluation to be preserved")
+	require.False(annotated.ThresholdRuleID.Valid, "expected the rule reference to be cleared")
+
+	s.AssertAuditLogCount(map[string]int{
+		ActionResourceKey(enum.ActionCreate, enum.ResourceThresholdRule): 2,
+		ActionResourceKey(enum.ActionUpdate, enum.ResourceThresholdRule): 1,
+		ActionResourceKey(enum.ActionDelete, enum.ResourceThresholdRule): 1,
+		ActionResourceKey(enum.ActionCreate, enum.ResourceTransaction):   1,
+	})
+}
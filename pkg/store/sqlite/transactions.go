@@ -20,7 +20,12 @@ import (
 // Transaction CRUD interface
 //==========================================================================
 
-const listTransactionsSQL = "SELECT t.id, t.source, t.status, t.counterparty, t.counterparty_id, t.originator, t.originator_address, t.beneficiary, t.beneficiary_address, t.virtual_asset, t.amount, t.archived, t.archived_on, t.last_update, t.modified, t.created, count(e.id) AS numEnvelopes FROM transactions t LEFT JOIN secure_envelopes e ON t.id=e.envelope_id WHERE t.archived=:archives GROUP BY t.id ORDER BY t.created DESC"
+const listTransactionsSQL = "SELECT t.id, t.source, t.status, t.counterparty, t.counterparty_id, t.originator, t.originator_address, t.beneficiary, t.beneficiary_address, t.virtual_asset, t.amount, t.archived, t.archived_on, t.last_update, t.modified, t.created, t.reply_not_after, t.reply_reminded_on, t.reply_expired_on, t.travel_rule_required, t.fiat_amount, t.fiat_currency, t.threshold_rule_id, t.assignee_id, u.name AS assignee, " + transactionTagsColumn + ", count(e.id) AS numEnvelopes FROM transactions t LEFT JOIN secure_envelopes e ON t.id=e.envelope_id LEFT JOIN users u ON t.assignee_id=u.id WHERE t.archived=:archives GROUP BY t.id ORDER BY t.created DESC"
+
+// Selects the tags of the transaction aliased as t aggregated into a single column.
+const overdueTransactionsFilter = "reply_not_after IS NOT NULL AND datetime(reply_not_after) < datetime(:now) AND status IN ('pending', 'review', 'repair')"
+
+const transactionTagsColumn = "(SELECT group_concat(tag, '" + models.TagSeparator + "') FROM transaction_tags WHERE transaction_id=t.id) AS tags"
 
 func (s *Store) ListTransactions(ctx context.Context, page *models.TransactionPageInfo) (out *models.TransactionPage, err error) {
 	var tx *Tx
@@ -46,6 +51,8 @@ func (t *Tx) ListTransactions(page *models.TransactionPageInfo) (out *models.Tra
 			PageInfo:     *models.PageInfoFrom(&page.PageInfo),
 			Status:       page.Status,
 			VirtualAsset: page.VirtualAsset,
+			Tags:         page.Tags,
+			Overdue:      page.Overdue,
 			Archives:     page.Archives,
 		},
 	}
@@ -55,8 +62,9 @@ func (t *Tx) ListTransactions(page *models.TransactionPageInfo) (out *models.Tra
 	params := []interface{}{sql.Named("archives", page.Archives)}
 
 	// If there are filters in the page query, then modify the SQL query with them.
-	if len(page.Status) > 0 || len(page.VirtualAsset) > 0 {
-		filters := make([]string, 0, 2)
+	tags := models.NormalizeTags(page.Tags)
+	if len(page.Status) > 0 || len(page.VirtualAsset) > 0 || len(tags) > 0 || page.Overdue {
+		filters := make([]string, 0, 4)
 		if len(page.Status) > 0 {
 			inquery, inparams := listParametrize(page.Status, "s")
 			filters = append(filters, "status IN "+inquery)
@@ -69,6 +77,19 @@ func (t *Tx) ListTransactions(page *models.TransactionPageInfo) (out *models.Tra
 			params = append(params, inparams...)
 		}
 
+		// Transactions that have any of the tags are returned
+		if len(tags) > 0 {
+			inquery, inparams := listParametrize(tags, "g")
+			filters = append(filters, "id IN (SELECT transaction_id FROM transaction_tags WHERE tag IN "+inquery+")")
+			params = append(params, inparams...)
+		}
+
+		// Overdue transactions are awaiting a reply whose deadline has passed
+		if page.Overdue {
+			filters = append(filters, overdueTransactionsFilter)
+			params = append(params, sql.Named("now", time.Now().UTC()))
+		}
+
 		query = "WITH txns AS (" + listTransactionsSQL + ") SELECT * FROM txns WHERE "
 		query += strings.Join(filters, " AND ")
 	}
@@ -81,7 +102,7 @@ func (t *Tx) ListTransactions(page *models.TransactionPageInfo) (out *models.Tra
 
 	for rows.Next() {
 		transaction := &models.Transaction{}
-		if err = transaction.ScanWithCount(rows); err != nil {
+		if err = transaction.ScanWithCount(t.decrypt(rows, encryptedTransactionFields(transaction)...)); err != nil {
 			return nil, err
 		}
 		out.Transactions = append(out.Transactions, transaction)
@@ -90,7 +111,104 @@ func (t *Tx) ListTransactions(page *models.TransactionPageInfo) (out *models.Tra
 	return out, nil
 }
 
-const createTransactionSQL = "INSERT INTO transactions (id, source, status, counterparty, counterparty_id, originator, originator_address, beneficiary, beneficiary_address, virtual_asset, amount, archived, archived_on, last_update, created, modified) VALUES (:id, :source, :status, :counterparty, :counterpartyID, :originator, :originatorAddress, :beneficiary, :beneficiaryAddress, :virtualAsset, :amount, :archived, :archivedOn, :lastUpdate, :created, :modified)"
+// IterateTransactions streams the transactions that match the filter in the same
+// order as ListTransactions. The read transaction is held open until the iterator is
+// closed so callers should close the iterator as soon as they are done with it.
+func (s *Store) IterateTransactions(ctx context.Context, filter *models.TransactionFilter) (_ models.TransactionIterator, err error) {
+	var tx *Tx
+	if tx, err = s.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err != nil {
+		return nil, err
+	}
+
+	if filter == nil {
+		filter = &models.TransactionFilter{}
+	}
+
+	// Create the base query and the query parameters list.
+	query := listTransactionsSQL
+	params := []interface{}{sql.Named("archives", filter.Archives)}
+
+	filters := make([]string, 0, 5)
+	if len(filter.Status) > 0 {
+		inquery, inparams := listParametrize(filter.Status, "s")
+		filters = append(filters, "status IN "+inquery)
+		params = append(params, inparams...)
+	}
+
+	if len(filter.VirtualAsset) > 0 {
+		inquery, inparams := listParametrize(filter.VirtualAsset, "a")
+		filters = append(filters, "virtual_asset IN "+inquery)
+		params = append(params, inparams...)
+	}
+
+	if !filter.CounterpartyID.IsZero() {
+		filters = append(filters, "counterparty_id=:counterpartyID")
+		params = append(params, sql.Named("counterpartyID", filter.CounterpartyID))
+	}
+
+	if !filter.After.IsZero() {
+		filters = append(filters, "datetime(created) >= datetime(:after)")
+		params = append(params, sql.Named("after", filter.After.UTC()))
+	}
+
+	if !filter.Before.IsZero() {
+		filters = append(filters, "datetime(created) < datetime(:before)")
+		params = append(params, sql.Named("before", filter.Before.UTC()))
+	}
+
+	if len(filters) > 0 {
+		query = "WITH txns AS (" + listTransactionsSQL + ") SELECT * FROM txns WHERE "
+		query += strings.Join(filters, " AND ")
+	}
+
+	var rows *sql.Rows
+	if rows, err = tx.tx.Query(query, params...); err != nil {
+		tx.Rollback()
+		return nil, dbe(err)
+	}
+
+	return &transactionIterator{tx: tx, rows: rows}, nil
+}
+
+type transactionIterator struct {
+	tx      *Tx
+	rows    *sql.Rows
+	current *models.Transaction
+	err     error
+}
+
+var _ models.TransactionIterator = &transactionIterator{}
+
+func (i *transactionIterator) Next() bool {
+	if i.err != nil || !i.rows.Next() {
+		return false
+	}
+
+	i.current = &models.Transaction{}
+	if i.err = i.current.ScanWithCount(i.tx.decrypt(i.rows, encryptedTransactionFields(i.current)...)); i.err != nil {
+		i.current = nil
+		return false
+	}
+	return true
+}
+
+func (i *transactionIterator) Transaction() *models.Transaction {
+	return i.current
+}
+
+func (i *transactionIterator) Err() error {
+	if i.err != nil {
+		return i.err
+	}
+	return dbe(i.rows.Err())
+}
+
+func (i *transactionIterator) Close() error {
+	i.rows.Close()
+	return i.tx.Rollback()
+}
+
+const createTransactionSQL = "INSERT INTO transactions (id, source, status, counterparty, counterparty_id, originator, originator_address, beneficiary, beneficiary_address, virtual_asset, amount, archived, archived_on, last_update, created, modified, originator_address_idx, beneficiary_address_idx, reply_not_after, travel_rule_required, fiat_amount, fiat_currency, threshold_rule_id) VALUES (:id, :source, :status, :counterparty, :counterpartyID, :originator, :originatorAddress, :beneficiary, :beneficiaryAddress, :virtualAsset, :amount, :archived, :archivedOn, :lastUpdate, :created, :modified, :originatorAddressIdx, :beneficiaryAddressIdx, :replyNotAfter, :travelRuleRequired, :fiatAmount, :fiatCurrency, :thresholdRuleID)"
 
 func (s *Store) CreateTransaction(ctx context.Context, transaction *models.Transaction, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
@@ -112,16 +230,30 @@ func (t *Tx) CreateTransaction(transaction *models.Transaction, auditLog *models
 		return dberr.ErrNoIDOnCreate
 	}
 
+	if err = validateTransition(nil, transaction); err != nil {
+		return err
+	}
+
 	// Create IDs and model metadata, updating the transaction in place
 	transaction.ID = uuid.New()
 	transaction.Created = time.Now()
 	transaction.Modified = transaction.Created
 
-	// Insert the transaction into the database
-	if _, err = t.tx.Exec(createTransactionSQL, transaction.Params()...); err != nil {
+	// Insert the transaction into the database with the PII fields encrypted
+	var params []any
+	if params, err = t.transactionParams(transaction); err != nil {
+		return err
+	}
+
+	if _, err = t.tx.Exec(createTransactionSQL, params...); err != nil {
 		return dbe(err)
 	}
 
+	// Record the initial status of the transaction in the status history
+	if _, err = t.transition(nil, transaction, ulid.NullULID{}); err != nil {
+		return err
+	}
+
 	// Fill the audit log and create it
 	actorID, actorType := t.GetActor()
 	if err := t.CreateComplianceAuditLog(&models.ComplianceAuditLog{
@@ -139,7 +271,7 @@ func (t *Tx) CreateTransaction(transaction *models.Transaction, auditLog *models
 	return nil
 }
 
-const retrieveTransactionSQL = "SELECT id, source, status, counterparty, counterparty_id, originator, originator_address, beneficiary, beneficiary_address, virtual_asset, amount, archived, archived_on, last_update, created, modified FROM transactions WHERE id=:id"
+const retrieveTransactionSQL = "SELECT t.id, t.source, t.status, t.counterparty, t.counterparty_id, t.originator, t.originator_address, t.beneficiary, t.beneficiary_address, t.virtual_asset, t.amount, t.archived, t.archived_on, t.last_update, t.created, t.modified, t.reply_not_after, t.reply_reminded_on, t.reply_expired_on, t.travel_rule_required, t.fiat_amount, t.fiat_currency, t.threshold_rule_id, t.assignee_id, u.name AS assignee, " + transactionTagsColumn + " FROM transactions t LEFT JOIN users u ON t.assignee_id=u.id WHERE t.id=:id"
 
 // Retrieve a transaction record by its ID and any related secure envelopes.
 func (s *Store) RetrieveTransaction(ctx context.Context, id uuid.UUID) (transaction *models.Transaction, err error) {
@@ -176,13 +308,17 @@ func (t *Tx) RetrieveTransaction(transactionID uuid.UUID) (transaction *models.T
 // Retrieve only a transaction record by its ID without associated secure envelopes.
 func (t *Tx) retrieveTransaction(transactionID uuid.UUID) (transaction *models.Transaction, err error) {
 	transaction = &models.Transaction{}
-	if err = transaction.Scan(t.tx.QueryRow(retrieveTransactionSQL, sql.Named("id", transactionID))); err != nil {
+	if err = transaction.Scan(t.decrypt(t.tx.QueryRow(retrieveTransactionSQL, sql.Named("id", transactionID)), encryptedTransactionFields(transaction)...)); err != nil {
 		return nil, dbe(err)
 	}
 	return transaction, nil
 }
 
-const updateTransactionSQL = "UPDATE transactions SET source=:source, status=:status, counterparty=:counterparty, counterparty_id=:counterpartyID, originator=:originator, originator_address=:originatorAddress, beneficiary=:beneficiary, beneficiary_address=:beneficiaryAddress, virtual_asset=:virtualAsset, amount=:amount, archived=:archived, archived_on=:archivedOn, last_update=:lastUpdate, modified=:modified WHERE id=:id"
+const updateTransactionSQL = "UPDATE transactions SET source=:source, status=:status, counterparty=:counterparty, counterparty_id=:counterpartyID, originator=:originator, originator_address=:originatorAddress, originator_address_idx=:originatorAddressIdx, beneficiary=:beneficiary, beneficiary_address=:beneficiaryAddress, beneficiary_address_idx=:beneficiaryAddressIdx, virtual_asset=:virtualAsset, amount=:amount, archived=:archived, archived_on=:archivedOn, last_update=:lastUpdate, reply_not_after=:replyNotAfter, travel_rule_required=:travelRuleRequired, fiat_amount=:fiatAmount, fiat_currency=:fiatCurrency, threshold_rule_id=:thresholdRuleID, " + resetReplyStateSQL + ", modified=:modified WHERE id=:id"
+
+// When the reply deadline of a transaction changes (e.g. a new pending message is
+// exchanged) the reminder and expiration of the previous deadline no longer apply.
+const resetReplyStateSQL = "reply_reminded_on=CASE WHEN reply_not_after IS :replyNotAfter THEN reply_reminded_on ELSE NULL END, reply_expired_on=CASE WHEN reply_not_after IS :replyNotAfter THEN reply_expired_on ELSE NULL END"
 
 func (s *Store) UpdateTransaction(ctx context.Context, t *models.Transaction, auditLog *models.ComplianceAuditLog) (err error) {
 	var tx *Tx
@@ -203,14 +339,30 @@ func (t *Tx) UpdateTransaction(transaction *models.Transaction, auditLog *models
 		return dberr.ErrMissingID
 	}
 
+	// Fetch the current transaction to validate the change of status and details
+	var orig *models.Transaction
+	if orig, err = t.retrieveTransaction(transaction.ID); err != nil {
+		return err
+	}
+
 	// Update modified timestamp (in place).
 	transaction.Modified = time.Now()
 
 	// NOTE: do not update `LastUpdate` timestamp - this refers to when a secure envelope is sent/received.
 
-	// Execute the update into the database
+	// Validate the transition and record the change of status (if any)
+	if _, err = t.transition(orig, transaction, ulid.NullULID{}); err != nil {
+		return err
+	}
+
+	// Execute the update into the database with the PII fields encrypted
+	var params []any
+	if params, err = t.transactionParams(transaction); err != nil {
+		return err
+	}
+
 	var result sql.Result
-	if result, err = t.tx.Exec(updateTransactionSQL, transaction.Params()...); err != nil {
+	if result, err = t.tx.Exec(updateTransactionSQL, params...); err != nil {
 		return dbe(err)
 	}
 
@@ -252,6 +404,14 @@ func (s *Store) DeleteTransaction(ctx context.Context, id uuid.UUID, auditLog *m
 }
 
 func (t *Tx) DeleteTransaction(id uuid.UUID, auditLog *models.ComplianceAuditLog) (err error) {
+	// Transactions under legal hold cannot be deleted
+	var held bool
+	if held, err = t.transactionOnHold(id); err != nil {
+		return err
+	} else if held {
+		return dberr.ErrLegalHold
+	}
+
 	var result sql.Result
 	if result, err = t.tx.Exec(deleteTransactionSQL, sql.Named("id", id)); err != nil {
 		return dbe(err)
@@ -295,6 +455,14 @@ func (s *Store) ArchiveTransaction(ctx context.Context, transactionID uuid.UUID,
 }
 
 func (t *Tx) ArchiveTransaction(transactionID uuid.UUID, auditLog *models.ComplianceAuditLog) (err error) {
+	// Transactions under legal hold cannot be archived
+	var held bool
+	if held, err = t.transactionOnHold(transactionID); err != nil {
+		return err
+	} else if held {
+		return dberr.ErrLegalHold
+	}
+
 	timestamp := time.Now()
 	params := []any{
 		sql.Named("id", transactionID),
@@ -715,6 +883,14 @@ func (s *Store) DeleteSecureEnvelope(ctx context.Context, txID uuid.UUID, envID
 }
 
 func (t *Tx) DeleteSecureEnvelope(txID uuid.UUID, envID ulid.ULID, auditLog *models.ComplianceAuditLog) (err error) {
+	// Secure envelopes of transactions under legal hold cannot be deleted
+	var held bool
+	if held, err = t.transactionOnHold(txID); err != nil {
+		return err
+	} else if held {
+		return dberr.ErrLegalHold
+	}
+
 	var result sql.Result
 	if result, err = t.tx.Exec(deleteSecureEnvelopeSQL, sql.Named("txID", txID), sql.Named("envID", envID)); err != nil {
 		return dbe(err)
@@ -839,7 +1015,10 @@ func (s *Store) PrepareTransaction(ctx context.Context, envelopeID uuid.UUID, au
 	}
 
 	// Check if a transaction exists with the specified envelope ID
-	var exists bool
+	var (
+		exists     bool
+		transition *models.StatusTransition
+	)
 	if err = tx.QueryRow(transactionExistsSQL, sql.Named("envelopeID", envelopeID)).Scan(&exists); err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("transaction existence check failed: %w", err)
@@ -860,11 +1039,23 @@ func (s *Store) PrepareTransaction(ctx context.Context, envelopeID uuid.UUID, au
 			Modified:     now,
 		}
 
-		if _, err = tx.Exec(createTransactionSQL, transaction.Params()...); err != nil {
+		var params []any
+		if params, err = tx.transactionParams(transaction); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if _, err = tx.Exec(createTransactionSQL, params...); err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("create transaction failed: %w", dbe(err))
 		}
 
+		// The creation of the transaction is linked to the first envelope stored
+		if transition, err = tx.transition(nil, transaction, ulid.NullULID{}); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("could not record transaction status: %w", err)
+		}
+
 		// Make an audit log note with this function name so we know why the
 		// ResourceID is not a Sunrise.ID
 		notes := sql.NullString{
@@ -892,13 +1083,27 @@ func (s *Store) PrepareTransaction(ctx context.Context, envelopeID uuid.UUID, au
 	}
 
 	// Create the prepared transaction for the user to interact with
-	return &PreparedTransaction{tx: tx, envelopeID: envelopeID, created: !exists}, nil
+	prepared := &PreparedTransaction{tx: tx, envelopeID: envelopeID, created: !exists}
+	if transition != nil {
+		prepared.unlinked = append(prepared.unlinked, unlinkedTransition{id: transition.ID})
+	}
+	return prepared, nil
 }
 
 type PreparedTransaction struct {
 	tx         *Tx
 	envelopeID uuid.UUID
 	created    bool
+	envelopes  []*models.SecureEnvelope
+	unlinked   []unlinkedTransition
+}
+
+// A status transition that has not been linked to the secure envelope that caused it.
+// The envelope is identified by its timestamp, which the postman sets as the last update
+// of the transaction; a transition without a timestamp is linked to the next envelope.
+type unlinkedTransition struct {
+	id        ulid.ULID
+	timestamp time.Time
 }
 
 func (p *PreparedTransaction) Created() bool {
@@ -907,7 +1112,7 @@ func (p *PreparedTransaction) Created() bool {
 
 func (p *PreparedTransaction) Fetch() (transaction *models.Transaction, err error) {
 	transaction = &models.Transaction{}
-	if err = transaction.Scan(p.tx.QueryRow(retrieveTransactionSQL, sql.Named("id", p.envelopeID))); err != nil {
+	if err = transaction.Scan(p.tx.decrypt(p.tx.QueryRow(retrieveTransactionSQL, sql.Named("id", p.envelopeID)), encryptedTransactionFields(transaction)...)); err != nil {
 		return nil, dbe(err)
 	}
 	return transaction, nil
@@ -920,16 +1125,35 @@ func (p *PreparedTransaction) Update(in *models.Transaction, auditLog *models.Co
 	}
 
 	// Fetch the previous transaction and update from the input only non-zero values
-	var orig *models.Transaction
-	if orig, err = p.Fetch(); err != nil {
+	var prev, orig *models.Transaction
+	if prev, err = p.Fetch(); err != nil {
 		return err
 	}
 
 	// Update orig with incoming values and updated modified timestamp
+	orig = &models.Transaction{}
+	*orig = *prev
 	orig.Update(in)
 	orig.Modified = time.Now()
 
-	if _, err = p.tx.Exec(updateTransactionSQL, orig.Params()...); err != nil {
+	// Validate the transition and record the change of status (if any)
+	var transition *models.StatusTransition
+	if transition, err = p.tx.transition(prev, orig, ulid.NullULID{}); err != nil {
+		return err
+	}
+
+	if transition != nil {
+		if err = p.link(unlinkedTransition{id: transition.ID, timestamp: in.LastUpdate.Time}); err != nil {
+			return err
+		}
+	}
+
+	var params []any
+	if params, err = p.tx.transactionParams(orig); err != nil {
+		return err
+	}
+
+	if _, err = p.tx.Exec(updateTransactionSQL, params...); err != nil {
 		return fmt.Errorf("could not update transaction: %w", dbe(err))
 	}
 
@@ -1060,6 +1284,20 @@ func (p *PreparedTransaction) AddEnvelope(in *models.SecureEnvelope, auditLog *m
 		return fmt.Errorf("could not add secure envelope: %w", dbe(err))
 	}
 
+	// Link any status changes that were caused by this envelope
+	p.envelopes = append(p.envelopes, in)
+	unlinked := p.unlinked[:0]
+	for _, transition := range p.unlinked {
+		if transition.timestamp.IsZero() || in.Timestamp.Equal(transition.timestamp) {
+			if err = p.tx.linkTransition(transition.id, in.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		unlinked = append(unlinked, transition)
+	}
+	p.unlinked = unlinked
+
 	// Fill the audit log and create it
 	actorID, actorType := p.tx.GetActor()
 	if err := p.tx.CreateComplianceAuditLog(&models.ComplianceAuditLog{
@@ -1077,6 +1315,22 @@ func (p *PreparedTransaction) AddEnvelope(in *models.SecureEnvelope, auditLog *m
 	return nil
 }
 
+// Links the status transition to the stored envelope with the same timestamp. If the
+// envelope has not been stored yet (or the transition does not have a timestamp) the
+// transition is linked when the envelope is stored.
+func (p *PreparedTransaction) link(transition unlinkedTransition) (err error) {
+	if !transition.timestamp.IsZero() {
+		for _, env := range p.envelopes {
+			if env.Timestamp.Equal(transition.timestamp) {
+				return p.tx.linkTransition(transition.id, env.ID)
+			}
+		}
+	}
+
+	p.unlinked = append(p.unlinked, transition)
+	return nil
+}
+
 func (p *PreparedTransaction) CreateSunrise(in *models.Sunrise, auditLog *models.ComplianceAuditLog) error {
 	return p.tx.CreateSunrise(in, auditLog)
 }
@@ -1089,6 +1343,15 @@ func (p *PreparedTransaction) UpdateSunriseStatus(txID uuid.UUID, status enum.St
 	return p.tx.UpdateSunriseStatus(txID, status, auditLog)
 }
 
+func (p *PreparedTransaction) Enqueue(in *models.QueuedTransfer, auditLog *models.ComplianceAuditLog) error {
+	in.TransactionID = p.envelopeID
+	return p.tx.CreateQueuedTransfer(in, auditLog)
+}
+
+func (p *PreparedTransaction) UpdateQueuedTransfer(in *models.QueuedTransfer, auditLog *models.ComplianceAuditLog) error {
+	return p.tx.UpdateQueuedTransfer(in, auditLog)
+}
+
 func (p *PreparedTransaction) Rollback() error {
 	return p.tx.Rollback()
 }
@@ -40,6 +40,67 @@ func (s *storeTestSuite) TestListTransactions() {
 	}
 }
 
+func (s *storeTestSuite) TestIterateTransactions() {
+	iterate := func(filter *models.TransactionFilter) (ids []string) {
+		require := s.Require()
+		iter, err := s.store.IterateTransactions(s.ActorContext(), filter)
+		require.NoError(err, "could not iterate transactions")
+		defer iter.Close()
+
+		for iter.Next() {
+			tx := iter.Transaction()
+			require.NotNil(tx, "expected a transaction from the iterator")
+			ids = append(ids, tx.ID.String())
+		}
+		require.NoError(iter.Err(), "unexpected error iterating transactions")
+		return ids
+	}
+
+	s.Run("All", func() {
+		ids := iterate(nil)
+		s.Require().Equal([]string{
+			"b04dc71c-7214-46a5-a514-381ef0bcc494",
+			"82624eee-2dab-45e6-abc0-df931fe2d832",
+			"2c891c75-14fa-4c71-aa07-6405b98db7a3",
+			"c20a7cdf-5c23-4b44-b7cd-a29cd00761a3",
+		}, ids, "expected the same order as the transactions list")
+	})
+
+	s.Run("Decrypted", func() {
+		require := s.Require()
+		iter, err := s.store.IterateTransactions(s.ActorContext(), &models.TransactionFilter{Status: []string{"pending"}})
+		require.NoError(err)
+		defer iter.Close()
+
+		require.True(iter.Next())
+		require.Equal("Mary Tilcott", iter.Transaction().Originator.String)
+		require.Equal(int64(2), iter.Transaction().NumEnvelopes())
+		require.False(iter.Next())
+	})
+
+	tests := []struct {
+		name     string
+		filter   *models.TransactionFilter
+		expected int
+	}{
+		{"Status", &models.TransactionFilter{Status: []string{"completed", "review"}}, 2},
+		{"Asset", &models.TransactionFilter{VirtualAsset: []string{"LTC"}}, 2},
+		{"Counterparty", &models.TransactionFilter{CounterpartyID: ulid.MustParse("01HWR5VWW8V7ZFFVJVBEC7AV8A")}, 2},
+		{"After", &models.TransactionFilter{After: time.Date(2024, 5, 15, 14, 49, 0, 0, time.UTC)}, 2},
+		{"Before", &models.TransactionFilter{Before: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}, 1},
+		{"Range", &models.TransactionFilter{After: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Before: time.Date(2024, 5, 16, 0, 0, 0, 0, time.UTC)}, 2},
+		{"Combined", &models.TransactionFilter{VirtualAsset: []string{"LTC"}, Status: []string{"draft"}}, 1},
+		{"Archives", &models.TransactionFilter{Archives: true}, 1},
+		{"NoResults", &models.TransactionFilter{VirtualAsset: []string{"DOGE"}}, 0},
+	}
+
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			s.Require().Len(iterate(tc.filter), tc.expected)
+		})
+	}
+}
+
 func (s *storeTestSuite) TestCreateTransaction() {
 	s.Run("Success", func() {
 		//setup
@@ -11,6 +11,7 @@ import (
 	"github.com/trisacrypto/envoy/pkg/store/dsn"
 	"github.com/trisacrypto/envoy/pkg/store/mock"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/store/pii"
 	"github.com/trisacrypto/envoy/pkg/store/secrets"
 	"github.com/trisacrypto/envoy/pkg/store/sqlite"
 	"github.com/trisacrypto/envoy/pkg/store/txn"
@@ -56,7 +57,18 @@ type Store interface {
 	UserStore
 	APIKeyStore
 	ResetPasswordLinkStore
+	UserInviteStore
 	ComplianceAuditLogStore
+	RetentionStore
+	LegalHoldStore
+	ApprovalStore
+	OutboundQueueStore
+	FieldEncryptionStore
+	SigningKeyStore
+	IdempotencyStore
+	BatchStore
+	ReportStore
+	ThresholdRuleStore
 }
 
 // Secrets is a generic storage interface for storing secrets such as private key
@@ -94,7 +106,10 @@ type Stats interface {
 // part of completing a travel rule exchange for the transaction.
 type TransactionStore interface {
 	SecureEnvelopeStore
+	TransactionCaseStore
+	ReplyDeadlineStore
 	ListTransactions(context.Context, *models.TransactionPageInfo) (*models.TransactionPage, error)
+	IterateTransactions(context.Context, *models.TransactionFilter) (models.TransactionIterator, error)
 	CreateTransaction(context.Context, *models.Transaction, *models.ComplianceAuditLog) error
 	RetrieveTransaction(context.Context, uuid.UUID) (*models.Transaction, error)
 	UpdateTransaction(context.Context, *models.Transaction, *models.ComplianceAuditLog) error
@@ -104,6 +119,26 @@ type TransactionStore interface {
 	PrepareTransaction(context.Context, uuid.UUID, *models.ComplianceAuditLog) (models.PreparedTransaction, error)
 	CountTransactions(context.Context) (*models.TransactionCounts, error)
 	TransactionState(context.Context, uuid.UUID) (archived bool, status enum.Status, err error)
+	ListStatusHistory(ctx context.Context, txID uuid.UUID) ([]*models.StatusTransition, error)
+}
+
+// TransactionCaseStore manages the notes, assignment, and tags that investigators use
+// to track the review of transactions. Notes cannot be modified once they are posted.
+type TransactionCaseStore interface {
+	ListTransactionNotes(ctx context.Context, txID uuid.UUID) ([]*models.TransactionNote, error)
+	CreateTransactionNote(context.Context, *models.TransactionNote, *models.ComplianceAuditLog) error
+	AssignTransaction(ctx context.Context, txID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error
+	ListTransactionTags(context.Context) ([]string, error)
+	SetTransactionTags(ctx context.Context, txID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error
+}
+
+// ReplyDeadlineStore is used by the deadline scheduler to find transactions that are
+// awaiting a reply and to record the reminders and expirations of their deadlines.
+type ReplyDeadlineStore interface {
+	ListReplyDeadlines(ctx context.Context, dueBefore time.Time) ([]*models.Transaction, error)
+	MarkReplyReminded(ctx context.Context, txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	MarkReplyExpired(ctx context.Context, txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	ListTransactionReviewers(context.Context) ([]*models.User, error)
 }
 
 // SecureEnvelopes are associated with individual transactions.
@@ -150,6 +185,7 @@ type CounterpartyStore interface {
 	RetrieveCounterparty(ctx context.Context, counterpartyID ulid.ULID) (*models.Counterparty, error)
 	LookupCounterparty(ctx context.Context, field, value string) (*models.Counterparty, error)
 	UpdateCounterparty(context.Context, *models.Counterparty, *models.ComplianceAuditLog) error
+	UpdateCounterpartyPending(ctx context.Context, counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error
 	DeleteCounterparty(ctx context.Context, counterpartyID ulid.ULID, auditLog *models.ComplianceAuditLog) error
 }
 
@@ -165,6 +201,29 @@ type TravelAddressStore interface {
 	UseTravelAddressFactory(models.TravelAddressFactory)
 }
 
+// FieldEncryptionStore configures the encryption of PII columns at rest and persists
+// the wrapped data encryption key when the key is managed by the keychain.
+type FieldEncryptionStore interface {
+	UseFieldEncryption(*pii.Cipher) error
+	RetrieveDataKey(context.Context) (*models.DataKey, error)
+	CreateDataKey(context.Context, *models.DataKey) error
+}
+
+// SigningKeyStore persists the keys used to sign access and refresh tokens so that they
+// can be shared by every replica of the node and rotated.
+type SigningKeyStore interface {
+	ListSigningKeys(context.Context) ([]*models.SigningKey, error)
+	CreateSigningKey(context.Context, *models.SigningKey) error
+	DeleteExpiredSigningKeys(context.Context) (int64, error)
+}
+
+// IdempotencyStore persists the responses to requests made with an Idempotency-Key
+// header so that retried requests can be replayed. Expired keys are not returned.
+type IdempotencyStore interface {
+	RetrieveIdempotencyKey(ctx context.Context, actorID []byte, key string) (*models.IdempotencyKey, error)
+	CreateIdempotencyKey(context.Context, *models.IdempotencyKey) error
+}
+
 // Sunrise store manages both contacts and counterparties.
 type SunriseStore interface {
 	ListSunrise(context.Context, *models.PageInfo) (*models.SunrisePage, error)
@@ -185,8 +244,36 @@ type UserStore interface {
 	SetUserPassword(ctx context.Context, userID ulid.ULID, password string) error
 	// NOTE: last login time update does not require an audit log entry:
 	SetUserLastLogin(ctx context.Context, userID ulid.ULID, lastLogin time.Time) error
+	// NOTE: recording logins does not require an audit log entry:
+	RecordUserLoginFailure(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) error
+	ResetUserLoginFailures(ctx context.Context, userID ulid.ULID) error
+	UnlockUser(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	// NOTE: user sessions do not require an audit log entry:
+	ListUserSessions(ctx context.Context, userID ulid.ULID) ([]*models.UserSession, error)
+	CreateUserSession(context.Context, *models.UserSession) error
+	RefreshUserSession(ctx context.Context, userID, sessionID ulid.ULID, expires time.Time) error
+	RevokeUserSession(ctx context.Context, userID, sessionID ulid.ULID) error
+	RevokeUserSessions(ctx context.Context, userID, keep ulid.ULID) error
 	DeleteUser(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	RetrieveRevokedUser(ctx context.Context, userID ulid.ULID) (*models.RevokedUser, error)
+	ListRoles(ctx context.Context) ([]*models.Role, error)
 	LookupRole(ctx context.Context, role string) (*models.Role, error)
+	// NOTE: starting MFA enrollment does not require an audit log entry:
+	SetUserMFASecret(ctx context.Context, userID ulid.ULID, secret string) error
+	EnableUserMFA(ctx context.Context, userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) error
+	SetUserRecoveryCodes(ctx context.Context, userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) error
+	// NOTE: using a recovery code does not require an audit log entry:
+	UseUserRecoveryCode(ctx context.Context, userID ulid.ULID, recoveryCode string) error
+	// NOTE: recording MFA verification attempts does not require an audit log entry:
+	SetUserMFAStep(ctx context.Context, userID ulid.ULID, step int64) error
+	RecordUserMFAFailure(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout time.Duration) error
+	ResetUserMFA(ctx context.Context, userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	SetRoleMFARequired(ctx context.Context, roleID int64, required bool, auditLog *models.ComplianceAuditLog) error
+	CreateRole(context.Context, *models.Role, *models.ComplianceAuditLog) error
+	RetrieveRole(ctx context.Context, roleID int64) (*models.Role, error)
+	UpdateRole(context.Context, *models.Role, *models.ComplianceAuditLog) error
+	DeleteRole(ctx context.Context, roleID int64, auditLog *models.ComplianceAuditLog) error
+	ListPermissions(ctx context.Context) ([]*models.Permission, error)
 }
 
 type APIKeyStore interface {
@@ -196,7 +283,12 @@ type APIKeyStore interface {
 	UpdateAPIKey(context.Context, *models.APIKey, *models.ComplianceAuditLog) error
 	// NOTE: last seen time update does not require an audit log entry:
 	SetAPIKeyLastSeen(ctx context.Context, keyID ulid.ULID, lastSeen time.Time) error
+	RotateAPIKey(context.Context, *models.APIKey, *models.ComplianceAuditLog) error
 	DeleteAPIKey(ctx context.Context, keyID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	RetrieveRevokedAPIKey(ctx context.Context, keyID ulid.ULID) (*models.RevokedAPIKey, error)
+	// NOTE: usage statistics do not require an audit log entry:
+	RecordAPIKeyUsage(ctx context.Context, keyID ulid.ULID, ip string, ts time.Time) error
+	ListAPIKeyUsage(ctx context.Context, keyID ulid.ULID, since time.Time) ([]*models.APIKeyUsage, error)
 }
 
 type ResetPasswordLinkStore interface {
@@ -208,6 +300,16 @@ type ResetPasswordLinkStore interface {
 	DeleteResetPasswordLink(context.Context, ulid.ULID) error
 }
 
+type UserInviteStore interface {
+	ListUserInvites(context.Context) ([]*models.UserInvite, error)
+	CreateUserInvite(context.Context, *models.UserInvite, *models.ComplianceAuditLog) error
+	RetrieveUserInvite(context.Context, ulid.ULID) (*models.UserInvite, error)
+	// NOTE: sending an invite does not require an audit log entry
+	UpdateUserInvite(context.Context, *models.UserInvite) error
+	DeleteUserInvite(context.Context, ulid.ULID, *models.ComplianceAuditLog) error
+	AcceptUserInvite(ctx context.Context, inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) error
+}
+
 type ComplianceAuditLogStore interface {
 	ListComplianceAuditLogs(context.Context, *models.ComplianceAuditLogPageInfo) (*models.ComplianceAuditLogPage, error)
 	CreateComplianceAuditLog(context.Context, *models.ComplianceAuditLog) error
@@ -215,6 +317,93 @@ type ComplianceAuditLogStore interface {
 	// NOTE: ComplianceAuditLogs are required to be immutable; do not create Update or Delete functions
 }
 
+// RetentionStore enforces data retention policies by archiving, purging, or
+// crypto-shredding all records of a resource that expired before the specified cutoff.
+// Each method returns the number of records affected. Records under legal hold must
+// never be archived, purged, or shredded and the audit trail must be preserved.
+type RetentionStore interface {
+	ArchiveExpiredTransactions(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	PurgeArchivedTransactions(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	ShredArchivedTransactions(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	ShredExpiredSecureEnvelopes(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	PurgeExpiredSunrise(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	PurgeExpiredAccounts(ctx context.Context, before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	// NOTE: no audit logs required for this resource
+	PurgeExpiredResetPasswordLinks(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LegalHoldStore manages legal holds on transactions, accounts, and counterparties.
+// Resources under an active legal hold cannot be deleted or archived; these methods
+// return errors.ErrLegalHold instead. Legal holds cannot be deleted, only released.
+type LegalHoldStore interface {
+	ListLegalHolds(context.Context, *models.LegalHoldPageInfo) (*models.LegalHoldPage, error)
+	CreateLegalHold(context.Context, *models.LegalHold, *models.ComplianceAuditLog) error
+	RetrieveLegalHold(context.Context, ulid.ULID) (*models.LegalHold, error)
+	UpdateLegalHold(context.Context, *models.LegalHold, *models.ComplianceAuditLog) error
+	ReleaseLegalHold(ctx context.Context, holdID ulid.ULID, releasedBy string, auditLog *models.ComplianceAuditLog) error
+}
+
+// ApprovalStore manages the rules that require four-eyes approval of transfer workflow
+// actions and the approvals of those actions. Approvals cannot be deleted; they are
+// approved or denied by a reviewer or canceled by the requester instead.
+type ApprovalStore interface {
+	ListApprovalRules(context.Context) ([]*models.ApprovalRule, error)
+	CreateApprovalRule(context.Context, *models.ApprovalRule, *models.ComplianceAuditLog) error
+	RetrieveApprovalRule(context.Context, ulid.ULID) (*models.ApprovalRule, error)
+	UpdateApprovalRule(context.Context, *models.ApprovalRule, *models.ComplianceAuditLog) error
+	DeleteApprovalRule(context.Context, ulid.ULID, *models.ComplianceAuditLog) error
+	ListApprovals(context.Context, *models.ApprovalPageInfo) (*models.ApprovalPage, error)
+	CreateApproval(context.Context, *models.Approval, *models.ComplianceAuditLog) error
+	RetrieveApproval(context.Context, ulid.ULID) (*models.Approval, error)
+	ClaimApproval(context.Context, ulid.ULID) error
+	ReleaseApproval(context.Context, ulid.ULID) error
+	ReviewApproval(context.Context, *models.Approval, *models.ComplianceAuditLog) error
+	ListApprovalReviewers(context.Context) ([]*models.User, error)
+}
+
+// OutboundQueueStore manages transfers that are waiting to be sent to counterparties
+// that were unreachable or that are scheduled to be sent later. Queued transfers are
+// not deleted; they are marked as sent, failed, or canceled instead.
+type OutboundQueueStore interface {
+	ListQueuedTransfers(context.Context) ([]*models.QueuedTransfer, error)
+	ListDueTransfers(ctx context.Context, before time.Time) ([]*models.QueuedTransfer, error)
+	CreateQueuedTransfer(context.Context, *models.QueuedTransfer, *models.ComplianceAuditLog) error
+	RetrieveQueuedTransfer(context.Context, ulid.ULID) (*models.QueuedTransfer, error)
+	UpdateQueuedTransfer(context.Context, *models.QueuedTransfer, *models.ComplianceAuditLog) error
+}
+
+// BatchStore manages batches of transfers that are imported from a file to be sent
+// together and the result of sending each transfer (item) in the batch.
+type BatchStore interface {
+	ListBatches(context.Context) ([]*models.Batch, error)
+	CreateBatch(context.Context, *models.Batch, *models.ComplianceAuditLog) error
+	RetrieveBatch(context.Context, ulid.ULID) (*models.Batch, error)
+	UpdateBatch(context.Context, *models.Batch, *models.ComplianceAuditLog) error
+	ListBatchItems(ctx context.Context, batchID ulid.ULID) ([]*models.BatchItem, error)
+	UpdateBatchItem(context.Context, *models.BatchItem) error
+}
+
+// ReportStore manages the regulatory reports that have been generated and provides
+// the envelope timings of the transactions in a period that reports are computed from.
+type ReportStore interface {
+	ListReports(context.Context) ([]*models.Report, error)
+	CreateReport(context.Context, *models.Report, *models.ComplianceAuditLog) error
+	RetrieveReport(context.Context, ulid.ULID) (*models.Report, error)
+	RetrieveScheduledReport(ctx context.Context, schedule string, periodStart time.Time) (*models.Report, error)
+	DeleteReport(context.Context, ulid.ULID, *models.ComplianceAuditLog) error
+	ListEnvelopeTimings(ctx context.Context, after, before time.Time) ([]*models.EnvelopeTiming, error)
+}
+
+// ThresholdRuleStore manages the jurisdiction-aware threshold rules that determine if
+// travel rule data is required for a transfer.
+type ThresholdRuleStore interface {
+	ListThresholdRules(context.Context) ([]*models.ThresholdRule, error)
+	CreateThresholdRule(context.Context, *models.ThresholdRule, *models.ComplianceAuditLog) error
+	RetrieveThresholdRule(context.Context, ulid.ULID) (*models.ThresholdRule, error)
+	UpdateThresholdRule(context.Context, *models.ThresholdRule, *models.ComplianceAuditLog) error
+	DeleteThresholdRule(context.Context, ulid.ULID, *models.ComplianceAuditLog) error
+}
+
 // Methods required for managing Daybreak records in the database. This interface allows
 // us to have a single transaction open for a daybreak operation so that with respect
 // to a single counterparty we completely create the record or rollback on failure.
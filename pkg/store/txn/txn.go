@@ -36,7 +36,12 @@ type Txn interface {
 	UserTxn
 	APIKeyTxn
 	ResetPasswordLinkTxn
+	UserInviteTxn
 	ComplianceAuditLogTxn
+	RetentionTxn
+	LegalHoldTxn
+	ApprovalTxn
+	OutboundQueueTxn
 }
 
 // TransactionTxn stores some lightweight information about specific transactions
@@ -45,6 +50,8 @@ type Txn interface {
 // part of completing a travel rule exchange for the transaction.
 type TransactionTxn interface {
 	SecureEnvelopeTxn
+	TransactionCaseTxn
+	ReplyDeadlineTxn
 	ListTransactions(*models.TransactionPageInfo) (*models.TransactionPage, error)
 	CreateTransaction(*models.Transaction, *models.ComplianceAuditLog) error
 	RetrieveTransaction(uuid.UUID) (*models.Transaction, error)
@@ -54,6 +61,26 @@ type TransactionTxn interface {
 	UnarchiveTransaction(uuid.UUID, *models.ComplianceAuditLog) error
 	CountTransactions() (*models.TransactionCounts, error)
 	TransactionState(uuid.UUID) (archived bool, status enum.Status, err error)
+	ListStatusHistory(txID uuid.UUID) ([]*models.StatusTransition, error)
+}
+
+// TransactionCaseTxn manages the notes, assignment, and tags that investigators use to
+// track the review of transactions. Notes cannot be modified once they are posted.
+type TransactionCaseTxn interface {
+	ListTransactionNotes(txID uuid.UUID) ([]*models.TransactionNote, error)
+	CreateTransactionNote(*models.TransactionNote, *models.ComplianceAuditLog) error
+	AssignTransaction(txID uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error
+	ListTransactionTags() ([]string, error)
+	SetTransactionTags(txID uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error
+}
+
+// ReplyDeadlineTxn finds transactions that are awaiting a reply and records the
+// reminders and expirations of their deadlines.
+type ReplyDeadlineTxn interface {
+	ListReplyDeadlines(dueBefore time.Time) ([]*models.Transaction, error)
+	MarkReplyReminded(txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	MarkReplyExpired(txID uuid.UUID, auditLog *models.ComplianceAuditLog) error
+	ListTransactionReviewers() ([]*models.User, error)
 }
 
 // SecureEnvelopes are associated with individual transactions.
@@ -98,6 +125,7 @@ type CounterpartyTxn interface {
 	RetrieveCounterparty(counterpartyID ulid.ULID) (*models.Counterparty, error)
 	LookupCounterparty(field, value string) (*models.Counterparty, error)
 	UpdateCounterparty(*models.Counterparty, *models.ComplianceAuditLog) error
+	UpdateCounterpartyPending(counterpartyID ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error
 	DeleteCounterparty(counterpartyID ulid.ULID, auditLog *models.ComplianceAuditLog) error
 }
 
@@ -129,8 +157,36 @@ type UserTxn interface {
 	SetUserPassword(userID ulid.ULID, password string) error
 	// NOTE: last login time update does not require an audit log entry:
 	SetUserLastLogin(userID ulid.ULID, lastLogin time.Time) error
+	// NOTE: recording logins does not require an audit log entry:
+	RecordUserLoginFailure(userID ulid.ULID, maxFailures int64, lockout, maxLockout time.Duration) error
+	ResetUserLoginFailures(userID ulid.ULID) error
+	UnlockUser(userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	// NOTE: user sessions do not require an audit log entry:
+	ListUserSessions(userID ulid.ULID) ([]*models.UserSession, error)
+	CreateUserSession(*models.UserSession) error
+	RefreshUserSession(userID, sessionID ulid.ULID, expires time.Time) error
+	RevokeUserSession(userID, sessionID ulid.ULID) error
+	RevokeUserSessions(userID, keep ulid.ULID) error
 	DeleteUser(userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	RetrieveRevokedUser(userID ulid.ULID) (*models.RevokedUser, error)
+	ListRoles() ([]*models.Role, error)
 	LookupRole(role string) (*models.Role, error)
+	// NOTE: starting MFA enrollment does not require an audit log entry:
+	SetUserMFASecret(userID ulid.ULID, secret string) error
+	EnableUserMFA(userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) error
+	SetUserRecoveryCodes(userID ulid.ULID, recoveryCodes []string, auditLog *models.ComplianceAuditLog) error
+	// NOTE: using a recovery code does not require an audit log entry:
+	UseUserRecoveryCode(userID ulid.ULID, recoveryCode string) error
+	// NOTE: recording MFA verification attempts does not require an audit log entry:
+	SetUserMFAStep(userID ulid.ULID, step int64) error
+	RecordUserMFAFailure(userID ulid.ULID, maxFailures int64, lockout time.Duration) error
+	ResetUserMFA(userID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	SetRoleMFARequired(roleID int64, required bool, auditLog *models.ComplianceAuditLog) error
+	CreateRole(*models.Role, *models.ComplianceAuditLog) error
+	RetrieveRole(roleID int64) (*models.Role, error)
+	UpdateRole(*models.Role, *models.ComplianceAuditLog) error
+	DeleteRole(roleID int64, auditLog *models.ComplianceAuditLog) error
+	ListPermissions() ([]*models.Permission, error)
 }
 
 type APIKeyTxn interface {
@@ -140,7 +196,12 @@ type APIKeyTxn interface {
 	UpdateAPIKey(*models.APIKey, *models.ComplianceAuditLog) error
 	// NOTE: last seen time update does not require an audit log entry:
 	SetAPIKeyLastSeen(keyID ulid.ULID, lastSeen time.Time) error
+	RotateAPIKey(*models.APIKey, *models.ComplianceAuditLog) error
 	DeleteAPIKey(keyID ulid.ULID, auditLog *models.ComplianceAuditLog) error
+	RetrieveRevokedAPIKey(keyID ulid.ULID) (*models.RevokedAPIKey, error)
+	// NOTE: usage statistics do not require an audit log entry:
+	RecordAPIKeyUsage(keyID ulid.ULID, ip string, ts time.Time) error
+	ListAPIKeyUsage(keyID ulid.ULID, since time.Time) ([]*models.APIKeyUsage, error)
 }
 
 type ResetPasswordLinkTxn interface {
@@ -152,6 +213,16 @@ type ResetPasswordLinkTxn interface {
 	DeleteResetPasswordLink(ulid.ULID) error
 }
 
+type UserInviteTxn interface {
+	ListUserInvites() ([]*models.UserInvite, error)
+	CreateUserInvite(*models.UserInvite, *models.ComplianceAuditLog) error
+	RetrieveUserInvite(ulid.ULID) (*models.UserInvite, error)
+	// NOTE: sending an invite does not require an audit log entry
+	UpdateUserInvite(*models.UserInvite) error
+	DeleteUserInvite(ulid.ULID, *models.ComplianceAuditLog) error
+	AcceptUserInvite(inviteID ulid.ULID, user *models.User, auditLog *models.ComplianceAuditLog) error
+}
+
 type ComplianceAuditLogTxn interface {
 	ListComplianceAuditLogs(*models.ComplianceAuditLogPageInfo) (*models.ComplianceAuditLogPage, error)
 	CreateComplianceAuditLog(*models.ComplianceAuditLog) error
@@ -159,6 +230,53 @@ type ComplianceAuditLogTxn interface {
 	// NOTE: ComplianceAuditLogs are required to be immutable; do not create Update or Delete functions
 }
 
+// RetentionTxn enforces data retention policies by archiving, purging, or
+// crypto-shredding all records of a resource that expired before the specified cutoff.
+type RetentionTxn interface {
+	ArchiveExpiredTransactions(before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	PurgeArchivedTransactions(before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	ShredArchivedTransactions(before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	ShredExpiredSecureEnvelopes(before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	PurgeExpiredSunrise(before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	PurgeExpiredAccounts(before time.Time, auditLog *models.ComplianceAuditLog) (int64, error)
+	// NOTE: no audit logs required for this resource
+	PurgeExpiredResetPasswordLinks(before time.Time) (int64, error)
+}
+
+// LegalHoldTxn manages legal holds on transactions, accounts, and counterparties.
+type LegalHoldTxn interface {
+	ListLegalHolds(*models.LegalHoldPageInfo) (*models.LegalHoldPage, error)
+	CreateLegalHold(*models.LegalHold, *models.ComplianceAuditLog) error
+	RetrieveLegalHold(ulid.ULID) (*models.LegalHold, error)
+	UpdateLegalHold(*models.LegalHold, *models.ComplianceAuditLog) error
+	ReleaseLegalHold(holdID ulid.ULID, releasedBy string, auditLog *models.ComplianceAuditLog) error
+}
+
+// ApprovalTxn manages approval rules and the approvals of transfer workflow actions.
+type ApprovalTxn interface {
+	ListApprovalRules() ([]*models.ApprovalRule, error)
+	CreateApprovalRule(*models.ApprovalRule, *models.ComplianceAuditLog) error
+	RetrieveApprovalRule(ulid.ULID) (*models.ApprovalRule, error)
+	UpdateApprovalRule(*models.ApprovalRule, *models.ComplianceAuditLog) error
+	DeleteApprovalRule(ulid.ULID, *models.ComplianceAuditLog) error
+	ListApprovals(*models.ApprovalPageInfo) (*models.ApprovalPage, error)
+	CreateApproval(*models.Approval, *models.ComplianceAuditLog) error
+	RetrieveApproval(ulid.ULID) (*models.Approval, error)
+	ClaimApproval(ulid.ULID) error
+	ReleaseApproval(ulid.ULID) error
+	ReviewApproval(*models.Approval, *models.ComplianceAuditLog) error
+	ListApprovalReviewers() ([]*models.User, error)
+}
+
+// OutboundQueueTxn manages transfers that are waiting to be sent to counterparties.
+type OutboundQueueTxn interface {
+	ListQueuedTransfers() ([]*models.QueuedTransfer, error)
+	ListDueTransfers(before time.Time) ([]*models.QueuedTransfer, error)
+	CreateQueuedTransfer(*models.QueuedTransfer, *models.ComplianceAuditLog) error
+	RetrieveQueuedTransfer(ulid.ULID) (*models.QueuedTransfer, error)
+	UpdateQueuedTransfer(*models.QueuedTransfer, *models.ComplianceAuditLog) error
+}
+
 // Methods required for managing Daybreak records in the database. This interface allows
 // us to have a single transaction open for a daybreak operation so that with respect
 // to a single counterparty we completely create the record or rollback on failure.
@@ -0,0 +1,271 @@
+/*
+Package thresholds evaluates transfers against the jurisdiction-aware threshold rules
+to determine if complete travel rule data (e.g. the name and address or national
+identification of the originator and the name of the beneficiary) is required.
+
+Each rule applies to transfers between an originator and a beneficiary jurisdiction
+of a virtual asset and requires travel rule data if the fiat-equivalent amount of the
+transfer is greater than or equal to the threshold of the rule. The jurisdiction of
+the local VASP is the country of the RegionInfo of the node and is used if the
+country of the local customer is unknown; the country of the counterparty VASP is
+used if the country of the remote customer is unknown.
+*/
+package thresholds
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/postman"
+	"github.com/trisacrypto/envoy/pkg/store"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"go.rtnl.ai/ulid"
+)
+
+// Evaluator loads the threshold rules from the store to evaluate transfers. A nil or
+// disabled evaluator does not evaluate transfers so that it can be used by servers
+// whether or not thresholds are enabled.
+type Evaluator struct {
+	conf    config.ThresholdsConfig
+	country string
+	store   store.ThresholdRuleStore
+}
+
+// Transfer describes the jurisdictions, virtual asset, and amount of a transfer that
+// is evaluated against the threshold rules. If the country of the originator or the
+// beneficiary is not known, the country of the local or counterparty VASP is used
+// depending on the source of the transfer.
+type Transfer struct {
+	Source              enum.Source // local for outgoing transfers and remote for incoming transfers
+	OriginatorCountry   string      // the alpha-2 country code of the originator, if known
+	BeneficiaryCountry  string      // the alpha-2 country code of the beneficiary, if known
+	CounterpartyCountry string      // the alpha-2 country code of the counterparty VASP, if known
+	VirtualAsset        string      // the representation of the network and asset type of the transfer
+	Amount              float64     // the amount of the transfer in the virtual asset
+}
+
+// Decision is the result of evaluating a transfer against the threshold rules.
+type Decision struct {
+	Required           bool                  // if travel rule data is required for the transfer
+	OriginatorCountry  string                // the originator jurisdiction the rules were matched with
+	BeneficiaryCountry string                // the beneficiary jurisdiction the rules were matched with
+	FiatAmount         sql.NullFloat64       // the fiat-equivalent amount, null if no rate is configured for the asset
+	Currency           string                // the fiat currency of the fiat amount and threshold
+	Rule               *models.ThresholdRule // the rule that applied to the transfer; nil if no rule matched
+}
+
+// New creates an evaluator for the thresholds configuration using the country of the
+// region of the node as the jurisdiction of the local VASP.
+func New(conf config.Config, store store.ThresholdRuleStore) *Evaluator {
+	return &Evaluator{
+		conf:    conf.Thresholds,
+		country: strings.ToUpper(strings.TrimSpace(conf.RegionInfo.Country)),
+		store:   store,
+	}
+}
+
+// Enabled returns true if transfers should be evaluated against the threshold rules.
+func (e *Evaluator) Enabled() bool {
+	return e != nil && e.conf.Enabled
+}
+
+// Evaluate the transfer against the threshold rules in the store. A nil decision is
+// returned if the evaluator is not enabled.
+func (e *Evaluator) Evaluate(ctx context.Context, transfer *Transfer) (_ *Decision, err error) {
+	if !e.Enabled() {
+		return nil, nil
+	}
+
+	var rules []*models.ThresholdRule
+	if rules, err = e.store.ListThresholdRules(ctx); err != nil {
+		return nil, err
+	}
+	return e.Decide(rules, transfer), nil
+}
+
+// Decide if travel rule data is required for the transfer using the specified rules.
+// If more than one rule matches the transfer, the most specific rule applies and if
+// several rules are equally specific, the rule with the lowest threshold applies. If
+// no rule matches the transfer or if the fiat-equivalent amount of the transfer is
+// unknown, travel rule data is required so that transfers are never under-reported.
+func (e *Evaluator) Decide(rules []*models.ThresholdRule, transfer *Transfer) *Decision {
+	decision := &Decision{
+		OriginatorCountry:  normalizeCountry(transfer.OriginatorCountry),
+		BeneficiaryCountry: normalizeCountry(transfer.BeneficiaryCountry),
+		FiatAmount:         e.FiatAmount(transfer.VirtualAsset, transfer.Amount),
+		Currency:           e.conf.Currency,
+	}
+
+	// Use the jurisdictions of the VASPs if the countries of the customers are unknown
+	local, remote := &decision.OriginatorCountry, &decision.BeneficiaryCountry
+	if transfer.Source == enum.SourceRemote {
+		local, remote = remote, local
+	}
+
+	if *local == "" {
+		*local = e.country
+	}
+
+	if *remote == "" {
+		*remote = normalizeCountry(transfer.CounterpartyCountry)
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(decision.OriginatorCountry, decision.BeneficiaryCountry, transfer.VirtualAsset) {
+			continue
+		}
+
+		if decision.Rule == nil || rule.Specificity() > decision.Rule.Specificity() || (rule.Specificity() == decision.Rule.Specificity() && rule.Threshold < decision.Rule.Threshold) {
+			decision.Rule = rule
+		}
+	}
+
+	decision.Required = decision.Rule == nil || !decision.FiatAmount.Valid || decision.FiatAmount.Float64 >= decision.Rule.Threshold
+	return decision
+}
+
+// FiatAmount converts the amount of the virtual asset into the fiat currency using the
+// configured rate of the asset. Rates are matched to the virtual asset in the same way
+// as the virtual asset criteria of rules, e.g. a BTC rate matches "Bitcoin (BTC)". If
+// more than one rate matches, the rates are checked in alphabetical order.
+func (e *Evaluator) FiatAmount(virtualAsset string, amount float64) sql.NullFloat64 {
+	assets := make([]string, 0, len(e.conf.Rates))
+	for asset := range e.conf.Rates {
+		assets = append(assets, asset)
+	}
+	sort.Strings(assets)
+
+	for _, asset := range assets {
+		if models.MatchVirtualAsset(asset, virtualAsset) {
+			return sql.NullFloat64{Valid: true, Float64: amount * e.conf.Rates[asset]}
+		}
+	}
+	return sql.NullFloat64{}
+}
+
+// Annotate evaluates the transfer in the payload and records the decision on the
+// transaction being prepared. The counterparty is the remote VASP of the transfer and
+// may be nil if it is not known. Nothing is recorded if the evaluator is not enabled.
+func (e *Evaluator) Annotate(ctx context.Context, db models.PreparedTransaction, source enum.Source, payload *trisa.Payload, counterparty *models.Counterparty) (err error) {
+	if !e.Enabled() || payload == nil {
+		return nil
+	}
+
+	var decision *Decision
+	if decision, err = e.Evaluate(ctx, TransferFromPayload(source, payload, counterparty)); err != nil {
+		return err
+	}
+
+	annotation := &models.Transaction{}
+	decision.Annotate(annotation)
+
+	return db.Update(annotation, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Evaluator.Annotate()"},
+	})
+}
+
+// Annotate the transaction with the decision.
+func (d *Decision) Annotate(tx *models.Transaction) {
+	tx.TravelRuleRequired = sql.NullBool{Valid: true, Bool: d.Required}
+	tx.FiatAmount = d.FiatAmount
+	tx.FiatCurrency = sql.NullString{Valid: d.FiatAmount.Valid && d.Currency != "", String: d.Currency}
+	tx.ThresholdRuleID = ulid.NullULID{}
+	if d.Rule != nil {
+		tx.ThresholdRuleID = ulid.NullULID{Valid: true, ULID: d.Rule.ID}
+	}
+}
+
+// TravelRule returns the API representation of the decision.
+func (d *Decision) TravelRule() *api.TravelRule {
+	out := &api.TravelRule{
+		Required:           d.Required,
+		OriginatorCountry:  d.OriginatorCountry,
+		BeneficiaryCountry: d.BeneficiaryCountry,
+	}
+
+	if d.FiatAmount.Valid {
+		out.FiatAmount = &d.FiatAmount.Float64
+		out.FiatCurrency = d.Currency
+	}
+
+	if d.Rule != nil {
+		out.RuleID = d.Rule.ID
+		out.Rule = d.Rule.Description
+		out.Threshold = &d.Rule.Threshold
+	}
+
+	return out
+}
+
+// TransferFromPayload describes the transfer in a TRISA payload for evaluation; the
+// countries of the originator and beneficiary are found in the identity payload.
+func TransferFromPayload(source enum.Source, payload *trisa.Payload, counterparty *models.Counterparty) *Transfer {
+	transfer := &Transfer{Source: source}
+	if counterparty != nil {
+		transfer.CounterpartyCountry = counterparty.Country.String
+	}
+
+	if payload.Transaction != nil {
+		data := &generic.Transaction{}
+		if err := payload.Transaction.UnmarshalTo(data); err == nil {
+			transfer.VirtualAsset = postman.VirtualAsset(data.Network, data.AssetType)
+			transfer.Amount = data.Amount
+		}
+	}
+
+	if payload.Identity != nil {
+		identity := &ivms101.IdentityPayload{}
+		if err := payload.Identity.UnmarshalTo(identity); err == nil {
+			if identity.Originator != nil {
+				transfer.OriginatorCountry = Country(identity.Originator.OriginatorPersons...)
+			}
+
+			if identity.Beneficiary != nil {
+				transfer.BeneficiaryCountry = Country(identity.Beneficiary.BeneficiaryPersons...)
+			}
+		}
+	}
+
+	return transfer
+}
+
+// Country returns the first country found for the persons; the country of residence
+// of a natural person or the country of registration of a legal person is preferred
+// to the country of their geographic addresses.
+func Country(persons ...*ivms101.Person) string {
+	var addresses []*ivms101.Address
+	for _, person := range persons {
+		if natural := person.GetNaturalPerson(); natural != nil {
+			if natural.CountryOfResidence != "" {
+				return normalizeCountry(natural.CountryOfResidence)
+			}
+			addresses = append(addresses, natural.GeographicAddresses...)
+		}
+
+		if legal := person.GetLegalPerson(); legal != nil {
+			if legal.CountryOfRegistration != "" {
+				return normalizeCountry(legal.CountryOfRegistration)
+			}
+			addresses = append(addresses, legal.GeographicAddresses...)
+		}
+	}
+
+	for _, address := range addresses {
+		if address != nil && address.Country != "" {
+			return normalizeCountry(address.Country)
+		}
+	}
+	return ""
+}
+
+func normalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
@@ -0,0 +1,262 @@
+package thresholds_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	store "github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"go.rtnl.ai/ulid"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+var (
+	anywhere = &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}, Description: "Anywhere", Threshold: 1000}
+	fromEU   = &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}, Description: "From Germany", OriginatorCountry: sql.NullString{Valid: true, String: "DE"}}
+	fromUS   = &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}, Description: "From US", OriginatorCountry: sql.NullString{Valid: true, String: "us"}, Threshold: 3000}
+	usToUS   = &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}, Description: "US to US", OriginatorCountry: sql.NullString{Valid: true, String: "US"}, BeneficiaryCountry: sql.NullString{Valid: true, String: "US"}, Threshold: 3000}
+	usToUS2  = &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}, Description: "US to US (lower)", OriginatorCountry: sql.NullString{Valid: true, String: "US"}, BeneficiaryCountry: sql.NullString{Valid: true, String: "US"}, Threshold: 250}
+	ethOnly  = &models.ThresholdRule{Model: models.Model{ID: ulid.MakeSecure()}, Description: "ETH", VirtualAsset: sql.NullString{Valid: true, String: "ETH"}, Threshold: 10}
+)
+
+func evaluator(country string) *thresholds.Evaluator {
+	conf := config.Config{
+		RegionInfo: config.RegionInfo{Country: country},
+		Thresholds: config.ThresholdsConfig{
+			Enabled:  true,
+			Currency: "USD",
+			Rates:    map[string]float64{"BTC": 60000, "ETH": 3000},
+		},
+	}
+	return thresholds.New(conf, nil)
+}
+
+func TestDecide(t *testing.T) {
+	rules := []*models.ThresholdRule{anywhere, fromEU, fromUS, usToUS, usToUS2, ethOnly}
+
+	testCases := []struct {
+		name     string
+		country  string
+		transfer *thresholds.Transfer
+		rule     *models.ThresholdRule
+		required bool
+		fiat     float64
+	}{
+		{
+			name:     "local country is the originator of outgoing transfers",
+			country:  "de",
+			transfer: &thresholds.Transfer{Source: enum.SourceLocal, VirtualAsset: "Bitcoin (BTC)", Amount: 0.0001},
+			rule:     fromEU,
+			required: true,
+			fiat:     6,
+		},
+		{
+			name:     "below threshold",
+			country:  "US",
+			transfer: &thresholds.Transfer{Source: enum.SourceLocal, VirtualAsset: "BTC", Amount: 0.01, CounterpartyCountry: "GB"},
+			rule:     fromUS,
+			required: false,
+			fiat:     600,
+		},
+		{
+			name:     "at threshold",
+			country:  "US",
+			transfer: &thresholds.Transfer{Source: enum.SourceLocal, VirtualAsset: "BTC", Amount: 0.05, CounterpartyCountry: "GB"},
+			rule:     fromUS,
+			required: true,
+			fiat:     3000,
+		},
+		{
+			name:     "customer country overrides local country",
+			country:  "US",
+			transfer: &thresholds.Transfer{Source: enum.SourceLocal, OriginatorCountry: "DE", VirtualAsset: "BTC", Amount: 0.01},
+			rule:     fromEU,
+			required: true,
+			fiat:     600,
+		},
+		{
+			name:     "most specific rule with the lowest threshold",
+			country:  "US",
+			transfer: &thresholds.Transfer{Source: enum.SourceLocal, VirtualAsset: "BTC", Amount: 0.01, CounterpartyCountry: "us"},
+			rule:     usToUS2,
+			required: true,
+			fiat:     600,
+		},
+		{
+			name:     "local country is the beneficiary of incoming transfers",
+			country:  "US",
+			transfer: &thresholds.Transfer{Source: enum.SourceRemote, VirtualAsset: "BTC", Amount: 0.01, CounterpartyCountry: "GB"},
+			rule:     anywhere,
+			required: false,
+			fiat:     600,
+		},
+		{
+			name:     "virtual asset rule",
+			country:  "SG",
+			transfer: &thresholds.Transfer{Source: enum.SourceRemote, VirtualAsset: "Ethereum (ETH)", Amount: 0.001, CounterpartyCountry: "GB"},
+			rule:     ethOnly,
+			required: false,
+			fiat:     3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decision := evaluator(tc.country).Decide(rules, tc.transfer)
+			require.Equal(t, tc.rule, decision.Rule)
+			require.Equal(t, tc.required, decision.Required)
+			require.True(t, decision.FiatAmount.Valid)
+			require.InDelta(t, tc.fiat, decision.FiatAmount.Float64, 1e-9)
+			require.Equal(t, "USD", decision.Currency)
+		})
+	}
+
+	t.Run("NoRules", func(t *testing.T) {
+		decision := evaluator("US").Decide(nil, &thresholds.Transfer{Source: enum.SourceLocal, VirtualAsset: "BTC", Amount: 0.0001})
+		require.Nil(t, decision.Rule)
+		require.True(t, decision.Required, "expected travel rule data to be required if no rules match")
+		require.Equal(t, "US", decision.OriginatorCountry)
+		require.Equal(t, "", decision.BeneficiaryCountry)
+	})
+
+	t.Run("NoRate", func(t *testing.T) {
+		decision := evaluator("US").Decide(rules, &thresholds.Transfer{Source: enum.SourceLocal, VirtualAsset: "LTC", Amount: 0.0001, CounterpartyCountry: "GB"})
+		require.Equal(t, fromUS, decision.Rule)
+		require.False(t, decision.FiatAmount.Valid)
+		require.True(t, decision.Required, "expected travel rule data to be required if the fiat amount is unknown")
+
+		tx := &models.Transaction{}
+		decision.Annotate(tx)
+		require.True(t, tx.TravelRuleRequired.Valid && tx.TravelRuleRequired.Bool)
+		require.False(t, tx.FiatAmount.Valid)
+		require.False(t, tx.FiatCurrency.Valid)
+		require.Equal(t, fromUS.ID, tx.ThresholdRuleID.ULID)
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	db, err := store.Open(nil)
+	require.NoError(t, err, "could not open mock store")
+
+	t.Run("Disabled", func(t *testing.T) {
+		var nilEvaluator *thresholds.Evaluator
+		require.False(t, nilEvaluator.Enabled())
+
+		disabled := thresholds.New(config.Config{}, db)
+		require.False(t, disabled.Enabled())
+
+		decision, err := disabled.Evaluate(context.Background(), &thresholds.Transfer{})
+		require.NoError(t, err)
+		require.Nil(t, decision)
+		db.AssertCalls(t, "ListThresholdRules", 0)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		db.OnListThresholdRules = func(context.Context) ([]*models.ThresholdRule, error) {
+			return []*models.ThresholdRule{fromUS}, nil
+		}
+		defer db.Reset()
+
+		conf := config.Config{RegionInfo: config.RegionInfo{Country: "US"}, Thresholds: config.ThresholdsConfig{Enabled: true, Currency: "USD", Rates: map[string]float64{"BTC": 60000}}}
+		decision, err := thresholds.New(conf, db).Evaluate(context.Background(), &thresholds.Transfer{Source: enum.SourceLocal, VirtualAsset: "BTC", Amount: 1})
+		require.NoError(t, err)
+		require.True(t, decision.Required)
+		require.Equal(t, fromUS, decision.Rule)
+		db.AssertCalls(t, "ListThresholdRules", 1)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		db.OnListThresholdRules = func(context.Context) ([]*models.ThresholdRule, error) {
+			return nil, errors.New("whoopsie")
+		}
+		defer db.Reset()
+
+		_, err := evaluatorWithStore(db).Evaluate(context.Background(), &thresholds.Transfer{})
+		require.EqualError(t, err, "whoopsie")
+	})
+}
+
+func TestTransferFromPayload(t *testing.T) {
+	identity := &ivms101.IdentityPayload{
+		Originator: &ivms101.Originator{
+			OriginatorPersons: []*ivms101.Person{
+				{
+					Person: &ivms101.Person_NaturalPerson{
+						NaturalPerson: &ivms101.NaturalPerson{
+							GeographicAddresses: []*ivms101.Address{{Country: "de"}},
+						},
+					},
+				},
+			},
+		},
+		Beneficiary: &ivms101.Beneficiary{
+			BeneficiaryPersons: []*ivms101.Person{
+				{
+					Person: &ivms101.Person_NaturalPerson{
+						NaturalPerson: &ivms101.NaturalPerson{
+							CountryOfResidence:  "FR",
+							GeographicAddresses: []*ivms101.Address{{Country: "BE"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	payload := &trisa.Payload{}
+	var err error
+	payload.Identity, err = anypb.New(identity)
+	require.NoError(t, err)
+	payload.Transaction, err = anypb.New(&generic.Transaction{Network: "Bitcoin", AssetType: "BTC", Amount: 0.5})
+	require.NoError(t, err)
+
+	counterparty := &models.Counterparty{Country: sql.NullString{Valid: true, String: "FR"}}
+	transfer := thresholds.TransferFromPayload(enum.SourceRemote, payload, counterparty)
+	require.Equal(t, &thresholds.Transfer{
+		Source:              enum.SourceRemote,
+		OriginatorCountry:   "DE",
+		BeneficiaryCountry:  "FR",
+		CounterpartyCountry: "FR",
+		VirtualAsset:        "Bitcoin (BTC)",
+		Amount:              0.5,
+	}, transfer)
+
+	// An empty payload has no countries or asset
+	transfer = thresholds.TransferFromPayload(enum.SourceLocal, &trisa.Payload{}, nil)
+	require.Equal(t, &thresholds.Transfer{Source: enum.SourceLocal}, transfer)
+}
+
+func TestCountry(t *testing.T) {
+	legal := &ivms101.Person{
+		Person: &ivms101.Person_LegalPerson{
+			LegalPerson: &ivms101.LegalPerson{CountryOfRegistration: "sg "},
+		},
+	}
+
+	addressed := &ivms101.Person{
+		Person: &ivms101.Person_NaturalPerson{
+			NaturalPerson: &ivms101.NaturalPerson{
+				GeographicAddresses: []*ivms101.Address{{}, {Country: "US"}},
+			},
+		},
+	}
+
+	require.Equal(t, "", thresholds.Country())
+	require.Equal(t, "", thresholds.Country(&ivms101.Person{}))
+	require.Equal(t, "SG", thresholds.Country(legal))
+	require.Equal(t, "US", thresholds.Country(addressed))
+	require.Equal(t, "SG", thresholds.Country(addressed, legal), "expected registration to be preferred to addresses")
+}
+
+func evaluatorWithStore(db *store.Store) *thresholds.Evaluator {
+	return thresholds.New(config.Config{Thresholds: config.ThresholdsConfig{Enabled: true}}, db)
+}
@@ -3,27 +3,31 @@ package trisa
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"io"
 	"sync"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"github.com/trisacrypto/envoy/pkg/enum"
 	"github.com/trisacrypto/envoy/pkg/logger"
 	"github.com/trisacrypto/envoy/pkg/postman"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
 	"github.com/trisacrypto/envoy/pkg/trisa/peers"
 	"github.com/trisacrypto/envoy/pkg/webhook"
 
 	api "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
-	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
 	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/anypb"
 )
 
-var internalError = status.Error(codes.Internal, "unable to process secure envelope")
+var (
+	internalError   = status.Error(codes.Internal, "unable to process secure envelope")
+	transitionError = status.Error(codes.FailedPrecondition, "transfer is completed or rejected or cannot move to the requested transfer state")
+)
 
 //===========================================================================
 // Transfer and TransferStream gRPC Handlers
@@ -200,6 +204,9 @@ func (s *Server) Handle(ctx context.Context, p *postman.TRISAPacket) (err error)
 	// TODO: this may return an invalid counterparty error, which should return a different status error
 	if err = p.In.UpdateTransaction(); err != nil {
 		p.Log.Warn().Err(err).Bool("stored_to_database", false).Msg("could not update transaction details and counterparty information")
+		if isTransitionError(err) {
+			return transitionError
+		}
 		return internalError
 	}
 
@@ -241,6 +248,9 @@ func (s *Server) Handle(ctx context.Context, p *postman.TRISAPacket) (err error)
 	// Update the transaction with the outgoing message info
 	if err = p.Out.UpdateTransaction(); err != nil {
 		p.Log.Error().Err(err).Bool("stored_to_database", false).Msg("could not update transaction with outgoing info in database")
+		if isTransitionError(err) {
+			return transitionError
+		}
 		return internalError
 	}
 
@@ -299,8 +309,20 @@ func (s *Server) HandleSealed(ctx context.Context, p *postman.TRISAPacket) (err
 		return p.Error(reject)
 	}
 
+	// Record if travel rule data is required for new incoming transfers by the threshold
+	// rules; if the evaluation fails, log the error but do not cancel processing.
+	txn := postman.TransactionFromPayload(payload)
+	if p.DB.Created() {
+		var decision *thresholds.Decision
+		if decision, err = s.rules.Evaluate(ctx, thresholds.TransferFromPayload(enum.SourceRemote, payload, p.Counterparty)); err != nil {
+			p.Log.Warn().Err(err).Msg("could not evaluate transfer against travel rule threshold rules")
+		} else if decision != nil {
+			decision.Annotate(txn)
+		}
+	}
+
 	// Update transaction with decrypted details if available
-	if err = p.DB.Update(postman.TransactionFromPayload(payload), &models.ComplianceAuditLog{
+	if err = p.DB.Update(txn, &models.ComplianceAuditLog{
 		ChangeNotes: sql.NullString{Valid: true, String: "Server.HandleSealed()"},
 	}); err != nil {
 		p.Log.Error().Err(err).Msg("could not update transaction in database with decrypted details")
@@ -442,7 +464,7 @@ func (s *Server) DefaultResponse(payload *api.Payload, p *postman.TRISAPacket) (
 	switch p.In.TransferState() {
 	// If the incoming state is unspecified, started, review, or repair, return pending
 	case api.TransferStateUnspecified, api.TransferStarted, api.TransferReview, api.TransferRepair:
-		if payload, err = pendingPayload(payload, p.EnvelopeID()); err != nil {
+		if payload, err = p.PendingPayload(payload, p.EnvelopeID(), s.conf.Pending); err != nil {
 			p.Log.Error().Err(err).Msg("could not create outgoing pending payload")
 			return internalError
 		}
@@ -477,37 +499,6 @@ func (s *Server) DefaultResponse(payload *api.Payload, p *postman.TRISAPacket) (
 // Helper Methods
 //===========================================================================
 
-func pendingPayload(in *api.Payload, envelopeID string) (out *api.Payload, err error) {
-	ts := time.Now().UTC()
-	out = &api.Payload{
-		Identity:   in.Identity,
-		SentAt:     in.SentAt,
-		ReceivedAt: ts.Format(time.RFC3339),
-	}
-
-	// TODO: populate pending from configuration
-	pending := &generic.Pending{
-		EnvelopeId:     envelopeID,
-		ReceivedBy:     "TRISA Envoy Node",
-		ReceivedAt:     ts.Format(time.RFC3339),
-		Message:        "We are reviewing your travel rule exchange request and will reply once we have completed our internal compliance checks",
-		ReplyNotAfter:  ts.Add(24 * time.Hour).Format(time.RFC3339),
-		ReplyNotBefore: ts.Add(5 * time.Minute).Format(time.RFC3339),
-		Transaction:    &generic.Transaction{},
-	}
-
-	// If we've received a transaction, add it to the pending response
-	// NOTE: ignoring errors here, expecting transaction to be nil if we didn't receive
-	// an incoming transaction (e.g. if we received another pending message).
-	in.Transaction.UnmarshalTo(pending.Transaction)
-
-	// Add the pending payload to the transaction
-	if out.Transaction, err = anypb.New(pending); err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
 func streamClosed(err error) bool {
 	if err == io.EOF {
 		return true
@@ -521,3 +512,9 @@ func streamClosed(err error) bool {
 
 	return false
 }
+
+// Returns true if the transaction could not be updated because the transfer state of
+// the envelope is not an allowed change of the transaction status.
+func isTransitionError(err error) bool {
+	return errors.Is(err, dberr.ErrInvalidTransition) || errors.Is(err, dberr.ErrTransactionFinal)
+}
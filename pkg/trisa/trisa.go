@@ -5,6 +5,7 @@ import (
 
 	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/store"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
 	"github.com/trisacrypto/envoy/pkg/trisa/interceptors"
 	"github.com/trisacrypto/envoy/pkg/trisa/network"
 	"github.com/trisacrypto/envoy/pkg/webhook"
@@ -29,16 +30,18 @@ type Server struct {
 	network  network.Network
 	store    store.Store
 	webhook  webhook.Handler
+	rules    *thresholds.Evaluator
 	echan    chan<- error
 }
 
 // Create a new TRISA server ready to handle gRPC requests.
-func New(conf config.TRISAConfig, network network.Network, store store.Store, webhook webhook.Handler, echan chan<- error) (s *Server, err error) {
+func New(conf config.TRISAConfig, network network.Network, store store.Store, webhook webhook.Handler, rules *thresholds.Evaluator, echan chan<- error) (s *Server, err error) {
 	s = &Server{
 		conf:    conf,
 		network: network,
 		store:   store,
 		webhook: webhook,
+		rules:   rules,
 		echan:   echan,
 	}
 
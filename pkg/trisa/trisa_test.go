@@ -71,7 +71,7 @@ func (s *trisaTestSuite) SetupSuite() {
 	s.store, err = store.Open("mock:///")
 	require.NoError(err, "could not create mock store")
 
-	s.svc, err = trisa.New(s.conf, s.network, s.store, nil, s.echan)
+	s.svc, err = trisa.New(s.conf, s.network, s.store, nil, nil, s.echan)
 	require.NoError(err, "could not create a new TRISA server")
 
 	// Run the TRISA server on the bufconn for tests
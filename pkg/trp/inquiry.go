@@ -8,8 +8,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/envoy/pkg/enum"
 	"github.com/trisacrypto/envoy/pkg/logger"
 	"github.com/trisacrypto/envoy/pkg/postman"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/trisa/pkg/openvasp"
 	"github.com/trisacrypto/trisa/pkg/openvasp/trp/v3"
@@ -78,10 +80,20 @@ func (s *Server) Inquiry(c *gin.Context) {
 	// TODO: this may return an invalid counterparty error, which should return a different status error
 	if err = packet.In.UpdateTransaction(); err != nil {
 		log.Warn().Err(err).Bool("stored_to_database", false).Msg("could not update transaction details and counterparty information")
+		if errors.Is(err, dberr.ErrInvalidTransition) || errors.Is(err, dberr.ErrTransactionFinal) {
+			c.AbortWithError(http.StatusConflict, err)
+			return
+		}
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
 	}
 
+	// Record if travel rule data is required for the transfer by the threshold rules.
+	// If the evaluation fails, log the error but do not cancel processing.
+	if err = s.thresholds.Annotate(c.Request.Context(), packet.DB, enum.SourceRemote, packet.Payload(), packet.Counterparty); err != nil {
+		log.Warn().Err(err).Msg("could not evaluate transfer against travel rule threshold rules")
+	}
+
 	// TODO: load auto approve/reject policies for counterparty to determine response
 
 	// Determine how to construct a response back to the remote counterparty; e.g. by
@@ -100,7 +112,7 @@ func (s *Server) Inquiry(c *gin.Context) {
 	}
 
 	// Handle the outgoing message
-	if err = packet.Resolve(out); err != nil {
+	if err = packet.Resolve(out, s.conf.Node.Pending); err != nil {
 		log.Error().Err(err).Bool("stored_to_database", false).Msg("could not resolve outgoing trp inquiry")
 		c.AbortWithError(http.StatusInternalServerError, err)
 		return
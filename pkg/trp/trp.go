@@ -21,6 +21,7 @@ import (
 
 	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/store"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
 	"github.com/trisacrypto/envoy/pkg/trisa/network"
 	"github.com/trisacrypto/trisa/pkg/openvasp/extensions/discoverability"
 	"github.com/trisacrypto/trisa/pkg/openvasp/trp/v3"
@@ -57,6 +58,7 @@ type Server struct {
 	started    time.Time
 	healthy    bool
 	ready      bool
+	thresholds *thresholds.Evaluator
 }
 
 func New(conf config.Config, store store.Store, network network.Network) (s *Server, err error) {
@@ -65,9 +67,10 @@ func New(conf config.Config, store store.Store, network network.Network) (s *Ser
 	}
 
 	s = &Server{
-		conf:  conf,
-		store: store,
-		trisa: network,
+		conf:       conf,
+		store:      store,
+		trisa:      network,
+		thresholds: thresholds.New(conf, store),
 	}
 
 	// If not enabled, return just the server stub
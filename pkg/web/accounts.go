@@ -367,6 +367,11 @@ func (s *Server) DeleteAccount(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, dberr.ErrLegalHold) {
+			c.JSON(http.StatusConflict, api.Error("account is under legal hold and cannot be deleted"))
+			return
+		}
+
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
 		return
@@ -764,6 +769,11 @@ func (s *Server) DeleteCryptoAddress(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, dberr.ErrLegalHold) {
+			c.JSON(http.StatusConflict, api.Error("account is under legal hold and its crypto addresses cannot be deleted"))
+			return
+		}
+
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
 		return
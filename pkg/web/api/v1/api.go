@@ -40,7 +40,7 @@ type Client interface {
 	// Transaction Actions
 	Prepare(context.Context, *Prepare) (*Prepared, error)
 	SendPrepared(context.Context, *Prepared) (*Transaction, error)
-	Export(context.Context, io.Writer) error
+	Export(context.Context, *TransactionExportQuery, io.Writer) error
 
 	// Transaction Detail Actions
 	SendEnvelope(ctx context.Context, transactionID uuid.UUID, in *Envelope) (*Envelope, error)
@@ -54,6 +54,15 @@ type Client interface {
 	Complete(ctx context.Context, transactionID uuid.UUID, in *generic.Transaction) (*Envelope, error)
 	ArchiveTransaction(context.Context, uuid.UUID) error
 	UnarchiveTransaction(context.Context, uuid.UUID) error
+	TransactionHistory(context.Context, uuid.UUID) (*TransactionHistory, error)
+	TransactionEvidence(context.Context, uuid.UUID, io.Writer) error
+
+	// Transaction Case Management
+	ListTransactionNotes(context.Context, uuid.UUID) (*TransactionNoteList, error)
+	CreateTransactionNote(context.Context, uuid.UUID, *TransactionNote) (*TransactionNote, error)
+	AssignTransaction(context.Context, uuid.UUID, *TransactionAssignment) (*Transaction, error)
+	ListTransactionTags(context.Context) (*TransactionTags, error)
+	SetTransactionTags(context.Context, uuid.UUID, *TransactionTags) (*TransactionTags, error)
 
 	// SecureEnvelopes Resource
 	ListSecureEnvelopes(ctx context.Context, transactionID uuid.UUID, in *EnvelopeListQuery) (*EnvelopesList, error)
@@ -85,6 +94,8 @@ type Client interface {
 	CounterpartyDetail(context.Context, ulid.ULID) (*Counterparty, error)
 	UpdateCounterparty(context.Context, *Counterparty) (*Counterparty, error)
 	DeleteCounterparty(context.Context, ulid.ULID) error
+	CounterpartyPending(context.Context, ulid.ULID) (*CounterpartyPending, error)
+	UpdateCounterpartyPending(context.Context, ulid.ULID, *CounterpartyPending) (*CounterpartyPending, error)
 
 	// Contacts Resource
 	ListContacts(ctx context.Context, counterpartyID ulid.ULID, in *PageQuery) (*ContactList, error)
@@ -100,18 +111,81 @@ type Client interface {
 	UpdateUser(context.Context, *User) (*User, error)
 	DeleteUser(context.Context, ulid.ULID) error
 	ChangeUserPassword(context.Context, ulid.ULID, *UserPassword) error
+	ResetUserMFA(context.Context, ulid.ULID) error
+	UnlockUser(context.Context, ulid.ULID) error
+
+	// User Invites Resource
+	ListInvites(context.Context) (*UserInviteList, error)
+	CreateInvite(context.Context, *UserInvite) (*UserInvite, error)
+	ResendInvite(context.Context, ulid.ULID) (*UserInvite, error)
+	RevokeInvite(context.Context, ulid.ULID) error
+
+	// Roles Resource
+	ListRoles(context.Context) (*RoleList, error)
+	CreateRole(context.Context, *Role) (*Role, error)
+	RoleDetail(ctx context.Context, role string) (*Role, error)
+	UpdateRole(ctx context.Context, role string, in *Role) (*Role, error)
+	DeleteRole(ctx context.Context, role string) error
+	SetRoleMFAPolicy(ctx context.Context, role string, in *RoleMFAPolicy) (*Role, error)
 
 	// APIKey Resource
 	ListAPIKeys(context.Context, *PageQuery) (*APIKeyList, error)
 	CreateAPIKey(context.Context, *APIKey) (*APIKey, error)
 	APIKeyDetail(context.Context, ulid.ULID) (*APIKey, error)
 	UpdateAPIKey(context.Context, *APIKey) (*APIKey, error)
+	RotateAPIKey(context.Context, ulid.ULID, *APIKeyRotation) (*APIKey, error)
 	DeleteAPIKey(context.Context, ulid.ULID) error
 
 	// ComplianceAuditLog Resource
 	ListComplianceAuditLogs(context.Context, *ComplianceAuditLogQuery) (*ComplianceAuditLogList, error)
 	ComplianceAuditLogDetail(context.Context, ulid.ULID) (*ComplianceAuditLog, error)
 
+	// LegalHold Resource
+	ListLegalHolds(context.Context, *LegalHoldQuery) (*LegalHoldList, error)
+	CreateLegalHold(context.Context, *LegalHold) (*LegalHold, error)
+	LegalHoldDetail(context.Context, ulid.ULID) (*LegalHold, error)
+	UpdateLegalHold(context.Context, *LegalHold) (*LegalHold, error)
+	ReleaseLegalHold(context.Context, ulid.ULID) (*LegalHold, error)
+
+	// Approval Resource
+	ListApprovals(context.Context, *ApprovalQuery) (*ApprovalList, error)
+	ApprovalDetail(context.Context, ulid.ULID) (*Approval, error)
+	ApproveAction(context.Context, ulid.ULID, *ApprovalReview) (*Approval, error)
+	DenyAction(context.Context, ulid.ULID, *ApprovalReview) (*Approval, error)
+	CancelAction(context.Context, ulid.ULID, *ApprovalReview) (*Approval, error)
+	ListApprovalRules(context.Context) (*ApprovalRuleList, error)
+	CreateApprovalRule(context.Context, *ApprovalRule) (*ApprovalRule, error)
+	ApprovalRuleDetail(context.Context, ulid.ULID) (*ApprovalRule, error)
+	UpdateApprovalRule(context.Context, *ApprovalRule) (*ApprovalRule, error)
+	DeleteApprovalRule(context.Context, ulid.ULID) error
+
+	// Outbound Queue Resource
+	ListQueuedTransfers(context.Context) (*QueuedTransferList, error)
+	QueuedTransferDetail(context.Context, ulid.ULID) (*QueuedTransfer, error)
+	CancelQueuedTransfer(context.Context, ulid.ULID) (*QueuedTransfer, error)
+	RetryQueuedTransfer(context.Context, ulid.ULID) (*QueuedTransfer, error)
+
+	// Batch Import Resource
+	ListBatches(context.Context) (*BatchList, error)
+	CreateBatch(ctx context.Context, filename string, data []byte) (*Batch, error)
+	BatchDetail(context.Context, ulid.ULID) (*Batch, error)
+	SendBatch(context.Context, ulid.ULID) (*Batch, error)
+	CancelBatch(context.Context, ulid.ULID) (*Batch, error)
+
+	// Reports Resource
+	ListReports(context.Context) (*ReportList, error)
+	CreateReport(context.Context, *Report) (*Report, error)
+	ReportDetail(context.Context, ulid.ULID) (*Report, error)
+	DownloadReport(context.Context, ulid.ULID, *ReportDownloadQuery, io.Writer) error
+	DeleteReport(context.Context, ulid.ULID) error
+
+	// Threshold Rules Resource
+	ListThresholdRules(context.Context) (*ThresholdRuleList, error)
+	CreateThresholdRule(context.Context, *ThresholdRule) (*ThresholdRule, error)
+	ThresholdRuleDetail(context.Context, ulid.ULID) (*ThresholdRule, error)
+	UpdateThresholdRule(context.Context, *ThresholdRule) (*ThresholdRule, error)
+	DeleteThresholdRule(context.Context, ulid.ULID) error
+
 	// Utilities
 	EncodeTravelAddress(context.Context, *TravelAddress) (*TravelAddress, error)
 	DecodeTravelAddress(context.Context, *TravelAddress) (*TravelAddress, error)
@@ -154,6 +228,7 @@ type PageQuery struct {
 	PageSize      int    `json:"page_size,omitempty" url:"page_size,omitempty" form:"page_size"`
 	NextPageToken string `json:"next_page_token,omitempty" url:"next_page_token,omitempty" form:"next_page_token"`
 	PrevPageToken string `json:"prev_page_token,omitempty" url:"prev_page_token,omitempty" form:"prev_page_token"`
+	PageToken     string `json:"page_token,omitempty" url:"-" form:"-"` // the cursor of the requested page (response only)
 }
 
 // RoutingQuery manages the counterparty information in a send form URL
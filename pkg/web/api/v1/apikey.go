@@ -3,6 +3,7 @@ package api
 import (
 	"database/sql"
 	"fmt"
+	"net"
 	"time"
 
 	"github.com/trisacrypto/envoy/pkg/store/models"
@@ -12,14 +13,19 @@ import (
 )
 
 type APIKey struct {
-	ID          ulid.ULID  `json:"id,omitempty"`
-	Description string     `json:"description"`
-	ClientID    string     `json:"client_id"`
-	Secret      string     `json:"client_secret,omitempty"`
-	LastSeen    *time.Time `json:"last_seen,omitempty"`
-	Permissions []string   `json:"permissions"`
-	Created     time.Time  `json:"created,omitempty"`
-	Modified    time.Time  `json:"modified,omitempty"`
+	ID                    ulid.ULID    `json:"id,omitempty"`
+	Description           string       `json:"description"`
+	ClientID              string       `json:"client_id"`
+	Secret                string       `json:"client_secret,omitempty"`
+	LastSeen              *time.Time   `json:"last_seen,omitempty"`
+	Permissions           []string     `json:"permissions"`
+	Expires               *time.Time   `json:"expires,omitempty"`
+	Expired               bool         `json:"expired,omitempty"`
+	AllowedNetworks       []string     `json:"allowed_networks,omitempty"`
+	PreviousSecretExpires *time.Time   `json:"previous_secret_expires,omitempty"`
+	Usage                 *APIKeyUsage `json:"usage,omitempty"`
+	Created               time.Time    `json:"created,omitempty"`
+	Modified              time.Time    `json:"modified,omitempty"`
 }
 
 type APIKeyList struct {
@@ -27,6 +33,32 @@ type APIKeyList struct {
 	APIKeys []*APIKey  `json:"api_keys"`
 }
 
+// APIKeyUsage summarizes the requests made with an api key over the usage window.
+type APIKeyUsage struct {
+	Since    time.Time           `json:"since"`
+	Requests int64               `json:"requests"`
+	LastIP   string              `json:"last_ip,omitempty"`
+	LastUsed *time.Time          `json:"last_used,omitempty"`
+	Daily    []*APIKeyDailyUsage `json:"daily"`
+}
+
+type APIKeyDailyUsage struct {
+	Day      string `json:"day"`
+	Requests int64  `json:"requests"`
+}
+
+// APIKeyRotation requests a new secret for an api key; the old secret remains valid
+// for the grace period so that clients can be updated without downtime.
+type APIKeyRotation struct {
+	GracePeriod string `json:"grace_period,omitempty"`
+}
+
+const (
+	APIKeyUsageDays            = 30
+	DefaultRotationGracePeriod = 24 * time.Hour
+	MaxRotationGracePeriod     = 30 * 24 * time.Hour
+)
+
 func NewAPIKey(model *models.APIKey) (out *APIKey, err error) {
 	out = &APIKey{
 		ID:          model.ID,
@@ -41,9 +73,47 @@ func NewAPIKey(model *models.APIKey) (out *APIKey, err error) {
 		out.LastSeen = &model.LastSeen.Time
 	}
 
+	if model.Expires.Valid {
+		out.Expires = &model.Expires.Time
+		out.Expired = model.Expired(time.Now())
+	}
+
+	if len(model.AllowedNetworks) > 0 {
+		out.AllowedNetworks = []string(model.AllowedNetworks)
+	}
+
+	if model.PreviousSecretValid(time.Now()) {
+		out.PreviousSecretExpires = &model.PreviousSecretExpires.Time
+	}
+
 	return out, nil
 }
 
+// NewAPIKeyUsage aggregates the daily usage records (ordered from most recent) into a
+// summary of the usage of the api key since the specified timestamp.
+func NewAPIKeyUsage(usage []*models.APIKeyUsage, since time.Time) *APIKeyUsage {
+	out := &APIKeyUsage{
+		Since: since,
+		Daily: make([]*APIKeyDailyUsage, 0, len(usage)),
+	}
+
+	for _, day := range usage {
+		out.Requests += day.Requests
+		out.Daily = append(out.Daily, &APIKeyDailyUsage{
+			Day:      day.Day.Format(time.DateOnly),
+			Requests: day.Requests,
+		})
+
+		if out.LastUsed == nil || day.LastUsed.After(*out.LastUsed) {
+			lastUsed := day.LastUsed
+			out.LastUsed = &lastUsed
+			out.LastIP = day.LastIP.String
+		}
+	}
+
+	return out
+}
+
 func NewAPIKeyList(page *models.APIKeyPage) (out *APIKeyList, err error) {
 	out = &APIKeyList{
 		Page:    &PageQuery{},
@@ -74,6 +144,31 @@ func (k *APIKey) Validate(create bool) (err error) {
 		err = ValidationError(err, ReadOnlyField("last_seen"))
 	}
 
+	if k.Expired {
+		err = ValidationError(err, ReadOnlyField("expired"))
+	}
+
+	if k.PreviousSecretExpires != nil {
+		err = ValidationError(err, ReadOnlyField("previous_secret_expires"))
+	}
+
+	if k.Usage != nil {
+		err = ValidationError(err, ReadOnlyField("usage"))
+	}
+
+	if k.Expires != nil && !k.Expires.After(time.Now()) {
+		err = ValidationError(err, IncorrectField("expires", "the expiration date must be in the future"))
+	}
+
+	// Normalize allowed networks into CIDR notation; bare IP addresses are allowed
+	for i, network := range k.AllowedNetworks {
+		if cidr, perr := ParseNetwork(network); perr != nil {
+			err = ValidationError(err, IncorrectField("allowed_networks", fmt.Sprintf("%q is not a valid ip address or cidr network", network)))
+		} else {
+			k.AllowedNetworks[i] = cidr
+		}
+	}
+
 	// Permissions should be zero on update, but non-zero on create
 	if create {
 		if !k.ID.IsZero() {
@@ -121,9 +216,55 @@ func (k *APIKey) Model() (model *models.APIKey, err error) {
 		}
 	}
 
+	if k.Expires != nil {
+		model.Expires = sql.NullTime{
+			Time:  *k.Expires,
+			Valid: true,
+		}
+	}
+
+	if len(k.AllowedNetworks) > 0 {
+		model.AllowedNetworks = models.AllowedNetworks(k.AllowedNetworks)
+	}
+
 	if len(k.Permissions) > 0 {
 		model.SetPermissions(k.Permissions)
 	}
 
 	return model, nil
 }
+
+// ParseNetwork parses an ip address or CIDR network and returns the normalized CIDR
+// string of the network (bare ip addresses are converted to single host networks).
+func ParseNetwork(s string) (_ string, err error) {
+	var network *net.IPNet
+	if _, network, err = net.ParseCIDR(s); err == nil {
+		return network.String(), nil
+	}
+
+	if ip := net.ParseIP(s); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return (&net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}).String(), nil
+		}
+		return (&net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}).String(), nil
+	}
+
+	return "", err
+}
+
+// Validate the rotation request and return the grace period.
+func (r *APIKeyRotation) Validate() (gracePeriod time.Duration, err error) {
+	if r.GracePeriod == "" {
+		return DefaultRotationGracePeriod, nil
+	}
+
+	if gracePeriod, err = time.ParseDuration(r.GracePeriod); err != nil {
+		return 0, ValidationError(nil, IncorrectField("grace_period", "could not parse duration, e.g. use 24h or 30m"))
+	}
+
+	if gracePeriod < 0 || gracePeriod > MaxRotationGracePeriod {
+		return 0, ValidationError(nil, IncorrectField("grace_period", "grace period must be between 0s and 720h"))
+	}
+
+	return gracePeriod, nil
+}
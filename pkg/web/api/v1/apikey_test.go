@@ -0,0 +1,73 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+func TestAPIKeyValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		expires := time.Now().AddDate(0, 1, 0)
+		key := &api.APIKey{
+			Description:     "restricted key",
+			Permissions:     []string{"travelrule:view"},
+			Expires:         &expires,
+			AllowedNetworks: []string{"10.1.2.3/8", "192.0.2.1", "2001:db8::1"},
+		}
+		require.NoError(t, key.Validate(true))
+		require.Equal(t, []string{"10.0.0.0/8", "192.0.2.1/32", "2001:db8::1/128"}, key.AllowedNetworks, "expected networks to be normalized")
+
+		model, err := key.Model()
+		require.NoError(t, err)
+		require.True(t, model.Expires.Valid)
+		require.Len(t, model.AllowedNetworks, 3)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		testCases := []struct {
+			key    *api.APIKey
+			create bool
+			err    string
+		}{
+			{&api.APIKey{Permissions: []string{"travelrule:view"}, Expires: &past}, true, "invalid field expires: the expiration date must be in the future"},
+			{&api.APIKey{Permissions: []string{"travelrule:view"}, AllowedNetworks: []string{"10.0.0.0/33"}}, true, `invalid field allowed_networks: "10.0.0.0/33" is not a valid ip address or cidr network`},
+			{&api.APIKey{Expired: true}, false, "read-only field expired: this field cannot be written by the user"},
+			{&api.APIKey{Usage: &api.APIKeyUsage{}}, false, "read-only field usage: this field cannot be written by the user"},
+		}
+
+		for i, tc := range testCases {
+			require.EqualError(t, tc.key.Validate(tc.create), tc.err, "test case %d failed", i)
+		}
+	})
+}
+
+func TestAPIKeyRotationValidate(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected time.Duration
+		err      string
+	}{
+		{"", api.DefaultRotationGracePeriod, ""},
+		{"0s", 0, ""},
+		{"72h", 72 * time.Hour, ""},
+		{"720h", api.MaxRotationGracePeriod, ""},
+		{"721h", 0, "invalid field grace_period: grace period must be between 0s and 720h"},
+		{"-1h", 0, "invalid field grace_period: grace period must be between 0s and 720h"},
+		{"one day", 0, "invalid field grace_period: could not parse duration, e.g. use 24h or 30m"},
+	}
+
+	for i, tc := range testCases {
+		actual, err := (&api.APIKeyRotation{GracePeriod: tc.in}).Validate()
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err, "test case %d failed", i)
+			continue
+		}
+
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, tc.expected, actual, "test case %d failed", i)
+	}
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Approval Rules
+//===========================================================================
+
+// ApprovalRule defines the threshold above which sending, accepting, rejecting, or
+// completing a transfer requires the approval of a second user. Unset criteria match
+// any value, e.g. a rule with only a min amount applies to all actions on all assets.
+type ApprovalRule struct {
+	ID             ulid.ULID `json:"id,omitempty"`
+	Description    string    `json:"description"`
+	Action         string    `json:"action,omitempty"`
+	VirtualAsset   string    `json:"virtual_asset,omitempty"`
+	MinAmount      *float64  `json:"min_amount,omitempty"`
+	CounterpartyID ulid.ULID `json:"counterparty_id,omitempty"`
+	Created        time.Time `json:"created,omitempty"`
+	Modified       time.Time `json:"modified,omitempty"`
+}
+
+type ApprovalRuleList struct {
+	Rules []*ApprovalRule `json:"rules"`
+}
+
+func NewApprovalRule(model *models.ApprovalRule) (out *ApprovalRule, err error) {
+	out = &ApprovalRule{
+		ID:           model.ID,
+		Description:  model.Description,
+		VirtualAsset: model.VirtualAsset.String,
+		Created:      model.Created,
+		Modified:     model.Modified,
+	}
+
+	if model.Action != enum.ApprovalActionUnknown {
+		out.Action = model.Action.String()
+	}
+
+	if model.MinAmount.Valid {
+		out.MinAmount = &model.MinAmount.Float64
+	}
+
+	if model.CounterpartyID.Valid {
+		out.CounterpartyID = model.CounterpartyID.ULID
+	}
+
+	return out, nil
+}
+
+func NewApprovalRuleList(rules []*models.ApprovalRule) (out *ApprovalRuleList, err error) {
+	out = &ApprovalRuleList{
+		Rules: make([]*ApprovalRule, 0, len(rules)),
+	}
+
+	for _, model := range rules {
+		var rule *ApprovalRule
+		if rule, err = NewApprovalRule(model); err != nil {
+			return nil, err
+		}
+		out.Rules = append(out.Rules, rule)
+	}
+
+	return out, nil
+}
+
+func (r *ApprovalRule) Validate() (err error) {
+	r.Description = strings.TrimSpace(r.Description)
+	if r.Description == "" {
+		err = ValidationError(err, MissingField("description"))
+	}
+
+	r.Action = strings.TrimSpace(r.Action)
+	if r.Action != "" {
+		if action, perr := enum.ParseApprovalAction(r.Action); perr != nil || action == enum.ApprovalActionUnknown {
+			err = ValidationError(err, IncorrectField("action", "action must be one of send, accept, reject, or complete"))
+		} else {
+			r.Action = action.String()
+		}
+	}
+
+	r.VirtualAsset = strings.TrimSpace(r.VirtualAsset)
+
+	if r.MinAmount != nil && *r.MinAmount < 0 {
+		err = ValidationError(err, IncorrectField("min_amount", "min amount cannot be negative"))
+	}
+
+	return err
+}
+
+func (r *ApprovalRule) Model() (model *models.ApprovalRule, err error) {
+	model = &models.ApprovalRule{
+		Model: models.Model{
+			ID:       r.ID,
+			Created:  r.Created,
+			Modified: r.Modified,
+		},
+		Description:  r.Description,
+		VirtualAsset: sql.NullString{String: r.VirtualAsset, Valid: r.VirtualAsset != ""},
+	}
+
+	if r.Action != "" {
+		if model.Action, err = enum.ParseApprovalAction(r.Action); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.MinAmount != nil {
+		model.MinAmount = sql.NullFloat64{Float64: *r.MinAmount, Valid: true}
+	}
+
+	if !r.CounterpartyID.IsZero() {
+		model.CounterpartyID = ulid.NullULID{ULID: r.CounterpartyID, Valid: true}
+	}
+
+	return model, nil
+}
+
+//===========================================================================
+// Approvals
+//===========================================================================
+
+// Approval is a transfer workflow action that requires the approval of a second user
+// before it is executed. The request is the original JSON request of the action.
+type Approval struct {
+	ID             ulid.ULID       `json:"id"`
+	Action         string          `json:"action"`
+	Status         string          `json:"status"`
+	RuleID         ulid.ULID       `json:"rule_id,omitempty"`
+	TransactionID  string          `json:"transaction_id,omitempty"`
+	Counterparty   string          `json:"counterparty,omitempty"`
+	CounterpartyID ulid.ULID       `json:"counterparty_id,omitempty"`
+	VirtualAsset   string          `json:"virtual_asset,omitempty"`
+	Amount         *float64        `json:"amount,omitempty"`
+	Request        json.RawMessage `json:"request,omitempty"`
+	RequestedBy    string          `json:"requested_by"`
+	ReviewedBy     string          `json:"reviewed_by,omitempty"`
+	ReviewNotes    string          `json:"review_notes,omitempty"`
+	ReviewedOn     *time.Time      `json:"reviewed_on,omitempty"`
+	Created        time.Time       `json:"created"`
+	Modified       time.Time       `json:"modified"`
+}
+
+type ApprovalList struct {
+	Page      *ApprovalQuery `json:"page"`
+	Approvals []*Approval    `json:"approvals"`
+}
+
+// ApprovalQuery filters the approvals returned by the list endpoint.
+type ApprovalQuery struct {
+	PageQuery
+	Status        string `json:"status,omitempty" url:"status,omitempty" form:"status"`
+	TransactionID string `json:"transaction_id,omitempty" url:"transaction_id,omitempty" form:"transaction_id"`
+}
+
+// ApprovalReview is the optional note a reviewer adds when approving or denying an
+// action or that the requester adds when canceling it.
+type ApprovalReview struct {
+	Notes string `json:"notes,omitempty"`
+}
+
+func NewApproval(model *models.Approval) (out *Approval, err error) {
+	out = &Approval{
+		ID:           model.ID,
+		Action:       model.Action.String(),
+		Status:       model.Status.String(),
+		Counterparty: model.Counterparty.String,
+		VirtualAsset: model.VirtualAsset.String,
+		Request:      json.RawMessage(model.Request),
+		RequestedBy:  model.RequestedBy,
+		ReviewedBy:   model.ReviewedBy.String,
+		ReviewNotes:  model.ReviewNotes.String,
+		Created:      model.Created,
+		Modified:     model.Modified,
+	}
+
+	if model.RuleID.Valid {
+		out.RuleID = model.RuleID.ULID
+	}
+
+	if model.TransactionID.Valid {
+		out.TransactionID = model.TransactionID.UUID.String()
+	}
+
+	if model.CounterpartyID.Valid {
+		out.CounterpartyID = model.CounterpartyID.ULID
+	}
+
+	if model.Amount.Valid {
+		out.Amount = &model.Amount.Float64
+	}
+
+	if model.ReviewedOn.Valid {
+		out.ReviewedOn = &model.ReviewedOn.Time
+	}
+
+	return out, nil
+}
+
+func NewApprovalList(page *models.ApprovalPage) (out *ApprovalList, err error) {
+	out = &ApprovalList{
+		Page: &ApprovalQuery{
+			PageQuery: PageQuery{
+				PageSize: int(page.Page.PageSize),
+			},
+			Status:        page.Page.Status,
+			TransactionID: page.Page.TransactionID,
+		},
+		Approvals: make([]*Approval, 0, len(page.Approvals)),
+	}
+
+	if !page.Page.NextPageID.IsZero() {
+		out.Page.NextPageToken = page.Page.NextPageID.String()
+	}
+
+	for _, model := range page.Approvals {
+		var approval *Approval
+		if approval, err = NewApproval(model); err != nil {
+			return nil, err
+		}
+		out.Approvals = append(out.Approvals, approval)
+	}
+
+	return out, nil
+}
+
+// Returns true if the approval has not been approved, denied, or canceled.
+func (a *Approval) IsPending() bool {
+	return a.Status == enum.ApprovalPending.String()
+}
+
+func (q *ApprovalQuery) Validate() (err error) {
+	if q.Status != "" {
+		if status, perr := enum.ParseApprovalStatus(q.Status); perr != nil || status == enum.ApprovalStatusUnknown {
+			err = ValidationError(err, IncorrectField("status", "status must be one of pending, executing, approved, denied, or canceled"))
+		} else {
+			q.Status = status.String()
+		}
+	}
+
+	if q.TransactionID != "" {
+		if _, perr := uuid.Parse(q.TransactionID); perr != nil {
+			err = ValidationError(err, IncorrectField("transaction_id", "transaction ids must be a valid uuid"))
+		}
+	}
+
+	// The next page token must be the ID of the last approval in the previous page
+	if q.NextPageToken != "" {
+		if _, perr := ulid.Parse(q.NextPageToken); perr != nil {
+			err = ValidationError(err, IncorrectField("next_page_token", "invalid pagination token"))
+		}
+	}
+
+	return err
+}
+
+func (q *ApprovalQuery) Query() (query *models.ApprovalPageInfo) {
+	query = &models.ApprovalPageInfo{
+		PageInfo: models.PageInfo{
+			PageSize: uint32(q.PageSize),
+		},
+		Status:        q.Status,
+		TransactionID: q.TransactionID,
+	}
+
+	if q.NextPageToken != "" {
+		query.NextPageID, _ = ulid.Parse(q.NextPageToken)
+	}
+
+	return query
+}
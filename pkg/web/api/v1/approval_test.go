@@ -0,0 +1,155 @@
+package api_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func TestApprovalRuleValidate(t *testing.T) {
+	amount := 1.5
+	negative := -1.0
+
+	t.Run("Valid", func(t *testing.T) {
+		testCases := []*api.ApprovalRule{
+			{Description: "all transfers"},
+			{Description: "large sends", Action: "send", MinAmount: &amount},
+			{Description: "bitcoin", Action: "Accept", VirtualAsset: "BTC"},
+			{Description: "counterparty", CounterpartyID: ulid.MakeSecure()},
+		}
+
+		for i, tc := range testCases {
+			require.NoError(t, tc.Validate(), "test case %d failed", i)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := []struct {
+			rule *api.ApprovalRule
+			err  string
+		}{
+			{&api.ApprovalRule{Description: "  "}, "missing description: this field is required"},
+			{&api.ApprovalRule{Description: "foo", Action: "archive"}, "invalid field action: action must be one of send, accept, reject, or complete"},
+			{&api.ApprovalRule{Description: "foo", Action: "unknown"}, "invalid field action: action must be one of send, accept, reject, or complete"},
+			{&api.ApprovalRule{Description: "foo", MinAmount: &negative}, "invalid field min_amount: min amount cannot be negative"},
+		}
+
+		for i, tc := range testCases {
+			require.EqualError(t, tc.rule.Validate(), tc.err, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Normalize", func(t *testing.T) {
+		rule := &api.ApprovalRule{Description: " large sends ", Action: "SEND", VirtualAsset: " BTC "}
+		require.NoError(t, rule.Validate())
+		require.Equal(t, "large sends", rule.Description)
+		require.Equal(t, "send", rule.Action)
+		require.Equal(t, "BTC", rule.VirtualAsset)
+	})
+}
+
+func TestApprovalRuleModel(t *testing.T) {
+	amount := 10.0
+	rule := &api.ApprovalRule{
+		Description:    "large bitcoin sends",
+		Action:         "send",
+		VirtualAsset:   "BTC",
+		MinAmount:      &amount,
+		CounterpartyID: ulid.MakeSecure(),
+	}
+
+	model, err := rule.Model()
+	require.NoError(t, err)
+	require.Equal(t, enum.ApprovalActionSend, model.Action)
+	require.Equal(t, sql.NullString{Valid: true, String: "BTC"}, model.VirtualAsset)
+	require.Equal(t, sql.NullFloat64{Valid: true, Float64: 10.0}, model.MinAmount)
+	require.Equal(t, ulid.NullULID{Valid: true, ULID: rule.CounterpartyID}, model.CounterpartyID)
+
+	cmp, err := api.NewApprovalRule(model)
+	require.NoError(t, err)
+	require.Equal(t, rule, cmp)
+
+	// Unset criteria are null in the model and omitted from the API response
+	model, err = (&api.ApprovalRule{Description: "everything"}).Model()
+	require.NoError(t, err)
+	require.Equal(t, enum.ApprovalActionUnknown, model.Action)
+	require.False(t, model.VirtualAsset.Valid)
+	require.False(t, model.MinAmount.Valid)
+	require.False(t, model.CounterpartyID.Valid)
+
+	cmp, err = api.NewApprovalRule(model)
+	require.NoError(t, err)
+	require.Empty(t, cmp.Action)
+	require.Nil(t, cmp.MinAmount)
+}
+
+func TestNewApproval(t *testing.T) {
+	txID := uuid.New()
+	model := &models.Approval{
+		Model:         models.Model{ID: ulid.MakeSecure(), Created: time.Now(), Modified: time.Now()},
+		Action:        enum.ApprovalActionAccept,
+		Status:        enum.ApprovalApproved,
+		TransactionID: uuid.NullUUID{Valid: true, UUID: txID},
+		Counterparty:  sql.NullString{Valid: true, String: "AliceVASP"},
+		VirtualAsset:  sql.NullString{Valid: true, String: "BTC"},
+		Amount:        sql.NullFloat64{Valid: true, Float64: 0.5},
+		Request:       []byte(`{"identity":{}}`),
+		RequestedBy:   "Compliance User",
+		ReviewedBy:    sql.NullString{Valid: true, String: "Admin User"},
+		ReviewedOn:    sql.NullTime{Valid: true, Time: time.Now()},
+	}
+
+	out, err := api.NewApproval(model)
+	require.NoError(t, err)
+	require.Equal(t, "accept", out.Action)
+	require.Equal(t, "approved", out.Status)
+	require.Equal(t, txID.String(), out.TransactionID)
+	require.Equal(t, 0.5, *out.Amount)
+	require.JSONEq(t, `{"identity":{}}`, string(out.Request))
+	require.Equal(t, "Admin User", out.ReviewedBy)
+	require.NotNil(t, out.ReviewedOn)
+	require.True(t, out.RuleID.IsZero())
+	require.False(t, out.IsPending())
+}
+
+func TestApprovalQuery(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		cursor := ulid.MakeSecure()
+		query := &api.ApprovalQuery{
+			PageQuery:     api.PageQuery{PageSize: 10, NextPageToken: cursor.String()},
+			Status:        "Pending",
+			TransactionID: "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3",
+		}
+
+		require.NoError(t, query.Validate())
+		require.Equal(t, "pending", query.Status)
+
+		page := query.Query()
+		require.Equal(t, uint32(10), page.PageSize)
+		require.Equal(t, cursor, page.NextPageID)
+		require.Equal(t, "pending", page.Status)
+		require.Equal(t, "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3", page.TransactionID)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := []struct {
+			query *api.ApprovalQuery
+			err   string
+		}{
+			{&api.ApprovalQuery{Status: "waiting"}, "invalid field status: status must be one of pending, executing, approved, denied, or canceled"},
+			{&api.ApprovalQuery{TransactionID: "foo"}, "invalid field transaction_id: transaction ids must be a valid uuid"},
+			{&api.ApprovalQuery{PageQuery: api.PageQuery{NextPageToken: "foo"}}, "invalid field next_page_token: invalid pagination token"},
+		}
+
+		for i, tc := range testCases {
+			require.EqualError(t, tc.query.Validate(), tc.err, "test case %d failed", i)
+		}
+	})
+}
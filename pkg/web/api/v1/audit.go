@@ -42,6 +42,9 @@ type ComplianceAuditLog struct {
 	// field may be returned as the empty string depending on the API request
 	// options.
 	ChangeNotes string
+	// Actor is the identity of the user or api key that made the change; it is
+	// only returned by the detail endpoint and is also set for revoked actors.
+	Actor *ComplianceAuditLogActor
 
 	// SIGNATURE METADATA FIELDS:
 
@@ -59,6 +62,55 @@ type ComplianceAuditLog struct {
 	Algorithm string
 }
 
+// ComplianceAuditLogActor describes the user or api key that made a change. When a
+// user or api key is deleted its identity is retained so that the actors of
+// historical audit log entries can still be resolved; Revoked is set in that case.
+type ComplianceAuditLogActor struct {
+	ID       ulid.ULID  `json:"id"`
+	Name     string     `json:"name"`
+	Email    string     `json:"email,omitempty"`
+	ClientID string     `json:"client_id,omitempty"`
+	Revoked  *time.Time `json:"revoked,omitempty"`
+}
+
+// Create a new api.ComplianceAuditLogActor from an active database model.User.
+func NewUserActor(user *models.User) *ComplianceAuditLogActor {
+	return &ComplianceAuditLogActor{
+		ID:    user.ID,
+		Name:  user.Name.String,
+		Email: user.Email,
+	}
+}
+
+// Create a new api.ComplianceAuditLogActor from a database model.RevokedUser.
+func NewRevokedUserActor(user *models.RevokedUser) *ComplianceAuditLogActor {
+	return &ComplianceAuditLogActor{
+		ID:      user.ID,
+		Name:    user.Name.String,
+		Email:   user.Email,
+		Revoked: &user.Revoked,
+	}
+}
+
+// Create a new api.ComplianceAuditLogActor from an active database model.APIKey.
+func NewAPIKeyActor(key *models.APIKey) *ComplianceAuditLogActor {
+	return &ComplianceAuditLogActor{
+		ID:       key.ID,
+		Name:     key.Description.String,
+		ClientID: key.ClientID,
+	}
+}
+
+// Create a new api.ComplianceAuditLogActor from a database model.RevokedAPIKey.
+func NewRevokedAPIKeyActor(key *models.RevokedAPIKey) *ComplianceAuditLogActor {
+	return &ComplianceAuditLogActor{
+		ID:       key.ID,
+		Name:     key.Description.String,
+		ClientID: key.ClientID,
+		Revoked:  &key.Revoked,
+	}
+}
+
 // Create a new api.ComplianceAuditLog from a database model.ComplianceAuditLog.
 func NewComplianceAuditLog(model *models.ComplianceAuditLog) (out *ComplianceAuditLog) {
 	out = &ComplianceAuditLog{
@@ -96,10 +148,6 @@ func NewComplianceAuditLog(model *models.ComplianceAuditLog) (out *ComplianceAud
 type ComplianceAuditLogQuery struct {
 	PageQuery
 
-	// Maximum number of records to query from database
-	// TODO: remove this once proper pagination has been implemented
-	Limit int `json:"limit,omitempty" form:"limit" url:"limit,omitempty"`
-
 	// FILTERING OPTIONS
 
 	// ResourceTypes filters results to include only these enum.Resource values
@@ -110,6 +158,8 @@ type ComplianceAuditLogQuery struct {
 	ActorTypes []string `json:"actor_types,omitempty" form:"actor_types" url:"actor_types,omitempty"`
 	// ActorID filters results by a specific actor ID
 	ActorID string `json:"actor_id,omitempty" form:"actor_id" url:"actor_id,omitempty"`
+	// Actions filters results to include only these enum.Action values
+	Actions []string `json:"actions,omitempty" form:"actions" url:"actions,omitempty"`
 	// After filters results to include logs with ResourceModified on or after this time (inclusive)
 	After *time.Time `json:"after,omitempty" form:"after" url:"after,omitempty"`
 	// Before filters results to include logs with ResourceModified before this time (exclusive)
@@ -142,6 +192,15 @@ func (q *ComplianceAuditLogQuery) Validate() (err error) {
 		}
 	}
 
+	// Check Actions are valid for the enum values
+	if len(q.Actions) != 0 {
+		for _, t := range q.Actions {
+			if !enum.ValidAction(t) {
+				err = ValidationError(err, IncorrectField("actions", fmt.Sprintf("invalid actions value: '%s'", t)))
+			}
+		}
+	}
+
 	// Check Before is after After (timeline example: '...(After)->......<-(Before)...')
 	if (q.After != nil && !q.After.IsZero()) && (q.Before != nil && !q.Before.IsZero()) {
 		if ok := q.Before.After(*q.After); !ok {
@@ -150,6 +209,13 @@ func (q *ComplianceAuditLogQuery) Validate() (err error) {
 		}
 	}
 
+	// The next page token must be the ID of the last log in the previous page
+	if q.NextPageToken != "" {
+		if _, perr := ulid.Parse(q.NextPageToken); perr != nil {
+			err = ValidationError(err, IncorrectField("next_page_token", "invalid pagination token"))
+		}
+	}
+
 	// NOTE: ResourceID, ActorID, and DetailedLogs require no checks
 
 	return err
@@ -160,16 +226,20 @@ func (q *ComplianceAuditLogQuery) Query() (query *models.ComplianceAuditLogPageI
 	query = &models.ComplianceAuditLogPageInfo{
 		PageInfo: models.PageInfo{
 			PageSize: uint32(q.PageSize),
-			// TODO: pagination tokens->IDs
 		},
-		Limit:         q.Limit,
 		ResourceTypes: q.ResourceTypes,
 		ResourceID:    q.ResourceID,
 		ActorTypes:    q.ActorTypes,
 		ActorID:       q.ActorID,
+		Actions:       q.Actions,
 		DetailedLogs:  q.DetailedLogs,
 	}
 
+	// NOTE: the token is expected to be validated before the query is created
+	if q.NextPageToken != "" {
+		query.NextPageID, _ = ulid.Parse(q.NextPageToken)
+	}
+
 	if q.After != nil && !q.After.IsZero() {
 		query.After = *q.After
 	}
@@ -202,11 +272,11 @@ func NewComplianceAuditLogList(page *models.ComplianceAuditLogPage) (out *Compli
 			PageQuery: PageQuery{
 				PageSize: int(page.Page.PageSize),
 			},
-			Limit:         page.Page.Limit,
 			ResourceTypes: page.Page.ResourceTypes,
 			ResourceID:    page.Page.ResourceID,
 			ActorTypes:    page.Page.ActorTypes,
 			ActorID:       page.Page.ActorID,
+			Actions:       page.Page.Actions,
 			After:         &page.Page.After,
 			Before:        &page.Page.Before,
 			DetailedLogs:  page.Page.DetailedLogs,
@@ -214,6 +284,10 @@ func NewComplianceAuditLogList(page *models.ComplianceAuditLogPage) (out *Compli
 		Logs: make([]*ComplianceAuditLog, 0, len(page.Logs)),
 	}
 
+	if !page.Page.NextPageID.IsZero() {
+		out.Page.NextPageToken = page.Page.NextPageID.String()
+	}
+
 	for _, model := range page.Logs {
 		out.Logs = append(out.Logs, NewComplianceAuditLog(model))
 	}
@@ -74,6 +74,7 @@ func TestComplianceAuditLogQueryQuery(t *testing.T) {
 			ResourceID:    ulid.MakeSecure().String(),
 			ActorTypes:    []string{"user", "api_key", "sunrise"},
 			ActorID:       uuid.NewString(),
+			Actions:       []string{"create", "update"},
 			DetailedLogs:  true,
 			After:         &after,
 			Before:        &before,
@@ -85,6 +86,20 @@ func TestComplianceAuditLogQueryQuery(t *testing.T) {
 		require.NoError(t, compareComplianceAuditLogQueries(&model, query))
 	})
 
+	t.Run("SuccessPageToken", func(t *testing.T) {
+		//setup
+		nextPage := ulid.MakeSecure()
+		model := api.ComplianceAuditLogQuery{
+			PageQuery: api.PageQuery{PageSize: 10, NextPageToken: nextPage.String()},
+		}
+
+		//test
+		query := model.Query()
+		require.NotNil(t, query, "expected query to be non-nil")
+		require.NoError(t, compareComplianceAuditLogQueries(&model, query))
+		require.Equal(t, nextPage, query.NextPageID)
+	})
+
 	t.Run("SuccessEmptyLists", func(t *testing.T) {
 		//setup
 		model := api.ComplianceAuditLogQuery{
@@ -118,6 +133,7 @@ func TestComplianceAuditLogQueryValidate(t *testing.T) {
 			ResourceID:    ulid.MakeSecure().String(),
 			ActorTypes:    []string{"user", "api_key", "sunrise"},
 			ActorID:       uuid.NewString(),
+			Actions:       []string{"create", "update"},
 			DetailedLogs:  true,
 			After:         &after,
 			Before:        &before,
@@ -162,6 +178,28 @@ func TestComplianceAuditLogQueryValidate(t *testing.T) {
 		require.ErrorContains(t, err, "invalid field actor_types: invalid actor_types value", "validation failed")
 	})
 
+	t.Run("FailureIncorrectActions", func(t *testing.T) {
+		//setup
+		model := api.ComplianceAuditLogQuery{
+			Actions: []string{"create", "foo"},
+		}
+
+		//test
+		err := model.Validate()
+		require.ErrorContains(t, err, "invalid field actions: invalid actions value: 'foo'", "validation failed")
+	})
+
+	t.Run("FailureIncorrectPageToken", func(t *testing.T) {
+		//setup
+		model := api.ComplianceAuditLogQuery{
+			PageQuery: api.PageQuery{NextPageToken: "foo"},
+		}
+
+		//test
+		err := model.Validate()
+		require.ErrorContains(t, err, "invalid field next_page_token: invalid pagination token", "validation failed")
+	})
+
 	t.Run("FailureBeforeBeforeAfter", func(t *testing.T) {
 		//setup
 		after := time.Now().Add((-1 * time.Hour))
@@ -198,6 +236,7 @@ func TestNewComplianceAuditLogList(t *testing.T) {
 			ResourceID:    ulid.MakeSecure().String(),
 			ActorTypes:    []string{"user", "api_key", "sunrise"},
 			ActorID:       uuid.NewString(),
+			Actions:       []string{"delete"},
 			DetailedLogs:  true,
 			After:         after,
 			Before:        before,
@@ -291,6 +330,10 @@ func compareComplianceAuditLogQueries(apiLogQuery *api.ComplianceAuditLogQuery,
 		return errors.New("ActorID did not match")
 	}
 
+	if apiLogQuery.Actions != nil && !reflect.DeepEqual(apiLogQuery.Actions, modelLogPageInfo.Actions) {
+		return errors.New("Actions did not match")
+	}
+
 	if apiLogQuery.After != nil && !apiLogQuery.After.Equal(modelLogPageInfo.After) {
 		return errors.New("After did not match")
 	}
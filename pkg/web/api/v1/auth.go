@@ -9,6 +9,7 @@ import "strings"
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Code     string `json:"code,omitempty"`
 	Next     string `json:"next"`
 }
 
@@ -22,6 +23,13 @@ type LoginReply struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// Returned when the user's credentials are valid but the user has enrolled in MFA and
+// must also supply a TOTP or recovery code to login.
+type MFARequiredReply struct {
+	Reply
+	MFARequired bool `json:"mfa_required"`
+}
+
 type ReauthenticateRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
@@ -36,6 +44,18 @@ type ResetPasswordChangeRequest struct {
 	Confirm  string `json:"confirm"`
 }
 
+// OpenIDConfiguration is the discovery document that describes how to verify the
+// access tokens issued by Envoy (OpenID Connect Discovery 1.0).
+type OpenIDConfiguration struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	TokenEndpoint                    string   `json:"token_endpoint"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
+
 func (r *LoginRequest) Validate() (err error) {
 	r.Email = strings.TrimSpace(r.Email)
 	if r.Email == "" {
@@ -47,6 +67,7 @@ func (r *LoginRequest) Validate() (err error) {
 		err = ValidationError(err, MissingField("password"))
 	}
 
+	r.Code = strings.TrimSpace(r.Code)
 	return err
 }
 
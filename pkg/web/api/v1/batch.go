@@ -0,0 +1,472 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+// Batch is a set of transfers imported from a CSV or JSON Lines file. Every row of the
+// file is validated when it is imported; the valid rows are sent concurrently when the
+// batch is sent and the counts of each item status track the progress of the batch.
+type Batch struct {
+	ID        ulid.ULID    `json:"id"`
+	Filename  string       `json:"filename,omitempty"`
+	Format    string       `json:"format"`
+	Status    string       `json:"status"`
+	CreatedBy string       `json:"created_by,omitempty"`
+	Total     int64        `json:"total"`
+	Invalid   int64        `json:"invalid"`
+	Pending   int64        `json:"pending"`
+	Sent      int64        `json:"sent"`
+	Queued    int64        `json:"queued"`
+	Held      int64        `json:"held"`
+	Failed    int64        `json:"failed"`
+	Items     []*BatchItem `json:"items,omitempty"`
+	Created   time.Time    `json:"created"`
+	Modified  time.Time    `json:"modified"`
+}
+
+// BatchItem is the result of validating and sending a single row of a batch.
+type BatchItem struct {
+	ID            ulid.ULID   `json:"id"`
+	Row           int64       `json:"row"`
+	Status        string      `json:"status"`
+	Request       *Prepare    `json:"request,omitempty"`
+	Errors        ErrorDetail `json:"errors,omitempty"`
+	TransactionID string      `json:"transaction_id,omitempty"`
+	Modified      time.Time   `json:"modified"`
+}
+
+type BatchList struct {
+	Batches []*Batch `json:"batches"`
+}
+
+func NewBatch(model *models.Batch, items []*models.BatchItem) (out *Batch, err error) {
+	out = &Batch{
+		ID:        model.ID,
+		Filename:  model.Filename,
+		Format:    model.Format,
+		Status:    model.Status.String(),
+		CreatedBy: model.CreatedBy,
+		Total:     model.Total,
+		Invalid:   model.Invalid,
+		Pending:   model.Pending,
+		Sent:      model.Sent,
+		Queued:    model.Queued,
+		Held:      model.Held,
+		Failed:    model.Failed,
+		Created:   model.Created,
+		Modified:  model.Modified,
+	}
+
+	if len(items) > 0 {
+		out.Items = make([]*BatchItem, 0, len(items))
+		for _, item := range items {
+			var batchItem *BatchItem
+			if batchItem, err = NewBatchItem(item); err != nil {
+				return nil, err
+			}
+			out.Items = append(out.Items, batchItem)
+		}
+	}
+
+	return out, nil
+}
+
+func NewBatchItem(model *models.BatchItem) (out *BatchItem, err error) {
+	out = &BatchItem{
+		ID:       model.ID,
+		Row:      model.Row,
+		Status:   model.Status.String(),
+		Modified: model.Modified,
+	}
+
+	// The request of an invalid row may not be parseable so it is omitted if the
+	// request cannot be unmarshaled; the errors describe why the row is invalid.
+	if model.Request.Valid {
+		request := &Prepare{}
+		if json.Unmarshal([]byte(model.Request.String), request) == nil {
+			out.Request = request
+		}
+	}
+
+	if len(model.Errors) > 0 {
+		if err = json.Unmarshal(model.Errors, &out.Errors); err != nil {
+			return nil, err
+		}
+	}
+
+	if model.TransactionID.Valid {
+		out.TransactionID = model.TransactionID.UUID.String()
+	}
+
+	return out, nil
+}
+
+func NewBatchList(batches []*models.Batch) (out *BatchList, err error) {
+	out = &BatchList{
+		Batches: make([]*Batch, 0, len(batches)),
+	}
+
+	for _, model := range batches {
+		var batch *Batch
+		if batch, err = NewBatch(model, nil); err != nil {
+			return nil, err
+		}
+		out.Batches = append(out.Batches, batch)
+	}
+
+	return out, nil
+}
+
+// Returns true if the batch has valid transfers that have not been sent yet.
+func (b *Batch) IsSendable() bool {
+	return (b.Status == "validated" || b.Status == "canceled") && b.Pending > 0
+}
+
+// Returns true if the transfers in the batch are currently being sent.
+func (b *Batch) IsSending() bool {
+	return b.Status == "sending"
+}
+
+// Returns the number of items in the batch that have been processed (e.g. are not
+// invalid or pending) for displaying the progress of sending the batch.
+func (b *Batch) Processed() int64 {
+	return b.Sent + b.Queued + b.Held + b.Failed
+}
+
+// Returns the beneficiary name and amount of the item for display purposes.
+func (i *BatchItem) Summary() string {
+	if i.Request == nil || i.Request.Beneficiary == nil || i.Request.Transfer == nil {
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %v %s", i.Request.Beneficiary.FullName(), i.Request.Transfer.Amount, i.Request.Transfer.Network))
+}
+
+// Converts an error into the error detail of a batch item so that every field that
+// could not be validated is reported for the row.
+func NewErrorDetail(err error) ErrorDetail {
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) {
+		detail := make(ErrorDetail, 0, len(verrs))
+		for _, verr := range verrs {
+			detail = append(detail, &DetailError{Field: verr.field, Error: verr.Error()})
+		}
+		return detail
+	}
+	return ErrorDetail{{Error: err.Error()}}
+}
+
+//===========================================================================
+// Batch Import
+//===========================================================================
+
+const (
+	BatchFormatCSV   = "csv"
+	BatchFormatJSONL = "jsonl"
+)
+
+var (
+	ErrUnknownBatchFormat = errors.New("could not determine batch format: upload a .csv or .jsonl file")
+	ErrEmptyBatch         = errors.New("the batch does not contain any transfers")
+)
+
+// BatchRow is a parsed row of an imported batch. If the row could not be parsed or is
+// invalid then Err describes every problem with the row and the row is not sent.
+type BatchRow struct {
+	Row     int64
+	Prepare *Prepare
+	Request string
+	Err     error
+}
+
+// Determines the format of a batch from the content type of the upload or, if the
+// content type is not specific, from the extension of the file name.
+func ParseBatchFormat(filename, contentType string) (string, error) {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mt {
+		case "text/csv":
+			return BatchFormatCSV, nil
+		case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+			return BatchFormatJSONL, nil
+		}
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return BatchFormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return BatchFormatJSONL, nil
+	default:
+		return "", ErrUnknownBatchFormat
+	}
+}
+
+// Returns the content type used to upload a batch in the specified format.
+func BatchContentType(format string) string {
+	if format == BatchFormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// ParseBatch reads every row of a CSV or JSON Lines file into a prepare transaction
+// request and validates it. An error is returned only if the file as a whole cannot be
+// imported (e.g. the CSV header has unknown columns or there are more than maxRows
+// rows); errors in individual rows are reported on the row so that every problem in
+// the file can be fixed at once.
+func ParseBatch(format string, r io.Reader, maxRows int) (rows []*BatchRow, err error) {
+	switch format {
+	case BatchFormatCSV:
+		rows, err = parseBatchCSV(r, maxRows)
+	case BatchFormatJSONL:
+		rows, err = parseBatchJSONL(r, maxRows)
+	default:
+		return nil, ErrUnknownBatchFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	for _, row := range rows {
+		// Rows that could not be unmarshaled keep the raw row as the request
+		if row.Request != "" {
+			continue
+		}
+
+		// Combine the validation errors with any errors parsing the columns of the row
+		verrs, _ := row.Prepare.Validate().(ValidationErrors)
+		row.Err = ValidationError(row.Err, verrs...)
+
+		var data []byte
+		if data, err = json.Marshal(row.Prepare); err != nil {
+			return nil, err
+		}
+		row.Request = string(data)
+	}
+
+	return rows, nil
+}
+
+func parseBatchJSONL(r io.Reader, maxRows int) (rows []*BatchRow, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var line int64
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		if len(rows) >= maxRows {
+			return nil, fmt.Errorf("the batch exceeds the maximum of %d transfers", maxRows)
+		}
+
+		row := &BatchRow{Row: line, Prepare: &Prepare{}}
+		if err = json.Unmarshal(data, row.Prepare); err != nil {
+			row.Request = string(data)
+			row.Err = ValidationError(nil, IncorrectField("row", "could not parse json: "+err.Error()))
+		}
+		rows = append(rows, row)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read batch: %w", err)
+	}
+	return rows, nil
+}
+
+func parseBatchCSV(r io.Reader, maxRows int) (rows []*BatchRow, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var header []string
+	if header, err = reader.Read(); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrEmptyBatch
+		}
+		return nil, fmt.Errorf("could not read csv header: %w", err)
+	}
+
+	setters := make([]batchColumn, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if i == 0 {
+			// Excel prepends a byte order mark to UTF-8 encoded CSV files
+			column = strings.TrimPrefix(column, "\ufeff")
+		}
+
+		var ok bool
+		if setters[i], ok = batchColumns[column]; !ok {
+			return nil, fmt.Errorf("unknown column %q in csv header", header[i])
+		}
+		header[i] = column
+	}
+
+	for {
+		var record []string
+		if record, err = reader.Read(); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("could not read csv: %w", err)
+		}
+
+		if isBlank(record) {
+			continue
+		}
+
+		if len(rows) >= maxRows {
+			return nil, fmt.Errorf("the batch exceeds the maximum of %d transfers", maxRows)
+		}
+
+		// Rows are identified by the line of the file they start on since quoted values
+		// may span multiple lines and the csv reader skips empty lines.
+		line, _ := reader.FieldPos(0)
+		row := &BatchRow{Row: int64(line), Prepare: &Prepare{}}
+		for i, value := range record {
+			if i >= len(setters) {
+				row.Err = ValidationError(row.Err, IncorrectField("row", "has more values than the csv header"))
+				break
+			}
+
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+
+			if serr := setters[i](row.Prepare, value); serr != nil {
+				row.Err = ValidationError(row.Err, IncorrectField(header[i], serr.Error()))
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// A batchColumn sets the value of a CSV column on the prepare transaction request.
+type batchColumn func(p *Prepare, value string) error
+
+// Maps the columns of a CSV batch to the fields of the prepare transaction request.
+// The column names are the JSON paths of the fields; each person has a single address
+// whose address lines are separated by newlines.
+var batchColumns = map[string]batchColumn{
+	"routing.protocol":        routingColumn(func(r *Routing, v string) error { r.Protocol = v; return nil }),
+	"routing.travel_address":  routingColumn(func(r *Routing, v string) error { r.TravelAddress = v; return nil }),
+	"routing.counterparty":    routingColumn(func(r *Routing, v string) error { r.Counterparty = v; return nil }),
+	"routing.email":           routingColumn(func(r *Routing, v string) error { r.EmailAddress = v; return nil }),
+	"routing.counterparty_id": routingColumn(func(r *Routing, v string) (err error) { r.CounterpartyID, err = ulid.Parse(v); return err }),
+	"transfer.amount":         transferColumn(func(t *Transfer, v string) (err error) { t.Amount, err = strconv.ParseFloat(v, 64); return err }),
+	"transfer.network":        transferColumn(func(t *Transfer, v string) error { t.Network = v; return nil }),
+	"transfer.asset_type":     transferColumn(func(t *Transfer, v string) error { t.AssetType = v; return nil }),
+	"transfer.transaction_id": transferColumn(func(t *Transfer, v string) error { t.TxID = v; return nil }),
+	"transfer.tag":            transferColumn(func(t *Transfer, v string) error { t.Tag = v; return nil }),
+}
+
+func init() {
+	for _, prefix := range []string{"originator", "beneficiary"} {
+		person := func(p *Prepare) *Person {
+			if prefix == "originator" {
+				if p.Originator == nil {
+					p.Originator = &Person{}
+				}
+				return p.Originator
+			}
+
+			if p.Beneficiary == nil {
+				p.Beneficiary = &Person{}
+			}
+			return p.Beneficiary
+		}
+
+		for field, set := range personColumns {
+			batchColumns[prefix+"."+field] = func(p *Prepare, value string) error {
+				return set(person(p), value)
+			}
+		}
+	}
+}
+
+var personColumns = map[string]func(*Person, string) error{
+	"crypto_address":             func(p *Person, v string) error { p.CryptoAddress = v; return nil },
+	"forename":                   func(p *Person, v string) error { p.Forename = v; return nil },
+	"surname":                    func(p *Person, v string) error { p.Surname = v; return nil },
+	"country_of_residence":       func(p *Person, v string) error { p.ResidesIn = v; return nil },
+	"customer_id":                func(p *Person, v string) error { p.CustomerID = v; return nil },
+	"identification.type_code":   identificationColumn(func(i *Identification, v string) { i.TypeCode = v }),
+	"identification.number":      identificationColumn(func(i *Identification, v string) { i.Number = v }),
+	"identification.country":     identificationColumn(func(i *Identification, v string) { i.Country = v }),
+	"identification.dob":         identificationColumn(func(i *Identification, v string) { i.DateOfBirth = v }),
+	"identification.birth_place": identificationColumn(func(i *Identification, v string) { i.BirthPlace = v }),
+	"address.address_type":       addressColumn(func(a *Address, v string) { a.AddressType = v }),
+	"address.address_lines":      addressColumn(func(a *Address, v string) { a.AddressLines = strings.Split(v, "\n") }),
+	"address.country":            addressColumn(func(a *Address, v string) { a.Country = v }),
+}
+
+func routingColumn(set func(*Routing, string) error) batchColumn {
+	return func(p *Prepare, value string) error {
+		if p.Routing == nil {
+			p.Routing = &Routing{}
+		}
+		return set(p.Routing, value)
+	}
+}
+
+func transferColumn(set func(*Transfer, string) error) batchColumn {
+	return func(p *Prepare, value string) error {
+		if p.Transfer == nil {
+			p.Transfer = &Transfer{}
+		}
+		return set(p.Transfer, value)
+	}
+}
+
+func identificationColumn(set func(*Identification, string)) func(*Person, string) error {
+	return func(p *Person, value string) error {
+		if p.Identification == nil {
+			p.Identification = &Identification{}
+		}
+		set(p.Identification, value)
+		return nil
+	}
+}
+
+func addressColumn(set func(*Address, string)) func(*Person, string) error {
+	return func(p *Person, value string) error {
+		if len(p.Addresses) == 0 {
+			p.Addresses = []*Address{{}}
+		}
+		set(p.Addresses[0], value)
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+package api_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+func TestParseBatchFormat(t *testing.T) {
+	testCases := []struct {
+		filename    string
+		contentType string
+		expected    string
+		err         error
+	}{
+		{"transfers.csv", "", api.BatchFormatCSV, nil},
+		{"transfers.CSV", "application/octet-stream", api.BatchFormatCSV, nil},
+		{"transfers.jsonl", "", api.BatchFormatJSONL, nil},
+		{"transfers.ndjson", "", api.BatchFormatJSONL, nil},
+		{"", "text/csv; charset=utf-8", api.BatchFormatCSV, nil},
+		{"", "application/x-ndjson", api.BatchFormatJSONL, nil},
+		{"transfers.txt", "text/csv", api.BatchFormatCSV, nil},
+		{"transfers.txt", "text/plain", "", api.ErrUnknownBatchFormat},
+		{"", "", "", api.ErrUnknownBatchFormat},
+	}
+
+	for i, tc := range testCases {
+		format, err := api.ParseBatchFormat(tc.filename, tc.contentType)
+		require.ErrorIs(t, err, tc.err, "test case %d failed", i)
+		require.Equal(t, tc.expected, format, "test case %d failed", i)
+	}
+}
+
+func TestParseBatchCSV(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		data := strings.Join([]string{
+			"Routing.Protocol,routing.travel_address,originator.forename,originator.surname,originator.crypto_address,beneficiary.forename,beneficiary.surname,beneficiary.crypto_address,beneficiary.address.address_lines,beneficiary.address.country,transfer.amount,transfer.network",
+			`trisa,ta2TzS6Ss5Z2AaB9mBbQKF2NzS4bN5S1WE,Alice,Smith,n2nrF4Y5VKRTPzt8H5dVg1zoFpoFLnBVaH,Bob,Jones,mjJfCz6bhoAkVd9Ke3cF9nBZHPiHyX5Vq2,"1 Main St` + "\n" + `Springfield",US,0.25,BTC`,
+			"",
+			"trp,,Carol,Lee,0xb794f5ea0ba39494ce839613fffba74279579268,Dan,Brown,0x1f9090aae28b8a3dceadf281b0f12828e676c326,,,1.5,ETH",
+		}, "\n")
+
+		rows, err := api.ParseBatch(api.BatchFormatCSV, strings.NewReader(data), 10)
+		require.NoError(t, err, "could not parse csv batch")
+		require.Len(t, rows, 2, "expected blank rows to be skipped")
+
+		require.Equal(t, int64(2), rows[0].Row)
+		require.NoError(t, rows[0].Err)
+		require.Equal(t, "trisa", rows[0].Prepare.Routing.Protocol)
+		require.Equal(t, "Alice", rows[0].Prepare.Originator.Forename)
+		require.Equal(t, []string{"1 Main St", "Springfield"}, rows[0].Prepare.Beneficiary.Addresses[0].AddressLines)
+		require.Equal(t, 0.25, rows[0].Prepare.Transfer.Amount)
+
+		prepare := &api.Prepare{}
+		require.NoError(t, json.Unmarshal([]byte(rows[0].Request), prepare), "expected the request to be json")
+		require.Equal(t, "BTC", prepare.Transfer.Network)
+
+		require.Equal(t, int64(5), rows[1].Row, "expected the line the row starts on")
+		require.Error(t, rows[1].Err, "expected trp row without a travel address to be invalid")
+	})
+
+	t.Run("RowErrors", func(t *testing.T) {
+		data := strings.Join([]string{
+			"routing.protocol,routing.counterparty_id,originator.crypto_address,beneficiary.crypto_address,transfer.amount",
+			"trisa,notaulid,abc,def,ten",
+			"trisa,01HWR5VWW8V7ZFFVJVBEC7AV8A,,def,1",
+		}, "\n")
+
+		rows, err := api.ParseBatch(api.BatchFormatCSV, strings.NewReader(data), 10)
+		require.NoError(t, err, "expected row errors not to fail the batch")
+		require.Len(t, rows, 2)
+
+		detail := api.NewErrorDetail(rows[0].Err)
+		require.Len(t, detail, 3, "expected an error for each invalid column and validation error")
+		require.Equal(t, "routing.counterparty_id", detail[0].Field)
+		require.Equal(t, "transfer.amount", detail[1].Field)
+		require.Equal(t, "routing.travel_address or routing.counterparty_id", detail[2].Field)
+
+		detail = api.NewErrorDetail(rows[1].Err)
+		require.Len(t, detail, 1)
+		require.Equal(t, "originator", detail[0].Field)
+	})
+
+	t.Run("FileErrors", func(t *testing.T) {
+		_, err := api.ParseBatch(api.BatchFormatCSV, strings.NewReader("routing.protocol,foo\ntrisa,bar\n"), 10)
+		require.EqualError(t, err, `unknown column "foo" in csv header`)
+
+		_, err = api.ParseBatch(api.BatchFormatCSV, strings.NewReader(""), 10)
+		require.ErrorIs(t, err, api.ErrEmptyBatch)
+
+		_, err = api.ParseBatch(api.BatchFormatCSV, strings.NewReader("routing.protocol\n"), 10)
+		require.ErrorIs(t, err, api.ErrEmptyBatch)
+
+		_, err = api.ParseBatch(api.BatchFormatCSV, strings.NewReader("routing.protocol\ntrisa\ntrisa\ntrisa\n"), 2)
+		require.EqualError(t, err, "the batch exceeds the maximum of 2 transfers")
+	})
+}
+
+func TestParseBatchJSONL(t *testing.T) {
+	data := strings.Join([]string{
+		`{"routing": {"protocol": "trisa", "travel_address": "ta2TzS6Ss5Z2AaB9mBbQKF2NzS4bN5S1WE"}, "originator": {"crypto_address": "abc"}, "beneficiary": {"crypto_address": "def"}, "transfer": {"amount": 1.5, "network": "BTC"}}`,
+		``,
+		`{"routing": `,
+		`{"routing": {"protocol": "trisa"}}`,
+	}, "\n")
+
+	rows, err := api.ParseBatch(api.BatchFormatJSONL, strings.NewReader(data), 10)
+	require.NoError(t, err, "could not parse jsonl batch")
+	require.Len(t, rows, 3)
+
+	require.Equal(t, int64(1), rows[0].Row)
+	require.NoError(t, rows[0].Err)
+	require.Equal(t, 1.5, rows[0].Prepare.Transfer.Amount)
+
+	require.Equal(t, int64(3), rows[1].Row)
+	require.Error(t, rows[1].Err, "expected unparseable row to be invalid")
+	require.Equal(t, `{"routing":`, rows[1].Request, "expected the raw row to be kept")
+
+	require.Equal(t, int64(4), rows[2].Row)
+	require.Len(t, api.NewErrorDetail(rows[2].Err), 4)
+
+	_, err = api.ParseBatch(api.BatchFormatJSONL, strings.NewReader(data), 2)
+	require.Error(t, err, "expected too many rows")
+
+	_, err = api.ParseBatch("xml", strings.NewReader(data), 10)
+	require.ErrorIs(t, err, api.ErrUnknownBatchFormat)
+}
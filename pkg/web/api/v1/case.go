@@ -0,0 +1,155 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+const (
+	maxNoteLength = 4096
+	maxTagLength  = 64
+	maxTags       = 32
+)
+
+//===========================================================================
+// Transaction Notes
+//===========================================================================
+
+// TransactionNote is an investigator's comment in the review thread of a transaction;
+// notes cannot be modified or deleted once they are posted.
+type TransactionNote struct {
+	ID            ulid.ULID `json:"id,omitempty"`
+	TransactionID uuid.UUID `json:"transaction_id,omitempty"`
+	Body          string    `json:"body"`
+	Author        string    `json:"author,omitempty"`
+	Created       time.Time `json:"created,omitempty"`
+}
+
+type TransactionNoteList struct {
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	Notes         []*TransactionNote `json:"notes"`
+}
+
+func NewTransactionNote(model *models.TransactionNote) (out *TransactionNote, err error) {
+	return &TransactionNote{
+		ID:            model.ID,
+		TransactionID: model.TransactionID,
+		Body:          model.Body,
+		Author:        model.Author,
+		Created:       model.Created,
+	}, nil
+}
+
+func NewTransactionNoteList(transactionID uuid.UUID, notes []*models.TransactionNote) (out *TransactionNoteList, err error) {
+	out = &TransactionNoteList{
+		TransactionID: transactionID,
+		Notes:         make([]*TransactionNote, 0, len(notes)),
+	}
+
+	for _, model := range notes {
+		var note *TransactionNote
+		if note, err = NewTransactionNote(model); err != nil {
+			return nil, err
+		}
+		out.Notes = append(out.Notes, note)
+	}
+
+	return out, nil
+}
+
+// Validate a note that is being posted; only the body of the note can be written.
+func (n *TransactionNote) Validate() (err error) {
+	n.Body = strings.TrimSpace(n.Body)
+	if n.Body == "" {
+		err = ValidationError(err, MissingField("body"))
+	} else if len(n.Body) > maxNoteLength {
+		err = ValidationError(err, IncorrectField("body", "notes cannot be longer than 4096 characters"))
+	}
+
+	if !n.ID.IsZero() {
+		err = ValidationError(err, ReadOnlyField("id"))
+	}
+
+	if n.Author != "" {
+		err = ValidationError(err, ReadOnlyField("author"))
+	}
+
+	if !n.Created.IsZero() {
+		err = ValidationError(err, ReadOnlyField("created"))
+	}
+
+	return err
+}
+
+func (n *TransactionNote) Model(transactionID uuid.UUID) (model *models.TransactionNote, err error) {
+	return &models.TransactionNote{
+		TransactionID: transactionID,
+		Body:          n.Body,
+	}, nil
+}
+
+//===========================================================================
+// Transaction Assignment
+//===========================================================================
+
+// TransactionAssignment assigns a transaction to a user for review; an empty assignee
+// removes the assignment from the transaction.
+type TransactionAssignment struct {
+	AssigneeID string `json:"assignee_id"`
+}
+
+func (a *TransactionAssignment) Validate() (err error) {
+	a.AssigneeID = strings.TrimSpace(a.AssigneeID)
+	if a.AssigneeID != "" {
+		if _, perr := ulid.Parse(a.AssigneeID); perr != nil {
+			err = ValidationError(err, IncorrectField("assignee_id", "assignee must be the id of a user"))
+		}
+	}
+	return err
+}
+
+// Assignee returns the parsed ID of the user, which is null if the transaction is
+// being unassigned. Validate must be called first.
+func (a *TransactionAssignment) Assignee() (assignee ulid.NullULID) {
+	if a.AssigneeID != "" {
+		assignee.ULID, _ = ulid.Parse(a.AssigneeID)
+		assignee.Valid = true
+	}
+	return assignee
+}
+
+//===========================================================================
+// Transaction Tags
+//===========================================================================
+
+// TransactionTags are the free-form labels of a transaction or all of the labels that
+// are in use. Tags are case-insensitive and are returned in lowercase.
+type TransactionTags struct {
+	Tags []string `json:"tags"`
+}
+
+// Validate and normalize the tags; an empty list removes all tags from a transaction.
+func (t *TransactionTags) Validate() (err error) {
+	t.Tags = models.NormalizeTags(t.Tags)
+	if len(t.Tags) > maxTags {
+		err = ValidationError(err, IncorrectField("tags", "a transaction cannot have more than 32 tags"))
+	}
+
+	for _, tag := range t.Tags {
+		if len(tag) > maxTagLength {
+			err = ValidationError(err, IncorrectField("tags", "tags cannot be longer than 64 characters"))
+			break
+		}
+
+		if strings.Contains(tag, models.TagSeparator) {
+			err = ValidationError(err, IncorrectField("tags", "tags cannot contain commas"))
+			break
+		}
+	}
+
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/trisacrypto/envoy/pkg/web/api/v1/credentials"
@@ -51,6 +52,12 @@ func New(endpoint string, opts ...ClientOption) (_ Client, err error) {
 		}
 	}
 
+	// Wrap the transport of a copy of the http client (which may be shared) so that
+	// expired access tokens are refreshed and the request retried automatically.
+	client := *c.client
+	client.Transport = &reauthenticator{client: c, next: client.Transport}
+	c.client = &client
+
 	return c, nil
 }
 
@@ -58,7 +65,10 @@ func New(endpoint string, opts ...ClientOption) (_ Client, err error) {
 type APIv1 struct {
 	endpoint *url.URL                // the base url for all requests
 	client   *http.Client            // used to make http requests to the server
+	mu       sync.RWMutex            // guards the creds and refresh token
 	creds    credentials.Credentials // default credentials used to authorize requests
+	refresh  string                  // refresh token used to reauthenticate when creds expire
+	reauth   sync.Mutex              // ensures concurrent requests only reauthenticate once
 }
 
 // Ensure the APIv1 implements the Client interface
@@ -143,12 +153,23 @@ func (s *APIv1) authenticate(ctx context.Context, endpoint string, in any) (out
 		return nil, err
 	}
 
-	// Set the returned credentials on the client for future requests
-	// TODO: handle refresh tokens for reauthentication
+	// Set the returned credentials on the client for future requests and keep the
+	// refresh token so the client can reauthenticate when the access token expires.
+	s.mu.Lock()
 	s.creds = credentials.Token(out.AccessToken)
+	s.refresh = out.RefreshToken
+	s.mu.Unlock()
 	return out, err
 }
 
+// Returns the current credentials and refresh token of the client, which may be
+// replaced by concurrent requests that authenticate or reauthenticate the client.
+func (s *APIv1) credentials() (credentials.Credentials, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.creds, s.refresh
+}
+
 //===========================================================================
 // Transactions Resource
 //===========================================================================
@@ -163,6 +184,7 @@ func (s *APIv1) ListTransactions(ctx context.Context, in *TransactionListQuery)
 }
 
 func (s *APIv1) CreateTransaction(ctx context.Context, in *Transaction) (out *Transaction, err error) {
+	ctx = withIdempotencyKey(ctx)
 	if err = s.Create(ctx, transactionsEP, in, &out); err != nil {
 		return nil, err
 	}
@@ -207,6 +229,7 @@ func (s *APIv1) Prepare(ctx context.Context, in *Prepare) (out *Prepared, err er
 const sendPreparedEP = "send-prepared"
 
 func (s *APIv1) SendPrepared(ctx context.Context, in *Prepared) (out *Transaction, err error) {
+	ctx = withIdempotencyKey(ctx)
 	endpoint, _ := url.JoinPath(transactionsEP, sendPreparedEP)
 	if err = s.Create(ctx, endpoint, in, &out); err != nil {
 		return nil, err
@@ -216,46 +239,27 @@ func (s *APIv1) SendPrepared(ctx context.Context, in *Prepared) (out *Transactio
 
 const exportEP = "export"
 
-func (s *APIv1) Export(ctx context.Context, w io.Writer) (err error) {
-	endpoint, _ := url.JoinPath(transactionsEP, exportEP)
-
-	// Create a new authenticated request with the correct headers
-	var req *http.Request
-	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, nil); err != nil {
-		return err
-	}
-
-	// Execute the request directly with the client so we can stream the response
-	var rep *http.Response
-	if rep, err = s.client.Do(req); err != nil {
-		return fmt.Errorf("could not execute export request: %w", err)
-	}
-	defer rep.Body.Close()
-
-	// Check the status to ensure we can start reading
-	if rep.StatusCode != http.StatusOK {
-		serr := &StatusError{StatusCode: rep.StatusCode}
-		if err = json.NewDecoder(rep.Body).Decode(&serr.Reply); err != nil {
-			serr.Reply = Unsuccessful
-			serr.Reply.Error = http.StatusText(rep.StatusCode)
-		}
-		return serr
+func (s *APIv1) Export(ctx context.Context, in *TransactionExportQuery, w io.Writer) (err error) {
+	var params url.Values
+	if params, err = query.Values(in); err != nil {
+		return fmt.Errorf("could not encode export query: %w", err)
 	}
 
-	// Copy the body of the response into the writer
-	if _, err := io.Copy(w, rep.Body); err != nil {
-		return fmt.Errorf("could not copy csv export to writer: %w", err)
-	}
-	return nil
+	endpoint, _ := url.JoinPath(transactionsEP, exportEP)
+	return s.Download(ctx, endpoint, &params, w)
 }
 
 //===========================================================================
 // Transaction Detail Actions
 //===========================================================================
 
-const sendEP = "send"
+const (
+	sendEP     = "send"
+	evidenceEP = "evidence"
+)
 
 func (s *APIv1) SendEnvelope(ctx context.Context, transactionID uuid.UUID, in *Envelope) (out *Envelope, err error) {
+	ctx = withIdempotencyKey(ctx)
 	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), sendEP)
 	if err = s.Create(ctx, endpoint, in, &out); err != nil {
 		return nil, err
@@ -284,6 +288,7 @@ func (s *APIv1) AcceptPreview(ctx context.Context, transactionID uuid.UUID) (out
 }
 
 func (s *APIv1) Accept(ctx context.Context, transactionID uuid.UUID, in *Envelope) (out *Envelope, err error) {
+	ctx = withIdempotencyKey(ctx)
 	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), acceptEP)
 	if err = s.Create(ctx, endpoint, in, &out); err != nil {
 		return nil, err
@@ -294,6 +299,7 @@ func (s *APIv1) Accept(ctx context.Context, transactionID uuid.UUID, in *Envelop
 const rejectEP = "reject"
 
 func (s *APIv1) Reject(ctx context.Context, transactionID uuid.UUID, in *Rejection) (out *Envelope, err error) {
+	ctx = withIdempotencyKey(ctx)
 	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), rejectEP)
 	if err = s.Create(ctx, endpoint, in, &out); err != nil {
 		return nil, err
@@ -330,6 +336,7 @@ func (s *APIv1) CompletePreview(ctx context.Context, transactionID uuid.UUID) (o
 }
 
 func (s *APIv1) Complete(ctx context.Context, transactionID uuid.UUID, in *generic.Transaction) (out *Envelope, err error) {
+	ctx = withIdempotencyKey(ctx)
 	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), completeEP)
 	if err = s.Create(ctx, endpoint, in, &out); err != nil {
 		return nil, err
@@ -371,6 +378,71 @@ func (s *APIv1) UnarchiveTransaction(ctx context.Context, transactionID uuid.UUI
 	return nil
 }
 
+const historyEP = "history"
+
+func (s *APIv1) TransactionHistory(ctx context.Context, transactionID uuid.UUID) (out *TransactionHistory, err error) {
+	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), historyEP)
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) TransactionEvidence(ctx context.Context, transactionID uuid.UUID, w io.Writer) (err error) {
+	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), evidenceEP)
+	return s.Download(ctx, endpoint, nil, w)
+}
+
+//===========================================================================
+// Transaction Case Management
+//===========================================================================
+
+const (
+	notesEP    = "notes"
+	assigneeEP = "assignee"
+	tagsEP     = "tags"
+)
+
+func (s *APIv1) ListTransactionNotes(ctx context.Context, transactionID uuid.UUID) (out *TransactionNoteList, err error) {
+	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), notesEP)
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CreateTransactionNote(ctx context.Context, transactionID uuid.UUID, in *TransactionNote) (out *TransactionNote, err error) {
+	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), notesEP)
+	if err = s.Create(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) AssignTransaction(ctx context.Context, transactionID uuid.UUID, in *TransactionAssignment) (out *Transaction, err error) {
+	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), assigneeEP)
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ListTransactionTags(ctx context.Context) (out *TransactionTags, err error) {
+	endpoint, _ := url.JoinPath(transactionsEP, tagsEP)
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) SetTransactionTags(ctx context.Context, transactionID uuid.UUID, in *TransactionTags) (out *TransactionTags, err error) {
+	endpoint, _ := url.JoinPath(transactionsEP, transactionID.String(), tagsEP)
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 //===========================================================================
 // Secure and Decrypted Envelopes Resource
 //===========================================================================
@@ -664,6 +736,24 @@ func (s *APIv1) DeleteCounterparty(ctx context.Context, id ulid.ULID) (err error
 	return s.Delete(ctx, endpoint)
 }
 
+const pendingEP = "pending"
+
+func (s *APIv1) CounterpartyPending(ctx context.Context, id ulid.ULID) (out *CounterpartyPending, err error) {
+	endpoint, _ := url.JoinPath(counterpartiesEP, id.String(), pendingEP)
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) UpdateCounterpartyPending(ctx context.Context, id ulid.ULID, in *CounterpartyPending) (out *CounterpartyPending, err error) {
+	endpoint, _ := url.JoinPath(counterpartiesEP, id.String(), pendingEP)
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 //===========================================================================
 // Contacts Resource
 //===========================================================================
@@ -755,6 +845,108 @@ func (s *APIv1) ChangeUserPassword(ctx context.Context, id ulid.ULID, in *UserPa
 	return s.Create(ctx, endpoint, in, nil)
 }
 
+const resetMFAEP = "mfa/reset"
+
+func (s *APIv1) ResetUserMFA(ctx context.Context, id ulid.ULID) error {
+	endpoint, _ := url.JoinPath(usersEP, id.String(), resetMFAEP)
+	return s.Create(ctx, endpoint, nil, nil)
+}
+
+const unlockUserEP = "unlock"
+
+func (s *APIv1) UnlockUser(ctx context.Context, id ulid.ULID) error {
+	endpoint, _ := url.JoinPath(usersEP, id.String(), unlockUserEP)
+	return s.Create(ctx, endpoint, nil, nil)
+}
+
+//===========================================================================
+// User Invites Resource
+//===========================================================================
+
+const (
+	invitesEP      = "/v1/invites"
+	resendInviteEP = "resend"
+)
+
+func (s *APIv1) ListInvites(ctx context.Context) (out *UserInviteList, err error) {
+	if err = s.Detail(ctx, invitesEP, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CreateInvite(ctx context.Context, in *UserInvite) (out *UserInvite, err error) {
+	if err = s.Create(ctx, invitesEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ResendInvite(ctx context.Context, id ulid.ULID) (out *UserInvite, err error) {
+	endpoint, _ := url.JoinPath(invitesEP, id.String(), resendInviteEP)
+	if err = s.Create(ctx, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) RevokeInvite(ctx context.Context, id ulid.ULID) error {
+	endpoint, _ := url.JoinPath(invitesEP, id.String())
+	return s.Delete(ctx, endpoint)
+}
+
+//===========================================================================
+// Roles Resource
+//===========================================================================
+
+const (
+	rolesEP   = "/v1/roles"
+	roleMFAEP = "mfa"
+)
+
+func (s *APIv1) ListRoles(ctx context.Context) (out *RoleList, err error) {
+	if err = s.Detail(ctx, rolesEP, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CreateRole(ctx context.Context, in *Role) (out *Role, err error) {
+	if err = s.Create(ctx, rolesEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) RoleDetail(ctx context.Context, role string) (out *Role, err error) {
+	endpoint, _ := url.JoinPath(rolesEP, role)
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) UpdateRole(ctx context.Context, role string, in *Role) (out *Role, err error) {
+	endpoint, _ := url.JoinPath(rolesEP, role)
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) DeleteRole(ctx context.Context, role string) error {
+	endpoint, _ := url.JoinPath(rolesEP, role)
+	return s.Delete(ctx, endpoint)
+}
+
+func (s *APIv1) SetRoleMFAPolicy(ctx context.Context, role string, in *RoleMFAPolicy) (out *Role, err error) {
+	endpoint, _ := url.JoinPath(rolesEP, role, roleMFAEP)
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 //===========================================================================
 // APIKeys Resource
 //===========================================================================
@@ -790,6 +982,14 @@ func (s *APIv1) UpdateAPIKey(ctx context.Context, in *APIKey) (out *APIKey, err
 	return out, nil
 }
 
+func (s *APIv1) RotateAPIKey(ctx context.Context, keyID ulid.ULID, in *APIKeyRotation) (out *APIKey, err error) {
+	endpoint, _ := url.JoinPath(apikeysEP, keyID.String(), "rotate")
+	if err = s.Create(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (s *APIv1) DeleteAPIKey(ctx context.Context, keyID ulid.ULID) error {
 	endpoint, _ := url.JoinPath(apikeysEP, keyID.String())
 	return s.Delete(ctx, endpoint)
@@ -816,6 +1016,335 @@ func (s *APIv1) ComplianceAuditLogDetail(ctx context.Context, logID ulid.ULID) (
 	return out, nil
 }
 
+//===========================================================================
+// LegalHolds Resource
+//===========================================================================
+
+const (
+	legalholdsEP = "/v1/legalholds"
+	releaseEP    = "release"
+)
+
+func (s *APIv1) ListLegalHolds(ctx context.Context, in *LegalHoldQuery) (out *LegalHoldList, err error) {
+	if err = s.List(ctx, legalholdsEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CreateLegalHold(ctx context.Context, in *LegalHold) (out *LegalHold, err error) {
+	if err = s.Create(ctx, legalholdsEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) LegalHoldDetail(ctx context.Context, holdID ulid.ULID) (out *LegalHold, err error) {
+	endpoint, _ := url.JoinPath(legalholdsEP, holdID.String())
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) UpdateLegalHold(ctx context.Context, in *LegalHold) (out *LegalHold, err error) {
+	endpoint, _ := url.JoinPath(legalholdsEP, in.ID.String())
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ReleaseLegalHold(ctx context.Context, holdID ulid.ULID) (out *LegalHold, err error) {
+	endpoint, _ := url.JoinPath(legalholdsEP, holdID.String(), releaseEP)
+	if err = s.Create(ctx, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+//===========================================================================
+// Approvals Resource
+//===========================================================================
+
+const (
+	approvalsEP     = "/v1/approvals"
+	approvalRulesEP = "/v1/approvals/rules"
+	approveEP       = "approve"
+	denyEP          = "deny"
+	cancelEP        = "cancel"
+)
+
+func (s *APIv1) ListApprovals(ctx context.Context, in *ApprovalQuery) (out *ApprovalList, err error) {
+	if err = s.List(ctx, approvalsEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ApprovalDetail(ctx context.Context, approvalID ulid.ULID) (out *Approval, err error) {
+	endpoint, _ := url.JoinPath(approvalsEP, approvalID.String())
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ApproveAction(ctx context.Context, approvalID ulid.ULID, in *ApprovalReview) (out *Approval, err error) {
+	endpoint, _ := url.JoinPath(approvalsEP, approvalID.String(), approveEP)
+	if err = s.Create(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) DenyAction(ctx context.Context, approvalID ulid.ULID, in *ApprovalReview) (out *Approval, err error) {
+	endpoint, _ := url.JoinPath(approvalsEP, approvalID.String(), denyEP)
+	if err = s.Create(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CancelAction(ctx context.Context, approvalID ulid.ULID, in *ApprovalReview) (out *Approval, err error) {
+	endpoint, _ := url.JoinPath(approvalsEP, approvalID.String(), cancelEP)
+	if err = s.Create(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ListApprovalRules(ctx context.Context) (out *ApprovalRuleList, err error) {
+	if err = s.Detail(ctx, approvalRulesEP, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CreateApprovalRule(ctx context.Context, in *ApprovalRule) (out *ApprovalRule, err error) {
+	if err = s.Create(ctx, approvalRulesEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ApprovalRuleDetail(ctx context.Context, ruleID ulid.ULID) (out *ApprovalRule, err error) {
+	endpoint, _ := url.JoinPath(approvalRulesEP, ruleID.String())
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) UpdateApprovalRule(ctx context.Context, in *ApprovalRule) (out *ApprovalRule, err error) {
+	endpoint, _ := url.JoinPath(approvalRulesEP, in.ID.String())
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) DeleteApprovalRule(ctx context.Context, ruleID ulid.ULID) (err error) {
+	endpoint, _ := url.JoinPath(approvalRulesEP, ruleID.String())
+	return s.Delete(ctx, endpoint)
+}
+
+//===========================================================================
+// Outbound Queue Resource
+//===========================================================================
+
+const (
+	queueEP = "/v1/queue"
+	retryEP = "retry"
+)
+
+func (s *APIv1) ListQueuedTransfers(ctx context.Context) (out *QueuedTransferList, err error) {
+	if err = s.Detail(ctx, queueEP, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) QueuedTransferDetail(ctx context.Context, transferID ulid.ULID) (out *QueuedTransfer, err error) {
+	endpoint, _ := url.JoinPath(queueEP, transferID.String())
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CancelQueuedTransfer(ctx context.Context, transferID ulid.ULID) (out *QueuedTransfer, err error) {
+	endpoint, _ := url.JoinPath(queueEP, transferID.String(), cancelEP)
+	if err = s.Create(ctx, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) RetryQueuedTransfer(ctx context.Context, transferID ulid.ULID) (out *QueuedTransfer, err error) {
+	endpoint, _ := url.JoinPath(queueEP, transferID.String(), retryEP)
+	if err = s.Create(ctx, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+//===========================================================================
+// Batch Import Resource
+//===========================================================================
+
+const batchesEP = "/v1/batches"
+
+func (s *APIv1) ListBatches(ctx context.Context) (out *BatchList, err error) {
+	if err = s.Detail(ctx, batchesEP, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateBatch uploads a CSV or JSON Lines file of transfers; the format of the file is
+// determined from the extension of the filename.
+func (s *APIv1) CreateBatch(ctx context.Context, filename string, data []byte) (out *Batch, err error) {
+	var format string
+	if format, err = ParseBatchFormat(filename, ""); err != nil {
+		return nil, err
+	}
+
+	ctx = withIdempotencyKey(ctx)
+	params := &url.Values{}
+	params.Set("filename", filename)
+
+	// Create a new authenticated request then replace the JSON body with the file
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodPost, batchesEP, nil, params); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", BatchContentType(format))
+	req.ContentLength = int64(len(data))
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	if _, err = s.Do(req, &out, true); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) BatchDetail(ctx context.Context, batchID ulid.ULID) (out *Batch, err error) {
+	endpoint, _ := url.JoinPath(batchesEP, batchID.String())
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) SendBatch(ctx context.Context, batchID ulid.ULID) (out *Batch, err error) {
+	ctx = withIdempotencyKey(ctx)
+	endpoint, _ := url.JoinPath(batchesEP, batchID.String(), sendEP)
+	if err = s.Create(ctx, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CancelBatch(ctx context.Context, batchID ulid.ULID) (out *Batch, err error) {
+	endpoint, _ := url.JoinPath(batchesEP, batchID.String(), cancelEP)
+	if err = s.Create(ctx, endpoint, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+//===========================================================================
+// Reports Resource
+//===========================================================================
+
+const (
+	reportsEP  = "/v1/reports"
+	downloadEP = "download"
+)
+
+func (s *APIv1) ListReports(ctx context.Context) (out *ReportList, err error) {
+	if err = s.Detail(ctx, reportsEP, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CreateReport(ctx context.Context, in *Report) (out *Report, err error) {
+	if err = s.Create(ctx, reportsEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ReportDetail(ctx context.Context, reportID ulid.ULID) (out *Report, err error) {
+	endpoint, _ := url.JoinPath(reportsEP, reportID.String())
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) DownloadReport(ctx context.Context, reportID ulid.ULID, in *ReportDownloadQuery, w io.Writer) (err error) {
+	var params url.Values
+	if params, err = query.Values(in); err != nil {
+		return fmt.Errorf("could not encode report download query: %w", err)
+	}
+
+	endpoint, _ := url.JoinPath(reportsEP, reportID.String(), downloadEP)
+	return s.Download(ctx, endpoint, &params, w)
+}
+
+func (s *APIv1) DeleteReport(ctx context.Context, reportID ulid.ULID) error {
+	endpoint, _ := url.JoinPath(reportsEP, reportID.String())
+	return s.Delete(ctx, endpoint)
+}
+
+//===========================================================================
+// Threshold Rules Resource
+//===========================================================================
+
+const thresholdsEP = "/v1/thresholds"
+
+func (s *APIv1) ListThresholdRules(ctx context.Context) (out *ThresholdRuleList, err error) {
+	if err = s.Detail(ctx, thresholdsEP, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) CreateThresholdRule(ctx context.Context, in *ThresholdRule) (out *ThresholdRule, err error) {
+	if err = s.Create(ctx, thresholdsEP, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) ThresholdRuleDetail(ctx context.Context, ruleID ulid.ULID) (out *ThresholdRule, err error) {
+	endpoint, _ := url.JoinPath(thresholdsEP, ruleID.String())
+	if err = s.Detail(ctx, endpoint, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) UpdateThresholdRule(ctx context.Context, in *ThresholdRule) (out *ThresholdRule, err error) {
+	endpoint, _ := url.JoinPath(thresholdsEP, in.ID.String())
+	if err = s.Update(ctx, endpoint, in, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *APIv1) DeleteThresholdRule(ctx context.Context, ruleID ulid.ULID) error {
+	endpoint, _ := url.JoinPath(thresholdsEP, ruleID.String())
+	return s.Delete(ctx, endpoint)
+}
+
 //===========================================================================
 // Utilities Resource
 //===========================================================================
@@ -988,6 +1517,38 @@ func (s *APIv1) Delete(ctx context.Context, endpoint string) (err error) {
 	return nil
 }
 
+// Download streams the body of a GET request (e.g. a file export) into the writer.
+func (s *APIv1) Download(ctx context.Context, endpoint string, params *url.Values, w io.Writer) (err error) {
+	// Create a new authenticated request with the correct headers
+	var req *http.Request
+	if req, err = s.NewRequest(ctx, http.MethodGet, endpoint, nil, params); err != nil {
+		return err
+	}
+
+	// Execute the request directly with the client so we can stream the response
+	var rep *http.Response
+	if rep, err = s.client.Do(req); err != nil {
+		return fmt.Errorf("could not execute download request: %w", err)
+	}
+	defer rep.Body.Close()
+
+	// Check the status to ensure we can start reading
+	if rep.StatusCode != http.StatusOK {
+		serr := &StatusError{StatusCode: rep.StatusCode}
+		if err = json.NewDecoder(rep.Body).Decode(&serr.Reply); err != nil {
+			serr.Reply = Unsuccessful
+			serr.Reply.Error = http.StatusText(rep.StatusCode)
+		}
+		return serr
+	}
+
+	// Copy the body of the response into the writer
+	if _, err := io.Copy(w, rep.Body); err != nil {
+		return fmt.Errorf("could not copy download to writer: %w", err)
+	}
+	return nil
+}
+
 //===========================================================================
 // Helper Methods
 //===========================================================================
@@ -1037,10 +1598,15 @@ func (s *APIv1) NewRequest(ctx context.Context, method, path string, data interf
 	}
 	req.Header.Add("X-Request-ID", requestID)
 
+	// If there is an idempotency key on the context, set it on the request
+	if key, _ := IdempotencyKeyFromContext(ctx); key != "" {
+		req.Header.Add("Idempotency-Key", key)
+	}
+
 	// Add authentication and authorization header.
-	if s.creds != nil {
+	if creds, _ := s.credentials(); creds != nil {
 		var token string
-		if token, err = s.creds.AccessToken(); err != nil {
+		if token, err = creds.AccessToken(); err != nil {
 			return nil, err
 		}
 		req.Header.Set("Authorization", "Bearer "+token)
@@ -1088,6 +1654,16 @@ func (s *APIv1) Do(req *http.Request, data interface{}, checkStatus bool) (rep *
 		}
 	}
 
+	// A transfer workflow action that requires four-eyes approval is held for review
+	// and the pending approval is returned instead of the expected response.
+	if rep.StatusCode == http.StatusAccepted {
+		aerr := &ApprovalRequired{Approval: &Approval{}}
+		if err = json.NewDecoder(rep.Body).Decode(aerr.Approval); err != nil {
+			return nil, fmt.Errorf("could not deserialize approval response: %s", err)
+		}
+		return rep, aerr
+	}
+
 	// Deserialize the JSON data from the body
 	if data != nil && rep.StatusCode >= 200 && rep.StatusCode < 300 && rep.StatusCode != http.StatusNoContent {
 		ct := rep.Header.Get("Content-Type")
@@ -1109,3 +1685,14 @@ func (s *APIv1) Do(req *http.Request, data interface{}, checkStatus bool) (rep *
 
 	return rep, nil
 }
+
+// Ensures that the request made with the context has an idempotency key so that the
+// server does not create or send a transaction twice if the request is retried (e.g.
+// when reauthenticating). Callers that retry requests themselves should add a key to
+// the context with ContextWithIdempotencyKey and reuse it for every attempt.
+func withIdempotencyKey(ctx context.Context) context.Context {
+	if key, _ := IdempotencyKeyFromContext(ctx); key != "" {
+		return ctx
+	}
+	return ContextWithIdempotencyKey(ctx, uuid.NewString())
+}
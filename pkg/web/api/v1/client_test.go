@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"go.rtnl.ai/ulid"
 )
 
 var (
@@ -169,6 +170,65 @@ func TestDeleteTransaction(t *testing.T) {
 	require.NoError(t, err, "could not execute delete transaction request")
 }
 
+func TestApprovalRequired(t *testing.T) {
+	fixture := &api.Approval{
+		ID:          ulid.MustParse("01JB7T6Y4KX8QS0R6V3M2N9P5D"),
+		Action:      "send",
+		Status:      "pending",
+		RequestedBy: "Compliance User",
+	}
+
+	_, client := testServer(t, &testServerConfig{
+		expectedMethod: http.MethodPost,
+		expectedPath:   "/v1/transactions/send-prepared",
+		fixture:        fixture,
+		statusCode:     http.StatusAccepted,
+	})
+
+	rep, err := client.SendPrepared(ctx, &api.Prepared{})
+	require.Nil(t, rep, "expected no transaction when the send is held for approval")
+
+	var approval *api.ApprovalRequired
+	require.ErrorAs(t, err, &approval, "expected an approval required error")
+	require.Equal(t, fixture.ID, approval.Approval.ID)
+	require.True(t, approval.Approval.IsPending())
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	var keys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(&api.Transaction{})
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := api.New(ts.URL)
+	require.NoError(t, err, "could not create api client")
+
+	// A key is generated for every request if one is not on the context
+	_, err = client.CreateTransaction(ctx, &api.Transaction{})
+	require.NoError(t, err, "could not execute create transaction request")
+	_, err = client.CreateTransaction(ctx, &api.Transaction{})
+	require.NoError(t, err, "could not execute create transaction request")
+
+	require.Len(t, keys, 2)
+	require.NotEmpty(t, keys[0], "expected an idempotency key to be generated")
+	require.NotEqual(t, keys[0], keys[1], "expected a new idempotency key for each request")
+
+	// The key on the context is used so the request can be retried
+	kctx := api.ContextWithIdempotencyKey(ctx, "retry-me")
+	_, err = client.CreateTransaction(kctx, &api.Transaction{})
+	require.NoError(t, err, "could not execute create transaction request")
+	require.Equal(t, "retry-me", keys[2])
+
+	// Requests that are not idempotent do not send a key
+	_, err = client.ListTransactions(ctx, page)
+	require.NoError(t, err, "could not execute list transactions request")
+	require.Empty(t, keys[3], "expected no idempotency key on list requests")
+}
+
 type testServerConfig struct {
 	expectedMethod string
 	expectedPath   string
@@ -10,6 +10,7 @@ type contextKey uint8
 const (
 	contextKeyUnknown contextKey = iota
 	contextKeyRequestID
+	contextKeyIdempotencyKey
 )
 
 // Adds a request ID to the context which is sent with the request in the X-Request-ID header.
@@ -23,7 +24,20 @@ func RequestIDFromContext(ctx context.Context) (string, bool) {
 	return requestID, ok
 }
 
-var contextKeyNames = []string{"unknown", "requestID"}
+// Adds an idempotency key to the context which is sent with the request in the
+// Idempotency-Key header. Requests that create or send transactions are retried safely
+// by using the same idempotency key; if no key is on the context, one is generated.
+func ContextWithIdempotencyKey(parent context.Context, key string) context.Context {
+	return context.WithValue(parent, contextKeyIdempotencyKey, key)
+}
+
+// Extracts an idempotency key from the context.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(contextKeyIdempotencyKey).(string)
+	return key, ok
+}
+
+var contextKeyNames = []string{"unknown", "requestID", "idempotencyKey"}
 
 // String returns a human readable representation of the context key for easier debugging.
 func (c contextKey) String() string {
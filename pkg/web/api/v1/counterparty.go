@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/enum"
 	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
@@ -16,6 +17,7 @@ import (
 	"github.com/trisacrypto/trisa/pkg/ivms101"
 	"github.com/trisacrypto/trisa/pkg/openvasp/traddr"
 	"go.rtnl.ai/ulid"
+	"golang.org/x/text/language"
 )
 
 //===========================================================================
@@ -28,25 +30,26 @@ type CounterpartyQuery struct {
 }
 
 type Counterparty struct {
-	ID                  ulid.ULID      `json:"id,omitempty"`
-	Source              string         `json:"source,omitempty"`
-	DirectoryID         string         `json:"directory_id,omitempty"`
-	RegisteredDirectory string         `json:"registered_directory,omitempty"`
-	Protocol            string         `json:"protocol"`
-	CommonName          string         `json:"common_name,omitempty"`
-	Endpoint            string         `json:"endpoint"`
-	TravelAddress       string         `json:"travel_address,omitempty"`
-	Name                string         `json:"name"`
-	Website             string         `json:"website,omitempty"`
-	Country             string         `json:"country"`
-	BusinessCategory    string         `json:"business_category,omitempty"`
-	VASPCategories      []string       `json:"vasp_categories,omitempty"`
-	VerifiedOn          *time.Time     `json:"verified_on,omitempty"`
-	IVMSRecord          string         `json:"ivms101,omitempty"`
-	Contacts            []*Contact     `json:"contacts,omitempty"`
-	Created             time.Time      `json:"created,omitempty"`
-	Modified            *time.Time     `json:"modified,omitempty"`
-	encoding            *EncodingQuery `json:"-"`
+	ID                  ulid.ULID            `json:"id,omitempty"`
+	Source              string               `json:"source,omitempty"`
+	DirectoryID         string               `json:"directory_id,omitempty"`
+	RegisteredDirectory string               `json:"registered_directory,omitempty"`
+	Protocol            string               `json:"protocol"`
+	CommonName          string               `json:"common_name,omitempty"`
+	Endpoint            string               `json:"endpoint"`
+	TravelAddress       string               `json:"travel_address,omitempty"`
+	Name                string               `json:"name"`
+	Website             string               `json:"website,omitempty"`
+	Country             string               `json:"country"`
+	BusinessCategory    string               `json:"business_category,omitempty"`
+	VASPCategories      []string             `json:"vasp_categories,omitempty"`
+	VerifiedOn          *time.Time           `json:"verified_on,omitempty"`
+	IVMSRecord          string               `json:"ivms101,omitempty"`
+	Contacts            []*Contact           `json:"contacts,omitempty"`
+	Pending             *CounterpartyPending `json:"pending,omitempty"`
+	Created             time.Time            `json:"created,omitempty"`
+	Modified            *time.Time           `json:"modified,omitempty"`
+	encoding            *EncodingQuery       `json:"-"`
 }
 
 type Contact struct {
@@ -126,6 +129,11 @@ func NewCounterparty(model *models.Counterparty, encoding *EncodingQuery) (out *
 		out.Contacts = append(out.Contacts, c)
 	}
 
+	// Add the pending overrides if any are set
+	if !model.Pending.IsZero() {
+		out.Pending, _ = NewCounterpartyPending(model)
+	}
+
 	// Compute the travel address from the endpoint (ignore errors)
 	out.TravelAddress, _ = EndpointTravelAddress(model.Endpoint, model.Protocol)
 	return out, nil
@@ -303,6 +311,117 @@ func (c *Counterparty) SetEncoding(encoding *EncodingQuery) {
 	c.encoding = encoding
 }
 
+//===========================================================================
+// Counterparty Pending Overrides
+//===========================================================================
+
+const maxPendingMessageLength = 1024
+
+// CounterpartyPending customizes the pending response that is sent to the counterparty
+// when an incoming travel rule request must be reviewed; any field that is empty uses
+// the pending configuration of the node. The message is a template that can reference
+// {{.Organization}}, {{.Counterparty}}, {{.ReplyNotBefore}}, and {{.ReplyNotAfter}}
+// and the reply windows are durations such as 30m or 48h.
+type CounterpartyPending struct {
+	CounterpartyID ulid.ULID `json:"counterparty_id,omitempty"`
+	Language       string    `json:"language,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	ReplyNotBefore string    `json:"reply_not_before,omitempty"`
+	ReplyNotAfter  string    `json:"reply_not_after,omitempty"`
+}
+
+func NewCounterpartyPending(model *models.Counterparty) (out *CounterpartyPending, err error) {
+	out = &CounterpartyPending{
+		CounterpartyID: model.ID,
+		Language:       model.Pending.Language.String,
+		Message:        model.Pending.Message.String,
+	}
+
+	if model.Pending.ReplyNotBefore.Valid {
+		out.ReplyNotBefore = (time.Duration(model.Pending.ReplyNotBefore.Int64) * time.Second).String()
+	}
+
+	if model.Pending.ReplyNotAfter.Valid {
+		out.ReplyNotAfter = (time.Duration(model.Pending.ReplyNotAfter.Int64) * time.Second).String()
+	}
+
+	return out, nil
+}
+
+func (p *CounterpartyPending) Validate() (err error) {
+	p.Language = strings.TrimSpace(p.Language)
+	if p.Language != "" {
+		if tag, perr := language.Parse(p.Language); perr != nil {
+			err = ValidationError(err, IncorrectField("language", "language must be a language tag such as en or fr-CA"))
+		} else {
+			p.Language = tag.String()
+		}
+	}
+
+	p.Message = strings.TrimSpace(p.Message)
+	if len(p.Message) > maxPendingMessageLength {
+		err = ValidationError(err, IncorrectField("message", "message cannot be longer than 1024 characters"))
+	} else if p.Message != "" {
+		if _, perr := config.RenderPending(p.Message, &config.PendingData{}); perr != nil {
+			err = ValidationError(err, IncorrectField("message", perr.Error()))
+		}
+	}
+
+	notBefore, nbErr := p.replyWindow(p.ReplyNotBefore)
+	if nbErr != nil {
+		err = ValidationError(err, IncorrectField("reply_not_before", nbErr.Error()))
+	}
+
+	notAfter, naErr := p.replyWindow(p.ReplyNotAfter)
+	if naErr != nil {
+		err = ValidationError(err, IncorrectField("reply_not_after", naErr.Error()))
+	}
+
+	if nbErr == nil && naErr == nil && notBefore.Valid && notAfter.Valid && notAfter.Int64 != 0 && notAfter.Int64 < notBefore.Int64 {
+		err = ValidationError(err, IncorrectField("reply_not_after", "reply not after window must not be before the reply not before window"))
+	}
+
+	return err
+}
+
+// Model returns the pending overrides to store on the counterparty; Validate must be
+// called first.
+func (p *CounterpartyPending) Model() (model *models.PendingOverrides, err error) {
+	model = &models.PendingOverrides{
+		Language: sql.NullString{String: p.Language, Valid: p.Language != ""},
+		Message:  sql.NullString{String: p.Message, Valid: p.Message != ""},
+	}
+
+	if model.ReplyNotBefore, err = p.replyWindow(p.ReplyNotBefore); err != nil {
+		return nil, err
+	}
+
+	if model.ReplyNotAfter, err = p.replyWindow(p.ReplyNotAfter); err != nil {
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// Parses a reply window duration into seconds; an empty window is null.
+func (p *CounterpartyPending) replyWindow(window string) (seconds sql.NullInt64, err error) {
+	window = strings.TrimSpace(window)
+	if window == "" {
+		return seconds, nil
+	}
+
+	var duration time.Duration
+	if duration, err = time.ParseDuration(window); err != nil {
+		return seconds, errors.New("reply window must be a duration such as 30m or 48h")
+	}
+
+	if duration < 0 {
+		return seconds, errors.New("reply window cannot be negative")
+	}
+
+	return sql.NullInt64{Int64: int64(duration / time.Second), Valid: true}, nil
+}
+
 func NewContact(model *models.Contact) (*Contact, error) {
 	return &Contact{
 		ID:       model.ID,
@@ -1,6 +1,8 @@
 package api_test
 
 import (
+	"database/sql"
+	"strings"
 	"testing"
 
 	. "github.com/trisacrypto/envoy/pkg/web/api/v1"
@@ -292,3 +294,70 @@ func TestCounterpartyQueryValidate(t *testing.T) {
 		}
 	})
 }
+
+func TestCounterpartyPendingValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		pending := &CounterpartyPending{
+			Language:       " fr-ca ",
+			Message:        " {{ .Organization }} examine la demande de {{ .Counterparty }} ",
+			ReplyNotBefore: "0s",
+			ReplyNotAfter:  "48h",
+		}
+
+		require.NoError(t, pending.Validate(), "expected pending overrides to be valid")
+		require.Equal(t, "fr-CA", pending.Language, "expected language to be normalized")
+		require.Equal(t, "{{ .Organization }} examine la demande de {{ .Counterparty }}", pending.Message, "expected message to be trimmed")
+
+		model, err := pending.Model()
+		require.NoError(t, err, "could not convert pending overrides to model")
+		require.Equal(t, sql.NullString{Valid: true, String: "fr-CA"}, model.Language)
+		require.Equal(t, sql.NullInt64{Valid: true, Int64: 0}, model.ReplyNotBefore)
+		require.Equal(t, sql.NullInt64{Valid: true, Int64: 172800}, model.ReplyNotAfter)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		pending := &CounterpartyPending{}
+		require.NoError(t, pending.Validate(), "expected empty pending overrides to be valid")
+
+		model, err := pending.Model()
+		require.NoError(t, err, "could not convert pending overrides to model")
+		require.True(t, model.IsZero(), "expected empty overrides to clear the counterparty overrides")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := []struct {
+			pending *CounterpartyPending
+			err     string
+		}{
+			{
+				&CounterpartyPending{Language: "not a language"},
+				"invalid field language: language must be a language tag such as en or fr-CA",
+			},
+			{
+				&CounterpartyPending{Message: strings.Repeat("a", 1025)},
+				"invalid field message: message cannot be longer than 1024 characters",
+			},
+			{
+				&CounterpartyPending{ReplyNotBefore: "tomorrow"},
+				"invalid field reply_not_before: reply window must be a duration such as 30m or 48h",
+			},
+			{
+				&CounterpartyPending{ReplyNotAfter: "-1h"},
+				"invalid field reply_not_after: reply window cannot be negative",
+			},
+			{
+				&CounterpartyPending{ReplyNotBefore: "1h", ReplyNotAfter: "30m"},
+				"invalid field reply_not_after: reply not after window must not be before the reply not before window",
+			},
+		}
+
+		for i, tc := range testCases {
+			require.EqualError(t, tc.pending.Validate(), tc.err, "test case %d failed", i)
+		}
+	})
+
+	t.Run("BadTemplate", func(t *testing.T) {
+		pending := &CounterpartyPending{Message: "{{ .Unknown }}"}
+		require.ErrorContains(t, pending.Validate(), "could not render pending message template")
+	})
+}
@@ -95,6 +95,21 @@ func ErrorStatus(err error) int {
 	}
 }
 
+//===========================================================================
+// Approval Required
+//===========================================================================
+
+// ApprovalRequired is returned by the client when the server holds a transfer workflow
+// action for four-eyes approval; the action will be executed when the approval is
+// approved by a different user.
+type ApprovalRequired struct {
+	Approval *Approval
+}
+
+func (e *ApprovalRequired) Error() string {
+	return fmt.Sprintf("%s action requires approval (approval id %s)", e.Approval.Action, e.Approval.ID)
+}
+
 //===========================================================================
 // Detail Error
 //===========================================================================
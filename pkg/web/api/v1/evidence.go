@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/pem"
+	"time"
+
+	"github.com/google/uuid"
+	"go.rtnl.ai/ulid"
+
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+// Names of the files in a transaction evidence bundle; secure envelopes are stored
+// in the envelopes directory as both raw protocol buffers and decrypted JSON.
+const (
+	EvidenceManifestFile     = "manifest.json"
+	EvidenceSignatureFile    = "manifest.sig"
+	EvidenceSigningKeyFile   = "signing_key.pem"
+	EvidenceReadmeFile       = "README.txt"
+	EvidenceTransactionFile  = "transaction.json"
+	EvidenceCounterpartyFile = "counterparty.json"
+	EvidenceAuditLogsFile    = "audit_logs.json"
+	EvidenceVerificationFile = "verification.json"
+	EvidenceEnvelopesDir     = "envelopes/"
+)
+
+// EvidenceManifest lists every file in a transaction evidence bundle with its size
+// and SHA-256 hash. The manifest is signed with the signing key of the node; the
+// signature is stored in manifest.sig and the public key that verifies it in
+// signing_key.pem so that the bundle can be verified without access to the node.
+type EvidenceManifest struct {
+	TransactionID      uuid.UUID       `json:"transaction_id"`
+	Organization       string          `json:"organization"`
+	GeneratedBy        string          `json:"generated_by,omitempty"`
+	Generated          time.Time       `json:"generated"`
+	SignatureAlgorithm string          `json:"signature_algorithm"`
+	KeyID              string          `json:"key_id"`
+	Files              []*EvidenceFile `json:"files"`
+}
+
+type EvidenceFile struct {
+	Name   string `json:"name"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// EvidenceCounterparty is the counterparty of the transaction along with the
+// certificate info of the sealing key the node holds for the counterparty, if any.
+type EvidenceCounterparty struct {
+	Counterparty     *Counterparty    `json:"counterparty,omitempty"`
+	Certificate      *CertificateInfo `json:"certificate,omitempty"`
+	CertificateError string           `json:"certificate_error,omitempty"`
+}
+
+// CertificateInfo describes the x.509 certificate of a counterparty sealing key.
+type CertificateInfo struct {
+	KeyID              string `json:"key_id"`
+	Version            int64  `json:"version"`
+	SignatureAlgorithm string `json:"signature_algorithm"`
+	PublicKeyAlgorithm string `json:"public_key_algorithm"`
+	NotBefore          string `json:"not_before"`
+	NotAfter           string `json:"not_after"`
+	Revoked            bool   `json:"revoked"`
+	PublicKey          string `json:"public_key"`
+}
+
+// EvidenceAuditLogs contains the compliance audit logs of the transaction and its
+// secure envelopes. The signed data of each log is included with its signature, and
+// the public keys that verify the signatures are included by key ID.
+type EvidenceAuditLogs struct {
+	Logs []*EvidenceAuditLog `json:"logs"`
+	Keys map[string]string   `json:"keys"`
+}
+
+type EvidenceAuditLog struct {
+	Log       *ComplianceAuditLog `json:"log"`
+	Data      []byte              `json:"data"`
+	Signature []byte              `json:"signature"`
+	KeyID     string              `json:"key_id"`
+	Algorithm string              `json:"algorithm"`
+}
+
+// EvidenceVerification records the results of verifying the secure envelopes and
+// the audit log signatures when the evidence bundle was generated.
+type EvidenceVerification struct {
+	Envelopes []*EnvelopeVerification `json:"envelopes"`
+	AuditLogs []*AuditLogVerification `json:"audit_logs"`
+}
+
+type EnvelopeVerification struct {
+	ID          ulid.ULID `json:"id"`
+	EnvelopeID  uuid.UUID `json:"envelope_id"`
+	Direction   string    `json:"direction"`
+	IsError     bool      `json:"is_error"`
+	Decrypted   bool      `json:"decrypted"`
+	ValidHMAC   *bool     `json:"valid_hmac"`
+	StoredHMAC  *bool     `json:"stored_valid_hmac"`
+	PublicKeyID string    `json:"public_key_id,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type AuditLogVerification struct {
+	ID       ulid.ULID `json:"id"`
+	KeyID    string    `json:"key_id"`
+	Verified bool      `json:"verified"`
+	Error    string    `json:"error,omitempty"`
+}
+
+func NewCertificateInfo(keyID string, key *trisa.SigningKey) (out *CertificateInfo) {
+	out = &CertificateInfo{
+		KeyID:              keyID,
+		Version:            key.Version,
+		SignatureAlgorithm: key.SignatureAlgorithm,
+		PublicKeyAlgorithm: key.PublicKeyAlgorithm,
+		NotBefore:          key.NotBefore,
+		NotAfter:           key.NotAfter,
+		Revoked:            key.Revoked,
+	}
+
+	if len(key.Data) > 0 {
+		out.PublicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: key.Data}))
+	}
+	return out
+}
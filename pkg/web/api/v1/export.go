@@ -0,0 +1,165 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Transaction Exports
+//===========================================================================
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+	ExportFormatXLSX  = "xlsx"
+)
+
+// ExportColumn is a column that can be selected for a transaction export. The key is
+// used to select the column and as the field name in JSON Lines exports; the header
+// is used in CSV and XLSX exports.
+type ExportColumn struct {
+	Key    string `json:"key"`
+	Header string `json:"header"`
+}
+
+// ExportColumns are all of the columns that can be selected in a transaction export
+// in the order that they are exported. Columns that describe the originator and
+// beneficiary IVMS101 details, countries, and the transaction hash are read from the
+// latest decrypted payload of the transaction.
+var ExportColumns = []ExportColumn{
+	{"id", "ID"},
+	{"status", "Status"},
+	{"source", "Source"},
+	{"counterparty", "Counterparty"},
+	{"counterparty_id", "Counterparty ID"},
+	{"originator", "Originator"},
+	{"originator_address", "Originator Address"},
+	{"originator_country", "Originator Country"},
+	{"originator_vasp", "Originator VASP"},
+	{"originator_ivms101", "Originator IVMS101"},
+	{"beneficiary", "Beneficiary"},
+	{"beneficiary_address", "Beneficiary Address"},
+	{"beneficiary_country", "Beneficiary Country"},
+	{"beneficiary_vasp", "Beneficiary VASP"},
+	{"beneficiary_ivms101", "Beneficiary IVMS101"},
+	{"virtual_asset", "Virtual Asset"},
+	{"network", "Network"},
+	{"amount", "Amount"},
+	{"tx_hash", "Transaction Hash"},
+	{"tags", "Tags"},
+	{"assignee", "Assignee"},
+	{"last_update", "Last Update"},
+	{"created", "Created"},
+	{"modified", "Modified"},
+	{"envelopes", "Number of Envelopes"},
+	{"hmac", "HMAC Signature"},
+}
+
+// DefaultExportColumns are exported if no columns are selected.
+var DefaultExportColumns = []string{
+	"id", "status", "counterparty", "originator", "originator_address",
+	"beneficiary", "beneficiary_address", "virtual_asset", "amount",
+	"last_update", "created", "envelopes", "hmac",
+}
+
+type TransactionExportQuery struct {
+	Format       string     `json:"format,omitempty" url:"format,omitempty" form:"format"`
+	Columns      []string   `json:"columns,omitempty" url:"columns,omitempty,comma" form:"columns" collection_format:"csv"`
+	Status       []string   `json:"status,omitempty" url:"status,omitempty" form:"status"`
+	VirtualAsset []string   `json:"asset,omitempty" url:"asset,omitempty" form:"asset"`
+	Counterparty string     `json:"counterparty,omitempty" url:"counterparty,omitempty" form:"counterparty"`
+	After        *time.Time `json:"after,omitempty" url:"after,omitempty" form:"after"`
+	Before       *time.Time `json:"before,omitempty" url:"before,omitempty" form:"before"`
+	Archives     bool       `json:"archives,omitempty" url:"archives,omitempty" form:"archives"`
+}
+
+func (q *TransactionExportQuery) Validate() (err error) {
+	q.Format = strings.ToLower(strings.TrimSpace(q.Format))
+	switch q.Format {
+	case "":
+		q.Format = ExportFormatCSV
+	case ExportFormatCSV, ExportFormatJSONL, ExportFormatXLSX:
+	default:
+		err = ValidationError(err, IncorrectField("format", "format must be one of csv, jsonl, or xlsx"))
+	}
+
+	if len(q.Columns) == 0 {
+		q.Columns = slices.Clone(DefaultExportColumns)
+	} else {
+		for i, column := range q.Columns {
+			q.Columns[i] = strings.ToLower(strings.TrimSpace(column))
+			if !slices.ContainsFunc(ExportColumns, func(c ExportColumn) bool { return c.Key == q.Columns[i] }) {
+				err = ValidationError(err, IncorrectField("columns", fmt.Sprintf("unknown export column: '%s'", column)))
+			}
+		}
+	}
+
+	for i, status := range q.Status {
+		q.Status[i] = strings.ToLower(strings.TrimSpace(status))
+		if !enum.ValidStatus(q.Status[i]) {
+			err = ValidationError(err, IncorrectField("status", "invalid status enum"))
+			break
+		}
+	}
+
+	for i, asset := range q.VirtualAsset {
+		q.VirtualAsset[i] = strings.ToUpper(strings.TrimSpace(asset))
+	}
+
+	if q.Counterparty = strings.TrimSpace(q.Counterparty); q.Counterparty != "" {
+		if _, perr := ulid.Parse(q.Counterparty); perr != nil {
+			err = ValidationError(err, IncorrectField("counterparty", "counterparty must be the ULID of a counterparty"))
+		}
+	}
+
+	if (q.After != nil && !q.After.IsZero()) && (q.Before != nil && !q.Before.IsZero()) {
+		if !q.Before.After(*q.After) {
+			err = ValidationError(err, IncorrectField("before", "before must come after after"))
+		}
+	}
+
+	return err
+}
+
+// Returns the headers of the selected columns; the query must be validated first.
+func (q *TransactionExportQuery) Headers() []string {
+	headers := make([]string, 0, len(q.Columns))
+	for _, key := range q.Columns {
+		for _, column := range ExportColumns {
+			if column.Key == key {
+				headers = append(headers, column.Header)
+				break
+			}
+		}
+	}
+	return headers
+}
+
+func (q *TransactionExportQuery) Filter() (filter *models.TransactionFilter) {
+	filter = &models.TransactionFilter{
+		Status:       q.Status,
+		VirtualAsset: q.VirtualAsset,
+		Archives:     q.Archives,
+	}
+
+	if q.Counterparty != "" {
+		filter.CounterpartyID, _ = ulid.Parse(q.Counterparty)
+	}
+
+	if q.After != nil {
+		filter.After = *q.After
+	}
+
+	if q.Before != nil {
+		filter.Before = *q.Before
+	}
+
+	return filter
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Transaction Status History
+//===========================================================================
+
+// StatusTransition is a change of the status of a transaction in its timeline. The
+// envelope is the secure envelope that was exchanged with the counterparty when the
+// status changed, if any; otherwise the status was changed by the actor directly.
+type StatusTransition struct {
+	ID         ulid.ULID  `json:"id"`
+	From       string     `json:"from"`
+	To         string     `json:"to"`
+	Actor      string     `json:"actor"`
+	ActorType  string     `json:"actor_type"`
+	EnvelopeID *ulid.ULID `json:"envelope_id,omitempty"`
+	Created    time.Time  `json:"created"`
+}
+
+type TransactionHistory struct {
+	TransactionID uuid.UUID           `json:"transaction_id"`
+	Transitions   []*StatusTransition `json:"transitions"`
+}
+
+func NewStatusTransition(model *models.StatusTransition) (out *StatusTransition, err error) {
+	out = &StatusTransition{
+		ID:        model.ID,
+		From:      model.From.String(),
+		To:        model.To.String(),
+		Actor:     model.Actor.String,
+		ActorType: model.ActorType.String(),
+		Created:   model.Created,
+	}
+
+	// If the actor is not a user or api key (e.g. the system), use the type as the name
+	if out.Actor == "" {
+		out.Actor = out.ActorType
+	}
+
+	if model.EnvelopeID.Valid {
+		out.EnvelopeID = &model.EnvelopeID.ULID
+	}
+
+	return out, nil
+}
+
+func NewTransactionHistory(transactionID uuid.UUID, history []*models.StatusTransition) (out *TransactionHistory, err error) {
+	out = &TransactionHistory{
+		TransactionID: transactionID,
+		Transitions:   make([]*StatusTransition, 0, len(history)),
+	}
+
+	for _, model := range history {
+		var transition *StatusTransition
+		if transition, err = NewStatusTransition(model); err != nil {
+			return nil, err
+		}
+		out.Transitions = append(out.Transitions, transition)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// User Invites
+//===========================================================================
+
+type UserInvite struct {
+	ID      ulid.ULID  `json:"id,omitempty"`
+	Email   string     `json:"email"`
+	Name    string     `json:"name,omitempty"`
+	Role    string     `json:"role"`
+	Expires *time.Time `json:"expires,omitempty"`
+	Expired bool       `json:"expired,omitempty"`
+	SentOn  *time.Time `json:"sent_on,omitempty"`
+	Created time.Time  `json:"created,omitempty"`
+}
+
+type UserInviteList struct {
+	Invites []*UserInvite `json:"invites"`
+}
+
+type AcceptInviteRequest struct {
+	URLVerification
+	Name     string `json:"name,omitempty"`
+	Password string `json:"password"`
+	Confirm  string `json:"confirm"`
+}
+
+func NewUserInvite(model *models.UserInvite) (out *UserInvite, err error) {
+	out = &UserInvite{
+		ID:      model.ID,
+		Email:   model.Email,
+		Name:    model.Name.String,
+		Expires: &model.Expiration,
+		Expired: model.IsExpired(),
+		Created: model.Created,
+	}
+
+	if model.SentOn.Valid {
+		out.SentOn = &model.SentOn.Time
+	}
+
+	if role, err := model.Role(); err == nil {
+		out.Role = role.Title
+	}
+
+	return out, nil
+}
+
+func NewUserInviteList(invites []*models.UserInvite) (out *UserInviteList, err error) {
+	out = &UserInviteList{
+		Invites: make([]*UserInvite, 0, len(invites)),
+	}
+
+	for _, model := range invites {
+		var invite *UserInvite
+		if invite, err = NewUserInvite(model); err != nil {
+			return nil, err
+		}
+		out.Invites = append(out.Invites, invite)
+	}
+
+	return out, nil
+}
+
+func (u *UserInvite) Validate() (err error) {
+	u.Email = strings.TrimSpace(u.Email)
+	if u.Email == "" {
+		err = ValidationError(err, MissingField("email"))
+	}
+
+	u.Role = strings.TrimSpace(u.Role)
+	if u.Role == "" {
+		err = ValidationError(err, MissingField("role"))
+	}
+
+	if u.Expires != nil {
+		err = ValidationError(err, ReadOnlyField("expires"))
+	}
+
+	if u.SentOn != nil {
+		err = ValidationError(err, ReadOnlyField("sent_on"))
+	}
+
+	// NOTE: role cannot be verified without a database query
+	return err
+}
+
+func (u *UserInvite) Model() (model *models.UserInvite, err error) {
+	// NOTE: the role and expiration must be set by the caller who has database access.
+	model = &models.UserInvite{
+		Model: models.Model{
+			ID: u.ID,
+		},
+		Email: u.Email,
+	}
+
+	if u.Name != "" {
+		model.Name.Valid = true
+		model.Name.String = strings.TrimSpace(u.Name)
+	}
+
+	return model, nil
+}
+
+func (r *AcceptInviteRequest) Validate() (err error) {
+	if err = r.URLVerification.Validate(); err != nil {
+		return err
+	}
+
+	r.Name = strings.TrimSpace(r.Name)
+
+	// Confirm the two entered passwords are valid and match
+	password := ProfilePassword{
+		Current:  "ignored",
+		Password: r.Password,
+		Confirm:  r.Confirm,
+	}
+
+	if err = password.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
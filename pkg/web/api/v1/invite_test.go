@@ -0,0 +1,33 @@
+package api_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+func TestUserInviteValidate(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		invite *api.UserInvite
+		err    string
+	}{
+		{&api.UserInvite{}, "2 validation errors occurred:\n  missing email: this field is required\n  missing role: this field is required"},
+		{&api.UserInvite{Email: "  ", Role: "Observer"}, "missing email: this field is required"},
+		{&api.UserInvite{Email: "invitee@example.com", Role: "Observer", Expires: &now}, "read-only field expires: this field cannot be written by the user"},
+		{&api.UserInvite{Email: "invitee@example.com", Role: "Observer", SentOn: &now}, "read-only field sent_on: this field cannot be written by the user"},
+		{&api.UserInvite{Email: "invitee@example.com", Role: "Observer"}, ""},
+	}
+
+	for i, tc := range testCases {
+		err := tc.invite.Validate()
+		if tc.err == "" {
+			require.NoError(t, err, "test case %d failed", i)
+		} else {
+			require.EqualError(t, err, tc.err, "test case %d failed", i)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// LegalHold
+//===========================================================================
+
+// LegalHold prevents the transaction, account, or counterparty it is applied to from
+// being deleted, archived, or purged by data retention policies until it is released.
+type LegalHold struct {
+	ID            ulid.ULID  `json:"id,omitempty"`
+	ResourceType  string     `json:"resource_type"`
+	ResourceID    string     `json:"resource_id"`
+	Reason        string     `json:"reason"`
+	CaseReference string     `json:"case_reference,omitempty"`
+	AppliedBy     string     `json:"applied_by,omitempty"`
+	Active        bool       `json:"active"`
+	ReleasedBy    string     `json:"released_by,omitempty"`
+	ReleasedOn    *time.Time `json:"released_on,omitempty"`
+	Created       time.Time  `json:"created,omitempty"`
+	Modified      time.Time  `json:"modified,omitempty"`
+}
+
+type LegalHoldList struct {
+	Page       *LegalHoldQuery `json:"page"`
+	LegalHolds []*LegalHold    `json:"legal_holds"`
+}
+
+// LegalHoldQuery filters the legal holds returned by the list endpoint.
+type LegalHoldQuery struct {
+	PageQuery
+	ResourceType string `json:"resource_type,omitempty" url:"resource_type,omitempty" form:"resource_type"`
+	ResourceID   string `json:"resource_id,omitempty" url:"resource_id,omitempty" form:"resource_id"`
+	Released     bool   `json:"released,omitempty" url:"released,omitempty" form:"released"`
+}
+
+func NewLegalHold(model *models.LegalHold) (out *LegalHold, err error) {
+	out = &LegalHold{
+		ID:            model.ID,
+		ResourceType:  model.ResourceType.String(),
+		ResourceID:    model.ResourceID,
+		Reason:        model.Reason,
+		CaseReference: model.CaseReference.String,
+		AppliedBy:     model.AppliedBy,
+		Active:        model.IsActive(),
+		ReleasedBy:    model.ReleasedBy.String,
+		Created:       model.Created,
+		Modified:      model.Modified,
+	}
+
+	if model.ReleasedOn.Valid {
+		out.ReleasedOn = &model.ReleasedOn.Time
+	}
+
+	return out, nil
+}
+
+func NewLegalHoldList(page *models.LegalHoldPage) (out *LegalHoldList, err error) {
+	out = &LegalHoldList{
+		Page: &LegalHoldQuery{
+			PageQuery: PageQuery{
+				PageSize: int(page.Page.PageSize),
+			},
+			ResourceType: page.Page.ResourceType,
+			ResourceID:   page.Page.ResourceID,
+			Released:     page.Page.Released,
+		},
+		LegalHolds: make([]*LegalHold, 0, len(page.LegalHolds)),
+	}
+
+	if !page.Page.NextPageID.IsZero() {
+		out.Page.NextPageToken = page.Page.NextPageID.String()
+	}
+
+	for _, model := range page.LegalHolds {
+		var hold *LegalHold
+		if hold, err = NewLegalHold(model); err != nil {
+			return nil, err
+		}
+		out.LegalHolds = append(out.LegalHolds, hold)
+	}
+
+	return out, nil
+}
+
+// Validate the legal hold; the resource of a hold can only be set when it is created
+// and all other fields except the reason and case reference are read-only.
+func (h *LegalHold) Validate(create bool) (err error) {
+	if h.Reason == "" {
+		err = ValidationError(err, MissingField("reason"))
+	}
+
+	if h.AppliedBy != "" {
+		err = ValidationError(err, ReadOnlyField("applied_by"))
+	}
+
+	if h.ReleasedBy != "" {
+		err = ValidationError(err, ReadOnlyField("released_by"))
+	}
+
+	if h.ReleasedOn != nil {
+		err = ValidationError(err, ReadOnlyField("released_on"))
+	}
+
+	if !create {
+		return err
+	}
+
+	if !h.ID.IsZero() {
+		err = ValidationError(err, ReadOnlyField("id"))
+	}
+
+	var resource enum.Resource
+	if h.ResourceType == "" {
+		err = ValidationError(err, MissingField("resource_type"))
+	} else if resource, _ = enum.ParseResource(h.ResourceType); !models.ValidLegalHoldResource(resource) {
+		err = ValidationError(err, IncorrectField("resource_type", "legal holds can only be applied to transactions, accounts, or counterparties"))
+	} else {
+		h.ResourceType = resource.String()
+	}
+
+	if h.ResourceID == "" {
+		err = ValidationError(err, MissingField("resource_id"))
+	} else {
+		switch resource {
+		case enum.ResourceTransaction:
+			if _, perr := uuid.Parse(h.ResourceID); perr != nil {
+				err = ValidationError(err, IncorrectField("resource_id", "transaction ids must be a valid uuid"))
+			}
+		case enum.ResourceAccount, enum.ResourceCounterparty:
+			if _, perr := ulid.Parse(h.ResourceID); perr != nil {
+				err = ValidationError(err, IncorrectField("resource_id", fmt.Sprintf("%s ids must be a valid ulid", resource)))
+			}
+		}
+	}
+
+	return err
+}
+
+func (h *LegalHold) Model() (model *models.LegalHold, err error) {
+	model = &models.LegalHold{
+		Model: models.Model{
+			ID:       h.ID,
+			Created:  h.Created,
+			Modified: h.Modified,
+		},
+		ResourceID:    h.ResourceID,
+		Reason:        h.Reason,
+		CaseReference: sql.NullString{String: h.CaseReference, Valid: h.CaseReference != ""},
+		AppliedBy:     h.AppliedBy,
+	}
+
+	if h.ResourceType != "" {
+		if model.ResourceType, err = enum.ParseResource(h.ResourceType); err != nil {
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
+
+func (q *LegalHoldQuery) Validate() (err error) {
+	if q.ResourceType != "" {
+		if resource, perr := enum.ParseResource(q.ResourceType); perr != nil || !models.ValidLegalHoldResource(resource) {
+			err = ValidationError(err, IncorrectField("resource_type", "legal holds can only be applied to transactions, accounts, or counterparties"))
+		} else {
+			q.ResourceType = resource.String()
+		}
+	}
+
+	// The next page token must be the ID of the last hold in the previous page
+	if q.NextPageToken != "" {
+		if _, perr := ulid.Parse(q.NextPageToken); perr != nil {
+			err = ValidationError(err, IncorrectField("next_page_token", "invalid pagination token"))
+		}
+	}
+
+	return err
+}
+
+func (q *LegalHoldQuery) Query() (query *models.LegalHoldPageInfo) {
+	query = &models.LegalHoldPageInfo{
+		PageInfo: models.PageInfo{
+			PageSize: uint32(q.PageSize),
+		},
+		ResourceType: q.ResourceType,
+		ResourceID:   q.ResourceID,
+		Released:     q.Released,
+	}
+
+	if q.NextPageToken != "" {
+		query.NextPageID, _ = ulid.Parse(q.NextPageToken)
+	}
+
+	return query
+}
@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func TestLegalHoldValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		testCases := []*api.LegalHold{
+			{ResourceType: "transaction", ResourceID: "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3", Reason: "subpoena"},
+			{ResourceType: "Account", ResourceID: "01HV6RV08YNR2GH8MEEFCV4NKN", Reason: "subpoena", CaseReference: "CASE-0042"},
+			{ResourceType: "counterparty", ResourceID: "01HWR5VWW8V7ZFFVJVBEC7AV8A", Reason: "subpoena"},
+		}
+
+		for i, tc := range testCases {
+			require.NoError(t, tc.Validate(true), "test case %d failed", i)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := []struct {
+			hold *api.LegalHold
+			err  string
+		}{
+			{&api.LegalHold{ResourceType: "transaction", ResourceID: "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3"}, "missing reason: this field is required"},
+			{&api.LegalHold{ResourceID: "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3", Reason: "subpoena"}, "missing resource_type: this field is required"},
+			{&api.LegalHold{ResourceType: "user", ResourceID: "01HV6RV08YNR2GH8MEEFCV4NKN", Reason: "subpoena"}, "invalid field resource_type: legal holds can only be applied to transactions, accounts, or counterparties"},
+			{&api.LegalHold{ResourceType: "transaction", Reason: "subpoena"}, "missing resource_id: this field is required"},
+			{&api.LegalHold{ResourceType: "transaction", ResourceID: "01HV6RV08YNR2GH8MEEFCV4NKN", Reason: "subpoena"}, "invalid field resource_id: transaction ids must be a valid uuid"},
+			{&api.LegalHold{ResourceType: "account", ResourceID: "c20a7cdf-5c23-4b44-b7cd-a29cd00761a3", Reason: "subpoena"}, "invalid field resource_id: account ids must be a valid ulid"},
+			{&api.LegalHold{ResourceType: "account", ResourceID: "01HV6RV08YNR2GH8MEEFCV4NKN", Reason: "subpoena", AppliedBy: "Mallory"}, "read-only field applied_by: this field cannot be written by the user"},
+		}
+
+		for i, tc := range testCases {
+			require.EqualError(t, tc.hold.Validate(true), tc.err, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		// Resource fields are not validated on update since they are read-only
+		hold := &api.LegalHold{Reason: "updated reason"}
+		require.NoError(t, hold.Validate(false))
+	})
+}
+
+func TestLegalHoldQuery(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		cursor := ulid.MakeSecure()
+		query := &api.LegalHoldQuery{
+			PageQuery:    api.PageQuery{PageSize: 10, NextPageToken: cursor.String()},
+			ResourceType: "Account",
+		}
+
+		require.NoError(t, query.Validate())
+		require.Equal(t, "account", query.ResourceType)
+
+		page := query.Query()
+		require.Equal(t, uint32(10), page.PageSize)
+		require.Equal(t, cursor, page.NextPageID)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		query := &api.LegalHoldQuery{PageQuery: api.PageQuery{NextPageToken: "foo"}}
+		require.EqualError(t, query.Validate(), "invalid field next_page_token: invalid pagination token")
+	})
+}
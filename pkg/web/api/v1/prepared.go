@@ -18,12 +18,18 @@ type Prepare struct {
 	Originator  *Person   `json:"originator"`
 	Beneficiary *Person   `json:"beneficiary"`
 	Transfer    *Transfer `json:"transfer"`
+
+	// Set by the server from the threshold rules; if travel rule data is required then
+	// the IVMS101 fields of the originator and beneficiary are validated as mandatory.
+	TravelRule *TravelRule `json:"-"`
 }
 
 type Prepared struct {
 	Routing     *Routing                 `json:"routing"`
 	Identity    *ivms101.IdentityPayload `json:"identity"`
 	Transaction *generic.Transaction     `json:"transaction"`
+	TravelRule  *TravelRule              `json:"travel_rule,omitempty"`
+	SendAt      *time.Time               `json:"send_at,omitempty"`
 }
 
 type Routing struct {
@@ -180,6 +186,33 @@ func (p *Prepare) Validate() (err error) {
 		err = ValidationError(err, MissingField("transfer"))
 	}
 
+	if p.TravelRule != nil && p.TravelRule.Required {
+		err = p.validateTravelRule(err)
+	}
+
+	return err
+}
+
+// If travel rule data is required, the name of the originator and the beneficiary is
+// mandatory along with the address, customer identification, national identification,
+// or date of birth of the originator.
+func (p *Prepare) validateTravelRule(err error) error {
+	if p.Originator != nil {
+		if strings.TrimSpace(p.Originator.Surname) == "" {
+			err = ValidationError(err, MissingField("originator.surname"))
+		}
+
+		if !p.Originator.HasAddress() && strings.TrimSpace(p.Originator.CustomerID) == "" && strings.TrimSpace(p.Originator.Identification.Number) == "" && strings.TrimSpace(p.Originator.Identification.DateOfBirth) == "" {
+			err = ValidationError(err, OneOfMissing("originator.addresses", "originator.customer_id", "originator.identification.number", "originator.identification.dob"))
+		}
+	}
+
+	if p.Beneficiary != nil {
+		if strings.TrimSpace(p.Beneficiary.Surname) == "" {
+			err = ValidationError(err, MissingField("beneficiary.surname"))
+		}
+	}
+
 	return err
 }
 
@@ -200,6 +233,10 @@ func (p *Prepared) Validate() (err error) {
 		err = ValidationError(err, MissingField("transaction"))
 	}
 
+	if p.SendAt != nil && p.Routing != nil && !strings.EqualFold(p.Routing.Protocol, enum.ProtocolTRISA.String()) {
+		err = ValidationError(err, IncorrectField("send_at", "scheduled sends are only supported for the trisa protocol"))
+	}
+
 	return err
 }
 
@@ -221,6 +258,18 @@ func (p *Prepare) Transaction() *generic.Transaction {
 	}
 }
 
+// HasAddress returns true if any of the addresses of the person has an address line.
+func (p *Person) HasAddress() bool {
+	for _, addr := range p.Addresses {
+		for _, line := range addr.AddressLines {
+			if line = strings.TrimSpace(line); line != "" && line != "," {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (p *Person) FullName() string {
 	return strings.TrimSpace(p.Forename + " " + p.Surname)
 }
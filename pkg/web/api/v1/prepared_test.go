@@ -138,6 +138,48 @@ func TestPrepareValidate(t *testing.T) {
 
 }
 
+func TestPrepareValidateTravelRule(t *testing.T) {
+	routing := `"routing": {"protocol": "trisa", "travel_address": "ta2CdjAHciVXahu8sPNTbtGkD6BnaVq4WKcHG6ks2RB4nN4YEvtGMviaNXxsgFWEPV58HtC"}, "transfer": {}`
+	testCases := []struct {
+		input string
+		err   error
+	}{
+		{
+			`{` + routing + `, "originator": {"crypto_address": "n1fKM7ZdxiwnnYWg3r4c1RKw7CqSVS5R8k"}, "beneficiary": {"crypto_address": "mxJmGucUxscdaWhhXNKvRuRoCoTpVzZ5uj"}}`,
+			ValidationError(nil, MissingField("originator.surname"), OneOfMissing("originator.addresses", "originator.customer_id", "originator.identification.number", "originator.identification.dob"), MissingField("beneficiary.surname")),
+		},
+		{
+			`{` + routing + `, "originator": {"crypto_address": "n1fKM7ZdxiwnnYWg3r4c1RKw7CqSVS5R8k", "surname": "Doe", "addresses": [{"address_lines": [" ", ","]}]}, "beneficiary": {"crypto_address": "mxJmGucUxscdaWhhXNKvRuRoCoTpVzZ5uj", "surname": "Roe"}}`,
+			ValidationError(nil, OneOfMissing("originator.addresses", "originator.customer_id", "originator.identification.number", "originator.identification.dob")),
+		},
+		{
+			`{` + routing + `, "originator": {"crypto_address": "n1fKM7ZdxiwnnYWg3r4c1RKw7CqSVS5R8k", "surname": "Doe", "addresses": [{"address_lines": ["1 Main St"]}]}, "beneficiary": {"crypto_address": "mxJmGucUxscdaWhhXNKvRuRoCoTpVzZ5uj", "surname": "Roe"}}`,
+			nil,
+		},
+		{
+			`{` + routing + `, "originator": {"crypto_address": "n1fKM7ZdxiwnnYWg3r4c1RKw7CqSVS5R8k", "surname": "Doe", "identification": {"dob": "1980-01-01"}}, "beneficiary": {"crypto_address": "mxJmGucUxscdaWhhXNKvRuRoCoTpVzZ5uj", "surname": "Roe"}}`,
+			nil,
+		},
+	}
+
+	for i, tc := range testCases {
+		prepare := &Prepare{}
+		require.NoError(t, json.Unmarshal([]byte(tc.input), prepare), "could not unmarshal test input for test %d", i)
+		require.NoError(t, prepare.Validate(), "expected no error if travel rule data is not required for test case %d", i)
+
+		prepare.TravelRule = &TravelRule{Required: false}
+		require.NoError(t, prepare.Validate(), "expected no error if travel rule data is not required for test case %d", i)
+
+		prepare.TravelRule = &TravelRule{Required: true}
+		err := prepare.Validate()
+		if tc.err == nil {
+			require.NoError(t, err, "was expecting no error for test case %d", i)
+		} else {
+			require.EqualError(t, err, tc.err.Error(), "did not match expected error for test case %d", i)
+		}
+	}
+}
+
 func TestParseNationalIdentifierType(t *testing.T) {
 	testCases := []struct {
 		input    string
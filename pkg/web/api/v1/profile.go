@@ -1,6 +1,10 @@
 package api
 
-import "github.com/trisacrypto/envoy/pkg/web/auth/passwords"
+import (
+	"strings"
+
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
+)
 
 type ProfilePassword struct {
 	Current  string `json:"current,omitempty"`
@@ -8,6 +12,12 @@ type ProfilePassword struct {
 	Confirm  string `json:"confirm,omitempty"`
 }
 
+// ProfileMFACode is used to confirm MFA enrollment and to authorize changes to the
+// MFA settings of the logged in user. The code may be a TOTP code or a recovery code.
+type ProfileMFACode struct {
+	Code string `json:"code,omitempty"`
+}
+
 // Validate ensures that the password change request is valid and meets the minimum
 // password requirements. Note that this method does not check the current password
 // against the user's actual password and must be performed by any handler that has
@@ -19,13 +29,11 @@ func (p *ProfilePassword) Validate() (err error) {
 
 	if p.Password == "" {
 		err = ValidationError(err, MissingField("password"))
-	} else if len(p.Password) < 8 {
-		err = ValidationError(err, IncorrectField("password", "must be at least 8 characters long"))
-	} else {
-		// Validate password strength
-		if _, verr := passwords.Strength(p.Password); verr != nil {
-			err = ValidationError(err, IncorrectField("password", verr.Error()))
-		}
+	} else if _, verr := passwords.Strength(p.Password); verr != nil {
+		// Validate password strength against the password policy
+		err = ValidationError(err, IncorrectField("password", verr.Error()))
+	} else if p.Password == p.Current {
+		err = ValidationError(err, IncorrectField("password", passwords.ErrPasswordReused.Error()))
 	}
 
 	if p.Confirm == "" {
@@ -36,3 +44,11 @@ func (p *ProfilePassword) Validate() (err error) {
 
 	return err
 }
+
+func (p *ProfileMFACode) Validate() (err error) {
+	p.Code = strings.TrimSpace(p.Code)
+	if p.Code == "" {
+		err = ValidationError(err, MissingField("code"))
+	}
+	return err
+}
@@ -33,3 +33,12 @@ func TestProfilePasswordValidate(t *testing.T) {
 		}
 	})
 }
+
+func TestProfileMFACodeValidate(t *testing.T) {
+	code := &api.ProfileMFACode{Code: " 123456 "}
+	require.NoError(t, code.Validate(), "expected valid code")
+	require.Equal(t, "123456", code.Code, "expected code to be trimmed")
+
+	code = &api.ProfileMFACode{Code: "  "}
+	require.EqualError(t, code.Validate(), "missing code: this field is required")
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+// QueuedTransfer is an outgoing transfer that is waiting in the outbound queue to be
+// sent to the counterparty, either because it was scheduled to be sent later or
+// because the counterparty could not be reached when it was first sent.
+type QueuedTransfer struct {
+	ID             ulid.ULID  `json:"id"`
+	TransactionID  string     `json:"transaction_id"`
+	CounterpartyID ulid.ULID  `json:"counterparty_id"`
+	Counterparty   string     `json:"counterparty"`
+	VirtualAsset   string     `json:"virtual_asset"`
+	Amount         float64    `json:"amount"`
+	Status         string     `json:"status"`
+	Attempts       int64      `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastAttempt    *time.Time `json:"last_attempt,omitempty"`
+	NextAttempt    time.Time  `json:"next_attempt"`
+	Created        time.Time  `json:"created"`
+	Modified       time.Time  `json:"modified"`
+}
+
+type QueuedTransferList struct {
+	Transfers []*QueuedTransfer `json:"transfers"`
+}
+
+func NewQueuedTransfer(model *models.QueuedTransfer) (out *QueuedTransfer, err error) {
+	out = &QueuedTransfer{
+		ID:             model.ID,
+		TransactionID:  model.TransactionID.String(),
+		CounterpartyID: model.CounterpartyID,
+		Counterparty:   model.Counterparty,
+		VirtualAsset:   model.VirtualAsset,
+		Amount:         model.Amount,
+		Status:         model.Status.String(),
+		Attempts:       model.Attempts,
+		LastError:      model.LastError.String,
+		NextAttempt:    model.NextAttempt,
+		Created:        model.Created,
+		Modified:       model.Modified,
+	}
+
+	if model.LastAttempt.Valid {
+		out.LastAttempt = &model.LastAttempt.Time
+	}
+
+	return out, nil
+}
+
+func NewQueuedTransferList(transfers []*models.QueuedTransfer) (out *QueuedTransferList, err error) {
+	out = &QueuedTransferList{
+		Transfers: make([]*QueuedTransfer, 0, len(transfers)),
+	}
+
+	for _, model := range transfers {
+		var transfer *QueuedTransfer
+		if transfer, err = NewQueuedTransfer(model); err != nil {
+			return nil, err
+		}
+		out.Transfers = append(out.Transfers, transfer)
+	}
+
+	return out, nil
+}
+
+// Returns true if the transfer can be canceled or retried by the user.
+func (q *QueuedTransfer) IsPending() bool {
+	return q.Status == "queued" || q.Status == "failed"
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// reauthenticator is an http.RoundTripper that transparently refreshes expired access
+// tokens. If a request is rejected as unauthorized and the client has a refresh token
+// from a previous login or authentication, then the client is reauthenticated using the
+// refresh token and the original request is retried once with the new access token.
+// Concurrent requests that are rejected with the same access token only reauthenticate
+// the client once; the other requests are retried with the refreshed access token.
+type reauthenticator struct {
+	client *APIv1
+	next   http.RoundTripper
+}
+
+func (t *reauthenticator) RoundTrip(req *http.Request) (rep *http.Response, err error) {
+	if rep, err = t.transport().RoundTrip(req); err != nil {
+		return rep, err
+	}
+
+	if rep.StatusCode != http.StatusUnauthorized || !t.canRetry(req) {
+		return rep, nil
+	}
+
+	// Attempt to reauthenticate; if this fails return the original response
+	var token string
+	if token, err = t.reauthenticate(req); err != nil {
+		log.Debug().Err(err).Msg("could not reauthenticate api client with refresh token")
+		return rep, nil
+	}
+
+	// Retry the request once with the new access token
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return rep, nil
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	rep.Body.Close()
+	return t.transport().RoundTrip(retry)
+}
+
+// Reauthenticates the client unless the access token that the request was rejected
+// with has already been replaced by a concurrent request, then returns the current
+// access token of the client.
+func (t *reauthenticator) reauthenticate(req *http.Request) (token string, err error) {
+	t.client.reauth.Lock()
+	defer t.client.reauth.Unlock()
+
+	creds, refresh := t.client.credentials()
+	if creds != nil {
+		if token, err = creds.AccessToken(); err == nil && "Bearer "+token != req.Header.Get("Authorization") {
+			return token, nil
+		}
+	}
+
+	if _, err = t.client.Reauthenticate(req.Context(), &ReauthenticateRequest{RefreshToken: refresh}); err != nil {
+		return "", err
+	}
+
+	creds, _ = t.client.credentials()
+	return creds.AccessToken()
+}
+
+// Requests can only be retried if the client has a refresh token, the request is not
+// itself an authentication request, and the body of the request can be replayed.
+func (t *reauthenticator) canRetry(req *http.Request) bool {
+	if _, refresh := t.client.credentials(); refresh == "" {
+		return false
+	}
+
+	for _, endpoint := range []string{loginEP, authenticateEP, refreshEP} {
+		if strings.HasSuffix(req.URL.Path, endpoint) {
+			return false
+		}
+	}
+
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func (t *reauthenticator) transport() http.RoundTripper {
+	if t.next == nil {
+		return http.DefaultTransport
+	}
+	return t.next
+}
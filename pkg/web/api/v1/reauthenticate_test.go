@@ -0,0 +1,175 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1/credentials"
+)
+
+func TestReauthenticate(t *testing.T) {
+	// The server issues a new access token when the refresh token is valid and only
+	// accepts the valid access token for the test case.
+	var (
+		reauths int
+		valid   string
+	)
+
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, &api.LoginReply{AccessToken: "access1", RefreshToken: "refresh1"})
+	})
+
+	mux.HandleFunc("/v1/reauthenticate", func(w http.ResponseWriter, r *http.Request) {
+		reauths++
+		in := &api.ReauthenticateRequest{}
+		json.NewDecoder(r.Body).Decode(in)
+		if in.RefreshToken != "refresh1" {
+			reply(w, http.StatusForbidden, api.Error("invalid reauthentication credentials"))
+			return
+		}
+
+		reply(w, http.StatusOK, &api.LoginReply{AccessToken: "access2", RefreshToken: "refresh2"})
+	})
+
+	mux.HandleFunc("/v1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid {
+			reply(w, http.StatusUnauthorized, api.Error("this endpoint requires authentication"))
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			// Echo the transaction to ensure the body is replayed on retry
+			in := &api.Transaction{}
+			json.NewDecoder(r.Body).Decode(in)
+			reply(w, http.StatusCreated, in)
+			return
+		}
+
+		reply(w, http.StatusOK, &api.TransactionsList{})
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	t.Run("RetryOnce", func(t *testing.T) {
+		reauths, valid = 0, "access1"
+		client, err := api.New(ts.URL)
+		require.NoError(t, err, "could not create api client")
+
+		_, err = client.Login(ctx, &api.LoginRequest{Email: "jane@example.com", Password: "supersecret"})
+		require.NoError(t, err, "could not login")
+
+		// Neither the original nor the refreshed access token is accepted by the server
+		valid = "revoked"
+		_, err = client.ListTransactions(ctx, page)
+		CheckStatusError(t, err, http.StatusUnauthorized, "this endpoint requires authentication")
+		require.Equal(t, 1, reauths, "expected the request to be retried only once")
+	})
+
+	t.Run("RetryWithBody", func(t *testing.T) {
+		reauths, valid = 0, "access1"
+		client, err := api.New(ts.URL)
+		require.NoError(t, err, "could not create api client")
+
+		_, err = client.Login(ctx, &api.LoginRequest{Email: "jane@example.com", Password: "supersecret"})
+		require.NoError(t, err, "could not login")
+
+		// Expire the access token so that only the refreshed access token is accepted
+		valid = "access2"
+		out, err := client.CreateTransaction(ctx, &api.Transaction{Source: "local", Status: "draft"})
+		require.NoError(t, err, "expected the request to be retried after reauthenticating")
+		require.Equal(t, "local", out.Source, "expected the request body to be replayed")
+		require.Equal(t, 1, reauths, "expected one reauthentication")
+
+		// Subsequent requests should use the new access token without reauthenticating
+		_, err = client.ListTransactions(ctx, page)
+		require.NoError(t, err, "expected the new access token to be used")
+		require.Equal(t, 1, reauths, "expected no further reauthentication")
+	})
+
+	t.Run("NoRefreshToken", func(t *testing.T) {
+		reauths, valid = 0, "access2"
+		client, err := api.New(ts.URL, api.WithCreds(credentials.Token("access1")))
+		require.NoError(t, err, "could not create api client")
+
+		_, err = client.ListTransactions(ctx, page)
+		CheckStatusError(t, err, http.StatusUnauthorized, "this endpoint requires authentication")
+		require.Equal(t, 0, reauths, "expected no reauthentication without a refresh token")
+	})
+}
+
+func TestReauthenticateConcurrent(t *testing.T) {
+	// The server only accepts the refreshed access token and counts reauthentications;
+	// run with -race to detect unsynchronized access to the client credentials.
+	var reauths atomic.Int32
+
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, status int, v any) {
+		w.Header().Add("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, &api.LoginReply{AccessToken: "access1", RefreshToken: "refresh1"})
+	})
+
+	mux.HandleFunc("/v1/reauthenticate", func(w http.ResponseWriter, r *http.Request) {
+		reauths.Add(1)
+		in := &api.ReauthenticateRequest{}
+		json.NewDecoder(r.Body).Decode(in)
+		if in.RefreshToken != "refresh1" {
+			reply(w, http.StatusForbidden, api.Error("invalid reauthentication credentials"))
+			return
+		}
+
+		reply(w, http.StatusOK, &api.LoginReply{AccessToken: "access2", RefreshToken: "refresh2"})
+	})
+
+	mux.HandleFunc("/v1/transactions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access2" {
+			reply(w, http.StatusUnauthorized, api.Error("this endpoint requires authentication"))
+			return
+		}
+		reply(w, http.StatusOK, &api.TransactionsList{})
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	client, err := api.New(ts.URL)
+	require.NoError(t, err, "could not create api client")
+
+	_, err = client.Login(ctx, &api.LoginRequest{Email: "jane@example.com", Password: "supersecret"})
+	require.NoError(t, err, "could not login")
+
+	// All requests are rejected with the expired access token at the same time
+	var wg sync.WaitGroup
+	errs := make([]error, 16)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = client.ListTransactions(ctx, page)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		require.NoError(t, err, "expected request %d to be retried with the refreshed access token", i)
+	}
+	require.Equal(t, int32(1), reauths.Load(), "expected concurrent requests to reauthenticate once")
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+// Report is a regulatory report of the travel rule transfers created during a period.
+// Reports are either generated on demand or by the reports scheduler (in which case
+// the schedule is set). The statistics of the report are only returned by the detail
+// endpoint; use the download endpoint to render the report as CSV, JSON, or HTML.
+type Report struct {
+	ID          ulid.ULID       `json:"id,omitempty"`
+	Name        string          `json:"name"`
+	Schedule    string          `json:"schedule,omitempty"`
+	PeriodStart time.Time       `json:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end"`
+	Sections    []string        `json:"sections,omitempty"`
+	CreatedBy   string          `json:"created_by,omitempty"`
+	Statistics  json.RawMessage `json:"statistics,omitempty"`
+	Created     time.Time       `json:"created,omitempty"`
+	Modified    time.Time       `json:"modified,omitempty"`
+}
+
+type ReportList struct {
+	Reports []*Report `json:"reports"`
+}
+
+// ReportDownloadQuery specifies the format a report is rendered in when downloaded.
+type ReportDownloadQuery struct {
+	Format string `json:"format,omitempty" url:"format,omitempty" form:"format"`
+}
+
+// Report download formats
+const (
+	ReportFormatCSV  = "csv"
+	ReportFormatJSON = "json"
+	ReportFormatHTML = "html"
+)
+
+func NewReport(model *models.Report, statistics bool) (out *Report, err error) {
+	out = &Report{
+		ID:          model.ID,
+		Name:        model.Name,
+		Schedule:    model.Schedule.String,
+		PeriodStart: model.PeriodStart,
+		PeriodEnd:   model.PeriodEnd,
+		CreatedBy:   model.CreatedBy,
+		Created:     model.Created,
+		Modified:    model.Modified,
+	}
+
+	if statistics && len(model.Data) > 0 {
+		out.Statistics = json.RawMessage(model.Data)
+	}
+
+	return out, nil
+}
+
+func NewReportList(reports []*models.Report) (out *ReportList, err error) {
+	out = &ReportList{
+		Reports: make([]*Report, 0, len(reports)),
+	}
+
+	for _, model := range reports {
+		var report *Report
+		if report, err = NewReport(model, false); err != nil {
+			return nil, err
+		}
+		out.Reports = append(out.Reports, report)
+	}
+
+	return out, nil
+}
+
+// Validate a request to generate a report; only the name, the period, and the sections
+// of the report can be specified. The sections are validated by the reports package.
+func (r *Report) Validate() (err error) {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		err = ValidationError(err, MissingField("name"))
+	}
+
+	if r.PeriodStart.IsZero() {
+		err = ValidationError(err, MissingField("period_start"))
+	}
+
+	if r.PeriodEnd.IsZero() {
+		err = ValidationError(err, MissingField("period_end"))
+	}
+
+	if !r.PeriodStart.IsZero() && !r.PeriodEnd.IsZero() && !r.PeriodStart.Before(r.PeriodEnd) {
+		err = ValidationError(err, IncorrectField("period_end", "the end of the period must be after the start of the period"))
+	}
+
+	if !r.ID.IsZero() {
+		err = ValidationError(err, ReadOnlyField("id"))
+	}
+
+	if r.Schedule != "" {
+		err = ValidationError(err, ReadOnlyField("schedule"))
+	}
+
+	if r.CreatedBy != "" {
+		err = ValidationError(err, ReadOnlyField("created_by"))
+	}
+
+	if len(r.Statistics) > 0 {
+		err = ValidationError(err, ReadOnlyField("statistics"))
+	}
+
+	return err
+}
+
+// Validate the download query, defaulting to CSV if no format is specified.
+func (q *ReportDownloadQuery) Validate() (err error) {
+	q.Format = strings.ToLower(strings.TrimSpace(q.Format))
+	switch q.Format {
+	case "":
+		q.Format = ReportFormatCSV
+	case ReportFormatCSV, ReportFormatJSON, ReportFormatHTML:
+	default:
+		err = ValidationError(err, IncorrectField("format", "format must be one of csv, json, or html"))
+	}
+	return err
+}
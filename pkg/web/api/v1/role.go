@@ -0,0 +1,142 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/auth/permissions"
+)
+
+type Role struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	IsDefault   bool      `json:"is_default"`
+	RequireMFA  bool      `json:"require_mfa"`
+	IsBuiltin   bool      `json:"is_builtin"`
+	Permissions []string  `json:"permissions"`
+	Created     time.Time `json:"created,omitempty"`
+	Modified    time.Time `json:"modified,omitempty"`
+}
+
+type RoleList struct {
+	Roles []*Role `json:"roles"`
+}
+
+type RoleMFAPolicy struct {
+	Required bool `json:"required"`
+}
+
+func NewRole(model *models.Role) (out *Role, err error) {
+	out = &Role{
+		ID:          model.ID,
+		Title:       model.Title,
+		Description: model.Description,
+		IsDefault:   model.IsDefault,
+		RequireMFA:  model.RequireMFA,
+		IsBuiltin:   model.IsBuiltin,
+		Created:     model.Created,
+		Modified:    model.Modified,
+	}
+
+	// Permissions are only available if they were fetched with the role
+	if permissions, perr := model.Permissions(); perr == nil {
+		out.Permissions = make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			out.Permissions = append(out.Permissions, permission.Title)
+		}
+	}
+
+	return out, nil
+}
+
+func NewRoleList(roles []*models.Role) (out *RoleList, err error) {
+	out = &RoleList{
+		Roles: make([]*Role, 0, len(roles)),
+	}
+
+	for _, model := range roles {
+		var role *Role
+		if role, err = NewRole(model); err != nil {
+			return nil, err
+		}
+		out.Roles = append(out.Roles, role)
+	}
+
+	return out, nil
+}
+
+// Role titles are used to lookup roles in URLs and user filters so they are limited to
+// letters, numbers, spaces, hyphens, and underscores.
+var roleTitle = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} _-]*$`)
+
+// Validates a role to create or update; the MFA policy of a role can be set when it is
+// created but is otherwise updated with the role MFA policy endpoint.
+func (r *Role) Validate(create bool) (err error) {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Description = strings.TrimSpace(r.Description)
+
+	if create && r.ID != 0 {
+		err = ValidationError(err, ReadOnlyField("id"))
+	}
+
+	if r.IsDefault {
+		err = ValidationError(err, ReadOnlyField("is_default"))
+	}
+
+	if r.IsBuiltin {
+		err = ValidationError(err, ReadOnlyField("is_builtin"))
+	}
+
+	switch {
+	case r.Title == "":
+		err = ValidationError(err, MissingField("title"))
+	case len(r.Title) > 64:
+		err = ValidationError(err, IncorrectField("title", "title must be 64 characters or fewer"))
+	case !roleTitle.MatchString(r.Title):
+		err = ValidationError(err, IncorrectField("title", "title may only contain letters, numbers, spaces, hyphens, and underscores"))
+	}
+
+	if len(r.Permissions) == 0 {
+		err = ValidationError(err, MissingField("permissions"))
+	}
+
+	// Using the permiss package, validate the permissions assigned to the role.
+	// NOTE: this does not perform database validation, just string constant matches
+	seen := make(map[string]struct{}, len(r.Permissions))
+	unique := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		if p, perr := permissions.Parse(permission); perr != nil || p == permissions.Unknown {
+			err = ValidationError(err, IncorrectField("permissions", fmt.Sprintf("%q is not a valid permission", permission)))
+		} else if _, ok := seen[p.String()]; !ok {
+			// Ensure the permission is in the correct format and not duplicated
+			seen[p.String()] = struct{}{}
+			unique = append(unique, p.String())
+		}
+	}
+	r.Permissions = unique
+
+	return err
+}
+
+func (r *Role) Model() (model *models.Role, err error) {
+	model = &models.Role{
+		ID:          r.ID,
+		Title:       r.Title,
+		Description: r.Description,
+		RequireMFA:  r.RequireMFA,
+		Created:     r.Created,
+		Modified:    r.Modified,
+	}
+
+	permissions := make([]*models.Permission, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		permissions = append(permissions, &models.Permission{Title: permission})
+	}
+	model.SetPermissions(permissions)
+
+	return model, nil
+}
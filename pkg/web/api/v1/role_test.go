@@ -0,0 +1,45 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+func TestRoleValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		role := &api.Role{
+			Title:       " Auditor ",
+			Permissions: []string{"users:view", "TravelRule:View", "users:view"},
+		}
+		require.NoError(t, role.Validate(true))
+		require.Equal(t, "Auditor", role.Title)
+		require.Equal(t, []string{"users:view", "travelrule:view"}, role.Permissions, "expected permissions to be normalized and deduplicated")
+
+		model, err := role.Model()
+		require.NoError(t, err)
+		permissions, err := model.Permissions()
+		require.NoError(t, err)
+		require.Len(t, permissions, 2)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := []struct {
+			role   *api.Role
+			create bool
+			err    string
+		}{
+			{&api.Role{Permissions: []string{"users:view"}}, true, "missing title: this field is required"},
+			{&api.Role{Title: "Auditor"}, true, "missing permissions: this field is required"},
+			{&api.Role{Title: "Auditor", Permissions: []string{"foo:bar"}}, true, `invalid field permissions: "foo:bar" is not a valid permission`},
+			{&api.Role{Title: "Auditor%", Permissions: []string{"users:view"}}, true, "invalid field title: title may only contain letters, numbers, spaces, hyphens, and underscores"},
+			{&api.Role{ID: 4, Title: "Auditor", Permissions: []string{"users:view"}}, true, "read-only field id: this field cannot be written by the user"},
+			{&api.Role{ID: 4, Title: "Auditor", IsBuiltin: true, Permissions: []string{"users:view"}}, false, "read-only field is_builtin: this field cannot be written by the user"},
+		}
+
+		for i, tc := range testCases {
+			require.EqualError(t, tc.role.Validate(tc.create), tc.err, "test case %d failed", i)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"go.rtnl.ai/ulid"
+)
+
+//===========================================================================
+// Threshold Rules
+//===========================================================================
+
+// ThresholdRule defines the fiat-equivalent amount at or above which a transfer
+// between the originator and beneficiary jurisdictions requires complete travel rule
+// data. Unset criteria match any value, e.g. a rule with only an originator country
+// applies to all transfers from that country. The threshold is denominated in the
+// fiat currency of the thresholds configuration of the node.
+type ThresholdRule struct {
+	ID                 ulid.ULID `json:"id,omitempty"`
+	Description        string    `json:"description"`
+	OriginatorCountry  string    `json:"originator_country,omitempty"`
+	BeneficiaryCountry string    `json:"beneficiary_country,omitempty"`
+	VirtualAsset       string    `json:"virtual_asset,omitempty"`
+	Threshold          float64   `json:"threshold"`
+	Created            time.Time `json:"created,omitempty"`
+	Modified           time.Time `json:"modified,omitempty"`
+}
+
+type ThresholdRuleList struct {
+	Rules []*ThresholdRule `json:"rules"`
+}
+
+// TravelRule describes if complete travel rule data is required for a transfer based on
+// the threshold rule that applies to the jurisdictions, asset, and fiat-equivalent
+// amount of the transfer. If no rule applies, travel rule data is required.
+type TravelRule struct {
+	Required           bool      `json:"required"`
+	OriginatorCountry  string    `json:"originator_country,omitempty"`
+	BeneficiaryCountry string    `json:"beneficiary_country,omitempty"`
+	FiatAmount         *float64  `json:"fiat_amount,omitempty"`
+	FiatCurrency       string    `json:"fiat_currency,omitempty"`
+	RuleID             ulid.ULID `json:"rule_id,omitempty"`
+	Rule               string    `json:"rule,omitempty"`
+	Threshold          *float64  `json:"threshold,omitempty"`
+}
+
+func NewThresholdRule(model *models.ThresholdRule) (out *ThresholdRule, err error) {
+	return &ThresholdRule{
+		ID:                 model.ID,
+		Description:        model.Description,
+		OriginatorCountry:  model.OriginatorCountry.String,
+		BeneficiaryCountry: model.BeneficiaryCountry.String,
+		VirtualAsset:       model.VirtualAsset.String,
+		Threshold:          model.Threshold,
+		Created:            model.Created,
+		Modified:           model.Modified,
+	}, nil
+}
+
+func NewThresholdRuleList(rules []*models.ThresholdRule) (out *ThresholdRuleList, err error) {
+	out = &ThresholdRuleList{
+		Rules: make([]*ThresholdRule, 0, len(rules)),
+	}
+
+	for _, model := range rules {
+		var rule *ThresholdRule
+		if rule, err = NewThresholdRule(model); err != nil {
+			return nil, err
+		}
+		out.Rules = append(out.Rules, rule)
+	}
+
+	return out, nil
+}
+
+func (r *ThresholdRule) Validate() (err error) {
+	r.Description = strings.TrimSpace(r.Description)
+	if r.Description == "" {
+		err = ValidationError(err, MissingField("description"))
+	}
+
+	r.OriginatorCountry = strings.ToUpper(strings.TrimSpace(r.OriginatorCountry))
+	if r.OriginatorCountry != "" && len(r.OriginatorCountry) != 2 {
+		err = ValidationError(err, IncorrectField("originator_country", "country must be the two character (alpha-2) country code"))
+	}
+
+	r.BeneficiaryCountry = strings.ToUpper(strings.TrimSpace(r.BeneficiaryCountry))
+	if r.BeneficiaryCountry != "" && len(r.BeneficiaryCountry) != 2 {
+		err = ValidationError(err, IncorrectField("beneficiary_country", "country must be the two character (alpha-2) country code"))
+	}
+
+	r.VirtualAsset = strings.TrimSpace(r.VirtualAsset)
+
+	if r.Threshold < 0 {
+		err = ValidationError(err, IncorrectField("threshold", "threshold cannot be negative"))
+	}
+
+	return err
+}
+
+func (r *ThresholdRule) Model() (model *models.ThresholdRule, err error) {
+	return &models.ThresholdRule{
+		Model: models.Model{
+			ID:       r.ID,
+			Created:  r.Created,
+			Modified: r.Modified,
+		},
+		Description:        r.Description,
+		OriginatorCountry:  sql.NullString{String: r.OriginatorCountry, Valid: r.OriginatorCountry != ""},
+		BeneficiaryCountry: sql.NullString{String: r.BeneficiaryCountry, Valid: r.BeneficiaryCountry != ""},
+		VirtualAsset:       sql.NullString{String: r.VirtualAsset, Valid: r.VirtualAsset != ""},
+		Threshold:          r.Threshold,
+	}, nil
+}
+
+// NewTravelRule returns the threshold evaluation recorded on the transaction or nil
+// if the transaction was not evaluated against the threshold rules.
+func NewTravelRule(model *models.Transaction) *TravelRule {
+	if !model.TravelRuleRequired.Valid {
+		return nil
+	}
+
+	out := &TravelRule{
+		Required:     model.TravelRuleRequired.Bool,
+		FiatCurrency: model.FiatCurrency.String,
+		RuleID:       model.ThresholdRuleID.ULID,
+	}
+
+	if model.FiatAmount.Valid {
+		out.FiatAmount = &model.FiatAmount.Float64
+	}
+
+	return out
+}
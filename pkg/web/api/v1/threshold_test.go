@@ -0,0 +1,96 @@
+package api_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func TestThresholdRuleValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		testCases := []*api.ThresholdRule{
+			{Description: "all transfers"},
+			{Description: "eu", OriginatorCountry: "DE"},
+			{Description: "us", OriginatorCountry: "us", BeneficiaryCountry: "US", Threshold: 3000},
+			{Description: "bitcoin", VirtualAsset: "BTC", Threshold: 1000},
+		}
+
+		for i, tc := range testCases {
+			require.NoError(t, tc.Validate(), "test case %d failed", i)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		testCases := []struct {
+			rule *api.ThresholdRule
+			err  string
+		}{
+			{&api.ThresholdRule{Description: "  "}, "missing description: this field is required"},
+			{&api.ThresholdRule{Description: "foo", OriginatorCountry: "USA"}, "invalid field originator_country: country must be the two character (alpha-2) country code"},
+			{&api.ThresholdRule{Description: "foo", BeneficiaryCountry: "D"}, "invalid field beneficiary_country: country must be the two character (alpha-2) country code"},
+			{&api.ThresholdRule{Description: "foo", Threshold: -1}, "invalid field threshold: threshold cannot be negative"},
+		}
+
+		for i, tc := range testCases {
+			require.EqualError(t, tc.rule.Validate(), tc.err, "test case %d failed", i)
+		}
+	})
+
+	t.Run("Normalize", func(t *testing.T) {
+		rule := &api.ThresholdRule{Description: " us ", OriginatorCountry: " us", BeneficiaryCountry: "de ", VirtualAsset: " BTC "}
+		require.NoError(t, rule.Validate())
+		require.Equal(t, "us", rule.Description)
+		require.Equal(t, "US", rule.OriginatorCountry)
+		require.Equal(t, "DE", rule.BeneficiaryCountry)
+		require.Equal(t, "BTC", rule.VirtualAsset)
+	})
+}
+
+func TestThresholdRuleModel(t *testing.T) {
+	rule := &api.ThresholdRule{
+		Description:        "us to us",
+		OriginatorCountry:  "US",
+		BeneficiaryCountry: "US",
+		VirtualAsset:       "BTC",
+		Threshold:          3000,
+	}
+
+	model, err := rule.Model()
+	require.NoError(t, err)
+	require.Equal(t, sql.NullString{Valid: true, String: "US"}, model.OriginatorCountry)
+	require.Equal(t, sql.NullString{Valid: true, String: "US"}, model.BeneficiaryCountry)
+	require.Equal(t, sql.NullString{Valid: true, String: "BTC"}, model.VirtualAsset)
+	require.Equal(t, 3000.0, model.Threshold)
+
+	cmp, err := api.NewThresholdRule(model)
+	require.NoError(t, err)
+	require.Equal(t, rule, cmp)
+
+	// Unset criteria are null in the model
+	model, err = (&api.ThresholdRule{Description: "everything"}).Model()
+	require.NoError(t, err)
+	require.False(t, model.OriginatorCountry.Valid)
+	require.False(t, model.BeneficiaryCountry.Valid)
+	require.False(t, model.VirtualAsset.Valid)
+}
+
+func TestNewTravelRule(t *testing.T) {
+	require.Nil(t, api.NewTravelRule(&models.Transaction{}), "expected no travel rule if the transaction was not evaluated")
+
+	ruleID := ulid.MakeSecure()
+	out := api.NewTravelRule(&models.Transaction{
+		TravelRuleRequired: sql.NullBool{Valid: true, Bool: false},
+		FiatAmount:         sql.NullFloat64{Valid: true, Float64: 250},
+		FiatCurrency:       sql.NullString{Valid: true, String: "USD"},
+		ThresholdRuleID:    ulid.NullULID{Valid: true, ULID: ruleID},
+	})
+
+	require.False(t, out.Required)
+	require.Equal(t, 250.0, *out.FiatAmount)
+	require.Equal(t, "USD", out.FiatCurrency)
+	require.Equal(t, ruleID, out.RuleID)
+}
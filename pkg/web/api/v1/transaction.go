@@ -33,23 +33,29 @@ const (
 )
 
 type Transaction struct {
-	ID                 uuid.UUID  `json:"id"`
-	Source             string     `json:"source"`
-	Status             string     `json:"status"`
-	Counterparty       string     `json:"counterparty"`
-	CounterpartyID     ulid.ULID  `json:"counterparty_id,omitempty"`
-	Originator         string     `json:"originator,omitempty"`
-	OriginatorAddress  string     `json:"originator_address,omitempty"`
-	Beneficiary        string     `json:"beneficiary,omitempty"`
-	BeneficiaryAddress string     `json:"beneficiary_address,omitempty"`
-	VirtualAsset       string     `json:"virtual_asset"`
-	Amount             float64    `json:"amount"`
-	Archived           bool       `json:"archived,omitempty"`
-	ArchivedOn         *time.Time `json:"archived_on,omitempty"`
-	LastUpdate         *time.Time `json:"last_update,omitempty"`
-	EnvelopeCount      int64      `json:"envelope_count,omitempty"`
-	Created            time.Time  `json:"created"`
-	Modified           time.Time  `json:"modified"`
+	ID                 uuid.UUID   `json:"id"`
+	Source             string      `json:"source"`
+	Status             string      `json:"status"`
+	Counterparty       string      `json:"counterparty"`
+	CounterpartyID     ulid.ULID   `json:"counterparty_id,omitempty"`
+	Originator         string      `json:"originator,omitempty"`
+	OriginatorAddress  string      `json:"originator_address,omitempty"`
+	Beneficiary        string      `json:"beneficiary,omitempty"`
+	BeneficiaryAddress string      `json:"beneficiary_address,omitempty"`
+	VirtualAsset       string      `json:"virtual_asset"`
+	Amount             float64     `json:"amount"`
+	Archived           bool        `json:"archived,omitempty"`
+	ArchivedOn         *time.Time  `json:"archived_on,omitempty"`
+	LastUpdate         *time.Time  `json:"last_update,omitempty"`
+	ReplyNotAfter      *time.Time  `json:"reply_not_after,omitempty"`
+	ReplyStatus        string      `json:"reply_status,omitempty"`
+	EnvelopeCount      int64       `json:"envelope_count,omitempty"`
+	AssigneeID         ulid.ULID   `json:"assignee_id,omitempty"`
+	Assignee           string      `json:"assignee,omitempty"`
+	Tags               []string    `json:"tags,omitempty"`
+	TravelRule         *TravelRule `json:"travel_rule,omitempty"`
+	Created            time.Time   `json:"created"`
+	Modified           time.Time   `json:"modified"`
 }
 
 type SecureEnvelope struct {
@@ -121,6 +127,8 @@ type TransactionListQuery struct {
 	PageQuery
 	Status       []string `json:"status,omitempty" url:"status,omitempty" form:"status"`
 	VirtualAsset []string `json:"asset,omitempty" url:"asset,omitempty" form:"asset"`
+	Tags         []string `json:"tags,omitempty" url:"tags,omitempty" form:"tags"`
+	Overdue      bool     `json:"overdue,omitempty" url:"overdue,omitempty" form:"overdue"`
 	Archives     bool     `json:"archives,omitempty" url:"archives,omitempty" form:"archives"`
 }
 
@@ -155,6 +163,11 @@ func NewTransaction(model *models.Transaction) (*Transaction, error) {
 		Amount:             model.Amount,
 		Archived:           model.Archived,
 		EnvelopeCount:      model.NumEnvelopes(),
+		AssigneeID:         model.AssigneeID().ULID,
+		Assignee:           model.Assignee(),
+		Tags:               model.Tags(),
+		ReplyStatus:        model.ReplyStatus(time.Now()),
+		TravelRule:         NewTravelRule(model),
 		Created:            model.Created,
 		Modified:           model.Modified,
 	}
@@ -168,6 +181,11 @@ func NewTransaction(model *models.Transaction) (*Transaction, error) {
 	if model.LastUpdate.Valid {
 		tx.LastUpdate = &model.LastUpdate.Time
 	}
+
+	// If the transaction is waiting on a reply, add the deadline to the response.
+	if model.ReplyNotAfter.Valid {
+		tx.ReplyNotAfter = &model.ReplyNotAfter.Time
+	}
 	return tx, nil
 }
 
@@ -179,6 +197,8 @@ func NewTransactionList(page *models.TransactionPage) (out *TransactionsList, er
 			},
 			Status:       page.Page.Status,
 			VirtualAsset: page.Page.VirtualAsset,
+			Tags:         page.Page.Tags,
+			Overdue:      page.Page.Overdue,
 			Archives:     page.Page.Archives,
 		},
 		Transactions: make([]*Transaction, 0, len(page.Transactions)),
@@ -251,6 +271,10 @@ func (c *Transaction) Model() (model *models.Transaction, err error) {
 		model.LastUpdate = sql.NullTime{Valid: !c.LastUpdate.IsZero(), Time: *c.LastUpdate}
 	}
 
+	if c.ReplyNotAfter != nil {
+		model.ReplyNotAfter = sql.NullTime{Valid: !c.ReplyNotAfter.IsZero(), Time: c.ReplyNotAfter.UTC()}
+	}
+
 	return model, nil
 }
 
@@ -608,6 +632,10 @@ func (q *TransactionListQuery) Validate() (err error) {
 		}
 	}
 
+	if len(q.Tags) > 0 {
+		q.Tags = models.NormalizeTags(q.Tags)
+	}
+
 	return err
 }
 
@@ -618,6 +646,8 @@ func (q *TransactionListQuery) Query() (query *models.TransactionPageInfo) {
 		},
 		Status:       q.Status,
 		VirtualAsset: q.VirtualAsset,
+		Tags:         q.Tags,
+		Overdue:      q.Overdue,
 		Archives:     q.Archives,
 	}
 	return query
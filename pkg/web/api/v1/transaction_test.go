@@ -0,0 +1,61 @@
+package api_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+func TestTransactionReplyDeadline(t *testing.T) {
+	t.Run("Awaiting", func(t *testing.T) {
+		deadline := time.Now().Add(-1 * time.Hour).UTC()
+		model := &models.Transaction{
+			ID:            uuid.New(),
+			Status:        enum.StatusReview,
+			ReplyNotAfter: sql.NullTime{Valid: true, Time: deadline},
+		}
+
+		out, err := api.NewTransaction(model)
+		require.NoError(t, err, "could not create api transaction")
+		require.NotNil(t, out.ReplyNotAfter)
+		require.True(t, deadline.Equal(*out.ReplyNotAfter))
+		require.Equal(t, models.ReplyOverdue, out.ReplyStatus)
+
+		model, err = out.Model()
+		require.NoError(t, err, "could not convert api transaction to model")
+		require.True(t, model.ReplyNotAfter.Valid)
+		require.True(t, deadline.Equal(model.ReplyNotAfter.Time))
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		model := &models.Transaction{
+			ID:            uuid.New(),
+			Status:        enum.StatusCompleted,
+			ReplyNotAfter: sql.NullTime{Valid: true, Time: time.Now().Add(-1 * time.Hour)},
+		}
+
+		out, err := api.NewTransaction(model)
+		require.NoError(t, err, "could not create api transaction")
+		require.NotNil(t, out.ReplyNotAfter)
+		require.Empty(t, out.ReplyStatus, "expected no reply status for completed transactions")
+	})
+
+	t.Run("NoDeadline", func(t *testing.T) {
+		out, err := api.NewTransaction(&models.Transaction{ID: uuid.New(), Status: enum.StatusPending})
+		require.NoError(t, err, "could not create api transaction")
+		require.Nil(t, out.ReplyNotAfter)
+		require.Empty(t, out.ReplyStatus)
+	})
+}
+
+func TestTransactionListQueryOverdue(t *testing.T) {
+	query := &api.TransactionListQuery{Overdue: true}
+	require.NoError(t, query.Validate(), "expected valid query")
+	require.True(t, query.Query().Overdue)
+}
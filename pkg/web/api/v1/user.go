@@ -17,14 +17,16 @@ const (
 )
 
 type User struct {
-	ID        ulid.ULID  `json:"id,omitempty"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	Password  string     `json:"password,omitempty"`
-	Role      string     `json:"role"`
-	LastLogin *time.Time `json:"last_login,omitempty"`
-	Created   time.Time  `json:"created,omitempty"`
-	Modified  time.Time  `json:"modified,omitempty"`
+	ID          ulid.ULID  `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Password    string     `json:"password,omitempty"`
+	Role        string     `json:"role"`
+	MFAEnabled  bool       `json:"mfa_enabled"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	LastLogin   *time.Time `json:"last_login,omitempty"`
+	Created     time.Time  `json:"created,omitempty"`
+	Modified    time.Time  `json:"modified,omitempty"`
 }
 
 type UserList struct {
@@ -48,17 +50,23 @@ type UserQuery struct {
 
 func NewUser(model *models.User) (out *User, err error) {
 	out = &User{
-		ID:       model.ID,
-		Name:     model.Name.String,
-		Email:    model.Email,
-		Created:  model.Created,
-		Modified: model.Modified,
+		ID:         model.ID,
+		Name:       model.Name.String,
+		Email:      model.Email,
+		MFAEnabled: model.MFAEnabled(),
+		Created:    model.Created,
+		Modified:   model.Modified,
 	}
 
 	if model.LastLogin.Valid {
 		out.LastLogin = &model.LastLogin.Time
 	}
 
+	// Only users who are currently locked out of login have a lockout
+	if model.LoginLockedOut(time.Now()) {
+		out.LockedUntil = &model.LoginLocked.Time
+	}
+
 	if role, err := model.Role(); err == nil {
 		out.Role = role.Title
 	}
@@ -105,6 +113,10 @@ func (u *User) Validate() (err error) {
 		err = ValidationError(err, ReadOnlyField("last_login"))
 	}
 
+	if u.LockedUntil != nil {
+		err = ValidationError(err, ReadOnlyField("locked_until"))
+	}
+
 	// NOTE: role cannot be verified without a database query
 	return err
 }
@@ -171,12 +183,10 @@ func (q *UserQuery) Validate() (err error) {
 //===========================================================================
 
 func (q *UserListQuery) Validate() (err error) {
-	// TODO: valiating role should be a database query
+	// Roles are filtered by title; unknown roles simply return no users.
 	q.Role = strings.ToLower(strings.TrimSpace(q.Role))
-	if q.Role != "" {
-		if q.Role != "admin" && q.Role != "compliance" && q.Role != "observer" {
-			err = ValidationError(err, IncorrectField("role", "should be 'admin', 'compliance', or 'observer'"))
-		}
+	if q.Role != "" && !roleTitle.MatchString(q.Role) {
+		err = ValidationError(err, IncorrectField("role", "should be the title of a role"))
 	}
 	return err
 }
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -162,6 +163,16 @@ func (s *Server) APIKeyDetail(c *gin.Context) {
 		return
 	}
 
+	// Add the usage statistics of the api key to the detail
+	var usage []*models.APIKeyUsage
+	since := time.Now().AddDate(0, 0, -api.APIKeyUsageDays)
+	if usage, err = s.store.ListAPIKeyUsage(c.Request.Context(), keyID, since); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("unable to process apikey detail request"))
+		return
+	}
+	out.Usage = api.NewAPIKeyUsage(usage, since)
+
 	// Content negotiation
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
@@ -289,6 +300,98 @@ func (s *Server) UpdateAPIKey(c *gin.Context) {
 	}
 }
 
+func (s *Server) RotateAPIKey(c *gin.Context) {
+	var (
+		err         error
+		keyID       ulid.ULID
+		in          *api.APIKeyRotation
+		gracePeriod time.Duration
+		apikey      *models.APIKey
+		secret      string
+		out         *api.APIKey
+	)
+
+	// Parse the keyID from the URL
+	if keyID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("apikey not found"))
+		return
+	}
+
+	// Parse the rotation request
+	in = &api.APIKeyRotation{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse apikey rotation request"))
+		return
+	}
+
+	if gracePeriod, err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	// Fetch the model from the database
+	ctx := c.Request.Context()
+	if apikey, err = s.store.RetrieveAPIKey(ctx, keyID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("apikey not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process apikey rotation request"))
+		return
+	}
+
+	// Expired keys cannot be used so there is no reason to issue a new secret
+	now := time.Now()
+	if apikey.Expired(now) {
+		c.JSON(http.StatusConflict, api.Error("cannot rotate the secret of an expired api key"))
+		return
+	}
+
+	// Create a new secret; the current secret remains valid during the grace period
+	apikey.PreviousSecret = sql.NullString{String: apikey.Secret, Valid: true}
+	secret = passwords.Secret()
+	if apikey.Secret, err = passwords.CreateDerivedKey(secret); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process apikey rotation request"))
+		return
+	}
+	apikey.PreviousSecretExpires = sql.NullTime{Time: now.Add(gracePeriod), Valid: true}
+
+	if err = s.store.RotateAPIKey(ctx, apikey, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.RotateAPIKey()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("apikey not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process apikey rotation request"))
+		return
+	}
+
+	// Convert the model back to an API response
+	if out, err = api.NewAPIKey(apikey); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process apikey rotation request"))
+		return
+	}
+
+	// Ensure the new apikey secret is returned back to the user
+	out.Secret = secret
+
+	// Content negotiation
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/apikeys/rotated.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
 func (s *Server) DeleteAPIKey(c *gin.Context) {
 	var (
 		err   error
@@ -301,8 +404,8 @@ func (s *Server) DeleteAPIKey(c *gin.Context) {
 		return
 	}
 
-	// Delete the API key from the database
-	// TODO: for audit purposes we may simply want to move the API key to a revoked table.
+	// Delete the API key from the database; the identity of the key is retained without
+	// its secret as a revoked key so that audit log actors can still be resolved.
 	if err = s.store.DeleteAPIKey(c.Request.Context(), keyID, &models.ComplianceAuditLog{
 		ChangeNotes: sql.NullString{Valid: true, String: "Server.DeleteAPIKey()"},
 	}); err != nil {
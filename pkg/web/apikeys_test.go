@@ -0,0 +1,329 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerAuthenticateAPIKey() {
+	newKey := func(secret string) *models.APIKey {
+		dk, err := passwords.CreateDerivedKey(secret)
+		w.Require().NoError(err, "could not create derived key")
+
+		key := &models.APIKey{
+			Model:    models.Model{ID: ulid.MakeSecure()},
+			ClientID: passwords.KeyID(),
+			Secret:   dk,
+		}
+		key.SetPermissions([]string{"travelrule:view"})
+
+		w.store.OnRetrieveAPIKey = func(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error) {
+			return key, nil
+		}
+		w.store.OnSetAPIKeyLastSeen = func(ctx context.Context, keyID ulid.ULID, lastSeen time.Time) error {
+			return nil
+		}
+		return key
+	}
+
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		secret := passwords.Secret()
+		key := newKey(secret)
+		key.Expires = sql.NullTime{Valid: true, Time: time.Now().Add(time.Hour)}
+		key.AllowedNetworks = models.AllowedNetworks{"127.0.0.0/8"}
+
+		//test
+		out, err := w.ClientNoAuth().Authenticate(ctx, &api.APIAuthentication{ClientID: key.ClientID, ClientSecret: secret})
+		require.NoError(err, "unexpected client request error")
+		require.NotEmpty(out.AccessToken)
+		w.store.AssertCalls(w.T(), "SetAPIKeyLastSeen", 1)
+	})
+
+	w.Run("PreviousSecret", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		previous := passwords.Secret()
+		key := newKey(passwords.Secret())
+
+		dk, err := passwords.CreateDerivedKey(previous)
+		require.NoError(err, "could not create derived key")
+		key.PreviousSecret = sql.NullString{Valid: true, String: dk}
+		key.PreviousSecretExpires = sql.NullTime{Valid: true, Time: time.Now().Add(time.Hour)}
+
+		//test
+		out, err := w.ClientNoAuth().Authenticate(ctx, &api.APIAuthentication{ClientID: key.ClientID, ClientSecret: previous})
+		require.NoError(err, "expected the previous secret to be valid during the grace period")
+		require.NotEmpty(out.AccessToken)
+	})
+
+	w.Run("PreviousSecretExpired", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		previous := passwords.Secret()
+		key := newKey(passwords.Secret())
+
+		dk, err := passwords.CreateDerivedKey(previous)
+		require.NoError(err, "could not create derived key")
+		key.PreviousSecret = sql.NullString{Valid: true, String: dk}
+		key.PreviousSecretExpires = sql.NullTime{Valid: true, Time: time.Now().Add(-time.Minute)}
+
+		//test
+		out, err := w.ClientNoAuth().Authenticate(ctx, &api.APIAuthentication{ClientID: key.ClientID, ClientSecret: previous})
+		require.ErrorContains(err, "invalid api credentials", "expected the previous secret to be rejected after the grace period")
+		require.Nil(out)
+	})
+
+	w.Run("Expired", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		secret := passwords.Secret()
+		key := newKey(secret)
+		key.Expires = sql.NullTime{Valid: true, Time: time.Now().Add(-time.Minute)}
+
+		//test
+		out, err := w.ClientNoAuth().Authenticate(ctx, &api.APIAuthentication{ClientID: key.ClientID, ClientSecret: secret})
+		require.ErrorContains(err, "api key has expired")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "SetAPIKeyLastSeen", 0)
+	})
+
+	w.Run("NetworkNotAllowed", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		secret := passwords.Secret()
+		key := newKey(secret)
+		key.AllowedNetworks = models.AllowedNetworks{"10.0.0.0/8"}
+
+		//test
+		out, err := w.ClientNoAuth().Authenticate(ctx, &api.APIAuthentication{ClientID: key.ClientID, ClientSecret: secret})
+		require.ErrorContains(err, "api key cannot be used from this network")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "SetAPIKeyLastSeen", 0)
+	})
+}
+
+func (w *webTestSuite) TestServerAPIKeyRestrictions() {
+	newClaims := func() *auth.Claims {
+		return &auth.Claims{
+			ClientID:    "webTestSuite",
+			Permissions: []string{"users:view"},
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: "k" + ulid.MakeSecure().String(),
+			},
+		}
+	}
+
+	w.Run("RecordsUsage", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		claims := newClaims()
+		claims.Networks = []string{"127.0.0.0/8"}
+		claims.KeyExpires = jwt.NewNumericDate(time.Now().Add(time.Hour))
+
+		var ip string
+		w.store.OnListRoles = func(ctx context.Context) ([]*models.Role, error) {
+			return []*models.Role{}, nil
+		}
+		w.store.OnRecordAPIKeyUsage = func(ctx context.Context, keyID ulid.ULID, clientIP string, ts time.Time) error {
+			ip = clientIP
+			return nil
+		}
+
+		//test
+		_, err := w.ClientWithClaims(claims).ListRoles(ctx)
+		require.NoError(err, "unexpected client request error")
+		require.Equal("127.0.0.1", ip, "expected the client ip to be recorded")
+		w.store.AssertCalls(w.T(), "RecordAPIKeyUsage", 1)
+	})
+
+	w.Run("Expired", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		claims := newClaims()
+		claims.KeyExpires = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+		//test
+		out, err := w.ClientWithClaims(claims).ListRoles(ctx)
+		require.ErrorContains(err, "api key has expired")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "RecordAPIKeyUsage", 0)
+	})
+
+	w.Run("NetworkNotAllowed", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		claims := newClaims()
+		claims.Networks = []string{"10.0.0.0/8"}
+
+		//test
+		out, err := w.ClientWithClaims(claims).ListRoles(ctx)
+		require.ErrorContains(err, "api key cannot be used from this network")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "RecordAPIKeyUsage", 0)
+	})
+
+	w.Run("SpoofedForwardedFor", func() {
+		//setup
+		require := w.Require()
+		claims := newClaims()
+		claims.Networks = []string{"10.0.0.0/8"}
+
+		access, _, err := w.s.Issuer().CreateTokens(claims)
+		require.NoError(err, "could not create access token")
+
+		// No proxies are trusted so the forwarded ip cannot be used to bypass the allowlist
+		req, err := http.NewRequest(http.MethodGet, w.tsrv.URL+"/v1/roles", nil)
+		require.NoError(err, "could not create request")
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", "Bearer "+access)
+		req.Header.Set("X-Forwarded-For", "10.1.2.3")
+		req.Header.Set("X-Real-IP", "10.1.2.3")
+
+		//test
+		rep, err := w.tsrv.Client().Do(req)
+		require.NoError(err, "could not make request")
+		rep.Body.Close()
+		require.Equal(http.StatusForbidden, rep.StatusCode, "expected the spoofed client ip to be ignored")
+		w.store.AssertCalls(w.T(), "RecordAPIKeyUsage", 0)
+	})
+}
+
+func (w *webTestSuite) TestServerRotateAPIKey() {
+	newKey := func() *models.APIKey {
+		key := &models.APIKey{
+			Model:    models.Model{ID: ulid.MakeSecure()},
+			ClientID: passwords.KeyID(),
+			Secret:   "$argon2id$v=19$m=65536,t=1,p=2$current$current",
+		}
+
+		w.store.OnRetrieveAPIKey = func(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error) {
+			return key, nil
+		}
+		return key
+	}
+
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		key := newKey()
+
+		var rotated *models.APIKey
+		w.store.OnRotateAPIKey = func(ctx context.Context, key *models.APIKey, auditLog *models.ComplianceAuditLog) error {
+			rotated = key
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"apikeys:manage"}).RotateAPIKey(ctx, key.ID, &api.APIKeyRotation{GracePeriod: "1h"})
+		require.NoError(err, "unexpected client request error")
+		require.NotEmpty(out.Secret, "expected the new secret to be returned")
+		require.NotNil(out.PreviousSecretExpires, "expected the grace period to be returned")
+		require.WithinDuration(time.Now().Add(time.Hour), *out.PreviousSecretExpires, time.Minute)
+
+		verified, err := passwords.VerifyDerivedKey(rotated.Secret, out.Secret)
+		require.NoError(err)
+		require.True(verified, "expected the new secret to be stored")
+		w.store.AssertCalls(w.T(), "RotateAPIKey", 1)
+	})
+
+	w.Run("DefaultGracePeriod", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		key := newKey()
+		w.store.OnRotateAPIKey = func(ctx context.Context, key *models.APIKey, auditLog *models.ComplianceAuditLog) error {
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"apikeys:manage"}).RotateAPIKey(ctx, key.ID, &api.APIKeyRotation{})
+		require.NoError(err, "unexpected client request error")
+		require.WithinDuration(time.Now().Add(api.DefaultRotationGracePeriod), *out.PreviousSecretExpires, time.Minute)
+	})
+
+	w.Run("InvalidGracePeriod", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		key := newKey()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"apikeys:manage"}).RotateAPIKey(ctx, key.ID, &api.APIKeyRotation{GracePeriod: "1000h"})
+		require.ErrorContains(err, "grace period must be between 0s and 720h")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "RotateAPIKey", 0)
+	})
+
+	w.Run("Expired", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		key := newKey()
+		key.Expires = sql.NullTime{Valid: true, Time: time.Now().Add(-time.Minute)}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"apikeys:manage"}).RotateAPIKey(ctx, key.ID, &api.APIKeyRotation{})
+		require.ErrorContains(err, "cannot rotate the secret of an expired api key")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "RotateAPIKey", 0)
+	})
+
+	w.Run("FailureNoPermissions", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"apikeys:view"}).RotateAPIKey(ctx, ulid.MakeSecure(), &api.APIKeyRotation{})
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerAPIKeyDetailUsage() {
+	//setup
+	require := w.Require()
+	ctx := context.Background()
+	keyID := ulid.MakeSecure()
+	now := time.Now().UTC()
+
+	w.store.OnRetrieveAPIKey = func(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error) {
+		return &models.APIKey{Model: models.Model{ID: keyID}, ClientID: "abc1234"}, nil
+	}
+	w.store.OnListAPIKeyUsage = func(ctx context.Context, id ulid.ULID, since time.Time) ([]*models.APIKeyUsage, error) {
+		require.Equal(keyID, id)
+		return []*models.APIKeyUsage{
+			{APIKeyID: keyID, Day: now.Truncate(24 * time.Hour), Requests: 12, LastIP: sql.NullString{Valid: true, String: "10.0.0.2"}, LastUsed: now},
+			{APIKeyID: keyID, Day: now.AddDate(0, 0, -1).Truncate(24 * time.Hour), Requests: 30, LastIP: sql.NullString{Valid: true, String: "10.0.0.1"}, LastUsed: now.AddDate(0, 0, -1)},
+		}, nil
+	}
+
+	//test
+	out, err := w.ClientWithPermissions([]string{"apikeys:view"}).APIKeyDetail(ctx, keyID)
+	require.NoError(err, "unexpected client request error")
+	require.NotNil(out.Usage, "expected usage statistics in the detail")
+	require.Equal(int64(42), out.Usage.Requests)
+	require.Equal("10.0.0.2", out.Usage.LastIP)
+	require.Len(out.Usage.Daily, 2)
+	require.Equal(now.Format(time.DateOnly), out.Usage.Daily[0].Day)
+}
@@ -0,0 +1,834 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.rtnl.ai/ulid"
+
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/emails"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/postman"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+)
+
+//===========================================================================
+// Approvals
+//===========================================================================
+
+func (s *Server) ListApprovals(c *gin.Context) {
+	var (
+		err  error
+		in   *api.ApprovalQuery
+		page *models.ApprovalPage
+		out  *api.ApprovalList
+	)
+
+	// Parse the URL parameters from the input request
+	in = &api.ApprovalQuery{}
+	if err = c.BindQuery(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse approval query request"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	// Fetch the list of approvals from the database
+	if page, err = s.store.ListApprovals(c.Request.Context(), in.Query()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approvals list request"))
+		return
+	}
+
+	if out, err = api.NewApprovalList(page); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approvals list request"))
+		return
+	}
+
+	// Echo the cursor of the requested page so that clients can return to the newest approvals
+	out.Page.PageToken = in.NextPageToken
+
+	// Content negotiation
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/approvals/list.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+func (s *Server) ApprovalDetail(c *gin.Context) {
+	var (
+		err      error
+		approval *models.Approval
+		out      *api.Approval
+	)
+
+	if approval, err = s.retrieveApproval(c); err != nil {
+		return
+	}
+
+	if out, err = api.NewApproval(approval); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval detail request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Approves the pending action and executes it on behalf of the requester. The approval
+// is claimed before the action is executed so that it cannot be executed twice, even by
+// reviewers on different nodes. If the action cannot be executed, the claim is released
+// and the error from the action is returned to the reviewer so that the approval remains
+// pending and can be retried or denied.
+func (s *Server) ApproveAction(c *gin.Context) {
+	var (
+		err           error
+		in            *api.ApprovalReview
+		approval      *models.Approval
+		transactionID uuid.UUID
+		queued        bool
+		out           *api.Approval
+	)
+
+	if in, err = bindApprovalReview(c); err != nil {
+		return
+	}
+
+	if approval, err = s.retrieveApproval(c); err != nil {
+		return
+	}
+
+	if !approval.IsPending() {
+		c.JSON(http.StatusConflict, api.Error(dberr.ErrApprovalNotPending))
+		return
+	}
+
+	// The requester cannot approve their own action; this is also checked by the store
+	// but must be verified before the action is executed.
+	if isRequester(c, approval) {
+		c.JSON(http.StatusForbidden, api.Error(dberr.ErrSelfApproval))
+		return
+	}
+
+	// Claim the approval so that a concurrent review cannot execute the action again.
+	if err = s.store.ClaimApproval(c.Request.Context(), approval.ID); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound):
+			c.JSON(http.StatusNotFound, api.Error("approval not found"))
+		case errors.Is(err, dberr.ErrApprovalNotPending):
+			c.JSON(http.StatusConflict, api.Error(err))
+		case errors.Is(err, dberr.ErrSelfApproval):
+			c.JSON(http.StatusForbidden, api.Error(err))
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not process approve action request"))
+		}
+		return
+	}
+
+	// Execute the original request of the approval.
+	// NOTE: executeApproval handles any error response that needs to be sent to the user.
+	if transactionID, queued, err = s.executeApproval(c, approval); err != nil {
+		if err = s.store.ReleaseApproval(c.Request.Context(), approval.ID); err != nil {
+			c.Error(fmt.Errorf("could not release claim on approval after failed action: %w", err))
+		}
+		return
+	}
+
+	approval.Status = enum.ApprovalApproved
+	approval.ReviewedBy = sql.NullString{Valid: true, String: actorName(c)}
+	approval.ReviewNotes = sql.NullString{Valid: in.Notes != "", String: in.Notes}
+	approval.TransactionID = uuid.NullUUID{Valid: true, UUID: transactionID}
+
+	if err = s.store.ReviewApproval(c.Request.Context(), approval, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.ApproveAction()"},
+	}); err != nil {
+		// The action has already been executed, so the error is logged but the reviewer
+		// is still redirected to the transaction that was updated. The approval remains
+		// claimed so that the action cannot be executed again.
+		c.Error(fmt.Errorf("action executed but could not mark approval as approved: %w", err))
+	}
+
+	if htmx.IsHTMXRequest(c) {
+		if queued {
+			s.AddToastMessage(c, "Action Approved", fmt.Sprintf("The %s action was approved and the transfer was queued to be sent to the counterparty.", approval.Action), "success")
+			htmx.Redirect(c, http.StatusSeeOther, "/queue")
+			return
+		}
+
+		detailURL, _ := url.JoinPath("/transactions", transactionID.String())
+		s.AddToastMessage(c, "Action Approved", fmt.Sprintf("The %s action was approved and successfully sent to the counterparty.", approval.Action), "success")
+		htmx.Redirect(c, http.StatusSeeOther, detailURL)
+		return
+	}
+
+	if out, err = api.NewApproval(approval); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approve action request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) DenyAction(c *gin.Context) {
+	s.reviewApproval(c, enum.ApprovalDenied, "Server.DenyAction()")
+}
+
+func (s *Server) CancelAction(c *gin.Context) {
+	s.reviewApproval(c, enum.ApprovalCanceled, "Server.CancelAction()")
+}
+
+// Denies or cancels the approval without executing the action; the store ensures that
+// only a different actor can deny and only the requester can cancel the approval.
+func (s *Server) reviewApproval(c *gin.Context, status enum.ApprovalStatus, notes string) {
+	var (
+		err      error
+		in       *api.ApprovalReview
+		approval *models.Approval
+		out      *api.Approval
+	)
+
+	if in, err = bindApprovalReview(c); err != nil {
+		return
+	}
+
+	if approval, err = s.retrieveApproval(c); err != nil {
+		return
+	}
+
+	approval.Status = status
+	approval.ReviewedBy = sql.NullString{Valid: true, String: actorName(c)}
+	approval.ReviewNotes = sql.NullString{Valid: in.Notes != "", String: in.Notes}
+
+	if err = s.store.ReviewApproval(c.Request.Context(), approval, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: notes},
+	}); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrApprovalNotPending):
+			c.JSON(http.StatusConflict, api.Error(err))
+		case errors.Is(err, dberr.ErrSelfApproval), errors.Is(err, dberr.ErrNotRequester):
+			c.JSON(http.StatusForbidden, api.Error(err))
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not process approval review request"))
+		}
+		return
+	}
+
+	if out, err = api.NewApproval(approval); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval review request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.ApprovalsUpdated)
+	}
+}
+
+// Parses the optional review notes from the request; an empty body is allowed.
+func bindApprovalReview(c *gin.Context) (in *api.ApprovalReview, err error) {
+	in = &api.ApprovalReview{}
+	if c.Request.ContentLength == 0 {
+		return in, nil
+	}
+
+	if err = c.ShouldBind(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse approval review"))
+		return nil, err
+	}
+	return in, nil
+}
+
+// Retrieves the approval identified by the id URL parameter; if an error is returned
+// then the error response has already been sent to the user.
+func (s *Server) retrieveApproval(c *gin.Context) (approval *models.Approval, err error) {
+	var approvalID ulid.ULID
+	if approvalID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("approval not found"))
+		return nil, err
+	}
+
+	if approval, err = s.store.RetrieveApproval(c.Request.Context(), approvalID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("approval not found"))
+			return nil, err
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not retrieve approval"))
+		return nil, err
+	}
+	return approval, nil
+}
+
+// Returns true if the user or api key making the request requested the approval.
+func isRequester(c *gin.Context, approval *models.Approval) bool {
+	ctx := c.Request.Context()
+	actorID, _ := audit.ActorID(ctx)
+	actorType, _ := audit.ActorType(ctx)
+	return bytes.Equal(actorID, approval.RequestedByID) && actorType == approval.RequestedByType
+}
+
+// Decodes the original request of the approval and executes the action, returning the
+// ID of the transaction the action was performed on and if the transfer was queued to be
+// sent later. If an error is returned, the error response has already been sent to the
+// user.
+func (s *Server) executeApproval(c *gin.Context, approval *models.Approval) (transactionID uuid.UUID, queued bool, err error) {
+	if approval.Action != enum.ApprovalActionSend {
+		if !approval.TransactionID.Valid {
+			c.JSON(http.StatusConflict, api.Error("approval is not associated with a transaction"))
+			return uuid.Nil, false, dberr.ErrMissingReference
+		}
+		transactionID = approval.TransactionID.UUID
+	}
+
+	switch approval.Action {
+	case enum.ApprovalActionSend:
+		in := &api.Prepared{}
+		if err = s.decodeApprovalRequest(c, approval, in, in.Validate); err != nil {
+			return uuid.Nil, false, err
+		}
+
+		var payload *trisa.Payload
+		if payload, err = in.Payload(); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, api.Error("could not create payload for transfer"))
+			return uuid.Nil, false, err
+		}
+
+		// NOTE: Send commits/rollsback the database transaction from the packet.
+		var packet *postman.Packet
+		if packet, queued, err = s.Send(c, in.Routing, payload, in.SendAt); err != nil {
+			return uuid.Nil, false, err
+		}
+		return packet.Transaction.ID, queued, nil
+
+	case enum.ApprovalActionAccept:
+		in := &api.Envelope{}
+		if err = s.decodeApprovalRequest(c, approval, in, in.Validate); err != nil {
+			return uuid.Nil, false, err
+		}
+
+		var payload *trisa.Payload
+		if payload, err = in.Payload(); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, api.Error("could not create payload for transfer accept"))
+			return uuid.Nil, false, err
+		}
+
+		if _, err = s.acceptTransaction(c, transactionID, payload); err != nil {
+			return uuid.Nil, false, err
+		}
+		return transactionID, false, nil
+
+	case enum.ApprovalActionReject:
+		in := &api.Rejection{}
+		if err = s.decodeApprovalRequest(c, approval, in, in.Validate); err != nil {
+			return uuid.Nil, false, err
+		}
+
+		if _, err = s.rejectTransaction(c, transactionID, in); err != nil {
+			return uuid.Nil, false, err
+		}
+		return transactionID, false, nil
+
+	case enum.ApprovalActionComplete:
+		in := &generic.Transaction{}
+		validate := func() error { return api.ValidateTransactionPayload(in, enum.StatusCompleted) }
+		if err = s.decodeApprovalRequest(c, approval, in, validate); err != nil {
+			return uuid.Nil, false, err
+		}
+
+		if _, err = s.completeTransaction(c, transactionID, in); err != nil {
+			return uuid.Nil, false, err
+		}
+		return transactionID, false, nil
+
+	default:
+		err = fmt.Errorf("unhandled approval action %q", approval.Action)
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not execute approved action"))
+		return uuid.Nil, false, err
+	}
+}
+
+// Unmarshals the stored request of the approval into the request type of the action
+// and validates it again since validation may depend on the current state of the node.
+func (s *Server) decodeApprovalRequest(c *gin.Context, approval *models.Approval, in any, validate func() error) (err error) {
+	if err = json.Unmarshal(approval.Request, in); err != nil {
+		c.Error(fmt.Errorf("could not unmarshal approval request: %w", err))
+		c.JSON(http.StatusInternalServerError, api.Error("could not execute approved action"))
+		return err
+	}
+
+	if err = validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Checks the approval rules to determine if the transfer workflow action requires
+// four-eyes approval; if so, a pending approval is created with the original request
+// so that it can be executed when a different user approves it. Returns true if the
+// response has been handled, either because the action was held or an error occurred.
+// For the send action the transaction ID is unknown and the request must be prepared.
+func (s *Server) holdForApproval(c *gin.Context, action enum.ApprovalAction, transactionID uuid.UUID, request any) bool {
+	approval, rule, err := s.requireApproval(c.Request.Context(), action, transactionID, request, actorName(c))
+	if err != nil {
+		s.ResponseError(c, err)
+		return true
+	}
+
+	if approval == nil {
+		return false
+	}
+
+	if approval.TransactionID.Valid {
+		setIdempotentTransaction(c, approval.TransactionID.UUID)
+	}
+
+	if htmx.IsHTMXRequest(c) {
+		redirectURL := "/approvals"
+		if approval.TransactionID.Valid {
+			redirectURL, _ = url.JoinPath("/transactions", approval.TransactionID.UUID.String())
+		}
+
+		s.AddToastMessage(c, "Approval Required", fmt.Sprintf("The %s action requires approval by another user: %s", action, rule.Description), "info")
+		htmx.Redirect(c, http.StatusSeeOther, redirectURL)
+		return true
+	}
+
+	out, err := api.NewApproval(approval)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not create approval request"))
+		return true
+	}
+
+	c.JSON(http.StatusAccepted, out)
+	return true
+}
+
+// Creates a pending approval and notifies the reviewers if the action requires
+// four-eyes approval by the approval rules, returning the approval and the rule that
+// requires it. If the action does not require approval, a nil approval is returned.
+// If an error is returned it is a *ResponseError.
+func (s *Server) requireApproval(ctx context.Context, action enum.ApprovalAction, transactionID uuid.UUID, request any, requestedBy string) (approval *models.Approval, rule *models.ApprovalRule, err error) {
+	var rules []*models.ApprovalRule
+	if rules, err = s.store.ListApprovalRules(ctx); err != nil {
+		return nil, nil, NewResponseError(http.StatusInternalServerError, "could not check if approval is required", err)
+	}
+
+	// No rules means that four-eyes approval is not enabled
+	if len(rules) == 0 {
+		return nil, nil, nil
+	}
+
+	approval = &models.Approval{
+		Action:      action,
+		RequestedBy: requestedBy,
+	}
+
+	if action == enum.ApprovalActionSend {
+		in, ok := request.(*api.Prepared)
+		if !ok {
+			return nil, nil, NewResponseError(http.StatusInternalServerError, "could not check if approval is required", fmt.Errorf("unexpected request type %T for send approval", request))
+		}
+
+		if in.Transaction != nil {
+			approval.VirtualAsset = sql.NullString{Valid: true, String: postman.VirtualAsset(in.Transaction.Network, in.Transaction.AssetType)}
+			approval.Amount = sql.NullFloat64{Valid: true, Float64: in.Transaction.Amount}
+		}
+
+		approval.Counterparty = sql.NullString{Valid: in.Routing.Counterparty != "", String: in.Routing.Counterparty}
+		if !in.Routing.CounterpartyID.IsZero() {
+			approval.CounterpartyID = ulid.NullULID{Valid: true, ULID: in.Routing.CounterpartyID}
+		} else if rulesRequireCounterparty(rules) {
+			// Resolve the counterparty so that counterparty rules can be matched
+			var vasp *models.Counterparty
+			if vasp, err = s.ResolveCounterparty(ctx, in.Routing); err != nil {
+				return nil, nil, err
+			}
+			approval.Counterparty = sql.NullString{Valid: true, String: vasp.Name}
+			approval.CounterpartyID = ulid.NullULID{Valid: true, ULID: vasp.ID}
+		}
+	} else {
+		// If the transaction cannot be found or is not in the correct state, the action
+		// is not held so that the action returns the appropriate error to the user.
+		var tx *models.Transaction
+		if tx, err = s.store.RetrieveTransaction(ctx, transactionID); err != nil {
+			if errors.Is(err, dberr.ErrNotFound) {
+				return nil, nil, nil
+			}
+			return nil, nil, NewResponseError(http.StatusInternalServerError, "could not check if approval is required", err)
+		}
+
+		if tx.Archived || tx.Status != approvalActionStatus(action) {
+			return nil, nil, nil
+		}
+
+		approval.TransactionID = uuid.NullUUID{Valid: true, UUID: tx.ID}
+		approval.Counterparty = sql.NullString{Valid: tx.Counterparty != "", String: tx.Counterparty}
+		approval.CounterpartyID = tx.CounterpartyID
+		approval.VirtualAsset = sql.NullString{Valid: tx.VirtualAsset != "", String: tx.VirtualAsset}
+		approval.Amount = sql.NullFloat64{Valid: true, Float64: tx.Amount}
+	}
+
+	// The first matching rule requires approval of the action
+	for _, r := range rules {
+		if r.Matches(approval) {
+			rule = r
+			break
+		}
+	}
+
+	if rule == nil {
+		return nil, nil, nil
+	}
+
+	// Only one action can be pending approval for a transaction at a time
+	if approval.TransactionID.Valid {
+		var page *models.ApprovalPage
+		if page, err = s.store.ListApprovals(ctx, &models.ApprovalPageInfo{
+			PageInfo:      models.PageInfo{PageSize: 1},
+			Status:        enum.ApprovalPending.String(),
+			TransactionID: transactionID.String(),
+		}); err != nil {
+			return nil, nil, NewResponseError(http.StatusInternalServerError, "could not check if approval is required", err)
+		}
+
+		if len(page.Approvals) > 0 {
+			return nil, nil, NewResponseError(http.StatusConflict, "transaction already has a pending approval", nil)
+		}
+	}
+
+	approval.RuleID = ulid.NullULID{Valid: true, ULID: rule.ID}
+	if approval.Request, err = json.Marshal(request); err != nil {
+		return nil, nil, NewResponseError(http.StatusInternalServerError, "could not create approval request", err)
+	}
+
+	if err = s.store.CreateApproval(ctx, approval, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.holdForApproval()"},
+	}); err != nil {
+		return nil, nil, NewResponseError(http.StatusInternalServerError, "could not create approval request", err)
+	}
+
+	// If the reviewers cannot be notified the approval is still pending and is listed
+	// on the approvals page so the error is logged but not returned to the user.
+	s.notifyApprovalReviewers(ctx, approval, rule)
+	return approval, rule, nil
+}
+
+// Returns the transaction status that is required to perform the action.
+func approvalActionStatus(action enum.ApprovalAction) enum.Status {
+	switch action {
+	case enum.ApprovalActionAccept, enum.ApprovalActionReject:
+		return enum.StatusReview
+	case enum.ApprovalActionComplete:
+		return enum.StatusAccepted
+	default:
+		return enum.StatusUnspecified
+	}
+}
+
+func rulesRequireCounterparty(rules []*models.ApprovalRule) bool {
+	for _, rule := range rules {
+		if rule.CounterpartyID.Valid {
+			return true
+		}
+	}
+	return false
+}
+
+// Sends an email to every user that can review approvals other than the requester.
+func (s *Server) notifyApprovalReviewers(ctx context.Context, approval *models.Approval, rule *models.ApprovalRule) {
+	reviewers, err := s.store.ListApprovalReviewers(ctx)
+	if err != nil {
+		log.Warn().Err(err).Str("approval_id", approval.ID.String()).Msg("could not list approval reviewers")
+		return
+	}
+
+	data := emails.ApprovalRequestEmailData{
+		RequestedBy:  approval.RequestedBy,
+		Action:       approval.Action.String(),
+		Counterparty: approval.Counterparty.String,
+		VirtualAsset: approval.VirtualAsset.String,
+		Rule:         rule.Description,
+		ApprovalsURL: s.conf.Web.ApprovalsURL(),
+		SupportEmail: s.conf.Email.SupportEmail,
+	}
+
+	if approval.Amount.Valid {
+		data.Amount = strconv.FormatFloat(approval.Amount.Float64, 'f', -1, 64)
+	}
+
+	for _, reviewer := range reviewers {
+		if approval.RequestedByType == enum.ActorUser && bytes.Equal(reviewer.ID[:], approval.RequestedByID) {
+			continue
+		}
+
+		data.ContactName = reviewer.Name.String
+
+		var email *emails.Email
+		if email, err = emails.NewApprovalRequestEmail(reviewer.Email, data); err != nil {
+			log.Warn().Err(err).Str("approval_id", approval.ID.String()).Msg("could not create approval request email")
+			continue
+		}
+
+		if err = email.Send(); err != nil {
+			log.Warn().Err(err).Str("approval_id", approval.ID.String()).Msg("could not send approval request email")
+		}
+	}
+}
+
+//===========================================================================
+// Approval Rules
+//===========================================================================
+
+func (s *Server) ListApprovalRules(c *gin.Context) {
+	var (
+		err   error
+		rules []*models.ApprovalRule
+		out   *api.ApprovalRuleList
+	)
+
+	if rules, err = s.store.ListApprovalRules(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval rules list request"))
+		return
+	}
+
+	if out, err = api.NewApprovalRuleList(rules); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval rules list request"))
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/approvals/rules.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+func (s *Server) CreateApprovalRule(c *gin.Context) {
+	var (
+		err  error
+		in   *api.ApprovalRule
+		rule *models.ApprovalRule
+		out  *api.ApprovalRule
+	)
+
+	in = &api.ApprovalRule{}
+	if err = c.Bind(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse approval rule data"))
+		return
+	}
+
+	if !in.ID.IsZero() {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(api.ReadOnlyField("id")))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if rule, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval rule data"))
+		return
+	}
+
+	if err = s.store.CreateApprovalRule(c.Request.Context(), rule, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateApprovalRule()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrMissingReference) {
+			c.JSON(http.StatusUnprocessableEntity, api.Error("counterparty not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create approval rule request"))
+		return
+	}
+
+	if out, err = api.NewApprovalRule(rule); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create approval rule request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusCreated, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.ApprovalsUpdated)
+	}
+}
+
+func (s *Server) ApprovalRuleDetail(c *gin.Context) {
+	var (
+		err    error
+		ruleID ulid.ULID
+		rule   *models.ApprovalRule
+		out    *api.ApprovalRule
+	)
+
+	if ruleID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("approval rule not found"))
+		return
+	}
+
+	if rule, err = s.store.RetrieveApprovalRule(c.Request.Context(), ruleID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("approval rule not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval rule detail request"))
+		return
+	}
+
+	if out, err = api.NewApprovalRule(rule); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval rule detail request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) UpdateApprovalRule(c *gin.Context) {
+	var (
+		err    error
+		ruleID ulid.ULID
+		in     *api.ApprovalRule
+		rule   *models.ApprovalRule
+		out    *api.ApprovalRule
+	)
+
+	if ruleID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("approval rule not found"))
+		return
+	}
+
+	in = &api.ApprovalRule{}
+	if err = c.Bind(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse approval rule data"))
+		return
+	}
+
+	if err = CheckIDMatch(in.ID, ruleID); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if rule, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process approval rule data"))
+		return
+	}
+
+	if err = s.store.UpdateApprovalRule(c.Request.Context(), rule, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.UpdateApprovalRule()"},
+	}); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound):
+			c.JSON(http.StatusNotFound, api.Error("approval rule not found"))
+		case errors.Is(err, dberr.ErrMissingReference):
+			c.JSON(http.StatusUnprocessableEntity, api.Error("counterparty not found"))
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not process update approval rule request"))
+		}
+		return
+	}
+
+	if out, err = api.NewApprovalRule(rule); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process update approval rule request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.ApprovalsUpdated)
+	}
+}
+
+func (s *Server) DeleteApprovalRule(c *gin.Context) {
+	var (
+		err    error
+		ruleID ulid.ULID
+	)
+
+	if ruleID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("approval rule not found"))
+		return
+	}
+
+	if err = s.store.DeleteApprovalRule(c.Request.Context(), ruleID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.DeleteApprovalRule()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("approval rule not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process delete approval rule request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, api.Reply{Success: true})
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.ApprovalsUpdated)
+	}
+}
@@ -0,0 +1,441 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerListApprovals() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListApprovals = func(ctx context.Context, page *models.ApprovalPageInfo) (*models.ApprovalPage, error) {
+			require.Equal("pending", page.Status)
+			return &models.ApprovalPage{
+				Approvals: []*models.Approval{
+					{
+						Model:         models.Model{ID: ulid.MakeSecure(), Created: time.Now()},
+						Action:        enum.ApprovalActionAccept,
+						Status:        enum.ApprovalPending,
+						TransactionID: uuid.NullUUID{Valid: true, UUID: uuid.New()},
+						Request:       []byte(`{}`),
+						RequestedBy:   "Compliance User",
+					},
+				},
+				Page: page,
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListApprovals(ctx, &api.ApprovalQuery{Status: "pending"})
+		require.NoError(err, "unexpected client request error")
+		require.Len(out.Approvals, 1)
+		require.Equal("accept", out.Approvals[0].Action)
+		require.True(out.Approvals[0].IsPending())
+	})
+
+	w.Run("InvalidQuery", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListApprovals(ctx, &api.ApprovalQuery{Status: "waiting"})
+		require.ErrorContains(err, "status must be one of pending, executing, approved, denied, or canceled")
+		require.Nil(out)
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).ListApprovals(ctx, &api.ApprovalQuery{})
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerHoldForApproval() {
+	rejection := &api.Rejection{Code: "COMPLIANCE_CHECK_FAIL", Message: "sanctioned beneficiary"}
+	transaction := func(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+		return &models.Transaction{
+			ID:           id,
+			Status:       enum.StatusReview,
+			Counterparty: "AliceVASP",
+			VirtualAsset: "BTC",
+			Amount:       1.5,
+		}, nil
+	}
+
+	w.Run("Held", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		ruleID := ulid.MakeSecure()
+		w.store.OnListApprovalRules = func(ctx context.Context) ([]*models.ApprovalRule, error) {
+			return []*models.ApprovalRule{
+				{Model: models.Model{ID: ulid.MakeSecure()}, Description: "large sends", Action: enum.ApprovalActionSend},
+				{Model: models.Model{ID: ruleID}, Description: "bitcoin", VirtualAsset: sql.NullString{Valid: true, String: "BTC"}},
+			}, nil
+		}
+		w.store.OnRetrieveTransaction = transaction
+		w.store.OnListApprovals = func(ctx context.Context, page *models.ApprovalPageInfo) (*models.ApprovalPage, error) {
+			return &models.ApprovalPage{Page: page}, nil
+		}
+		w.store.OnCreateApproval = func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(enum.ApprovalActionReject, approval.Action)
+			require.Equal(ruleID, approval.RuleID.ULID)
+			require.Equal(1.5, approval.Amount.Float64)
+			require.JSONEq(`{"code":"COMPLIANCE_CHECK_FAIL","message":"sanctioned beneficiary","retry":false}`, string(approval.Request))
+			approval.ID = ulid.MakeSecure()
+			approval.Status = enum.ApprovalPending
+			return nil
+		}
+		w.store.OnListApprovalReviewers = func(ctx context.Context) ([]*models.User, error) {
+			return []*models.User{{Model: models.Model{ID: ulid.MakeSecure()}, Email: "reviewer@example.com"}}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).Reject(ctx, uuid.New(), rejection)
+		require.Nil(out)
+
+		var held *api.ApprovalRequired
+		require.ErrorAs(err, &held, "expected the rejection to be held for approval")
+		require.Equal("reject", held.Approval.Action)
+		require.True(held.Approval.IsPending())
+
+		// Emails are not configured in tests so reviewers are not notified but the
+		// action is still held for approval.
+		w.store.AssertCalls(w.T(), "CreateApproval", 1)
+		w.store.AssertCalls(w.T(), "ListApprovalReviewers", 1)
+		w.store.AssertCalls(w.T(), "TransactionState", 0)
+	})
+
+	w.Run("AlreadyPending", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListApprovalRules = func(ctx context.Context) ([]*models.ApprovalRule, error) {
+			return []*models.ApprovalRule{{Model: models.Model{ID: ulid.MakeSecure()}, Description: "all rejections", Action: enum.ApprovalActionReject}}, nil
+		}
+		w.store.OnRetrieveTransaction = transaction
+		w.store.OnListApprovals = func(ctx context.Context, page *models.ApprovalPageInfo) (*models.ApprovalPage, error) {
+			return &models.ApprovalPage{Approvals: []*models.Approval{{Status: enum.ApprovalPending}}, Page: page}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).Reject(ctx, uuid.New(), rejection)
+		require.ErrorContains(err, "transaction already has a pending approval")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "CreateApproval", 0)
+	})
+
+	w.Run("NoMatchingRule", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListApprovalRules = func(ctx context.Context) ([]*models.ApprovalRule, error) {
+			return []*models.ApprovalRule{{Model: models.Model{ID: ulid.MakeSecure()}, Description: "large amounts", MinAmount: sql.NullFloat64{Valid: true, Float64: 100}}}, nil
+		}
+		w.store.OnRetrieveTransaction = transaction
+		w.store.OnTransactionState = func(ctx context.Context, id uuid.UUID) (bool, enum.Status, error) {
+			return true, enum.StatusReview, nil
+		}
+
+		//test
+		// The rejection is not held so it is executed and fails because it is archived
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).Reject(ctx, uuid.New(), rejection)
+		require.ErrorContains(err, "transaction is archived")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "CreateApproval", 0)
+		w.store.AssertCalls(w.T(), "TransactionState", 1)
+	})
+}
+
+func (w *webTestSuite) TestServerApproveAction() {
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"approvals:review"}).ApproveAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "approval not found")
+		require.Nil(out)
+	})
+
+	w.Run("NotPending", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{Model: models.Model{ID: approvalID}, Action: enum.ApprovalActionAccept, Status: enum.ApprovalDenied}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"approvals:review"}).ApproveAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "approval has already been reviewed")
+		require.Nil(out)
+	})
+
+	w.Run("SelfApproval", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		keyID := ulid.MakeSecure()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{
+				Model:           models.Model{ID: approvalID},
+				Action:          enum.ApprovalActionAccept,
+				Status:          enum.ApprovalPending,
+				TransactionID:   uuid.NullUUID{Valid: true, UUID: uuid.New()},
+				RequestedByID:   keyID.Bytes(),
+				RequestedByType: enum.ActorAPIKey,
+			}, nil
+		}
+
+		//test
+		client := w.ClientWithClaims(&auth.Claims{
+			ClientID:         "webTestSuite",
+			Permissions:      []string{"approvals:review"},
+			RegisteredClaims: jwt.RegisteredClaims{Subject: "k" + keyID.String()},
+		})
+
+		out, err := client.ApproveAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "actions cannot be reviewed by the actor that requested them")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "TransactionState", 0)
+		w.store.AssertCalls(w.T(), "ReviewApproval", 0)
+	})
+
+	w.Run("ActionFailed", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{
+				Model:           models.Model{ID: approvalID},
+				Action:          enum.ApprovalActionReject,
+				Status:          enum.ApprovalPending,
+				TransactionID:   uuid.NullUUID{Valid: true, UUID: uuid.New()},
+				Request:         []byte(`{"code":"COMPLIANCE_CHECK_FAIL","message":"sanctioned beneficiary"}`),
+				RequestedByID:   ulid.MakeSecure().Bytes(),
+				RequestedByType: enum.ActorUser,
+			}, nil
+		}
+		w.store.OnClaimApproval = func(ctx context.Context, approvalID ulid.ULID) error {
+			return nil
+		}
+		w.store.OnReleaseApproval = func(ctx context.Context, approvalID ulid.ULID) error {
+			return nil
+		}
+		w.store.OnTransactionState = func(ctx context.Context, id uuid.UUID) (bool, enum.Status, error) {
+			return false, enum.StatusCompleted, nil
+		}
+
+		//test
+		// If the action cannot be executed the claim is released so the approval remains pending
+		out, err := w.ClientWithPermissions([]string{"approvals:review"}).ApproveAction(ctx, ulid.MakeSecure(), &api.ApprovalReview{Notes: "looks good"})
+		require.ErrorContains(err, "transaction not in a reviewable state")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "ClaimApproval", 1)
+		w.store.AssertCalls(w.T(), "TransactionState", 1)
+		w.store.AssertCalls(w.T(), "ReleaseApproval", 1)
+		w.store.AssertCalls(w.T(), "ReviewApproval", 0)
+	})
+
+	w.Run("AlreadyClaimed", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{
+				Model:           models.Model{ID: approvalID},
+				Action:          enum.ApprovalActionReject,
+				Status:          enum.ApprovalPending,
+				TransactionID:   uuid.NullUUID{Valid: true, UUID: uuid.New()},
+				Request:         []byte(`{"code":"COMPLIANCE_CHECK_FAIL","message":"sanctioned beneficiary"}`),
+				RequestedByID:   ulid.MakeSecure().Bytes(),
+				RequestedByType: enum.ActorUser,
+			}, nil
+		}
+
+		// Another reviewer claimed the approval after it was retrieved
+		w.store.OnClaimApproval = func(ctx context.Context, approvalID ulid.ULID) error {
+			return dberr.ErrApprovalNotPending
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"approvals:review"}).ApproveAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "approval has already been reviewed")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "TransactionState", 0)
+		w.store.AssertCalls(w.T(), "ReleaseApproval", 0)
+		w.store.AssertCalls(w.T(), "ReviewApproval", 0)
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).ApproveAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerDenyAction() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{Model: models.Model{ID: approvalID}, Action: enum.ApprovalActionSend, Status: enum.ApprovalPending}, nil
+		}
+		w.store.OnReviewApproval = func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(enum.ApprovalDenied, approval.Status)
+			require.Equal("counterparty is not verified", approval.ReviewNotes.String)
+			require.Equal("webTestSuite", approval.ReviewedBy.String)
+			approval.ReviewedOn = sql.NullTime{Valid: true, Time: time.Now()}
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"approvals:review"}).DenyAction(ctx, ulid.MakeSecure(), &api.ApprovalReview{Notes: "counterparty is not verified"})
+		require.NoError(err, "unexpected client request error")
+		require.Equal("denied", out.Status)
+		require.NotNil(out.ReviewedOn)
+	})
+
+	w.Run("SelfApproval", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{Model: models.Model{ID: approvalID}, Action: enum.ApprovalActionSend, Status: enum.ApprovalPending}, nil
+		}
+		w.store.OnReviewApproval = func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrSelfApproval
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"approvals:review"}).DenyAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "[403]")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerCancelAction() {
+	w.Run("NotRequester", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{Model: models.Model{ID: approvalID}, Action: enum.ApprovalActionSend, Status: enum.ApprovalPending}, nil
+		}
+		w.store.OnReviewApproval = func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(enum.ApprovalCanceled, approval.Status)
+			return dberr.ErrNotRequester
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CancelAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "approvals can only be canceled by the actor that requested them")
+		require.Nil(out)
+	})
+
+	w.Run("NotPending", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveApproval = func(ctx context.Context, approvalID ulid.ULID) (*models.Approval, error) {
+			return &models.Approval{Model: models.Model{ID: approvalID}, Action: enum.ApprovalActionSend, Status: enum.ApprovalApproved}, nil
+		}
+		w.store.OnReviewApproval = func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrApprovalNotPending
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CancelAction(ctx, ulid.MakeSecure(), nil)
+		require.ErrorContains(err, "[409]")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerApprovalRules() {
+	w.Run("Create", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnCreateApprovalRule = func(ctx context.Context, rule *models.ApprovalRule, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(enum.ApprovalActionSend, rule.Action)
+			require.Equal(10.0, rule.MinAmount.Float64)
+			rule.ID = ulid.MakeSecure()
+			return nil
+		}
+
+		//test
+		amount := 10.0
+		out, err := w.ClientWithPermissions([]string{"approvals:manage"}).CreateApprovalRule(ctx, &api.ApprovalRule{
+			Description: "large sends",
+			Action:      "send",
+			MinAmount:   &amount,
+		})
+		require.NoError(err, "unexpected client request error")
+		require.False(out.ID.IsZero())
+		require.Equal("send", out.Action)
+	})
+
+	w.Run("Invalid", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"approvals:manage"}).CreateApprovalRule(ctx, &api.ApprovalRule{Action: "archive"})
+		require.ErrorContains(err, "2 validation errors occurred")
+		require.Nil(out)
+	})
+
+	w.Run("Delete", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnDeleteApprovalRule = func(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"approvals:manage"}).DeleteApprovalRule(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "approval rule not found")
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"approvals:review"}).CreateApprovalRule(ctx, &api.ApprovalRule{Description: "all"})
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
@@ -1,11 +1,13 @@
 package web
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/trisacrypto/envoy/pkg/enum"
 	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
@@ -34,8 +36,6 @@ func (s *Server) ListComplianceAuditLogs(c *gin.Context) {
 		return
 	}
 
-	// TODO: implement better pagination mechanism (with pagination tokens)
-
 	if page, err = s.store.ListComplianceAuditLogs(c.Request.Context(), in.Query()); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("could not process compliance audit log list request"))
@@ -49,6 +49,10 @@ func (s *Server) ListComplianceAuditLogs(c *gin.Context) {
 		return
 	}
 
+	// Return the cursor of the requested page so that clients can refresh the current
+	// page or return to the newest logs if an older page was requested.
+	out.Page.PageToken = in.NextPageToken
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
 		Data:     out,
@@ -86,6 +90,13 @@ func (s *Server) ComplianceAuditLogDetail(c *gin.Context) {
 	// Convert the DB model to the API model
 	out = api.NewComplianceAuditLog(log)
 
+	// Resolve the identity of the actor, including users and api keys that were revoked
+	if out.Actor, err = s.complianceAuditLogActor(c.Request.Context(), log); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error(err))
+		return
+	}
+
 	c.Negotiate(http.StatusOK, gin.Negotiate{
 		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
 		Data:     out,
@@ -93,3 +104,49 @@ func (s *Server) ComplianceAuditLogDetail(c *gin.Context) {
 		HTMLData: scene.New(c).WithAPIData(out),
 	})
 }
+
+// Looks up the user or api key that is the actor of the audit log, falling back to the
+// revoked users and api keys if the actor has been deleted. Returns nil if the actor is
+// not a user or api key or if the actor cannot be found.
+func (s *Server) complianceAuditLogActor(ctx context.Context, log *models.ComplianceAuditLog) (_ *api.ComplianceAuditLogActor, err error) {
+	var actorID ulid.ULID
+	switch log.ActorType {
+	case enum.ActorUser, enum.ActorAPIKey:
+		if actorID, err = ulid.Parse(log.ActorID); err != nil {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	if log.ActorType == enum.ActorUser {
+		var user *models.User
+		if user, err = s.store.RetrieveUser(ctx, actorID); err == nil {
+			return api.NewUserActor(user), nil
+		} else if !errors.Is(err, dberr.ErrNotFound) {
+			return nil, err
+		}
+
+		var revoked *models.RevokedUser
+		if revoked, err = s.store.RetrieveRevokedUser(ctx, actorID); err == nil {
+			return api.NewRevokedUserActor(revoked), nil
+		}
+	} else {
+		var key *models.APIKey
+		if key, err = s.store.RetrieveAPIKey(ctx, actorID); err == nil {
+			return api.NewAPIKeyActor(key), nil
+		} else if !errors.Is(err, dberr.ErrNotFound) {
+			return nil, err
+		}
+
+		var revoked *models.RevokedAPIKey
+		if revoked, err = s.store.RetrieveRevokedAPIKey(ctx, actorID); err == nil {
+			return api.NewRevokedAPIKeyActor(revoked), nil
+		}
+	}
+
+	if errors.Is(err, dberr.ErrNotFound) {
+		return nil, nil
+	}
+	return nil, err
+}
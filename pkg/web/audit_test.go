@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/mock"
 	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
@@ -21,13 +23,14 @@ func (w *webTestSuite) TestServerListComplianceAuditLogs() {
 			query := &api.ComplianceAuditLogQuery{
 				PageQuery: api.PageQuery{
 					PageSize:      999,
-					NextPageToken: "this can be anything",
+					NextPageToken: ulid.MakeSecure().String(),
 					PrevPageToken: "this can be anything",
 				},
 				ResourceTypes: []string{"transaction", "user", "api_key", "counterparty", "account", "sunrise"},
 				ResourceID:    "this can be anything",
 				ActorTypes:    []string{"user", "api_key", "sunrise"},
 				ActorID:       "this can be anything",
+				Actions:       []string{"create", "update", "delete"},
 				After:         &after,
 				Before:        &before,
 				DetailedLogs:  true,
@@ -55,24 +58,50 @@ func (w *webTestSuite) TestServerListComplianceAuditLogs() {
 			query := &api.ComplianceAuditLogQuery{
 				PageQuery: api.PageQuery{
 					PageSize:      999,
-					NextPageToken: "this can be anything",
+					NextPageToken: "not a valid token",
 					PrevPageToken: "this can be anything",
 				},
 				ResourceTypes: []string{ulid.MakeSecure().String()},
 				ResourceID:    "this can be anything",
 				ActorTypes:    []string{ulid.MakeSecure().String()},
 				ActorID:       "this can be anything",
+				Actions:       []string{"foo"},
 				After:         &after,
 				Before:        &before,
 			}
 
 			//test
 			logs, err := w.ClientWithPermissions(AllPermissions).ListComplianceAuditLogs(ctx, query)
-			require.ErrorContains(err, "4 validation errors occurred", "should have found 4 validation errors")
+			require.ErrorContains(err, "6 validation errors occurred", "should have found 6 validation errors")
 			require.Nil(logs, "response object should be nil")
 		})
 	})
 
+	w.Run("PageTokens", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		current, next := ulid.MakeSecure(), ulid.MakeSecure()
+		query := &api.ComplianceAuditLogQuery{
+			PageQuery: api.PageQuery{NextPageToken: current.String()},
+		}
+		w.store.OnListComplianceAuditLogs = func(ctx context.Context, page *models.ComplianceAuditLogPageInfo) (*models.ComplianceAuditLogPage, error) {
+			require.Equal(current, page.NextPageID, "expected the page cursor to be passed to the store")
+			page.NextPageID = next
+			return &models.ComplianceAuditLogPage{
+				Page: page,
+				Logs: []*models.ComplianceAuditLog{},
+			}, nil
+		}
+
+		//test
+		logs, err := w.ClientWithPermissions(AllPermissions).ListComplianceAuditLogs(ctx, query)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(next.String(), logs.Page.NextPageToken, "expected the cursor of the next page")
+		require.Equal(current.String(), logs.Page.PageToken, "expected the cursor of the requested page")
+		require.Empty(logs.Page.PrevPageToken, "expected no previous page cursor")
+	})
+
 	w.Run("Auth", func() {
 		w.Run("SuccessTailoredPermissions", func() {
 			//setup
@@ -195,6 +224,9 @@ func (w *webTestSuite) TestServerComplianceAuditLogDetail() {
 			w.store.OnRetrieveComplianceAuditLog = func(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error) {
 				return mock.GetComplianceAuditLog(true, true), nil
 			}
+			w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+				return mock.GetSampleUser(false), nil
+			}
 			permissions := []string{
 				"users:view",
 				"apikeys:view",
@@ -217,6 +249,9 @@ func (w *webTestSuite) TestServerComplianceAuditLogDetail() {
 			w.store.OnRetrieveComplianceAuditLog = func(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error) {
 				return mock.GetComplianceAuditLog(true, true), nil
 			}
+			w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+				return mock.GetSampleUser(false), nil
+			}
 			permissions := AllPermissions
 
 			//test
@@ -282,4 +317,114 @@ func (w *webTestSuite) TestServerComplianceAuditLogDetail() {
 			require.Nil(log, "expected a nil response object")
 		})
 	})
+
+	w.Run("Actor", func() {
+		w.Run("ActiveUser", func() {
+			//setup
+			require := w.Require()
+			ctx := context.Background()
+			model := mock.GetComplianceAuditLog(true, true)
+			w.store.OnRetrieveComplianceAuditLog = func(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error) {
+				return model, nil
+			}
+
+			user := mock.GetSampleUser(false)
+			user.ID = ulid.MustParse(model.ActorID)
+			w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+				return user, nil
+			}
+
+			//test
+			log, err := w.ClientWithPermissions(AllPermissions).ComplianceAuditLogDetail(ctx, model.ID)
+			require.NoError(err, "unexpected client request error")
+			require.NotNil(log.Actor, "expected the actor to be resolved")
+			require.Equal(user.ID, log.Actor.ID)
+			require.Equal(user.Email, log.Actor.Email)
+			require.Nil(log.Actor.Revoked, "expected the actor to not be revoked")
+			w.store.AssertCalls(w.T(), "RetrieveRevokedUser", 0)
+		})
+
+		w.Run("RevokedUser", func() {
+			//setup
+			require := w.Require()
+			ctx := context.Background()
+			model := mock.GetComplianceAuditLog(true, true)
+			w.store.OnRetrieveComplianceAuditLog = func(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error) {
+				return model, nil
+			}
+			w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+				return nil, dberr.ErrNotFound
+			}
+
+			revoked := &models.RevokedUser{
+				ID:      ulid.MustParse(model.ActorID),
+				Email:   "revoked@example.com",
+				Role:    "Compliance",
+				Created: time.Now().Add(-720 * time.Hour),
+				Revoked: time.Now().Add(-1 * time.Hour),
+			}
+			w.store.OnRetrieveRevokedUser = func(ctx context.Context, userID ulid.ULID) (*models.RevokedUser, error) {
+				return revoked, nil
+			}
+
+			//test
+			log, err := w.ClientWithPermissions(AllPermissions).ComplianceAuditLogDetail(ctx, model.ID)
+			require.NoError(err, "unexpected client request error")
+			require.NotNil(log.Actor, "expected the revoked actor to be resolved")
+			require.Equal(revoked.ID, log.Actor.ID)
+			require.Equal(revoked.Email, log.Actor.Email)
+			require.NotNil(log.Actor.Revoked, "expected the actor to be revoked")
+		})
+
+		w.Run("RevokedAPIKey", func() {
+			//setup
+			require := w.Require()
+			ctx := context.Background()
+			model := mock.GetComplianceAuditLog(true, true)
+			model.ActorType = enum.ActorAPIKey
+			w.store.OnRetrieveComplianceAuditLog = func(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error) {
+				return model, nil
+			}
+			w.store.OnRetrieveAPIKey = func(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error) {
+				return nil, dberr.ErrNotFound
+			}
+
+			revoked := &models.RevokedAPIKey{
+				ID:       ulid.MustParse(model.ActorID),
+				ClientID: "revokedclientid",
+				Revoked:  time.Now().Add(-1 * time.Hour),
+			}
+			w.store.OnRetrieveRevokedAPIKey = func(ctx context.Context, keyID ulid.ULID) (*models.RevokedAPIKey, error) {
+				return revoked, nil
+			}
+
+			//test
+			log, err := w.ClientWithPermissions(AllPermissions).ComplianceAuditLogDetail(ctx, model.ID)
+			require.NoError(err, "unexpected client request error")
+			require.NotNil(log.Actor, "expected the revoked actor to be resolved")
+			require.Equal(revoked.ClientID, log.Actor.ClientID)
+			require.NotNil(log.Actor.Revoked, "expected the actor to be revoked")
+		})
+
+		w.Run("Unresolved", func() {
+			//setup
+			require := w.Require()
+			ctx := context.Background()
+			model := mock.GetComplianceAuditLog(true, true)
+			w.store.OnRetrieveComplianceAuditLog = func(ctx context.Context, id ulid.ULID) (*models.ComplianceAuditLog, error) {
+				return model, nil
+			}
+			w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+				return nil, dberr.ErrNotFound
+			}
+			w.store.OnRetrieveRevokedUser = func(ctx context.Context, userID ulid.ULID) (*models.RevokedUser, error) {
+				return nil, dberr.ErrNotFound
+			}
+
+			//test
+			log, err := w.ClientWithPermissions(AllPermissions).ComplianceAuditLogDetail(ctx, model.ID)
+			require.NoError(err, "expected the log to be returned without an actor")
+			require.Nil(log.Actor, "expected no actor")
+		})
+	})
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
 	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/mfa"
 	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
 	"github.com/trisacrypto/envoy/pkg/web/htmx"
 	"github.com/trisacrypto/envoy/pkg/web/scene"
@@ -36,6 +37,11 @@ func (s *Server) Login(c *gin.Context) {
 		ctx    context.Context
 	)
 
+	if s.conf.Web.Auth.DisablePasswordLogin {
+		c.JSON(http.StatusForbidden, api.Error(ErrPasswordLogin))
+		return
+	}
+
 	if err = c.BindJSON(&in); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusBadRequest, api.Error("could not parse login request"))
@@ -62,15 +68,56 @@ func (s *Server) Login(c *gin.Context) {
 		return
 	}
 
+	// Users who have failed too many logins cannot login until the lockout expires
+	if user.LoginLockedOut(time.Now()) {
+		c.JSON(http.StatusTooManyRequests, api.Error(ErrLoginLocked))
+		return
+	}
+
 	// Check that the password supplied in the request is correct
 	if verified, err := passwords.VerifyDerivedKey(user.Password, in.Password); err != nil || !verified {
 		log := logger.Tracing(ctx)
 		log.Debug().Err(err).Msg("invalid login credentials")
 
+		s.recordLoginFailure(ctx, user)
 		c.JSON(http.StatusForbidden, api.Error("invalid login credentials"))
 		return
 	}
 
+	// If the user has enrolled in MFA, a TOTP or recovery code is required to login
+	if user.MFAEnabled() {
+		if in.Code == "" {
+			c.JSON(http.StatusUnauthorized, &api.MFARequiredReply{
+				Reply:       api.Error("multi-factor authentication code required"),
+				MFARequired: true,
+			})
+			return
+		}
+
+		if verified, err := s.verifyMFACode(ctx, user, in.Code); err != nil || !verified {
+			if errors.Is(err, mfa.ErrLocked) {
+				c.JSON(http.StatusTooManyRequests, api.Error("too many invalid authentication codes, please try again later"))
+				return
+			}
+
+			if err != nil && !errors.Is(err, mfa.ErrBadCode) && !errors.Is(err, mfa.ErrCodeReused) {
+				c.Error(err)
+			}
+
+			c.JSON(http.StatusForbidden, api.Error("invalid login credentials"))
+			return
+		}
+	}
+
+	// Reset the failed logins of the user after a successful login
+	if user.LoginFailures > 0 || user.LoginLockouts > 0 {
+		if err = s.store.ResetUserLoginFailures(ctx, user.ID); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("unable to process login request"))
+			return
+		}
+	}
+
 	// Update user last login timestamp
 	user.LastLogin = sql.NullTime{Valid: true, Time: time.Now()}
 	if err = s.store.SetUserLastLogin(ctx, user.ID, user.LastLogin.Time); err != nil {
@@ -89,6 +136,13 @@ func (s *Server) Login(c *gin.Context) {
 		return
 	}
 
+	// Create a session for the tokens so that the user can revoke it
+	if err = s.createSession(c, user, claims); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("unable to process login request"))
+		return
+	}
+
 	out = &api.LoginReply{}
 	if out.AccessToken, out.RefreshToken, err = s.issuer.CreateTokens(claims); err != nil {
 		c.Error(err)
@@ -108,8 +162,15 @@ func (s *Server) Login(c *gin.Context) {
 	case binding.MIMEJSON:
 		c.JSON(http.StatusOK, out)
 	case binding.MIMEHTML:
-		if in.Next != "" {
-			htmx.Redirect(c, http.StatusSeeOther, in.Next)
+		// Users that must enroll in MFA are sent to their account page to do so
+		if claims.MFAEnroll {
+			htmx.Redirect(c, http.StatusSeeOther, "/profile/account")
+			return
+		}
+
+		// Only redirect to local paths to prevent open redirects to other sites
+		if next := safeRedirect(in.Next); next != "" {
+			htmx.Redirect(c, http.StatusSeeOther, next)
 			return
 		}
 		htmx.Redirect(c, http.StatusSeeOther, "/")
@@ -118,7 +179,24 @@ func (s *Server) Login(c *gin.Context) {
 	}
 }
 
+// Records a failed login for the user, locking the user out once the configured number
+// of consecutive logins have failed. Errors are logged since the login has failed.
+func (s *Server) recordLoginFailure(ctx context.Context, user *models.User) {
+	conf := s.conf.Web.Auth
+	if conf.MaxLoginFailures <= 0 {
+		return
+	}
+
+	if err := s.store.RecordUserLoginFailure(ctx, user.ID, conf.MaxLoginFailures, conf.LockoutDuration, conf.MaxLockoutDuration); err != nil {
+		log := logger.Tracing(ctx)
+		log.Warn().Err(err).Str("user_id", user.ID.String()).Msg("could not record failed login")
+	}
+}
+
 func (s *Server) Logout(c *gin.Context) {
+	// Revoke the session of the user so that the refresh token cannot be reused
+	s.revokeCurrentSession(c)
+
 	// Clear the client cookies
 	auth.ClearAuthCookies(c, s.conf.Web.Auth.CookieDomain)
 
@@ -163,7 +241,8 @@ func (s *Server) Authenticate(c *gin.Context) {
 	}
 
 	// Verify the client secret is correct
-	if verified, err := passwords.VerifyDerivedKey(apikey.Secret, in.ClientSecret); err != nil || !verified {
+	now := time.Now()
+	if err = verifyAPIKeySecret(apikey, in.ClientSecret, now); err != nil {
 		log := logger.Tracing(ctx)
 		log.Debug().Err(err).Msg("invalid api key credentials")
 
@@ -171,6 +250,12 @@ func (s *Server) Authenticate(c *gin.Context) {
 		return
 	}
 
+	// Ensure the api key has not expired and is used from an allowed network
+	if err = verifyAPIKeyRestrictions(c, apikey, now); err != nil {
+		c.JSON(http.StatusForbidden, api.Error(err))
+		return
+	}
+
 	// Update api key last seen timestamp
 	if err = s.store.SetAPIKeyLastSeen(ctx, apikey.ID, time.Now()); err != nil {
 		log := logger.Tracing(ctx)
@@ -258,7 +343,7 @@ func (s *Server) Reauthenticate(c *gin.Context) {
 	// Update last seen or last login timestamp
 	switch sub {
 	case auth.SubjectUser:
-		if claims, err = s.reauthenticateUser(c, subID); err != nil {
+		if claims, err = s.reauthenticateUser(c, subID, claims); err != nil {
 			// Error logging and response is handled in method
 			return
 		}
@@ -299,7 +384,7 @@ func (s *Server) Reauthenticate(c *gin.Context) {
 	}
 }
 
-func (s *Server) reauthenticateUser(c *gin.Context, userID ulid.ULID) (_ *auth.Claims, err error) {
+func (s *Server) reauthenticateUser(c *gin.Context, userID ulid.ULID, refresh *auth.Claims) (claims *auth.Claims, err error) {
 	ctx := c.Request.Context()
 
 	var user *models.User
@@ -314,6 +399,24 @@ func (s *Server) reauthenticateUser(c *gin.Context, userID ulid.ULID) (_ *auth.C
 		return nil, err
 	}
 
+	if claims, err = auth.NewClaims(ctx, user); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("unable to process reauthenticate request"))
+		return nil, err
+	}
+
+	// The refresh token cannot be used if its session has been revoked
+	if err = s.refreshSession(ctx, user.ID, refresh, claims); err != nil {
+		if errors.Is(err, auth.ErrSessionRevoked) {
+			c.JSON(http.StatusForbidden, api.Error("invalid reauthentication credentials"))
+			return nil, err
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("unable to process reauthenticate request"))
+		return nil, err
+	}
+
 	user.LastLogin = sql.NullTime{Valid: true, Time: time.Now()}
 	if err = s.store.SetUserLastLogin(ctx, user.ID, user.LastLogin.Time); err != nil {
 		log := logger.Tracing(ctx)
@@ -324,7 +427,11 @@ func (s *Server) reauthenticateUser(c *gin.Context, userID ulid.ULID) (_ *auth.C
 		return
 	}
 
-	return auth.NewClaims(ctx, user)
+	// Users who logged in with single sign-on remain exempt from Envoy MFA enrollment
+	if refresh.SSO {
+		claims.SetSingleSignOn(user)
+	}
+	return claims, nil
 }
 
 func (s *Server) reauthenticateAPIKey(c *gin.Context, keyID ulid.ULID) (_ *auth.Claims, err error) {
@@ -342,6 +449,12 @@ func (s *Server) reauthenticateAPIKey(c *gin.Context, keyID ulid.ULID) (_ *auth.
 		return nil, err
 	}
 
+	// Expired keys and keys used from disallowed networks cannot be reauthenticated
+	if err = verifyAPIKeyRestrictions(c, apikey, time.Now()); err != nil {
+		c.JSON(http.StatusForbidden, api.Error(err))
+		return nil, err
+	}
+
 	if err = s.store.SetAPIKeyLastSeen(ctx, apikey.ID, time.Now()); err != nil {
 		log := logger.Tracing(ctx)
 		log.Warn().Err(err).Msg("unable to update api key last seen timestamp")
@@ -354,11 +467,116 @@ func (s *Server) reauthenticateAPIKey(c *gin.Context, keyID ulid.ULID) (_ *auth.
 	return auth.NewClaims(ctx, apikey)
 }
 
+// RefreshClaims implements auth.ClaimsRefresher so that the authentication middleware
+// can refresh expired access tokens using the refresh token cookie. As with the
+// Reauthenticate endpoint, the claims are loaded from the database so that changes to
+// the permissions of the user or api key take effect when the access token is refreshed.
+func (s *Server) RefreshClaims(ctx context.Context, refresh *auth.Claims) (claims *auth.Claims, err error) {
+	var (
+		sub   auth.SubjectType
+		subID ulid.ULID
+	)
+
+	if sub, subID, err = refresh.SubjectID(); err != nil {
+		return nil, err
+	}
+
+	switch sub {
+	case auth.SubjectUser:
+		var user *models.User
+		if user, err = s.store.RetrieveUser(ctx, subID); err != nil {
+			return nil, err
+		}
+
+		if claims, err = auth.NewClaims(ctx, user); err != nil {
+			return nil, err
+		}
+
+		// The refresh token cannot be used if its session has been revoked
+		if err = s.refreshSession(ctx, user.ID, refresh, claims); err != nil {
+			return nil, err
+		}
+
+		if err = s.store.SetUserLastLogin(ctx, user.ID, time.Now()); err != nil {
+			log := logger.Tracing(ctx)
+			log.Warn().Err(err).Msg("unable to update user last login timestamp")
+		}
+
+		// Users who logged in with single sign-on remain exempt from Envoy MFA enrollment
+		if refresh.SSO {
+			claims.SetSingleSignOn(user)
+		}
+		return claims, nil
+	case auth.SubjectAPIKey:
+		var apikey *models.APIKey
+		if apikey, err = s.store.RetrieveAPIKey(ctx, subID); err != nil {
+			return nil, err
+		}
+
+		// NOTE: the network restrictions are verified by the middleware from the claims
+		if apikey.Expired(time.Now()) {
+			return nil, auth.ErrAPIKeyExpired
+		}
+
+		// Refresh tokens issued before the secret was rotated cannot be used so that
+		// clients must authenticate with the new (or previous, during the grace period)
+		// secret. Token timestamps are truncated to the second so the comparison is too.
+		if apikey.SecretRotated.Valid {
+			if refresh.IssuedAt == nil || refresh.IssuedAt.Before(apikey.SecretRotated.Time.Truncate(time.Second)) {
+				return nil, auth.ErrSecretRotated
+			}
+		}
+
+		if err = s.store.SetAPIKeyLastSeen(ctx, apikey.ID, time.Now()); err != nil {
+			log := logger.Tracing(ctx)
+			log.Warn().Err(err).Msg("unable to update api key last seen timestamp")
+		}
+
+		return auth.NewClaims(ctx, apikey)
+	default:
+		return nil, fmt.Errorf("unknown subject type %c", sub)
+	}
+}
+
+// Verifies the client secret against the api key secret; if the key was recently
+// rotated, the previous secret is also accepted until its grace period ends.
+func verifyAPIKeySecret(apikey *models.APIKey, secret string, now time.Time) error {
+	verified, err := passwords.VerifyDerivedKey(apikey.Secret, secret)
+	if err == nil && verified {
+		return nil
+	}
+
+	if apikey.PreviousSecretValid(now) {
+		if verified, err = passwords.VerifyDerivedKey(apikey.PreviousSecret.String, secret); err == nil && verified {
+			return nil
+		}
+	}
+
+	if err == nil {
+		err = ErrInvalidCredentials
+	}
+	return err
+}
+
+// Checks that the api key has not expired and that the request originates from one
+// of the networks the api key is allowed to be used from.
+func verifyAPIKeyRestrictions(c *gin.Context, apikey *models.APIKey, now time.Time) error {
+	if apikey.Expired(now) {
+		return auth.ErrAPIKeyExpired
+	}
+
+	if !apikey.AllowedNetworks.Allows(c.ClientIP()) {
+		return auth.ErrNetworkNotAllowed
+	}
+	return nil
+}
+
 func (s *Server) ChangePassword(c *gin.Context) {
 	var (
 		err         error
 		claims      *auth.Claims
 		in          *api.UserPassword
+		user        *models.User
 		userID      ulid.ULID
 		subjectType auth.SubjectType
 		derivedKey  string
@@ -400,6 +618,18 @@ func (s *Server) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	// The new password cannot be the same as the current password
+	if user, err = s.store.RetrieveUser(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not initiate change user password request"))
+		return
+	}
+
+	if reused, _ := passwords.VerifyDerivedKey(user.Password, in.Password); reused {
+		c.JSON(http.StatusBadRequest, api.Error(passwords.ErrPasswordReused))
+		return
+	}
+
 	// Create derived key from requested password reset
 	if derivedKey, err = passwords.CreateDerivedKey(in.Password); err != nil {
 		c.Error(err)
@@ -414,6 +644,10 @@ func (s *Server) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	// Revoke the other sessions of the user now that the password has changed
+	keep, _ := ulid.Parse(claims.Session)
+	s.revokeSessions(c.Request.Context(), userID, keep)
+
 	// Create template scene for rendering information about the user
 	data := scene.New(c)
 	data["Success"] = true
@@ -447,6 +681,11 @@ func (s *Server) ForgotPassword(c *gin.Context) {
 		return
 	}
 
+	if s.conf.Web.Auth.DisablePasswordLogin {
+		c.AbortWithStatusJSON(http.StatusForbidden, api.Error(ErrPasswordLogin))
+		return
+	}
+
 	in = &api.ResetPasswordRequest{}
 	if err = c.BindJSON(in); err != nil {
 		s.Error(c, errors.New("could not parse reset password request"))
@@ -490,6 +729,7 @@ func (s *Server) ResetPassword(c *gin.Context) {
 		err        error
 		in         *api.ResetPasswordChangeRequest
 		link       *models.ResetPasswordLink
+		user       *models.User
 	)
 
 	// We do not allow JSON API requests to this endpoint. Returning a 406 error
@@ -542,6 +782,17 @@ func (s *Server) ResetPassword(c *gin.Context) {
 		}
 	}
 
+	// The new password cannot be the same as the current password
+	if user, err = s.store.RetrieveUser(c.Request.Context(), link.UserID); err != nil {
+		s.Error(c, err)
+		return
+	}
+
+	if reused, _ := passwords.VerifyDerivedKey(user.Password, in.Password); reused {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(passwords.ErrPasswordReused))
+		return
+	}
+
 	// Create derived key from requested password reset
 	if derivedKey, err = passwords.CreateDerivedKey(in.Password); err != nil {
 		s.Error(c, err)
@@ -554,6 +805,9 @@ func (s *Server) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	// Revoke all sessions of the user since the password may have been compromised
+	s.revokeSessions(c.Request.Context(), link.UserID, ulid.Null)
+
 	// Now that the password has been changed, delete the ResetPasswordLink record
 	if err = s.store.DeleteResetPasswordLink(c.Request.Context(), link.ID); err != nil {
 		// Do not return an error if we could not delete the record, just log it.
@@ -14,13 +14,18 @@ import (
 
 type Claims struct {
 	jwt.RegisteredClaims
-	ClientID     string   `json:"clientID,omitempty"`
-	Name         string   `json:"name,omitempty"`
-	Email        string   `json:"email,omitempty"`
-	Gravatar     string   `json:"gravatar,omitempty"`
-	Organization string   `json:"org,omitempty"`
-	Role         string   `json:"role,omitempty"`
-	Permissions  []string `json:"permissions,omitempty"`
+	ClientID     string           `json:"clientID,omitempty"`
+	Name         string           `json:"name,omitempty"`
+	Email        string           `json:"email,omitempty"`
+	Gravatar     string           `json:"gravatar,omitempty"`
+	Organization string           `json:"org,omitempty"`
+	Role         string           `json:"role,omitempty"`
+	Permissions  []string         `json:"permissions,omitempty"`
+	MFAEnroll    bool             `json:"mfaEnroll,omitempty"`
+	SSO          bool             `json:"sso,omitempty"`
+	Session      string           `json:"sid,omitempty"`
+	Networks     []string         `json:"networks,omitempty"`
+	KeyExpires   *jwt.NumericDate `json:"kexp,omitempty"`
 }
 
 type SubjectType rune
@@ -60,15 +65,38 @@ func NewClaimsForUser(ctx context.Context, user *models.User) (claims *Claims, e
 		return nil, err
 	}
 
+	// If the role requires MFA and the user has not enrolled, the user is not granted
+	// any permissions and can only access their profile to complete MFA enrollment.
+	if role.RequireMFA && !user.MFAEnabled() {
+		claims.Permissions = nil
+		claims.MFAEnroll = true
+	}
+
 	claims.Role = role.Title
 	claims.SetSubjectID(SubjectUser, user.ID)
 	return claims, nil
 }
 
+// SetSingleSignOn marks the claims as issued for a user who logged in with single
+// sign-on. Multi-factor authentication is the responsibility of the identity provider
+// so the user is not required to enroll in multi-factor authentication with Envoy.
+func (c *Claims) SetSingleSignOn(user *models.User) {
+	c.SSO = true
+	if c.MFAEnroll {
+		c.MFAEnroll = false
+		c.Permissions = user.Permissions()
+	}
+}
+
 func NewClaimsForAPIClient(ctx context.Context, key *models.APIKey) (claims *Claims, err error) {
 	claims = &Claims{
 		ClientID:    key.ClientID,
 		Permissions: key.Permissions(),
+		Networks:    key.AllowedNetworks,
+	}
+
+	if key.Expires.Valid {
+		claims.KeyExpires = jwt.NewNumericDate(key.Expires.Time)
 	}
 
 	claims.SetSubjectID(SubjectAPIKey, key.ID)
@@ -85,6 +113,20 @@ func NewClaimsForSunrise(ctx context.Context, model *models.Sunrise) (claims *Cl
 	return claims, nil
 }
 
+// VerifyAPIKey checks the restrictions placed on the api key the claims were issued
+// for: the key must not have expired and the request must originate from one of the
+// networks the key is allowed to be used from (if any are specified).
+func (c Claims) VerifyAPIKey(ip string, now time.Time) error {
+	if c.KeyExpires != nil && !now.Before(c.KeyExpires.Time) {
+		return ErrAPIKeyExpired
+	}
+
+	if !models.AllowedNetworks(c.Networks).Allows(ip) {
+		return ErrNetworkNotAllowed
+	}
+	return nil
+}
+
 func (c *Claims) SetSubjectID(sub SubjectType, id ulid.ULID) {
 	c.Subject = fmt.Sprintf("%c%s", sub, id)
 }
@@ -51,6 +51,30 @@ func TestNewClaims(t *testing.T) {
 		require.Equal(t, expected, actual, "created claims did not match expectation")
 	})
 
+	t.Run("UserMFARequired", func(t *testing.T) {
+		model := &models.User{
+			Model: models.Model{
+				ID: ulid.MustParse("01HVEH4E88XMYDXFAE4Y48CE9F"),
+			},
+			Email:  "carlos@example.com",
+			RoleID: 2,
+		}
+		model.SetRole(&models.Role{ID: 2, Title: "editor", RequireMFA: true})
+		model.SetPermissions([]string{"foo:manage", "foo:view"})
+
+		actual, err := NewClaims(ctx, model)
+		require.NoError(t, err, "could not create claims for user")
+		require.True(t, actual.MFAEnroll, "expected user to be required to enroll in mfa")
+		require.Empty(t, actual.Permissions, "expected no permissions until user enrolls in mfa")
+
+		model.MFASecret = sql.NullString{Valid: true, String: "JBSWY3DPEHPK3PXP"}
+		model.MFAEnrolled = sql.NullTime{Valid: true, Time: time.Now()}
+		actual, err = NewClaims(ctx, model)
+		require.NoError(t, err, "could not create claims for user")
+		require.False(t, actual.MFAEnroll, "expected enrolled user to not be required to enroll")
+		require.Equal(t, []string{"foo:manage", "foo:view"}, actual.Permissions)
+	})
+
 	t.Run("APIKey", func(t *testing.T) {
 		model := &models.APIKey{
 			Model: models.Model{
@@ -73,6 +97,23 @@ func TestNewClaims(t *testing.T) {
 		require.Equal(t, expected, actual, "created claims did not match expectation")
 	})
 
+	t.Run("RestrictedAPIKey", func(t *testing.T) {
+		expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		model := &models.APIKey{
+			Model: models.Model{
+				ID: ulid.MustParse("01HVEH4E88XMYDXFAE4Y48CE9F"),
+			},
+			ClientID:        "abc1234",
+			Expires:         sql.NullTime{Valid: true, Time: expires},
+			AllowedNetworks: models.AllowedNetworks{"10.0.0.0/8"},
+		}
+
+		actual, err := NewClaims(ctx, model)
+		require.NoError(t, err, "could not create claims for api key")
+		require.Equal(t, []string{"10.0.0.0/8"}, actual.Networks)
+		require.Equal(t, jwt.NewNumericDate(expires), actual.KeyExpires)
+	})
+
 	t.Run("Invalid", func(t *testing.T) {
 		claims, err := NewClaims(ctx, "foo")
 		require.EqualError(t, err, "unknown model type string: cannot create claims")
@@ -169,6 +210,20 @@ func TestSubjectType(t *testing.T) {
 	})
 }
 
+func TestClaimsVerifyAPIKey(t *testing.T) {
+	now := time.Now()
+
+	claims := &Claims{}
+	require.NoError(t, claims.VerifyAPIKey("203.0.113.42", now), "unrestricted keys should be allowed")
+
+	claims.KeyExpires = jwt.NewNumericDate(now.Add(time.Hour))
+	claims.Networks = []string{"10.0.0.0/8", "2001:db8::/32"}
+	require.NoError(t, claims.VerifyAPIKey("10.1.2.3", now))
+	require.NoError(t, claims.VerifyAPIKey("2001:db8::1", now))
+	require.ErrorIs(t, claims.VerifyAPIKey("203.0.113.42", now), ErrNetworkNotAllowed)
+	require.ErrorIs(t, claims.VerifyAPIKey("10.1.2.3", now.Add(2*time.Hour)), ErrAPIKeyExpired)
+}
+
 func TestClaimsHasPermission(t *testing.T) {
 	claims := &Claims{
 		Permissions: []string{"foo:manage", "foo:view", "foo:delete", "bar:view"},
@@ -5,6 +5,7 @@ import "errors"
 // Authentication Errors
 var (
 	ErrUnknownSigningKey = errors.New("unknown signing key")
+	ErrSigningKeyExpired = errors.New("signing key has expired")
 	ErrNoKeyID           = errors.New("token does not have kid in header")
 	ErrInvalidKeyID      = errors.New("invalid key id")
 	ErrUnparsableClaims  = errors.New("could not parse or verify claims")
@@ -22,4 +23,8 @@ var (
 	ErrNoRefreshToken    = errors.New("cannot reauthenticate no refresh token in request")
 	ErrNotAccepted       = errors.New("the accepted formats are not offered by the server")
 	ErrNoSubject         = errors.New("no subject found on the request context claims")
+	ErrAPIKeyExpired     = errors.New("api key has expired")
+	ErrNetworkNotAllowed = errors.New("api key cannot be used from this network")
+	ErrSessionRevoked    = errors.New("session has been revoked or has expired")
+	ErrSecretRotated     = errors.New("api key secret was rotated after the token was issued")
 )
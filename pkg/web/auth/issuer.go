@@ -30,16 +30,26 @@ var (
 	entropyMu     sync.Mutex
 )
 
+// ClaimsIssuer creates and verifies the access and refresh tokens of the node. Tokens
+// are signed by the current key, which is either a static key loaded from the
+// configuration, a volatile key generated at startup, or (once the issuer is synced)
+// the signing key stored in the database that is shared by every replica. Tokens
+// signed by any published key that has not expired can be verified.
 type ClaimsIssuer struct {
+	sync.RWMutex
 	conf            config.AuthConfig
 	keyID           ulid.ULID
 	key             *rsa.PrivateKey
 	publicKeys      map[ulid.ULID]*rsa.PublicKey
+	expires         map[ulid.ULID]time.Time
+	staticKeyID     ulid.ULID
+	staticKey       *rsa.PrivateKey
+	staticKeys      map[ulid.ULID]*rsa.PublicKey
 	refreshAudience string
 }
 
 func NewIssuer(conf config.AuthConfig) (_ *ClaimsIssuer, err error) {
-	issuer := &ClaimsIssuer{conf: conf, publicKeys: make(map[ulid.ULID]*rsa.PublicKey, len(conf.Keys))}
+	issuer := &ClaimsIssuer{conf: conf, staticKeys: make(map[ulid.ULID]*rsa.PublicKey, len(conf.Keys))}
 
 	for kid, path := range conf.Keys {
 		var keyID ulid.ULID
@@ -57,23 +67,28 @@ func NewIssuer(conf config.AuthConfig) (_ *ClaimsIssuer, err error) {
 			return nil, fmt.Errorf("could not parse private key %s: %w", path, err)
 		}
 
-		issuer.publicKeys[keyID] = &key.PublicKey
+		issuer.staticKeys[keyID] = &key.PublicKey
 
-		if issuer.key == nil || keyID.Time() > issuer.keyID.Time() {
-			issuer.key = key
-			issuer.keyID = keyID
+		if issuer.staticKey == nil || keyID.Time() > issuer.staticKeyID.Time() {
+			issuer.staticKey = key
+			issuer.staticKeyID = keyID
 		}
 	}
 
 	// If we have no keys, generate one for use (e.g. for testing or simple deployment)
-	if issuer.key == nil {
-		if issuer.key, err = rsa.GenerateKey(rand.Reader, 4096); err != nil {
+	if issuer.staticKey == nil {
+		if issuer.staticKey, err = rsa.GenerateKey(rand.Reader, keySize); err != nil {
 			return nil, err
 		}
 
-		issuer.keyID = ulid.MustNew(ulid.Now(), entropy)
-		issuer.publicKeys[issuer.keyID] = &issuer.key.PublicKey
-		log.Warn().Str("keyID", issuer.keyID.String()).Msg("generated volatile claims issuer rsa key")
+		issuer.staticKeyID = ulid.MustNew(ulid.Now(), entropy)
+		issuer.staticKeys[issuer.staticKeyID] = &issuer.staticKey.PublicKey
+		log.Warn().Str("keyID", issuer.staticKeyID.String()).Msg("generated volatile claims issuer rsa key")
+	}
+
+	// Until the issuer is synced with the database, the static keys are used.
+	if err = issuer.load(nil, time.Now()); err != nil {
+		return nil, err
 	}
 
 	return issuer, nil
@@ -115,8 +130,12 @@ func (tm *ClaimsIssuer) Parse(tks string) (claims *Claims, err error) {
 }
 
 func (tm *ClaimsIssuer) Sign(token *jwt.Token) (tks string, err error) {
-	token.Header["kid"] = tm.keyID.String()
-	return token.SignedString(tm.key)
+	tm.RLock()
+	keyID, key := tm.keyID, tm.key
+	tm.RUnlock()
+
+	token.Header["kid"] = keyID.String()
+	return token.SignedString(key)
 }
 
 func (tm *ClaimsIssuer) CreateAccessToken(claims *Claims) (_ *jwt.Token, err error) {
@@ -145,6 +164,8 @@ func (tm *ClaimsIssuer) CreateRefreshToken(accessToken *jwt.Token) (_ *jwt.Token
 	// Add the refresh audience to the audience claims
 	audience := append(accessClaims.Audience, tm.RefreshAudience())
 
+	// NOTE: the single sign-on flag and session are retained so that they survive
+	// reauthentication
 	claims := &Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        accessClaims.ID,
@@ -155,6 +176,8 @@ func (tm *ClaimsIssuer) CreateRefreshToken(accessToken *jwt.Token) (_ *jwt.Token
 			NotBefore: jwt.NewNumericDate(accessClaims.ExpiresAt.Add(tm.conf.TokenOverlap)),
 			ExpiresAt: jwt.NewNumericDate(accessClaims.IssuedAt.Add(tm.conf.RefreshTokenTTL)),
 		},
+		SSO:     accessClaims.SSO,
+		Session: accessClaims.Session,
 	}
 
 	return jwt.NewWithClaims(signingMethod, claims), nil
@@ -185,11 +208,20 @@ func (tm *ClaimsIssuer) CreateTokens(claims *Claims) (signedAccessToken, signedR
 
 // Keys returns the map of ulid to public key for use externally.
 func (tm *ClaimsIssuer) Keys() map[ulid.ULID]*rsa.PublicKey {
-	return tm.publicKeys
+	tm.RLock()
+	defer tm.RUnlock()
+
+	keys := make(map[ulid.ULID]*rsa.PublicKey, len(tm.publicKeys))
+	for keyID, key := range tm.publicKeys {
+		keys[keyID] = key
+	}
+	return keys
 }
 
 // CurrentKey returns the ulid of the current key being used to sign tokens.
 func (tm *ClaimsIssuer) CurrentKey() ulid.ULID {
+	tm.RLock()
+	defer tm.RUnlock()
 	return tm.keyID
 }
 
@@ -234,9 +266,17 @@ func (tm *ClaimsIssuer) keyFunc(token *jwt.Token) (key interface{}, err error) {
 	}
 
 	// Fetch the key from the list of managed keys
+	tm.RLock()
+	defer tm.RUnlock()
+
 	if key, ok = tm.publicKeys[keyID]; !ok {
 		return nil, ErrUnknownSigningKey
 	}
+
+	// Keys stored in the database are not used to verify tokens once they expire
+	if expires, ok := tm.expires[keyID]; ok && !expires.After(time.Now()) {
+		return nil, ErrSigningKeyExpired
+	}
 	return key, nil
 }
 
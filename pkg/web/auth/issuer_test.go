@@ -87,6 +87,22 @@ func (s *TokenTestSuite) TestTokenManager() {
 	require.True(rc.NotBefore.After(now))
 	require.True(rc.ExpiresAt.After(rc.NotBefore.Time))
 	require.Empty(rc.Email)
+	require.False(rc.SSO)
+
+	// The single sign-on flag is retained by the refresh token for reauthentication
+	ssoToken, err := tm.CreateAccessToken(&auth.Claims{Email: "kate@example.com", SSO: true})
+	require.NoError(err, "could not create sso access token")
+	ssoRefresh, err := tm.CreateRefreshToken(ssoToken)
+	require.NoError(err, "could not create sso refresh token")
+	require.True(ssoRefresh.Claims.(*auth.Claims).SSO, "expected refresh token to retain sso flag")
+
+	// The session is retained by the refresh token so that it can be refreshed
+	sessionID := ulid.MakeSecure().String()
+	sessionToken, err := tm.CreateAccessToken(&auth.Claims{Email: "kate@example.com", Session: sessionID})
+	require.NoError(err, "could not create session access token")
+	sessionRefresh, err := tm.CreateRefreshToken(sessionToken)
+	require.NoError(err, "could not create session refresh token")
+	require.Equal(sessionID, sessionRefresh.Claims.(*auth.Claims).Session, "expected refresh token to retain session")
 
 	// Verify relative nbf and exp claims of access and refresh tokens
 	require.True(ac.IssuedAt.Equal(rc.IssuedAt.Time), "access and refresh tokens do not have same iss timestamp")
@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/auth/oidc"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+	"go.rtnl.ai/ulid"
+)
+
+const (
+	jwksPath        = "/.well-known/jwks.json"
+	DefaultJWKSURI  = "http://localhost:8000/.well-known/jwks.json"
+	KeySyncInterval = 5 * time.Minute
+)
+
+var (
+	// The size of the generated RSA signing keys.
+	keySize = 4096
+
+	// A new signing key is created this long before the current key stops signing
+	// tokens; since the issuer is synced every KeySyncInterval, every replica publishes
+	// the new key before it is used to sign tokens.
+	keyRotationLead = 3 * KeySyncInterval
+)
+
+// SigningKeyStore persists the signing keys shared by every replica of the node.
+type SigningKeyStore interface {
+	ListSigningKeys(context.Context) ([]*models.SigningKey, error)
+	CreateSigningKey(context.Context, *models.SigningKey) error
+	DeleteExpiredSigningKeys(context.Context) (int64, error)
+}
+
+// Sync the signing keys of the issuer with the keys stored in the database. Expired
+// keys are deleted and, if the most recent key is due to be rotated, the next signing
+// key is generated and stored so that it can be published before it is used. The
+// current key is the most recent stored key whose signing period has started; if there
+// is no such key the static (or volatile) key continues to be used. Static keys are
+// always published. Sync should be called every KeySyncInterval.
+func (tm *ClaimsIssuer) Sync(ctx context.Context, db SigningKeyStore) (err error) {
+	if _, err = db.DeleteExpiredSigningKeys(ctx); err != nil {
+		return fmt.Errorf("could not delete expired signing keys: %w", err)
+	}
+
+	var keys []*models.SigningKey
+	if keys, err = db.ListSigningKeys(ctx); err != nil {
+		return fmt.Errorf("could not list signing keys: %w", err)
+	}
+
+	slices.SortFunc(keys, func(a, b *models.SigningKey) int {
+		return b.NotBefore.Compare(a.NotBefore)
+	})
+
+	now := time.Now()
+	if len(keys) == 0 || keys[0].NotAfter.Sub(now) < keyRotationLead {
+		var key *models.SigningKey
+		if key, err = tm.nextSigningKey(keys, now); err != nil {
+			return err
+		}
+
+		if err = db.CreateSigningKey(ctx, key); err != nil {
+			return fmt.Errorf("could not store signing key: %w", err)
+		}
+
+		log.Info().Str("keyID", key.ID.String()).Time("not_before", key.NotBefore).Msg("generated claims issuer signing key")
+		keys = append([]*models.SigningKey{key}, keys...)
+	}
+
+	return tm.load(keys, now)
+}
+
+// Generates the signing key that follows the most recent key; if no key is currently
+// signing tokens the new key is used immediately. The key is published until every
+// token it could have signed has expired.
+func (tm *ClaimsIssuer) nextSigningKey(keys []*models.SigningKey, now time.Time) (_ *models.SigningKey, err error) {
+	var key *rsa.PrivateKey
+	if key, err = rsa.GenerateKey(rand.Reader, keySize); err != nil {
+		return nil, fmt.Errorf("could not generate signing key: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err = pem.Encode(buf, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}); err != nil {
+		return nil, fmt.Errorf("could not encode signing key: %w", err)
+	}
+
+	out := &models.SigningKey{
+		Model:      models.Model{ID: newULID()},
+		PrivateKey: buf.Bytes(),
+		NotBefore:  now,
+	}
+
+	if len(keys) > 0 && keys[0].NotAfter.After(now) {
+		out.NotBefore = keys[0].NotAfter
+	}
+
+	out.NotAfter = out.NotBefore.Add(tm.conf.KeyRotation)
+	out.Expires = out.NotAfter.Add(max(tm.conf.AccessTokenTTL, tm.conf.RefreshTokenTTL))
+	return out, nil
+}
+
+// Replaces the published keys with the static keys and the specified signing keys,
+// selecting the current key to sign tokens with. Keys must be sorted most recent first.
+func (tm *ClaimsIssuer) load(keys []*models.SigningKey, now time.Time) (err error) {
+	publicKeys := make(map[ulid.ULID]*rsa.PublicKey, len(tm.staticKeys)+len(keys))
+	expires := make(map[ulid.ULID]time.Time, len(keys))
+	for keyID, key := range tm.staticKeys {
+		publicKeys[keyID] = key
+	}
+
+	keyID, key := tm.staticKeyID, tm.staticKey
+	current := false
+
+	for _, sk := range keys {
+		var pk *rsa.PrivateKey
+		if pk, err = jwt.ParseRSAPrivateKeyFromPEM(sk.PrivateKey); err != nil {
+			return fmt.Errorf("could not parse signing key %s: %w", sk.ID, err)
+		}
+
+		publicKeys[sk.ID] = &pk.PublicKey
+		expires[sk.ID] = sk.Expires
+
+		if !current && !sk.NotBefore.After(now) && sk.NotAfter.After(now) {
+			keyID, key, current = sk.ID, pk, true
+		}
+	}
+
+	tm.Lock()
+	defer tm.Unlock()
+	tm.keyID, tm.key = keyID, key
+	tm.publicKeys, tm.expires = publicKeys, expires
+	return nil
+}
+
+// JWKS returns the public keys that verify the tokens issued by the claims issuer as a
+// JSON web key set (RFC 7517), including keys published before they sign tokens.
+func (tm *ClaimsIssuer) JWKS() *oidc.JWKS {
+	tm.RLock()
+	defer tm.RUnlock()
+
+	now := time.Now()
+	jwks := &oidc.JWKS{Keys: make([]oidc.JWK, 0, len(tm.publicKeys))}
+	for keyID, key := range tm.publicKeys {
+		if expires, ok := tm.expires[keyID]; ok && !expires.After(now) {
+			continue
+		}
+
+		jwks.Keys = append(jwks.Keys, oidc.JWK{
+			KeyID: keyID.String(),
+			Type:  "RSA",
+			Use:   "sig",
+			Alg:   signingMethod.Alg(),
+			N:     base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:     base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+
+	slices.SortFunc(jwks.Keys, func(a, b oidc.JWK) int {
+		return bytes.Compare([]byte(a.KeyID), []byte(b.KeyID))
+	})
+	return jwks
+}
+
+// JWKSURI returns the url of the JSON web key set relative to the issuer.
+func (tm *ClaimsIssuer) JWKSURI() string {
+	if tm.conf.Issuer != "" {
+		if uri, err := url.Parse(tm.conf.Issuer); err == nil {
+			return uri.ResolveReference(&url.URL{Path: jwksPath}).String()
+		}
+	}
+	return DefaultJWKSURI
+}
+
+// Issuer returns the value of the iss claim of the tokens created by the issuer.
+func (tm *ClaimsIssuer) Issuer() string {
+	return tm.conf.Issuer
+}
+
+// SigningAlgorithm returns the algorithm used to sign tokens.
+func (tm *ClaimsIssuer) SigningAlgorithm() string {
+	return signingMethod.Alg()
+}
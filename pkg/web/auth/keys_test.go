@@ -0,0 +1,183 @@
+package auth_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"go.rtnl.ai/ulid"
+)
+
+func TestSync(t *testing.T) {
+	conf := func(kid string) config.AuthConfig {
+		return config.AuthConfig{
+			Keys:            map[string]string{kid: "testdata/" + kid + ".pem"},
+			Audience:        "http://localhost:3000",
+			Issuer:          "http://localhost:3001",
+			AccessTokenTTL:  1 * time.Hour,
+			RefreshTokenTTL: 2 * time.Hour,
+			TokenOverlap:    -15 * time.Minute,
+			KeyRotation:     720 * time.Hour,
+		}
+	}
+
+	pem, err := os.ReadFile("testdata/01GE62EXXR0X0561XD53RDFBQJ.pem")
+	require.NoError(t, err, "could not read testdata key")
+
+	claims := func() *auth.Claims {
+		return &auth.Claims{Email: "kate@example.com", Name: "Kate Holland"}
+	}
+
+	t.Run("Replicas", func(t *testing.T) {
+		db := &signingKeys{}
+
+		alice, err := auth.NewIssuer(conf("01GE6191AQTGMCJ9BN0QC3CCVG"))
+		require.NoError(t, err, "could not create issuer")
+
+		bob, err := auth.NewIssuer(conf("01GE62EXXR0X0561XD53RDFBQJ"))
+		require.NoError(t, err, "could not create issuer")
+
+		// The first replica generates the signing key for every replica
+		require.NoError(t, alice.Sync(context.Background(), db), "could not sync issuer")
+		require.Len(t, db.keys, 1, "expected a signing key to be generated")
+
+		key := db.keys[0]
+		require.Equal(t, key.ID, alice.CurrentKey())
+		require.WithinDuration(t, time.Now(), key.NotBefore, time.Minute)
+		require.Equal(t, key.NotBefore.Add(720*time.Hour), key.NotAfter)
+		require.Equal(t, key.NotAfter.Add(2*time.Hour), key.Expires)
+
+		require.NoError(t, bob.Sync(context.Background(), db), "could not sync issuer")
+		require.Len(t, db.keys, 1, "expected the signing key to be shared")
+		require.Equal(t, key.ID, bob.CurrentKey())
+
+		// Tokens signed by either replica are verified by the other
+		atks, _, err := alice.CreateTokens(claims())
+		require.NoError(t, err, "could not create tokens")
+
+		_, err = bob.Verify(atks)
+		require.NoError(t, err, "expected the token to be verified by the other replica")
+
+		// The signing key and the static key are published
+		jwks := alice.JWKS()
+		require.Len(t, jwks.Keys, 2)
+		for _, jwk := range jwks.Keys {
+			require.Equal(t, "RSA", jwk.Type)
+			require.Equal(t, "sig", jwk.Use)
+			require.Equal(t, "RS256", jwk.Alg)
+			require.NotEmpty(t, jwk.N)
+			require.Equal(t, "AQAB", jwk.E)
+		}
+
+		keys, err := jwks.PublicKeys()
+		require.NoError(t, err, "could not parse published keys")
+		require.Contains(t, keys, key.ID.String())
+		require.Contains(t, keys, "01GE6191AQTGMCJ9BN0QC3CCVG")
+	})
+
+	t.Run("Rotate", func(t *testing.T) {
+		now := time.Now()
+		current := &models.SigningKey{
+			Model:      models.Model{ID: ulid.MakeSecure()},
+			PrivateKey: pem,
+			NotBefore:  now.Add(-720 * time.Hour).Add(10 * time.Minute),
+			NotAfter:   now.Add(10 * time.Minute),
+			Expires:    now.Add(130 * time.Minute),
+		}
+		db := &signingKeys{keys: []*models.SigningKey{current}}
+
+		issuer, err := auth.NewIssuer(conf("01GE6191AQTGMCJ9BN0QC3CCVG"))
+		require.NoError(t, err, "could not create issuer")
+		require.NoError(t, issuer.Sync(context.Background(), db), "could not sync issuer")
+
+		// The next key is published but is not used until the current key is rotated
+		require.Len(t, db.keys, 2, "expected the next signing key to be generated")
+		next := db.keys[1]
+		require.Equal(t, current.NotAfter, next.NotBefore)
+		require.Equal(t, current.ID, issuer.CurrentKey())
+		require.Len(t, issuer.JWKS().Keys, 3)
+
+		// Syncing again does not generate another key
+		require.NoError(t, issuer.Sync(context.Background(), db), "could not sync issuer")
+		require.Len(t, db.keys, 2)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		now := time.Now()
+		expired := &models.SigningKey{
+			Model:      models.Model{ID: ulid.MakeSecure()},
+			PrivateKey: pem,
+			NotBefore:  now.Add(-4 * time.Hour),
+			NotAfter:   now.Add(-3 * time.Hour),
+			Expires:    now.Add(-1 * time.Minute),
+		}
+
+		// Sign a token with the key before it expired
+		issuer, err := auth.NewIssuer(conf("01GE6191AQTGMCJ9BN0QC3CCVG"))
+		require.NoError(t, err, "could not create issuer")
+
+		token, err := issuer.CreateAccessToken(claims())
+		require.NoError(t, err, "could not create access token")
+
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		require.NoError(t, err, "could not parse testdata key")
+
+		token.Header["kid"] = expired.ID.String()
+		tks, err := token.SignedString(key)
+		require.NoError(t, err, "could not sign token")
+
+		// The store has not deleted the expired key yet
+		db := &signingKeys{keys: []*models.SigningKey{expired}, keepExpired: true}
+		require.NoError(t, issuer.Sync(context.Background(), db), "could not sync issuer")
+		require.NotEqual(t, expired.ID, issuer.CurrentKey())
+
+		_, err = issuer.Verify(tks)
+		require.ErrorIs(t, err, auth.ErrSigningKeyExpired)
+
+		for _, jwk := range issuer.JWKS().Keys {
+			require.NotEqual(t, expired.ID.String(), jwk.KeyID, "expected the expired key not to be published")
+		}
+	})
+}
+
+// An in-memory signing key store shared by the issuers in the tests.
+type signingKeys struct {
+	keys        []*models.SigningKey
+	keepExpired bool
+}
+
+func (s *signingKeys) ListSigningKeys(context.Context) ([]*models.SigningKey, error) {
+	out := make([]*models.SigningKey, len(s.keys))
+	copy(out, s.keys)
+	return out, nil
+}
+
+func (s *signingKeys) CreateSigningKey(_ context.Context, key *models.SigningKey) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func (s *signingKeys) DeleteExpiredSigningKeys(context.Context) (int64, error) {
+	if s.keepExpired {
+		return 0, nil
+	}
+
+	var deleted int64
+	keys := s.keys[:0]
+	for _, key := range s.keys {
+		if key.Expires.After(time.Now()) {
+			keys = append(keys, key)
+			continue
+		}
+		deleted++
+	}
+	s.keys = keys
+	return deleted, nil
+}
@@ -0,0 +1,75 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+//===========================================================================
+// Recovery Codes
+//===========================================================================
+
+const (
+	NumRecoveryCodes   = 10 // the number of recovery codes generated on enrollment
+	recoveryCodeLength = 16 // the number of characters in a recovery code (excluding separators)
+	recoveryCodeGroup  = 4  // the number of characters between separators
+)
+
+// Unambiguous lowercase alphabet for recovery codes (no 0/o, 1/l/i).
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// GenerateRecoveryCodes creates n random single-use recovery codes formatted as four
+// groups of four characters separated by dashes, e.g. "k7qz-rm2h-tawx-9pcd".
+func GenerateRecoveryCodes(n int) (codes []string, err error) {
+	codes = make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		var code string
+		if code, err = generateRecoveryCode(); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode returns the hex encoded SHA-256 digest of the normalized recovery
+// code; only the digest is stored. Recovery codes have ~79 bits of entropy so a slow
+// key derivation function is not required to protect them.
+func HashRecoveryCode(code string) string {
+	digest := sha256.Sum256([]byte(NormalizeRecoveryCode(code)))
+	return hex.EncodeToString(digest[:])
+}
+
+// NormalizeRecoveryCode removes separators and whitespace and lowercases the code so
+// that it can be compared against the stored recovery code.
+func NormalizeRecoveryCode(code string) string {
+	return strings.ToLower(NormalizeCode(code))
+}
+
+func generateRecoveryCode() (_ string, err error) {
+	// Use rejection sampling to avoid modulo bias when selecting characters.
+	limit := byte(256 - 256%len(recoveryAlphabet))
+	code := make([]byte, 0, recoveryCodeLength)
+	buf := make([]byte, recoveryCodeLength)
+
+	for len(code) < recoveryCodeLength {
+		if _, err = rand.Read(buf); err != nil {
+			return "", fmt.Errorf("could not generate recovery code: %w", err)
+		}
+
+		for _, b := range buf {
+			if b < limit && len(code) < recoveryCodeLength {
+				code = append(code, recoveryAlphabet[int(b)%len(recoveryAlphabet)])
+			}
+		}
+	}
+
+	groups := make([]string, 0, recoveryCodeLength/recoveryCodeGroup)
+	for i := 0; i < recoveryCodeLength; i += recoveryCodeGroup {
+		groups = append(groups, string(code[i:i+recoveryCodeGroup]))
+	}
+	return strings.Join(groups, "-"), nil
+}
@@ -0,0 +1,173 @@
+/*
+Package mfa implements time-based one-time passwords (TOTP) as described in RFC 6238
+for multi-factor authentication of web UI users, along with single-use recovery codes
+that can be used if the user loses access to their authenticator app.
+*/
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+//===========================================================================
+// Time-Based One-Time Passwords
+//===========================================================================
+
+// TOTP constants are the defaults supported by all common authenticator apps.
+const (
+	SecretSize = 20               // 160 bit secret as recommended by RFC 4226
+	Digits     = 6                // the number of digits in a code
+	Period     = 30 * time.Second // the time step of a code
+	Skew       = 1                // the number of time steps before and after now to accept
+	Algorithm  = "SHA1"           // the HMAC algorithm used to generate codes
+)
+
+// Lockout constants throttle guessing of codes; after MaxFailures consecutive invalid
+// codes the user cannot verify any code until the LockoutPeriod has passed.
+const (
+	MaxFailures   = 5
+	LockoutPeriod = 15 * time.Minute
+)
+
+var (
+	b32           = base32.StdEncoding.WithPadding(base32.NoPadding)
+	ErrNoSecret   = errors.New("no totp secret available")
+	ErrBadSecret  = errors.New("could not decode totp secret")
+	ErrBadCode    = errors.New("totp code must be 6 digits")
+	ErrCodeReused = errors.New("totp code has already been used")
+	ErrLocked     = errors.New("too many failed attempts, multi-factor authentication is temporarily locked")
+	digitsModulus = uint32(1_000_000)
+)
+
+// GenerateSecret creates a new random base32 encoded TOTP secret.
+func GenerateSecret() (_ string, err error) {
+	secret := make([]byte, SecretSize)
+	if _, err = rand.Read(secret); err != nil {
+		return "", fmt.Errorf("could not generate totp secret: %w", err)
+	}
+	return b32.EncodeToString(secret), nil
+}
+
+// URI returns the otpauth:// key URI that is encoded into the QR code scanned by the
+// authenticator app to enroll the secret.
+// See: https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func URI(issuer, account, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", Algorithm)
+	params.Set("digits", fmt.Sprintf("%d", Digits))
+	params.Set("period", fmt.Sprintf("%d", int(Period.Seconds())))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: params.Encode(),
+	}
+	return uri.String()
+}
+
+// Code returns the TOTP code for the secret at the specified time.
+func Code(secret string, ts time.Time) (_ string, err error) {
+	var key []byte
+	if key, err = decodeSecret(secret); err != nil {
+		return "", err
+	}
+	return hotp(key, counter(ts)), nil
+}
+
+// Verify returns the time step of the code if it matches the TOTP code of the secret
+// for the time step of the specified time or any time step within the allowed clock
+// skew. Codes for time steps at or before lastStep, the step of the last code accepted
+// for the user, are rejected with ErrCodeReused so that a code cannot be replayed as
+// required by RFC 6238 Section 5.2; a lastStep of 0 accepts any time step.
+func Verify(secret, code string, ts time.Time, lastStep int64) (step int64, ok bool, err error) {
+	if code = NormalizeCode(code); len(code) != Digits {
+		return 0, false, ErrBadCode
+	}
+
+	var key []byte
+	if key, err = decodeSecret(secret); err != nil {
+		return 0, false, err
+	}
+
+	now := counter(ts)
+	for i := -Skew; i <= Skew; i++ {
+		step := now + uint64(i)
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			if int64(step) <= lastStep {
+				return 0, false, ErrCodeReused
+			}
+			return int64(step), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// IsTOTPCode returns true if the code looks like a TOTP code rather than a recovery
+// code (e.g. it is comprised of only the expected number of digits).
+func IsTOTPCode(code string) bool {
+	code = NormalizeCode(code)
+	if len(code) != Digits {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeCode removes whitespace and separators that users may enter with a code.
+func NormalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '\t':
+			return -1
+		default:
+			return r
+		}
+	}, code)
+}
+
+func decodeSecret(secret string) (key []byte, err error) {
+	if secret == "" {
+		return nil, ErrNoSecret
+	}
+
+	if key, err = b32.DecodeString(strings.ToUpper(strings.TrimRight(secret, "="))); err != nil {
+		return nil, ErrBadSecret
+	}
+	return key, nil
+}
+
+func counter(ts time.Time) uint64 {
+	return uint64(ts.Unix()) / uint64(Period.Seconds())
+}
+
+// Implements the HOTP algorithm from RFC 4226 with dynamic truncation.
+func hotp(key []byte, count uint64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, count)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%digitsModulus)
+}
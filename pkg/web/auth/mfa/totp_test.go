@@ -0,0 +1,117 @@
+package mfa_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/auth/mfa"
+)
+
+func TestCode(t *testing.T) {
+	// Test vectors from RFC 6238 Appendix B (SHA1, truncated to 6 digits)
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := []struct {
+		ts       int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for i, tc := range tests {
+		code, err := mfa.Code(secret, time.Unix(tc.ts, 0))
+		require.NoError(t, err, "test case %d failed", i)
+		require.Equal(t, tc.expected, code, "test case %d failed", i)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret, err := mfa.GenerateSecret()
+	require.NoError(t, err, "could not generate secret")
+	require.Len(t, secret, 32, "expected 160 bit base32 encoded secret")
+
+	now := time.Now()
+	code, err := mfa.Code(secret, now)
+	require.NoError(t, err, "could not generate code")
+
+	step, ok, err := mfa.Verify(secret, code, now, 0)
+	require.NoError(t, err)
+	require.True(t, ok, "expected code to be valid now")
+	require.Equal(t, now.Unix()/int64(mfa.Period.Seconds()), step, "expected the time step of the code")
+
+	_, ok, err = mfa.Verify(secret, code[:3]+" "+code[3:], now.Add(mfa.Period), 0)
+	require.NoError(t, err)
+	require.True(t, ok, "expected code to be valid within allowed skew")
+
+	_, ok, err = mfa.Verify(secret, code, now.Add(3*mfa.Period), 0)
+	require.NoError(t, err)
+	require.False(t, ok, "expected code to be invalid outside of allowed skew")
+
+	// Codes at or before the last accepted time step cannot be replayed
+	_, ok, err = mfa.Verify(secret, code, now, step)
+	require.ErrorIs(t, err, mfa.ErrCodeReused)
+	require.False(t, ok, "expected a replayed code to be rejected")
+
+	_, ok, err = mfa.Verify(secret, code, now.Add(mfa.Period), step+1)
+	require.ErrorIs(t, err, mfa.ErrCodeReused)
+	require.False(t, ok, "expected an older code to be rejected after a newer code is accepted")
+
+	next, err := mfa.Code(secret, now.Add(mfa.Period))
+	require.NoError(t, err, "could not generate code")
+	nextStep, ok, err := mfa.Verify(secret, next, now.Add(mfa.Period), step)
+	require.NoError(t, err)
+	require.True(t, ok, "expected the code of the next time step to be valid")
+	require.Equal(t, step+1, nextStep)
+
+	_, _, err = mfa.Verify(secret, "1234", now, 0)
+	require.ErrorIs(t, err, mfa.ErrBadCode)
+
+	_, _, err = mfa.Verify("", "123456", now, 0)
+	require.ErrorIs(t, err, mfa.ErrNoSecret)
+
+	_, _, err = mfa.Verify("!!!!", "123456", now, 0)
+	require.ErrorIs(t, err, mfa.ErrBadSecret)
+}
+
+func TestURI(t *testing.T) {
+	uri := mfa.URI("TRISA Envoy", "admin@example.com", "JBSWY3DPEHPK3PXP")
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/TRISA%20Envoy:admin@example.com?"), "unexpected uri %q", uri)
+	require.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	require.Contains(t, uri, "issuer=TRISA+Envoy")
+	require.Contains(t, uri, "digits=6")
+	require.Contains(t, uri, "period=30")
+}
+
+func TestIsTOTPCode(t *testing.T) {
+	require.True(t, mfa.IsTOTPCode("123456"))
+	require.True(t, mfa.IsTOTPCode("123 456"))
+	require.False(t, mfa.IsTOTPCode("12345"))
+	require.False(t, mfa.IsTOTPCode("k7qz-rm2h-tawx-9pcd"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := mfa.GenerateRecoveryCodes(mfa.NumRecoveryCodes)
+	require.NoError(t, err, "could not generate recovery codes")
+	require.Len(t, codes, mfa.NumRecoveryCodes)
+
+	seen := make(map[string]struct{})
+	for _, code := range codes {
+		require.Len(t, code, 19, "expected four groups of four characters")
+		require.Equal(t, 3, strings.Count(code, "-"))
+		require.False(t, mfa.IsTOTPCode(code))
+
+		_, ok := seen[code]
+		require.False(t, ok, "expected recovery codes to be unique")
+		seen[code] = struct{}{}
+
+		require.Equal(t, strings.ReplaceAll(code, "-", ""), mfa.NormalizeRecoveryCode(strings.ToUpper(code)))
+		require.Equal(t, mfa.HashRecoveryCode(code), mfa.HashRecoveryCode(" "+strings.ToUpper(code)))
+	}
+}
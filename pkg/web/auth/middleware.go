@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -15,6 +17,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/rs/zerolog/log"
 )
 
@@ -34,19 +37,83 @@ const (
 // used to extract the access token from the authorization header
 var bearer = regexp.MustCompile(`^\s*[Bb]earer\s+([a-zA-Z0-9_\-\.]+)\s*$`)
 
-func Authenticate(issuer *ClaimsIssuer) gin.HandlerFunc {
+// UsageRecorder records requests made by api keys for usage statistics.
+type UsageRecorder interface {
+	RecordAPIKeyUsage(ctx context.Context, keyID ulid.ULID, ip string, ts time.Time) error
+}
+
+// ClaimsRefresher loads up-to-date claims for the subject of a verified refresh token
+// so that the authentication middleware can transparently refresh access tokens.
+type ClaimsRefresher interface {
+	RefreshClaims(ctx context.Context, refresh *Claims) (*Claims, error)
+}
+
+// Authenticate verifies the access token in the request and adds the claims to the
+// request context. Requests made with an api key are rejected if the key has expired
+// or if the request does not originate from an allowed network; otherwise the request
+// is recorded using the usage recorder (if not nil). If the access token cookie has
+// expired, the claims are refreshed using the refresh token cookie and new cookies are
+// set on the response (if the refresher is not nil).
+func Authenticate(issuer *ClaimsIssuer, usage UsageRecorder, refresher ClaimsRefresher) gin.HandlerFunc {
+	// Refresh the claims using the refresh token cookie, setting new auth cookies.
+	refreshClaims := func(c *gin.Context) (claims *Claims, err error) {
+		if refresher == nil {
+			return nil, ErrAuthRequired
+		}
+
+		var refreshToken string
+		if refreshToken, err = GetRefreshToken(c); err != nil {
+			return nil, err
+		}
+
+		// The refresh token must be valid (e.g. after its not before time) and must
+		// contain the refresh audience to ensure it isn't an access token.
+		if claims, err = issuer.Verify(refreshToken); err != nil {
+			return nil, err
+		}
+
+		if !claims.VerifyAudience(issuer.RefreshAudience(), true) {
+			return nil, ErrInvalidAudience
+		}
+
+		// Load new claims rather than reusing the claims in the refresh token
+		if claims, err = refresher.RefreshClaims(c.Request.Context(), claims); err != nil {
+			return nil, err
+		}
+
+		var accessToken string
+		if accessToken, refreshToken, err = issuer.CreateTokens(claims); err != nil {
+			return nil, err
+		}
+
+		if err = SetAuthCookies(c, accessToken, refreshToken, issuer.conf.CookieDomain); err != nil {
+			return nil, err
+		}
+
+		log.Debug().Str("subject", claims.Subject).Msg("refreshed expired access token")
+		return claims, nil
+	}
+
 	innerAuthenticate := func(c *gin.Context) (claims *Claims, err error) {
-		// Fetch access token from the request, if no access token is available, reject.
+		// Fetch access token from the request, if no access token is available, attempt
+		// to refresh the claims (the access token cookie expires with the token).
 		var accessToken string
 		if accessToken, err = GetAccessToken(c); err != nil {
-			log.Debug().Err(err).Msg("no access token in authenticated request")
-			return nil, ErrAuthRequired
-		}
+			if claims, err = refreshClaims(c); err != nil {
+				log.Debug().Err(err).Msg("no access token in authenticated request")
+				return nil, ErrAuthRequired
+			}
+		} else if claims, err = issuer.Verify(accessToken); err != nil {
+			// Attempt to refresh the claims if the access token is expired.
+			if !errors.Is(err, jwt.ErrTokenExpired) {
+				log.Debug().Err(err).Msg("invalid access token in request")
+				return nil, ErrAuthRequired
+			}
 
-		if claims, err = issuer.Verify(accessToken); err != nil {
-			// TODO: attempt to refresh the claims if the accessToken is expired.
-			log.Debug().Err(err).Msg("invalid access token in request")
-			return nil, ErrAuthRequired
+			if claims, err = refreshClaims(c); err != nil {
+				log.Debug().Err(err).Msg("could not refresh expired access token")
+				return nil, ErrAuthRequired
+			}
 		}
 
 		// Do not allow sunrise subjects to be authenticated
@@ -55,6 +122,14 @@ func Authenticate(issuer *ClaimsIssuer) gin.HandlerFunc {
 			return nil, ErrAuthRequired
 		}
 
+		// Enforce the expiration and network restrictions of api keys
+		if claims.SubjectType() == SubjectAPIKey {
+			if err = claims.VerifyAPIKey(c.ClientIP(), time.Now()); err != nil {
+				log.Debug().Err(err).Str("client_id", claims.ClientID).Msg("api key restriction violated")
+				return nil, err
+			}
+		}
+
 		return claims, nil
 	}
 
@@ -65,6 +140,12 @@ func Authenticate(issuer *ClaimsIssuer) gin.HandlerFunc {
 		)
 
 		if claims, err = innerAuthenticate(c); err != nil {
+			// API keys are only used by API clients and may not be used from this network
+			if errors.Is(err, ErrNetworkNotAllowed) {
+				c.AbortWithStatusJSON(http.StatusForbidden, api.Error(err))
+				return
+			}
+
 			// If this is an HTMX query to an API endpoint, redirect to login without
 			// the path as next because it's likely an API endpoint.
 			if htmx.IsHTMXRequest(c) {
@@ -102,6 +183,15 @@ func Authenticate(issuer *ClaimsIssuer) gin.HandlerFunc {
 			return
 		}
 
+		// Record api key usage; failures should not prevent the request from proceeding
+		if usage != nil && claims.SubjectType() == SubjectAPIKey {
+			if _, keyID, err := claims.SubjectID(); err == nil {
+				if err = usage.RecordAPIKeyUsage(c.Request.Context(), keyID, c.ClientIP(), time.Now()); err != nil {
+					log.Warn().Err(err).Str("client_id", claims.ClientID).Msg("could not record api key usage")
+				}
+			}
+		}
+
 		c.Next()
 	}
 }
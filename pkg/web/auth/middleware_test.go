@@ -1,10 +1,20 @@
 package auth_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"go.rtnl.ai/ulid"
 )
 
 func TestIsLocalhost(t *testing.T) {
@@ -46,3 +56,128 @@ func TestIsLocalhost(t *testing.T) {
 		tc.assert(t, auth.IsLocalhost(tc.domain), "test case %d failed", i)
 	}
 }
+
+type refresher struct {
+	calls int
+	err   error
+}
+
+func (r *refresher) RefreshClaims(ctx context.Context, refresh *auth.Claims) (*auth.Claims, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	// Return new claims with updated permissions for the subject
+	claims := &auth.Claims{Email: "kate@example.com", Permissions: []string{"travelrule:view"}}
+	claims.Subject = refresh.Subject
+	return claims, nil
+}
+
+func TestAuthenticateRefresh(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issuer, err := auth.NewIssuer(config.AuthConfig{
+		Keys:            map[string]string{"01GE62EXXR0X0561XD53RDFBQJ": "testdata/01GE62EXXR0X0561XD53RDFBQJ.pem"},
+		Audience:        "http://localhost:3000",
+		Issuer:          "http://localhost:3001",
+		CookieDomain:    "localhost",
+		AccessTokenTTL:  1 * time.Hour,
+		RefreshTokenTTL: 2 * time.Hour,
+		TokenOverlap:    -15 * time.Minute,
+	})
+	require.NoError(t, err, "could not create claims issuer")
+
+	// Create an expired access token whose refresh token is still valid
+	claims := &auth.Claims{Email: "kate@example.com"}
+	claims.SetSubjectID(auth.SubjectUser, ulid.MakeSecure())
+
+	accessToken, err := issuer.CreateAccessToken(claims)
+	require.NoError(t, err, "could not create access token")
+	claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(-90 * time.Minute))
+	claims.NotBefore = claims.IssuedAt
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-30 * time.Minute))
+
+	refreshToken, err := issuer.CreateRefreshToken(accessToken)
+	require.NoError(t, err, "could not create refresh token")
+
+	expired, err := issuer.Sign(accessToken)
+	require.NoError(t, err, "could not sign access token")
+	refresh, err := issuer.Sign(refreshToken)
+	require.NoError(t, err, "could not sign refresh token")
+
+	request := func(refresher auth.ClaimsRefresher, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/", auth.Authenticate(issuer, nil, refresher), func(c *gin.Context) {
+			claims, err := auth.GetClaims(c)
+			require.NoError(t, err, "expected claims on the context")
+			c.JSON(http.StatusOK, claims)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", "application/json")
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	accessCookie := &http.Cookie{Name: auth.AccessTokenCookie, Value: expired}
+	refreshCookie := &http.Cookie{Name: auth.RefreshTokenCookie, Value: refresh}
+
+	t.Run("ExpiredAccessToken", func(t *testing.T) {
+		r := &refresher{}
+		w := request(r, accessCookie, refreshCookie)
+		require.Equal(t, http.StatusOK, w.Code, "expected the access token to be refreshed")
+		require.Equal(t, 1, r.calls)
+
+		out := &auth.Claims{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), out))
+		require.Equal(t, []string{"travelrule:view"}, out.Permissions, "expected the refreshed claims")
+		require.Equal(t, claims.Subject, out.Subject)
+
+		var names []string
+		for _, cookie := range w.Result().Cookies() {
+			names = append(names, cookie.Name)
+		}
+		require.ElementsMatch(t, []string{auth.AccessTokenCookie, auth.RefreshTokenCookie}, names, "expected new auth cookies to be set")
+	})
+
+	t.Run("MissingAccessToken", func(t *testing.T) {
+		r := &refresher{}
+		w := request(r, refreshCookie)
+		require.Equal(t, http.StatusOK, w.Code, "expected the access token to be refreshed")
+		require.Equal(t, 1, r.calls)
+	})
+
+	t.Run("NoRefreshToken", func(t *testing.T) {
+		r := &refresher{}
+		w := request(r, accessCookie)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, 0, r.calls)
+	})
+
+	t.Run("AccessTokenAsRefreshToken", func(t *testing.T) {
+		r := &refresher{}
+		valid, _, err := issuer.CreateTokens(&auth.Claims{Email: "kate@example.com"})
+		require.NoError(t, err, "could not create tokens")
+
+		w := request(r, accessCookie, &http.Cookie{Name: auth.RefreshTokenCookie, Value: valid})
+		require.Equal(t, http.StatusUnauthorized, w.Code, "an access token cannot be used to refresh")
+		require.Equal(t, 0, r.calls)
+	})
+
+	t.Run("RefreshFailed", func(t *testing.T) {
+		r := &refresher{err: errors.New("user not found")}
+		w := request(r, accessCookie, refreshCookie)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, 1, r.calls)
+	})
+
+	t.Run("NoRefresher", func(t *testing.T) {
+		w := request(nil, accessCookie, refreshCookie)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,37 @@
+package oidc
+
+import "strings"
+
+// Claims are the verified claims about the user from the identity provider's ID token.
+type Claims struct {
+	Subject string
+	Email   string
+	Name    string
+	Groups  []string
+}
+
+// Role returns the role mapped from the first of the user's groups that has a mapping
+// (groups are matched case-insensitively). If none of the groups are mapped then the
+// default role is returned, or ErrNoRole if there is no default role.
+func (c *Claims) Role(mapping map[string]string, defaultRole string) (string, error) {
+	for _, group := range c.Groups {
+		for mapped, role := range mapping {
+			if strings.EqualFold(group, mapped) {
+				return role, nil
+			}
+		}
+	}
+
+	if defaultRole != "" {
+		return defaultRole, nil
+	}
+	return "", ErrNoRole
+}
+
+// DisplayName returns the name of the user or their email if no name was provided.
+func (c *Claims) DisplayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Email
+}
@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// JWKS is a JSON web key set published by the identity provider (RFC 7517).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is a public JSON web key; only RSA and EC signing keys are supported.
+type JWK struct {
+	KeyID string `json:"kid"`
+	Type  string `json:"kty"`
+	Use   string `json:"use,omitempty"`
+	Alg   string `json:"alg,omitempty"`
+	N     string `json:"n,omitempty"`
+	E     string `json:"e,omitempty"`
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// PublicKeys returns the signing keys in the key set by key id; encryption keys and
+// keys of unsupported types are skipped.
+func (s *JWKS) PublicKeys() (keys map[string]any, err error) {
+	keys = make(map[string]any, len(s.Keys))
+	for _, jwk := range s.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var key any
+		switch jwk.Type {
+		case "RSA":
+			if key, err = jwk.rsa(); err != nil {
+				return nil, err
+			}
+		case "EC":
+			if key, err = jwk.ecdsa(); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
+
+func (k JWK) rsa() (_ *rsa.PublicKey, err error) {
+	var n, e []byte
+	if n, err = base64.RawURLEncoding.DecodeString(k.N); err != nil {
+		return nil, fmt.Errorf("could not decode rsa modulus of key %q: %w", k.KeyID, err)
+	}
+
+	if e, err = base64.RawURLEncoding.DecodeString(k.E); err != nil {
+		return nil, fmt.Errorf("could not decode rsa exponent of key %q: %w", k.KeyID, err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func (k JWK) ecdsa() (_ *ecdsa.PublicKey, err error) {
+	var curve elliptic.Curve
+	switch k.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q for key %q", k.Curve, k.KeyID)
+	}
+
+	var x, y []byte
+	if x, err = base64.RawURLEncoding.DecodeString(k.X); err != nil {
+		return nil, fmt.Errorf("could not decode x coordinate of key %q: %w", k.KeyID, err)
+	}
+
+	if y, err = base64.RawURLEncoding.DecodeString(k.Y); err != nil {
+		return nil, fmt.Errorf("could not decode y coordinate of key %q: %w", k.KeyID, err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
@@ -0,0 +1,218 @@
+/*
+Package mock implements a stub OpenID Connect identity provider for testing single
+sign-on without an external identity provider. The authorization endpoint immediately
+redirects back to the relying party with an authorization code for the configured user
+claims and the token endpoint verifies the PKCE code verifier before issuing an RS256
+signed ID token.
+*/
+package mock
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	KeyID        = "stub-signing-key"
+	ClientID     = "envoy"
+	ClientSecret = "supersecretsquirrel"
+)
+
+// Provider is a stub OpenID Connect provider served by an httptest server.
+type Provider struct {
+	sync.Mutex
+	srv    *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+	codes  map[string]*authRequest
+}
+
+type authRequest struct {
+	nonce       string
+	challenge   string
+	redirectURI string
+	claims      jwt.MapClaims
+}
+
+// New starts a stub provider that issues ID tokens with the specified user claims,
+// e.g. email, name, and groups.
+func New(claims jwt.MapClaims) (p *Provider, err error) {
+	p = &Provider{
+		claims: claims,
+		codes:  make(map[string]*authRequest),
+	}
+
+	if p.key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /jwks", p.jwks)
+	mux.HandleFunc("GET /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	p.srv = httptest.NewServer(mux)
+	return p, nil
+}
+
+// Issuer returns the issuer URL of the stub provider.
+func (p *Provider) Issuer() string {
+	return p.srv.URL
+}
+
+// Client returns an http client that can connect to the stub provider.
+func (p *Provider) Client() *http.Client {
+	return p.srv.Client()
+}
+
+// SetClaims changes the user claims issued for subsequent authentication requests.
+func (p *Provider) SetClaims(claims jwt.MapClaims) {
+	p.Lock()
+	defer p.Unlock()
+	p.claims = claims
+}
+
+// Close shuts down the stub provider.
+func (p *Provider) Close() {
+	p.srv.Close()
+}
+
+// IDToken returns an ID token signed by the provider with the standard claims for the
+// nonce and the specified user claims; claims can override the standard claims.
+func (p *Provider) IDToken(nonce string, claims jwt.MapClaims) (string, error) {
+	now := time.Now()
+	token := jwt.MapClaims{
+		"iss":   p.Issuer(),
+		"aud":   ClientID,
+		"sub":   "stub-user",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": nonce,
+	}
+
+	for key, val := range claims {
+		token[key] = val
+	}
+
+	jwtoken := jwt.NewWithClaims(jwt.SigningMethodRS256, token)
+	jwtoken.Header["kid"] = KeyID
+	return jwtoken.SignedString(p.key)
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.Issuer(),
+		"authorization_endpoint": p.Issuer() + "/authorize",
+		"token_endpoint":         p.Issuer() + "/token",
+		"jwks_uri":               p.Issuer() + "/jwks",
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{
+			{
+				"kid": KeyID,
+				"kty": "RSA",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+			},
+		},
+	})
+}
+
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != ClientID || query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	redirect, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+
+	code := rand.Text()
+	p.Lock()
+	p.codes[code] = &authRequest{
+		nonce:       query.Get("nonce"),
+		challenge:   query.Get("code_challenge"),
+		redirectURI: redirect.String(),
+		claims:      p.claims,
+	}
+	p.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	if clientID != ClientID || clientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// Authorization codes are single use
+	p.Lock()
+	req, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.Unlock()
+
+	if !ok || req.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	digest := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(digest[:]) != req.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce verification failed"})
+		return
+	}
+
+	idToken, err := p.IDToken(req.nonce, req.claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,324 @@
+/*
+Package oidc implements an OpenID Connect relying party so that web UI users can login
+with single sign-on from an external identity provider using the authorization code
+flow with PKCE. The provider's endpoints are discovered from its issuer URL and ID
+tokens are verified against the provider's published JSON web key set.
+*/
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/config"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	httpTimeout   = 30 * time.Second
+)
+
+// OIDC Errors
+var (
+	ErrNotEnabled       = errors.New("openid connect single sign-on is not enabled")
+	ErrIssuerMismatch   = errors.New("discovered issuer does not match the configured issuer url")
+	ErrNoIDToken        = errors.New("no id token in the token response")
+	ErrInvalidIDToken   = errors.New("could not verify id token")
+	ErrInvalidNonce     = errors.New("id token nonce does not match the authentication request")
+	ErrNoEmail          = errors.New("id token does not contain an email address")
+	ErrEmailNotVerified = errors.New("id token email address has not been verified")
+	ErrNoRole           = errors.New("no envoy role is mapped to the user's groups")
+	ErrUnknownKey       = errors.New("id token is signed by an unknown key")
+)
+
+// Provider is an OpenID Connect identity provider. The provider's configuration is
+// discovered when it is first used so that Envoy can start if the provider is down.
+type Provider struct {
+	sync.RWMutex
+	conf   config.OIDCConfig
+	client *http.Client
+	oauth  *oauth2.Config
+	meta   *Metadata
+	keys   map[string]any
+}
+
+// Metadata is the subset of the OpenID Provider metadata required by Envoy.
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// New returns a provider for the configuration; an error is returned if OIDC is not
+// enabled or the configuration is invalid.
+func New(conf config.OIDCConfig) (_ *Provider, err error) {
+	if !conf.Enabled {
+		return nil, ErrNotEnabled
+	}
+
+	if err = conf.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &Provider{
+		conf:   conf,
+		client: &http.Client{Timeout: httpTimeout},
+	}, nil
+}
+
+// WithClient sets the http client used to connect to the provider (e.g. for testing).
+func (p *Provider) WithClient(client *http.Client) *Provider {
+	p.client = client
+	return p
+}
+
+// Name returns the display name of the identity provider.
+func (p *Provider) Name() string {
+	return p.conf.ProviderName
+}
+
+// AuthCodeURL returns the url the user is redirected to in order to authenticate with
+// the identity provider. The state, nonce, and PKCE verifier must be stored by the
+// caller to verify the callback from the identity provider.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (_ string, err error) {
+	var conf *oauth2.Config
+	if conf, err = p.config(ctx); err != nil {
+		return "", err
+	}
+
+	return conf.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier), oauth2.SetAuthURLParam("nonce", nonce)), nil
+}
+
+// Exchange the authorization code from the callback for an ID token and return the
+// verified claims of the user from the ID token.
+func (p *Provider) Exchange(ctx context.Context, code, nonce, verifier string) (claims *Claims, err error) {
+	var conf *oauth2.Config
+	if conf, err = p.config(ctx); err != nil {
+		return nil, err
+	}
+
+	var token *oauth2.Token
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	if token, err = conf.Exchange(ctx, code, oauth2.VerifierOption(verifier)); err != nil {
+		return nil, fmt.Errorf("could not exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrNoIDToken
+	}
+
+	return p.Verify(ctx, rawIDToken, nonce)
+}
+
+// Verify the signature and claims of the ID token and return the user's claims.
+func (p *Provider) Verify(ctx context.Context, rawIDToken, nonce string) (claims *Claims, err error) {
+	var meta *Metadata
+	if meta, err = p.metadata(ctx); err != nil {
+		return nil, err
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	mapClaims := jwt.MapClaims{}
+	if _, err = parser.ParseWithClaims(rawIDToken, mapClaims, p.keyFunc(ctx)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if !mapClaims.VerifyIssuer(meta.Issuer, true) {
+		return nil, fmt.Errorf("%w: invalid issuer", ErrInvalidIDToken)
+	}
+
+	if !mapClaims.VerifyAudience(p.conf.ClientID, true) {
+		return nil, fmt.Errorf("%w: invalid audience", ErrInvalidIDToken)
+	}
+
+	if _, ok := mapClaims["exp"]; !ok {
+		return nil, fmt.Errorf("%w: missing expiration", ErrInvalidIDToken)
+	}
+
+	if tokenNonce, _ := mapClaims["nonce"].(string); tokenNonce != nonce {
+		return nil, ErrInvalidNonce
+	}
+
+	claims = &Claims{}
+	claims.Subject, _ = mapClaims["sub"].(string)
+	claims.Email, _ = mapClaims["email"].(string)
+	claims.Name, _ = mapClaims["name"].(string)
+	claims.Groups = stringSlice(mapClaims[p.conf.GroupsClaim])
+
+	if claims.Email == "" {
+		return nil, ErrNoEmail
+	}
+
+	// If the provider reports that the email is unverified it cannot be used to match
+	// the user; providers that do not send the claim are trusted to verify emails.
+	if verified, ok := mapClaims["email_verified"].(bool); ok && !verified {
+		return nil, ErrEmailNotVerified
+	}
+
+	return claims, nil
+}
+
+// Role returns the envoy role mapped from the user's groups claim; if none of the
+// user's groups are mapped then the default role is returned, if configured.
+func (p *Provider) Role(claims *Claims) (string, error) {
+	return claims.Role(p.conf.RoleMapping, p.conf.DefaultRole)
+}
+
+// Provision returns true if users should be created when they first login.
+func (p *Provider) Provision() bool {
+	return p.conf.Provision
+}
+
+func (p *Provider) config(ctx context.Context) (_ *oauth2.Config, err error) {
+	var meta *Metadata
+	if meta, err = p.metadata(ctx); err != nil {
+		return nil, err
+	}
+
+	p.RLock()
+	defer p.RUnlock()
+	return &oauth2.Config{
+		ClientID:     p.conf.ClientID,
+		ClientSecret: p.conf.ClientSecret,
+		RedirectURL:  p.conf.RedirectURL,
+		Scopes:       p.conf.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  meta.AuthorizationEndpoint,
+			TokenURL: meta.TokenEndpoint,
+		},
+	}, nil
+}
+
+// Returns the provider metadata, discovering it from the issuer if required.
+func (p *Provider) metadata(ctx context.Context) (_ *Metadata, err error) {
+	p.RLock()
+	meta := p.meta
+	p.RUnlock()
+
+	if meta != nil {
+		return meta, nil
+	}
+
+	meta = &Metadata{}
+	if err = p.getJSON(ctx, strings.TrimSuffix(p.conf.IssuerURL, "/")+discoveryPath, meta); err != nil {
+		return nil, fmt.Errorf("could not discover openid provider configuration: %w", err)
+	}
+
+	if strings.TrimSuffix(meta.Issuer, "/") != strings.TrimSuffix(p.conf.IssuerURL, "/") {
+		return nil, ErrIssuerMismatch
+	}
+
+	p.Lock()
+	p.meta = meta
+	p.Unlock()
+	return meta, nil
+}
+
+// Returns a jwt key func that looks up the signing key by its key id, refreshing the
+// key set once if the key is not found in case the provider has rotated its keys.
+func (p *Provider) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (key any, err error) {
+		kid, _ := token.Header["kid"].(string)
+
+		p.RLock()
+		key, ok := p.keys[kid]
+		p.RUnlock()
+
+		if ok {
+			return key, nil
+		}
+
+		if err = p.refreshKeys(ctx); err != nil {
+			return nil, err
+		}
+
+		p.RLock()
+		defer p.RUnlock()
+		if key, ok = p.keys[kid]; !ok {
+			return nil, ErrUnknownKey
+		}
+		return key, nil
+	}
+}
+
+func (p *Provider) refreshKeys(ctx context.Context) (err error) {
+	var meta *Metadata
+	if meta, err = p.metadata(ctx); err != nil {
+		return err
+	}
+
+	jwks := &JWKS{}
+	if err = p.getJSON(ctx, meta.JWKSURI, jwks); err != nil {
+		return fmt.Errorf("could not fetch openid provider keys: %w", err)
+	}
+
+	var keys map[string]any
+	if keys, err = jwks.PublicKeys(); err != nil {
+		return err
+	}
+
+	p.Lock()
+	p.keys = keys
+	p.Unlock()
+	return nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, out any) (err error) {
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, url, nil); err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var rep *http.Response
+	if rep, err = p.client.Do(req); err != nil {
+		return err
+	}
+	defer rep.Body.Close()
+
+	if rep.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", rep.Status, url)
+	}
+
+	return json.NewDecoder(rep.Body).Decode(out)
+}
+
+// RandomString returns a url safe random string for use as a state or nonce value.
+func RandomString() (_ string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Groups claims may be a list of strings or a single string.
+func stringSlice(val any) []string {
+	switch v := val.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
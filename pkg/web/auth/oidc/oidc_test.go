@@ -0,0 +1,172 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/web/auth/oidc"
+	"github.com/trisacrypto/envoy/pkg/web/auth/oidc/mock"
+	"golang.org/x/oauth2"
+)
+
+const redirectURL = "http://localhost:8000/login/oidc/callback"
+
+func TestNew(t *testing.T) {
+	_, err := oidc.New(config.OIDCConfig{Enabled: false})
+	require.ErrorIs(t, err, oidc.ErrNotEnabled)
+
+	_, err = oidc.New(config.OIDCConfig{Enabled: true, ClientID: mock.ClientID})
+	require.EqualError(t, err, "invalid configuration: oidc issuer url, client id, and redirect url are required")
+}
+
+func TestExchange(t *testing.T) {
+	idp, err := mock.New(jwt.MapClaims{
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+		"groups":         []string{"everyone", "envoy-admins"},
+	})
+	require.NoError(t, err, "could not start stub provider")
+	defer idp.Close()
+
+	provider := newProvider(t, idp)
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		verifier := oauth2.GenerateVerifier()
+		code := authorize(t, idp, provider, "state", "nonce", verifier)
+
+		claims, err := provider.Exchange(ctx, code, "nonce", verifier)
+		require.NoError(t, err, "could not exchange authorization code")
+		require.Equal(t, "jane@example.com", claims.Email)
+		require.Equal(t, "Jane Doe", claims.DisplayName())
+		require.Equal(t, "stub-user", claims.Subject)
+		require.Equal(t, []string{"everyone", "envoy-admins"}, claims.Groups)
+
+		role, err := provider.Role(claims)
+		require.NoError(t, err, "expected role to be mapped from groups")
+		require.Equal(t, "admin", role)
+
+		// The authorization code cannot be reused
+		_, err = provider.Exchange(ctx, code, "nonce", verifier)
+		require.Error(t, err, "expected authorization code to be single use")
+	})
+
+	t.Run("BadVerifier", func(t *testing.T) {
+		code := authorize(t, idp, provider, "state", "nonce", oauth2.GenerateVerifier())
+		_, err := provider.Exchange(ctx, code, "nonce", oauth2.GenerateVerifier())
+		require.ErrorContains(t, err, "could not exchange authorization code")
+	})
+
+	t.Run("BadNonce", func(t *testing.T) {
+		verifier := oauth2.GenerateVerifier()
+		code := authorize(t, idp, provider, "state", "nonce", verifier)
+		_, err := provider.Exchange(ctx, code, "other", verifier)
+		require.ErrorIs(t, err, oidc.ErrInvalidNonce)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	idp, err := mock.New(nil)
+	require.NoError(t, err, "could not start stub provider")
+	defer idp.Close()
+
+	provider := newProvider(t, idp)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		err    error
+	}{
+		{"Audience", jwt.MapClaims{"email": "jane@example.com", "aud": "other"}, oidc.ErrInvalidIDToken},
+		{"Issuer", jwt.MapClaims{"email": "jane@example.com", "iss": "https://other.example.com"}, oidc.ErrInvalidIDToken},
+		{"Expired", jwt.MapClaims{"email": "jane@example.com", "exp": time.Now().Add(-time.Minute).Unix()}, oidc.ErrInvalidIDToken},
+		{"NoEmail", jwt.MapClaims{}, oidc.ErrNoEmail},
+		{"EmailNotVerified", jwt.MapClaims{"email": "jane@example.com", "email_verified": false}, oidc.ErrEmailNotVerified},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := idp.IDToken("nonce", tc.claims)
+			require.NoError(t, err, "could not sign id token")
+
+			_, err = provider.Verify(ctx, token, "nonce")
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": "jane@example.com"})
+		signed, err := token.SignedString([]byte("secret"))
+		require.NoError(t, err, "could not sign id token")
+
+		_, err = provider.Verify(ctx, signed, "nonce")
+		require.ErrorIs(t, err, oidc.ErrInvalidIDToken, "expected symmetric signing methods to be rejected")
+	})
+}
+
+func TestRole(t *testing.T) {
+	mapping := map[string]string{"Envoy-Admins": "admin", "envoy-compliance": "compliance"}
+
+	claims := &oidc.Claims{Groups: []string{"everyone", "envoy-admins"}}
+	role, err := claims.Role(mapping, "")
+	require.NoError(t, err)
+	require.Equal(t, "admin", role, "expected groups to be matched case-insensitively")
+
+	claims = &oidc.Claims{Groups: []string{"envoy-compliance", "envoy-admins"}}
+	role, err = claims.Role(mapping, "")
+	require.NoError(t, err)
+	require.Equal(t, "compliance", role, "expected the first mapped group to be used")
+
+	claims = &oidc.Claims{Groups: []string{"everyone"}}
+	role, err = claims.Role(mapping, "observer")
+	require.NoError(t, err)
+	require.Equal(t, "observer", role, "expected the default role for unmapped groups")
+
+	_, err = claims.Role(mapping, "")
+	require.ErrorIs(t, err, oidc.ErrNoRole)
+}
+
+func newProvider(t *testing.T, idp *mock.Provider) *oidc.Provider {
+	provider, err := oidc.New(config.OIDCConfig{
+		Enabled:      true,
+		IssuerURL:    idp.Issuer(),
+		ClientID:     mock.ClientID,
+		ClientSecret: mock.ClientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile"},
+		GroupsClaim:  "groups",
+		RoleMapping:  map[string]string{"envoy-admins": "admin"},
+	})
+	require.NoError(t, err, "could not create provider")
+	return provider.WithClient(idp.Client())
+}
+
+// Follows the authorization url to the stub provider and returns the code from the
+// redirect back to the relying party.
+func authorize(t *testing.T, idp *mock.Provider, provider *oidc.Provider, state, nonce, verifier string) string {
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, verifier)
+	require.NoError(t, err, "could not create auth code url")
+
+	client := idp.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	rep, err := client.Get(authURL)
+	require.NoError(t, err, "could not make authorization request")
+	defer rep.Body.Close()
+	require.Equal(t, http.StatusFound, rep.StatusCode)
+
+	location, err := url.Parse(rep.Header.Get("Location"))
+	require.NoError(t, err, "could not parse redirect")
+	require.Equal(t, state, location.Query().Get("state"))
+	return location.Query().Get("code")
+}
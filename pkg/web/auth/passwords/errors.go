@@ -1,6 +1,9 @@
 package passwords
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Password Strength Errors
 var (
@@ -8,4 +11,23 @@ var (
 	ErrPasswordTooShort   = errors.New("password must be at least 8 characters")
 	ErrPasswordWhitespace = errors.New("password must not start or end with whitespace")
 	ErrPasswordStrength   = errors.New("password must contain uppercase letters, lowercase letters, numbers, and special characters")
+	ErrPasswordUppercase  = errors.New("password must contain an uppercase letter")
+	ErrPasswordLowercase  = errors.New("password must contain a lowercase letter")
+	ErrPasswordNumber     = errors.New("password must contain a number")
+	ErrPasswordSpecial    = errors.New("password must contain a special character")
+	ErrPasswordReused     = errors.New("password must not be the same as the current password")
 )
+
+// LengthError is returned when the password is shorter than the minimum length of the
+// password policy; it matches ErrPasswordTooShort with errors.Is.
+type LengthError struct {
+	MinLength int
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("password must be at least %d characters", e.MinLength)
+}
+
+func (e *LengthError) Is(target error) bool {
+	return target == ErrPasswordTooShort
+}
@@ -0,0 +1,73 @@
+package passwords
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultPolicy is the password policy enforced if no other policy is set.
+var DefaultPolicy = Policy{
+	MinLength:   8,
+	MinStrength: 3,
+}
+
+var (
+	policy   = DefaultPolicy
+	policymu sync.RWMutex
+)
+
+// Policy describes the complexity requirements of user passwords. The strength of a
+// password is the number of character classes (numbers, uppercase, lowercase, and
+// special characters) it contains, with a bonus point for passwords longer than 16
+// characters. Character classes that are required are checked in addition to the
+// minimum strength.
+type Policy struct {
+	MinLength        int
+	MinStrength      uint8
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireNumber    bool
+	RequireSpecial   bool
+}
+
+// Set the password policy enforced by Strength.
+func SetPolicy(p Policy) {
+	policymu.Lock()
+	defer policymu.Unlock()
+	policy = p
+}
+
+// Get the password policy that is currently enforced by Strength.
+func GetPolicy() Policy {
+	policymu.RLock()
+	defer policymu.RUnlock()
+	return policy
+}
+
+// Requirements returns a human readable description of each rule of the policy so
+// that the requirements can be displayed to users when they choose a password.
+func (p Policy) Requirements() []string {
+	reqs := []string{fmt.Sprintf("Minimum of %d characters", p.MinLength)}
+	if p.RequireUppercase {
+		reqs = append(reqs, "At least one uppercase letter")
+	}
+
+	if p.RequireLowercase {
+		reqs = append(reqs, "At least one lowercase letter")
+	}
+
+	if p.RequireNumber {
+		reqs = append(reqs, "At least one number")
+	}
+
+	if p.RequireSpecial {
+		reqs = append(reqs, "At least one special character")
+	}
+
+	if p.MinStrength > 0 {
+		reqs = append(reqs, fmt.Sprintf("At least %d of: uppercase letters, lowercase letters, numbers, special characters, or more than 16 characters", p.MinStrength))
+	}
+
+	reqs = append(reqs, "Can't be the same as the current password")
+	return reqs
+}
@@ -3,16 +3,26 @@ package passwords
 import "unicode"
 
 // Returns an error if the password is not strong enough as well as a password strength
-// score to compare the strengths of different passwords.
+// score to compare the strengths of different passwords. The password is checked
+// against the current password policy (see SetPolicy).
 func Strength(password string) (strength uint8, err error) {
+	return GetPolicy().Strength(password)
+}
+
+// Strength returns an error if the password does not meet the requirements of the
+// policy as well as the password strength score.
+func (p Policy) Strength(password string) (strength uint8, err error) {
 	// Password cannot be empty
 	if password == "" {
 		return 0, ErrPasswordEmpty
 	}
 
-	// Password must be at least 8 characters
-	if len(password) < 8 {
-		return 0, ErrPasswordTooShort
+	// Password must be at least the minimum length
+	if len(password) < p.MinLength {
+		if p.MinLength == DefaultPolicy.MinLength {
+			return 0, ErrPasswordTooShort
+		}
+		return 0, &LengthError{MinLength: p.MinLength}
 	}
 
 	// Password must not start or end with whitespace
@@ -46,7 +56,19 @@ func Strength(password string) (strength uint8, err error) {
 		strength = strength + flag
 	}
 
-	if strength < 3 {
+	// Check the character classes required by the policy
+	switch {
+	case p.RequireUppercase && flags[1] == 0:
+		return strength, ErrPasswordUppercase
+	case p.RequireLowercase && flags[2] == 0:
+		return strength, ErrPasswordLowercase
+	case p.RequireNumber && flags[0] == 0:
+		return strength, ErrPasswordNumber
+	case p.RequireSpecial && flags[3] == 0:
+		return strength, ErrPasswordSpecial
+	}
+
+	if strength < p.MinStrength {
 		return strength, ErrPasswordStrength
 	}
 
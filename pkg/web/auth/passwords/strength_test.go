@@ -53,3 +53,53 @@ func TestStrength(t *testing.T) {
 		}
 	}
 }
+
+func TestPolicy(t *testing.T) {
+	policy := Policy{
+		MinLength:        12,
+		MinStrength:      4,
+		RequireUppercase: true,
+		RequireNumber:    true,
+		RequireSpecial:   true,
+	}
+
+	testCases := []struct {
+		password    string
+		expectedErr error
+	}{
+		{"", ErrPasswordEmpty},
+		{"Sup3rS3@ret", ErrPasswordTooShort},
+		{"sup3rs3@retpass", ErrPasswordUppercase},
+		{"SuperSe@retPass", ErrPasswordNumber},
+		{"Sup3rS3cretPass", ErrPasswordSpecial},
+		{"Sup3rS3@retPass", nil},
+	}
+
+	for i, tc := range testCases {
+		_, err := policy.Strength(tc.password)
+		if tc.expectedErr == nil {
+			require.NoError(t, err, "unexpected error on test case %d", i)
+		} else {
+			require.ErrorIs(t, err, tc.expectedErr, "error mismatch on test case %d", i)
+		}
+	}
+
+	// The minimum length is included in the error message
+	_, err := policy.Strength("Sup3rS3@ret")
+	require.EqualError(t, err, "password must be at least 12 characters")
+
+	// The minimum strength is enforced in addition to the required characters
+	policy = Policy{MinLength: 8, MinStrength: 5, RequireLowercase: true}
+	_, err = policy.Strength("ONLYUPPERCASE")
+	require.ErrorIs(t, err, ErrPasswordLowercase)
+
+	_, err = policy.Strength("Sup3rS3@ret")
+	require.ErrorIs(t, err, ErrPasswordStrength)
+
+	// The current policy is used by Strength
+	defer SetPolicy(GetPolicy())
+	SetPolicy(Policy{MinLength: 16})
+	_, err = Strength("Sup3rS3@ret")
+	require.ErrorIs(t, err, ErrPasswordTooShort)
+	require.Len(t, GetPolicy().Requirements(), 2)
+}
@@ -30,9 +30,13 @@ const (
 	PKIManage
 	PKIDelete
 	PKIView
+	LegalHoldsManage
+	LegalHoldsView
+	ApprovalsManage
+	ApprovalsReview
 )
 
-var AllPermissions = [17]Permission{
+var AllPermissions = [21]Permission{
 	UsersManage, UsersView,
 	APIKeysManage, APIKeysView, APIKeysRevoke,
 	CounterpartiesManage, CounterpartiesView,
@@ -40,9 +44,11 @@ var AllPermissions = [17]Permission{
 	TravelRuleManage, TravelRuleDelete, TravelRuleView,
 	ConfigManage, ConfigView,
 	PKIManage, PKIDelete, PKIView,
+	LegalHoldsManage, LegalHoldsView,
+	ApprovalsManage, ApprovalsReview,
 }
 
-var names = [18]string{
+var names = [22]string{
 	"unknown",
 	"users:manage", "users:view",
 	"apikeys:manage", "apikeys:view", "apikeys:revoke",
@@ -51,6 +57,8 @@ var names = [18]string{
 	"travelrule:manage", "travelrule:delete", "travelrule:view",
 	"config:manage", "config:view",
 	"pki:manage", "pki:delete", "pki:view",
+	"legalholds:manage", "legalholds:view",
+	"approvals:manage", "approvals:review",
 }
 
 func Parse(p any) (Permission, error) {
@@ -34,6 +34,10 @@ func TestParse(t *testing.T) {
 			{uint8(15), permissions.PKIManage},
 			{uint8(16), permissions.PKIDelete},
 			{uint8(17), permissions.PKIView},
+			{uint8(18), permissions.LegalHoldsManage},
+			{uint8(19), permissions.LegalHoldsView},
+			{uint8(20), permissions.ApprovalsManage},
+			{uint8(21), permissions.ApprovalsReview},
 			{int64(0), permissions.Unknown},
 			{int64(1), permissions.UsersManage},
 			{int64(2), permissions.UsersView},
@@ -52,6 +56,10 @@ func TestParse(t *testing.T) {
 			{int64(15), permissions.PKIManage},
 			{int64(16), permissions.PKIDelete},
 			{int64(17), permissions.PKIView},
+			{int64(18), permissions.LegalHoldsManage},
+			{int64(19), permissions.LegalHoldsView},
+			{int64(20), permissions.ApprovalsManage},
+			{int64(21), permissions.ApprovalsReview},
 			{"unknown", permissions.Unknown},
 			{"users:manage", permissions.UsersManage},
 			{"users:view", permissions.UsersView},
@@ -70,6 +78,10 @@ func TestParse(t *testing.T) {
 			{"pki:manage", permissions.PKIManage},
 			{"pki:delete", permissions.PKIDelete},
 			{"pki:view", permissions.PKIView},
+			{"legalholds:manage", permissions.LegalHoldsManage},
+			{"legalholds:view", permissions.LegalHoldsView},
+			{"approvals:manage", permissions.ApprovalsManage},
+			{"approvals:review", permissions.ApprovalsReview},
 			{"TRAVELRULE:DELETE", permissions.TravelRuleDelete},
 			{"APIKeys:Revoke", permissions.APIKeysRevoke},
 			{"  config:manage   ", permissions.ConfigManage},
@@ -118,6 +130,10 @@ func TestJSON(t *testing.T) {
 		permissions.PKIManage,
 		permissions.PKIDelete,
 		permissions.PKIView,
+		permissions.LegalHoldsManage,
+		permissions.LegalHoldsView,
+		permissions.ApprovalsManage,
+		permissions.ApprovalsReview,
 	}
 
 	for _, perm := range all {
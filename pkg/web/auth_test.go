@@ -0,0 +1,330 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerRefreshClaims() {
+	w.Run("User", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		user := &models.User{Model: models.Model{ID: ulid.MakeSecure()}, Email: "kate@example.com"}
+		user.SetRole(&models.Role{ID: 2, Title: "Compliance", RequireMFA: true})
+		user.SetPermissions([]string{"travelrule:view", "travelrule:manage"})
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+		w.store.OnSetUserLastLogin = func(ctx context.Context, userID ulid.ULID, lastLogin time.Time) error {
+			return nil
+		}
+
+		session := ulid.MakeSecure()
+		w.store.OnRefreshUserSession = func(ctx context.Context, userID, sessionID ulid.ULID, expires time.Time) error {
+			require.Equal(user.ID, userID)
+			require.Equal(session, sessionID)
+			return nil
+		}
+
+		refresh := &auth.Claims{SSO: true, Session: session.String()}
+		refresh.SetSubjectID(auth.SubjectUser, user.ID)
+
+		//test
+		claims, err := w.s.RefreshClaims(ctx, refresh)
+		require.NoError(err, "could not refresh claims")
+		require.Equal(refresh.Subject, claims.Subject)
+		require.Equal([]string{"travelrule:view", "travelrule:manage"}, claims.Permissions, "expected permissions to be loaded from the database")
+		require.True(claims.SSO, "expected single sign-on to be retained")
+		require.False(claims.MFAEnroll, "sso users are exempt from mfa enrollment")
+		require.Equal(refresh.Session, claims.Session, "expected the session to be retained")
+		w.store.AssertCalls(w.T(), "SetUserLastLogin", 1)
+	})
+
+	w.Run("SessionRevoked", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		user := &models.User{Model: models.Model{ID: ulid.MakeSecure()}, Email: "kate@example.com"}
+		user.SetRole(&models.Role{ID: 2, Title: "Compliance"})
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+		w.store.OnRefreshUserSession = func(ctx context.Context, userID, sessionID ulid.ULID, expires time.Time) error {
+			return dberr.ErrNotFound
+		}
+
+		refresh := &auth.Claims{Session: ulid.MakeSecure().String()}
+		refresh.SetSubjectID(auth.SubjectUser, user.ID)
+
+		//test
+		claims, err := w.s.RefreshClaims(ctx, refresh)
+		require.ErrorIs(err, auth.ErrSessionRevoked)
+		require.Nil(claims)
+
+		// Refresh tokens issued before sessions were tracked cannot be used
+		refresh.Session = ""
+		claims, err = w.s.RefreshClaims(ctx, refresh)
+		require.ErrorIs(err, auth.ErrSessionRevoked)
+		require.Nil(claims)
+		w.store.AssertCalls(w.T(), "RefreshUserSession", 1)
+		w.store.AssertCalls(w.T(), "SetUserLastLogin", 0)
+	})
+
+	w.Run("UserNotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		refresh := &auth.Claims{}
+		refresh.SetSubjectID(auth.SubjectUser, ulid.MakeSecure())
+
+		//test
+		claims, err := w.s.RefreshClaims(ctx, refresh)
+		require.ErrorIs(err, dberr.ErrNotFound)
+		require.Nil(claims)
+	})
+
+	w.Run("APIKeyExpired", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		key := &models.APIKey{
+			Model:    models.Model{ID: ulid.MakeSecure()},
+			ClientID: "clientid",
+			Expires:  sql.NullTime{Valid: true, Time: time.Now().Add(-1 * time.Hour)},
+		}
+		w.store.OnRetrieveAPIKey = func(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error) {
+			return key, nil
+		}
+
+		refresh := &auth.Claims{}
+		refresh.SetSubjectID(auth.SubjectAPIKey, key.ID)
+
+		//test
+		claims, err := w.s.RefreshClaims(ctx, refresh)
+		require.ErrorIs(err, auth.ErrAPIKeyExpired)
+		require.Nil(claims)
+		w.store.AssertCalls(w.T(), "SetAPIKeyLastSeen", 0)
+	})
+
+	w.Run("APIKeySecretRotated", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		rotated := time.Now().Add(-10 * time.Minute)
+		key := &models.APIKey{
+			Model:         models.Model{ID: ulid.MakeSecure()},
+			ClientID:      "clientid",
+			SecretRotated: sql.NullTime{Valid: true, Time: rotated},
+		}
+		key.SetPermissions([]string{"travelrule:view"})
+		w.store.OnRetrieveAPIKey = func(ctx context.Context, clientIDOrKeyID any) (*models.APIKey, error) {
+			return key, nil
+		}
+		w.store.OnSetAPIKeyLastSeen = func(ctx context.Context, keyID ulid.ULID, lastSeen time.Time) error {
+			return nil
+		}
+
+		refresh := &auth.Claims{}
+		refresh.SetSubjectID(auth.SubjectAPIKey, key.ID)
+
+		//test
+		refresh.IssuedAt = jwt.NewNumericDate(rotated.Add(-time.Minute))
+		claims, err := w.s.RefreshClaims(ctx, refresh)
+		require.ErrorIs(err, auth.ErrSecretRotated, "expected tokens issued before the rotation to be rejected")
+		require.Nil(claims)
+
+		refresh.IssuedAt = nil
+		claims, err = w.s.RefreshClaims(ctx, refresh)
+		require.ErrorIs(err, auth.ErrSecretRotated, "expected tokens without an issued at timestamp to be rejected")
+		require.Nil(claims)
+		w.store.AssertCalls(w.T(), "SetAPIKeyLastSeen", 0)
+
+		refresh.IssuedAt = jwt.NewNumericDate(rotated.Add(time.Minute))
+		claims, err = w.s.RefreshClaims(ctx, refresh)
+		require.NoError(err, "expected tokens issued after the rotation to be refreshed")
+		require.Equal(refresh.Subject, claims.Subject)
+	})
+}
+
+func (w *webTestSuite) TestServerLogout() {
+	logout := func(cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/logout", nil)
+		for _, cookie := range cookies {
+			c.Request.AddCookie(cookie)
+		}
+
+		w.s.Logout(c)
+		return rec
+	}
+
+	w.Run("RevokeSession", func() {
+		//setup
+		require := w.Require()
+		userID, session := ulid.MakeSecure(), ulid.MakeSecure()
+		w.store.OnRevokeUserSession = func(ctx context.Context, uid, sid ulid.ULID) error {
+			require.Equal(userID, uid)
+			require.Equal(session, sid)
+			return nil
+		}
+
+		claims := &auth.Claims{Session: session.String()}
+		claims.SetSubjectID(auth.SubjectUser, userID)
+		access, refresh, err := w.s.Issuer().CreateTokens(claims)
+		require.NoError(err, "could not create tokens")
+
+		//test
+		rep := logout(&http.Cookie{Name: auth.AccessTokenCookie, Value: access}, &http.Cookie{Name: auth.RefreshTokenCookie, Value: refresh})
+		require.Equal(http.StatusSeeOther, rep.Code)
+		w.store.AssertCalls(w.T(), "RevokeUserSession", 1)
+
+		// The refresh token is used when the access token cookie has expired
+		rep = logout(&http.Cookie{Name: auth.RefreshTokenCookie, Value: refresh})
+		require.Equal(http.StatusSeeOther, rep.Code)
+		w.store.AssertCalls(w.T(), "RevokeUserSession", 2)
+	})
+
+	w.Run("InvalidToken", func() {
+		//setup
+		require := w.Require()
+		claims := &auth.Claims{Session: ulid.MakeSecure().String()}
+		claims.SetSubjectID(auth.SubjectUser, ulid.MakeSecure())
+		access, _, err := w.s.Issuer().CreateTokens(claims)
+		require.NoError(err, "could not create tokens")
+
+		//test
+		rep := logout(&http.Cookie{Name: auth.AccessTokenCookie, Value: access + "tampered"})
+		require.Equal(http.StatusSeeOther, rep.Code, "expected the user to be logged out")
+
+		rep = logout()
+		require.Equal(http.StatusSeeOther, rep.Code, "expected the user to be logged out")
+		w.store.AssertCalls(w.T(), "RevokeUserSession", 0)
+	})
+}
+
+func (w *webTestSuite) TestServerLoginLockout() {
+	password := "supersecretsquirrel"
+	newUser := func() *models.User {
+		dk, err := passwords.CreateDerivedKey(password)
+		w.Require().NoError(err, "could not create derived key")
+
+		user := &models.User{
+			Model:    models.Model{ID: ulid.MakeSecure()},
+			Email:    "locked@example.com",
+			Password: dk,
+		}
+		user.SetRole(&models.Role{ID: 2, Title: "Compliance"})
+		return user
+	}
+
+	w.Run("RecordFailure", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		user := newUser()
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+
+		var max int64
+		var lockout, maxLockout time.Duration
+		w.store.OnRecordUserLoginFailure = func(ctx context.Context, userID ulid.ULID, maxFailures int64, lockoutDuration, maxLockoutDuration time.Duration) error {
+			require.Equal(user.ID, userID)
+			max, lockout, maxLockout = maxFailures, lockoutDuration, maxLockoutDuration
+			return nil
+		}
+
+		//test
+		out, err := w.ClientNoAuth().Login(ctx, &api.LoginRequest{Email: user.Email, Password: "wrongpassword"})
+		require.ErrorContains(err, "invalid login credentials")
+		require.Nil(out)
+		require.Equal(int64(5), max, "expected the configured max failures")
+		require.Equal(5*time.Minute, lockout, "expected the configured lockout duration")
+		require.Equal(24*time.Hour, maxLockout, "expected the configured max lockout duration")
+		w.store.AssertCalls(w.T(), "RecordUserLoginFailure", 1)
+	})
+
+	w.Run("LockedOut", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		user := newUser()
+		user.LoginLocked = sql.NullTime{Valid: true, Time: time.Now().Add(5 * time.Minute)}
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+
+		//test
+		out, err := w.ClientNoAuth().Login(ctx, &api.LoginRequest{Email: user.Email, Password: password})
+		require.ErrorContains(err, "too many failed login attempts", "expected a valid password to be rejected while locked out")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "RecordUserLoginFailure", 0)
+		w.store.AssertCalls(w.T(), "SetUserLastLogin", 0)
+	})
+}
+
+func (w *webTestSuite) TestServerUnlockUser() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		userID := ulid.MakeSecure()
+
+		var calledWith ulid.ULID
+		w.store.OnUnlockUser = func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			calledWith = id
+			return nil
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).UnlockUser(ctx, userID)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(userID, calledWith, "expected the user to be unlocked")
+		w.store.AssertCalls(w.T(), "UnlockUser", 1)
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnUnlockUser = func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).UnlockUser(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "user not found")
+	})
+
+	w.Run("FailureNoPermissions", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:view"}).UnlockUser(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "user does not have permission to perform this operation", "the user should not be authorized")
+	})
+}
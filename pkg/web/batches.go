@@ -0,0 +1,479 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"go.rtnl.ai/ulid"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/postman"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+)
+
+//===========================================================================
+// Batch Import
+//===========================================================================
+
+func (s *Server) ListBatches(c *gin.Context) {
+	var (
+		err     error
+		batches []*models.Batch
+		out     *api.BatchList
+	)
+
+	if batches, err = s.store.ListBatches(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process batch list request"))
+		return
+	}
+
+	if out, err = api.NewBatchList(batches); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process batch list request"))
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/batches/list.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+// Imports a CSV or JSON Lines file of transfers as a batch. The UI uploads the file as
+// a multipart form; API clients post the file as the request body with the content
+// type of the file and an optional filename query parameter. Every row is validated
+// and the batch is created even if some rows are invalid so that the user can review
+// the errors; only the valid rows are sent when the batch is sent.
+func (s *Server) CreateBatch(c *gin.Context) {
+	var (
+		err      error
+		filename string
+		format   string
+		data     io.Reader
+		rows     []*api.BatchRow
+		batch    *models.Batch
+	)
+
+	if !s.conf.Batch.Enabled {
+		c.JSON(http.StatusNotFound, api.Error(ErrBatchDisabled))
+		return
+	}
+
+	if strings.HasPrefix(c.ContentType(), binding.MIMEMultipartPOSTForm) {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, api.Error("a batch file is required"))
+			return
+		}
+
+		filename = file.Filename
+		if format, err = api.ParseBatchFormat(filename, file.Header.Get("Content-Type")); err != nil {
+			c.JSON(http.StatusUnsupportedMediaType, api.Error(err))
+			return
+		}
+
+		f, err := file.Open()
+		if err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not read batch file"))
+			return
+		}
+		defer f.Close()
+		data = f
+	} else {
+		filename = c.Query("filename")
+		if format, err = api.ParseBatchFormat(filename, c.ContentType()); err != nil {
+			c.JSON(http.StatusUnsupportedMediaType, api.Error(err))
+			return
+		}
+		data = c.Request.Body
+	}
+
+	if rows, err = api.ParseBatch(format, data, s.conf.Batch.MaxRows); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	batch = &models.Batch{
+		Filename:  filename,
+		Format:    format,
+		CreatedBy: actorName(c),
+		Items:     make([]*models.BatchItem, 0, len(rows)),
+	}
+
+	for _, row := range rows {
+		item := &models.BatchItem{
+			Row:     row.Row,
+			Status:  enum.BatchItemPending,
+			Request: sql.NullString{Valid: row.Request != "", String: row.Request},
+		}
+
+		if row.Err != nil {
+			item.Status = enum.BatchItemInvalid
+			if item.Errors, err = json.Marshal(api.NewErrorDetail(row.Err)); err != nil {
+				c.Error(err)
+				c.JSON(http.StatusInternalServerError, api.Error("could not create batch"))
+				return
+			}
+		}
+
+		batch.Items = append(batch.Items, item)
+	}
+
+	if err = s.store.CreateBatch(c.Request.Context(), batch, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateBatch()"},
+	}); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not create batch"))
+		return
+	}
+
+	// Redirect the user to the batch detail page to review the import
+	if htmx.IsHTMXRequest(c) {
+		htmx.Redirect(c, http.StatusSeeOther, "/batches/"+batch.ID.String())
+		return
+	}
+
+	// Retrieve the batch to return the counts of each item status
+	s.batchResponse(c, http.StatusCreated, batch.ID)
+}
+
+func (s *Server) BatchDetail(c *gin.Context) {
+	var (
+		err   error
+		batch *models.Batch
+	)
+
+	if batch, err = s.retrieveBatch(c); err != nil {
+		return
+	}
+
+	s.batchResponse(c, http.StatusOK, batch.ID)
+}
+
+// Sends the valid transfers of the batch in the background; the response is returned
+// as soon as the batch begins sending and the progress of the batch is tracked by the
+// status of its items. Transfers are sent exactly as if they had been prepared and
+// sent individually by the user, so four-eyes approval rules may hold transfers and
+// transfers to unreachable counterparties may be added to the outbound queue.
+func (s *Server) SendBatch(c *gin.Context) {
+	var (
+		err    error
+		batch  *models.Batch
+		claims *auth.Claims
+	)
+
+	if !s.conf.Batch.Enabled {
+		c.JSON(http.StatusNotFound, api.Error(ErrBatchDisabled))
+		return
+	}
+
+	if batch, err = s.retrieveBatch(c); err != nil {
+		return
+	}
+
+	if claims, err = auth.GetClaims(c); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusUnauthorized, api.Error("no claims found for the request"))
+		return
+	}
+
+	// The background send is detached from the request so that it continues after
+	// the response is returned but retains the actor of the request for audit logs.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(c.Request.Context()))
+	sender := &batchSender{cancel: cancel, done: make(chan struct{})}
+
+	// Only one send of the batch can be in progress at a time; a batch that is marked
+	// as sending without a sender on this node was interrupted and is resumed.
+	if _, sending := s.batches.LoadOrStore(batch.ID, sender); sending || !batch.IsSendable() {
+		cancel()
+		if !sending {
+			s.batches.Delete(batch.ID)
+		}
+		c.JSON(http.StatusConflict, api.Error(ErrBatchNotSendable))
+		return
+	}
+
+	batch.Status = enum.BatchSending
+	if err = s.store.UpdateBatch(ctx, batch, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.SendBatch()"},
+	}); err != nil {
+		cancel()
+		s.batches.Delete(batch.ID)
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not send batch"))
+		return
+	}
+
+	go s.sendBatch(ctx, batch.ID, claims, sender)
+
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.BatchesUpdated)
+		return
+	}
+	s.batchResponse(c, http.StatusOK, batch.ID)
+}
+
+// Stops sending the batch; transfers that are being sent when the batch is canceled
+// are completed but no further transfers are sent. The batch can be sent again to
+// resume sending the remaining transfers. If the send was interrupted (e.g. the node
+// restarted while the batch was being sent) the batch is marked as canceled.
+func (s *Server) CancelBatch(c *gin.Context) {
+	var (
+		err   error
+		batch *models.Batch
+	)
+
+	if batch, err = s.retrieveBatch(c); err != nil {
+		return
+	}
+
+	if val, ok := s.batches.Load(batch.ID); ok {
+		// Wait for the in-flight transfers to complete so the response has the final counts
+		sender := val.(*batchSender)
+		sender.cancel()
+		<-sender.done
+	} else {
+		if batch.Status != enum.BatchSending {
+			c.JSON(http.StatusConflict, api.Error(ErrBatchNotSending))
+			return
+		}
+
+		batch.Status = enum.BatchCanceled
+		if err = s.store.UpdateBatch(c.Request.Context(), batch, &models.ComplianceAuditLog{
+			ChangeNotes: sql.NullString{Valid: true, String: "Server.CancelBatch()"},
+		}); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not cancel batch"))
+			return
+		}
+	}
+
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.BatchesUpdated)
+		return
+	}
+	s.batchResponse(c, http.StatusOK, batch.ID)
+}
+
+func (s *Server) batchResponse(c *gin.Context, status int, batchID ulid.ULID) {
+	var (
+		err   error
+		batch *models.Batch
+		items []*models.BatchItem
+		out   *api.Batch
+	)
+
+	ctx := c.Request.Context()
+	if batch, err = s.store.RetrieveBatch(ctx, batchID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process batch request"))
+		return
+	}
+
+	if items, err = s.store.ListBatchItems(ctx, batchID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process batch request"))
+		return
+	}
+
+	if out, err = api.NewBatch(batch, items); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process batch request"))
+		return
+	}
+
+	c.Negotiate(status, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/batches/detail.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+// Retrieves the batch identified by the id URL parameter; if an error is returned then
+// the error response has already been sent to the user.
+func (s *Server) retrieveBatch(c *gin.Context) (batch *models.Batch, err error) {
+	var batchID ulid.ULID
+	if batchID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("batch not found"))
+		return nil, err
+	}
+
+	if batch, err = s.store.RetrieveBatch(c.Request.Context(), batchID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("batch not found"))
+			return nil, err
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not retrieve batch"))
+		return nil, err
+	}
+	return batch, nil
+}
+
+//===========================================================================
+// Sending Batches
+//===========================================================================
+
+// Tracks a batch that is being sent in the background so it can be canceled.
+type batchSender struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Sends the pending items of the batch with at most the configured number of
+// transfers in flight at a time. When all items have been processed or the batch is
+// canceled the status of the batch is updated.
+func (s *Server) sendBatch(ctx context.Context, batchID ulid.ULID, claims *auth.Claims, sender *batchSender) {
+	defer close(sender.done)
+	defer s.batches.Delete(batchID)
+	defer sender.cancel()
+
+	// Transfers that have started sending are completed even if the batch is canceled
+	dctx := context.WithoutCancel(ctx)
+
+	items, err := s.store.ListBatchItems(dctx, batchID)
+	if err != nil {
+		// Mark the batch as canceled so that it can be sent again
+		log.Error().Err(err).Str("batch_id", batchID.String()).Msg("could not list batch items to send")
+		sender.cancel()
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, s.conf.Batch.Concurrency)
+
+items:
+	for _, item := range items {
+		if item.Status != enum.BatchItemPending {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break items
+		}
+
+		// Check again in case the batch was canceled while waiting for a slot
+		if ctx.Err() != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(item *models.BatchItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.sendBatchItem(dctx, item, claims)
+		}(item)
+	}
+
+	wg.Wait()
+
+	var batch *models.Batch
+	if batch, err = s.store.RetrieveBatch(dctx, batchID); err != nil {
+		log.Error().Err(err).Str("batch_id", batchID.String()).Msg("could not retrieve sent batch")
+		return
+	}
+
+	batch.Status = enum.BatchCompleted
+	if ctx.Err() != nil && batch.Pending > 0 {
+		batch.Status = enum.BatchCanceled
+	}
+
+	if err = s.store.UpdateBatch(dctx, batch, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: fmt.Sprintf("Server.sendBatch(): %s", batch.Status)},
+	}); err != nil {
+		log.Error().Err(err).Str("batch_id", batchID.String()).Msg("could not update sent batch status")
+	}
+}
+
+// Prepares and sends a single transfer of a batch and records the result on the item.
+func (s *Server) sendBatchItem(ctx context.Context, item *models.BatchItem, claims *auth.Claims) {
+	var (
+		err      error
+		in       *api.Prepare
+		prepared *api.Prepared
+		approval *models.Approval
+		payload  *trisa.Payload
+		packet   *postman.Packet
+		queued   bool
+	)
+
+	in = &api.Prepare{}
+	if err = json.Unmarshal([]byte(item.Request.String), in); err != nil {
+		s.batchItemResult(ctx, item, enum.BatchItemFailed, err)
+		return
+	}
+
+	if prepared, err = s.prepare(ctx, in); err != nil {
+		s.batchItemResult(ctx, item, enum.BatchItemFailed, err)
+		return
+	}
+
+	// If sending the transfer requires four-eyes approval it is held for a reviewer
+	if approval, _, err = s.requireApproval(ctx, enum.ApprovalActionSend, uuid.Nil, prepared, claimsActorName(claims)); err != nil {
+		s.batchItemResult(ctx, item, enum.BatchItemFailed, err)
+		return
+	}
+
+	if approval != nil {
+		s.batchItemResult(ctx, item, enum.BatchItemHeld, nil)
+		return
+	}
+
+	if payload, err = prepared.Payload(); err != nil {
+		s.batchItemResult(ctx, item, enum.BatchItemFailed, err)
+		return
+	}
+
+	if packet, queued, err = s.send(ctx, prepared.Routing, payload, nil); err != nil {
+		s.batchItemResult(ctx, item, enum.BatchItemFailed, err)
+		return
+	}
+
+	item.TransactionID = uuid.NullUUID{Valid: true, UUID: packet.Transaction.ID}
+	if queued {
+		s.batchItemResult(ctx, item, enum.BatchItemQueued, nil)
+		return
+	}
+	s.batchItemResult(ctx, item, enum.BatchItemSent, nil)
+}
+
+func (s *Server) batchItemResult(ctx context.Context, item *models.BatchItem, status enum.BatchItemStatus, err error) {
+	item.Status = status
+	item.Errors = nil
+
+	if err != nil {
+		item.Errors, _ = json.Marshal(api.NewErrorDetail(err))
+	}
+
+	if err = s.store.UpdateBatchItem(ctx, item); err != nil {
+		log.Error().Err(err).Str("batch_item_id", item.ID.String()).Msg("could not update batch item")
+	}
+}
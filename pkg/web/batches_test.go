@@ -0,0 +1,322 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+const batchCSV = `routing.protocol,routing.counterparty_id,originator.crypto_address,beneficiary.crypto_address,transfer.amount,transfer.network
+trisa,01HWR5VWW8V7ZFFVJVBEC7AV8A,n2nrF4Y5VKRTPzt8H5dVg1zoFpoFLnBVaH,mjJfCz6bhoAkVd9Ke3cF9nBZHPiHyX5Vq2,0.25,BTC
+trisa,,n2nrF4Y5VKRTPzt8H5dVg1zoFpoFLnBVaH,mjJfCz6bhoAkVd9Ke3cF9nBZHPiHyX5Vq2,ten,BTC
+`
+
+// Sets callbacks on the mock store that store the batch in memory. The batch is
+// guarded by a mutex since batches are sent in the background.
+func (w *webTestSuite) mockBatchStore() *mockBatch {
+	mu := &mockBatch{}
+	batch := &mu.batch
+
+	w.store.OnCreateBatch = func(ctx context.Context, in *models.Batch, auditLog *models.ComplianceAuditLog) error {
+		mu.Lock()
+		defer mu.Unlock()
+		in.ID = ulid.MakeSecure()
+		in.Status = enum.BatchValidated
+		in.Created = time.Now()
+		in.Modified = in.Created
+		for i, item := range in.Items {
+			item.ID = ulid.MakeSecure()
+			item.BatchID = in.ID
+			item.Created = in.Created
+			if i == 0 {
+				item.Request.String = `{"routing":{"protocol":"trisa","counterparty_id":"01HWR5VWW8V7ZFFVJVBEC7AV8A"}}`
+			}
+		}
+		*batch = *in
+		return nil
+	}
+
+	w.store.OnRetrieveBatch = func(ctx context.Context, id ulid.ULID) (*models.Batch, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if batch.ID.Compare(id) != 0 {
+			return nil, dberr.ErrNotFound
+		}
+
+		out := *batch
+		out.Total, out.Invalid, out.Pending, out.Failed = 0, 0, 0, 0
+		for _, item := range batch.Items {
+			out.Total++
+			switch item.Status {
+			case enum.BatchItemInvalid:
+				out.Invalid++
+			case enum.BatchItemPending:
+				out.Pending++
+			case enum.BatchItemFailed:
+				out.Failed++
+			}
+		}
+		return &out, nil
+	}
+
+	w.store.OnListBatchItems = func(ctx context.Context, batchID ulid.ULID) ([]*models.BatchItem, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		items := make([]*models.BatchItem, 0, len(batch.Items))
+		for _, item := range batch.Items {
+			cpy := *item
+			items = append(items, &cpy)
+		}
+		return items, nil
+	}
+
+	w.store.OnUpdateBatch = func(ctx context.Context, in *models.Batch, auditLog *models.ComplianceAuditLog) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batch.Status = in.Status
+		return nil
+	}
+
+	w.store.OnUpdateBatchItem = func(ctx context.Context, in *models.BatchItem) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, item := range batch.Items {
+			if item.ID.Compare(in.ID) == 0 {
+				*item = *in
+			}
+		}
+		return nil
+	}
+
+	return mu
+}
+
+type mockBatch struct {
+	sync.Mutex
+	batch models.Batch
+}
+
+func (m *mockBatch) Status() enum.BatchStatus {
+	m.Lock()
+	defer m.Unlock()
+	return m.batch.Status
+}
+
+func (w *webTestSuite) TestServerCreateBatch() {
+	w.Run("CSV", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.mockBatchStore()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CreateBatch(ctx, "transfers.csv", []byte(batchCSV))
+		require.NoError(err, "unexpected client request error")
+		require.Equal("transfers.csv", out.Filename)
+		require.Equal(api.BatchFormatCSV, out.Format)
+		require.Equal("validated", out.Status)
+		require.Equal(int64(2), out.Total)
+		require.Equal(int64(1), out.Pending)
+		require.Equal(int64(1), out.Invalid)
+		require.True(out.IsSendable())
+
+		require.Len(out.Items, 2)
+		require.Equal(int64(2), out.Items[0].Row)
+		require.Equal("pending", out.Items[0].Status)
+		require.Equal(int64(3), out.Items[1].Row)
+		require.Equal("invalid", out.Items[1].Status)
+		require.Len(out.Items[1].Errors, 2, "expected an error for the counterparty and the amount")
+	})
+
+	w.Run("JSONL", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.mockBatchStore()
+
+		data := `{"routing":{"protocol":"trisa","travel_address":"ta2TzS6Ss5Z2AaB9mBbQKF2NzS4bN5S1WE"},"originator":{"crypto_address":"abc"},"beneficiary":{"crypto_address":"def"},"transfer":{"amount":1,"network":"BTC"}}` + "\n"
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CreateBatch(ctx, "transfers.jsonl", []byte(data))
+		require.NoError(err, "unexpected client request error")
+		require.Equal(api.BatchFormatJSONL, out.Format)
+		require.Equal(int64(1), out.Pending)
+	})
+
+	w.Run("TooManyRows", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		data := "routing.protocol\n"
+		for i := 0; i < 11; i++ {
+			data += "trisa\n"
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CreateBatch(ctx, "transfers.csv", []byte(data))
+		require.ErrorContains(err, "the batch exceeds the maximum of 10 transfers")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "CreateBatch", 0)
+	})
+
+	w.Run("UnknownFormat", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CreateBatch(ctx, "transfers.xlsx", []byte(batchCSV))
+		require.ErrorIs(err, api.ErrUnknownBatchFormat)
+		require.Nil(out)
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).CreateBatch(ctx, "transfers.csv", []byte(batchCSV))
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerSendBatch() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		client := w.ClientWithPermissions([]string{"travelrule:view", "travelrule:manage"})
+		batch := w.mockBatchStore()
+
+		done := make(chan *models.BatchItem, 1)
+		update := w.store.OnUpdateBatchItem
+		w.store.OnUpdateBatchItem = func(ctx context.Context, item *models.BatchItem) error {
+			done <- item
+			return update(ctx, item)
+		}
+
+		created, err := client.CreateBatch(ctx, "transfers.csv", []byte(batchCSV))
+		require.NoError(err, "could not create batch")
+
+		//test
+		out, err := client.SendBatch(ctx, created.ID)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(created.ID, out.ID)
+
+		// The local party is not configured in tests so the transfer cannot be prepared
+		select {
+		case item := <-done:
+			require.Equal(enum.BatchItemFailed, item.Status)
+
+			var errs api.ErrorDetail
+			require.NoError(json.Unmarshal(item.Errors, &errs))
+			require.Len(errs, 1)
+			require.Contains(errs[0].Error, "could not complete prepare request")
+		case <-time.After(5 * time.Second):
+			require.Fail("timed out waiting for batch item to be sent")
+		}
+
+		require.Eventually(func() bool {
+			cmp, err := client.BatchDetail(ctx, created.ID)
+			return err == nil && cmp.Status == "completed"
+		}, 5*time.Second, 10*time.Millisecond, "expected batch to be completed")
+		require.Equal(enum.BatchCompleted, batch.Status())
+
+		// The completed batch cannot be sent again
+		_, err = client.SendBatch(ctx, created.ID)
+		require.ErrorContains(err, "[409]")
+
+		// The batch is not being sent so it cannot be canceled
+		_, err = client.CancelBatch(ctx, created.ID)
+		require.ErrorContains(err, "the batch is not currently being sent")
+	})
+
+	w.Run("Interrupted", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		client := w.ClientWithPermissions([]string{"travelrule:view", "travelrule:manage"})
+		batch := w.mockBatchStore()
+
+		created, err := client.CreateBatch(ctx, "transfers.csv", []byte(batchCSV))
+		require.NoError(err, "could not create batch")
+
+		// Simulate a batch that was being sent when the node stopped
+		interrupt := func() {
+			batch.Lock()
+			defer batch.Unlock()
+			batch.batch.Status = enum.BatchSending
+		}
+
+		//test
+		interrupt()
+		out, err := client.CancelBatch(ctx, created.ID)
+		require.NoError(err, "expected interrupted batch to be canceled")
+		require.Equal("canceled", out.Status)
+		require.Equal(enum.BatchCanceled, batch.Status())
+
+		interrupt()
+		out, err = client.SendBatch(ctx, created.ID)
+		require.NoError(err, "expected interrupted batch to be resumed")
+		require.Equal(created.ID, out.ID)
+
+		require.Eventually(func() bool {
+			return batch.Status() == enum.BatchCompleted
+		}, 5*time.Second, 10*time.Millisecond, "expected resumed batch to be completed")
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveBatch = func(ctx context.Context, id ulid.ULID) (*models.Batch, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).SendBatch(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "batch not found")
+		require.Nil(out)
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).SendBatch(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerListBatches() {
+	//setup
+	require := w.Require()
+	ctx := context.Background()
+	w.store.OnListBatches = func(ctx context.Context) ([]*models.Batch, error) {
+		return []*models.Batch{
+			{Model: models.Model{ID: ulid.MakeSecure()}, Filename: "transfers.csv", Format: "csv", Status: enum.BatchSending, Total: 4, Sent: 2, Pending: 2},
+		}, nil
+	}
+
+	//test
+	out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListBatches(ctx)
+	require.NoError(err, "unexpected client request error")
+	require.Len(out.Batches, 1)
+	require.Equal("sending", out.Batches[0].Status)
+	require.True(out.Batches[0].IsSending())
+	require.Equal(int64(2), out.Batches[0].Processed())
+	require.Empty(out.Batches[0].Items)
+}
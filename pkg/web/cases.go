@@ -0,0 +1,245 @@
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+)
+
+//===========================================================================
+// Transaction Notes
+//===========================================================================
+
+func (s *Server) ListTransactionNotes(c *gin.Context) {
+	var (
+		err           error
+		transactionID uuid.UUID
+		notes         []*models.TransactionNote
+		out           *api.TransactionNoteList
+	)
+
+	if transactionID, err = uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+		return
+	}
+
+	if notes, err = s.store.ListTransactionNotes(c.Request.Context(), transactionID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process transaction notes request"))
+		return
+	}
+
+	if out, err = api.NewTransactionNoteList(transactionID, notes); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process transaction notes request"))
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/transactions/notes.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+func (s *Server) CreateTransactionNote(c *gin.Context) {
+	var (
+		err           error
+		transactionID uuid.UUID
+		in            *api.TransactionNote
+		note          *models.TransactionNote
+		out           *api.TransactionNote
+	)
+
+	if transactionID, err = uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+		return
+	}
+
+	in = &api.TransactionNote{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse transaction note"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if note, err = in.Model(transactionID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	// Record the name of the user or api key that posted the note
+	note.Author = actorName(c)
+
+	if err = s.store.CreateTransactionNote(c.Request.Context(), note, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateTransactionNote()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create transaction note request"))
+		return
+	}
+
+	// If this is an HTMX request, trigger the notes updated event to reload the thread
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.TransactionNotesUpdated)
+		return
+	}
+
+	if out, err = api.NewTransactionNote(note); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create transaction note request"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+//===========================================================================
+// Transaction Assignment
+//===========================================================================
+
+func (s *Server) AssignTransaction(c *gin.Context) {
+	var (
+		err           error
+		transactionID uuid.UUID
+		in            *api.TransactionAssignment
+		out           *api.Transaction
+	)
+
+	if transactionID, err = uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+		return
+	}
+
+	in = &api.TransactionAssignment{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse transaction assignment"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if err = s.store.AssignTransaction(c.Request.Context(), transactionID, in.Assignee(), &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.AssignTransaction()"},
+	}); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound):
+			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+		case errors.Is(err, dberr.ErrUnknownAssignee):
+			c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not process transaction assignment request"))
+		}
+		return
+	}
+
+	// If this is an HTMX request, trigger the transactions updated event to reload the detail
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.TransactionsUpdated)
+		return
+	}
+
+	if out, err = s.retrieveTransaction(c); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process transaction assignment request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+//===========================================================================
+// Transaction Tags
+//===========================================================================
+
+func (s *Server) ListTransactionTags(c *gin.Context) {
+	var (
+		err  error
+		tags []string
+	)
+
+	if tags, err = s.store.ListTransactionTags(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process transaction tags request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, &api.TransactionTags{Tags: tags})
+}
+
+func (s *Server) SetTransactionTags(c *gin.Context) {
+	var (
+		err           error
+		transactionID uuid.UUID
+		in            *api.TransactionTags
+	)
+
+	if transactionID, err = uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+		return
+	}
+
+	in = &api.TransactionTags{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse transaction tags"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if err = s.store.SetTransactionTags(c.Request.Context(), transactionID, in.Tags, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.SetTransactionTags()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process transaction tags request"))
+		return
+	}
+
+	// If this is an HTMX request, trigger the transactions updated event to reload the detail
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.TransactionsUpdated)
+		return
+	}
+
+	c.JSON(http.StatusOK, in)
+}
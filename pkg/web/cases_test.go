@@ -0,0 +1,229 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerTransactionNotes() {
+	txID := uuid.New()
+
+	w.Run("List", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListTransactionNotes = func(ctx context.Context, id uuid.UUID) ([]*models.TransactionNote, error) {
+			require.Equal(txID, id)
+			return []*models.TransactionNote{
+				{ID: ulid.MakeSecure(), TransactionID: id, Body: "requested source of funds", Author: "Compliance User", Created: time.Now()},
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListTransactionNotes(ctx, txID)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(txID, out.TransactionID)
+		require.Len(out.Notes, 1)
+		require.Equal("Compliance User", out.Notes[0].Author)
+	})
+
+	w.Run("ListNotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListTransactionNotes = func(ctx context.Context, id uuid.UUID) ([]*models.TransactionNote, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListTransactionNotes(ctx, txID)
+		require.ErrorContains(err, "transaction not found")
+		require.Nil(out)
+	})
+
+	w.Run("Create", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnCreateTransactionNote = func(ctx context.Context, note *models.TransactionNote, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(txID, note.TransactionID)
+			require.Equal("source of funds verified", note.Body)
+			require.NotEmpty(note.Author, "expected the author to be set from the claims")
+			require.Equal(sql.NullString{Valid: true, String: "Server.CreateTransactionNote()"}, auditLog.ChangeNotes)
+
+			note.ID = ulid.MakeSecure()
+			note.Created = time.Now()
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CreateTransactionNote(ctx, txID, &api.TransactionNote{Body: "  source of funds verified  "})
+		require.NoError(err, "unexpected client request error")
+		require.False(out.ID.IsZero())
+		require.Equal("source of funds verified", out.Body)
+		w.store.AssertCalls(w.T(), "CreateTransactionNote", 1)
+	})
+
+	w.Run("CreateInvalid", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CreateTransactionNote(ctx, txID, &api.TransactionNote{Body: " ", Author: "Mallory"})
+		require.ErrorContains(err, "2 validation errors occurred")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "CreateTransactionNote", 0)
+	})
+
+	w.Run("CreateNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).CreateTransactionNote(ctx, txID, &api.TransactionNote{Body: "note"})
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerAssignTransaction() {
+	txID := uuid.New()
+	userID := ulid.MakeSecure()
+
+	w.Run("Assign", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnAssignTransaction = func(ctx context.Context, id uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(txID, id)
+			require.Equal(ulid.NullULID{ULID: userID, Valid: true}, assigneeID)
+			return nil
+		}
+		w.store.OnRetrieveTransaction = func(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+			txn := &models.Transaction{ID: id, Source: enum.SourceRemote, Status: enum.StatusReview}
+			txn.SetAssignee(ulid.NullULID{ULID: userID, Valid: true}, "Compliance User")
+			return txn, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).AssignTransaction(ctx, txID, &api.TransactionAssignment{AssigneeID: userID.String()})
+		require.NoError(err, "unexpected client request error")
+		require.Equal(userID, out.AssigneeID)
+		require.Equal("Compliance User", out.Assignee)
+	})
+
+	w.Run("Unassign", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnAssignTransaction = func(ctx context.Context, id uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error {
+			require.False(assigneeID.Valid)
+			return nil
+		}
+		w.store.OnRetrieveTransaction = func(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+			return &models.Transaction{ID: id, Source: enum.SourceRemote, Status: enum.StatusReview}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).AssignTransaction(ctx, txID, &api.TransactionAssignment{})
+		require.NoError(err, "unexpected client request error")
+		require.True(out.AssigneeID.IsZero())
+		require.Empty(out.Assignee)
+	})
+
+	w.Run("UnknownAssignee", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnAssignTransaction = func(ctx context.Context, id uuid.UUID, assigneeID ulid.NullULID, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrUnknownAssignee
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).AssignTransaction(ctx, txID, &api.TransactionAssignment{AssigneeID: userID.String()})
+		require.ErrorContains(err, "transactions can only be assigned to existing users")
+		require.Nil(out)
+	})
+
+	w.Run("InvalidAssignee", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).AssignTransaction(ctx, txID, &api.TransactionAssignment{AssigneeID: "compliance"})
+		require.ErrorContains(err, "assignee must be the id of a user")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "AssignTransaction", 0)
+	})
+}
+
+func (w *webTestSuite) TestServerTransactionTags() {
+	txID := uuid.New()
+
+	w.Run("List", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListTransactionTags = func(ctx context.Context) ([]string, error) {
+			return []string{"review", "sanctions"}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListTransactionTags(ctx)
+		require.NoError(err, "unexpected client request error")
+		require.Equal([]string{"review", "sanctions"}, out.Tags)
+	})
+
+	w.Run("Set", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnSetTransactionTags = func(ctx context.Context, id uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(txID, id)
+			require.Equal([]string{"escalated", "sanctions"}, tags)
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).SetTransactionTags(ctx, txID, &api.TransactionTags{Tags: []string{"Sanctions", " escalated", "sanctions"}})
+		require.NoError(err, "unexpected client request error")
+		require.Equal([]string{"escalated", "sanctions"}, out.Tags)
+	})
+
+	w.Run("SetInvalid", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).SetTransactionTags(ctx, txID, &api.TransactionTags{Tags: []string{"a,b"}})
+		require.ErrorContains(err, "tags cannot contain commas")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "SetTransactionTags", 0)
+	})
+
+	w.Run("SetNotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnSetTransactionTags = func(ctx context.Context, id uuid.UUID, tags []string, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).SetTransactionTags(ctx, txID, &api.TransactionTags{Tags: []string{"review"}})
+		require.ErrorContains(err, "transaction not found")
+		require.Nil(out)
+	})
+}
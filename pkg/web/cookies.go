@@ -14,6 +14,12 @@ const (
 	ResetPasswordTokenCookie = "reset_password_token"
 	ResetPasswordPath        = "/v1/reset-password"
 	ResetPasswordTokenTTL    = 15 * time.Minute
+	InviteTokenCookie        = "invite_token"
+	AcceptInvitePath         = "/v1/accept-invite"
+	InviteTokenTTL           = 1 * time.Hour
+	OIDCRequestCookie        = "oidc_request"
+	OIDCRequestPath          = "/login/oidc"
+	OIDCRequestTTL           = 10 * time.Minute
 )
 
 func (s *Server) SetCookie(c *gin.Context, name, value, path string, maxAge int, httpOnly bool) {
@@ -35,6 +41,14 @@ func (s *Server) ClearResetPasswordTokenCookie(c *gin.Context) {
 	s.ClearCookie(c, ResetPasswordTokenCookie, ResetPasswordPath, true)
 }
 
+func (s *Server) SetInviteTokenCookie(c *gin.Context, token string) {
+	s.SetCookie(c, InviteTokenCookie, token, AcceptInvitePath, int(InviteTokenTTL.Seconds()), true)
+}
+
+func (s *Server) ClearInviteTokenCookie(c *gin.Context) {
+	s.ClearCookie(c, InviteTokenCookie, AcceptInvitePath, true)
+}
+
 func (s *Server) AddToastMessage(c *gin.Context, heading, message, toastType string) {
 	messages := s.ToastMessages(c)
 	messages = append(messages, scene.ToastMessage{Heading: heading, Message: message, Type: toastType})
@@ -9,6 +9,7 @@ import (
 	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
 	"github.com/trisacrypto/envoy/pkg/web/scene"
 
 	"github.com/gin-gonic/gin"
@@ -465,6 +466,11 @@ func (s *Server) DeleteCounterparty(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, dberr.ErrLegalHold) {
+			c.JSON(http.StatusConflict, api.Error("counterparty is under legal hold and cannot be deleted"))
+			return
+		}
+
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
 		return
@@ -477,6 +483,113 @@ func (s *Server) DeleteCounterparty(c *gin.Context) {
 	})
 }
 
+func (s *Server) CounterpartyPending(c *gin.Context) {
+	var (
+		err            error
+		counterpartyID ulid.ULID
+		counterparty   *models.Counterparty
+		out            *api.CounterpartyPending
+	)
+
+	// Parse the counterpartyID passed in from the URL
+	if counterpartyID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("counterparty not found"))
+		return
+	}
+
+	// Fetch the model from the database
+	if counterparty, err = s.store.RetrieveCounterparty(c.Request.Context(), counterpartyID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("counterparty not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error(err))
+		return
+	}
+
+	if out, err = api.NewCounterpartyPending(counterparty); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error(err))
+		return
+	}
+
+	// The node configuration is used for any pending fields that are not overridden
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/counterparties/pending.html",
+		HTMLData: scene.New(c).WithAPIData(out).With("PendingDefaults", s.conf.Node.Pending),
+	})
+}
+
+func (s *Server) UpdateCounterpartyPending(c *gin.Context) {
+	var (
+		err            error
+		counterpartyID ulid.ULID
+		in             *api.CounterpartyPending
+		overrides      *models.PendingOverrides
+	)
+
+	// Parse the counterpartyID passed in from the URL
+	if counterpartyID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("counterparty not found"))
+		return
+	}
+
+	in = &api.CounterpartyPending{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse pending response data"))
+		return
+	}
+
+	// Sanity check if the counterparty ID is specified in the request
+	if !in.CounterpartyID.IsZero() {
+		if err = CheckIDMatch(in.CounterpartyID, counterpartyID); err != nil {
+			c.Error(err)
+			c.JSON(http.StatusBadRequest, api.Error(err))
+			return
+		}
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if overrides, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	// Pending overrides can be set on counterparties from any source since they are
+	// not modified by the directory sync.
+	if err = s.store.UpdateCounterpartyPending(c.Request.Context(), counterpartyID, overrides, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.UpdateCounterpartyPending()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("counterparty not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not update counterparty pending response"))
+		return
+	}
+
+	// If this is an HTMX request, trigger the counterparties updated event to reload
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.CounterpartiesUpdated)
+		return
+	}
+
+	in.CounterpartyID = counterpartyID
+	c.JSON(http.StatusOK, in)
+}
+
 func (s *Server) ListContacts(c *gin.Context) {
 	var (
 		err            error
@@ -0,0 +1,152 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerCounterpartyPending() {
+	counterpartyID := ulid.MakeSecure()
+
+	w.Run("Detail", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveCounterparty = func(ctx context.Context, id ulid.ULID) (*models.Counterparty, error) {
+			require.Equal(counterpartyID, id)
+			return &models.Counterparty{
+				Model: models.Model{ID: id},
+				Name:  "Bob VASP",
+				Pending: models.PendingOverrides{
+					Language:      sql.NullString{Valid: true, String: "de"},
+					ReplyNotAfter: sql.NullInt64{Valid: true, Int64: 7200},
+				},
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"counterparties:view"}).CounterpartyPending(ctx, counterpartyID)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(counterpartyID, out.CounterpartyID)
+		require.Equal("de", out.Language)
+		require.Empty(out.Message)
+		require.Empty(out.ReplyNotBefore)
+		require.Equal("2h0m0s", out.ReplyNotAfter)
+	})
+
+	w.Run("DetailNotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveCounterparty = func(ctx context.Context, id ulid.ULID) (*models.Counterparty, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"counterparties:view"}).CounterpartyPending(ctx, counterpartyID)
+		require.ErrorContains(err, "counterparty not found")
+		require.Nil(out)
+	})
+
+	w.Run("Update", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnUpdateCounterpartyPending = func(ctx context.Context, id ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(counterpartyID, id)
+			require.Equal(sql.NullString{Valid: true, String: "fr-CA"}, overrides.Language)
+			require.Equal(sql.NullString{Valid: true, String: "{{ .Organization }} examine votre demande"}, overrides.Message)
+			require.Equal(sql.NullInt64{Valid: true, Int64: 0}, overrides.ReplyNotBefore)
+			require.Equal(sql.NullInt64{Valid: true, Int64: 172800}, overrides.ReplyNotAfter)
+			require.Equal(sql.NullString{Valid: true, String: "Server.UpdateCounterpartyPending()"}, auditLog.ChangeNotes)
+			return nil
+		}
+
+		//test
+		in := &api.CounterpartyPending{
+			Language:       "fr-ca",
+			Message:        " {{ .Organization }} examine votre demande ",
+			ReplyNotBefore: "0s",
+			ReplyNotAfter:  "48h",
+		}
+
+		out, err := w.ClientWithPermissions([]string{"counterparties:manage"}).UpdateCounterpartyPending(ctx, counterpartyID, in)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(counterpartyID, out.CounterpartyID)
+		require.Equal("fr-CA", out.Language)
+		w.store.AssertCalls(w.T(), "UpdateCounterpartyPending", 1)
+	})
+
+	w.Run("UpdateInvalid", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		in := &api.CounterpartyPending{
+			Language:       "not a language",
+			Message:        "{{ .Unknown }}",
+			ReplyNotBefore: "tomorrow",
+			ReplyNotAfter:  "-1h",
+		}
+
+		out, err := w.ClientWithPermissions([]string{"counterparties:manage"}).UpdateCounterpartyPending(ctx, counterpartyID, in)
+		require.ErrorContains(err, "4 validation errors occurred")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "UpdateCounterpartyPending", 0)
+	})
+
+	w.Run("UpdateWindowOrder", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		in := &api.CounterpartyPending{ReplyNotBefore: "1h", ReplyNotAfter: "30m"}
+		out, err := w.ClientWithPermissions([]string{"counterparties:manage"}).UpdateCounterpartyPending(ctx, counterpartyID, in)
+		require.ErrorContains(err, "reply not after window must not be before the reply not before window")
+		require.Nil(out)
+	})
+
+	w.Run("UpdateIDMismatch", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		in := &api.CounterpartyPending{CounterpartyID: ulid.MakeSecure()}
+		out, err := w.ClientWithPermissions([]string{"counterparties:manage"}).UpdateCounterpartyPending(ctx, counterpartyID, in)
+		require.Error(err, "expected an id mismatch error")
+		require.Nil(out)
+	})
+
+	w.Run("UpdateNotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnUpdateCounterpartyPending = func(ctx context.Context, id ulid.ULID, overrides *models.PendingOverrides, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"counterparties:manage"}).UpdateCounterpartyPending(ctx, counterpartyID, &api.CounterpartyPending{})
+		require.ErrorContains(err, "counterparty not found")
+		require.Nil(out)
+	})
+
+	w.Run("UpdateNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"counterparties:view"}).UpdateCounterpartyPending(ctx, counterpartyID, &api.CounterpartyPending{})
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
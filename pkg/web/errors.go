@@ -28,6 +28,18 @@ var (
 	ErrNotAllowed           = errors.New("the requested action is not allowed")
 	ErrExpiredToken         = errors.New("the verification token is expired")
 	ErrNoTransactionPayload = errors.New("no transaction payload found in latest secure envelope")
+	ErrPasswordLogin        = errors.New("password login is disabled, please use single sign-on")
+	ErrInvalidCredentials   = errors.New("invalid api credentials")
+	ErrLoginLocked          = errors.New("too many failed login attempts, please try again later")
+	ErrTransactionState     = errors.New("transaction is not in a state that allows the requested action")
+	ErrQueueDisabled        = errors.New("the outbound queue is disabled so transfers cannot be scheduled")
+	ErrNotQueued            = errors.New("the transfer is no longer waiting in the outbound queue")
+	ErrIdempotencyKeyReused = errors.New("the idempotency key has already been used with a different request")
+	ErrIdempotencyInFlight  = errors.New("a request with this idempotency key is already being processed")
+	ErrBatchDisabled        = errors.New("bulk transaction import is disabled")
+	ErrBatchNotSendable     = errors.New("the batch has already been sent or does not contain any valid transfers")
+	ErrBatchNotSending      = errors.New("the batch is not currently being sent")
+	ErrEvidenceUnsigned     = errors.New("the evidence bundle could not be signed with the node's signing key")
 )
 
 // Logs the error with c.Error and negotiates the response. If HTML is requested by the
@@ -77,3 +89,43 @@ func (s *Server) InternalError(c *gin.Context) {
 		JSONData: api.InternalError,
 	})
 }
+
+// ResponseError is returned by the transfer workflows that are shared by the API
+// handlers and by background tasks such as batch sending. It describes the response
+// that the handler should return to the user while wrapping the internal error so
+// that background tasks can record the message and inspect the cause.
+type ResponseError struct {
+	Code    int
+	Message string
+	Err     error
+}
+
+// Creates a response error; if the message is empty the message of err is used.
+func NewResponseError(code int, message string, err error) *ResponseError {
+	if message == "" && err != nil {
+		message = err.Error()
+	}
+	return &ResponseError{Code: code, Message: message, Err: err}
+}
+
+func (e *ResponseError) Error() string {
+	return e.Message
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Logs the error with c.Error and writes the JSON response described by the error.
+// Errors that are not response errors are returned as an internal server error.
+func (s *Server) ResponseError(c *gin.Context, err error) {
+	var rerr *ResponseError
+	if !errors.As(err, &rerr) {
+		rerr = NewResponseError(http.StatusInternalServerError, "could not complete request", err)
+	}
+
+	if rerr.Err != nil {
+		c.Error(rerr.Err)
+	}
+	c.JSON(rerr.Code, api.Error(rerr.Message))
+}
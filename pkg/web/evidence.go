@@ -0,0 +1,412 @@
+package web
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+	"github.com/trisacrypto/trisa/pkg/trisa/keys"
+	"google.golang.org/protobuf/proto"
+)
+
+const ContentTypeZIP = "application/zip"
+
+const evidenceReadme = `Transaction Evidence Bundle
+===========================
+
+This bundle contains the evidence recorded by the TRISA node for a single travel
+rule transaction:
+
+  transaction.json    the transaction record
+  envelopes/          each secure envelope as a raw protocol buffer (.pb) and
+                      as decrypted JSON (.json)
+  counterparty.json   the counterparty record and its certificate info
+  audit_logs.json     the compliance audit logs with their signed data
+  verification.json   the HMAC and signature verification results at export
+
+manifest.json lists every file with its SHA-256 hash and is signed with the node's
+signing key (RSA-PSS over the SHA-512 digest of the manifest). To verify the
+bundle offline, check the signature of the manifest then the hash of each file:
+
+  openssl dgst -sha512 -sigopt rsa_padding_mode:pss -sigopt rsa_pss_saltlen:auto \
+    -verify signing_key.pem -signature manifest.sig manifest.json
+
+  sha256sum transaction.json envelopes/* counterparty.json audit_logs.json verification.json
+`
+
+// TransactionEvidence exports a zip archive with all of the evidence for a single
+// transaction along with a signed manifest so that the bundle can be verified offline
+// (e.g. when it is handed to a regulator or an auditor). The bundle is created in
+// memory before it is returned so that a bundle that cannot be signed is never sent.
+func (s *Server) TransactionEvidence(c *gin.Context) {
+	var (
+		err           error
+		transactionID uuid.UUID
+		transaction   *models.Transaction
+		bundle        *evidenceBundle
+	)
+
+	if transactionID, err = uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if transaction, err = s.store.RetrieveTransaction(ctx, transactionID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not create transaction evidence bundle"))
+		return
+	}
+
+	bundle = &evidenceBundle{
+		manifest: &api.EvidenceManifest{
+			TransactionID:      transaction.ID,
+			Organization:       s.conf.Organization,
+			Generated:          time.Now().UTC(),
+			SignatureAlgorithm: audit.SignatureAlgorithm(),
+			Files:              make([]*api.EvidenceFile, 0, 8),
+		},
+		verification: &api.EvidenceVerification{
+			Envelopes: make([]*api.EnvelopeVerification, 0),
+			AuditLogs: make([]*api.AuditLogVerification, 0),
+		},
+	}
+
+	if claims, err := auth.GetClaims(c); err == nil {
+		bundle.manifest.GeneratedBy = claims.Subject
+	}
+
+	if bundle.manifest.KeyID, err = audit.VerificationKeyID(); err != nil {
+		c.Error(fmt.Errorf("could not identify evidence signing key: %w", err))
+		c.JSON(http.StatusServiceUnavailable, api.Error(ErrEvidenceUnsigned))
+		return
+	}
+
+	if err = s.collectEvidence(c, transaction, bundle); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not create transaction evidence bundle"))
+		return
+	}
+
+	var archive []byte
+	if archive, err = bundle.sign(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusServiceUnavailable, api.Error(ErrEvidenceUnsigned))
+		return
+	}
+
+	filename := fmt.Sprintf("evidence-%s.zip", transaction.ID)
+	c.Header(ContentDisposition, "attachment; filename="+filename)
+	c.Data(http.StatusOK, ContentTypeZIP, archive)
+}
+
+// Collects the transaction, envelopes, counterparty, and audit logs into the bundle
+// and verifies the HMAC signatures of the envelopes and the signatures of the logs.
+func (s *Server) collectEvidence(c *gin.Context, transaction *models.Transaction, bundle *evidenceBundle) (err error) {
+	ctx := c.Request.Context()
+
+	var tx *api.Transaction
+	if tx, err = api.NewTransaction(transaction); err != nil {
+		return err
+	}
+
+	if err = bundle.addJSON(api.EvidenceTransactionFile, tx); err != nil {
+		return err
+	}
+
+	// Secure envelopes in both raw and decrypted form
+	var page *models.SecureEnvelopePage
+	if page, err = s.store.ListSecureEnvelopes(ctx, transaction.ID, nil); err != nil {
+		return err
+	}
+
+	resourceIDs := make([]string, 0, len(page.Envelopes)+1)
+	resourceIDs = append(resourceIDs, transaction.ID.String())
+
+	for i, model := range page.Envelopes {
+		resourceIDs = append(resourceIDs, model.ID.String())
+		if err = s.collectEnvelope(i+1, model, bundle); err != nil {
+			return err
+		}
+	}
+
+	// Counterparty record and certificate info
+	counterparty := &api.EvidenceCounterparty{}
+	if transaction.CounterpartyID.Valid {
+		var model *models.Counterparty
+		if model, err = s.store.RetrieveCounterparty(ctx, transaction.CounterpartyID.ULID); err != nil {
+			if !errors.Is(err, dberr.ErrNotFound) {
+				return err
+			}
+		} else {
+			if counterparty.Counterparty, err = api.NewCounterparty(model, nil); err != nil {
+				return err
+			}
+
+			if model.Protocol == enum.ProtocolTRISA && model.CommonName != "" {
+				counterparty.Certificate, err = s.certificateInfo(model.CommonName)
+				if err != nil {
+					counterparty.CertificateError = err.Error()
+				}
+			}
+		}
+	}
+
+	if err = bundle.addJSON(api.EvidenceCounterpartyFile, counterparty); err != nil {
+		return err
+	}
+
+	// Compliance audit logs of the transaction and its secure envelopes
+	logs := &api.EvidenceAuditLogs{
+		Logs: make([]*api.EvidenceAuditLog, 0),
+		Keys: make(map[string]string),
+	}
+
+	for _, resourceID := range resourceIDs {
+		info := &models.ComplianceAuditLogPageInfo{
+			PageInfo:     models.PageInfo{PageSize: 50},
+			ResourceID:   resourceID,
+			DetailedLogs: true,
+		}
+
+		for {
+			var page *models.ComplianceAuditLogPage
+			if page, err = s.store.ListComplianceAuditLogs(ctx, info); err != nil {
+				return err
+			}
+
+			for _, model := range page.Logs {
+				logs.Logs = append(logs.Logs, &api.EvidenceAuditLog{
+					Log:       api.NewComplianceAuditLog(model),
+					Data:      model.Data(),
+					Signature: model.Signature,
+					KeyID:     model.KeyID,
+					Algorithm: model.Algorithm,
+				})
+
+				result := &api.AuditLogVerification{ID: model.ID, KeyID: model.KeyID}
+				if err := audit.Verify(model); err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Verified = true
+				}
+				bundle.verification.AuditLogs = append(bundle.verification.AuditLogs, result)
+
+				if _, ok := logs.Keys[model.KeyID]; !ok && model.KeyID != "" {
+					if key, err := audit.VerificationKeyPEM(model.KeyID); err == nil {
+						logs.Keys[model.KeyID] = string(key)
+					}
+				}
+			}
+
+			if page.Page == nil || page.Page.NextPageID.IsZero() {
+				break
+			}
+			info.NextPageID = page.Page.NextPageID
+		}
+	}
+
+	if err = bundle.addJSON(api.EvidenceAuditLogsFile, logs); err != nil {
+		return err
+	}
+
+	return bundle.addJSON(api.EvidenceVerificationFile, bundle.verification)
+}
+
+// Adds the raw protocol buffer and the decrypted JSON of a secure envelope to the
+// bundle. If the envelope cannot be decrypted the secure envelope is stored as JSON
+// instead and the error is recorded in the verification results.
+func (s *Server) collectEnvelope(seq int, model *models.SecureEnvelope, bundle *evidenceBundle) (err error) {
+	result := &api.EnvelopeVerification{
+		ID:          model.ID,
+		EnvelopeID:  model.EnvelopeID,
+		Direction:   model.Direction.String(),
+		IsError:     model.IsError,
+		PublicKeyID: model.PublicKey.String,
+	}
+	bundle.verification.Envelopes = append(bundle.verification.Envelopes, result)
+
+	if model.ValidHMAC.Valid {
+		result.StoredHMAC = &model.ValidHMAC.Bool
+	}
+
+	if model.Envelope == nil {
+		result.Error = "secure envelope has no stored payload"
+		return nil
+	}
+
+	name := fmt.Sprintf("%s%02d-%s", api.EvidenceEnvelopesDir, seq, model.ID)
+
+	// NOTE: the raw envelope must be serialized before it is decrypted since
+	// decrypting outgoing envelopes sets the encryption keys on the envelope.
+	var raw []byte
+	if raw, err = proto.Marshal(model.Envelope); err != nil {
+		return fmt.Errorf("could not marshal secure envelope %s: %w", model.ID, err)
+	}
+
+	if err = bundle.add(name+".pb", raw); err != nil {
+		return err
+	}
+
+	// Opening the envelope verifies the HMAC signature before decrypting it
+	var (
+		env *envelope.Envelope
+		out any
+	)
+
+	if env, err = s.Decrypt(model); err == nil {
+		result.Decrypted = true
+		if !model.IsError {
+			valid := true
+			result.ValidHMAC = &valid
+		}
+
+		if out, err = api.NewEnvelope(model, env); err == nil {
+			return bundle.addJSON(name+".json", out)
+		}
+	}
+
+	log.Debug().Err(err).Str("envelope_id", model.ID.String()).Msg("could not decrypt envelope for evidence bundle")
+	result.Error = err.Error()
+
+	if out, err = api.NewSecureEnvelope(model); err != nil {
+		return err
+	}
+	return bundle.addJSON(name+".json", out)
+}
+
+// Returns the certificate info of the sealing key cached for the counterparty.
+func (s *Server) certificateInfo(commonName string) (_ *api.CertificateInfo, err error) {
+	var (
+		key   keys.PublicKey
+		keyID string
+	)
+
+	if key, err = s.trisa.SealingKey(commonName); err != nil {
+		return nil, fmt.Errorf("no sealing key available for %s: %w", commonName, err)
+	}
+
+	if keyID, err = key.PublicKeySignature(); err != nil {
+		return nil, err
+	}
+
+	var info *trisa.SigningKey
+	if info, err = key.Proto(); err != nil {
+		return nil, err
+	}
+
+	return api.NewCertificateInfo(keyID, info), nil
+}
+
+//===========================================================================
+// Evidence Bundle
+//===========================================================================
+
+type evidenceBundle struct {
+	buf          bytes.Buffer
+	archive      *zip.Writer
+	manifest     *api.EvidenceManifest
+	verification *api.EvidenceVerification
+}
+
+// Adds a file to the archive, recording its size and hash in the manifest.
+func (b *evidenceBundle) add(name string, data []byte) (err error) {
+	if err = b.write(name, data); err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(data)
+	b.manifest.Files = append(b.manifest.Files, &api.EvidenceFile{
+		Name:   name,
+		Size:   len(data),
+		SHA256: hex.EncodeToString(hash[:]),
+	})
+	return nil
+}
+
+func (b *evidenceBundle) addJSON(name string, v any) (err error) {
+	var data []byte
+	if data, err = json.MarshalIndent(v, "", "  "); err != nil {
+		return fmt.Errorf("could not marshal %s: %w", name, err)
+	}
+	return b.add(name, data)
+}
+
+func (b *evidenceBundle) write(name string, data []byte) (err error) {
+	if b.archive == nil {
+		b.archive = zip.NewWriter(&b.buf)
+	}
+
+	var f io.Writer
+	if f, err = b.archive.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: b.manifest.Generated,
+	}); err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	return err
+}
+
+// Signs the manifest and adds it to the archive with the signature, the key that
+// verifies it, and the instructions to verify it, returning the completed archive.
+func (b *evidenceBundle) sign() (_ []byte, err error) {
+	var manifest, signature, key []byte
+	if manifest, err = json.MarshalIndent(b.manifest, "", "  "); err != nil {
+		return nil, err
+	}
+
+	if signature, err = audit.SignData(manifest); err != nil {
+		return nil, fmt.Errorf("could not sign evidence manifest: %w", err)
+	}
+
+	if key, err = audit.VerificationKeyPEM(b.manifest.KeyID); err != nil {
+		return nil, fmt.Errorf("could not export evidence signing key: %w", err)
+	}
+
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{api.EvidenceManifestFile, manifest},
+		{api.EvidenceSignatureFile, signature},
+		{api.EvidenceSigningKeyFile, key},
+		{api.EvidenceReadmeFile, []byte(evidenceReadme)},
+	}
+
+	for _, file := range files {
+		if err = b.write(file.name, file.data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = b.archive.Close(); err != nil {
+		return nil, err
+	}
+	return b.buf.Bytes(), nil
+}
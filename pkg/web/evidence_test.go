@@ -0,0 +1,237 @@
+package web_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/trisa/keychain"
+	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/keys"
+	"github.com/trisacrypto/trisa/pkg/trust"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerTransactionEvidence() {
+	perms := []string{"travelrule:manage", "counterparties:view"}
+
+	w.Run("Bundle", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.useAuditKeyChain()
+		defer audit.UseKeyChain(nil)
+
+		transaction := mock.GetSampleTransaction(true, false, false)
+		counterparty := mock.GetSampleCounterparty(true, false)
+		counterparty.ID = transaction.CounterpartyID.ULID
+		counterparty.Protocol = enum.ProtocolTRISA
+		counterparty.CommonName = "unknown.trisatest.dev"
+
+		rejection := mock.GetSampleSecureEnvelope(false, false)
+		rejection.IsError = true
+		rejection.Envelope = &trisa.SecureEnvelope{
+			Id:        rejection.EnvelopeID.String(),
+			Timestamp: time.Now().Format(time.RFC3339Nano),
+			Error:     &trisa.Error{Code: trisa.Error_COMPLIANCE_CHECK_FAIL, Message: "beneficiary not found"},
+		}
+
+		sealed := mock.GetSampleSecureEnvelope(false, false)
+		sealed.ValidHMAC = sql.NullBool{Valid: true, Bool: true}
+		sealed.Envelope = &trisa.SecureEnvelope{Id: sealed.EnvelopeID.String(), Payload: []byte("encrypted payload")}
+
+		log := mock.GetComplianceAuditLog(true, false)
+		log.ResourceID = transaction.ID[:]
+		require.NoError(audit.Sign(log), "could not sign audit log fixture")
+
+		w.store.OnRetrieveTransaction = func(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+			require.Equal(transaction.ID, id)
+			return transaction, nil
+		}
+
+		w.store.OnListSecureEnvelopes = func(ctx context.Context, txID uuid.UUID, page *models.PageInfo) (*models.SecureEnvelopePage, error) {
+			return &models.SecureEnvelopePage{Envelopes: []*models.SecureEnvelope{sealed, rejection}}, nil
+		}
+
+		w.store.OnRetrieveCounterparty = func(ctx context.Context, id ulid.ULID) (*models.Counterparty, error) {
+			require.Equal(counterparty.ID, id)
+			return counterparty, nil
+		}
+
+		w.store.OnListComplianceAuditLogs = func(ctx context.Context, page *models.ComplianceAuditLogPageInfo) (*models.ComplianceAuditLogPage, error) {
+			require.True(page.DetailedLogs, "expected detailed logs to be requested")
+			out := &models.ComplianceAuditLogPage{Logs: []*models.ComplianceAuditLog{}, Page: page}
+			if page.ResourceID == transaction.ID.String() {
+				out.Logs = append(out.Logs, log)
+			}
+			return out, nil
+		}
+
+		//test
+		buf := &bytes.Buffer{}
+		err := w.ClientWithPermissions(perms).TransactionEvidence(ctx, transaction.ID, buf)
+		require.NoError(err, "unexpected client request error")
+
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(err, "could not read evidence archive")
+
+		files := make(map[string][]byte)
+		for _, f := range archive.File {
+			rc, err := f.Open()
+			require.NoError(err)
+			files[f.Name], err = io.ReadAll(rc)
+			require.NoError(err)
+			rc.Close()
+		}
+
+		// Verify the manifest signature with the included public key
+		block, _ := pem.Decode(files[api.EvidenceSigningKeyFile])
+		require.NotNil(block, "expected a PEM encoded signing key")
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		require.NoError(err, "could not parse signing key")
+
+		digest := sha512.Sum512(files[api.EvidenceManifestFile])
+		err = rsa.VerifyPSS(pub.(*rsa.PublicKey), crypto.SHA512, digest[:], files[api.EvidenceSignatureFile], nil)
+		require.NoError(err, "could not verify the manifest signature")
+
+		// Verify the hash of every file in the manifest
+		manifest := &api.EvidenceManifest{}
+		require.NoError(json.Unmarshal(files[api.EvidenceManifestFile], manifest))
+		require.Equal(transaction.ID, manifest.TransactionID)
+		require.Equal("Envoy Testing", manifest.Organization)
+		require.Equal(audit.SignatureAlgorithm(), manifest.SignatureAlgorithm)
+		require.NotEmpty(manifest.KeyID)
+		require.Len(manifest.Files, 8)
+
+		for _, file := range manifest.Files {
+			data, ok := files[file.Name]
+			require.True(ok, "manifest file %s is missing from the archive", file.Name)
+			require.Len(data, file.Size)
+
+			hash := sha256.Sum256(data)
+			require.Equal(hex.EncodeToString(hash[:]), file.SHA256, "hash mismatch for %s", file.Name)
+		}
+
+		// The raw envelopes should be the serialized protocol buffers
+		pbs := 0
+		for name := range files {
+			if strings.HasPrefix(name, api.EvidenceEnvelopesDir) && strings.HasSuffix(name, ".pb") {
+				pbs++
+			}
+		}
+		require.Equal(2, pbs, "expected a raw protocol buffer for each envelope")
+
+		// Check the verification results
+		verification := &api.EvidenceVerification{}
+		require.NoError(json.Unmarshal(files[api.EvidenceVerificationFile], verification))
+		require.Len(verification.Envelopes, 2)
+
+		require.Equal(sealed.ID, verification.Envelopes[0].ID)
+		require.False(verification.Envelopes[0].Decrypted)
+		require.NotEmpty(verification.Envelopes[0].Error)
+		require.True(*verification.Envelopes[0].StoredHMAC)
+
+		require.Equal(rejection.ID, verification.Envelopes[1].ID)
+		require.True(verification.Envelopes[1].IsError)
+		require.True(verification.Envelopes[1].Decrypted)
+		require.Nil(verification.Envelopes[1].ValidHMAC)
+
+		require.Len(verification.AuditLogs, 1)
+		require.Equal(log.ID, verification.AuditLogs[0].ID)
+		require.True(verification.AuditLogs[0].Verified)
+
+		logs := &api.EvidenceAuditLogs{}
+		require.NoError(json.Unmarshal(files[api.EvidenceAuditLogsFile], logs))
+		require.Len(logs.Logs, 1)
+		require.Equal(log.Data(), logs.Logs[0].Data)
+		require.Contains(logs.Keys, log.KeyID)
+
+		// The counterparty sealing key is not cached by the mocked network
+		cp := &api.EvidenceCounterparty{}
+		require.NoError(json.Unmarshal(files[api.EvidenceCounterpartyFile], cp))
+		require.Equal(counterparty.ID, cp.Counterparty.ID)
+		require.Nil(cp.Certificate)
+		require.NotEmpty(cp.CertificateError)
+	})
+
+	w.Run("Unsigned", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		audit.UseKeyChain(nil)
+
+		w.store.OnRetrieveTransaction = func(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+			return mock.GetSampleTransaction(false, false, false), nil
+		}
+
+		//test
+		err := w.ClientWithPermissions(perms).TransactionEvidence(ctx, uuid.New(), io.Discard)
+		require.Error(err)
+
+		serr, ok := err.(*api.StatusError)
+		require.True(ok, "expected a status error")
+		require.Equal(http.StatusServiceUnavailable, serr.StatusCode)
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		w.store.OnRetrieveTransaction = func(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		err := w.ClientWithPermissions(perms).TransactionEvidence(ctx, uuid.New(), io.Discard)
+		require.ErrorContains(err, "transaction not found")
+	})
+
+	w.Run("NoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		err := w.ClientWithPermissions([]string{"travelrule:view"}).TransactionEvidence(ctx, uuid.New(), io.Discard)
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+	})
+}
+
+// Loads the audit keychain fixture so that the server can sign evidence bundles.
+func (w *webTestSuite) useAuditKeyChain() {
+	require := w.Require()
+
+	sz, err := trust.NewSerializer(false)
+	require.NoError(err, "could not create serializer to load fixture")
+
+	provider, err := sz.ReadFile("../audit/testdata/certs.pem")
+	require.NoError(err, "could not read test fixture")
+
+	certs, err := keys.FromProvider(provider)
+	require.NoError(err, "could not create key from provider")
+
+	kc, err := keychain.New(keychain.WithCacheDuration(1*time.Hour), keychain.WithDefaultKey(certs))
+	require.NoError(err, "could not create keychain")
+	audit.UseKeyChain(kc)
+}
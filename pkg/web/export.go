@@ -1,15 +1,27 @@
 package web
 
 import (
+	"bytes"
+	"context"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
-	"go.rtnl.ai/ulid"
+	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/xlsx"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
 )
 
 const (
@@ -17,79 +29,441 @@ const (
 	ContentType        = "Content-Type"
 	AcceptLength       = "Accept-Length"
 	ContentTypeCSV     = "text/csv"
+	ContentTypeJSONL   = "application/x-ndjson"
 )
 
-var TransactionsHeader = []string{
-	"ID", "Status", "Counterparty", "Originator", "Originator Address",
-	"Beneficiary", "Beneficiary Address", "Virtual Asset", "Amount",
-	"Last Update", "Created", "Number of Envelopes", "HMAC Signature",
-}
-
+// ExportTransactions streams the transactions that match the query filters to the
+// client as a CSV, JSON Lines, or XLSX file with the selected columns. Transactions are
+// read from a database iterator so that the export is not loaded into memory and the
+// latest payload envelope is only decrypted if a selected column requires it.
 func (s *Server) ExportTransactions(c *gin.Context) {
 	var (
 		err  error
-		page *models.TransactionPage
-		info *models.TransactionPageInfo
+		in   *api.TransactionExportQuery
+		iter models.TransactionIterator
+		out  exportWriter
 	)
 
+	in = &api.TransactionExportQuery{}
+	if err = c.BindQuery(in); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error("could not parse export query"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if iter, err = s.store.IterateTransactions(ctx, in.Filter()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not export transactions"))
+		return
+	}
+	defer iter.Close()
+
+	// Determine which columns require the payload to be fetched or decrypted
+	columns := make([]exportColumn, 0, len(in.Columns))
+	fetch, decrypt := false, false
+	for _, key := range in.Columns {
+		column := exportColumns[key]
+		columns = append(columns, column)
+		fetch = fetch || column.envelope || column.payload
+		decrypt = decrypt || column.payload
+	}
+
 	// Create the filename for export based on the current date
-	filename := fmt.Sprintf("transactions-%s.csv", time.Now().Format("2006-01-02"))
+	filename := fmt.Sprintf("transactions-%s.%s", time.Now().Format("2006-01-02"), in.Format)
 
 	// Prepare the header for writing
 	c.Header(ContentDisposition, "attachment; filename="+filename)
-	c.Header(ContentType, ContentTypeCSV)
+	switch in.Format {
+	case api.ExportFormatJSONL:
+		c.Header(ContentType, ContentTypeJSONL)
+	case api.ExportFormatXLSX:
+		c.Header(ContentType, xlsx.ContentType)
+	default:
+		c.Header(ContentType, ContentTypeCSV)
+	}
 	c.Writer.WriteHeader(http.StatusOK)
 
-	// Stream the csv to the specified endpoint
-	writer := csv.NewWriter(c.Writer)
-	defer writer.Flush()
-
-	// Write the header to the CSV file
-	if err = writer.Write(TransactionsHeader); err != nil {
+	// Stream the export to the client in the requested format
+	if out, err = newExportWriter(c.Writer, in); err != nil {
 		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error writing header to download stream: %w", err))
 		return
 	}
 
-	// Fetch 50 records per page from the database
-	info = &models.TransactionPageInfo{PageInfo: models.PageInfo{PageSize: 50, NextPageID: ulid.Null}}
+	for i := 1; iter.Next(); i++ {
+		record := &exportRecord{transaction: iter.Transaction()}
+		if fetch {
+			s.loadExportRecord(ctx, record, decrypt)
+		}
+
+		row := make([]any, len(columns))
+		for j, column := range columns {
+			row[j] = column.value(record)
+		}
 
-	// TODO: we'll probably want to load more information from the secure envelope
-	// besides what's in the transaction and if we do that, we'll want a transaction
-	// iterator with a link to the database rather than loading them a page at a time.
-transactionsIterator:
-	for {
-		if page, err = s.store.ListTransactions(c.Request.Context(), info); err != nil {
-			c.AbortWithError(http.StatusInternalServerError, err)
+		if err = out.Write(row); err != nil {
+			c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error writing row %d to download stream: %w", i, err))
 			return
 		}
+	}
+
+	if err = iter.Err(); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error iterating over transactions: %w", err))
+		return
+	}
+
+	if err = out.Close(); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error completing download stream: %w", err))
+		return
+	}
+}
+
+// Loads the latest payload envelope of the transaction and decrypts it if required. An
+// export should not fail because of a single transaction so errors are logged and the
+// payload columns are left empty.
+func (s *Server) loadExportRecord(ctx context.Context, record *exportRecord, decrypt bool) {
+	var err error
+	if record.envelope, err = s.store.LatestPayloadEnvelope(ctx, record.transaction.ID, enum.DirectionAny); err != nil {
+		if !errors.Is(err, dberr.ErrNotFound) {
+			log.Warn().Err(err).Str("transaction_id", record.transaction.ID.String()).Msg("could not fetch payload envelope for export")
+		}
+		return
+	}
+
+	if !decrypt {
+		return
+	}
+
+	var decrypted *envelope.Envelope
+	if decrypted, err = s.Decrypt(record.envelope); err != nil {
+		log.Debug().Err(err).Str("transaction_id", record.transaction.ID.String()).Msg("could not decrypt payload envelope for export")
+		return
+	}
+
+	if record.payload, err = api.NewEnvelope(record.envelope, decrypted); err != nil {
+		log.Debug().Err(err).Str("transaction_id", record.transaction.ID.String()).Msg("could not parse payload envelope for export")
+		return
+	}
+}
+
+//===========================================================================
+// Export Columns
+//===========================================================================
+
+// An exportRecord holds the data required to produce a row of the export.
+type exportRecord struct {
+	transaction *models.Transaction
+	envelope    *models.SecureEnvelope
+	payload     *api.Envelope
+}
+
+// An exportColumn extracts the value of a column from an export record. If envelope is
+// true the latest payload envelope is fetched; if payload is true it is also decrypted.
+type exportColumn struct {
+	envelope bool
+	payload  bool
+	value    func(*exportRecord) any
+}
+
+// Maps the api.ExportColumns keys to their value extractors.
+var exportColumns = map[string]exportColumn{
+	"id":                  {value: func(r *exportRecord) any { return r.transaction.ID.String() }},
+	"status":              {value: func(r *exportRecord) any { return r.transaction.Status.String() }},
+	"source":              {value: func(r *exportRecord) any { return r.transaction.Source.String() }},
+	"counterparty":        {value: func(r *exportRecord) any { return r.transaction.Counterparty }},
+	"counterparty_id":     {value: exportCounterpartyID},
+	"originator":          {value: func(r *exportRecord) any { return r.transaction.Originator.String }},
+	"originator_address":  {value: func(r *exportRecord) any { return r.transaction.OriginatorAddress.String }},
+	"originator_country":  {payload: true, value: exportOriginatorCountry},
+	"originator_vasp":     {payload: true, value: exportOriginatorVASP},
+	"originator_ivms101":  {payload: true, value: exportOriginatorIVMS101},
+	"beneficiary":         {value: func(r *exportRecord) any { return r.transaction.Beneficiary.String }},
+	"beneficiary_address": {value: func(r *exportRecord) any { return r.transaction.BeneficiaryAddress.String }},
+	"beneficiary_country": {payload: true, value: exportBeneficiaryCountry},
+	"beneficiary_vasp":    {payload: true, value: exportBeneficiaryVASP},
+	"beneficiary_ivms101": {payload: true, value: exportBeneficiaryIVMS101},
+	"virtual_asset":       {value: func(r *exportRecord) any { return r.transaction.VirtualAsset }},
+	"network":             {payload: true, value: exportNetwork},
+	"amount":              {value: func(r *exportRecord) any { return r.transaction.Amount }},
+	"tx_hash":             {payload: true, value: exportTxHash},
+	"tags":                {value: func(r *exportRecord) any { return r.transaction.Tags() }},
+	"assignee":            {value: func(r *exportRecord) any { return r.transaction.Assignee() }},
+	"last_update":         {value: exportLastUpdate},
+	"created":             {value: func(r *exportRecord) any { return r.transaction.Created }},
+	"modified":            {value: func(r *exportRecord) any { return r.transaction.Modified }},
+	"envelopes":           {value: func(r *exportRecord) any { return r.transaction.NumEnvelopes() }},
+	"hmac":                {envelope: true, value: exportHMAC},
+}
+
+func exportCounterpartyID(r *exportRecord) any {
+	if r.transaction.CounterpartyID.Valid {
+		return r.transaction.CounterpartyID.ULID.String()
+	}
+	return ""
+}
+
+func exportLastUpdate(r *exportRecord) any {
+	if r.transaction.LastUpdate.Valid {
+		return r.transaction.LastUpdate.Time
+	}
+	return nil
+}
+
+func exportHMAC(r *exportRecord) any {
+	if r.envelope == nil || !r.envelope.ValidHMAC.Valid {
+		return ""
+	}
+
+	if r.envelope.ValidHMAC.Bool {
+		return "valid"
+	}
+	return "invalid"
+}
+
+func exportOriginatorCountry(r *exportRecord) any {
+	if r.payload == nil {
+		return ""
+	}
+	return personCountry(r.payload.Originators())
+}
+
+func exportBeneficiaryCountry(r *exportRecord) any {
+	if r.payload == nil {
+		return ""
+	}
+	return personCountry(r.payload.Beneficiaries())
+}
+
+func exportOriginatorVASP(r *exportRecord) any {
+	if r.payload == nil {
+		return ""
+	}
+	return legalPersonName(r.payload.OriginatorVASP())
+}
+
+func exportBeneficiaryVASP(r *exportRecord) any {
+	if r.payload == nil {
+		return ""
+	}
+	return legalPersonName(r.payload.BeneficiaryVASP())
+}
+
+func exportOriginatorIVMS101(r *exportRecord) any {
+	if r.payload == nil || r.payload.Identity == nil || r.payload.Identity.Originator == nil {
+		return nil
+	}
+	return r.payload.Identity.Originator
+}
+
+func exportBeneficiaryIVMS101(r *exportRecord) any {
+	if r.payload == nil || r.payload.Identity == nil || r.payload.Identity.Beneficiary == nil {
+		return nil
+	}
+	return r.payload.Identity.Beneficiary
+}
+
+func exportNetwork(r *exportRecord) any {
+	if r.payload == nil {
+		return ""
+	}
+	if payload := r.payload.TransactionPayload(); payload != nil {
+		return payload.Network
+	}
+	return ""
+}
+
+func exportTxHash(r *exportRecord) any {
+	if r.payload == nil {
+		return ""
+	}
+	if payload := r.payload.TransactionPayload(); payload != nil {
+		return payload.Txid
+	}
+	return ""
+}
+
+// Returns the country of the first person: the country of residence or registration if
+// specified, otherwise the country of their primary address.
+func personCountry(persons []*ivms101.Person) string {
+	if len(persons) == 0 {
+		return ""
+	}
+
+	switch {
+	case persons[0].GetNaturalPerson() != nil:
+		if country := persons[0].GetNaturalPerson().CountryOfResidence; country != "" {
+			return country
+		}
+	case persons[0].GetLegalPerson() != nil:
+		if country := persons[0].GetLegalPerson().CountryOfRegistration; country != "" {
+			return country
+		}
+	}
+
+	if addr := api.FindPrimaryAddress(persons[0]); addr != nil {
+		return addr.Country
+	}
+	return ""
+}
+
+func legalPersonName(person *ivms101.LegalPerson) string {
+	if person == nil {
+		return ""
+	}
+
+	if idx := api.FindLegalName(person); idx >= 0 {
+		return person.Name.NameIdentifiers[idx].LegalPersonName
+	}
+	return ""
+}
 
-		for i, transaction := range page.Transactions {
-			record := []string{
-				transaction.ID.String(),
-				transaction.Status.String(),
-				transaction.Counterparty,
-				transaction.Originator.String,
-				transaction.OriginatorAddress.String,
-				transaction.Beneficiary.String,
-				transaction.BeneficiaryAddress.String,
-				transaction.VirtualAsset,
-				strconv.FormatFloat(transaction.Amount, 'f', -1, 64),
-				transaction.LastUpdate.Time.Format(time.RFC3339),
-				transaction.Created.Format(time.RFC3339),
-				strconv.FormatInt(transaction.NumEnvelopes(), 10),
-				"",
-			}
-
-			if err = writer.Write(record); err != nil {
-				c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error writing row %d to download stream: %w", i+1, err))
-				return
-			}
-		}
-
-		// If there is no next page ID, then stop iterating
-		info.NextPageID = page.Page.NextPageID
-		if info.NextPageID.IsZero() {
-			break transactionsIterator
+//===========================================================================
+// Export Writers
+//===========================================================================
+
+// An exportWriter writes rows of column values to the download stream in a specific
+// format. Close must be called to flush or complete the stream.
+type exportWriter interface {
+	Write(row []any) error
+	Close() error
+}
+
+// Creates the writer for the format of the query and writes the header if required.
+func newExportWriter(w io.Writer, in *api.TransactionExportQuery) (out exportWriter, err error) {
+	switch in.Format {
+	case api.ExportFormatJSONL:
+		return &jsonlExport{w: w, keys: in.Columns}, nil
+	case api.ExportFormatXLSX:
+		export := &xlsxExport{}
+		if export.w, err = xlsx.NewWriter(w, "Transactions"); err != nil {
+			return nil, err
+		}
+
+		header := make([]any, 0, len(in.Columns))
+		for _, h := range in.Headers() {
+			header = append(header, h)
+		}
+
+		if err = export.w.Write(header); err != nil {
+			return nil, err
+		}
+		return export, nil
+	default:
+		export := &csvExport{w: csv.NewWriter(w)}
+		if err = export.w.Write(in.Headers()); err != nil {
+			return nil, err
+		}
+		return export, nil
+	}
+}
+
+type csvExport struct {
+	w *csv.Writer
+}
+
+func (e *csvExport) Write(row []any) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		record[i] = exportString(value)
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExport) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Writes each row as a JSON object keyed by the column keys in the order the columns
+// were selected; IVMS101 columns are written as nested objects rather than strings.
+type jsonlExport struct {
+	w    io.Writer
+	keys []string
+}
+
+func (e *jsonlExport) Write(row []any) (err error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, value := range row {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		var key, data []byte
+		if key, err = json.Marshal(e.keys[i]); err != nil {
+			return err
+		}
+
+		if t, ok := value.(time.Time); ok && t.IsZero() {
+			value = nil
+		}
+
+		if data, err = json.Marshal(value); err != nil {
+			return err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
+	}
+	buf.WriteString("}\n")
+
+	_, err = e.w.Write(buf.Bytes())
+	return err
+}
+
+func (e *jsonlExport) Close() error {
+	return nil
+}
+
+type xlsxExport struct {
+	w *xlsx.Writer
+}
+
+func (e *xlsxExport) Write(row []any) error {
+	cells := make([]any, len(row))
+	for i, value := range row {
+		switch v := value.(type) {
+		case string, float64, int64, time.Time, nil:
+			cells[i] = v
+		default:
+			cells[i] = exportString(v)
+		}
+	}
+	return e.w.Write(cells)
+}
+
+func (e *xlsxExport) Close() error {
+	return e.w.Close()
+}
+
+// Formats a column value as a string for CSV and XLSX exports.
+func exportString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ", ")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
 		}
+		return string(data)
 	}
 }
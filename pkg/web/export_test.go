@@ -0,0 +1,212 @@
+package web_test
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerExportTransactions() {
+	perms := []string{"travelrule:manage"}
+	transactions := []*models.Transaction{
+		mock.GetSampleTransaction(true, false, false),
+		mock.GetSampleTransaction(false, false, false),
+	}
+
+	w.Run("DefaultCSV", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		iter := mock.NewTransactionIterator(transactions...)
+
+		w.store.OnIterateTransactions = func(ctx context.Context, filter *models.TransactionFilter) (models.TransactionIterator, error) {
+			return iter, nil
+		}
+
+		w.store.OnLatestPayloadEnvelope = func(ctx context.Context, txID uuid.UUID, direction enum.Direction) (*models.SecureEnvelope, error) {
+			env := mock.GetSampleSecureEnvelope(false, false)
+			env.ValidHMAC = sql.NullBool{Valid: txID == transactions[0].ID, Bool: true}
+			return env, nil
+		}
+
+		//test
+		buf := &bytes.Buffer{}
+		err := w.ClientWithPermissions(perms).Export(ctx, &api.TransactionExportQuery{}, buf)
+		require.NoError(err, "unexpected client request error")
+		require.True(iter.Closed, "expected the iterator to be closed")
+
+		records, err := csv.NewReader(buf).ReadAll()
+		require.NoError(err, "could not read csv export")
+		require.Len(records, 3)
+
+		header := (&api.TransactionExportQuery{Columns: api.DefaultExportColumns}).Headers()
+		require.Equal(header, records[0])
+		require.Equal(transactions[0].ID.String(), records[1][0])
+		require.Equal(transactions[0].Originator.String, records[1][3])
+		require.Equal("0.123456", records[1][8])
+		require.Equal("valid", records[1][12])
+		require.Equal("", records[2][9], "expected a null last update to be empty")
+		require.Equal("", records[2][12])
+	})
+
+	w.Run("JSONL", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		w.store.OnIterateTransactions = func(ctx context.Context, filter *models.TransactionFilter) (models.TransactionIterator, error) {
+			return mock.NewTransactionIterator(transactions...), nil
+		}
+
+		//test
+		buf := &bytes.Buffer{}
+		in := &api.TransactionExportQuery{Format: "jsonl", Columns: []string{"amount", "id", "tags", "counterparty_id"}}
+		err := w.ClientWithPermissions(perms).Export(ctx, in, buf)
+		require.NoError(err, "unexpected client request error")
+
+		scanner := bufio.NewScanner(buf)
+		for i := 0; scanner.Scan(); i++ {
+			require.Less(i, len(transactions))
+			require.Regexp(`^\{"amount":.*,"id":.*,"tags":.*,"counterparty_id":.*\}$`, scanner.Text(), "expected the keys in the selected column order")
+
+			row := make(map[string]any)
+			require.NoError(json.Unmarshal(scanner.Bytes(), &row))
+			require.Len(row, 4)
+			require.Equal(transactions[i].ID.String(), row["id"])
+			require.Equal(transactions[i].Amount, row["amount"])
+		}
+	})
+
+	w.Run("AllColumns", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		w.store.OnIterateTransactions = func(ctx context.Context, filter *models.TransactionFilter) (models.TransactionIterator, error) {
+			return mock.NewTransactionIterator(transactions...), nil
+		}
+
+		w.store.OnLatestPayloadEnvelope = func(ctx context.Context, txID uuid.UUID, direction enum.Direction) (*models.SecureEnvelope, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		in := &api.TransactionExportQuery{Format: "jsonl"}
+		for _, column := range api.ExportColumns {
+			in.Columns = append(in.Columns, column.Key)
+		}
+
+		//test
+		buf := &bytes.Buffer{}
+		err := w.ClientWithPermissions(perms).Export(ctx, in, buf)
+		require.NoError(err, "unexpected client request error")
+
+		row := make(map[string]any)
+		require.NoError(json.NewDecoder(buf).Decode(&row))
+		require.Len(row, len(api.ExportColumns))
+		require.Nil(row["originator_ivms101"], "expected no payload when the envelope is not found")
+		require.Equal("", row["tx_hash"])
+	})
+
+	w.Run("XLSX", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		w.store.OnIterateTransactions = func(ctx context.Context, filter *models.TransactionFilter) (models.TransactionIterator, error) {
+			return mock.NewTransactionIterator(transactions...), nil
+		}
+
+		//test
+		buf := &bytes.Buffer{}
+		in := &api.TransactionExportQuery{Format: "xlsx", Columns: []string{"id", "amount", "created"}}
+		err := w.ClientWithPermissions(perms).Export(ctx, in, buf)
+		require.NoError(err, "unexpected client request error")
+
+		archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(err, "expected a valid xlsx workbook")
+
+		var sheet []byte
+		for _, f := range archive.File {
+			if f.Name == "xl/worksheets/sheet1.xml" {
+				rc, err := f.Open()
+				require.NoError(err)
+				sheet, err = io.ReadAll(rc)
+				require.NoError(err)
+				rc.Close()
+			}
+		}
+
+		require.Contains(string(sheet), ">Amount</t>")
+		require.Contains(string(sheet), transactions[1].ID.String())
+		require.Contains(string(sheet), "<v>0.123456</v>")
+	})
+
+	w.Run("Filters", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		counterpartyID := ulid.MakeSecure()
+		after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		before := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+		w.store.OnIterateTransactions = func(ctx context.Context, filter *models.TransactionFilter) (models.TransactionIterator, error) {
+			require.Equal([]string{"completed", "rejected"}, filter.Status)
+			require.Equal([]string{"BTC"}, filter.VirtualAsset)
+			require.Equal(counterpartyID, filter.CounterpartyID)
+			require.True(after.Equal(filter.After))
+			require.True(before.Equal(filter.Before))
+			require.True(filter.Archives)
+			return mock.NewTransactionIterator(), nil
+		}
+
+		//test
+		in := &api.TransactionExportQuery{
+			Status:       []string{"completed", "rejected"},
+			VirtualAsset: []string{"btc"},
+			Counterparty: counterpartyID.String(),
+			After:        &after,
+			Before:       &before,
+			Archives:     true,
+		}
+		err := w.ClientWithPermissions(perms).Export(ctx, in, io.Discard)
+		require.NoError(err, "unexpected client request error")
+	})
+
+	w.Run("Invalid", func() {
+		tests := []*api.TransactionExportQuery{
+			{Format: "pdf"},
+			{Columns: []string{"id", "secret"}},
+			{Status: []string{"foo"}},
+			{Counterparty: "foo"},
+		}
+
+		for _, in := range tests {
+			err := w.ClientWithPermissions(perms).Export(context.Background(), in, io.Discard)
+			w.Require().Error(err)
+
+			serr, ok := err.(*api.StatusError)
+			w.Require().True(ok, "expected a status error")
+			w.Require().Equal(http.StatusUnprocessableEntity, serr.StatusCode)
+		}
+	})
+
+	w.Run("NoPermission", func() {
+		err := w.ClientWithPermissions([]string{"travelrule:view"}).Export(context.Background(), &api.TransactionExportQuery{}, io.Discard)
+		w.Require().ErrorContains(err, "user does not have permission to perform this operation")
+	})
+}
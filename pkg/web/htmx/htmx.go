@@ -37,12 +37,20 @@ const HXTrigger = "HX-Trigger" // the id of the triggered element if it exists i
 
 // Envoy-specific HTMX events
 const (
-	TransactionsUpdated    = "transactions-updated"
-	AccountsUpdated        = "accounts-updated"
-	CryptoAddressesUpdated = "crypto-addresses-updated"
-	CounterpartiesUpdated  = "counterparties-updated"
-	UsersUpdated           = "users-updated"
-	APIKeysUpdated         = "apikeys-updated"
+	TransactionsUpdated     = "transactions-updated"
+	TransactionNotesUpdated = "transaction-notes-updated"
+	AccountsUpdated         = "accounts-updated"
+	CryptoAddressesUpdated  = "crypto-addresses-updated"
+	CounterpartiesUpdated   = "counterparties-updated"
+	UsersUpdated            = "users-updated"
+	InvitesUpdated          = "invites-updated"
+	RolesUpdated            = "roles-updated"
+	APIKeysUpdated          = "apikeys-updated"
+	LegalHoldsUpdated       = "legalholds-updated"
+	ApprovalsUpdated        = "approvals-updated"
+	QueueUpdated            = "queue-updated"
+	BatchesUpdated          = "batches-updated"
+	ReportsUpdated          = "reports-updated"
 )
 
 // Redirect determines if the request is an HTMX request, if so, it sets the HX-Redirect
@@ -0,0 +1,151 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"github.com/trisacrypto/envoy/pkg/audit"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	MaxIdempotencyKeyLength  = 255
+
+	contextIdempotentTransaction = "idempotent_transaction"
+)
+
+// Returns a middleware that makes the route safe to retry when the request has an
+// Idempotency-Key header. The first successful response for a key is stored for the
+// configured window and replayed (with an Idempotent-Replayed header) when the request
+// is retried with the same key. Reusing a key with a different request, or while the
+// original request is still being handled, returns a 409 Conflict. Requests without
+// the header are handled normally.
+//
+// This middleware must be used after authentication since keys are scoped to the
+// actor that made the request.
+func (s *Server) Idempotent() gin.HandlerFunc {
+	conf := s.conf.Idempotency
+	return func(c *gin.Context) {
+		var (
+			err     error
+			key     string
+			actorID []byte
+			body    []byte
+			ok      bool
+		)
+
+		if key = c.GetHeader(IdempotencyKeyHeader); key == "" || !conf.Enabled {
+			c.Next()
+			return
+		}
+
+		if len(key) > MaxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, api.Error("idempotency key must be at most 255 characters"))
+			return
+		}
+
+		if actorID, ok = audit.ActorID(c.Request.Context()); !ok {
+			c.Next()
+			return
+		}
+
+		// Read the body to compute the request hash then restore it for the handler
+		if body, err = io.ReadAll(c.Request.Body); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, api.Error("could not read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method))
+		hash.Write([]byte(c.Request.URL.Path))
+		hash.Write(body)
+		requestHash := hash.Sum(nil)
+
+		// Only one request with the key may be handled at a time
+		inflight := string(actorID) + ":" + key
+		if _, loaded := s.idempotent.LoadOrStore(inflight, struct{}{}); loaded {
+			c.AbortWithStatusJSON(http.StatusConflict, api.Error(ErrIdempotencyInFlight))
+			return
+		}
+		defer s.idempotent.Delete(inflight)
+
+		var stored *models.IdempotencyKey
+		if stored, err = s.store.RetrieveIdempotencyKey(c.Request.Context(), actorID, key); err != nil && !errors.Is(err, dberr.ErrNotFound) {
+			c.Error(err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error("could not process idempotency key"))
+			return
+		}
+
+		if stored != nil {
+			if !bytes.Equal(stored.RequestHash, requestHash) {
+				c.AbortWithStatusJSON(http.StatusConflict, api.Error(ErrIdempotencyKeyReused))
+				return
+			}
+
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.Status, stored.ContentType, stored.Response)
+			c.Abort()
+			return
+		}
+
+		// Capture the response so that it can be stored after the handler is done
+		writer := &idempotentWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Only successful responses are stored so that failed requests can be retried
+		if status := writer.Status(); status >= 200 && status < 300 {
+			record := &models.IdempotencyKey{
+				Key:         key,
+				ActorID:     actorID,
+				RequestHash: requestHash,
+				Status:      status,
+				ContentType: writer.Header().Get("Content-Type"),
+				Response:    writer.body.Bytes(),
+				Expires:     time.Now().Add(conf.Window),
+			}
+
+			if transactionID, ok := c.Get(contextIdempotentTransaction); ok {
+				record.TransactionID = uuid.NullUUID{Valid: true, UUID: transactionID.(uuid.UUID)}
+			}
+
+			if err = s.store.CreateIdempotencyKey(c.Request.Context(), record); err != nil {
+				log.Warn().Err(err).Str("idempotency_key", key).Msg("could not store idempotent response")
+			}
+		}
+	}
+}
+
+// Records the transaction described by the response to an idempotent request so that
+// the stored response is deleted when the transaction is purged or crypto-shredded.
+func setIdempotentTransaction(c *gin.Context, transactionID uuid.UUID) {
+	c.Set(contextIdempotentTransaction, transactionID)
+}
+
+// Captures the body of the response written by the handler in addition to writing it.
+type idempotentWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotentWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotentWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,115 @@
+package web_test
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerIdempotencyKey() {
+	transactionID := uuid.New()
+	rejection := &api.Rejection{Code: "COMPLIANCE_CHECK_FAIL", Message: "sanctioned beneficiary"}
+
+	// Rejections are held for approval so that the handler has a side effect that
+	// does not require a remote counterparty.
+	setup := func() {
+		w.store.OnListApprovalRules = func(ctx context.Context) ([]*models.ApprovalRule, error) {
+			return []*models.ApprovalRule{{Model: models.Model{ID: ulid.MakeSecure()}, Description: "all rejections", Action: enum.ApprovalActionReject}}, nil
+		}
+		w.store.OnRetrieveTransaction = func(ctx context.Context, id uuid.UUID) (*models.Transaction, error) {
+			return &models.Transaction{ID: id, Status: enum.StatusReview, Counterparty: "AliceVASP"}, nil
+		}
+		w.store.OnListApprovals = func(ctx context.Context, page *models.ApprovalPageInfo) (*models.ApprovalPage, error) {
+			return &models.ApprovalPage{Page: page}, nil
+		}
+		w.store.OnCreateApproval = func(ctx context.Context, approval *models.Approval, auditLog *models.ComplianceAuditLog) error {
+			approval.ID = ulid.MakeSecure()
+			approval.Status = enum.ApprovalPending
+			return nil
+		}
+		w.store.OnListApprovalReviewers = func(ctx context.Context) ([]*models.User, error) {
+			return nil, nil
+		}
+	}
+
+	w.Run("Replay", func() {
+		//setup
+		require := w.Require()
+		setup()
+		ctx := api.ContextWithIdempotencyKey(context.Background(), uuid.NewString())
+		client := w.ClientWithPermissions([]string{"travelrule:manage"})
+
+		// The stored response must be linked to the transaction so it is purged with it
+		createIdempotencyKey := w.store.OnCreateIdempotencyKey
+		w.store.OnCreateIdempotencyKey = func(ctx context.Context, record *models.IdempotencyKey) error {
+			require.Equal(uuid.NullUUID{Valid: true, UUID: transactionID}, record.TransactionID)
+			return createIdempotencyKey(ctx, record)
+		}
+
+		//test
+		_, err := client.Reject(ctx, transactionID, rejection)
+		var first *api.ApprovalRequired
+		require.ErrorAs(err, &first, "expected the rejection to be held for approval")
+
+		_, err = client.Reject(ctx, transactionID, rejection)
+		var second *api.ApprovalRequired
+		require.ErrorAs(err, &second, "expected the original response to be replayed")
+		require.Equal(first.Approval.ID, second.Approval.ID, "expected the same approval to be returned")
+
+		w.store.AssertCalls(w.T(), "CreateApproval", 1)
+		w.store.AssertCalls(w.T(), "CreateIdempotencyKey", 1)
+	})
+
+	w.Run("Conflict", func() {
+		//setup
+		require := w.Require()
+		setup()
+		ctx := api.ContextWithIdempotencyKey(context.Background(), uuid.NewString())
+		client := w.ClientWithPermissions([]string{"travelrule:manage"})
+
+		//test
+		_, err := client.Reject(ctx, transactionID, rejection)
+		var held *api.ApprovalRequired
+		require.ErrorAs(err, &held, "expected the rejection to be held for approval")
+
+		_, err = client.Reject(ctx, transactionID, &api.Rejection{Code: "COMPLIANCE_CHECK_FAIL", Message: "a different reason"})
+		require.ErrorContains(err, "the idempotency key has already been used with a different request")
+		w.store.AssertCalls(w.T(), "CreateApproval", 1)
+	})
+
+	w.Run("NewKey", func() {
+		//setup
+		require := w.Require()
+		setup()
+		client := w.ClientWithPermissions([]string{"travelrule:manage"})
+
+		//test
+		// The client generates a new key for every request so both are handled
+		_, err := client.Reject(context.Background(), transactionID, rejection)
+		var held *api.ApprovalRequired
+		require.ErrorAs(err, &held, "expected the rejection to be held for approval")
+
+		_, err = client.Reject(context.Background(), transactionID, rejection)
+		require.ErrorAs(err, &held, "expected the rejection to be held for approval")
+		w.store.AssertCalls(w.T(), "CreateApproval", 2)
+	})
+
+	w.Run("KeyTooLong", func() {
+		//setup
+		require := w.Require()
+		key := make([]byte, 256)
+		for i := range key {
+			key[i] = 'a'
+		}
+		ctx := api.ContextWithIdempotencyKey(context.Background(), string(key))
+
+		//test
+		_, err := w.ClientWithPermissions([]string{"travelrule:manage"}).Reject(ctx, transactionID, rejection)
+		require.ErrorContains(err, "idempotency key must be at most 255 characters")
+		w.store.AssertCalls(w.T(), "RetrieveIdempotencyKey", 0)
+	})
+}
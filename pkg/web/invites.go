@@ -0,0 +1,405 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.rtnl.ai/ulid"
+	"go.rtnl.ai/x/vero"
+
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/emails"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/logger"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+)
+
+func (s *Server) ListInvites(c *gin.Context) {
+	var (
+		err     error
+		invites []*models.UserInvite
+		out     *api.UserInviteList
+	)
+
+	if invites, err = s.store.ListUserInvites(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process invite list request"))
+		return
+	}
+
+	if out, err = api.NewUserInviteList(invites); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process invite list request"))
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/users/invites.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+func (s *Server) CreateInvite(c *gin.Context) {
+	var (
+		err    error
+		in     *api.UserInvite
+		invite *models.UserInvite
+		role   *models.Role
+		out    *api.UserInvite
+	)
+
+	// Invited users set a password to login, which is not possible with SSO only
+	if s.conf.Web.Auth.DisablePasswordLogin {
+		c.JSON(http.StatusForbidden, api.Error(ErrPasswordLogin))
+		return
+	}
+
+	in = &api.UserInvite{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse invite data"))
+		return
+	}
+
+	if !in.ID.IsZero() {
+		c.JSON(http.StatusBadRequest, api.Error("cannot specify an id when creating an invite"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	// Validate the role in the database
+	if role, err = s.store.LookupRole(c.Request.Context(), in.Role); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusBadRequest, api.Error(api.ValidationError(nil, api.IncorrectField("role", "unknown role - specify the title of an existing role"))))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create invite request"))
+		return
+	}
+
+	if invite, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	invite.SetRole(role)
+	invite.Expiration = time.Now().Add(s.inviteTTL())
+
+	if err = s.store.CreateUserInvite(c.Request.Context(), invite, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateInvite()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrAlreadyExists) {
+			c.JSON(http.StatusConflict, api.Error("a user with this email address already exists or has already been invited"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create invite request"))
+		return
+	}
+
+	// If the email cannot be sent, the invite is still created so that it can be resent
+	// by an administrator; the invite will be listed without a sent on timestamp.
+	if err = s.sendUserInvite(c, invite); err != nil {
+		c.Error(err)
+	}
+
+	if out, err = api.NewUserInvite(invite); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create invite request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusCreated, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.InvitesUpdated)
+	}
+}
+
+func (s *Server) ResendInvite(c *gin.Context) {
+	var (
+		err      error
+		inviteID ulid.ULID
+		invite   *models.UserInvite
+		out      *api.UserInvite
+	)
+
+	if s.conf.Web.Auth.DisablePasswordLogin {
+		c.JSON(http.StatusForbidden, api.Error(ErrPasswordLogin))
+		return
+	}
+
+	if inviteID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("invite not found"))
+		return
+	}
+
+	if invite, err = s.store.RetrieveUserInvite(c.Request.Context(), inviteID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("invite not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not resend invite"))
+		return
+	}
+
+	// Resending the invite extends the expiration and invalidates the previous link
+	invite.Expiration = time.Now().Add(s.inviteTTL())
+	if err = s.sendUserInvite(c, invite); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not send invite email"))
+		return
+	}
+
+	if out, err = api.NewUserInvite(invite); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not resend invite"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.InvitesUpdated)
+	}
+}
+
+func (s *Server) RevokeInvite(c *gin.Context) {
+	var (
+		err      error
+		inviteID ulid.ULID
+	)
+
+	if inviteID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("invite not found"))
+		return
+	}
+
+	if err = s.store.DeleteUserInvite(c.Request.Context(), inviteID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.RevokeInvite()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("invite not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not revoke invite"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, api.Reply{Success: true})
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.InvitesUpdated)
+	}
+}
+
+// Verifies the invite link token submitted by the invitee and creates their user
+// account with the password they have chosen and the role assigned by the invite.
+func (s *Server) AcceptInvite(c *gin.Context) {
+	var (
+		err        error
+		in         *api.AcceptInviteRequest
+		invite     *models.UserInvite
+		user       *models.User
+		derivedKey string
+	)
+
+	// We do not allow JSON API requests to this endpoint. Returning a 406 error
+	// here is for the legitimate API users who need to not use this endpoint.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if s.conf.Web.Auth.DisablePasswordLogin {
+		c.JSON(http.StatusForbidden, api.Error(ErrPasswordLogin))
+		return
+	}
+
+	in = &api.AcceptInviteRequest{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse accept invite request"))
+		return
+	}
+
+	// Get the verification token from the cookie
+	if in.Token, err = c.Cookie(InviteTokenCookie); err != nil {
+		// If no cookie is submitted, then slow down the request and send back a 403.
+		SlowDown()
+		c.JSON(http.StatusForbidden, api.Error("unable to process accept invite request"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if invite, err = s.verifyUserInviteToken(c.Request.Context(), &in.URLVerification); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound), errors.Is(err, ErrExpiredToken):
+			c.JSON(http.StatusBadRequest, api.Error("your invite link is invalid or expired, please ask your administrator to resend the invite"))
+			return
+		case errors.Is(err, ErrNotAllowed):
+			// The slow down prevents brute force attacks on the accept invite endpoint.
+			SlowDown()
+			c.JSON(http.StatusForbidden, api.Error("unable to process accept invite request"))
+			return
+		default:
+			s.Error(c, err)
+			return
+		}
+	}
+
+	if derivedKey, err = passwords.CreateDerivedKey(in.Password); err != nil {
+		s.Error(c, err)
+		return
+	}
+
+	user = &models.User{
+		Name:     sql.NullString{Valid: in.Name != "", String: in.Name},
+		Password: derivedKey,
+	}
+
+	// The invitee is not authenticated so the user is created by the system
+	ctx := audit.WithActor(c.Request.Context(), []byte("Server.AcceptInvite()"), enum.ActorSystem)
+	if err = s.store.AcceptUserInvite(ctx, invite.ID, user, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.AcceptInvite()"},
+	}); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound):
+			c.JSON(http.StatusBadRequest, api.Error("your invite link is invalid or expired, please ask your administrator to resend the invite"))
+		case errors.Is(err, dberr.ErrAlreadyExists):
+			c.JSON(http.StatusConflict, api.Error("a user with this email address already exists"))
+		default:
+			s.Error(c, err)
+		}
+		return
+	}
+
+	// Make sure any other user is logged out and the invite token cannot be reused
+	auth.ClearAuthCookies(c, s.conf.Web.Auth.CookieDomain)
+	s.ClearInviteTokenCookie(c)
+
+	c.HTML(http.StatusOK, "auth/invite/success.html", scene.New(c))
+}
+
+//////////////////////////////////////////////////////////////////////////////
+// User Invite Workflow internal functions
+//////////////////////////////////////////////////////////////////////////////
+
+// The default amount of time that a UserInvite will expire after
+const defaultInviteTTL = 72 * time.Hour
+
+func (s *Server) inviteTTL() time.Duration {
+	if s.conf.Web.Auth.InviteTTL > 0 {
+		return s.conf.Web.Auth.InviteTTL
+	}
+	return defaultInviteTTL
+}
+
+// Signs a new verification token for the invite (invalidating any previously sent
+// links) and sends the invite email to the invitee. The expiration of the invite must
+// be set by the caller.
+func (s *Server) sendUserInvite(c *gin.Context, invite *models.UserInvite) (err error) {
+	ctx := c.Request.Context()
+
+	emailData := emails.UserInviteEmailData{
+		ContactName:    invite.Name.String,
+		ComplianceName: s.GetComplianceName(),
+		Expiration:     invite.Expiration,
+		BaseURL:        s.conf.Web.InviteURL(),
+		SupportEmail:   s.conf.Email.SupportEmail,
+	}
+
+	if role, err := invite.Role(); err == nil {
+		emailData.Role = role.Title
+	}
+
+	// Create the HMAC verification token for the UserInvite
+	var verification *vero.Token
+	if verification, err = vero.New(invite.ID[:], invite.Expiration); err != nil {
+		return err
+	}
+
+	if emailData.Token, invite.Signature, err = verification.Sign(); err != nil {
+		return err
+	}
+
+	// Store the signature before sending so the link can be verified when accepted
+	if err = s.store.UpdateUserInvite(ctx, invite); err != nil {
+		return err
+	}
+
+	var email *emails.Email
+	if email, err = emails.NewUserInviteEmail(invite.Email, emailData); err != nil {
+		return err
+	}
+
+	if err = email.Send(); err != nil {
+		return err
+	}
+
+	invite.SentOn = sql.NullTime{Valid: true, Time: time.Now()}
+	if err = s.store.UpdateUserInvite(ctx, invite); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Verifies a UserInvite token and returns the UserInvite object.
+func (s *Server) verifyUserInviteToken(ctx context.Context, token *api.URLVerification) (invite *models.UserInvite, err error) {
+	log := logger.Tracing(ctx)
+
+	if invite, err = s.store.RetrieveUserInvite(ctx, token.RecordULID()); err != nil {
+		log.Debug().Err(err).Str("invite_id", token.RecordULID().String()).Msg("could not retrieve user invite record")
+		return nil, err
+	}
+
+	// An invite that has never been signed cannot be verified
+	if invite.Signature == nil {
+		return nil, ErrNotAllowed
+	}
+
+	if secure, err := invite.Signature.Verify(token.VerificationToken()); err != nil || !secure {
+		log.Warn().Err(err).Str("invite_id", invite.ID.String()).Bool("secure", secure).Msg("a user invite hmac verification failed")
+		return nil, ErrNotAllowed
+	}
+
+	if invite.Signature.Token.IsExpired() || invite.IsExpired() {
+		log.Debug().Str("invite_id", invite.ID.String()).Msg("received a request with an expired invite token")
+		return nil, ErrExpiredToken
+	}
+
+	return invite, nil
+}
@@ -0,0 +1,228 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerListInvites() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListUserInvites = func(ctx context.Context) ([]*models.UserInvite, error) {
+			sent := &models.UserInvite{
+				Model:      models.Model{ID: ulid.MakeSecure()},
+				Email:      "sent@example.com",
+				Expiration: time.Now().Add(time.Hour),
+				SentOn:     sql.NullTime{Valid: true, Time: time.Now()},
+			}
+			sent.SetRole(&models.Role{ID: 2, Title: "Compliance"})
+
+			expired := &models.UserInvite{
+				Model:      models.Model{ID: ulid.MakeSecure()},
+				Email:      "expired@example.com",
+				Expiration: time.Now().Add(-1 * time.Hour),
+			}
+			return []*models.UserInvite{sent, expired}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).ListInvites(ctx)
+		require.NoError(err, "unexpected client request error")
+		require.Len(out.Invites, 2)
+		require.Equal("Compliance", out.Invites[0].Role)
+		require.NotNil(out.Invites[0].SentOn)
+		require.False(out.Invites[0].Expired)
+		require.Nil(out.Invites[1].SentOn)
+		require.True(out.Invites[1].Expired)
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListInvites(ctx)
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerCreateInvite() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, title string) (*models.Role, error) {
+			return &models.Role{ID: 3, Title: "Observer"}, nil
+		}
+		w.store.OnCreateUserInvite = func(ctx context.Context, invite *models.UserInvite, auditLog *models.ComplianceAuditLog) error {
+			require.Equal("invitee@example.com", invite.Email)
+			require.Equal(int64(3), invite.RoleID)
+			require.True(invite.Expiration.After(time.Now()), "expected the expiration to be set")
+			invite.ID = ulid.MakeSecure()
+			return nil
+		}
+		w.store.OnUpdateUserInvite = func(ctx context.Context, invite *models.UserInvite) error {
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).CreateInvite(ctx, &api.UserInvite{
+			Email: "invitee@example.com",
+			Name:  "Invited User",
+			Role:  "Observer",
+		})
+		require.NoError(err, "unexpected client request error")
+		require.False(out.ID.IsZero())
+		require.Equal("Observer", out.Role)
+		require.NotNil(out.Expires)
+
+		// Emails are not configured in tests so the invite is created but not sent
+		require.Nil(out.SentOn)
+		w.store.AssertCalls(w.T(), "CreateUserInvite", 1)
+		w.store.AssertCalls(w.T(), "UpdateUserInvite", 1)
+	})
+
+	w.Run("AlreadyExists", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, title string) (*models.Role, error) {
+			return &models.Role{ID: 3, Title: "Observer"}, nil
+		}
+		w.store.OnCreateUserInvite = func(ctx context.Context, invite *models.UserInvite, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrAlreadyExists
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).CreateInvite(ctx, &api.UserInvite{
+			Email: "observer@example.com",
+			Role:  "Observer",
+		})
+		require.ErrorContains(err, "already exists or has already been invited")
+		require.Nil(out)
+	})
+
+	w.Run("UnknownRole", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, title string) (*models.Role, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).CreateInvite(ctx, &api.UserInvite{
+			Email: "invitee@example.com",
+			Role:  "Unknown",
+		})
+		require.ErrorContains(err, "unknown role")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "CreateUserInvite", 0)
+	})
+
+	w.Run("InvalidInvite", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).CreateInvite(ctx, &api.UserInvite{})
+		require.ErrorContains(err, "2 validation errors occurred")
+		require.Nil(out)
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).CreateInvite(ctx, &api.UserInvite{
+			Email: "invitee@example.com",
+			Role:  "Observer",
+		})
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerResendInvite() {
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveUserInvite = func(ctx context.Context, inviteID ulid.ULID) (*models.UserInvite, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).ResendInvite(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "invite not found")
+		require.Nil(out)
+	})
+
+	w.Run("ExtendsExpiration", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveUserInvite = func(ctx context.Context, inviteID ulid.ULID) (*models.UserInvite, error) {
+			return &models.UserInvite{
+				Model:      models.Model{ID: inviteID},
+				Email:      "invitee@example.com",
+				Expiration: time.Now().Add(-1 * time.Hour),
+			}, nil
+		}
+		w.store.OnUpdateUserInvite = func(ctx context.Context, invite *models.UserInvite) error {
+			require.True(invite.Expiration.After(time.Now()), "expected the expiration to be extended")
+			return nil
+		}
+
+		//test
+		// Emails are not configured in tests so the invite cannot be sent
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).ResendInvite(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "could not send invite email")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "UpdateUserInvite", 1)
+	})
+}
+
+func (w *webTestSuite) TestServerRevokeInvite() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		inviteID := ulid.MakeSecure()
+		w.store.OnDeleteUserInvite = func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(inviteID, id)
+			return nil
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).RevokeInvite(ctx, inviteID)
+		require.NoError(err, "unexpected client request error")
+		w.store.AssertCalls(w.T(), "DeleteUserInvite", 1)
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnDeleteUserInvite = func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).RevokeInvite(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "invite not found")
+	})
+}
@@ -0,0 +1,69 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const authenticatePath = "/v1/authenticate"
+
+// JWKS publishes the public keys that verify the access and refresh tokens issued by
+// the node so that other services (and other replicas) can verify them.
+func (s *Server) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, s.issuer.JWKS())
+}
+
+// OpenIDConfiguration returns the discovery document that describes the issuer of the
+// access tokens and where to find the keys that verify them.
+func (s *Server) OpenIDConfiguration(c *gin.Context) {
+	out := &api.OpenIDConfiguration{
+		Issuer:                           s.issuer.Issuer(),
+		JWKSURI:                          s.issuer.JWKSURI(),
+		ResponseTypesSupported:           []string{"token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{s.issuer.SigningAlgorithm()},
+		ClaimsSupported: []string{
+			"iss", "sub", "aud", "exp", "nbf", "iat", "jti",
+			"clientID", "name", "email", "gravatar", "org", "role", "permissions", "sso", "sid",
+		},
+	}
+
+	if uri, err := url.Parse(out.Issuer); err == nil && out.Issuer != "" {
+		out.TokenEndpoint = uri.ResolveReference(&url.URL{Path: authenticatePath}).String()
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Synchronizes the signing keys of the claims issuer with the database every
+// KeySyncInterval until the stop channel is closed. Errors are not fatal since the
+// issuer continues to use its current keys until the next interval.
+func (s *Server) syncSigningKeys(stop <-chan struct{}, done chan<- struct{}) {
+	ticker := time.NewTicker(auth.KeySyncInterval)
+	log.Info().Dur("key_rotation", s.conf.Web.Auth.KeyRotation).Msg("signing key rotation running")
+
+	defer func() {
+		ticker.Stop()
+		close(done)
+		log.Debug().Msg("signing key rotation stopped")
+	}()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := s.issuer.Sync(context.Background(), s.store); err != nil {
+				log.Warn().Err(err).Msg("could not sync claims issuer signing keys")
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth/oidc"
+)
+
+func (w *webTestSuite) TestServerJWKS() {
+	//setup
+	require := w.Require()
+
+	//test
+	rep, err := w.tsrv.Client().Get(w.tsrv.URL + "/.well-known/jwks.json")
+	require.NoError(err, "could not make jwks request")
+	defer rep.Body.Close()
+	require.Equal(http.StatusOK, rep.StatusCode)
+
+	jwks := &oidc.JWKS{}
+	require.NoError(json.NewDecoder(rep.Body).Decode(jwks), "could not decode jwks")
+	require.Len(jwks.Keys, 1, "expected the volatile key to be published")
+	require.Equal(w.s.Issuer().CurrentKey().String(), jwks.Keys[0].KeyID)
+	require.Equal("RS256", jwks.Keys[0].Alg)
+
+	keys, err := jwks.PublicKeys()
+	require.NoError(err, "could not parse published keys")
+	require.Equal(w.s.Issuer().Keys()[w.s.Issuer().CurrentKey()], keys[jwks.Keys[0].KeyID])
+}
+
+func (w *webTestSuite) TestServerOpenIDConfiguration() {
+	//setup
+	require := w.Require()
+
+	//test
+	rep, err := w.tsrv.Client().Get(w.tsrv.URL + "/.well-known/openid-configuration")
+	require.NoError(err, "could not make discovery request")
+	defer rep.Body.Close()
+	require.Equal(http.StatusOK, rep.StatusCode)
+
+	out := &api.OpenIDConfiguration{}
+	require.NoError(json.NewDecoder(rep.Body).Decode(out), "could not decode discovery document")
+	require.Equal("http://localhost:4000", out.Issuer)
+	require.Equal("http://localhost:4000/.well-known/jwks.json", out.JWKSURI)
+	require.Equal("http://localhost:4000/v1/authenticate", out.TokenEndpoint)
+	require.Equal([]string{"RS256"}, out.IDTokenSigningAlgValuesSupported)
+}
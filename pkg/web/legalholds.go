@@ -0,0 +1,311 @@
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+	"go.rtnl.ai/ulid"
+)
+
+func (s *Server) ListLegalHolds(c *gin.Context) {
+	var (
+		err  error
+		in   *api.LegalHoldQuery
+		page *models.LegalHoldPage
+		out  *api.LegalHoldList
+	)
+
+	// Parse the URL parameters from the input request
+	in = &api.LegalHoldQuery{}
+	if err = c.BindQuery(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse legal hold query request"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	// Fetch the list of legal holds from the database
+	if page, err = s.store.ListLegalHolds(c.Request.Context(), in.Query()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process legal holds list request"))
+		return
+	}
+
+	if out, err = api.NewLegalHoldList(page); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process legal holds list request"))
+		return
+	}
+
+	// Return the cursor of the requested page so that clients can return to the newest holds
+	out.Page.PageToken = in.NextPageToken
+
+	// Content negotiation
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/legalholds/list.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+func (s *Server) CreateLegalHold(c *gin.Context) {
+	var (
+		err  error
+		in   *api.LegalHold
+		hold *models.LegalHold
+		out  *api.LegalHold
+	)
+
+	// Parse the model from the POST request
+	in = &api.LegalHold{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse legal hold data"))
+		return
+	}
+
+	if err = in.Validate(true); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	// Convert the API serializer into a database model
+	if hold, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	// Record the name of the user or api key that applied the hold
+	hold.AppliedBy = actorName(c)
+
+	if err = s.store.CreateLegalHold(c.Request.Context(), hold, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateLegalHold()"},
+	}); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound):
+			c.JSON(http.StatusNotFound, api.Error("the resource to place under legal hold was not found"))
+		case errors.Is(err, dberr.ErrInvalidHoldResource):
+			c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not process create legal hold request"))
+		}
+		return
+	}
+
+	// If this is an HTMX request, trigger the legal holds updated event
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.LegalHoldsUpdated)
+		return
+	}
+
+	// Convert the model back to an API response
+	if out, err = api.NewLegalHold(hold); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create legal hold request"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+func (s *Server) LegalHoldDetail(c *gin.Context) {
+	var (
+		err    error
+		holdID ulid.ULID
+		hold   *models.LegalHold
+		out    *api.LegalHold
+	)
+
+	// Parse the holdID from the URL
+	if holdID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("legal hold not found"))
+		return
+	}
+
+	// Fetch the model from the database
+	if hold, err = s.store.RetrieveLegalHold(c.Request.Context(), holdID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("legal hold not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("unable to process legal hold detail request"))
+		return
+	}
+
+	if out, err = api.NewLegalHold(hold); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("unable to process legal hold detail request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) UpdateLegalHold(c *gin.Context) {
+	var (
+		err    error
+		holdID ulid.ULID
+		hold   *models.LegalHold
+		in     *api.LegalHold
+		out    *api.LegalHold
+	)
+
+	// Parse the holdID from the URL
+	if holdID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("legal hold not found"))
+		return
+	}
+
+	// Parse the legal hold data for the update request
+	in = &api.LegalHold{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse legal hold data"))
+		return
+	}
+
+	// Sanity check
+	if err = CheckIDMatch(in.ID, holdID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	// Validation in update mode (e.g. create=false)
+	if err = in.Validate(false); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if hold, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	if err = s.store.UpdateLegalHold(c.Request.Context(), hold, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.UpdateLegalHold()"},
+	}); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound):
+			c.JSON(http.StatusNotFound, api.Error("legal hold not found"))
+		case errors.Is(err, dberr.ErrHoldReleased):
+			c.JSON(http.StatusConflict, api.Error("released legal holds cannot be updated"))
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not process legal hold update request"))
+		}
+		return
+	}
+
+	// Convert model back to an API response
+	if out, err = api.NewLegalHold(hold); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process legal hold update request"))
+		return
+	}
+
+	// Return successful JSON response or 204 with htmx trigger depending on the content negotiation
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.LegalHoldsUpdated)
+	}
+}
+
+func (s *Server) ReleaseLegalHold(c *gin.Context) {
+	var (
+		err    error
+		holdID ulid.ULID
+		hold   *models.LegalHold
+		out    *api.LegalHold
+	)
+
+	// Parse the holdID from the URL
+	if holdID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("legal hold not found"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err = s.store.ReleaseLegalHold(ctx, holdID, actorName(c), &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.ReleaseLegalHold()"},
+	}); err != nil {
+		switch {
+		case errors.Is(err, dberr.ErrNotFound):
+			c.JSON(http.StatusNotFound, api.Error("legal hold not found"))
+		case errors.Is(err, dberr.ErrHoldReleased):
+			c.JSON(http.StatusConflict, api.Error("legal hold has already been released"))
+		default:
+			c.Error(err)
+			c.JSON(http.StatusInternalServerError, api.Error("could not process legal hold release request"))
+		}
+		return
+	}
+
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.LegalHoldsUpdated)
+		return
+	}
+
+	// Return the released legal hold to the API client
+	if hold, err = s.store.RetrieveLegalHold(ctx, holdID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process legal hold release request"))
+		return
+	}
+
+	if out, err = api.NewLegalHold(hold); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process legal hold release request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Returns a human readable name for the user or api key making the request so that it
+// can be recorded on legal holds; the claims name is preferred, then the email address
+// of the user, and finally the client ID of an api key.
+func actorName(c *gin.Context) string {
+	claims, err := auth.GetClaims(c)
+	if err != nil {
+		return ""
+	}
+	return claimsActorName(claims)
+}
+
+// Returns the name of the actor identified by the claims.
+func claimsActorName(claims *auth.Claims) string {
+	if claims == nil {
+		return ""
+	}
+
+	switch {
+	case claims.Name != "":
+		return claims.Name
+	case claims.Email != "":
+		return claims.Email
+	default:
+		return claims.ClientID
+	}
+}
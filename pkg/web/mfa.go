@@ -0,0 +1,542 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/skip2/go-qrcode"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/mfa"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"go.rtnl.ai/ulid"
+)
+
+const (
+	mfaTemplate   = "partials/profile/mfa.html"
+	defaultIssuer = "TRISA Envoy"
+)
+
+//===========================================================================
+// Profile MFA Management
+//===========================================================================
+
+func (s *Server) ProfileMFA(c *gin.Context) {
+	var (
+		err  error
+		user *models.User
+	)
+
+	// Profile requests are only available for logged in users and therefore are UI
+	// only requests (Accept: text/html). JSON requests return a 406 error.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if user, err = s.retrieveProfile(c); err != nil {
+		s.profileMFAError(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, mfaTemplate, mfaStatus(user))
+}
+
+func (s *Server) EnrollProfileMFA(c *gin.Context) {
+	var (
+		err    error
+		user   *models.User
+		secret string
+		out    gin.H
+	)
+
+	// Profile requests are only available for logged in users and therefore are UI
+	// only requests (Accept: text/html). JSON requests return a 406 error.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if user, err = s.retrieveProfile(c); err != nil {
+		s.profileMFAError(c, err)
+		return
+	}
+
+	if user.MFAEnabled() {
+		c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "Error", "multi-factor authentication is already enabled"))
+		return
+	}
+
+	// Generate a new secret; enrollment is not complete until the user verifies a code
+	if secret, err = mfa.GenerateSecret(); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not start multi-factor authentication enrollment"))
+		return
+	}
+
+	if err = s.store.SetUserMFASecret(c.Request.Context(), user.ID, secret); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "Error", "multi-factor authentication is already enabled"))
+			return
+		}
+
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not start multi-factor authentication enrollment"))
+		return
+	}
+
+	if out, err = mfaEnrollment(user, secret); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not generate QR code"))
+		return
+	}
+
+	c.HTML(http.StatusOK, mfaTemplate, out)
+}
+
+func (s *Server) VerifyProfileMFA(c *gin.Context) {
+	var (
+		err    error
+		in     *api.ProfileMFACode
+		user   *models.User
+		claims *auth.Claims
+		codes  []string
+		out    gin.H
+	)
+
+	// Profile requests are only available for logged in users and therefore are UI
+	// only requests (Accept: text/html). JSON requests return a 406 error.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if user, err = s.retrieveProfile(c); err != nil {
+		s.profileMFAError(c, err)
+		return
+	}
+
+	if user.MFAEnabled() || !user.MFASecret.Valid {
+		c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "Error", "multi-factor authentication enrollment has not been started"))
+		return
+	}
+
+	// Any errors in the code are displayed on the enrollment form with the QR code
+	if out, err = mfaEnrollment(user, user.MFASecret.String); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not generate QR code"))
+		return
+	}
+
+	in = &api.ProfileMFACode{}
+	if err = c.BindJSON(in); err != nil {
+		out["Error"] = "could not parse verification code"
+		c.HTML(http.StatusBadRequest, mfaTemplate, out)
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		out["FieldErrors"] = map[string]string{"code": "a verification code is required"}
+		c.HTML(http.StatusBadRequest, mfaTemplate, out)
+		return
+	}
+
+	// Only TOTP codes can be used to complete enrollment
+	if verified, err := s.verifyTOTPCode(c.Request.Context(), user, in.Code); err != nil || !verified {
+		out["FieldErrors"] = map[string]string{"code": mfaCodeError(c, err, "invalid verification code")}
+		c.HTML(http.StatusBadRequest, mfaTemplate, out)
+		return
+	}
+
+	if codes, err = mfa.GenerateRecoveryCodes(mfa.NumRecoveryCodes); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not complete multi-factor authentication enrollment"))
+		return
+	}
+
+	if err = s.store.EnableUserMFA(c.Request.Context(), user.ID, hashRecoveryCodes(codes), &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.VerifyProfileMFA()"},
+	}); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not complete multi-factor authentication enrollment"))
+		return
+	}
+	user.MFAEnrolled = sql.NullTime{Valid: true, Time: time.Now()}
+
+	// If the user's role requires MFA, the user's current tokens do not have any
+	// permissions, so new tokens are issued now that the user has enrolled.
+	if claims, err = auth.GetClaims(c); err == nil && claims.MFAEnroll {
+		if err = s.refreshProfileTokens(c, user); err != nil {
+			c.Error(err)
+		}
+	}
+
+	c.HTML(http.StatusOK, mfaTemplate, mfaStatus(user, "RecoveryCodes", codes))
+}
+
+func (s *Server) RegenerateProfileRecoveryCodes(c *gin.Context) {
+	var (
+		err   error
+		user  *models.User
+		codes []string
+	)
+
+	// Profile requests are only available for logged in users and therefore are UI
+	// only requests (Accept: text/html). JSON requests return a 406 error.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if user, err = s.verifyProfileMFA(c); err != nil {
+		// Error logging and response is handled in method
+		return
+	}
+
+	if codes, err = mfa.GenerateRecoveryCodes(mfa.NumRecoveryCodes); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not generate recovery codes"))
+		return
+	}
+
+	if err = s.store.SetUserRecoveryCodes(c.Request.Context(), user.ID, hashRecoveryCodes(codes), &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.RegenerateProfileRecoveryCodes()"},
+	}); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not generate recovery codes"))
+		return
+	}
+
+	c.HTML(http.StatusOK, mfaTemplate, mfaStatus(user, "RecoveryCodes", codes))
+}
+
+func (s *Server) DisableProfileMFA(c *gin.Context) {
+	var (
+		err  error
+		user *models.User
+	)
+
+	// Profile requests are only available for logged in users and therefore are UI
+	// only requests (Accept: text/html). JSON requests return a 406 error.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if user, err = s.verifyProfileMFA(c); err != nil {
+		// Error logging and response is handled in method
+		return
+	}
+
+	// Users cannot disable MFA if it is required by their role
+	if role, err := user.Role(); err == nil && role.RequireMFA {
+		c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "Error", "multi-factor authentication is required for your role"))
+		return
+	}
+
+	if err = s.store.ResetUserMFA(c.Request.Context(), user.ID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.DisableProfileMFA()"},
+	}); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, mfaStatus(user, "Error", "could not disable multi-factor authentication"))
+		return
+	}
+
+	user.MFASecret = sql.NullString{}
+	user.MFAEnrolled = sql.NullTime{}
+	c.HTML(http.StatusOK, mfaTemplate, mfaStatus(user))
+}
+
+// Parses the MFA code from the request and verifies it against the profile of the
+// logged in user, who must have MFA enabled. Error responses are handled by this method.
+func (s *Server) verifyProfileMFA(c *gin.Context) (user *models.User, err error) {
+	if user, err = s.retrieveProfile(c); err != nil {
+		s.profileMFAError(c, err)
+		return nil, err
+	}
+
+	if !user.MFAEnabled() {
+		c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "Error", "multi-factor authentication is not enabled"))
+		return nil, ErrNotFound
+	}
+
+	in := &api.ProfileMFACode{}
+	if err = c.BindJSON(in); err != nil {
+		c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "Error", "could not parse authentication code"))
+		return nil, err
+	}
+
+	if err = in.Validate(); err != nil {
+		c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "FieldErrors", map[string]string{"code": "an authentication code is required"}))
+		return nil, err
+	}
+
+	var verified bool
+	if verified, err = s.verifyMFACode(c.Request.Context(), user, in.Code); err != nil || !verified {
+		c.HTML(http.StatusBadRequest, mfaTemplate, mfaStatus(user, "FieldErrors", map[string]string{"code": mfaCodeError(c, err, "invalid authentication code")}))
+		return nil, mfa.ErrBadCode
+	}
+
+	return user, nil
+}
+
+// Verifies a TOTP code or consumes a single-use recovery code for a user that has
+// enrolled in MFA. Returns false if the code is not valid for the user. Accepted TOTP
+// codes cannot be replayed and the user is locked out of MFA verification after too
+// many consecutive invalid codes, in which case ErrLocked is returned.
+func (s *Server) verifyMFACode(ctx context.Context, user *models.User, code string) (_ bool, err error) {
+	if !mfa.IsTOTPCode(code) {
+		if user.MFALockedOut(time.Now()) {
+			return false, mfa.ErrLocked
+		}
+
+		if err = s.store.UseUserRecoveryCode(ctx, user.ID, mfa.HashRecoveryCode(code)); err != nil {
+			if errors.Is(err, dberr.ErrNotFound) {
+				return false, s.store.RecordUserMFAFailure(ctx, user.ID, mfa.MaxFailures, mfa.LockoutPeriod)
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	return s.verifyTOTPCode(ctx, user, code)
+}
+
+// Verifies a TOTP code against the user's secret and records the time step of the code
+// so that it cannot be used again; failed codes count towards the user's lockout.
+func (s *Server) verifyTOTPCode(ctx context.Context, user *models.User, code string) (verified bool, err error) {
+	now := time.Now()
+	if user.MFALockedOut(now) {
+		return false, mfa.ErrLocked
+	}
+
+	var step int64
+	if step, verified, err = mfa.Verify(user.MFASecret.String, code, now, user.MFALastStep.Int64); err != nil || !verified {
+		if ferr := s.store.RecordUserMFAFailure(ctx, user.ID, mfa.MaxFailures, mfa.LockoutPeriod); ferr != nil {
+			return false, ferr
+		}
+		return false, err
+	}
+
+	// The step is only recorded if it is after the last accepted step, otherwise the
+	// code was accepted by a concurrent request and is a replay.
+	if err = s.store.SetUserMFAStep(ctx, user.ID, step); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			return false, mfa.ErrCodeReused
+		}
+		return false, err
+	}
+
+	user.MFALastStep = sql.NullInt64{Valid: true, Int64: step}
+	return true, nil
+}
+
+// Issues new access and refresh tokens for the logged in user so that changes to the
+// user's claims take effect without requiring the user to log in again.
+func (s *Server) refreshProfileTokens(c *gin.Context, user *models.User) (err error) {
+	var (
+		claims       *auth.Claims
+		current      *auth.Claims
+		accessToken  string
+		refreshToken string
+	)
+
+	if current, err = auth.GetClaims(c); err != nil {
+		return err
+	}
+
+	if claims, err = auth.NewClaims(c.Request.Context(), user); err != nil {
+		return err
+	}
+
+	// The new tokens belong to the session of the current tokens
+
+	if err = s.refreshSession(c.Request.Context(), user.ID, current, claims); err != nil {
+		return err
+	}
+
+	if accessToken, refreshToken, err = s.issuer.CreateTokens(claims); err != nil {
+		return err
+	}
+
+	return auth.SetAuthCookies(c, accessToken, refreshToken, s.conf.Web.Auth.CookieDomain)
+}
+
+// Returns the message displayed to the user when an MFA code could not be verified,
+// logging any unexpected errors that occurred during verification.
+func mfaCodeError(c *gin.Context, err error, invalid string) string {
+	switch {
+	case errors.Is(err, mfa.ErrLocked):
+		return "too many invalid codes, please try again later"
+	case err == nil || errors.Is(err, mfa.ErrBadCode) || errors.Is(err, mfa.ErrCodeReused):
+		return invalid
+	default:
+		c.Error(err)
+		return invalid
+	}
+}
+
+func (s *Server) profileMFAError(c *gin.Context, err error) {
+	// By default in the MFA partial we'll return 400 to display the error alert.
+	// Only if something is really bad we will redirect to error page.
+	switch {
+	case errors.Is(err, auth.ErrNotAuthorized) || errors.Is(err, dberr.ErrNotFound):
+		c.HTML(http.StatusBadRequest, mfaTemplate, gin.H{"Error": "could not retrieve multi-factor authentication settings"})
+	default:
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, mfaTemplate, gin.H{"Error": "could not retrieve multi-factor authentication settings"})
+	}
+}
+
+// Returns the template data to render the MFA status of the user along with any
+// additional key/value pairs specified.
+func mfaStatus(user *models.User, kvs ...any) gin.H {
+	out := gin.H{"Enabled": user.MFAEnabled()}
+	if role, err := user.Role(); err == nil {
+		out["Required"] = role.RequireMFA
+	}
+
+	for i := 0; i+1 < len(kvs); i += 2 {
+		out[kvs[i].(string)] = kvs[i+1]
+	}
+	return out
+}
+
+// Returns the template data to render the enrollment form with the QR code that is
+// scanned by the user's authenticator app.
+func mfaEnrollment(user *models.User, secret string) (_ gin.H, err error) {
+	issuer := auth.GetOrganization()
+	if issuer == "" {
+		issuer = defaultIssuer
+	}
+
+	uri := mfa.URI(issuer, user.Email, secret)
+
+	var qrc *qrcode.QRCode
+	if qrc, err = qrcode.New(uri, qrcode.Medium); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	if err = qrc.Write(256, buf); err != nil {
+		return nil, err
+	}
+
+	out := mfaStatus(user)
+	out["Enroll"] = true
+	out["Secret"] = secret
+	out["QRCode"] = template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes()))
+	return out, nil
+}
+
+func hashRecoveryCodes(codes []string) []string {
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, mfa.HashRecoveryCode(code))
+	}
+	return hashes
+}
+
+//===========================================================================
+// MFA Administration
+//===========================================================================
+
+func (s *Server) ResetUserMFA(c *gin.Context) {
+	var (
+		err    error
+		userID ulid.ULID
+	)
+
+	// Parse the userID from the URL
+	if userID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("user not found"))
+		return
+	}
+
+	// Remove the user's MFA secret and recovery codes so that they can re-enroll
+	if err = s.store.ResetUserMFA(c.Request.Context(), userID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.ResetUserMFA()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("user not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not reset user multi-factor authentication"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, api.Reply{Success: true})
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.UsersUpdated)
+	}
+}
+
+func (s *Server) SetRoleMFAPolicy(c *gin.Context) {
+	var (
+		err  error
+		in   *api.RoleMFAPolicy
+		role *models.Role
+		out  *api.Role
+	)
+
+	in = &api.RoleMFAPolicy{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse role mfa policy"))
+		return
+	}
+
+	// Lookup the role by its title
+	ctx := c.Request.Context()
+	if role, err = s.store.LookupRole(ctx, c.Param("role")); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("role not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not update role mfa policy"))
+		return
+	}
+
+	if err = s.store.SetRoleMFARequired(ctx, role.ID, in.Required, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.SetRoleMFAPolicy()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("role not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not update role mfa policy"))
+		return
+	}
+
+	role.RequireMFA = in.Required
+	if out, err = api.NewRole(role); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not update role mfa policy"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.RolesUpdated)
+	}
+}
@@ -0,0 +1,237 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth/mfa"
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerResetUserMFA() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		userID := ulid.MakeSecure()
+
+		var calledWith ulid.ULID
+		w.store.OnResetUserMFA = func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			calledWith = id
+			return nil
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).ResetUserMFA(ctx, userID)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(userID, calledWith, "expected the user's mfa to be reset")
+		w.store.AssertCalls(w.T(), "ResetUserMFA", 1)
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnResetUserMFA = func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).ResetUserMFA(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "user not found")
+	})
+
+	w.Run("FailureNoPermissions", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:view"}).ResetUserMFA(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "user does not have permission to perform this operation", "the user should not be authorized")
+	})
+
+	w.Run("FailureNoAuth", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		err := w.ClientNoAuth().ResetUserMFA(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "this endpoint requires authentication", "the user should not be authenticated")
+	})
+}
+
+func (w *webTestSuite) TestServerSetRoleMFAPolicy() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return &models.Role{ID: 2, Title: "Compliance"}, nil
+		}
+
+		var required bool
+		w.store.OnSetRoleMFARequired = func(ctx context.Context, roleID int64, req bool, auditLog *models.ComplianceAuditLog) error {
+			required = req
+			return nil
+		}
+
+		//test
+		role, err := w.ClientWithPermissions([]string{"users:manage"}).SetRoleMFAPolicy(ctx, "compliance", &api.RoleMFAPolicy{Required: true})
+		require.NoError(err, "unexpected client request error")
+		require.True(required, "expected the role mfa policy to be set")
+		require.Equal(int64(2), role.ID)
+		require.True(role.RequireMFA, "expected the returned role to require mfa")
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		role, err := w.ClientWithPermissions([]string{"users:manage"}).SetRoleMFAPolicy(ctx, "foo", &api.RoleMFAPolicy{Required: true})
+		require.ErrorContains(err, "role not found")
+		require.Nil(role)
+	})
+
+	w.Run("FailureNoPermissions", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		role, err := w.ClientWithPermissions([]string{"users:view"}).SetRoleMFAPolicy(ctx, "compliance", &api.RoleMFAPolicy{Required: true})
+		require.ErrorContains(err, "user does not have permission to perform this operation", "the user should not be authorized")
+		require.Nil(role)
+	})
+}
+
+func (w *webTestSuite) TestServerLoginMFA() {
+	password := "supersecretsquirrel"
+	secret, err := mfa.GenerateSecret()
+	w.Require().NoError(err, "could not generate totp secret")
+
+	newUser := func() *models.User {
+		dk, err := passwords.CreateDerivedKey(password)
+		w.Require().NoError(err, "could not create derived key")
+
+		user := &models.User{
+			Model:       models.Model{ID: ulid.MakeSecure()},
+			Email:       "mfa@example.com",
+			Password:    dk,
+			MFASecret:   sql.NullString{Valid: true, String: secret},
+			MFAEnrolled: sql.NullTime{Valid: true, Time: time.Now()},
+		}
+		user.SetRole(&models.Role{ID: 2, Title: "Compliance"})
+		return user
+	}
+
+	w.Run("ReplayedCode", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		now := time.Now()
+
+		code, err := mfa.Code(secret, now)
+		require.NoError(err, "could not generate totp code")
+
+		user := newUser()
+		user.MFALastStep = sql.NullInt64{Valid: true, Int64: now.Unix() / int64(mfa.Period.Seconds())}
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+		w.store.OnRecordUserMFAFailure = func(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout time.Duration) error {
+			return nil
+		}
+
+		//test
+		out, err := w.ClientNoAuth().Login(ctx, &api.LoginRequest{Email: user.Email, Password: password, Code: code})
+		require.ErrorContains(err, "invalid login credentials", "expected a replayed code to be rejected")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "RecordUserMFAFailure", 1)
+		w.store.AssertCalls(w.T(), "SetUserMFAStep", 0)
+	})
+
+	w.Run("ConcurrentReplay", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		code, err := mfa.Code(secret, time.Now())
+		require.NoError(err, "could not generate totp code")
+
+		user := newUser()
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+
+		// The step has already been recorded by another request with the same code
+		w.store.OnSetUserMFAStep = func(ctx context.Context, userID ulid.ULID, step int64) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientNoAuth().Login(ctx, &api.LoginRequest{Email: user.Email, Password: password, Code: code})
+		require.ErrorContains(err, "invalid login credentials", "expected a concurrently replayed code to be rejected")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "SetUserMFAStep", 1)
+	})
+
+	w.Run("InvalidCode", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		user := newUser()
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+
+		var max int64
+		w.store.OnRecordUserMFAFailure = func(ctx context.Context, userID ulid.ULID, maxFailures int64, lockout time.Duration) error {
+			max = maxFailures
+			return nil
+		}
+		w.store.OnUseUserRecoveryCode = func(ctx context.Context, userID ulid.ULID, recoveryCode string) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientNoAuth().Login(ctx, &api.LoginRequest{Email: user.Email, Password: password, Code: "k7qz-rm2h-tawx-9pcd"})
+		require.ErrorContains(err, "invalid login credentials", "expected an invalid recovery code to be rejected")
+		require.Nil(out)
+		require.Equal(int64(mfa.MaxFailures), max, "expected the failure to be recorded")
+		w.store.AssertCalls(w.T(), "RecordUserMFAFailure", 1)
+	})
+
+	w.Run("LockedOut", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		code, err := mfa.Code(secret, time.Now())
+		require.NoError(err, "could not generate totp code")
+
+		user := newUser()
+		user.MFALocked = sql.NullTime{Valid: true, Time: time.Now().Add(mfa.LockoutPeriod)}
+		w.store.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+
+		//test
+		out, err := w.ClientNoAuth().Login(ctx, &api.LoginRequest{Email: user.Email, Password: password, Code: code})
+		require.ErrorContains(err, "too many invalid authentication codes", "expected a valid code to be rejected while locked out")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "SetUserMFAStep", 0)
+	})
+}
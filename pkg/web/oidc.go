@@ -0,0 +1,307 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/audit"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/logger"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/oidc"
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+// Error codes passed to the login page when single sign-on fails; the login page maps
+// the codes to messages so that arbitrary text cannot be injected into the page.
+const (
+	ssoErrorFailed = "sso_failed"
+	ssoErrorDenied = "sso_denied"
+)
+
+// oidcRequest is stored in a short-lived cookie between the redirect to the identity
+// provider and the callback so that the callback can be verified.
+type oidcRequest struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Next     string `json:"next,omitempty"`
+}
+
+// LoginOIDC redirects the user to the identity provider to login with single sign-on.
+func (s *Server) LoginOIDC(c *gin.Context) {
+	var (
+		err     error
+		req     *oidcRequest
+		authURL string
+	)
+
+	if s.oidc == nil {
+		s.NotFound(c)
+		return
+	}
+
+	req = &oidcRequest{
+		Verifier: oauth2.GenerateVerifier(),
+		Next:     safeRedirect(c.Query("next")),
+	}
+
+	if req.State, err = oidc.RandomString(); err != nil {
+		s.Error(c, err)
+		return
+	}
+
+	if req.Nonce, err = oidc.RandomString(); err != nil {
+		s.Error(c, err)
+		return
+	}
+
+	if authURL, err = s.oidc.AuthCodeURL(c.Request.Context(), req.State, req.Nonce, req.Verifier); err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	if err = s.SetOIDCRequestCookie(c, req); err != nil {
+		s.Error(c, err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// LoginOIDCCallback handles the redirect back from the identity provider, exchanging the
+// authorization code for the user's identity and logging the user into Envoy. Users are
+// matched by email and created on their first login if provisioning is enabled; the
+// role of the user is updated from their groups at the identity provider every login.
+// Envoy does not require its own multi-factor authentication code for single sign-on
+// since multi-factor authentication is managed by the identity provider.
+func (s *Server) LoginOIDCCallback(c *gin.Context) {
+	var (
+		err    error
+		req    *oidcRequest
+		claims *oidc.Claims
+		role   *models.Role
+		user   *models.User
+		tokens *auth.Claims
+		ctx    context.Context
+	)
+
+	if s.oidc == nil {
+		s.NotFound(c)
+		return
+	}
+
+	// The request cookie is single use and must match the state in the callback
+	req, err = s.OIDCRequest(c)
+	s.ClearOIDCRequestCookie(c)
+	if err != nil || req.State == "" || req.State != c.Query("state") {
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	// The identity provider may redirect back with an error (e.g. access denied)
+	ctx = c.Request.Context()
+	if idpErr := c.Query("error"); idpErr != "" || c.Query("code") == "" {
+		log := logger.Tracing(ctx)
+		log.Debug().Str("error", idpErr).Str("description", c.Query("error_description")).Msg("single sign-on was not completed")
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorDenied))
+		return
+	}
+
+	if claims, err = s.oidc.Exchange(ctx, c.Query("code"), req.Nonce, req.Verifier); err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	if role, err = s.oidcRole(ctx, claims); err != nil {
+		if !errors.Is(err, oidc.ErrNoRole) {
+			c.Error(err)
+		}
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorDenied))
+		return
+	}
+
+	if user, err = s.oidcUser(ctx, claims, role); err != nil {
+		if !errors.Is(err, dberr.ErrNotFound) {
+			c.Error(err)
+		}
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorDenied))
+		return
+	}
+
+	// Update user last login timestamp
+	user.LastLogin = sql.NullTime{Valid: true, Time: time.Now()}
+	if err = s.store.SetUserLastLogin(ctx, user.ID, user.LastLogin.Time); err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	// Create access and refresh tokens for authentication
+	if tokens, err = auth.NewClaims(ctx, user); err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	tokens.SetSingleSignOn(user)
+
+	// Create a session for the tokens so that the user can revoke it
+	if err = s.createSession(c, user, tokens); err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	var accessToken, refreshToken string
+	if accessToken, refreshToken, err = s.issuer.CreateTokens(tokens); err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	if err = auth.SetAuthCookies(c, accessToken, refreshToken, s.conf.Web.Auth.CookieDomain); err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusFound, loginErrorURL(ssoErrorFailed))
+		return
+	}
+
+	if req.Next != "" {
+		c.Redirect(http.StatusFound, req.Next)
+		return
+	}
+	c.Redirect(http.StatusFound, "/")
+}
+
+// Returns the envoy role mapped from the user's groups at the identity provider.
+func (s *Server) oidcRole(ctx context.Context, claims *oidc.Claims) (_ *models.Role, err error) {
+	var title string
+	if title, err = s.oidc.Role(claims); err != nil {
+		return nil, err
+	}
+
+	var role *models.Role
+	if role, err = s.store.LookupRole(ctx, title); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			log := logger.Tracing(ctx)
+			log.Warn().Str("role", title).Msg("single sign-on role mapping refers to an unknown role")
+			return nil, oidc.ErrNoRole
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// Returns the user matching the email of the claims, creating the user if they do not
+// exist and provisioning is enabled, and updating their role if it has changed.
+func (s *Server) oidcUser(ctx context.Context, claims *oidc.Claims, role *models.Role) (user *models.User, err error) {
+	// Changes made during single sign-on are recorded as system actions
+	ctx = audit.WithActor(ctx, []byte("Server.LoginOIDCCallback()"), enum.ActorSystem)
+
+	if user, err = s.store.RetrieveUser(ctx, claims.Email); err != nil {
+		if !errors.Is(err, dberr.ErrNotFound) || !s.oidc.Provision() {
+			return nil, err
+		}
+
+		user = &models.User{
+			Name:  sql.NullString{Valid: true, String: claims.DisplayName()},
+			Email: claims.Email,
+		}
+		user.SetRole(role)
+
+		// Provisioned users authenticate with single sign-on so they are given a random
+		// password that is never revealed; an admin can reset it if required.
+		if user.Password, err = passwords.CreateDerivedKey(passwords.AlphaNumeric(32)); err != nil {
+			return nil, err
+		}
+
+		if err = s.store.CreateUser(ctx, user, &models.ComplianceAuditLog{
+			ChangeNotes: sql.NullString{Valid: true, String: "provisioned by single sign-on"},
+		}); err != nil {
+			return nil, err
+		}
+
+		// Retrieve the user again to load the permissions of the role
+		return s.store.RetrieveUser(ctx, user.ID)
+	}
+
+	if user.RoleID != role.ID {
+		user.SetRole(role)
+		if err = s.store.UpdateUser(ctx, user, &models.ComplianceAuditLog{
+			ChangeNotes: sql.NullString{Valid: true, String: "role updated by single sign-on"},
+		}); err != nil {
+			return nil, err
+		}
+		return s.store.RetrieveUser(ctx, user.ID)
+	}
+
+	return user, nil
+}
+
+//===========================================================================
+// OIDC Request Cookie
+//===========================================================================
+
+func (s *Server) SetOIDCRequestCookie(c *gin.Context, req *oidcRequest) (err error) {
+	var data []byte
+	if data, err = json.Marshal(req); err != nil {
+		return err
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(data)
+	s.SetCookie(c, OIDCRequestCookie, value, OIDCRequestPath, int(OIDCRequestTTL.Seconds()), true)
+	return nil
+}
+
+func (s *Server) OIDCRequest(c *gin.Context) (req *oidcRequest, err error) {
+	var cookie string
+	if cookie, err = c.Cookie(OIDCRequestCookie); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if data, err = base64.RawURLEncoding.DecodeString(cookie); err != nil {
+		return nil, err
+	}
+
+	req = &oidcRequest{}
+	if err = json.Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *Server) ClearOIDCRequestCookie(c *gin.Context) {
+	s.ClearCookie(c, OIDCRequestCookie, OIDCRequestPath, true)
+}
+
+//===========================================================================
+// Helpers
+//===========================================================================
+
+func loginErrorURL(code string) string {
+	return "/login?" + url.Values{"error": []string{code}}.Encode()
+}
+
+// Only relative redirects on this site are allowed after login to prevent the next
+// parameter from being used to redirect users to another site.
+func safeRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return ""
+	}
+	return next
+}
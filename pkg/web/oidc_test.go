@@ -0,0 +1,231 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/config"
+	"github.com/trisacrypto/envoy/pkg/store"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+	oidcmock "github.com/trisacrypto/envoy/pkg/web/auth/oidc/mock"
+	"go.rtnl.ai/ulid"
+)
+
+func TestLoginOIDC(t *testing.T) {
+	idp, err := oidcmock.New(jwt.MapClaims{
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane Doe",
+		"groups":         []string{"envoy-admins"},
+	})
+	require.NoError(t, err, "could not start stub identity provider")
+	defer idp.Close()
+
+	tsrv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tsrv.Close()
+
+	sto, err := store.Open("mock:///")
+	require.NoError(t, err, "could not open mock store")
+	db := sto.(*mock.Store)
+
+	conf := config.Config{
+		Organization: "Envoy Testing",
+		Mode:         "testing",
+		Web: config.WebConfig{
+			Enabled:    true,
+			APIEnabled: true,
+			UIEnabled:  true,
+			BindAddr:   ":4000",
+			Origin:     "http://localhost:4000",
+			Auth: config.AuthConfig{
+				AccessTokenTTL:       1 * time.Hour,
+				Audience:             "http://localhost:4000",
+				Issuer:               "http://localhost:4000",
+				DisablePasswordLogin: true,
+				OIDC: config.OIDCConfig{
+					Enabled:      true,
+					ProviderName: "Stub",
+					IssuerURL:    idp.Issuer(),
+					ClientID:     oidcmock.ClientID,
+					ClientSecret: oidcmock.ClientSecret,
+					RedirectURL:  tsrv.URL + "/login/oidc/callback",
+					Scopes:       []string{"openid", "email", "profile"},
+					GroupsClaim:  "groups",
+					RoleMapping:  map[string]string{"envoy-admins": "admin"},
+					Provision:    true,
+				},
+			},
+		},
+	}
+
+	srv, err := web.Debug(conf, db, nil, tsrv.Config)
+	require.NoError(t, err, "could not create web server")
+
+	roles := map[string]*models.Role{
+		"admin":    {ID: 1, Title: "Admin", RequireMFA: true},
+		"observer": {ID: 3, Title: "Observer"},
+	}
+
+	// Logs in with the stub identity provider and returns the final redirect from
+	// envoy and the access token cookie if one was set.
+	login := func(t *testing.T, next string) (string, *auth.Claims) {
+		jar, err := cookiejar.New(nil)
+		require.NoError(t, err, "could not create cookie jar")
+
+		client := tsrv.Client()
+		client.Jar = jar
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+
+		location := tsrv.URL + "/login/oidc?" + url.Values{"next": []string{next}}.Encode()
+		for range 3 {
+			rep, err := client.Get(location)
+			require.NoError(t, err, "could not make request")
+			rep.Body.Close()
+			require.Equal(t, http.StatusFound, rep.StatusCode, "expected redirect from %s", location)
+			location = rep.Header.Get("Location")
+		}
+
+		envoy, _ := url.Parse(tsrv.URL)
+		for _, cookie := range jar.Cookies(envoy) {
+			if cookie.Name == auth.AccessTokenCookie {
+				claims, err := srv.Issuer().Verify(cookie.Value)
+				require.NoError(t, err, "could not verify access token")
+				return location, claims
+			}
+		}
+		return location, nil
+	}
+
+	t.Run("Provision", func(t *testing.T) {
+		db.Reset()
+		defer db.Reset()
+
+		var created *models.User
+		db.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return roles[role], nil
+		}
+		db.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			if created != nil {
+				require.Equal(t, created.ID, emailOrUserID)
+				created.SetPermissions([]string{"users:manage"})
+				return created, nil
+			}
+			require.Equal(t, "jane@example.com", emailOrUserID)
+			return nil, dberr.ErrNotFound
+		}
+		db.OnCreateUser = func(ctx context.Context, in *models.User, log *models.ComplianceAuditLog) error {
+			require.Equal(t, "Jane Doe", in.Name.String)
+			require.Equal(t, int64(1), in.RoleID)
+			require.NotEmpty(t, in.Password, "expected an unusable password to be set")
+			in.ID = ulid.MakeSecure()
+			created = in
+			return nil
+		}
+		db.OnSetUserLastLogin = func(ctx context.Context, userID ulid.ULID, lastLogin time.Time) error {
+			return nil
+		}
+		db.OnCreateUserSession = func(ctx context.Context, session *models.UserSession) error {
+			require.True(t, session.SSO, "expected a single sign-on session")
+			session.ID = ulid.MakeSecure()
+			return nil
+		}
+
+		location, claims := login(t, "/transactions")
+		require.Equal(t, "/transactions", location)
+		require.NotNil(t, claims, "expected access token to be set")
+		require.Equal(t, "jane@example.com", claims.Email)
+		require.Equal(t, "Admin", claims.Role)
+		require.True(t, claims.SSO)
+		require.False(t, claims.MFAEnroll, "single sign-on users should not be required to enroll in envoy mfa")
+		require.Equal(t, []string{"users:manage"}, claims.Permissions)
+		require.NotEmpty(t, claims.Session, "expected a session to be created")
+		db.AssertCalls(t, "CreateUser", 1)
+	})
+
+	t.Run("UpdateRole", func(t *testing.T) {
+		db.Reset()
+		defer db.Reset()
+
+		user := &models.User{Model: models.Model{ID: ulid.MakeSecure()}, Email: "jane@example.com"}
+		user.SetRole(roles["observer"])
+
+		db.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return roles[role], nil
+		}
+		db.OnRetrieveUser = func(ctx context.Context, emailOrUserID any) (*models.User, error) {
+			return user, nil
+		}
+		db.OnUpdateUser = func(ctx context.Context, in *models.User, log *models.ComplianceAuditLog) error {
+			require.Equal(t, int64(1), in.RoleID)
+			return nil
+		}
+		db.OnSetUserLastLogin = func(ctx context.Context, userID ulid.ULID, lastLogin time.Time) error {
+			return nil
+		}
+		db.OnCreateUserSession = func(ctx context.Context, session *models.UserSession) error {
+			require.True(t, session.SSO, "expected a single sign-on session")
+			session.ID = ulid.MakeSecure()
+			return nil
+		}
+
+		location, claims := login(t, "https://evil.example.com")
+		require.Equal(t, "/", location, "expected external redirects to be ignored")
+		require.NotNil(t, claims, "expected access token to be set")
+		require.Equal(t, "Admin", claims.Role)
+		db.AssertCalls(t, "UpdateUser", 1)
+		db.AssertCalls(t, "CreateUser", 0)
+	})
+
+	t.Run("NoRole", func(t *testing.T) {
+		db.Reset()
+		defer db.Reset()
+
+		idp.SetClaims(jwt.MapClaims{"email": "jane@example.com", "groups": []string{"everyone"}})
+		defer idp.SetClaims(jwt.MapClaims{"email": "jane@example.com", "name": "Jane Doe", "groups": []string{"envoy-admins"}})
+
+		location, claims := login(t, "")
+		require.Equal(t, "/login?error=sso_denied", location)
+		require.Nil(t, claims, "expected no access token to be set")
+		db.AssertCalls(t, "RetrieveUser", 0)
+	})
+
+	t.Run("BadState", func(t *testing.T) {
+		client := tsrv.Client()
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+
+		rep, err := client.Get(tsrv.URL + "/login/oidc/callback?state=foo&code=bar")
+		require.NoError(t, err, "could not make request")
+		rep.Body.Close()
+		require.Equal(t, http.StatusFound, rep.StatusCode)
+		require.Equal(t, "/login?error=sso_failed", rep.Header.Get("Location"))
+	})
+
+	t.Run("PasswordLoginDisabled", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"email": "jane@example.com", "password": "supersecretsquirrel"}`)
+		req, err := http.NewRequest(http.MethodPost, tsrv.URL+"/v1/login", body)
+		require.NoError(t, err, "could not create request")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		rep, err := tsrv.Client().Do(req)
+		require.NoError(t, err, "could not make request")
+		rep.Body.Close()
+		require.Equal(t, http.StatusForbidden, rep.StatusCode)
+	})
+}
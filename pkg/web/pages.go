@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"github.com/trisacrypto/envoy/pkg"
 	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/reports"
 	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
@@ -44,6 +46,10 @@ func (s *Server) LoginPage(c *gin.Context) {
 // ForgotPasswordPage displays the reset password form for the UI so that the user can
 // enter their email address and receive a password reset link.
 func (s *Server) ForgotPasswordPage(c *gin.Context) {
+	if s.conf.Web.Auth.DisablePasswordLogin {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
 	c.HTML(http.StatusOK, "auth/reset/forgot.html", scene.New(c))
 }
 
@@ -76,6 +82,20 @@ func (s *Server) ResetPasswordPage(c *gin.Context) {
 	c.HTML(http.StatusOK, "auth/reset/password.html", scene.New(c))
 }
 
+// AcceptInvitePage allows an invited user to choose their name and password to create
+// their account; the invite link is only verified when the form is submitted.
+func (s *Server) AcceptInvitePage(c *gin.Context) {
+	in := &api.URLVerification{}
+	if err := c.BindQuery(in); err != nil {
+		log.Debug().Err(err).Msg("could not parse query string")
+	}
+
+	// Set the token into a cookie so that it can be parsed when the form is submitted.
+	s.SetInviteTokenCookie(c, in.Token)
+
+	c.HTML(http.StatusOK, "auth/invite/accept.html", scene.New(c))
+}
+
 //===========================================================================
 // Transactions Pages
 //===========================================================================
@@ -86,6 +106,8 @@ func (s *Server) TransactionsListPage(c *gin.Context) {
 
 	ctx := scene.New(c).WithAPIData(counts)
 	ctx["Archives"] = strings.ToLower(c.Query("archives"))
+	ctx["Tag"] = models.NormalizeTag(c.Query("tags"))
+	ctx["Overdue"] = strings.ToLower(c.Query("overdue")) == "true"
 
 	c.HTML(http.StatusOK, "dashboard/transactions/list.html", ctx)
 }
@@ -191,6 +213,15 @@ func (s *Server) TransactionDetailPage(c *gin.Context) {
 	ctx := scene.New(c).WithToastMessages(c)
 	ctx["ID"] = txID
 
+	// Users that the transaction can be assigned to for review.
+	if !ctx.IsViewOnly() {
+		if users, err := s.store.ListUsers(c.Request.Context(), &models.UserPageInfo{}); err != nil {
+			log.Warn().Err(err).Msg("could not list users to assign the transaction to")
+		} else {
+			ctx["Assignees"] = users.Users
+		}
+	}
+
 	s.ClearToastMessages(c)
 	c.HTML(http.StatusOK, "pages/transactions/detail.html", ctx)
 }
@@ -278,8 +309,27 @@ func (s *Server) TravelAddressUtility(c *gin.Context) {
 //===========================================================================
 
 func (s *Server) UsersListPage(c *gin.Context) {
+	var (
+		err   error
+		roles []*models.Role
+		out   *api.RoleList
+	)
+
+	// Roles are required to populate the role tabs and the create user form
+	if roles, err = s.store.ListRoles(c.Request.Context()); err != nil {
+		s.Error(c, err)
+		return
+	}
+
+	if out, err = api.NewRoleList(roles); err != nil {
+		s.Error(c, err)
+		return
+	}
+
 	ctx := scene.New(c)
 	ctx["Role"] = strings.ToLower(c.Query("role"))
+	ctx["Roles"] = out.Roles
+	ctx["DisablePasswordLogin"] = s.conf.Web.Auth.DisablePasswordLogin
 	c.HTML(http.StatusOK, "dashboard/users/list.html", ctx)
 }
 
@@ -291,12 +341,79 @@ func (s *Server) APIKeysListPage(c *gin.Context) {
 	c.HTML(http.StatusOK, "dashboard/apikeys/list.html", scene.New(c))
 }
 
+func (s *Server) ApprovalsListPage(c *gin.Context) {
+	ctx := scene.New(c)
+	ctx["Tab"] = strings.ToLower(c.Query("tab"))
+	c.HTML(http.StatusOK, "dashboard/approvals/list.html", ctx)
+}
+
+func (s *Server) QueuedTransfersPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "dashboard/queue/list.html", scene.New(c))
+}
+
+func (s *Server) BatchesListPage(c *gin.Context) {
+	ctx := scene.New(c)
+	ctx["BatchEnabled"] = s.conf.Batch.Enabled
+	ctx["MaxRows"] = s.conf.Batch.MaxRows
+	c.HTML(http.StatusOK, "dashboard/batches/list.html", ctx)
+}
+
+func (s *Server) BatchDetailPage(c *gin.Context) {
+	// Get the batch ID from the URL path and make available to the template.
+	// The batch detail is loaded using htmx.
+	batchID := c.Param("id")
+
+	// Validate that the batch ID is a valid ULID.
+	if _, err := ulid.Parse(batchID); err != nil {
+		htmx.Redirect(c, http.StatusTemporaryRedirect, "/not-found")
+		return
+	}
+
+	ctx := scene.New(c)
+	ctx["ID"] = batchID
+
+	c.HTML(http.StatusOK, "pages/batches/detail.html", ctx)
+}
+
+func (s *Server) ReportsListPage(c *gin.Context) {
+	// The sections that can be selected when generating a report on demand
+	sections := make([]struct{ Value, Title string }, 0, len(reports.Sections))
+	for _, section := range reports.Sections {
+		sections = append(sections, struct{ Value, Title string }{section, reports.SectionTitle(section)})
+	}
+
+	ctx := scene.New(c)
+	ctx["Sections"] = sections
+	ctx["ScheduleEnabled"] = s.conf.Reports.Enabled
+	ctx["Frequency"] = s.conf.Reports.Frequency
+	c.HTML(http.StatusOK, "dashboard/reports/list.html", ctx)
+}
+
 //===========================================================================
 // Audit Log Management Pages
 //===========================================================================
 
+// Audit log filters that can be passed to the list page in the URL, e.g. to link to
+// the history of a specific resource from its detail page.
+var auditLogFilters = []string{"resource_types", "resource_id", "actor_types", "actor_id", "actions", "after", "before"}
+
 func (s *Server) ComplianceAuditLogListPage(c *gin.Context) {
-	c.HTML(http.StatusOK, "dashboard/auditlogs/list.html", scene.New(c))
+	// Forward any filters in the URL to the htmx list request; the UI paginates the
+	// logs returned by the server so fetch larger pages than the API default.
+	query := url.Values{}
+	query.Set("page_size", "100")
+	for _, key := range auditLogFilters {
+		if values, ok := c.GetQueryArray(key); ok {
+			query[key] = values
+		}
+	}
+
+	ctx := scene.New(c)
+	ctx["Query"] = query.Encode()
+	ctx["ResourceID"] = c.Query("resource_id")
+	ctx["ActorID"] = c.Query("actor_id")
+
+	c.HTML(http.StatusOK, "dashboard/auditlogs/list.html", ctx)
 }
 
 func (s *Server) ComplianceAuditLogDetailPage(c *gin.Context) {
@@ -1,28 +1,30 @@
 package web
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
+	"github.com/trisacrypto/envoy/pkg/enum"
 	"github.com/trisacrypto/envoy/pkg/postman"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
 	"github.com/trisacrypto/envoy/pkg/web/htmx"
 	"github.com/trisacrypto/envoy/pkg/web/scene"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
 	"github.com/trisacrypto/trisa/pkg/ivms101"
 	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
 )
 
 func (s *Server) PrepareTransaction(c *gin.Context) {
 	var (
-		err             error
-		in              *api.Prepare
-		out             *api.Prepared
-		beneficiaryVASP *models.Counterparty
-		originatorVASP  *models.Counterparty
+		err error
+		in  *api.Prepare
+		out *api.Prepared
 	)
 
 	in = &api.Prepare{}
@@ -38,24 +40,41 @@ func (s *Server) PrepareTransaction(c *gin.Context) {
 		return
 	}
 
+	if out, err = s.prepare(c.Request.Context(), in); err != nil {
+		s.ResponseError(c, err)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLData: scene.New(c).WithAPIData(out),
+		HTMLName: "partials/send/preview.html",
+	})
+}
+
+// Converts a validated prepare request into the TRISA data structures of a prepared
+// transaction by looking up the originator and beneficiary VASPs. If an error is
+// returned it is a *ResponseError that describes the response to send to the user.
+func (s *Server) prepare(ctx context.Context, in *api.Prepare) (out *api.Prepared, err error) {
+	var (
+		beneficiaryVASP *models.Counterparty
+		originatorVASP  *models.Counterparty
+	)
+
 	// Get originator VASP information from database
-	if originatorVASP, err = s.Localparty(c.Request.Context()); err != nil {
-		c.Error(err)
+	if originatorVASP, err = s.Localparty(ctx); err != nil {
 		if errors.Is(err, ErrNoLocalparty) {
-			c.JSON(http.StatusPreconditionFailed, api.Error("no IVMS101 information found for local party; node is incorrectly configured or directory sync has failed"))
-			return
+			return nil, NewResponseError(http.StatusPreconditionFailed, "no IVMS101 information found for local party; node is incorrectly configured or directory sync has failed", err)
 		}
-
-		c.JSON(http.StatusInternalServerError, api.Error("could not complete prepare request"))
-		return
+		return nil, NewResponseError(http.StatusInternalServerError, "could not complete prepare request", err)
 	}
 
 	// Parse the routing object to identify the beneficiary VASP and lookup the
 	// counterparty in the local database for IVMS101 information if any.
 	// If this is a sunrise message, the counterparty is created if necessary.
-	if beneficiaryVASP, err = s.ResolveCounterparty(c, in.Routing); err != nil {
-		// NOTE: CounterpartyFromTravelAddress handles API response back to user.
-		return
+	if beneficiaryVASP, err = s.ResolveCounterparty(ctx, in.Routing); err != nil {
+		return nil, err
 	}
 
 	// Convert the incoming data into the appropriate TRISA data structures
@@ -98,12 +117,41 @@ func (s *Server) PrepareTransaction(c *gin.Context) {
 		Transaction: in.Transaction(),
 	}
 
-	c.Negotiate(http.StatusOK, gin.Negotiate{
-		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
-		Data:     out,
-		HTMLData: scene.New(c).WithAPIData(out),
-		HTMLName: "partials/send/preview.html",
-	})
+	// Evaluate the transfer against the threshold rules to determine if travel rule
+	// data is required and if so validate that the mandatory IVMS101 fields are set.
+	if s.thresholds.Enabled() {
+		if out.TravelRule, err = s.travelRule(ctx, in, beneficiaryVASP); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
+
+// Evaluates the prepare request against the threshold rules, returning an error if
+// the travel rule data required by the threshold rules is missing from the request.
+// If an error is returned it is a *ResponseError.
+func (s *Server) travelRule(ctx context.Context, in *api.Prepare, beneficiaryVASP *models.Counterparty) (_ *api.TravelRule, err error) {
+	transfer := &thresholds.Transfer{
+		Source:              enum.SourceLocal,
+		OriginatorCountry:   thresholds.Country(in.Originator.NaturalPerson()),
+		BeneficiaryCountry:  thresholds.Country(in.Beneficiary.NaturalPerson()),
+		CounterpartyCountry: beneficiaryVASP.Country.String,
+		VirtualAsset:        postman.VirtualAsset(in.Transfer.Network, in.Transfer.AssetType),
+		Amount:              in.Transfer.Amount,
+	}
+
+	var decision *thresholds.Decision
+	if decision, err = s.thresholds.Evaluate(ctx, transfer); err != nil {
+		return nil, NewResponseError(http.StatusInternalServerError, "could not complete prepare request", err)
+	}
+
+	in.TravelRule = decision.TravelRule()
+	if err = in.Validate(); err != nil {
+		return nil, NewResponseError(http.StatusUnprocessableEntity, "", err)
+	}
+
+	return in.TravelRule, nil
 }
 
 func (s *Server) SendPreparedTransaction(c *gin.Context) {
@@ -112,6 +160,7 @@ func (s *Server) SendPreparedTransaction(c *gin.Context) {
 		in      *api.Prepared
 		payload *trisa.Payload
 		packet  *postman.Packet
+		queued  bool
 		out     *api.Transaction
 	)
 
@@ -135,12 +184,20 @@ func (s *Server) SendPreparedTransaction(c *gin.Context) {
 		return
 	}
 
+	// If sending the transfer requires four-eyes approval, hold it for a reviewer.
+	// NOTE: holdForApproval handles the response to the user if the request is held.
+	if s.holdForApproval(c, enum.ApprovalActionSend, uuid.Nil, in) {
+		return
+	}
+
 	// Send the transfer to the counterparty and get the secure envelope response
 	// NOTE: Send handles any error response that needs to be sent to the user.
 	// WARNING: Send commits/rollsback the database transaction from the packet.
-	if packet, err = s.Send(c, in.Routing, payload); err != nil {
+	// NOTE: if the transfer is queued the transaction remains pending until it is sent.
+	if packet, queued, err = s.Send(c, in.Routing, payload, in.SendAt); err != nil {
 		return
 	}
+	setIdempotentTransaction(c, packet.Transaction.ID)
 
 	// Create the API response to send back to the user
 	if out, err = api.NewTransaction(packet.Transaction); err != nil {
@@ -149,8 +206,13 @@ func (s *Server) SendPreparedTransaction(c *gin.Context) {
 		return
 	}
 
-	// If this is a UI request, then redirect the user to the transaction detail page
+	// If this is a UI request, then redirect the user to the transaction detail page or
+	// to the queued transfers page if the transfer is waiting in the outbound queue.
 	if htmx.IsHTMXRequest(c) {
+		if queued {
+			htmx.Redirect(c, http.StatusSeeOther, "/queue")
+			return
+		}
 		htmx.Redirect(c, http.StatusSeeOther, "/transactions/"+packet.Transaction.ID.String())
 		return
 	}
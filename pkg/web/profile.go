@@ -144,8 +144,8 @@ func (s *Server) DeleteProfile(c *gin.Context) {
 		return
 	}
 
-	// Delete the user from the database
-	// TODO: for audit purposes we may simply want to move the user to a revoked table.
+	// Delete the user from the database; the identity of the user is retained without
+	// credentials as a revoked user so that audit log actors can still be resolved.
 	if err = s.store.DeleteUser(c.Request.Context(), userID, &models.ComplianceAuditLog{
 		ChangeNotes: sql.NullString{Valid: true, String: "Server.DeleteProfile()"},
 	}); err != nil {
@@ -235,7 +235,8 @@ func (s *Server) ChangeProfilePassword(c *gin.Context) {
 		return
 	}
 
-	// Success! Log the user out and redirect to the login page.
+	// Success! Revoke the user's sessions, log the user out and redirect to the login page.
+	s.revokeSessions(c.Request.Context(), user.ID, ulid.Null)
 	auth.ClearAuthCookies(c, s.conf.Web.Auth.CookieDomain)
 
 	// Send the user to the login page if this is an HTMX request
@@ -0,0 +1,345 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.rtnl.ai/ulid"
+
+	"github.com/trisacrypto/envoy/pkg/enum"
+	"github.com/trisacrypto/envoy/pkg/logger"
+	"github.com/trisacrypto/envoy/pkg/postman"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+	"github.com/trisacrypto/trisa/pkg/trisa/keys"
+
+	trisa "github.com/trisacrypto/trisa/pkg/trisa/api/v1beta1"
+	"github.com/trisacrypto/trisa/pkg/trisa/envelope"
+)
+
+//===========================================================================
+// Outbound Queue
+//===========================================================================
+
+func (s *Server) ListQueuedTransfers(c *gin.Context) {
+	var (
+		err       error
+		transfers []*models.QueuedTransfer
+		out       *api.QueuedTransferList
+	)
+
+	if transfers, err = s.store.ListQueuedTransfers(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process queued transfers list request"))
+		return
+	}
+
+	if out, err = api.NewQueuedTransferList(transfers); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process queued transfers list request"))
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/queue/list.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+func (s *Server) QueuedTransferDetail(c *gin.Context) {
+	var (
+		err      error
+		transfer *models.QueuedTransfer
+		out      *api.QueuedTransfer
+	)
+
+	if transfer, err = s.retrieveQueuedTransfer(c); err != nil {
+		return
+	}
+
+	if out, err = api.NewQueuedTransfer(transfer); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process queued transfer detail request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Removes the transfer from the outbound queue so that it is not sent; the transaction
+// remains pending and can be archived or sent again by the user.
+func (s *Server) CancelQueuedTransfer(c *gin.Context) {
+	var (
+		err      error
+		transfer *models.QueuedTransfer
+	)
+
+	s.queue.Lock()
+	defer s.queue.Unlock()
+
+	if transfer, err = s.retrieveQueuedTransfer(c); err != nil {
+		return
+	}
+
+	if transfer.Status != enum.QueueQueued && transfer.Status != enum.QueueFailed {
+		c.JSON(http.StatusConflict, api.Error(ErrNotQueued))
+		return
+	}
+
+	transfer.Status = enum.QueueCanceled
+	if err = s.store.UpdateQueuedTransfer(c.Request.Context(), transfer, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CancelQueuedTransfer()"},
+	}); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not cancel queued transfer"))
+		return
+	}
+
+	s.queuedTransferResponse(c, transfer)
+}
+
+// Sends the queued transfer immediately rather than waiting for the next attempt; a
+// failed transfer is requeued so that it is retried by the outbound queue if the
+// counterparty is still unreachable.
+func (s *Server) RetryQueuedTransfer(c *gin.Context) {
+	var (
+		err      error
+		transfer *models.QueuedTransfer
+	)
+
+	if transfer, err = s.retrieveQueuedTransfer(c); err != nil {
+		return
+	}
+
+	if transfer.Status != enum.QueueQueued && transfer.Status != enum.QueueFailed {
+		c.JSON(http.StatusConflict, api.Error(ErrNotQueued))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err = s.SendQueuedTransfer(ctx, transfer.ID); err != nil && !errors.Is(err, ErrUnavailable) {
+		c.Error(err)
+		if errors.Is(err, ErrNotQueued) {
+			c.JSON(http.StatusConflict, api.Error(err))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, api.Error("could not retry queued transfer"))
+		return
+	}
+
+	// Return the transfer with the result of the attempt
+	if transfer, err = s.store.RetrieveQueuedTransfer(ctx, transfer.ID); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not retry queued transfer"))
+		return
+	}
+
+	s.queuedTransferResponse(c, transfer)
+}
+
+func (s *Server) queuedTransferResponse(c *gin.Context, transfer *models.QueuedTransfer) {
+	out, err := api.NewQueuedTransfer(transfer)
+	if err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process queued transfer request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.QueueUpdated)
+	}
+}
+
+// Retrieves the queued transfer identified by the id URL parameter; if an error is
+// returned then the error response has already been sent to the user.
+func (s *Server) retrieveQueuedTransfer(c *gin.Context) (transfer *models.QueuedTransfer, err error) {
+	var transferID ulid.ULID
+	if transferID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("queued transfer not found"))
+		return nil, err
+	}
+
+	if transfer, err = s.store.RetrieveQueuedTransfer(c.Request.Context(), transferID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("queued transfer not found"))
+			return nil, err
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not retrieve queued transfer"))
+		return nil, err
+	}
+	return transfer, nil
+}
+
+//===========================================================================
+// Sending Queued Transfers
+//===========================================================================
+
+// SendQueuedTransfer sends a transfer from the outbound queue to its TRISA
+// counterparty, completing the transaction through the same persistence path as a
+// transfer that is sent directly. If the counterparty cannot be reached the attempt is
+// recorded and the transfer is rescheduled with backoff until the maximum number of
+// attempts is reached, at which point the transfer is marked as failed.
+func (s *Server) SendQueuedTransfer(ctx context.Context, transferID ulid.ULID) (err error) {
+	s.queue.Lock()
+	defer s.queue.Unlock()
+
+	var transfer *models.QueuedTransfer
+	if transfer, err = s.store.RetrieveQueuedTransfer(ctx, transferID); err != nil {
+		return err
+	}
+
+	// A failed transfer is only sent again when it is retried by a user.
+	if transfer.Status != enum.QueueQueued && transfer.Status != enum.QueueFailed {
+		return ErrNotQueued
+	}
+
+	var payload *trisa.Payload
+	if payload, err = s.openQueuedPayload(transfer.Envelope); err != nil {
+		return err
+	}
+
+	var packet *postman.Packet
+	if packet, err = postman.Send(transfer.TransactionID, payload, trisa.TransferStarted); err != nil {
+		return err
+	}
+
+	packet.Log = logger.Tracing(ctx).With().Str("envelope_id", transfer.TransactionID.String()).Logger()
+
+	if packet.Transaction, err = s.store.RetrieveTransaction(ctx, transfer.TransactionID); err != nil {
+		return err
+	}
+
+	if packet.Counterparty, err = s.store.RetrieveCounterparty(ctx, transfer.CounterpartyID); err != nil {
+		return err
+	}
+
+	if packet.DB, err = s.store.PrepareTransaction(ctx, transfer.TransactionID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.SendQueuedTransfer()"},
+	}); err != nil {
+		return err
+	}
+	defer packet.DB.Rollback()
+
+	if err = packet.Out.UpdateTransaction(); err != nil {
+		return err
+	}
+
+	var sent *postman.Packet
+	if sent, err = s.SendPacket(ctx, enum.ProtocolTRISA, packet); err != nil {
+		// Release the database transaction before recording the failed attempt.
+		packet.DB.Rollback()
+		if rerr := s.recordQueuedAttempt(ctx, transfer, err); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+
+	if err = sent.In.UpdateTransaction(); err != nil {
+		return err
+	}
+
+	transfer.Status = enum.QueueSent
+	transfer.Attempts++
+	transfer.LastError = sql.NullString{}
+	transfer.LastAttempt = sql.NullTime{Valid: true, Time: time.Now()}
+
+	if err = sent.DB.UpdateQueuedTransfer(transfer, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.SendQueuedTransfer()"},
+	}); err != nil {
+		return err
+	}
+
+	return sent.DB.Commit()
+}
+
+// Records a failed attempt to send a queued transfer and schedules the next attempt.
+func (s *Server) recordQueuedAttempt(ctx context.Context, transfer *models.QueuedTransfer, cause error) error {
+	now := time.Now()
+	transfer.Attempts++
+	transfer.LastError = sql.NullString{Valid: true, String: cause.Error()}
+	transfer.LastAttempt = sql.NullTime{Valid: true, Time: now}
+	transfer.NextAttempt = now.Add(s.conf.OutboundQueue.Backoff(transfer.Attempts))
+
+	if transfer.Attempts >= s.conf.OutboundQueue.MaxAttempts {
+		transfer.Status = enum.QueueFailed
+	} else {
+		transfer.Status = enum.QueueQueued
+	}
+
+	return s.store.UpdateQueuedTransfer(ctx, transfer, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.SendQueuedTransfer()"},
+	})
+}
+
+// Adds the prepared transfer to the outbound queue, sealing the payload with the
+// storage key of the local node, and commits the pending transaction.
+func (s *Server) enqueue(packet *postman.Packet, payload *trisa.Payload, transfer *models.QueuedTransfer) (err error) {
+	transfer.CounterpartyID = packet.Counterparty.ID
+	if transfer.Envelope, err = s.sealQueuedPayload(packet.EnvelopeID(), payload); err != nil {
+		return err
+	}
+
+	if err = packet.DB.Enqueue(transfer, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.Send()"},
+	}); err != nil {
+		return err
+	}
+
+	if err = packet.RefreshTransaction(); err != nil {
+		return err
+	}
+
+	return packet.DB.Commit()
+}
+
+func (s *Server) sealQueuedPayload(envelopeID string, payload *trisa.Payload) (_ *trisa.SecureEnvelope, err error) {
+	var storageKey keys.PublicKey
+	if storageKey, err = s.trisa.StorageKey("", ""); err != nil {
+		return nil, fmt.Errorf("could not fetch storage key: %w", err)
+	}
+
+	var env *envelope.Envelope
+	if env, err = envelope.New(payload, envelope.WithEnvelopeID(envelopeID)); err != nil {
+		return nil, fmt.Errorf("could not create queued envelope: %w", err)
+	}
+
+	if env, _, err = env.Encrypt(); err != nil {
+		return nil, fmt.Errorf("could not encrypt queued envelope: %w", err)
+	}
+
+	if env, _, err = env.Seal(envelope.WithSealingKey(storageKey)); err != nil {
+		return nil, fmt.Errorf("could not seal queued envelope: %w", err)
+	}
+
+	return env.Proto(), nil
+}
+
+func (s *Server) openQueuedPayload(sealed *trisa.SecureEnvelope) (_ *trisa.Payload, err error) {
+	var unsealingKey keys.PrivateKey
+	if unsealingKey, err = s.trisa.UnsealingKey(sealed.PublicKeySignature, ""); err != nil {
+		return nil, fmt.Errorf("could not lookup unsealing key for queued envelope: %w", err)
+	}
+
+	var env *envelope.Envelope
+	if env, _, err = envelope.Open(sealed, envelope.WithUnsealingKey(unsealingKey)); err != nil {
+		return nil, fmt.Errorf("could not open queued envelope: %w", err)
+	}
+
+	return env.Payload()
+}
@@ -0,0 +1,182 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/trisa/pkg/ivms101"
+	generic "github.com/trisacrypto/trisa/pkg/trisa/data/generic/v1beta1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerListQueuedTransfers() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListQueuedTransfers = func(ctx context.Context) ([]*models.QueuedTransfer, error) {
+			return []*models.QueuedTransfer{
+				{
+					Model:         models.Model{ID: ulid.MakeSecure(), Created: time.Now()},
+					TransactionID: uuid.New(),
+					Status:        enum.QueueQueued,
+					Attempts:      2,
+					LastError:     sql.NullString{Valid: true, String: "could not connect to remote counterparty"},
+					LastAttempt:   sql.NullTime{Valid: true, Time: time.Now()},
+					NextAttempt:   time.Now().Add(time.Minute),
+					Counterparty:  "AliceVASP",
+					VirtualAsset:  "BTC",
+					Amount:        1.5,
+				},
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListQueuedTransfers(ctx)
+		require.NoError(err, "unexpected client request error")
+		require.Len(out.Transfers, 1)
+		require.Equal("queued", out.Transfers[0].Status)
+		require.Equal(int64(2), out.Transfers[0].Attempts)
+		require.NotNil(out.Transfers[0].LastAttempt)
+		require.True(out.Transfers[0].IsPending())
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).ListQueuedTransfers(ctx)
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerCancelQueuedTransfer() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveQueuedTransfer = func(ctx context.Context, transferID ulid.ULID) (*models.QueuedTransfer, error) {
+			return &models.QueuedTransfer{Model: models.Model{ID: transferID}, TransactionID: uuid.New(), Status: enum.QueueFailed}, nil
+		}
+		w.store.OnUpdateQueuedTransfer = func(ctx context.Context, transfer *models.QueuedTransfer, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(enum.QueueCanceled, transfer.Status)
+			require.True(auditLog.ChangeNotes.Valid)
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CancelQueuedTransfer(ctx, ulid.MakeSecure())
+		require.NoError(err, "unexpected client request error")
+		require.Equal("canceled", out.Status)
+		require.False(out.IsPending())
+	})
+
+	w.Run("NotQueued", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveQueuedTransfer = func(ctx context.Context, transferID ulid.ULID) (*models.QueuedTransfer, error) {
+			return &models.QueuedTransfer{Model: models.Model{ID: transferID}, Status: enum.QueueSent}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CancelQueuedTransfer(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "[409]")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "UpdateQueuedTransfer", 0)
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveQueuedTransfer = func(ctx context.Context, transferID ulid.ULID) (*models.QueuedTransfer, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CancelQueuedTransfer(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "queued transfer not found")
+		require.Nil(out)
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).CancelQueuedTransfer(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerRetryQueuedTransfer() {
+	w.Run("NotQueued", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnRetrieveQueuedTransfer = func(ctx context.Context, transferID ulid.ULID) (*models.QueuedTransfer, error) {
+			return &models.QueuedTransfer{Model: models.Model{ID: transferID}, Status: enum.QueueCanceled}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).RetryQueuedTransfer(ctx, ulid.MakeSecure())
+		require.ErrorContains(err, "[409]")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerScheduledSend() {
+	w.Run("QueueDisabled", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListApprovalRules = func(ctx context.Context) ([]*models.ApprovalRule, error) {
+			return nil, nil
+		}
+
+		sendAt := time.Now().Add(time.Hour)
+		in := &api.Prepared{
+			Routing:     &api.Routing{Protocol: "trisa", CounterpartyID: ulid.MakeSecure()},
+			Identity:    &ivms101.IdentityPayload{},
+			Transaction: &generic.Transaction{Amount: 1.5, Network: "BTC"},
+			SendAt:      &sendAt,
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).SendPrepared(ctx, in)
+		require.ErrorContains(err, "the outbound queue is disabled so transfers cannot be scheduled")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "PrepareTransaction", 0)
+	})
+
+	w.Run("InvalidProtocol", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		sendAt := time.Now().Add(time.Hour)
+		in := &api.Prepared{
+			Routing:     &api.Routing{Protocol: "sunrise", EmailAddress: "compliance@example.com", Counterparty: "Example VASP"},
+			Identity:    &ivms101.IdentityPayload{},
+			Transaction: &generic.Transaction{Amount: 1.5, Network: "BTC"},
+			SendAt:      &sendAt,
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).SendPrepared(ctx, in)
+		require.ErrorContains(err, "scheduled sends are only supported for the trisa protocol")
+		require.Nil(out)
+	})
+}
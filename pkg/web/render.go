@@ -8,6 +8,7 @@ import (
 	"html/template"
 	"io/fs"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -182,6 +183,9 @@ func (r *Render) FuncMap() template.FuncMap {
 				}
 				return code
 			},
+			"contains": func(s []string, v string) bool {
+				return slices.Contains(s, v)
+			},
 			"add": func(a, b int) int {
 				return a + b
 			},
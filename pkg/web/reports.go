@@ -0,0 +1,246 @@
+package web
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.rtnl.ai/ulid"
+
+	"github.com/trisacrypto/envoy/pkg/reports"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+)
+
+//===========================================================================
+// Regulatory Reports
+//===========================================================================
+
+func (s *Server) ListReports(c *gin.Context) {
+	var (
+		err     error
+		records []*models.Report
+		out     *api.ReportList
+	)
+
+	if records, err = s.store.ListReports(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process report list request"))
+		return
+	}
+
+	if out, err = api.NewReportList(records); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process report list request"))
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/reports/list.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+// Generates a report of the transfers created during the requested period and stores
+// it so that it can be downloaded later. Reports are generated synchronously since
+// they are computed by streaming the transactions of the period from the database.
+func (s *Server) CreateReport(c *gin.Context) {
+	var (
+		err    error
+		in     *api.Report
+		report *reports.Report
+		record *models.Report
+		out    *api.Report
+	)
+
+	in = &api.Report{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse report data"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if err = reports.ValidateSections(in.Sections); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(api.ValidationError(nil, api.IncorrectField("sections", err.Error()))))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if report, err = reports.Generate(ctx, s.store, reports.Params{
+		Name:        in.Name,
+		PeriodStart: in.PeriodStart,
+		PeriodEnd:   in.PeriodEnd,
+		Sections:    in.Sections,
+	}); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not generate report"))
+		return
+	}
+
+	record = &models.Report{
+		Name:        report.Name,
+		PeriodStart: report.PeriodStart,
+		PeriodEnd:   report.PeriodEnd,
+		CreatedBy:   actorName(c),
+	}
+
+	if record.Data, err = json.Marshal(report); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not generate report"))
+		return
+	}
+
+	if err = s.store.CreateReport(ctx, record, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateReport()"},
+	}); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create report request"))
+		return
+	}
+
+	if out, err = api.NewReport(record, true); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create report request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusCreated, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.ReportsUpdated)
+	}
+}
+
+func (s *Server) ReportDetail(c *gin.Context) {
+	var (
+		err    error
+		record *models.Report
+		out    *api.Report
+	)
+
+	if record, err = s.retrieveReport(c); err != nil {
+		return
+	}
+
+	if out, err = api.NewReport(record, true); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process report detail request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Renders the stored statistics of the report as a CSV, JSON, or HTML file download.
+func (s *Server) DownloadReport(c *gin.Context) {
+	var (
+		err         error
+		in          *api.ReportDownloadQuery
+		record      *models.Report
+		contentType string
+	)
+
+	in = &api.ReportDownloadQuery{}
+	if err = c.BindQuery(in); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error("could not parse report download query"))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if record, err = s.retrieveReport(c); err != nil {
+		return
+	}
+
+	report := &reports.Report{}
+	if err = json.Unmarshal(record.Data, report); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not download report"))
+		return
+	}
+
+	if contentType, err = reports.ContentType(in.Format); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not download report"))
+		return
+	}
+
+	c.Header(ContentDisposition, "attachment; filename="+report.Filename(in.Format))
+	c.Header(ContentType, contentType)
+	c.Writer.WriteHeader(http.StatusOK)
+
+	if err = report.Render(c.Writer, in.Format); err != nil {
+		c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("error rendering report to download stream: %w", err))
+		return
+	}
+}
+
+func (s *Server) DeleteReport(c *gin.Context) {
+	var (
+		err      error
+		reportID ulid.ULID
+	)
+
+	if reportID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("report not found"))
+		return
+	}
+
+	if err = s.store.DeleteReport(c.Request.Context(), reportID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.DeleteReport()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("report not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process delete report request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, api.Reply{Success: true})
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.ReportsUpdated)
+	}
+}
+
+func (s *Server) retrieveReport(c *gin.Context) (report *models.Report, err error) {
+	var reportID ulid.ULID
+	if reportID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("report not found"))
+		return nil, err
+	}
+
+	if report, err = s.store.RetrieveReport(c.Request.Context(), reportID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("report not found"))
+			return nil, err
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not retrieve report"))
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,223 @@
+package web_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/reports"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+var (
+	reportStart = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	reportEnd   = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func (w *webTestSuite) TestServerListReports() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListReports = func(ctx context.Context) ([]*models.Report, error) {
+			return []*models.Report{
+				{Model: models.Model{ID: ulid.MakeSecure()}, Name: "Monthly Travel Rule Report May 2024", Schedule: sql.NullString{Valid: true, String: "monthly"}, PeriodStart: reportStart, PeriodEnd: reportEnd, CreatedBy: "Scheduler"},
+				{Model: models.Model{ID: ulid.MakeSecure()}, Name: "Q2 Review", PeriodStart: reportStart, PeriodEnd: reportEnd, CreatedBy: "Jane Smith"},
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListReports(ctx)
+		require.NoError(err, "unexpected client request error")
+		require.Len(out.Reports, 2)
+		require.Equal("monthly", out.Reports[0].Schedule)
+		require.Equal("", out.Reports[1].Schedule)
+		require.Nil(out.Reports[0].Statistics, "expected no statistics in the list")
+	})
+
+	w.Run("FailureNoPermission", func() {
+		out, err := w.ClientWithPermissions([]string{"users:view"}).ListReports(context.Background())
+		w.Require().ErrorContains(err, "user does not have permission to perform this operation")
+		w.Require().Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerCreateReport() {
+	perms := []string{"travelrule:manage"}
+	transactions := []*models.Transaction{
+		mock.GetSampleTransaction(true, false, false),
+		mock.GetSampleTransaction(false, false, false),
+	}
+
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		w.store.OnIterateTransactions = func(ctx context.Context, filter *models.TransactionFilter) (models.TransactionIterator, error) {
+			require.True(reportStart.Equal(filter.After))
+			require.True(reportEnd.Equal(filter.Before))
+			if filter.Archives {
+				return mock.NewTransactionIterator(), nil
+			}
+			return mock.NewTransactionIterator(transactions...), nil
+		}
+
+		var created *models.Report
+		w.store.OnCreateReport = func(ctx context.Context, report *models.Report, auditLog *models.ComplianceAuditLog) error {
+			require.True(auditLog.ChangeNotes.Valid)
+			require.False(report.Schedule.Valid, "expected on demand reports to have no schedule")
+			report.ID = ulid.MakeSecure()
+			created = report
+			return nil
+		}
+
+		//test
+		in := &api.Report{Name: "May Assets", PeriodStart: reportStart, PeriodEnd: reportEnd, Sections: []string{reports.SectionAsset, reports.SectionOutcome}}
+		out, err := w.ClientWithPermissions(perms).CreateReport(ctx, in)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(created.ID, out.ID)
+		require.Equal("May Assets", out.Name)
+		require.NotEmpty(created.CreatedBy, "expected the report creator to be recorded")
+
+		stats := &reports.Report{}
+		require.NoError(json.Unmarshal(out.Statistics, stats), "expected the statistics to be returned")
+		require.Equal(2, stats.Transfers)
+		require.Equal([]string{reports.SectionAsset, reports.SectionOutcome}, stats.Sections)
+		require.NotEmpty(stats.Assets)
+		require.Empty(stats.Jurisdictions)
+	})
+
+	w.Run("Invalid", func() {
+		tests := []*api.Report{
+			{PeriodStart: reportStart, PeriodEnd: reportEnd},
+			{Name: "foo", PeriodStart: reportStart},
+			{Name: "foo", PeriodStart: reportEnd, PeriodEnd: reportStart},
+			{Name: "foo", PeriodStart: reportStart, PeriodEnd: reportEnd, Schedule: "monthly"},
+			{Name: "foo", PeriodStart: reportStart, PeriodEnd: reportEnd, Sections: []string{"foo"}},
+		}
+
+		for _, in := range tests {
+			_, err := w.ClientWithPermissions(perms).CreateReport(context.Background(), in)
+			w.Require().Error(err)
+
+			serr, ok := err.(*api.StatusError)
+			w.Require().True(ok, "expected a status error")
+			w.Require().Equal(http.StatusUnprocessableEntity, serr.StatusCode)
+		}
+	})
+
+	w.Run("FailureNoPermission", func() {
+		in := &api.Report{Name: "foo", PeriodStart: reportStart, PeriodEnd: reportEnd}
+		_, err := w.ClientWithPermissions([]string{"travelrule:view"}).CreateReport(context.Background(), in)
+		w.Require().ErrorContains(err, "user does not have permission to perform this operation")
+	})
+}
+
+func (w *webTestSuite) TestServerReportDetail() {
+	report := &reports.Report{
+		Name:        "Monthly Travel Rule Report May 2024",
+		PeriodStart: reportStart,
+		PeriodEnd:   reportEnd,
+		Sections:    []string{reports.SectionDirection},
+		Transfers:   3,
+		Directions:  []*reports.Tally{{Key: reports.DirectionOutgoing, VirtualAsset: "BTC", Count: 3, Volume: 1.25}},
+	}
+	data, _ := json.Marshal(report)
+
+	// The store is reset before each subtest so the callback is set by each subtest
+	onRetrieveReport := func(ctx context.Context, id ulid.ULID) (*models.Report, error) {
+		if id.IsZero() {
+			return nil, dberr.ErrNotFound
+		}
+		return &models.Report{Model: models.Model{ID: id}, Name: report.Name, PeriodStart: reportStart, PeriodEnd: reportEnd, Data: data}, nil
+	}
+
+	w.Run("Detail", func() {
+		w.store.OnRetrieveReport = onRetrieveReport
+		require := w.Require()
+		reportID := ulid.MakeSecure()
+
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ReportDetail(context.Background(), reportID)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(reportID, out.ID)
+		require.JSONEq(string(data), string(out.Statistics))
+	})
+
+	w.Run("NotFound", func() {
+		w.store.OnRetrieveReport = onRetrieveReport
+		_, err := w.ClientWithPermissions([]string{"travelrule:view"}).ReportDetail(context.Background(), ulid.ULID{})
+		w.Require().ErrorContains(err, "report not found")
+	})
+
+	w.Run("DownloadCSV", func() {
+		w.store.OnRetrieveReport = onRetrieveReport
+		require := w.Require()
+
+		buf := &bytes.Buffer{}
+		err := w.ClientWithPermissions([]string{"travelrule:view"}).DownloadReport(context.Background(), ulid.MakeSecure(), &api.ReportDownloadQuery{}, buf)
+		require.NoError(err, "unexpected client request error")
+
+		rows, err := csv.NewReader(buf).ReadAll()
+		require.NoError(err, "could not read csv report")
+		require.Equal(reports.CSVHeader, rows[0])
+		require.Contains(rows, []string{"direction", "outgoing", "BTC", "volume", "1.25"})
+	})
+
+	w.Run("DownloadHTML", func() {
+		w.store.OnRetrieveReport = onRetrieveReport
+		buf := &bytes.Buffer{}
+		err := w.ClientWithPermissions([]string{"travelrule:view"}).DownloadReport(context.Background(), ulid.MakeSecure(), &api.ReportDownloadQuery{Format: "html"}, buf)
+		w.Require().NoError(err, "unexpected client request error")
+		w.Require().Contains(buf.String(), "Transfers by Direction")
+	})
+
+	w.Run("DownloadInvalidFormat", func() {
+		w.store.OnRetrieveReport = onRetrieveReport
+		err := w.ClientWithPermissions([]string{"travelrule:view"}).DownloadReport(context.Background(), ulid.MakeSecure(), &api.ReportDownloadQuery{Format: "pdf"}, io.Discard)
+		w.Require().Error(err)
+
+		serr, ok := err.(*api.StatusError)
+		w.Require().True(ok, "expected a status error")
+		w.Require().Equal(http.StatusUnprocessableEntity, serr.StatusCode)
+	})
+}
+
+func (w *webTestSuite) TestServerDeleteReport() {
+	w.Run("Success", func() {
+		require := w.Require()
+		reportID := ulid.MakeSecure()
+
+		w.store.OnDeleteReport = func(ctx context.Context, id ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(reportID, id)
+			require.True(auditLog.ChangeNotes.Valid)
+			return nil
+		}
+
+		err := w.ClientWithPermissions([]string{"travelrule:delete"}).DeleteReport(context.Background(), reportID)
+		require.NoError(err, "unexpected client request error")
+	})
+
+	w.Run("NotFound", func() {
+		w.store.OnDeleteReport = func(context.Context, ulid.ULID, *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		err := w.ClientWithPermissions([]string{"travelrule:delete"}).DeleteReport(context.Background(), ulid.MakeSecure())
+		w.Require().ErrorContains(err, "report not found")
+	})
+
+	w.Run("FailureNoPermission", func() {
+		err := w.ClientWithPermissions([]string{"travelrule:manage"}).DeleteReport(context.Background(), ulid.MakeSecure())
+		w.Require().ErrorContains(err, "user does not have permission to perform this operation")
+	})
+}
@@ -0,0 +1,269 @@
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/htmx"
+	"github.com/trisacrypto/envoy/pkg/web/scene"
+)
+
+func (s *Server) ListRoles(c *gin.Context) {
+	var (
+		err   error
+		roles []*models.Role
+		out   *api.RoleList
+	)
+
+	if roles, err = s.store.ListRoles(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process role list request"))
+		return
+	}
+
+	if out, err = api.NewRoleList(roles); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process role list request"))
+		return
+	}
+
+	// Content negotiation
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/users/roles.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
+func (s *Server) CreateRole(c *gin.Context) {
+	var (
+		err  error
+		in   *api.Role
+		role *models.Role
+		out  *api.Role
+	)
+
+	in = &api.Role{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse role data"))
+		return
+	}
+
+	// NOTE: this also validates the permissions using the permissions package in auth
+	if err = in.Validate(true); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if role, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	if err = s.store.CreateRole(c.Request.Context(), role, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateRole()"},
+	}); err != nil {
+		s.roleError(c, err, "could not process create role request")
+		return
+	}
+
+	if out, err = api.NewRole(role); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create role request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusCreated, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.RolesUpdated)
+	}
+}
+
+func (s *Server) RoleDetail(c *gin.Context) {
+	var (
+		err  error
+		role *models.Role
+		out  *api.Role
+	)
+
+	if role, err = s.retrieveRole(c); err != nil {
+		s.roleError(c, err, "could not process role detail request")
+		return
+	}
+
+	if out, err = api.NewRole(role); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process role detail request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) UpdateRolePreview(c *gin.Context) {
+	var (
+		err  error
+		role *models.Role
+		out  *api.Role
+	)
+
+	// Preview requests target a UI only audience and therefore only accept text/html
+	// requests (Accept: text/html). JSON requests return a 406 error. The endpoint
+	// still may return JSON errors for AJAX handling on the front-end.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if role, err = s.retrieveRole(c); err != nil {
+		s.roleError(c, err, "could not process role detail request")
+		return
+	}
+
+	if role.IsBuiltin {
+		c.JSON(http.StatusForbidden, api.Error(dberr.ErrBuiltinRole.Error()))
+		return
+	}
+
+	if out, err = api.NewRole(role); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process role detail request"))
+		return
+	}
+
+	// Render the edit form for the role
+	c.HTML(http.StatusOK, "partials/users/editRole.html", scene.New(c).WithAPIData(out))
+}
+
+func (s *Server) UpdateRole(c *gin.Context) {
+	var (
+		err  error
+		in   *api.Role
+		role *models.Role
+		out  *api.Role
+	)
+
+	in = &api.Role{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse role data"))
+		return
+	}
+
+	// Roles are identified by title in the URL, which may be changed by the update
+	ctx := c.Request.Context()
+	if role, err = s.store.LookupRole(ctx, c.Param("role")); err != nil {
+		s.roleError(c, err, "could not process role update request")
+		return
+	}
+
+	// Sanity check
+	if in.ID != 0 && in.ID != role.ID {
+		c.JSON(http.StatusBadRequest, api.Error(ErrIDMismatch))
+		return
+	}
+	in.ID = role.ID
+
+	if err = in.Validate(false); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if role, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	if err = s.store.UpdateRole(ctx, role, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.UpdateRole()"},
+	}); err != nil {
+		s.roleError(c, err, "could not process role update request")
+		return
+	}
+
+	// Retrieve the role to return the complete record (e.g. the MFA policy)
+	if role, err = s.store.RetrieveRole(ctx, role.ID); err != nil {
+		s.roleError(c, err, "could not process role update request")
+		return
+	}
+
+	if out, err = api.NewRole(role); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process role update request"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, out)
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.RolesUpdated)
+	}
+}
+
+func (s *Server) DeleteRole(c *gin.Context) {
+	var (
+		err  error
+		role *models.Role
+	)
+
+	ctx := c.Request.Context()
+	if role, err = s.store.LookupRole(ctx, c.Param("role")); err != nil {
+		s.roleError(c, err, "could not process role delete request")
+		return
+	}
+
+	if err = s.store.DeleteRole(ctx, role.ID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.DeleteRole()"},
+	}); err != nil {
+		s.roleError(c, err, "could not process role delete request")
+		return
+	}
+
+	if htmx.IsHTMXRequest(c) {
+		htmx.Trigger(c, htmx.RolesUpdated)
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Reply{Success: true})
+}
+
+// Looks up the role by the title in the URL and retrieves it with its permissions.
+func (s *Server) retrieveRole(c *gin.Context) (role *models.Role, err error) {
+	ctx := c.Request.Context()
+	if role, err = s.store.LookupRole(ctx, c.Param("role")); err != nil {
+		return nil, err
+	}
+	return s.store.RetrieveRole(ctx, role.ID)
+}
+
+// Maps role store errors onto the appropriate http responses.
+func (s *Server) roleError(c *gin.Context, err error, msg string) {
+	switch {
+	case errors.Is(err, dberr.ErrNotFound):
+		c.JSON(http.StatusNotFound, api.Error("role not found"))
+	case errors.Is(err, dberr.ErrBuiltinRole):
+		c.JSON(http.StatusForbidden, api.Error(err.Error()))
+	case errors.Is(err, dberr.ErrAlreadyExists):
+		c.JSON(http.StatusConflict, api.Error("a role with this title already exists"))
+	case errors.Is(err, dberr.ErrRoleInUse):
+		c.JSON(http.StatusConflict, api.Error("role is assigned to one or more users or pending invites and cannot be deleted"))
+	case errors.Is(err, dberr.ErrUnknownPermission):
+		c.JSON(http.StatusBadRequest, api.Error(err.Error()))
+	default:
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error(msg))
+	}
+}
@@ -0,0 +1,267 @@
+package web_test
+
+import (
+	"context"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+func (w *webTestSuite) TestServerListRoles() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListRoles = func(ctx context.Context) ([]*models.Role, error) {
+			return []*models.Role{
+				{ID: 1, Title: "Admin", RequireMFA: true},
+				{ID: 2, Title: "Compliance", IsDefault: true},
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).ListRoles(ctx)
+		require.NoError(err, "unexpected client request error")
+		require.Len(out.Roles, 2)
+		require.True(out.Roles[0].RequireMFA, "expected the admin role to require mfa")
+		require.False(out.Roles[1].RequireMFA, "expected the compliance role to not require mfa")
+	})
+
+	w.Run("FailureNoAuth", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientNoAuth().ListRoles(ctx)
+		require.ErrorContains(err, "this endpoint requires authentication", "the user should not be authenticated")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerCreateRole() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnCreateRole = func(ctx context.Context, role *models.Role, auditLog *models.ComplianceAuditLog) error {
+			require.Equal("Auditor", role.Title)
+			permissions, err := role.Permissions()
+			require.NoError(err, "expected permissions to be set on the role")
+			require.Len(permissions, 2)
+			role.ID = 4
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).CreateRole(ctx, &api.Role{
+			Title:       "Auditor",
+			Permissions: []string{"travelrule:view", "legalholds:view"},
+		})
+		require.NoError(err, "unexpected client request error")
+		require.Equal(int64(4), out.ID)
+		require.False(out.IsBuiltin)
+		require.Equal([]string{"travelrule:view", "legalholds:view"}, out.Permissions)
+		w.store.AssertCalls(w.T(), "CreateRole", 1)
+	})
+
+	w.Run("InvalidPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).CreateRole(ctx, &api.Role{
+			Title:       "Auditor",
+			Permissions: []string{"travelrule:audit"},
+		})
+		require.ErrorContains(err, `"travelrule:audit" is not a valid permission`)
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "CreateRole", 0)
+	})
+
+	w.Run("AlreadyExists", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnCreateRole = func(ctx context.Context, role *models.Role, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrAlreadyExists
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).CreateRole(ctx, &api.Role{
+			Title:       "admin",
+			Permissions: []string{"users:view"},
+		})
+		require.ErrorContains(err, "a role with this title already exists")
+		require.Nil(out)
+	})
+
+	w.Run("FailureNoPermissions", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).CreateRole(ctx, &api.Role{
+			Title:       "Auditor",
+			Permissions: []string{"users:view"},
+		})
+		require.ErrorContains(err, "user does not have permission to perform this operation", "the user should not be authorized")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerRoleDetail() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			require.Equal("Auditor", role)
+			return &models.Role{ID: 4, Title: "Auditor"}, nil
+		}
+		w.store.OnRetrieveRole = func(ctx context.Context, roleID int64) (*models.Role, error) {
+			require.Equal(int64(4), roleID)
+			role := &models.Role{ID: 4, Title: "Auditor"}
+			role.SetPermissions([]*models.Permission{{ID: 19, Title: "users:view"}})
+			return role, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).RoleDetail(ctx, "Auditor")
+		require.NoError(err, "unexpected client request error")
+		require.Equal([]string{"users:view"}, out.Permissions)
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:view"}).RoleDetail(ctx, "foo")
+		require.ErrorContains(err, "role not found")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerUpdateRole() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return &models.Role{ID: 4, Title: "Auditor"}, nil
+		}
+		w.store.OnUpdateRole = func(ctx context.Context, role *models.Role, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(int64(4), role.ID)
+			require.Equal("Senior Auditor", role.Title)
+			return nil
+		}
+		w.store.OnRetrieveRole = func(ctx context.Context, roleID int64) (*models.Role, error) {
+			role := &models.Role{ID: 4, Title: "Senior Auditor", RequireMFA: true}
+			role.SetPermissions([]*models.Permission{{ID: 19, Title: "users:view"}})
+			return role, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).UpdateRole(ctx, "Auditor", &api.Role{
+			Title:       "Senior Auditor",
+			Permissions: []string{"users:view"},
+		})
+		require.NoError(err, "unexpected client request error")
+		require.Equal("Senior Auditor", out.Title)
+		require.True(out.RequireMFA, "expected the mfa policy to be returned")
+		w.store.AssertCalls(w.T(), "UpdateRole", 1)
+	})
+
+	w.Run("Builtin", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return &models.Role{ID: 1, Title: "Admin", IsBuiltin: true}, nil
+		}
+		w.store.OnUpdateRole = func(ctx context.Context, role *models.Role, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrBuiltinRole
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).UpdateRole(ctx, "Admin", &api.Role{
+			Title:       "Admin",
+			Permissions: []string{"users:view"},
+		})
+		require.ErrorContains(err, "built-in roles cannot be modified or deleted")
+		require.Nil(out)
+	})
+
+	w.Run("IDMismatch", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return &models.Role{ID: 4, Title: "Auditor"}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"users:manage"}).UpdateRole(ctx, "Auditor", &api.Role{
+			ID:          5,
+			Title:       "Auditor",
+			Permissions: []string{"users:view"},
+		})
+		require.ErrorContains(err, "resource id does not match target")
+		require.Nil(out)
+		w.store.AssertCalls(w.T(), "UpdateRole", 0)
+	})
+}
+
+func (w *webTestSuite) TestServerDeleteRole() {
+	w.Run("Success", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return &models.Role{ID: 4, Title: "Auditor"}, nil
+		}
+		w.store.OnDeleteRole = func(ctx context.Context, roleID int64, auditLog *models.ComplianceAuditLog) error {
+			require.Equal(int64(4), roleID)
+			return nil
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).DeleteRole(ctx, "Auditor")
+		require.NoError(err, "unexpected client request error")
+		w.store.AssertCalls(w.T(), "DeleteRole", 1)
+	})
+
+	w.Run("InUse", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnLookupRole = func(ctx context.Context, role string) (*models.Role, error) {
+			return &models.Role{ID: 4, Title: "Auditor"}, nil
+		}
+		w.store.OnDeleteRole = func(ctx context.Context, roleID int64, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrRoleInUse
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:manage"}).DeleteRole(ctx, "Auditor")
+		require.ErrorContains(err, "role is assigned to one or more users")
+	})
+
+	w.Run("FailureNoPermissions", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		err := w.ClientWithPermissions([]string{"users:view"}).DeleteRole(ctx, "Auditor")
+		require.ErrorContains(err, "user does not have permission to perform this operation", "the user should not be authorized")
+	})
+}
@@ -58,6 +58,11 @@ func (s *Server) setupRoutes() (err error) {
 	s.router.GET("/livez", s.Healthz)
 	s.router.GET("/readyz", s.Readyz)
 
+	// Token verification keys and discovery added before middleware so that they are
+	// available to relying parties whether or not the API and UI are enabled.
+	s.router.GET("/.well-known/jwks.json", s.JWKS)
+	s.router.GET("/.well-known/openid-configuration", s.OpenIDConfiguration)
+
 	// Prometheus metrics handler added before middleware.
 	// Note metrics will be served at /metrics
 	metrics.Routes(s.router)
@@ -83,9 +88,12 @@ func (s *Server) setupRoutes() (err error) {
 	s.router.StaticFS("/static", http.FS(staticFiles))
 
 	// Authentication Middleware
-	authenticate := auth.Authenticate(s.issuer)
+	authenticate := auth.Authenticate(s.issuer, s.store, s)
 	sunriseAuth := s.SunriseAuthenticate(s.issuer)
 
+	// Replays responses to retried requests with an Idempotency-Key header
+	idempotent := s.Idempotent()
+
 	// Authorization Helper
 	authorize := func(permissions ...permiss.Permission) gin.HandlerFunc {
 		perms := permiss.Permissions(permissions)
@@ -95,9 +103,12 @@ func (s *Server) setupRoutes() (err error) {
 	// Web UI Routes (Dashboards and Pages) - Unauthenticated
 	s.router.GET("/login", s.LoginPage)
 	s.router.GET("/logout", s.Logout)
+	s.router.GET("/login/oidc", s.LoginOIDC)
+	s.router.GET("/login/oidc/callback", s.LoginOIDCCallback)
 	s.router.GET("/forgot-password", s.ForgotPasswordPage)
 	s.router.GET("/forgot-password/sent", s.ForgotPasswordSentPage)
 	s.router.GET("/reset-password", s.ResetPasswordPage)
+	s.router.GET("/invite", s.AcceptInvitePage)
 
 	// Web UI Routes (Dashboards and Pages) - Authenticated
 	ui := s.router.Group("", authenticate)
@@ -109,6 +120,11 @@ func (s *Server) setupRoutes() (err error) {
 		ui.GET("/counterparties/:id", s.CounterpartyDetailPage)
 		ui.GET("/users", s.UsersListPage)
 		ui.GET("/apikeys", s.APIKeysListPage)
+		ui.GET("/approvals", authorize(permiss.TravelRuleView), s.ApprovalsListPage)
+		ui.GET("/queue", authorize(permiss.TravelRuleView), s.QueuedTransfersPage)
+		ui.GET("/batches", authorize(permiss.TravelRuleView), s.BatchesListPage)
+		ui.GET("/batches/:id", authorize(permiss.TravelRuleView), s.BatchDetailPage)
+		ui.GET("/reports", authorize(permiss.TravelRuleView), s.ReportsListPage)
 		ui.GET("/utilities/travel-address", s.TravelAddressUtility)
 
 		// Accounts Pages
@@ -192,6 +208,7 @@ func (s *Server) setupRoutes() (err error) {
 		v1.POST("/forgot-password", s.ForgotPassword)
 		v1.POST("/reset-password", s.ResetPassword)
 		v1.POST("/change-password", authenticate, s.ChangePassword)
+		v1.POST("/accept-invite", s.AcceptInvite)
 
 		// Accounts Resource
 		accounts := v1.Group("/accounts", authenticate)
@@ -222,31 +239,42 @@ func (s *Server) setupRoutes() (err error) {
 		transactions := v1.Group("/transactions", authenticate)
 		{
 			transactions.GET("", authorize(permiss.TravelRuleView), s.ListTransactions)
-			transactions.POST("", authorize(permiss.TravelRuleManage), s.CreateTransaction)
+			transactions.POST("", authorize(permiss.TravelRuleManage), idempotent, s.CreateTransaction)
 			transactions.GET("/:id", authorize(permiss.TravelRuleView), s.TransactionDetail)
 			transactions.PUT("/:id", authorize(permiss.TravelRuleManage), s.UpdateTransaction)
 			transactions.DELETE("/:id", authorize(permiss.TravelRuleDelete), s.DeleteTransaction)
 
 			// Primarily UI methods but are also API Helper Methods
 			transactions.POST("/prepare", authorize(permiss.TravelRuleManage), s.PrepareTransaction)
-			transactions.POST("/send-prepared", authorize(permiss.TravelRuleManage), s.SendPreparedTransaction)
+			transactions.POST("/send-prepared", authorize(permiss.TravelRuleManage), idempotent, s.SendPreparedTransaction)
 
 			// Export method to export transactions to a CSV
 			transactions.GET("/export", authorize(permiss.TravelRuleManage), s.ExportTransactions)
 
+			// Lists all of the tags applied to transactions (e.g. for filtering)
+			transactions.GET("/tags", authorize(permiss.TravelRuleView), s.ListTransactionTags)
+
 			// Transaction specific actions
-			transactions.POST("/:id/send", authorize(permiss.TravelRuleManage), s.SendEnvelopeForTransaction)
+			transactions.POST("/:id/send", authorize(permiss.TravelRuleManage), idempotent, s.SendEnvelopeForTransaction)
 			transactions.GET("/:id/latest", authorize(permiss.TravelRuleView), s.LatestEnvelope)
 			transactions.GET("/:id/payload", authorize(permiss.TravelRuleView), s.LatestPayloadEnvelope)
+			transactions.GET("/:id/evidence", authorize(permiss.TravelRuleManage, permiss.CounterpartiesView), s.TransactionEvidence)
 			transactions.GET("/:id/accept", authorize(permiss.TravelRuleView), s.AcceptTransactionPreview)
-			transactions.POST("/:id/accept", authorize(permiss.TravelRuleManage), s.AcceptTransaction)
-			transactions.POST("/:id/reject", authorize(permiss.TravelRuleManage), s.RejectTransaction)
+			transactions.POST("/:id/accept", authorize(permiss.TravelRuleManage), idempotent, s.AcceptTransaction)
+			transactions.POST("/:id/reject", authorize(permiss.TravelRuleManage), idempotent, s.RejectTransaction)
 			transactions.GET("/:id/repair", authorize(permiss.TravelRuleView), s.RepairTransactionPreview)
 			transactions.POST("/:id/repair", authorize(permiss.TravelRuleManage), s.RepairTransaction)
 			transactions.GET("/:id/complete", authorize(permiss.TravelRuleView), s.CompleteTransactionPreview)
-			transactions.POST("/:id/complete", authorize(permiss.TravelRuleManage), s.CompleteTransaction)
+			transactions.POST("/:id/complete", authorize(permiss.TravelRuleManage), idempotent, s.CompleteTransaction)
 			transactions.POST("/:id/archive", authorize(permiss.TravelRuleManage), s.ArchiveTransaction)
 			transactions.POST("/:id/unarchive", authorize(permiss.TravelRuleManage), s.UnarchiveTransaction)
+			transactions.GET("/:id/history", authorize(permiss.TravelRuleView), s.TransactionHistory)
+
+			// Case management of the review of the transaction
+			transactions.GET("/:id/notes", authorize(permiss.TravelRuleView), s.ListTransactionNotes)
+			transactions.POST("/:id/notes", authorize(permiss.TravelRuleManage), s.CreateTransactionNote)
+			transactions.PUT("/:id/assignee", authorize(permiss.TravelRuleManage), s.AssignTransaction)
+			transactions.PUT("/:id/tags", authorize(permiss.TravelRuleManage), s.SetTransactionTags)
 
 			// SecureEnvelope Resource (nested on Transactions)
 			se := transactions.Group("/:id/secure-envelopes")
@@ -266,6 +294,8 @@ func (s *Server) setupRoutes() (err error) {
 			counterparties.GET("/:id/edit", authorize(permiss.CounterpartiesManage), s.UpdateCounterpartyPreview)
 			counterparties.PUT("/:id", authorize(permiss.CounterpartiesManage), s.UpdateCounterparty)
 			counterparties.DELETE("/:id", authorize(permiss.CounterpartiesManage), s.DeleteCounterparty)
+			counterparties.GET("/:id/pending", authorize(permiss.CounterpartiesView), s.CounterpartyPending)
+			counterparties.PUT("/:id/pending", authorize(permiss.CounterpartiesManage), s.UpdateCounterpartyPending)
 
 			// Contacts Resource (nested on Counterparty)
 			contacts := counterparties.Group("/:id/contacts")
@@ -287,6 +317,29 @@ func (s *Server) setupRoutes() (err error) {
 			users.PUT("/:id", authorize(permiss.UsersManage), s.UpdateUser)
 			users.DELETE("/:id", authorize(permiss.UsersManage), s.DeleteUser)
 			users.POST("/:id/password", authorize(permiss.UsersManage), s.ChangeUserPassword)
+			users.POST("/:id/mfa/reset", authorize(permiss.UsersManage), s.ResetUserMFA)
+			users.POST("/:id/unlock", authorize(permiss.UsersManage), s.UnlockUser)
+		}
+
+		// User Invites Resource
+		invites := v1.Group("/invites", authenticate)
+		{
+			invites.GET("", authorize(permiss.UsersView), s.ListInvites)
+			invites.POST("", authorize(permiss.UsersManage), s.CreateInvite)
+			invites.POST("/:id/resend", authorize(permiss.UsersManage), s.ResendInvite)
+			invites.DELETE("/:id", authorize(permiss.UsersManage), s.RevokeInvite)
+		}
+
+		// Roles Resource
+		roles := v1.Group("/roles", authenticate)
+		{
+			roles.GET("", authorize(permiss.UsersView), s.ListRoles)
+			roles.POST("", authorize(permiss.UsersManage), s.CreateRole)
+			roles.GET("/:role", authorize(permiss.UsersView), s.RoleDetail)
+			roles.GET("/:role/edit", authorize(permiss.UsersManage), s.UpdateRolePreview)
+			roles.PUT("/:role", authorize(permiss.UsersManage), s.UpdateRole)
+			roles.DELETE("/:role", authorize(permiss.UsersManage), s.DeleteRole)
+			roles.PUT("/:role/mfa", authorize(permiss.UsersManage), s.SetRoleMFAPolicy)
 		}
 
 		// Profile Resource: Similar to user resource but for logged in user and does
@@ -298,6 +351,13 @@ func (s *Server) setupRoutes() (err error) {
 			profile.PUT("", s.UpdateProfile)
 			profile.DELETE("", s.DeleteProfile)
 			profile.POST("/password", s.ChangeProfilePassword)
+			profile.GET("/mfa", s.ProfileMFA)
+			profile.POST("/mfa", s.EnrollProfileMFA)
+			profile.POST("/mfa/verify", s.VerifyProfileMFA)
+			profile.POST("/mfa/recovery-codes", s.RegenerateProfileRecoveryCodes)
+			profile.POST("/mfa/disable", s.DisableProfileMFA)
+			profile.GET("/sessions", s.ProfileSessions)
+			profile.DELETE("/sessions/:sessionID", s.RevokeProfileSession)
 		}
 
 		// API Keys Resource
@@ -308,6 +368,7 @@ func (s *Server) setupRoutes() (err error) {
 			apikeys.GET("/:id", authorize(permiss.APIKeysView), s.APIKeyDetail)
 			apikeys.GET("/:id/edit", authorize(permiss.APIKeysManage), s.UpdateAPIKeyPreview)
 			apikeys.PUT("/:id", authorize(permiss.APIKeysManage), s.UpdateAPIKey)
+			apikeys.POST("/:id/rotate", authorize(permiss.APIKeysManage), s.RotateAPIKey)
 			apikeys.DELETE("/:id", authorize(permiss.APIKeysRevoke), s.DeleteAPIKey)
 		}
 
@@ -328,6 +389,75 @@ func (s *Server) setupRoutes() (err error) {
 
 		}
 
+		// Legal Holds Resource
+		legalholds := v1.Group("/legalholds", authenticate)
+		{
+			legalholds.GET("", authorize(permiss.LegalHoldsView), s.ListLegalHolds)
+			legalholds.POST("", authorize(permiss.LegalHoldsManage), s.CreateLegalHold)
+			legalholds.GET("/:id", authorize(permiss.LegalHoldsView), s.LegalHoldDetail)
+			legalholds.PUT("/:id", authorize(permiss.LegalHoldsManage), s.UpdateLegalHold)
+			legalholds.POST("/:id/release", authorize(permiss.LegalHoldsManage), s.ReleaseLegalHold)
+		}
+
+		// Approvals Resource
+		approvals := v1.Group("/approvals", authenticate)
+		{
+			approvals.GET("", authorize(permiss.TravelRuleView), s.ListApprovals)
+			approvals.GET("/:id", authorize(permiss.TravelRuleView), s.ApprovalDetail)
+			approvals.POST("/:id/approve", authorize(permiss.ApprovalsReview), s.ApproveAction)
+			approvals.POST("/:id/deny", authorize(permiss.ApprovalsReview), s.DenyAction)
+			approvals.POST("/:id/cancel", authorize(permiss.TravelRuleManage), s.CancelAction)
+
+			// Approval Rules Resource (nested on Approvals)
+			rules := approvals.Group("/rules")
+			{
+				rules.GET("", authorize(permiss.TravelRuleView), s.ListApprovalRules)
+				rules.POST("", authorize(permiss.ApprovalsManage), s.CreateApprovalRule)
+				rules.GET("/:id", authorize(permiss.TravelRuleView), s.ApprovalRuleDetail)
+				rules.PUT("/:id", authorize(permiss.ApprovalsManage), s.UpdateApprovalRule)
+				rules.DELETE("/:id", authorize(permiss.ApprovalsManage), s.DeleteApprovalRule)
+			}
+		}
+
+		// Outbound Queue Resource
+		queue := v1.Group("/queue", authenticate)
+		{
+			queue.GET("", authorize(permiss.TravelRuleView), s.ListQueuedTransfers)
+			queue.GET("/:id", authorize(permiss.TravelRuleView), s.QueuedTransferDetail)
+			queue.POST("/:id/cancel", authorize(permiss.TravelRuleManage), s.CancelQueuedTransfer)
+			queue.POST("/:id/retry", authorize(permiss.TravelRuleManage), s.RetryQueuedTransfer)
+		}
+
+		// Batch Import Resource
+		batches := v1.Group("/batches", authenticate)
+		{
+			batches.GET("", authorize(permiss.TravelRuleView), s.ListBatches)
+			batches.POST("", authorize(permiss.TravelRuleManage), idempotent, s.CreateBatch)
+			batches.GET("/:id", authorize(permiss.TravelRuleView), s.BatchDetail)
+			batches.POST("/:id/send", authorize(permiss.TravelRuleManage), idempotent, s.SendBatch)
+			batches.POST("/:id/cancel", authorize(permiss.TravelRuleManage), s.CancelBatch)
+		}
+
+		// Regulatory Reports Resource
+		reports := v1.Group("/reports", authenticate)
+		{
+			reports.GET("", authorize(permiss.TravelRuleView), s.ListReports)
+			reports.POST("", authorize(permiss.TravelRuleManage), s.CreateReport)
+			reports.GET("/:id", authorize(permiss.TravelRuleView), s.ReportDetail)
+			reports.GET("/:id/download", authorize(permiss.TravelRuleView), s.DownloadReport)
+			reports.DELETE("/:id", authorize(permiss.TravelRuleDelete), s.DeleteReport)
+		}
+
+		// Travel Rule Threshold Rules Resource
+		rules := v1.Group("/thresholds", authenticate)
+		{
+			rules.GET("", authorize(permiss.TravelRuleView), s.ListThresholdRules)
+			rules.POST("", authorize(permiss.ConfigManage), s.CreateThresholdRule)
+			rules.GET("/:id", authorize(permiss.TravelRuleView), s.ThresholdRuleDetail)
+			rules.PUT("/:id", authorize(permiss.ConfigManage), s.UpdateThresholdRule)
+			rules.DELETE("/:id", authorize(permiss.ConfigManage), s.DeleteThresholdRule)
+		}
+
 		// Utilities
 		utils := v1.Group("/utilities", authenticate)
 		{
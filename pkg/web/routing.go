@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/gin-gonic/gin"
 	"github.com/trisacrypto/envoy/pkg/enum"
 	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
@@ -59,50 +58,33 @@ func (s *Server) CounterpartyRouting(ctx context.Context, in *api.RoutingQuery)
 }
 
 // Resolves a counterparty from the routing information and validates in the incoming
-// counterparty. If there is an error, this function will return the appropriate error
-// response and status code to the client so the caller simply has to terminate handling.
-func (s *Server) ResolveCounterparty(c *gin.Context, in *api.Routing) (vasp *models.Counterparty, err error) {
+// counterparty. If there is an error, a *ResponseError is returned that describes the
+// response and status code to return to the client.
+func (s *Server) ResolveCounterparty(ctx context.Context, in *api.Routing) (vasp *models.Counterparty, err error) {
 	if err = in.Validate(); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
-		return nil, err
+		return nil, NewResponseError(http.StatusUnprocessableEntity, "", err)
 	}
 
 	var protocol enum.Protocol
 	if protocol, err = enum.ParseProtocol(in.Protocol); err != nil {
 		// NOTE: if this error occurs, it means that the Validate code above has a bug in it.
-		c.Error(fmt.Errorf("could not parse protocol from valid routing API request: %w", err))
-		c.JSON(http.StatusInternalServerError, api.Error("could not identify counterparty from routing information"))
-		return nil, err
+		return nil, NewResponseError(http.StatusInternalServerError, "could not identify counterparty from routing information", fmt.Errorf("could not parse protocol from valid routing API request: %w", err))
 	}
 
-	ctx := c.Request.Context()
-
 	// Ideally we look up the counterparty by ID for any protocol
 	if !in.CounterpartyID.IsZero() {
 		if vasp, err = s.store.RetrieveCounterparty(ctx, in.CounterpartyID); err != nil {
 			if errors.Is(err, dberr.ErrNotFound) {
-				c.JSON(http.StatusNotFound, api.Error("could not identify counterparty from routing information"))
-				return nil, err
+				return nil, NewResponseError(http.StatusNotFound, "could not identify counterparty from routing information", err)
 			}
-
-			c.Error(err)
-			c.JSON(http.StatusInternalServerError, api.Error("could not identify counterparty from routing information"))
-			return nil, err
+			return nil, NewResponseError(http.StatusInternalServerError, "could not identify counterparty from routing information", err)
 		}
 	} else {
 		// If there was no counterparty ID given, then try the backup methods for lookup on a per-protocol basis
 		switch protocol {
-		case enum.ProtocolTRISA:
+		case enum.ProtocolTRISA, enum.ProtocolTRP:
 			// Lookup the counterparty by travel address
-			if vasp, err = s.CounterpartyFromTravelAddress(c, in.TravelAddress); err != nil {
-				// NOTE: CounterpartyFromTravelAddress handles API response back to user.
-				return nil, err
-			}
-
-		case enum.ProtocolTRP:
-			// Lookup the counterparty by travel address
-			if vasp, err = s.CounterpartyFromTravelAddress(c, in.TravelAddress); err != nil {
-				// NOTE: CounterpartyFromTravelAddress handles API response back to user.
+			if vasp, err = s.CounterpartyFromTravelAddress(ctx, in.TravelAddress); err != nil {
 				return nil, err
 			}
 
@@ -111,9 +93,7 @@ func (s *Server) ResolveCounterparty(c *gin.Context, in *api.Routing) (vasp *mod
 			if vasp, err = s.store.GetOrCreateSunriseCounterparty(ctx, in.EmailAddress, in.Counterparty, &models.ComplianceAuditLog{
 				ChangeNotes: sql.NullString{Valid: true, String: "Server.ResolveCounterparty()"},
 			}); err != nil {
-				c.Error(err)
-				c.JSON(http.StatusConflict, api.Error("could not find or create a counterparty with the specified name and/or email address"))
-				return nil, err
+				return nil, NewResponseError(http.StatusConflict, "could not find or create a counterparty with the specified name and/or email address", err)
 			}
 
 		default:
@@ -126,45 +106,35 @@ func (s *Server) ResolveCounterparty(c *gin.Context, in *api.Routing) (vasp *mod
 	switch {
 	// If vasp is nil we probably shouldn't have made it this far in the code, but protecting ourselves anyway.
 	case vasp == nil:
-		c.JSON(http.StatusNotFound, api.Error("could not identify counterparty from routing information"))
-		return nil, errors.New("unhandled nil vasp at end of resolve counterparty")
+		return nil, NewResponseError(http.StatusNotFound, "could not identify counterparty from routing information", errors.New("unhandled nil vasp at end of resolve counterparty"))
 	case vasp.Protocol != protocol:
-		err := errors.New("could not find counterparty that supports requested protocol")
-		c.JSON(http.StatusNotFound, api.Error(err))
-		return nil, err
+		return nil, NewResponseError(http.StatusNotFound, "", errors.New("could not find counterparty that supports requested protocol"))
 	default:
 		return vasp, nil
 	}
 }
 
-func (s *Server) CounterpartyFromTravelAddress(c *gin.Context, address string) (cp *models.Counterparty, err error) {
+// Looks up the counterparty from the travel address. If there is an error, a
+// *ResponseError is returned that describes the response to return to the client.
+func (s *Server) CounterpartyFromTravelAddress(ctx context.Context, address string) (cp *models.Counterparty, err error) {
 	var (
 		dst    string
 		dstURI *traddr.URL
 	)
 
 	if dst, err = traddr.Decode(address); err != nil {
-		c.Error(fmt.Errorf("could not decode travel address %q: %w", address, err))
-		c.JSON(http.StatusBadRequest, api.Error("could not parse the travel address"))
-		return nil, err
+		return nil, NewResponseError(http.StatusBadRequest, "could not parse the travel address", fmt.Errorf("could not decode travel address %q: %w", address, err))
 	}
 
 	if dstURI, err = traddr.Parse(dst); err != nil {
-		c.Error(err)
-		c.JSON(http.StatusBadRequest, api.Error("could not parse travel address url"))
-		return nil, err
+		return nil, NewResponseError(http.StatusBadRequest, "could not parse travel address url", err)
 	}
 
-	if cp, err = s.findCounterparty(c.Request.Context(), dstURI); err != nil {
+	if cp, err = s.findCounterparty(ctx, dstURI); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
-			c.Error(fmt.Errorf("could not identify counterparty for %s or %s", dstURI.Hostname(), dstURI.Host))
-			c.JSON(http.StatusNotFound, api.Error("could not identify counterparty from travel address"))
-			return nil, err
+			return nil, NewResponseError(http.StatusNotFound, "could not identify counterparty from travel address", fmt.Errorf("could not identify counterparty for %s or %s: %w", dstURI.Hostname(), dstURI.Host, err))
 		}
-
-		c.Error(err)
-		c.JSON(http.StatusInternalServerError, api.Error("could not complete request"))
-		return nil, err
+		return nil, NewResponseError(http.StatusInternalServerError, "could not complete request", err)
 	}
 
 	return cp, nil
@@ -2,6 +2,8 @@ package scene
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
 	"github.com/trisacrypto/trisa/pkg/ivms101"
@@ -12,6 +14,7 @@ type Prepared struct {
 	Routing     *api.Routing
 	identity    *ivms101.IdentityPayload
 	transaction *generic.Transaction
+	travelRule  *api.TravelRule
 }
 
 type Transfer struct {
@@ -24,10 +27,21 @@ type Transfer struct {
 	Tag          string
 }
 
+// TravelRule describes the threshold rule evaluation of the transfer for display.
+type TravelRule struct {
+	Required           bool
+	OriginatorCountry  string
+	BeneficiaryCountry string
+	FiatAmount         string
+	Rule               string
+	Threshold          string
+}
+
 func (s Scene) Prepared() Prepared {
 	if prepared, ok := s[APIData].(*api.Prepared); ok {
 		return Prepared{
 			Routing:     prepared.Routing,
+			travelRule:  prepared.TravelRule,
 			identity:    prepared.Identity,
 			transaction: prepared.Transaction,
 		}
@@ -50,6 +64,30 @@ func (s Prepared) Transfer() Transfer {
 	}
 }
 
+// TravelRule returns nil if the transfer was not evaluated against threshold rules.
+func (s Prepared) TravelRule() *TravelRule {
+	if s.travelRule == nil {
+		return nil
+	}
+
+	out := &TravelRule{
+		Required:           s.travelRule.Required,
+		OriginatorCountry:  s.travelRule.OriginatorCountry,
+		BeneficiaryCountry: s.travelRule.BeneficiaryCountry,
+		Rule:               s.travelRule.Rule,
+	}
+
+	if s.travelRule.FiatAmount != nil {
+		out.FiatAmount = strings.TrimSpace(fmt.Sprintf("%s %.2f", s.travelRule.FiatCurrency, *s.travelRule.FiatAmount))
+	}
+
+	if s.travelRule.Threshold != nil {
+		out.Threshold = strings.TrimSpace(fmt.Sprintf("%s %.2f", s.travelRule.FiatCurrency, *s.travelRule.Threshold))
+	}
+
+	return out
+}
+
 func (s Prepared) Originator() Person {
 	if s.identity == nil || s.identity.Originator == nil || len(s.identity.Originator.OriginatorPersons) == 0 {
 		return Person{}
@@ -15,6 +15,7 @@ import (
 	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/web/api/v1"
 	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
 	"go.rtnl.ai/ulid"
 	"go.rtnl.ai/x/typecase"
 )
@@ -38,6 +39,11 @@ var (
 	// daybreakEnabled is true iff daybreak is enabled by the configuration
 	daybreakEnabled *bool
 
+	// Login configuration for single sign-on and password authentication
+	ssoEnabled            *bool
+	ssoProviderName       *string
+	passwordLoginDisabled *bool
+
 	// Sets a custom logo for the web UI (either a file in static or a URL)
 	logoURI string
 )
@@ -58,6 +64,10 @@ const (
 	SunriseEnabled  = "SunriseEnabled"
 	DaybreakEnabled = "DaybreakEnabled"
 	LogoURI         = "LogoURI"
+
+	SSOEnabled            = "SSOEnabled"
+	SSOProviderName       = "SSOProviderName"
+	PasswordLoginDisabled = "PasswordLoginDisabled"
 )
 
 const (
@@ -112,6 +122,18 @@ func New(c *gin.Context) Scene {
 	if daybreakEnabled != nil {
 		context[DaybreakEnabled] = *daybreakEnabled
 	}
+
+	if ssoEnabled != nil {
+		context[SSOEnabled] = *ssoEnabled
+	}
+
+	if ssoProviderName != nil {
+		context[SSOProviderName] = *ssoProviderName
+	}
+
+	if passwordLoginDisabled != nil {
+		context[PasswordLoginDisabled] = *passwordLoginDisabled
+	}
 	return context
 }
 
@@ -190,6 +212,13 @@ func (s Scene) HasRole(role string) bool {
 	return false
 }
 
+func (s Scene) HasPermission(permission string) bool {
+	if user := s.GetUser(); user != nil {
+		return user.HasPermission(permission)
+	}
+	return false
+}
+
 func (s Scene) IsAdmin() bool {
 	return s.HasRole(RoleAdmin)
 }
@@ -198,6 +227,12 @@ func (s Scene) IsViewOnly() bool {
 	return !s.HasRole(RoleAdmin) && !s.HasRole(RoleCompliance)
 }
 
+// PasswordRequirements describes the configured password policy for display on the
+// password change and reset forms.
+func (s Scene) PasswordRequirements() []string {
+	return passwords.GetPolicy().Requirements()
+}
+
 //===========================================================================
 // Scene API Data Related Helpers
 //===========================================================================
@@ -261,6 +296,33 @@ func (s Scene) UserDetail() *api.User {
 	return nil
 }
 
+func (s Scene) UserInviteList() *api.UserInviteList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.UserInviteList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) RoleList() *api.RoleList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.RoleList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) RoleDetail() *api.Role {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.Role); ok {
+			return out
+		}
+	}
+	return nil
+}
+
 func (s Scene) CounterpartyList() *api.CounterpartyList {
 	if data, ok := s[APIData]; ok {
 		if out, ok := data.(*api.CounterpartyList); ok {
@@ -279,6 +341,15 @@ func (s Scene) CounterpartyDetail() *api.Counterparty {
 	return nil
 }
 
+func (s Scene) CounterpartyPending() *api.CounterpartyPending {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.CounterpartyPending); ok {
+			return out
+		}
+	}
+	return nil
+}
+
 func (s Scene) APIKeysList() *api.APIKeyList {
 	if data, ok := s[APIData]; ok {
 		if out, ok := data.(*api.APIKeyList); ok {
@@ -306,6 +377,69 @@ func (s Scene) APIKeyDetail() *api.APIKey {
 	return nil
 }
 
+func (s Scene) LegalHoldList() *api.LegalHoldList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.LegalHoldList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) ApprovalList() *api.ApprovalList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.ApprovalList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) ApprovalRuleList() *api.ApprovalRuleList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.ApprovalRuleList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) QueuedTransferList() *api.QueuedTransferList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.QueuedTransferList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) BatchList() *api.BatchList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.BatchList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) Batch() *api.Batch {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.Batch); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) ReportList() *api.ReportList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.ReportList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
 func (s Scene) EnvelopeList() *api.EnvelopesList {
 	if data, ok := s[APIData]; ok {
 		if out, ok := data.(*api.EnvelopesList); ok {
@@ -425,6 +559,10 @@ func WithConf(conf *config.Config) {
 	trpEnabled = &conf.TRP.Enabled
 	daybreakEnabled = &conf.Web.Daybreak.Enabled
 
+	ssoEnabled = &conf.Web.Auth.OIDC.Enabled
+	ssoProviderName = &conf.Web.Auth.OIDC.ProviderName
+	passwordLoginDisabled = &conf.Web.Auth.DisablePasswordLogin
+
 	// Set the logo URI for the configuration
 	if conf.Web.LogoURI != "" {
 		logoURI = conf.Web.LogoURI
@@ -20,6 +20,19 @@ type Transaction struct {
 	Status Status
 }
 
+// Converted from an *api.TransactionHistory to render the status timeline.
+type TransactionHistory struct {
+	TransactionID string
+	Transitions   []*StatusTransition
+}
+
+// Wraps an *api.StatusTransition to render the statuses of the transition.
+type StatusTransition struct {
+	api.StatusTransition
+	From Status
+	To   Status
+}
+
 //===========================================================================
 // Scene Transaction Helpers
 //===========================================================================
@@ -57,6 +70,37 @@ func (s Scene) TransactionDetail() *Transaction {
 	return nil
 }
 
+func (s Scene) TransactionNoteList() *api.TransactionNoteList {
+	if data, ok := s[APIData]; ok {
+		if out, ok := data.(*api.TransactionNoteList); ok {
+			return out
+		}
+	}
+	return nil
+}
+
+func (s Scene) TransactionHistory() *TransactionHistory {
+	if data, ok := s[APIData]; ok {
+		if history, ok := data.(*api.TransactionHistory); ok {
+			out := &TransactionHistory{
+				TransactionID: history.TransactionID.String(),
+				Transitions:   make([]*StatusTransition, len(history.Transitions)),
+			}
+
+			for i, transition := range history.Transitions {
+				out.Transitions[i] = &StatusTransition{
+					StatusTransition: *transition,
+					From:             NewStatus(transition.From),
+					To:               NewStatus(transition.To),
+				}
+			}
+
+			return out
+		}
+	}
+	return nil
+}
+
 func (s Scene) TransactionCounts() *models.TransactionCounts {
 	if data, ok := s[APIData]; ok {
 		if out, ok := data.(*models.TransactionCounts); ok {
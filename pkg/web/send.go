@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,81 +22,124 @@ import (
 // Send performs the bulk of the work to send a travel rule transfer to the
 // counterparty specified and storing both the outgoing and incoming secure envelopes in
 // the database. This method is used to send the prepared transaction, to send envelopes
-// for a transaction, and in the accept/reject workflows.
-func (s *Server) Send(c *gin.Context, routing *api.Routing, payload *trisa.Payload) (packet *postman.Packet, err error) {
+// for a transaction, and in the accept/reject workflows. If an error is returned then
+// the error response has already been sent to the user.
+//
+// The transfer is not canceled if the user disconnects so that the envelopes are
+// stored once the transfer has been exchanged with the counterparty.
+func (s *Server) Send(c *gin.Context, routing *api.Routing, payload *trisa.Payload, sendAt *time.Time) (packet *postman.Packet, queued bool, err error) {
+	if packet, queued, err = s.send(context.WithoutCancel(c.Request.Context()), routing, payload, sendAt); err != nil {
+		s.ResponseError(c, err)
+		return nil, false, err
+	}
+	return packet, queued, nil
+}
+
+// Sends the transfer to the counterparty without a request so that it can be used by
+// both the API handlers and background tasks; if an error is returned it is a
+// *ResponseError that describes the response to return to the user.
+//
+// If sendAt is in the future, or if the TRISA counterparty cannot be reached and the
+// outbound queue is enabled, the transfer is added to the outbound queue instead and
+// queued is true; the transaction remains pending until the queue sends the transfer.
+func (s *Server) send(ctx context.Context, routing *api.Routing, payload *trisa.Payload, sendAt *time.Time) (packet *postman.Packet, queued bool, err error) {
+	// Scheduled transfers can only be sent if the outbound queue is running
+	if sendAt != nil && !s.conf.OutboundQueue.Enabled {
+		return nil, false, NewResponseError(http.StatusFailedDependency, "", ErrQueueDisabled)
+	}
+
 	// Create a packet to begin the sending process
 	envelopeID := uuid.New()
 	if packet, err = postman.Send(envelopeID, payload, trisa.TransferStarted); err != nil {
-		c.Error(err)
-		c.JSON(http.StatusInternalServerError, api.Error("could not process send prepared transaction request"))
-		return nil, err
+		return nil, false, NewResponseError(http.StatusInternalServerError, "could not process send prepared transaction request", err)
 	}
 
 	// Add the log to the packet for debugging
-	ctx := c.Request.Context()
 	packet.Log = logger.Tracing(ctx).With().Str("envelope_id", envelopeID.String()).Logger()
 
 	// Lookup the counterparty from the travel address in the request
-	if packet.Counterparty, err = s.ResolveCounterparty(c, routing); err != nil {
-		// NOTE: CounterpartyFromTravelAddress handles API response back to user.
-		return nil, err
+	if packet.Counterparty, err = s.ResolveCounterparty(ctx, routing); err != nil {
+		return nil, false, err
 	}
 
 	// Create the transaction in the database
 	if packet.DB, err = s.store.PrepareTransaction(ctx, envelopeID, &models.ComplianceAuditLog{
 		ChangeNotes: sql.NullString{Valid: true, String: "Server.Send()"},
 	}); err != nil {
-		c.Error(err)
-		c.JSON(http.StatusInternalServerError, api.Error("could not process send prepared transaction request"))
-		return nil, err
+		return nil, false, NewResponseError(http.StatusInternalServerError, "could not process send prepared transaction request", err)
 	}
 	defer packet.DB.Rollback()
 
 	// Add the counterparty to the database associated with the transaction
 	// If the update fails, log the error but do not cancel processing.
 	if err = packet.Out.UpdateTransaction(); err != nil {
-		c.Error(err)
+		packet.Log.Warn().Err(err).Msg("could not update transaction with outgoing transfer")
+	}
+
+	// Record if travel rule data is required for the transfer by the threshold rules.
+	// If the evaluation fails, log the error but do not cancel processing.
+	if err = s.thresholds.Annotate(ctx, packet.DB, enum.SourceLocal, payload, packet.Counterparty); err != nil {
+		packet.Log.Warn().Err(err).Msg("could not annotate transaction with threshold rules")
 	}
 
-	// The protocol was already parsed in ResolveCounterparty
+	// If the transfer is scheduled to be sent later, add it to the outbound queue.
+	if sendAt != nil && sendAt.After(time.Now()) {
+		if err = s.enqueue(packet, payload, &models.QueuedTransfer{NextAttempt: *sendAt}); err != nil {
+			return nil, false, NewResponseError(http.StatusInternalServerError, "could not schedule transfer", err)
+		}
+		return packet, true, nil
+	}
+
+	// The protocol was already parsed in ResolveCounterparty; keep a reference to the
+	// packet since SendPacket does not return it if the send fails.
+	prepared := packet
 	protocol, _ := enum.ParseProtocol(routing.Protocol)
-	if packet, err = s.SendPacket(c, protocol, packet); err != nil {
-		c.Error(err)
-		if errors.Is(err, ErrUnavailable) {
-			c.JSON(http.StatusBadGateway, api.Error(err))
-			return nil, err
-		} else if errors.Is(err, ErrDisabled) {
-			c.JSON(http.StatusFailedDependency, api.Error(err))
-			return nil, err
+	if packet, err = s.SendPacket(ctx, protocol, packet); err != nil {
+		// If the counterparty is unreachable, queue the transfer to be retried later.
+		if errors.Is(err, ErrUnavailable) && protocol == enum.ProtocolTRISA && s.conf.OutboundQueue.Enabled {
+			prepared.Log.Warn().Err(err).Msg("counterparty unavailable, queueing transfer for retry")
+
+			now := time.Now()
+			transfer := &models.QueuedTransfer{
+				Attempts:    1,
+				LastError:   sql.NullString{Valid: true, String: err.Error()},
+				LastAttempt: sql.NullTime{Valid: true, Time: now},
+				NextAttempt: now.Add(s.conf.OutboundQueue.Backoff(1)),
+			}
+
+			if err = s.enqueue(prepared, payload, transfer); err != nil {
+				return nil, false, NewResponseError(http.StatusInternalServerError, "could not queue transfer for retry", err)
+			}
+			return prepared, true, nil
 		}
 
-		c.JSON(http.StatusInternalServerError, api.Error("could not send transfer message to counterparty"))
-		return nil, err
+		switch {
+		case errors.Is(err, ErrUnavailable):
+			return nil, false, NewResponseError(http.StatusBadGateway, "", err)
+		case errors.Is(err, ErrDisabled):
+			return nil, false, NewResponseError(http.StatusFailedDependency, "", err)
+		default:
+			return nil, false, NewResponseError(http.StatusInternalServerError, "could not send transfer message to counterparty", err)
+		}
 	}
 
 	// Update transaction state based on response from counterparty
 	// If the update fails rollback and return the error.
 	if err = packet.In.UpdateTransaction(); err != nil {
-		c.Error(err)
-		c.JSON(http.StatusInternalServerError, api.Error("could not process send prepared transaction request"))
-		return nil, err
+		return nil, false, NewResponseError(http.StatusInternalServerError, "could not process send prepared transaction request", err)
 	}
 
 	// Read the record from the database to return to the user
 	if err = packet.RefreshTransaction(); err != nil {
-		c.Error(err)
-		c.JSON(http.StatusInternalServerError, api.Error("could not process send prepared transaction request"))
-		return nil, err
+		return nil, false, NewResponseError(http.StatusInternalServerError, "could not process send prepared transaction request", err)
 	}
 
 	// Commit the transaction to the database
 	if err = packet.DB.Commit(); err != nil {
-		c.Error(err)
-		c.JSON(http.StatusInternalServerError, api.Error("could not process send prepared transaction request"))
-		return nil, err
+		return nil, false, NewResponseError(http.StatusInternalServerError, "could not process send prepared transaction request", err)
 	}
 
-	return packet, nil
+	return packet, false, nil
 }
 
 func (s *Server) SendPacket(ctx context.Context, protocol enum.Protocol, packet *postman.Packet) (_ *postman.Packet, err error) {
@@ -14,8 +14,10 @@ import (
 	"github.com/trisacrypto/envoy/pkg/store"
 	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
 	"github.com/trisacrypto/envoy/pkg/trisa/network"
 	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/oidc"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -30,12 +32,27 @@ type Server struct {
 	srv     *http.Server
 	router  *gin.Engine
 	issuer  *auth.ClaimsIssuer
+	oidc    *oidc.Provider
 	url     *url.URL
 	vasp    *models.Counterparty
 	trisa   network.Network
 	started time.Time
 	healthy bool
 	ready   bool
+	keystop chan struct{}
+	keydone chan struct{}
+
+	// Serializes sending queued transfers so a transfer cannot be sent twice
+	queue sync.Mutex
+
+	// Idempotency keys of requests that are currently being handled
+	idempotent sync.Map
+
+	// Batches that are currently being sent in the background
+	batches sync.Map
+
+	// Evaluates transfers against the travel rule threshold rules
+	thresholds *thresholds.Evaluator
 }
 
 // Serve the compliance and administrative user interfaces in its own go routine.
@@ -55,6 +72,20 @@ func (s *Server) Serve(errc chan<- error) (err error) {
 		return fmt.Errorf("could not listen on bind addr %s: %s", s.srv.Addr, err)
 	}
 
+	// Load the shared signing keys and rotate them in the background if enabled.
+	// NOTE: keys are synced before serving so that tokens are not signed by a key that
+	// other replicas have not published.
+	if s.conf.Web.Auth.KeyRotation > 0 {
+		if err = s.issuer.Sync(context.Background(), s.store); err != nil {
+			sock.Close()
+			return fmt.Errorf("could not sync claims issuer signing keys: %w", err)
+		}
+
+		s.keystop = make(chan struct{})
+		s.keydone = make(chan struct{})
+		go s.syncSigningKeys(s.keystop, s.keydone)
+	}
+
 	s.setURL(sock.Addr())
 	s.SetStatus(true, true)
 	s.started = time.Now()
@@ -100,6 +131,21 @@ func (s *Server) Shutdown() (err error) {
 		return err
 	}
 
+	// Stop the signing key rotation routine if it is running
+	if s.keystop != nil {
+		close(s.keystop)
+		<-s.keydone
+		s.keystop, s.keydone = nil, nil
+	}
+
+	// Stop sending batches; canceled batches can be resumed when the server restarts
+	s.batches.Range(func(_, val any) bool {
+		sender := val.(*batchSender)
+		sender.cancel()
+		<-sender.done
+		return true
+	})
+
 	return nil
 }
 
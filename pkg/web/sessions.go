@@ -0,0 +1,224 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/trisacrypto/envoy/pkg/logger"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"github.com/trisacrypto/envoy/pkg/web/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.rtnl.ai/ulid"
+)
+
+const (
+	sessionsTemplate = "partials/profile/sessions.html"
+	maxUserAgent     = 512
+)
+
+//===========================================================================
+// Profile Sessions
+//===========================================================================
+
+// ProfileSessions lists the active sessions of the logged in user so that the user can
+// revoke sessions that they do not recognize.
+func (s *Server) ProfileSessions(c *gin.Context) {
+	// Profile requests are only available for logged in users and therefore are UI
+	// only requests (Accept: text/html). JSON requests return a 406 error.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	s.renderProfileSessions(c, http.StatusOK, nil)
+}
+
+// RevokeProfileSession revokes one of the sessions of the logged in user; the session
+// cannot be used to refresh its access token but the access token remains valid until
+// it expires. If the user revokes their current session they are logged out.
+func (s *Server) RevokeProfileSession(c *gin.Context) {
+	var (
+		err       error
+		userID    ulid.ULID
+		sessionID ulid.ULID
+		claims    *auth.Claims
+	)
+
+	// Profile requests are only available for logged in users and therefore are UI
+	// only requests (Accept: text/html). JSON requests return a 406 error.
+	if IsAPIRequest(c) {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, api.Error("endpoint unavailable for API calls"))
+		return
+	}
+
+	if userID, err = s.retrieveUserID(c); err != nil {
+		c.HTML(http.StatusBadRequest, sessionsTemplate, gin.H{"Error": "could not revoke session"})
+		return
+	}
+
+	if sessionID, err = ulid.Parse(c.Param("sessionID")); err != nil {
+		s.renderProfileSessions(c, http.StatusNotFound, errors.New("session not found"))
+		return
+	}
+
+	if err = s.store.RevokeUserSession(c.Request.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			s.renderProfileSessions(c, http.StatusNotFound, errors.New("session not found"))
+			return
+		}
+
+		c.Error(err)
+		s.renderProfileSessions(c, http.StatusInternalServerError, errors.New("could not revoke session"))
+		return
+	}
+
+	// If the current session was revoked, log the user out.
+	if claims, err = auth.GetClaims(c); err == nil && claims.Session == sessionID.String() {
+		s.Logout(c)
+		return
+	}
+
+	s.renderProfileSessions(c, http.StatusOK, nil)
+}
+
+// Renders the sessions partial with the active sessions of the logged in user, marking
+// the session of the request as the current session.
+func (s *Server) renderProfileSessions(c *gin.Context, code int, msg error) {
+	var (
+		err      error
+		userID   ulid.ULID
+		claims   *auth.Claims
+		sessions []*models.UserSession
+	)
+
+	if userID, err = s.retrieveUserID(c); err != nil {
+		c.HTML(http.StatusBadRequest, sessionsTemplate, gin.H{"Error": "could not retrieve sessions"})
+		return
+	}
+
+	if sessions, err = s.store.ListUserSessions(c.Request.Context(), userID); err != nil {
+		c.Error(err)
+		c.HTML(http.StatusInternalServerError, sessionsTemplate, gin.H{"Error": "could not retrieve sessions"})
+		return
+	}
+
+	out := gin.H{"Sessions": sessions}
+	if claims, err = auth.GetClaims(c); err == nil {
+		out["Current"] = claims.Session
+	}
+
+	if msg != nil {
+		out["Error"] = msg.Error()
+	}
+
+	c.HTML(code, sessionsTemplate, out)
+}
+
+//===========================================================================
+// Session Helpers
+//===========================================================================
+
+// Creates a session for the user who has just logged in and sets the session on the
+// claims so that the tokens issued for the claims identify the session.
+func (s *Server) createSession(c *gin.Context, user *models.User, claims *auth.Claims) (err error) {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgent {
+		userAgent = userAgent[:maxUserAgent]
+	}
+
+	session := &models.UserSession{
+		UserID:    user.ID,
+		UserAgent: sql.NullString{Valid: userAgent != "", String: userAgent},
+		IPAddress: sql.NullString{Valid: c.ClientIP() != "", String: c.ClientIP()},
+		SSO:       claims.SSO,
+		Expires:   time.Now().Add(s.conf.Web.Auth.RefreshTokenTTL),
+	}
+
+	if err = s.store.CreateUserSession(c.Request.Context(), session); err != nil {
+		return err
+	}
+
+	claims.Session = session.ID.String()
+	return nil
+}
+
+// Verifies that the session of the refresh token has not been revoked or expired and
+// extends the session for the new tokens issued for the claims. Returns
+// auth.ErrSessionRevoked if the refresh token cannot be used.
+func (s *Server) refreshSession(ctx context.Context, userID ulid.ULID, refresh, claims *auth.Claims) (err error) {
+	var sessionID ulid.ULID
+	if sessionID, err = ulid.Parse(refresh.Session); err != nil {
+		return auth.ErrSessionRevoked
+	}
+
+	if err = s.store.RefreshUserSession(ctx, userID, sessionID, time.Now().Add(s.conf.Web.Auth.RefreshTokenTTL)); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			return auth.ErrSessionRevoked
+		}
+		return err
+	}
+
+	claims.Session = refresh.Session
+	return nil
+}
+
+// Revokes the sessions of the user after their password is changed, keeping the
+// specified session (if not ulid.Null) so the user is not logged out. Errors are logged
+// rather than returned since the password has already been changed.
+func (s *Server) revokeSessions(ctx context.Context, userID, keep ulid.ULID) {
+	if err := s.store.RevokeUserSessions(ctx, userID, keep); err != nil {
+		log := logger.Tracing(ctx)
+		log.Warn().Err(err).Str("user_id", userID.String()).Msg("could not revoke user sessions after password change")
+	}
+}
+
+// Revokes the session of the logged in user when they log out. The session is read from
+// the claims of the request if it was authenticated, otherwise from the access or
+// refresh token cookies, verifying their signatures but allowing expired tokens. Errors
+// are logged rather than returned since the user is logged out regardless.
+func (s *Server) revokeCurrentSession(c *gin.Context) {
+	var (
+		err       error
+		claims    *auth.Claims
+		userID    ulid.ULID
+		sessionID ulid.ULID
+		subject   auth.SubjectType
+	)
+
+	if claims, err = auth.GetClaims(c); err != nil {
+		for _, token := range []func(*gin.Context) (string, error){auth.GetAccessToken, auth.GetRefreshToken} {
+			var tks string
+			if tks, err = token(c); err != nil {
+				continue
+			}
+
+			if claims, err = s.issuer.Parse(tks); err == nil && claims.Session != "" {
+				break
+			}
+		}
+	}
+
+	// Tokens without a session (e.g. issued before sessions were tracked) have nothing to revoke
+	if claims == nil || claims.Session == "" {
+		return
+	}
+
+	if subject, userID, err = claims.SubjectID(); err != nil || subject != auth.SubjectUser {
+		return
+	}
+
+	if sessionID, err = ulid.Parse(claims.Session); err != nil {
+		return
+	}
+
+	if err = s.store.RevokeUserSession(c.Request.Context(), userID, sessionID); err != nil && !errors.Is(err, dberr.ErrNotFound) {
+		log := logger.Tracing(c.Request.Context())
+		log.Warn().Err(err).Str("user_id", userID.String()).Msg("could not revoke user session on logout")
+	}
+}
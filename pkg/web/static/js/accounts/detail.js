@@ -53,6 +53,9 @@ document.body.addEventListener("crypto-addresses-updated", function(e) {
 Handle any htmx errors that are not swapped by the htmx config.
 */
 document.body.addEventListener("htmx:responseError", function(e) {
+  // Legal hold errors are handled by the legal holds module
+  if (isRequestMatch(e, "^/v1/legalholds", "post")) return;
+
   // Handle errors for create API key modal
   if (isRequestMatch(e, "/v1/accounts/[0-7][0-9A-HJKMNP-TV-Z]{25}/crypto-addresses", "post") || isRequestMatch(e, "/v1/accounts/[0-7][0-9A-HJKMNP-TV-Z]{25}/crypto-addresses/[0-7][0-9A-HJKMNP-TV-Z]{25}", "put")) {
     const error = JSON.parse(e.detail.xhr.response);
@@ -38,6 +38,23 @@ function resetPermissionsCheckboxes() {
   toggleFullPermissions(false);
 }
 
+/*
+Adds the optional expiration and allowed networks restrictions from the form data to
+the request parameters. The expiration date is sent as the end of the selected day in
+the user's timezone and networks may be separated by commas, spaces, or newlines.
+*/
+function appendRestrictions(source, params) {
+  const expires = source.get("expires");
+  if (expires) {
+    params.append("expires", new Date(expires + "T23:59:59").toISOString());
+  }
+
+  const networks = (source.get("allowed_networks") || "").split(/[\s,]+/).filter(network => network);
+  if (networks.length > 0) {
+    params.append("json:allowed_networks", JSON.stringify(networks));
+  }
+}
+
 /*
 Pre-flight request configuration for htmx requests.
 */
@@ -58,8 +75,24 @@ document.body.addEventListener("htmx:configRequest", function(e) {
     for (const permission of permissions) {
       params.append("permissions", permission);
     }
+    appendRestrictions(e.detail.parameters, params);
+
+    e.detail.parameters = params;
+    return;
+  }
+
+  /*
+  When updating an API key, the expiration date and networks must be converted into the
+  formats expected by the backend.
+  */
+  if (isRequestMatch(e, "/v1/apikeys/[0-7][0-9A-HJKMNP-TV-Z]{25}", "put")) {
+    const params = new FormData();
+    params.append("id", e.detail.parameters.get("id"));
+    params.append("description", e.detail.parameters.get("description"));
+    appendRestrictions(e.detail.parameters, params);
 
     e.detail.parameters = params;
+    return;
   }
 });
 
@@ -102,6 +135,21 @@ document.body.addEventListener("htmx:afterSettle", function(e) {
     return;
   }
 
+  /*
+  After rotating an apikey secret, close the detail modal and display the new secret in
+  the apikey created modal generated by the htmx request.
+  */
+  if (isRequestMatch(e, /^\/v1\/apikeys\/[0-7][0-9A-HJKMNP-TV-Z]{25}\/rotate$/gm, "post")) {
+    const apiKeyDetailModal = Modal.getInstance(document.getElementById("apiKeyDetailModal"));
+    apiKeyDetailModal.hide();
+
+    activateCopyButtons();
+
+    const apiKeyCreatedModal = new Modal("#apiKeyCreatedModal", {});
+    apiKeyCreatedModal.show();
+    return;
+  }
+
   // After fetching the preview form, display the apikeyEditModal.
   if (isRequestMatch(e, /^\/v1\/apikeys\/[0-7][0-9A-HJKMNP-TV-Z]{25}\/edit$/gm, "get")) {
     const apiKeyEditModal = new Modal("#apiKeyEditModal", {});
@@ -166,6 +214,14 @@ document.body.addEventListener("htmx:responseError", function(e) {
     return;
   }
 
+  // Handle errors for rotating the API key secret from the detail modal
+  if (isRequestMatch(e, "/v1/apikeys/[0-7][0-9A-HJKMNP-TV-Z]{25}/rotate", "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    const rotateAPIKeyAlerts = new Alerts("#rotateAPIKeyAlerts");
+    rotateAPIKeyAlerts.danger("Error:", error.error);
+    return;
+  }
+
   // Handle errors for revoke API key modal
   if (isRequestMatch(e, "/v1/apikeys/[0-7][0-9A-HJKMNP-TV-Z]{25}", "delete")) {
     window.location.href = '/error';
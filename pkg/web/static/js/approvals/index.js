@@ -0,0 +1,91 @@
+/*
+Application code for the transfer approvals dashboard page.
+*/
+
+import { isRequestFor, isRequestMatch } from '../htmx/helpers.js';
+import Alerts from '../modules/alerts.js';
+
+
+// Create alert managers for the create approval rule modal
+const createApprovalRuleAlerts = new Alerts("#createApprovalRuleAlerts");
+
+// Matches requests to approve, deny, or cancel an approval.
+const reviewPath = "^/v1/approvals/[0-7][0-9A-HJKMNP-TV-Z]{25}/(approve|deny|cancel)$";
+
+/*
+When the create approval rule modal is hidden, reset the form and clear any alerts so
+that the modal is ready to create another rule.
+*/
+const createApprovalRuleModal = document.getElementById("createApprovalRuleModal");
+if (createApprovalRuleModal) {
+  createApprovalRuleModal.addEventListener("hidden.bs.modal", function() {
+    createApprovalRuleModal.querySelector("#createApprovalRuleForm").reset();
+    createApprovalRuleModal.querySelector("#createApprovalRuleAlerts").innerHTML = "";
+  });
+}
+
+/*
+Pre-flight request configuration for htmx requests.
+*/
+document.body.addEventListener("htmx:configRequest", function(e) {
+  /*
+  When creating an approval rule, blank criteria must be omitted so that they match any
+  value and the minimum amount must be sent as a number rather than a string.
+  */
+  if (isRequestFor(e, "/v1/approvals/rules", "post")) {
+    const params = new FormData();
+    for (const [key, value] of e.detail.parameters.entries()) {
+      if (value === "") {
+        continue;
+      }
+
+      if (key === "min_amount") {
+        params.append("json:min_amount", value);
+        continue;
+      }
+
+      params.append(key, value);
+    }
+
+    e.detail.parameters = params;
+    return;
+  }
+});
+
+/*
+Post-event handling when the approvals-updated event is fired.
+*/
+document.body.addEventListener("approvals-updated", function(e) {
+  const elt = e.detail?.elt;
+  if (elt && elt.id === 'createApprovalRuleForm') {
+    const modal = Modal.getInstance(createApprovalRuleModal);
+    modal.hide();
+  }
+});
+
+/*
+Handle any htmx errors from approval requests that are not swapped by the htmx config.
+*/
+document.body.addEventListener("htmx:responseError", function(e) {
+  if (isRequestFor(e, "/v1/approvals/rules", "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    switch (e.detail.xhr.status) {
+      case 400:
+        createApprovalRuleAlerts.danger("Error:", error.error);
+        break;
+      case 422:
+        createApprovalRuleAlerts.danger("Validation error:", error.error);
+        break;
+      default:
+        createApprovalRuleAlerts.danger("Could not create approval rule:", error.error);
+        break;
+    }
+    return;
+  }
+
+  if (isRequestMatch(e, reviewPath, "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    alert(`Could not review approval: ${error.error}`);
+    return;
+  }
+});
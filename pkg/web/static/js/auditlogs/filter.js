@@ -27,6 +27,7 @@ class Filters {
     // Initialize the actor types and resource types choices
     this.actorTypes = createChoices(this.form.querySelector('[name="actorTypes"]'));
     this.resourceTypes = createChoices(this.form.querySelector('[name="resourceTypes"]'));
+    this.actions = createChoices(this.form.querySelector('[name="actions"]'));
 
     // Initialize the before and after datetime pickers
     this.beforePicker = this.form.querySelector('[name="before"]');
@@ -49,6 +50,11 @@ class Filters {
       nFilters++;
     });
 
+    query.getAll('actions').forEach(action => {
+      this.actions.setChoiceByValue(action);
+      nFilters++;
+    });
+
     const before = query.get('before')
     if (before) {
       this.beforePicker._flatpickr.setDate(before)
@@ -81,13 +87,14 @@ class Filters {
     const formData = new FormData(this.form);
     const actorTypes = formData.getAll("actorTypes");
     const resourceTypes = formData.getAll("resourceTypes");
+    const actions = formData.getAll("actions");
     const before = formData.get("before")
     const after = formData.get("after")
     const actorId = formData.get("actorId")
     const resourceId = formData.get("resourceId")
 
-    this.updateFilterBadge(actorTypes.length + resourceTypes.length + (before ? 1 : 0) + (after ? 1 : 0) + (actorId ? 1 : 0) + (resourceId ? 1 : 0));
-    this.filterList(actorTypes, resourceTypes, before, after, actorId, resourceId);
+    this.updateFilterBadge(actorTypes.length + resourceTypes.length + actions.length + (before ? 1 : 0) + (after ? 1 : 0) + (actorId ? 1 : 0) + (resourceId ? 1 : 0));
+    this.filterList(actorTypes, resourceTypes, actions, before, after, actorId, resourceId);
     this.hideFilterDropdown()
 
     return false;
@@ -95,18 +102,20 @@ class Filters {
 
   onReset(e) {
     this.updateFilterBadge(0);
-    this.filterList(null, null);
+    this.filterList(null, null, null);
     this.hideFilterDropdown()
   }
 
-  filterList(actorTypes, resourceTypes, before, after, actorId, resourceId) {
+  filterList(actorTypes, resourceTypes, actions, before, after, actorId, resourceId) {
     const url = this.list.getAttribute('hx-get');
     const path = urlPath(url);
     const query = urlQuery(url);
 
-    // Remove existing filters
+    // Remove existing filters and start again from the newest logs
+    query.delete('next_page_token');
     query.delete('actor_types');
     query.delete('resource_types');
+    query.delete('actions');
     query.delete('before');
     query.delete('after');
     query.delete('actor_id');
@@ -121,6 +130,10 @@ class Filters {
       resourceTypes.forEach(resourceT => query.append('resource_types', resourceT));
     }
 
+    if (actions) {
+      actions.forEach(action => query.append('actions', action));
+    }
+
     if (before) {
       query.append('before', before);
     }
@@ -144,6 +157,7 @@ class Filters {
       detail: {
         actorTypes: actorTypes,
         resourceTypes: resourceTypes,
+        actions: actions,
         before: before,
         after: after
       },
@@ -1,15 +1,42 @@
-import { createList, createPageSizeSelect } from '../modules/components.js';
+import { createList } from '../modules/components.js';
+import { urlPath, urlQuery } from '../htmx/helpers.js';
 import Filter from './filter.js';
 
+/*
+Reloads the audit log list starting after the specified page token (or from the
+newest logs if no token is specified) without modifying the current filters.
+*/
+function loadPage(token) {
+  const list = document.getElementById('auditlogs');
+  const url = list.getAttribute('hx-get');
+  const query = urlQuery(url);
+
+  query.delete('next_page_token');
+  if (token) {
+    query.append('next_page_token', token);
+  }
+
+  list.setAttribute('hx-get', `${urlPath(url)}?${query.toString()}`);
+  htmx.process(list);
+  list.dispatchEvent(new CustomEvent('list-filter', { bubbles: true, cancelable: true }));
+}
+
 document.addEventListener("htmx:afterSettle", function (e) {
   const logList = document.getElementById('complianceAuditLogList');
   if (logList) {
-    // Initialize List.js
-    const list = createList(logList);
+    // Initialize List.js (pagination is handled server-side with page tokens)
+    createList(logList);
+  }
+
+  // Initialize server-side pagination
+  const olderLogsButton = document.getElementById('olderLogsButton');
+  if (olderLogsButton) {
+    olderLogsButton.addEventListener('click', () => loadPage(olderLogsButton.dataset.nextPageToken));
+  }
 
-    // Initialize Page Size Select
-    const pageSizeSelect = document.getElementById('pageSizeSelect');
-    createPageSizeSelect(pageSizeSelect, list);
+  const newestLogsButton = document.getElementById('newestLogsButton');
+  if (newestLogsButton) {
+    newestLogsButton.addEventListener('click', () => loadPage(null));
   }
 
   // Initialize filters
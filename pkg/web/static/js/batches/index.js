@@ -0,0 +1,26 @@
+/*
+Application code for the batch transfers dashboard and detail pages.
+*/
+
+import { isRequestMatch } from '../htmx/helpers.js';
+
+// Matches requests to upload, send, or cancel a batch.
+const uploadPath = "^/v1/batches$";
+const actionPath = "^/v1/batches/[0-7][0-9A-HJKMNP-TV-Z]{25}/(send|cancel)$";
+
+/*
+Handle any htmx errors from batch requests that are not swapped by the htmx config.
+*/
+document.body.addEventListener("htmx:responseError", function(e) {
+  if (isRequestMatch(e, uploadPath, "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    alert(`Could not import batch: ${error.error}`);
+    return;
+  }
+
+  if (isRequestMatch(e, actionPath, "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    alert(`Could not update batch: ${error.error}`);
+    return;
+  }
+});
@@ -0,0 +1,34 @@
+/*
+Application code for the counterparty detail page.
+*/
+
+import { isRequestMatch } from '../htmx/helpers.js';
+import Alerts from '../modules/alerts.js';
+
+
+/*
+Handle any htmx errors from the pending response form that are not swapped by the
+htmx config. The alerts are created when the error occurs since the form is reloaded
+whenever the counterparty is updated.
+*/
+document.body.addEventListener("htmx:responseError", function(e) {
+  if (isRequestMatch(e, /\/v1\/counterparties\/[0-7][0-9A-HJKMNP-TV-Z]{25}\/pending/, "put")) {
+    const alerts = new Alerts("#pendingAlerts");
+    const error = JSON.parse(e.detail.xhr.response);
+    switch (e.detail.xhr.status) {
+      case 400:
+        alerts.warning("Bad request", error.error);
+        break;
+      case 404:
+        alerts.danger("Not found", error.error);
+        break;
+      case 422:
+        alerts.warning("Validation error", error.error);
+        break;
+      default:
+        alerts.danger("Error", error.error);
+        break;
+    }
+    return;
+  }
+});
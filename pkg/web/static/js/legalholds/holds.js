@@ -0,0 +1,63 @@
+/*
+Application code for the legal holds card on the transaction, account, and
+counterparty detail pages.
+*/
+
+import { isRequestFor, isRequestMatch } from '../htmx/helpers.js';
+import Alerts from '../modules/alerts.js';
+
+
+// Create alert managers for the apply legal hold modal
+const applyLegalHoldAlerts = new Alerts("#applyLegalHoldAlerts");
+
+/*
+When the apply legal hold modal is hidden, reset the form and clear any alerts so that
+the modal is ready to apply another hold.
+*/
+const applyLegalHoldModal = document.getElementById("applyLegalHoldModal");
+if (applyLegalHoldModal) {
+  applyLegalHoldModal.addEventListener("hidden.bs.modal", function() {
+    applyLegalHoldModal.querySelector("#applyLegalHoldForm").reset();
+    applyLegalHoldModal.querySelector("#applyLegalHoldAlerts").innerHTML = "";
+  });
+}
+
+/*
+Post-event handling when the legalholds-updated event is fired.
+*/
+document.body.addEventListener("legalholds-updated", function(e) {
+  const elt = e.detail?.elt;
+  if (elt && elt.id === 'applyLegalHoldForm') {
+    const modal = Modal.getInstance(applyLegalHoldModal);
+    modal.hide();
+  }
+});
+
+/*
+Handle any htmx errors from legal hold requests that are not swapped by the htmx config.
+*/
+document.body.addEventListener("htmx:responseError", function(e) {
+  if (isRequestFor(e, "/v1/legalholds", "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    switch (e.detail.xhr.status) {
+      case 400:
+        applyLegalHoldAlerts.danger("Error:", error.error);
+        break;
+      case 404:
+        applyLegalHoldAlerts.danger("Not found:", error.error);
+        break;
+      case 422:
+        applyLegalHoldAlerts.danger("Validation error:", error.error);
+        break;
+      default:
+        break;
+    }
+    return;
+  }
+
+  if (isRequestMatch(e, "^/v1/legalholds/[0-7][0-9A-HJKMNP-TV-Z]{25}/release$", "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    alert(`Could not release legal hold: ${error.error}`);
+    return;
+  }
+});
@@ -0,0 +1,19 @@
+/*
+Application code for the queued transfers dashboard page.
+*/
+
+import { isRequestMatch } from '../htmx/helpers.js';
+
+// Matches requests to cancel or retry a queued transfer.
+const actionPath = "^/v1/queue/[0-7][0-9A-HJKMNP-TV-Z]{25}/(cancel|retry)$";
+
+/*
+Handle any htmx errors from queue requests that are not swapped by the htmx config.
+*/
+document.body.addEventListener("htmx:responseError", function(e) {
+  if (isRequestMatch(e, actionPath, "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    alert(`Could not update queued transfer: ${error.error}`);
+    return;
+  }
+});
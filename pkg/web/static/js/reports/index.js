@@ -0,0 +1,99 @@
+/*
+Application code for the travel rule reports dashboard page.
+*/
+
+import { isRequestFor, isRequestMatch } from '../htmx/helpers.js';
+import Alerts from '../modules/alerts.js';
+
+
+// Create alert managers for the generate report modal
+const createReportAlerts = new Alerts("#createReportAlerts");
+
+// Matches requests to delete a report.
+const reportPath = "^/v1/reports/[0-7][0-9A-HJKMNP-TV-Z]{25}$";
+
+/*
+When the generate report modal is hidden, reset the form and clear any alerts so that
+the modal is ready to generate another report.
+*/
+const createReportModal = document.getElementById("createReportModal");
+if (createReportModal) {
+  createReportModal.addEventListener("hidden.bs.modal", function() {
+    createReportModal.querySelector("#createReportForm").reset();
+    createReportModal.querySelector("#createReportAlerts").innerHTML = "";
+  });
+}
+
+/*
+Pre-flight request configuration for htmx requests.
+*/
+document.body.addEventListener("htmx:configRequest", function(e) {
+  /*
+  When generating a report, the first and last days of the period are converted into
+  UTC timestamps; the end of the period is exclusive so it is midnight after the last
+  day. The sections are sent as a JSON array so that a single section is not sent as a
+  string by the json-enc extension.
+  */
+  if (isRequestFor(e, "/v1/reports", "post")) {
+    const params = new FormData();
+    params.append("name", e.detail.parameters.get("name"));
+
+    const start = e.detail.parameters.get("period_start");
+    if (start) {
+      params.append("period_start", new Date(`${start}T00:00:00Z`).toISOString());
+    }
+
+    const end = e.detail.parameters.get("period_end");
+    if (end) {
+      const periodEnd = new Date(`${end}T00:00:00Z`);
+      periodEnd.setUTCDate(periodEnd.getUTCDate() + 1);
+      params.append("period_end", periodEnd.toISOString());
+    }
+
+    const sections = Array.from(document.getElementsByName("sections"))
+      .filter(section => section.checked)
+      .map(section => section.value);
+    params.append("json:sections", JSON.stringify(sections));
+
+    e.detail.parameters = params;
+    return;
+  }
+});
+
+/*
+Post-event handling when the reports-updated event is fired.
+*/
+document.body.addEventListener("reports-updated", function(e) {
+  const elt = e.detail?.elt;
+  if (elt && elt.id === 'createReportForm') {
+    const modal = Modal.getInstance(createReportModal);
+    modal.hide();
+  }
+});
+
+/*
+Handle any htmx errors from report requests that are not swapped by the htmx config.
+*/
+document.body.addEventListener("htmx:responseError", function(e) {
+  if (isRequestFor(e, "/v1/reports", "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    switch (e.detail.xhr.status) {
+      case 400:
+        createReportAlerts.danger("Error:", error.error);
+        break;
+      case 422:
+        createReportAlerts.danger("Validation error:", error.error);
+        break;
+      default:
+        createReportAlerts.danger("Could not generate report:", error.error);
+        break;
+    }
+    return;
+  }
+
+  if (isRequestMatch(e, reportPath, "delete")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    alert(`Could not delete report: ${error.error}`);
+    return;
+  }
+});
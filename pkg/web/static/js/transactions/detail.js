@@ -12,6 +12,9 @@ import Alerts from '../modules/alerts.js';
 // Initialize the alerts component.
 var rejectAlerts;
 var completeAlerts;
+var tagAlerts;
+const assignAlerts = new Alerts("#assignAlerts");
+const noteAlerts = new Alerts("#noteAlerts");
 
 /*
 Pre-flight request configuration for htmx requests.
@@ -53,6 +56,20 @@ document.body.addEventListener("htmx:configRequest", function(e) {
     return;
   }
 
+  /*
+  Tags are entered as a comma separated list and need to be sent as an array.
+  */
+  if (isRequestMatch(e, /\/v1\/transactions\/[A-Fa-f0-9-]{36}\/tags/, "put")) {
+    const tags = (e.detail.parameters.get("tags") || "")
+      .split(",")
+      .map(tag => tag.trim())
+      .filter(tag => tag.length > 0);
+
+    e.detail.parameters = new FormData();
+    e.detail.parameters.append("json:tags", JSON.stringify(tags));
+    return;
+  }
+
 });
 
 
@@ -73,6 +90,7 @@ document.addEventListener("htmx:afterSettle", function(e) {
       // Initialize the alerts components
       rejectAlerts = new Alerts("#rejectAlerts");
       completeAlerts = new Alerts("#completeAlerts");
+      tagAlerts = new Alerts("#tagAlerts");
       return;
     }
 
@@ -93,6 +111,28 @@ document.body.addEventListener("transactions-updated", function(e) {
       const modal = Modal.getInstance(document.getElementById("rejectTransferModal"));
       modal.hide();
     }
+
+    if (elt.id === 'assignForm') {
+      const modal = Modal.getInstance(document.getElementById("assignTransferModal"));
+      modal.hide();
+      document.getElementById("assignAlerts").innerHTML = "";
+    }
+
+    if (elt.id === 'tagForm') {
+      const modal = Modal.getInstance(document.getElementById("tagTransferModal"));
+      modal.hide();
+    }
+  }
+});
+
+/*
+Reset the note form once the note has been posted to the case notes thread.
+*/
+document.body.addEventListener("transaction-notes-updated", function(e) {
+  const elt = e.detail?.elt;
+  if (elt && elt.id === 'noteForm') {
+    elt.reset();
+    document.getElementById("noteAlerts").innerHTML = "";
   }
 });
 
@@ -100,6 +140,9 @@ document.body.addEventListener("transactions-updated", function(e) {
 Handle any htmx errors that are not swapped by the htmx config.
 */
 document.body.addEventListener("htmx:responseError", function(e) {
+  // Legal hold errors are handled by the legal holds module
+  if (isRequestMatch(e, "^/v1/legalholds", "post")) return;
+
   // Try to parse the error response.
   var error;
   try {
@@ -161,5 +204,33 @@ document.body.addEventListener("htmx:responseError", function(e) {
     return;
   }
 
+  /*
+  Handle case management errors by displaying them in the form that made the request.
+  */
+  const caseAlerts = [
+    [/\/v1\/transactions\/[A-Fa-f0-9-]{36}\/notes/, "post", noteAlerts],
+    [/\/v1\/transactions\/[A-Fa-f0-9-]{36}\/assignee/, "put", assignAlerts],
+    [/\/v1\/transactions\/[A-Fa-f0-9-]{36}\/tags/, "put", tagAlerts],
+  ];
+
+  for (const [pattern, method, alerts] of caseAlerts) {
+    if (isRequestMatch(e, pattern, method)) {
+      switch (error.statusCode) {
+        case 400:
+          alerts.warning("Bad request", error.error);
+          break;
+        case 404:
+          alerts.danger("Not found", error.error);
+          break;
+        case 422:
+          alerts.warning("Validation error", error.error);
+          break;
+        default:
+          throw new Error("Unhandled case management error code: " + error.statusCode + " - " + error.error);
+      }
+      return;
+    }
+  }
+
   throw new Error("Unhandled error code: " + error.statusCode + " - " + error.error);
 });
\ No newline at end of file
@@ -40,6 +40,12 @@ class Filters {
       nFilters++;
     });
 
+    this.overdue = this.form.querySelector('[name="overdue"]');
+    if (query.get('overdue') === 'true') {
+      this.overdue.checked = true;
+      nFilters++;
+    }
+
     this.updateFilterBadge(nFilters);
   }
 
@@ -48,18 +54,19 @@ class Filters {
     const formData = new FormData(this.form);
     const status = formData.getAll("status");
     const asset = formData.getAll("asset");
+    const overdue = formData.get("overdue") === "true";
 
-    this.updateFilterBadge(status.length + asset.length);
-    this.filterList(status, asset);
+    this.updateFilterBadge(status.length + asset.length + (overdue ? 1 : 0));
+    this.filterList(status, asset, overdue);
     return false;
   }
 
   onReset(e) {
     this.updateFilterBadge(0);
-    this.filterList(null, null);
+    this.filterList(null, null, false);
   }
 
-  filterList(status, asset) {
+  filterList(status, asset, overdue) {
     const url = this.list.getAttribute('hx-get');
     const path = urlPath(url);
     const query = urlQuery(url);
@@ -67,6 +74,7 @@ class Filters {
     // Remove existing filters
     query.delete('status');
     query.delete('asset');
+    query.delete('overdue');
 
     // Add the specified filters
     if (status) {
@@ -77,10 +85,14 @@ class Filters {
       asset.forEach(asset => query.append('asset', asset));
     }
 
+    if (overdue) {
+      query.append('overdue', 'true');
+    }
+
     this.list.setAttribute('hx-get', `${path}?${query.toString()}`);
     htmx.process(this.list);
 
-    this.list.dispatchEvent(new CustomEvent('list-filter', {detail: {status: status, asset: asset}, bubbles: true, cancelable: true}));
+    this.list.dispatchEvent(new CustomEvent('list-filter', {detail: {status: status, asset: asset, overdue: overdue}, bubbles: true, cancelable: true}));
   }
 
   updateFilterBadge(count) {
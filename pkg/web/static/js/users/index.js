@@ -4,6 +4,48 @@ Application code for the user management dashboard page.
 
 import { createList, createPageSizeSelect, activateCopyButtons } from '../modules/components.js';
 import { isRequestFor, isRequestMatch } from '../htmx/helpers.js';
+import Alerts from '../modules/alerts.js';
+
+
+// Add the alerts manager for the role forms on the page.
+const createRoleAlerts = new Alerts("#createRoleAlerts");
+const roleAlerts = new Alerts("#roleAlerts");
+
+// Matches requests for a specific role (but not the role mfa policy).
+const rolePath = /^\/v1\/roles\/[^\/]+$/;
+const roleEditPath = /^\/v1\/roles\/[^\/]+\/edit$/;
+
+// Matches requests to resend or revoke a user invite.
+const invitePath = /^\/v1\/invites\/[^\/]+(\/resend)?$/;
+
+
+/*
+Pre-flight request configuration for htmx requests.
+*/
+document.body.addEventListener("htmx:configRequest", function(e) {
+  /*
+  When creating or updating a role, the permissions from the checkboxes in the form must
+  be gathered and stored as an array of strings in the FormData object. The custom
+  json-enc extension can then turn this into an array of strings as required by the
+  backend. This is required because htmx v2 does not support multiple parameters with
+  the same name.
+  */
+  if (isRequestFor(e, "/v1/roles", "post") || isRequestMatch(e, rolePath, "put")) {
+    const form = e.detail.elt;
+    const permissions = Array.from(form.querySelectorAll('input[name="permissions"]'))
+      .filter(permission => permission.checked)
+      .map(permission => permission.value);
+
+    const params = new FormData();
+    params.append("title", e.detail.parameters.get("title"));
+    params.append("description", e.detail.parameters.get("description"));
+    for (const permission of permissions) {
+      params.append("permissions", permission);
+    }
+
+    e.detail.parameters = params;
+  }
+});
 
 
 /*
@@ -44,6 +86,39 @@ document.addEventListener("htmx:afterSettle", function(e) {
     return;
   }
 
+  // After fetching the role preview form, display the roleEditModal.
+  if (isRequestMatch(e, roleEditPath, "get")) {
+    const roleEditModal = new Modal("#roleEditModal", {});
+    roleEditModal.show();
+    return;
+  }
+});
+
+/*
+Post-event handling when the roles-updated event is fired.
+*/
+document.body.addEventListener("roles-updated", function(e) {
+  const createRoleModal = Modal.getInstance(document.getElementById("createRoleModal"));
+  if (createRoleModal) {
+    document.getElementById("createRoleForm").reset();
+    createRoleModal.hide();
+  }
+
+  const roleEditModal = Modal.getInstance(document.getElementById("roleEditModal"));
+  if (roleEditModal) {
+    roleEditModal.hide();
+  }
+});
+
+/*
+Post-event handling when the invites-updated event is fired.
+*/
+document.body.addEventListener("invites-updated", function(e) {
+  const inviteUserModal = Modal.getInstance(document.getElementById("inviteUserModal"));
+  if (inviteUserModal) {
+    document.getElementById("inviteUserForm").reset();
+    inviteUserModal.hide();
+  }
 });
 
 /*
@@ -79,10 +154,112 @@ document.body.addEventListener("htmx:responseError", function(e) {
     return;
   }
 
+  // Handle errors for the invite user modal
+  if (isRequestFor(e, "/v1/invites", "post")) {
+    const inviteUserAlerts = new Alerts("#inviteUserAlerts");
+    const error = JSON.parse(e.detail.xhr.response);
+    switch (e.detail.xhr.status) {
+      case 422:
+        inviteUserAlerts.danger("Validation error:", error.error);
+        break;
+      default:
+        inviteUserAlerts.danger("Error:", error.error);
+        break;
+    }
+    return;
+  }
+
+  // Handle errors for resending or revoking invites
+  if (isRequestMatch(e, invitePath, "post") || isRequestMatch(e, invitePath, "delete")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    roleAlerts.danger("Invite error:", error.error);
+    return;
+  }
+
+  // Handle errors for the create role modal
+  if (isRequestFor(e, "/v1/roles", "post")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    switch (e.detail.xhr.status) {
+      case 422:
+        createRoleAlerts.danger("Validation error:", error.error);
+        break;
+      default:
+        createRoleAlerts.danger("Error:", error.error);
+        break;
+    }
+    return;
+  }
+
+  // Handle errors for the edit role modal
+  if (isRequestMatch(e, rolePath, "put")) {
+    const editRoleAlerts = new Alerts("#editRoleAlerts");
+    const error = JSON.parse(e.detail.xhr.response);
+    switch (e.detail.xhr.status) {
+      case 422:
+        editRoleAlerts.danger("Validation error:", error.error);
+        break;
+      default:
+        editRoleAlerts.danger("Error:", error.error);
+        break;
+    }
+    return;
+  }
+
+  // Handle errors for deleting a role (e.g. if the role is assigned to users)
+  if (isRequestMatch(e, rolePath, "delete")) {
+    const error = JSON.parse(e.detail.xhr.response);
+    roleAlerts.danger("Could not delete role:", error.error);
+    return;
+  }
+
+  // Handle errors for the role edit preview
+  if (isRequestMatch(e, roleEditPath, "get")) {
+    if (e.detail.xhr.status === 404) {
+      window.location.href = '/not-found';
+    } else {
+      window.location.href = '/error';
+    }
+    return;
+  }
+
   // If the error is unhandled; throw it
   throw new Error(`unhandled htmx error: status ${e.detail.xhr.status}`);
 });
 
+/*
+Ensure the create role form is fully reset including removing any alerts that may have
+been added from errors.
+*/
+const createRoleForm = document.getElementById('createRoleForm');
+if (createRoleForm) {
+  createRoleForm.addEventListener('reset', function() {
+    const alerts = document.getElementById('createRoleAlerts');
+    alerts.querySelector('.alert')?.remove();
+  });
+}
+
+/*
+Ensure the invite user form is fully reset including removing any alerts that may have
+been added from errors.
+*/
+const inviteUserForm = document.getElementById('inviteUserForm');
+if (inviteUserForm) {
+  inviteUserForm.addEventListener('reset', function() {
+    const alerts = document.getElementById('inviteUserAlerts');
+    alerts.querySelector('.alert')?.remove();
+  });
+}
+
+/*
+When the role edit modal is closed, remove the preview form so that it is fetched again.
+*/
+const roleEditModal = document.getElementById('roleEditModal');
+if (roleEditModal) {
+  roleEditModal.addEventListener('hidden.bs.modal', function() {
+    roleEditModal.innerHTML = '';
+  });
+}
+
 /*
 When the user created confirmation modal is closed, remove all of the HTML from it.
 This has the effect of removing the user's password from the DOM, which feels like a
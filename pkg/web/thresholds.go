@@ -0,0 +1,207 @@
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.rtnl.ai/ulid"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+)
+
+//===========================================================================
+// Travel Rule Threshold Rules
+//===========================================================================
+
+func (s *Server) ListThresholdRules(c *gin.Context) {
+	var (
+		err   error
+		rules []*models.ThresholdRule
+		out   *api.ThresholdRuleList
+	)
+
+	if rules, err = s.store.ListThresholdRules(c.Request.Context()); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process threshold rules list request"))
+		return
+	}
+
+	if out, err = api.NewThresholdRuleList(rules); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process threshold rules list request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) CreateThresholdRule(c *gin.Context) {
+	var (
+		err  error
+		in   *api.ThresholdRule
+		rule *models.ThresholdRule
+		out  *api.ThresholdRule
+	)
+
+	in = &api.ThresholdRule{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse threshold rule data"))
+		return
+	}
+
+	if !in.ID.IsZero() {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(api.ReadOnlyField("id")))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if rule, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process threshold rule data"))
+		return
+	}
+
+	if err = s.store.CreateThresholdRule(c.Request.Context(), rule, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.CreateThresholdRule()"},
+	}); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create threshold rule request"))
+		return
+	}
+
+	if out, err = api.NewThresholdRule(rule); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process create threshold rule request"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+func (s *Server) ThresholdRuleDetail(c *gin.Context) {
+	var (
+		err    error
+		ruleID ulid.ULID
+		rule   *models.ThresholdRule
+		out    *api.ThresholdRule
+	)
+
+	if ruleID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("threshold rule not found"))
+		return
+	}
+
+	if rule, err = s.store.RetrieveThresholdRule(c.Request.Context(), ruleID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("threshold rule not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process threshold rule detail request"))
+		return
+	}
+
+	if out, err = api.NewThresholdRule(rule); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process threshold rule detail request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) UpdateThresholdRule(c *gin.Context) {
+	var (
+		err    error
+		ruleID ulid.ULID
+		in     *api.ThresholdRule
+		rule   *models.ThresholdRule
+		out    *api.ThresholdRule
+	)
+
+	if ruleID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("threshold rule not found"))
+		return
+	}
+
+	in = &api.ThresholdRule{}
+	if err = c.BindJSON(in); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, api.Error("could not parse threshold rule data"))
+		return
+	}
+
+	if err = CheckIDMatch(in.ID, ruleID); err != nil {
+		c.JSON(http.StatusBadRequest, api.Error(err))
+		return
+	}
+
+	if err = in.Validate(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, api.Error(err))
+		return
+	}
+
+	if rule, err = in.Model(); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process threshold rule data"))
+		return
+	}
+
+	if err = s.store.UpdateThresholdRule(c.Request.Context(), rule, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.UpdateThresholdRule()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("threshold rule not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process update threshold rule request"))
+		return
+	}
+
+	if out, err = api.NewThresholdRule(rule); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process update threshold rule request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func (s *Server) DeleteThresholdRule(c *gin.Context) {
+	var (
+		err    error
+		ruleID ulid.ULID
+	)
+
+	if ruleID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("threshold rule not found"))
+		return
+	}
+
+	if err = s.store.DeleteThresholdRule(c.Request.Context(), ruleID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.DeleteThresholdRule()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("threshold rule not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process delete threshold rule request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, api.Reply{Success: true})
+}
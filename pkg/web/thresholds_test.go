@@ -0,0 +1,104 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerThresholdRules() {
+	w.Run("List", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListThresholdRules = func(ctx context.Context) ([]*models.ThresholdRule, error) {
+			return []*models.ThresholdRule{
+				{Model: models.Model{ID: ulid.MakeSecure()}, Description: "EU", OriginatorCountry: sql.NullString{Valid: true, String: "DE"}},
+				{Model: models.Model{ID: ulid.MakeSecure()}, Description: "US", OriginatorCountry: sql.NullString{Valid: true, String: "US"}, Threshold: 3000},
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).ListThresholdRules(ctx)
+		require.NoError(err, "unexpected client request error")
+		require.Len(out.Rules, 2)
+		require.Equal("DE", out.Rules[0].OriginatorCountry)
+		require.Equal(3000.0, out.Rules[1].Threshold)
+	})
+
+	w.Run("Create", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnCreateThresholdRule = func(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) error {
+			require.Equal("US", rule.OriginatorCountry.String)
+			require.False(rule.BeneficiaryCountry.Valid)
+			require.Equal(3000.0, rule.Threshold)
+			rule.ID = ulid.MakeSecure()
+			return nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"config:manage"}).CreateThresholdRule(ctx, &api.ThresholdRule{
+			Description:       "US transfers",
+			OriginatorCountry: "us",
+			Threshold:         3000,
+		})
+		require.NoError(err, "unexpected client request error")
+		require.False(out.ID.IsZero())
+		require.Equal("US", out.OriginatorCountry)
+	})
+
+	w.Run("Invalid", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"config:manage"}).CreateThresholdRule(ctx, &api.ThresholdRule{OriginatorCountry: "USA"})
+		require.ErrorContains(err, "2 validation errors occurred")
+		require.Nil(out)
+	})
+
+	w.Run("Update", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnUpdateThresholdRule = func(ctx context.Context, rule *models.ThresholdRule, auditLog *models.ComplianceAuditLog) error {
+			return dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"config:manage"}).UpdateThresholdRule(ctx, &api.ThresholdRule{ID: ulid.MakeSecure(), Description: "EU"})
+		require.ErrorContains(err, "threshold rule not found")
+		require.Nil(out)
+	})
+
+	w.Run("Delete", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnDeleteThresholdRule = func(ctx context.Context, ruleID ulid.ULID, auditLog *models.ComplianceAuditLog) error {
+			return nil
+		}
+
+		//test
+		err := w.ClientWithPermissions([]string{"config:manage"}).DeleteThresholdRule(ctx, ulid.MakeSecure())
+		require.NoError(err, "unexpected client request error")
+	})
+
+	w.Run("FailureNoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).CreateThresholdRule(ctx, &api.ThresholdRule{Description: "all"})
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
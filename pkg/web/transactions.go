@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -120,6 +121,7 @@ func (s *Server) CreateTransaction(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, api.Error(err))
 		return
 	}
+	setIdempotentTransaction(c, transaction.ID)
 
 	// Convert the model back to an API response
 	if out, err = api.NewTransaction(transaction); err != nil {
@@ -246,6 +248,11 @@ func (s *Server) UpdateTransaction(c *gin.Context) {
 			return
 		}
 
+		if isTransitionError(err) {
+			c.JSON(http.StatusConflict, api.Error(err))
+			return
+		}
+
 		// TODO: are there other error types that we need to handle to return a 400?
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
@@ -286,6 +293,11 @@ func (s *Server) DeleteTransaction(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, dberr.ErrLegalHold) {
+			c.JSON(http.StatusConflict, api.Error("transaction is under legal hold and cannot be deleted"))
+			return
+		}
+
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
 		return
@@ -397,6 +409,10 @@ func (s *Server) SendEnvelopeForTransaction(c *gin.Context) {
 	// Update the transaction based on the outgoing message from the API client
 	if err = packet.Out.UpdateTransaction(); err != nil {
 		c.Error(err)
+		if isTransitionError(err) {
+			c.JSON(http.StatusConflict, api.Error(err))
+			return
+		}
 	}
 
 	// Send the secure envelope and get secure envelope response
@@ -417,6 +433,7 @@ func (s *Server) SendEnvelopeForTransaction(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, api.Error("transfer sent but unable to store secure envelopes locally"))
 		return
 	}
+	setIdempotentTransaction(c, envelopeID)
 
 	// If the content requested is HTML (e.g. the web-front end), then
 	// respond with a 204 no content response and the front-end will handle the
@@ -673,8 +690,6 @@ func (s *Server) AcceptTransaction(c *gin.Context) {
 	var (
 		err        error
 		envelopeID uuid.UUID
-		archived   bool
-		status     enum.Status
 		in         *api.Envelope
 		payload    *trisa.Payload
 		out        *api.Envelope
@@ -716,35 +731,78 @@ func (s *Server) AcceptTransaction(c *gin.Context) {
 		return
 	}
 
+	// If accepting the transfer requires four-eyes approval, hold it for a reviewer.
+	// NOTE: holdForApproval handles the response to the user if the request is held.
+	if s.holdForApproval(c, enum.ApprovalActionAccept, envelopeID, in) {
+		return
+	}
+
+	// Send the accept to the counterparty and get the secure envelope response
+	// NOTE: acceptTransaction handles any error response that needs to be sent to the user.
+	if packet, err = s.acceptTransaction(c, envelopeID, payload); err != nil {
+		return
+	}
+	setIdempotentTransaction(c, envelopeID)
+
+	// If the content requested is HTML (e.g. the web-front end), then redirect the user
+	// to the transaction detail page and set a cookie to display a toast message
+	if htmx.IsHTMXRequest(c) {
+		detailURL, _ := url.JoinPath("/transactions", packet.Transaction.ID.String())
+		s.AddToastMessage(c, "Transaction Accepted", "The transaction was accepted and successfully sent to the counterparty.", "success")
+		htmx.Redirect(c, http.StatusSeeOther, detailURL)
+		return
+	}
+
+	// If the content request is JSON (e.g. the API) then render the incoming envelope
+	// as the response by decrypting it and sending it back to the user.
+	if out, err = api.NewEnvelope(packet.In.Model(), packet.In.Envelope); err != nil {
+		c.Error(fmt.Errorf("could not parse incoming secure envelope: %w", err))
+		c.JSON(http.StatusInternalServerError, api.Error("could not return incoming response from counterparty"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Sends the accept payload to the counterparty of a transaction that is in review and
+// stores the secure envelopes. It is executed by AcceptTransaction or, if the accept
+// required four-eyes approval, by ApproveAction when a reviewer approves the request.
+// If an error is returned, the error response has already been sent to the user.
+func (s *Server) acceptTransaction(c *gin.Context, envelopeID uuid.UUID, payload *trisa.Payload) (packet *postman.TRISAPacket, err error) {
+	var (
+		archived bool
+		status   enum.Status
+	)
+
 	// Verify that the transaction is in a state that we can perform the action on.
 	// Check that transaction is in a repairable state.
 	ctx := c.Request.Context()
 	if archived, status, err = s.store.TransactionState(ctx, envelopeID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	if archived {
 		c.JSON(http.StatusBadRequest, api.Error("transaction is archived and cannot be repaired"))
-		return
+		return nil, ErrTransactionState
 	}
 
 	if status != enum.StatusReview {
 		c.JSON(http.StatusBadRequest, api.Error("transaction not in a reviewable state"))
-		return
+		return nil, ErrTransactionState
 	}
 
 	// Send the payload with the accept transfer state
 	if packet, err = postman.SendTRISA(envelopeID, payload, trisa.TransferAccepted); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusBadRequest, api.Error("could not create outgoing packet for transfer accept"))
-		return
+		return nil, err
 	}
 
 	// Ensure the logger is set!
@@ -754,24 +812,24 @@ func (s *Server) AcceptTransaction(c *gin.Context) {
 	if packet.Transaction, err = s.store.RetrieveTransaction(ctx, envelopeID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	// Get the counterparty from the database
 	if packet.Counterparty, err = s.store.RetrieveCounterparty(ctx, packet.Transaction.CounterpartyID.ULID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("counterparty not found: transaction needs to be updated"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	// Create a prepared transaction to update the transaction and secure envelopes
@@ -780,13 +838,17 @@ func (s *Server) AcceptTransaction(c *gin.Context) {
 	}); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("unable to send transfer to remote counterparty"))
-		return
+		return nil, err
 	}
 	defer packet.DB.Rollback()
 
 	// Update the transaction based on the outgoing message from the API client
 	if err = packet.Out.UpdateTransaction(); err != nil {
 		c.Error(err)
+		if isTransitionError(err) {
+			c.JSON(http.StatusConflict, api.Error(err))
+			return nil, err
+		}
 	}
 
 	// Send the secure envelope and get secure envelope response
@@ -794,7 +856,7 @@ func (s *Server) AcceptTransaction(c *gin.Context) {
 	if err = s.SendEnvelope(ctx, packet); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("unable to send transfer to remote counterparty"))
-		return
+		return nil, err
 	}
 
 	// Update transaction state based on response from counterparty
@@ -805,34 +867,15 @@ func (s *Server) AcceptTransaction(c *gin.Context) {
 	if err = packet.DB.Commit(); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("transfer sent but unable to store secure envelopes locally"))
-		return
-	}
-
-	// If the content requested is HTML (e.g. the web-front end), then redirect the user
-	// to the transaction detail page and set a cookie to display a toast message
-	if htmx.IsHTMXRequest(c) {
-		detailURL, _ := url.JoinPath("/transactions", packet.Transaction.ID.String())
-		s.AddToastMessage(c, "Transaction Accepted", "The transaction was accepted and successfully sent to the counterparty.", "success")
-		htmx.Redirect(c, http.StatusSeeOther, detailURL)
-		return
-	}
-
-	// If the content request is JSON (e.g. the API) then render the incoming envelope
-	// as the response by decrypting it and sending it back to the user.
-	if out, err = api.NewEnvelope(packet.In.Model(), packet.In.Envelope); err != nil {
-		c.Error(fmt.Errorf("could not parse incoming secure envelope: %w", err))
-		c.JSON(http.StatusInternalServerError, api.Error("could not return incoming response from counterparty"))
-		return
+		return nil, err
 	}
 
-	c.JSON(http.StatusOK, out)
+	return packet, nil
 }
 
 func (s *Server) RejectTransaction(c *gin.Context) {
 	var (
 		err        error
-		archived   bool
-		status     enum.Status
 		envelopeID uuid.UUID
 		in         *api.Rejection
 		out        *api.Envelope
@@ -859,33 +902,75 @@ func (s *Server) RejectTransaction(c *gin.Context) {
 		return
 	}
 
+	// If rejecting the transfer requires four-eyes approval, hold it for a reviewer.
+	// NOTE: holdForApproval handles the response to the user if the request is held.
+	if s.holdForApproval(c, enum.ApprovalActionReject, envelopeID, in) {
+		return
+	}
+
+	// Send the rejection to the counterparty and get the secure envelope response
+	// NOTE: rejectTransaction handles any error response that needs to be sent to the user.
+	if packet, err = s.rejectTransaction(c, envelopeID, in); err != nil {
+		return
+	}
+	setIdempotentTransaction(c, envelopeID)
+
+	// If the content requested is HTML (e.g. the web-front end), then
+	// respond with a 204 no content response and the front-end will handle the
+	// success message in the toast.
+	if c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) == binding.MIMEHTML {
+		htmx.Trigger(c, htmx.TransactionsUpdated)
+		return
+	}
+
+	// If the content request is JSON (e.g. the API) then render the incoming envelope
+	// as the response by decrypting it and sending it back to the user.
+	if out, err = api.NewEnvelope(packet.In.Model(), packet.In.Envelope); err != nil {
+		c.Error(fmt.Errorf("could not parse incoming secure envelope: %w", err))
+		c.JSON(http.StatusInternalServerError, api.Error("could not return incoming response from counterparty"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Sends the rejection to the counterparty of a transaction that is in review and stores
+// the secure envelopes. It is executed by RejectTransaction or, if the rejection
+// required four-eyes approval, by ApproveAction when a reviewer approves the request.
+// If an error is returned, the error response has already been sent to the user.
+func (s *Server) rejectTransaction(c *gin.Context, envelopeID uuid.UUID, in *api.Rejection) (packet *postman.TRISAPacket, err error) {
+	var (
+		archived bool
+		status   enum.Status
+	)
+
 	// Check that transaction is in a rejectable state.
 	ctx := c.Request.Context()
 	if archived, status, err = s.store.TransactionState(ctx, envelopeID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	if archived {
 		c.JSON(http.StatusBadRequest, api.Error("transaction is archived and cannot be repaired"))
-		return
+		return nil, ErrTransactionState
 	}
 
 	if status != enum.StatusReview {
 		c.JSON(http.StatusBadRequest, api.Error("transaction not in a reviewable state"))
-		return
+		return nil, ErrTransactionState
 	}
 
 	if packet, err = postman.SendTRISAReject(envelopeID, in.Proto()); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("could not process reject transaction request"))
-		return
+		return nil, err
 	}
 
 	// Ensure the logger is set!
@@ -895,24 +980,24 @@ func (s *Server) RejectTransaction(c *gin.Context) {
 	if packet.Transaction, err = s.store.RetrieveTransaction(ctx, envelopeID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	// Get the counterparty from the database
 	if packet.Counterparty, err = s.store.RetrieveCounterparty(ctx, packet.Transaction.CounterpartyID.ULID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("counterparty not found: transaction needs to be updated"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	// Create a prepared transaction to update the transaction and secure envelopes
@@ -921,13 +1006,17 @@ func (s *Server) RejectTransaction(c *gin.Context) {
 	}); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("unable to send transfer to remote counterparty"))
-		return
+		return nil, err
 	}
 	defer packet.DB.Rollback()
 
 	// Update the transaction state based on the outgoing message
 	if err = packet.Out.UpdateTransaction(); err != nil {
 		c.Error(err)
+		if isTransitionError(err) {
+			c.JSON(http.StatusConflict, api.Error(err))
+			return nil, err
+		}
 	}
 
 	// Send the secure envelope and get secure envelope response
@@ -935,7 +1024,7 @@ func (s *Server) RejectTransaction(c *gin.Context) {
 	if err = s.SendEnvelope(ctx, packet); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusBadRequest, api.Error("unable to send transfer to remote counterparty"))
-		return
+		return nil, err
 	}
 
 	// Update the transaction state based on the incoming message from the counterparty
@@ -946,26 +1035,71 @@ func (s *Server) RejectTransaction(c *gin.Context) {
 	if err = packet.DB.Commit(); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("transfer sent but unable to store secure envelopes locally"))
-		return
+		return nil, err
 	}
 
-	// If the content requested is HTML (e.g. the web-front end), then
-	// respond with a 204 no content response and the front-end will handle the
-	// success message in the toast.
-	if c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) == binding.MIMEHTML {
-		htmx.Trigger(c, htmx.TransactionsUpdated)
-		return
+	return packet, nil
+}
+
+// RejectExpiredTransaction sends a rejection to the counterparty of a transaction that
+// the local node did not reply to before the reply deadline. It is used by the
+// deadlines service when it is configured to reject transactions on expiry; the actor
+// of the audit log must be set on the context by the caller.
+func (s *Server) RejectExpiredTransaction(ctx context.Context, transactionID uuid.UUID) (err error) {
+	var (
+		archived bool
+		status   enum.Status
+		packet   *postman.TRISAPacket
+	)
+
+	if archived, status, err = s.store.TransactionState(ctx, transactionID); err != nil {
+		return err
 	}
 
-	// If the content request is JSON (e.g. the API) then render the incoming envelope
-	// as the response by decrypting it and sending it back to the user.
-	if out, err = api.NewEnvelope(packet.In.Model(), packet.In.Envelope); err != nil {
-		c.Error(fmt.Errorf("could not parse incoming secure envelope: %w", err))
-		c.JSON(http.StatusInternalServerError, api.Error("could not return incoming response from counterparty"))
-		return
+	if archived || status != enum.StatusReview {
+		return ErrTransactionState
 	}
 
-	c.JSON(http.StatusOK, out)
+	rejection := &api.Rejection{
+		Code:    trisa.ComplianceCheckFail.String(),
+		Message: "the reply deadline for this transfer has passed",
+		Retry:   false,
+	}
+
+	if packet, err = postman.SendTRISAReject(transactionID, rejection.Proto()); err != nil {
+		return err
+	}
+
+	packet.Log = logger.Tracing(ctx).With().Str("envelope_id", transactionID.String()).Logger()
+
+	if packet.Transaction, err = s.store.RetrieveTransaction(ctx, transactionID); err != nil {
+		return err
+	}
+
+	if packet.Counterparty, err = s.store.RetrieveCounterparty(ctx, packet.Transaction.CounterpartyID.ULID); err != nil {
+		return err
+	}
+
+	if packet.DB, err = s.store.PrepareTransaction(ctx, transactionID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.RejectExpiredTransaction()"},
+	}); err != nil {
+		return err
+	}
+	defer packet.DB.Rollback()
+
+	if err = packet.Out.UpdateTransaction(); err != nil {
+		return err
+	}
+
+	if err = s.SendEnvelope(ctx, packet); err != nil {
+		return err
+	}
+
+	if err = packet.In.UpdateTransaction(); err != nil {
+		return err
+	}
+
+	return packet.DB.Commit()
 }
 
 func (s *Server) RepairTransactionPreview(c *gin.Context) {
@@ -1182,6 +1316,10 @@ func (s *Server) RepairTransaction(c *gin.Context) {
 	// Update the transaction based on the outgoing message from the API client
 	if err = packet.Out.UpdateTransaction(); err != nil {
 		c.Error(err)
+		if isTransitionError(err) {
+			c.JSON(http.StatusConflict, api.Error(err))
+			return
+		}
 	}
 
 	// Send the secure envelope and get secure envelope response
@@ -1307,12 +1445,7 @@ func (s *Server) CompleteTransaction(c *gin.Context) {
 	var (
 		err        error
 		envelopeID uuid.UUID
-		archived   bool
-		status     enum.Status
 		in         *generic.Transaction
-		env        *models.SecureEnvelope
-		decrypted  *envelope.Envelope
-		payload    *trisa.Payload
 		out        *api.Envelope
 		packet     *postman.TRISAPacket
 	)
@@ -1336,28 +1469,75 @@ func (s *Server) CompleteTransaction(c *gin.Context) {
 		return
 	}
 
+	// If completing the transfer requires four-eyes approval, hold it for a reviewer.
+	// NOTE: holdForApproval handles the response to the user if the request is held.
+	if s.holdForApproval(c, enum.ApprovalActionComplete, envelopeID, in) {
+		return
+	}
+
+	// Send the completed transaction to the counterparty and get the envelope response
+	// NOTE: completeTransaction handles any error response that needs to be sent to the user.
+	if packet, err = s.completeTransaction(c, envelopeID, in); err != nil {
+		return
+	}
+	setIdempotentTransaction(c, envelopeID)
+
+	// If the content requested is HTML (e.g. the web-front end), then redirect the user
+	// to the transaction detail page and set a cookie to display a toast message
+	if htmx.IsHTMXRequest(c) {
+		detailURL, _ := url.JoinPath("/transactions", packet.Transaction.ID.String())
+		s.AddToastMessage(c, "Transaction Completed", "The transaction was completed and successfully sent to the counterparty.", "success")
+		htmx.Redirect(c, http.StatusSeeOther, detailURL)
+		return
+	}
+
+	// If the content request is JSON (e.g. the API) then render the incoming envelope
+	// as the response by decrypting it and sending it back to the user.
+	if out, err = api.NewEnvelope(packet.In.Model(), packet.In.Envelope); err != nil {
+		c.Error(fmt.Errorf("could not parse incoming secure envelope: %w", err))
+		c.JSON(http.StatusInternalServerError, api.Error("could not return incoming response from counterparty"))
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// Adds the completed transaction to the latest payload of an accepted transaction and
+// sends it to the counterparty, storing the secure envelopes. It is executed by
+// CompleteTransaction or, if completing the transfer required four-eyes approval, by
+// ApproveAction when a reviewer approves the request. If an error is returned, the
+// error response has already been sent to the user.
+func (s *Server) completeTransaction(c *gin.Context, envelopeID uuid.UUID, in *generic.Transaction) (packet *postman.TRISAPacket, err error) {
+	var (
+		archived  bool
+		status    enum.Status
+		env       *models.SecureEnvelope
+		decrypted *envelope.Envelope
+		payload   *trisa.Payload
+	)
+
 	// Verify that the transaction is in a state that we can perform the action on.
 	// Check that transaction is in a repairable state.
 	ctx := c.Request.Context()
 	if archived, status, err = s.store.TransactionState(ctx, envelopeID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	if archived {
 		c.JSON(http.StatusBadRequest, api.Error("transaction is archived and cannot be repaired"))
-		return
+		return nil, ErrTransactionState
 	}
 
 	if status != enum.StatusAccepted {
 		c.JSON(http.StatusBadRequest, api.Error("transaction not in an accepted state"))
-		return
+		return nil, ErrTransactionState
 	}
 
 	// Get the last envelope payload from the transaction to add the completed
@@ -1366,39 +1546,39 @@ func (s *Server) CompleteTransaction(c *gin.Context) {
 	if env, err = s.store.LatestSecureEnvelope(ctx, envelopeID, enum.DirectionAny); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	// Decrypt the secure envelope using the private keys in the key store
 	if decrypted, err = s.Decrypt(env); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("this payload cannot be decrypted"))
-		return
+		return nil, err
 	}
 
 	if payload, err = decrypted.Payload(); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("unable to retrieve identity details"))
-		return
+		return nil, err
 	}
 
 	// Update the payload transaction with the completed transaction
 	if payload.Transaction, err = anypb.New(in); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("could not process complete transaction request"))
-		return
+		return nil, err
 	}
 
 	// Send the payload with the completed transfer state
 	if packet, err = postman.SendTRISA(envelopeID, payload, trisa.TransferCompleted); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("could not process complete transaction request"))
-		return
+		return nil, err
 	}
 
 	// Ensure the logger is set!
@@ -1408,31 +1588,31 @@ func (s *Server) CompleteTransaction(c *gin.Context) {
 	if packet.Transaction, err = s.store.RetrieveTransaction(ctx, envelopeID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	// Get the counterparty from the database
 	if packet.Counterparty, err = s.store.RetrieveCounterparty(ctx, packet.Transaction.CounterpartyID.ULID); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
 			c.JSON(http.StatusNotFound, api.Error("counterparty not found: transaction needs to be updated"))
-			return
+			return nil, err
 		}
 
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
-		return
+		return nil, err
 	}
 
 	// TODO: Handle Sunrise and TRP Counterparties
 	if packet.Counterparty.Protocol != enum.ProtocolTRISA {
 		c.Error(fmt.Errorf("%s protcol not supported for complete transaction", packet.Counterparty.Protocol))
 		c.JSON(http.StatusBadRequest, api.Error("only the TRISA protocol is supported for this endpoint at this time"))
-		return
+		return nil, err
 	}
 
 	// Create a prepared transaction to update the transaction and secure envelopes
@@ -1441,13 +1621,17 @@ func (s *Server) CompleteTransaction(c *gin.Context) {
 	}); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("unable to send transfer to remote counterparty"))
-		return
+		return nil, err
 	}
 	defer packet.DB.Rollback()
 
 	// Update the transaction based on the outgoing message from the API client
 	if err = packet.Out.UpdateTransaction(); err != nil {
 		c.Error(err)
+		if isTransitionError(err) {
+			c.JSON(http.StatusConflict, api.Error(err))
+			return nil, err
+		}
 	}
 
 	// Send the secure envelope and get secure envelope response
@@ -1455,7 +1639,7 @@ func (s *Server) CompleteTransaction(c *gin.Context) {
 	if err = s.SendEnvelope(ctx, packet); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("unable to send transfer to remote counterparty"))
-		return
+		return nil, err
 	}
 
 	// Update transaction state based on response from counterparty
@@ -1466,27 +1650,10 @@ func (s *Server) CompleteTransaction(c *gin.Context) {
 	if err = packet.DB.Commit(); err != nil {
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error("transfer sent but unable to store secure envelopes locally"))
-		return
-	}
-
-	// If the content requested is HTML (e.g. the web-front end), then redirect the user
-	// to the transaction detail page and set a cookie to display a toast message
-	if htmx.IsHTMXRequest(c) {
-		detailURL, _ := url.JoinPath("/transactions", packet.Transaction.ID.String())
-		s.AddToastMessage(c, "Transaction Completed", "The transaction was completed and successfully sent to the counterparty.", "success")
-		htmx.Redirect(c, http.StatusSeeOther, detailURL)
-		return
-	}
-
-	// If the content request is JSON (e.g. the API) then render the incoming envelope
-	// as the response by decrypting it and sending it back to the user.
-	if out, err = api.NewEnvelope(packet.In.Model(), packet.In.Envelope); err != nil {
-		c.Error(fmt.Errorf("could not parse incoming secure envelope: %w", err))
-		c.JSON(http.StatusInternalServerError, api.Error("could not return incoming response from counterparty"))
-		return
+		return nil, err
 	}
 
-	c.JSON(http.StatusOK, out)
+	return packet, nil
 }
 
 func (s *Server) ArchiveTransaction(c *gin.Context) {
@@ -1509,6 +1676,11 @@ func (s *Server) ArchiveTransaction(c *gin.Context) {
 			return
 		}
 
+		if errors.Is(err, dberr.ErrLegalHold) {
+			c.JSON(http.StatusConflict, api.Error("transaction is under legal hold and cannot be archived"))
+			return
+		}
+
 		c.Error(err)
 		c.JSON(http.StatusInternalServerError, api.Error(err))
 		return
@@ -1559,6 +1731,46 @@ func (s *Server) UnarchiveTransaction(c *gin.Context) {
 	}
 }
 
+// TransactionHistory returns the timeline of the changes of status of the transaction.
+func (s *Server) TransactionHistory(c *gin.Context) {
+	var (
+		err           error
+		transactionID uuid.UUID
+		history       []*models.StatusTransition
+		out           *api.TransactionHistory
+	)
+
+	// Parse the transactionID passed in from the URL
+	if transactionID, err = uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+		return
+	}
+
+	if history, err = s.store.ListStatusHistory(c.Request.Context(), transactionID); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("transaction not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process transaction history request"))
+		return
+	}
+
+	if out, err = api.NewTransactionHistory(transactionID, history); err != nil {
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not process transaction history request"))
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  []string{binding.MIMEJSON, binding.MIMEHTML},
+		Data:     out,
+		HTMLName: "partials/transactions/history.html",
+		HTMLData: scene.New(c).WithAPIData(out),
+	})
+}
+
 //===========================================================================
 // Secure Envelopes REST Resource
 //===========================================================================
@@ -1738,3 +1950,9 @@ func CheckUUIDMatch(id, target uuid.UUID) error {
 
 	return nil
 }
+
+// Returns true if the transaction could not be updated because the change of status is
+// not allowed or because the transaction has already been completed or rejected.
+func isTransitionError(err error) bool {
+	return errors.Is(err, dberr.ErrInvalidTransition) || errors.Is(err, dberr.ErrTransactionFinal)
+}
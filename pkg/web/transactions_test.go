@@ -0,0 +1,115 @@
+package web_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/trisacrypto/envoy/pkg/enum"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
+	"github.com/trisacrypto/envoy/pkg/store/models"
+	"github.com/trisacrypto/envoy/pkg/web/api/v1"
+	"go.rtnl.ai/ulid"
+)
+
+func (w *webTestSuite) TestServerTransactionHistory() {
+	txID := uuid.New()
+
+	w.Run("List", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		envelopeID := ulid.MakeSecure()
+		w.store.OnListStatusHistory = func(ctx context.Context, id uuid.UUID) ([]*models.StatusTransition, error) {
+			require.Equal(txID, id)
+			return []*models.StatusTransition{
+				{ID: ulid.MakeSecure(), TransactionID: id, From: enum.StatusUnspecified, To: enum.StatusDraft, ActorType: enum.ActorUser, Actor: sql.NullString{Valid: true, String: "Compliance User"}, Created: time.Now()},
+				{ID: ulid.MakeSecure(), TransactionID: id, From: enum.StatusDraft, To: enum.StatusPending, ActorType: enum.ActorSystem, EnvelopeID: ulid.NullULID{Valid: true, ULID: envelopeID}, Created: time.Now()},
+			}, nil
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).TransactionHistory(ctx, txID)
+		require.NoError(err, "unexpected client request error")
+		require.Equal(txID, out.TransactionID)
+		require.Len(out.Transitions, 2)
+
+		require.Equal("unspecified", out.Transitions[0].From)
+		require.Equal("draft", out.Transitions[0].To)
+		require.Equal("Compliance User", out.Transitions[0].Actor)
+		require.Nil(out.Transitions[0].EnvelopeID)
+
+		require.Equal("pending", out.Transitions[1].To)
+		require.Equal(enum.ActorSystem.String(), out.Transitions[1].Actor, "expected the actor type to be used as the name")
+		require.Equal(envelopeID, *out.Transitions[1].EnvelopeID)
+	})
+
+	w.Run("NotFound", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnListStatusHistory = func(ctx context.Context, id uuid.UUID) ([]*models.StatusTransition, error) {
+			return nil, dberr.ErrNotFound
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:view"}).TransactionHistory(ctx, txID)
+		require.ErrorContains(err, "transaction not found")
+		require.Nil(out)
+	})
+
+	w.Run("NoPermission", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"counterparties:view"}).TransactionHistory(ctx, txID)
+		require.ErrorContains(err, "user does not have permission to perform this operation")
+		require.Nil(out)
+	})
+}
+
+func (w *webTestSuite) TestServerUpdateTransactionTransition() {
+	txID := uuid.New()
+	in := &api.Transaction{
+		ID:           txID,
+		Source:       "local",
+		Status:       "pending",
+		Counterparty: "Example VASP",
+		VirtualAsset: "BTC",
+		Amount:       0.25,
+	}
+
+	w.Run("Final", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnUpdateTransaction = func(ctx context.Context, transaction *models.Transaction, auditLog *models.ComplianceAuditLog) error {
+			return fmt.Errorf("%w: transaction is completed", dberr.ErrTransactionFinal)
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).UpdateTransaction(ctx, in)
+		require.ErrorContains(err, "[409]")
+		require.ErrorContains(err, "completed or rejected transactions cannot be modified")
+		require.Nil(out)
+	})
+
+	w.Run("InvalidTransition", func() {
+		//setup
+		require := w.Require()
+		ctx := context.Background()
+		w.store.OnUpdateTransaction = func(ctx context.Context, transaction *models.Transaction, auditLog *models.ComplianceAuditLog) error {
+			return fmt.Errorf("%w: cannot change status from accepted to pending", dberr.ErrInvalidTransition)
+		}
+
+		//test
+		out, err := w.ClientWithPermissions([]string{"travelrule:manage"}).UpdateTransaction(ctx, in)
+		require.ErrorContains(err, "[409]")
+		require.ErrorContains(err, "cannot change status from accepted to pending")
+		require.Nil(out)
+	})
+}
@@ -95,7 +95,7 @@ func (s *Server) CreateUser(c *gin.Context) {
 	// Validate the role in the database
 	if role, err = s.store.LookupRole(c.Request.Context(), in.Role); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
-			c.JSON(http.StatusBadRequest, api.Error(api.ValidationError(nil, api.IncorrectField("role", "unknown role - specify one of admin, compliance, or observer"))))
+			c.JSON(http.StatusBadRequest, api.Error(api.ValidationError(nil, api.IncorrectField("role", "unknown role - specify the title of an existing role"))))
 			return
 		}
 
@@ -264,7 +264,7 @@ func (s *Server) UpdateUser(c *gin.Context) {
 	// Validate the role in the database
 	if role, err = s.store.LookupRole(c.Request.Context(), in.Role); err != nil {
 		if errors.Is(err, dberr.ErrNotFound) {
-			c.JSON(http.StatusBadRequest, api.Error(api.ValidationError(nil, api.IncorrectField("role", "unknown role - specify one of admin, compliance, or observer"))))
+			c.JSON(http.StatusBadRequest, api.Error(api.ValidationError(nil, api.IncorrectField("role", "unknown role - specify the title of an existing role"))))
 			return
 		}
 
@@ -317,8 +317,8 @@ func (s *Server) DeleteUser(c *gin.Context) {
 		return
 	}
 
-	// Delete the user from the database
-	// TODO: for audit purposes we may simply want to move the user to a revoked table.
+	// Delete the user from the database; the identity of the user is retained without
+	// credentials as a revoked user so that audit log actors can still be resolved.
 	if err = s.store.DeleteUser(c.Request.Context(), userID, &models.ComplianceAuditLog{
 		ChangeNotes: sql.NullString{Valid: true, String: "Server.DeleteUser()"},
 	}); err != nil {
@@ -388,6 +388,9 @@ func (s *Server) ChangeUserPassword(c *gin.Context) {
 		return
 	}
 
+	// Revoke the sessions of the user so they must login with the new password
+	s.revokeSessions(c.Request.Context(), userID, ulid.Null)
+
 	// TODO: email the user the password if requested
 
 	c.Negotiate(http.StatusOK, gin.Negotiate{
@@ -397,3 +400,38 @@ func (s *Server) ChangeUserPassword(c *gin.Context) {
 		HTMLName: "user_password_changed.html",
 	})
 }
+
+// UnlockUser removes the login lockout of a user who has failed too many logins so that
+// the user can login immediately.
+func (s *Server) UnlockUser(c *gin.Context) {
+	var (
+		err    error
+		userID ulid.ULID
+	)
+
+	// Parse the userID from the URL
+	if userID, err = ulid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, api.Error("user not found"))
+		return
+	}
+
+	if err = s.store.UnlockUser(c.Request.Context(), userID, &models.ComplianceAuditLog{
+		ChangeNotes: sql.NullString{Valid: true, String: "Server.UnlockUser()"},
+	}); err != nil {
+		if errors.Is(err, dberr.ErrNotFound) {
+			c.JSON(http.StatusNotFound, api.Error("user not found"))
+			return
+		}
+
+		c.Error(err)
+		c.JSON(http.StatusInternalServerError, api.Error("could not unlock user"))
+		return
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEJSON:
+		c.JSON(http.StatusOK, api.Reply{Success: true})
+	case binding.MIMEHTML:
+		htmx.Trigger(c, htmx.UsersUpdated)
+	}
+}
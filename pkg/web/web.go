@@ -6,8 +6,11 @@ import (
 
 	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/store"
+	"github.com/trisacrypto/envoy/pkg/thresholds"
 	"github.com/trisacrypto/envoy/pkg/trisa/network"
 	"github.com/trisacrypto/envoy/pkg/web/auth"
+	"github.com/trisacrypto/envoy/pkg/web/auth/oidc"
+	"github.com/trisacrypto/envoy/pkg/web/auth/passwords"
 	"github.com/trisacrypto/envoy/pkg/web/scene"
 
 	"github.com/gin-gonic/gin"
@@ -21,9 +24,10 @@ func New(conf config.Config, store store.Store, network network.Network) (s *Ser
 	}
 
 	s = &Server{
-		conf:  conf,
-		store: store,
-		trisa: network,
+		conf:       conf,
+		store:      store,
+		trisa:      network,
+		thresholds: thresholds.New(conf, store),
 	}
 
 	// If not enabled, return just the server stub
@@ -36,9 +40,19 @@ func New(conf config.Config, store store.Store, network network.Network) (s *Ser
 		return nil, err
 	}
 
+	// Configure single sign-on with the identity provider if enabled
+	if s.conf.Web.Auth.OIDC.Enabled {
+		if s.oidc, err = oidc.New(s.conf.Web.Auth.OIDC); err != nil {
+			return nil, err
+		}
+	}
+
 	// Configure the claims issuer with the name of the organization
 	auth.SetOrganization(conf.Organization)
 
+	// Configure the password policy enforced when users set their passwords
+	passwords.SetPolicy(passwordPolicy(conf.Web.Auth.PasswordPolicy))
+
 	// Configure the gin router if enabled
 	s.router = gin.New()
 	s.router.RedirectTrailingSlash = true
@@ -47,6 +61,13 @@ func New(conf config.Config, store store.Store, network network.Network) (s *Ser
 	s.router.ForwardedByClientIP = true
 	s.router.UseRawPath = false
 	s.router.UnescapePathValues = true
+
+	// Only trust the X-Forwarded-For headers of the configured proxies so that clients
+	// cannot spoof their ip address (e.g. to bypass api key network restrictions).
+	if err = s.router.SetTrustedProxies(conf.Web.TrustedProxies); err != nil {
+		return nil, err
+	}
+
 	if err = s.setupRoutes(); err != nil {
 		return nil, err
 	}
@@ -100,3 +121,20 @@ func CheckIDMatch(id, target ulid.ULID) error {
 
 	return nil
 }
+
+// Returns the password policy for the configuration; if the minimum length is not
+// configured the default password policy is used.
+func passwordPolicy(conf config.PasswordPolicyConfig) passwords.Policy {
+	if conf.MinLength == 0 {
+		return passwords.DefaultPolicy
+	}
+
+	return passwords.Policy{
+		MinLength:        conf.MinLength,
+		MinStrength:      conf.MinStrength,
+		RequireUppercase: conf.RequireUppercase,
+		RequireLowercase: conf.RequireLowercase,
+		RequireNumber:    conf.RequireNumber,
+		RequireSpecial:   conf.RequireSpecial,
+	}
+}
@@ -1,6 +1,7 @@
 package web_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -14,7 +15,9 @@ import (
 	"github.com/trisacrypto/envoy/pkg/bufconn"
 	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/store"
+	dberr "github.com/trisacrypto/envoy/pkg/store/errors"
 	"github.com/trisacrypto/envoy/pkg/store/mock"
+	"github.com/trisacrypto/envoy/pkg/store/models"
 	"github.com/trisacrypto/envoy/pkg/trisa/network"
 	"github.com/trisacrypto/envoy/pkg/web"
 	api "github.com/trisacrypto/envoy/pkg/web/api/v1"
@@ -331,11 +334,23 @@ func (w *webTestSuite) CreateAPIServer() {
 			BindAddr:   ":4000",
 			Origin:     "http://localhost:4000",
 			Auth: config.AuthConfig{
-				AccessTokenTTL: 1 * time.Hour,
-				Audience:       "http://localhost:4000",
-				Issuer:         "http://localhost:4000",
+				AccessTokenTTL:     1 * time.Hour,
+				Audience:           "http://localhost:4000",
+				Issuer:             "http://localhost:4000",
+				MaxLoginFailures:   5,
+				LockoutDuration:    5 * time.Minute,
+				MaxLockoutDuration: 24 * time.Hour,
 			},
 		},
+		Idempotency: config.IdempotencyConfig{
+			Enabled: true,
+			Window:  24 * time.Hour,
+		},
+		Batch: config.BatchConfig{
+			Enabled:     true,
+			Concurrency: 2,
+			MaxRows:     10,
+		},
 	}
 
 	// Create the web.Server
@@ -350,6 +365,25 @@ func (w *webTestSuite) ResetAllComponents() {
 	// Reset the mock store
 	w.store.Reset()
 
+	// Test clients authenticate as api keys so usage is recorded on every request
+	w.store.OnRecordAPIKeyUsage = func(context.Context, ulid.ULID, string, time.Time) error {
+		return nil
+	}
+
+	// Test clients send idempotency keys when creating and sending transactions so
+	// the responses are stored in memory and replayed if the key is reused.
+	idempotencyKeys := make(map[string]*models.IdempotencyKey)
+	w.store.OnRetrieveIdempotencyKey = func(_ context.Context, actorID []byte, key string) (*models.IdempotencyKey, error) {
+		if record, ok := idempotencyKeys[string(actorID)+key]; ok {
+			return record, nil
+		}
+		return nil, dberr.ErrNotFound
+	}
+	w.store.OnCreateIdempotencyKey = func(_ context.Context, record *models.IdempotencyKey) error {
+		idempotencyKeys[string(record.ActorID)+record.Key] = record
+		return nil
+	}
+
 	// Close all connections on the HTTP test server
 	w.tsrv.CloseClientConnections()
 
@@ -382,8 +416,7 @@ var AllPermissions = []string{
 // server with the provided list of permissions. Use the variable AllPermissions
 // if you want all of the permissions available.
 func (w *webTestSuite) ClientWithPermissions(permissions []string) api.Client {
-	// Generate an access token with the permissions given
-	access, _, err := w.s.Issuer().CreateTokens(&auth.Claims{
+	return w.ClientWithClaims(&auth.Claims{
 		ClientID:    "webTestSuite",
 		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -391,6 +424,13 @@ func (w *webTestSuite) ClientWithPermissions(permissions []string) api.Client {
 			Subject: "k" + ulid.MakeSecure().String(),
 		},
 	})
+}
+
+// Returns an authenticated api.Client configured to communicate with the test
+// server using an access token issued for the specified claims.
+func (w *webTestSuite) ClientWithClaims(claims *auth.Claims) api.Client {
+	// Generate an access token with the claims given
+	access, _, err := w.s.Issuer().CreateTokens(claims)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,170 @@
+// Package xlsx writes single sheet Office Open XML spreadsheets a row at a time so
+// that large exports can be streamed to the client like a CSV file. Only the parts of
+// the format required for a readable workbook are written: strings are stored inline
+// rather than in a shared strings table, which is larger but requires no buffering.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+var ErrClosed = errors.New("xlsx writer is closed")
+
+// Writer streams rows into the first worksheet of a workbook. Close must be called to
+// complete the workbook; no data is valid until the writer is closed.
+type Writer struct {
+	archive *zip.Writer
+	sheet   io.Writer
+	rows    int
+	closed  bool
+}
+
+// NewWriter writes the workbook parts to w and opens the worksheet named sheet.
+func NewWriter(w io.Writer, sheet string) (_ *Writer, err error) {
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+
+	xw := &Writer{archive: zip.NewWriter(w)}
+	parts := []struct {
+		name string
+		data string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", relsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escape(sheet))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+
+	for _, part := range parts {
+		var f io.Writer
+		if f, err = xw.archive.Create(part.name); err != nil {
+			return nil, err
+		}
+
+		if _, err = io.WriteString(f, part.data); err != nil {
+			return nil, err
+		}
+	}
+
+	// The worksheet must be the last part since it is written as rows are added.
+	if xw.sheet, err = xw.archive.Create("xl/worksheets/sheet1.xml"); err != nil {
+		return nil, err
+	}
+
+	if _, err = io.WriteString(xw.sheet, sheetHeaderXML); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+// Write a row of cells to the worksheet. Numbers and booleans are written as typed
+// cells, times as RFC 3339 strings, nil as an empty cell, and everything else as a
+// string.
+func (w *Writer) Write(row []any) (err error) {
+	if w.closed {
+		return ErrClosed
+	}
+
+	w.rows++
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<row r="%d">`, w.rows)
+
+	for i, value := range row {
+		ref := CellRef(i, w.rows)
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			writeString(&sb, ref, v)
+		case bool:
+			b := 0
+			if v {
+				b = 1
+			}
+			fmt.Fprintf(&sb, `<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+		case int:
+			fmt.Fprintf(&sb, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case int64:
+			fmt.Fprintf(&sb, `<c r="%s"><v>%d</v></c>`, ref, v)
+		case float64:
+			fmt.Fprintf(&sb, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(v, 'f', -1, 64))
+		case time.Time:
+			if !v.IsZero() {
+				writeString(&sb, ref, v.Format(time.RFC3339))
+			}
+		case fmt.Stringer:
+			writeString(&sb, ref, v.String())
+		default:
+			writeString(&sb, ref, fmt.Sprint(v))
+		}
+	}
+
+	sb.WriteString("</row>")
+	_, err = io.WriteString(w.sheet, sb.String())
+	return err
+}
+
+// Close completes the worksheet and the workbook. It does not close the underlying
+// writer.
+func (w *Writer) Close() (err error) {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+
+	if _, err = io.WriteString(w.sheet, sheetFooterXML); err != nil {
+		return err
+	}
+	return w.archive.Close()
+}
+
+// CellRef returns the A1 style reference of the zero-indexed column in the row.
+func CellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+func writeString(sb *strings.Builder, ref, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(sb, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escape(s))
+}
+
+func escape(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+	relsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+	sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	sheetFooterXML = `</sheetData></worksheet>`
+)
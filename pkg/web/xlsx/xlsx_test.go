@@ -0,0 +1,103 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trisacrypto/envoy/pkg/web/xlsx"
+)
+
+type worksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := xlsx.NewWriter(buf, "Transactions")
+	require.NoError(t, err)
+
+	ts := time.Date(2024, 5, 15, 10, 48, 57, 0, time.UTC)
+	require.NoError(t, w.Write([]any{"ID", "Amount", "Archived", "Created", "Notes"}))
+	require.NoError(t, w.Write([]any{"abc", 0.0003842, false, ts, "<Tom & Jerry>"}))
+	require.NoError(t, w.Write([]any{"def", int64(12), true, nil, ""}))
+	require.NoError(t, w.Close())
+
+	require.ErrorIs(t, w.Write([]any{"closed"}), xlsx.ErrClosed)
+	require.ErrorIs(t, w.Close(), xlsx.ErrClosed)
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err, "expected a valid zip archive")
+
+	files := make(map[string][]byte)
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		require.Contains(t, files, name, "missing workbook part")
+		require.NoError(t, xml.Unmarshal(files[name], new(any)), "part %s is not well-formed", name)
+	}
+	require.Contains(t, string(files["xl/workbook.xml"]), `name="Transactions"`)
+
+	sheet := &worksheet{}
+	require.NoError(t, xml.Unmarshal(files["xl/worksheets/sheet1.xml"], sheet))
+	require.Len(t, sheet.Rows, 3)
+
+	header := sheet.Rows[0]
+	require.Equal(t, 1, header.R)
+	require.Len(t, header.Cells, 5)
+	require.Equal(t, "A1", header.Cells[0].R)
+	require.Equal(t, "inlineStr", header.Cells[0].T)
+	require.Equal(t, "ID", header.Cells[0].Inline)
+
+	row := sheet.Rows[1]
+	require.Equal(t, "abc", row.Cells[0].Inline)
+	require.Equal(t, "", row.Cells[1].T, "expected a numeric cell")
+	require.Equal(t, "0.0003842", row.Cells[1].V)
+	require.Equal(t, "b", row.Cells[2].T)
+	require.Equal(t, "0", row.Cells[2].V)
+	require.Equal(t, "2024-05-15T10:48:57Z", row.Cells[3].Inline)
+	require.Equal(t, "<Tom & Jerry>", row.Cells[4].Inline)
+
+	// Empty cells are omitted from the row
+	row = sheet.Rows[2]
+	require.Len(t, row.Cells, 3)
+	require.Equal(t, "12", row.Cells[1].V)
+	require.Equal(t, "1", row.Cells[2].V)
+}
+
+func TestCellRef(t *testing.T) {
+	tests := []struct {
+		col, row int
+		expected string
+	}{
+		{0, 1, "A1"},
+		{25, 2, "Z2"},
+		{26, 3, "AA3"},
+		{51, 4, "AZ4"},
+		{52, 5, "BA5"},
+		{701, 6, "ZZ6"},
+		{702, 7, "AAA7"},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.expected, xlsx.CellRef(tc.col, tc.row))
+	}
+}
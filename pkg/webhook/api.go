@@ -58,6 +58,24 @@ const (
 	DefaultTransferAction = "default"
 )
 
+// Event is sent to the webhook to notify the compliance system about a change to a
+// transaction that was not caused by an incoming message, e.g. when the reply deadline
+// of the transaction is approaching or has passed. No reply is expected.
+type Event struct {
+	Type          string    `json:"event"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Timestamp     string    `json:"timestamp"`
+	Status        string    `json:"status"`
+	Counterparty  string    `json:"counterparty,omitempty"`
+	ReplyNotAfter string    `json:"reply_not_after,omitempty"`
+	ReplyStatus   string    `json:"reply_status,omitempty"`
+}
+
+const (
+	EventReplyReminder = "reply_deadline.reminder"
+	EventReplyExpired  = "reply_deadline.expired"
+)
+
 const (
 	transactionPBType = "type.googleapis.com/trisa.data.generic.v1beta1.Transaction"
 	pendingPBType     = "type.googleapis.com/trisa.data.generic.v1beta1.Pending"
@@ -24,7 +24,9 @@ var MockConfig = config.WebhookConfig{
 // Mock implements the webhook Handler and is used for testing webhook interactions.
 type Mock struct {
 	OnCallback func(context.Context, *Request) (*Reply, error)
+	OnNotify   func(context.Context, *Event) error
 	Callbacks  int
+	Notifies   int
 }
 
 func NewMock() *Mock {
@@ -39,6 +41,14 @@ func (m *Mock) Callback(ctx context.Context, req *Request) (*Reply, error) {
 	return nil, errors.New("no mock callback configured")
 }
 
+func (m *Mock) Notify(ctx context.Context, event *Event) error {
+	m.Notifies++
+	if m.OnNotify != nil {
+		return m.OnNotify(ctx, event)
+	}
+	return errors.New("no mock notify configured")
+}
+
 func (m *Mock) UseError(err error) {
 	m.OnCallback = func(context.Context, *Request) (*Reply, error) {
 		return nil, err
@@ -69,7 +79,9 @@ func (m *Mock) UseFixture(path string) (err error) {
 
 func (m *Mock) Reset() {
 	m.OnCallback = nil
+	m.OnNotify = nil
 	m.Callbacks = 0
+	m.Notifies = 0
 }
 
 func MockPendingReply(_ context.Context, req *Request) (*Reply, error) {
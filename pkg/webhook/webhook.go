@@ -65,6 +65,7 @@ func New(conf config.WebhookConfig) (_ Handler, err error) {
 
 type Handler interface {
 	Callback(context.Context, *Request) (*Reply, error)
+	Notify(context.Context, *Event) error
 }
 
 // Webhook implements the Handler to make POST requests to the webhook URL.
@@ -89,13 +90,88 @@ const (
 
 func (h *Webhook) Callback(ctx context.Context, out *Request) (in *Reply, err error) {
 	var (
-		req  *http.Request
-		rep  *http.Response
-		data *bytes.Buffer
+		req *http.Request
+		rep *http.Response
 	)
 
-	data = new(bytes.Buffer)
-	if err = json.NewEncoder(data).Encode(out); err != nil {
+	if req, err = h.newRequest(ctx, out, out.TransactionID.String(), out.Timestamp); err != nil {
+		return nil, err
+	}
+
+	if rep, err = h.do(req, "preparing to send webhook callback"); err != nil {
+		return nil, err
+	}
+	defer rep.Body.Close()
+
+	// Check for non-content 204 response for default handling.
+	if rep.StatusCode == http.StatusNoContent {
+		return &Reply{TransferAction: DefaultTransferAction}, nil
+	}
+
+	// Handle encoding of the response body
+	var body io.Reader
+	switch rep.Header.Get("Content-Encoding") {
+	case gzipEncode:
+		if body, err = gzip.NewReader(rep.Body); err != nil {
+			return nil, fmt.Errorf("could not create gzip reader: %s", err)
+		}
+	case zlibEncode:
+		if body, err = zlib.NewReader(rep.Body); err != nil {
+			return nil, fmt.Errorf("could not create zlib reader: %s", err)
+		}
+	case "", identityEncode:
+		body = rep.Body
+	case lzwEncode:
+		body = lzw.NewReader(rep.Body, lzw.MSB, 8)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", rep.Header.Get("Content-Encoding"))
+	}
+
+	// Deserialize reply to the webhook POST call
+	in = &Reply{}
+	if err = json.NewDecoder(body).Decode(in); err != nil {
+		return nil, fmt.Errorf("could not unmarshal reply: %s", err)
+	}
+
+	// Nilify any zero-valued structs on the reply
+	if in.Error != nil && in.Error.IsZero() {
+		in.Error = nil
+	}
+
+	if in.Payload != nil && in.Payload.IsZero() {
+		in.Payload = nil
+	}
+
+	return in, nil
+}
+
+// Notify sends an event to the webhook that informs the compliance system about a
+// change to a transaction that is not the result of an incoming message. Events are
+// distinguished from callbacks by the X-Transfer-Event header; the body of any reply
+// is ignored.
+func (h *Webhook) Notify(ctx context.Context, event *Event) (err error) {
+	var (
+		req *http.Request
+		rep *http.Response
+	)
+
+	if req, err = h.newRequest(ctx, event, event.TransactionID.String(), event.Timestamp); err != nil {
+		return err
+	}
+	req.Header.Add("X-Transfer-Event", event.Type)
+
+	if rep, err = h.do(req, "preparing to send webhook event"); err != nil {
+		return err
+	}
+	rep.Body.Close()
+	return nil
+}
+
+// Creates a POST request to the webhook with the JSON encoded body, adding the headers
+// and the HMAC authorization token if client authentication is required.
+func (h *Webhook) newRequest(ctx context.Context, body any, transferID, timestamp string) (req *http.Request, err error) {
+	data := new(bytes.Buffer)
+	if err = json.NewEncoder(data).Encode(body); err != nil {
 		return nil, fmt.Errorf("could not marshal request: %s", err)
 	}
 
@@ -109,8 +185,8 @@ func (h *Webhook) Callback(ctx context.Context, out *Request) (in *Reply, err er
 	req.Header.Add("Accept-Language", acceptLang)
 	req.Header.Add("Accept-Encoding", acceptEncode)
 	req.Header.Add("Content-Type", contentType)
-	req.Header.Add("X-Transfer-ID", out.TransactionID.String())
-	req.Header.Add("X-Transfer-Timestamp", out.Timestamp)
+	req.Header.Add("X-Transfer-ID", transferID)
+	req.Header.Add("X-Transfer-Timestamp", timestamp)
 
 	if h.conf.RequireClientAuth() {
 		// Create HMAC authorization token and add it to the request
@@ -126,6 +202,12 @@ func (h *Webhook) Callback(ctx context.Context, out *Request) (in *Reply, err er
 		req.Header.Add("Authorization", auth)
 	}
 
+	return req, nil
+}
+
+// Executes the request, checking the status code of the reply and authenticating the
+// server if required. The caller must close the body of the reply.
+func (h *Webhook) do(req *http.Request, msg string) (rep *http.Response, err error) {
 	// Debug logging for the webhook POST request
 	log.Debug().
 		Str("url", req.URL.String()).
@@ -134,13 +216,12 @@ func (h *Webhook) Callback(ctx context.Context, out *Request) (in *Reply, err er
 		Bool("client_auth_required", h.conf.RequireClientAuth()).
 		Bool("server_auth_required", h.conf.RequireServerAuth).
 		Int64("content_length", req.ContentLength).
-		Msg("preparing to send webhook callback")
+		Msg(msg)
 
 	// Execute the request
 	if rep, err = h.client.Do(req); err != nil {
 		return nil, err
 	}
-	defer rep.Body.Close()
 
 	// Debug logging for the webhook reply
 	log.Debug().
@@ -150,6 +231,7 @@ func (h *Webhook) Callback(ctx context.Context, out *Request) (in *Reply, err er
 		Msg("webhook request complete")
 
 	if rep.StatusCode < 200 || rep.StatusCode >= 300 {
+		rep.Body.Close()
 		return nil, fmt.Errorf("could not make webhook callback: received status %s", rep.Status)
 	}
 
@@ -157,56 +239,20 @@ func (h *Webhook) Callback(ctx context.Context, out *Request) (in *Reply, err er
 	if h.conf.RequireServerAuth {
 		var token *HMACToken
 		if token, err = ParseHMAC(rep.Header.Get("Server-Authorization")); err != nil {
+			rep.Body.Close()
 			return nil, fmt.Errorf("could not parse server authorization header: %s", err)
 		}
 
 		token.Collect(rep.Header)
 
 		if valid, err := token.Verify(h.authKey); err != nil {
+			rep.Body.Close()
 			return nil, fmt.Errorf("could not verify server authorization header: %s", err)
 		} else if !valid {
+			rep.Body.Close()
 			return nil, fmt.Errorf("could not authorize webhook server")
 		}
 	}
 
-	// Check for non-content 204 response for default handling.
-	if rep.StatusCode == http.StatusNoContent {
-		return &Reply{TransferAction: DefaultTransferAction}, nil
-	}
-
-	// Handle encoding of the response body
-	var body io.Reader
-	switch rep.Header.Get("Content-Encoding") {
-	case gzipEncode:
-		if body, err = gzip.NewReader(rep.Body); err != nil {
-			return nil, fmt.Errorf("could not create gzip reader: %s", err)
-		}
-	case zlibEncode:
-		if body, err = zlib.NewReader(rep.Body); err != nil {
-			return nil, fmt.Errorf("could not create zlib reader: %s", err)
-		}
-	case "", identityEncode:
-		body = rep.Body
-	case lzwEncode:
-		body = lzw.NewReader(rep.Body, lzw.MSB, 8)
-	default:
-		return nil, fmt.Errorf("unsupported content encoding %q", rep.Header.Get("Content-Encoding"))
-	}
-
-	// Deserialize reply to the webhook POST call
-	in = &Reply{}
-	if err = json.NewDecoder(body).Decode(in); err != nil {
-		return nil, fmt.Errorf("could not unmarshal reply: %s", err)
-	}
-
-	// Nilify any zero-valued structs on the reply
-	if in.Error != nil && in.Error.IsZero() {
-		in.Error = nil
-	}
-
-	if in.Payload != nil && in.Payload.IsZero() {
-		in.Payload = nil
-	}
-
-	return in, nil
+	return rep, nil
 }
@@ -14,7 +14,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/trisacrypto/envoy/pkg/config"
 	"github.com/trisacrypto/envoy/pkg/webhook"
@@ -186,6 +188,62 @@ func TestWebhook(t *testing.T) {
 	})
 }
 
+func TestWebhookNotify(t *testing.T) {
+	ctx := context.Background()
+	event := &webhook.Event{
+		Type:          webhook.EventReplyReminder,
+		TransactionID: uuid.MustParse("b1b9a7a4-8d3e-4e0c-9a39-a8f3a4a9c4a2"),
+		Timestamp:     time.Now().Format(time.RFC3339),
+		Status:        "review",
+		ReplyNotAfter: time.Now().Add(time.Hour).Format(time.RFC3339),
+		ReplyStatus:   "due_soon",
+	}
+
+	t.Run("Event", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			in := &webhook.Event{}
+			if err := json.NewDecoder(r.Body).Decode(in); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if r.Header.Get("X-Transfer-Event") != in.Type || r.Header.Get("X-Transfer-ID") != in.TransactionID.String() {
+				http.Error(w, "unexpected event headers", http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		cb, err := webhook.New(config.WebhookConfig{URL: srv.URL})
+		require.NoError(t, err, "could not create webhook handler")
+		require.NoError(t, cb.Notify(ctx, event), "could not send event")
+	})
+
+	t.Run("HTTPError", func(t *testing.T) {
+		srv := httptest.NewServer(makeWebhookError("service unavailable", http.StatusServiceUnavailable))
+		defer srv.Close()
+
+		cb, err := webhook.New(config.WebhookConfig{URL: srv.URL})
+		require.NoError(t, err, "could not create webhook handler")
+		require.EqualError(t, cb.Notify(ctx, event), "could not make webhook callback: received status 503 Service Unavailable")
+	})
+
+	t.Run("ServerAuth", func(t *testing.T) {
+		srv := httptest.NewServer(makeWebhookAuthHandler())
+		defer srv.Close()
+
+		cb, err := webhook.New(config.WebhookConfig{
+			URL:               srv.URL,
+			AuthKeyID:         "01JT4B3R5Z6AHJXV87QHPPKRBM",
+			AuthKeySecret:     "cfbabc4715b4759d45ba26953dd2fc0bfc2344ef70a2005432e7f16b5081610d",
+			RequireServerAuth: true,
+		})
+		require.NoError(t, err, "could not create webhook with server auth")
+		require.NoError(t, cb.Notify(ctx, event), "could not send event")
+	})
+}
+
 //===========================================================================
 // Test Server Helpers
 //===========================================================================